@@ -2,26 +2,82 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
+	_ "time/tzdata" // users' time zones, on hosts without a zone database
 
+	"receipt-bot/internal/adapters/anylist"
+	"receipt-bot/internal/adapters/barcode"
+	"receipt-bot/internal/adapters/clipapi"
+	"receipt-bot/internal/adapters/crouton"
+	"receipt-bot/internal/adapters/dropbox"
 	"receipt-bot/internal/adapters/firebase"
+	"receipt-bot/internal/adapters/gcs"
+	"receipt-bot/internal/adapters/gdrive"
+	"receipt-bot/internal/adapters/googletts"
+	"receipt-bot/internal/adapters/inboundmail"
+	"receipt-bot/internal/adapters/integrations"
 	"receipt-bot/internal/adapters/llm"
+	"receipt-bot/internal/adapters/logging"
+	"receipt-bot/internal/adapters/memory"
 	"receipt-bot/internal/adapters/notion"
 	"receipt-bot/internal/adapters/obsidian"
+	"receipt-bot/internal/adapters/openaiimages"
+	"receipt-bot/internal/adapters/openfoodfacts"
 	"receipt-bot/internal/adapters/python"
+	"receipt-bot/internal/adapters/rss"
+	"receipt-bot/internal/adapters/s3"
+	"receipt-bot/internal/adapters/sandbox"
 	"receipt-bot/internal/adapters/telegram"
+	"receipt-bot/internal/adapters/webapp"
+	"receipt-bot/internal/adapters/webhook"
+	"receipt-bot/internal/adapters/whisk"
 	"receipt-bot/internal/application/command"
 	"receipt-bot/internal/application/query"
 	"receipt-bot/internal/config"
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/analytics"
+	"receipt-bot/internal/domain/bookmark"
+	"receipt-bot/internal/domain/conversation"
+	"receipt-bot/internal/domain/diet"
+	"receipt-bot/internal/domain/experiment"
+	"receipt-bot/internal/domain/feature"
+	"receipt-bot/internal/domain/feed"
+	"receipt-bot/internal/domain/freezer"
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/mealplan"
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/nutrition"
 	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/report"
+	"receipt-bot/internal/domain/requests"
+	"receipt-bot/internal/domain/share"
+	"receipt-bot/internal/domain/shopping"
+	"receipt-bot/internal/domain/stats"
+	"receipt-bot/internal/domain/telemetry"
+	"receipt-bot/internal/domain/user"
 	"receipt-bot/internal/ports"
 )
 
+// userStore is the user persistence the bot needs, including Notion connections
+type userStore interface {
+	user.Repository
+	notion.UserRepository
+}
+
 func main() {
+	check := flag.Bool("check", false, "check every configured integration and print a readiness report before starting, exiting if one fails")
+	flag.Parse()
+
 	// Load configuration
 	log.Println("Loading configuration...")
 	cfg, err := config.Load()
@@ -29,95 +85,333 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Leave out log entries below APP_LOG_LEVEL
+	logFilter := logging.NewFilter(os.Stderr, cfg.App.LogLevel)
+	log.SetOutput(logFilter)
+
 	// Initialize context
 	ctx := context.Background()
 
-	// Initialize Firebase
-	log.Println("Initializing Firebase...")
-	firebaseClient, err := firebase.NewClient(ctx, firebase.Config{
-		ProjectID:       cfg.Firebase.ProjectID,
-		CredentialsPath: cfg.Firebase.CredentialsPath,
-	})
-	if err != nil {
-		log.Fatalf("Failed to initialize Firebase: %v", err)
+	if *check && !preflight(ctx, cfg, os.Stdout) {
+		os.Exit(1)
 	}
-	defer firebaseClient.Close()
 
-	// Initialize repositories
-	recipeRepo := firebase.NewRecipeRepository(firebaseClient.Firestore())
-	userRepo := firebase.NewUserRepository(firebaseClient.Firestore())
+	// Long transcripts and captions go to a bucket when one is configured
+	blobs := newBlobStorage(ctx, cfg.Blobs)
 
-	// Initialize Python service adapter
-	log.Println("Connecting to Python service...")
-	scraperAdapter, err := python.NewScraperAdapter(
-		cfg.Python.URL,
-		time.Duration(cfg.Python.Timeout)*time.Second,
+	var (
+		recipeRepo       recipe.Repository
+		versionRepo      recipe.VersionRepository
+		variantRepo      recipe.VariantRepository
+		processingRepo   recipe.ProcessingRepository
+		checkpointRepo   recipe.ExportCheckpointRepository
+		freezerRepo      freezer.Repository
+		requestRepo      requests.Repository
+		statsRepo        stats.Repository
+		linkCodeRepo     user.LinkCodeRepository
+		shareRepo        share.Repository
+		reportRepo       report.Repository
+		activityRepo     activity.Repository
+		conversationRepo conversation.Repository
+		usageRepo        analytics.Repository
+		feedRepo         feed.Repository
+		moderationRepo   moderation.Repository
+		mealPlanRepo     mealplan.Repository
+		shoppingRepo     shopping.Repository
+		nutritionRepo    nutrition.Repository
+		userRepo         userStore
+		featureFlagRepo  feature.Repository
+		scraper          ports.ScraperPort
+		llmAdapter       ports.LLMPort
+		intentDetector   ports.IntentDetector
+		experiments      *experiment.Tracker // nil unless a prompt experiment is configured
 	)
-	if err != nil {
-		log.Fatalf("Failed to initialize scraper adapter: %v", err)
-	}
-	defer scraperAdapter.Close()
 
-	// Initialize LLM adapter
-	log.Printf("Initializing LLM adapter (%s)...", cfg.LLM.Provider)
-	llmAdapter, err := llm.NewLLMAdapter(llm.LLMConfig{
-		Provider: cfg.LLM.Provider,
-		APIKey:   cfg.LLM.APIKey,
-		Model:    cfg.LLM.Model,
-	})
-	if err != nil {
-		log.Fatalf("Failed to initialize LLM adapter: %v", err)
-	}
+	if cfg.App.Sandbox {
+		// Sandbox mode: recorded fixtures instead of the scraper and LLM,
+		// Firestore emulator or memory instead of the real project
+		log.Println("Running in SANDBOX mode")
 
-	// Close Gemini client if needed
-	if geminiAdapter, ok := llmAdapter.(*llm.GeminiAdapter); ok {
-		defer geminiAdapter.Close()
-	}
+		fixtures, err := sandbox.LoadFixtures(cfg.App.SandboxFixtures)
+		if err != nil {
+			log.Fatalf("Failed to load sandbox fixtures: %v", err)
+		}
 
-	// Initialize intent detector for conversational interface
-	log.Println("Initializing intent detector...")
-	intentDetector, err := llm.NewIntentDetector(llm.LLMConfig{
-		Provider: cfg.LLM.Provider,
-		APIKey:   cfg.LLM.APIKey,
-		Model:    cfg.LLM.Model,
-	})
-	if err != nil {
-		log.Printf("Warning: Failed to initialize intent detector: %v", err)
-		log.Println("Conversational interface will be disabled")
-		intentDetector = nil
+		if cfg.Firebase.EmulatorHost != "" {
+			log.Printf("Connecting to Firestore emulator at %s...", cfg.Firebase.EmulatorHost)
+			firebaseClient, err := firebase.NewEmulatorClient(ctx, cfg.Firebase.ProjectID, cfg.Firebase.EmulatorHost)
+			if err != nil {
+				log.Fatalf("Failed to initialize Firestore emulator: %v", err)
+			}
+			defer firebaseClient.Close()
+
+			recipeRepo = firebase.NewRecipeRepository(firebaseClient.Firestore(), blobs)
+			versionRepo = firebase.NewRecipeVersionRepository(firebaseClient.Firestore())
+			variantRepo = firebase.NewRecipeVariantRepository(firebaseClient.Firestore())
+			processingRepo = firebase.NewProcessingReportRepository(firebaseClient.Firestore())
+			checkpointRepo = firebase.NewExportCheckpointRepository(firebaseClient.Firestore())
+			freezerRepo = firebase.NewFreezerRepository(firebaseClient.Firestore())
+			requestRepo = firebase.NewRequestRepository(firebaseClient.Firestore())
+			statsRepo = firebase.NewStatsRepository(firebaseClient.Firestore())
+			linkCodeRepo = firebase.NewLinkCodeRepository(firebaseClient.Firestore())
+			shareRepo = firebase.NewGuestShareRepository(firebaseClient.Firestore())
+			reportRepo = firebase.NewErrorReportRepository(firebaseClient.Firestore())
+			activityRepo = firebase.NewActivityRepository(firebaseClient.Firestore())
+			conversationRepo = firebase.NewConversationRepository(firebaseClient.Firestore())
+			usageRepo = firebase.NewUsageRepository(firebaseClient.Firestore())
+			feedRepo = firebase.NewFeedRepository(firebaseClient.Firestore())
+			moderationRepo = firebase.NewModerationRepository(firebaseClient.Firestore())
+			mealPlanRepo = firebase.NewMealPlanRepository(firebaseClient.Firestore())
+			shoppingRepo = firebase.NewShoppingListRepository(firebaseClient.Firestore())
+			nutritionRepo = firebase.NewNutritionRepository(firebaseClient.Firestore())
+			userRepo = firebase.NewUserRepository(firebaseClient.Firestore())
+			featureFlagRepo = firebase.NewFeatureFlagRepository(firebaseClient.Firestore())
+		} else {
+			log.Println("FIRESTORE_EMULATOR_HOST not set, data is kept in memory")
+			recipeRepo = memory.NewRecipeRepository()
+			versionRepo = memory.NewRecipeVersionRepository()
+			variantRepo = memory.NewRecipeVariantRepository()
+			processingRepo = memory.NewProcessingReportRepository()
+			checkpointRepo = memory.NewExportCheckpointRepository()
+			freezerRepo = memory.NewFreezerRepository()
+			requestRepo = memory.NewRequestRepository()
+			statsRepo = memory.NewStatsRepository()
+			linkCodeRepo = memory.NewLinkCodeRepository()
+			shareRepo = memory.NewGuestShareRepository()
+			reportRepo = memory.NewErrorReportRepository()
+			activityRepo = memory.NewActivityRepository()
+			conversationRepo = memory.NewConversationRepository()
+			usageRepo = memory.NewUsageRepository()
+			feedRepo = memory.NewFeedRepository()
+			moderationRepo = memory.NewModerationRepository()
+			mealPlanRepo = memory.NewMealPlanRepository()
+			shoppingRepo = memory.NewShoppingListRepository()
+			nutritionRepo = memory.NewNutritionRepository()
+			userRepo = memory.NewUserRepository()
+			featureFlagRepo = memory.NewFeatureFlagRepository()
+		}
+
+		scraper = sandbox.NewScraper(fixtures)
+		llmAdapter = sandbox.NewLLM(fixtures)
+		log.Println("Conversational interface is disabled in sandbox mode")
+	} else {
+		// Initialize Firebase
+		log.Println("Initializing Firebase...")
+		firebaseClient, err := firebase.NewClient(ctx, firebase.Config{
+			ProjectID:       cfg.Firebase.ProjectID,
+			CredentialsPath: cfg.Firebase.CredentialsPath,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize Firebase: %v", err)
+		}
+		defer firebaseClient.Close()
+
+		// Initialize repositories
+		firestoreRecipeRepo := firebase.NewRecipeRepository(firebaseClient.Firestore(), blobs)
+		if cfg.Firebase.QueryStats {
+			log.Println("Logging Firestore recipe query stats")
+			firestoreRecipeRepo.SetQueryObserver(firebase.LogQueryStats)
+		}
+		recipeRepo = firestoreRecipeRepo
+		versionRepo = firebase.NewRecipeVersionRepository(firebaseClient.Firestore())
+		variantRepo = firebase.NewRecipeVariantRepository(firebaseClient.Firestore())
+		processingRepo = firebase.NewProcessingReportRepository(firebaseClient.Firestore())
+		checkpointRepo = firebase.NewExportCheckpointRepository(firebaseClient.Firestore())
+		freezerRepo = firebase.NewFreezerRepository(firebaseClient.Firestore())
+		requestRepo = firebase.NewRequestRepository(firebaseClient.Firestore())
+		statsRepo = firebase.NewStatsRepository(firebaseClient.Firestore())
+		linkCodeRepo = firebase.NewLinkCodeRepository(firebaseClient.Firestore())
+		shareRepo = firebase.NewGuestShareRepository(firebaseClient.Firestore())
+		reportRepo = firebase.NewErrorReportRepository(firebaseClient.Firestore())
+		activityRepo = firebase.NewActivityRepository(firebaseClient.Firestore())
+		conversationRepo = firebase.NewConversationRepository(firebaseClient.Firestore())
+		usageRepo = firebase.NewUsageRepository(firebaseClient.Firestore())
+		feedRepo = firebase.NewFeedRepository(firebaseClient.Firestore())
+		moderationRepo = firebase.NewModerationRepository(firebaseClient.Firestore())
+		mealPlanRepo = firebase.NewMealPlanRepository(firebaseClient.Firestore())
+		shoppingRepo = firebase.NewShoppingListRepository(firebaseClient.Firestore())
+		nutritionRepo = firebase.NewNutritionRepository(firebaseClient.Firestore())
+		userRepo = firebase.NewUserRepository(firebaseClient.Firestore())
+		featureFlagRepo = firebase.NewFeatureFlagRepository(firebaseClient.Firestore())
+
+		// Initialize Python service adapter
+		log.Println("Connecting to Python service...")
+		scraperAdapter, err := python.NewScraperAdapter(
+			cfg.Python.URL,
+			time.Duration(cfg.Python.Timeout)*time.Second,
+		)
+		if err != nil {
+			log.Fatalf("Failed to initialize scraper adapter: %v", err)
+		}
+		defer scraperAdapter.Close()
+		scraper = scraperAdapter
+
+		// Initialize LLM adapter
+		log.Printf("Initializing LLM adapter (%s)...", cfg.LLM.Provider)
+		extractionExperiment := promptExperiment(cfg.LLM.ExtractionExperiment)
+		intentExperiment := promptExperiment(cfg.LLM.IntentExperiment)
+		if extractionExperiment != nil || intentExperiment != nil {
+			experiments = experiment.NewTracker()
+		}
+
+		llmAdapter, err = llm.NewLLMAdapter(llm.LLMConfig{
+			Provider:       cfg.LLM.Provider,
+			APIKey:         cfg.LLM.APIKey,
+			Model:          cfg.LLM.Model,
+			BaseURL:        cfg.LLM.BaseURL,
+			ExtractTask:    llmTask(cfg.LLM.ExtractTask),
+			TranslateTask:  llmTask(cfg.LLM.TranslateTask),
+			PromptVersion:  cfg.LLM.PromptVersion,
+			PromptCacheTTL: time.Duration(cfg.LLM.PromptCacheMinutes) * time.Minute,
+			Extraction:     extractionExperiment,
+			Tracker:        experiments,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize LLM adapter: %v", err)
+		}
+
+		// Close Gemini client if needed
+		if geminiAdapter, ok := llmAdapter.(*llm.GeminiAdapter); ok {
+			defer geminiAdapter.Close()
+		}
+
+		// Initialize intent detector for conversational interface
+		log.Println("Initializing intent detector...")
+		intentDetector, err = llm.NewIntentDetector(llm.LLMConfig{
+			Provider:       cfg.LLM.Provider,
+			APIKey:         cfg.LLM.APIKey,
+			Model:          cfg.LLM.Model,
+			BaseURL:        cfg.LLM.BaseURL,
+			IntentTask:     llmTask(cfg.LLM.IntentTask),
+			PromptCacheTTL: time.Duration(cfg.LLM.PromptCacheMinutes) * time.Minute,
+			Intent:         intentExperiment,
+			Tracker:        experiments,
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to initialize intent detector: %v", err)
+			log.Println("Conversational interface will be disabled")
+			intentDetector = nil
+		}
 	}
 
 	// Initialize Telegram bot
 	log.Println("Initializing Telegram bot...")
 	bot, err := telegram.NewBot(telegram.Config{
 		BotToken: cfg.Telegram.BotToken,
-		Debug:    cfg.Telegram.Debug,
+		Debug:    cfg.Telegram.Debug || cfg.App.LogLevel == "debug",
+		Blobs:    blobs,
 	})
 	if err != nil {
 		log.Fatalf("Failed to initialize Telegram bot: %v", err)
 	}
 
+	// Reload non-critical settings on SIGHUP or config file change
+	configWatcher := config.NewWatcher(cfg)
+	configWatcher.OnChange(func(runtime config.RuntimeConfig) {
+		logFilter.SetLevel(runtime.LogLevel)
+		bot.SetDebug(cfg.Telegram.Debug || runtime.LogLevel == "debug")
+		if versioned, ok := llmAdapter.(llm.PromptVersioned); ok {
+			if err := versioned.SetPromptVersion(runtime.PromptVersion); err != nil {
+				log.Printf("Warning: failed to change the prompt version: %v", err)
+			}
+		}
+	})
+	configWatcher.Start(ctx)
+
 	// Initialize domain services
 	recipeService := recipe.NewService()
 
+	// Feature flags: environment defaults, overridden at runtime from Firestore
+	featureDefaults := make(map[feature.Flag]bool)
+	for name, enabled := range cfg.Features.Flags {
+		flag, ok := feature.ParseFlag(name)
+		if !ok {
+			log.Printf("Warning: Unknown feature flag %q ignored", name)
+			continue
+		}
+		featureDefaults[flag] = enabled
+	}
+	featureService := feature.NewService(featureDefaults, featureFlagRepo)
+
+	// Premium tiers paid in Telegram: a higher link quota and the premium features
+	var premiumCmd *command.ManagePremiumCommand
+	var entitlements feature.Entitlements
+	if cfg.Payments.Enabled {
+		premiumCmd = command.NewManagePremiumCommand(userRepo, cfg.Payments.Currency, command.PremiumPlans(cfg.Payments.MonthPrice, cfg.Payments.YearPrice))
+		entitlements = premiumCmd
+
+		var premiumFlags []feature.Flag
+		for _, name := range cfg.Payments.Features {
+			flag, ok := feature.ParseFlag(name)
+			if !ok {
+				log.Printf("Warning: Unknown premium feature %q ignored", name)
+				continue
+			}
+			premiumFlags = append(premiumFlags, flag)
+		}
+		featureService.RequirePremium(premiumCmd, premiumFlags...)
+	}
+	linkQuota := command.NewLinkQuota(cfg.RateLimit.LinksPerHour, cfg.RateLimit.PremiumLinksPerHour, entitlements)
+	configWatcher.OnChange(func(runtime config.RuntimeConfig) {
+		linkQuota.SetLimits(runtime.RateLimit.LinksPerHour, runtime.RateLimit.PremiumLinksPerHour)
+	})
+
 	// Initialize application layer
 	log.Println("Initializing application layer...")
 
-	processRecipeLinkCmd := command.NewProcessRecipeLinkCommand(
-		scraperAdapter,
-		llmAdapter,
-		recipeService,
-		recipeRepo,
-		bot,
-	)
+	// Ingredients are matched by their normalized names, with the operator's synonyms
+	normalizer := newIngredientNormalizer(cfg.Matching.SynonymsFile)
+
+	// Every way of processing links keeps a report of each recipe for /inspect
+	newProcessRecipeLinkCmd := func(messenger ports.MessengerPort) *command.ProcessRecipeLinkCommand {
+		cmd := command.NewProcessRecipeLinkCommand(scraper, llmAdapter, recipeService, recipeRepo, messenger)
+		cmd.SetNormalizer(normalizer)
+		cmd.SetProcessingReports(processingRepo)
+		cmd.SetStoreTranscripts(cfg.App.StoreTranscripts)
+		cmd.SetUserRepository(userRepo)
+		return cmd
+	}
+
+	processRecipeLinkCmd := newProcessRecipeLinkCmd(bot)
 
 	getOrCreateUserCmd := command.NewGetOrCreateUserCommand(userRepo)
 
 	listRecipesQuery := query.NewListRecipesQuery(recipeRepo)
 
-	matchIngredientsCmd := command.NewMatchIngredientsCommand(recipeRepo)
+	matchIngredientsCmd := command.NewMatchIngredientsCommand(recipeRepo, userRepo, cfg.Matching.Specificity)
+	matchIngredientsCmd.SetNormalizer(normalizer)
+
+	// Ingredients are sorted into aisles by keyword rules, asking the LLM
+	// (when it supports it) only about ingredients the rules do not know
+	llmAisleClassifier, _ := llmAdapter.(ports.AisleClassifier)
+	aisleClassifier := command.NewIngredientClassifier(llmAisleClassifier)
+	aisleClassifier.SetNormalizer(normalizer)
 
-	managePantryCmd := command.NewManagePantryCommand(userRepo)
+	managePantryCmd := command.NewManagePantryCommand(userRepo, aisleClassifier)
+	managePantryCmd.SetNormalizer(normalizer)
+
+	// Open Food Facts needs no credentials, so barcode scanning works in sandbox mode too.
+	// Product data is cached, so each barcode is fetched at most once a month.
+	productCatalog := command.NewProductCatalog(nutritionRepo, openfoodfacts.NewClient(openfoodfacts.Config{}))
+	scanBarcodeCmd := command.NewScanBarcodeCommand(
+		barcode.NewDecoder(),
+		productCatalog,
+		managePantryCmd,
+		nutritionRepo,
+	)
+	nutritionCmd := command.NewEstimateNutritionCommand(nutritionRepo, productCatalog, userRepo, recipeRepo)
+	nutritionCmd.SetNormalizer(normalizer)
+
+	manageMealPlanCmd := command.NewManageMealPlanCommand(mealPlanRepo, recipeRepo)
+
+	shoppingListCmd := command.NewGenerateShoppingListCommand(
+		mealPlanRepo,
+		recipeRepo,
+		userRepo,
+		shoppingRepo,
+		aisleClassifier,
+	)
+	shoppingListCmd.SetNormalizer(normalizer)
 
 	// Initialize exporters
 	obsidianExporter := obsidian.NewExporter()
@@ -131,7 +425,7 @@ func main() {
 			ClientSecret: cfg.Notion.ClientSecret,
 			RedirectURI:  cfg.Notion.RedirectURI,
 		})
-		notionExporter = notion.NewExporter(notionClient, userRepo)
+		notionExporter = notion.NewExporter(notionClient, userRepo, blobs)
 	} else {
 		log.Println("Notion integration not configured (NOTION_CLIENT_ID and NOTION_CLIENT_SECRET not set)")
 	}
@@ -141,22 +435,331 @@ func main() {
 		recipeRepo,
 		obsidianExporter,
 		notionExporter,
+		map[command.ExportFormat]ports.AppExporter{
+			command.ExportFormatCrouton: crouton.NewExporter(),
+			command.ExportFormatAnyList: anylist.NewExporter(),
+			command.ExportFormatWhisk:   whisk.NewExporter(),
+		},
+	)
+	exportRecipeCmd.SetExportCheckpoints(checkpointRepo)
+
+	// Export recipes to Notion or Obsidian as users save them, when they turn it on
+	autoExportCmd := command.NewAutoExportCommand(userRepo, recipeRepo, obsidianExporter, notionExporter)
+
+	// Write /export obsidian files into the Dropbox or Google Drive folder users connect,
+	// offering the providers that have OAuth credentials
+	cloudProviders := map[user.CloudProvider]ports.CloudStorage{}
+	if cfg.Cloud.DropboxAppKey != "" && cfg.Cloud.DropboxAppSecret != "" {
+		cloudProviders[user.CloudDropbox] = dropbox.NewClient(dropbox.Config{
+			AppKey:      cfg.Cloud.DropboxAppKey,
+			AppSecret:   cfg.Cloud.DropboxAppSecret,
+			RedirectURI: cfg.Cloud.DropboxRedirectURI,
+		})
+	}
+	if cfg.Cloud.DriveClientID != "" && cfg.Cloud.DriveClientSecret != "" {
+		cloudProviders[user.CloudDrive] = gdrive.NewClient(gdrive.Config{
+			ClientID:     cfg.Cloud.DriveClientID,
+			ClientSecret: cfg.Cloud.DriveClientSecret,
+			RedirectURI:  cfg.Cloud.DriveRedirectURI,
+		})
+	}
+	var cloudStorageCmd *command.CloudStorageCommand
+	if len(cloudProviders) > 0 {
+		cloudStorageCmd = command.NewCloudStorageCommand(userRepo, recipeRepo, obsidianExporter, cloudProviders)
+	}
+
+	// Initialize recipe history command
+	recipeHistoryCmd := command.NewRecipeHistoryCommand(recipeRepo, versionRepo)
+
+	// Simplified recipe views need an LLM that can rewrite instructions
+	var simplifyRecipeCmd *command.SimplifyRecipeCommand
+	if simplifier, ok := llmAdapter.(ports.InstructionSimplifier); ok {
+		simplifyRecipeCmd = command.NewSimplifyRecipeCommand(recipeRepo, simplifier)
+	}
+
+	// Menus fill courses missing from the collection with suggestions when the LLM supports it
+	var menuSuggester ports.MenuSuggester
+	if suggester, ok := llmAdapter.(ports.MenuSuggester); ok {
+		menuSuggester = suggester
+	}
+	planMenuCmd := command.NewPlanMenuCommand(recipeRepo, menuSuggester)
+	cookingTimelineCmd := command.NewCookingTimelineCommand(recipeRepo)
+	cookTogetherCmd := command.NewCookTogetherCommand(recipeRepo)
+
+	// Filing recipes saved in forum topics under the topic's category is opt-in
+	var categorizeRecipeCmd *command.CategorizeRecipeCommand
+	if cfg.Telegram.TopicCollections {
+		categorizeRecipeCmd = command.NewCategorizeRecipeCommand(recipeRepo)
+	}
+	manageFreezerCmd := command.NewManageFreezerCommand(freezerRepo, recipeRepo)
+	manageRequestsCmd := command.NewManageRequestsCommand(requestRepo, recipeRepo, manageMealPlanCmd)
+	achievementsCmd := command.NewTrackAchievementsCommand(statsRepo, recipeRepo)
+	savedFiltersCmd := command.NewManageSavedFiltersCommand(userRepo)
+	shortcutsCmd := command.NewManageShortcutsCommand(userRepo)
+	learnClarificationsCmd := command.NewLearnClarificationsCommand(userRepo)
+	notificationsCmd := command.NewManageNotificationsCommand(userRepo)
+	staplesCmd := command.NewManageStaplesCommand(userRepo)
+	staplesCmd.SetNormalizer(normalizer)
+	checkDietCmd := command.NewCheckDietCommand(userRepo, recipeRepo, newDietChecker(cfg.Diets.RulesFile))
+	exportFieldsCmd := command.NewManageExportFieldsCommand(userRepo)
+	linkAccountCmd := command.NewLinkAccountCommand(userRepo, linkCodeRepo)
+	shareCollectionCmd := command.NewShareCollectionCommand(shareRepo)
+	reportErrorCmd := command.NewReportErrorCommand(reportRepo)
+	activityLogCmd := command.NewActivityLogCommand(activityRepo)
+	transcriptCmd := command.NewConversationTranscriptCommand(conversationRepo)
+
+	// Shared recipes are checked before guests see them; the content check needs an LLM that supports it
+	var contentModerator ports.ContentModerator
+	if moderator, ok := llmAdapter.(ports.ContentModerator); ok {
+		contentModerator = moderator
+	}
+	moderateContentCmd := command.NewModerateContentCommand(
+		moderationRepo, recipeRepo, moderation.NewLinkPolicy(cfg.Moderation.BlockedDomains), contentModerator,
 	)
+	browseSharedQuery := query.NewBrowseSharedQuery(shareRepo, recipeRepo, moderateContentCmd)
+
+	// Bookmark imports process links quietly, without progress messages for each one
+	importBookmarksCmd := command.NewImportBookmarksCommand(
+		newProcessRecipeLinkCmd(nil),
+		bookmark.NewSelector(cfg.Bookmarks.Domains),
+		time.Duration(cfg.Bookmarks.Interval)*time.Second,
+	)
+
+	// The browser extension API needs a secret to sign the users' tokens
+	var clipRecipeCmd *command.ClipRecipeCommand
+	if cfg.Clip.Secret != "" {
+		clipRecipeCmd = command.NewClipRecipeCommand(
+			newProcessRecipeLinkCmd(nil),
+			userRepo,
+			cfg.Clip.Secret,
+		)
+	}
+
+	// Forwarded emails need a domain the mail provider receives for
+	var forwardEmailCmd *command.ForwardEmailCommand
+	if cfg.Email.Domain != "" {
+		forwardEmailCmd = command.NewForwardEmailCommand(
+			newProcessRecipeLinkCmd(nil),
+			userRepo,
+			bookmark.NewSelector(cfg.Bookmarks.Domains),
+			cfg.Email.Domain,
+			cfg.Email.Secret,
+		)
+	}
+
+	// Recipe blog posts are processed quietly, and only saved when the user asks
+	manageSubscriptionsCmd := command.NewManageSubscriptionsCommand(
+		feedRepo,
+		rss.NewReader(),
+		newProcessRecipeLinkCmd(nil),
+		recipeRepo,
+	)
+
+	// Appliance conversions need an LLM that supports them
+	var convertRecipeCmd *command.ConvertRecipeCommand
+	if converter, ok := llmAdapter.(ports.RecipeConverter); ok {
+		convertRecipeCmd = command.NewConvertRecipeCommand(recipeRepo, variantRepo, converter)
+	}
+
+	var remixRecipeCmd *command.RemixRecipeCommand
+	if remixer, ok := llmAdapter.(ports.RecipeRemixer); ok {
+		remixRecipeCmd = command.NewRemixRecipeCommand(recipeRepo, remixer)
+		remixRecipeCmd.SetNormalizer(normalizer)
+	}
+
+	// Reading recipes aloud needs Google Cloud Text-to-Speech
+	var recipeAudioCmd *command.RecipeAudioCommand
+	if cfg.Speech.APIKey != "" {
+		recipeAudioCmd = command.NewRecipeAudioCommand(googletts.NewClient(googletts.Config{APIKey: cfg.Speech.APIKey}))
+	}
+
+	// Cover images for recipes without a picture need an image generator and a
+	// bucket to keep them in
+	var generateCoverCmd *command.GenerateCoverCommand
+	if cfg.Images.APIKey != "" && blobs != nil {
+		images := openaiimages.NewClient(openaiimages.Config{APIKey: cfg.Images.APIKey, Model: cfg.Images.Model})
+		generateCoverCmd = command.NewGenerateCoverCommand(recipeRepo, images, blobs)
+	}
+
+	// Recreating dishes from photos needs a multimodal LLM
+	var recreateDishCmd *command.RecreateDishCommand
+	if recreator, ok := llmAdapter.(ports.DishRecreator); ok {
+		recreateDishCmd = command.NewRecreateDishCommand(recipeRepo, recreator)
+		recreateDishCmd.SetNormalizer(normalizer)
+	}
+
+	// Pantry shelf photos need an LLM that recognizes items in images
+	var scanPantryPhotoCmd *command.ScanPantryPhotoCommand
+	if recognizer, ok := llmAdapter.(ports.PantryRecognizer); ok {
+		scanPantryPhotoCmd = command.NewScanPantryPhotoCommand(recognizer, managePantryCmd)
+	}
+
+	// Opt-in anonymous quality metrics
+	var metrics *telemetry.Collector
+	if cfg.Telemetry.Enabled {
+		metrics = telemetry.NewCollector()
+		log.Printf("Sending anonymous quality metrics to %s every %d minutes", cfg.Telemetry.Endpoint, cfg.Telemetry.Interval)
+	}
 
 	// Initialize handler
 	handler := telegram.NewHandler(telegram.HandlerConfig{
-		Bot:                      bot,
-		ProcessRecipeLinkCommand: processRecipeLinkCmd,
-		GetOrCreateUserCommand:   getOrCreateUserCmd,
-		ListRecipesQuery:         listRecipesQuery,
-		MatchIngredientsCommand:  matchIngredientsCmd,
-		ManagePantryCommand:      managePantryCmd,
-		ExportRecipeCommand:      exportRecipeCmd,
-		IntentDetector:           intentDetector,
-		UserRepo:                 userRepo,
-		LLM:                      llmAdapter,
+		Bot:                        bot,
+		ProcessRecipeLinkCommand:   processRecipeLinkCmd,
+		GetOrCreateUserCommand:     getOrCreateUserCmd,
+		ListRecipesQuery:           listRecipesQuery,
+		MatchIngredientsCommand:    matchIngredientsCmd,
+		ManagePantryCommand:        managePantryCmd,
+		ExportRecipeCommand:        exportRecipeCmd,
+		RecipeHistoryCommand:       recipeHistoryCmd,
+		ManageMealPlanCommand:      manageMealPlanCmd,
+		ShoppingListCommand:        shoppingListCmd,
+		ScanBarcodeCommand:         scanBarcodeCmd,
+		NutritionCommand:           nutritionCmd,
+		AchievementsCommand:        achievementsCmd,
+		SimplifyRecipeCommand:      simplifyRecipeCmd,
+		PlanMenuCommand:            planMenuCmd,
+		CookingTimelineCommand:     cookingTimelineCmd,
+		CookTogetherCommand:        cookTogetherCmd,
+		CategorizeRecipeCommand:    categorizeRecipeCmd,
+		ConvertRecipeCommand:       convertRecipeCmd,
+		RemixRecipeCommand:         remixRecipeCmd,
+		RecipeAudioCommand:         recipeAudioCmd,
+		GenerateCoverCommand:       generateCoverCmd,
+		PremiumCommand:             premiumCmd,
+		LinkQuota:                  linkQuota,
+		ManageFreezerCommand:       manageFreezerCmd,
+		ManageRequestsCommand:      manageRequestsCmd,
+		SavedFiltersCommand:        savedFiltersCmd,
+		ShortcutsCommand:           shortcutsCmd,
+		LearnClarificationsCommand: learnClarificationsCmd,
+		NotificationsCommand:       notificationsCmd,
+		StaplesCommand:             staplesCmd,
+		CheckDietCommand:           checkDietCmd,
+		ExportFieldsCommand:        exportFieldsCmd,
+		RecreateDishCommand:        recreateDishCmd,
+		ScanPantryPhotoCommand:     scanPantryPhotoCmd,
+		LinkAccountCommand:         linkAccountCmd,
+		ShareCollectionCommand:     shareCollectionCmd,
+		ReportErrorCommand:         reportErrorCmd,
+		AutoExportCommand:          autoExportCmd,
+		CloudStorageCommand:        cloudStorageCmd,
+		ActivityLogCommand:         activityLogCmd,
+		TranscriptCommand:          transcriptCmd,
+		UsageAnalyticsCommand:      command.NewUsageAnalyticsCommand(usageRepo),
+		ImportBookmarksCommand:     importBookmarksCmd,
+		ClipRecipeCommand:          clipRecipeCmd,
+		ForwardEmailCommand:        forwardEmailCmd,
+		ManageSubscriptionsCommand: manageSubscriptionsCmd,
+		ModerateContentCommand:     moderateContentCmd,
+		BrowseSharedQuery:          browseSharedQuery,
+		WebAppURL:                  cfg.Telegram.WebAppURL,
+		ClipAPIURL:                 cfg.Clip.URL,
+		PaymentProviderToken:       cfg.Payments.ProviderToken,
+		AdminChatID:                cfg.Telegram.AdminChatID,
+		GroupPantry:                cfg.Telegram.GroupPantry,
+		IntentDetector:             intentDetector,
+		UserRepo:                   userRepo,
+		LLM:                        llmAdapter,
+		Features:                   featureService,
+		Telemetry:                  metrics,
+		Durations:                  telemetry.NewDurations(),
+		ProcessingReports:          processingRepo,
+		UpdateTimeout:              time.Duration(cfg.Telegram.UpdateTimeout) * time.Second,
+		MessageWindow:              time.Duration(cfg.Telegram.MessageWindow) * time.Millisecond,
 	})
 
+	// Serve the Mini App and its API when it has a public URL, the browser extension
+	// API when it has a secret, the inbound email webhook when it has a domain and
+	// the cloud storage OAuth callbacks when a provider is configured, on the same port
+	var webServer *http.Server
+	mux := http.NewServeMux()
+	if cfg.Telegram.WebAppURL != "" {
+		miniApp := webapp.NewServer(webapp.Config{BotToken: cfg.Telegram.BotToken, Blobs: blobs}, userRepo, listRecipesQuery, featureService)
+		mux.Handle("/", miniApp.Handler())
+		log.Printf("Serving the Mini App on port %d for %s", cfg.App.Port, cfg.Telegram.WebAppURL)
+	}
+	if clipRecipeCmd != nil {
+		clipAPI := clipapi.NewServer(clipapi.Config{
+			RecipeLink: func(recipeID string) string { return telegram.RecipeDeepLink(bot.Username(), recipeID) },
+		}, clipRecipeCmd, activityLogCmd)
+		mux.Handle("/api/v1/", clipAPI.Handler())
+		log.Printf("Serving the browser extension API on port %d", cfg.App.Port)
+	}
+	if forwardEmailCmd != nil {
+		inbound := inboundmail.NewServer(inboundmail.Config{SigningKey: cfg.Email.SigningKey}, forwardEmailCmd, bot, activityLogCmd)
+		mux.Handle("POST /api/v1/inbound-email", inbound.Handler())
+		log.Printf("Receiving forwarded emails for %s on port %d", cfg.Email.Domain, cfg.App.Port)
+	}
+	if cloudStorageCmd != nil {
+		mux.Handle("GET /oauth/", integrations.NewServer(cloudStorageCmd, bot).Handler())
+		log.Printf("Receiving cloud storage OAuth callbacks on port %d", cfg.App.Port)
+	}
+	if cfg.Telegram.WebAppURL != "" || clipRecipeCmd != nil || forwardEmailCmd != nil || cloudStorageCmd != nil {
+		webServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.App.Port),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := webServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Web server stopped: %v", err)
+			}
+		}()
+	}
+
+	// Send the daily and weekly notifications users opted into
+	scheduler := telegram.NewScheduler(telegram.SchedulerConfig{
+		Bot:                  bot,
+		UserRepo:             userRepo,
+		ListRecipesQuery:     listRecipesQuery,
+		ManageFreezerCommand: manageFreezerCmd,
+		ActivityLogCommand:   activityLogCmd,
+		NutritionCommand:     nutritionCmd,
+		AchievementsCommand:  achievementsCmd,
+	})
+	schedulerCtx, stopScheduler := context.WithCancel(ctx)
+	go scheduler.Run(schedulerCtx)
+
+	// Send the recipes of new posts of the followed recipe blogs
+	feedPoller := telegram.NewFeedPoller(telegram.FeedPollerConfig{
+		Bot:                        bot,
+		UserRepo:                   userRepo,
+		ManageSubscriptionsCommand: manageSubscriptionsCmd,
+	})
+	go feedPoller.Run(schedulerCtx)
+
+	// Export the recipes users save to Notion or Obsidian, as they chose in /autoexport
+	autoExporter := telegram.NewAutoExporter(telegram.AutoExporterConfig{
+		Bot:                bot,
+		UserRepo:           userRepo,
+		AutoExportCommand:  autoExportCmd,
+		ActivityLogCommand: activityLogCmd,
+	})
+	go autoExporter.Run(schedulerCtx)
+
+	// Strip the transcripts of recipes past retention
+	if cfg.App.TranscriptRetentionDays > 0 {
+		retention := time.Duration(cfg.App.TranscriptRetentionDays) * 24 * time.Hour
+		go stripOldTranscripts(schedulerCtx, command.NewTranscriptRetentionCommand(recipeRepo, retention), 24*time.Hour)
+	}
+
+	// Compare the prompt experiment variants
+	if experiments != nil {
+		go reportExperiments(schedulerCtx, experiments, time.Hour)
+	}
+
+	// Send the opt-in anonymous quality metrics
+	telemetryDone := make(chan struct{})
+	if metrics != nil {
+		sender := webhook.NewTelemetrySender(cfg.Telemetry.Endpoint)
+		go func() {
+			sendTelemetry(schedulerCtx, metrics, sender, time.Duration(cfg.Telemetry.Interval)*time.Minute)
+			close(telemetryDone)
+		}()
+	} else {
+		close(telemetryDone)
+	}
+
 	// Setup graceful shutdown
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -167,11 +770,13 @@ func main() {
 
 	updates := bot.GetUpdatesChan()
 
-	// Main loop
+	// Main loop. Canceling updatesCtx stops the updates being handled along with
+	// the imports and reminders updates started.
+	updatesCtx, stopUpdates := context.WithCancel(ctx)
+	updatesDone := make(chan struct{})
 	go func() {
-		for update := range updates {
-			handler.HandleUpdate(update)
-		}
+		handler.Serve(updatesCtx, updates, cfg.Telegram.UpdateWorkers)
+		close(updatesDone)
 	}()
 
 	// Wait for shutdown signal
@@ -179,5 +784,334 @@ func main() {
 
 	log.Println("Shutting down gracefully...")
 	bot.Stop()
+	stopUpdates()
+	select {
+	case <-updatesDone:
+	case <-time.After(10 * time.Second):
+		log.Println("Gave up waiting for the updates being handled")
+	}
+	stopScheduler()
+	<-telemetryDone
+	if webServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = webServer.Shutdown(shutdownCtx)
+		cancel()
+	}
 	log.Println("Goodbye!")
 }
+
+// newBlobStorage connects to the bucket large content is stored in, nil if none is configured
+func newBlobStorage(ctx context.Context, cfg config.BlobStorageConfig) ports.BlobStorage {
+	switch cfg.Provider {
+	case "gcs":
+		storage, err := gcs.NewStorage(ctx, cfg.Bucket)
+		if err != nil {
+			log.Fatalf("Failed to initialize GCS storage: %v", err)
+		}
+		log.Printf("Storing large content in GCS bucket %s", cfg.Bucket)
+		return storage
+	case "s3":
+		storage, err := s3.NewStorage(s3.Config{
+			Bucket:          cfg.Bucket,
+			Region:          cfg.Region,
+			Endpoint:        cfg.Endpoint,
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize S3 storage: %v", err)
+		}
+		log.Printf("Storing large content in S3 bucket %s", cfg.Bucket)
+		return storage
+	}
+	return nil
+}
+
+// preflightTimeout bounds each check of the readiness report
+const preflightTimeout = 20 * time.Second
+
+// preflightResult is one line of the readiness report
+type preflightResult struct {
+	name    string
+	err     error
+	detail  string // what was found, or why the check was skipped
+	skipped bool
+}
+
+// preflight checks every configured integration without sending or saving
+// anything, prints a readiness report to w and returns whether all checks passed
+func preflight(ctx context.Context, cfg *config.Config, w io.Writer) bool {
+	check := func(name string, run func(ctx context.Context) (string, error)) preflightResult {
+		ctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+		defer cancel()
+		detail, err := run(ctx)
+		return preflightResult{name: name, err: err, detail: detail}
+	}
+	skip := func(name, reason string) preflightResult {
+		return preflightResult{name: name, detail: reason, skipped: true}
+	}
+
+	var results []preflightResult
+
+	results = append(results, check("Telegram token", func(ctx context.Context) (string, error) {
+		username, err := telegram.CheckToken(ctx, telegram.Config{BotToken: cfg.Telegram.BotToken})
+		return "authorized as @" + username, err
+	}))
+
+	// Firestore: access, then the composite indexes the queries need. Clients are
+	// created with ctx as they outlive each check's deadline.
+	var firebaseClient *firebase.Client
+	var clientErr error
+	switch {
+	case cfg.App.Sandbox && cfg.Firebase.EmulatorHost == "":
+		results = append(results, skip("Firestore access", "sandbox mode keeps data in memory"))
+	case cfg.App.Sandbox:
+		firebaseClient, clientErr = firebase.NewEmulatorClient(ctx, cfg.Firebase.ProjectID, cfg.Firebase.EmulatorHost)
+	default:
+		firebaseClient, clientErr = firebase.NewClient(ctx, firebase.Config{
+			ProjectID:       cfg.Firebase.ProjectID,
+			CredentialsPath: cfg.Firebase.CredentialsPath,
+		})
+	}
+	if firebaseClient != nil {
+		defer firebaseClient.Close()
+	}
+	firestoreReady := false
+	if firebaseClient != nil || clientErr != nil {
+		results = append(results, check("Firestore access", func(ctx context.Context) (string, error) {
+			if clientErr != nil {
+				return "", clientErr
+			}
+			if err := firebaseClient.CheckAccess(ctx); err != nil {
+				return "", err
+			}
+			firestoreReady = true
+			if cfg.App.Sandbox {
+				return "emulator at " + cfg.Firebase.EmulatorHost, nil
+			}
+			return "project " + cfg.Firebase.ProjectID, nil
+		}))
+	}
+	switch {
+	case cfg.App.Sandbox:
+		// The emulator builds indexes as queries need them
+	case firestoreReady:
+		results = append(results, check("Firestore indexes", func(ctx context.Context) (string, error) {
+			missing, err := firebaseClient.MissingIndexes(ctx)
+			if err != nil {
+				return "", err
+			}
+			if len(missing) > 0 {
+				return "", fmt.Errorf("missing %s; deploy them with `firebase deploy --only firestore:indexes`", strings.Join(missing, "; "))
+			}
+			return "all composite indexes present", nil
+		}))
+	default:
+		results = append(results, skip("Firestore indexes", "Firestore is not reachable"))
+	}
+
+	if cfg.App.Sandbox {
+		results = append(results,
+			skip("Python service", "sandbox mode uses recorded fixtures"),
+			skip("LLM", "sandbox mode uses recorded fixtures"))
+	} else {
+		results = append(results, check("Python service", func(ctx context.Context) (string, error) {
+			err := python.CheckService(ctx, cfg.Python.URL)
+			return "reachable at " + cfg.Python.URL, err
+		}))
+		results = append(results, check("LLM", func(ctx context.Context) (string, error) {
+			models, err := llm.CheckModels(ctx, llm.LLMConfig{
+				Provider:      cfg.LLM.Provider,
+				APIKey:        cfg.LLM.APIKey,
+				Model:         cfg.LLM.Model,
+				BaseURL:       cfg.LLM.BaseURL,
+				ExtractTask:   llmTask(cfg.LLM.ExtractTask),
+				TranslateTask: llmTask(cfg.LLM.TranslateTask),
+				IntentTask:    llmTask(cfg.LLM.IntentTask),
+			})
+			return cfg.LLM.Provider + " serves " + strings.Join(models, ", "), err
+		}))
+	}
+
+	if cfg.Notion.ClientID != "" && cfg.Notion.ClientSecret != "" {
+		results = append(results, check("Notion", func(ctx context.Context) (string, error) {
+			client := notion.NewClient(notion.Config{
+				ClientID:     cfg.Notion.ClientID,
+				ClientSecret: cfg.Notion.ClientSecret,
+				RedirectURI:  cfg.Notion.RedirectURI,
+			})
+			return "client credentials accepted", client.CheckCredentials(ctx)
+		}))
+	} else {
+		results = append(results, skip("Notion", "not configured"))
+	}
+
+	return printReadiness(w, results)
+}
+
+// printReadiness writes the readiness report and returns whether no check failed
+func printReadiness(w io.Writer, results []preflightResult) bool {
+	fmt.Fprintln(w, "Readiness report")
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	failed := 0
+	for _, result := range results {
+		switch {
+		case result.skipped:
+			fmt.Fprintf(table, "  -\t%s\tskipped: %s\n", result.name, result.detail)
+		case result.err != nil:
+			failed++
+			fmt.Fprintf(table, "  ✗\t%s\t%v\n", result.name, result.err)
+		default:
+			fmt.Fprintf(table, "  ✓\t%s\t%s\n", result.name, result.detail)
+		}
+	}
+	table.Flush()
+
+	if failed > 0 {
+		fmt.Fprintf(w, "Not ready: %d of %d checks failed\n", failed, len(results))
+		return false
+	}
+	fmt.Fprintln(w, "Ready")
+	return true
+}
+
+// llmTask converts the configured model and temperature of a kind of LLM call
+func llmTask(cfg config.TaskConfig) llm.Task {
+	return llm.Task{Model: cfg.Model, Temperature: cfg.Temperature}
+}
+
+// promptExperiment loads the alternate prompt of an experiment, nil if it is off
+func promptExperiment(cfg config.ExperimentConfig) *llm.PromptExperiment {
+	if cfg.Variant == "" {
+		return nil
+	}
+
+	var prompt string
+	if cfg.PromptFile != "" {
+		data, err := os.ReadFile(cfg.PromptFile)
+		if err != nil {
+			log.Fatalf("Failed to read experiment prompt %s: %v", cfg.PromptFile, err)
+		}
+		prompt = string(data)
+	}
+
+	log.Printf("Prompt experiment %q enabled for %d%% of calls", cfg.Variant, cfg.Percent)
+	return &llm.PromptExperiment{
+		Variant: cfg.Variant,
+		Percent: cfg.Percent,
+		Model:   cfg.Model,
+		Prompt:  prompt,
+	}
+}
+
+// newIngredientNormalizer creates the ingredient normalizer, resolving the synonyms
+// of the operator's file, if any, besides the built-in ones
+func newIngredientNormalizer(path string) *matching.RuleBasedNormalizer {
+	if path == "" {
+		return matching.NewRuleBasedNormalizer(nil)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open ingredient synonyms %s: %v", path, err)
+	}
+	defer file.Close()
+
+	synonyms, err := matching.ParseSynonyms(file)
+	if err != nil {
+		log.Fatalf("Failed to read ingredient synonyms %s: %v", path, err)
+	}
+	log.Printf("Loaded %d ingredient synonyms", len(synonyms))
+	return matching.NewRuleBasedNormalizer(synonyms)
+}
+
+// newDietChecker creates the checker of /diet, with the rules of the file at path
+// replacing or adding to the built-in ones
+func newDietChecker(path string) *diet.Checker {
+	checker := diet.NewChecker()
+	if path == "" {
+		return checker
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open diet rules %s: %v", path, err)
+	}
+	defer file.Close()
+
+	rules, err := diet.ParseRules(file)
+	if err != nil {
+		log.Fatalf("Failed to read diet rules %s: %v", path, err)
+	}
+	checker.Configure(rules)
+	log.Printf("Loaded rules for %d diets", len(rules))
+	return checker
+}
+
+// stripOldTranscripts strips the transcripts past retention on start and then periodically
+func stripOldTranscripts(ctx context.Context, retention *command.TranscriptRetentionCommand, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		stripped, err := retention.Execute(ctx)
+		if err != nil {
+			log.Printf("Failed to strip old transcripts: %v", err)
+		} else if stripped > 0 {
+			log.Printf("Stripped the transcripts of %d recipes past retention", stripped)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportExperiments logs the failure and parse error rates of every variant periodically
+func reportExperiments(ctx context.Context, tracker *experiment.Tracker, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, stats := range tracker.Report() {
+				log.Printf("Prompt experiment %s", stats)
+			}
+		}
+	}
+}
+
+// sendTelemetry sends the collected metrics periodically, and once more when ctx is done.
+// Batches that fail to send are kept for the next one.
+func sendTelemetry(ctx context.Context, metrics *telemetry.Collector, sender ports.TelemetrySender, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	send := func(ctx context.Context) {
+		report := metrics.Flush()
+		if report.IsEmpty() {
+			return
+		}
+		if err := sender.Send(ctx, report); err != nil {
+			log.Printf("Failed to send telemetry: %v", err)
+			metrics.Restore(report)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			finalCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			send(finalCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			send(ctx)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package anylist
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/ports"
+)
+
+// Exporter implements the AppExporter interface for AnyList's text recipe import
+type Exporter struct{}
+
+// NewExporter creates a new AnyList exporter
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// recipeSeparator separates recipes in a multi-recipe export
+const recipeSeparator = "\n==========\n\n"
+
+// ExportRecipe exports a single recipe as text AnyList can import
+func (e *Exporter) ExportRecipe(rec *recipe.Recipe) (*ports.ExportResult, error) {
+	return &ports.ExportResult{
+		Success:  true,
+		Format:   "anylist",
+		Filename: sanitizeFilename(rec.Title()) + ".txt",
+		Data:     []byte(e.generateText(rec)),
+		Message:  fmt.Sprintf("Recipe exported for AnyList: %s. Paste the text into Add Recipe → Import from Text.", rec.Title()),
+	}, nil
+}
+
+// ExportRecipes exports multiple recipes into one text file, one recipe per section
+func (e *Exporter) ExportRecipes(recipes []*recipe.Recipe, loc *time.Location) (*ports.ExportResult, error) {
+	if len(recipes) == 0 {
+		return &ports.ExportResult{
+			Success: false,
+			Format:  "anylist",
+			Message: "No recipes to export",
+		}, nil
+	}
+
+	sections := make([]string, len(recipes))
+	for i, rec := range recipes {
+		sections[i] = e.generateText(rec)
+	}
+
+	return &ports.ExportResult{
+		Success:  true,
+		Format:   "anylist",
+		Filename: fmt.Sprintf("anylist_recipes_%s.txt", time.Now().In(loc).Format("2006-01-02")),
+		Data:     []byte(strings.Join(sections, recipeSeparator)),
+		Message:  fmt.Sprintf("Exported %d recipes for AnyList. Paste each section into Add Recipe → Import from Text.", len(recipes)),
+	}, nil
+}
+
+// generateText lays a recipe out the way AnyList's text import expects:
+// title first, then metadata lines, then Ingredients and Directions sections and
+// a Notes section crediting the recipe's creator
+func (e *Exporter) generateText(rec *recipe.Recipe) string {
+	var sb strings.Builder
+
+	sb.WriteString(rec.Title() + "\n\n")
+
+	if rec.Servings() != nil {
+		sb.WriteString(fmt.Sprintf("Servings: %d\n", *rec.Servings()))
+	}
+	if rec.PrepTime() != nil {
+		sb.WriteString(fmt.Sprintf("Prep Time: %d minutes\n", int(rec.PrepTime().Minutes())))
+	}
+	if rec.CookTime() != nil {
+		sb.WriteString(fmt.Sprintf("Cook Time: %d minutes\n", int(rec.CookTime().Minutes())))
+	}
+	if rec.Source().URL() != "" {
+		sb.WriteString(fmt.Sprintf("Source: %s\n", rec.Source().OriginalURL()))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("Ingredients\n")
+	for _, ing := range rec.Ingredients() {
+		line := strings.TrimSpace(strings.Join([]string{ing.Quantity(), ing.Unit(), ing.Name()}, " "))
+		if ing.Notes() != "" {
+			line += ", " + ing.Notes()
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("Directions\n")
+	for i, inst := range rec.Instructions() {
+		stepNum := inst.StepNumber()
+		if stepNum == 0 {
+			stepNum = i + 1
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s\n", stepNum, inst.Text()))
+	}
+
+	if credit := rec.Source().Credit(); credit != "" {
+		sb.WriteString("\nNotes\n" + credit + "\n")
+	}
+
+	return sb.String()
+}
+
+// sanitizeFilename creates a safe filename from a recipe title
+func sanitizeFilename(title string) string {
+	safe := regexp.MustCompile(`[<>:"/\\|?*]`).ReplaceAllString(title, "")
+	safe = strings.ReplaceAll(safe, " ", "_")
+	if len(safe) > 100 {
+		safe = safe[:100]
+	}
+	safe = strings.Trim(safe, "_")
+	if safe == "" {
+		safe = "recipe"
+	}
+	return safe
+}
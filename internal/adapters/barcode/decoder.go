@@ -0,0 +1,322 @@
+// Package barcode decodes retail product barcodes (EAN-13, UPC-A and EAN-8) from photos.
+package barcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG, the format Telegram uses for photos
+	_ "image/png"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// scanLines is the number of rows (and columns) sampled across the image
+const scanLines = 24
+
+// maxDigitError is the largest total deviation, in modules, accepted when matching a digit
+const maxDigitError = 1.6
+
+// digitWidths holds the bar/space widths of the EAN "L" (odd parity) digit patterns.
+// "G" patterns are the same widths reversed, "R" patterns use the same widths as "L".
+var digitWidths = [10][4]float64{
+	{3, 2, 1, 1},
+	{2, 2, 2, 1},
+	{2, 1, 2, 2},
+	{1, 4, 1, 1},
+	{1, 1, 3, 2},
+	{1, 2, 3, 1},
+	{1, 1, 1, 4},
+	{1, 3, 1, 2},
+	{1, 2, 1, 3},
+	{3, 1, 1, 2},
+}
+
+// firstDigitParity maps the L/G parity pattern of the left half of an EAN-13 to its first digit.
+// The leftmost digit is the highest of the six bits; a set bit means the G pattern.
+var firstDigitParity = map[int]int{
+	0b000000: 0, 0b001011: 1, 0b001101: 2, 0b001110: 3, 0b010011: 4,
+	0b011001: 5, 0b011100: 6, 0b010101: 7, 0b010110: 8, 0b011010: 9,
+}
+
+// Decoder implements the ports.BarcodeDecoder interface with a scanline decoder.
+// It handles barcodes that are roughly horizontal or vertical, in either direction.
+type Decoder struct{}
+
+// NewDecoder creates a new barcode decoder
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// run is a stretch of consecutive dark (bar) or light (space) pixels
+type run struct {
+	bar   bool
+	width float64
+}
+
+// DecodeBarcode implements the BarcodeDecoder interface
+func (d *Decoder) DecodeBarcode(data []byte) (string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	for _, line := range scanlines(img) {
+		if code, ok := decodeLine(line); ok {
+			return code, nil
+		}
+		reverse(line)
+		if code, ok := decodeLine(line); ok {
+			return code, nil
+		}
+	}
+
+	return "", shared.ErrNoBarcode
+}
+
+// scanlines returns the luminance of evenly spaced rows, then columns, starting from the middle
+func scanlines(img image.Image) [][]uint8 {
+	b := img.Bounds()
+	var lines [][]uint8
+
+	for _, offset := range centerOut(scanLines) {
+		y := b.Min.Y + b.Dy()*offset/scanLines
+		line := make([]uint8, b.Dx())
+		for x := b.Min.X; x < b.Max.X; x++ {
+			line[x-b.Min.X] = luminance(img, x, y)
+		}
+		lines = append(lines, line)
+	}
+
+	for _, offset := range centerOut(scanLines) {
+		x := b.Min.X + b.Dx()*offset/scanLines
+		line := make([]uint8, b.Dy())
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			line[y-b.Min.Y] = luminance(img, x, y)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// centerOut returns 1..n-1 ordered from the middle outwards, since barcodes are usually centered
+func centerOut(n int) []int {
+	mid := n / 2
+	order := []int{mid}
+	for d := 1; d < n; d++ {
+		if mid-d > 0 {
+			order = append(order, mid-d)
+		}
+		if mid+d < n {
+			order = append(order, mid+d)
+		}
+	}
+	return order
+}
+
+func luminance(img image.Image, x, y int) uint8 {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return uint8((299*r + 587*g + 114*b) / 1000 >> 8)
+}
+
+func reverse(line []uint8) {
+	for i, j := 0, len(line)-1; i < j; i, j = i+1, j-1 {
+		line[i], line[j] = line[j], line[i]
+	}
+}
+
+// toRuns binarizes a scanline halfway between its darkest and lightest pixel
+func toRuns(line []uint8) []run {
+	if len(line) == 0 {
+		return nil
+	}
+
+	lo, hi := line[0], line[0]
+	for _, v := range line {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	if hi-lo < 32 {
+		return nil // no contrast, nothing to read
+	}
+	threshold := (int(lo) + int(hi)) / 2
+
+	var runs []run
+	for _, v := range line {
+		bar := int(v) < threshold
+		if len(runs) > 0 && runs[len(runs)-1].bar == bar {
+			runs[len(runs)-1].width++
+			continue
+		}
+		runs = append(runs, run{bar: bar, width: 1})
+	}
+	return runs
+}
+
+// decodeLine looks for an EAN-13 or EAN-8 barcode in a scanline
+func decodeLine(line []uint8) (string, bool) {
+	runs := toRuns(line)
+	for i := 1; i < len(runs); i++ {
+		if !runs[i].bar {
+			continue
+		}
+		if code, ok := decodeEAN13(runs[i:]); ok {
+			return code, true
+		}
+		if code, ok := decodeEAN8(runs[i:]); ok {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// decodeEAN13 decodes an EAN-13 (or UPC-A) barcode whose start guard is the first run:
+// 3 guard runs, 6 digits of 4 runs, 5 middle guard runs, 6 digits, 3 guard runs.
+func decodeEAN13(runs []run) (string, bool) {
+	const total, modules = 59, 95
+	if len(runs) < total {
+		return "", false
+	}
+	runs = runs[:total]
+	module := sumWidths(runs) / modules
+
+	if !isGuard(runs[0:3], module) || !isGuard(runs[27:32], module) || !isGuard(runs[56:59], module) {
+		return "", false
+	}
+
+	digits := make([]byte, 13)
+	parity := 0
+	for i := 0; i < 6; i++ {
+		digit, g, ok := matchDigit(runs[3+4*i:7+4*i], true)
+		if !ok {
+			return "", false
+		}
+		digits[1+i] = byte('0' + digit)
+		if g {
+			parity |= 1 << (5 - i)
+		}
+	}
+	for i := 0; i < 6; i++ {
+		digit, _, ok := matchDigit(runs[32+4*i:36+4*i], false)
+		if !ok {
+			return "", false
+		}
+		digits[7+i] = byte('0' + digit)
+	}
+
+	first, ok := firstDigitParity[parity]
+	if !ok {
+		return "", false
+	}
+	digits[0] = byte('0' + first)
+
+	code := string(digits)
+	return code, validChecksum(code)
+}
+
+// decodeEAN8 decodes an EAN-8 barcode whose start guard is the first run
+func decodeEAN8(runs []run) (string, bool) {
+	const total, modules = 43, 67
+	if len(runs) < total {
+		return "", false
+	}
+	runs = runs[:total]
+	module := sumWidths(runs) / modules
+
+	if !isGuard(runs[0:3], module) || !isGuard(runs[19:24], module) || !isGuard(runs[40:43], module) {
+		return "", false
+	}
+
+	digits := make([]byte, 8)
+	for i := 0; i < 4; i++ {
+		digit, g, ok := matchDigit(runs[3+4*i:7+4*i], true)
+		if !ok || g {
+			return "", false
+		}
+		digits[i] = byte('0' + digit)
+	}
+	for i := 0; i < 4; i++ {
+		digit, _, ok := matchDigit(runs[24+4*i:28+4*i], false)
+		if !ok {
+			return "", false
+		}
+		digits[4+i] = byte('0' + digit)
+	}
+
+	code := string(digits)
+	return code, validChecksum(code)
+}
+
+// isGuard reports whether every run is about one module wide
+func isGuard(runs []run, module float64) bool {
+	for _, r := range runs {
+		if r.width < module*0.5 || r.width > module*1.5 {
+			return false
+		}
+	}
+	return true
+}
+
+// matchDigit finds the digit whose pattern best matches four runs.
+// When allowG is set, mirrored G patterns are tried as well and g reports which matched.
+func matchDigit(runs []run, allowG bool) (digit int, g bool, ok bool) {
+	scale := 7 / sumWidths(runs)
+	best := maxDigitError
+	digit = -1
+
+	for d, widths := range digitWidths {
+		if e := patternError(runs, widths, scale, false); e < best {
+			best, digit, g = e, d, false
+		}
+		if allowG {
+			if e := patternError(runs, widths, scale, true); e < best {
+				best, digit, g = e, d, true
+			}
+		}
+	}
+
+	return digit, g, digit >= 0
+}
+
+func patternError(runs []run, widths [4]float64, scale float64, reversed bool) float64 {
+	var total float64
+	for i, r := range runs {
+		w := widths[i]
+		if reversed {
+			w = widths[3-i]
+		}
+		diff := r.width*scale - w
+		if diff < 0 {
+			diff = -diff
+		}
+		total += diff
+	}
+	return total
+}
+
+func sumWidths(runs []run) float64 {
+	var total float64
+	for _, r := range runs {
+		total += r.width
+	}
+	return total
+}
+
+// validChecksum verifies the EAN check digit: weights alternate 3 and 1 from the right
+func validChecksum(code string) bool {
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		n := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			n *= 3
+		}
+		sum += n
+	}
+	check := (10 - sum%10) % 10
+	return check == int(code[len(code)-1]-'0')
+}
@@ -0,0 +1,161 @@
+package barcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// encodeEAN13 returns the module pattern (true = bar) of an EAN-13 code
+func encodeEAN13(t *testing.T, code string) []bool {
+	t.Helper()
+
+	// L/G patterns of the left half, by first digit
+	parities := [10]string{
+		"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+		"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+	}
+	parity := parities[code[0]-'0']
+
+	var modules []bool
+	appendWidths := func(widths [4]float64, bar bool, reversed bool) {
+		for i := 0; i < 4; i++ {
+			w := widths[i]
+			if reversed {
+				w = widths[3-i]
+			}
+			for j := 0; j < int(w); j++ {
+				modules = append(modules, bar)
+			}
+			bar = !bar
+		}
+	}
+	guard := func(pattern ...bool) { modules = append(modules, pattern...) }
+
+	guard(true, false, true)
+	for i := 0; i < 6; i++ {
+		g := parity[i] == 'G'
+		appendWidths(digitWidths[code[1+i]-'0'], false, g)
+	}
+	guard(false, true, false, true, false)
+	for i := 0; i < 6; i++ {
+		appendWidths(digitWidths[code[7+i]-'0'], true, false)
+	}
+	guard(true, false, true)
+
+	if len(modules) != 95 {
+		t.Fatalf("encoded %d modules, want 95", len(modules))
+	}
+	return modules
+}
+
+// renderBarcode draws the modules on a white image, moduleWidth pixels per module
+func renderBarcode(modules []bool, moduleWidth int, vertical bool) image.Image {
+	quiet := 10 * moduleWidth
+	length := len(modules)*moduleWidth + 2*quiet
+	height := 120
+
+	w, h := length, height
+	if vertical {
+		w, h = height, length
+	}
+
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			pos := x
+			if vertical {
+				pos = y
+			}
+			c := color.Gray{Y: 235}
+			if m := (pos - quiet) / moduleWidth; pos >= quiet && m < len(modules) && modules[m] {
+				c = color.Gray{Y: 25}
+			}
+			img.SetGray(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecoder_DecodeBarcode(t *testing.T) {
+	const code = "8076800195057"
+
+	tests := []struct {
+		name string
+		img  func(t *testing.T) []byte
+	}{
+		{"horizontal png", func(t *testing.T) []byte {
+			return encodePNG(t, renderBarcode(encodeEAN13(t, code), 3, false))
+		}},
+		{"vertical png", func(t *testing.T) []byte {
+			return encodePNG(t, renderBarcode(encodeEAN13(t, code), 2, true))
+		}},
+		{"upside down", func(t *testing.T) []byte {
+			modules := encodeEAN13(t, code)
+			for i, j := 0, len(modules)-1; i < j; i, j = i+1, j-1 {
+				modules[i], modules[j] = modules[j], modules[i]
+			}
+			return encodePNG(t, renderBarcode(modules, 3, false))
+		}},
+		{"jpeg", func(t *testing.T) []byte {
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, renderBarcode(encodeEAN13(t, code), 4, false), &jpeg.Options{Quality: 75}); err != nil {
+				t.Fatalf("jpeg.Encode() error = %v", err)
+			}
+			return buf.Bytes()
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDecoder().DecodeBarcode(tt.img(t))
+			if err != nil {
+				t.Fatalf("DecodeBarcode() error = %v", err)
+			}
+			if got != code {
+				t.Errorf("DecodeBarcode() = %q, want %q", got, code)
+			}
+		})
+	}
+}
+
+func TestDecoder_NoBarcode(t *testing.T) {
+	blank := image.NewGray(image.Rect(0, 0, 200, 100))
+	_, err := NewDecoder().DecodeBarcode(encodePNG(t, blank))
+	if !errors.Is(err, shared.ErrNoBarcode) {
+		t.Errorf("DecodeBarcode() error = %v, want ErrNoBarcode", err)
+	}
+
+	if _, err := NewDecoder().DecodeBarcode([]byte("not an image")); err == nil {
+		t.Error("DecodeBarcode() should fail on invalid image data")
+	}
+}
+
+func TestValidChecksum(t *testing.T) {
+	tests := map[string]bool{
+		"8076800195057": true,
+		"8076800195058": false,
+		"96385074":      true,
+		"036000291452":  true, // UPC-A
+	}
+	for code, want := range tests {
+		if got := validChecksum(code); got != want {
+			t.Errorf("validChecksum(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package bookmarks reads the bookmark files browsers export, in the Netscape
+// bookmark format used by Chrome, Firefox, Safari and Edge.
+package bookmarks
+
+import (
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+
+	"receipt-bot/internal/domain/bookmark"
+)
+
+// ErrNotBookmarkFile is returned for files that are not a Netscape bookmark export
+var ErrNotBookmarkFile = errors.New("not a bookmark export")
+
+// doctype is the first line of every Netscape bookmark file
+const doctype = "<!doctype netscape-bookmark-file-1>"
+
+var (
+	linkPattern = regexp.MustCompile(`(?is)<a\s[^>]*?href\s*=\s*"([^"]*)"[^>]*>(.*?)</a>`)
+	tagPattern  = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ParseNetscape returns the bookmarks of a Netscape bookmark file, in file order.
+// Folders are flattened.
+func ParseNetscape(data []byte) ([]bookmark.Bookmark, error) {
+	if !strings.Contains(strings.ToLower(string(data)), doctype) {
+		return nil, ErrNotBookmarkFile
+	}
+
+	matches := linkPattern.FindAllSubmatch(data, -1)
+	bookmarks := make([]bookmark.Bookmark, 0, len(matches))
+	for _, m := range matches {
+		link := strings.TrimSpace(html.UnescapeString(string(m[1])))
+		if link == "" {
+			continue
+		}
+		title := tagPattern.ReplaceAllString(string(m[2]), "")
+		bookmarks = append(bookmarks, bookmark.Bookmark{
+			URL:   link,
+			Title: strings.TrimSpace(html.UnescapeString(title)),
+		})
+	}
+
+	return bookmarks, nil
+}
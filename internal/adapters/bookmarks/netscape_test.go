@@ -0,0 +1,45 @@
+package bookmarks
+
+import (
+	"errors"
+	"testing"
+)
+
+const chromeExport = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file. -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3 ADD_DATE="1700000000">Cooking</H3>
+    <DL><p>
+        <DT><A HREF="https://www.seriouseats.com/pasta-carbonara" ADD_DATE="1700000001">Pasta Carbonara &amp; Tips</A>
+        <DT><A HREF="https://example.com/?a=1&amp;b=2" ICON="data:image/png;base64,AAA">Example</A>
+    </DL><p>
+    <DT><A HREF="">Empty</A>
+</DL><p>
+`
+
+func TestParseNetscape(t *testing.T) {
+	got, err := ParseNetscape([]byte(chromeExport))
+	if err != nil {
+		t.Fatalf("ParseNetscape() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("ParseNetscape() = %+v, want 2 bookmarks", got)
+	}
+	if got[0].URL != "https://www.seriouseats.com/pasta-carbonara" || got[0].Title != "Pasta Carbonara & Tips" {
+		t.Errorf("first bookmark = %+v", got[0])
+	}
+	if got[1].URL != "https://example.com/?a=1&b=2" {
+		t.Errorf("second bookmark URL = %q, want entities decoded", got[1].URL)
+	}
+}
+
+func TestParseNetscape_NotBookmarks(t *testing.T) {
+	_, err := ParseNetscape([]byte(`<html><body><a href="https://example.com">x</a></body></html>`))
+	if !errors.Is(err, ErrNotBookmarkFile) {
+		t.Errorf("ParseNetscape() error = %v, want %v", err, ErrNotBookmarkFile)
+	}
+}
@@ -0,0 +1,38 @@
+package clipapi
+
+import (
+	"strings"
+
+	"receipt-bot/internal/adapters/htmltext"
+	"receipt-bot/internal/ports"
+)
+
+// maxPageText caps the text read from a clipped page, well above any recipe page
+const maxPageText = 100000
+
+// pageContent turns the HTML of a clipped page into the content the recipe
+// pipeline reads: its visible text and structured data, with its title, author and
+// canonical link
+func pageContent(link, page string) *ports.ScrapeResult {
+	metadata := make(map[string]string)
+	if title := htmltext.Title(page); title != "" {
+		metadata["title"] = title
+	}
+	if author := htmltext.Author(page); author != "" {
+		metadata["author"] = author
+	}
+	if canonical := htmltext.CanonicalURL(page); canonical != "" {
+		metadata["canonical_url"] = canonical
+	}
+
+	text := htmltext.Text(page)
+	if len(text) > maxPageText {
+		text = strings.ToValidUTF8(text[:maxPageText], "")
+	}
+
+	return &ports.ScrapeResult{
+		Captions:    text,
+		OriginalURL: link,
+		Metadata:    metadata,
+	}
+}
@@ -0,0 +1,153 @@
+// Package clipapi serves the HTTP API of the browser extension companion: the
+// extension posts the page the user is on and gets back a link to the saved recipe
+// in Telegram. Requests are authenticated with the API token the bot gives users.
+package clipapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// maxRequestBytes caps a clip request, page HTML included
+const maxRequestBytes = 5 << 20
+
+// Config holds clip API configuration
+type Config struct {
+	// RecipeLink returns the link opening a recipe in the bot
+	RecipeLink func(recipeID string) string
+}
+
+// Server serves the clip API
+type Server struct {
+	recipeLink         func(recipeID string) string
+	clipRecipeCommand  *command.ClipRecipeCommand
+	activityLogCommand *command.ActivityLogCommand // optional, clips are not logged when nil
+}
+
+// NewServer creates a new clip API server
+func NewServer(config Config, clipRecipeCommand *command.ClipRecipeCommand, activityLogCommand *command.ActivityLogCommand) *Server {
+	return &Server{
+		recipeLink:         config.RecipeLink,
+		clipRecipeCommand:  clipRecipeCommand,
+		activityLogCommand: activityLogCommand,
+	}
+}
+
+// Handler returns the HTTP handler for the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/clip", s.handleClip)
+	return mux
+}
+
+// clipRequest is a page clipped by the extension
+type clipRequest struct {
+	URL  string `json:"url"`
+	HTML string `json:"html,omitempty"` // the page as the browser rendered it, scraped from the URL when empty
+}
+
+// clipResponse is the recipe saved from a clipped page
+type clipResponse struct {
+	RecipeID string `json:"recipeId"`
+	Title    string `json:"title"`
+	Link     string `json:"link"` // opens the recipe in Telegram
+}
+
+// handleClip runs the recipe pipeline on a clipped page
+func (s *Server) handleClip(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing API token, send /clip to the bot to get yours")
+		return
+	}
+	usr, err := s.clipRecipeCommand.Authenticate(r.Context(), token)
+	if errors.Is(err, shared.ErrInvalidAPIToken) {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Clip API user lookup failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load user")
+		return
+	}
+
+	var req clipRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var page *ports.ScrapeResult
+	if strings.TrimSpace(req.HTML) != "" {
+		page = pageContent(req.URL, req.HTML)
+	}
+
+	rec, err := s.clipRecipeCommand.Execute(r.Context(), usr.ID(), req.URL, page)
+	if err != nil {
+		status, message := clipError(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("Clip failed for %s: %v", req.URL, err)
+		}
+		writeError(w, status, message)
+		return
+	}
+
+	s.recordClip(r.Context(), usr.ID(), rec.Title())
+	writeJSON(w, http.StatusOK, clipResponse{
+		RecipeID: rec.ID().String(),
+		Title:    rec.Title(),
+		Link:     s.recipeLink(rec.ID().String()),
+	})
+}
+
+// recordClip logs the saved recipe in the user's activity
+func (s *Server) recordClip(ctx context.Context, userID user.UserID, title string) {
+	if s.activityLogCommand == nil {
+		return
+	}
+	if err := s.activityLogCommand.Record(ctx, userID, activity.ActionRecipeSaved, title); err != nil {
+		log.Printf("Failed to record clip activity: %v", err)
+	}
+}
+
+// clipError maps a pipeline error to a status and a message for the extension
+func clipError(err error) (int, string) {
+	switch {
+	case errors.Is(err, shared.ErrInvalidURL):
+		return http.StatusBadRequest, "url must be an http(s) link"
+	case errors.Is(err, shared.ErrMultipleRecipes):
+		return http.StatusUnprocessableEntity, "the page holds several recipes, send the link to the bot to choose which to save"
+	case errors.Is(err, shared.ErrNoContent), errors.Is(err, shared.ErrNoIngredients), errors.Is(err, shared.ErrNoInstructions):
+		return http.StatusUnprocessableEntity, "no recipe found on the page"
+	case errors.Is(err, shared.ErrScrapeFailed):
+		return http.StatusBadGateway, "failed to download the page"
+	case errors.Is(err, shared.ErrLLMTimeout), errors.Is(err, shared.ErrQuotaExceeded):
+		return http.StatusServiceUnavailable, "the recipe service is busy, try again later"
+	default:
+		return http.StatusInternalServerError, "failed to save the recipe"
+	}
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Failed to write clip API response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
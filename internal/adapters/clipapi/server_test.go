@@ -0,0 +1,201 @@
+package clipapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/adapters/sandbox"
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/user"
+)
+
+const testSecret = "clip-test-secret-of-32-characters"
+
+type testServer struct {
+	handler  http.Handler
+	clip     *command.ClipRecipeCommand
+	recipes  *memory.RecipeRepository
+	activity *command.ActivityLogCommand
+	user     *user.User
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	users := memory.NewUserRepository()
+	usr, _ := user.NewUser(1001, "alice")
+	if err := users.Save(context.Background(), usr); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	fixtures, err := sandbox.LoadFixtures("")
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+	recipes := memory.NewRecipeRepository()
+	process := command.NewProcessRecipeLinkCommand(sandbox.NewScraper(fixtures), sandbox.NewLLM(fixtures), recipe.NewService(), recipes, nil)
+	clip := command.NewClipRecipeCommand(process, users, testSecret)
+	activityLog := command.NewActivityLogCommand(memory.NewActivityRepository())
+
+	server := NewServer(Config{
+		RecipeLink: func(recipeID string) string { return "https://t.me/test_bot?start=recipe_" + recipeID },
+	}, clip, activityLog)
+
+	return &testServer{handler: server.Handler(), clip: clip, recipes: recipes, activity: activityLog, user: usr}
+}
+
+// post clips a page with the given token and returns the status and JSON body
+func (s *testServer) post(t *testing.T, token, body string) (int, map[string]string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/clip", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+
+	var decoded map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("POST /api/v1/clip: invalid JSON: %v", err)
+	}
+	return rec.Code, decoded
+}
+
+func TestServer_Auth(t *testing.T) {
+	s := newTestServer(t)
+	body := `{"url":"https://www.youtube.com/watch?v=sandbox-carbonara"}`
+	other := command.NewClipRecipeCommand(nil, nil, "another-secret-of-32-characters!!")
+	stranger, _ := user.NewUser(2002, "mallory")
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"valid", s.clip.Token(s.user), http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"signed by another server", other.Token(s.user), http.StatusUnauthorized},
+		{"unknown user", s.clip.Token(stranger), http.StatusUnauthorized},
+		{"malformed", "not-a-token", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := s.post(t, tt.token, body); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_Auth_ResetToken(t *testing.T) {
+	s := newTestServer(t)
+	body := `{"url":"https://www.youtube.com/watch?v=sandbox-carbonara"}`
+
+	old := s.clip.Token(s.user)
+	token, err := s.clip.ResetToken(context.Background(), s.user)
+	if err != nil {
+		t.Fatalf("ResetToken() error = %v", err)
+	}
+	if token == old {
+		t.Fatal("ResetToken() returned the previous token")
+	}
+
+	if got, _ := s.post(t, old, body); got != http.StatusUnauthorized {
+		t.Errorf("status with the previous token = %d, want %d", got, http.StatusUnauthorized)
+	}
+	if got, _ := s.post(t, token, body); got != http.StatusOK {
+		t.Errorf("status with the new token = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestServer_Clip(t *testing.T) {
+	s := newTestServer(t)
+	token := s.clip.Token(s.user)
+
+	status, body := s.post(t, token, `{"url":"https://www.youtube.com/watch?v=sandbox-carbonara"}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want %d (%v)", status, http.StatusOK, body)
+	}
+	if body["title"] != "Spaghetti Carbonara" || body["link"] != "https://t.me/test_bot?start=recipe_"+body["recipeId"] {
+		t.Errorf("response = %v", body)
+	}
+
+	entries, err := s.activity.Recent(context.Background(), s.user.ID())
+	if err != nil || len(entries) != 1 || entries[0].Action != activity.ActionRecipeSaved {
+		t.Errorf("activity = %+v, %v, want the clip recorded as a save", entries, err)
+	}
+}
+
+func TestServer_ClipPageHTML(t *testing.T) {
+	s := newTestServer(t)
+	page := `<html><head><title>Carbonara | My Blog</title><meta name="author" content="Nonna"></head>
+<body><script>track()</script><h1>Spaghetti Carbonara</h1><p>Boil the spaghetti &amp; crisp the guanciale.</p></body></html>`
+	req, _ := json.Marshal(map[string]string{"url": "https://blog.example.com/carbonara", "html": page})
+
+	status, body := s.post(t, s.clip.Token(s.user), string(req))
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want %d (%v)", status, http.StatusOK, body)
+	}
+
+	rec, err := s.recipes.FindBySourceURL(context.Background(), "https://blog.example.com/carbonara")
+	if err != nil {
+		t.Fatalf("FindBySourceURL() error = %v", err)
+	}
+	if rec.Source().Author() != "Nonna" {
+		t.Errorf("author = %q, want the page's author", rec.Source().Author())
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	s := newTestServer(t)
+	token := s.clip.Token(s.user)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"invalid JSON", `{"url":`, http.StatusBadRequest},
+		{"not a link", `{"url":"javascript:alert(1)"}`, http.StatusBadRequest},
+		{"several recipes", `{"url":"https://www.youtube.com/watch?v=sandbox-breakfasts"}`, http.StatusUnprocessableEntity},
+		{"no recipe", `{"url":"https://www.instagram.com/reel/sandbox-vlog/"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, body := s.post(t, token, tt.body); got != tt.want {
+				t.Errorf("status = %d, want %d (%v)", got, tt.want, body)
+			}
+		})
+	}
+}
+
+func TestPageContent(t *testing.T) {
+	page := `<html><head><title>Pão de queijo</title>
+<script type="application/ld+json">{"@type":"Recipe","name":"Pão de queijo"}</script>
+<style>p { color: red }</style></head>
+<body><ul><li>500 g polvilho</li><li>2 ovos</li></ul><p>Asse por 25&nbsp;minutos.</p></body></html>`
+
+	got := pageContent("https://example.com/pao", page)
+
+	if got.Metadata["title"] != "Pão de queijo" {
+		t.Errorf("title = %q", got.Metadata["title"])
+	}
+	for _, want := range []string{`"@type":"Recipe"`, "500 g polvilho\n2 ovos", "Asse por 25"} {
+		if !strings.Contains(got.Captions, want) {
+			t.Errorf("text = %q, want it to contain %q", got.Captions, want)
+		}
+	}
+	if strings.Contains(got.Captions, "color") {
+		t.Errorf("text = %q, want styles dropped", got.Captions)
+	}
+}
@@ -0,0 +1,258 @@
+package crouton
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/ports"
+)
+
+// Exporter implements the AppExporter interface for Crouton's .crumb files
+type Exporter struct{}
+
+// NewExporter creates a new Crouton exporter
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// crumb is the JSON document Crouton imports from a .crumb file
+type crumb struct {
+	UUID            string            `json:"uuid"`
+	Name            string            `json:"name"`
+	Serves          int               `json:"serves,omitempty"`
+	Duration        int               `json:"duration,omitempty"`
+	CookingDuration int               `json:"cookingDuration,omitempty"`
+	WebLink         string            `json:"webLink,omitempty"`
+	SourceName      string            `json:"sourceName,omitempty"`
+	Notes           string            `json:"notes,omitempty"`
+	Tags            []string          `json:"tags"`
+	Ingredients     []crumbIngredient `json:"ingredients"`
+	Steps           []crumbStep       `json:"steps"`
+	Images          []string          `json:"images"`
+	FolderIDs       []string          `json:"folderIDs"`
+	DefaultScale    int               `json:"defaultScale"`
+}
+
+type crumbIngredient struct {
+	UUID       string         `json:"uuid"`
+	Order      int            `json:"order"`
+	Ingredient crumbName      `json:"ingredient"`
+	Quantity   *crumbQuantity `json:"quantity,omitempty"`
+}
+
+type crumbName struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+type crumbQuantity struct {
+	Amount       float64 `json:"amount"`
+	QuantityType string  `json:"quantityType"`
+}
+
+type crumbStep struct {
+	UUID      string `json:"uuid"`
+	Order     int    `json:"order"`
+	Step      string `json:"step"`
+	IsSection bool   `json:"isSection"`
+}
+
+// quantityTypes maps common units to Crouton's quantity types
+var quantityTypes = map[string]string{
+	"g": "GRAMS", "gram": "GRAMS", "grams": "GRAMS",
+	"kg": "KGS", "kilogram": "KGS", "kilograms": "KGS",
+	"ml": "MILLS", "milliliter": "MILLS", "milliliters": "MILLS",
+	"l": "LITRES", "liter": "LITRES", "liters": "LITRES", "litre": "LITRES", "litres": "LITRES",
+	"cup": "CUP", "cups": "CUP",
+	"tbsp": "TABLESPOON", "tablespoon": "TABLESPOON", "tablespoons": "TABLESPOON",
+	"tsp": "TEASPOON", "teaspoon": "TEASPOON", "teaspoons": "TEASPOON",
+	"oz": "OUNCE", "ounce": "OUNCE", "ounces": "OUNCE",
+	"lb": "POUND", "lbs": "POUND", "pound": "POUND", "pounds": "POUND",
+	"pinch": "PINCH", "can": "CAN", "cans": "CAN", "bunch": "BUNCH",
+	"": "ITEM",
+}
+
+// ExportRecipe exports a single recipe as a .crumb file
+func (e *Exporter) ExportRecipe(rec *recipe.Recipe) (*ports.ExportResult, error) {
+	data, err := e.generateCrumb(rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ports.ExportResult{
+		Success:  true,
+		Format:   "crouton",
+		Filename: sanitizeFilename(rec.Title()) + ".crumb",
+		Data:     data,
+		Message:  fmt.Sprintf("Recipe exported for Crouton: %s. Open the file on your phone to import it.", rec.Title()),
+	}, nil
+}
+
+// ExportRecipes exports multiple recipes as a ZIP of .crumb files
+func (e *Exporter) ExportRecipes(recipes []*recipe.Recipe, loc *time.Location) (*ports.ExportResult, error) {
+	if len(recipes) == 0 {
+		return &ports.ExportResult{
+			Success: false,
+			Format:  "crouton",
+			Message: "No recipes to export",
+		}, nil
+	}
+
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	used := make(map[string]int)
+
+	for _, rec := range recipes {
+		data, err := e.generateCrumb(rec)
+		if err != nil {
+			return nil, err
+		}
+
+		// Recipes with the same title still need distinct zip entries
+		name := sanitizeFilename(rec.Title())
+		used[name]++
+		if used[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, used[name])
+		}
+
+		writer, err := zipWriter.Create(name + ".crumb")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zip entry: %w", err)
+		}
+		if _, err := writer.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to write zip entry: %w", err)
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close zip: %w", err)
+	}
+
+	return &ports.ExportResult{
+		Success:  true,
+		Format:   "crouton",
+		Filename: fmt.Sprintf("crouton_recipes_%s.zip", time.Now().In(loc).Format("2006-01-02")),
+		Data:     buf.Bytes(),
+		Message:  fmt.Sprintf("Exported %d recipes for Crouton. Unzip and open the .crumb files to import them.", len(recipes)),
+	}, nil
+}
+
+// generateCrumb builds the .crumb JSON for a recipe
+func (e *Exporter) generateCrumb(rec *recipe.Recipe) ([]byte, error) {
+	id := rec.ID().String()
+	doc := crumb{
+		UUID:         stableUUID(id),
+		Name:         rec.Title(),
+		WebLink:      rec.Source().OriginalURL(),
+		SourceName:   rec.Source().Creator(),
+		Notes:        rec.Source().Credit(),
+		Tags:         append([]string{string(rec.Category())}, rec.Tags()...),
+		Ingredients:  make([]crumbIngredient, 0, len(rec.Ingredients())),
+		Steps:        make([]crumbStep, 0, len(rec.Instructions())),
+		Images:       []string{},
+		FolderIDs:    []string{},
+		DefaultScale: 1,
+	}
+
+	if rec.Servings() != nil {
+		doc.Serves = *rec.Servings()
+	}
+	if rec.PrepTime() != nil {
+		doc.Duration = int(rec.PrepTime().Minutes())
+	}
+	if rec.CookTime() != nil {
+		doc.CookingDuration = int(rec.CookTime().Minutes())
+	}
+
+	for i, ing := range rec.Ingredients() {
+		entry := crumbIngredient{
+			UUID:       stableUUID(fmt.Sprintf("%s/ingredient/%d", id, i)),
+			Order:      i,
+			Ingredient: crumbName{UUID: stableUUID("ingredient/" + strings.ToLower(ing.Name())), Name: ing.Name()},
+		}
+
+		// Quantities Crouton cannot represent stay readable in the name
+		amount, ok := parseAmount(ing.Quantity())
+		quantityType, known := quantityTypes[strings.ToLower(strings.TrimSuffix(ing.Unit(), "."))]
+		if ok && known {
+			entry.Quantity = &crumbQuantity{Amount: amount, QuantityType: quantityType}
+		} else {
+			entry.Ingredient.Name = strings.TrimSpace(strings.Join([]string{ing.Quantity(), ing.Unit(), ing.Name()}, " "))
+		}
+		if ing.Notes() != "" {
+			entry.Ingredient.Name += fmt.Sprintf(" (%s)", ing.Notes())
+		}
+		doc.Ingredients = append(doc.Ingredients, entry)
+	}
+
+	for i, inst := range rec.Instructions() {
+		doc.Steps = append(doc.Steps, crumbStep{
+			UUID:  stableUUID(fmt.Sprintf("%s/step/%d", id, i)),
+			Order: i,
+			Step:  inst.Text(),
+		})
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode crumb: %w", err)
+	}
+	return data, nil
+}
+
+// parseAmount parses quantities like "2", "1.5", "1/2" and "1 1/2"
+func parseAmount(quantity string) (float64, bool) {
+	var total float64
+	fields := strings.Fields(strings.ReplaceAll(quantity, ",", "."))
+	if len(fields) == 0 {
+		return 0, false
+	}
+	for _, field := range fields {
+		if num, den, found := strings.Cut(field, "/"); found {
+			n, err1 := strconv.ParseFloat(num, 64)
+			d, err2 := strconv.ParseFloat(den, 64)
+			if err1 != nil || err2 != nil || d == 0 {
+				return 0, false
+			}
+			total += n / d
+			continue
+		}
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return 0, false
+		}
+		total += v
+	}
+	return total, true
+}
+
+// stableUUID derives a UUID-shaped identifier from a key so that exporting the
+// same recipe twice updates it in Crouton instead of duplicating it
+func stableUUID(key string) string {
+	sum := sha1.Sum([]byte(key))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return strings.ToUpper(fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16]))
+}
+
+// sanitizeFilename creates a safe filename from a recipe title
+func sanitizeFilename(title string) string {
+	safe := regexp.MustCompile(`[<>:"/\\|?*]`).ReplaceAllString(title, "")
+	safe = strings.ReplaceAll(safe, " ", "_")
+	if len(safe) > 100 {
+		safe = safe[:100]
+	}
+	safe = strings.Trim(safe, "_")
+	if safe == "" {
+		safe = "recipe"
+	}
+	return safe
+}
@@ -0,0 +1,166 @@
+// Package dropbox writes exported recipes into a folder of a user's Dropbox
+package dropbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	dropboxAuthURL   = "https://www.dropbox.com/oauth2/authorize"
+	dropboxTokenURL  = "https://api.dropboxapi.com/oauth2/token"
+	dropboxUploadURL = "https://content.dropboxapi.com/2/files/upload"
+)
+
+// Config holds Dropbox OAuth configuration
+type Config struct {
+	AppKey      string
+	AppSecret   string
+	RedirectURI string
+}
+
+// Client is the Dropbox API client
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewClient creates a new Dropbox API client
+func NewClient(config Config) *Client {
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// tokenResponse represents the OAuth token response
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// AuthURL generates the OAuth authorization URL, asking for a refresh token
+func (c *Client) AuthURL(state string) string {
+	params := url.Values{}
+	params.Set("client_id", c.config.AppKey)
+	params.Set("redirect_uri", c.config.RedirectURI)
+	params.Set("response_type", "code")
+	params.Set("token_access_type", "offline")
+	params.Set("state", state)
+
+	return fmt.Sprintf("%s?%s", dropboxAuthURL, params.Encode())
+}
+
+// ExchangeCode exchanges an authorization code for a refresh token
+func (c *Client) ExchangeCode(ctx context.Context, code string) (string, error) {
+	token, err := c.token(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.config.RedirectURI},
+	})
+	if err != nil {
+		return "", err
+	}
+	if token.RefreshToken == "" {
+		return "", fmt.Errorf("token exchange returned no refresh token")
+	}
+	return token.RefreshToken, nil
+}
+
+// Upload writes a file into a folder, replacing a file of the same name.
+// Dropbox creates missing folders on upload.
+func (c *Client) Upload(ctx context.Context, refreshToken, folder, filename string, data []byte) error {
+	token, err := c.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return err
+	}
+
+	arg, err := json.Marshal(map[string]any{
+		"path":       path.Join("/", folder, filename),
+		"mode":       "overwrite",
+		"autorename": false,
+		"mute":       true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload arguments: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", dropboxUploadURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Dropbox-API-Arg", headerSafe(string(arg)))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("upload failed: %s", string(body))
+	}
+	return nil
+}
+
+// token requests an access token from the token endpoint
+func (c *Client) token(ctx context.Context, form url.Values) (*tokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", dropboxTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(c.config.AppKey, c.config.AppSecret)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("token request failed: %s", string(body))
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &token, nil
+}
+
+// headerSafe escapes the characters outside ASCII in a JSON header value, since
+// Dropbox-API-Arg must be ASCII ("Crème Brûlée.md")
+func headerSafe(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r < 0x80:
+			b.WriteRune(r)
+		case r > 0xFFFF:
+			r1, r2 := utf16.EncodeRune(r)
+			fmt.Fprintf(&b, `\u%04x\u%04x`, r1, r2)
+		default:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ActivityRepository implements the activity.Repository interface using Firestore.
+// Entries live in the users/{userId}/activity subcollection, keyed by entry ID.
+// Each entry carries an expiresAt field so a Firestore TTL policy on it can enforce
+// retention even for users who never come back.
+type ActivityRepository struct {
+	client *firestore.Client
+}
+
+// NewActivityRepository creates a new Firebase activity repository
+func NewActivityRepository(client *firestore.Client) *ActivityRepository {
+	return &ActivityRepository{
+		client: client,
+	}
+}
+
+// activityDoc represents the Firestore document structure of an audit entry
+type activityDoc struct {
+	Action    string    `firestore:"action"`
+	Detail    string    `firestore:"detail,omitempty"`
+	CreatedAt time.Time `firestore:"createdAt"`
+	ExpiresAt time.Time `firestore:"expiresAt"`
+}
+
+func (r *ActivityRepository) entries(userID activity.UserID) *firestore.CollectionRef {
+	return r.client.Collection("users").Doc(userID.String()).Collection("activity")
+}
+
+// Append stores a new entry
+func (r *ActivityRepository) Append(ctx context.Context, e *activity.Entry) error {
+	doc := activityDoc{
+		Action:    string(e.Action),
+		Detail:    e.Detail,
+		CreatedAt: e.CreatedAt,
+		ExpiresAt: e.CreatedAt.Add(activity.Retention),
+	}
+
+	// Create rather than Set, so an existing entry is never overwritten
+	_, err := r.entries(e.UserID).Doc(e.ID.String()).Create(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save activity: %w", err)
+	}
+
+	return nil
+}
+
+// FindByUser retrieves the entries of a user recorded since the given time, newest first
+func (r *ActivityRepository) FindByUser(ctx context.Context, userID activity.UserID, since time.Time) ([]*activity.Entry, error) {
+	iter := r.entries(userID).
+		Where("createdAt", ">=", since).
+		OrderBy("createdAt", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var entries []*activity.Entry
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate activity: %w", err)
+		}
+
+		var doc activityDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse activity: %w", err)
+		}
+		entries = append(entries, &activity.Entry{
+			ID:        shared.ID(snap.Ref.ID),
+			UserID:    userID,
+			Action:    activity.Action(doc.Action),
+			Detail:    doc.Detail,
+			CreatedAt: doc.CreatedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+// DeleteBefore removes the entries of a user recorded before the cutoff
+func (r *ActivityRepository) DeleteBefore(ctx context.Context, userID activity.UserID, cutoff time.Time) (int, error) {
+	iter := r.entries(userID).
+		Where("createdAt", "<", cutoff).
+		Documents(ctx)
+	defer iter.Stop()
+
+	removed := 0
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to iterate activity: %w", err)
+		}
+
+		if _, err := snap.Ref.Delete(ctx); err != nil {
+			return removed, fmt.Errorf("failed to delete activity: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
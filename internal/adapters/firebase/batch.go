@@ -0,0 +1,100 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxBatchWrites is the most writes Firestore accepts in one batch
+	maxBatchWrites = 500
+
+	// maxBatchSize is the most bytes of documents written in one batch. Firestore
+	// refuses commits over 10 MiB; the margin covers what size estimates miss.
+	maxBatchSize = 9 << 20
+
+	// batchAttempts is how many times a batch is committed before giving up
+	batchAttempts = 4
+)
+
+// batchRetryDelay is the pause before the first retry of a batch, doubled after each
+var batchRetryDelay = 500 * time.Millisecond
+
+// batchWrite sets one document in a batch
+type batchWrite struct {
+	ref  *firestore.DocumentRef
+	data any
+	size int // estimated encoded size of data, 0 when unknown
+}
+
+// writeBatches sets documents in batches of up to maxBatchWrites writes and
+// maxBatchSize bytes rather than one write at a time, retrying batches that fail
+// with transient errors. A batch is applied entirely or not at all, so retrying
+// it is safe. Returns how many writes were committed before an error.
+func writeBatches(ctx context.Context, client *firestore.Client, writes []batchWrite) (int, error) {
+	written := 0
+	for _, batch := range splitBatches(writes) {
+		if err := commitBatch(ctx, client, batch); err != nil {
+			return written, err
+		}
+		written += len(batch)
+	}
+	return written, nil
+}
+
+// splitBatches splits writes into batches Firestore accepts: at most
+// maxBatchWrites writes and, unless a single write is larger, maxBatchSize bytes
+func splitBatches(writes []batchWrite) [][]batchWrite {
+	var batches [][]batchWrite
+	start, size := 0, 0
+	for i, w := range writes {
+		if i > start && (i-start == maxBatchWrites || size+w.size > maxBatchSize) {
+			batches = append(batches, writes[start:i])
+			start, size = i, 0
+		}
+		size += w.size
+	}
+	if start < len(writes) {
+		batches = append(batches, writes[start:])
+	}
+	return batches
+}
+
+// commitBatch commits one batch, retrying transient errors with exponential backoff
+func commitBatch(ctx context.Context, client *firestore.Client, writes []batchWrite) error {
+	delay := batchRetryDelay
+	for attempt := 1; ; attempt++ {
+		batch := client.Batch()
+		for _, w := range writes {
+			batch.Set(w.ref, w.data)
+		}
+		_, err := batch.Commit(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt == batchAttempts || !retryable(err) {
+			return fmt.Errorf("failed to commit batch of %d writes: %w", len(writes), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// retryable reports whether a Firestore error is worth retrying
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Aborted, codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package firebase
+
+import "testing"
+
+func TestSplitBatches(t *testing.T) {
+	sizes := func(batches [][]batchWrite) []int {
+		lens := make([]int, len(batches))
+		for i, batch := range batches {
+			lens[i] = len(batch)
+		}
+		return lens
+	}
+
+	// Small documents are split by count only
+	small := make([]batchWrite, 1200)
+	for i := range small {
+		small[i].size = 2 << 10
+	}
+	if got := sizes(splitBatches(small)); len(got) != 3 || got[0] != 500 || got[1] != 500 || got[2] != 200 {
+		t.Errorf("splitBatches() of small writes = %v, want [500 500 200]", got)
+	}
+
+	// Documents near the size limit fill a batch long before 500 writes
+	large := make([]batchWrite, 30)
+	for i := range large {
+		large[i].size = maxDocumentSize
+	}
+	batches := splitBatches(large)
+	total := 0
+	for _, batch := range batches {
+		bytes := 0
+		for _, w := range batch {
+			bytes += w.size
+		}
+		if bytes > maxBatchSize {
+			t.Errorf("batch of %d writes holds %d bytes, over %d", len(batch), bytes, maxBatchSize)
+		}
+		total += len(batch)
+	}
+	if total != len(large) || len(batches) < 4 {
+		t.Errorf("splitBatches() of large writes = %v, want all 30 writes in at least 4 batches", sizes(batches))
+	}
+
+	if got := splitBatches(nil); len(got) != 0 {
+		t.Errorf("splitBatches(nil) = %v, want no batches", got)
+	}
+}
@@ -74,6 +74,32 @@ func NewClient(ctx context.Context, config Config) (*Client, error) {
 	}, nil
 }
 
+// NewEmulatorClient creates a Firestore-only client for the emulator at host
+// (e.g. "localhost:8081"). No credentials are needed.
+func NewEmulatorClient(ctx context.Context, projectID, host string) (*Client, error) {
+	if host == "" {
+		return nil, fmt.Errorf("Firestore emulator host is required")
+	}
+
+	// The Firestore client detects the emulator through this variable
+	if err := os.Setenv("FIRESTORE_EMULATOR_HOST", host); err != nil {
+		return nil, fmt.Errorf("failed to configure Firestore emulator: %w", err)
+	}
+
+	if projectID == "" {
+		projectID = "sandbox"
+	}
+
+	firestoreClient, err := firestore.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Firestore emulator: %w", err)
+	}
+
+	return &Client{
+		firestore: firestoreClient,
+	}, nil
+}
+
 // Close closes all Firebase connections
 func (c *Client) Close() error {
 	if c.firestore != nil {
@@ -0,0 +1,115 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"receipt-bot/internal/domain/conversation"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ConversationRepository implements the conversation.Repository interface using
+// Firestore. Turns live in the users/{userId}/conversation subcollection, keyed by
+// turn ID, with an expiresAt field for a Firestore TTL policy like activity entries.
+type ConversationRepository struct {
+	client *firestore.Client
+}
+
+// NewConversationRepository creates a new Firebase conversation repository
+func NewConversationRepository(client *firestore.Client) *ConversationRepository {
+	return &ConversationRepository{
+		client: client,
+	}
+}
+
+// conversationDoc represents the Firestore document structure of a turn
+type conversationDoc struct {
+	Role      string    `firestore:"role"`
+	Text      string    `firestore:"text"`
+	CreatedAt time.Time `firestore:"createdAt"`
+	ExpiresAt time.Time `firestore:"expiresAt"`
+}
+
+func (r *ConversationRepository) turns(userID conversation.UserID) *firestore.CollectionRef {
+	return r.client.Collection("users").Doc(userID.String()).Collection("conversation")
+}
+
+// Append stores a new turn
+func (r *ConversationRepository) Append(ctx context.Context, t *conversation.Turn) error {
+	doc := conversationDoc{
+		Role:      string(t.Role),
+		Text:      t.Text,
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.CreatedAt.Add(conversation.Retention),
+	}
+
+	_, err := r.turns(t.UserID).Doc(t.ID.String()).Create(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save conversation turn: %w", err)
+	}
+
+	return nil
+}
+
+// FindByUser retrieves the turns of a user said since the given time, newest first
+func (r *ConversationRepository) FindByUser(ctx context.Context, userID conversation.UserID, since time.Time) ([]*conversation.Turn, error) {
+	iter := r.turns(userID).
+		Where("createdAt", ">=", since).
+		OrderBy("createdAt", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var turns []*conversation.Turn
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate conversation: %w", err)
+		}
+
+		var doc conversationDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse conversation turn: %w", err)
+		}
+		turns = append(turns, &conversation.Turn{
+			ID:        shared.ID(snap.Ref.ID),
+			UserID:    userID,
+			Role:      conversation.Role(doc.Role),
+			Text:      doc.Text,
+			CreatedAt: doc.CreatedAt,
+		})
+	}
+
+	return turns, nil
+}
+
+// DeleteBefore removes the turns of a user said before the cutoff
+func (r *ConversationRepository) DeleteBefore(ctx context.Context, userID conversation.UserID, cutoff time.Time) (int, error) {
+	iter := r.turns(userID).
+		Where("createdAt", "<", cutoff).
+		Documents(ctx)
+	defer iter.Stop()
+
+	removed := 0
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to iterate conversation: %w", err)
+		}
+
+		if _, err := snap.Ref.Delete(ctx); err != nil {
+			return removed, fmt.Errorf("failed to delete conversation turn: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,202 @@
+package firebase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDocumentSize is the largest recipe document saved. Firestore takes at most
+// 1 MiB; the margin covers what estimateDocumentSize gets wrong.
+const maxDocumentSize = 960 << 10
+
+// truncatedNotice ends a transcript or captions cut short to fit the document
+const truncatedNotice = "\n\n[…cut short: too long to store in full]"
+
+// sourceText is a transcript or captions of a recipe document, with the fields
+// it is kept in when it is not stored as plain text
+type sourceText struct {
+	name string
+	text *string
+	gzip *[]byte // gzip-compressed text, nil unless it is stored compressed
+	key  *string // blob key, empty unless it is stored in blob storage
+}
+
+// sourceTexts returns the transcript and captions of a document, transcript first
+func (d *recipeDoc) sourceTexts() []sourceText {
+	return []sourceText{
+		{"transcript", &d.Transcript, &d.TranscriptGzip, &d.TranscriptBlob},
+		{"captions", &d.Captions, &d.CaptionsGzip, &d.CaptionsBlob},
+	}
+}
+
+// hasStoredSources reports whether the transcript or captions are kept outside the
+// plain text fields, so they must be loaded with loadSources
+func (d *recipeDoc) hasStoredSources() bool {
+	return d.TranscriptBlob != "" || d.CaptionsBlob != "" || d.TranscriptGzip != nil || d.CaptionsGzip != nil
+}
+
+// estimateDocumentSize estimates how many bytes Firestore counts for a document.
+// Its JSON encoding overestimates field names and numbers slightly; compressed
+// sources count as their raw bytes rather than the base64 JSON writes.
+func estimateDocumentSize(doc *recipeDoc) int {
+	plain := *doc
+	plain.TranscriptGzip, plain.CaptionsGzip = nil, nil
+	encoded, err := json.Marshal(plain)
+	if err != nil {
+		return maxDocumentSize + 1
+	}
+	return len(encoded) + len(doc.TranscriptGzip) + len(doc.CaptionsGzip)
+}
+
+// fitDocument makes a recipe document fit in Firestore. Long transcripts and
+// captions go to blob storage when it is configured; when the document is still
+// too large they are compressed and, as a last resort, cut short.
+func (r *RecipeRepository) fitDocument(ctx context.Context, doc *recipeDoc) error {
+	if err := r.storeSources(ctx, doc, sourceInlineLimit); err != nil {
+		return err
+	}
+	if estimateDocumentSize(doc) <= maxDocumentSize {
+		return nil
+	}
+
+	if r.blobs != nil {
+		if err := r.storeSources(ctx, doc, 0); err != nil {
+			return err
+		}
+		if estimateDocumentSize(doc) <= maxDocumentSize {
+			return nil
+		}
+	}
+
+	for _, text := range doc.sourceTexts() {
+		if err := compressSource(text); err != nil {
+			return err
+		}
+	}
+	if estimateDocumentSize(doc) <= maxDocumentSize {
+		return nil
+	}
+
+	for _, text := range doc.sourceTexts() {
+		if err := truncateSource(doc, text); err != nil {
+			return err
+		}
+		if estimateDocumentSize(doc) <= maxDocumentSize {
+			log.Printf("Cut the sources of recipe %s short to fit its document", doc.RecipeID)
+			return nil
+		}
+	}
+	return fmt.Errorf("recipe document is about %d bytes, over the %d bytes Firestore takes", estimateDocumentSize(doc), maxDocumentSize)
+}
+
+// compressSource stores a source text gzip-compressed when that makes it smaller
+func compressSource(text sourceText) error {
+	if *text.text == "" {
+		return nil
+	}
+	compressed, err := gzipText(*text.text)
+	if err != nil {
+		return fmt.Errorf("failed to compress recipe %s: %w", text.name, err)
+	}
+	if len(compressed) < len(*text.text) {
+		*text.text, *text.gzip = "", compressed
+	}
+	return nil
+}
+
+// truncateSource cuts a source text short until the document fits, dropping it
+// altogether if it has to
+func truncateSource(doc *recipeDoc, text sourceText) error {
+	full := *text.text
+	if *text.gzip != nil {
+		unzipped, err := gunzipText(*text.gzip)
+		if err != nil {
+			return fmt.Errorf("failed to read compressed recipe %s: %w", text.name, err)
+		}
+		full = unzipped
+	}
+
+	kept := full
+	for kept != "" {
+		size := estimateDocumentSize(doc)
+		if size <= maxDocumentSize {
+			return nil
+		}
+		stored := len(*text.text) + len(*text.gzip)
+		if stored == 0 {
+			return nil
+		}
+
+		// Keep the share of the text that fits, less a little as compression
+		// does not shrink every part of a text alike
+		share := float64(stored-(size-maxDocumentSize)) / float64(stored) * 0.9
+		if share <= 0 {
+			kept = ""
+		} else {
+			kept = cutText(kept, int(float64(len(kept))*share))
+		}
+
+		*text.text, *text.gzip = "", nil
+		if kept != "" {
+			*text.text = kept + truncatedNotice
+			if err := compressSource(text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cutText returns the first n bytes of s, without splitting a character or,
+// when it can, a word
+func cutText(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	cut := s[:n]
+	if space := strings.LastIndexAny(cut, " \n"); space > n*9/10 {
+		cut = cut[:space]
+	}
+	return cut
+}
+
+// gzipText compresses text with gzip
+func gzipText(text string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write([]byte(text)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gunzipText decompresses text compressed with gzipText
+func gunzipText(data []byte) (string, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	text, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
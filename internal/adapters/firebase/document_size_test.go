@@ -0,0 +1,49 @@
+package firebase
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestFitDocument(t *testing.T) {
+	ctx := context.Background()
+	repo := &RecipeRepository{}
+
+	// A long transcript that compresses well is kept in full
+	repeated := strings.Repeat("stir the sauce until it thickens, then ", 40000)
+	doc := &recipeDoc{RecipeID: "long", Transcript: repeated, Captions: "Pasta night"}
+	if err := repo.fitDocument(ctx, doc); err != nil {
+		t.Fatalf("fitDocument() unexpected error = %v", err)
+	}
+	if doc.TranscriptGzip == nil || doc.Transcript != "" {
+		t.Fatalf("expected the transcript compressed, got %d plain bytes", len(doc.Transcript))
+	}
+	if err := repo.loadSources(ctx, doc); err != nil {
+		t.Fatalf("loadSources() unexpected error = %v", err)
+	}
+	if doc.Transcript != repeated || doc.Captions != "Pasta night" {
+		t.Errorf("expected the sources read back in full, got %d bytes of transcript", len(doc.Transcript))
+	}
+
+	// One that does not is cut short
+	random := rand.New(rand.NewSource(1))
+	var sb strings.Builder
+	for sb.Len() < 2<<20 {
+		sb.WriteByte(byte('a' + random.Intn(26)))
+	}
+	doc = &recipeDoc{RecipeID: "huge", Transcript: sb.String()}
+	if err := repo.fitDocument(ctx, doc); err != nil {
+		t.Fatalf("fitDocument() unexpected error = %v", err)
+	}
+	if size := estimateDocumentSize(doc); size > maxDocumentSize {
+		t.Errorf("estimateDocumentSize() = %d, want at most %d", size, maxDocumentSize)
+	}
+	if err := repo.loadSources(ctx, doc); err != nil {
+		t.Fatalf("loadSources() unexpected error = %v", err)
+	}
+	if !strings.HasSuffix(doc.Transcript, truncatedNotice) || !strings.HasPrefix(sb.String(), strings.TrimSuffix(doc.Transcript, truncatedNotice)) {
+		t.Errorf("expected the start of the transcript kept with a notice, got %d bytes", len(doc.Transcript))
+	}
+}
@@ -0,0 +1,58 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"receipt-bot/internal/domain/report"
+)
+
+// ErrorReportRepository implements the report.Repository interface using Firestore.
+// Reports are stored in the errorReports collection, keyed by report ID.
+type ErrorReportRepository struct {
+	client *firestore.Client
+}
+
+// NewErrorReportRepository creates a new Firebase error report repository
+func NewErrorReportRepository(client *firestore.Client) *ErrorReportRepository {
+	return &ErrorReportRepository{
+		client: client,
+	}
+}
+
+// errorReportDoc represents the Firestore document structure
+type errorReportDoc struct {
+	UserID      string    `firestore:"userId"`
+	URL         string    `firestore:"url"`
+	Platform    string    `firestore:"platform"`
+	Stage       string    `firestore:"stage"`
+	Error       string    `firestore:"error"`
+	LLMResponse string    `firestore:"llmResponse,omitempty"`
+	Note        string    `firestore:"note,omitempty"`
+	OccurredAt  time.Time `firestore:"occurredAt"`
+	CreatedAt   time.Time `firestore:"createdAt"`
+}
+
+// Save persists an error report
+func (r *ErrorReportRepository) Save(ctx context.Context, rep *report.ErrorReport) error {
+	doc := errorReportDoc{
+		UserID:      rep.UserID.String(),
+		URL:         rep.Failure.URL,
+		Platform:    rep.Failure.Platform,
+		Stage:       string(rep.Failure.Stage),
+		Error:       rep.Failure.Error,
+		LLMResponse: rep.Failure.LLMResponse,
+		Note:        rep.Note,
+		OccurredAt:  rep.Failure.OccurredAt,
+		CreatedAt:   rep.CreatedAt,
+	}
+
+	_, err := r.client.Collection("errorReports").Doc(rep.ID).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save error report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/recipe"
+)
+
+// ExportCheckpointRepository implements the recipe.ExportCheckpointRepository interface
+// using Firestore. Checkpoints live in the exportCheckpoints collection, one document
+// per user and export target.
+type ExportCheckpointRepository struct {
+	client *firestore.Client
+}
+
+// NewExportCheckpointRepository creates a new Firebase export checkpoint repository
+func NewExportCheckpointRepository(client *firestore.Client) *ExportCheckpointRepository {
+	return &ExportCheckpointRepository{
+		client: client,
+	}
+}
+
+// exportCheckpointDoc represents the Firestore document structure of an export checkpoint
+type exportCheckpointDoc struct {
+	UserID    string    `firestore:"userId"`
+	Target    string    `firestore:"target"`
+	Sent      []string  `firestore:"sent"`
+	UpdatedAt time.Time `firestore:"updatedAt"`
+}
+
+// FindExportCheckpoint returns the recipes an unfinished bulk export already sent
+func (r *ExportCheckpointRepository) FindExportCheckpoint(ctx context.Context, userID recipe.UserID, target string) ([]recipe.RecipeID, error) {
+	snap, err := r.doc(userID, target).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find export checkpoint: %w", err)
+	}
+
+	var doc exportCheckpointDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse export checkpoint document: %w", err)
+	}
+	sent := make([]recipe.RecipeID, len(doc.Sent))
+	for i, id := range doc.Sent {
+		sent[i] = recipe.RecipeID(id)
+	}
+	return sent, nil
+}
+
+// SaveExportCheckpoint replaces the recipes a bulk export already sent
+func (r *ExportCheckpointRepository) SaveExportCheckpoint(ctx context.Context, userID recipe.UserID, target string, sent []recipe.RecipeID) error {
+	if len(sent) == 0 {
+		if _, err := r.doc(userID, target).Delete(ctx); err != nil {
+			return fmt.Errorf("failed to delete export checkpoint: %w", err)
+		}
+		return nil
+	}
+
+	doc := exportCheckpointDoc{
+		UserID:    userID.String(),
+		Target:    target,
+		Sent:      make([]string, len(sent)),
+		UpdatedAt: time.Now(),
+	}
+	for i, id := range sent {
+		doc.Sent[i] = id.String()
+	}
+	if _, err := r.doc(userID, target).Set(ctx, doc); err != nil {
+		return fmt.Errorf("failed to save export checkpoint: %w", err)
+	}
+	return nil
+}
+
+// doc returns the document of the export checkpoint of a user and target
+func (r *ExportCheckpointRepository) doc(userID recipe.UserID, target string) *firestore.DocumentRef {
+	return r.client.Collection("exportCheckpoints").Doc(userID.String() + "_" + target)
+}
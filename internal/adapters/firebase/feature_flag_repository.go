@@ -0,0 +1,91 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"receipt-bot/internal/domain/feature"
+	"receipt-bot/internal/domain/shared"
+)
+
+// featureFlagCacheTTL controls how often overrides are re-read from Firestore
+const featureFlagCacheTTL = time.Minute
+
+// FeatureFlagRepository implements the feature.Repository interface using Firestore.
+// Each document in the featureFlags collection is keyed by flag name.
+// Overrides are cached briefly since they are consulted on every message.
+type FeatureFlagRepository struct {
+	client *firestore.Client
+
+	mu        sync.Mutex
+	cached    []feature.Override
+	fetchedAt time.Time
+}
+
+// NewFeatureFlagRepository creates a new Firebase feature flag repository
+func NewFeatureFlagRepository(client *firestore.Client) *FeatureFlagRepository {
+	return &FeatureFlagRepository{
+		client: client,
+	}
+}
+
+// featureFlagDoc represents the Firestore document structure for flag overrides
+type featureFlagDoc struct {
+	Enabled        bool     `firestore:"enabled"`
+	RolloutPercent int      `firestore:"rolloutPercent,omitempty"`
+	UserIDs        []string `firestore:"userIds,omitempty"`
+}
+
+// FindAll retrieves all overrides, served from cache when fresh
+func (r *FeatureFlagRepository) FindAll(ctx context.Context) ([]feature.Override, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.fetchedAt.IsZero() && time.Since(r.fetchedAt) < featureFlagCacheTTL {
+		return r.cached, nil
+	}
+
+	iter := r.client.Collection("featureFlags").Documents(ctx)
+
+	var overrides []feature.Override
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate feature flags: %w", err)
+		}
+
+		flag, ok := feature.ParseFlag(doc.Ref.ID)
+		if !ok {
+			continue // Skip unknown flags
+		}
+
+		var flagDoc featureFlagDoc
+		if err := doc.DataTo(&flagDoc); err != nil {
+			continue // Skip invalid documents
+		}
+
+		userIDs := make([]shared.ID, len(flagDoc.UserIDs))
+		for i, id := range flagDoc.UserIDs {
+			userIDs[i] = shared.ID(id)
+		}
+
+		overrides = append(overrides, feature.Override{
+			Flag:           flag,
+			Enabled:        flagDoc.Enabled,
+			RolloutPercent: flagDoc.RolloutPercent,
+			UserIDs:        userIDs,
+		})
+	}
+
+	r.cached = overrides
+	r.fetchedAt = time.Now()
+
+	return overrides, nil
+}
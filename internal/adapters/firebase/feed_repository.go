@@ -0,0 +1,104 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"receipt-bot/internal/domain/feed"
+)
+
+// FeedRepository implements the feed.Repository interface using Firestore.
+// Subscriptions are stored in the feedSubscriptions collection, keyed by ID.
+type FeedRepository struct {
+	client *firestore.Client
+}
+
+// NewFeedRepository creates a new Firebase feed subscription repository
+func NewFeedRepository(client *firestore.Client) *FeedRepository {
+	return &FeedRepository{
+		client: client,
+	}
+}
+
+// feedDoc represents the Firestore document structure of a subscription
+type feedDoc struct {
+	UserID    string    `firestore:"userId"`
+	URL       string    `firestore:"url"`
+	Title     string    `firestore:"title"`
+	Seen      []string  `firestore:"seen"`
+	CreatedAt time.Time `firestore:"createdAt"`
+}
+
+// Save persists a subscription
+func (r *FeedRepository) Save(ctx context.Context, s *feed.Subscription) error {
+	doc := feedDoc{
+		UserID:    s.UserID.String(),
+		URL:       s.URL,
+		Title:     s.Title,
+		Seen:      s.Seen,
+		CreatedAt: s.CreatedAt,
+	}
+
+	_, err := r.client.Collection("feedSubscriptions").Doc(s.ID).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save feed subscription: %w", err)
+	}
+
+	return nil
+}
+
+// FindByUser retrieves the subscriptions of a user, oldest first
+func (r *FeedRepository) FindByUser(ctx context.Context, userID feed.UserID) ([]*feed.Subscription, error) {
+	return r.find(ctx, r.client.Collection("feedSubscriptions").
+		Where("userId", "==", userID.String()).
+		OrderBy("createdAt", firestore.Asc))
+}
+
+// FindAll retrieves every subscription, oldest first
+func (r *FeedRepository) FindAll(ctx context.Context) ([]*feed.Subscription, error) {
+	return r.find(ctx, r.client.Collection("feedSubscriptions").OrderBy("createdAt", firestore.Asc))
+}
+
+// Delete removes a subscription
+func (r *FeedRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.client.Collection("feedSubscriptions").Doc(id).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete feed subscription: %w", err)
+	}
+	return nil
+}
+
+// find runs a subscription query
+func (r *FeedRepository) find(ctx context.Context, q firestore.Query) ([]*feed.Subscription, error) {
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var subscriptions []*feed.Subscription
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate feed subscriptions: %w", err)
+		}
+
+		var doc feedDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse feed subscription: %w", err)
+		}
+
+		subscriptions = append(subscriptions, &feed.Subscription{
+			ID:        snap.Ref.ID,
+			UserID:    feed.UserID(doc.UserID),
+			URL:       doc.URL,
+			Title:     doc.Title,
+			Seen:      doc.Seen,
+			CreatedAt: doc.CreatedAt,
+		})
+	}
+
+	return subscriptions, nil
+}
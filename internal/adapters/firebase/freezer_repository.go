@@ -0,0 +1,96 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/freezer"
+	"receipt-bot/internal/domain/shared"
+)
+
+// FreezerRepository implements the freezer.Repository interface using Firestore.
+// Freezers are stored in the freezers collection, one document per user.
+type FreezerRepository struct {
+	client *firestore.Client
+}
+
+// NewFreezerRepository creates a new Firebase freezer repository
+func NewFreezerRepository(client *firestore.Client) *FreezerRepository {
+	return &FreezerRepository{
+		client: client,
+	}
+}
+
+// freezerDoc represents the Firestore document structure
+type freezerDoc struct {
+	UserID    string     `firestore:"userId"`
+	Batches   []batchDoc `firestore:"batches"`
+	UpdatedAt time.Time  `firestore:"updatedAt"`
+}
+
+type batchDoc struct {
+	RecipeID string    `firestore:"recipeId"`
+	Title    string    `firestore:"title"`
+	Portions int       `firestore:"portions"`
+	FrozenAt time.Time `firestore:"frozenAt"`
+}
+
+// FindByUser retrieves the freezer of a user
+func (r *FreezerRepository) FindByUser(ctx context.Context, userID freezer.UserID) (*freezer.Freezer, error) {
+	snap, err := r.client.Collection("freezers").Doc(userID.String()).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrFreezerNotFound
+		}
+		return nil, fmt.Errorf("failed to find freezer: %w", err)
+	}
+
+	var doc freezerDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse freezer document: %w", err)
+	}
+
+	batches := make([]freezer.Batch, len(doc.Batches))
+	for i, b := range doc.Batches {
+		batches[i] = freezer.Batch{
+			RecipeID: freezer.RecipeID(b.RecipeID),
+			Title:    b.Title,
+			Portions: b.Portions,
+			FrozenAt: b.FrozenAt,
+		}
+	}
+
+	return freezer.ReconstructFreezer(freezer.FreezerData{
+		UserID:    freezer.UserID(doc.UserID),
+		Batches:   batches,
+		UpdatedAt: doc.UpdatedAt,
+	}), nil
+}
+
+// Save persists a freezer
+func (r *FreezerRepository) Save(ctx context.Context, f *freezer.Freezer) error {
+	doc := freezerDoc{
+		UserID:    f.UserID().String(),
+		Batches:   make([]batchDoc, len(f.Batches())),
+		UpdatedAt: f.UpdatedAt(),
+	}
+	for i, b := range f.Batches() {
+		doc.Batches[i] = batchDoc{
+			RecipeID: b.RecipeID.String(),
+			Title:    b.Title,
+			Portions: b.Portions,
+			FrozenAt: b.FrozenAt,
+		}
+	}
+
+	_, err := r.client.Collection("freezers").Doc(f.UserID().String()).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save freezer: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/share"
+	"receipt-bot/internal/domain/shared"
+)
+
+// GuestShareRepository implements the share.Repository interface using Firestore.
+// Shares are stored in the guestShares collection, keyed by token.
+type GuestShareRepository struct {
+	client *firestore.Client
+}
+
+// NewGuestShareRepository creates a new Firebase guest share repository
+func NewGuestShareRepository(client *firestore.Client) *GuestShareRepository {
+	return &GuestShareRepository{
+		client: client,
+	}
+}
+
+// guestShareDoc represents the Firestore document structure
+type guestShareDoc struct {
+	OwnerID   string    `firestore:"ownerId"`
+	Category  string    `firestore:"category,omitempty"`
+	CreatedAt time.Time `firestore:"createdAt"`
+	ExpiresAt time.Time `firestore:"expiresAt"`
+}
+
+// Save persists a guest share
+func (r *GuestShareRepository) Save(ctx context.Context, s *share.GuestShare) error {
+	doc := guestShareDoc{
+		OwnerID:   s.OwnerID.String(),
+		CreatedAt: s.CreatedAt,
+		ExpiresAt: s.ExpiresAt,
+	}
+	if s.Category != nil {
+		doc.Category = string(*s.Category)
+	}
+
+	_, err := r.client.Collection("guestShares").Doc(s.Token).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save guest share: %w", err)
+	}
+
+	return nil
+}
+
+// FindByToken retrieves a guest share by its token
+func (r *GuestShareRepository) FindByToken(ctx context.Context, token string) (*share.GuestShare, error) {
+	snap, err := r.client.Collection("guestShares").Doc(token).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrShareNotFound
+		}
+		return nil, fmt.Errorf("failed to find guest share: %w", err)
+	}
+
+	var doc guestShareDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse guest share document: %w", err)
+	}
+
+	s := &share.GuestShare{
+		Token:     token,
+		OwnerID:   share.UserID(doc.OwnerID),
+		CreatedAt: doc.CreatedAt,
+		ExpiresAt: doc.ExpiresAt,
+	}
+	if doc.Category != "" {
+		category := recipe.Category(doc.Category)
+		s.Category = &category
+	}
+
+	return s, nil
+}
+
+// DeleteByOwner removes all guest shares of a user
+func (r *GuestShareRepository) DeleteByOwner(ctx context.Context, ownerID share.UserID) error {
+	iter := r.client.Collection("guestShares").
+		Where("ownerId", "==", ownerID.String()).
+		Documents(ctx)
+
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to iterate guest shares: %w", err)
+		}
+
+		if _, err := doc.Ref.Delete(ctx); err != nil {
+			return fmt.Errorf("failed to delete guest share: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// LinkCodeRepository implements the user.LinkCodeRepository interface using Firestore.
+// Codes live in the linkCodes collection, keyed by code.
+type LinkCodeRepository struct {
+	client *firestore.Client
+}
+
+// NewLinkCodeRepository creates a new Firebase link code repository
+func NewLinkCodeRepository(client *firestore.Client) *LinkCodeRepository {
+	return &LinkCodeRepository{
+		client: client,
+	}
+}
+
+// linkCodeDoc represents the Firestore document structure of a link code
+type linkCodeDoc struct {
+	UserID    string    `firestore:"userId"`
+	ExpiresAt time.Time `firestore:"expiresAt"`
+}
+
+// SaveLinkCode persists a link code
+func (r *LinkCodeRepository) SaveLinkCode(ctx context.Context, code *user.LinkCode) error {
+	doc := linkCodeDoc{
+		UserID:    code.UserID.String(),
+		ExpiresAt: code.ExpiresAt,
+	}
+
+	_, err := r.client.Collection("linkCodes").Doc(code.Code).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save link code: %w", err)
+	}
+
+	return nil
+}
+
+// TakeLinkCode retrieves a link code and deletes it so it can only be used once
+func (r *LinkCodeRepository) TakeLinkCode(ctx context.Context, code string) (*user.LinkCode, error) {
+	ref := r.client.Collection("linkCodes").Doc(code)
+
+	var doc linkCodeDoc
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		if err := snap.DataTo(&doc); err != nil {
+			return err
+		}
+		return tx.Delete(ref)
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, shared.ErrInvalidLinkCode
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to take link code: %w", err)
+	}
+
+	return &user.LinkCode{
+		Code:      code,
+		UserID:    user.UserID(doc.UserID),
+		ExpiresAt: doc.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/mealplan"
+	"receipt-bot/internal/domain/shared"
+)
+
+// MealPlanRepository implements the mealplan.Repository interface using Firestore.
+// Plans are stored in the mealPlans collection, one document per user and week.
+type MealPlanRepository struct {
+	client *firestore.Client
+}
+
+// NewMealPlanRepository creates a new Firebase meal plan repository
+func NewMealPlanRepository(client *firestore.Client) *MealPlanRepository {
+	return &MealPlanRepository{
+		client: client,
+	}
+}
+
+// mealPlanDoc represents the Firestore document structure
+type mealPlanDoc struct {
+	UserID    string             `firestore:"userId"`
+	WeekStart time.Time          `firestore:"weekStart"`
+	Entries   []mealPlanEntryDoc `firestore:"entries"`
+	UpdatedAt time.Time          `firestore:"updatedAt"`
+}
+
+type mealPlanEntryDoc struct {
+	Day      int    `firestore:"day"` // time.Weekday, Sunday = 0
+	RecipeID string `firestore:"recipeId"`
+	Servings int    `firestore:"servings,omitempty"`
+}
+
+// mealPlanDocID returns the document ID of a user's plan for a week
+func mealPlanDocID(userID mealplan.UserID, weekStart time.Time) string {
+	return userID.String() + "_" + weekStart.Format("2006-01-02")
+}
+
+// FindByWeek retrieves the plan of a user for the week starting on weekStart
+func (r *MealPlanRepository) FindByWeek(ctx context.Context, userID mealplan.UserID, weekStart time.Time) (*mealplan.MealPlan, error) {
+	snap, err := r.client.Collection("mealPlans").Doc(mealPlanDocID(userID, weekStart)).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrMealPlanNotFound
+		}
+		return nil, fmt.Errorf("failed to find meal plan: %w", err)
+	}
+
+	var doc mealPlanDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse meal plan document: %w", err)
+	}
+
+	entries := make([]mealplan.Entry, len(doc.Entries))
+	for i, e := range doc.Entries {
+		entries[i] = mealplan.Entry{
+			Day:      time.Weekday(e.Day),
+			RecipeID: mealplan.RecipeID(e.RecipeID),
+			Servings: e.Servings,
+		}
+	}
+
+	return mealplan.ReconstructMealPlan(mealplan.PlanData{
+		UserID:    mealplan.UserID(doc.UserID),
+		WeekStart: doc.WeekStart.In(weekStart.Location()),
+		Entries:   entries,
+		UpdatedAt: doc.UpdatedAt,
+	}), nil
+}
+
+// Save persists a meal plan
+func (r *MealPlanRepository) Save(ctx context.Context, plan *mealplan.MealPlan) error {
+	doc := mealPlanDoc{
+		UserID:    plan.UserID().String(),
+		WeekStart: plan.WeekStart(),
+		Entries:   make([]mealPlanEntryDoc, len(plan.Entries())),
+		UpdatedAt: plan.UpdatedAt(),
+	}
+	for i, e := range plan.Entries() {
+		doc.Entries[i] = mealPlanEntryDoc{
+			Day:      int(e.Day),
+			RecipeID: e.RecipeID.String(),
+			Servings: e.Servings,
+		}
+	}
+
+	_, err := r.client.Collection("mealPlans").Doc(mealPlanDocID(plan.UserID(), plan.WeekStart())).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save meal plan: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ModerationRepository implements the moderation.Repository interface using Firestore.
+// Decisions are stored in the moderationDecisions collection, keyed by recipe ID.
+type ModerationRepository struct {
+	client *firestore.Client
+}
+
+// NewModerationRepository creates a new Firebase moderation decision repository
+func NewModerationRepository(client *firestore.Client) *ModerationRepository {
+	return &ModerationRepository{
+		client: client,
+	}
+}
+
+// moderationDoc represents the Firestore document structure of a decision
+type moderationDoc struct {
+	OwnerID     string    `firestore:"ownerId"`
+	Title       string    `firestore:"title"`
+	SourceURL   string    `firestore:"sourceUrl"`
+	Fingerprint string    `firestore:"fingerprint"`
+	Verdict     string    `firestore:"verdict"`
+	Reason      string    `firestore:"reason"`
+	CheckedAt   time.Time `firestore:"checkedAt"`
+	Reviewed    bool      `firestore:"reviewed"`
+}
+
+// Save persists a decision, replacing the previous one of the recipe
+func (r *ModerationRepository) Save(ctx context.Context, d *moderation.Decision) error {
+	doc := moderationDoc{
+		OwnerID:     d.OwnerID.String(),
+		Title:       d.Title,
+		SourceURL:   d.SourceURL,
+		Fingerprint: d.Fingerprint,
+		Verdict:     string(d.Verdict),
+		Reason:      d.Reason,
+		CheckedAt:   d.CheckedAt,
+		Reviewed:    d.Reviewed,
+	}
+
+	_, err := r.client.Collection("moderationDecisions").Doc(d.RecipeID.String()).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save moderation decision: %w", err)
+	}
+
+	return nil
+}
+
+// FindByRecipe retrieves the decision of a recipe
+func (r *ModerationRepository) FindByRecipe(ctx context.Context, recipeID recipe.RecipeID) (*moderation.Decision, error) {
+	snap, err := r.client.Collection("moderationDecisions").Doc(recipeID.String()).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrModerationNotFound
+		}
+		return nil, fmt.Errorf("failed to get moderation decision: %w", err)
+	}
+
+	return toDecision(snap)
+}
+
+// FindPending retrieves the decisions waiting for an admin review, oldest first
+func (r *ModerationRepository) FindPending(ctx context.Context) ([]*moderation.Decision, error) {
+	iter := r.client.Collection("moderationDecisions").
+		Where("verdict", "==", string(moderation.VerdictReview)).
+		OrderBy("checkedAt", firestore.Asc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var pending []*moderation.Decision
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate moderation decisions: %w", err)
+		}
+
+		d, err := toDecision(snap)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, d)
+	}
+
+	return pending, nil
+}
+
+// toDecision converts a decision document
+func toDecision(snap *firestore.DocumentSnapshot) (*moderation.Decision, error) {
+	var doc moderationDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse moderation decision: %w", err)
+	}
+
+	return &moderation.Decision{
+		RecipeID:    recipe.RecipeID(snap.Ref.ID),
+		OwnerID:     moderation.UserID(doc.OwnerID),
+		Title:       doc.Title,
+		SourceURL:   doc.SourceURL,
+		Fingerprint: doc.Fingerprint,
+		Verdict:     moderation.Verdict(doc.Verdict),
+		Reason:      doc.Reason,
+		CheckedAt:   doc.CheckedAt,
+		Reviewed:    doc.Reviewed,
+	}, nil
+}
@@ -0,0 +1,306 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/nutrition"
+	"receipt-bot/internal/domain/shared"
+)
+
+// NutritionRepository implements the nutrition.Repository interface using Firestore.
+// Product data is cached in the products collection keyed by barcode, and pantry
+// links live in the pantryProducts collection keyed by user ID and barcode. Macro
+// goals are kept in nutritionGoals keyed by user ID and logged meals in meals.
+type NutritionRepository struct {
+	client *firestore.Client
+}
+
+// NewNutritionRepository creates a new Firebase nutrition repository
+func NewNutritionRepository(client *firestore.Client) *NutritionRepository {
+	return &NutritionRepository{
+		client: client,
+	}
+}
+
+// productDoc represents the Firestore document structure of a cached product
+type productDoc struct {
+	Barcode   string    `firestore:"barcode"`
+	Name      string    `firestore:"name"`
+	Brand     string    `firestore:"brand,omitempty"`
+	Quantity  string    `firestore:"quantity,omitempty"`
+	Facts     *factsDoc `firestore:"facts,omitempty"`
+	FetchedAt time.Time `firestore:"fetchedAt"`
+}
+
+type factsDoc struct {
+	EnergyKcal    float64 `firestore:"energyKcal"`
+	Protein       float64 `firestore:"protein"`
+	Carbohydrates float64 `firestore:"carbohydrates"`
+	Sugars        float64 `firestore:"sugars"`
+	Fat           float64 `firestore:"fat"`
+	SaturatedFat  float64 `firestore:"saturatedFat"`
+	Fiber         float64 `firestore:"fiber"`
+	Salt          float64 `firestore:"salt"`
+}
+
+func toFactsDoc(f nutrition.Facts) *factsDoc {
+	return &factsDoc{
+		EnergyKcal:    f.EnergyKcal,
+		Protein:       f.Protein,
+		Carbohydrates: f.Carbohydrates,
+		Sugars:        f.Sugars,
+		Fat:           f.Fat,
+		SaturatedFat:  f.SaturatedFat,
+		Fiber:         f.Fiber,
+		Salt:          f.Salt,
+	}
+}
+
+func (d *factsDoc) facts() nutrition.Facts {
+	return nutrition.Facts{
+		EnergyKcal:    d.EnergyKcal,
+		Protein:       d.Protein,
+		Carbohydrates: d.Carbohydrates,
+		Sugars:        d.Sugars,
+		Fat:           d.Fat,
+		SaturatedFat:  d.SaturatedFat,
+		Fiber:         d.Fiber,
+		Salt:          d.Salt,
+	}
+}
+
+// goalsDoc represents the Firestore document structure of a user's macro goals
+type goalsDoc struct {
+	UserID    string    `firestore:"userId"`
+	Daily     *factsDoc `firestore:"daily"`
+	UpdatedAt time.Time `firestore:"updatedAt"`
+}
+
+// mealDoc represents the Firestore document structure of a logged meal
+type mealDoc struct {
+	UserID   string    `firestore:"userId"`
+	RecipeID string    `firestore:"recipeId"`
+	Title    string    `firestore:"title"`
+	Facts    *factsDoc `firestore:"facts"`
+	EatenAt  time.Time `firestore:"eatenAt"`
+}
+
+// pantryProductDoc represents the Firestore document structure of a pantry link
+type pantryProductDoc struct {
+	UserID   string    `firestore:"userId"`
+	Item     string    `firestore:"item"`
+	Barcode  string    `firestore:"barcode"`
+	LinkedAt time.Time `firestore:"linkedAt"`
+}
+
+// SaveProduct caches product data by barcode
+func (r *NutritionRepository) SaveProduct(ctx context.Context, product *nutrition.Product) error {
+	doc := productDoc{
+		Barcode:   product.Barcode,
+		Name:      product.Name,
+		Brand:     product.Brand,
+		Quantity:  product.Quantity,
+		FetchedAt: product.FetchedAt,
+	}
+	if product.Facts != nil {
+		doc.Facts = toFactsDoc(*product.Facts)
+	}
+
+	_, err := r.client.Collection("products").Doc(product.Barcode).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save product: %w", err)
+	}
+
+	return nil
+}
+
+// FindProduct returns cached product data
+func (r *NutritionRepository) FindProduct(ctx context.Context, barcode string) (*nutrition.Product, error) {
+	snap, err := r.client.Collection("products").Doc(barcode).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to find product: %w", err)
+	}
+
+	var doc productDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse product document: %w", err)
+	}
+
+	product := &nutrition.Product{
+		Barcode:   doc.Barcode,
+		Name:      doc.Name,
+		Brand:     doc.Brand,
+		Quantity:  doc.Quantity,
+		FetchedAt: doc.FetchedAt,
+	}
+	if doc.Facts != nil {
+		facts := doc.Facts.facts()
+		product.Facts = &facts
+	}
+
+	return product, nil
+}
+
+// SavePantryProduct links a pantry item to a product
+func (r *NutritionRepository) SavePantryProduct(ctx context.Context, link *nutrition.PantryProduct) error {
+	doc := pantryProductDoc{
+		UserID:   link.UserID.String(),
+		Item:     link.Item,
+		Barcode:  link.Barcode,
+		LinkedAt: link.LinkedAt,
+	}
+
+	docID := fmt.Sprintf("%s_%s", link.UserID.String(), link.Barcode)
+	_, err := r.client.Collection("pantryProducts").Doc(docID).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save pantry product: %w", err)
+	}
+
+	return nil
+}
+
+// FindPantryProducts returns all pantry product links of a user
+func (r *NutritionRepository) FindPantryProducts(ctx context.Context, userID nutrition.UserID) ([]*nutrition.PantryProduct, error) {
+	iter := r.client.Collection("pantryProducts").
+		Where("userId", "==", userID.String()).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var links []*nutrition.PantryProduct
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate pantry products: %w", err)
+		}
+
+		var doc pantryProductDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse pantry product: %w", err)
+		}
+		links = append(links, &nutrition.PantryProduct{
+			UserID:   nutrition.UserID(doc.UserID),
+			Item:     doc.Item,
+			Barcode:  doc.Barcode,
+			LinkedAt: doc.LinkedAt,
+		})
+	}
+
+	return links, nil
+}
+
+// SaveGoals stores a user's daily macro goals
+func (r *NutritionRepository) SaveGoals(ctx context.Context, goals *nutrition.Goals) error {
+	doc := goalsDoc{
+		UserID:    goals.UserID.String(),
+		Daily:     toFactsDoc(goals.Daily),
+		UpdatedAt: goals.UpdatedAt,
+	}
+
+	_, err := r.client.Collection("nutritionGoals").Doc(goals.UserID.String()).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save macro goals: %w", err)
+	}
+
+	return nil
+}
+
+// FindGoals returns a user's daily macro goals
+func (r *NutritionRepository) FindGoals(ctx context.Context, userID nutrition.UserID) (*nutrition.Goals, error) {
+	snap, err := r.client.Collection("nutritionGoals").Doc(userID.String()).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrNoMacroGoals
+		}
+		return nil, fmt.Errorf("failed to find macro goals: %w", err)
+	}
+
+	var doc goalsDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse macro goals: %w", err)
+	}
+
+	goals := &nutrition.Goals{UserID: userID, UpdatedAt: doc.UpdatedAt}
+	if doc.Daily != nil {
+		goals.Daily = doc.Daily.facts()
+	}
+	return goals, nil
+}
+
+// DeleteGoals removes a user's daily macro goals
+func (r *NutritionRepository) DeleteGoals(ctx context.Context, userID nutrition.UserID) error {
+	_, err := r.client.Collection("nutritionGoals").Doc(userID.String()).Delete(ctx)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return fmt.Errorf("failed to delete macro goals: %w", err)
+	}
+
+	return nil
+}
+
+// SaveMeal logs a meal
+func (r *NutritionRepository) SaveMeal(ctx context.Context, meal *nutrition.Meal) error {
+	doc := mealDoc{
+		UserID:   meal.UserID.String(),
+		RecipeID: meal.RecipeID.String(),
+		Title:    meal.Title,
+		Facts:    toFactsDoc(meal.Facts),
+		EatenAt:  meal.EatenAt,
+	}
+
+	_, err := r.client.Collection("meals").Doc(meal.ID.String()).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save meal: %w", err)
+	}
+
+	return nil
+}
+
+// FindMeals returns the meals a user logged since the given time, oldest first
+func (r *NutritionRepository) FindMeals(ctx context.Context, userID nutrition.UserID, since time.Time) ([]*nutrition.Meal, error) {
+	iter := r.client.Collection("meals").
+		Where("userId", "==", userID.String()).
+		Where("eatenAt", ">=", since).
+		OrderBy("eatenAt", firestore.Asc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var meals []*nutrition.Meal
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate meals: %w", err)
+		}
+
+		var doc mealDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse meal: %w", err)
+		}
+		meal := &nutrition.Meal{
+			ID:       shared.ID(snap.Ref.ID),
+			UserID:   userID,
+			RecipeID: shared.ID(doc.RecipeID),
+			Title:    doc.Title,
+			EatenAt:  doc.EatenAt,
+		}
+		if doc.Facts != nil {
+			meal.Facts = doc.Facts.facts()
+		}
+		meals = append(meals, meal)
+	}
+
+	return meals, nil
+}
@@ -0,0 +1,116 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// indexProbeValue is what probe queries filter on; no document matches it
+const indexProbeValue = "preflight-check"
+
+// compositeIndex is a composite index the repositories' queries need
+type compositeIndex struct {
+	collection string
+	fields     []indexField
+}
+
+// indexField is a field of a composite index, with its order, or "CONTAINS" for
+// arrays queried with array-contains
+type indexField struct {
+	path  string
+	order string // "ASCENDING", "DESCENDING" or "CONTAINS"
+}
+
+// compositeIndexes are the indexes of firestore.indexes.json, which must be
+// deployed for the repositories' queries to run
+var compositeIndexes = []compositeIndex{
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"createdAt", "DESCENDING"}}},
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"dietaryTags", "CONTAINS"}, {"createdAt", "DESCENDING"}}},
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"category", "ASCENDING"}, {"createdAt", "DESCENDING"}}},
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"category", "ASCENDING"}, {"dietaryTags", "CONTAINS"}, {"createdAt", "DESCENDING"}}},
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"sourceLanguage", "ASCENDING"}, {"createdAt", "DESCENDING"}}},
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"sourceLanguage", "ASCENDING"}, {"dietaryTags", "CONTAINS"}, {"createdAt", "DESCENDING"}}},
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"category", "ASCENDING"}, {"sourceLanguage", "ASCENDING"}, {"createdAt", "DESCENDING"}}},
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"category", "ASCENDING"}, {"sourceLanguage", "ASCENDING"}, {"dietaryTags", "CONTAINS"}, {"createdAt", "DESCENDING"}}},
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"source.platform", "ASCENDING"}, {"createdAt", "DESCENDING"}}},
+	{"recipes", []indexField{{"userId", "ASCENDING"}, {"flavors", "CONTAINS"}, {"createdAt", "DESCENDING"}}},
+	{"meals", []indexField{{"userId", "ASCENDING"}, {"eatenAt", "ASCENDING"}}},
+	{"feedSubscriptions", []indexField{{"userId", "ASCENDING"}, {"createdAt", "ASCENDING"}}},
+	{"moderationDecisions", []indexField{{"verdict", "ASCENDING"}, {"checkedAt", "ASCENDING"}}},
+}
+
+// String describes the index like the Firebase console lists it, e.g.
+// "recipes (userId ↑, createdAt ↓)"
+func (i compositeIndex) String() string {
+	fields := make([]string, len(i.fields))
+	for n, field := range i.fields {
+		switch field.order {
+		case "DESCENDING":
+			fields[n] = field.path + " ↓"
+		case "CONTAINS":
+			fields[n] = field.path + " contains"
+		default:
+			fields[n] = field.path + " ↑"
+		}
+	}
+	return i.collection + " (" + strings.Join(fields, ", ") + ")"
+}
+
+// probe returns a query only the index can serve: equality and array-contains
+// filters on its fields, ordered by the last field that is not an array
+func (i compositeIndex) probe(client *firestore.Client) firestore.Query {
+	orderBy := len(i.fields) - 1
+	for orderBy > 0 && i.fields[orderBy].order == "CONTAINS" {
+		orderBy--
+	}
+
+	query := client.Collection(i.collection).Query
+	for n, field := range i.fields {
+		switch {
+		case n == orderBy:
+		case field.order == "CONTAINS":
+			query = query.Where(field.path, "array-contains", indexProbeValue)
+		default:
+			query = query.Where(field.path, "==", indexProbeValue)
+		}
+	}
+
+	direction := firestore.Asc
+	if i.fields[orderBy].order == "DESCENDING" {
+		direction = firestore.Desc
+	}
+	return query.OrderBy(i.fields[orderBy].path, direction).Limit(1)
+}
+
+// CheckAccess reads one recipe to verify the credentials may use the database
+func (c *Client) CheckAccess(ctx context.Context) error {
+	_, err := c.firestore.Collection("recipes").Limit(1).Documents(ctx).Next()
+	if err != nil && err != iterator.Done {
+		return fmt.Errorf("failed to read recipes: %w", err)
+	}
+	return nil
+}
+
+// MissingIndexes runs a query needing each composite index of
+// firestore.indexes.json and returns the indexes Firestore reports missing.
+// The emulator builds indexes on demand, so it never reports any.
+func (c *Client) MissingIndexes(ctx context.Context) ([]string, error) {
+	var missing []string
+	for _, index := range compositeIndexes {
+		_, err := index.probe(c.firestore).Documents(ctx).Next()
+		switch {
+		case err == nil || err == iterator.Done:
+		case status.Code(err) == codes.FailedPrecondition:
+			missing = append(missing, index.String())
+		default:
+			return missing, fmt.Errorf("failed to query %s: %w", index, err)
+		}
+	}
+	return missing, nil
+}
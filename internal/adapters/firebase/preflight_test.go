@@ -0,0 +1,53 @@
+package firebase
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCompositeIndexesMatchIndexFile(t *testing.T) {
+	data, err := os.ReadFile("../../../firestore.indexes.json")
+	if err != nil {
+		t.Fatalf("failed to read firestore.indexes.json: %v", err)
+	}
+
+	var file struct {
+		Indexes []struct {
+			CollectionGroup string `json:"collectionGroup"`
+			Fields          []struct {
+				FieldPath   string `json:"fieldPath"`
+				Order       string `json:"order"`
+				ArrayConfig string `json:"arrayConfig"`
+			} `json:"fields"`
+		} `json:"indexes"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("failed to parse firestore.indexes.json: %v", err)
+	}
+
+	var deployed []compositeIndex
+	for _, index := range file.Indexes {
+		fields := make([]indexField, len(index.Fields))
+		for n, field := range index.Fields {
+			order := field.Order
+			if field.ArrayConfig != "" {
+				order = field.ArrayConfig
+			}
+			fields[n] = indexField{field.FieldPath, order}
+		}
+		deployed = append(deployed, compositeIndex{index.CollectionGroup, fields})
+	}
+
+	if !reflect.DeepEqual(deployed, compositeIndexes) {
+		t.Errorf("compositeIndexes differ from firestore.indexes.json:\n got %v\nwant %v", compositeIndexes, deployed)
+	}
+}
+
+func TestCompositeIndexString(t *testing.T) {
+	index := compositeIndex{"recipes", []indexField{{"userId", "ASCENDING"}, {"dietaryTags", "CONTAINS"}, {"createdAt", "DESCENDING"}}}
+	if got, want := index.String(), "recipes (userId ↑, dietaryTags contains, createdAt ↓)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ProcessingReportRepository implements the recipe.ProcessingRepository interface using
+// Firestore. Reports live in the processingReports collection, keyed by recipe ID.
+type ProcessingReportRepository struct {
+	client *firestore.Client
+}
+
+// NewProcessingReportRepository creates a new Firebase processing report repository
+func NewProcessingReportRepository(client *firestore.Client) *ProcessingReportRepository {
+	return &ProcessingReportRepository{
+		client: client,
+	}
+}
+
+// processingReportDoc represents the Firestore document structure of a processing report
+type processingReportDoc struct {
+	RecipeID        string    `firestore:"recipeId"`
+	Platform        string    `firestore:"platform"`
+	ScrapeMs        int64     `firestore:"scrapeMs"`
+	TranscriptionMs int64     `firestore:"transcriptionMs"`
+	OnScreenTextMs  int64     `firestore:"onScreenTextMs"`
+	LLMMs           int64     `firestore:"llmMs"`
+	Model           string    `firestore:"model,omitempty"`
+	LLMCalls        int       `firestore:"llmCalls"`
+	InputTokens     int       `firestore:"inputTokens"`
+	OutputTokens    int       `firestore:"outputTokens"`
+	ProcessedAt     time.Time `firestore:"processedAt"`
+}
+
+// SaveProcessingReport stores a report, replacing the recipe's previous one
+func (r *ProcessingReportRepository) SaveProcessingReport(ctx context.Context, report *recipe.ProcessingReport) error {
+	doc := processingReportDoc{
+		RecipeID:        report.RecipeID.String(),
+		Platform:        string(report.Platform),
+		ScrapeMs:        report.Scrape.Milliseconds(),
+		TranscriptionMs: report.Transcription.Milliseconds(),
+		OnScreenTextMs:  report.OnScreenText.Milliseconds(),
+		LLMMs:           report.LLM.Milliseconds(),
+		Model:           report.Model,
+		LLMCalls:        report.LLMCalls,
+		InputTokens:     report.InputTokens,
+		OutputTokens:    report.OutputTokens,
+		ProcessedAt:     report.ProcessedAt,
+	}
+
+	_, err := r.client.Collection("processingReports").Doc(report.RecipeID.String()).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save processing report: %w", err)
+	}
+
+	return nil
+}
+
+// FindProcessingReport returns the report of a recipe
+func (r *ProcessingReportRepository) FindProcessingReport(ctx context.Context, recipeID recipe.RecipeID) (*recipe.ProcessingReport, error) {
+	snap, err := r.client.Collection("processingReports").Doc(recipeID.String()).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrNoProcessingReport
+		}
+		return nil, fmt.Errorf("failed to get processing report: %w", err)
+	}
+
+	var doc processingReportDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse processing report: %w", err)
+	}
+
+	return &recipe.ProcessingReport{
+		RecipeID:      recipe.RecipeID(doc.RecipeID),
+		Platform:      recipe.Platform(doc.Platform),
+		Scrape:        time.Duration(doc.ScrapeMs) * time.Millisecond,
+		Transcription: time.Duration(doc.TranscriptionMs) * time.Millisecond,
+		OnScreenText:  time.Duration(doc.OnScreenTextMs) * time.Millisecond,
+		LLM:           time.Duration(doc.LLMMs) * time.Millisecond,
+		Model:         doc.Model,
+		LLMCalls:      doc.LLMCalls,
+		InputTokens:   doc.InputTokens,
+		OutputTokens:  doc.OutputTokens,
+		ProcessedAt:   doc.ProcessedAt,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package firebase
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// wastefulMinReads is the fewest documents a query must read before its in-memory
+// filtering is worth reporting
+const wastefulMinReads = 50
+
+// QueryStats describes how a repository query ran
+type QueryStats struct {
+	Query         string   // repository query, e.g. "recipes.byFilters"
+	DocumentsRead int      // documents Firestore returned
+	ResultsKept   int      // documents left after in-memory filtering
+	IndexesUsed   []string // from Query Explain, e.g. "(userId ASC, createdAt DESC, __name__ DESC)"
+	Duration      time.Duration
+}
+
+// Wasteful reports whether most documents read were thrown away in memory,
+// the sign of a filter Firestore should run instead
+func (s QueryStats) Wasteful() bool {
+	return s.DocumentsRead >= wastefulMinReads && s.ResultsKept*4 < s.DocumentsRead
+}
+
+// QueryObserver receives the stats of every query a repository runs
+type QueryObserver func(QueryStats)
+
+// LogQueryStats is a QueryObserver that logs every query, flagging wasteful ones
+func LogQueryStats(stats QueryStats) {
+	indexes := strings.Join(stats.IndexesUsed, ", ")
+	if indexes == "" {
+		indexes = "unknown"
+	}
+	msg := fmt.Sprintf("%s read %d documents, kept %d in %s (indexes: %s)",
+		stats.Query, stats.DocumentsRead, stats.ResultsKept, stats.Duration.Round(time.Millisecond), indexes)
+	if stats.Wasteful() {
+		log.Printf("Wasteful Firestore query %s", msg)
+		return
+	}
+	log.Printf("Firestore query %s", msg)
+}
+
+// indexesUsed lists the indexes Query Explain reports for a finished query
+func indexesUsed(iter *firestore.DocumentIterator) []string {
+	metrics, err := iter.ExplainMetrics()
+	if err != nil || metrics == nil || metrics.PlanSummary == nil {
+		return nil
+	}
+
+	var indexes []string
+	for _, index := range metrics.PlanSummary.IndexesUsed {
+		if index == nil {
+			continue
+		}
+		if properties, ok := (*index)["properties"].(string); ok {
+			indexes = append(indexes, properties)
+		}
+	}
+	return indexes
+}
@@ -10,17 +10,35 @@ import (
 	"google.golang.org/api/iterator"
 	"receipt-bot/internal/domain/recipe"
 	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
 )
 
+// sourceInlineLimit is the longest transcript or captions kept in the recipe
+// document when blob storage is configured; longer ones are stored as blobs,
+// since a Firestore document holds at most 1 MiB (see fitDocument)
+const sourceInlineLimit = 64 << 10
+
+// summaryFields are the document fields read for recipe summaries
+var summaryFields = []string{
+	"recipeId", "userId", "title", "ingredients", "source",
+	"prepTimeMinutes", "cookTimeMinutes", "servings", "category", "cuisine",
+	"dietaryTags", "tags", "flavors", "createdAt", "updatedAt",
+	"sourceLanguage", "normalizedIngredients", "difficultyScore", "cover",
+}
+
 // RecipeRepository implements the recipe.Repository interface using Firestore
 type RecipeRepository struct {
 	client *firestore.Client
+	blobs  ports.BlobStorage // optional, long source texts stay in the document when nil
+
+	observer QueryObserver // optional, set with SetQueryObserver
 }
 
 // NewRecipeRepository creates a new Firebase recipe repository
-func NewRecipeRepository(client *firestore.Client) *RecipeRepository {
+func NewRecipeRepository(client *firestore.Client, blobs ports.BlobStorage) *RecipeRepository {
 	return &RecipeRepository{
 		client: client,
+		blobs:  blobs,
 	}
 }
 
@@ -34,6 +52,10 @@ type recipeDoc struct {
 	Source          sourceDoc        `firestore:"source"`
 	Transcript      string           `firestore:"transcript"`
 	Captions        string           `firestore:"captions"`
+	TranscriptBlob  string           `firestore:"transcriptBlob,omitempty"` // blob key when the transcript is stored outside
+	CaptionsBlob    string           `firestore:"captionsBlob,omitempty"`   // blob key when the captions are stored outside
+	TranscriptGzip  []byte           `firestore:"transcriptGzip,omitempty"` // compressed transcript when the document would be too large
+	CaptionsGzip    []byte           `firestore:"captionsGzip,omitempty"`   // compressed captions when the document would be too large
 	PrepTimeMinutes *int             `firestore:"prepTimeMinutes,omitempty"`
 	CookTimeMinutes *int             `firestore:"cookTimeMinutes,omitempty"`
 	Servings        *int             `firestore:"servings,omitempty"`
@@ -41,6 +63,7 @@ type recipeDoc struct {
 	Cuisine         string           `firestore:"cuisine,omitempty"`
 	DietaryTags     []string         `firestore:"dietaryTags,omitempty"`
 	Tags            []string         `firestore:"tags,omitempty"`
+	Flavors         []string         `firestore:"flavors,omitempty"`
 	CreatedAt       time.Time        `firestore:"createdAt"`
 	UpdatedAt       time.Time        `firestore:"updatedAt"`
 
@@ -52,6 +75,43 @@ type recipeDoc struct {
 
 	// Cached normalized ingredients for faster matching
 	NormalizedIngredients []string `firestore:"normalizedIngredients,omitempty"`
+
+	// Difficulty score from 1 (trivial) to 10 (demanding)
+	DifficultyScore int `firestore:"difficultyScore,omitempty"`
+
+	// Where the extracted fields came from
+	Provenance []provenanceDoc `firestore:"provenance,omitempty"`
+
+	// The recipe this one was remixed from
+	Remix *remixDoc `firestore:"remix,omitempty"`
+
+	// Image shown with the recipe, stored in blob storage
+	Cover *coverDoc `firestore:"cover,omitempty"`
+}
+
+type coverDoc struct {
+	Key         string    `firestore:"key"`
+	AIGenerated bool      `firestore:"aiGenerated"`
+	CreatedAt   time.Time `firestore:"createdAt"`
+}
+
+type remixDoc struct {
+	ParentRecipeID string            `firestore:"parentRecipeId"`
+	ParentTitle    string            `firestore:"parentTitle"`
+	Goal           string            `firestore:"goal"`
+	Substitutions  []substitutionDoc `firestore:"substitutions,omitempty"`
+}
+
+type substitutionDoc struct {
+	Original    string `firestore:"original"`
+	Replacement string `firestore:"replacement"`
+	Reason      string `firestore:"reason,omitempty"`
+}
+
+type provenanceDoc struct {
+	Field      string  `firestore:"field"`
+	Source     string  `firestore:"source"`
+	Confidence float64 `firestore:"confidence"`
 }
 
 type ingredientDoc struct {
@@ -65,17 +125,29 @@ type instructionDoc struct {
 	StepNumber      int  `firestore:"stepNumber"`
 	Text            string `firestore:"text"`
 	DurationMinutes *int   `firestore:"durationMinutes,omitempty"`
+	StartSeconds    *int   `firestore:"startSeconds,omitempty"`
 }
 
 type sourceDoc struct {
-	URL      string `firestore:"url"`
-	Platform string `firestore:"platform"`
-	Author   string `firestore:"author"`
+	URL          string     `firestore:"url"`
+	Platform     string     `firestore:"platform"`
+	Author       string     `firestore:"author"`
+	Segment      int        `firestore:"segment,omitempty"`
+	Handle       string     `firestore:"handle,omitempty"`
+	CanonicalURL string     `firestore:"canonicalUrl,omitempty"`
+	PublishedAt  *time.Time `firestore:"publishedAt,omitempty"`
 }
 
 // Save persists a recipe to Firestore
 func (r *RecipeRepository) Save(ctx context.Context, rec *recipe.Recipe) error {
+	if rec.IsSummary() {
+		return shared.ErrRecipeSummary
+	}
+
 	doc := r.toDocument(rec)
+	if err := r.fitDocument(ctx, doc); err != nil {
+		return err
+	}
 
 	_, err := r.client.Collection("recipes").Doc(rec.ID().String()).Set(ctx, doc)
 	if err != nil {
@@ -85,6 +157,31 @@ func (r *RecipeRepository) Save(ctx context.Context, rec *recipe.Recipe) error {
 	return nil
 }
 
+// SaveAll persists several recipes in batched writes, for backfills and imports
+func (r *RecipeRepository) SaveAll(ctx context.Context, recipes []*recipe.Recipe) (int, error) {
+	writes := make([]batchWrite, 0, len(recipes))
+	for _, rec := range recipes {
+		if rec.IsSummary() {
+			return 0, shared.ErrRecipeSummary
+		}
+		doc := r.toDocument(rec)
+		if err := r.fitDocument(ctx, doc); err != nil {
+			return 0, err
+		}
+		writes = append(writes, batchWrite{
+			ref:  r.client.Collection("recipes").Doc(rec.ID().String()),
+			data: doc,
+			size: estimateDocumentSize(doc),
+		})
+	}
+
+	written, err := writeBatches(ctx, r.client, writes)
+	if err != nil {
+		return written, fmt.Errorf("failed to save recipes: %w", err)
+	}
+	return written, nil
+}
+
 // FindByID retrieves a recipe by its ID
 func (r *RecipeRepository) FindByID(ctx context.Context, id recipe.RecipeID) (*recipe.Recipe, error) {
 	doc, err := r.client.Collection("recipes").Doc(id.String()).Get(ctx)
@@ -96,18 +193,37 @@ func (r *RecipeRepository) FindByID(ctx context.Context, id recipe.RecipeID) (*r
 	if err := doc.DataTo(&recipeDoc); err != nil {
 		return nil, fmt.Errorf("failed to parse recipe document: %w", err)
 	}
+	if err := r.loadSources(ctx, &recipeDoc); err != nil {
+		return nil, err
+	}
 
 	return r.fromDocument(&recipeDoc), nil
 }
 
-// FindByUserID retrieves all recipes for a user
-func (r *RecipeRepository) FindByUserID(ctx context.Context, userID recipe.UserID) ([]*recipe.Recipe, error) {
-	iter := r.client.Collection("recipes").
-		Where("userId", "==", userID.String()).
-		OrderBy("createdAt", firestore.Desc).
-		Documents(ctx)
+// SetQueryObserver makes the repository report how its queries ran, asking
+// Firestore to explain them
+func (r *RecipeRepository) SetQueryObserver(observer QueryObserver) {
+	r.observer = observer
+}
+
+// byUser is the query for a user's recipes, newest first. Queries adding
+// equality filters to it need a composite index, see firestore.indexes.json.
+func (r *RecipeRepository) byUser(userID recipe.UserID) firestore.Query {
+	return r.client.Collection("recipes").Where("userId", "==", userID.String())
+}
+
+// find runs a query and returns the recipes keep accepts, or all of them when keep
+// is nil. Reports the query to the observer under name.
+func (r *RecipeRepository) find(ctx context.Context, name string, q firestore.Query, keep func(*recipe.Recipe) bool) ([]*recipe.Recipe, error) {
+	if r.observer != nil {
+		q = q.WithRunOptions(firestore.ExplainOptions{Analyze: true})
+	}
+	start := time.Now()
+	iter := q.Documents(ctx)
+	defer iter.Stop()
 
 	var recipes []*recipe.Recipe
+	read := 0
 	for {
 		doc, err := iter.Next()
 		if err == iterator.Done {
@@ -116,15 +232,57 @@ func (r *RecipeRepository) FindByUserID(ctx context.Context, userID recipe.UserI
 		if err != nil {
 			return nil, fmt.Errorf("failed to iterate recipes: %w", err)
 		}
+		read++
 
 		var recipeDoc recipeDoc
 		if err := doc.DataTo(&recipeDoc); err != nil {
 			continue // Skip invalid documents
 		}
+		rec := r.fromDocument(&recipeDoc)
+		if keep != nil && !keep(rec) {
+			continue
+		}
+		// Only recipes kept have their long sources read back
+		if recipeDoc.hasStoredSources() {
+			if err := r.loadSources(ctx, &recipeDoc); err != nil {
+				return nil, err
+			}
+			rec = r.fromDocument(&recipeDoc)
+		}
 
-		recipes = append(recipes, r.fromDocument(&recipeDoc))
+		recipes = append(recipes, rec)
 	}
 
+	if r.observer != nil {
+		r.observer(QueryStats{
+			Query:         name,
+			DocumentsRead: read,
+			ResultsKept:   len(recipes),
+			IndexesUsed:   indexesUsed(iter),
+			Duration:      time.Since(start),
+		})
+	}
+	return recipes, nil
+}
+
+// FindByUserID retrieves all recipes for a user
+func (r *RecipeRepository) FindByUserID(ctx context.Context, userID recipe.UserID) ([]*recipe.Recipe, error) {
+	return r.find(ctx, "recipes.byUser", r.byUser(userID).OrderBy("createdAt", firestore.Desc), nil)
+}
+
+// FindSummariesByUserID retrieves summaries of all recipes for a user, reading
+// only the fields they keep. Recipes saved before difficulty scoring have their
+// difficulty estimated without their steps.
+func (r *RecipeRepository) FindSummariesByUserID(ctx context.Context, userID recipe.UserID) ([]*recipe.Recipe, error) {
+	q := r.byUser(userID).Select(summaryFields...).OrderBy("createdAt", firestore.Desc)
+	recipes, err := r.find(ctx, "recipes.summariesByUser", q, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rec := range recipes {
+		recipes[i] = rec.Summary()
+	}
 	return recipes, nil
 }
 
@@ -147,19 +305,29 @@ func (r *RecipeRepository) FindBySourceURL(ctx context.Context, sourceURL string
 	if err := doc.DataTo(&recipeDoc); err != nil {
 		return nil, fmt.Errorf("failed to parse recipe document: %w", err)
 	}
+	if err := r.loadSources(ctx, &recipeDoc); err != nil {
+		return nil, err
+	}
 
 	return r.fromDocument(&recipeDoc), nil
 }
 
 // FindByUserIDAndCategory retrieves recipes for a user filtered by category
 func (r *RecipeRepository) FindByUserIDAndCategory(ctx context.Context, userID recipe.UserID, category recipe.Category) ([]*recipe.Recipe, error) {
-	iter := r.client.Collection("recipes").
-		Where("userId", "==", userID.String()).
+	q := r.byUser(userID).
 		Where("category", "==", string(category)).
-		OrderBy("createdAt", firestore.Desc).
-		Documents(ctx)
+		OrderBy("createdAt", firestore.Desc)
+	return r.find(ctx, "recipes.byCategory", q, nil)
+}
 
-	var recipes []*recipe.Recipe
+// GetCategoryCounts returns the count of recipes per category for a user
+func (r *RecipeRepository) GetCategoryCounts(ctx context.Context, userID recipe.UserID) (map[recipe.Category]int, error) {
+	// Firestore doesn't support GROUP BY, so we count in-memory,
+	// reading only the category of each recipe
+	iter := r.byUser(userID).Select("category").Documents(ctx)
+	defer iter.Stop()
+
+	counts := make(map[recipe.Category]int)
 	for {
 		doc, err := iter.Next()
 		if err == iterator.Done {
@@ -173,213 +341,254 @@ func (r *RecipeRepository) FindByUserIDAndCategory(ctx context.Context, userID r
 		if err := doc.DataTo(&recipeDoc); err != nil {
 			continue // Skip invalid documents
 		}
-
-		recipes = append(recipes, r.fromDocument(&recipeDoc))
+		counts[recipe.CategoryFromLLM(recipeDoc.Category)]++
 	}
 
-	return recipes, nil
+	return counts, nil
 }
 
-// GetCategoryCounts returns the count of recipes per category for a user
-func (r *RecipeRepository) GetCategoryCounts(ctx context.Context, userID recipe.UserID) (map[recipe.Category]int, error) {
-	// Fetch all recipes for user and count locally
-	// (Firestore doesn't support GROUP BY, so we count in-memory)
+// FindByUserIDAndAuthor retrieves recipes for a user saved from a source author
+func (r *RecipeRepository) FindByUserIDAndAuthor(ctx context.Context, userID recipe.UserID, author string) ([]*recipe.Recipe, error) {
+	// Authors are stored as extracted ("@ThatPastaGuy", "thatpastaguy"),
+	// so we fetch all and match in-memory
+	return r.find(ctx, "recipes.byAuthor", r.byUser(userID).OrderBy("createdAt", firestore.Desc), func(rec *recipe.Recipe) bool {
+		return rec.Source().IsFrom(author)
+	})
+}
+
+// GetAuthorCounts returns the count of recipes per source author for a user
+func (r *RecipeRepository) GetAuthorCounts(ctx context.Context, userID recipe.UserID) (map[string]int, error) {
 	recipes, err := r.FindByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	counts := make(map[recipe.Category]int)
-	for _, rec := range recipes {
-		counts[rec.Category()]++
-	}
+	return recipe.CountAuthors(recipes), nil
+}
 
-	return counts, nil
+// FindByUserIDAndPlatform retrieves recipes for a user saved from a source platform
+func (r *RecipeRepository) FindByUserIDAndPlatform(ctx context.Context, userID recipe.UserID, platform recipe.Platform) ([]*recipe.Recipe, error) {
+	q := r.byUser(userID).
+		Where("source.platform", "==", string(platform)).
+		OrderBy("createdAt", firestore.Desc)
+	return r.find(ctx, "recipes.byPlatform", q, nil)
 }
 
-// SearchByIngredient searches recipes containing a specific ingredient in title or ingredients
-func (r *RecipeRepository) SearchByIngredient(ctx context.Context, userID recipe.UserID, ingredient string) ([]*recipe.Recipe, error) {
-	// Firestore doesn't support full-text search, so we fetch all and filter in-memory
-	allRecipes, err := r.FindByUserID(ctx, userID)
-	if err != nil {
-		return nil, err
-	}
+// FindByUserIDAndFlavor retrieves recipes for a user classified with a flavor profile
+func (r *RecipeRepository) FindByUserIDAndFlavor(ctx context.Context, userID recipe.UserID, flavor recipe.Flavor) ([]*recipe.Recipe, error) {
+	q := r.byUser(userID).
+		Where("flavors", "array-contains", string(flavor)).
+		OrderBy("createdAt", firestore.Desc)
+	return r.find(ctx, "recipes.byFlavor", q, nil)
+}
 
-	// Normalize search term
-	searchTerm := strings.ToLower(strings.TrimSpace(ingredient))
-	if searchTerm == "" {
-		return allRecipes, nil
-	}
+// GetPlatformCounts returns the count of recipes per source platform for a user
+func (r *RecipeRepository) GetPlatformCounts(ctx context.Context, userID recipe.UserID) (map[recipe.Platform]int, error) {
+	// Counted in-memory like categories, reading only the platform of each recipe
+	iter := r.byUser(userID).Select("source.platform").Documents(ctx)
+	defer iter.Stop()
 
-	var matchingRecipes []*recipe.Recipe
-	for _, rec := range allRecipes {
-		// Check title
-		if strings.Contains(strings.ToLower(rec.Title()), searchTerm) {
-			matchingRecipes = append(matchingRecipes, rec)
-			continue
+	counts := make(map[recipe.Platform]int)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate recipes: %w", err)
 		}
 
-		// Check ingredients
-		for _, ing := range rec.Ingredients() {
-			if strings.Contains(strings.ToLower(ing.Name()), searchTerm) {
-				matchingRecipes = append(matchingRecipes, rec)
-				break
-			}
+		var recipeDoc recipeDoc
+		if err := doc.DataTo(&recipeDoc); err != nil {
+			continue // Skip invalid documents
 		}
+		counts[recipe.Platform(recipeDoc.Source.Platform)]++
 	}
 
-	return matchingRecipes, nil
+	return counts, nil
 }
 
-// SearchByIngredientFilter searches recipes using complex ingredient filters (AND/OR/NOT logic)
-func (r *RecipeRepository) SearchByIngredientFilter(ctx context.Context, userID recipe.UserID, filter recipe.IngredientFilter) ([]*recipe.Recipe, error) {
-	// Firestore doesn't support complex text search, so we fetch all and filter in-memory
-	allRecipes, err := r.FindByUserID(ctx, userID)
-	if err != nil {
-		return nil, err
-	}
+// FindByUserIDAndDateRange retrieves recipes for a user saved within a date range, newest first
+func (r *RecipeRepository) FindByUserIDAndDateRange(ctx context.Context, userID recipe.UserID, saved recipe.DateRange) ([]*recipe.Recipe, error) {
+	q := r.byUser(userID).
+		Where("createdAt", ">=", saved.From).
+		Where("createdAt", "<", saved.To).
+		OrderBy("createdAt", firestore.Desc)
+	return r.find(ctx, "recipes.byDateRange", q, nil)
+}
 
-	var matchingRecipes []*recipe.Recipe
-	for _, rec := range allRecipes {
-		if r.matchesIngredientFilter(rec, filter) {
-			matchingRecipes = append(matchingRecipes, rec)
+// SearchByIngredient searches recipes containing a specific ingredient in title or ingredients
+func (r *RecipeRepository) SearchByIngredient(ctx context.Context, userID recipe.UserID, ingredient string) ([]*recipe.Recipe, error) {
+	// Firestore doesn't support full-text search, so we fetch all and filter in-memory
+	var keep func(*recipe.Recipe) bool
+	if strings.TrimSpace(ingredient) != "" {
+		keep = func(rec *recipe.Recipe) bool {
+			return rec.MentionsIngredient(ingredient)
 		}
 	}
 
-	return matchingRecipes, nil
+	return r.find(ctx, "recipes.byIngredient", r.byUser(userID).OrderBy("createdAt", firestore.Desc), keep)
 }
 
-// matchesIngredientFilter checks if a recipe matches the ingredient filter criteria
-func (r *RecipeRepository) matchesIngredientFilter(rec *recipe.Recipe, filter recipe.IngredientFilter) bool {
-	// Get all searchable text from recipe (title + ingredient names)
-	searchableText := r.getSearchableText(rec)
+// SearchByIngredientFilter searches recipes using complex ingredient filters (AND/OR/NOT logic)
+func (r *RecipeRepository) SearchByIngredientFilter(ctx context.Context, userID recipe.UserID, filter recipe.IngredientFilter) ([]*recipe.Recipe, error) {
+	// Firestore doesn't support complex text search, so we fetch all and filter in-memory
+	return r.find(ctx, "recipes.byIngredientFilter", r.byUser(userID).OrderBy("createdAt", firestore.Desc), filter.Matches)
+}
 
-	// All INCLUDE ingredients must be present (AND logic)
-	for _, required := range filter.Include {
-		if !r.containsIngredient(searchableText, required) {
-			return false
-		}
+// FindByUserIDAndFilters retrieves recipes for a user with optional category, dietary tag and language filters
+func (r *RecipeRepository) FindByUserIDAndFilters(ctx context.Context, userID recipe.UserID, category *recipe.Category, dietaryTags []recipe.DietaryTag, language string) ([]*recipe.Recipe, error) {
+	q := r.byUser(userID)
+	if category != nil {
+		q = q.Where("category", "==", string(*category))
 	}
-
-	// No EXCLUDE ingredients should be present (NOT logic)
-	for _, excluded := range filter.Exclude {
-		if r.containsIngredient(searchableText, excluded) {
-			return false
-		}
+	if language != "" {
+		q = q.Where("sourceLanguage", "==", language)
 	}
 
-	// Optional: at least one of these should be present (OR logic)
-	// Only apply if there are optional ingredients specified
-	if len(filter.Optional) > 0 {
-		hasAny := false
-		for _, optional := range filter.Optional {
-			if r.containsIngredient(searchableText, optional) {
-				hasAny = true
-				break
+	// Firestore allows one array-contains filter per query, so the first
+	// dietary tag is matched there and the others in-memory
+	var keep func(*recipe.Recipe) bool
+	if len(dietaryTags) > 0 {
+		q = q.Where("dietaryTags", "array-contains", string(dietaryTags[0]))
+		if len(dietaryTags) > 1 {
+			keep = func(rec *recipe.Recipe) bool {
+				return rec.HasAllDietaryTags(dietaryTags[1:])
 			}
 		}
-		if !hasAny {
-			return false
-		}
 	}
 
-	return true
+	return r.find(ctx, "recipes.byFilters", q.OrderBy("createdAt", firestore.Desc), keep)
 }
 
-// getSearchableText extracts all searchable text from a recipe
-func (r *RecipeRepository) getSearchableText(rec *recipe.Recipe) []string {
-	var texts []string
-
-	// Add title
-	texts = append(texts, strings.ToLower(rec.Title()))
-
-	// Add all ingredient names
-	for _, ing := range rec.Ingredients() {
-		texts = append(texts, strings.ToLower(ing.Name()))
-	}
-
-	// Add normalized ingredients if available (for better matching)
-	for _, normalized := range rec.NormalizedIngredients() {
-		texts = append(texts, strings.ToLower(normalized))
-	}
-
-	return texts
+// Update updates an existing recipe
+func (r *RecipeRepository) Update(ctx context.Context, rec *recipe.Recipe) error {
+	return r.Save(ctx, rec) // In Firestore, Set with merge accomplishes update
 }
 
-// containsIngredient checks if any of the searchable texts contain the ingredient
-func (r *RecipeRepository) containsIngredient(searchableTexts []string, ingredient string) bool {
-	normalizedIngredient := strings.ToLower(strings.TrimSpace(ingredient))
-	if normalizedIngredient == "" {
-		return true // Empty ingredient matches everything
-	}
+// StripTranscripts removes the transcripts and captions of every user's recipes
+// saved before the cutoff, along with the blobs of those stored outside the
+// document. Only the transcript and captions fields of the old recipes are read.
+func (r *RecipeRepository) StripTranscripts(ctx context.Context, savedBefore time.Time) (int, error) {
+	iter := r.client.Collection("recipes").
+		Where("createdAt", "<", savedBefore).
+		Select("transcript", "transcriptBlob", "transcriptGzip", "captions", "captionsBlob", "captionsGzip").
+		Documents(ctx)
+	defer iter.Stop()
 
-	for _, text := range searchableTexts {
-		if strings.Contains(text, normalizedIngredient) {
-			return true
+	stripped := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return stripped, nil
+		}
+		if err != nil {
+			return stripped, fmt.Errorf("failed to find old transcripts: %w", err)
 		}
-	}
-	return false
-}
 
-// FindByUserIDAndFilters retrieves recipes for a user with optional category and dietary tag filters
-func (r *RecipeRepository) FindByUserIDAndFilters(ctx context.Context, userID recipe.UserID, category *recipe.Category, dietaryTags []recipe.DietaryTag) ([]*recipe.Recipe, error) {
-	// Start with all user recipes
-	var recipes []*recipe.Recipe
-	var err error
+		var sources struct {
+			Transcript     string `firestore:"transcript"`
+			TranscriptBlob string `firestore:"transcriptBlob"`
+			TranscriptGzip []byte `firestore:"transcriptGzip"`
+			Captions       string `firestore:"captions"`
+			CaptionsBlob   string `firestore:"captionsBlob"`
+			CaptionsGzip   []byte `firestore:"captionsGzip"`
+		}
+		if err := doc.DataTo(&sources); err != nil {
+			return stripped, fmt.Errorf("failed to parse recipe document: %w", err)
+		}
+		if sources.Transcript == "" && sources.TranscriptBlob == "" && sources.TranscriptGzip == nil &&
+			sources.Captions == "" && sources.CaptionsBlob == "" && sources.CaptionsGzip == nil {
+			continue
+		}
 
-	if category != nil {
-		// Use category filter if provided
-		recipes, err = r.FindByUserIDAndCategory(ctx, userID, *category)
-	} else {
-		recipes, err = r.FindByUserID(ctx, userID)
+		_, err = doc.Ref.Update(ctx, []firestore.Update{
+			{Path: "transcript", Value: ""},
+			{Path: "transcriptBlob", Value: firestore.Delete},
+			{Path: "transcriptGzip", Value: firestore.Delete},
+			{Path: "captions", Value: ""},
+			{Path: "captionsBlob", Value: firestore.Delete},
+			{Path: "captionsGzip", Value: firestore.Delete},
+		})
+		if err != nil {
+			return stripped, fmt.Errorf("failed to strip transcript: %w", err)
+		}
+		if r.blobs != nil {
+			for _, key := range []string{sources.TranscriptBlob, sources.CaptionsBlob} {
+				if key == "" {
+					continue
+				}
+				if err := r.blobs.Delete(ctx, key); err != nil {
+					return stripped, fmt.Errorf("failed to delete recipe sources: %w", err)
+				}
+			}
+		}
+		stripped++
 	}
+}
 
+// Delete removes a recipe
+func (r *RecipeRepository) Delete(ctx context.Context, id recipe.RecipeID) error {
+	_, err := r.client.Collection("recipes").Doc(id.String()).Delete(ctx)
 	if err != nil {
-		return nil, err
-	}
-
-	// If no dietary tags, return as-is
-	if len(dietaryTags) == 0 {
-		return recipes, nil
+		return fmt.Errorf("failed to delete recipe: %w", err)
 	}
 
-	// Filter by dietary tags in-memory
-	var filtered []*recipe.Recipe
-	for _, rec := range recipes {
-		if r.hasAllTags(rec, dietaryTags) {
-			filtered = append(filtered, rec)
+	if r.blobs != nil {
+		for _, key := range []string{sourceBlobKey(id.String(), "transcript"), sourceBlobKey(id.String(), "captions")} {
+			if err := r.blobs.Delete(ctx, key); err != nil {
+				return fmt.Errorf("failed to delete recipe sources: %w", err)
+			}
 		}
 	}
+	return nil
+}
 
-	return filtered, nil
+// sourceBlobKey is the blob key of a recipe's transcript or captions
+func sourceBlobKey(recipeID, name string) string {
+	return fmt.Sprintf("sources/%s/%s.txt", recipeID, name)
 }
 
-// hasAllTags checks if a recipe has all the specified dietary tags
-func (r *RecipeRepository) hasAllTags(rec *recipe.Recipe, requiredTags []recipe.DietaryTag) bool {
-	recipeTags := make(map[recipe.DietaryTag]bool)
-	for _, tag := range rec.DietaryTags() {
-		recipeTags[tag] = true
+// storeSources moves a transcript or captions longer than limit into blob storage
+func (r *RecipeRepository) storeSources(ctx context.Context, doc *recipeDoc, limit int) error {
+	if r.blobs == nil {
+		return nil
 	}
-
-	for _, required := range requiredTags {
-		if !recipeTags[required] {
-			return false
+	for _, text := range doc.sourceTexts() {
+		if *text.text == "" || len(*text.text) <= limit {
+			continue
+		}
+		key := sourceBlobKey(doc.RecipeID, text.name)
+		if err := r.blobs.Put(ctx, key, "text/plain; charset=utf-8", []byte(*text.text)); err != nil {
+			return fmt.Errorf("failed to store recipe %s: %w", text.name, err)
 		}
+		*text.text, *text.key = "", key
 	}
-
-	return true
-}
-
-// Update updates an existing recipe
-func (r *RecipeRepository) Update(ctx context.Context, rec *recipe.Recipe) error {
-	return r.Save(ctx, rec) // In Firestore, Set with merge accomplishes update
+	return nil
 }
 
-// Delete removes a recipe
-func (r *RecipeRepository) Delete(ctx context.Context, id recipe.RecipeID) error {
-	_, err := r.client.Collection("recipes").Doc(id.String()).Delete(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to delete recipe: %w", err)
+// loadSources reads back a transcript or captions kept in blob storage or compressed
+func (r *RecipeRepository) loadSources(ctx context.Context, doc *recipeDoc) error {
+	for _, text := range doc.sourceTexts() {
+		if *text.gzip != nil {
+			unzipped, err := gunzipText(*text.gzip)
+			if err != nil {
+				return fmt.Errorf("failed to read compressed recipe %s: %w", text.name, err)
+			}
+			*text.text = unzipped
+			continue
+		}
+		if *text.key == "" {
+			continue
+		}
+		if r.blobs == nil {
+			return fmt.Errorf("recipe %s is in blob storage, which is not configured", text.name)
+		}
+		data, err := r.blobs.Get(ctx, *text.key)
+		if err != nil {
+			return fmt.Errorf("failed to load recipe %s: %w", text.name, err)
+		}
+		*text.text = string(data)
 	}
 	return nil
 }
@@ -420,14 +629,22 @@ func (r *RecipeRepository) toDocument(rec *recipe.Recipe) *recipeDoc {
 			StepNumber:      inst.StepNumber(),
 			Text:            inst.Text(),
 			DurationMinutes: durationMinutes,
+			StartSeconds:    timestampToSeconds(inst.Timestamp()),
 		}
 	}
 
 	// Convert source
+	attribution := rec.Source().Attribution()
 	doc.Source = sourceDoc{
-		URL:      rec.Source().URL(),
-		Platform: string(rec.Source().Platform()),
-		Author:   rec.Source().Author(),
+		URL:          rec.Source().URL(),
+		Platform:     string(rec.Source().Platform()),
+		Author:       rec.Source().Author(),
+		Segment:      rec.Source().Segment(),
+		Handle:       attribution.Handle,
+		CanonicalURL: attribution.CanonicalURL,
+	}
+	if !attribution.PublishedAt.IsZero() {
+		doc.Source.PublishedAt = &attribution.PublishedAt
 	}
 
 	// Convert optional times
@@ -462,6 +679,44 @@ func (r *RecipeRepository) toDocument(rec *recipe.Recipe) *recipeDoc {
 	// Convert normalized ingredients
 	doc.NormalizedIngredients = rec.NormalizedIngredients()
 
+	// Convert difficulty
+	doc.DifficultyScore = rec.DifficultyScore()
+
+	// Convert provenance
+	for _, p := range rec.Provenance() {
+		doc.Provenance = append(doc.Provenance, provenanceDoc{
+			Field:      p.Field(),
+			Source:     string(p.Source()),
+			Confidence: p.Confidence(),
+		})
+	}
+
+	// Convert flavors
+	for _, f := range rec.Flavors() {
+		doc.Flavors = append(doc.Flavors, string(f))
+	}
+
+	// Convert remix
+	if remix := rec.Remix(); remix != nil {
+		doc.Remix = &remixDoc{
+			ParentRecipeID: remix.ParentID.String(),
+			ParentTitle:    remix.ParentTitle,
+			Goal:           remix.Goal,
+		}
+		for _, sub := range remix.Substitutions {
+			doc.Remix.Substitutions = append(doc.Remix.Substitutions, substitutionDoc{
+				Original:    sub.Original,
+				Replacement: sub.Replacement,
+				Reason:      sub.Reason,
+			})
+		}
+	}
+
+	// Convert cover
+	if cover := rec.Cover(); cover != nil {
+		doc.Cover = &coverDoc{Key: cover.Key, AIGenerated: cover.AIGenerated, CreatedAt: cover.CreatedAt}
+	}
+
 	// Convert translated ingredients
 	if rec.TranslatedIngredients() != nil {
 		doc.TranslatedIngredients = make([]ingredientDoc, len(rec.TranslatedIngredients()))
@@ -489,6 +744,7 @@ func (r *RecipeRepository) toDocument(rec *recipe.Recipe) *recipeDoc {
 				StepNumber:      inst.StepNumber(),
 				Text:            inst.Text(),
 				DurationMinutes: durationMinutes,
+				StartSeconds:    timestampToSeconds(inst.Timestamp()),
 			}
 		}
 	}
@@ -515,12 +771,24 @@ func (r *RecipeRepository) fromDocument(doc *recipeDoc) *recipe.Recipe {
 		}
 
 		inst, _ := recipe.NewInstruction(instDoc.StepNumber, instDoc.Text, duration)
+		if instDoc.StartSeconds != nil {
+			inst = inst.WithTimestamp(time.Duration(*instDoc.StartSeconds) * time.Second)
+		}
 		instructions[i] = inst
 	}
 
 	// Convert source
 	platform := recipe.Platform(doc.Source.Platform)
 	source, _ := recipe.NewSource(doc.Source.URL, platform, doc.Source.Author)
+	if platform == recipe.PlatformAIGenerated {
+		source = recipe.NewGeneratedSource()
+	}
+	source = source.WithSegment(doc.Source.Segment)
+	var published time.Time
+	if doc.Source.PublishedAt != nil {
+		published = *doc.Source.PublishedAt
+	}
+	source = source.WithAttribution(recipe.NewAttribution(doc.Source.Handle, doc.Source.CanonicalURL, published))
 
 	// Convert optional times
 	var prepTime, cookTime *time.Duration
@@ -567,12 +835,41 @@ func (r *RecipeRepository) fromDocument(doc *recipeDoc) *recipe.Recipe {
 			}
 
 			inst, _ := recipe.NewInstruction(instDoc.StepNumber, instDoc.Text, duration)
+			if instDoc.StartSeconds != nil {
+				inst = inst.WithTimestamp(time.Duration(*instDoc.StartSeconds) * time.Second)
+			}
+		if instDoc.StartSeconds != nil {
+			inst = inst.WithTimestamp(time.Duration(*instDoc.StartSeconds) * time.Second)
+		}
 			translatedInstructions[i] = inst
 		}
 	}
 
-	// Reconstruct the recipe with all fields including normalized ingredients
-	return recipe.ReconstructRecipeWithNormalizedIngredients(
+	// Convert provenance
+	var provenance []recipe.FieldProvenance
+	for _, p := range doc.Provenance {
+		if fp, err := recipe.NewFieldProvenance(p.Field, recipe.TextSource(p.Source), p.Confidence); err == nil {
+			provenance = append(provenance, fp)
+		}
+	}
+
+	// Convert remix
+	var remix *recipe.Remix
+	if doc.Remix != nil {
+		substitutions := make([]recipe.Substitution, len(doc.Remix.Substitutions))
+		for i, sub := range doc.Remix.Substitutions {
+			substitutions[i] = recipe.Substitution{Original: sub.Original, Replacement: sub.Replacement, Reason: sub.Reason}
+		}
+		remix, _ = recipe.NewRemix(recipe.RecipeID(doc.Remix.ParentRecipeID), doc.Remix.ParentTitle, doc.Remix.Goal, substitutions)
+	}
+
+	var cover *recipe.Cover
+	if doc.Cover != nil {
+		cover = &recipe.Cover{Key: doc.Cover.Key, AIGenerated: doc.Cover.AIGenerated, CreatedAt: doc.Cover.CreatedAt}
+	}
+
+	// Reconstruct the recipe with all fields including normalized ingredients, difficulty, provenance, remix, flavors and cover
+	return recipe.ReconstructRecipeWithCover(
 		recipe.RecipeID(doc.RecipeID),
 		recipe.UserID(doc.UserID),
 		doc.Title,
@@ -595,5 +892,10 @@ func (r *RecipeRepository) fromDocument(doc *recipeDoc) *recipe.Recipe {
 		translatedIngredients,
 		translatedInstructions,
 		doc.NormalizedIngredients,
+		doc.DifficultyScore,
+		provenance,
+		remix,
+		recipe.ParseFlavors(doc.Flavors),
+		cover,
 	)
 }
@@ -0,0 +1,117 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// RecipeVariantRepository implements the recipe.VariantRepository interface using Firestore.
+// Variants live in the recipes/{recipeId}/variants subcollection, keyed by appliance.
+type RecipeVariantRepository struct {
+	client *firestore.Client
+}
+
+// NewRecipeVariantRepository creates a new Firebase recipe variant repository
+func NewRecipeVariantRepository(client *firestore.Client) *RecipeVariantRepository {
+	return &RecipeVariantRepository{
+		client: client,
+	}
+}
+
+// variantDoc represents the Firestore document structure of a recipe variant
+type variantDoc struct {
+	RecipeID        string           `firestore:"recipeId"`
+	Appliance       string           `firestore:"appliance"`
+	Instructions    []instructionDoc `firestore:"instructions"`
+	PrepTimeMinutes *int             `firestore:"prepTimeMinutes,omitempty"`
+	CookTimeMinutes *int             `firestore:"cookTimeMinutes,omitempty"`
+	Notes           string           `firestore:"notes,omitempty"`
+	CreatedAt       time.Time        `firestore:"createdAt"`
+}
+
+func (r *RecipeVariantRepository) variants(recipeID recipe.RecipeID) *firestore.CollectionRef {
+	return r.client.Collection("recipes").Doc(recipeID.String()).Collection("variants")
+}
+
+// SaveVariant stores a variant, replacing the recipe's previous variant for the same appliance
+func (r *RecipeVariantRepository) SaveVariant(ctx context.Context, variant *recipe.Variant) error {
+	doc := variantDoc{
+		RecipeID:        variant.RecipeID.String(),
+		Appliance:       string(variant.Appliance),
+		Instructions:    toInstructionDocs(variant.Instructions),
+		PrepTimeMinutes: durationToMinutes(variant.PrepTime),
+		CookTimeMinutes: durationToMinutes(variant.CookTime),
+		Notes:           variant.Notes,
+		CreatedAt:       variant.CreatedAt,
+	}
+
+	_, err := r.variants(variant.RecipeID).Doc(string(variant.Appliance)).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save recipe variant: %w", err)
+	}
+
+	return nil
+}
+
+// FindVariants returns all variants of a recipe
+func (r *RecipeVariantRepository) FindVariants(ctx context.Context, recipeID recipe.RecipeID) ([]*recipe.Variant, error) {
+	iter := r.variants(recipeID).Documents(ctx)
+	defer iter.Stop()
+
+	var variants []*recipe.Variant
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate recipe variants: %w", err)
+		}
+
+		var doc variantDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse recipe variant: %w", err)
+		}
+		variants = append(variants, fromVariantDoc(&doc))
+	}
+
+	return variants, nil
+}
+
+// FindVariant returns the variant of a recipe for an appliance
+func (r *RecipeVariantRepository) FindVariant(ctx context.Context, recipeID recipe.RecipeID, appliance recipe.Appliance) (*recipe.Variant, error) {
+	snap, err := r.variants(recipeID).Doc(string(appliance)).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrVariantNotFound
+		}
+		return nil, fmt.Errorf("failed to get recipe variant: %w", err)
+	}
+
+	var doc variantDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse recipe variant: %w", err)
+	}
+
+	return fromVariantDoc(&doc), nil
+}
+
+func fromVariantDoc(doc *variantDoc) *recipe.Variant {
+	return &recipe.Variant{
+		RecipeID:     recipe.RecipeID(doc.RecipeID),
+		Appliance:    recipe.Appliance(doc.Appliance),
+		Instructions: fromInstructionDocs(doc.Instructions),
+		PrepTime:     minutesToDuration(doc.PrepTimeMinutes),
+		CookTime:     minutesToDuration(doc.CookTimeMinutes),
+		Notes:        doc.Notes,
+		CreatedAt:    doc.CreatedAt,
+	}
+}
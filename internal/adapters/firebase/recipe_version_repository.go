@@ -0,0 +1,293 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// RecipeVersionRepository implements the recipe.VersionRepository interface using Firestore.
+// Versions live in the recipes/{recipeId}/versions subcollection, keyed by version number.
+type RecipeVersionRepository struct {
+	client *firestore.Client
+}
+
+// NewRecipeVersionRepository creates a new Firebase recipe version repository
+func NewRecipeVersionRepository(client *firestore.Client) *RecipeVersionRepository {
+	return &RecipeVersionRepository{
+		client: client,
+	}
+}
+
+// versionDoc represents the Firestore document structure of a recipe version
+type versionDoc struct {
+	Number    int              `firestore:"number"`
+	RecipeID  string           `firestore:"recipeId"`
+	Reason    string           `firestore:"reason"`
+	Snapshot  snapshotDoc      `firestore:"snapshot"`
+	Changes   []fieldChangeDoc `firestore:"changes"`
+	CreatedAt time.Time        `firestore:"createdAt"`
+}
+
+type snapshotDoc struct {
+	Title                  string           `firestore:"title"`
+	Ingredients            []ingredientDoc  `firestore:"ingredients"`
+	Instructions           []instructionDoc `firestore:"instructions"`
+	PrepTimeMinutes        *int             `firestore:"prepTimeMinutes,omitempty"`
+	CookTimeMinutes        *int             `firestore:"cookTimeMinutes,omitempty"`
+	Servings               *int             `firestore:"servings,omitempty"`
+	Category               string           `firestore:"category,omitempty"`
+	Cuisine                string           `firestore:"cuisine,omitempty"`
+	DietaryTags            []string         `firestore:"dietaryTags,omitempty"`
+	Tags                   []string         `firestore:"tags,omitempty"`
+	SourceLanguage         string           `firestore:"sourceLanguage,omitempty"`
+	TranslatedTitle        *string          `firestore:"translatedTitle,omitempty"`
+	TranslatedIngredients  []ingredientDoc  `firestore:"translatedIngredients,omitempty"`
+	TranslatedInstructions []instructionDoc `firestore:"translatedInstructions,omitempty"`
+	NormalizedIngredients  []string         `firestore:"normalizedIngredients,omitempty"`
+	Flavors                []string         `firestore:"flavors"` // missing from versions stored before flavors were kept
+}
+
+type fieldChangeDoc struct {
+	Field  string `firestore:"field"`
+	Before string `firestore:"before"`
+	After  string `firestore:"after"`
+}
+
+func (r *RecipeVersionRepository) versions(recipeID recipe.RecipeID) *firestore.CollectionRef {
+	return r.client.Collection("recipes").Doc(recipeID.String()).Collection("versions")
+}
+
+// SaveVersion stores a version of a recipe
+func (r *RecipeVersionRepository) SaveVersion(ctx context.Context, version *recipe.Version) error {
+	doc := versionDoc{
+		Number:    version.Number,
+		RecipeID:  version.RecipeID.String(),
+		Reason:    version.Reason,
+		Snapshot:  toSnapshotDoc(version.Snapshot),
+		CreatedAt: version.CreatedAt,
+	}
+	for _, c := range version.Changes {
+		doc.Changes = append(doc.Changes, fieldChangeDoc{Field: c.Field, Before: c.Before, After: c.After})
+	}
+
+	_, err := r.versions(version.RecipeID).Doc(strconv.Itoa(version.Number)).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save recipe version: %w", err)
+	}
+
+	return nil
+}
+
+// FindVersions returns all versions of a recipe, oldest first
+func (r *RecipeVersionRepository) FindVersions(ctx context.Context, recipeID recipe.RecipeID) ([]*recipe.Version, error) {
+	iter := r.versions(recipeID).OrderBy("number", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+
+	var versions []*recipe.Version
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate recipe versions: %w", err)
+		}
+
+		var doc versionDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse recipe version: %w", err)
+		}
+		versions = append(versions, fromVersionDoc(&doc))
+	}
+
+	return versions, nil
+}
+
+// FindVersion returns a single version of a recipe
+func (r *RecipeVersionRepository) FindVersion(ctx context.Context, recipeID recipe.RecipeID, number int) (*recipe.Version, error) {
+	snap, err := r.versions(recipeID).Doc(strconv.Itoa(number)).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrVersionNotFound
+		}
+		return nil, fmt.Errorf("failed to get recipe version: %w", err)
+	}
+
+	var doc versionDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse recipe version: %w", err)
+	}
+
+	return fromVersionDoc(&doc), nil
+}
+
+func fromVersionDoc(doc *versionDoc) *recipe.Version {
+	changes := make([]recipe.FieldChange, len(doc.Changes))
+	for i, c := range doc.Changes {
+		changes[i] = recipe.FieldChange{Field: c.Field, Before: c.Before, After: c.After}
+	}
+
+	return &recipe.Version{
+		Number:    doc.Number,
+		RecipeID:  recipe.RecipeID(doc.RecipeID),
+		Snapshot:  fromSnapshotDoc(doc.Snapshot),
+		Changes:   changes,
+		Reason:    doc.Reason,
+		CreatedAt: doc.CreatedAt,
+	}
+}
+
+func toSnapshotDoc(s recipe.Snapshot) snapshotDoc {
+	doc := snapshotDoc{
+		Title:                  s.Title,
+		Ingredients:            toIngredientDocs(s.Ingredients),
+		Instructions:           toInstructionDocs(s.Instructions),
+		PrepTimeMinutes:        durationToMinutes(s.PrepTime),
+		CookTimeMinutes:        durationToMinutes(s.CookTime),
+		Servings:               s.Servings,
+		Category:               string(s.Category),
+		Cuisine:                s.Cuisine,
+		Tags:                   s.Tags,
+		SourceLanguage:         s.SourceLanguage,
+		TranslatedTitle:        s.TranslatedTitle,
+		TranslatedIngredients:  toIngredientDocs(s.TranslatedIngredients),
+		TranslatedInstructions: toInstructionDocs(s.TranslatedInstructions),
+		NormalizedIngredients:  s.NormalizedIngredients,
+	}
+	if s.Flavors != nil {
+		doc.Flavors = make([]string, 0, len(s.Flavors))
+		for _, flavor := range s.Flavors {
+			doc.Flavors = append(doc.Flavors, string(flavor))
+		}
+	}
+	for _, tag := range s.DietaryTags {
+		doc.DietaryTags = append(doc.DietaryTags, string(tag))
+	}
+	return doc
+}
+
+func fromSnapshotDoc(doc snapshotDoc) recipe.Snapshot {
+	dietaryTags := make([]recipe.DietaryTag, 0, len(doc.DietaryTags))
+	for _, tagStr := range doc.DietaryTags {
+		if tag, valid := recipe.ParseDietaryTag(tagStr); valid {
+			dietaryTags = append(dietaryTags, tag)
+		}
+	}
+
+	// Versions stored before flavors were kept have none recorded
+	var flavors []recipe.Flavor
+	if doc.Flavors != nil {
+		flavors = recipe.ParseFlavors(doc.Flavors)
+	}
+
+	return recipe.Snapshot{
+		Title:                  doc.Title,
+		Ingredients:            fromIngredientDocs(doc.Ingredients),
+		Instructions:           fromInstructionDocs(doc.Instructions),
+		PrepTime:               minutesToDuration(doc.PrepTimeMinutes),
+		CookTime:               minutesToDuration(doc.CookTimeMinutes),
+		Servings:               doc.Servings,
+		Category:               recipe.CategoryFromLLM(doc.Category),
+		Cuisine:                doc.Cuisine,
+		DietaryTags:            dietaryTags,
+		Tags:                   doc.Tags,
+		SourceLanguage:         doc.SourceLanguage,
+		TranslatedTitle:        doc.TranslatedTitle,
+		TranslatedIngredients:  fromIngredientDocs(doc.TranslatedIngredients),
+		TranslatedInstructions: fromInstructionDocs(doc.TranslatedInstructions),
+		NormalizedIngredients:  doc.NormalizedIngredients,
+		Flavors:                flavors,
+	}
+}
+
+func toIngredientDocs(ingredients []recipe.Ingredient) []ingredientDoc {
+	if ingredients == nil {
+		return nil
+	}
+	docs := make([]ingredientDoc, len(ingredients))
+	for i, ing := range ingredients {
+		docs[i] = ingredientDoc{
+			Name:     ing.Name(),
+			Quantity: ing.Quantity(),
+			Unit:     ing.Unit(),
+			Notes:    ing.Notes(),
+		}
+	}
+	return docs
+}
+
+func fromIngredientDocs(docs []ingredientDoc) []recipe.Ingredient {
+	if len(docs) == 0 {
+		return nil
+	}
+	ingredients := make([]recipe.Ingredient, len(docs))
+	for i, ingDoc := range docs {
+		ing, _ := recipe.NewIngredient(ingDoc.Name, ingDoc.Quantity, ingDoc.Unit, ingDoc.Notes)
+		ingredients[i] = ing
+	}
+	return ingredients
+}
+
+func toInstructionDocs(instructions []recipe.Instruction) []instructionDoc {
+	if instructions == nil {
+		return nil
+	}
+	docs := make([]instructionDoc, len(instructions))
+	for i, inst := range instructions {
+		docs[i] = instructionDoc{
+			StepNumber:      inst.StepNumber(),
+			Text:            inst.Text(),
+			DurationMinutes: durationToMinutes(inst.Duration()),
+			StartSeconds:    timestampToSeconds(inst.Timestamp()),
+		}
+	}
+	return docs
+}
+
+func fromInstructionDocs(docs []instructionDoc) []recipe.Instruction {
+	if len(docs) == 0 {
+		return nil
+	}
+	instructions := make([]recipe.Instruction, len(docs))
+	for i, instDoc := range docs {
+		inst, _ := recipe.NewInstruction(instDoc.StepNumber, instDoc.Text, minutesToDuration(instDoc.DurationMinutes))
+		if instDoc.StartSeconds != nil {
+			inst = inst.WithTimestamp(time.Duration(*instDoc.StartSeconds) * time.Second)
+		}
+		instructions[i] = inst
+	}
+	return instructions
+}
+
+func durationToMinutes(d *time.Duration) *int {
+	if d == nil {
+		return nil
+	}
+	minutes := int(d.Minutes())
+	return &minutes
+}
+
+func timestampToSeconds(d *time.Duration) *int {
+	if d == nil {
+		return nil
+	}
+	seconds := int(d.Seconds())
+	return &seconds
+}
+
+func minutesToDuration(minutes *int) *time.Duration {
+	if minutes == nil {
+		return nil
+	}
+	d := time.Duration(*minutes) * time.Minute
+	return &d
+}
@@ -0,0 +1,24 @@
+package firebase
+
+import (
+	"testing"
+
+	"receipt-bot/internal/domain/recipe"
+)
+
+func TestSnapshotDoc_Flavors(t *testing.T) {
+	snap := recipe.Snapshot{Title: "Chili", Flavors: []recipe.Flavor{recipe.FlavorSpicy, recipe.FlavorUmami}}
+	got := fromSnapshotDoc(toSnapshotDoc(snap))
+	if len(got.Flavors) != 2 || got.Flavors[0] != recipe.FlavorSpicy || got.Flavors[1] != recipe.FlavorUmami {
+		t.Errorf("flavors read back = %v, want %v", got.Flavors, snap.Flavors)
+	}
+
+	// No flavors are stored as none, telling them apart from versions that predate flavors
+	none := fromSnapshotDoc(toSnapshotDoc(recipe.Snapshot{Title: "Rice", Flavors: []recipe.Flavor{}}))
+	if none.Flavors == nil || len(none.Flavors) != 0 {
+		t.Errorf("empty flavors read back = %#v, want an empty list", none.Flavors)
+	}
+	if legacy := fromSnapshotDoc(snapshotDoc{Title: "Old"}); legacy.Flavors != nil {
+		t.Errorf("flavors of a version without any recorded = %#v, want nil", legacy.Flavors)
+	}
+}
@@ -0,0 +1,131 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/requests"
+	"receipt-bot/internal/domain/shared"
+)
+
+// RequestRepository implements the requests.Repository interface using Firestore.
+// Request lists are stored in the dishRequests collection, one document per household.
+type RequestRepository struct {
+	client *firestore.Client
+}
+
+// NewRequestRepository creates a new Firebase request list repository
+func NewRequestRepository(client *firestore.Client) *RequestRepository {
+	return &RequestRepository{
+		client: client,
+	}
+}
+
+// requestListDoc represents the Firestore document structure
+type requestListDoc struct {
+	OwnerID   string       `firestore:"ownerId"`
+	Requests  []requestDoc `firestore:"requests"`
+	UpdatedAt time.Time    `firestore:"updatedAt"`
+}
+
+type requestDoc struct {
+	Dish        string    `firestore:"dish"`
+	Requester   memberDoc `firestore:"requester"`
+	RequestedAt time.Time `firestore:"requestedAt"`
+	Plan        *planDoc  `firestore:"plan,omitempty"`
+}
+
+type memberDoc struct {
+	UserID     string `firestore:"userId"`
+	TelegramID int64  `firestore:"telegramId"`
+	Name       string `firestore:"name"`
+}
+
+type planDoc struct {
+	Cook     memberDoc `firestore:"cook"`
+	RecipeID string    `firestore:"recipeId"`
+	Title    string    `firestore:"title"`
+	Day      int       `firestore:"day"`
+}
+
+// FindByOwner retrieves the request list of a household
+func (r *RequestRepository) FindByOwner(ctx context.Context, ownerID requests.OwnerID) (*requests.List, error) {
+	snap, err := r.client.Collection("dishRequests").Doc(ownerID.String()).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrRequestsNotFound
+		}
+		return nil, fmt.Errorf("failed to find dish requests: %w", err)
+	}
+
+	var doc requestListDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse dish requests document: %w", err)
+	}
+
+	reqs := make([]requests.Request, len(doc.Requests))
+	for i, d := range doc.Requests {
+		reqs[i] = requests.Request{
+			Dish:        d.Dish,
+			Requester:   fromMemberDoc(d.Requester),
+			RequestedAt: d.RequestedAt,
+		}
+		if d.Plan != nil {
+			reqs[i].Plan = &requests.Plan{
+				Cook:     fromMemberDoc(d.Plan.Cook),
+				RecipeID: requests.RecipeID(d.Plan.RecipeID),
+				Title:    d.Plan.Title,
+				Day:      time.Weekday(d.Plan.Day),
+			}
+		}
+	}
+
+	return requests.ReconstructList(requests.ListData{
+		OwnerID:   requests.OwnerID(doc.OwnerID),
+		Requests:  reqs,
+		UpdatedAt: doc.UpdatedAt,
+	}), nil
+}
+
+// Save persists a request list
+func (r *RequestRepository) Save(ctx context.Context, l *requests.List) error {
+	doc := requestListDoc{
+		OwnerID:   l.OwnerID().String(),
+		Requests:  make([]requestDoc, len(l.Requests())),
+		UpdatedAt: l.UpdatedAt(),
+	}
+	for i, req := range l.Requests() {
+		doc.Requests[i] = requestDoc{
+			Dish:        req.Dish,
+			Requester:   toMemberDoc(req.Requester),
+			RequestedAt: req.RequestedAt,
+		}
+		if req.Plan != nil {
+			doc.Requests[i].Plan = &planDoc{
+				Cook:     toMemberDoc(req.Plan.Cook),
+				RecipeID: req.Plan.RecipeID.String(),
+				Title:    req.Plan.Title,
+				Day:      int(req.Plan.Day),
+			}
+		}
+	}
+
+	_, err := r.client.Collection("dishRequests").Doc(l.OwnerID().String()).Set(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save dish requests: %w", err)
+	}
+
+	return nil
+}
+
+func toMemberDoc(m requests.Member) memberDoc {
+	return memberDoc{UserID: m.ID.String(), TelegramID: m.TelegramID, Name: m.Name}
+}
+
+func fromMemberDoc(d memberDoc) requests.Member {
+	return requests.Member{ID: requests.UserID(d.UserID), TelegramID: d.TelegramID, Name: d.Name}
+}
@@ -0,0 +1,156 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/shopping"
+)
+
+// ShoppingListRepository implements the shopping.Repository interface using Firestore.
+// The current list of each user is stored in the shoppingLists collection, keyed by user ID.
+type ShoppingListRepository struct {
+	client *firestore.Client
+}
+
+// NewShoppingListRepository creates a new Firebase shopping list repository
+func NewShoppingListRepository(client *firestore.Client) *ShoppingListRepository {
+	return &ShoppingListRepository{
+		client: client,
+	}
+}
+
+// shoppingListDoc represents the Firestore document structure
+type shoppingListDoc struct {
+	UserID    string            `firestore:"userId"`
+	WeekStart time.Time         `firestore:"weekStart"`
+	Items     []shoppingItemDoc `firestore:"items"`
+	CreatedAt time.Time         `firestore:"createdAt"`
+}
+
+type shoppingItemDoc struct {
+	Name    string      `firestore:"name"`
+	Amounts []amountDoc `firestore:"amounts,omitempty"`
+	Extras  []string    `firestore:"extras,omitempty"`
+	Aisle   string      `firestore:"aisle"`
+	Checked bool        `firestore:"checked"`
+}
+
+type amountDoc struct {
+	Value float64 `firestore:"value"`
+	Unit  string  `firestore:"unit"`
+}
+
+// Save persists the current shopping list of a user
+func (r *ShoppingListRepository) Save(ctx context.Context, list *shopping.List) error {
+	_, err := r.client.Collection("shoppingLists").Doc(list.UserID().String()).Set(ctx, toShoppingListDoc(list))
+	if err != nil {
+		return fmt.Errorf("failed to save shopping list: %w", err)
+	}
+
+	return nil
+}
+
+// FindByUserID retrieves the current shopping list of a user
+func (r *ShoppingListRepository) FindByUserID(ctx context.Context, userID shopping.UserID) (*shopping.List, error) {
+	snap, err := r.client.Collection("shoppingLists").Doc(userID.String()).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrShoppingListNotFound
+		}
+		return nil, fmt.Errorf("failed to find shopping list: %w", err)
+	}
+
+	return fromShoppingListSnapshot(snap)
+}
+
+// Modify changes the current shopping list of a user in a transaction
+func (r *ShoppingListRepository) Modify(ctx context.Context, userID shopping.UserID, change func(list *shopping.List) error) (*shopping.List, error) {
+	ref := r.client.Collection("shoppingLists").Doc(userID.String())
+
+	var changeErr error
+	var list *shopping.List
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		list, err = fromShoppingListSnapshot(snap)
+		if err != nil {
+			return err
+		}
+		// A refused change ends the transaction without retrying it
+		if changeErr = change(list); changeErr != nil {
+			return changeErr
+		}
+		return tx.Set(ref, toShoppingListDoc(list))
+	})
+	if changeErr != nil {
+		return nil, changeErr
+	}
+	if status.Code(err) == codes.NotFound {
+		return nil, shared.ErrShoppingListNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify shopping list: %w", err)
+	}
+
+	return list, nil
+}
+
+// toShoppingListDoc converts a shopping list to its stored form
+func toShoppingListDoc(list *shopping.List) shoppingListDoc {
+	doc := shoppingListDoc{
+		UserID:    list.UserID().String(),
+		WeekStart: list.WeekStart(),
+		Items:     make([]shoppingItemDoc, len(list.Items())),
+		CreatedAt: list.CreatedAt(),
+	}
+	for i, item := range list.Items() {
+		itemDoc := shoppingItemDoc{
+			Name:    item.Name,
+			Extras:  item.Extras,
+			Aisle:   item.Aisle.String(),
+			Checked: item.Checked,
+		}
+		for _, a := range item.Amounts {
+			itemDoc.Amounts = append(itemDoc.Amounts, amountDoc{Value: a.Value, Unit: a.Unit})
+		}
+		doc.Items[i] = itemDoc
+	}
+	return doc
+}
+
+// fromShoppingListSnapshot converts a stored shopping list
+func fromShoppingListSnapshot(snap *firestore.DocumentSnapshot) (*shopping.List, error) {
+	var doc shoppingListDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse shopping list document: %w", err)
+	}
+
+	items := make([]shopping.Item, len(doc.Items))
+	for i, itemDoc := range doc.Items {
+		item := shopping.Item{
+			Name:    itemDoc.Name,
+			Extras:  itemDoc.Extras,
+			Aisle:   shopping.ParseAisle(itemDoc.Aisle),
+			Checked: itemDoc.Checked,
+		}
+		for _, a := range itemDoc.Amounts {
+			item.Amounts = append(item.Amounts, shopping.Amount{Value: a.Value, Unit: a.Unit})
+		}
+		items[i] = item
+	}
+
+	return shopping.ReconstructList(shopping.ListData{
+		UserID:    shopping.UserID(doc.UserID),
+		WeekStart: doc.WeekStart,
+		Items:     items,
+		CreatedAt: doc.CreatedAt,
+	}), nil
+}
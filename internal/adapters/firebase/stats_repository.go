@@ -0,0 +1,167 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/stats"
+)
+
+// StatsRepository implements the stats.Repository interface using Firestore.
+// Stats are stored in the cookingStats collection, one document per user.
+type StatsRepository struct {
+	client *firestore.Client
+}
+
+// NewStatsRepository creates a new Firebase cooking stats repository
+func NewStatsRepository(client *firestore.Client) *StatsRepository {
+	return &StatsRepository{
+		client: client,
+	}
+}
+
+// statsDoc represents the Firestore document structure
+type statsDoc struct {
+	UserID        string               `firestore:"userId"`
+	CurrentStreak int                  `firestore:"currentStreak"`
+	LongestStreak int                  `firestore:"longestStreak"`
+	LastCooked    string               `firestore:"lastCooked"`
+	Recipes       []string             `firestore:"recipes"`
+	Cuisines      []string             `firestore:"cuisines"`
+	Earned        map[string]time.Time `firestore:"earned"`
+	Months        map[string]monthDoc  `firestore:"months"`
+	UpdatedAt     time.Time            `firestore:"updatedAt"`
+}
+
+type monthDoc struct {
+	Cooked      int      `firestore:"cooked"`
+	Days        int      `firestore:"days"`
+	NewRecipes  int      `firestore:"newRecipes"`
+	NewCuisines []string `firestore:"newCuisines"`
+	Badges      []string `firestore:"badges"`
+}
+
+// FindByUser retrieves the stats of a user
+func (r *StatsRepository) FindByUser(ctx context.Context, userID stats.UserID) (*stats.Stats, error) {
+	snap, err := r.client.Collection("cookingStats").Doc(userID.String()).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, shared.ErrStatsNotFound
+		}
+		return nil, fmt.Errorf("failed to find cooking stats: %w", err)
+	}
+
+	var doc statsDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse cooking stats document: %w", err)
+	}
+	return fromStatsDoc(&doc), nil
+}
+
+// fromStatsDoc converts a Firestore document to stats
+func fromStatsDoc(doc *statsDoc) *stats.Stats {
+	recipes := make([]stats.RecipeID, len(doc.Recipes))
+	for i, id := range doc.Recipes {
+		recipes[i] = stats.RecipeID(id)
+	}
+	months := make(map[string]stats.Month, len(doc.Months))
+	for key, m := range doc.Months {
+		months[key] = stats.Month{
+			Cooked:      m.Cooked,
+			Days:        m.Days,
+			NewRecipes:  m.NewRecipes,
+			NewCuisines: m.NewCuisines,
+			Badges:      m.Badges,
+		}
+	}
+
+	return stats.ReconstructStats(stats.StatsData{
+		UserID:        stats.UserID(doc.UserID),
+		CurrentStreak: doc.CurrentStreak,
+		LongestStreak: doc.LongestStreak,
+		LastCooked:    doc.LastCooked,
+		Recipes:       recipes,
+		Cuisines:      doc.Cuisines,
+		Earned:        doc.Earned,
+		Months:        months,
+		UpdatedAt:     doc.UpdatedAt,
+	})
+}
+
+// Save persists stats
+func (r *StatsRepository) Save(ctx context.Context, s *stats.Stats) error {
+	_, err := r.client.Collection("cookingStats").Doc(s.UserID().String()).Set(ctx, toStatsDoc(s))
+	if err != nil {
+		return fmt.Errorf("failed to save cooking stats: %w", err)
+	}
+
+	return nil
+}
+
+// Modify reads, changes and saves the stats of a user in a transaction
+func (r *StatsRepository) Modify(ctx context.Context, userID stats.UserID, change func(s *stats.Stats) error) (*stats.Stats, error) {
+	ref := r.client.Collection("cookingStats").Doc(userID.String())
+
+	var s *stats.Stats
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		switch {
+		case status.Code(err) == codes.NotFound:
+			if s, err = stats.NewStats(userID); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			var doc statsDoc
+			if err := snap.DataTo(&doc); err != nil {
+				return fmt.Errorf("failed to parse cooking stats document: %w", err)
+			}
+			s = fromStatsDoc(&doc)
+		}
+
+		if err := change(s); err != nil {
+			return err
+		}
+		return tx.Set(ref, toStatsDoc(s))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify cooking stats: %w", err)
+	}
+	return s, nil
+}
+
+// toStatsDoc converts stats to their Firestore representation
+func toStatsDoc(s *stats.Stats) statsDoc {
+	doc := statsDoc{
+		UserID:        s.UserID().String(),
+		CurrentStreak: s.CurrentStreak(),
+		LongestStreak: s.LongestStreak(),
+		LastCooked:    s.LastCooked(),
+		Cuisines:      s.Cuisines(),
+		Earned:        make(map[string]time.Time),
+		Months:        make(map[string]monthDoc, len(s.Months())),
+		UpdatedAt:     s.UpdatedAt(),
+	}
+	for _, id := range s.Recipes() {
+		doc.Recipes = append(doc.Recipes, id.String())
+	}
+	for _, e := range s.Earned() {
+		doc.Earned[e.Badge.ID] = e.EarnedAt
+	}
+	for key, m := range s.Months() {
+		doc.Months[key] = monthDoc{
+			Cooked:      m.Cooked,
+			Days:        m.Days,
+			NewRecipes:  m.NewRecipes,
+			NewCuisines: m.NewCuisines,
+			Badges:      m.Badges,
+		}
+	}
+	return doc
+}
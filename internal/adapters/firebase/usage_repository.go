@@ -0,0 +1,78 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"receipt-bot/internal/domain/analytics"
+)
+
+// UsageRepository implements the analytics.Repository interface using Firestore.
+// Each week is a document of the usageAnalytics collection, keyed by the date it
+// starts on, with a count per feature that is incremented on the server.
+type UsageRepository struct {
+	client *firestore.Client
+}
+
+// NewUsageRepository creates a new Firebase usage repository
+func NewUsageRepository(client *firestore.Client) *UsageRepository {
+	return &UsageRepository{
+		client: client,
+	}
+}
+
+// usageDoc represents the Firestore document structure of a week of usage
+type usageDoc struct {
+	Start  time.Time      `firestore:"start"`
+	Counts map[string]int `firestore:"counts"`
+}
+
+// Increment counts one use of a feature in the week starting at week
+func (r *UsageRepository) Increment(ctx context.Context, week time.Time, feature analytics.Feature) error {
+	week = week.UTC()
+	_, err := r.client.Collection("usageAnalytics").Doc(week.Format("2006-01-02")).Set(ctx, map[string]interface{}{
+		"start": week,
+		"counts": map[string]interface{}{
+			string(feature): firestore.Increment(1),
+		},
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("failed to count usage: %w", err)
+	}
+	return nil
+}
+
+// FindSince retrieves the weeks starting at or after since, oldest first
+func (r *UsageRepository) FindSince(ctx context.Context, since time.Time) ([]*analytics.Week, error) {
+	iter := r.client.Collection("usageAnalytics").
+		Where("start", ">=", since).
+		OrderBy("start", firestore.Asc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var weeks []*analytics.Week
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate usage: %w", err)
+		}
+
+		var doc usageDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse usage: %w", err)
+		}
+		counts := make(map[analytics.Feature]int, len(doc.Counts))
+		for feature, count := range doc.Counts {
+			counts[analytics.Feature(feature)] = count
+		}
+		weeks = append(weeks, &analytics.Week{Start: doc.Start.UTC(), Counts: counts})
+	}
+
+	return weeks, nil
+}
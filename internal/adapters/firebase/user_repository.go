@@ -2,12 +2,15 @@ package firebase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"receipt-bot/internal/domain/shared"
 	"receipt-bot/internal/domain/user"
 )
@@ -39,27 +42,150 @@ type userDoc struct {
 	PantryItems     []string   `firestore:"pantryItems,omitempty"`
 	PantryUpdatedAt *time.Time `firestore:"pantryUpdatedAt,omitempty"`
 
+	// Linked Telegram accounts
+	LinkedTelegramIDs []int64 `firestore:"linkedTelegramIds,omitempty"`
+
+	// Saved search filters
+	SavedFilters []savedFilterDoc `firestore:"savedFilters,omitempty"`
+
+	// Text shortcuts expanded before intent detection
+	Shortcuts []shortcutDoc `firestore:"shortcuts,omitempty"`
+
+	// How clarifying questions were answered, oldest first
+	ClarificationChoices []clarificationChoiceDoc `firestore:"clarificationChoices,omitempty"`
+
+	// Notification choices, keyed by notification kind
+	Notifications map[string]bool `firestore:"notifications,omitempty"`
+
+	// Pantry staples; CustomStaples tells an emptied list from one never chosen
+	Staples       []string `firestore:"staples,omitempty"`
+	CustomStaples bool     `firestore:"customStaples,omitempty"`
+
+	// Recipe fields exported by default, empty for the default fields
+	ExportFields []string `firestore:"exportFields,omitempty"`
+
+	// Time zone, locale and quiet hours for dates and scheduled notifications
+	Timezone   string         `firestore:"timezone,omitempty"`
+	Locale     string         `firestore:"locale,omitempty"`
+	QuietHours *quietHoursDoc `firestore:"quietHours,omitempty"`
+
+	// Messages without emojis or formatting, for screen readers
+	PlainMode bool `firestore:"plainMode,omitempty"`
+
+	// Diet recipes are checked against
+	Diet string `firestore:"diet,omitempty"`
+
+	// Version signed into the browser extension token, raised when it is reset
+	ClipTokenVersion int `firestore:"clipTokenVersion,omitempty"`
+
+	// Saved recipes leave out their transcripts and captions
+	SkipTranscripts bool `firestore:"skipTranscripts,omitempty"`
+
+	// Auto-export of saved recipes
+	AutoExport *autoExportDoc `firestore:"autoExport,omitempty"`
+
+	// Cloud storage Markdown exports are written to
+	CloudStorage *cloudStorageDoc `firestore:"cloudStorage,omitempty"`
+
+	// End of the paid premium tier
+	PremiumUntil *time.Time `firestore:"premiumUntil,omitempty"`
+
 	// Notion integration
 	NotionAccessToken string     `firestore:"notionAccessToken,omitempty"`
 	NotionWorkspaceID string     `firestore:"notionWorkspaceId,omitempty"`
 	NotionDatabaseID  string     `firestore:"notionDatabaseId,omitempty"`
 	NotionConnectedAt *time.Time `firestore:"notionConnectedAt,omitempty"`
+
+	// Properties of the Notion database recipe fields are exported to, keyed by field
+	NotionProperties map[string]notionPropertyDoc `firestore:"notionProperties,omitempty"`
+}
+
+// savedFilterDoc represents a saved search filter embedded in the user document
+type savedFilterDoc struct {
+	Name        string   `firestore:"name"`
+	Category    string   `firestore:"category,omitempty"`
+	DietaryTags []string `firestore:"dietaryTags,omitempty"`
+	Difficulty  string   `firestore:"difficulty,omitempty"`
+	SearchTerm  string   `firestore:"searchTerm,omitempty"`
+	MaxMinutes  int      `firestore:"maxMinutes,omitempty"`
+	Include     []string `firestore:"include,omitempty"`
+	Exclude     []string `firestore:"exclude,omitempty"`
+	Optional    []string `firestore:"optional,omitempty"`
+}
+
+// shortcutDoc represents a text shortcut
+type shortcutDoc struct {
+	Trigger   string `firestore:"trigger"`
+	Expansion string `firestore:"expansion"`
+}
+
+// clarificationChoiceDoc represents the answer to a clarifying question
+type clarificationChoiceDoc struct {
+	Message string `firestore:"message"`
+	Choice  string `firestore:"choice"`
+	Count   int    `firestore:"count"`
+}
+
+// quietHoursDoc represents quiet hours in minutes after midnight
+type quietHoursDoc struct {
+	Start int `firestore:"start"`
+	End   int `firestore:"end"`
+}
+
+// autoExportDoc represents an auto-export embedded in the user document
+type autoExportDoc struct {
+	Target   string    `firestore:"target"`
+	Schedule string    `firestore:"schedule"`
+	Since    time.Time `firestore:"since"`
+}
+
+// cloudStorageDoc represents a connected cloud storage embedded in the user document
+type cloudStorageDoc struct {
+	Provider     string    `firestore:"provider"`
+	RefreshToken string    `firestore:"refreshToken"`
+	Folder       string    `firestore:"folder"`
+	ConnectedAt  time.Time `firestore:"connectedAt"`
+}
+
+// notionPropertyDoc represents a property of the user's Notion database
+type notionPropertyDoc struct {
+	Name string `firestore:"name"`
+	Type string `firestore:"type"`
 }
 
 // Save persists a user to Firestore
 func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
 	doc := &userDoc{
-		UserID:            u.ID().String(),
-		TelegramID:        u.TelegramID(),
-		Username:          u.Username(),
-		Language:          string(u.Language()),
-		CreatedAt:         u.CreatedAt().Time(),
-		PantryItems:       u.PantryItems(),
-		PantryUpdatedAt:   u.PantryUpdatedAt(),
-		NotionAccessToken: u.NotionAccessToken(),
-		NotionWorkspaceID: u.NotionWorkspaceID(),
-		NotionDatabaseID:  u.NotionDatabaseID(),
-		NotionConnectedAt: u.NotionConnectedAt(),
+		UserID:               u.ID().String(),
+		TelegramID:           u.TelegramID(),
+		Username:             u.Username(),
+		Language:             string(u.Language()),
+		CreatedAt:            u.CreatedAt().Time(),
+		PantryItems:          u.PantryItems(),
+		PantryUpdatedAt:      u.PantryUpdatedAt(),
+		LinkedTelegramIDs:    u.LinkedTelegramIDs(),
+		SavedFilters:         toSavedFilterDocs(u.SavedFilters()),
+		Shortcuts:            toShortcutDocs(u.Shortcuts()),
+		ClarificationChoices: toClarificationChoiceDocs(u.ClarificationChoices()),
+		Notifications:        toNotificationDoc(u.NotificationSettings()),
+		Staples:              u.Staples(),
+		CustomStaples:        u.Staples() != nil,
+		ExportFields:         u.ExportFields(),
+		Timezone:             u.Timezone(),
+		Locale:               storedLocale(u),
+		PlainMode:            u.PlainMode(),
+		Diet:                 u.Diet(),
+		ClipTokenVersion:     u.ClipTokenVersion(),
+		SkipTranscripts:      !u.KeepsTranscripts(),
+		QuietHours:           toQuietHoursDoc(u.QuietHours()),
+		AutoExport:           toAutoExportDoc(u.AutoExport()),
+		CloudStorage:         toCloudStorageDoc(u.CloudStorage()),
+		PremiumUntil:         u.PremiumUntil(),
+		NotionAccessToken:    u.NotionAccessToken(),
+		NotionWorkspaceID:    u.NotionWorkspaceID(),
+		NotionDatabaseID:     u.NotionDatabaseID(),
+		NotionConnectedAt:    u.NotionConnectedAt(),
+		NotionProperties:     toNotionPropertyDocs(u.NotionProperties()),
 	}
 
 	_, err := r.client.Collection("users").Doc(u.ID().String()).Set(ctx, doc)
@@ -85,8 +211,25 @@ func (r *UserRepository) FindByID(ctx context.Context, id user.UserID) (*user.Us
 	return r.fromDocument(&userDoc), nil
 }
 
-// FindByTelegramID retrieves a user by their Telegram ID
+// FindByTelegramID retrieves a user by their Telegram ID.
+// A linked account resolves to the user it is linked to, even if it had a collection of its own before
 func (r *UserRepository) FindByTelegramID(ctx context.Context, telegramID int64) (*user.User, error) {
+	linked, err := r.client.Collection("users").
+		Where("linkedTelegramIds", "array-contains", telegramID).
+		Limit(1).
+		Documents(ctx).
+		Next()
+	if err == nil {
+		var userDoc userDoc
+		if err := linked.DataTo(&userDoc); err != nil {
+			return nil, fmt.Errorf("failed to parse user document: %w", err)
+		}
+		return r.fromDocument(&userDoc), nil
+	}
+	if err != iterator.Done {
+		return nil, fmt.Errorf("failed to find linked user by Telegram ID: %w", err)
+	}
+
 	iter := r.client.Collection("users").
 		Where("telegramId", "==", telegramID).
 		Limit(1).
@@ -118,6 +261,30 @@ func (r *UserRepository) FindByTelegramID(ctx context.Context, telegramID int64)
 	return r.fromDocument(&userDoc), nil
 }
 
+// FindAll retrieves every user
+func (r *UserRepository) FindAll(ctx context.Context) ([]*user.User, error) {
+	iter := r.client.Collection("users").Documents(ctx)
+	defer iter.Stop()
+
+	var users []*user.User
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate users: %w", err)
+		}
+
+		var userDoc userDoc
+		if err := doc.DataTo(&userDoc); err != nil {
+			return nil, fmt.Errorf("failed to parse user document: %w", err)
+		}
+		users = append(users, r.fromDocument(&userDoc))
+	}
+	return users, nil
+}
+
 // Update updates an existing user
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	return r.Save(ctx, u) // In Firestore, Set accomplishes update
@@ -126,17 +293,35 @@ func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 // fromDocument converts a Firestore document to a domain User
 func (r *UserRepository) fromDocument(doc *userDoc) *user.User {
 	return user.ReconstructUserFromData(user.UserData{
-		ID:                user.UserID(doc.UserID),
-		TelegramID:        doc.TelegramID,
-		Username:          doc.Username,
-		Language:          user.Language(doc.Language),
-		CreatedAt:         shared.NewTimestampFromTime(doc.CreatedAt),
-		PantryItems:       doc.PantryItems,
-		PantryUpdatedAt:   doc.PantryUpdatedAt,
-		NotionAccessToken: doc.NotionAccessToken,
-		NotionWorkspaceID: doc.NotionWorkspaceID,
-		NotionDatabaseID:  doc.NotionDatabaseID,
-		NotionConnectedAt: doc.NotionConnectedAt,
+		ID:                   user.UserID(doc.UserID),
+		TelegramID:           doc.TelegramID,
+		Username:             doc.Username,
+		Language:             user.Language(doc.Language),
+		CreatedAt:            shared.NewTimestampFromTime(doc.CreatedAt),
+		PantryItems:          doc.PantryItems,
+		PantryUpdatedAt:      doc.PantryUpdatedAt,
+		LinkedTelegramIDs:    doc.LinkedTelegramIDs,
+		SavedFilters:         fromSavedFilterDocs(doc.SavedFilters),
+		Shortcuts:            fromShortcutDocs(doc.Shortcuts),
+		ClarificationChoices: fromClarificationChoiceDocs(doc.ClarificationChoices),
+		Notifications:        fromNotificationDoc(doc.Notifications),
+		Staples:              fromStaplesDoc(doc.Staples, doc.CustomStaples),
+		ExportFields:         doc.ExportFields,
+		Timezone:             doc.Timezone,
+		Locale:               user.Locale(doc.Locale),
+		PlainMode:            doc.PlainMode,
+		Diet:                 doc.Diet,
+		ClipTokenVersion:     doc.ClipTokenVersion,
+		SkipTranscripts:      doc.SkipTranscripts,
+		QuietHours:           fromQuietHoursDoc(doc.QuietHours),
+		AutoExport:           fromAutoExportDoc(doc.AutoExport),
+		CloudStorage:         fromCloudStorageDoc(doc.CloudStorage),
+		PremiumUntil:         doc.PremiumUntil,
+		NotionAccessToken:    doc.NotionAccessToken,
+		NotionWorkspaceID:    doc.NotionWorkspaceID,
+		NotionDatabaseID:     doc.NotionDatabaseID,
+		NotionConnectedAt:    doc.NotionConnectedAt,
+		NotionProperties:     fromNotionPropertyDocs(doc.NotionProperties),
 	})
 }
 
@@ -164,6 +349,104 @@ func (r *UserRepository) UpdatePantry(ctx context.Context, userID user.UserID, i
 	return nil
 }
 
+// ModifyPantry reads and replaces the pantry items for a user in a transaction,
+// which Firestore retries when another change lands in between
+func (r *UserRepository) ModifyPantry(ctx context.Context, userID user.UserID, change func(items []string) []string) ([]string, error) {
+	ref := r.client.Collection("users").Doc(userID.String())
+
+	var items []string
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		var doc userDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return fmt.Errorf("failed to parse user document: %w", err)
+		}
+
+		items = change(append([]string(nil), doc.PantryItems...))
+		return tx.Update(ref, []firestore.Update{
+			{Path: "pantryItems", Value: items},
+			{Path: "pantryUpdatedAt", Value: time.Now()},
+		})
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, shared.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify pantry: %w", err)
+	}
+	return items, nil
+}
+
+// ModifyLinkedTelegramIDs applies a change to a user in a transaction and
+// updates only their linked Telegram accounts
+func (r *UserRepository) ModifyLinkedTelegramIDs(ctx context.Context, userID user.UserID, change func(u *user.User) error) (*user.User, error) {
+	u, err := r.modifyUser(ctx, userID, change, func(u *user.User) []firestore.Update {
+		return []firestore.Update{{Path: "linkedTelegramIds", Value: u.LinkedTelegramIDs()}}
+	})
+	if err != nil && !errors.Is(err, shared.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to modify linked accounts: %w", err)
+	}
+	return u, err
+}
+
+// ModifyShortcuts applies a change to a user in a transaction and updates only
+// their text shortcuts
+func (r *UserRepository) ModifyShortcuts(ctx context.Context, userID user.UserID, change func(u *user.User) error) (*user.User, error) {
+	u, err := r.modifyUser(ctx, userID, change, func(u *user.User) []firestore.Update {
+		return []firestore.Update{{Path: "shortcuts", Value: toShortcutDocs(u.Shortcuts())}}
+	})
+	if err != nil && !errors.Is(err, shared.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to modify shortcuts: %w", err)
+	}
+	return u, err
+}
+
+// ModifyClarificationChoices applies a change to a user in a transaction and
+// updates only how they answered clarifying questions
+func (r *UserRepository) ModifyClarificationChoices(ctx context.Context, userID user.UserID, change func(u *user.User) error) (*user.User, error) {
+	u, err := r.modifyUser(ctx, userID, change, func(u *user.User) []firestore.Update {
+		return []firestore.Update{{Path: "clarificationChoices", Value: toClarificationChoiceDocs(u.ClarificationChoices())}}
+	})
+	if err != nil && !errors.Is(err, shared.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to modify clarification choices: %w", err)
+	}
+	return u, err
+}
+
+// modifyUser reads a user in a transaction, applies change and writes the fields
+// it returns for the changed user
+func (r *UserRepository) modifyUser(ctx context.Context, userID user.UserID, change func(u *user.User) error, fields func(u *user.User) []firestore.Update) (*user.User, error) {
+	ref := r.client.Collection("users").Doc(userID.String())
+
+	var u *user.User
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		var doc userDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return fmt.Errorf("failed to parse user document: %w", err)
+		}
+
+		u = r.fromDocument(&doc)
+		if err := change(u); err != nil {
+			return err
+		}
+		return tx.Update(ref, fields(u))
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, shared.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
 // GetPantry retrieves the pantry items for a user
 func (r *UserRepository) GetPantry(ctx context.Context, userID user.UserID) ([]string, error) {
 	doc, err := r.client.Collection("users").Doc(userID.String()).Get(ctx)
@@ -179,6 +462,439 @@ func (r *UserRepository) GetPantry(ctx context.Context, userID user.UserID) ([]s
 	return userDoc.PantryItems, nil
 }
 
+// UpdateSavedFilters replaces the saved filters for a user
+func (r *UserRepository) UpdateSavedFilters(ctx context.Context, userID user.UserID, filters []user.SavedFilter) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "savedFilters", Value: toSavedFilterDocs(filters)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update saved filters: %w", err)
+	}
+	return nil
+}
+
+// UpdateNotificationSettings replaces the notification choices for a user
+func (r *UserRepository) UpdateNotificationSettings(ctx context.Context, userID user.UserID, settings map[user.Notification]bool) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "notifications", Value: toNotificationDoc(settings)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update notification settings: %w", err)
+	}
+	return nil
+}
+
+// ModifyStaples reads and replaces the pantry staples for a user in a transaction
+func (r *UserRepository) ModifyStaples(ctx context.Context, userID user.UserID, change func(staples []string) []string) ([]string, error) {
+	ref := r.client.Collection("users").Doc(userID.String())
+
+	var staples []string
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		var doc userDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return fmt.Errorf("failed to parse user document: %w", err)
+		}
+
+		staples = change(fromStaplesDoc(doc.Staples, doc.CustomStaples))
+		return tx.Update(ref, []firestore.Update{
+			{Path: "staples", Value: staples},
+			{Path: "customStaples", Value: staples != nil},
+		})
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, shared.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify staples: %w", err)
+	}
+	return staples, nil
+}
+
+// UpdateExportFields replaces the default export fields for a user
+func (r *UserRepository) UpdateExportFields(ctx context.Context, userID user.UserID, fields []string) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "exportFields", Value: fields},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update export fields: %w", err)
+	}
+	return nil
+}
+
+// UpdateQuietHours replaces the quiet hours for a user
+func (r *UserRepository) UpdateQuietHours(ctx context.Context, userID user.UserID, quiet *user.QuietHours) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "quietHours", Value: toQuietHoursDoc(quiet)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update quiet hours: %w", err)
+	}
+	return nil
+}
+
+// UpdateTimezone sets the time zone for a user
+func (r *UserRepository) UpdateTimezone(ctx context.Context, userID user.UserID, timezone string) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "timezone", Value: timezone},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update timezone: %w", err)
+	}
+	return nil
+}
+
+// UpdateLocale sets the locale for a user
+func (r *UserRepository) UpdateLocale(ctx context.Context, userID user.UserID, locale user.Locale) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "locale", Value: string(locale)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update locale: %w", err)
+	}
+	return nil
+}
+
+// UpdatePlainMode turns plain mode on or off for a user
+func (r *UserRepository) UpdatePlainMode(ctx context.Context, userID user.UserID, on bool) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "plainMode", Value: on},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update plain mode: %w", err)
+	}
+	return nil
+}
+
+// UpdateDiet sets the diet a user's recipes are checked against
+func (r *UserRepository) UpdateDiet(ctx context.Context, userID user.UserID, diet string) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "diet", Value: diet},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update diet: %w", err)
+	}
+	return nil
+}
+
+// UpdateClipTokenVersion sets the version of a user's browser extension token
+func (r *UserRepository) UpdateClipTokenVersion(ctx context.Context, userID user.UserID, version int) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "clipTokenVersion", Value: version},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update clip token version: %w", err)
+	}
+	return nil
+}
+
+// UpdateKeepTranscripts sets whether a user's recipes keep their transcripts and captions
+func (r *UserRepository) UpdateKeepTranscripts(ctx context.Context, userID user.UserID, keep bool) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "skipTranscripts", Value: !keep},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update transcript setting: %w", err)
+	}
+	return nil
+}
+
+// storedLocale returns the locale the user chose, empty when they go by their language's default
+func storedLocale(u *user.User) string {
+	if !u.HasLocale() {
+		return ""
+	}
+	return string(u.Locale())
+}
+
+// UpdateAutoExport replaces the auto-export for a user
+func (r *UserRepository) UpdateAutoExport(ctx context.Context, userID user.UserID, autoExport *user.AutoExport) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "autoExport", Value: toAutoExportDoc(autoExport)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update auto-export: %w", err)
+	}
+	return nil
+}
+
+// toAutoExportDoc converts an auto-export to its stored form
+func toAutoExportDoc(autoExport *user.AutoExport) *autoExportDoc {
+	if autoExport == nil {
+		return nil
+	}
+	return &autoExportDoc{
+		Target:   string(autoExport.Target),
+		Schedule: string(autoExport.Schedule),
+		Since:    autoExport.Since,
+	}
+}
+
+// fromAutoExportDoc converts a stored auto-export
+func fromAutoExportDoc(doc *autoExportDoc) *user.AutoExport {
+	if doc == nil {
+		return nil
+	}
+	return &user.AutoExport{
+		Target:   user.AutoExportTarget(doc.Target),
+		Schedule: user.AutoExportSchedule(doc.Schedule),
+		Since:    doc.Since,
+	}
+}
+
+// UpdateCloudStorage replaces the connected cloud storage for a user
+func (r *UserRepository) UpdateCloudStorage(ctx context.Context, userID user.UserID, storage *user.CloudStorage) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "cloudStorage", Value: toCloudStorageDoc(storage)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update cloud storage: %w", err)
+	}
+	return nil
+}
+
+// paymentDoc is a premium payment as stored in Firestore, keyed by its charge ID
+type paymentDoc struct {
+	UserID       string    `firestore:"userId"`
+	PlanID       string    `firestore:"planId"`
+	Days         int       `firestore:"days"`
+	Amount       int       `firestore:"amount"`
+	Currency     string    `firestore:"currency"`
+	PaidAt       time.Time `firestore:"paidAt"`
+	PremiumUntil time.Time `firestore:"premiumUntil"`
+}
+
+// RecordPayment extends a user's premium tier and saves the payment in a
+// transaction that reads the payment first, so a charge is applied once
+func (r *UserRepository) RecordPayment(ctx context.Context, payment user.Payment) (*user.Payment, error) {
+	paymentRef := r.client.Collection("payments").Doc(payment.ChargeID)
+	userRef := r.client.Collection("users").Doc(payment.UserID.String())
+
+	var saved user.Payment
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(paymentRef)
+		if err == nil {
+			var doc paymentDoc
+			if err := snap.DataTo(&doc); err != nil {
+				return fmt.Errorf("failed to parse payment document: %w", err)
+			}
+			saved = fromPaymentDoc(payment.ChargeID, doc)
+			return shared.ErrPaymentRecorded
+		}
+		if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		snap, err = tx.Get(userRef)
+		if status.Code(err) == codes.NotFound {
+			return shared.ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var doc userDoc
+		if err := snap.DataTo(&doc); err != nil {
+			return fmt.Errorf("failed to parse user document: %w", err)
+		}
+
+		saved = payment
+		saved.PremiumUntil = r.fromDocument(&doc).ExtendPremium(payment.Days, payment.PaidAt)
+		if err := tx.Update(userRef, []firestore.Update{
+			{Path: "premiumUntil", Value: saved.PremiumUntil},
+		}); err != nil {
+			return err
+		}
+		return tx.Create(paymentRef, paymentDoc{
+			UserID:       saved.UserID.String(),
+			PlanID:       saved.PlanID,
+			Days:         saved.Days,
+			Amount:       saved.Amount,
+			Currency:     saved.Currency,
+			PaidAt:       saved.PaidAt,
+			PremiumUntil: saved.PremiumUntil,
+		})
+	})
+	switch {
+	case errors.Is(err, shared.ErrPaymentRecorded):
+		return &saved, err
+	case errors.Is(err, shared.ErrUserNotFound):
+		return nil, err
+	case err != nil:
+		return nil, fmt.Errorf("failed to record payment: %w", err)
+	}
+	return &saved, nil
+}
+
+// fromPaymentDoc converts a stored payment to a domain payment
+func fromPaymentDoc(chargeID string, doc paymentDoc) user.Payment {
+	return user.Payment{
+		ChargeID:     chargeID,
+		UserID:       user.UserID(doc.UserID),
+		PlanID:       doc.PlanID,
+		Days:         doc.Days,
+		Amount:       doc.Amount,
+		Currency:     doc.Currency,
+		PaidAt:       doc.PaidAt,
+		PremiumUntil: doc.PremiumUntil,
+	}
+}
+
+// UpdatePremium sets when the user's premium tier ends; nil removes it
+func (r *UserRepository) UpdatePremium(ctx context.Context, userID user.UserID, until *time.Time) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "premiumUntil", Value: until},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update premium: %w", err)
+	}
+	return nil
+}
+
+// toCloudStorageDoc converts a connected cloud storage to its stored form
+func toCloudStorageDoc(storage *user.CloudStorage) *cloudStorageDoc {
+	if storage == nil {
+		return nil
+	}
+	return &cloudStorageDoc{
+		Provider:     string(storage.Provider),
+		RefreshToken: storage.RefreshToken,
+		Folder:       storage.Folder,
+		ConnectedAt:  storage.ConnectedAt,
+	}
+}
+
+// fromCloudStorageDoc converts a stored cloud storage
+func fromCloudStorageDoc(doc *cloudStorageDoc) *user.CloudStorage {
+	if doc == nil {
+		return nil
+	}
+	return &user.CloudStorage{
+		Provider:     user.CloudProvider(doc.Provider),
+		RefreshToken: doc.RefreshToken,
+		Folder:       doc.Folder,
+		ConnectedAt:  doc.ConnectedAt,
+	}
+}
+
+// toQuietHoursDoc converts quiet hours to their stored form
+func toQuietHoursDoc(quiet *user.QuietHours) *quietHoursDoc {
+	if quiet == nil {
+		return nil
+	}
+	return &quietHoursDoc{Start: quiet.Start, End: quiet.End}
+}
+
+// fromQuietHoursDoc converts stored quiet hours
+func fromQuietHoursDoc(doc *quietHoursDoc) *user.QuietHours {
+	if doc == nil {
+		return nil
+	}
+	return &user.QuietHours{Start: doc.Start, End: doc.End}
+}
+
+// fromStaplesDoc converts stored staples, keeping a chosen empty list apart from the defaults
+func fromStaplesDoc(staples []string, custom bool) []string {
+	if !custom {
+		return nil
+	}
+	if staples == nil {
+		return []string{}
+	}
+	return staples
+}
+
+// toNotificationDoc converts notification choices to their Firestore representation
+func toNotificationDoc(settings map[user.Notification]bool) map[string]bool {
+	if len(settings) == 0 {
+		return nil
+	}
+	doc := make(map[string]bool, len(settings))
+	for n, enabled := range settings {
+		doc[string(n)] = enabled
+	}
+	return doc
+}
+
+// fromNotificationDoc converts Firestore notification choices, skipping unknown kinds
+func fromNotificationDoc(doc map[string]bool) map[user.Notification]bool {
+	if len(doc) == 0 {
+		return nil
+	}
+	settings := make(map[user.Notification]bool, len(doc))
+	for kind, enabled := range doc {
+		if n := user.Notification(kind); n.IsValid() {
+			settings[n] = enabled
+		}
+	}
+	return settings
+}
+
+// toSavedFilterDocs converts saved filters to their Firestore representation
+func toSavedFilterDocs(filters []user.SavedFilter) []savedFilterDoc {
+	docs := make([]savedFilterDoc, len(filters))
+	for i, f := range filters {
+		docs[i] = savedFilterDoc(f)
+	}
+	return docs
+}
+
+// fromSavedFilterDocs converts Firestore saved filters to domain saved filters
+func fromSavedFilterDocs(docs []savedFilterDoc) []user.SavedFilter {
+	if len(docs) == 0 {
+		return nil
+	}
+	filters := make([]user.SavedFilter, len(docs))
+	for i, d := range docs {
+		filters[i] = user.SavedFilter(d)
+	}
+	return filters
+}
+
+// toShortcutDocs converts text shortcuts to their Firestore representation
+func toShortcutDocs(shortcuts []user.Shortcut) []shortcutDoc {
+	docs := make([]shortcutDoc, len(shortcuts))
+	for i, s := range shortcuts {
+		docs[i] = shortcutDoc(s)
+	}
+	return docs
+}
+
+// fromShortcutDocs converts Firestore shortcuts to domain shortcuts
+func fromShortcutDocs(docs []shortcutDoc) []user.Shortcut {
+	if len(docs) == 0 {
+		return nil
+	}
+	shortcuts := make([]user.Shortcut, len(docs))
+	for i, d := range docs {
+		shortcuts[i] = user.Shortcut(d)
+	}
+	return shortcuts
+}
+
+// toClarificationChoiceDocs converts answers to clarifying questions to their Firestore representation
+func toClarificationChoiceDocs(choices []user.ClarificationChoice) []clarificationChoiceDoc {
+	docs := make([]clarificationChoiceDoc, len(choices))
+	for i, c := range choices {
+		docs[i] = clarificationChoiceDoc(c)
+	}
+	return docs
+}
+
+// fromClarificationChoiceDocs converts Firestore answers to clarifying questions to domain ones
+func fromClarificationChoiceDocs(docs []clarificationChoiceDoc) []user.ClarificationChoice {
+	if len(docs) == 0 {
+		return nil
+	}
+	choices := make([]user.ClarificationChoice, len(docs))
+	for i, d := range docs {
+		choices[i] = user.ClarificationChoice(d)
+	}
+	return choices
+}
+
 // UpdateNotionConnection updates the Notion connection for a user
 func (r *UserRepository) UpdateNotionConnection(ctx context.Context, userID user.UserID, accessToken, workspaceID, databaseID string) error {
 	now := time.Now()
@@ -201,6 +917,7 @@ func (r *UserRepository) ClearNotionConnection(ctx context.Context, userID user.
 		{Path: "notionWorkspaceId", Value: ""},
 		{Path: "notionDatabaseId", Value: ""},
 		{Path: "notionConnectedAt", Value: nil},
+		{Path: "notionProperties", Value: nil},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to clear Notion connection: %w", err)
@@ -208,6 +925,41 @@ func (r *UserRepository) ClearNotionConnection(ctx context.Context, userID user.
 	return nil
 }
 
+// UpdateNotionProperties replaces how recipe fields map to the user's Notion database properties
+func (r *UserRepository) UpdateNotionProperties(ctx context.Context, userID user.UserID, properties user.NotionProperties) error {
+	_, err := r.client.Collection("users").Doc(userID.String()).Update(ctx, []firestore.Update{
+		{Path: "notionProperties", Value: toNotionPropertyDocs(properties)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update Notion properties: %w", err)
+	}
+	return nil
+}
+
+// toNotionPropertyDocs converts a Notion property mapping for storage, keyed by field
+func toNotionPropertyDocs(properties user.NotionProperties) map[string]notionPropertyDoc {
+	if len(properties) == 0 {
+		return nil
+	}
+	docs := make(map[string]notionPropertyDoc, len(properties))
+	for field, property := range properties {
+		docs[string(field)] = notionPropertyDoc{Name: property.Name, Type: property.Type}
+	}
+	return docs
+}
+
+// fromNotionPropertyDocs converts a stored Notion property mapping back
+func fromNotionPropertyDocs(docs map[string]notionPropertyDoc) user.NotionProperties {
+	if len(docs) == 0 {
+		return nil
+	}
+	properties := make(user.NotionProperties, len(docs))
+	for field, doc := range docs {
+		properties[user.NotionField(field)] = user.NotionProperty{Name: doc.Name, Type: doc.Type}
+	}
+	return properties
+}
+
 // GetNotionConnection retrieves Notion connection details for a user
 func (r *UserRepository) GetNotionConnection(ctx context.Context, userID user.UserID) (accessToken, workspaceID, databaseID string, connectedAt *time.Time, err error) {
 	doc, err := r.client.Collection("users").Doc(userID.String()).Get(ctx)
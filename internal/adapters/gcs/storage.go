@@ -0,0 +1,92 @@
+// Package gcs stores large content in a Google Cloud Storage bucket
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"receipt-bot/internal/domain/shared"
+)
+
+// Storage implements the ports.BlobStorage interface with a GCS bucket
+type Storage struct {
+	client *storage.Client
+	bucket *storage.BucketHandle
+}
+
+// NewStorage creates a new GCS blob storage for a bucket, with the application
+// default credentials. Signing URLs needs a service account that can sign blobs.
+func NewStorage(ctx context.Context, bucket string) (*Storage, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+	return &Storage{
+		client: client,
+		bucket: client.Bucket(bucket),
+	}, nil
+}
+
+// Put stores data under a key
+func (s *Storage) Put(ctx context.Context, key, contentType string, data []byte) error {
+	w := s.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get returns the data stored under a key
+func (s *Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	r, err := s.bucket.Object(key).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, shared.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// Delete removes the data stored under a key
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	err := s.bucket.Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// SignedURL returns a V4 signed GET URL for a key
+func (s *Storage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	signed, err := s.bucket.SignedURL(key, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  http.MethodGet,
+		Expires: time.Now().Add(expiry),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign URL for %s: %w", key, err)
+	}
+	return signed, nil
+}
+
+// Close closes the GCS client
+func (s *Storage) Close() error {
+	return s.client.Close()
+}
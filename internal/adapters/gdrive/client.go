@@ -0,0 +1,265 @@
+// Package gdrive writes exported recipes into a folder of a user's Google Drive
+package gdrive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	googleAuthURL   = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL  = "https://oauth2.googleapis.com/token"
+	driveFilesURL   = "https://www.googleapis.com/drive/v3/files"
+	driveUploadURL  = "https://www.googleapis.com/upload/drive/v3/files"
+	driveFolderType = "application/vnd.google-apps.folder"
+
+	// driveScope only reaches the files and folders the bot creates
+	driveScope = "https://www.googleapis.com/auth/drive.file"
+)
+
+// Config holds Google Drive OAuth configuration
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+}
+
+// Client is the Google Drive API client
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewClient creates a new Google Drive API client
+func NewClient(config Config) *Client {
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// tokenResponse represents the OAuth token response
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// file represents a Drive file or folder
+type file struct {
+	ID string `json:"id"`
+}
+
+// AuthURL generates the OAuth authorization URL, asking for a refresh token
+func (c *Client) AuthURL(state string) string {
+	params := url.Values{}
+	params.Set("client_id", c.config.ClientID)
+	params.Set("redirect_uri", c.config.RedirectURI)
+	params.Set("response_type", "code")
+	params.Set("scope", driveScope)
+	params.Set("access_type", "offline")
+	params.Set("prompt", "consent")
+	params.Set("state", state)
+
+	return fmt.Sprintf("%s?%s", googleAuthURL, params.Encode())
+}
+
+// ExchangeCode exchanges an authorization code for a refresh token
+func (c *Client) ExchangeCode(ctx context.Context, code string) (string, error) {
+	token, err := c.token(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.config.RedirectURI},
+	})
+	if err != nil {
+		return "", err
+	}
+	if token.RefreshToken == "" {
+		return "", fmt.Errorf("token exchange returned no refresh token")
+	}
+	return token.RefreshToken, nil
+}
+
+// Upload writes a file into a folder, creating the folders of its path and
+// replacing a file of the same name
+func (c *Client) Upload(ctx context.Context, refreshToken, folder, filename string, data []byte) error {
+	token, err := c.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return err
+	}
+	accessToken := token.AccessToken
+
+	parentID := "root"
+	for _, name := range strings.Split(folder, "/") {
+		if name == "" {
+			continue
+		}
+		parentID, err = c.folder(ctx, accessToken, parentID, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	existing, err := c.find(ctx, accessToken, parentID, filename, "")
+	if err != nil {
+		return err
+	}
+	fileID := ""
+	if existing != nil {
+		fileID = existing.ID
+	} else {
+		created, err := c.create(ctx, accessToken, parentID, filename, "text/markdown")
+		if err != nil {
+			return err
+		}
+		fileID = created.ID
+	}
+
+	uploadURL := fmt.Sprintf("%s/%s?uploadType=media", driveUploadURL, url.PathEscape(fileID))
+	resp, err := c.do(ctx, accessToken, "PATCH", uploadURL, "text/markdown", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// folder returns the ID of the folder with a name inside a parent, creating it if missing
+func (c *Client) folder(ctx context.Context, accessToken, parentID, name string) (string, error) {
+	existing, err := c.find(ctx, accessToken, parentID, name, driveFolderType)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		return existing.ID, nil
+	}
+	created, err := c.create(ctx, accessToken, parentID, name, driveFolderType)
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// find returns the file with a name inside a parent, nil when there is none.
+// An empty mimeType matches any file that is not a folder.
+func (c *Client) find(ctx context.Context, accessToken, parentID, name, mimeType string) (*file, error) {
+	query := fmt.Sprintf("name = '%s' and '%s' in parents and trashed = false", quote(name), quote(parentID))
+	if mimeType != "" {
+		query += fmt.Sprintf(" and mimeType = '%s'", mimeType)
+	} else {
+		query += fmt.Sprintf(" and mimeType != '%s'", driveFolderType)
+	}
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("fields", "files(id)")
+	params.Set("pageSize", "1")
+
+	resp, err := c.do(ctx, accessToken, "GET", driveFilesURL+"?"+params.Encode(), "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search files: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Files []file `json:"files"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Files) == 0 {
+		return nil, nil
+	}
+	return &result.Files[0], nil
+}
+
+// create creates an empty file or folder inside a parent
+func (c *Client) create(ctx context.Context, accessToken, parentID, name, mimeType string) (*file, error) {
+	body, err := json.Marshal(map[string]any{
+		"name":     name,
+		"mimeType": mimeType,
+		"parents":  []string{parentID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.do(ctx, accessToken, "POST", driveFilesURL+"?fields=id", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %q: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	var created file
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// do sends an authorized request to the Drive API, failing on error statuses
+func (c *Client) do(ctx context.Context, accessToken, method, target, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("drive API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return resp, nil
+}
+
+// token requests an access token from the token endpoint
+func (c *Client) token(ctx context.Context, form url.Values) (*tokenResponse, error) {
+	form.Set("client_id", c.config.ClientID)
+	form.Set("client_secret", c.config.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", googleTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("token request failed: %s", string(body))
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &token, nil
+}
+
+// quote escapes a value for a string literal in a Drive search query
+func quote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
@@ -0,0 +1,118 @@
+// Package googletts speaks text with the Google Cloud Text-to-Speech API.
+package googletts
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/ports"
+)
+
+const defaultBaseURL = "https://texttospeech.googleapis.com"
+
+// voiceLanguages maps the bot's languages to the voices' language codes
+var voiceLanguages = map[string]string{
+	"en":    "en-US",
+	"pt-BR": "pt-BR",
+}
+
+// Config holds Text-to-Speech client configuration
+type Config struct {
+	APIKey  string
+	BaseURL string // optional, defaults to the public API
+}
+
+// Client implements the ports.SpeechSynthesizer interface using Google Cloud Text-to-Speech
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Text-to-Speech client
+func NewClient(config Config) *Client {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &Client{
+		apiKey:  config.APIKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// synthesizeRequest is the body of the text:synthesize endpoint
+type synthesizeRequest struct {
+	Input struct {
+		Text string `json:"text"`
+	} `json:"input"`
+	Voice struct {
+		LanguageCode string `json:"languageCode"`
+	} `json:"voice"`
+	AudioConfig struct {
+		AudioEncoding string `json:"audioEncoding"`
+	} `json:"audioConfig"`
+}
+
+// Synthesize implements the SpeechSynthesizer interface
+func (c *Client) Synthesize(ctx context.Context, text string, language string) ([]byte, error) {
+	if len(text) > ports.MaxSpeechText {
+		return nil, fmt.Errorf("text of %d bytes is longer than %d", len(text), ports.MaxSpeechText)
+	}
+
+	var body synthesizeRequest
+	body.Input.Text = text
+	body.Voice.LanguageCode = voiceLanguages[language]
+	if body.Voice.LanguageCode == "" {
+		body.Voice.LanguageCode = voiceLanguages["en"]
+	}
+	body.AudioConfig.AudioEncoding = "OGG_OPUS"
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := c.baseURL + "/v1/text:synthesize?key=" + url.QueryEscape(c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to synthesize speech: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("speech synthesis failed: %s", string(respBody))
+	}
+
+	var result struct {
+		AudioContent string `json:"audioContent"` // base64
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	audio, err := base64.StdEncoding.DecodeString(result.AudioContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audio: %w", err)
+	}
+	return audio, nil
+}
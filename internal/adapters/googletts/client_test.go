@@ -0,0 +1,40 @@
+package googletts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-bot/internal/ports"
+)
+
+func TestClient_Synthesize(t *testing.T) {
+	var got synthesizeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/text:synthesize" || r.URL.Query().Get("key") != "test-key" {
+			t.Errorf("request to %s", r.URL)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"audioContent":"T2dnUw=="}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{APIKey: "test-key", BaseURL: server.URL})
+	audio, err := client.Synthesize(context.Background(), "Passo 1. Misture.", "pt-BR")
+	if err != nil {
+		t.Fatalf("Synthesize() error = %v", err)
+	}
+	if string(audio) != "OggS" {
+		t.Errorf("Synthesize() = %q, want the decoded audio", audio)
+	}
+	if got.Input.Text != "Passo 1. Misture." || got.Voice.LanguageCode != "pt-BR" || got.AudioConfig.AudioEncoding != "OGG_OPUS" {
+		t.Errorf("request = %+v, want the Portuguese text as OGG/Opus", got)
+	}
+
+	if _, err := client.Synthesize(context.Background(), strings.Repeat("a", ports.MaxSpeechText+1), "en"); err == nil {
+		t.Error("Synthesize() of a text over MaxSpeechText succeeded, want an error")
+	}
+}
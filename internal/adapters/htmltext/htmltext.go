@@ -0,0 +1,105 @@
+// Package htmltext reads the text a recipe pipeline needs out of HTML pages and
+// emails, without a full HTML parser
+package htmltext
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Structured recipe data is kept: it is the most reliable part of a recipe page
+	jsonLDPattern  = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']application/ld\+json["'][^>]*>(.*?)</script>`)
+	hiddenPattern  = regexp.MustCompile(`(?is)<(script|style|noscript|svg|template)\b.*?</(script|style|noscript|svg|template)>`)
+	blockPattern   = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/h[1-6]|/tr|/section|/article)\b[^>]*>`)
+	tagPattern     = regexp.MustCompile(`(?s)<[^>]*>`)
+	titlePattern   = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	authorPattern  = regexp.MustCompile(`(?is)<meta\s[^>]*name\s*=\s*["']author["'][^>]*content\s*=\s*["']([^"']*)["']`)
+	canonPattern   = regexp.MustCompile(`(?is)<link\s[^>]*rel\s*=\s*["']canonical["'][^>]*href\s*=\s*["']([^"']*)["']`)
+	ogURLPattern   = regexp.MustCompile(`(?is)<meta\s[^>]*property\s*=\s*["']og:url["'][^>]*content\s*=\s*["']([^"']*)["']`)
+	hrefPattern    = regexp.MustCompile(`(?is)<a\s[^>]*?href\s*=\s*["']([^"']*)["']`)
+	urlPattern     = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+	spacesPattern  = regexp.MustCompile(`[ \t\f\r\x{00a0}]+`)
+	newlinePattern = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// Text returns the visible text of a page, one block per line, preceded by its
+// structured data (JSON-LD) if it has any
+func Text(page string) string {
+	var structured []string
+	for _, m := range jsonLDPattern.FindAllStringSubmatch(page, -1) {
+		structured = append(structured, strings.TrimSpace(m[1]))
+	}
+
+	text := hiddenPattern.ReplaceAllString(page, " ")
+	text = blockPattern.ReplaceAllString(text, "\n")
+	text = Clean(tagPattern.ReplaceAllString(text, " "))
+	if len(structured) > 0 {
+		text = strings.Join(structured, "\n") + "\n\n" + text
+	}
+	return text
+}
+
+// Title returns the title of a page, empty if it has none
+func Title(page string) string {
+	if m := titlePattern.FindStringSubmatch(page); m != nil {
+		return Clean(m[1])
+	}
+	return ""
+}
+
+// Author returns the author a page declares in its meta tags, empty if none
+func Author(page string) string {
+	if m := authorPattern.FindStringSubmatch(page); m != nil {
+		return Clean(m[1])
+	}
+	return ""
+}
+
+// CanonicalURL returns the link a page declares as its own, which tracking and
+// short links resolve to, empty if none
+func CanonicalURL(page string) string {
+	for _, pattern := range []*regexp.Regexp{canonPattern, ogURLPattern} {
+		if m := pattern.FindStringSubmatch(page); m != nil {
+			return html.UnescapeString(strings.TrimSpace(m[1]))
+		}
+	}
+	return ""
+}
+
+// Links returns the http(s) links of a page or plain text, in order and without
+// duplicates: the targets of its anchors, then the URLs written out in its text
+func Links(content string) []string {
+	var candidates []string
+	for _, m := range hrefPattern.FindAllStringSubmatch(content, -1) {
+		candidates = append(candidates, html.UnescapeString(strings.TrimSpace(m[1])))
+	}
+	candidates = append(candidates, urlPattern.FindAllString(html.UnescapeString(content), -1)...)
+
+	seen := make(map[string]bool, len(candidates))
+	var links []string
+	for _, link := range candidates {
+		link = strings.TrimRight(link, ".,;:!?")
+		lower := strings.ToLower(link)
+		if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+			continue
+		}
+		if seen[link] {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+	}
+	return links
+}
+
+// Clean decodes entities and collapses the whitespace left by markup
+func Clean(s string) string {
+	s = spacesPattern.ReplaceAllString(html.UnescapeString(s), " ")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(newlinePattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,43 @@
+package htmltext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	page := `<html><head><title>Bolo</title><style>p { color: red }</style></head>
+<body><h1>Bolo de cenoura</h1><ul><li>3 cenouras</li><li>2&nbsp;ovos</li></ul>
+<script>track()</script><p>Asse por   40 minutos.</p></body></html>`
+
+	want := "Bolo\nBolo de cenoura\n3 cenouras\n2 ovos\n\nAsse por 40 minutos."
+	if got := Text(page); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	content := `<a href="https://example.com/a?x=1&amp;y=2">A</a> <a href="mailto:me@example.com">mail</a>
+Also see https://example.com/b. And again: https://example.com/a?x=1&y=2`
+
+	want := []string{"https://example.com/a?x=1&y=2", "https://example.com/b"}
+	if got := Links(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("Links() = %v, want %v", got, want)
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		page string
+		want string
+	}{
+		{`<head><link rel="canonical" href="https://example.com/bolo?a=1&amp;b=2"></head>`, "https://example.com/bolo?a=1&b=2"},
+		{`<head><meta property="og:url" content="https://example.com/bolo"></head>`, "https://example.com/bolo"},
+		{`<head><title>Bolo</title></head>`, ""},
+	}
+	for _, tt := range tests {
+		if got := CanonicalURL(tt.page); got != tt.want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", tt.page, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package inboundmail receives the emails users forward to their personal address,
+// posted by the mail provider's inbound webhook (Mailgun routes), and saves the
+// recipes they hold.
+package inboundmail
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/adapters/htmltext"
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+const (
+	// maxRequestBytes caps a webhook request; attachments are not used
+	maxRequestBytes = 10 << 20
+
+	// maxSignatureAge rejects replayed webhook requests
+	maxSignatureAge = 15 * time.Minute
+
+	// processTimeout bounds the processing of one email
+	processTimeout = 10 * time.Minute
+)
+
+var errBadSignature = errors.New("webhook signature does not match")
+
+// Config holds inbound email configuration
+type Config struct {
+	SigningKey string // the provider's webhook signing key
+}
+
+// Server receives the inbound email webhook
+type Server struct {
+	signingKey          string
+	forwardEmailCommand *command.ForwardEmailCommand
+	messenger           ports.MessengerPort
+	activityLogCommand  *command.ActivityLogCommand // optional, emails are not logged when nil
+	now                 func() time.Time
+	run                 func(func()) // runs the processing of an email after the webhook is answered
+}
+
+// NewServer creates a new inbound email server
+func NewServer(config Config, forwardEmailCommand *command.ForwardEmailCommand, messenger ports.MessengerPort, activityLogCommand *command.ActivityLogCommand) *Server {
+	return &Server{
+		signingKey:          config.SigningKey,
+		forwardEmailCommand: forwardEmailCommand,
+		messenger:           messenger,
+		activityLogCommand:  activityLogCommand,
+		now:                 time.Now,
+		run:                 func(f func()) { go f() },
+	}
+}
+
+// Handler returns the HTTP handler for the webhook
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/inbound-email", s.handleInbound)
+	return mux
+}
+
+// handleInbound accepts a forwarded email and processes it in the background,
+// since extraction takes longer than the provider waits for an answer
+func (s *Server) handleInbound(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBytes)
+	if err := r.ParseMultipartForm(maxRequestBytes); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.verify(r.FormValue("timestamp"), r.FormValue("token"), r.FormValue("signature")); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	usr, err := s.forwardEmailCommand.Resolve(r.Context(), r.FormValue("recipient"))
+	if err != nil {
+		if !errors.Is(err, shared.ErrUserNotFound) {
+			log.Printf("Inbound email user lookup failed: %v", err)
+			http.Error(w, "failed to load user", http.StatusInternalServerError)
+			return
+		}
+		// Answered with success so the provider does not retry mail for unknown addresses
+		log.Printf("Dropped inbound email for unknown address %q", r.FormValue("recipient"))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	email := emailContent(r.FormValue("body-plain"), r.FormValue("body-html"))
+	s.run(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), processTimeout)
+		defer cancel()
+		s.process(ctx, usr, email)
+	})
+	w.WriteHeader(http.StatusOK)
+}
+
+// process saves the recipes of an email and tells the user what was saved
+func (s *Server) process(ctx context.Context, usr *user.User, email command.InboundEmail) {
+	chatID := usr.TelegramID()
+
+	result, err := s.forwardEmailCommand.Execute(ctx, usr.ID(), email)
+	if err != nil || len(result.Saved) == 0 {
+		if err != nil && !errors.Is(err, shared.ErrNoRecipeLinks) {
+			log.Printf("Inbound email processing failed: %v", err)
+		}
+		_ = s.messenger.SendMessage(ctx, chatID, "📧 I couldn't find a recipe in the email you forwarded.")
+		return
+	}
+
+	_ = s.messenger.SendMessage(ctx, chatID, "📧 Saved from the email you forwarded:")
+	for _, rec := range result.Saved {
+		_ = s.messenger.SendRecipe(ctx, chatID, rec)
+		s.recordSave(ctx, usr.ID(), rec.Title())
+	}
+}
+
+// recordSave logs a saved recipe in the user's activity
+func (s *Server) recordSave(ctx context.Context, userID user.UserID, title string) {
+	if s.activityLogCommand == nil {
+		return
+	}
+	if err := s.activityLogCommand.Record(ctx, userID, activity.ActionRecipeSaved, title); err != nil {
+		log.Printf("Failed to record inbound email activity: %v", err)
+	}
+}
+
+// verify checks the signature the provider put on the webhook request
+// (https://documentation.mailgun.com/docs/mailgun/user-manual/tracking-messages/#securing-webhooks)
+func (s *Server) verify(timestamp, token, signature string) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || token == "" {
+		return errBadSignature
+	}
+	if age := s.now().Sub(time.Unix(unix, 0)); age > maxSignatureAge || age < -maxSignatureAge {
+		return errBadSignature
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(sign(s.signingKey, timestamp, token), expected) {
+		return errBadSignature
+	}
+	return nil
+}
+
+// sign computes the webhook signature of a timestamp and token
+func sign(signingKey, timestamp, token string) []byte {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(timestamp + token))
+	return mac.Sum(nil)
+}
+
+// emailContent reads the text and links of an email, preferring its plain text body
+func emailContent(plain, html string) command.InboundEmail {
+	text := strings.TrimSpace(plain)
+	if text == "" {
+		text = htmltext.Text(html)
+	}
+
+	links := htmltext.Links(html)
+	if len(links) == 0 {
+		links = htmltext.Links(plain)
+	}
+	return command.InboundEmail{Text: text, Links: links}
+}
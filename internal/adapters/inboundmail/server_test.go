@@ -0,0 +1,240 @@
+package inboundmail
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/adapters/sandbox"
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/domain/bookmark"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+const (
+	testSigningKey = "mailgun-signing-key"
+	testDomain     = "recipes.example.com"
+)
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// recordingMessenger keeps the messages sent to users
+type recordingMessenger struct {
+	messages []string
+	recipes  []*recipe.Recipe
+}
+
+func (m *recordingMessenger) SendMessage(ctx context.Context, chatID int64, text string) error {
+	m.messages = append(m.messages, text)
+	return nil
+}
+
+func (m *recordingMessenger) SendRecipe(ctx context.Context, chatID int64, rec *recipe.Recipe) error {
+	m.recipes = append(m.recipes, rec)
+	return nil
+}
+
+func (m *recordingMessenger) SendProgress(ctx context.Context, chatID int64, message string) error {
+	return nil
+}
+
+func (m *recordingMessenger) SendError(ctx context.Context, chatID int64, errorMsg string) error {
+	return nil
+}
+
+func (m *recordingMessenger) KeepChatAction(ctx context.Context, chatID int64, action ports.ChatAction) func() {
+	return func() {}
+}
+
+type testServer struct {
+	handler   http.Handler
+	forward   *command.ForwardEmailCommand
+	messenger *recordingMessenger
+	recipes   *memory.RecipeRepository
+	user      *user.User
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	users := memory.NewUserRepository()
+	usr, _ := user.NewUser(1001, "alice")
+	if err := users.Save(context.Background(), usr); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// The recipe site linked from a mail without a recipe replays the carbonara
+	fixtures := loadFixtures(t, map[string]string{
+		"https://www.allrecipes.com/recipe/1/": "https://www.youtube.com/watch?v=sandbox-carbonara",
+	})
+	recipes := memory.NewRecipeRepository()
+	process := command.NewProcessRecipeLinkCommand(sandbox.NewScraper(fixtures), sandbox.NewLLM(fixtures), recipe.NewService(), recipes, nil)
+	forward := command.NewForwardEmailCommand(process, users, bookmark.NewSelector(nil), testDomain, "inbound-test-secret-of-32-characters")
+	messenger := &recordingMessenger{}
+
+	server := NewServer(Config{SigningKey: testSigningKey}, forward, messenger, nil)
+	server.now = func() time.Time { return testNow }
+	server.run = func(f func()) { f() }
+
+	return &testServer{handler: server.Handler(), forward: forward, messenger: messenger, recipes: recipes, user: usr}
+}
+
+// post delivers a webhook request signed at the given time and returns the status
+func (s *testServer) post(t *testing.T, signedAt time.Time, signingKey string, fields map[string]string) int {
+	t.Helper()
+
+	form := url.Values{}
+	for k, v := range fields {
+		form.Set(k, v)
+	}
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	form.Set("timestamp", timestamp)
+	form.Set("token", "webhook-token")
+	form.Set("signature", hex.EncodeToString(sign(signingKey, timestamp, "webhook-token")))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inbound-email", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestServer_Signature(t *testing.T) {
+	s := newTestServer(t)
+	fields := map[string]string{"recipient": s.forward.Address(s.user), "body-plain": "hello"}
+
+	tests := []struct {
+		name       string
+		signedAt   time.Time
+		signingKey string
+		want       int
+	}{
+		{"valid", testNow.Add(-time.Minute), testSigningKey, http.StatusOK},
+		{"another key", testNow, "other-key", http.StatusUnauthorized},
+		{"replayed", testNow.Add(-time.Hour), testSigningKey, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.post(t, tt.signedAt, tt.signingKey, fields); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_UnknownAddress(t *testing.T) {
+	s := newTestServer(t)
+	address := s.forward.Address(s.user)
+
+	for _, recipient := range []string{
+		"u1001.aaaaaaaaaa@" + testDomain,              // wrong code
+		strings.Replace(address, "u1001", "u2002", 1), // another user's ID with this code
+		strings.Replace(address, testDomain, "elsewhere.example.com", 1),
+	} {
+		if got := s.post(t, testNow, testSigningKey, map[string]string{"recipient": recipient, "body-plain": "hello"}); got != http.StatusOK {
+			t.Errorf("status for %s = %d, want %d so the provider does not retry", recipient, got, http.StatusOK)
+		}
+	}
+	if len(s.messenger.messages) != 0 {
+		t.Errorf("sent %v for unknown addresses", s.messenger.messages)
+	}
+}
+
+func TestServer_NewsletterRecipe(t *testing.T) {
+	s := newTestServer(t)
+
+	s.post(t, testNow, testSigningKey, map[string]string{
+		"recipient": strings.ToUpper(s.forward.Address(s.user)),
+		"body-plain": "This week: spaghetti carbonara. Boil the spaghetti, crisp the guanciale.\n" +
+			"Read online: https://news.example.com/issue/12\nhttps://www.seriouseats.com/pasta-carbonara",
+	})
+
+	if len(s.messenger.recipes) != 1 || s.messenger.recipes[0].Title() != "Spaghetti Carbonara" {
+		t.Fatalf("sent recipes %v, want the carbonara", s.messenger.recipes)
+	}
+	// The recipe link is its source, not the newsletter's own link
+	if _, err := s.recipes.FindBySourceURL(context.Background(), "https://www.seriouseats.com/pasta-carbonara"); err != nil {
+		t.Errorf("FindBySourceURL() error = %v", err)
+	}
+}
+
+func TestServer_RecipeLinks(t *testing.T) {
+	s := newTestServer(t)
+
+	// The body has no recipe, so the recipe link it holds is processed
+	s.post(t, testNow, testSigningKey, map[string]string{
+		"recipient": s.forward.Address(s.user),
+		"body-html": `<p>A day in my life, no cooking today</p><a href="https://www.allrecipes.com/recipe/1/">Try this</a>`,
+	})
+	if len(s.messenger.recipes) != 1 {
+		t.Fatalf("sent recipes %v, want the linked recipe", s.messenger.recipes)
+	}
+
+	s.post(t, testNow, testSigningKey, map[string]string{
+		"recipient":  s.forward.Address(s.user),
+		"body-plain": "A day in my life, no cooking today. https://news.example.com/",
+	})
+	if last := s.messenger.messages[len(s.messenger.messages)-1]; !strings.Contains(last, "couldn't find a recipe") {
+		t.Errorf("last message = %q, want no recipe found", last)
+	}
+}
+
+// loadFixtures loads the sandbox's built-in fixtures, with each URL of aliases
+// also recorded as a copy of the fixture of the URL it maps to
+func loadFixtures(t *testing.T, aliases map[string]string) *sandbox.Fixtures {
+	t.Helper()
+
+	data, err := os.ReadFile("../sandbox/fixtures/default.json")
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("failed to parse fixtures: %v", err)
+	}
+
+	recorded := make(map[string]map[string]any, len(entries))
+	for _, entry := range entries {
+		recorded[entry["url"].(string)] = entry
+	}
+	for alias, url := range aliases {
+		entry, ok := recorded[url]
+		if !ok {
+			t.Fatalf("no fixture recorded for %s", url)
+		}
+		copied := make(map[string]any, len(entry))
+		for k, v := range entry {
+			copied[k] = v
+		}
+		copied["url"] = alias
+		entries = append(entries, copied)
+	}
+
+	data, err = json.Marshal(entries)
+	if err != nil {
+		t.Fatalf("failed to encode fixtures: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write fixtures: %v", err)
+	}
+
+	fixtures, err := sandbox.LoadFixtures(path)
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+	return fixtures
+}
@@ -0,0 +1,75 @@
+// Package integrations serves the OAuth callbacks of the services users connect
+// with /connect, then tells them in chat that the connection worked.
+package integrations
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"time"
+
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// Server receives OAuth callbacks
+type Server struct {
+	cloudStorageCommand *command.CloudStorageCommand
+	messenger           ports.MessengerPort
+	now                 func() time.Time
+}
+
+// NewServer creates a new OAuth callback server
+func NewServer(cloudStorageCommand *command.CloudStorageCommand, messenger ports.MessengerPort) *Server {
+	return &Server{
+		cloudStorageCommand: cloudStorageCommand,
+		messenger:           messenger,
+		now:                 time.Now,
+	}
+}
+
+// Handler returns the HTTP handler for the callbacks, one path per provider:
+// /oauth/dropbox/callback and /oauth/drive/callback
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /oauth/{provider}/callback", s.handleCallback)
+	return mux
+}
+
+// handleCallback finishes connecting the provider a /connect link was sent for
+func (s *Server) handleCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if reason := query.Get("error"); reason != "" {
+		log.Printf("OAuth callback for %s denied: %s", r.PathValue("provider"), reason)
+		page(w, http.StatusOK, "Nothing was connected. You can close this page and go back to Telegram.")
+		return
+	}
+
+	usr, err := s.cloudStorageCommand.Callback(r.Context(), query.Get("state"), query.Get("code"), s.now())
+	if errors.Is(err, shared.ErrConnectLinkExpired) {
+		page(w, http.StatusBadRequest, "This link has expired. Send /connect in Telegram to get a new one.")
+		return
+	}
+	if err != nil {
+		log.Printf("OAuth callback for %s failed: %v", r.PathValue("provider"), err)
+		page(w, http.StatusBadGateway, "Connecting failed. Please try /connect again in Telegram.")
+		return
+	}
+
+	name := usr.CloudStorage().Provider.Name()
+	msg := fmt.Sprintf("☁️ %s connected! /export obsidian now saves your recipes there as Markdown files.", name)
+	if err := s.messenger.SendMessage(r.Context(), usr.TelegramID(), msg); err != nil {
+		log.Printf("Failed to confirm %s connection: %v", name, err)
+	}
+	page(w, http.StatusOK, name+" is connected. You can close this page and go back to Telegram.")
+}
+
+// page answers with a minimal HTML page holding one message
+func page(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<!doctype html><meta charset=\"utf-8\"><title>Receipt Bot</title><p>%s</p>\n", html.EscapeString(message))
+}
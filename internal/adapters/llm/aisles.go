@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/domain/shopping"
+)
+
+// AislePrompt asks the LLM to sort ingredients into grocery store aisles
+const AislePrompt = `Classify each ingredient into the grocery store aisle where it is usually found.
+
+Allowed aisles: produce, bakery, meat, seafood, dairy, pantry, spices, frozen, beverages, other
+
+Ingredients:
+%s
+
+Return ONLY valid JSON mapping every ingredient, exactly as written above, to its aisle:
+{"ingredient name": "aisle"}`
+
+// buildAislePrompt builds the aisle classification prompt for the ingredients
+func buildAislePrompt(ingredients []string) string {
+	return fmt.Sprintf(AislePrompt, "- "+strings.Join(ingredients, "\n- "))
+}
+
+// parseAisleResponse parses the LLM answer, keeping only requested ingredients
+func parseAisleResponse(response string, ingredients []string) (map[string]shopping.Aisle, error) {
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse aisle response: %w", err)
+	}
+
+	// Match case-insensitively, the LLM sometimes changes capitalization
+	byLower := make(map[string]string, len(raw))
+	for name, aisle := range raw {
+		byLower[strings.ToLower(strings.TrimSpace(name))] = aisle
+	}
+
+	aisles := make(map[string]shopping.Aisle, len(ingredients))
+	for _, name := range ingredients {
+		if aisle, ok := byLower[strings.ToLower(name)]; ok {
+			aisles[name] = shopping.ParseAisle(aisle)
+		}
+	}
+	return aisles, nil
+}
+
+// ClassifyAisles implements the AisleClassifier interface
+func (a *GeminiAdapter) ClassifyAisles(ctx context.Context, ingredients []string) (map[string]shopping.Aisle, error) {
+	if len(ingredients) == 0 {
+		return map[string]shopping.Aisle{}, nil
+	}
+
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(0.1)
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(buildAislePrompt(ingredients)))
+	if err != nil {
+		return nil, fmt.Errorf("aisle classification failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini for aisle classification")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseAisleResponse(responseText, ingredients)
+}
+
+// ClassifyAisles implements the AisleClassifier interface
+func (a *OpenAIAdapter) ClassifyAisles(ctx context.Context, ingredients []string) (map[string]shopping.Aisle, error) {
+	if len(ingredients) == 0 {
+		return map[string]shopping.Aisle{}, nil
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: buildAislePrompt(ingredients),
+			},
+		},
+		Temperature: 0.1,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("aisle classification failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI for aisle classification")
+	}
+
+	return parseAisleResponse(resp.Choices[0].Message.Content, ingredients)
+}
@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CheckModels verifies the provider accepts the API key and serves the model of
+// every kind of call, returning the models checked. It asks for each model's
+// details rather than generating anything, so it costs no tokens.
+func CheckModels(ctx context.Context, config LLMConfig) ([]string, error) {
+	switch provider := strings.ToLower(config.Provider); provider {
+	case "gemini":
+		adapter, err := NewGeminiAdapter(config.APIKey, config.Model)
+		if err != nil {
+			return nil, err
+		}
+		defer adapter.client.Close()
+
+		models := taskModels(adapter.model, config)
+		for _, model := range models {
+			if _, err := adapter.client.GenerativeModel(model).Info(ctx); err != nil {
+				return nil, checkError(model, err, config.APIKey)
+			}
+		}
+		return models, nil
+
+	case "openai", "ollama":
+		var adapter *OpenAIAdapter
+		var err error
+		if provider == "ollama" {
+			adapter, err = NewOllamaAdapter(config.BaseURL, config.APIKey, config.Model)
+		} else {
+			adapter, err = NewOpenAIAdapter(config.APIKey, config.Model)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		models := taskModels(adapter.model, config)
+		for _, model := range models {
+			if _, err := adapter.client.GetModel(ctx, model); err != nil {
+				return nil, checkError(model, err, config.APIKey)
+			}
+		}
+		return models, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s (supported: gemini, openai, ollama)", provider)
+	}
+}
+
+// checkError describes why a model failed its check. Errors may quote the request
+// URL, which carries Gemini's API key, so the key is left out.
+func checkError(model string, err error, apiKey string) error {
+	if apiKey == "" {
+		return fmt.Errorf("model %s: %w", model, err)
+	}
+	return fmt.Errorf("model %s: %s", model, strings.ReplaceAll(err.Error(), apiKey, "<key>"))
+}
+
+// taskModels returns the distinct models calls are made with: the default model
+// and those tasks override it with
+func taskModels(model string, config LLMConfig) []string {
+	models := []string{model}
+	for _, task := range []Task{config.ExtractTask, config.TranslateTask, config.IntentTask} {
+		if m := task.model(model); !containsModel(models, m) {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+// containsModel reports whether models includes model
+func containsModel(models []string, model string) bool {
+	for _, m := range models {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
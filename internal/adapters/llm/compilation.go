@@ -0,0 +1,96 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/ports"
+)
+
+// CompilationPrompt asks the LLM for every recipe in the text, for compilation videos.
+// It is sent after SystemPrompt, which defines the format of each recipe.
+const CompilationPrompt = `The text may describe SEVERAL different dishes, for example a video showing "3 easy breakfasts".
+
+- Extract each dish as its own recipe, in the order they appear, with only its own ingredients and steps
+- Variations of one dish (e.g. a topping or a different sauce) belong to that dish's recipe
+- If the text describes a single dish, return a list with one recipe
+
+Return ONLY valid JSON in this exact format, each recipe in the format above:
+{"recipes": [{"title": "...", "ingredients": [...], "instructions": [...]}]}`
+
+// compilationInstructions are the instructions that extract every recipe in a text
+const compilationInstructions = SystemPrompt + "\n\n" + CompilationPrompt
+
+// parseCompilationResponse parses the recipes, dropping those without ingredients
+func parseCompilationResponse(response string) ([]*ports.RecipeExtraction, error) {
+	var raw struct {
+		Recipes []recipeJSON `json:"recipes"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, &ports.LLMResponseError{Response: response, Err: fmt.Errorf("failed to parse recipes: %w", err)}
+	}
+
+	var extractions []*ports.RecipeExtraction
+	for i := range raw.Recipes {
+		if len(raw.Recipes[i].Ingredients) == 0 {
+			continue
+		}
+		extractions = append(extractions, convertJSONToExtraction(&raw.Recipes[i]))
+	}
+	return extractions, nil
+}
+
+// ExtractRecipes implements the MultiRecipeExtractor interface
+func (a *GeminiAdapter) ExtractRecipes(ctx context.Context, text string) ([]*ports.RecipeExtraction, error) {
+	modelName := a.extract.model(a.model)
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 90*time.Second)
+	defer cancel()
+
+	resp, err := a.prompts.generate(ctxWithTimeout, a.client, modelName, compilationInstructions, BuildUserPrompt(text), func(model *genai.GenerativeModel) {
+		model.SetTemperature(a.extract.temperature(0.3))
+		model.ResponseMIMEType = "application/json"
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Gemini API call failed: %w", classifyAPIError(err))
+	}
+	recordGeminiUsage(ctx, modelName, resp)
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseCompilationResponse(responseText)
+}
+
+// ExtractRecipes implements the MultiRecipeExtractor interface
+func (a *OpenAIAdapter) ExtractRecipes(ctx context.Context, text string) ([]*ports.RecipeExtraction, error) {
+	req := openai.ChatCompletionRequest{
+		Model: a.extract.model(a.model),
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: compilationInstructions},
+			{Role: openai.ChatMessageRoleUser, Content: BuildUserPrompt(text)},
+		},
+		Temperature: a.extract.temperature(0.3),
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	responseText, err := a.completeJSON(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI API call failed: %w", classifyAPIError(err))
+	}
+
+	return parseCompilationResponse(responseText)
+}
@@ -0,0 +1,173 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/ports"
+)
+
+// ConsolidatePrompt asks the LLM to merge the recipes extracted from the parts of a long video.
+// It is sent after SystemPrompt, which defines the JSON format and categories.
+const ConsolidatePrompt = `A long cooking video was split into parts, and a recipe was extracted from each part.
+These partial recipes, in video order, all describe the SAME dish:
+
+%s
+
+Merge them into one complete recipe:
+- Keep every ingredient, listing each once; when parts disagree on a quantity, keep the most precise one
+- Keep every step in video order, dropping steps that repeat an earlier one, and number them from 1
+- Take the title, category, cuisine, times and servings from the parts that give them
+- Give each field the provenance of the part it was taken from, lowering the confidence when parts disagree
+- Ignore parts that only contain talk unrelated to the recipe
+- Keep the original language and fill the "translated_*" fields as usual`
+
+// buildConsolidatePrompt builds the consolidation prompt for the candidates
+func buildConsolidatePrompt(candidates []*ports.RecipeExtraction) (string, error) {
+	parts := make([]recipeJSON, len(candidates))
+	for i, candidate := range candidates {
+		parts[i] = convertExtractionToJSON(candidate)
+	}
+	data, err := json.MarshalIndent(parts, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode partial recipes: %w", err)
+	}
+	return fmt.Sprintf("%s\n\n%s", SystemPrompt, fmt.Sprintf(ConsolidatePrompt, data)), nil
+}
+
+// convertExtractionToJSON converts an extraction back to the JSON format the LLM answers in
+func convertExtractionToJSON(extraction *ports.RecipeExtraction) recipeJSON {
+	raw := recipeJSON{
+		Title:                  extraction.Title,
+		Category:               extraction.Category,
+		Cuisine:                extraction.Cuisine,
+		DietaryTags:            extraction.DietaryTags,
+		Flavors:                extraction.Flavors,
+		Tags:                   extraction.Tags,
+		Ingredients:            ingredientsToJSON(extraction.Ingredients),
+		Instructions:           instructionsToJSON(extraction.Instructions),
+		Servings:               extraction.Servings,
+		SourceLanguage:         extraction.SourceLanguage,
+		TranslatedTitle:        extraction.TranslatedTitle,
+		TranslatedIngredients:  ingredientsToJSON(extraction.TranslatedIngredients),
+		TranslatedInstructions: instructionsToJSON(extraction.TranslatedInstructions),
+	}
+	for _, p := range extraction.Provenance {
+		raw.Provenance = append(raw.Provenance, provenanceJSON{Field: p.Field, Source: p.Source, Confidence: p.Confidence})
+	}
+	if extraction.PrepTime != nil {
+		minutes := int(extraction.PrepTime.Minutes())
+		raw.PrepTimeMinutes = &minutes
+	}
+	if extraction.CookTime != nil {
+		minutes := int(extraction.CookTime.Minutes())
+		raw.CookTimeMinutes = &minutes
+	}
+	return raw
+}
+
+// ingredientsToJSON converts ingredients to the LLM JSON format
+func ingredientsToJSON(ingredients []ports.IngredientData) []ingredientJSON {
+	if len(ingredients) == 0 {
+		return nil
+	}
+	raw := make([]ingredientJSON, len(ingredients))
+	for i, ing := range ingredients {
+		raw[i] = ingredientJSON{Name: ing.Name, Quantity: ing.Quantity, Unit: ing.Unit, Notes: ing.Notes}
+	}
+	return raw
+}
+
+// instructionsToJSON converts instructions to the LLM JSON format
+func instructionsToJSON(instructions []ports.InstructionData) []instructionJSON {
+	if len(instructions) == 0 {
+		return nil
+	}
+	raw := make([]instructionJSON, len(instructions))
+	for i, inst := range instructions {
+		raw[i] = instructionJSON{StepNumber: inst.StepNumber, Text: inst.Text}
+		if inst.Duration != nil {
+			minutes := inst.Duration.Minutes()
+			raw[i].DurationMinutes = &minutes
+		}
+		if inst.Timestamp != nil {
+			seconds := int(inst.Timestamp.Seconds())
+			raw[i].StartSeconds = &seconds
+		}
+	}
+	return raw
+}
+
+// parseConsolidateResponse parses the merged recipe
+func parseConsolidateResponse(response string) (*ports.RecipeExtraction, error) {
+	var raw recipeJSON
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse consolidated recipe: %w", err)
+	}
+	return convertJSONToExtraction(&raw), nil
+}
+
+// ConsolidateRecipe implements the RecipeConsolidator interface
+func (a *GeminiAdapter) ConsolidateRecipe(ctx context.Context, candidates []*ports.RecipeExtraction) (*ports.RecipeExtraction, error) {
+	prompt, err := buildConsolidatePrompt(candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	modelName := a.extract.model(a.model)
+	model := a.client.GenerativeModel(modelName)
+	model.SetTemperature(a.extract.temperature(0.2))
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 90*time.Second)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(prompt))
+	if err != nil {
+		return nil, fmt.Errorf("recipe consolidation failed: %w", err)
+	}
+	recordGeminiUsage(ctx, modelName, resp)
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini for recipe consolidation")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseConsolidateResponse(responseText)
+}
+
+// ConsolidateRecipe implements the RecipeConsolidator interface
+func (a *OpenAIAdapter) ConsolidateRecipe(ctx context.Context, candidates []*ports.RecipeExtraction) (*ports.RecipeExtraction, error) {
+	prompt, err := buildConsolidatePrompt(candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: a.extract.model(a.model),
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: prompt},
+		},
+		Temperature: a.extract.temperature(0.2),
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	responseText, err := a.completeJSON(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("recipe consolidation failed: %w", err)
+	}
+
+	return parseConsolidateResponse(responseText)
+}
@@ -0,0 +1,173 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/ports"
+)
+
+// ConvertPrompt asks the LLM to adapt a recipe to a slow cooker or Instant Pot
+const ConvertPrompt = `Adapt this recipe to cook in a %s.
+
+Recipe: %s
+Servings: %s
+Prep time: %s
+Cook time: %s
+
+Ingredients:
+%s
+
+Instructions:
+%s
+
+Rules:
+- Write in %s
+- Rewrite the instructions for the %s, keeping every ingredient and quantity
+- Give the appliance setting and time in each step that uses it (e.g. "Cook on LOW for 6-8 hours", "Pressure cook on HIGH for 12 minutes, then natural release for 10 minutes")
+- Steps done outside the appliance (chopping, searing, finishing) stay as separate steps
+- Give realistic total prep and cook times in minutes for the converted recipe
+- Put advice that doesn't fit a step in "notes" (e.g. reduce the liquid, minimum liquid for pressure cooking), or "" if there is none
+
+Return ONLY valid JSON in this exact format:
+{"instructions": [{"step_number": 1, "text": "step", "duration_minutes": 10}], "prep_time_minutes": 15, "cook_time_minutes": 360, "notes": ""}`
+
+// applianceNames are the appliance names used in the conversion prompt
+var applianceNames = map[recipe.Appliance]string{
+	recipe.ApplianceSlowCooker: "slow cooker (Crock-Pot)",
+	recipe.ApplianceInstantPot: "Instant Pot (electric pressure cooker)",
+}
+
+// buildConvertPrompt builds the conversion prompt for the recipe
+func buildConvertPrompt(input *ports.RecipeConversionInput, appliance recipe.Appliance, targetLang string) string {
+	ingredients := make([]string, len(input.Ingredients))
+	for i, ing := range input.Ingredients {
+		ingredients[i] = "- " + strings.TrimSpace(strings.Join([]string{ing.Quantity, ing.Unit, ing.Name}, " "))
+		if ing.Notes != "" {
+			ingredients[i] += " (" + ing.Notes + ")"
+		}
+	}
+
+	steps := make([]string, len(input.Instructions))
+	for i, inst := range input.Instructions {
+		steps[i] = fmt.Sprintf("%d. %s", inst.StepNumber, inst.Text)
+	}
+
+	servings := "unknown"
+	if input.Servings != nil {
+		servings = fmt.Sprintf("%d", *input.Servings)
+	}
+
+	name := applianceNames[appliance]
+	return fmt.Sprintf(ConvertPrompt, name, input.Title, servings,
+		promptMinutes(input.PrepTime), promptMinutes(input.CookTime),
+		strings.Join(ingredients, "\n"), strings.Join(steps, "\n"), targetLang, name)
+}
+
+// promptMinutes formats an optional duration for a prompt
+func promptMinutes(d *time.Duration) string {
+	if d == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d minutes", int(d.Minutes()))
+}
+
+// parseConvertResponse parses the LLM answer, numbering the steps in order
+func parseConvertResponse(response string) (*ports.RecipeConversionOutput, error) {
+	var raw struct {
+		Instructions []struct {
+			Text            string `json:"text"`
+			DurationMinutes *int   `json:"duration_minutes"`
+		} `json:"instructions"`
+		PrepTimeMinutes *int   `json:"prep_time_minutes"`
+		CookTimeMinutes *int   `json:"cook_time_minutes"`
+		Notes           string `json:"notes"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse converted recipe: %w", err)
+	}
+
+	output := &ports.RecipeConversionOutput{
+		PrepTime: minutesToDuration(raw.PrepTimeMinutes),
+		CookTime: minutesToDuration(raw.CookTimeMinutes),
+		Notes:    strings.TrimSpace(raw.Notes),
+	}
+	for _, inst := range raw.Instructions {
+		text := strings.TrimSpace(inst.Text)
+		if text == "" {
+			continue
+		}
+		output.Instructions = append(output.Instructions, ports.InstructionData{
+			StepNumber: len(output.Instructions) + 1,
+			Text:       text,
+			Duration:   minutesToDuration(inst.DurationMinutes),
+		})
+	}
+
+	if len(output.Instructions) == 0 {
+		return nil, fmt.Errorf("no converted instructions in response")
+	}
+	return output, nil
+}
+
+// ConvertRecipe implements the RecipeConverter interface
+func (a *GeminiAdapter) ConvertRecipe(ctx context.Context, input *ports.RecipeConversionInput, appliance recipe.Appliance, targetLang string) (*ports.RecipeConversionOutput, error) {
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(0.3)
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(buildConvertPrompt(input, appliance, targetLang)))
+	if err != nil {
+		return nil, fmt.Errorf("recipe conversion failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini for recipe conversion")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseConvertResponse(responseText)
+}
+
+// ConvertRecipe implements the RecipeConverter interface
+func (a *OpenAIAdapter) ConvertRecipe(ctx context.Context, input *ports.RecipeConversionInput, appliance recipe.Appliance, targetLang string) (*ports.RecipeConversionOutput, error) {
+	req := openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: buildConvertPrompt(input, appliance, targetLang),
+			},
+		},
+		Temperature: 0.3,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("recipe conversion failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI for recipe conversion")
+	}
+
+	return parseConvertResponse(resp.Choices[0].Message.Content)
+}
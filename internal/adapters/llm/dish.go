@@ -0,0 +1,122 @@
+package llm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/ports"
+)
+
+// DishPrompt asks the LLM to propose a recipe for the dish in a photo.
+// It is sent after SystemPrompt, which defines the JSON format and categories.
+const DishPrompt = `The image is a photo of a finished dish, for example a plate at a restaurant. It is NOT a written recipe.
+
+Work out what the dish is and propose a home recipe that recreates it:
+- Name the dish as a restaurant menu would
+- List every ingredient you can see or would expect, with realistic quantities for the servings
+- Write clear steps a home cook can follow, with times where useful
+- Write everything in %s and set "source_language" to its language code
+- Set the "translated_*" fields to null
+%s`
+
+// buildDishPrompt builds the dish recreation prompt, including what the user wrote with the photo
+func buildDishPrompt(hint string, targetLang string) string {
+	note := ""
+	if hint = strings.TrimSpace(hint); hint != "" {
+		note = fmt.Sprintf("\nThe user wrote this with the photo, use it if it names the dish or the restaurant: %q", hint)
+	}
+	return fmt.Sprintf("%s\n\n%s", SystemPrompt, fmt.Sprintf(DishPrompt, targetLang, note))
+}
+
+// parseDishResponse parses the proposed recipe
+func parseDishResponse(response string) (*ports.RecipeExtraction, error) {
+	var raw recipeJSON
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse dish recipe: %w", err)
+	}
+	return convertJSONToExtraction(&raw), nil
+}
+
+// imageFormat returns the image subtype of the photo, e.g. "jpeg"
+func imageFormat(image []byte) string {
+	return strings.TrimPrefix(http.DetectContentType(image), "image/")
+}
+
+// imageDataURL embeds the photo in a data URL, for APIs that take images by URL
+func imageDataURL(image []byte) string {
+	return fmt.Sprintf("data:%s;base64,%s", http.DetectContentType(image), base64.StdEncoding.EncodeToString(image))
+}
+
+// RecreateDish implements the DishRecreator interface
+func (a *GeminiAdapter) RecreateDish(ctx context.Context, image []byte, hint string, targetLang string) (*ports.RecipeExtraction, error) {
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(0.7)
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout,
+		genai.ImageData(imageFormat(image), image),
+		genai.Text(buildDishPrompt(hint, targetLang)))
+	if err != nil {
+		return nil, fmt.Errorf("dish recreation failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini for dish recreation")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseDishResponse(responseText)
+}
+
+// RecreateDish implements the DishRecreator interface
+func (a *OpenAIAdapter) RecreateDish(ctx context.Context, image []byte, hint string, targetLang string) (*ports.RecipeExtraction, error) {
+	req := openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role: openai.ChatMessageRoleUser,
+				MultiContent: []openai.ChatMessagePart{
+					{
+						Type:     openai.ChatMessagePartTypeImageURL,
+						ImageURL: &openai.ChatMessageImageURL{URL: imageDataURL(image)},
+					},
+					{
+						Type: openai.ChatMessagePartTypeText,
+						Text: buildDishPrompt(hint, targetLang),
+					},
+				},
+			},
+		},
+		Temperature: 0.7,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("dish recreation failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI for dish recreation")
+	}
+
+	return parseDishResponse(resp.Choices[0].Message.Content)
+}
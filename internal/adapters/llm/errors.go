@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// classifyAPIError tags an LLM API error with the domain error it amounts to,
+// so callers can tell a timeout or an exhausted quota from other failures
+func classifyAPIError(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", shared.ErrLLMTimeout, err)
+	case isQuotaError(err):
+		return fmt.Errorf("%w: %w", shared.ErrQuotaExceeded, err)
+	}
+	return err
+}
+
+// isQuotaError reports whether the provider rejected the call for rate or quota limits
+func isQuotaError(err error) bool {
+	if status.Code(err) == codes.ResourceExhausted {
+		return true
+	}
+
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) && apiErr.HTTPStatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) && reqErr.HTTPStatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	errStr := err.Error()
+	return contains(errStr, "RESOURCE_EXHAUSTED") || contains(errStr, "quota")
+}
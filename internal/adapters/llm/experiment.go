@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+
+	"receipt-bot/internal/domain/experiment"
+	"receipt-bot/internal/ports"
+)
+
+// PromptExperiment routes a share of LLM calls through an alternate prompt or model
+type PromptExperiment struct {
+	Variant string // name the results of the alternate calls are tagged with
+	Percent int    // share of calls routed through the variant, 0-100
+	Model   string // alternate model, empty keeps the configured one
+	Prompt  string // alternate instructions, empty keeps the built-in prompt
+}
+
+// experimentRunner picks the variant of each call of an experiment and records how it ended.
+// A nil runner always picks the control, so adapters without an experiment use it as is.
+type experimentRunner struct {
+	name    string
+	config  PromptExperiment
+	tracker *experiment.Tracker
+}
+
+// newExperimentRunner returns the runner of an experiment, or nil if none is configured
+func newExperimentRunner(name string, config *PromptExperiment, tracker *experiment.Tracker) *experimentRunner {
+	if config == nil || config.Variant == "" || tracker == nil {
+		return nil
+	}
+	return &experimentRunner{name: name, config: *config, tracker: tracker}
+}
+
+// variantChoice is the variant, model and prompt a call runs with
+type variantChoice struct {
+	name   string
+	model  string
+	prompt string
+}
+
+// pick chooses the variant of a call, defaulting to the given model and prompt
+func (r *experimentRunner) pick(model, prompt string) variantChoice {
+	choice := variantChoice{name: experiment.Control, model: model, prompt: prompt}
+	if r == nil || r.tracker.Assign(r.config.Variant, r.config.Percent) == experiment.Control {
+		return choice
+	}
+
+	choice.name = r.config.Variant
+	if r.config.Model != "" {
+		choice.model = r.config.Model
+	}
+	if r.config.Prompt != "" {
+		choice.prompt = r.config.Prompt
+	}
+	return choice
+}
+
+// record counts the outcome of a call made with the variant
+func (r *experimentRunner) record(variant string, outcome experiment.Outcome) {
+	if r == nil {
+		return
+	}
+	r.tracker.Record(r.name, variant, outcome)
+}
+
+// callOutcome classifies the error of an LLM call
+func callOutcome(err error) experiment.Outcome {
+	if err == nil {
+		return experiment.OutcomeSuccess
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return experiment.OutcomeParseError
+	}
+	return experiment.OutcomeFailure
+}
+
+// extractionOutcome classifies an extraction, counting one without ingredients as a failure
+func extractionOutcome(extraction *ports.RecipeExtraction, err error) experiment.Outcome {
+	if err == nil && (extraction == nil || len(extraction.Ingredients) == 0) {
+		return experiment.OutcomeFailure
+	}
+	return callOutcome(err)
+}
@@ -4,17 +4,49 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/option"
+	"receipt-bot/internal/domain/experiment"
 	"receipt-bot/internal/ports"
 )
 
 // LLMConfig holds configuration for LLM providers
 type LLMConfig struct {
-	Provider string // "gemini", "openai", "anthropic"
+	Provider string // "gemini", "openai" or "ollama"
 	APIKey   string
 	Model    string
+	BaseURL  string // endpoint of an OpenAI-compatible local server, DefaultOllamaURL when empty
+
+	// Model and temperature of each kind of call, overriding Model
+	ExtractTask   Task // recipe extraction, including compilations and consolidating long transcripts
+	TranslateTask Task // recipe translation
+	IntentTask    Task // intent detection
+
+	// Version of the extraction instructions, DefaultPromptVersion when empty
+	PromptVersion string
+
+	// How long Gemini keeps the static instructions of extraction and intent prompts
+	// cached, zero to send them with every call
+	PromptCacheTTL time.Duration
+
+	// Prompt experiments, run only when a tracker is set to record their outcomes
+	Extraction *PromptExperiment
+	Intent     *PromptExperiment
+	Tracker    *experiment.Tracker
+}
+
+// defaultModels are the models of each provider used when no model is configured
+var defaultModels = map[string]string{
+	"gemini": "gemini-pro",  // stable, widely available
+	"openai": "gpt-4o-mini", // cost-effective
+	"ollama": "llama3.1",
+}
+
+// DefaultModel returns the model a provider uses when none is configured
+func DefaultModel(provider string) string {
+	return defaultModels[strings.ToLower(provider)]
 }
 
 // NewLLMAdapter creates an appropriate LLM adapter based on configuration
@@ -23,31 +55,64 @@ func NewLLMAdapter(config LLMConfig) (ports.LLMPort, error) {
 
 	switch provider {
 	case "gemini":
-		return NewGeminiAdapter(config.APIKey, config.Model)
+		adapter, err := NewGeminiAdapter(config.APIKey, config.Model)
+		if err != nil {
+			return nil, err
+		}
+		adapter.extraction = newExperimentRunner(experiment.Extraction, config.Extraction, config.Tracker)
+		adapter.extract = config.ExtractTask
+		adapter.translate = config.TranslateTask
+		adapter.prompts = newPromptCache(config.PromptCacheTTL)
+		if err := adapter.SetPromptVersion(config.PromptVersion); err != nil {
+			return nil, err
+		}
+		return adapter, nil
 
 	case "openai":
-		return NewOpenAIAdapter(config.APIKey, config.Model)
+		adapter, err := NewOpenAIAdapter(config.APIKey, config.Model)
+		if err != nil {
+			return nil, err
+		}
+		adapter.extraction = newExperimentRunner(experiment.Extraction, config.Extraction, config.Tracker)
+		adapter.extract = config.ExtractTask
+		adapter.translate = config.TranslateTask
+		if err := adapter.SetPromptVersion(config.PromptVersion); err != nil {
+			return nil, err
+		}
+		return adapter, nil
+
+	case "ollama":
+		adapter, err := NewOllamaAdapter(config.BaseURL, config.APIKey, config.Model)
+		if err != nil {
+			return nil, err
+		}
+		adapter.extraction = newExperimentRunner(experiment.Extraction, config.Extraction, config.Tracker)
+		adapter.extract = config.ExtractTask
+		adapter.translate = config.TranslateTask
+		if err := adapter.SetPromptVersion(config.PromptVersion); err != nil {
+			return nil, err
+		}
+		return adapter, nil
 
 	// Future: Add Anthropic support
 	// case "anthropic":
 	//     return NewAnthropicAdapter(config.APIKey, config.Model)
 
 	default:
-		return nil, fmt.Errorf("unsupported LLM provider: %s (supported: gemini, openai)", provider)
+		return nil, fmt.Errorf("unsupported LLM provider: %s (supported: gemini, openai, ollama)", provider)
 	}
 }
 
 // NewIntentDetector creates an intent detector based on configuration
-// Currently only supports Gemini provider
 func NewIntentDetector(config LLMConfig) (ports.IntentDetector, error) {
 	provider := strings.ToLower(config.Provider)
 
 	switch provider {
 	case "gemini":
 		// Normalize model name
-		model := config.Model
+		model := config.IntentTask.model(config.Model)
 		if model == "" {
-			model = "gemini-pro"
+			model = DefaultModel(provider)
 		}
 		model = normalizeModelName(model)
 		if model == "gemini-1.5-flash" {
@@ -60,11 +125,28 @@ func NewIntentDetector(config LLMConfig) (ports.IntentDetector, error) {
 			return nil, fmt.Errorf("failed to create Gemini client for intent detection: %w", err)
 		}
 
-		return NewIntentDetectorAdapter(client, model), nil
+		detector := NewIntentDetectorAdapter(client, model)
+		detector.experiment = newExperimentRunner(experiment.Intent, config.Intent, config.Tracker)
+		detector.temperature = config.IntentTask.temperature(detector.temperature)
+		detector.prompts = newPromptCache(config.PromptCacheTTL)
+		return detector, nil
 
-	case "openai":
-		// TODO: Implement OpenAI intent detector
-		return nil, fmt.Errorf("OpenAI intent detector not yet implemented")
+	case "openai", "ollama":
+		var adapter *OpenAIAdapter
+		var err error
+		if provider == "ollama" {
+			adapter, err = NewOllamaAdapter(config.BaseURL, config.APIKey, config.Model)
+		} else {
+			adapter, err = NewOpenAIAdapter(config.APIKey, config.Model)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for intent detection: %w", err)
+		}
+
+		detector := NewOpenAIIntentDetector(adapter, config.IntentTask.model(adapter.model))
+		detector.experiment = newExperimentRunner(experiment.Intent, config.Intent, config.Tracker)
+		detector.temperature = config.IntentTask.temperature(detector.temperature)
+		return detector, nil
 
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider for intent detection: %s", provider)
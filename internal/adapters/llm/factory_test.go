@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+
+	"receipt-bot/internal/config"
+)
+
+func TestNewLLMAdapter_SupportsConfiguredProviders(t *testing.T) {
+	// Every provider the configuration accepts must have an adapter
+	for _, provider := range config.LLMProviders {
+		if _, err := NewLLMAdapter(LLMConfig{Provider: provider, APIKey: "test-key"}); err != nil {
+			t.Errorf("NewLLMAdapter(%q) error = %v, but config accepts it", provider, err)
+		}
+	}
+}
+
+func TestNewLLMAdapter_SupportsConfiguredPromptVersions(t *testing.T) {
+	// Every prompt version the configuration accepts must have extraction instructions
+	if got, want := strings.Join(PromptVersions(), ","), strings.Join(config.PromptVersions, ","); got != want {
+		t.Errorf("PromptVersions() = %s, config accepts %s", got, want)
+	}
+	for _, version := range config.PromptVersions {
+		if _, err := NewLLMAdapter(LLMConfig{Provider: "openai", APIKey: "test-key", PromptVersion: version}); err != nil {
+			t.Errorf("NewLLMAdapter() with prompt version %q error = %v, but config accepts it", version, err)
+		}
+	}
+	if _, err := NewLLMAdapter(LLMConfig{Provider: "openai", APIKey: "test-key", PromptVersion: "v9"}); err == nil {
+		t.Error("NewLLMAdapter() with an unknown prompt version returned no error")
+	}
+}
+
+func TestSetPromptVersion_ChoosesExtractionPrompt(t *testing.T) {
+	port, err := NewLLMAdapter(LLMConfig{Provider: "ollama"})
+	if err != nil {
+		t.Fatalf("NewLLMAdapter() error = %v", err)
+	}
+	adapter := port.(*OpenAIAdapter)
+	if got := adapter.version.extraction(); got != SystemPrompt {
+		t.Errorf("default extraction prompt is not v1")
+	}
+
+	versioned, ok := port.(PromptVersioned)
+	if !ok {
+		t.Fatalf("%T does not implement PromptVersioned", port)
+	}
+	if err := versioned.SetPromptVersion("v2"); err != nil {
+		t.Fatalf("SetPromptVersion(v2) error = %v", err)
+	}
+	if got := adapter.version.extraction(); !strings.Contains(got, StatedAmountsPrompt) {
+		t.Errorf("extraction prompt after SetPromptVersion(v2) does not contain the stated amounts rules")
+	}
+
+	// An unknown version keeps the current one
+	if err := versioned.SetPromptVersion("v9"); err == nil {
+		t.Error("SetPromptVersion(v9) returned no error")
+	}
+	if got := adapter.version.extraction(); !strings.Contains(got, StatedAmountsPrompt) {
+		t.Errorf("SetPromptVersion(v9) changed the extraction prompt")
+	}
+}
+
+func TestNewLLMAdapter_SelectsProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    LLMConfig
+		wantModel string
+		wantLocal bool
+	}{
+		{"openai", LLMConfig{Provider: "openai", APIKey: "key"}, "gpt-4o-mini", false},
+		{"ollama", LLMConfig{Provider: "ollama"}, "llama3.1", true},
+		{"ollama with a model", LLMConfig{Provider: "ollama", Model: "qwen2.5"}, "qwen2.5", true},
+		{"provider case", LLMConfig{Provider: "Ollama"}, "llama3.1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port, err := NewLLMAdapter(tt.config)
+			if err != nil {
+				t.Fatalf("NewLLMAdapter() error = %v", err)
+			}
+			adapter, ok := port.(*OpenAIAdapter)
+			if !ok {
+				t.Fatalf("NewLLMAdapter() = %T, want *OpenAIAdapter", port)
+			}
+			if adapter.model != tt.wantModel || adapter.local != tt.wantLocal {
+				t.Errorf("adapter model = %q, local = %v, want %q, %v", adapter.model, adapter.local, tt.wantModel, tt.wantLocal)
+			}
+		})
+	}
+
+	port, err := NewLLMAdapter(LLMConfig{Provider: "gemini", APIKey: "key"})
+	if err != nil {
+		t.Fatalf("NewLLMAdapter(gemini) error = %v", err)
+	}
+	gemini, ok := port.(*GeminiAdapter)
+	if !ok {
+		t.Fatalf("NewLLMAdapter(gemini) = %T, want *GeminiAdapter", port)
+	}
+	defer gemini.Close()
+	if gemini.model != "gemini-pro" {
+		t.Errorf("NewLLMAdapter(gemini) model = %q, want gemini-pro", gemini.model)
+	}
+
+	if _, err := NewLLMAdapter(LLMConfig{Provider: "anthropic", APIKey: "key"}); err == nil {
+		t.Error("NewLLMAdapter(anthropic) returned no error for an unsupported provider")
+	}
+	if _, err := NewLLMAdapter(LLMConfig{Provider: "openai"}); err == nil {
+		t.Error("NewLLMAdapter(openai) without an API key returned no error")
+	}
+}
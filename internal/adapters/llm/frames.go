@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+)
+
+// FramesPrompt asks the LLM to read the recipe text shown on screen in video frames
+const FramesPrompt = `The images are key frames of a cooking video, in order.
+
+Copy the text shown on screen that is about the recipe: ingredient names and quantities, temperatures, times and step titles.
+
+Rules:
+- Copy the text as written, in its original language
+- Skip usernames, watermarks, hashtags and calls to like or follow
+- List text repeated across frames once
+- Return an empty list if no recipe text is shown
+
+Return ONLY valid JSON in this exact format:
+{"lines": ["200g spaghetti", "Bake 20 min at 180°C"]}`
+
+// parseFramesResponse parses the LLM answer into one line per piece of on-screen text
+func parseFramesResponse(response string) (string, error) {
+	var raw struct {
+		Lines []string `json:"lines"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return "", fmt.Errorf("failed to parse on-screen text: %w", err)
+	}
+
+	lines := make([]string, 0, len(raw.Lines))
+	for _, line := range raw.Lines {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ReadOnScreenText implements the FrameReader interface
+func (a *GeminiAdapter) ReadOnScreenText(ctx context.Context, frames [][]byte) (string, error) {
+	if len(frames) == 0 {
+		return "", nil
+	}
+
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(0.1)
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	parts := make([]genai.Part, 0, len(frames)+1)
+	for _, frame := range frames {
+		parts = append(parts, genai.ImageData(imageFormat(frame), frame))
+	}
+	parts = append(parts, genai.Text(FramesPrompt))
+
+	resp, err := model.GenerateContent(ctxWithTimeout, parts...)
+	if err != nil {
+		return "", fmt.Errorf("on-screen text reading failed: %w", err)
+	}
+	recordGeminiUsage(ctx, a.model, resp)
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no response from Gemini for on-screen text")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseFramesResponse(responseText)
+}
+
+// ReadOnScreenText implements the FrameReader interface
+func (a *OpenAIAdapter) ReadOnScreenText(ctx context.Context, frames [][]byte) (string, error) {
+	if len(frames) == 0 {
+		return "", nil
+	}
+
+	content := make([]openai.ChatMessagePart, 0, len(frames)+1)
+	for _, frame := range frames {
+		content = append(content, openai.ChatMessagePart{
+			Type:     openai.ChatMessagePartTypeImageURL,
+			ImageURL: &openai.ChatMessageImageURL{URL: imageDataURL(frame)},
+		})
+	}
+	content = append(content, openai.ChatMessagePart{
+		Type: openai.ChatMessagePartTypeText,
+		Text: FramesPrompt,
+	})
+
+	req := openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:         openai.ChatMessageRoleUser,
+				MultiContent: content,
+			},
+		},
+		Temperature: 0.1,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("on-screen text reading failed: %w", err)
+	}
+	recordOpenAIUsage(ctx, resp)
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from OpenAI for on-screen text")
+	}
+
+	return parseFramesResponse(resp.Choices[0].Message.Content)
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/option"
+	"receipt-bot/internal/domain/shared"
 	"receipt-bot/internal/ports"
 )
 
@@ -20,8 +22,13 @@ func contains(s, substr string) bool {
 
 // GeminiAdapter implements the LLMPort using Google Gemini
 type GeminiAdapter struct {
-	client *genai.Client
-	model  string
+	client     *genai.Client
+	model      string
+	extraction *experimentRunner // nil unless an extraction prompt experiment is running
+	extract    Task
+	translate  Task
+	prompts    *promptCache // nil unless prompt caching is enabled
+	version    promptVersion
 }
 
 // NewGeminiAdapter creates a new Gemini adapter
@@ -31,7 +38,7 @@ func NewGeminiAdapter(apiKey string, model string) (*GeminiAdapter, error) {
 	}
 
 	if model == "" {
-		model = "gemini-pro" // Default to stable, widely available model
+		model = DefaultModel("gemini")
 	}
 
 	// Normalize model name - handle common variations
@@ -77,30 +84,48 @@ func normalizeModelName(model string) string {
 
 // Close closes the Gemini client
 func (a *GeminiAdapter) Close() error {
+	a.prompts.clear(context.Background(), a.client)
 	return a.client.Close()
 }
 
+// SetPromptVersion implements the PromptVersioned interface
+func (a *GeminiAdapter) SetPromptVersion(version string) error {
+	return a.version.set(version)
+}
+
 // ExtractRecipe implements the LLMPort interface
 func (a *GeminiAdapter) ExtractRecipe(ctx context.Context, text string) (*ports.RecipeExtraction, error) {
-	model := a.client.GenerativeModel(a.model)
+	choice := a.extraction.pick(a.extract.model(a.model), a.version.extraction())
 
-	// Configure model for JSON output
-	model.SetTemperature(0.3) // Lower temperature for more deterministic output
-	model.ResponseMIMEType = "application/json"
+	extraction, err := a.extractRecipe(ctx, text, choice.model, choice.prompt)
+	a.extraction.record(choice.name, extractionOutcome(extraction, err))
+	if err != nil {
+		return nil, err
+	}
 
-	// Build the prompt
-	prompt := fmt.Sprintf("%s\n\n%s", SystemPrompt, BuildUserPrompt(text))
+	extraction.Variant = choice.name
+	return extraction, nil
+}
 
+// extractRecipe extracts a recipe with the given model and system prompt
+func (a *GeminiAdapter) extractRecipe(ctx context.Context, text, modelName, systemPrompt string) (*ports.RecipeExtraction, error) {
 	// Add timeout to prevent hanging indefinitely
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
-	// Generate content
-	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(prompt))
+	// Generate content, with the system prompt from the cache when enabled
+	resp, err := a.prompts.generate(ctxWithTimeout, a.client, modelName, systemPrompt, BuildUserPrompt(text), func(model *genai.GenerativeModel) {
+		// Configure model for JSON output
+		model.SetTemperature(a.extract.temperature(0.3)) // Lower temperature for more deterministic output
+		model.ResponseMIMEType = "application/json"
+	})
 	if err != nil {
 		// Check for timeout
 		if ctxWithTimeout.Err() == context.DeadlineExceeded {
-			return nil, fmt.Errorf("Gemini API call timed out after 60 seconds. The API may be slow or unresponsive. Please try again")
+			return nil, fmt.Errorf("%w: Gemini API call timed out after 60 seconds. The API may be slow or unresponsive", shared.ErrLLMTimeout)
+		}
+		if isQuotaError(err) {
+			return nil, fmt.Errorf("%w: Gemini API call failed: %w", shared.ErrQuotaExceeded, err)
 		}
 
 		// Provide helpful error message for model not found errors
@@ -114,6 +139,7 @@ func (a *GeminiAdapter) ExtractRecipe(ctx context.Context, text string) (*ports.
 		}
 		return nil, fmt.Errorf("Gemini API call failed: %w", err)
 	}
+	recordGeminiUsage(ctx, modelName, resp)
 
 	// Extract text from response
 	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
@@ -133,7 +159,7 @@ func (a *GeminiAdapter) ExtractRecipe(ctx context.Context, text string) (*ports.
 	if len(responsePreview) > 1000 {
 		responsePreview = responsePreview[:1000] + "..."
 	}
-	fmt.Printf("[DEBUG] Gemini raw response (preview): %s\n", responsePreview)
+	log.Printf("[DEBUG] Gemini raw response (preview): %s\n", responsePreview)
 
 	// Clean up response - remove markdown code blocks if present
 	cleanedResponse := cleanJSONResponse(responseText)
@@ -141,11 +167,11 @@ func (a *GeminiAdapter) ExtractRecipe(ctx context.Context, text string) (*ports.
 	// Parse JSON response
 	var recipeJSON recipeJSON
 	if err := json.Unmarshal([]byte(cleanedResponse), &recipeJSON); err != nil {
-		fmt.Printf("[DEBUG] Failed to parse JSON. Raw response: %s\n", responseText)
-		return nil, fmt.Errorf("failed to parse Gemini response as JSON: %w", err)
+		log.Printf("[DEBUG] Failed to parse JSON. Raw response: %s\n", responseText)
+		return nil, &ports.LLMResponseError{Response: responseText, Err: fmt.Errorf("failed to parse Gemini response as JSON: %w", err)}
 	}
 
-	fmt.Printf("[DEBUG] Parsed JSON - Ingredients: %d, Instructions: %d\n", len(recipeJSON.Ingredients), len(recipeJSON.Instructions))
+	log.Printf("[DEBUG] Parsed JSON - Ingredients: %d, Instructions: %d\n", len(recipeJSON.Ingredients), len(recipeJSON.Instructions))
 
 	// Convert to domain format
 	extraction := convertJSONToExtraction(&recipeJSON)
@@ -181,6 +207,7 @@ type recipeJSON struct {
 	Category        string            `json:"category"`
 	Cuisine         string            `json:"cuisine"`
 	DietaryTags     []string          `json:"dietary_tags"`
+	Flavors         []string          `json:"flavors"`
 	Tags            []string          `json:"tags"`
 	Ingredients     []ingredientJSON  `json:"ingredients"`
 	Instructions    []instructionJSON `json:"instructions"`
@@ -193,6 +220,15 @@ type recipeJSON struct {
 	TranslatedTitle        *string           `json:"translated_title"`
 	TranslatedIngredients  []ingredientJSON  `json:"translated_ingredients"`
 	TranslatedInstructions []instructionJSON `json:"translated_instructions"`
+
+	// Where the fields were found
+	Provenance []provenanceJSON `json:"provenance"`
+}
+
+type provenanceJSON struct {
+	Field      string  `json:"field"`
+	Source     string  `json:"source"`
+	Confidence float64 `json:"confidence"`
 }
 
 type ingredientJSON struct {
@@ -206,6 +242,16 @@ type instructionJSON struct {
 	StepNumber      int      `json:"step_number"`
 	Text            string   `json:"text"`
 	DurationMinutes *float64 `json:"duration_minutes"`
+	StartSeconds    *int     `json:"start_seconds"`
+}
+
+// secondsToDuration converts an optional video position in seconds, ignoring negative values
+func secondsToDuration(seconds *int) *time.Duration {
+	if seconds == nil || *seconds < 0 {
+		return nil
+	}
+	d := time.Duration(*seconds) * time.Second
+	return &d
 }
 
 // convertJSONToExtraction converts the JSON response to domain format
@@ -215,6 +261,7 @@ func convertJSONToExtraction(recipe *recipeJSON) *ports.RecipeExtraction {
 		Category:       recipe.Category,
 		Cuisine:        recipe.Cuisine,
 		DietaryTags:    recipe.DietaryTags,
+		Flavors:        recipe.Flavors,
 		Tags:           recipe.Tags,
 		Ingredients:    make([]ports.IngredientData, len(recipe.Ingredients)),
 		Instructions:   make([]ports.InstructionData, len(recipe.Instructions)),
@@ -248,6 +295,7 @@ func convertJSONToExtraction(recipe *recipeJSON) *ports.RecipeExtraction {
 			StepNumber: inst.StepNumber,
 			Text:       inst.Text,
 			Duration:   duration,
+			Timestamp:  secondsToDuration(inst.StartSeconds),
 		}
 	}
 
@@ -264,6 +312,15 @@ func convertJSONToExtraction(recipe *recipeJSON) *ports.RecipeExtraction {
 
 	extraction.Servings = recipe.Servings
 
+	// Convert provenance
+	for _, p := range recipe.Provenance {
+		extraction.Provenance = append(extraction.Provenance, ports.ProvenanceData{
+			Field:      p.Field,
+			Source:     p.Source,
+			Confidence: p.Confidence,
+		})
+	}
+
 	// Convert translated title
 	extraction.TranslatedTitle = recipe.TranslatedTitle
 
@@ -294,6 +351,7 @@ func convertJSONToExtraction(recipe *recipeJSON) *ports.RecipeExtraction {
 				StepNumber: inst.StepNumber,
 				Text:       inst.Text,
 				Duration:   duration,
+				Timestamp:  secondsToDuration(inst.StartSeconds),
 			}
 		}
 	}
@@ -303,10 +361,10 @@ func convertJSONToExtraction(recipe *recipeJSON) *ports.RecipeExtraction {
 
 // TranslateRecipe translates a recipe to the target language
 func (a *GeminiAdapter) TranslateRecipe(ctx context.Context, recipe *ports.RecipeTranslationInput, targetLang string) (*ports.RecipeTranslationOutput, error) {
-	model := a.client.GenerativeModel(a.model)
+	model := a.client.GenerativeModel(a.translate.model(a.model))
 
 	// Configure model for JSON output
-	model.SetTemperature(0.3)
+	model.SetTemperature(a.translate.temperature(0.3))
 	model.ResponseMIMEType = "application/json"
 
 	// Build ingredients list
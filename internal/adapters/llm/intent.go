@@ -19,9 +19,9 @@ const IntentPrompt = `You are an intent detection assistant for a recipe bot. An
 IMPORTANT: The user may write in English OR Portuguese (Brazilian). You MUST understand both languages.
 
 The bot supports these intents:
-- LIST_RECIPES: User wants to see their recipes
-  EN: "show recipes", "my recipes", "recipe list", "what recipes do I have"
-  PT: "mostrar receitas", "minhas receitas", "lista de receitas", "quais receitas eu tenho"
+- LIST_RECIPES: User wants to see their recipes, optionally those saved in a period
+  EN: "show recipes", "my recipes", "recipe list", "what recipes do I have", "recipes I saved last month"
+  PT: "mostrar receitas", "minhas receitas", "lista de receitas", "quais receitas eu tenho", "receitas que salvei mês passado"
 - FILTER_CATEGORY: User wants to filter recipes by category ONLY
   EN: "seafood recipes", "pasta dishes", "breakfast ideas", "show me desserts"
   PT: "receitas de frutos do mar", "pratos de massa", "ideias de café da manhã", "mostrar sobremesas"
@@ -34,6 +34,21 @@ The bot supports these intents:
 - SHOW_CATEGORIES: User wants to see available categories
   EN: "categories", "what types do I have", "show categories"
   PT: "categorias", "quais tipos eu tenho", "mostrar categorias"
+- FILTER_AUTHOR: User wants recipes saved from a specific creator
+  EN: "show me everything from @thatpastaguy", "recipes by thatpastaguy"
+  PT: "mostrar tudo do @thatpastaguy", "receitas do thatpastaguy"
+- SHOW_AUTHORS: User wants to see which creators they save recipes from most
+  EN: "who do I save the most", "my favorite creators", "authors"
+  PT: "de quem eu mais salvo receitas", "meus criadores favoritos", "autores"
+- FILTER_PLATFORM: User wants recipes saved from a specific platform (TikTok, YouTube, Instagram, websites)
+  EN: "show my TikTok recipes", "recipes I saved from YouTube", "the ones from Instagram"
+  PT: "minhas receitas do TikTok", "receitas que salvei do YouTube", "as do Instagram"
+- SHOW_PLATFORMS: User wants to see how many recipes they saved from each platform
+  EN: "where do I save recipes from", "platforms", "how many from TikTok vs YouTube"
+  PT: "de onde eu salvo receitas", "plataformas"
+- FILTER_FLAVOR: User wants recipes with a flavor profile (spicy, sweet, umami-rich, tangy)
+  EN: "I want something spicy", "sweet recipes", "something tangy"
+  PT: "quero algo picante", "receitas doces", "algo azedinho"
 - MANAGE_PANTRY: User wants to manage their pantry
   EN: "add chicken to pantry", "my pantry", "remove eggs from pantry", "clear my pantry"
   PT: "adicionar frango à despensa", "minha despensa", "remover ovos da despensa", "limpar minha despensa"
@@ -47,14 +62,50 @@ The bot supports these intents:
   EN: "show more", "next", "more recipes", "continue"
   PT: "mostrar mais", "próximo", "mais receitas", "continuar"
 - SHOW_DETAILS: User wants to see details of a specific recipe from results
-  EN: "show me #3", "details on the first one", "tell me about number 2"
-  PT: "mostrar #3", "detalhes do primeiro", "falar sobre o número 2"
+  EN: "show me #3", "details on the first one", "tell me about number 2", "open the carbonara", "the second salmon one"
+  PT: "mostrar #3", "detalhes do primeiro", "falar sobre o número 2", "abrir a carbonara", "a segunda de salmão"
 - REPEAT_LAST: User wants to repeat the last action
   EN: "show again", "repeat", "one more time"
   PT: "mostrar de novo", "repetir", "mais uma vez"
-- COMPOUND_QUERY: User combines a category with dietary/tag filters
-  EN: "quick pasta recipes", "vegan breakfast", "easy seafood"
-  PT: "receitas rápidas de massa", "café da manhã vegano", "frutos do mar fácil"
+- COMPOUND_QUERY: User combines a category with dietary/tag filters, or filters by difficulty
+  EN: "quick pasta recipes", "vegan breakfast", "easy seafood", "easy recipes", "something challenging"
+  PT: "receitas rápidas de massa", "café da manhã vegano", "frutos do mar fácil", "receitas fáceis"
+- SHOPPING_LIST: User wants a shopping list for the recipes planned this week
+  EN: "generate shopping list for this week", "what do I need to buy", "shopping list"
+  PT: "gerar lista de compras da semana", "o que preciso comprar", "lista de compras"
+- PLAN_MENU: User wants to plan a multi-course menu for an occasion
+  EN: "plan Christmas dinner for 8", "help me plan a dinner party menu"
+  PT: "planejar a ceia de Natal para 8", "montar um cardápio para um jantar"
+- CONVERT_RECIPE: User wants a recipe adapted to a slow cooker or Instant Pot
+  EN: "convert #4 to Instant Pot", "make recipe 2 in the slow cooker"
+  PT: "converter a #4 para panela de pressão", "fazer a receita 2 na panela elétrica"
+- REMIX_RECIPE: User wants a new version of a recipe adapted to a diet or goal, with ingredients swapped
+  EN: "remix recipe #3 to be vegan", "make it gluten-free", "a dairy-free version of #2"
+  PT: "adaptar a receita #3 para ser vegana", "faz ela sem glúten", "uma versão sem lactose da #2"
+- FREEZER: User froze portions of a recipe, ate frozen portions, or asks about their freezer
+  EN: "I froze 3 portions of recipe #7", "we ate 2 portions of #7", "what's in my freezer", "what's in my freezer I should eat"
+  PT: "congelei 3 porções da receita #7", "comemos 2 porções da #7", "o que tem no meu freezer", "o que do freezer devo comer"
+- REQUEST_DISH: User asks someone in their household to cook a dish
+  EN: "someone please make lasagna this week", "can someone cook curry tonight"
+  PT: "alguém faz lasanha esta semana", "alguém pode fazer strogonoff hoje"
+- SAVE_FILTER: User wants to save the current search under a name
+  EN: "save this search as weeknight", "remember this filter as lazy sunday"
+  PT: "salvar esta busca como semana", "guardar este filtro como domingo"
+- RUN_FILTER: User wants to re-run a search they saved by name
+  EN: "show my weeknight recipes", "run my lazy sunday filter"
+  PT: "mostrar minhas receitas de semana", "usar meu filtro domingo"
+- START_OVER: User wants to drop the current conversation, its questions and filters, and start fresh
+  EN: "start over", "forget that", "never mind, let's start again", "reset"
+  PT: "começar de novo", "esquece isso", "deixa pra lá, vamos recomeçar"
+- EXPORT_RECIPE: User wants one recipe exported to Notion, Obsidian, Crouton, AnyList or Whisk
+  EN: "export it to Notion", "send recipe 3 to Obsidian"
+  PT: "exporta essa pro Notion", "mandar a receita 3 pro Obsidian"
+- ADD_TO_SHOPPING_LIST: User wants the ingredients of one recipe added to their shopping list
+  EN: "add its ingredients to my shopping list", "put recipe 2 on the shopping list"
+  PT: "coloca os ingredientes dela na lista de compras", "adicionar a receita 2 na lista de compras"
+- TRANSLATE_RECIPE: User wants a recipe translated
+  EN: "translate it", "translate recipe 5 to Spanish"
+  PT: "traduz essa", "traduzir a receita 5 para o inglês"
 - UNKNOWN: Cannot determine intent
 
 Available recipe categories (use English names in response):
@@ -84,20 +135,42 @@ Portuguese tag mappings:
 - sem glúten -> gluten-free
 - sem lactose/sem leite -> dairy-free
 - low-carb/baixo carboidrato -> low-carb
-- rápido/fácil -> quick
+- rápido -> quick
 - panela única -> one-pot
 - para crianças -> kid-friendly
 
+Difficulty levels (use English names in response): easy, medium, hard
+- fácil/simples/iniciante -> easy
+- médio/intermediário -> medium
+- difícil/avançado/desafiador -> hard
+
 Response format - return ONLY valid JSON:
 {
   "intent": "INTENT_TYPE",
   "category": "category name in English or null",
   "dietaryTags": ["tag1", "tag2"] or [],
+  "difficulty": "easy|medium|hard or null",
   "ingredients": ["list", "of", "ingredients"] or [],
   "searchTerm": "specific ingredient to filter by or null",
+  "author": "creator name or handle or null",
+  "platform": "tiktok|youtube|instagram|web or null",
+  "flavor": "spicy|sweet|umami-rich|tangy or null",
   "pantryAction": "SHOW|ADD|REMOVE|CLEAR or null",
   "pantryItems": ["items", "to", "add/remove"] or [],
   "recipeNumber": number or null,
+  "recipeName": "words naming a recipe from the results or null",
+  "occasion": "what the menu is for or null",
+  "guests": number or null,
+  "appliance": "slow cooker|instant pot or null",
+  "remixGoal": "diet or goal to remix the recipe for, in English, or null",
+  "freezerAction": "SHOW|ADD|REMOVE|EAT_FIRST or null",
+  "portions": number or null,
+  "dish": "dish asked for or null",
+  "maxMinutes": number or null,
+  "savedPeriod": "when the recipes were saved, in English, or null",
+  "filterName": "name of a saved search or null",
+  "exportFormat": "notion|obsidian|crouton|anylist|whisk or null",
+  "language": "language to translate into, in English, or null",
   "confidence": 0.0-1.0
 }
 
@@ -105,15 +178,31 @@ Rules:
 - ALWAYS return category names in ENGLISH regardless of input language
 - For FILTER_CATEGORY: Set "category" to the closest matching category from the list (NO dietary tags)
 - For COMPOUND_QUERY: Set BOTH "category" AND "dietaryTags" when user combines them
+- Set "difficulty" when the user asks for easy, medium or hard recipes ("easy" is a difficulty, not the "quick" tag)
 - For FILTER_INGREDIENT: Set "searchTerm" to the ingredient translated to ENGLISH
+- For FILTER_AUTHOR: Set "author" to the creator exactly as written, without translating it
+- For FILTER_PLATFORM: Set "platform" to tiktok, youtube, instagram or web (websites and blogs are "web")
+- For FILTER_FLAVOR: Set "flavor" to spicy, sweet, umami-rich or tangy ("picante" -> "spicy", "savory" -> "umami-rich", "sour" -> "tangy")
 - For MATCH_INGREDIENTS: Extract all ingredients mentioned into "ingredients" array, translated to ENGLISH
 - For MANAGE_PANTRY: Set "pantryAction" and "pantryItems" if adding/removing (translate items to ENGLISH)
-- For SHOW_DETAILS: Set "recipeNumber" to the 1-based index
+- For SHOW_DETAILS: Set "recipeNumber" to the 1-based index, or "recipeName" to the words naming the recipe exactly as written ("open the carbonara" -> "carbonara"). With both, "recipeNumber" counts among the recipes with that name ("the second salmon one" -> recipeName "salmon", recipeNumber 2)
+- For PLAN_MENU: Set "occasion" (e.g., "Christmas dinner") and "guests" if the user says how many people
+- For FREEZER: Set "freezerAction" (ADD when freezing, REMOVE when eating, EAT_FIRST when asking what to eat, SHOW otherwise), "recipeNumber" and "portions"
+- For REQUEST_DISH: Set "dish" to the dish exactly as written, without "someone please make" or "this week" ("alguém faz lasanha esta semana" -> "lasanha")
+- For CONVERT_RECIPE: Set "recipeNumber" to the recipe number and "appliance" to "slow cooker" or "instant pot" (a pressure cooker is "instant pot")
+- For REMIX_RECIPE: Set "recipeNumber" to the recipe number and "remixGoal" to the goal in English ("sem glúten" -> "gluten-free", "vegana" -> "vegan")
+- Set "maxMinutes" when the user limits the total time ("under 30 min", "em menos de 30 minutos")
+- For LIST_RECIPES: Set "savedPeriod" when the user asks for recipes saved in a period, as one of: today, yesterday, this week, last week, this month, last month, this year, last year, last N days, last N weeks, last N months, a month name ("december", "december 2024"), YYYY-MM-DD, YYYY-MM or YYYY ("o que salvei em dezembro" -> "december")
+- For SAVE_FILTER and RUN_FILTER: Set "filterName" to the name exactly as written, without translating it
+- For EXPORT_RECIPE: Set "exportFormat" to where the recipe goes ("Samsung Food" is "whisk", "Markdown" is "obsidian")
+- For TRANSLATE_RECIPE: Set "language" to the target language in English ("inglês" -> "English"), or null if none is named
+- "it", "that", "this one", "essa", "ela", "dela" refer to the recipe the user opened last: leave "recipeNumber" null for CONVERT_RECIPE, REMIX_RECIPE, FREEZER, EXPORT_RECIPE, ADD_TO_SHOPPING_LIST and TRANSLATE_RECIPE
 - Confidence should be 0.9+ for clear intents, 0.7-0.9 for likely matches, below 0.7 for uncertain
 - If a message mentions a specific food item but doesn't say "I have"/"tenho", treat it as FILTER_INGREDIENT
 - ALWAYS translate ingredient names to ENGLISH in searchTerm, ingredients, and pantryItems fields (e.g., "frango" -> "chicken", "carne" -> "beef")`
 
-// IntentPromptWithContext is the enhanced prompt that includes conversation history
+// IntentPromptWithContext is the enhanced prompt that includes conversation history.
+// It is sent as intentContextInstructions followed by intentContextSections.
 const IntentPromptWithContext = `You are a conversational assistant for a recipe bot. Analyze the user message IN CONTEXT of the conversation history and determine both the intent AND the best next action.
 
 IMPORTANT: The user may write in English OR Portuguese (Brazilian). You MUST understand both languages.
@@ -125,7 +214,7 @@ IMPORTANT: The user may write in English OR Portuguese (Brazilian). You MUST und
 %s
 
 ## AVAILABLE INTENTS:
-- LIST_RECIPES: User wants to see their recipes
+- LIST_RECIPES: User wants to see their recipes, optionally those saved in a period ("what did I save in December")
 - FILTER_CATEGORY: User wants to filter recipes by category ONLY
 - FILTER_INGREDIENT: User wants to find recipes containing a SINGLE specific ingredient
 - COMPLEX_SEARCH: User wants to find recipes with MULTIPLE ingredients or exclusions
@@ -133,13 +222,60 @@ IMPORTANT: The user may write in English OR Portuguese (Brazilian). You MUST und
   PT: "receitas com salmão e sriracha", "massa sem lactose", "receitas de frango ou carne"
 - MATCH_INGREDIENTS: User lists ingredients they HAVE and wants matching recipes (what can I make)
 - SHOW_CATEGORIES: User wants to see available categories
+- FILTER_AUTHOR: User wants recipes saved from a specific creator
+  EN: "show me everything from @thatpastaguy"
+  PT: "mostrar tudo do @thatpastaguy"
+- SHOW_AUTHORS: User wants to see which creators they save recipes from most
+- FILTER_PLATFORM: User wants recipes saved from a specific platform
+  EN: "show my TikTok recipes"
+  PT: "minhas receitas do TikTok"
+- SHOW_PLATFORMS: User wants to see how many recipes they saved from each platform
+- FILTER_FLAVOR: User wants recipes with a flavor profile (spicy, sweet, umami-rich, tangy)
+  EN: "I want something spicy"
+  PT: "quero algo picante"
 - MANAGE_PANTRY: User wants to manage their pantry
 - HELP: User needs help
 - GREETING: User is greeting
 - SHOW_MORE: User wants to see more results from previous query
-- SHOW_DETAILS: User wants to see details of a specific recipe from results
+- SHOW_DETAILS: User wants to see details of a specific recipe from results, by number or by name ("open the carbonara")
 - REPEAT_LAST: User wants to repeat the last action
-- COMPOUND_QUERY: User combines a category with dietary/tag filters
+- COMPOUND_QUERY: User combines a category with dietary/tag filters, or filters by difficulty ("easy recipes", "receitas fáceis")
+- SHOPPING_LIST: User wants a shopping list for the recipes planned this week
+  EN: "generate shopping list for this week", "what do I need to buy"
+  PT: "gerar lista de compras da semana", "o que preciso comprar"
+- PLAN_MENU: User wants to plan a multi-course menu for an occasion
+  EN: "plan Christmas dinner for 8"
+  PT: "planejar a ceia de Natal para 8"
+- CONVERT_RECIPE: User wants a recipe adapted to a slow cooker or Instant Pot
+  EN: "convert #4 to Instant Pot"
+  PT: "converter a #4 para panela de pressão"
+- REMIX_RECIPE: User wants a version of a recipe adapted to a diet or goal
+  EN: "remix recipe #3 to be vegan", "make it gluten-free"
+  PT: "adaptar a receita #3 para ser vegana", "faz ela sem glúten"
+- FREEZER: User froze or ate portions of a recipe, or asks about their freezer
+  EN: "I froze 3 portions of recipe #7", "what's in my freezer I should eat"
+  PT: "congelei 3 porções da receita #7", "o que do freezer devo comer"
+- REQUEST_DISH: User asks someone in their household to cook a dish
+  EN: "someone please make lasagna this week"
+  PT: "alguém faz lasanha esta semana"
+- SAVE_FILTER: User wants to save the current search under a name
+  EN: "save this search as weeknight"
+  PT: "salvar esta busca como semana"
+- RUN_FILTER: User wants to re-run a search they saved by name
+  EN: "show my weeknight recipes"
+  PT: "mostrar minhas receitas de semana"
+- START_OVER: User wants to drop the current conversation and start fresh
+  EN: "start over", "forget that"
+  PT: "começar de novo", "esquece isso"
+- EXPORT_RECIPE: User wants one recipe exported to Notion, Obsidian, Crouton, AnyList or Whisk
+  EN: "export it to Notion"
+  PT: "exporta essa pro Notion"
+- ADD_TO_SHOPPING_LIST: User wants the ingredients of one recipe added to their shopping list
+  EN: "add its ingredients to my shopping list"
+  PT: "coloca os ingredientes dela na lista de compras"
+- TRANSLATE_RECIPE: User wants a recipe translated
+  EN: "translate it", "translate recipe 5 to Spanish"
+  PT: "traduz essa"
 - UNKNOWN: Cannot determine intent
 
 ## NEXT ACTIONS:
@@ -153,21 +289,43 @@ Pasta & Noodles, Rice & Grains, Soups & Stews, Salads, Meat & Poultry, Seafood,
 ## DIETARY TAGS:
 vegetarian, vegan, gluten-free, dairy-free, low-carb, quick, one-pot, kid-friendly
 
+## DIFFICULTY:
+easy, medium, hard ("easy"/"fácil" is a difficulty, not the "quick" tag)
+
 ## RESPONSE FORMAT - return ONLY valid JSON:
 {
   "intent": "INTENT_TYPE",
   "category": "category name in English or null",
   "dietaryTags": ["tag1", "tag2"] or [],
+  "difficulty": "easy|medium|hard or null",
   "ingredientFilter": {
     "include": ["ingredients that MUST be present"],
     "exclude": ["ingredients that must NOT be present"],
     "optional": ["any of these is fine"]
   } or null,
   "searchTerm": "for simple single-ingredient search or null",
+  "author": "for FILTER_AUTHOR - creator name or handle" or null,
+  "platform": "for FILTER_PLATFORM - tiktok, youtube, instagram or web" or null,
+  "flavor": "for FILTER_FLAVOR - spicy, sweet, umami-rich or tangy" or null,
   "ingredients": ["for MATCH_INGREDIENTS - what user HAS"] or [],
   "pantryAction": "SHOW|ADD|REMOVE|CLEAR or null",
   "pantryItems": ["items", "to", "add/remove"] or [],
   "recipeNumber": number or null,
+  "recipeName": "for SHOW_DETAILS - words naming a recipe from the results, as written" or null,
+  "occasion": "for PLAN_MENU - what the menu is for" or null,
+  "guests": number of people for PLAN_MENU or null,
+  "appliance": "for CONVERT_RECIPE - slow cooker or instant pot" or null,
+  "remixGoal": "for REMIX_RECIPE - the diet or goal in English, e.g. vegan, gluten-free" or null,
+  "freezerAction": "for FREEZER - SHOW|ADD|REMOVE|EAT_FIRST" or null,
+  "portions": number of portions for FREEZER or null,
+  "dish": "for REQUEST_DISH - the dish asked for, as written" or null,
+  "maxMinutes": time limit in minutes ("under 30 min") or null,
+  "savedPeriod": "for LIST_RECIPES - when the recipes were saved: today, yesterday, this/last week, this/last month, this/last year, last N days/weeks/months, a month name with an optional year, YYYY-MM-DD, YYYY-MM or YYYY" or null,
+  "perfectOnly": true when narrowing ingredient matches to perfect ones, else false,
+  "maxMissing": number of missing ingredients allowed in ingredient matches or null,
+  "filterName": "for SAVE_FILTER and RUN_FILTER - the saved search name" or null,
+  "exportFormat": "for EXPORT_RECIPE - notion, obsidian, crouton, anylist or whisk" or null,
+  "language": "for TRANSLATE_RECIPE - target language in English" or null,
   "nextAction": "EXECUTE|CLARIFY|REFINE",
   "clarifyingQuestion": "question to ask if nextAction is CLARIFY" or null,
   "clarifyingOptions": ["option1", "option2", "option3"] or [],
@@ -185,7 +343,6 @@ vegetarian, vegan, gluten-free, dairy-free, low-carb, quick, one-pot, kid-friend
 
 ## CLARIFICATION RULES:
 - Ask for clarification when the request is vague:
-  - "something spicy" -> unclear if they mean spicy ingredients or cuisine type
   - "quick dinner" -> could be any category
   - "I'm in the mood for..." -> too vague
 - Provide 2-4 specific options when clarifying
@@ -195,6 +352,14 @@ vegetarian, vegan, gluten-free, dairy-free, low-carb, quick, one-pot, kid-friend
 - If user says "any of those that are quick?" or "the quick ones" after seeing results -> REFINE with dietaryTags: ["quick"]
 - If user references "those", "them", "the ones" -> check history, set refersToLast: true
 - Refinement adds constraints to previous search, doesn't replace it
+- After ingredient match results, keep intent "MATCH_INGREDIENTS" with an empty "ingredients" array:
+  "only perfect matches", "só as perfeitas" -> REFINE with perfectOnly: true
+  "allow 2 missing items", "pode faltar 2 ingredientes" -> REFINE with maxMissing: 2
+  "only seafood", "só frutos do mar" -> REFINE with category: "Seafood"
+
+## RECIPE REFERENCES:
+- "it", "that", "this one", "essa", "ela", "dela" refer to the recipe the user opened last
+- For CONVERT_RECIPE, REMIX_RECIPE, FREEZER, EXPORT_RECIPE, ADD_TO_SHOPPING_LIST and TRANSLATE_RECIPE, set "recipeNumber" only when the user gives a number; otherwise leave it null to mean the last opened recipe
 
 ## EXAMPLES:
 
@@ -205,36 +370,125 @@ User: "quick pasta without dairy"
 -> intent: "COMPLEX_SEARCH", ingredientFilter: {include: ["pasta"], exclude: ["dairy", "milk", "cheese", "cream", "butter"], optional: []}, dietaryTags: ["quick"], nextAction: "EXECUTE"
 
 User: "I want something spicy"
--> intent: "UNKNOWN", nextAction: "CLARIFY", clarifyingQuestion: "What kind of spicy food are you looking for?", clarifyingOptions: ["Spicy Asian recipes", "Spicy Mexican food", "Any recipe with hot peppers", "Spicy seafood"]
+-> intent: "FILTER_FLAVOR", flavor: "spicy", nextAction: "EXECUTE"
+
+User: "I'm in the mood for something"
+-> intent: "UNKNOWN", nextAction: "CLARIFY", clarifyingQuestion: "What are you in the mood for?", clarifyingOptions: ["Something spicy", "Something sweet", "A quick dinner", "Something with chicken"]
+
+User: "easy recipes"
+-> intent: "COMPOUND_QUERY", difficulty: "easy", nextAction: "EXECUTE"
+
+User: "plan Christmas dinner for 8"
+-> intent: "PLAN_MENU", occasion: "Christmas dinner", guests: 8, nextAction: "EXECUTE"
+
+User: "convert #4 to Instant Pot"
+-> intent: "CONVERT_RECIPE", recipeNumber: 4, appliance: "instant pot", nextAction: "EXECUTE"
+
+User: "I froze 3 portions of recipe #7"
+-> intent: "FREEZER", freezerAction: "ADD", recipeNumber: 7, portions: 3, nextAction: "EXECUTE"
+
+User: "someone please make lasagna this week"
+-> intent: "REQUEST_DISH", dish: "lasagna", nextAction: "EXECUTE"
+
+User: "show me everything from @thatpastaguy"
+-> intent: "FILTER_AUTHOR", author: "@thatpastaguy", nextAction: "EXECUTE"
+
+User: "show my TikTok recipes"
+-> intent: "FILTER_PLATFORM", platform: "tiktok", nextAction: "EXECUTE"
+
+User: "what did I save in December"
+-> intent: "LIST_RECIPES", savedPeriod: "december", nextAction: "EXECUTE"
+
+User: "quick vegetarian under 30 min"
+-> intent: "COMPOUND_QUERY", category: "Vegetarian", dietaryTags: ["quick"], maxMinutes: 30, nextAction: "EXECUTE"
+
+User: "save this search as weeknight"
+-> intent: "SAVE_FILTER", filterName: "weeknight", nextAction: "EXECUTE"
+
+User: "show my weeknight recipes"
+-> intent: "RUN_FILTER", filterName: "weeknight", nextAction: "EXECUTE"
+
+(After showing recipes) User: "open the carbonara"
+-> intent: "SHOW_DETAILS", recipeName: "carbonara", nextAction: "EXECUTE"
+
+(After showing recipes) User: "the second salmon one"
+-> intent: "SHOW_DETAILS", recipeName: "salmon", recipeNumber: 2, nextAction: "EXECUTE"
+
+(After showing "Carbonara (pastaguy)" and "Carbonara (nonna)") User: "the carbonara from pastaguy"
+-> intent: "SHOW_DETAILS", recipeName: "carbonara from pastaguy", nextAction: "EXECUTE"
+
+(While asked to clarify) User: "never mind, start over"
+-> intent: "START_OVER", nextAction: "EXECUTE"
+
+(After opening a recipe) User: "export it to Notion"
+-> intent: "EXPORT_RECIPE", exportFormat: "notion", nextAction: "EXECUTE"
+
+(After opening a recipe) User: "add its ingredients to my shopping list"
+-> intent: "ADD_TO_SHOPPING_LIST", nextAction: "EXECUTE"
+
+(After opening a recipe) User: "traduz essa pro espanhol"
+-> intent: "TRANSLATE_RECIPE", language: "Spanish", nextAction: "EXECUTE"
+
+(After opening a recipe) User: "convert it to the slow cooker"
+-> intent: "CONVERT_RECIPE", appliance: "slow cooker", nextAction: "EXECUTE"
+
+User: "remix recipe #3 to be vegan"
+-> intent: "REMIX_RECIPE", recipeNumber: 3, remixGoal: "vegan", nextAction: "EXECUTE"
+
+(After opening a recipe) User: "make it gluten-free"
+-> intent: "REMIX_RECIPE", remixGoal: "gluten-free", nextAction: "EXECUTE"
 
 (After showing salmon recipes) User: "any quick ones?"
 -> intent: "COMPOUND_QUERY", dietaryTags: ["quick"], refersToLast: true, nextAction: "REFINE"`
 
 // IntentDetectorAdapter implements IntentDetector using LLM
 type IntentDetectorAdapter struct {
-	client *genai.Client
-	model  string
+	client      *genai.Client
+	model       string
+	temperature float32
+	experiment  *experimentRunner // nil unless an intent prompt experiment is running
+	prompts     *promptCache      // nil unless prompt caching is enabled
 }
 
 // NewIntentDetectorAdapter creates a new intent detector adapter
 func NewIntentDetectorAdapter(client *genai.Client, model string) *IntentDetectorAdapter {
 	return &IntentDetectorAdapter{
-		client: client,
-		model:  model,
+		client:      client,
+		model:       model,
+		temperature: 0.2, // Low temperature for deterministic output
 	}
 }
 
 // intentResponse represents the JSON response from the LLM
 type intentResponse struct {
-	Intent       string   `json:"intent"`
-	Category     *string  `json:"category"`
-	DietaryTags  []string `json:"dietaryTags"`
-	Ingredients  []string `json:"ingredients"`
-	SearchTerm   *string  `json:"searchTerm"`
-	PantryAction *string  `json:"pantryAction"`
-	PantryItems  []string `json:"pantryItems"`
-	RecipeNumber *int     `json:"recipeNumber"`
-	Confidence   float64  `json:"confidence"`
+	Intent        string   `json:"intent"`
+	Category      *string  `json:"category"`
+	DietaryTags   []string `json:"dietaryTags"`
+	Difficulty    *string  `json:"difficulty"`
+	Ingredients   []string `json:"ingredients"`
+	SearchTerm    *string  `json:"searchTerm"`
+	Author        *string  `json:"author"`
+	Platform      *string  `json:"platform"`
+	Flavor        *string  `json:"flavor"`
+	PantryAction  *string  `json:"pantryAction"`
+	PantryItems   []string `json:"pantryItems"`
+	RecipeNumber  *int     `json:"recipeNumber"`
+	RecipeName    *string  `json:"recipeName"`
+	Occasion      *string  `json:"occasion"`
+	Guests        *int     `json:"guests"`
+	Appliance     *string  `json:"appliance"`
+	RemixGoal     *string  `json:"remixGoal"`
+	FreezerAction *string  `json:"freezerAction"`
+	Portions      *int     `json:"portions"`
+	Dish          *string  `json:"dish"`
+	MaxMinutes    *int     `json:"maxMinutes"`
+	SavedPeriod   *string  `json:"savedPeriod"`
+	PerfectOnly   bool     `json:"perfectOnly"`
+	MaxMissing    *int     `json:"maxMissing"`
+	FilterName    *string  `json:"filterName"`
+	ExportFormat  *string  `json:"exportFormat"`
+	Language      *string  `json:"language"`
+	Confidence    float64  `json:"confidence"`
 
 	// New fields for context-aware intent detection
 	IngredientFilter   *ingredientFilterResponse `json:"ingredientFilter"`
@@ -251,23 +505,49 @@ type ingredientFilterResponse struct {
 	Optional []string `json:"optional"`
 }
 
-// DetectIntent implements the IntentDetector interface
-func (a *IntentDetectorAdapter) DetectIntent(ctx context.Context, text string) (*ports.Intent, error) {
-	model := a.client.GenerativeModel(a.model)
+// intentContextSections are the parts of IntentPromptWithContext that change with every message
+const intentContextSections = "## CONVERSATION HISTORY:\n%s\n\n## CURRENT MESSAGE:\n%s"
 
-	// Configure model for JSON output
-	model.SetTemperature(0.2) // Low temperature for deterministic output
-	model.ResponseMIMEType = "application/json"
+// intentContextInstructions is IntentPromptWithContext without the history and message,
+// the part that stays the same and can be cached
+var intentContextInstructions = strings.Replace(IntentPromptWithContext, intentContextSections+"\n\n", "", 1)
 
-	// Build the prompt
-	prompt := fmt.Sprintf("%s\n\nUser message: %s", IntentPrompt, text)
+// buildIntentPromptWithContext builds the context-aware prompt as its instructions and
+// the history and message sections that follow them. An alternate prompt from an
+// experiment replaces the instructions.
+func buildIntentPromptWithContext(alternate, history, text string) (instructions, message string) {
+	instructions = alternate
+	if instructions == "" {
+		instructions = intentContextInstructions
+	}
+	return instructions, fmt.Sprintf(intentContextSections, history, text)
+}
 
+// tagIntent records the outcome of a detection and tags the intent with its variant
+func (r *experimentRunner) tagIntent(choice variantChoice, intent *ports.Intent, err error) (*ports.Intent, error) {
+	r.record(choice.name, callOutcome(err))
+	if err != nil {
+		return nil, err
+	}
+	intent.Variant = choice.name
+	return intent, nil
+}
+
+// DetectIntent implements the IntentDetector interface
+func (a *IntentDetectorAdapter) DetectIntent(ctx context.Context, text string) (*ports.Intent, error) {
+	choice := a.experiment.pick(a.model, IntentPrompt)
+	intent, err := a.detectIntent(ctx, text, choice.model, choice.prompt)
+	return a.experiment.tagIntent(choice, intent, err)
+}
+
+// detectIntent detects the intent of a message with the given model and prompt
+func (a *IntentDetectorAdapter) detectIntent(ctx context.Context, text, modelName, intentPrompt string) (*ports.Intent, error) {
 	// Add timeout
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	// Generate content
-	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(prompt))
+	// Generate content, with the intent prompt from the cache when enabled
+	resp, err := a.prompts.generate(ctxWithTimeout, a.client, modelName, intentPrompt, "User message: "+text, a.configure)
 	if err != nil {
 		return nil, fmt.Errorf("intent detection failed: %w", err)
 	}
@@ -299,6 +579,12 @@ func (a *IntentDetectorAdapter) DetectIntent(ctx context.Context, text string) (
 	return intent, nil
 }
 
+// configure sets the model up for deterministic JSON output
+func (a *IntentDetectorAdapter) configure(model *genai.GenerativeModel) {
+	model.SetTemperature(a.temperature)
+	model.ResponseMIMEType = "application/json"
+}
+
 // cleanIntentResponse removes markdown code blocks and extra text
 func cleanIntentResponse(response string) string {
 	// Remove markdown code blocks
@@ -340,21 +626,106 @@ func convertToIntent(resp *intentResponse, rawText string) *ports.Intent {
 		intent.DietaryTags = recipe.ParseDietaryTags(resp.DietaryTags)
 	}
 
+	// Handle difficulty
+	if resp.Difficulty != nil {
+		if difficulty, ok := recipe.ParseDifficulty(*resp.Difficulty); ok {
+			intent.Difficulty = &difficulty
+		}
+	}
+
 	// Handle search term
 	if resp.SearchTerm != nil && *resp.SearchTerm != "" {
 		intent.SearchTerm = *resp.SearchTerm
 	}
 
+	// Handle author for FILTER_AUTHOR
+	if resp.Author != nil && *resp.Author != "" {
+		intent.Author = *resp.Author
+	}
+
+	// Handle platform for FILTER_PLATFORM
+	if resp.Platform != nil && *resp.Platform != "" {
+		intent.Platform = *resp.Platform
+	}
+
+	// Handle flavor for FILTER_FLAVOR
+	if resp.Flavor != nil && *resp.Flavor != "" {
+		intent.Flavor = *resp.Flavor
+	}
+
 	// Handle pantry action
 	if resp.PantryAction != nil && *resp.PantryAction != "" {
 		intent.PantryAction = parsePantryAction(*resp.PantryAction)
 		intent.PantryItems = resp.PantryItems
 	}
 
-	// Handle recipe number for SHOW_DETAILS
+	// Handle recipe number and name for SHOW_DETAILS
 	if resp.RecipeNumber != nil && *resp.RecipeNumber > 0 {
 		intent.RecipeNumber = *resp.RecipeNumber
 	}
+	if resp.RecipeName != nil {
+		intent.RecipeName = strings.TrimSpace(*resp.RecipeName)
+	}
+
+	// Handle occasion and guests for PLAN_MENU
+	if resp.Occasion != nil && *resp.Occasion != "" {
+		intent.Occasion = *resp.Occasion
+	}
+	if resp.Guests != nil && *resp.Guests > 0 {
+		intent.Guests = *resp.Guests
+	}
+
+	// Handle appliance for CONVERT_RECIPE
+	if resp.Appliance != nil {
+		intent.Appliance = *resp.Appliance
+	}
+
+	// Handle goal for REMIX_RECIPE
+	if resp.RemixGoal != nil {
+		intent.RemixGoal = strings.TrimSpace(*resp.RemixGoal)
+	}
+
+	// Handle format for EXPORT_RECIPE and language for TRANSLATE_RECIPE
+	if resp.ExportFormat != nil {
+		intent.ExportFormat = strings.TrimSpace(*resp.ExportFormat)
+	}
+	if resp.Language != nil {
+		intent.Language = strings.TrimSpace(*resp.Language)
+	}
+
+	// Handle action and portions for FREEZER
+	if resp.FreezerAction != nil && *resp.FreezerAction != "" {
+		intent.FreezerAction = parseFreezerAction(*resp.FreezerAction)
+	}
+	if resp.Portions != nil && *resp.Portions > 0 {
+		intent.Portions = *resp.Portions
+	}
+
+	// Handle dish for REQUEST_DISH
+	if resp.Dish != nil && *resp.Dish != "" {
+		intent.Dish = strings.TrimSpace(*resp.Dish)
+	}
+
+	// Handle time limit
+	if resp.MaxMinutes != nil && *resp.MaxMinutes > 0 {
+		intent.MaxMinutes = *resp.MaxMinutes
+	}
+
+	// Handle saved period for LIST_RECIPES
+	if resp.SavedPeriod != nil && *resp.SavedPeriod != "" {
+		intent.SavedPeriod = *resp.SavedPeriod
+	}
+
+	// Handle ingredient match refinements
+	intent.PerfectOnly = resp.PerfectOnly
+	if resp.MaxMissing != nil && *resp.MaxMissing > 0 {
+		intent.MaxMissing = *resp.MaxMissing
+	}
+
+	// Handle saved search name for SAVE_FILTER and RUN_FILTER
+	if resp.FilterName != nil && *resp.FilterName != "" {
+		intent.FilterName = *resp.FilterName
+	}
 
 	// Handle ingredient filter for COMPLEX_SEARCH
 	if resp.IngredientFilter != nil {
@@ -399,6 +770,16 @@ func parseIntentType(s string) ports.IntentType {
 		return ports.IntentMatchIngredients
 	case "SHOW_CATEGORIES":
 		return ports.IntentShowCategories
+	case "FILTER_AUTHOR":
+		return ports.IntentFilterAuthor
+	case "SHOW_AUTHORS":
+		return ports.IntentShowAuthors
+	case "FILTER_PLATFORM":
+		return ports.IntentFilterPlatform
+	case "SHOW_PLATFORMS":
+		return ports.IntentShowPlatforms
+	case "FILTER_FLAVOR":
+		return ports.IntentFilterFlavor
 	case "MANAGE_PANTRY":
 		return ports.IntentManagePantry
 	case "HELP":
@@ -415,6 +796,30 @@ func parseIntentType(s string) ports.IntentType {
 		return ports.IntentCompoundQuery
 	case "COMPLEX_SEARCH":
 		return ports.IntentComplexSearch
+	case "SHOPPING_LIST":
+		return ports.IntentShoppingList
+	case "PLAN_MENU":
+		return ports.IntentPlanMenu
+	case "CONVERT_RECIPE":
+		return ports.IntentConvertRecipe
+	case "REMIX_RECIPE":
+		return ports.IntentRemixRecipe
+	case "FREEZER":
+		return ports.IntentFreezer
+	case "REQUEST_DISH":
+		return ports.IntentRequestDish
+	case "SAVE_FILTER":
+		return ports.IntentSaveFilter
+	case "RUN_FILTER":
+		return ports.IntentRunFilter
+	case "START_OVER":
+		return ports.IntentStartOver
+	case "EXPORT_RECIPE":
+		return ports.IntentExportRecipe
+	case "ADD_TO_SHOPPING_LIST":
+		return ports.IntentAddToShoppingList
+	case "TRANSLATE_RECIPE":
+		return ports.IntentTranslateRecipe
 	default:
 		return ports.IntentUnknown
 	}
@@ -434,6 +839,20 @@ func parsePantryAction(s string) ports.PantryAction {
 	}
 }
 
+// parseFreezerAction converts a string to FreezerAction
+func parseFreezerAction(s string) ports.FreezerAction {
+	switch strings.ToUpper(s) {
+	case "ADD":
+		return ports.FreezerActionAdd
+	case "REMOVE":
+		return ports.FreezerActionRemove
+	case "EAT_FIRST":
+		return ports.FreezerActionEatFirst
+	default:
+		return ports.FreezerActionShow
+	}
+}
+
 // parseConversationAction converts a string to ConversationAction
 func parseConversationAction(s string) ports.ConversationAction {
 	switch strings.ToUpper(s) {
@@ -459,6 +878,8 @@ func formatHistoryForPrompt(history []ports.ConversationTurn) string {
 			role = "User"
 		} else if role == "assistant" {
 			role = "Assistant"
+		} else if role == ports.RoleSummary {
+			role = "Summary of the earlier conversation"
 		}
 		sb.WriteString(fmt.Sprintf("%s: %s\n", role, turn.Content))
 	}
@@ -467,22 +888,24 @@ func formatHistoryForPrompt(history []ports.ConversationTurn) string {
 
 // DetectIntentWithContext implements context-aware intent detection with conversation history
 func (a *IntentDetectorAdapter) DetectIntentWithContext(ctx context.Context, text string, history []ports.ConversationTurn) (*ports.Intent, error) {
-	model := a.client.GenerativeModel(a.model)
-
-	// Configure model for JSON output
-	model.SetTemperature(0.2) // Low temperature for deterministic output
-	model.ResponseMIMEType = "application/json"
+	choice := a.experiment.pick(a.model, "")
+	intent, err := a.detectIntentWithContext(ctx, text, history, choice.model, choice.prompt)
+	return a.experiment.tagIntent(choice, intent, err)
+}
 
+// detectIntentWithContext detects the intent of a message in context with the given model,
+// using the alternate prompt of an experiment if set
+func (a *IntentDetectorAdapter) detectIntentWithContext(ctx context.Context, text string, history []ports.ConversationTurn, modelName, alternate string) (*ports.Intent, error) {
 	// Format history and build the prompt
 	historyStr := formatHistoryForPrompt(history)
-	prompt := fmt.Sprintf(IntentPromptWithContext, historyStr, text)
+	instructions, message := buildIntentPromptWithContext(alternate, historyStr, text)
 
 	// Add timeout
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	// Generate content
-	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(prompt))
+	// Generate content, with the instructions from the cache when enabled
+	resp, err := a.prompts.generate(ctxWithTimeout, a.client, modelName, instructions, message, a.configure)
 	if err != nil {
 		return nil, fmt.Errorf("intent detection with context failed: %w", err)
 	}
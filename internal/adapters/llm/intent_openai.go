@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/ports"
+)
+
+// Intent detection waits longer for local models, which may run on modest hardware
+const (
+	intentTimeout      = 30 * time.Second
+	localIntentTimeout = 2 * time.Minute
+)
+
+// OpenAIIntentDetector implements the IntentDetector interface with OpenAI or an
+// OpenAI-compatible local server, using the same prompts as the Gemini detector
+type OpenAIIntentDetector struct {
+	llm         *OpenAIAdapter
+	model       string
+	temperature float32
+	experiment  *experimentRunner // nil unless an intent prompt experiment is running
+}
+
+// NewOpenAIIntentDetector creates an intent detector that calls the adapter's client
+func NewOpenAIIntentDetector(llm *OpenAIAdapter, model string) *OpenAIIntentDetector {
+	return &OpenAIIntentDetector{
+		llm:         llm,
+		model:       model,
+		temperature: 0.2, // Low temperature for deterministic output
+	}
+}
+
+// DetectIntent implements the IntentDetector interface
+func (d *OpenAIIntentDetector) DetectIntent(ctx context.Context, text string) (*ports.Intent, error) {
+	choice := d.experiment.pick(d.model, IntentPrompt)
+	prompt := fmt.Sprintf("%s\n\nUser message: %s", choice.prompt, text)
+	intent, err := d.detect(ctx, prompt, text, choice.model)
+	return d.experiment.tagIntent(choice, intent, err)
+}
+
+// DetectIntentWithContext implements context-aware intent detection with conversation history
+func (d *OpenAIIntentDetector) DetectIntentWithContext(ctx context.Context, text string, history []ports.ConversationTurn) (*ports.Intent, error) {
+	choice := d.experiment.pick(d.model, "")
+	instructions, message := buildIntentPromptWithContext(choice.prompt, formatHistoryForPrompt(history), text)
+	intent, err := d.detect(ctx, instructions+"\n\n"+message, text, choice.model)
+	return d.experiment.tagIntent(choice, intent, err)
+}
+
+// detect sends an intent prompt to the model and parses its answer
+func (d *OpenAIIntentDetector) detect(ctx context.Context, prompt, text, modelName string) (*ports.Intent, error) {
+	req := openai.ChatCompletionRequest{
+		Model: modelName,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: prompt},
+		},
+		Temperature: d.temperature,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	timeout := intentTimeout
+	if d.llm.local {
+		timeout = localIntentTimeout
+	}
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	responseText, err := d.llm.completeJSON(ctxWithTimeout, req)
+	if err != nil {
+		return nil, fmt.Errorf("intent detection failed: %w", classifyAPIError(err))
+	}
+
+	var intentResp intentResponse
+	if err := json.Unmarshal([]byte(cleanIntentResponse(responseText)), &intentResp); err != nil {
+		return nil, fmt.Errorf("failed to parse intent response: %w", err)
+	}
+
+	return convertToIntent(&intentResp, text), nil
+}
@@ -0,0 +1,161 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/domain/menu"
+	"receipt-bot/internal/ports"
+)
+
+// MenuPrompt asks the LLM to suggest dishes for the courses of a menu
+const MenuPrompt = `Suggest dishes for a menu.
+
+Occasion: %s
+Guests: %d
+Courses to fill: %s
+
+Rules:
+- Suggest 2 dishes for each course, suited to the occasion
+- Write dish titles in %s
+- "course" must be one of: appetizer, main, side, dessert
+- "appliances" lists what the dish is cooked in: "oven", "stove", or [] for no cooking
+- Give realistic prep and cook times in minutes
+
+Return ONLY valid JSON in this exact format:
+{"dishes": [{"course": "main", "title": "Roast Turkey", "prep_time_minutes": 30, "cook_time_minutes": 180, "servings": 8, "appliances": ["oven"]}]}`
+
+// buildMenuPrompt builds the menu suggestion prompt
+func buildMenuPrompt(occasion string, courses []menu.Course, guests int, targetLang string) string {
+	names := make([]string, len(courses))
+	for i, course := range courses {
+		names[i] = course.String()
+	}
+	return fmt.Sprintf(MenuPrompt, occasion, guests, strings.Join(names, ", "), targetLang)
+}
+
+// parseMenuResponse parses the LLM answer, keeping dishes for the requested courses only
+func parseMenuResponse(response string, courses []menu.Course) ([]ports.DishSuggestion, error) {
+	var raw struct {
+		Dishes []struct {
+			Course          string   `json:"course"`
+			Title           string   `json:"title"`
+			PrepTimeMinutes *int     `json:"prep_time_minutes"`
+			CookTimeMinutes *int     `json:"cook_time_minutes"`
+			Servings        *int     `json:"servings"`
+			Appliances      []string `json:"appliances"`
+		} `json:"dishes"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse menu suggestions: %w", err)
+	}
+
+	wanted := make(map[menu.Course]bool, len(courses))
+	for _, course := range courses {
+		wanted[course] = true
+	}
+
+	var dishes []ports.DishSuggestion
+	for _, d := range raw.Dishes {
+		course, ok := menu.ParseCourse(d.Course)
+		title := strings.TrimSpace(d.Title)
+		if !ok || !wanted[course] || title == "" {
+			continue
+		}
+
+		dish := ports.DishSuggestion{
+			Course:   course,
+			Title:    title,
+			PrepTime: minutesToDuration(d.PrepTimeMinutes),
+			CookTime: minutesToDuration(d.CookTimeMinutes),
+			Servings: d.Servings,
+		}
+		for _, a := range d.Appliances {
+			switch appliance := menu.Appliance(strings.ToLower(strings.TrimSpace(a))); appliance {
+			case menu.ApplianceOven, menu.ApplianceStove:
+				dish.Appliances = append(dish.Appliances, appliance)
+			}
+		}
+		dishes = append(dishes, dish)
+	}
+
+	return dishes, nil
+}
+
+// minutesToDuration converts an optional number of minutes, ignoring non-positive values
+func minutesToDuration(minutes *int) *time.Duration {
+	if minutes == nil || *minutes <= 0 {
+		return nil
+	}
+	d := time.Duration(*minutes) * time.Minute
+	return &d
+}
+
+// SuggestDishes implements the MenuSuggester interface
+func (a *GeminiAdapter) SuggestDishes(ctx context.Context, occasion string, courses []menu.Course, guests int, targetLang string) ([]ports.DishSuggestion, error) {
+	if len(courses) == 0 {
+		return nil, nil
+	}
+
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(0.7)
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(buildMenuPrompt(occasion, courses, guests, targetLang)))
+	if err != nil {
+		return nil, fmt.Errorf("menu suggestion failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini for menu suggestion")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseMenuResponse(responseText, courses)
+}
+
+// SuggestDishes implements the MenuSuggester interface
+func (a *OpenAIAdapter) SuggestDishes(ctx context.Context, occasion string, courses []menu.Course, guests int, targetLang string) ([]ports.DishSuggestion, error) {
+	if len(courses) == 0 {
+		return nil, nil
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: buildMenuPrompt(occasion, courses, guests, targetLang),
+			},
+		},
+		Temperature: 0.7,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("menu suggestion failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI for menu suggestion")
+	}
+
+	return parseMenuResponse(resp.Choices[0].Message.Content, courses)
+}
@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/ports"
+)
+
+// maxModerationChars caps the recipe text sent for moderation
+const maxModerationChars = 8000
+
+// ModeratePrompt asks the LLM whether a recipe is safe to share with other people
+const ModeratePrompt = `You review recipes before they are shared with other people. Judge the recipe below.
+
+Recipe:
+%s
+
+Answer "blocked" if it:
+- Describes making drugs, poisons, weapons or anything meant to harm someone
+- Contains hate speech, harassment, sexual content or graphic violence
+- Is spam, an advertisement or a scam rather than a recipe
+
+Answer "review" if you are unsure, for example:
+- Dangerous food preparation (raw foraged mushrooms, home canning without safety steps, alcohol for children)
+- Links or contact details that look like promotion
+
+Answer "allowed" for any ordinary recipe, whatever its cuisine or diet.
+
+Return ONLY valid JSON in this exact format:
+{"verdict": "allowed", "reason": "short reason, empty when allowed"}`
+
+// buildModeratePrompt builds the moderation prompt for the recipe text
+func buildModeratePrompt(text string) string {
+	if len(text) > maxModerationChars {
+		text = strings.ToValidUTF8(text[:maxModerationChars], "")
+	}
+	return fmt.Sprintf(ModeratePrompt, text)
+}
+
+// parseModerateResponse parses the LLM answer; unknown verdicts are held for review
+func parseModerateResponse(response string) (*ports.ModerationResult, error) {
+	var raw struct {
+		Verdict string `json:"verdict"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse moderation verdict: %w", err)
+	}
+
+	return &ports.ModerationResult{
+		Verdict: moderation.ParseVerdict(raw.Verdict),
+		Reason:  strings.TrimSpace(raw.Reason),
+	}, nil
+}
+
+// ModerateRecipe implements the ContentModerator interface
+func (a *GeminiAdapter) ModerateRecipe(ctx context.Context, text string) (*ports.ModerationResult, error) {
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(0)
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(buildModeratePrompt(text)))
+	if err != nil {
+		return nil, fmt.Errorf("recipe moderation failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini for recipe moderation")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseModerateResponse(responseText)
+}
+
+// ModerateRecipe implements the ContentModerator interface
+func (a *OpenAIAdapter) ModerateRecipe(ctx context.Context, text string) (*ports.ModerationResult, error) {
+	req := openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: buildModeratePrompt(text),
+			},
+		},
+		Temperature: 0,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("recipe moderation failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI for recipe moderation")
+	}
+
+	return parseModerateResponse(resp.Choices[0].Message.Content)
+}
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultOllamaURL is the OpenAI-compatible endpoint of a local Ollama server
+const DefaultOllamaURL = "http://localhost:11434/v1"
+
+// LocalJSONPrompt is added to the system prompt of local models, which stray from
+// the requested format more often than the hosted ones
+const LocalJSONPrompt = `Answer with one JSON object and nothing else:
+- No explanations before or after it, and no markdown code fences
+- Use exactly the keys of the requested format, in English, even when the content is in another language
+- Use null for unknown values instead of leaving keys out or inventing values
+- Numbers are plain numbers, without units or quotes`
+
+// JSONRetryPrompt asks a local model to fix an answer that was not valid JSON
+const JSONRetryPrompt = `Your answer was not valid JSON. Reply again with only the JSON object, following the format exactly.`
+
+// errNoChoices is returned when a chat completion has no answer
+var errNoChoices = errors.New("no response from the model")
+
+// NewOllamaAdapter creates an adapter for a local model served by Ollama, or by any
+// OpenAI-compatible server such as LM Studio or llama.cpp, so recipes never leave the
+// machine. The API key is optional, for servers behind an authenticating proxy.
+func NewOllamaAdapter(baseURL, apiKey, model string) (*OpenAIAdapter, error) {
+	if baseURL == "" {
+		baseURL = DefaultOllamaURL
+	}
+	if model == "" {
+		model = DefaultModel("ollama")
+	}
+
+	config := openai.DefaultConfig(apiKey)
+	config.BaseURL = baseURL
+
+	return &OpenAIAdapter{
+		client: openai.NewClientWithConfig(config),
+		model:  model,
+		local:  true,
+	}, nil
+}
+
+// completeJSON sends a chat completion that must answer with a JSON object. Local
+// models are reminded of the format, their answer is stripped of code fences and
+// surrounding text, and they are asked once more when it still isn't valid JSON.
+func (a *OpenAIAdapter) completeJSON(ctx context.Context, req openai.ChatCompletionRequest) (string, error) {
+	if a.local {
+		req.Messages = withJSONReminder(req.Messages)
+	}
+
+	answer, err := a.complete(ctx, req)
+	if err != nil || !a.local {
+		return answer, err
+	}
+
+	cleaned := cleanJSONResponse(answer)
+	if json.Valid([]byte(cleaned)) {
+		return cleaned, nil
+	}
+
+	req.Messages = append(req.Messages,
+		openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: answer},
+		openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: JSONRetryPrompt},
+	)
+	answer, err = a.complete(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("retrying after invalid JSON: %w", err)
+	}
+	return cleanJSONResponse(answer), nil
+}
+
+// withJSONReminder adds LocalJSONPrompt to the system prompt, or starts one with it
+func withJSONReminder(messages []openai.ChatCompletionMessage) []openai.ChatCompletionMessage {
+	if len(messages) > 0 && messages[0].Role == openai.ChatMessageRoleSystem {
+		reminded := append([]openai.ChatCompletionMessage(nil), messages...)
+		reminded[0].Content += "\n\n" + LocalJSONPrompt
+		return reminded
+	}
+	return append([]openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: LocalJSONPrompt},
+	}, messages...)
+}
+
+// complete sends a chat completion and returns its first answer
+func (a *OpenAIAdapter) complete(ctx context.Context, req openai.ChatCompletionRequest) (string, error) {
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	recordOpenAIUsage(ctx, resp)
+
+	if len(resp.Choices) == 0 {
+		return "", errNoChoices
+	}
+	return resp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,151 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// fakeChatServer is an OpenAI-compatible server answering chat completions with
+// canned answers, in order, and recording the requests it got
+type fakeChatServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	answers  []string
+	requests []openai.ChatCompletionRequest
+}
+
+func newFakeChatServer(t *testing.T, answers ...string) *fakeChatServer {
+	t.Helper()
+	f := &fakeChatServer{answers: answers}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/chat/completions") {
+			http.NotFound(w, r)
+			return
+		}
+		var req openai.ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		f.mu.Lock()
+		f.requests = append(f.requests, req)
+		answer := ""
+		if len(f.answers) > 0 {
+			answer, f.answers = f.answers[0], f.answers[1:]
+		}
+		f.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+			Model: req.Model,
+			Choices: []openai.ChatCompletionChoice{{
+				Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: answer},
+			}},
+		})
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// Requests returns the chat completions requested so far
+func (f *fakeChatServer) Requests() []openai.ChatCompletionRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]openai.ChatCompletionRequest(nil), f.requests...)
+}
+
+func jsonRequest() openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
+		Model: "llama3.1",
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: "Extract the recipe."},
+			{Role: openai.ChatMessageRoleUser, Content: "Pancakes: flour, milk, eggs"},
+		},
+	}
+}
+
+func TestOpenAIAdapter_CompleteJSON_Local(t *testing.T) {
+	server := newFakeChatServer(t, "Here it is:\n```json\n{\"title\": \"Pancakes\"}\n```\nEnjoy!")
+	adapter, err := NewOllamaAdapter(server.URL, "", "")
+	if err != nil {
+		t.Fatalf("NewOllamaAdapter() error = %v", err)
+	}
+
+	got, err := adapter.completeJSON(context.Background(), jsonRequest())
+	if err != nil {
+		t.Fatalf("completeJSON() error = %v", err)
+	}
+	if got != `{"title": "Pancakes"}` {
+		t.Errorf("completeJSON() = %q, want the JSON object alone", got)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	if system := requests[0].Messages[0].Content; !strings.Contains(system, LocalJSONPrompt) || !strings.HasPrefix(system, "Extract the recipe.") {
+		t.Errorf("system prompt = %q, want the JSON reminder added to it", system)
+	}
+}
+
+func TestOpenAIAdapter_CompleteJSON_RetriesInvalidJSON(t *testing.T) {
+	server := newFakeChatServer(t, "Sorry, I am not sure what you mean.", `{"title": "Pancakes"}`)
+	adapter, _ := NewOllamaAdapter(server.URL, "", "")
+
+	got, err := adapter.completeJSON(context.Background(), jsonRequest())
+	if err != nil {
+		t.Fatalf("completeJSON() error = %v", err)
+	}
+	if got != `{"title": "Pancakes"}` {
+		t.Errorf("completeJSON() = %q, want the retried answer", got)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want a retry", len(requests))
+	}
+	retry := requests[1].Messages
+	if len(retry) != 4 {
+		t.Fatalf("retry has %d messages, want the first answer and the reminder appended", len(retry))
+	}
+	if retry[2].Role != openai.ChatMessageRoleAssistant || retry[2].Content != "Sorry, I am not sure what you mean." {
+		t.Errorf("retry message 3 = %+v, want the invalid answer", retry[2])
+	}
+	if retry[3].Role != openai.ChatMessageRoleUser || retry[3].Content != JSONRetryPrompt {
+		t.Errorf("retry message 4 = %+v, want JSONRetryPrompt", retry[3])
+	}
+}
+
+func TestOpenAIAdapter_CompleteJSON_Hosted(t *testing.T) {
+	server := newFakeChatServer(t, `{"title": "Pancakes"}`)
+	config := openai.DefaultConfig("key")
+	config.BaseURL = server.URL
+	adapter := &OpenAIAdapter{client: openai.NewClientWithConfig(config), model: "gpt-4o-mini"}
+
+	if _, err := adapter.completeJSON(context.Background(), jsonRequest()); err != nil {
+		t.Fatalf("completeJSON() error = %v", err)
+	}
+	requests := server.Requests()
+	if len(requests) != 1 || strings.Contains(requests[0].Messages[0].Content, LocalJSONPrompt) {
+		t.Errorf("hosted models got %d requests, want one without the local JSON reminder", len(requests))
+	}
+}
+
+func TestWithJSONReminder_NoSystemPrompt(t *testing.T) {
+	messages := []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}}
+	got := withJSONReminder(messages)
+	if len(got) != 2 || got[0].Role != openai.ChatMessageRoleSystem || got[0].Content != LocalJSONPrompt {
+		t.Errorf("withJSONReminder() = %+v, want a system prompt with the reminder first", got)
+	}
+	if len(messages) != 1 {
+		t.Error("withJSONReminder() changed the messages it was given")
+	}
+}
@@ -11,8 +11,13 @@ import (
 
 // OpenAIAdapter implements the LLMPort using OpenAI
 type OpenAIAdapter struct {
-	client *openai.Client
-	model  string
+	client     *openai.Client
+	model      string
+	extraction *experimentRunner // nil unless an extraction prompt experiment is running
+	extract    Task
+	translate  Task
+	local      bool // a local model, see NewOllamaAdapter
+	version    promptVersion
 }
 
 // NewOpenAIAdapter creates a new OpenAI adapter
@@ -22,7 +27,7 @@ func NewOpenAIAdapter(apiKey string, model string) (*OpenAIAdapter, error) {
 	}
 
 	if model == "" {
-		model = "gpt-4o-mini" // Default to cost-effective model
+		model = DefaultModel("openai")
 	}
 
 	client := openai.NewClient(apiKey)
@@ -33,13 +38,32 @@ func NewOpenAIAdapter(apiKey string, model string) (*OpenAIAdapter, error) {
 	}, nil
 }
 
+// SetPromptVersion implements the PromptVersioned interface
+func (a *OpenAIAdapter) SetPromptVersion(version string) error {
+	return a.version.set(version)
+}
+
 // ExtractRecipe implements the LLMPort interface
 func (a *OpenAIAdapter) ExtractRecipe(ctx context.Context, text string) (*ports.RecipeExtraction, error) {
+	choice := a.extraction.pick(a.extract.model(a.model), a.version.extraction())
+
+	extraction, err := a.extractRecipe(ctx, text, choice.model, choice.prompt)
+	a.extraction.record(choice.name, extractionOutcome(extraction, err))
+	if err != nil {
+		return nil, err
+	}
+
+	extraction.Variant = choice.name
+	return extraction, nil
+}
+
+// extractRecipe extracts a recipe with the given model and system prompt
+func (a *OpenAIAdapter) extractRecipe(ctx context.Context, text, model, systemPrompt string) (*ports.RecipeExtraction, error) {
 	// Build messages
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
-			Content: SystemPrompt,
+			Content: systemPrompt,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
@@ -49,31 +73,24 @@ func (a *OpenAIAdapter) ExtractRecipe(ctx context.Context, text string) (*ports.
 
 	// Create request with JSON mode
 	req := openai.ChatCompletionRequest{
-		Model:       a.model,
+		Model:       model,
 		Messages:    messages,
-		Temperature: 0.3,
+		Temperature: a.extract.temperature(0.3),
 		ResponseFormat: &openai.ChatCompletionResponseFormat{
 			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
 		},
 	}
 
 	// Call OpenAI API
-	resp, err := a.client.CreateChatCompletion(ctx, req)
+	responseText, err := a.completeJSON(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("OpenAI API call failed: %w", err)
-	}
-
-	// Extract response
-	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI")
+		return nil, fmt.Errorf("OpenAI API call failed: %w", classifyAPIError(err))
 	}
 
-	responseText := resp.Choices[0].Message.Content
-
 	// Parse JSON response
 	var recipeJSON recipeJSON
 	if err := json.Unmarshal([]byte(responseText), &recipeJSON); err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAI response as JSON: %w", err)
+		return nil, &ports.LLMResponseError{Response: responseText, Err: fmt.Errorf("failed to parse OpenAI response as JSON: %w", err)}
 	}
 
 	// Convert to domain format
@@ -144,25 +161,19 @@ IMPORTANT:
 	}
 
 	req := openai.ChatCompletionRequest{
-		Model:       a.model,
+		Model:       a.translate.model(a.model),
 		Messages:    messages,
-		Temperature: 0.3,
+		Temperature: a.translate.temperature(0.3),
 		ResponseFormat: &openai.ChatCompletionResponseFormat{
 			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
 		},
 	}
 
-	resp, err := a.client.CreateChatCompletion(ctx, req)
+	responseText, err := a.completeJSON(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("translation failed: %w", err)
 	}
 
-	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI for translation")
-	}
-
-	responseText := resp.Choices[0].Message.Content
-
 	var translationResp struct {
 		Title        string            `json:"title"`
 		Ingredients  []ingredientJSON  `json:"ingredients"`
@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+)
+
+// PantryPhotoPrompt asks the LLM to list the food items in a photo of a fridge or pantry shelf
+const PantryPhotoPrompt = `The image is a photo of a fridge, pantry shelf or kitchen counter.
+
+List the food items you can recognize.
+
+Rules:
+- Write item names in %s, lowercase and singular, as a shopping list would (e.g. "egg", "milk", "cheddar cheese")
+- Name the food, not the packaging: "tomato sauce", not "jar"
+- Leave out anything you are not sure about, and non-food items
+- List each item once
+
+Return ONLY valid JSON in this exact format:
+{"items": ["egg", "milk"]}`
+
+// parsePantryPhotoResponse parses the LLM answer, dropping empty and repeated items
+func parsePantryPhotoResponse(response string) ([]string, error) {
+	var raw struct {
+		Items []string `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse pantry items: %w", err)
+	}
+
+	seen := make(map[string]bool, len(raw.Items))
+	items := make([]string, 0, len(raw.Items))
+	for _, item := range raw.Items {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// RecognizeItems implements the PantryRecognizer interface
+func (a *GeminiAdapter) RecognizeItems(ctx context.Context, image []byte, targetLang string) ([]string, error) {
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(0.2)
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout,
+		genai.ImageData(imageFormat(image), image),
+		genai.Text(fmt.Sprintf(PantryPhotoPrompt, targetLang)))
+	if err != nil {
+		return nil, fmt.Errorf("pantry photo recognition failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini for pantry photo")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parsePantryPhotoResponse(responseText)
+}
+
+// RecognizeItems implements the PantryRecognizer interface
+func (a *OpenAIAdapter) RecognizeItems(ctx context.Context, image []byte, targetLang string) ([]string, error) {
+	req := openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role: openai.ChatMessageRoleUser,
+				MultiContent: []openai.ChatMessagePart{
+					{
+						Type:     openai.ChatMessagePartTypeImageURL,
+						ImageURL: &openai.ChatMessageImageURL{URL: imageDataURL(image)},
+					},
+					{
+						Type: openai.ChatMessagePartTypeText,
+						Text: fmt.Sprintf(PantryPhotoPrompt, targetLang),
+					},
+				},
+			},
+		},
+		Temperature: 0.2,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("pantry photo recognition failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI for pantry photo")
+	}
+
+	return parsePantryPhotoResponse(resp.Choices[0].Message.Content)
+}
@@ -0,0 +1,186 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// promptCacheRefresh is how long before a cached prompt expires that its TTL is extended
+const promptCacheRefresh = 5 * time.Minute
+
+// promptCache keeps the static instructions of Gemini calls in Gemini's context
+// cache, so large prompts like SystemPrompt are stored once rather than resent and
+// billed in full with every call. A cache is keyed by model and prompt text, so an
+// edited prompt or the alternate prompt of an experiment gets a cache of its own
+// while the old one runs out its TTL. A prompt Gemini won't cache, e.g. one under
+// the model's minimum cacheable size, is sent inline and not tried again until the
+// TTL passes. A nil cache always sends prompts inline.
+type promptCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex // guards entries, not the calls that fill them
+	entries map[string]*cachedPrompt
+}
+
+// cachedPrompt is the cache of one prompt
+type cachedPrompt struct {
+	// mu is held while the cache is created or extended, so calls with other
+	// prompts don't wait on Gemini
+	mu      sync.Mutex
+	content *genai.CachedContent // nil when the prompt couldn't be cached
+	expires time.Time            // when the cache ends, or when to try caching again; zero before the first try
+}
+
+// geminiCaches is the part of the Gemini client that manages cached content
+type geminiCaches interface {
+	CreateCachedContent(ctx context.Context, cc *genai.CachedContent) (*genai.CachedContent, error)
+	UpdateCachedContent(ctx context.Context, cc *genai.CachedContent, ccu *genai.CachedContentToUpdate) (*genai.CachedContent, error)
+	DeleteCachedContent(ctx context.Context, name string) error
+}
+
+// newPromptCache creates a cache whose prompts live for ttl, or nil if ttl isn't positive
+func newPromptCache(ttl time.Duration) *promptCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &promptCache{ttl: ttl, now: time.Now, entries: make(map[string]*cachedPrompt)}
+}
+
+// generate sends text to the model with prompt as its instructions, from the cache
+// when it can. configure sets the temperature and output format of the model.
+func (c *promptCache) generate(ctx context.Context, client *genai.Client, modelName, prompt, text string, configure func(*genai.GenerativeModel)) (*genai.GenerateContentResponse, error) {
+	return c.send(ctx, client, modelName, prompt, func(content *genai.CachedContent) (*genai.GenerateContentResponse, error) {
+		if content != nil {
+			model := client.GenerativeModelFromCachedContent(content)
+			configure(model)
+			return model.GenerateContent(ctx, genai.Text(text))
+		}
+		model := client.GenerativeModel(modelName)
+		configure(model)
+		return model.GenerateContent(ctx, genai.Text(prompt+"\n\n"+text))
+	})
+}
+
+// send makes a call with the prompt's cache, or with nil to send the prompt inline.
+// A cache that was deleted or ended early is forgotten and the call sent inline.
+func (c *promptCache) send(ctx context.Context, caches geminiCaches, modelName, prompt string, call func(*genai.CachedContent) (*genai.GenerateContentResponse, error)) (*genai.GenerateContentResponse, error) {
+	if content := c.cached(ctx, caches, modelName, prompt); content != nil {
+		resp, err := call(content)
+		if status.Code(err) != codes.NotFound {
+			return resp, err
+		}
+		// Cache it again next time
+		c.forget(modelName, prompt)
+	}
+	return call(nil)
+}
+
+// cached returns the cache of a prompt, creating it or extending its TTL as needed,
+// or nil if the prompt must be sent inline
+func (c *promptCache) cached(ctx context.Context, caches geminiCaches, modelName, prompt string) *genai.CachedContent {
+	if c == nil {
+		return nil
+	}
+
+	key := promptKey(modelName, prompt)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &cachedPrompt{}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	now := c.now()
+	if entry.expires.IsZero() || now.After(entry.expires) {
+		c.create(ctx, caches, modelName, prompt, entry)
+	} else if entry.content != nil && entry.expires.Sub(now) < promptCacheRefresh {
+		c.extend(ctx, caches, entry)
+	}
+	return entry.content
+}
+
+// create caches a prompt, recording when to try again if Gemini refuses it
+func (c *promptCache) create(ctx context.Context, caches geminiCaches, modelName, prompt string, entry *cachedPrompt) {
+	content, err := caches.CreateCachedContent(ctx, &genai.CachedContent{
+		Model:             modelName,
+		SystemInstruction: &genai.Content{Parts: []genai.Part{genai.Text(prompt)}},
+		Expiration:        genai.ExpireTimeOrTTL{TTL: c.ttl},
+	})
+	if err != nil {
+		log.Printf("Could not cache a prompt for %s, sending it inline: %v", modelName, err)
+		entry.content, entry.expires = nil, c.now().Add(c.ttl)
+		return
+	}
+	entry.content, entry.expires = content, c.expiry(content)
+}
+
+// extend renews the TTL of a cached prompt that is about to end
+func (c *promptCache) extend(ctx context.Context, caches geminiCaches, entry *cachedPrompt) {
+	content, err := caches.UpdateCachedContent(ctx, entry.content, &genai.CachedContentToUpdate{
+		Expiration: &genai.ExpireTimeOrTTL{TTL: c.ttl},
+	})
+	if err != nil {
+		// The cache keeps serving until it ends, then is created again
+		log.Printf("Could not extend cached prompt %s: %v", entry.content.Name, err)
+		return
+	}
+	content.Model = entry.content.Model
+	entry.content = content
+	entry.expires = c.expiry(content)
+}
+
+// forget drops the cache of a prompt so the next call creates it again
+func (c *promptCache) forget(modelName, prompt string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, promptKey(modelName, prompt))
+}
+
+// clear deletes every cached prompt rather than leaving them to run out their TTL
+func (c *promptCache) clear(ctx context.Context, caches geminiCaches) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	entries := c.entries
+	c.entries = make(map[string]*cachedPrompt)
+	c.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.mu.Lock()
+		if entry.content != nil {
+			if err := caches.DeleteCachedContent(ctx, entry.content.Name); err != nil {
+				log.Printf("Could not delete cached prompt %s: %v", entry.content.Name, err)
+			}
+		}
+		entry.mu.Unlock()
+	}
+}
+
+// expiry returns when a cache ends, assuming a full TTL if Gemini didn't say
+func (c *promptCache) expiry(content *genai.CachedContent) time.Time {
+	if !content.Expiration.ExpireTime.IsZero() {
+		return content.Expiration.ExpireTime
+	}
+	return c.now().Add(c.ttl)
+}
+
+// promptKey identifies the cache of a prompt for a model
+func promptKey(modelName, prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return modelName + ":" + hex.EncodeToString(sum[:])
+}
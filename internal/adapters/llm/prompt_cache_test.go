@@ -0,0 +1,239 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCaches stands in for Gemini's cached content API
+type fakeCaches struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	refuse  error // returned by CreateCachedContent when set
+	created int
+	updated int
+	deleted []string
+	block   chan struct{} // when set, CreateCachedContent waits on it
+}
+
+func (f *fakeCaches) CreateCachedContent(ctx context.Context, cc *genai.CachedContent) (*genai.CachedContent, error) {
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.refuse != nil {
+		return nil, f.refuse
+	}
+	f.created++
+	return &genai.CachedContent{
+		Name:       fmt.Sprintf("cachedContents/%d", f.created),
+		Model:      cc.Model,
+		Expiration: genai.ExpireTimeOrTTL{ExpireTime: f.now().Add(cc.Expiration.TTL)},
+	}, nil
+}
+
+func (f *fakeCaches) UpdateCachedContent(ctx context.Context, cc *genai.CachedContent, ccu *genai.CachedContentToUpdate) (*genai.CachedContent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updated++
+	return &genai.CachedContent{Name: cc.Name, Expiration: genai.ExpireTimeOrTTL{ExpireTime: f.now().Add(ccu.Expiration.TTL)}}, nil
+}
+
+func (f *fakeCaches) DeleteCachedContent(ctx context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+
+// newTestPromptCache returns a cache with an hour's TTL on a clock the test moves
+func newTestPromptCache() (*promptCache, *fakeCaches, *time.Time) {
+	now := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	cache := newPromptCache(time.Hour)
+	cache.now = clock
+	return cache, &fakeCaches{now: clock}, &now
+}
+
+// recordCall returns a call that records the cache it was made with
+func recordCall(got *[]string, err error) func(*genai.CachedContent) (*genai.GenerateContentResponse, error) {
+	return func(content *genai.CachedContent) (*genai.GenerateContentResponse, error) {
+		if content == nil {
+			*got = append(*got, "inline")
+			return &genai.GenerateContentResponse{}, nil
+		}
+		*got = append(*got, content.Name)
+		return &genai.GenerateContentResponse{}, err
+	}
+}
+
+func TestPromptCache_CreatesOnceAndReuses(t *testing.T) {
+	cache, caches, _ := newTestPromptCache()
+	ctx := context.Background()
+
+	var calls []string
+	for i := 0; i < 3; i++ {
+		if _, err := cache.send(ctx, caches, "gemini-1.5-flash-002", "instructions", recordCall(&calls, nil)); err != nil {
+			t.Fatalf("send() error = %v", err)
+		}
+	}
+	if caches.created != 1 {
+		t.Errorf("created %d caches, want 1", caches.created)
+	}
+	for _, call := range calls {
+		if call != "cachedContents/1" {
+			t.Errorf("calls = %v, want every call made with the cache", calls)
+			break
+		}
+	}
+
+	// Another prompt gets a cache of its own
+	cache.send(ctx, caches, "gemini-1.5-flash-002", "other instructions", recordCall(&calls, nil))
+	if caches.created != 2 {
+		t.Errorf("created %d caches, want one per prompt", caches.created)
+	}
+}
+
+func TestPromptCache_ExtendsNearExpiry(t *testing.T) {
+	cache, caches, now := newTestPromptCache()
+	ctx := context.Background()
+
+	cache.cached(ctx, caches, "model", "instructions")
+	*now = now.Add(50 * time.Minute)
+	if content := cache.cached(ctx, caches, "model", "instructions"); content == nil || caches.updated != 0 {
+		t.Fatalf("extended %d times with 10 minutes left, want none", caches.updated)
+	}
+
+	*now = now.Add(6 * time.Minute)
+	if content := cache.cached(ctx, caches, "model", "instructions"); content == nil || content.Model != "model" {
+		t.Fatalf("cached() = %+v, want the extended cache keeping its model", content)
+	}
+	if caches.updated != 1 || caches.created != 1 {
+		t.Errorf("updated %d and created %d caches, want the cache extended once", caches.updated, caches.created)
+	}
+
+	// The extended cache is good for another hour
+	*now = now.Add(50 * time.Minute)
+	cache.cached(ctx, caches, "model", "instructions")
+	if caches.created != 1 || caches.updated != 1 {
+		t.Errorf("updated %d and created %d caches, want the extension honoured", caches.updated, caches.created)
+	}
+}
+
+func TestPromptCache_NotFoundFallsBackInline(t *testing.T) {
+	cache, caches, _ := newTestPromptCache()
+	ctx := context.Background()
+
+	var calls []string
+	gone := status.Error(codes.NotFound, "cached content not found")
+	if _, err := cache.send(ctx, caches, "model", "instructions", recordCall(&calls, gone)); err != nil {
+		t.Fatalf("send() error = %v, want the call retried inline", err)
+	}
+	if len(calls) != 2 || calls[0] != "cachedContents/1" || calls[1] != "inline" {
+		t.Errorf("calls = %v, want the cache tried, then the prompt sent inline", calls)
+	}
+
+	// The next call caches the prompt again
+	cache.send(ctx, caches, "model", "instructions", recordCall(&calls, nil))
+	if caches.created != 2 || calls[len(calls)-1] != "cachedContents/2" {
+		t.Errorf("calls = %v after %d caches, want a new cache", calls, caches.created)
+	}
+
+	// Other errors are returned as they are
+	other := errors.New("quota exceeded")
+	if _, err := cache.send(ctx, caches, "model", "instructions", recordCall(&calls, other)); !errors.Is(err, other) {
+		t.Errorf("send() error = %v, want %v", err, other)
+	}
+}
+
+func TestPromptCache_WaitsAfterRefusal(t *testing.T) {
+	cache, caches, now := newTestPromptCache()
+	ctx := context.Background()
+	caches.refuse = status.Error(codes.InvalidArgument, "content is too small to cache")
+
+	var calls []string
+	cache.send(ctx, caches, "model", "short", recordCall(&calls, nil))
+	caches.refuse = nil
+
+	*now = now.Add(30 * time.Minute)
+	cache.send(ctx, caches, "model", "short", recordCall(&calls, nil))
+	if caches.created != 0 || calls[1] != "inline" {
+		t.Errorf("calls = %v, want the prompt sent inline without trying to cache it again", calls)
+	}
+
+	*now = now.Add(31 * time.Minute)
+	cache.send(ctx, caches, "model", "short", recordCall(&calls, nil))
+	if caches.created != 1 || calls[2] != "cachedContents/1" {
+		t.Errorf("calls = %v, want the prompt cached once the TTL passed", calls)
+	}
+}
+
+func TestPromptCache_Nil(t *testing.T) {
+	var cache *promptCache
+	caches := &fakeCaches{now: time.Now}
+
+	var calls []string
+	if _, err := cache.send(context.Background(), caches, "model", "instructions", recordCall(&calls, nil)); err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	if len(calls) != 1 || calls[0] != "inline" || caches.created != 0 {
+		t.Errorf("calls = %v, want the prompt sent inline without caching", calls)
+	}
+	cache.clear(context.Background(), caches)
+
+	if newPromptCache(0) != nil {
+		t.Error("newPromptCache(0) != nil, want caching off")
+	}
+}
+
+func TestPromptCache_Clear(t *testing.T) {
+	cache, caches, _ := newTestPromptCache()
+	ctx := context.Background()
+	cache.cached(ctx, caches, "model", "one")
+	cache.cached(ctx, caches, "model", "two")
+
+	cache.clear(ctx, caches)
+	if len(caches.deleted) != 2 {
+		t.Errorf("deleted %v, want both caches", caches.deleted)
+	}
+	cache.cached(ctx, caches, "model", "one")
+	if caches.created != 3 {
+		t.Errorf("created %d caches, want the prompt cached again after clear", caches.created)
+	}
+}
+
+func TestPromptCache_CreatingDoesNotBlockOtherPrompts(t *testing.T) {
+	cache, _, _ := newTestPromptCache()
+	ctx := context.Background()
+	slow := &fakeCaches{now: cache.now, block: make(chan struct{})}
+	fast := &fakeCaches{now: cache.now}
+
+	started := make(chan struct{})
+	go func() {
+		close(started)
+		cache.cached(ctx, slow, "model", "slow prompt")
+	}()
+	<-started
+	time.Sleep(10 * time.Millisecond) // let the slow call take its entry's lock
+
+	done := make(chan struct{})
+	go func() {
+		cache.cached(ctx, fast, "model", "fast prompt")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("caching one prompt waited on caching another")
+	}
+	close(slow.block)
+}
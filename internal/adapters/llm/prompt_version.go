@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+// DefaultPromptVersion is the version of the extraction instructions used when none is configured
+const DefaultPromptVersion = "v1"
+
+// StatedAmountsPrompt is added to the extraction instructions by prompt version v2,
+// which keeps the model from filling in amounts the content leaves out
+const StatedAmountsPrompt = `Rules for amounts:
+- Only give quantities, times and servings the content states or shows on screen
+- Leave a quantity empty rather than estimating it, and lower the confidence of the "quantities" provenance when amounts are missing
+- Never add ingredients the content does not mention, even if the dish usually has them`
+
+// extractionPrompts are the versions of the extraction instructions, chosen with
+// LLM_PROMPT_VERSION
+var extractionPrompts = map[string]string{
+	"v1": SystemPrompt,
+	"v2": SystemPrompt + "\n\n" + StatedAmountsPrompt,
+}
+
+// PromptVersions returns the versions of the extraction instructions, sorted
+func PromptVersions() []string {
+	versions := make([]string, 0, len(extractionPrompts))
+	for version := range extractionPrompts {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// PromptVersioned is an LLM adapter whose prompt version can change while it runs
+type PromptVersioned interface {
+	SetPromptVersion(version string) error
+}
+
+// promptVersion is the version of the extraction instructions an adapter uses.
+// It is safe for concurrent use, so it can change while calls are made.
+type promptVersion struct {
+	version atomic.Value // string
+}
+
+// set chooses a version; empty goes back to the default
+func (p *promptVersion) set(version string) error {
+	if version == "" {
+		version = DefaultPromptVersion
+	}
+	if _, ok := extractionPrompts[version]; !ok {
+		return fmt.Errorf("unknown prompt version %q", version)
+	}
+	p.version.Store(version)
+	return nil
+}
+
+// extraction returns the extraction instructions of the chosen version
+func (p *promptVersion) extraction() string {
+	version, _ := p.version.Load().(string)
+	if version == "" {
+		version = DefaultPromptVersion
+	}
+	return extractionPrompts[version]
+}
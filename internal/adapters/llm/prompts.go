@@ -18,12 +18,13 @@ You must respond with ONLY valid JSON in the following format:
   "category": "Category name (always in English)",
   "cuisine": "Cuisine type",
   "dietary_tags": ["tag1", "tag2"],
+  "flavors": ["flavor1"],
   "tags": ["descriptive", "tags"],
   "ingredients": [
     {"name": "ingredient name in ORIGINAL language", "quantity": "amount", "unit": "unit", "notes": "optional notes"}
   ],
   "instructions": [
-    {"step_number": 1, "text": "instruction text in ORIGINAL language", "duration_minutes": null}
+    {"step_number": 1, "text": "instruction text in ORIGINAL language", "duration_minutes": null, "start_seconds": null}
   ],
   "prep_time_minutes": null,
   "cook_time_minutes": null,
@@ -34,7 +35,10 @@ You must respond with ONLY valid JSON in the following format:
     {"name": "ingredient name in English", "quantity": "amount", "unit": "unit", "notes": "optional notes in English"}
   ],
   "translated_instructions": [
-    {"step_number": 1, "text": "instruction text in English", "duration_minutes": null}
+    {"step_number": 1, "text": "instruction text in English", "duration_minutes": null, "start_seconds": null}
+  ],
+  "provenance": [
+    {"field": "quantities", "source": "transcript", "confidence": 0.8}
   ]
 }
 
@@ -64,6 +68,12 @@ DIETARY TAGS (choose all that apply):
 - one-pot (cooked in single pot/pan)
 - kid-friendly (simple flavors, kid-approved)
 
+FLAVORS (choose the ones that stand out, or none):
+- spicy (chili heat, e.g. curries with chili, hot sauce, jalapeños)
+- sweet (sugar, honey or fruit lead the taste)
+- umami-rich (savory depth from aged cheese, cured meat, soy, miso, mushrooms, broth)
+- tangy (sour or acidic from citrus, vinegar, tamarind, yogurt, pickles)
+
 CUISINE (identify if applicable):
 Italian, Mexican, Chinese, Japanese, Indian, Thai, French, Greek, Mediterranean, American, Korean, Vietnamese, Middle Eastern, etc.
 
@@ -90,15 +100,23 @@ Rules for extraction:
 - For category: Choose the BEST matching category based on the main dish type
 - For cuisine: Identify the cuisine style if evident from ingredients/techniques
 - For dietary_tags: Only include tags that definitely apply based on ingredients
+- For flavors: Only include the tastes a cook would describe the dish by, usually one or two
 - For tags: Add 2-4 descriptive tags (e.g., "comfort-food", "weeknight-dinner", "meal-prep")
 - If the text contains a recipe, you MUST extract at least some ingredients
+- ON-SCREEN TEXT is read from the video picture and often has the exact quantities; prefer it when it disagrees with the transcript
+- VIDEO TIMELINE lists where chapters or transcript lines start, as [seconds] text; set each step's start_seconds to where the video starts showing it, or null without a timeline
+
+PROVENANCE RULES:
+- Add one provenance entry for each of these fields: title, ingredients, quantities, instructions, times, servings
+- source: the section of the text the field mostly came from: "captions", "transcript" or "on_screen"; use "inferred" when the text doesn't state it and you filled it in
+- confidence: from 0.0 to 1.0; use 0.9 or more when the text states the field clearly, and below 0.6 when you had to guess, e.g. quantities that were never said or a transcript that is hard to follow
 
 MULTILINGUAL RULES:
 - source_language: Use ISO 639-1 codes (en, pt, es, fr, de, it, etc.)
 - If source is English: Set translated_title, translated_ingredients, translated_instructions to null
 - If source is NOT English: Provide English translations in the translated_* fields
 - Keep original language content in the main fields (title, ingredients, instructions)
-- Category and dietary_tags should ALWAYS be in English
+- Category, dietary_tags and flavors should ALWAYS be in English
 - Units should ALWAYS be metric (g, ml, L, °C) - convert from imperial if needed`
 
 // BuildUserPrompt builds the user prompt with the provided text
@@ -123,6 +141,10 @@ const RecipeJSONSchema = `{
       "type": "array",
       "items": {"type": "string"}
     },
+    "flavors": {
+      "type": "array",
+      "items": {"type": "string", "enum": ["spicy", "sweet", "umami-rich", "tangy"]}
+    },
     "tags": {
       "type": "array",
       "items": {"type": "string"}
@@ -147,7 +169,8 @@ const RecipeJSONSchema = `{
         "properties": {
           "step_number": {"type": "integer"},
           "text": {"type": "string"},
-          "duration_minutes": {"type": ["integer", "null"]}
+          "duration_minutes": {"type": ["integer", "null"]},
+          "start_seconds": {"type": ["integer", "null"]}
         },
         "required": ["step_number", "text"]
       }
@@ -177,10 +200,23 @@ const RecipeJSONSchema = `{
         "properties": {
           "step_number": {"type": "integer"},
           "text": {"type": "string"},
-          "duration_minutes": {"type": ["integer", "null"]}
+          "duration_minutes": {"type": ["integer", "null"]},
+          "start_seconds": {"type": ["integer", "null"]}
         },
         "required": ["step_number", "text"]
       }
+    },
+    "provenance": {
+      "type": ["array", "null"],
+      "items": {
+        "type": "object",
+        "properties": {
+          "field": {"type": "string"},
+          "source": {"type": "string", "enum": ["captions", "transcript", "on_screen", "webpage", "inferred"]},
+          "confidence": {"type": "number"}
+        },
+        "required": ["field", "source", "confidence"]
+      }
     }
   },
   "required": ["title", "category", "ingredients", "instructions", "source_language"]
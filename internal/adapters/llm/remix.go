@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/ports"
+)
+
+// RemixPrompt asks the LLM to adapt a recipe to a goal such as a diet.
+// It is sent after SystemPrompt, which defines the JSON format and categories.
+const RemixPrompt = `Remix this recipe so that it is %s:
+
+%s
+
+Rules:
+- Swap only the ingredients that don't fit, for ones that keep the dish as close to the original as possible
+- Adjust the quantities, steps and times the swaps need, and keep everything else as it is
+- Give the remix a title that says what it is, e.g. "Vegan Spaghetti Carbonara"
+- Update the dietary tags to match the remix
+- Write everything in %s and set "source_language" to its language code
+- Set the "translated_*" fields to null
+- Add a "substitutions" array to the JSON listing every swap, with the original ingredient, its replacement and a short reason:
+  "substitutions": [{"original": "guanciale", "replacement": "smoked tofu", "reason": "smoky and firm like the pork"}]`
+
+// remixJSON is a remixed recipe as the LLM returns it
+type remixJSON struct {
+	recipeJSON
+	Substitutions []struct {
+		Original    string `json:"original"`
+		Replacement string `json:"replacement"`
+		Reason      string `json:"reason"`
+	} `json:"substitutions"`
+}
+
+// buildRemixPrompt builds the remix prompt with the original recipe as JSON
+func buildRemixPrompt(original *ports.RecipeExtraction, goal string, targetLang string) (string, error) {
+	recipeData, err := json.Marshal(convertExtractionToJSON(original))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode recipe: %w", err)
+	}
+	return fmt.Sprintf("%s\n\n%s", SystemPrompt, fmt.Sprintf(RemixPrompt, goal, recipeData, targetLang)), nil
+}
+
+// parseRemixResponse parses the remixed recipe and its substitutions
+func parseRemixResponse(response string) (*ports.RecipeRemix, error) {
+	var raw remixJSON
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse remixed recipe: %w", err)
+	}
+
+	remix := &ports.RecipeRemix{Recipe: convertJSONToExtraction(&raw.recipeJSON)}
+	for _, s := range raw.Substitutions {
+		if strings.TrimSpace(s.Original) == "" || strings.TrimSpace(s.Replacement) == "" {
+			continue
+		}
+		remix.Substitutions = append(remix.Substitutions, ports.SubstitutionData{
+			Original:    s.Original,
+			Replacement: s.Replacement,
+			Reason:      s.Reason,
+		})
+	}
+	return remix, nil
+}
+
+// RemixRecipe implements the RecipeRemixer interface
+func (a *GeminiAdapter) RemixRecipe(ctx context.Context, original *ports.RecipeExtraction, goal string, targetLang string) (*ports.RecipeRemix, error) {
+	prompt, err := buildRemixPrompt(original, goal, targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(0.5)
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(prompt))
+	if err != nil {
+		return nil, fmt.Errorf("recipe remix failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini for recipe remix")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseRemixResponse(responseText)
+}
+
+// RemixRecipe implements the RecipeRemixer interface
+func (a *OpenAIAdapter) RemixRecipe(ctx context.Context, original *ports.RecipeExtraction, goal string, targetLang string) (*ports.RecipeRemix, error) {
+	prompt, err := buildRemixPrompt(original, goal, targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
+			},
+		},
+		Temperature: 0.5,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("recipe remix failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI for recipe remix")
+	}
+
+	return parseRemixResponse(resp.Choices[0].Message.Content)
+}
@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/ports"
+)
+
+// SimplifyPrompt asks the LLM to rewrite recipe steps for kids and beginner cooks
+const SimplifyPrompt = `Rewrite these recipe instructions for a child or a beginner cook.
+
+Instructions:
+%s
+
+Rules:
+- Write in %s
+- Use short sentences and everyday words, one action per step
+- Split long steps into several steps, but do not add or drop anything
+- Keep every quantity, temperature and time from the original
+- Mention when an adult should help (knives, hot pans, the oven)
+
+Return ONLY valid JSON in this exact format:
+{"instructions": [{"step_number": 1, "text": "simple step"}]}`
+
+// buildSimplifyPrompt builds the simplification prompt for the instructions
+func buildSimplifyPrompt(instructions []ports.InstructionData, targetLang string) string {
+	steps := make([]string, len(instructions))
+	for i, inst := range instructions {
+		steps[i] = fmt.Sprintf("%d. %s", inst.StepNumber, inst.Text)
+	}
+	return fmt.Sprintf(SimplifyPrompt, strings.Join(steps, "\n"), targetLang)
+}
+
+// parseSimplifyResponse parses the LLM answer, numbering the steps in order
+func parseSimplifyResponse(response string) ([]ports.InstructionData, error) {
+	var raw struct {
+		Instructions []struct {
+			Text string `json:"text"`
+		} `json:"instructions"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSONResponse(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse simplified instructions: %w", err)
+	}
+
+	var steps []ports.InstructionData
+	for _, inst := range raw.Instructions {
+		text := strings.TrimSpace(inst.Text)
+		if text == "" {
+			continue
+		}
+		steps = append(steps, ports.InstructionData{StepNumber: len(steps) + 1, Text: text})
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no simplified instructions in response")
+	}
+	return steps, nil
+}
+
+// SimplifyInstructions implements the InstructionSimplifier interface
+func (a *GeminiAdapter) SimplifyInstructions(ctx context.Context, instructions []ports.InstructionData, targetLang string) ([]ports.InstructionData, error) {
+	if len(instructions) == 0 {
+		return nil, nil
+	}
+
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(0.3)
+	model.ResponseMIMEType = "application/json"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(buildSimplifyPrompt(instructions, targetLang)))
+	if err != nil {
+		return nil, fmt.Errorf("instruction simplification failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response from Gemini for instruction simplification")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseSimplifyResponse(responseText)
+}
+
+// SimplifyInstructions implements the InstructionSimplifier interface
+func (a *OpenAIAdapter) SimplifyInstructions(ctx context.Context, instructions []ports.InstructionData, targetLang string) ([]ports.InstructionData, error) {
+	if len(instructions) == 0 {
+		return nil, nil
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: buildSimplifyPrompt(instructions, targetLang),
+			},
+		},
+		Temperature: 0.3,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("instruction simplification failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI for instruction simplification")
+	}
+
+	return parseSimplifyResponse(resp.Choices[0].Message.Content)
+}
@@ -0,0 +1,109 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/ports"
+)
+
+// SummarizePrompt asks the LLM to fold older turns of a conversation into a summary
+const SummarizePrompt = `You keep a running summary of a conversation between a user and a recipe bot, so the bot remembers what was asked after the turns themselves are forgotten.
+
+Summary so far:
+%s
+
+Turns to add to it:
+%s
+
+Rules:
+- Write at most 3 short sentences, in the language of the conversation
+- Keep what later messages may refer to: dishes, ingredients, categories, filters, recipes shown and the user's preferences
+- Drop greetings and anything already done with
+- Return ONLY the new summary, without a title or quotes`
+
+// maxSummaryLength caps a summary, in characters, in case the model rambles
+const maxSummaryLength = 600
+
+// buildSummarizePrompt builds the summary prompt for the turns
+func buildSummarizePrompt(summary string, turns []ports.ConversationTurn) string {
+	if summary == "" {
+		summary = "(none yet)"
+	}
+	return fmt.Sprintf(SummarizePrompt, summary, formatHistoryForPrompt(turns))
+}
+
+// parseSummary trims the model's summary to maxSummaryLength
+func parseSummary(response string) (string, error) {
+	summary := strings.Trim(strings.TrimSpace(response), `"`)
+	if summary == "" {
+		return "", fmt.Errorf("empty conversation summary")
+	}
+	if runes := []rune(summary); len(runes) > maxSummaryLength {
+		summary = string(runes[:maxSummaryLength-1]) + "…"
+	}
+	return summary, nil
+}
+
+// SummarizeConversation implements the ConversationSummarizer interface
+func (a *IntentDetectorAdapter) SummarizeConversation(ctx context.Context, summary string, turns []ports.ConversationTurn) (string, error) {
+	if len(turns) == 0 {
+		return summary, nil
+	}
+
+	model := a.client.GenerativeModel(a.model)
+	model.SetTemperature(a.temperature)
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, intentTimeout)
+	defer cancel()
+
+	resp, err := model.GenerateContent(ctxWithTimeout, genai.Text(buildSummarizePrompt(summary, turns)))
+	if err != nil {
+		return "", fmt.Errorf("conversation summary failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no response from Gemini for conversation summary")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	return parseSummary(responseText)
+}
+
+// SummarizeConversation implements the ConversationSummarizer interface
+func (d *OpenAIIntentDetector) SummarizeConversation(ctx context.Context, summary string, turns []ports.ConversationTurn) (string, error) {
+	if len(turns) == 0 {
+		return summary, nil
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: d.model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: buildSummarizePrompt(summary, turns)},
+		},
+		Temperature: d.temperature,
+	}
+
+	timeout := intentTimeout
+	if d.llm.local {
+		timeout = localIntentTimeout
+	}
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	responseText, err := d.llm.complete(ctxWithTimeout, req)
+	if err != nil {
+		return "", fmt.Errorf("conversation summary failed: %w", classifyAPIError(err))
+	}
+
+	return parseSummary(responseText)
+}
@@ -0,0 +1,24 @@
+package llm
+
+// Task overrides the model and temperature of one kind of LLM call, so cheap calls
+// such as intent detection can run on a faster model than recipe extraction
+type Task struct {
+	Model       string   // the adapter's model when empty
+	Temperature *float32 // the call's built-in temperature when nil
+}
+
+// model returns the model of the task, or fallback when it has none
+func (t Task) model(fallback string) string {
+	if t.Model == "" {
+		return fallback
+	}
+	return t.Model
+}
+
+// temperature returns the temperature of the task, or fallback when it has none
+func (t Task) temperature(fallback float32) float32 {
+	if t.Temperature == nil {
+		return fallback
+	}
+	return *t.Temperature
+}
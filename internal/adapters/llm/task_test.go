@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTask_FallsBack(t *testing.T) {
+	var none Task
+	if got := none.model("gemini-1.5-flash"); got != "gemini-1.5-flash" {
+		t.Errorf("model() without an override = %q, want the global model", got)
+	}
+	if got := none.temperature(0.3); got != 0.3 {
+		t.Errorf("temperature() without an override = %v, want the built-in one", got)
+	}
+
+	zero := float32(0)
+	task := Task{Model: "gemini-1.5-pro", Temperature: &zero}
+	if got := task.model("gemini-1.5-flash"); got != "gemini-1.5-pro" {
+		t.Errorf("model() = %q, want the task's", got)
+	}
+	if got := task.temperature(0.3); got != 0 {
+		t.Errorf("temperature() = %v, want the task's 0 rather than the built-in one", got)
+	}
+}
+
+func TestOpenAIAdapter_ExtractTask(t *testing.T) {
+	const answer = `{"title": "Pancakes", "ingredients": [{"name": "flour"}], "instructions": [{"step_number": 1, "text": "Mix"}]}`
+	warm := float32(0.9)
+
+	tests := []struct {
+		name            string
+		task            Task
+		wantModel       string
+		wantTemperature float32
+	}{
+		{"no override", Task{}, "llama3.1", 0.3},
+		{"model override", Task{Model: "qwen2.5"}, "qwen2.5", 0.3},
+		{"temperature override", Task{Temperature: &warm}, "llama3.1", 0.9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeChatServer(t, answer)
+			port, err := NewLLMAdapter(LLMConfig{Provider: "ollama", BaseURL: server.URL, Model: "llama3.1", ExtractTask: tt.task})
+			if err != nil {
+				t.Fatalf("NewLLMAdapter() error = %v", err)
+			}
+			if _, err := port.ExtractRecipe(context.Background(), "Pancakes: flour, milk"); err != nil {
+				t.Fatalf("ExtractRecipe() error = %v", err)
+			}
+
+			requests := server.Requests()
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1", len(requests))
+			}
+			if requests[0].Model != tt.wantModel || requests[0].Temperature != tt.wantTemperature {
+				t.Errorf("request model = %q, temperature = %v, want %q, %v",
+					requests[0].Model, requests[0].Temperature, tt.wantModel, tt.wantTemperature)
+			}
+		})
+	}
+}
+
+func TestNewIntentDetector_IntentTask(t *testing.T) {
+	cool := float32(0.1)
+	tests := []struct {
+		name            string
+		task            Task
+		wantModel       string
+		wantTemperature float32
+	}{
+		{"no override", Task{}, "gpt-4o", 0.2},
+		{"overrides", Task{Model: "gpt-4o-mini", Temperature: &cool}, "gpt-4o-mini", 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port, err := NewIntentDetector(LLMConfig{Provider: "openai", APIKey: "key", Model: "gpt-4o", IntentTask: tt.task})
+			if err != nil {
+				t.Fatalf("NewIntentDetector() error = %v", err)
+			}
+			detector, ok := port.(*OpenAIIntentDetector)
+			if !ok {
+				t.Fatalf("NewIntentDetector() = %T, want *OpenAIIntentDetector", port)
+			}
+			if detector.model != tt.wantModel || detector.temperature != tt.wantTemperature {
+				t.Errorf("detector model = %q, temperature = %v, want %q, %v",
+					detector.model, detector.temperature, tt.wantModel, tt.wantTemperature)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+	"receipt-bot/internal/ports"
+)
+
+// recordGeminiUsage records the tokens a Gemini call used with the meter of ctx
+func recordGeminiUsage(ctx context.Context, model string, resp *genai.GenerateContentResponse) {
+	if resp == nil || resp.UsageMetadata == nil {
+		return
+	}
+	ports.RecordLLMUsage(ctx, model, int(resp.UsageMetadata.PromptTokenCount), int(resp.UsageMetadata.CandidatesTokenCount))
+}
+
+// recordOpenAIUsage records the tokens an OpenAI call used with the meter of ctx
+func recordOpenAIUsage(ctx context.Context, resp openai.ChatCompletionResponse) {
+	ports.RecordLLMUsage(ctx, resp.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// Log levels, from the most to the least verbose
+const (
+	LevelDebug = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// ParseLevel returns the level named by APP_LOG_LEVEL, info when unknown
+func ParseLevel(name string) int {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug
+	case "warn":
+		return LevelWarn
+	case "error":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+// Filter is the output of the standard logger that drops entries below a level.
+// The bot's log lines carry their level in how they start: "[DEBUG]" for debug,
+// "Warning" for warnings and "Error" or "Failed" for errors; everything else is info.
+// The level can change while the bot runs.
+type Filter struct {
+	out   io.Writer
+	level atomic.Int32
+}
+
+// NewFilter creates a filter writing the entries at level or above to out
+func NewFilter(out io.Writer, level string) *Filter {
+	f := &Filter{out: out}
+	f.SetLevel(level)
+	return f
+}
+
+// SetLevel changes the lowest level written
+func (f *Filter) SetLevel(level string) {
+	f.level.Store(int32(ParseLevel(level)))
+}
+
+// Write implements io.Writer. The standard logger writes one entry per call.
+func (f *Filter) Write(entry []byte) (int, error) {
+	if levelOf(entry) < int(f.level.Load()) {
+		return len(entry), nil
+	}
+	return f.out.Write(entry)
+}
+
+// levelOf returns the level of a log entry
+func levelOf(entry []byte) int {
+	message := skipTimestamp(entry)
+	switch {
+	case bytes.HasPrefix(message, []byte("[DEBUG]")):
+		return LevelDebug
+	case bytes.HasPrefix(message, []byte("Warning")):
+		return LevelWarn
+	case bytes.HasPrefix(message, []byte("Error")), bytes.HasPrefix(message, []byte("Failed")):
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+// skipTimestamp returns the message after the date and time the standard logger
+// puts in front of it
+func skipTimestamp(entry []byte) []byte {
+	for {
+		field, rest, found := bytes.Cut(entry, []byte(" "))
+		if !found || len(field) == 0 || strings.Trim(string(field), "0123456789/:.") != "" {
+			return entry
+		}
+		entry = rest
+	}
+}
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFilter_DropsEntriesBelowLevel(t *testing.T) {
+	entries := []string{
+		"[DEBUG] Captions length: 12",
+		"Bot started",
+		"Warning: Failed to initialize intent detector",
+		"Failed to save recipe",
+		"Error sending message",
+	}
+	tests := []struct {
+		level string
+		want  []string
+	}{
+		{"debug", entries},
+		{"info", entries[1:]},
+		{"warn", entries[2:]},
+		{"error", entries[3:]},
+		{"", entries[1:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := log.New(NewFilter(&out, tt.level), "", log.LstdFlags|log.Lmicroseconds)
+			for _, entry := range entries {
+				logger.Println(entry)
+			}
+
+			got := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if len(got) != len(tt.want) {
+				t.Fatalf("level %q wrote %d entries, want %d:\n%s", tt.level, len(got), len(tt.want), out.String())
+			}
+			for i, line := range got {
+				if !strings.HasSuffix(line, tt.want[i]) {
+					t.Errorf("entry %d = %q, want %q", i, line, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilter_SetLevel(t *testing.T) {
+	var out bytes.Buffer
+	filter := NewFilter(&out, "info")
+	logger := log.New(filter, "", log.LstdFlags)
+
+	logger.Printf("[DEBUG] hidden")
+	filter.SetLevel("debug")
+	logger.Printf("[DEBUG] shown")
+
+	if got := out.String(); strings.Contains(got, "hidden") || !strings.Contains(got, "shown") {
+		t.Errorf("output = %q, want only the entry written after SetLevel(debug)", got)
+	}
+}
+
+func TestFilter_SetLevelWhileLogging(t *testing.T) {
+	var (
+		out bytes.Buffer
+		mu  sync.Mutex
+	)
+	filter := NewFilter(writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return out.Write(p)
+	}), "info")
+	logger := log.New(filter, "", log.LstdFlags)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				filter.SetLevel("debug")
+			}
+			logger.Printf("[DEBUG] entry %d", i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
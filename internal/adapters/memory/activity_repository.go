@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/activity"
+)
+
+// ActivityRepository implements the activity.Repository interface in memory
+type ActivityRepository struct {
+	mu      sync.RWMutex
+	entries map[activity.UserID][]activity.Entry // oldest first
+}
+
+// NewActivityRepository creates a new in-memory activity repository
+func NewActivityRepository() *ActivityRepository {
+	return &ActivityRepository{
+		entries: make(map[activity.UserID][]activity.Entry),
+	}
+}
+
+// Append stores a new entry
+func (r *ActivityRepository) Append(ctx context.Context, e *activity.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[e.UserID] = append(r.entries[e.UserID], *e)
+	return nil
+}
+
+// FindByUser retrieves the entries of a user recorded since the given time, newest first
+func (r *ActivityRepository) FindByUser(ctx context.Context, userID activity.UserID, since time.Time) ([]*activity.Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := r.entries[userID]
+	var found []*activity.Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].CreatedAt.Before(since) {
+			continue
+		}
+		e := entries[i]
+		found = append(found, &e)
+	}
+	return found, nil
+}
+
+// DeleteBefore removes the entries of a user recorded before the cutoff
+func (r *ActivityRepository) DeleteBefore(ctx context.Context, userID activity.UserID, cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var kept []activity.Entry
+	for _, e := range r.entries[userID] {
+		if !e.CreatedAt.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(r.entries[userID]) - len(kept)
+	r.entries[userID] = kept
+	return removed, nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// BlobStorage implements the ports.BlobStorage interface in memory
+type BlobStorage struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewBlobStorage creates a new in-memory blob storage
+func NewBlobStorage() *BlobStorage {
+	return &BlobStorage{
+		blobs: make(map[string][]byte),
+	}
+}
+
+// Put stores data under a key
+func (s *BlobStorage) Put(ctx context.Context, key, contentType string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blobs[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Get returns the data stored under a key
+func (s *BlobStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, shared.ErrBlobNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Delete removes the data stored under a key
+func (s *BlobStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.blobs, key)
+	return nil
+}
+
+// SignedURL returns a memory:// URL naming the key; nothing can fetch it
+func (s *BlobStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("memory:///%s?expires=%d", url.PathEscape(key), time.Now().Add(expiry).Unix()), nil
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/conversation"
+)
+
+// ConversationRepository implements the conversation.Repository interface in memory
+type ConversationRepository struct {
+	mu    sync.RWMutex
+	turns map[conversation.UserID][]conversation.Turn // oldest first
+}
+
+// NewConversationRepository creates a new in-memory conversation repository
+func NewConversationRepository() *ConversationRepository {
+	return &ConversationRepository{
+		turns: make(map[conversation.UserID][]conversation.Turn),
+	}
+}
+
+// Append stores a new turn
+func (r *ConversationRepository) Append(ctx context.Context, t *conversation.Turn) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.turns[t.UserID] = append(r.turns[t.UserID], *t)
+	return nil
+}
+
+// FindByUser retrieves the turns of a user said since the given time, newest first
+func (r *ConversationRepository) FindByUser(ctx context.Context, userID conversation.UserID, since time.Time) ([]*conversation.Turn, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	turns := r.turns[userID]
+	var found []*conversation.Turn
+	for i := len(turns) - 1; i >= 0; i-- {
+		if turns[i].CreatedAt.Before(since) {
+			continue
+		}
+		t := turns[i]
+		found = append(found, &t)
+	}
+	return found, nil
+}
+
+// DeleteBefore removes the turns of a user said before the cutoff
+func (r *ConversationRepository) DeleteBefore(ctx context.Context, userID conversation.UserID, cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var kept []conversation.Turn
+	for _, t := range r.turns[userID] {
+		if !t.CreatedAt.Before(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	removed := len(r.turns[userID]) - len(kept)
+	r.turns[userID] = kept
+	return removed, nil
+}
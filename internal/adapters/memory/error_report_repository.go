@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/report"
+)
+
+// ErrorReportRepository implements the report.Repository interface in memory
+type ErrorReportRepository struct {
+	mu      sync.RWMutex
+	reports map[string]report.ErrorReport
+}
+
+// NewErrorReportRepository creates a new in-memory error report repository
+func NewErrorReportRepository() *ErrorReportRepository {
+	return &ErrorReportRepository{
+		reports: make(map[string]report.ErrorReport),
+	}
+}
+
+// Save persists an error report
+func (r *ErrorReportRepository) Save(ctx context.Context, rep *report.ErrorReport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reports[rep.ID] = *rep
+	return nil
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/recipe"
+)
+
+// exportCheckpointKey identifies the bulk export of a user to one target
+type exportCheckpointKey struct {
+	userID recipe.UserID
+	target string
+}
+
+// ExportCheckpointRepository implements the recipe.ExportCheckpointRepository interface in memory
+type ExportCheckpointRepository struct {
+	mu          sync.RWMutex
+	checkpoints map[exportCheckpointKey][]recipe.RecipeID
+}
+
+// NewExportCheckpointRepository creates a new in-memory export checkpoint repository
+func NewExportCheckpointRepository() *ExportCheckpointRepository {
+	return &ExportCheckpointRepository{
+		checkpoints: make(map[exportCheckpointKey][]recipe.RecipeID),
+	}
+}
+
+// FindExportCheckpoint returns the recipes an unfinished bulk export already sent
+func (r *ExportCheckpointRepository) FindExportCheckpoint(ctx context.Context, userID recipe.UserID, target string) ([]recipe.RecipeID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]recipe.RecipeID(nil), r.checkpoints[exportCheckpointKey{userID, target}]...), nil
+}
+
+// SaveExportCheckpoint replaces the recipes a bulk export already sent
+func (r *ExportCheckpointRepository) SaveExportCheckpoint(ctx context.Context, userID recipe.UserID, target string, sent []recipe.RecipeID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := exportCheckpointKey{userID, target}
+	if len(sent) == 0 {
+		delete(r.checkpoints, key)
+		return nil
+	}
+	r.checkpoints[key] = append([]recipe.RecipeID(nil), sent...)
+	return nil
+}
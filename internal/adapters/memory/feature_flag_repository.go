@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/feature"
+)
+
+// FeatureFlagRepository implements the feature.Repository interface in memory
+type FeatureFlagRepository struct {
+	mu        sync.RWMutex
+	overrides map[feature.Flag]feature.Override
+}
+
+// NewFeatureFlagRepository creates a new in-memory feature flag repository
+func NewFeatureFlagRepository() *FeatureFlagRepository {
+	return &FeatureFlagRepository{
+		overrides: make(map[feature.Flag]feature.Override),
+	}
+}
+
+// FindAll retrieves all stored overrides
+func (r *FeatureFlagRepository) FindAll(ctx context.Context) ([]feature.Override, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	overrides := make([]feature.Override, 0, len(r.overrides))
+	for _, o := range r.overrides {
+		overrides = append(overrides, o)
+	}
+	return overrides, nil
+}
+
+// SetOverride stores or replaces the override for a flag
+func (r *FeatureFlagRepository) SetOverride(o feature.Override) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.overrides[o.Flag] = o
+}
+
+// DeleteOverride removes the override for a flag
+func (r *FeatureFlagRepository) DeleteOverride(flag feature.Flag) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.overrides, flag)
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"receipt-bot/internal/domain/feed"
+)
+
+// FeedRepository implements the feed.Repository interface in memory
+type FeedRepository struct {
+	mu            sync.RWMutex
+	subscriptions map[string]feed.Subscription
+}
+
+// NewFeedRepository creates a new in-memory feed subscription repository
+func NewFeedRepository() *FeedRepository {
+	return &FeedRepository{
+		subscriptions: make(map[string]feed.Subscription),
+	}
+}
+
+// Save persists a subscription
+func (r *FeedRepository) Save(ctx context.Context, s *feed.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *s
+	stored.Seen = append([]string(nil), s.Seen...)
+	r.subscriptions[s.ID] = stored
+	return nil
+}
+
+// FindByUser retrieves the subscriptions of a user, oldest first
+func (r *FeedRepository) FindByUser(ctx context.Context, userID feed.UserID) ([]*feed.Subscription, error) {
+	all, _ := r.FindAll(ctx)
+
+	var subscriptions []*feed.Subscription
+	for _, s := range all {
+		if s.UserID == userID {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+	return subscriptions, nil
+}
+
+// FindAll retrieves every subscription, oldest first
+func (r *FeedRepository) FindAll(ctx context.Context) ([]*feed.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := make([]*feed.Subscription, 0, len(r.subscriptions))
+	for _, s := range r.subscriptions {
+		s.Seen = append([]string(nil), s.Seen...)
+		subscriptions = append(subscriptions, &s)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	return subscriptions, nil
+}
+
+// Delete removes a subscription
+func (r *FeedRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.subscriptions, id)
+	return nil
+}
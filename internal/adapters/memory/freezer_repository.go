@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/freezer"
+	"receipt-bot/internal/domain/shared"
+)
+
+// FreezerRepository implements the freezer.Repository interface in memory
+type FreezerRepository struct {
+	mu       sync.RWMutex
+	freezers map[freezer.UserID]*freezer.Freezer
+}
+
+// NewFreezerRepository creates a new in-memory freezer repository
+func NewFreezerRepository() *FreezerRepository {
+	return &FreezerRepository{
+		freezers: make(map[freezer.UserID]*freezer.Freezer),
+	}
+}
+
+// FindByUser retrieves the freezer of a user
+func (r *FreezerRepository) FindByUser(ctx context.Context, userID freezer.UserID) (*freezer.Freezer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, ok := r.freezers[userID]
+	if !ok {
+		return nil, shared.ErrFreezerNotFound
+	}
+	return f.Clone(), nil
+}
+
+// Save persists a freezer
+func (r *FreezerRepository) Save(ctx context.Context, f *freezer.Freezer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.freezers[f.UserID()] = f.Clone()
+	return nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/share"
+	"receipt-bot/internal/domain/shared"
+)
+
+// GuestShareRepository implements the share.Repository interface in memory
+type GuestShareRepository struct {
+	mu     sync.RWMutex
+	shares map[string]share.GuestShare
+}
+
+// NewGuestShareRepository creates a new in-memory guest share repository
+func NewGuestShareRepository() *GuestShareRepository {
+	return &GuestShareRepository{
+		shares: make(map[string]share.GuestShare),
+	}
+}
+
+// Save persists a guest share
+func (r *GuestShareRepository) Save(ctx context.Context, s *share.GuestShare) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.shares[s.Token] = *s
+	return nil
+}
+
+// FindByToken retrieves a guest share by its token
+func (r *GuestShareRepository) FindByToken(ctx context.Context, token string) (*share.GuestShare, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.shares[token]
+	if !ok {
+		return nil, shared.ErrShareNotFound
+	}
+	return &s, nil
+}
+
+// DeleteByOwner removes all guest shares of a user
+func (r *GuestShareRepository) DeleteByOwner(ctx context.Context, ownerID share.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for token, s := range r.shares {
+		if s.OwnerID == ownerID {
+			delete(r.shares, token)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// LinkCodeRepository implements the user.LinkCodeRepository interface in memory
+type LinkCodeRepository struct {
+	mu    sync.Mutex
+	codes map[string]user.LinkCode
+}
+
+// NewLinkCodeRepository creates a new in-memory link code repository
+func NewLinkCodeRepository() *LinkCodeRepository {
+	return &LinkCodeRepository{
+		codes: make(map[string]user.LinkCode),
+	}
+}
+
+// SaveLinkCode persists a link code
+func (r *LinkCodeRepository) SaveLinkCode(ctx context.Context, code *user.LinkCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.codes[code.Code] = *code
+	return nil
+}
+
+// TakeLinkCode retrieves a link code and deletes it so it can only be used once
+func (r *LinkCodeRepository) TakeLinkCode(ctx context.Context, code string) (*user.LinkCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.codes[code]
+	if !ok {
+		return nil, shared.ErrInvalidLinkCode
+	}
+	delete(r.codes, code)
+	return &c, nil
+}
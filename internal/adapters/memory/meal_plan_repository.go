@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/mealplan"
+	"receipt-bot/internal/domain/shared"
+)
+
+// MealPlanRepository implements the mealplan.Repository interface in memory
+type MealPlanRepository struct {
+	mu    sync.RWMutex
+	plans map[string]*mealplan.MealPlan
+}
+
+// NewMealPlanRepository creates a new in-memory meal plan repository
+func NewMealPlanRepository() *MealPlanRepository {
+	return &MealPlanRepository{
+		plans: make(map[string]*mealplan.MealPlan),
+	}
+}
+
+// FindByWeek retrieves the plan of a user for the week starting on weekStart
+func (r *MealPlanRepository) FindByWeek(ctx context.Context, userID mealplan.UserID, weekStart time.Time) (*mealplan.MealPlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plan, ok := r.plans[mealPlanKey(userID, weekStart)]
+	if !ok {
+		return nil, shared.ErrMealPlanNotFound
+	}
+	return plan.Clone(), nil
+}
+
+// Save persists a meal plan
+func (r *MealPlanRepository) Save(ctx context.Context, plan *mealplan.MealPlan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.plans[mealPlanKey(plan.UserID(), plan.WeekStart())] = plan.Clone()
+	return nil
+}
+
+func mealPlanKey(userID mealplan.UserID, weekStart time.Time) string {
+	return userID.String() + "_" + weekStart.Format("2006-01-02")
+}
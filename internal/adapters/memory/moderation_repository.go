@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ModerationRepository implements the moderation.Repository interface in memory
+type ModerationRepository struct {
+	mu        sync.RWMutex
+	decisions map[recipe.RecipeID]moderation.Decision
+}
+
+// NewModerationRepository creates a new in-memory moderation decision repository
+func NewModerationRepository() *ModerationRepository {
+	return &ModerationRepository{
+		decisions: make(map[recipe.RecipeID]moderation.Decision),
+	}
+}
+
+// Save persists a decision, replacing the previous one of the recipe
+func (r *ModerationRepository) Save(ctx context.Context, d *moderation.Decision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decisions[d.RecipeID] = *d
+	return nil
+}
+
+// FindByRecipe retrieves the decision of a recipe
+func (r *ModerationRepository) FindByRecipe(ctx context.Context, recipeID recipe.RecipeID) (*moderation.Decision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.decisions[recipeID]
+	if !ok {
+		return nil, shared.ErrModerationNotFound
+	}
+	return &d, nil
+}
+
+// FindPending retrieves the decisions waiting for an admin review, oldest first
+func (r *ModerationRepository) FindPending(ctx context.Context) ([]*moderation.Decision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var pending []*moderation.Decision
+	for _, d := range r.decisions {
+		if d.Verdict == moderation.VerdictReview {
+			pending = append(pending, &d)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].CheckedAt.Before(pending[j].CheckedAt)
+	})
+	return pending, nil
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/nutrition"
+	"receipt-bot/internal/domain/shared"
+)
+
+// NutritionRepository implements the nutrition.Repository interface in memory
+type NutritionRepository struct {
+	mu       sync.RWMutex
+	products map[string]nutrition.Product                            // barcode -> product
+	links    map[nutrition.UserID]map[string]nutrition.PantryProduct // user -> barcode -> link
+	goals    map[nutrition.UserID]nutrition.Goals
+	meals    []nutrition.Meal
+}
+
+// NewNutritionRepository creates a new in-memory nutrition repository
+func NewNutritionRepository() *NutritionRepository {
+	return &NutritionRepository{
+		products: make(map[string]nutrition.Product),
+		links:    make(map[nutrition.UserID]map[string]nutrition.PantryProduct),
+		goals:    make(map[nutrition.UserID]nutrition.Goals),
+	}
+}
+
+// SaveProduct caches product data by barcode
+func (r *NutritionRepository) SaveProduct(ctx context.Context, product *nutrition.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cp := *product
+	if product.Facts != nil {
+		facts := *product.Facts
+		cp.Facts = &facts
+	}
+	r.products[product.Barcode] = cp
+	return nil
+}
+
+// FindProduct returns cached product data
+func (r *NutritionRepository) FindProduct(ctx context.Context, barcode string) (*nutrition.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	product, ok := r.products[barcode]
+	if !ok {
+		return nil, shared.ErrProductNotFound
+	}
+	if product.Facts != nil {
+		facts := *product.Facts
+		product.Facts = &facts
+	}
+	return &product, nil
+}
+
+// SavePantryProduct links a pantry item to a product
+func (r *NutritionRepository) SavePantryProduct(ctx context.Context, link *nutrition.PantryProduct) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.links[link.UserID] == nil {
+		r.links[link.UserID] = make(map[string]nutrition.PantryProduct)
+	}
+	r.links[link.UserID][link.Barcode] = *link
+	return nil
+}
+
+// FindPantryProducts returns all pantry product links of a user, oldest first
+func (r *NutritionRepository) FindPantryProducts(ctx context.Context, userID nutrition.UserID) ([]*nutrition.PantryProduct, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	links := make([]*nutrition.PantryProduct, 0, len(r.links[userID]))
+	for _, link := range r.links[userID] {
+		link := link
+		links = append(links, &link)
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].LinkedAt.Before(links[j].LinkedAt) })
+	return links, nil
+}
+
+// SaveGoals stores a user's daily macro goals
+func (r *NutritionRepository) SaveGoals(ctx context.Context, goals *nutrition.Goals) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.goals[goals.UserID] = *goals
+	return nil
+}
+
+// FindGoals returns a user's daily macro goals
+func (r *NutritionRepository) FindGoals(ctx context.Context, userID nutrition.UserID) (*nutrition.Goals, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	goals, ok := r.goals[userID]
+	if !ok {
+		return nil, shared.ErrNoMacroGoals
+	}
+	return &goals, nil
+}
+
+// DeleteGoals removes a user's daily macro goals
+func (r *NutritionRepository) DeleteGoals(ctx context.Context, userID nutrition.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.goals, userID)
+	return nil
+}
+
+// SaveMeal logs a meal
+func (r *NutritionRepository) SaveMeal(ctx context.Context, meal *nutrition.Meal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.meals = append(r.meals, *meal)
+	return nil
+}
+
+// FindMeals returns the meals a user logged since the given time, oldest first
+func (r *NutritionRepository) FindMeals(ctx context.Context, userID nutrition.UserID, since time.Time) ([]*nutrition.Meal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var meals []*nutrition.Meal
+	for _, meal := range r.meals {
+		if meal.UserID == userID && !meal.EatenAt.Before(since) {
+			meal := meal
+			meals = append(meals, &meal)
+		}
+	}
+	sort.SliceStable(meals, func(i, j int) bool { return meals[i].EatenAt.Before(meals[j].EatenAt) })
+	return meals, nil
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ProcessingReportRepository implements the recipe.ProcessingRepository interface in memory
+type ProcessingReportRepository struct {
+	mu      sync.RWMutex
+	reports map[recipe.RecipeID]recipe.ProcessingReport
+}
+
+// NewProcessingReportRepository creates a new in-memory processing report repository
+func NewProcessingReportRepository() *ProcessingReportRepository {
+	return &ProcessingReportRepository{
+		reports: make(map[recipe.RecipeID]recipe.ProcessingReport),
+	}
+}
+
+// SaveProcessingReport stores a report, replacing the recipe's previous one
+func (r *ProcessingReportRepository) SaveProcessingReport(ctx context.Context, report *recipe.ProcessingReport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reports[report.RecipeID] = *report
+	return nil
+}
+
+// FindProcessingReport returns the report of a recipe
+func (r *ProcessingReportRepository) FindProcessingReport(ctx context.Context, recipeID recipe.RecipeID) (*recipe.ProcessingReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	report, ok := r.reports[recipeID]
+	if !ok {
+		return nil, shared.ErrNoProcessingReport
+	}
+	return &report, nil
+}
@@ -0,0 +1,232 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// RecipeRepository implements the recipe.Repository interface in memory.
+// It is safe for concurrent use. Recipes are copied on the way in and out,
+// so callers never share state with the store, just like a real database.
+type RecipeRepository struct {
+	mu      sync.RWMutex
+	recipes map[recipe.RecipeID]*recipe.Recipe
+}
+
+// NewRecipeRepository creates a new in-memory recipe repository
+func NewRecipeRepository() *RecipeRepository {
+	return &RecipeRepository{
+		recipes: make(map[recipe.RecipeID]*recipe.Recipe),
+	}
+}
+
+// Save persists a recipe
+func (r *RecipeRepository) Save(ctx context.Context, rec *recipe.Recipe) error {
+	if rec.IsSummary() {
+		return shared.ErrRecipeSummary
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recipes[rec.ID()] = rec.Clone()
+	return nil
+}
+
+// SaveAll persists several recipes
+func (r *RecipeRepository) SaveAll(ctx context.Context, recipes []*recipe.Recipe) (int, error) {
+	for i, rec := range recipes {
+		if err := r.Save(ctx, rec); err != nil {
+			return i, err
+		}
+	}
+	return len(recipes), nil
+}
+
+// FindByID retrieves a recipe by its ID
+func (r *RecipeRepository) FindByID(ctx context.Context, id recipe.RecipeID) (*recipe.Recipe, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rec, ok := r.recipes[id]
+	if !ok {
+		return nil, shared.ErrRecipeNotFound
+	}
+	return rec.Clone(), nil
+}
+
+// FindByUserID retrieves all recipes for a user, newest first
+func (r *RecipeRepository) FindByUserID(ctx context.Context, userID recipe.UserID) ([]*recipe.Recipe, error) {
+	return r.find(func(rec *recipe.Recipe) bool {
+		return rec.UserID() == userID
+	}), nil
+}
+
+// FindSummariesByUserID retrieves summaries of all recipes for a user, newest first
+func (r *RecipeRepository) FindSummariesByUserID(ctx context.Context, userID recipe.UserID) ([]*recipe.Recipe, error) {
+	recipes, _ := r.FindByUserID(ctx, userID)
+	for i, rec := range recipes {
+		recipes[i] = rec.Summary()
+	}
+	return recipes, nil
+}
+
+// FindByUserIDAndCategory retrieves recipes for a user filtered by category
+func (r *RecipeRepository) FindByUserIDAndCategory(ctx context.Context, userID recipe.UserID, category recipe.Category) ([]*recipe.Recipe, error) {
+	return r.find(func(rec *recipe.Recipe) bool {
+		return rec.UserID() == userID && rec.Category() == category
+	}), nil
+}
+
+// FindByUserIDAndFilters retrieves recipes for a user with optional category, dietary tag and language filters
+func (r *RecipeRepository) FindByUserIDAndFilters(ctx context.Context, userID recipe.UserID, category *recipe.Category, dietaryTags []recipe.DietaryTag, language string) ([]*recipe.Recipe, error) {
+	return r.find(func(rec *recipe.Recipe) bool {
+		if rec.UserID() != userID {
+			return false
+		}
+		if category != nil && rec.Category() != *category {
+			return false
+		}
+		if language != "" && rec.SourceLanguage() != language {
+			return false
+		}
+		return rec.HasAllDietaryTags(dietaryTags)
+	}), nil
+}
+
+// SearchByIngredient searches recipes containing a specific ingredient in title or ingredients
+func (r *RecipeRepository) SearchByIngredient(ctx context.Context, userID recipe.UserID, ingredient string) ([]*recipe.Recipe, error) {
+	return r.find(func(rec *recipe.Recipe) bool {
+		return rec.UserID() == userID && rec.MentionsIngredient(ingredient)
+	}), nil
+}
+
+// SearchByIngredientFilter searches recipes using complex ingredient filters (AND/OR/NOT logic)
+func (r *RecipeRepository) SearchByIngredientFilter(ctx context.Context, userID recipe.UserID, filter recipe.IngredientFilter) ([]*recipe.Recipe, error) {
+	return r.find(func(rec *recipe.Recipe) bool {
+		return rec.UserID() == userID && filter.Matches(rec)
+	}), nil
+}
+
+// FindBySourceURL retrieves a recipe by its source URL (for duplicate detection)
+func (r *RecipeRepository) FindBySourceURL(ctx context.Context, sourceURL string) (*recipe.Recipe, error) {
+	matches := r.find(func(rec *recipe.Recipe) bool {
+		return rec.Source().URL() == sourceURL
+	})
+	if len(matches) == 0 {
+		return nil, shared.ErrRecipeNotFound
+	}
+	return matches[0], nil
+}
+
+// GetCategoryCounts returns the count of recipes per category for a user
+func (r *RecipeRepository) GetCategoryCounts(ctx context.Context, userID recipe.UserID) (map[recipe.Category]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[recipe.Category]int)
+	for _, rec := range r.recipes {
+		if rec.UserID() == userID {
+			counts[rec.Category()]++
+		}
+	}
+	return counts, nil
+}
+
+// FindByUserIDAndAuthor retrieves recipes for a user saved from a source author
+func (r *RecipeRepository) FindByUserIDAndAuthor(ctx context.Context, userID recipe.UserID, author string) ([]*recipe.Recipe, error) {
+	return r.find(func(rec *recipe.Recipe) bool {
+		return rec.UserID() == userID && rec.Source().IsFrom(author)
+	}), nil
+}
+
+// GetAuthorCounts returns the count of recipes per source author for a user
+func (r *RecipeRepository) GetAuthorCounts(ctx context.Context, userID recipe.UserID) (map[string]int, error) {
+	recipes, _ := r.FindByUserID(ctx, userID)
+	return recipe.CountAuthors(recipes), nil
+}
+
+// FindByUserIDAndPlatform retrieves recipes for a user saved from a source platform
+func (r *RecipeRepository) FindByUserIDAndPlatform(ctx context.Context, userID recipe.UserID, platform recipe.Platform) ([]*recipe.Recipe, error) {
+	return r.find(func(rec *recipe.Recipe) bool {
+		return rec.UserID() == userID && rec.Source().Platform() == platform
+	}), nil
+}
+
+// GetPlatformCounts returns the count of recipes per source platform for a user
+func (r *RecipeRepository) GetPlatformCounts(ctx context.Context, userID recipe.UserID) (map[recipe.Platform]int, error) {
+	recipes, _ := r.FindByUserID(ctx, userID)
+	return recipe.CountPlatforms(recipes), nil
+}
+
+// FindByUserIDAndFlavor retrieves recipes for a user classified with a flavor profile
+func (r *RecipeRepository) FindByUserIDAndFlavor(ctx context.Context, userID recipe.UserID, flavor recipe.Flavor) ([]*recipe.Recipe, error) {
+	return r.find(func(rec *recipe.Recipe) bool {
+		return rec.UserID() == userID && rec.HasFlavor(flavor)
+	}), nil
+}
+
+// FindByUserIDAndDateRange retrieves recipes for a user saved within a date range, newest first
+func (r *RecipeRepository) FindByUserIDAndDateRange(ctx context.Context, userID recipe.UserID, saved recipe.DateRange) ([]*recipe.Recipe, error) {
+	return r.find(func(rec *recipe.Recipe) bool {
+		return rec.UserID() == userID && saved.Contains(rec.CreatedAt())
+	}), nil
+}
+
+// Update updates an existing recipe
+func (r *RecipeRepository) Update(ctx context.Context, rec *recipe.Recipe) error {
+	return r.Save(ctx, rec)
+}
+
+// StripTranscripts removes the transcripts and captions of every user's recipes saved before the cutoff
+func (r *RecipeRepository) StripTranscripts(ctx context.Context, savedBefore time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stripped := 0
+	for _, rec := range r.recipes {
+		if rec.CreatedAt().Before(savedBefore) && rec.StripTranscript() {
+			stripped++
+		}
+	}
+	return stripped, nil
+}
+
+// Delete removes a recipe
+func (r *RecipeRepository) Delete(ctx context.Context, id recipe.RecipeID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.recipes, id)
+	return nil
+}
+
+// find returns copies of the recipes matching the predicate, newest first
+// (the same order the Firestore repository uses)
+func (r *RecipeRepository) find(match func(*recipe.Recipe) bool) []*recipe.Recipe {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var results []*recipe.Recipe
+	for _, rec := range r.recipes {
+		if match(rec) {
+			results = append(results, rec.Clone())
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		ti, tj := results[i].CreatedAt(), results[j].CreatedAt()
+		if ti.Equal(tj) {
+			return strings.Compare(results[i].ID().String(), results[j].ID().String()) < 0
+		}
+		return ti.After(tj)
+	})
+
+	return results
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// RecipeVariantRepository implements the recipe.VariantRepository interface in memory
+type RecipeVariantRepository struct {
+	mu       sync.RWMutex
+	variants map[recipe.RecipeID]map[recipe.Appliance]recipe.Variant
+}
+
+// NewRecipeVariantRepository creates a new in-memory recipe variant repository
+func NewRecipeVariantRepository() *RecipeVariantRepository {
+	return &RecipeVariantRepository{
+		variants: make(map[recipe.RecipeID]map[recipe.Appliance]recipe.Variant),
+	}
+}
+
+// SaveVariant stores a variant, replacing the recipe's previous variant for the same appliance
+func (r *RecipeVariantRepository) SaveVariant(ctx context.Context, variant *recipe.Variant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.variants[variant.RecipeID] == nil {
+		r.variants[variant.RecipeID] = make(map[recipe.Appliance]recipe.Variant)
+	}
+	r.variants[variant.RecipeID][variant.Appliance] = *variant
+	return nil
+}
+
+// FindVariants returns all variants of a recipe, in appliance order
+func (r *RecipeVariantRepository) FindVariants(ctx context.Context, recipeID recipe.RecipeID) ([]*recipe.Variant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var variants []*recipe.Variant
+	for _, appliance := range recipe.AllAppliances() {
+		if v, ok := r.variants[recipeID][appliance]; ok {
+			variants = append(variants, &v)
+		}
+	}
+	return variants, nil
+}
+
+// FindVariant returns the variant of a recipe for an appliance
+func (r *RecipeVariantRepository) FindVariant(ctx context.Context, recipeID recipe.RecipeID, appliance recipe.Appliance) (*recipe.Variant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.variants[recipeID][appliance]
+	if !ok {
+		return nil, shared.ErrVariantNotFound
+	}
+	return &v, nil
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// RecipeVersionRepository implements the recipe.VersionRepository interface in memory
+type RecipeVersionRepository struct {
+	mu       sync.RWMutex
+	versions map[recipe.RecipeID][]recipe.Version
+}
+
+// NewRecipeVersionRepository creates a new in-memory recipe version repository
+func NewRecipeVersionRepository() *RecipeVersionRepository {
+	return &RecipeVersionRepository{
+		versions: make(map[recipe.RecipeID][]recipe.Version),
+	}
+}
+
+// SaveVersion stores a version of a recipe, replacing any version with the same number
+func (r *RecipeVersionRepository) SaveVersion(ctx context.Context, version *recipe.Version) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions := r.versions[version.RecipeID]
+	for i := range versions {
+		if versions[i].Number == version.Number {
+			versions[i] = *version
+			return nil
+		}
+	}
+
+	// Versions are numbered sequentially, so appending keeps them ordered
+	r.versions[version.RecipeID] = append(versions, *version)
+	return nil
+}
+
+// FindVersions returns all versions of a recipe, oldest first
+func (r *RecipeVersionRepository) FindVersions(ctx context.Context, recipeID recipe.RecipeID) ([]*recipe.Version, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]*recipe.Version, 0, len(r.versions[recipeID]))
+	for _, v := range r.versions[recipeID] {
+		v := v
+		versions = append(versions, &v)
+	}
+	return versions, nil
+}
+
+// FindVersion returns a single version of a recipe
+func (r *RecipeVersionRepository) FindVersion(ctx context.Context, recipeID recipe.RecipeID, number int) (*recipe.Version, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, v := range r.versions[recipeID] {
+		if v.Number == number {
+			v := v
+			return &v, nil
+		}
+	}
+	return nil, shared.ErrVersionNotFound
+}
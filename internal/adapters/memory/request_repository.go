@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/requests"
+	"receipt-bot/internal/domain/shared"
+)
+
+// RequestRepository implements the requests.Repository interface in memory
+type RequestRepository struct {
+	mu    sync.RWMutex
+	lists map[requests.OwnerID]*requests.List
+}
+
+// NewRequestRepository creates a new in-memory request list repository
+func NewRequestRepository() *RequestRepository {
+	return &RequestRepository{
+		lists: make(map[requests.OwnerID]*requests.List),
+	}
+}
+
+// FindByOwner retrieves the request list of a household
+func (r *RequestRepository) FindByOwner(ctx context.Context, ownerID requests.OwnerID) (*requests.List, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	l, ok := r.lists[ownerID]
+	if !ok {
+		return nil, shared.ErrRequestsNotFound
+	}
+	return l.Clone(), nil
+}
+
+// Save persists a request list
+func (r *RequestRepository) Save(ctx context.Context, l *requests.List) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lists[l.OwnerID()] = l.Clone()
+	return nil
+}
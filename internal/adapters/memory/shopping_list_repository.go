@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/shopping"
+)
+
+// ShoppingListRepository implements the shopping.Repository interface in memory
+type ShoppingListRepository struct {
+	mu    sync.RWMutex
+	lists map[shopping.UserID]*shopping.List
+}
+
+// NewShoppingListRepository creates a new in-memory shopping list repository
+func NewShoppingListRepository() *ShoppingListRepository {
+	return &ShoppingListRepository{
+		lists: make(map[shopping.UserID]*shopping.List),
+	}
+}
+
+// Save persists the current shopping list of a user
+func (r *ShoppingListRepository) Save(ctx context.Context, list *shopping.List) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lists[list.UserID()] = list.Clone()
+	return nil
+}
+
+// FindByUserID retrieves the current shopping list of a user
+func (r *ShoppingListRepository) FindByUserID(ctx context.Context, userID shopping.UserID) (*shopping.List, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list, ok := r.lists[userID]
+	if !ok {
+		return nil, shared.ErrShoppingListNotFound
+	}
+	return list.Clone(), nil
+}
+
+// Modify changes the current shopping list of a user under the lock
+func (r *ShoppingListRepository) Modify(ctx context.Context, userID shopping.UserID, change func(list *shopping.List) error) (*shopping.List, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.lists[userID]
+	if !ok {
+		return nil, shared.ErrShoppingListNotFound
+	}
+	list := stored.Clone()
+	if err := change(list); err != nil {
+		return nil, err
+	}
+	r.lists[userID] = list.Clone()
+	return list, nil
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/stats"
+)
+
+// StatsRepository implements the stats.Repository interface in memory
+type StatsRepository struct {
+	mu    sync.RWMutex
+	stats map[stats.UserID]*stats.Stats
+}
+
+// NewStatsRepository creates a new in-memory cooking stats repository
+func NewStatsRepository() *StatsRepository {
+	return &StatsRepository{
+		stats: make(map[stats.UserID]*stats.Stats),
+	}
+}
+
+// FindByUser retrieves the stats of a user
+func (r *StatsRepository) FindByUser(ctx context.Context, userID stats.UserID) (*stats.Stats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.stats[userID]
+	if !ok {
+		return nil, shared.ErrStatsNotFound
+	}
+	return s.Clone(), nil
+}
+
+// Save persists stats
+func (r *StatsRepository) Save(ctx context.Context, s *stats.Stats) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats[s.UserID()] = s.Clone()
+	return nil
+}
+
+// Modify applies a change to a copy of the stats of a user under the lock
+func (r *StatsRepository) Modify(ctx context.Context, userID stats.UserID, change func(s *stats.Stats) error) (*stats.Stats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var s *stats.Stats
+	if stored, ok := r.stats[userID]; ok {
+		s = stored.Clone()
+	} else {
+		var err error
+		if s, err = stats.NewStats(userID); err != nil {
+			return nil, err
+		}
+	}
+	if err := change(s); err != nil {
+		return nil, err
+	}
+	r.stats[userID] = s
+	return s.Clone(), nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/analytics"
+)
+
+// UsageRepository implements the analytics.Repository interface in memory
+type UsageRepository struct {
+	mu    sync.RWMutex
+	weeks map[time.Time]map[analytics.Feature]int
+}
+
+// NewUsageRepository creates a new in-memory usage repository
+func NewUsageRepository() *UsageRepository {
+	return &UsageRepository{
+		weeks: make(map[time.Time]map[analytics.Feature]int),
+	}
+}
+
+// Increment counts one use of a feature in the week starting at week
+func (r *UsageRepository) Increment(ctx context.Context, week time.Time, feature analytics.Feature) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	week = week.UTC()
+	if r.weeks[week] == nil {
+		r.weeks[week] = make(map[analytics.Feature]int)
+	}
+	r.weeks[week][feature]++
+	return nil
+}
+
+// FindSince retrieves the weeks starting at or after since, oldest first
+func (r *UsageRepository) FindSince(ctx context.Context, since time.Time) ([]*analytics.Week, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var weeks []*analytics.Week
+	for start, counts := range r.weeks {
+		if start.Before(since) {
+			continue
+		}
+		cp := make(map[analytics.Feature]int, len(counts))
+		for feature, count := range counts {
+			cp[feature] = count
+		}
+		weeks = append(weeks, &analytics.Week{Start: start, Counts: cp})
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Start.Before(weeks[j].Start) })
+	return weeks, nil
+}
@@ -0,0 +1,374 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// UserRepository implements the user.Repository interface in memory.
+// It is safe for concurrent use and also provides the Notion connection
+// methods used by the Notion exporter.
+type UserRepository struct {
+	mu       sync.RWMutex
+	users    map[user.UserID]*user.User
+	payments map[string]user.Payment // by charge ID
+}
+
+// NewUserRepository creates a new in-memory user repository
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users:    make(map[user.UserID]*user.User),
+		payments: make(map[string]user.Payment),
+	}
+}
+
+// Save persists a user
+func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.users[u.ID()] = u.Clone()
+	return nil
+}
+
+// FindByID retrieves a user by their ID
+func (r *UserRepository) FindByID(ctx context.Context, id user.UserID) (*user.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return nil, shared.ErrUserNotFound
+	}
+	return u.Clone(), nil
+}
+
+// FindByTelegramID retrieves a user by their Telegram ID
+func (r *UserRepository) FindByTelegramID(ctx context.Context, telegramID int64) (*user.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// A linked account resolves to the user it is linked to, even if
+	// it had a collection of its own before
+	var own *user.User
+	for _, u := range r.users {
+		if u.TelegramID() == telegramID {
+			own = u
+		} else if u.HasTelegramID(telegramID) {
+			return u.Clone(), nil
+		}
+	}
+	if own == nil {
+		return nil, shared.ErrUserNotFound
+	}
+	return own.Clone(), nil
+}
+
+// FindAll retrieves every user
+func (r *UserRepository) FindAll(ctx context.Context) ([]*user.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*user.User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u.Clone())
+	}
+	return users, nil
+}
+
+// Update updates an existing user
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	return r.Save(ctx, u)
+}
+
+// UpdatePantry updates only the pantry items for a user
+func (r *UserRepository) UpdatePantry(ctx context.Context, userID user.UserID, items []string) error {
+	return r.modify(userID, func(u *user.User) {
+		u.SetPantryItems(append([]string(nil), items...))
+	})
+}
+
+// ModifyPantry reads and replaces the pantry items for a user under the lock
+func (r *UserRepository) ModifyPantry(ctx context.Context, userID user.UserID, change func(items []string) []string) ([]string, error) {
+	var items []string
+	err := r.modify(userID, func(u *user.User) {
+		items = change(append([]string(nil), u.PantryItems()...))
+		u.SetPantryItems(append([]string(nil), items...))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ModifyLinkedTelegramIDs applies a change to the linked accounts of a user under the lock
+func (r *UserRepository) ModifyLinkedTelegramIDs(ctx context.Context, userID user.UserID, change func(u *user.User) error) (*user.User, error) {
+	return r.modifyUser(userID, change)
+}
+
+// ModifyShortcuts applies a change to the text shortcuts of a user under the lock
+func (r *UserRepository) ModifyShortcuts(ctx context.Context, userID user.UserID, change func(u *user.User) error) (*user.User, error) {
+	return r.modifyUser(userID, change)
+}
+
+// GetPantry retrieves the pantry items for a user
+func (r *UserRepository) GetPantry(ctx context.Context, userID user.UserID) ([]string, error) {
+	u, err := r.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.PantryItems(), nil
+}
+
+// UpdateLanguage updates only the language preference for a user
+func (r *UserRepository) UpdateLanguage(ctx context.Context, userID user.UserID, language user.Language) error {
+	return r.modify(userID, func(u *user.User) {
+		u.SetLanguage(language)
+	})
+}
+
+// UpdateSavedFilters replaces the saved filters for a user
+func (r *UserRepository) UpdateSavedFilters(ctx context.Context, userID user.UserID, filters []user.SavedFilter) error {
+	return r.modify(userID, func(u *user.User) {
+		u.SetSavedFilters(append([]user.SavedFilter(nil), filters...))
+	})
+}
+
+// ModifyClarificationChoices applies a change to how a user answered clarifying
+// questions under the lock
+func (r *UserRepository) ModifyClarificationChoices(ctx context.Context, userID user.UserID, change func(u *user.User) error) (*user.User, error) {
+	return r.modifyUser(userID, change)
+}
+
+// UpdateNotificationSettings replaces the notification choices for a user
+func (r *UserRepository) UpdateNotificationSettings(ctx context.Context, userID user.UserID, settings map[user.Notification]bool) error {
+	return r.modify(userID, func(u *user.User) {
+		cp := make(map[user.Notification]bool, len(settings))
+		for n, enabled := range settings {
+			cp[n] = enabled
+		}
+		u.SetNotificationSettings(cp)
+	})
+}
+
+// ModifyStaples reads and replaces the pantry staples for a user under the lock
+func (r *UserRepository) ModifyStaples(ctx context.Context, userID user.UserID, change func(staples []string) []string) ([]string, error) {
+	var staples []string
+	err := r.modify(userID, func(u *user.User) {
+		var current []string
+		if u.Staples() != nil {
+			current = append([]string{}, u.Staples()...)
+		}
+		staples = change(current)
+		if staples == nil {
+			u.SetStaples(nil)
+			return
+		}
+		u.SetStaples(append([]string{}, staples...))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return staples, nil
+}
+
+// UpdateExportFields replaces the default export fields for a user
+func (r *UserRepository) UpdateExportFields(ctx context.Context, userID user.UserID, fields []string) error {
+	return r.modify(userID, func(u *user.User) {
+		if fields == nil {
+			u.SetExportFields(nil)
+			return
+		}
+		u.SetExportFields(append([]string{}, fields...))
+	})
+}
+
+// UpdateQuietHours replaces the quiet hours for a user
+func (r *UserRepository) UpdateQuietHours(ctx context.Context, userID user.UserID, quiet *user.QuietHours) error {
+	return r.modify(userID, func(u *user.User) {
+		if quiet == nil {
+			u.SetQuietHours(nil)
+			return
+		}
+		cp := *quiet
+		u.SetQuietHours(&cp)
+	})
+}
+
+// UpdateTimezone sets the time zone for a user
+func (r *UserRepository) UpdateTimezone(ctx context.Context, userID user.UserID, timezone string) error {
+	var err error
+	modifyErr := r.modify(userID, func(u *user.User) {
+		err = u.SetTimezone(timezone)
+	})
+	if modifyErr != nil {
+		return modifyErr
+	}
+	return err
+}
+
+// UpdateLocale sets the locale for a user
+func (r *UserRepository) UpdateLocale(ctx context.Context, userID user.UserID, locale user.Locale) error {
+	var err error
+	modifyErr := r.modify(userID, func(u *user.User) {
+		err = u.SetLocale(locale)
+	})
+	if modifyErr != nil {
+		return modifyErr
+	}
+	return err
+}
+
+// UpdatePlainMode turns plain mode on or off for a user
+func (r *UserRepository) UpdatePlainMode(ctx context.Context, userID user.UserID, on bool) error {
+	return r.modify(userID, func(u *user.User) {
+		u.SetPlainMode(on)
+	})
+}
+
+// UpdateDiet sets the diet a user's recipes are checked against
+func (r *UserRepository) UpdateDiet(ctx context.Context, userID user.UserID, diet string) error {
+	return r.modify(userID, func(u *user.User) {
+		u.SetDiet(diet)
+	})
+}
+
+// UpdateClipTokenVersion sets the version of a user's browser extension token
+func (r *UserRepository) UpdateClipTokenVersion(ctx context.Context, userID user.UserID, version int) error {
+	return r.modify(userID, func(u *user.User) {
+		u.SetClipTokenVersion(version)
+	})
+}
+
+// UpdateKeepTranscripts sets whether a user's recipes keep their transcripts and captions
+func (r *UserRepository) UpdateKeepTranscripts(ctx context.Context, userID user.UserID, keep bool) error {
+	return r.modify(userID, func(u *user.User) {
+		u.SetKeepTranscripts(keep)
+	})
+}
+
+// UpdateAutoExport replaces the auto-export for a user
+func (r *UserRepository) UpdateAutoExport(ctx context.Context, userID user.UserID, autoExport *user.AutoExport) error {
+	return r.modify(userID, func(u *user.User) {
+		if autoExport == nil {
+			u.SetAutoExport(nil)
+			return
+		}
+		cp := *autoExport
+		u.SetAutoExport(&cp)
+	})
+}
+
+// UpdateCloudStorage replaces the connected cloud storage for a user
+func (r *UserRepository) UpdateCloudStorage(ctx context.Context, userID user.UserID, storage *user.CloudStorage) error {
+	return r.modify(userID, func(u *user.User) {
+		if storage == nil {
+			u.SetCloudStorage(nil)
+			return
+		}
+		cp := *storage
+		u.SetCloudStorage(&cp)
+	})
+}
+
+// RecordPayment extends a user's premium tier and saves the payment under the lock
+func (r *UserRepository) RecordPayment(ctx context.Context, payment user.Payment) (*user.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if recorded, ok := r.payments[payment.ChargeID]; ok {
+		return &recorded, shared.ErrPaymentRecorded
+	}
+	u, ok := r.users[payment.UserID]
+	if !ok {
+		return nil, shared.ErrUserNotFound
+	}
+
+	payment.PremiumUntil = u.ExtendPremium(payment.Days, payment.PaidAt)
+	r.payments[payment.ChargeID] = payment
+	return &payment, nil
+}
+
+// UpdatePremium sets when the user's premium tier ends; nil removes it
+func (r *UserRepository) UpdatePremium(ctx context.Context, userID user.UserID, until *time.Time) error {
+	return r.modify(userID, func(u *user.User) {
+		if until == nil {
+			u.SetPremiumUntil(nil)
+			return
+		}
+		cp := *until
+		u.SetPremiumUntil(&cp)
+	})
+}
+
+// UpdateNotionConnection updates the Notion connection for a user
+func (r *UserRepository) UpdateNotionConnection(ctx context.Context, userID user.UserID, accessToken, workspaceID, databaseID string) error {
+	return r.modify(userID, func(u *user.User) {
+		u.SetNotionConnection(accessToken, workspaceID, databaseID)
+	})
+}
+
+// ClearNotionConnection removes the Notion connection for a user
+func (r *UserRepository) ClearNotionConnection(ctx context.Context, userID user.UserID) error {
+	return r.modify(userID, func(u *user.User) {
+		u.ClearNotionConnection()
+	})
+}
+
+// UpdateNotionProperties replaces how recipe fields map to the user's Notion database properties
+func (r *UserRepository) UpdateNotionProperties(ctx context.Context, userID user.UserID, properties user.NotionProperties) error {
+	return r.modify(userID, func(u *user.User) {
+		var cp user.NotionProperties
+		if properties != nil {
+			cp = make(user.NotionProperties, len(properties))
+			for field, property := range properties {
+				cp[field] = property
+			}
+		}
+		u.SetNotionProperties(cp)
+	})
+}
+
+// GetNotionConnection retrieves Notion connection details for a user
+func (r *UserRepository) GetNotionConnection(ctx context.Context, userID user.UserID) (accessToken, workspaceID, databaseID string, connectedAt *time.Time, err error) {
+	u, err := r.FindByID(ctx, userID)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	return u.NotionAccessToken(), u.NotionWorkspaceID(), u.NotionDatabaseID(), u.NotionConnectedAt(), nil
+}
+
+// modify applies a change to a stored user under the write lock
+func (r *UserRepository) modify(userID user.UserID, change func(*user.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[userID]
+	if !ok {
+		return shared.ErrUserNotFound
+	}
+	change(u)
+	return nil
+}
+
+// modifyUser applies a change that may fail to a copy of a user under the lock,
+// storing it only when change succeeds
+func (r *UserRepository) modifyUser(userID user.UserID, change func(*user.User) error) (*user.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok {
+		return nil, shared.ErrUserNotFound
+	}
+	u := stored.Clone()
+	if err := change(u); err != nil {
+		return nil, err
+	}
+	r.users[userID] = u
+	return u.Clone(), nil
+}
@@ -0,0 +1,65 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// checkCode is the authorization code sent to check the client credentials. Notion
+// rejects it, saying whether it rejected the code or the client.
+const checkCode = "preflight-check"
+
+// CheckCredentials verifies Notion accepts the OAuth client ID and secret, by
+// exchanging an authorization code that cannot be valid
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	return c.checkCredentials(ctx, notionTokenURL)
+}
+
+// checkCredentials exchanges checkCode at tokenURL. An invalid_client error means
+// the credentials are wrong; any other refusal concerns the code, so they are right.
+func (c *Client) checkCredentials(ctx context.Context, tokenURL string) error {
+	if c.config.ClientID == "" || c.config.ClientSecret == "" {
+		return fmt.Errorf("Notion client ID and secret are required")
+	}
+
+	jsonData, err := json.Marshal(map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         checkCode,
+		"redirect_uri": c.config.RedirectURI,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.config.ClientID, c.config.ClientSecret)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Notion: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	var oauthErr struct {
+		Error string `json:"error"`
+	}
+	_ = json.Unmarshal(body, &oauthErr)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || oauthErr.Error == "invalid_client":
+		return fmt.Errorf("Notion refused the client credentials: %s", string(body))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("Notion answered with status %d: %s", resp.StatusCode, string(body))
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,34 @@
+package notion
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_CheckCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		w.Header().Set("Content-Type", "application/json")
+		if id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant","error_description":"Invalid code."}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	valid := NewClient(Config{ClientID: "client", ClientSecret: "secret"})
+	if err := valid.checkCredentials(ctx, server.URL); err != nil {
+		t.Errorf("checkCredentials() with valid credentials error = %v", err)
+	}
+
+	wrong := NewClient(Config{ClientID: "client", ClientSecret: "wrong"})
+	if err := wrong.checkCredentials(ctx, server.URL); err == nil {
+		t.Error("expected an error for credentials Notion refuses")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 )
 
@@ -29,6 +30,10 @@ type Config struct {
 type Client struct {
 	config     Config
 	httpClient *http.Client
+	wait       func(ctx context.Context, d time.Duration) error
+
+	mu   sync.Mutex
+	next time.Time // when the next API request may be sent
 }
 
 // NewClient creates a new Notion API client
@@ -38,6 +43,16 @@ func NewClient(config Config) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		wait: func(ctx context.Context, d time.Duration) error {
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+				return nil
+			}
+		},
 	}
 }
 
@@ -116,6 +131,39 @@ type Database struct {
 	Title []struct {
 		PlainText string `json:"plain_text"`
 	} `json:"title"`
+	// Properties are the database's columns by name, e.g. "Name" of type "title"
+	Properties map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+}
+
+// GetDatabase retrieves a database, including its properties
+func (c *Client) GetDatabase(ctx context.Context, accessToken string, databaseID string) (*Database, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", notionAPIURL+"/databases/"+databaseID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Notion-Version", notionAPIVersion)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("get database failed: %s", string(body))
+	}
+
+	var db Database
+	if err := json.NewDecoder(resp.Body).Decode(&db); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &db, nil
 }
 
 // SearchDatabases searches for databases the integration has access to
@@ -141,7 +189,7 @@ func (c *Client) SearchDatabases(ctx context.Context, accessToken string) ([]Dat
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Notion-Version", notionAPIVersion)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search databases: %w", err)
 	}
@@ -168,8 +216,9 @@ type PageResponse struct {
 	URL string `json:"url"`
 }
 
-// CreatePage creates a new page in a database
-func (c *Client) CreatePage(ctx context.Context, accessToken string, databaseID string, properties map[string]interface{}, children []interface{}) (*PageResponse, error) {
+// CreatePage creates a new page in a database, with the uploaded file coverUploadID
+// as its cover unless empty
+func (c *Client) CreatePage(ctx context.Context, accessToken string, databaseID string, properties map[string]interface{}, children []interface{}, coverUploadID string) (*PageResponse, error) {
 	data := map[string]interface{}{
 		"parent": map[string]string{
 			"database_id": databaseID,
@@ -181,6 +230,15 @@ func (c *Client) CreatePage(ctx context.Context, accessToken string, databaseID
 		data["children"] = children
 	}
 
+	if coverUploadID != "" {
+		data["cover"] = map[string]interface{}{
+			"type": "file_upload",
+			"file_upload": map[string]string{
+				"id": coverUploadID,
+			},
+		}
+	}
+
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -195,7 +253,7 @@ func (c *Client) CreatePage(ctx context.Context, accessToken string, databaseID
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Notion-Version", notionAPIVersion)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create page: %w", err)
 	}
@@ -271,7 +329,7 @@ func (c *Client) CreateDatabase(ctx context.Context, accessToken string, parentP
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Notion-Version", notionAPIVersion)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database: %w", err)
 	}
@@ -3,6 +3,7 @@ package notion
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
 
 	"receipt-bot/internal/domain/recipe"
@@ -15,19 +16,22 @@ type UserRepository interface {
 	FindByID(ctx context.Context, id user.UserID) (*user.User, error)
 	UpdateNotionConnection(ctx context.Context, userID user.UserID, accessToken, workspaceID, databaseID string) error
 	ClearNotionConnection(ctx context.Context, userID user.UserID) error
+	UpdateNotionProperties(ctx context.Context, userID user.UserID, properties user.NotionProperties) error
 }
 
 // Exporter implements the NotionExporter interface
 type Exporter struct {
 	client   *Client
 	userRepo UserRepository
+	blobs    ports.BlobStorage // optional, covers are not exported when nil
 }
 
 // NewExporter creates a new Notion exporter
-func NewExporter(client *Client, userRepo UserRepository) *Exporter {
+func NewExporter(client *Client, userRepo UserRepository, blobs ports.BlobStorage) *Exporter {
 	return &Exporter{
 		client:   client,
 		userRepo: userRepo,
+		blobs:    blobs,
 	}
 }
 
@@ -50,21 +54,28 @@ func (e *Exporter) HandleCallback(ctx context.Context, userID string, code strin
 		return fmt.Errorf("failed to search databases: %w", err)
 	}
 
-	var databaseID string
-	for _, db := range databases {
+	var database *Database
+	for i, db := range databases {
 		// Look for a database that might be for recipes
 		if len(db.Title) > 0 {
 			title := strings.ToLower(db.Title[0].PlainText)
 			if strings.Contains(title, "recipe") || strings.Contains(title, "receita") {
-				databaseID = db.ID
+				database = &databases[i]
 				break
 			}
 		}
 	}
 
 	// If no recipe database found, use the first one (user can change later)
-	if databaseID == "" && len(databases) > 0 {
-		databaseID = databases[0].ID
+	if database == nil && len(databases) > 0 {
+		database = &databases[0]
+	}
+
+	var databaseID string
+	var properties user.NotionProperties
+	if database != nil {
+		databaseID = database.ID
+		properties = DetectProperties(database)
 	}
 
 	// Store credentials
@@ -79,6 +90,11 @@ func (e *Exporter) HandleCallback(ctx context.Context, userID string, code strin
 		return fmt.Errorf("failed to store credentials: %w", err)
 	}
 
+	// Store which of the database's columns each recipe field goes to
+	if err := e.userRepo.UpdateNotionProperties(ctx, user.UserID(userID), properties); err != nil {
+		return fmt.Errorf("failed to store database properties: %w", err)
+	}
+
 	return nil
 }
 
@@ -105,14 +121,20 @@ func (e *Exporter) ExportRecipe(ctx context.Context, userID string, rec *recipe.
 		}, nil
 	}
 
-	// Build properties for the page
-	properties := e.buildProperties(rec)
+	// Build properties for the page, named after the database's own columns
+	properties := e.buildProperties(rec, e.databaseProperties(ctx, usr))
 
 	// Build content blocks
 	children := e.buildContent(rec)
 
+	// Use the recipe's cover as the page's, saying when it was AI-generated
+	coverUploadID := e.uploadCover(ctx, usr, rec)
+	if coverUploadID != "" && rec.Cover().AIGenerated {
+		children = append([]interface{}{coverNotice()}, children...)
+	}
+
 	// Create the page
-	page, err := e.client.CreatePage(ctx, usr.NotionAccessToken(), usr.NotionDatabaseID(), properties, children)
+	page, err := e.client.CreatePage(ctx, usr.NotionAccessToken(), usr.NotionDatabaseID(), properties, children, coverUploadID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create page: %w", err)
 	}
@@ -181,83 +203,80 @@ func (e *Exporter) Disconnect(ctx context.Context, userID string) error {
 	return e.userRepo.ClearNotionConnection(ctx, user.UserID(userID))
 }
 
-// buildProperties builds Notion page properties from a recipe
-func (e *Exporter) buildProperties(rec *recipe.Recipe) map[string]interface{} {
-	props := map[string]interface{}{
-		"Name": map[string]interface{}{
-			"title": []map[string]interface{}{
-				{
-					"text": map[string]string{
-						"content": rec.Title(),
-					},
-				},
-			},
-		},
+// uploadCover uploads a recipe's cover to the user's workspace, returning its upload
+// ID or "" when the recipe has none. A cover that fails to upload is left out
+// rather than failing the export.
+func (e *Exporter) uploadCover(ctx context.Context, usr *user.User, rec *recipe.Recipe) string {
+	cover := rec.Cover()
+	if cover == nil || e.blobs == nil {
+		return ""
 	}
 
-	// Category
-	if rec.Category() != "" {
-		props["Category"] = map[string]interface{}{
-			"select": map[string]string{
-				"name": string(rec.Category()),
-			},
-		}
+	image, err := e.blobs.Get(ctx, cover.Key)
+	if err != nil {
+		log.Printf("Failed to get cover of recipe %s: %v", rec.ID(), err)
+		return ""
 	}
+	uploadID, err := e.client.UploadFile(ctx, usr.NotionAccessToken(), "cover.png", "image/png", image)
+	if err != nil {
+		log.Printf("Failed to upload cover of recipe %s to Notion: %v", rec.ID(), err)
+		return ""
+	}
+	return uploadID
+}
 
-	// Cuisine
-	if rec.Cuisine() != "" {
-		props["Cuisine"] = map[string]interface{}{
+// coverNotice is the block flagging a page's cover as AI-generated
+func coverNotice() map[string]interface{} {
+	return map[string]interface{}{
+		"object": "block",
+		"type":   "callout",
+		"callout": map[string]interface{}{
+			"icon": map[string]string{
+				"type":  "emoji",
+				"emoji": "🤖",
+			},
 			"rich_text": []map[string]interface{}{
 				{
+					"type": "text",
 					"text": map[string]string{
-						"content": rec.Cuisine(),
+						"content": "The cover image is AI-generated and does not show the actual dish.",
 					},
 				},
 			},
-		}
-	}
-
-	// Prep Time
-	if rec.PrepTime() != nil {
-		props["Prep Time"] = map[string]interface{}{
-			"number": int(rec.PrepTime().Minutes()),
-		}
-	}
-
-	// Cook Time
-	if rec.CookTime() != nil {
-		props["Cook Time"] = map[string]interface{}{
-			"number": int(rec.CookTime().Minutes()),
-		}
+		},
 	}
+}
 
-	// Servings
-	if rec.Servings() != nil {
-		props["Servings"] = map[string]interface{}{
-			"number": *rec.Servings(),
-		}
+// databaseProperties returns which of the user's database columns each recipe
+// field goes to, detecting them when the user connected before they were stored
+func (e *Exporter) databaseProperties(ctx context.Context, usr *user.User) user.NotionProperties {
+	if properties := usr.NotionProperties(); properties != nil {
+		return properties
 	}
 
-	// Source URL
-	if rec.Source().URL() != "" {
-		props["Source URL"] = map[string]interface{}{
-			"url": rec.Source().URL(),
-		}
+	db, err := e.client.GetDatabase(ctx, usr.NotionAccessToken(), usr.NotionDatabaseID())
+	if err != nil {
+		return defaultProperties()
 	}
+	properties := DetectProperties(db)
+	// Not storing them only means detecting them again on the next export
+	_ = e.userRepo.UpdateNotionProperties(ctx, usr.ID(), properties)
+	return properties
+}
 
-	// Tags (dietary tags)
-	if len(rec.DietaryTags()) > 0 {
-		var tags []map[string]string
-		for _, tag := range rec.DietaryTags() {
-			tags = append(tags, map[string]string{
-				"name": string(tag),
-			})
+// buildProperties builds Notion page properties from a recipe, skipping fields
+// the database has no column for
+func (e *Exporter) buildProperties(rec *recipe.Recipe, columns user.NotionProperties) map[string]interface{} {
+	props := make(map[string]interface{})
+	for field, value := range recipeValues(rec) {
+		column, ok := columns[field]
+		if !ok {
+			continue
 		}
-		props["Tags"] = map[string]interface{}{
-			"multi_select": tags,
+		if property, ok := value.property(column.Type); ok {
+			props[column.Name] = property
 		}
 	}
-
 	return props
 }
 
@@ -265,96 +284,129 @@ func (e *Exporter) buildProperties(rec *recipe.Recipe) map[string]interface{} {
 func (e *Exporter) buildContent(rec *recipe.Recipe) []interface{} {
 	var blocks []interface{}
 
-	// Ingredients heading
-	blocks = append(blocks, map[string]interface{}{
-		"object": "block",
-		"type":   "heading_2",
-		"heading_2": map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": map[string]string{
-						"content": "Ingredients",
+	// Sections left out of the export are empty, see recipe.WithFields
+	if len(rec.Ingredients()) > 0 {
+		// Ingredients heading
+		blocks = append(blocks, map[string]interface{}{
+			"object": "block",
+			"type":   "heading_2",
+			"heading_2": map[string]interface{}{
+				"rich_text": []map[string]interface{}{
+					{
+						"type": "text",
+						"text": map[string]string{
+							"content": "Ingredients",
+						},
 					},
 				},
 			},
-		},
-	})
+		})
+
+		// Ingredients as bulleted list
+		for _, ing := range rec.Ingredients() {
+			text := formatIngredient(ing)
+			blocks = append(blocks, map[string]interface{}{
+				"object": "block",
+				"type":   "bulleted_list_item",
+				"bulleted_list_item": map[string]interface{}{
+					"rich_text": []map[string]interface{}{
+						{
+							"type": "text",
+							"text": map[string]string{
+								"content": text,
+							},
+						},
+					},
+				},
+			})
+		}
+	}
 
-	// Ingredients as bulleted list
-	for _, ing := range rec.Ingredients() {
-		text := formatIngredient(ing)
+	if len(rec.Instructions()) > 0 {
+		// Instructions heading
 		blocks = append(blocks, map[string]interface{}{
 			"object": "block",
-			"type":   "bulleted_list_item",
-			"bulleted_list_item": map[string]interface{}{
+			"type":   "heading_2",
+			"heading_2": map[string]interface{}{
 				"rich_text": []map[string]interface{}{
 					{
 						"type": "text",
 						"text": map[string]string{
-							"content": text,
+							"content": "Instructions",
 						},
 					},
 				},
 			},
 		})
-	}
 
-	// Instructions heading
-	blocks = append(blocks, map[string]interface{}{
-		"object": "block",
-		"type":   "heading_2",
-		"heading_2": map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": map[string]string{
-						"content": "Instructions",
+		// Instructions as numbered list
+		for i, inst := range rec.Instructions() {
+			stepNum := inst.StepNumber()
+			if stepNum == 0 {
+				stepNum = i + 1
+			}
+			text := inst.Text()
+			blocks = append(blocks, map[string]interface{}{
+				"object": "block",
+				"type":   "numbered_list_item",
+				"numbered_list_item": map[string]interface{}{
+					"rich_text": []map[string]interface{}{
+						{
+							"type": "text",
+							"text": map[string]string{
+								"content": text,
+							},
+						},
 					},
 				},
-			},
-		},
-	})
-
-	// Instructions as numbered list
-	for i, inst := range rec.Instructions() {
-		stepNum := inst.StepNumber()
-		if stepNum == 0 {
-			stepNum = i + 1
+			})
 		}
-		text := inst.Text()
+	}
+
+	// Transcript, when the export includes it
+	if rec.Transcript() != "" {
 		blocks = append(blocks, map[string]interface{}{
 			"object": "block",
-			"type":   "numbered_list_item",
-			"numbered_list_item": map[string]interface{}{
+			"type":   "heading_2",
+			"heading_2": map[string]interface{}{
 				"rich_text": []map[string]interface{}{
 					{
 						"type": "text",
 						"text": map[string]string{
-							"content": text,
+							"content": "Transcript",
 						},
 					},
 				},
 			},
 		})
+
+		// Notion caps a text object at 2000 characters
+		for _, chunk := range splitText(strings.TrimSpace(rec.Transcript()), 2000) {
+			blocks = append(blocks, map[string]interface{}{
+				"object": "block",
+				"type":   "paragraph",
+				"paragraph": map[string]interface{}{
+					"rich_text": []map[string]interface{}{
+						{
+							"type": "text",
+							"text": map[string]string{
+								"content": chunk,
+							},
+						},
+					},
+				},
+			})
+		}
 	}
 
-	// Source section
-	if rec.Source().URL() != "" {
+	// Source section, crediting the recipe's creator
+	if credit := rec.Source().Credit(); credit != "" {
 		blocks = append(blocks, map[string]interface{}{
 			"object": "block",
 			"type":   "divider",
 			"divider": map[string]interface{}{},
 		})
 
-		sourceText := "Original Recipe"
-		if rec.Source().Author() != "" {
-			sourceText += " by " + rec.Source().Author()
-		}
-		if rec.Source().Platform() != "" {
-			sourceText += " on " + string(rec.Source().Platform())
-		}
-
 		blocks = append(blocks, map[string]interface{}{
 			"object": "block",
 			"type":   "paragraph",
@@ -363,9 +415,9 @@ func (e *Exporter) buildContent(rec *recipe.Recipe) []interface{} {
 					{
 						"type": "text",
 						"text": map[string]interface{}{
-							"content": sourceText,
+							"content": credit,
 							"link": map[string]string{
-								"url": rec.Source().URL(),
+								"url": rec.Source().OriginalURL(),
 							},
 						},
 					},
@@ -397,3 +449,14 @@ func formatIngredient(ing recipe.Ingredient) string {
 
 	return result
 }
+
+// splitText splits text into chunks of at most size runes
+func splitText(text string, size int) []string {
+	runes := []rune(text)
+	var chunks []string
+	for len(runes) > size {
+		chunks = append(chunks, string(runes[:size]))
+		runes = runes[size:]
+	}
+	return append(chunks, string(runes))
+}
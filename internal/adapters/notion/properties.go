@@ -0,0 +1,213 @@
+package notion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/user"
+)
+
+// propertyAliases are the column names, lowercased, a database may use for each
+// recipe field, in order of preference. The title needs none: every database has
+// exactly one title column.
+var propertyAliases = map[user.NotionField][]string{
+	user.NotionFieldCategory:  {"category", "categoria", "course", "meal", "type", "tipo"},
+	user.NotionFieldCuisine:   {"cuisine", "cozinha", "culinária", "culinaria", "origin"},
+	user.NotionFieldPrepTime:  {"prep time", "preparation time", "prep", "prep (min)", "tempo de preparo", "preparo"},
+	user.NotionFieldCookTime:  {"cook time", "cooking time", "cook", "cook (min)", "tempo de cozimento", "cozimento"},
+	user.NotionFieldServings:  {"servings", "serves", "yield", "porções", "porcoes", "rendimento"},
+	user.NotionFieldSourceURL: {"source url", "source", "url", "link", "fonte"},
+	user.NotionFieldTags:      {"tags", "dietary tags", "diet", "dieta", "etiquetas"},
+}
+
+// propertyTypes are the property types each recipe field can be written to
+var propertyTypes = map[user.NotionField][]string{
+	user.NotionFieldTitle:     {"title"},
+	user.NotionFieldCategory:  {"select", "multi_select", "rich_text"},
+	user.NotionFieldCuisine:   {"rich_text", "select", "multi_select"},
+	user.NotionFieldPrepTime:  {"number", "rich_text"},
+	user.NotionFieldCookTime:  {"number", "rich_text"},
+	user.NotionFieldServings:  {"number", "rich_text"},
+	user.NotionFieldSourceURL: {"url", "rich_text"},
+	user.NotionFieldTags:      {"multi_select", "rich_text"},
+}
+
+// defaultProperties are the columns of a database created for recipes, used
+// when the user's database could not be read
+func defaultProperties() user.NotionProperties {
+	return user.NotionProperties{
+		user.NotionFieldTitle:     {Name: "Name", Type: "title"},
+		user.NotionFieldCategory:  {Name: "Category", Type: "select"},
+		user.NotionFieldCuisine:   {Name: "Cuisine", Type: "rich_text"},
+		user.NotionFieldPrepTime:  {Name: "Prep Time", Type: "number"},
+		user.NotionFieldCookTime:  {Name: "Cook Time", Type: "number"},
+		user.NotionFieldServings:  {Name: "Servings", Type: "number"},
+		user.NotionFieldSourceURL: {Name: "Source URL", Type: "url"},
+		user.NotionFieldTags:      {Name: "Tags", Type: "multi_select"},
+	}
+}
+
+// DetectProperties maps recipe fields to the columns of a database, by name and
+// type. Fields the database has no suitable column for are left out.
+func DetectProperties(db *Database) user.NotionProperties {
+	names := make([]string, 0, len(db.Properties))
+	for name := range db.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties := make(user.NotionProperties)
+	byName := make(map[string]string, len(names))
+	for _, name := range names {
+		if db.Properties[name].Type == "title" {
+			properties[user.NotionFieldTitle] = user.NotionProperty{Name: name, Type: "title"}
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := byName[key]; !ok {
+			byName[key] = name
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, field := range user.AllNotionFields() {
+		for _, alias := range propertyAliases[field] {
+			name, ok := byName[alias]
+			if !ok || used[name] || !acceptsType(field, db.Properties[name].Type) {
+				continue
+			}
+			properties[field] = user.NotionProperty{Name: name, Type: db.Properties[name].Type}
+			used[name] = true
+			break
+		}
+	}
+	return properties
+}
+
+// acceptsType reports whether a recipe field can be written to a property type
+func acceptsType(field user.NotionField, typ string) bool {
+	for _, t := range propertyTypes[field] {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValue is a recipe field's value before it is shaped for a property type
+type fieldValue struct {
+	text   string
+	number *int
+	unit   string // written after numbers in text properties, e.g. "min"
+	names  []string
+}
+
+// recipeValues returns the values of a recipe's fields; empty fields are left out
+func recipeValues(rec *recipe.Recipe) map[user.NotionField]fieldValue {
+	values := map[user.NotionField]fieldValue{
+		user.NotionFieldTitle: {text: rec.Title()},
+	}
+	if rec.Category() != "" {
+		values[user.NotionFieldCategory] = fieldValue{text: string(rec.Category())}
+	}
+	if rec.Cuisine() != "" {
+		values[user.NotionFieldCuisine] = fieldValue{text: rec.Cuisine()}
+	}
+	if rec.PrepTime() != nil {
+		minutes := int(rec.PrepTime().Minutes())
+		values[user.NotionFieldPrepTime] = fieldValue{number: &minutes, unit: " min"}
+	}
+	if rec.CookTime() != nil {
+		minutes := int(rec.CookTime().Minutes())
+		values[user.NotionFieldCookTime] = fieldValue{number: &minutes, unit: " min"}
+	}
+	if rec.Servings() != nil {
+		servings := *rec.Servings()
+		values[user.NotionFieldServings] = fieldValue{number: &servings}
+	}
+	if rec.Source().URL() != "" {
+		values[user.NotionFieldSourceURL] = fieldValue{text: rec.Source().URL()}
+	}
+	if len(rec.DietaryTags()) > 0 {
+		var tags []string
+		for _, tag := range rec.DietaryTags() {
+			tags = append(tags, string(tag))
+		}
+		values[user.NotionFieldTags] = fieldValue{names: tags}
+	}
+	return values
+}
+
+// String returns the value as plain text
+func (v fieldValue) String() string {
+	switch {
+	case v.number != nil:
+		return fmt.Sprintf("%d%s", *v.number, v.unit)
+	case len(v.names) > 0:
+		return strings.Join(v.names, ", ")
+	default:
+		return v.text
+	}
+}
+
+// property shapes the value for a property type. Returns false if the type
+// cannot hold it, e.g. text in a number column.
+func (v fieldValue) property(typ string) (interface{}, bool) {
+	names := v.names
+	if len(names) == 0 && v.text != "" {
+		names = []string{v.text}
+	}
+
+	switch typ {
+	case "title", "rich_text":
+		return map[string]interface{}{
+			typ: []map[string]interface{}{
+				{
+					"text": map[string]string{
+						"content": v.String(),
+					},
+				},
+			},
+		}, true
+	case "select":
+		if len(names) == 0 {
+			return nil, false
+		}
+		return map[string]interface{}{
+			"select": map[string]string{
+				"name": names[0],
+			},
+		}, true
+	case "multi_select":
+		if len(names) == 0 {
+			return nil, false
+		}
+		var options []map[string]string
+		for _, name := range names {
+			options = append(options, map[string]string{
+				"name": name,
+			})
+		}
+		return map[string]interface{}{
+			"multi_select": options,
+		}, true
+	case "number":
+		if v.number == nil {
+			return nil, false
+		}
+		return map[string]interface{}{
+			"number": *v.number,
+		}, true
+	case "url":
+		if v.text == "" {
+			return nil, false
+		}
+		return map[string]interface{}{
+			"url": v.text,
+		}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,76 @@
+package notion
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/user"
+)
+
+func testDatabase(t *testing.T, properties string) *Database {
+	t.Helper()
+	var db Database
+	if err := json.Unmarshal([]byte(`{"id":"db-1","properties":`+properties+`}`), &db); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	return &db
+}
+
+func TestDetectProperties(t *testing.T) {
+	db := testDatabase(t, `{
+		"Recipe": {"type": "title"},
+		"Course": {"type": "select"},
+		"Prep Time": {"type": "rich_text"},
+		"Servings": {"type": "checkbox"},
+		"Link": {"type": "url"},
+		"Diet": {"type": "multi_select"}
+	}`)
+
+	got := DetectProperties(db)
+	want := user.NotionProperties{
+		user.NotionFieldTitle:     {Name: "Recipe", Type: "title"},
+		user.NotionFieldCategory:  {Name: "Course", Type: "select"},
+		user.NotionFieldPrepTime:  {Name: "Prep Time", Type: "rich_text"},
+		user.NotionFieldSourceURL: {Name: "Link", Type: "url"},
+		user.NotionFieldTags:      {Name: "Diet", Type: "multi_select"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DetectProperties() = %v, want %v", got, want)
+	}
+	for field, property := range want {
+		if got[field] != property {
+			t.Errorf("DetectProperties()[%s] = %+v, want %+v", field, got[field], property)
+		}
+	}
+}
+
+func TestExporter_BuildProperties(t *testing.T) {
+	ing, _ := recipe.NewIngredient("flour", "2", "cups", "")
+	inst, _ := recipe.NewInstruction(1, "Mix", nil)
+	source, _ := recipe.NewSource("https://example.com/lasagna", recipe.PlatformWeb, "Chef")
+	rec, _ := recipe.NewRecipe("user-1", "Lasagna", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+	rec.SetCategory(recipe.CategorySoups)
+	rec.SetPrepTime(20 * time.Minute)
+	rec.SetServings(4)
+
+	columns := user.NotionProperties{
+		user.NotionFieldTitle:     {Name: "Recipe", Type: "title"},
+		user.NotionFieldCategory:  {Name: "Course", Type: "multi_select"},
+		user.NotionFieldPrepTime:  {Name: "Prep", Type: "rich_text"},
+		user.NotionFieldSourceURL: {Name: "Link", Type: "url"},
+	}
+	data, err := json.Marshal(new(Exporter).buildProperties(rec, columns))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"Course":{"multi_select":[{"name":"Soups \u0026 Stews"}]},` +
+		`"Link":{"url":"https://example.com/lasagna"},` +
+		`"Prep":{"rich_text":[{"text":{"content":"20 min"}}]},` +
+		`"Recipe":{"title":[{"text":{"content":"Lasagna"}}]}}`
+	if string(data) != want {
+		t.Errorf("buildProperties() = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,75 @@
+package notion
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Notion allows an integration about three requests per second on average and
+// rejects requests beyond that with 429 and a Retry-After header
+const (
+	requestInterval   = 350 * time.Millisecond
+	maxRetries        = 3
+	defaultRetryAfter = time.Second
+)
+
+// do sends an API request, spacing requests out to stay under Notion's rate limit
+// and retrying the ones Notion rejects for exceeding it. Once the retries run out
+// it returns an error wrapping shared.ErrNotionRateLimited.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.throttle(req); err != nil {
+			return nil, err
+		}
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		resp.Body.Close()
+
+		if attempt == maxRetries {
+			return nil, fmt.Errorf("%w after %d retries", shared.ErrNotionRateLimited, maxRetries)
+		}
+		if err := c.wait(req.Context(), retryAfter(resp)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// throttle waits until requestInterval has passed since the previous request
+func (c *Client) throttle(req *http.Request) error {
+	c.mu.Lock()
+	now := time.Now()
+	wait := c.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	c.next = now.Add(wait + requestInterval)
+	c.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	return c.wait(req.Context(), wait)
+}
+
+// retryAfter returns how long Notion asks to wait before retrying a request
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return defaultRetryAfter
+	}
+	return time.Duration(seconds) * time.Second
+}
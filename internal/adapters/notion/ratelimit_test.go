@@ -0,0 +1,71 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestClient_Do_RetriesRateLimited(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{})
+	var waits []time.Duration
+	client.wait = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), "POST", server.URL, strings.NewReader(`{"page":1}`))
+	resp, err := client.do(req)
+	if err != nil {
+		t.Fatalf("do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 3 || bodies[2] != `{"page":1}` {
+		t.Errorf("requests = %q, want the body sent three times", bodies)
+	}
+	var retries int
+	for _, d := range waits {
+		if d == 2*time.Second {
+			retries++
+		}
+	}
+	if retries != 2 {
+		t.Errorf("waits = %v, want two waits of the Retry-After", waits)
+	}
+}
+
+func TestClient_Do_GivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{})
+	client.wait = func(ctx context.Context, d time.Duration) error { return nil }
+
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", server.URL, nil)
+	if _, err := client.do(req); !errors.Is(err, shared.ErrNotionRateLimited) {
+		t.Errorf("do() error = %v, want ErrNotionRateLimited", err)
+	}
+}
@@ -0,0 +1,87 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+)
+
+// UploadFile uploads a file to the workspace, returning the ID pages refer to it by
+func (c *Client) UploadFile(ctx context.Context, accessToken string, filename, contentType string, data []byte) (string, error) {
+	jsonData, err := json.Marshal(map[string]string{
+		"filename":     filename,
+		"content_type": contentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", notionAPIURL+"/file_uploads", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var upload struct {
+		ID string `json:"id"`
+	}
+	if err := c.sendUpload(req, accessToken, &upload); err != nil {
+		return "", fmt.Errorf("failed to create file upload: %w", err)
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filename))
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, "POST", notionAPIURL+"/file_uploads/"+upload.ID+"/send", &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if err := c.sendUpload(req, accessToken, nil); err != nil {
+		return "", fmt.Errorf("failed to send file: %w", err)
+	}
+
+	return upload.ID, nil
+}
+
+// sendUpload sends a file upload request, decoding the response into result if not nil
+func (c *Client) sendUpload(req *http.Request, accessToken string, result interface{}) error {
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Notion-Version", notionAPIVersion)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("upload failed: %s", string(body))
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
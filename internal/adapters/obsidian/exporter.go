@@ -21,8 +21,8 @@ func NewExporter() *Exporter {
 }
 
 // ExportRecipe exports a single recipe as Obsidian-compatible markdown
-func (e *Exporter) ExportRecipe(rec *recipe.Recipe) (*ports.ExportResult, error) {
-	markdown := e.generateMarkdown(rec)
+func (e *Exporter) ExportRecipe(rec *recipe.Recipe, loc *time.Location) (*ports.ExportResult, error) {
+	markdown := e.generateMarkdown(rec, loc)
 	filename := e.sanitizeFilename(rec.Title()) + ".md"
 
 	return &ports.ExportResult{
@@ -35,7 +35,7 @@ func (e *Exporter) ExportRecipe(rec *recipe.Recipe) (*ports.ExportResult, error)
 }
 
 // ExportRecipes exports multiple recipes as a ZIP file
-func (e *Exporter) ExportRecipes(recipes []*recipe.Recipe) (*ports.ExportResult, error) {
+func (e *Exporter) ExportRecipes(recipes []*recipe.Recipe, loc *time.Location) (*ports.ExportResult, error) {
 	if len(recipes) == 0 {
 		return &ports.ExportResult{
 			Success: false,
@@ -49,7 +49,7 @@ func (e *Exporter) ExportRecipes(recipes []*recipe.Recipe) (*ports.ExportResult,
 	zipWriter := zip.NewWriter(buf)
 
 	for _, rec := range recipes {
-		markdown := e.generateMarkdown(rec)
+		markdown := e.generateMarkdown(rec, loc)
 		filename := e.sanitizeFilename(rec.Title()) + ".md"
 
 		writer, err := zipWriter.Create(filename)
@@ -69,20 +69,23 @@ func (e *Exporter) ExportRecipes(recipes []*recipe.Recipe) (*ports.ExportResult,
 	return &ports.ExportResult{
 		Success:  true,
 		Format:   "obsidian",
-		Filename: fmt.Sprintf("recipes_%s.zip", time.Now().Format("2006-01-02")),
+		Filename: fmt.Sprintf("recipes_%s.zip", time.Now().In(loc).Format("2006-01-02")),
 		Data:     buf.Bytes(),
 		Message:  fmt.Sprintf("Exported %d recipes", len(recipes)),
 	}, nil
 }
 
-// generateMarkdown generates Obsidian-compatible markdown with YAML frontmatter
-func (e *Exporter) generateMarkdown(rec *recipe.Recipe) string {
+// generateMarkdown generates Obsidian-compatible markdown with YAML frontmatter,
+// dated in the time zone loc
+func (e *Exporter) generateMarkdown(rec *recipe.Recipe, loc *time.Location) string {
 	var sb strings.Builder
 
 	// YAML Frontmatter
 	sb.WriteString("---\n")
 	sb.WriteString(fmt.Sprintf("title: \"%s\"\n", escapeYAML(rec.Title())))
-	sb.WriteString(fmt.Sprintf("category: %s\n", rec.Category()))
+	if rec.Category() != "" {
+		sb.WriteString(fmt.Sprintf("category: %s\n", rec.Category()))
+	}
 
 	if rec.Cuisine() != "" {
 		sb.WriteString(fmt.Sprintf("cuisine: %s\n", rec.Cuisine()))
@@ -114,7 +117,9 @@ func (e *Exporter) generateMarkdown(rec *recipe.Recipe) string {
 		sb.WriteString(fmt.Sprintf("servings: %d\n", *rec.Servings()))
 	}
 
-	sb.WriteString(fmt.Sprintf("source_url: %s\n", rec.Source().URL()))
+	if rec.Source().URL() != "" {
+		sb.WriteString(fmt.Sprintf("source_url: %s\n", rec.Source().URL()))
+	}
 
 	if rec.Source().Platform() != "" {
 		sb.WriteString(fmt.Sprintf("source_platform: %s\n", rec.Source().Platform()))
@@ -124,8 +129,20 @@ func (e *Exporter) generateMarkdown(rec *recipe.Recipe) string {
 		sb.WriteString(fmt.Sprintf("source_author: \"%s\"\n", escapeYAML(rec.Source().Author())))
 	}
 
-	sb.WriteString(fmt.Sprintf("created: %s\n", rec.CreatedAt().Format("2006-01-02")))
-	sb.WriteString(fmt.Sprintf("updated: %s\n", rec.UpdatedAt().Format("2006-01-02")))
+	if attribution := rec.Source().Attribution(); !attribution.IsZero() {
+		if attribution.Handle != "" {
+			sb.WriteString(fmt.Sprintf("source_handle: \"%s\"\n", escapeYAML(attribution.Handle)))
+		}
+		if attribution.CanonicalURL != "" {
+			sb.WriteString(fmt.Sprintf("source_canonical_url: %s\n", attribution.CanonicalURL))
+		}
+		if !attribution.PublishedAt.IsZero() {
+			sb.WriteString(fmt.Sprintf("source_published: %s\n", attribution.PublishedAt.Format("2006-01-02")))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("created: %s\n", rec.CreatedAt().In(loc).Format("2006-01-02")))
+	sb.WriteString(fmt.Sprintf("updated: %s\n", rec.UpdatedAt().In(loc).Format("2006-01-02")))
 	sb.WriteString("---\n\n")
 
 	// Title
@@ -167,35 +184,37 @@ func (e *Exporter) generateMarkdown(rec *recipe.Recipe) string {
 		sb.WriteString(strings.Join(tags, " ") + "\n\n")
 	}
 
-	// Ingredients
-	sb.WriteString("## Ingredients\n\n")
-	for _, ing := range rec.Ingredients() {
-		ingredient := formatIngredient(ing)
-		sb.WriteString(fmt.Sprintf("- %s\n", ingredient))
+	// Sections left out of the export are empty, see recipe.WithFields
+	if len(rec.Ingredients()) > 0 {
+		sb.WriteString("## Ingredients\n\n")
+		for _, ing := range rec.Ingredients() {
+			ingredient := formatIngredient(ing)
+			sb.WriteString(fmt.Sprintf("- %s\n", ingredient))
+		}
+		sb.WriteString("\n")
 	}
-	sb.WriteString("\n")
 
-	// Instructions
-	sb.WriteString("## Instructions\n\n")
-	for i, inst := range rec.Instructions() {
-		stepNum := inst.StepNumber()
-		if stepNum == 0 {
-			stepNum = i + 1
+	if len(rec.Instructions()) > 0 {
+		sb.WriteString("## Instructions\n\n")
+		for i, inst := range rec.Instructions() {
+			stepNum := inst.StepNumber()
+			if stepNum == 0 {
+				stepNum = i + 1
+			}
+			sb.WriteString(fmt.Sprintf("%d. %s\n", stepNum, inst.Text()))
 		}
-		sb.WriteString(fmt.Sprintf("%d. %s\n", stepNum, inst.Text()))
+		sb.WriteString("\n")
 	}
-	sb.WriteString("\n")
 
-	// Source
-	sb.WriteString("## Source\n\n")
-	sb.WriteString(fmt.Sprintf("[Original Recipe](%s)", rec.Source().URL()))
-	if rec.Source().Author() != "" {
-		sb.WriteString(fmt.Sprintf(" by %s", rec.Source().Author()))
+	if rec.Transcript() != "" {
+		sb.WriteString("## Transcript\n\n")
+		sb.WriteString(strings.TrimSpace(rec.Transcript()) + "\n\n")
 	}
-	if rec.Source().Platform() != "" {
-		sb.WriteString(fmt.Sprintf(" on %s", rec.Source().Platform()))
+
+	if credit := rec.Source().Credit(); credit != "" {
+		sb.WriteString("## Source\n\n")
+		sb.WriteString(credit + "\n")
 	}
-	sb.WriteString("\n")
 
 	return sb.String()
 }
@@ -0,0 +1,120 @@
+// Package openaiimages draws images with the OpenAI Images API.
+package openaiimages
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBaseURL = "https://api.openai.com"
+	defaultModel   = "gpt-image-1"
+	imageSize      = "1024x1024"
+)
+
+// Config holds Images API client configuration
+type Config struct {
+	APIKey  string
+	Model   string // optional, defaults to gpt-image-1
+	BaseURL string // optional, defaults to the public API
+}
+
+// Client implements the ports.ImageGenerator interface using the OpenAI Images API
+type Client struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Images API client
+func NewClient(config Config) *Client {
+	model := config.Model
+	if model == "" {
+		model = defaultModel
+	}
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &Client{
+		apiKey:  config.APIKey,
+		model:   model,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			// Drawing takes much longer than answering a prompt
+			Timeout: 2 * time.Minute,
+		},
+	}
+}
+
+// generateRequest is the body of the images/generations endpoint
+type generateRequest struct {
+	Model          string `json:"model"`
+	Prompt         string `json:"prompt"`
+	N              int    `json:"n"`
+	Size           string `json:"size"`
+	ResponseFormat string `json:"response_format,omitempty"` // DALL·E only: gpt-image models always return base64
+}
+
+// GenerateImage implements the ImageGenerator interface
+func (c *Client) GenerateImage(ctx context.Context, description string) ([]byte, error) {
+	body := generateRequest{
+		Model:  c.model,
+		Prompt: description,
+		N:      1,
+		Size:   imageSize,
+	}
+	if strings.HasPrefix(c.model, "dall-e") {
+		body.ResponseFormat = "b64_json"
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/images/generations", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("image generation failed: %s", string(respBody))
+	}
+
+	var result struct {
+		Data []struct {
+			B64JSON string `json:"b64_json"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("image generation returned no image")
+	}
+
+	image, err := base64.StdEncoding.DecodeString(result.Data[0].B64JSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return image, nil
+}
@@ -0,0 +1,38 @@
+package openaiimages
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GenerateImage(t *testing.T) {
+	var got generateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/images/generations" || r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("request to %s with %q", r.URL, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"data":[{"b64_json":"iVBORw=="}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{APIKey: "test-key", BaseURL: server.URL})
+	image, err := client.GenerateImage(context.Background(), "A bowl of chana masala")
+	if err != nil {
+		t.Fatalf("GenerateImage() error = %v", err)
+	}
+	if string(image) != "\x89PNG" {
+		t.Errorf("GenerateImage() = %q, want the decoded image", image)
+	}
+	if got.Model != defaultModel || got.Prompt != "A bowl of chana masala" || got.ResponseFormat != "" {
+		t.Errorf("request = %+v, want the description drawn by the default model", got)
+	}
+
+	client = NewClient(Config{APIKey: "test-key", Model: "dall-e-3", BaseURL: server.URL})
+	if _, err := client.GenerateImage(context.Background(), "A bowl of chana masala"); err != nil || got.ResponseFormat != "b64_json" {
+		t.Errorf("GenerateImage() with DALL·E = %v, response format %q, want base64", err, got.ResponseFormat)
+	}
+}
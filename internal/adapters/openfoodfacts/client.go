@@ -0,0 +1,162 @@
+// Package openfoodfacts looks up packaged products in the Open Food Facts database.
+package openfoodfacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/nutrition"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+const (
+	defaultBaseURL = "https://world.openfoodfacts.org"
+
+	// Open Food Facts asks API clients to identify themselves
+	userAgent = "receipt-bot/1.0 (https://github.com/zerbinidamata/receipt-bot)"
+)
+
+// Config holds Open Food Facts client configuration
+type Config struct {
+	BaseURL string // optional, defaults to the public API
+}
+
+// Client implements the ports.ProductLookup interface using the Open Food Facts API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Open Food Facts client
+func NewClient(config Config) *Client {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// productResponse represents the product endpoint response
+type productResponse struct {
+	Status  int    `json:"status"` // 1 if found, 0 if not
+	Code    string `json:"code"`
+	Product struct {
+		ProductName string                     `json:"product_name"`
+		GenericName string                     `json:"generic_name"`
+		Brands      string                     `json:"brands"`
+		Quantity    string                     `json:"quantity"`
+		Nutriments  map[string]json.RawMessage `json:"nutriments"`
+	} `json:"product"`
+}
+
+// LookupBarcode implements the ProductLookup interface
+func (c *Client) LookupBarcode(ctx context.Context, barcode string) (*ports.Product, error) {
+	endpoint := fmt.Sprintf("%s/api/v2/product/%s.json?fields=%s",
+		c.baseURL, url.PathEscape(barcode), url.QueryEscape("code,product_name,generic_name,brands,quantity,nutriments"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up product: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Unknown barcodes are reported as 404 with status 0
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, shared.ErrProductNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("product lookup failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result productResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse product response: %w", err)
+	}
+
+	name := strings.TrimSpace(result.Product.ProductName)
+	if name == "" {
+		name = strings.TrimSpace(result.Product.GenericName)
+	}
+	if result.Status != 1 || name == "" {
+		return nil, shared.ErrProductNotFound
+	}
+
+	return &ports.Product{
+		Barcode:   barcode,
+		Name:      name,
+		Brand:     firstBrand(result.Product.Brands),
+		Quantity:  strings.TrimSpace(result.Product.Quantity),
+		Nutrition: parseNutriments(result.Product.Nutriments),
+	}, nil
+}
+
+// parseNutriments reads the per-100 g values, returning nil if the product has none
+func parseNutriments(nutriments map[string]json.RawMessage) *nutrition.Facts {
+	facts := nutrition.Facts{
+		EnergyKcal:    nutriment(nutriments, "energy-kcal_100g"),
+		Protein:       nutriment(nutriments, "proteins_100g"),
+		Carbohydrates: nutriment(nutriments, "carbohydrates_100g"),
+		Sugars:        nutriment(nutriments, "sugars_100g"),
+		Fat:           nutriment(nutriments, "fat_100g"),
+		SaturatedFat:  nutriment(nutriments, "saturated-fat_100g"),
+		Fiber:         nutriment(nutriments, "fiber_100g"),
+		Salt:          nutriment(nutriments, "salt_100g"),
+	}
+
+	// Some products only report energy in kJ
+	if facts.EnergyKcal == 0 {
+		facts.EnergyKcal = nutriment(nutriments, "energy_100g") / 4.184
+	}
+
+	if facts.IsEmpty() {
+		return nil
+	}
+	return &facts
+}
+
+// nutriment returns a value that Open Food Facts may encode as a number or a string
+func nutriment(nutriments map[string]json.RawMessage, key string) float64 {
+	raw, ok := nutriments[key]
+	if !ok {
+		return 0
+	}
+
+	var value float64
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		value, _ = strconv.ParseFloat(strings.TrimSpace(text), 64)
+	}
+	return value
+}
+
+// firstBrand returns the first of a comma-separated list of brands
+func firstBrand(brands string) string {
+	brand, _, _ := strings.Cut(brands, ",")
+	return strings.TrimSpace(brand)
+}
@@ -0,0 +1,59 @@
+package openfoodfacts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestClient_LookupBarcode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("request has no User-Agent")
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/product/8076800195057.json":
+			_, _ = w.Write([]byte(`{"status":1,"code":"8076800195057","product":{"product_name":"Spaghetti n.5","brands":"Barilla, Barilla Italia","quantity":"500 g","nutriments":{"energy-kcal_100g":359,"proteins_100g":"13","carbohydrates_100g":70.2,"fat_100g":2}}}`))
+		case "/api/v2/product/3017620422003.json":
+			_, _ = w.Write([]byte(`{"status":1,"code":"3017620422003","product":{"product_name":"","generic_name":"Hazelnut spread","quantity":"400 g"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":0,"status_verbose":"product not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	product, err := client.LookupBarcode(ctx, "8076800195057")
+	if err != nil {
+		t.Fatalf("LookupBarcode() error = %v", err)
+	}
+	if product.Name != "Spaghetti n.5" || product.Brand != "Barilla" || product.Quantity != "500 g" {
+		t.Errorf("LookupBarcode() = %+v", product)
+	}
+	if product.Nutrition == nil || product.Nutrition.EnergyKcal != 359 || product.Nutrition.Protein != 13 || product.Nutrition.Carbohydrates != 70.2 {
+		t.Errorf("LookupBarcode() nutrition = %+v", product.Nutrition)
+	}
+
+	product, err = client.LookupBarcode(ctx, "3017620422003")
+	if err != nil {
+		t.Fatalf("LookupBarcode() error = %v", err)
+	}
+	if product.Name != "Hazelnut spread" {
+		t.Errorf("LookupBarcode() name = %q, want generic name", product.Name)
+	}
+	if product.Nutrition != nil {
+		t.Errorf("LookupBarcode() nutrition = %+v, want nil without nutriments", product.Nutrition)
+	}
+
+	if _, err := client.LookupBarcode(ctx, "0000000000000"); !errors.Is(err, shared.ErrProductNotFound) {
+		t.Errorf("LookupBarcode() error = %v, want ErrProductNotFound", err)
+	}
+}
@@ -0,0 +1,138 @@
+package printable
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 pages in points, with the text in 10pt Courier so the plain-text layout
+// keeps its columns. Courier is one of the standard PDF fonts, so no font file
+// has to be embedded.
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	pageMargin   = 56
+	fontSize     = 10
+	lineHeight   = 12
+	linesPerPage = (pageHeight - 2*pageMargin) / lineHeight
+	pdfColumns   = 80 // Courier is 0.6em wide: 80 columns fill the page between the margins
+)
+
+// Cookbook renders recipes as a PDF book: a contents page listing each recipe
+// with its page, then every recipe starting on a page of its own
+func Cookbook(title string, recipes []*Recipe) []byte {
+	contentLines := 2 + len(recipes)
+	contentPages := (contentLines + linesPerPage - 1) / linesPerPage
+
+	var body [][]string
+	starts := make([]int, len(recipes))
+	for i, r := range recipes {
+		starts[i] = contentPages + len(body) + 1
+		body = append(body, paginate(strings.Split(strings.TrimRight(r.Text(), "\n"), "\n"))...)
+	}
+
+	contents := []string{clean(title), ""}
+	for i, r := range recipes {
+		page := fmt.Sprint(starts[i])
+		name := []rune(r.Title)
+		if len(name) > LineWidth-len(page)-4 {
+			name = append(name[:LineWidth-len(page)-7], []rune("...")...)
+		}
+		dots := LineWidth - len(name) - len(page) - 2
+		contents = append(contents, string(name)+" "+strings.Repeat(".", dots)+" "+page)
+	}
+
+	return renderPDF(append(paginate(contents), body...))
+}
+
+// paginate wraps lines longer than a page is wide and splits them into pages
+func paginate(lines []string) [][]string {
+	var wrapped []string
+	for _, line := range lines {
+		runes := []rune(line)
+		for len(runes) > pdfColumns {
+			wrapped = append(wrapped, string(runes[:pdfColumns]))
+			runes = runes[pdfColumns:]
+		}
+		wrapped = append(wrapped, string(runes))
+	}
+
+	var pages [][]string
+	for len(wrapped) > linesPerPage {
+		pages = append(pages, wrapped[:linesPerPage])
+		wrapped = wrapped[linesPerPage:]
+	}
+	return append(pages, wrapped)
+}
+
+// renderPDF writes pages of lines as a PDF document
+func renderPDF(pages [][]string) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Objects 1-3 are the catalog, the page tree and the font; each page is
+	// then a page object followed by its content stream
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", fontSize, lineHeight, pageMargin, pageHeight-pageMargin-fontSize)
+		for _, line := range lines {
+			fmt.Fprintf(&content, "(%s) '\n", pdfString(line))
+		}
+		content.WriteString("ET")
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 5+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// winAnsi maps the characters outside Latin-1 that WinAnsiEncoding has
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '•': 0x95, '–': 0x96, '—': 0x97,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '™': 0x99,
+}
+
+// pdfString encodes text for a PDF string in WinAnsiEncoding, escaping the
+// characters strings treat specially. Characters the encoding lacks become "?".
+func pdfString(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			sb.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			sb.WriteByte(winAnsi[r])
+		default:
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package printable
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCookbook(t *testing.T) {
+	long := &Recipe{Title: "Feijoada (Brazilian)", Meta: []string{"Serves 8"}}
+	for i := 0; i < 70; i++ {
+		long.Instructions = append(long.Instructions, fmt.Sprintf("Step %d", i+1))
+	}
+	short := &Recipe{Title: "Crème brûlée", Ingredients: []string{"4 egg yolks"}}
+
+	pdf := Cookbook("My Cookbook", []*Recipe{long, short})
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("Cookbook() is not a PDF document:\n%s", pdf)
+	}
+	// The contents page, three pages of the long recipe and one of the short one
+	if !bytes.Contains(pdf, []byte("/Count 5 >>")) {
+		t.Errorf("Cookbook() does not have 5 pages:\n%s", pdf)
+	}
+	if !bytes.Contains(pdf, []byte(`(Feijoada \(Brazilian\) `)) || !bytes.Contains(pdf, []byte(" 5) '")) {
+		t.Errorf("Cookbook() contents do not list the recipes with their pages:\n%s", pdf)
+	}
+	if !bytes.Contains(pdf, []byte("Cr\xe8me br\xfbl\xe9e")) {
+		t.Error("Cookbook() does not encode accents in WinAnsiEncoding")
+	}
+
+	// Each offset in the cross-reference table points at its object
+	xref := pdf[bytes.LastIndex(pdf, []byte("xref\n")):]
+	for i, line := range strings.Split(string(xref), "\n")[3:] {
+		if !strings.HasSuffix(line, " n ") {
+			break
+		}
+		var offset int
+		fmt.Sscanf(line, "%d", &offset)
+		if want := fmt.Sprintf("%d 0 obj", i+1); !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, pdf[offset:offset+10], want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Package printable renders recipes for printing: an emoji-free plain-text
+// version that lines up in a monospace font, and an HTML page sized for
+// A4 and Letter paper.
+package printable
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"unicode"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shopping"
+)
+
+// LineWidth is the plain-text line width; 72 monospace columns fit A4 and
+// Letter paper at common print sizes
+const LineWidth = 72
+
+// Recipe is a recipe prepared for printing, with scaling applied
+type Recipe struct {
+	Title        string
+	Meta         []string // "Serves 4", "Prep 10 min", ...
+	Ingredients  []string
+	Instructions []string
+	Transcript   string
+	Source       string // credit of the recipe's creator, like "Recipe by ... Original: https://..."
+}
+
+// Prepare strips emojis, scales the recipe to the given servings and keeps only
+// the fields to print, nil for the default fields. Servings of 0 keeps the recipe
+// as written; scaling a recipe with unknown servings returns an error.
+func Prepare(rec *dto.RecipeDTO, servings int, fields recipe.ExportFields) (*Recipe, error) {
+	if fields == nil {
+		fields = recipe.DefaultExportFields()
+	}
+
+	scale := 1.0
+	if servings > 0 {
+		if rec.Servings == nil || *rec.Servings <= 0 {
+			return nil, fmt.Errorf("recipe does not say how many servings it makes, so it cannot be scaled")
+		}
+		scale = float64(servings) / float64(*rec.Servings)
+	}
+
+	printed := &Recipe{Title: clean(rec.Title)}
+
+	if fields.Has(recipe.ExportFieldMetadata) {
+		printed.Meta = formatMeta(rec, servings)
+	}
+
+	if fields.Has(recipe.ExportFieldIngredients) {
+		for _, ing := range rec.Ingredients {
+			if !fields.Has(recipe.ExportFieldNotes) {
+				ing.Notes = ""
+			}
+			printed.Ingredients = append(printed.Ingredients, clean(formatIngredient(ing, scale)))
+		}
+	}
+	if fields.Has(recipe.ExportFieldSteps) {
+		for _, inst := range rec.Instructions {
+			printed.Instructions = append(printed.Instructions, clean(inst.Text))
+		}
+	}
+	if fields.Has(recipe.ExportFieldTranscript) {
+		printed.Transcript = clean(strings.TrimSpace(rec.Transcript))
+	}
+
+	if fields.Has(recipe.ExportFieldSource) {
+		printed.Source = clean(rec.SourceCredit)
+	}
+
+	return printed, nil
+}
+
+// Text renders the recipe as monospace-friendly plain text
+func (r *Recipe) Text() string {
+	var sb strings.Builder
+
+	sb.WriteString(r.Title + "\n")
+	sb.WriteString(strings.Repeat("=", min(len([]rune(r.Title)), LineWidth)) + "\n")
+	if len(r.Meta) > 0 {
+		sb.WriteString(strings.Join(r.Meta, " | ") + "\n")
+	}
+
+	if len(r.Ingredients) > 0 {
+		sb.WriteString("\nINGREDIENTS\n\n")
+		for _, ing := range r.Ingredients {
+			writeWrapped(&sb, "  [ ] ", ing)
+		}
+	}
+
+	if len(r.Instructions) > 0 {
+		sb.WriteString("\nINSTRUCTIONS\n\n")
+		for i, inst := range r.Instructions {
+			writeWrapped(&sb, fmt.Sprintf("%3d. ", i+1), inst)
+			sb.WriteString("\n")
+		}
+	}
+
+	if r.Transcript != "" {
+		sb.WriteString("\nTRANSCRIPT\n\n")
+		writeWrapped(&sb, "", r.Transcript)
+		sb.WriteString("\n")
+	}
+
+	if r.Source != "" {
+		writeWrapped(&sb, "", r.Source)
+	}
+
+	return sb.String()
+}
+
+// HTML renders the recipe as a standalone page for printing from a browser
+func (r *Recipe) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, r); err != nil {
+		return "", fmt.Errorf("failed to render printable page: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Filename returns a file name for the printed recipe with the given extension
+func (r *Recipe) Filename(ext string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return r
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			return '_'
+		}
+		return -1
+	}, r.Title)
+	name = strings.Trim(name, "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	if name == "" {
+		name = "recipe"
+	}
+	return name + "." + ext
+}
+
+// pageTemplate keeps the content 170mm wide so it fits both A4 and Letter
+var pageTemplate = template.Must(template.New("print").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+@page { margin: 18mm; }
+body { font-family: Georgia, "Times New Roman", serif; font-size: 11pt; line-height: 1.4; color: #000; max-width: 170mm; margin: 0 auto; }
+h1 { font-size: 18pt; margin: 0 0 4pt; }
+h2 { font-size: 12pt; text-transform: uppercase; letter-spacing: 1pt; border-bottom: 1px solid #000; margin-top: 14pt; }
+.meta { font-style: italic; margin: 0; }
+ul { list-style: square; padding-left: 18pt; }
+li { margin-bottom: 3pt; break-inside: avoid; }
+.source { font-size: 9pt; margin-top: 16pt; word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Meta}}<p class="meta">{{range $i, $m := .Meta}}{{if $i}} | {{end}}{{$m}}{{end}}</p>{{end}}
+{{if .Ingredients}}<h2>Ingredients</h2>
+<ul>
+{{range .Ingredients}}<li>{{.}}</li>
+{{end}}</ul>{{end}}
+{{if .Instructions}}<h2>Instructions</h2>
+<ol>
+{{range .Instructions}}<li>{{.}}</li>
+{{end}}</ol>{{end}}
+{{if .Transcript}}<h2>Transcript</h2>
+<p>{{.Transcript}}</p>{{end}}
+{{if .Source}}<p class="source">{{.Source}}</p>{{end}}
+</body>
+</html>
+`))
+
+// formatMeta formats the servings, scaled to servings when not 0, and the times of a recipe
+func formatMeta(rec *dto.RecipeDTO, servings int) []string {
+	var meta []string
+	switch {
+	case rec.Servings != nil && servings > 0 && servings != *rec.Servings:
+		meta = append(meta, fmt.Sprintf("Serves %d (scaled from %d)", servings, *rec.Servings))
+	case rec.Servings != nil:
+		meta = append(meta, fmt.Sprintf("Serves %d", *rec.Servings))
+	}
+	if rec.PrepTimeMinutes != nil {
+		meta = append(meta, fmt.Sprintf("Prep %d min", *rec.PrepTimeMinutes))
+	}
+	if rec.CookTimeMinutes != nil {
+		meta = append(meta, fmt.Sprintf("Cook %d min", *rec.CookTimeMinutes))
+	}
+	return meta
+}
+
+// formatIngredient formats an ingredient, scaling numeric quantities
+func formatIngredient(ing dto.IngredientDTO, scale float64) string {
+	quantity := ing.Quantity
+	if scale != 1 {
+		// Parse without the unit so the recipe keeps its own units
+		if amount, ok := shopping.ParseAmount(ing.Quantity, ""); ok {
+			quantity = shopping.Amount{Value: amount.Value * scale}.String()
+		}
+	}
+
+	line := strings.Join(strings.Fields(strings.Join([]string{quantity, ing.Unit, ing.Name}, " ")), " ")
+	if ing.Notes != "" {
+		line += " (" + ing.Notes + ")"
+	}
+	return line
+}
+
+// writeWrapped writes text wrapped to LineWidth, indenting continuation lines under the prefix
+func writeWrapped(sb *strings.Builder, prefix, text string) {
+	indent := strings.Repeat(" ", len(prefix))
+	line := prefix
+	empty := true
+	for _, word := range strings.Fields(text) {
+		if !empty && len([]rune(line))+1+len([]rune(word)) > LineWidth {
+			sb.WriteString(line + "\n")
+			line, empty = indent, true
+		}
+		if !empty {
+			line += " "
+		}
+		line += word
+		empty = false
+	}
+	sb.WriteString(line + "\n")
+}
+
+// clean removes emojis and the joiners and variation selectors around them
+func clean(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '\u200d' || r == '\ufe0f' || (r >= 0x2190 && (unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r))) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,16 @@
+package python
+
+import (
+	"context"
+)
+
+// CheckService connects to the Python service and hangs up, returning an error when
+// it does not accept the connection before ctx is done. The service has no health
+// RPC, so a connection is as far as the check goes without scraping anything.
+func CheckService(ctx context.Context, address string) error {
+	conn, err := dial(ctx, address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,37 @@
+package python
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestCheckService_HonoursDeadline(t *testing.T) {
+	// A service that accepts connections but never answers
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := CheckService(ctx, listener.Addr().String()); err == nil {
+		t.Error("CheckService() of a service that never answers succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("CheckService() returned after %v, want it to stop at the deadline", elapsed)
+	}
+}
@@ -12,8 +12,9 @@ import (
 
 // GRPCClient manages the connection to the Python gRPC service
 type GRPCClient struct {
-	conn   *grpc.ClientConn
-	client pb.ScraperServiceClient
+	conn    *grpc.ClientConn
+	client  pb.ScraperServiceClient
+	timeout time.Duration // for connecting and for each call
 }
 
 // NewGRPCClient creates a new gRPC client connection to the Python service
@@ -21,6 +22,23 @@ func NewGRPCClient(address string, timeout time.Duration) (*GRPCClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	conn, err := dial(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the generated client
+	client := pb.NewScraperServiceClient(conn)
+
+	return &GRPCClient{
+		conn:    conn,
+		client:  client,
+		timeout: timeout,
+	}, nil
+}
+
+// dial connects to the Python service, waiting for the connection until ctx is done
+func dial(ctx context.Context, address string) (*grpc.ClientConn, error) {
 	// Create connection options
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
@@ -32,14 +50,7 @@ func NewGRPCClient(address string, timeout time.Duration) (*GRPCClient, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Python service at %s: %w", address, err)
 	}
-
-	// Use the generated client
-	client := pb.NewScraperServiceClient(conn)
-
-	return &GRPCClient{
-		conn:   conn,
-		client: client,
-	}, nil
+	return conn, nil
 }
 
 // Close closes the gRPC connection
@@ -50,8 +61,11 @@ func (c *GRPCClient) Close() error {
 	return nil
 }
 
-// ScrapeContent calls the Python service to scrape content
+// ScrapeContent calls the Python service to scrape content, giving up after the
+// client's timeout or when ctx is done, whichever comes first
 func (c *GRPCClient) ScrapeContent(ctx context.Context, req *pb.ScrapeRequest) (*pb.ScrapeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
 	return c.client.ScrapeContent(ctx, req)
 }
 
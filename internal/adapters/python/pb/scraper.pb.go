@@ -154,6 +154,8 @@ type ScrapeResponse struct {
 	OriginalUrl   string                 `protobuf:"bytes,3,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`                                                  // Original video URL
 	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (author, title, etc.)
 	Error         *Error                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`                                                                                 // Error if scraping failed
+	KeyFrames     [][]byte               `protobuf:"bytes,6,rep,name=key_frames,json=keyFrames,proto3" json:"key_frames,omitempty"`                                                        // Key video frames (JPEG), for reading text shown on screen
+	Timeline      []*TimelineEntry       `protobuf:"bytes,7,rep,name=timeline,proto3" json:"timeline,omitempty"`                                                                           // Chapters, or timed transcript lines, for linking steps to the video
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -223,6 +225,20 @@ func (x *ScrapeResponse) GetError() *Error {
 	return nil
 }
 
+func (x *ScrapeResponse) GetKeyFrames() [][]byte {
+	if x != nil {
+		return x.KeyFrames
+	}
+	return nil
+}
+
+func (x *ScrapeResponse) GetTimeline() []*TimelineEntry {
+	if x != nil {
+		return x.Timeline
+	}
+	return nil
+}
+
 // Error represents an error that occurred during scraping
 type Error struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -276,6 +292,59 @@ func (x *Error) GetCode() string {
 	return ""
 }
 
+// TimelineEntry marks where something starts in the video
+type TimelineEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartSeconds  int32                  `protobuf:"varint,1,opt,name=start_seconds,json=startSeconds,proto3" json:"start_seconds,omitempty"` // Offset from the start of the video
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`                                      // Chapter title or transcript line
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimelineEntry) Reset() {
+	*x = TimelineEntry{}
+	mi := &file_scraper_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimelineEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelineEntry) ProtoMessage() {}
+
+func (x *TimelineEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_scraper_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimelineEntry.ProtoReflect.Descriptor instead.
+func (*TimelineEntry) Descriptor() ([]byte, []int) {
+	return file_scraper_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TimelineEntry) GetStartSeconds() int32 {
+	if x != nil {
+		return x.StartSeconds
+	}
+	return 0
+}
+
+func (x *TimelineEntry) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
 var File_scraper_proto protoreflect.FileDescriptor
 
 const file_scraper_proto_rawDesc = "" +
@@ -287,7 +356,7 @@ const file_scraper_proto_rawDesc = "" +
 	"\x0edownload_video\x18\x03 \x01(\bR\rdownloadVideo\x12\x1e\n" +
 	"\n" +
 	"transcribe\x18\x04 \x01(\bR\n" +
-	"transcribe\"\x95\x02\n" +
+	"transcribe\"\xe8\x02\n" +
 	"\x0eScrapeResponse\x12\x1a\n" +
 	"\bcaptions\x18\x01 \x01(\tR\bcaptions\x12\x1e\n" +
 	"\n" +
@@ -295,13 +364,19 @@ const file_scraper_proto_rawDesc = "" +
 	"transcript\x12!\n" +
 	"\foriginal_url\x18\x03 \x01(\tR\voriginalUrl\x12A\n" +
 	"\bmetadata\x18\x04 \x03(\v2%.scraper.ScrapeResponse.MetadataEntryR\bmetadata\x12$\n" +
-	"\x05error\x18\x05 \x01(\v2\x0e.scraper.ErrorR\x05error\x1a;\n" +
+	"\x05error\x18\x05 \x01(\v2\x0e.scraper.ErrorR\x05error\x12\x1d\n" +
+	"\n" +
+	"key_frames\x18\x06 \x03(\fR\tkeyFrames\x122\n" +
+	"\btimeline\x18\a \x03(\v2\x16.scraper.TimelineEntryR\btimeline\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"5\n" +
 	"\x05Error\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
-	"\x04code\x18\x02 \x01(\tR\x04code*u\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"H\n" +
+	"\rTimelineEntry\x12#\n" +
+	"\rstart_seconds\x18\x01 \x01(\x05R\fstartSeconds\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text*u\n" +
 	"\bPlatform\x12\x14\n" +
 	"\x10PLATFORM_UNKNOWN\x10\x00\x12\x13\n" +
 	"\x0fPLATFORM_TIKTOK\x10\x01\x12\x14\n" +
@@ -324,25 +399,27 @@ func file_scraper_proto_rawDescGZIP() []byte {
 }
 
 var file_scraper_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_scraper_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_scraper_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_scraper_proto_goTypes = []any{
 	(Platform)(0),          // 0: scraper.Platform
 	(*ScrapeRequest)(nil),  // 1: scraper.ScrapeRequest
 	(*ScrapeResponse)(nil), // 2: scraper.ScrapeResponse
 	(*Error)(nil),          // 3: scraper.Error
-	nil,                    // 4: scraper.ScrapeResponse.MetadataEntry
+	(*TimelineEntry)(nil),  // 4: scraper.TimelineEntry
+	nil,                    // 5: scraper.ScrapeResponse.MetadataEntry
 }
 var file_scraper_proto_depIdxs = []int32{
 	0, // 0: scraper.ScrapeRequest.platform:type_name -> scraper.Platform
-	4, // 1: scraper.ScrapeResponse.metadata:type_name -> scraper.ScrapeResponse.MetadataEntry
+	5, // 1: scraper.ScrapeResponse.metadata:type_name -> scraper.ScrapeResponse.MetadataEntry
 	3, // 2: scraper.ScrapeResponse.error:type_name -> scraper.Error
-	1, // 3: scraper.ScraperService.ScrapeContent:input_type -> scraper.ScrapeRequest
-	2, // 4: scraper.ScraperService.ScrapeContent:output_type -> scraper.ScrapeResponse
-	4, // [4:5] is the sub-list for method output_type
-	3, // [3:4] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	4, // 3: scraper.ScrapeResponse.timeline:type_name -> scraper.TimelineEntry
+	1, // 4: scraper.ScraperService.ScrapeContent:input_type -> scraper.ScrapeRequest
+	2, // 5: scraper.ScraperService.ScrapeContent:output_type -> scraper.ScrapeResponse
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_scraper_proto_init() }
@@ -356,7 +433,7 @@ func file_scraper_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_scraper_proto_rawDesc), len(file_scraper_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   4,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
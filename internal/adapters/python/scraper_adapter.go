@@ -3,6 +3,7 @@ package python
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"receipt-bot/internal/adapters/python/pb"
@@ -46,7 +47,7 @@ func (a *ScraperAdapter) Scrape(ctx context.Context, req ports.ScrapeRequest) (*
 	}
 
 	// Log the request
-	fmt.Printf("[DEBUG] Scraper request - URL: %s, Platform: %v, DownloadVideo: %v, Transcribe: %v\n", 
+	log.Printf("[DEBUG] Scraper request - URL: %s, Platform: %v, DownloadVideo: %v, Transcribe: %v\n", 
 		grpcReq.Url, grpcReq.Platform, grpcReq.DownloadVideo, grpcReq.Transcribe)
 
 	// Call Python service
@@ -56,24 +57,24 @@ func (a *ScraperAdapter) Scrape(ctx context.Context, req ports.ScrapeRequest) (*
 	}
 
 	// Log the response
-	fmt.Printf("[DEBUG] Scraper response - Captions length: %d, Transcript length: %d, Has error: %v\n",
-		len(resp.Captions), len(resp.Transcript), resp.Error != nil)
+	log.Printf("[DEBUG] Scraper response - Captions length: %d, Transcript length: %d, Key frames: %d, Timeline entries: %d, Has error: %v\n",
+		len(resp.Captions), len(resp.Transcript), len(resp.KeyFrames), len(resp.Timeline), resp.Error != nil)
 	if resp.Error != nil {
-		fmt.Printf("[DEBUG] Scraper error: %s (code: %s)\n", resp.Error.Message, resp.Error.Code)
+		log.Printf("[DEBUG] Scraper error: %s (code: %s)\n", resp.Error.Message, resp.Error.Code)
 	}
 	if len(resp.Captions) > 0 {
 		captionsPreview := resp.Captions
 		if len(captionsPreview) > 200 {
 			captionsPreview = captionsPreview[:200] + "..."
 		}
-		fmt.Printf("[DEBUG] Captions preview: %s\n", captionsPreview)
+		log.Printf("[DEBUG] Captions preview: %s\n", captionsPreview)
 	}
 	if len(resp.Transcript) > 0 {
 		transcriptPreview := resp.Transcript
 		if len(transcriptPreview) > 200 {
 			transcriptPreview = transcriptPreview[:200] + "..."
 		}
-		fmt.Printf("[DEBUG] Transcript preview: %s\n", transcriptPreview)
+		log.Printf("[DEBUG] Transcript preview: %s\n", transcriptPreview)
 	}
 
 	// Check for service-level errors
@@ -87,6 +88,13 @@ func (a *ScraperAdapter) Scrape(ctx context.Context, req ports.ScrapeRequest) (*
 		Transcript:  resp.Transcript,
 		OriginalURL: resp.OriginalUrl,
 		Metadata:    resp.Metadata,
+		KeyFrames:   resp.KeyFrames,
+	}
+	for _, entry := range resp.Timeline {
+		result.Timeline = append(result.Timeline, ports.TimelineEntry{
+			Start: time.Duration(entry.StartSeconds) * time.Second,
+			Text:  entry.Text,
+		})
 	}
 
 	return result, nil
@@ -0,0 +1,167 @@
+// Package rss reads the RSS 2.0 and Atom feeds recipe blogs publish.
+package rss
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/feed"
+	"receipt-bot/internal/domain/shared"
+)
+
+// maxFeedBytes caps the size of a feed document
+const maxFeedBytes = 5 << 20
+
+// Reader implements the ports.FeedReader interface over HTTP
+type Reader struct {
+	httpClient *http.Client
+}
+
+// NewReader creates a new feed reader
+func NewReader() *Reader {
+	return &Reader{
+		httpClient: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+	}
+}
+
+// document holds the elements of both formats: RSS in channel, Atom at the root
+type document struct {
+	XMLName xml.Name
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// rssItem is an RSS 2.0 item
+type rssItem struct {
+	Title   string `xml:"title"`
+	Link    string `xml:"link"`
+	GUID    string `xml:"guid"`
+	PubDate string `xml:"pubDate"`
+}
+
+// atomEntry is an Atom entry
+type atomEntry struct {
+	Title string `xml:"title"`
+	ID    string `xml:"id"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+// Read implements the FeedReader interface
+func (r *Reader) Read(ctx context.Context, url string) (*feed.Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", shared.ErrFeedUnreadable, err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	req.Header.Set("User-Agent", "receipt-bot/1.0")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: feed returned status %d", shared.ErrFeedUnreadable, resp.StatusCode)
+	}
+
+	return Parse(io.LimitReader(resp.Body, maxFeedBytes))
+}
+
+// Parse reads an RSS 2.0 or Atom document
+func Parse(body io.Reader) (*feed.Feed, error) {
+	var doc document
+	decoder := xml.NewDecoder(body)
+	decoder.Strict = false
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		// Feeds declaring another charset are read as is; titles may be garbled but links are ASCII
+		return input, nil
+	}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", shared.ErrFeedUnreadable, err)
+	}
+
+	switch doc.XMLName.Local {
+	case "rss":
+		return fromRSS(doc), nil
+	case "feed":
+		return fromAtom(doc), nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected root element <%s>", shared.ErrFeedUnreadable, doc.XMLName.Local)
+	}
+}
+
+// fromRSS converts an RSS channel
+func fromRSS(doc document) *feed.Feed {
+	f := &feed.Feed{Title: strings.TrimSpace(doc.Channel.Title)}
+	for _, it := range doc.Channel.Items {
+		item := feed.Item{
+			ID:        strings.TrimSpace(it.GUID),
+			Title:     strings.TrimSpace(it.Title),
+			Link:      strings.TrimSpace(it.Link),
+			Published: parseTime(it.PubDate),
+		}
+		if item.ID == "" {
+			item.ID = item.Link
+		}
+		if item.Link != "" {
+			f.Items = append(f.Items, item)
+		}
+	}
+	return f
+}
+
+// fromAtom converts an Atom feed
+func fromAtom(doc document) *feed.Feed {
+	f := &feed.Feed{Title: strings.TrimSpace(doc.Title)}
+	for _, entry := range doc.Entries {
+		item := feed.Item{
+			ID:        strings.TrimSpace(entry.ID),
+			Title:     strings.TrimSpace(entry.Title),
+			Published: parseTime(entry.Published),
+		}
+		if item.Published.IsZero() {
+			item.Published = parseTime(entry.Updated)
+		}
+		for _, link := range entry.Links {
+			if link.Rel == "" || link.Rel == "alternate" {
+				item.Link = strings.TrimSpace(link.Href)
+				break
+			}
+		}
+		if item.ID == "" {
+			item.ID = item.Link
+		}
+		if item.Link != "" {
+			f.Items = append(f.Items, item)
+		}
+	}
+	return f
+}
+
+// parseTime reads the date formats feeds use, zero if none matches
+func parseTime(raw string) time.Time {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
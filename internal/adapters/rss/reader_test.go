@@ -0,0 +1,90 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel>
+  <title>Smitten Kitchen</title>
+  <item>
+    <title>Crispy Gnocchi</title>
+    <link>https://smittenkitchen.com/2026/03/crispy-gnocchi/</link>
+    <guid isPermaLink="false">https://smittenkitchen.com/?p=123</guid>
+    <pubDate>Sun, 01 Mar 2026 10:00:00 +0000</pubDate>
+  </item>
+  <item>
+    <title>Lemon Bars</title>
+    <link>https://smittenkitchen.com/2026/02/lemon-bars/</link>
+  </item>
+</channel></rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Serious Eats</title>
+  <entry>
+    <title>Pasta Carbonara</title>
+    <id>tag:seriouseats.com,2026:carbonara</id>
+    <link rel="self" href="https://www.seriouseats.com/feed/carbonara"/>
+    <link rel="alternate" href="https://www.seriouseats.com/pasta-carbonara"/>
+    <updated>2026-03-01T10:00:00Z</updated>
+  </entry>
+</feed>`
+
+func TestParse_RSS(t *testing.T) {
+	f, err := Parse(strings.NewReader(rssFeed))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if f.Title != "Smitten Kitchen" || len(f.Items) != 2 {
+		t.Fatalf("Parse() = %+v", f)
+	}
+	first := f.Items[0]
+	if first.ID != "https://smittenkitchen.com/?p=123" || first.Link != "https://smittenkitchen.com/2026/03/crispy-gnocchi/" || first.Published.IsZero() {
+		t.Errorf("first item = %+v", first)
+	}
+	if f.Items[1].ID != f.Items[1].Link {
+		t.Errorf("item without GUID has ID %q, want its link", f.Items[1].ID)
+	}
+}
+
+func TestParse_Atom(t *testing.T) {
+	f, err := Parse(strings.NewReader(atomFeed))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if f.Title != "Serious Eats" || len(f.Items) != 1 {
+		t.Fatalf("Parse() = %+v", f)
+	}
+	if item := f.Items[0]; item.Link != "https://www.seriouseats.com/pasta-carbonara" || item.Published.IsZero() {
+		t.Errorf("entry = %+v, want the alternate link and the updated time", item)
+	}
+}
+
+func TestReader_Read(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feed" {
+			_, _ = w.Write([]byte(rssFeed))
+			return
+		}
+		_, _ = w.Write([]byte("<html><body>Not a feed</body></html>"))
+	}))
+	defer server.Close()
+
+	reader := NewReader()
+	if _, err := reader.Read(context.Background(), server.URL+"/feed"); err != nil {
+		t.Errorf("Read() error = %v", err)
+	}
+	if _, err := reader.Read(context.Background(), server.URL+"/blog"); !errors.Is(err, shared.ErrFeedUnreadable) {
+		t.Errorf("Read() error = %v, want %v", err, shared.ErrFeedUnreadable)
+	}
+}
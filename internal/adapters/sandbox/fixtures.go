@@ -0,0 +1,173 @@
+package sandbox
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed fixtures/default.json
+var defaultFixtures embed.FS
+
+// Fixture is a recorded scrape result together with the recipe the LLM extracted from it
+type Fixture struct {
+	URL        string            `json:"url"`
+	Captions   string            `json:"captions"`
+	Transcript string            `json:"transcript"`
+	Metadata   map[string]string `json:"metadata"`
+	Recipe     recipeJSON        `json:"recipe"`
+
+	// Other recipes shown after Recipe in a compilation video
+	MoreRecipes []recipeJSON `json:"more_recipes,omitempty"`
+
+	// Content of a recorded photo of the dish, for recreating it from a photo
+	Photo string `json:"photo,omitempty"`
+
+	// Text shown on screen in the video, replayed as its only key frame
+	OnScreenText string `json:"on_screen_text,omitempty"`
+
+	// Chapters of the video, for linking steps to where they start
+	Timeline []timelineJSON `json:"timeline,omitempty"`
+}
+
+type timelineJSON struct {
+	StartSeconds int    `json:"start_seconds"`
+	Text         string `json:"text"`
+}
+
+// recipeJSON mirrors the JSON schema the LLM adapters return
+type recipeJSON struct {
+	Title           string            `json:"title"`
+	Category        string            `json:"category"`
+	Cuisine         string            `json:"cuisine"`
+	DietaryTags     []string          `json:"dietary_tags"`
+	Flavors         []string          `json:"flavors,omitempty"`
+	Tags            []string          `json:"tags"`
+	Ingredients     []ingredientJSON  `json:"ingredients"`
+	Instructions    []instructionJSON `json:"instructions"`
+	PrepTimeMinutes *int              `json:"prep_time_minutes"`
+	CookTimeMinutes *int              `json:"cook_time_minutes"`
+	Servings        *int              `json:"servings"`
+	SourceLanguage  string            `json:"source_language"`
+
+	// Recorded answer for the simplified instructions view
+	SimplifiedInstructions []instructionJSON `json:"simplified_instructions,omitempty"`
+
+	// Recorded answers for appliance conversions, keyed by appliance
+	Conversions map[string]conversionJSON `json:"conversions,omitempty"`
+
+	// Recorded answers for remixes, keyed by goal
+	Remixes map[string]remixJSON `json:"remixes,omitempty"`
+
+	// Recorded provenance of the extracted fields
+	Provenance []provenanceJSON `json:"provenance,omitempty"`
+}
+
+// remixJSON is a recorded remix: the remixed recipe and its substitutions
+type remixJSON struct {
+	recipeJSON
+	Substitutions []substitutionJSON `json:"substitutions"`
+}
+
+type substitutionJSON struct {
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"`
+	Reason      string `json:"reason"`
+}
+
+type provenanceJSON struct {
+	Field      string  `json:"field"`
+	Source     string  `json:"source"`
+	Confidence float64 `json:"confidence"`
+}
+
+type conversionJSON struct {
+	Instructions    []instructionJSON `json:"instructions"`
+	PrepTimeMinutes *int              `json:"prep_time_minutes"`
+	CookTimeMinutes *int              `json:"cook_time_minutes"`
+	Notes           string            `json:"notes"`
+}
+
+type ingredientJSON struct {
+	Name     string `json:"name"`
+	Quantity string `json:"quantity"`
+	Unit     string `json:"unit"`
+	Notes    string `json:"notes"`
+	Aisle    string `json:"aisle,omitempty"` // recorded aisle classification
+}
+
+type instructionJSON struct {
+	StepNumber      int      `json:"step_number"`
+	Text            string   `json:"text"`
+	DurationMinutes *float64 `json:"duration_minutes"`
+	StartSeconds    *int     `json:"start_seconds,omitempty"`
+}
+
+// Fixtures is a set of recorded fixtures used instead of the scraper and the LLM
+type Fixtures struct {
+	entries []Fixture
+}
+
+// LoadFixtures loads fixtures from a JSON file, or the built-in fixtures if path is empty
+func LoadFixtures(path string) (*Fixtures, error) {
+	var data []byte
+	var err error
+
+	if path == "" {
+		data, err = defaultFixtures.ReadFile("fixtures/default.json")
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var entries []Fixture
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("fixtures file contains no entries")
+	}
+
+	return &Fixtures{entries: entries}, nil
+}
+
+// ForURL returns the fixture recorded for a URL. Other URLs fail, so a link the
+// fixtures lack is not mistaken for a recording of another one.
+func (f *Fixtures) ForURL(url string) (Fixture, error) {
+	for _, entry := range f.entries {
+		if entry.URL == url {
+			return entry, nil
+		}
+	}
+	return Fixture{}, fmt.Errorf("no fixture recorded for %s, add it to a SANDBOX_FIXTURES file", url)
+}
+
+// ForText returns the fixture whose scraped content appears in the text,
+// falling back to the first fixture
+func (f *Fixtures) ForText(text string) Fixture {
+	for _, entry := range f.entries {
+		if entry.Captions != "" && strings.Contains(text, entry.Captions) {
+			return entry
+		}
+		if entry.Transcript != "" && strings.Contains(text, entry.Transcript) {
+			return entry
+		}
+	}
+	return f.entries[0]
+}
+
+// ForPhoto returns the fixture recorded for a photo of the dish,
+// falling back to the first fixture
+func (f *Fixtures) ForPhoto(image []byte) Fixture {
+	for _, entry := range f.entries {
+		if entry.Photo != "" && entry.Photo == string(image) {
+			return entry
+		}
+	}
+	return f.entries[0]
+}
@@ -0,0 +1,290 @@
+package sandbox
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/menu"
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shopping"
+	"receipt-bot/internal/ports"
+)
+
+// LLM implements the LLMPort interface by replaying recorded fixtures
+type LLM struct {
+	fixtures *Fixtures
+}
+
+// NewLLM creates a new fixture-backed LLM
+func NewLLM(fixtures *Fixtures) *LLM {
+	return &LLM{
+		fixtures: fixtures,
+	}
+}
+
+// ExtractRecipe implements the LLMPort interface
+func (l *LLM) ExtractRecipe(ctx context.Context, text string) (*ports.RecipeExtraction, error) {
+	return toExtraction(l.fixtures.ForText(text).Recipe), nil
+}
+
+// ExtractRecipes implements the MultiRecipeExtractor interface
+func (l *LLM) ExtractRecipes(ctx context.Context, text string) ([]*ports.RecipeExtraction, error) {
+	fixture := l.fixtures.ForText(text)
+
+	extractions := []*ports.RecipeExtraction{toExtraction(fixture.Recipe)}
+	for _, rec := range fixture.MoreRecipes {
+		extractions = append(extractions, toExtraction(rec))
+	}
+	return extractions, nil
+}
+
+// RecreateDish implements the DishRecreator interface by replaying the recipe
+// recorded for the photo
+func (l *LLM) RecreateDish(ctx context.Context, image []byte, hint string, targetLang string) (*ports.RecipeExtraction, error) {
+	return toExtraction(l.fixtures.ForPhoto(image).Recipe), nil
+}
+
+// RecognizeItems implements the PantryRecognizer interface. The sandbox sees the
+// ingredients of the recipe recorded for the photo on the shelf.
+func (l *LLM) RecognizeItems(ctx context.Context, image []byte, targetLang string) ([]string, error) {
+	rec := l.fixtures.ForPhoto(image).Recipe
+
+	items := make([]string, len(rec.Ingredients))
+	for i, ing := range rec.Ingredients {
+		items[i] = ing.Name
+	}
+	return items, nil
+}
+
+// ReadOnScreenText implements the FrameReader interface.
+// Sandbox key frames hold their recorded on-screen text.
+func (l *LLM) ReadOnScreenText(ctx context.Context, frames [][]byte) (string, error) {
+	lines := make([]string, len(frames))
+	for i, frame := range frames {
+		lines[i] = string(frame)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ConsolidateRecipe implements the RecipeConsolidator interface.
+// Sandbox parts all replay the same recorded recipe, so the fullest part is kept.
+func (l *LLM) ConsolidateRecipe(ctx context.Context, candidates []*ports.RecipeExtraction) (*ports.RecipeExtraction, error) {
+	var best *ports.RecipeExtraction
+	for _, candidate := range candidates {
+		if best == nil || len(candidate.Ingredients) > len(best.Ingredients) {
+			best = candidate
+		}
+	}
+	return best, nil
+}
+
+// toExtraction converts a recorded recipe to the LLM extraction format
+func toExtraction(rec recipeJSON) *ports.RecipeExtraction {
+	extraction := &ports.RecipeExtraction{
+		Title:          rec.Title,
+		Category:       rec.Category,
+		Cuisine:        rec.Cuisine,
+		DietaryTags:    rec.DietaryTags,
+		Flavors:        rec.Flavors,
+		Tags:           rec.Tags,
+		Ingredients:    make([]ports.IngredientData, len(rec.Ingredients)),
+		Instructions:   make([]ports.InstructionData, len(rec.Instructions)),
+		Servings:       rec.Servings,
+		SourceLanguage: rec.SourceLanguage,
+	}
+
+	if extraction.SourceLanguage == "" {
+		extraction.SourceLanguage = "en"
+	}
+
+	for i, ing := range rec.Ingredients {
+		extraction.Ingredients[i] = ports.IngredientData{
+			Name:     ing.Name,
+			Quantity: ing.Quantity,
+			Unit:     ing.Unit,
+			Notes:    ing.Notes,
+		}
+	}
+
+	for i, inst := range rec.Instructions {
+		var duration *time.Duration
+		if inst.DurationMinutes != nil && *inst.DurationMinutes > 0 {
+			d := time.Duration(*inst.DurationMinutes * float64(time.Minute))
+			duration = &d
+		}
+
+		extraction.Instructions[i] = ports.InstructionData{
+			StepNumber: inst.StepNumber,
+			Text:       inst.Text,
+			Duration:   duration,
+		}
+		if inst.StartSeconds != nil {
+			at := time.Duration(*inst.StartSeconds) * time.Second
+			extraction.Instructions[i].Timestamp = &at
+		}
+	}
+
+	if rec.PrepTimeMinutes != nil && *rec.PrepTimeMinutes > 0 {
+		d := time.Duration(*rec.PrepTimeMinutes) * time.Minute
+		extraction.PrepTime = &d
+	}
+
+	if rec.CookTimeMinutes != nil && *rec.CookTimeMinutes > 0 {
+		d := time.Duration(*rec.CookTimeMinutes) * time.Minute
+		extraction.CookTime = &d
+	}
+
+	for _, p := range rec.Provenance {
+		extraction.Provenance = append(extraction.Provenance, ports.ProvenanceData{
+			Field:      p.Field,
+			Source:     p.Source,
+			Confidence: p.Confidence,
+		})
+	}
+
+	return extraction
+}
+
+// TranslateRecipe implements the LLMPort interface.
+// Sandbox translations return the original text unchanged.
+func (l *LLM) TranslateRecipe(ctx context.Context, recipe *ports.RecipeTranslationInput, targetLang string) (*ports.RecipeTranslationOutput, error) {
+	return &ports.RecipeTranslationOutput{
+		Title:        recipe.Title,
+		Ingredients:  append([]ports.IngredientData(nil), recipe.Ingredients...),
+		Instructions: append([]ports.InstructionData(nil), recipe.Instructions...),
+	}, nil
+}
+
+// ClassifyAisles implements the AisleClassifier interface using the aisles
+// recorded on fixture ingredients. Unknown ingredients are left out.
+func (l *LLM) ClassifyAisles(ctx context.Context, ingredients []string) (map[string]shopping.Aisle, error) {
+	normalizer := matching.NewRuleBasedNormalizer(nil)
+
+	recorded := make(map[string]shopping.Aisle)
+	for _, entry := range l.fixtures.entries {
+		for _, ing := range entry.Recipe.Ingredients {
+			if ing.Aisle != "" {
+				recorded[normalizer.Normalize(ing.Name)] = shopping.ParseAisle(ing.Aisle)
+			}
+		}
+	}
+
+	aisles := make(map[string]shopping.Aisle, len(ingredients))
+	for _, name := range ingredients {
+		if aisle, ok := recorded[normalizer.Normalize(name)]; ok {
+			aisles[name] = aisle
+		}
+	}
+	return aisles, nil
+}
+
+// SimplifyInstructions implements the InstructionSimplifier interface using the
+// simplified steps recorded on the fixture with the same instructions.
+// Steps without a recording are returned unchanged.
+func (l *LLM) SimplifyInstructions(ctx context.Context, instructions []ports.InstructionData, targetLang string) ([]ports.InstructionData, error) {
+	for _, entry := range l.fixtures.entries {
+		rec := entry.Recipe
+		if len(rec.SimplifiedInstructions) == 0 || !sameInstructions(rec.Instructions, instructions) {
+			continue
+		}
+
+		steps := make([]ports.InstructionData, len(rec.SimplifiedInstructions))
+		for i, inst := range rec.SimplifiedInstructions {
+			steps[i] = ports.InstructionData{StepNumber: inst.StepNumber, Text: inst.Text}
+		}
+		return steps, nil
+	}
+
+	return append([]ports.InstructionData(nil), instructions...), nil
+}
+
+// SuggestDishes implements the MenuSuggester interface.
+// Nothing is recorded for menu suggestions, so sandbox menus only use saved recipes.
+func (l *LLM) SuggestDishes(ctx context.Context, occasion string, courses []menu.Course, guests int, targetLang string) ([]ports.DishSuggestion, error) {
+	return nil, nil
+}
+
+// ModerateRecipe implements the ContentModerator interface.
+// Nothing is recorded for moderation, so sandbox recipes only go through the link checks.
+func (l *LLM) ModerateRecipe(ctx context.Context, text string) (*ports.ModerationResult, error) {
+	return &ports.ModerationResult{Verdict: moderation.VerdictAllowed}, nil
+}
+
+// ConvertRecipe implements the RecipeConverter interface using the conversion recorded
+// for the appliance on the fixture with the same instructions.
+// Recipes without a recording keep their steps and times.
+func (l *LLM) ConvertRecipe(ctx context.Context, input *ports.RecipeConversionInput, appliance recipe.Appliance, targetLang string) (*ports.RecipeConversionOutput, error) {
+	for _, entry := range l.fixtures.entries {
+		conversion, ok := entry.Recipe.Conversions[string(appliance)]
+		if !ok || !sameInstructions(entry.Recipe.Instructions, input.Instructions) {
+			continue
+		}
+
+		output := &ports.RecipeConversionOutput{
+			PrepTime: minutesDuration(conversion.PrepTimeMinutes),
+			CookTime: minutesDuration(conversion.CookTimeMinutes),
+			Notes:    conversion.Notes,
+		}
+		for _, inst := range conversion.Instructions {
+			var duration *time.Duration
+			if inst.DurationMinutes != nil && *inst.DurationMinutes > 0 {
+				d := time.Duration(*inst.DurationMinutes * float64(time.Minute))
+				duration = &d
+			}
+			output.Instructions = append(output.Instructions, ports.InstructionData{StepNumber: inst.StepNumber, Text: inst.Text, Duration: duration})
+		}
+		return output, nil
+	}
+
+	return &ports.RecipeConversionOutput{
+		Instructions: append([]ports.InstructionData(nil), input.Instructions...),
+		PrepTime:     input.PrepTime,
+		CookTime:     input.CookTime,
+		Notes:        "No recorded conversion for this recipe.",
+	}, nil
+}
+
+// RemixRecipe implements the RecipeRemixer interface using the remix recorded for
+// the goal on the fixture with the same instructions.
+// Recipes without a recording come back unchanged, with no substitutions.
+func (l *LLM) RemixRecipe(ctx context.Context, original *ports.RecipeExtraction, goal string, targetLang string) (*ports.RecipeRemix, error) {
+	for _, entry := range l.fixtures.entries {
+		recorded, ok := entry.Recipe.Remixes[goal]
+		if !ok || !sameInstructions(entry.Recipe.Instructions, original.Instructions) {
+			continue
+		}
+
+		remix := &ports.RecipeRemix{Recipe: toExtraction(recorded.recipeJSON)}
+		for _, s := range recorded.Substitutions {
+			remix.Substitutions = append(remix.Substitutions, ports.SubstitutionData{Original: s.Original, Replacement: s.Replacement, Reason: s.Reason})
+		}
+		return remix, nil
+	}
+
+	unchanged := *original
+	return &ports.RecipeRemix{Recipe: &unchanged}, nil
+}
+
+// minutesDuration converts an optional number of minutes, ignoring non-positive values
+func minutesDuration(minutes *int) *time.Duration {
+	if minutes == nil || *minutes <= 0 {
+		return nil
+	}
+	d := time.Duration(*minutes) * time.Minute
+	return &d
+}
+
+func sameInstructions(recorded []instructionJSON, instructions []ports.InstructionData) bool {
+	if len(recorded) != len(instructions) {
+		return false
+	}
+	for i := range recorded {
+		if recorded[i].Text != instructions[i].Text {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package sandbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/ports"
+)
+
+func TestLLM_ExtractRecipe(t *testing.T) {
+	fixtures := loadDefaultFixtures(t)
+	llm := NewLLM(fixtures)
+
+	scraped, err := NewScraper(fixtures).Scrape(context.Background(), ports.ScrapeRequest{URL: curryURL})
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	extraction, err := llm.ExtractRecipe(context.Background(), "CAPTIONS/DESCRIPTION:\n"+scraped.Captions)
+	if err != nil {
+		t.Fatalf("ExtractRecipe() error = %v", err)
+	}
+
+	if extraction.Title != "One-Pot Chickpea Curry" || extraction.SourceLanguage != "en" {
+		t.Errorf("ExtractRecipe() title = %q, language = %q, want the curry's", extraction.Title, extraction.SourceLanguage)
+	}
+	if len(extraction.Ingredients) != 7 || extraction.Ingredients[4].Name != "chickpeas" || extraction.Ingredients[4].Quantity != "800" {
+		t.Errorf("ExtractRecipe() ingredients = %+v, want the 7 recorded ones", extraction.Ingredients)
+	}
+	if len(extraction.Instructions) != 3 || extraction.Instructions[0].Duration == nil || *extraction.Instructions[0].Duration != 5*time.Minute {
+		t.Errorf("ExtractRecipe() instructions = %+v, want the 3 recorded steps", extraction.Instructions)
+	}
+	if extraction.CookTime == nil || *extraction.CookTime != 25*time.Minute || *extraction.Servings != 4 {
+		t.Errorf("ExtractRecipe() cook time = %v, servings = %v, want 25m and 4", extraction.CookTime, extraction.Servings)
+	}
+	if len(extraction.Provenance) != 6 {
+		t.Errorf("ExtractRecipe() provenance = %+v, want the 6 recorded fields", extraction.Provenance)
+	}
+}
+
+func TestLLM_ExtractRecipes_Compilation(t *testing.T) {
+	fixtures := loadDefaultFixtures(t)
+
+	scraped, err := NewScraper(fixtures).Scrape(context.Background(), ports.ScrapeRequest{URL: "https://www.youtube.com/watch?v=sandbox-breakfasts"})
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	extractions, err := NewLLM(fixtures).ExtractRecipes(context.Background(), scraped.Transcript)
+	if err != nil {
+		t.Fatalf("ExtractRecipes() error = %v", err)
+	}
+	if len(extractions) != 3 {
+		t.Errorf("ExtractRecipes() returned %d recipes, want the 3 of the compilation", len(extractions))
+	}
+}
+
+func TestLLM_RecreateDish(t *testing.T) {
+	extraction, err := NewLLM(loadDefaultFixtures(t)).RecreateDish(context.Background(), []byte("sandbox-curry-photo"), "", "en")
+	if err != nil {
+		t.Fatalf("RecreateDish() error = %v", err)
+	}
+	if extraction.Title != "One-Pot Chickpea Curry" {
+		t.Errorf("RecreateDish() title = %q, want the recipe recorded for the photo", extraction.Title)
+	}
+}
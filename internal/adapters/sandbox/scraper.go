@@ -0,0 +1,53 @@
+package sandbox
+
+import (
+	"context"
+	"time"
+
+	"receipt-bot/internal/ports"
+)
+
+// Scraper implements the ScraperPort interface by replaying recorded fixtures
+type Scraper struct {
+	fixtures *Fixtures
+}
+
+// NewScraper creates a new fixture-backed scraper
+func NewScraper(fixtures *Fixtures) *Scraper {
+	return &Scraper{
+		fixtures: fixtures,
+	}
+}
+
+// Scrape implements the ScraperPort interface
+func (s *Scraper) Scrape(ctx context.Context, req ports.ScrapeRequest) (*ports.ScrapeResult, error) {
+	fixture, err := s.fixtures.ForURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]string, len(fixture.Metadata))
+	for k, v := range fixture.Metadata {
+		metadata[k] = v
+	}
+
+	// Recorded on-screen text stands in for the video's key frames
+	var keyFrames [][]byte
+	if fixture.OnScreenText != "" {
+		keyFrames = [][]byte{[]byte(fixture.OnScreenText)}
+	}
+
+	timeline := make([]ports.TimelineEntry, len(fixture.Timeline))
+	for i, entry := range fixture.Timeline {
+		timeline[i] = ports.TimelineEntry{Start: time.Duration(entry.StartSeconds) * time.Second, Text: entry.Text}
+	}
+
+	return &ports.ScrapeResult{
+		Captions:    fixture.Captions,
+		Transcript:  fixture.Transcript,
+		OriginalURL: req.URL,
+		Metadata:    metadata,
+		KeyFrames:   keyFrames,
+		Timeline:    timeline,
+	}, nil
+}
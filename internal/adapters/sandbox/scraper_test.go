@@ -0,0 +1,63 @@
+package sandbox
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/ports"
+)
+
+const (
+	carbonaraURL = "https://www.youtube.com/watch?v=sandbox-carbonara"
+	curryURL     = "https://www.tiktok.com/@sandbox/video/1"
+)
+
+func loadDefaultFixtures(t *testing.T) *Fixtures {
+	t.Helper()
+	fixtures, err := LoadFixtures("")
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+	return fixtures
+}
+
+func TestScraper_Scrape(t *testing.T) {
+	scraper := NewScraper(loadDefaultFixtures(t))
+
+	result, err := scraper.Scrape(context.Background(), ports.ScrapeRequest{URL: carbonaraURL})
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if !strings.HasPrefix(result.Captions, "Classic spaghetti carbonara") || !strings.HasPrefix(result.Transcript, "Boil the spaghetti") {
+		t.Errorf("Scrape() captions = %q, transcript = %q, want the carbonara's", result.Captions, result.Transcript)
+	}
+	if result.OriginalURL != carbonaraURL || result.Metadata["author"] != "sandbox-chef" {
+		t.Errorf("Scrape() URL = %q, metadata = %v, want the carbonara's", result.OriginalURL, result.Metadata)
+	}
+	if len(result.Timeline) != 5 || result.Timeline[1].Start != 42*time.Second {
+		t.Errorf("Scrape() timeline = %v, want the 5 recorded chapters", result.Timeline)
+	}
+
+	result, err = scraper.Scrape(context.Background(), ports.ScrapeRequest{URL: curryURL})
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if len(result.KeyFrames) != 1 || string(result.KeyFrames[0]) != "800 g chickpeas\n400 ml coconut milk" {
+		t.Errorf("Scrape() key frames = %q, want the recorded on-screen text", result.KeyFrames)
+	}
+}
+
+func TestScraper_Scrape_UnknownURL(t *testing.T) {
+	scraper := NewScraper(loadDefaultFixtures(t))
+
+	const unknown = "https://www.youtube.com/watch?v=not-recorded"
+	result, err := scraper.Scrape(context.Background(), ports.ScrapeRequest{URL: unknown})
+	if err == nil {
+		t.Fatalf("Scrape() = %+v, want an error for a URL without a fixture", result)
+	}
+	if !strings.Contains(err.Error(), unknown) {
+		t.Errorf("Scrape() error = %q, want it to name the URL", err)
+	}
+}
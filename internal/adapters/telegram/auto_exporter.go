@@ -0,0 +1,130 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/user"
+)
+
+// autoExportInterval is how often recipes saved since the last auto-export are exported
+const autoExportInterval = 5 * time.Minute
+
+// autoExportHour is when nightly auto-exports run, in each user's local time
+const autoExportHour = 2
+
+// AutoExporterConfig contains all dependencies for the AutoExporter
+type AutoExporterConfig struct {
+	Bot                *Bot
+	UserRepo           user.Repository
+	AutoExportCommand  *command.AutoExportCommand
+	ActivityLogCommand *command.ActivityLogCommand // optional, leaves auto-exports out of /activity when nil
+}
+
+// AutoExporter exports the recipes users save to Notion or Obsidian, shortly after
+// each save or nightly, as they chose in /autoexport. Notion pages are created
+// silently; Obsidian files are sent to the user.
+type AutoExporter struct {
+	bot                *Bot
+	userRepo           user.Repository
+	autoExportCommand  *command.AutoExportCommand
+	activityLogCommand *command.ActivityLogCommand
+
+	mu      sync.Mutex
+	nightly map[string]string // user ID -> day of the last nightly export
+}
+
+// NewAutoExporter creates a new auto-exporter
+func NewAutoExporter(cfg AutoExporterConfig) *AutoExporter {
+	return &AutoExporter{
+		bot:                cfg.Bot,
+		userRepo:           cfg.UserRepo,
+		autoExportCommand:  cfg.AutoExportCommand,
+		activityLogCommand: cfg.ActivityLogCommand,
+		nightly:            make(map[string]string),
+	}
+}
+
+// Run exports due recipes until ctx is cancelled
+func (e *AutoExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(autoExportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.ExportDue(ctx, now)
+		}
+	}
+}
+
+// ExportDue exports the recipes waiting for every user whose auto-export is due at now
+func (e *AutoExporter) ExportDue(ctx context.Context, now time.Time) {
+	users, err := e.userRepo.FindAll(ctx)
+	if err != nil {
+		log.Printf("Auto-exporter failed to load users: %v", err)
+		return
+	}
+
+	for _, usr := range users {
+		autoExport := usr.AutoExport()
+		if autoExport == nil {
+			continue
+		}
+		// Obsidian files arrive as messages, so they wait for quiet hours to end
+		if autoExport.Target == user.AutoExportObsidian && usr.InQuietHours(now) {
+			continue
+		}
+		if autoExport.Schedule == user.AutoExportNightly {
+			// Once a day after the export hour, for the recipes saved before it
+			local := now.In(usr.Location())
+			day := local.Format("2006-01-02")
+			if local.Hour() < autoExportHour || autoExport.Since.In(usr.Location()).Format("2006-01-02") >= day ||
+				!e.claimNight(usr.ID().String(), day) {
+				continue
+			}
+		}
+
+		result, err := e.autoExportCommand.Export(ctx, usr, now)
+		if err != nil {
+			log.Printf("Auto-export failed for user %s: %v", usr.ID(), err)
+			continue
+		}
+		if result == nil {
+			continue // Nothing saved since the last export
+		}
+
+		if e.activityLogCommand != nil {
+			detail := fmt.Sprintf("auto-export to %s", autoExport.Target)
+			if err := e.activityLogCommand.Record(ctx, usr.ID(), activity.ActionRecipeExported, detail); err != nil {
+				log.Printf("Failed to record auto-export for user %s: %v", usr.ID(), err)
+			}
+		}
+
+		if autoExport.Target == user.AutoExportObsidian {
+			caption := "🔄 Auto-export · " + escapeMarkdown(result.Message)
+			if err := e.bot.SendDocument(ctx, usr.TelegramID(), result.Filename, result.Data, caption); err != nil {
+				log.Printf("Auto-exporter failed to send a file to user %s: %v", usr.ID(), err)
+			}
+		}
+	}
+}
+
+// claimNight marks the nightly export of day as done and reports whether it was not done yet
+func (e *AutoExporter) claimNight(userID, day string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.nightly[userID] == day {
+		return false
+	}
+	e.nightly[userID] = day
+	return true
+}
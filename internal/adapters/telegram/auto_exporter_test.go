@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAutoExporter_ExportDue(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/autoexport")
+	h.expectReply("Off: use /export")
+	h.send("/autoexport notion")
+	h.expectReply("Connect Notion first")
+
+	h.send("/autoexport obsidian")
+	h.expectReply("On: recipes go to Obsidian files sent here shortly after you save each recipe")
+
+	exporter := NewAutoExporter(AutoExporterConfig{
+		Bot:                h.handler.bot,
+		UserRepo:           h.users,
+		AutoExportCommand:  h.handler.autoExportCommand,
+		ActivityLogCommand: h.handler.activityLogCommand,
+	})
+	ctx := context.Background()
+
+	exportDue := func(now time.Time) []string {
+		t.Helper()
+		h.api.Reset()
+		exporter.ExportDue(ctx, now)
+		var files []string
+		for _, msg := range h.api.Messages() {
+			if msg.Document == nil || msg.ChatID != h.from.ID {
+				t.Fatalf("auto-export sent %+v, want a file to the user", msg)
+			}
+			files = append(files, msg.Document.Name)
+		}
+		return files
+	}
+
+	if files := exportDue(time.Now()); len(files) != 0 {
+		t.Errorf("exported %q with nothing saved since auto-export was turned on", files)
+	}
+
+	h.send(carbonaraURL)
+	if files := exportDue(time.Now()); len(files) != 1 || files[0] != "Spaghetti_Carbonara.md" {
+		t.Errorf("exported %q, want the recipe saved since", files)
+	}
+	if files := exportDue(time.Now()); len(files) != 0 {
+		t.Errorf("exported %q again", files)
+	}
+
+	h.send("/activity")
+	h.expectReply("auto\\-export to obsidian")
+
+	// Nightly exports wait for the night
+	h.send("/autoexport obsidian nightly")
+	h.expectReply("every night after 02:00")
+	h.send(curryURL)
+
+	today := time.Now()
+	evening := time.Date(today.Year(), today.Month(), today.Day(), 23, 0, 0, 0, time.Local)
+	if evening.Before(today) {
+		t.Skip("too close to midnight to tell the nightly export apart")
+	}
+	if files := exportDue(evening); len(files) != 0 {
+		t.Errorf("nightly export sent %q in the evening", files)
+	}
+	night := evening.Add(4 * time.Hour) // 03:00 the next day
+	if files := exportDue(night); len(files) != 1 || !strings.Contains(files[0], "Curry") {
+		t.Errorf("nightly export = %q, want the recipe saved that day", files)
+	}
+	if files := exportDue(night.Add(time.Hour)); len(files) != 0 {
+		t.Errorf("nightly export ran twice in a night: %q", files)
+	}
+}
@@ -4,22 +4,47 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/ports"
 )
 
+// maxDownloadSize caps the size of files downloaded from Telegram (photos are far smaller)
+const maxDownloadSize = 20 << 20
+
+// updatesTimeout is how long, in seconds, a getUpdates call waits for new updates
+const updatesTimeout = 60
+
+// chatActionInterval is how often a chat action is repeated; Telegram shows one
+// for about five seconds
+const chatActionInterval = 4 * time.Second
+
 // Bot wraps the Telegram bot API
 type Bot struct {
-	api   *tgbotapi.BotAPI
-	debug bool
+	api          *tgbotapi.BotAPI
+	fileEndpoint string
+	httpClient   *http.Client
+	debug        bool
+	topics       topicNames
+	outbox       *outbox
+	blobs        ports.BlobStorage // optional, files too large for Telegram fail when nil
+	stop         chan struct{}
 }
 
 // Config holds Telegram bot configuration
 type Config struct {
-	BotToken string
-	Debug    bool
+	BotToken    string
+	Debug       bool
+	APIEndpoint string            // optional, defaults to the public Telegram API
+	Blobs       ports.BlobStorage // optional, stores files too large to send so they can be linked
 }
 
 // NewBot creates a new Telegram bot
@@ -28,7 +53,16 @@ func NewBot(config Config) (*Bot, error) {
 		return nil, fmt.Errorf("bot token is required")
 	}
 
-	bot, err := tgbotapi.NewBotAPI(config.BotToken)
+	endpoint := config.APIEndpoint
+	fileEndpoint := tgbotapi.FileEndpoint
+	if endpoint == "" {
+		endpoint = tgbotapi.APIEndpoint
+	} else {
+		// Files are served next to the API, e.g. https://host/file/bot<token>/<path>
+		fileEndpoint = strings.Replace(endpoint, "/bot%s/%s", "/file/bot%s/%s", 1)
+	}
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint(config.BotToken, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bot: %w", err)
 	}
@@ -38,17 +72,61 @@ func NewBot(config Config) (*Bot, error) {
 	log.Printf("Authorized on account %s", bot.Self.UserName)
 
 	return &Bot{
-		api:   bot,
-		debug: config.Debug,
+		api:          bot,
+		fileEndpoint: fileEndpoint,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		debug:        config.Debug,
+		outbox:       newOutbox(),
+		blobs:        config.Blobs,
+		stop:         make(chan struct{}),
 	}, nil
 }
 
-// GetUpdatesChan returns a channel for receiving updates
-func (b *Bot) GetUpdatesChan() tgbotapi.UpdatesChannel {
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+// GetUpdatesChan returns a channel for receiving updates.
+// Updates are polled by hand to keep the forum topic of each message.
+func (b *Bot) GetUpdatesChan() <-chan Update {
+	ch := make(chan Update, b.api.Buffer)
+
+	go func() {
+		offset := 0
+		for {
+			select {
+			case <-b.stop:
+				close(ch)
+				return
+			default:
+			}
+
+			params := tgbotapi.Params{}
+			params.AddNonZero("offset", offset)
+			params.AddNonZero("timeout", updatesTimeout)
+
+			resp, err := b.api.MakeRequest("getUpdates", params)
+			var updates []Update
+			if err == nil {
+				updates, err = b.topics.decode(resp.Result)
+			}
+			if err != nil {
+				log.Printf("Failed to get updates, retrying in 3 seconds: %v", err)
+				time.Sleep(3 * time.Second)
+				continue
+			}
+
+			for _, update := range updates {
+				if update.UpdateID >= offset {
+					offset = update.UpdateID + 1
+					ch <- update
+				}
+			}
+		}
+	}()
 
-	return b.api.GetUpdatesChan(u)
+	return ch
+}
+
+// Username returns the bot's Telegram username
+func (b *Bot) Username() string {
+	return b.api.Self.UserName
 }
 
 // SendMessage sends a text message to a chat
@@ -56,7 +134,51 @@ func (b *Bot) SendMessage(ctx context.Context, chatID int64, text string) error
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "Markdown"
 
-	_, err := b.api.Send(msg)
+	err := b.send(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return nil
+}
+
+// SendMessageWithKeyboard sends a text message with an inline keyboard
+func (b *Bot) SendMessageWithKeyboard(ctx context.Context, chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+
+	err := b.send(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return nil
+}
+
+// webAppKeyboard is an inline keyboard with a button that opens a Mini App.
+// The Telegram library predates Mini Apps, so the markup is built by hand
+type webAppKeyboard struct {
+	InlineKeyboard [][]webAppButton `json:"inline_keyboard"`
+}
+
+type webAppButton struct {
+	Text   string `json:"text"`
+	WebApp struct {
+		URL string `json:"url"`
+	} `json:"web_app"`
+}
+
+// SendWebAppButton sends a text message with a button that opens the Mini App at url
+func (b *Bot) SendWebAppButton(ctx context.Context, chatID int64, text string, buttonText string, url string) error {
+	button := webAppButton{Text: buttonText}
+	button.WebApp.URL = url
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = webAppKeyboard{InlineKeyboard: [][]webAppButton{{button}}}
+
+	err := b.send(ctx, msg)
 	if err != nil {
 		return fmt.Errorf("failed to send message: %w", err)
 	}
@@ -64,15 +186,152 @@ func (b *Bot) SendMessage(ctx context.Context, chatID int64, text string) error
 	return nil
 }
 
+// send sends a message through the outbox, written with the format options of ctx,
+// as plain text if Telegram cannot parse its Markdown
+func (b *Bot) send(ctx context.Context, msg tgbotapi.MessageConfig) error {
+	if msg.ParseMode != "" {
+		if text, markdown := formatOptionsFrom(ctx).Render(msg.Text); !markdown {
+			msg.Text, msg.ParseMode = text, ""
+		}
+	}
+
+	return b.outbox.deliver(ctx, msg.ChatID, "sendMessage", msg.Text, func() error {
+		err := b.sendOnce(ctx, msg)
+		if msg.ParseMode == "" || !isParseError(err) {
+			return err
+		}
+
+		log.Printf("Resending message to chat %d as plain text: %v", msg.ChatID, err)
+		plain := msg
+		plain.Text = plainText(msg.Text)
+		plain.ParseMode = ""
+		return b.sendOnce(ctx, plain)
+	})
+}
+
+// sendOnce sends a message, into the forum topic of ctx if any. The Telegram library
+// predates forum topics, so messages in a topic are sent with hand-built parameters.
+func (b *Bot) sendOnce(ctx context.Context, msg tgbotapi.MessageConfig) error {
+	topic := topicFrom(ctx)
+	if topic.ID == 0 {
+		_, err := b.api.Send(msg)
+		return err
+	}
+
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", msg.ChatID)
+	params.AddNonZero("message_thread_id", topic.ID)
+	params.AddNonEmpty("text", msg.Text)
+	params.AddNonEmpty("parse_mode", msg.ParseMode)
+	params.AddBool("disable_web_page_preview", msg.DisableWebPagePreview)
+	if err := params.AddInterface("reply_markup", msg.ReplyMarkup); err != nil {
+		return err
+	}
+
+	_, err := b.api.MakeRequest("sendMessage", params)
+	return err
+}
+
+// DeadLetters returns the recent messages that could not be delivered, even after
+// retries, newest first
+func (b *Bot) DeadLetters() []DeadLetter {
+	return b.outbox.deadLetters()
+}
+
+// EditMessageWithKeyboard replaces the text and inline keyboard of a sent message
+func (b *Bot) EditMessageWithKeyboard(ctx context.Context, chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+	edit.ParseMode = "Markdown"
+	if plain, markdown := formatOptionsFrom(ctx).Render(text); !markdown {
+		edit.Text, edit.ParseMode = plain, ""
+	}
+
+	_, err := b.api.Request(edit)
+	if isParseError(err) {
+		log.Printf("Editing message %d in chat %d as plain text: %v", messageID, chatID, err)
+		edit.Text = plainText(text)
+		edit.ParseMode = ""
+		_, err = b.api.Request(edit)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+
+	return nil
+}
+
+// EditMessageReplyMarkup replaces only the inline keyboard of a sent message
+func (b *Bot) EditMessageReplyMarkup(ctx context.Context, chatID int64, messageID int, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, keyboard)
+
+	_, err := b.api.Request(edit)
+	if err != nil {
+		return fmt.Errorf("failed to edit message keyboard: %w", err)
+	}
+
+	return nil
+}
+
+// AnswerCallback acknowledges an inline keyboard press, optionally showing a short notice
+func (b *Bot) AnswerCallback(ctx context.Context, callbackID string, text string) error {
+	_, err := b.api.Request(tgbotapi.NewCallback(callbackID, text))
+	if err != nil {
+		return fmt.Errorf("failed to answer callback: %w", err)
+	}
+
+	return nil
+}
+
 // SendRecipe sends a formatted recipe to a chat
 func (b *Bot) SendRecipe(ctx context.Context, chatID int64, rec *recipe.Recipe) error {
 	text := FormatRecipe(rec)
 	return b.SendMessage(ctx, chatID, text)
 }
 
-// SendProgress sends a progress update message
+// SendProgress sends a progress update message, with the time left when ctx has an estimate
 func (b *Bot) SendProgress(ctx context.Context, chatID int64, message string) error {
-	return b.SendMessage(ctx, chatID, message)
+	return b.SendMessage(ctx, chatID, withTimeLeft(ctx, message))
+}
+
+// SendChatAction shows a chat action such as "typing…", in the forum topic of ctx if any
+func (b *Bot) SendChatAction(ctx context.Context, chatID int64, action ports.ChatAction) error {
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", chatID)
+	params.AddNonZero("message_thread_id", topicFrom(ctx).ID)
+	params.AddNonEmpty("action", string(action))
+
+	if _, err := b.api.MakeRequest("sendChatAction", params); err != nil {
+		return fmt.Errorf("failed to send chat action: %w", err)
+	}
+
+	return nil
+}
+
+// KeepChatAction shows a chat action until stop is called or ctx is done, repeating
+// it so it stays visible during long operations
+func (b *Bot) KeepChatAction(ctx context.Context, chatID int64, action ports.ChatAction) (stop func()) {
+	_ = b.SendChatAction(ctx, chatID, action)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(chatActionInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = b.SendChatAction(ctx, chatID, action)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
 }
 
 // SendError sends an error message to a chat
@@ -81,27 +340,157 @@ func (b *Bot) SendError(ctx context.Context, chatID int64, errorMsg string) erro
 	return b.SendMessage(ctx, chatID, text)
 }
 
-// SendDocument sends a file document to a chat
+// SendDocument sends a file document to a chat. A file too large for Telegram is
+// sent as a link to a copy in blob storage instead.
 func (b *Bot) SendDocument(ctx context.Context, chatID int64, filename string, data []byte, caption string) error {
-	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileReader{
-		Name:   filename,
-		Reader: bytes.NewReader(data),
+	var err error
+	if len(data) > maxUploadSize {
+		err = b.sendDocumentLink(ctx, chatID, filename, data, caption)
+	} else if err = b.upload(ctx, "sendDocument", "document", chatID, filename, data, caption); isTooLarge(err) {
+		err = b.sendDocumentLink(ctx, chatID, filename, data, caption)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send document: %w", err)
+	}
+
+	return nil
+}
+
+// SendVoice sends OGG/Opus audio to a chat as a voice message
+func (b *Bot) SendVoice(ctx context.Context, chatID int64, filename string, data []byte, caption string) error {
+	if err := b.upload(ctx, "sendVoice", "voice", chatID, filename, data, caption); err != nil {
+		return fmt.Errorf("failed to send voice message: %w", err)
+	}
+
+	return nil
+}
+
+// SendPhoto sends an image to a chat as a photo
+func (b *Bot) SendPhoto(ctx context.Context, chatID int64, filename string, data []byte, caption string) error {
+	if err := b.upload(ctx, "sendPhoto", "photo", chatID, filename, data, caption); err != nil {
+		return fmt.Errorf("failed to send photo: %w", err)
+	}
+
+	return nil
+}
+
+// upload sends a file through the outbox, into the forum topic of ctx if any, with
+// its caption written with the format options of ctx, or plain if Telegram cannot
+// parse its Markdown. The parameters are built by
+// hand, as the Telegram library predates forum topics.
+func (b *Bot) upload(ctx context.Context, method, field string, chatID int64, filename string, data []byte, caption string) error {
+	topic := topicFrom(ctx)
+	send := func(caption, parseMode string) error {
+		params := tgbotapi.Params{}
+		params.AddNonZero64("chat_id", chatID)
+		params.AddNonZero("message_thread_id", topic.ID)
+		params.AddNonEmpty("caption", caption)
+		params.AddNonEmpty("parse_mode", parseMode)
+
+		// The file is read again on every attempt
+		file := tgbotapi.FileReader{Name: filename, Reader: bytes.NewReader(data)}
+		resp, err := b.api.UploadFiles(method, params, []tgbotapi.RequestFile{{Name: field, Data: file}})
+		return withErrorCode(resp, err)
+	}
+
+	return b.outbox.deliver(ctx, chatID, method, filename, func() error {
+		if caption == "" {
+			return send("", "")
+		}
+		if plain, markdown := formatOptionsFrom(ctx).Render(caption); !markdown {
+			return send(plain, "")
+		}
+
+		err := send(caption, "Markdown")
+		if !isParseError(err) {
+			return err
+		}
+		log.Printf("Resending %s caption to chat %d as plain text: %v", filename, chatID, err)
+		return send(plainText(caption), "")
 	})
+}
 
-	if caption != "" {
-		doc.Caption = caption
-		doc.ParseMode = "Markdown"
+// SendInvoice asks the user to pay a price, in the currency's smallest unit, for
+// something. An empty provider token and the XTR currency charge Telegram Stars.
+func (b *Bot) SendInvoice(ctx context.Context, chatID int64, title, description, payload, providerToken, currency string, price int) error {
+	// Built by hand: the library's InvoiceConfig sends null tip amounts, which Telegram rejects
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", chatID)
+	params.AddNonZero("message_thread_id", topicFrom(ctx).ID)
+	params["title"] = title
+	params["description"] = description
+	params["payload"] = payload
+	params["provider_token"] = providerToken
+	params["currency"] = currency
+	if err := params.AddInterface("prices", []tgbotapi.LabeledPrice{{Label: title, Amount: price}}); err != nil {
+		return err
 	}
 
-	_, err := b.api.Send(doc)
+	err := b.outbox.deliver(ctx, chatID, "sendInvoice", title, func() error {
+		_, err := b.api.MakeRequest("sendInvoice", params)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to send document: %w", err)
+		return fmt.Errorf("failed to send invoice: %w", err)
 	}
 
 	return nil
 }
 
+// AnswerPreCheckout confirms a payment Telegram is about to charge, or refuses it
+// with a reason shown to the user when reason is not empty
+func (b *Bot) AnswerPreCheckout(ctx context.Context, queryID string, reason string) error {
+	// Built by hand: the library's PreCheckoutConfig leaves out ok when it is false
+	params := tgbotapi.Params{}
+	params["pre_checkout_query_id"] = queryID
+	params["ok"] = strconv.FormatBool(reason == "")
+	params.AddNonEmpty("error_message", reason)
+
+	if _, err := b.api.MakeRequest("answerPreCheckoutQuery", params); err != nil {
+		return fmt.Errorf("failed to answer pre-checkout query: %w", err)
+	}
+
+	return nil
+}
+
+// DownloadFile downloads a file users sent to the bot, such as a photo
+func (b *Bot) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	file, err := b.api.GetFile(tgbotapi.FileConfig{FileID: fileID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	url := fmt.Sprintf(b.fileEndpoint, b.api.Token, file.FilePath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return data, nil
+}
+
+// SetDebug toggles verbose logging of Telegram API calls
+func (b *Bot) SetDebug(debug bool) {
+	b.debug = debug
+	b.api.Debug = debug
+}
+
 // Stop stops the bot
 func (b *Bot) Stop() {
-	b.api.StopReceivingUpdates()
+	close(b.stop)
 }
@@ -0,0 +1,41 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// CheckToken asks Telegram which bot the token belongs to and returns its
+// username, without receiving or sending any message. The request is cancelled
+// when ctx is done.
+func CheckToken(ctx context.Context, config Config) (string, error) {
+	if config.BotToken == "" {
+		return "", fmt.Errorf("bot token is required")
+	}
+
+	endpoint := config.APIEndpoint
+	if endpoint == "" {
+		endpoint = tgbotapi.APIEndpoint
+	}
+
+	api, err := tgbotapi.NewBotAPIWithClient(config.BotToken, endpoint, contextClient{ctx})
+	if err != nil {
+		// Errors quote the request URL, which includes the token
+		return "", fmt.Errorf("failed to check the bot token: %s", strings.ReplaceAll(err.Error(), config.BotToken, "<token>"))
+	}
+	return api.Self.UserName, nil
+}
+
+// contextClient sends the Bot API's requests with a context, which the
+// library's requests lack
+type contextClient struct {
+	ctx context.Context
+}
+
+func (c contextClient) Do(req *http.Request) (*http.Response, error) {
+	return http.DefaultClient.Do(req.WithContext(c.ctx))
+}
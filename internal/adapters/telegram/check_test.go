@@ -0,0 +1,49 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/telegram/telegramtest"
+)
+
+func TestCheckToken(t *testing.T) {
+	api := telegramtest.NewServer(t)
+
+	username, err := CheckToken(context.Background(), Config{BotToken: telegramtest.Token, APIEndpoint: api.Endpoint()})
+	if err != nil {
+		t.Fatalf("CheckToken() unexpected error = %v", err)
+	}
+	if username != telegramtest.BotUsername {
+		t.Errorf("CheckToken() = %q, want %q", username, telegramtest.BotUsername)
+	}
+	if messages := api.Messages(); len(messages) != 0 {
+		t.Errorf("expected no messages sent, got %d", len(messages))
+	}
+
+	if _, err := CheckToken(context.Background(), Config{BotToken: "654321:WRONG", APIEndpoint: api.Endpoint()}); err == nil {
+		t.Error("expected an error for a token Telegram refuses")
+	}
+}
+
+func TestCheckToken_HonoursDeadline(t *testing.T) {
+	// A Bot API that never answers
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer api.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := CheckToken(ctx, Config{BotToken: telegramtest.Token, APIEndpoint: api.URL + "/bot%s/%s"}); err == nil {
+		t.Error("CheckToken() of an API that never answers succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("CheckToken() returned after %v, want it to stop at the deadline", elapsed)
+	}
+}
@@ -1,7 +1,9 @@
 package telegram
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +24,7 @@ const (
 
 // PendingClarification tracks what we're asking the user about
 type PendingClarification struct {
+	ID              uint64   // Set when the question is asked, ties its option buttons to it
 	OriginalMessage string   // What the user originally said
 	Question        string   // What we asked them
 	Options         []string // Suggested options (if any)
@@ -33,6 +36,12 @@ type ActiveFilters struct {
 	DietaryTags      []recipe.DietaryTag
 	IngredientFilter *recipe.IngredientFilter
 	SearchTerm       string
+	Difficulty       *recipe.Difficulty
+	MaxMinutes       int // total prep and cook time limit, 0 for none
+
+	// Ingredient match refinements, applied to LastMatchIngredients
+	PerfectOnly bool // only recipes with nothing missing
+	MaxMissing  int  // most ingredients a recipe may lack, 0 for no limit
 }
 
 // ConversationContext stores the context of a user's conversation
@@ -45,8 +54,18 @@ type ConversationContext struct {
 	LastCategory *recipe.Category
 	// LastSearchTerm is the search term from the last search
 	LastSearchTerm string
+	// LastAuthor is the source author from the last author filter
+	LastAuthor string
+	// LastPlatform is the source platform from the last platform filter
+	LastPlatform recipe.Platform
+	// LastFlavor is the flavor profile from the last flavor filter
+	LastFlavor recipe.Flavor
+	// LastSavedPeriod is the period from the last saved-date filter, e.g. "last month"
+	LastSavedPeriod string
 	// LastMatchIngredients is the ingredients from the last match
 	LastMatchIngredients []string
+	// LastViewedRecipe is the recipe the user opened last, which "it" and "that" refer to
+	LastViewedRecipe *dto.RecipeDTO
 	// CurrentOffset is the pagination offset for "show more"
 	CurrentOffset int
 	// UpdatedAt is when the context was last updated
@@ -57,10 +76,17 @@ type ConversationContext struct {
 	State ConversationState
 	// PendingClarification tracks pending clarification if State is StateAwaitingClarification
 	PendingClarification *PendingClarification
+	// SkippedClarification is the last question answered with the user's usual choice
+	// instead of asking it, asked after all when they say "options"
+	SkippedClarification *PendingClarification
 
 	// === NEW: History for LLM Context (last 5 turns) ===
 	// History stores recent conversation turns for context-aware intent detection
 	History []ports.ConversationTurn
+	// Summary condenses the turns trimmed from History, so long sessions keep their context
+	Summary string
+	// unsummarized are the turns trimmed from History and not folded into Summary yet
+	unsummarized []ports.ConversationTurn
 
 	// === NEW: Active Filters for Refinement ===
 	// ActiveFilters stores current filters that can be refined
@@ -69,6 +95,9 @@ type ConversationContext struct {
 
 const maxHistorySize = 5
 
+// maxUnsummarized caps the trimmed turns waiting for a summary, should summaries keep failing
+const maxUnsummarized = 20
+
 // ActionType represents the type of last action
 type ActionType string
 
@@ -79,6 +108,10 @@ const (
 	ActionFilterIngredient ActionType = "filter_ingredient"
 	ActionMatchIngredients ActionType = "match_ingredients"
 	ActionShowCategories  ActionType = "show_categories"
+	ActionFilterAuthor    ActionType = "filter_author"
+	ActionFilterPlatform  ActionType = "filter_platform"
+	ActionFilterFlavor    ActionType = "filter_flavor"
+	ActionFilterSaved     ActionType = "filter_saved"
 	ActionViewRecipe      ActionType = "view_recipe"
 )
 
@@ -87,6 +120,11 @@ type ConversationManager struct {
 	mu       sync.RWMutex
 	contexts map[shared.ID]*ConversationContext
 	ttl      time.Duration
+
+	lastClarification uint64 // ID of the latest clarification asked
+
+	onTurn     func(userID shared.ID, turn ports.ConversationTurn) // optional, sees every turn added
+	summarizer ports.ConversationSummarizer                       // optional, trimmed turns are forgotten when nil
 }
 
 // NewConversationManager creates a new conversation manager
@@ -94,6 +132,9 @@ func NewConversationManager() *ConversationManager {
 	cm := &ConversationManager{
 		contexts: make(map[shared.ID]*ConversationContext),
 		ttl:      30 * time.Minute, // Context expires after 30 minutes of inactivity
+
+		// Start from the clock so buttons of questions asked before a restart match nothing
+		lastClarification: uint64(time.Now().Unix()),
 	}
 
 	// Start cleanup goroutine
@@ -177,6 +218,78 @@ func (cm *ConversationManager) UpdateIngredientSearch(userID shared.ID, searchTe
 	cm.contexts[userID] = ctx
 }
 
+// UpdateAuthorFilter updates the author filter context
+func (cm *ConversationManager) UpdateAuthorFilter(userID shared.ID, author string, recipes []*dto.RecipeDTO) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	ctx, exists := cm.contexts[userID]
+	if !exists {
+		ctx = &ConversationContext{}
+	}
+
+	ctx.LastAction = ActionFilterAuthor
+	ctx.LastAuthor = author
+	ctx.LastRecipes = recipes
+	ctx.CurrentOffset = 0
+	ctx.UpdatedAt = time.Now()
+	cm.contexts[userID] = ctx
+}
+
+// UpdatePlatformFilter updates the platform filter context
+func (cm *ConversationManager) UpdatePlatformFilter(userID shared.ID, platform recipe.Platform, recipes []*dto.RecipeDTO) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	ctx, exists := cm.contexts[userID]
+	if !exists {
+		ctx = &ConversationContext{}
+	}
+
+	ctx.LastAction = ActionFilterPlatform
+	ctx.LastPlatform = platform
+	ctx.LastRecipes = recipes
+	ctx.CurrentOffset = 0
+	ctx.UpdatedAt = time.Now()
+	cm.contexts[userID] = ctx
+}
+
+// UpdateFlavorFilter updates the flavor filter context
+func (cm *ConversationManager) UpdateFlavorFilter(userID shared.ID, flavor recipe.Flavor, recipes []*dto.RecipeDTO) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	ctx, exists := cm.contexts[userID]
+	if !exists {
+		ctx = &ConversationContext{}
+	}
+
+	ctx.LastAction = ActionFilterFlavor
+	ctx.LastFlavor = flavor
+	ctx.LastRecipes = recipes
+	ctx.CurrentOffset = 0
+	ctx.UpdatedAt = time.Now()
+	cm.contexts[userID] = ctx
+}
+
+// UpdateSavedFilter updates the saved-date filter context
+func (cm *ConversationManager) UpdateSavedFilter(userID shared.ID, period string, recipes []*dto.RecipeDTO) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	ctx, exists := cm.contexts[userID]
+	if !exists {
+		ctx = &ConversationContext{}
+	}
+
+	ctx.LastAction = ActionFilterSaved
+	ctx.LastSavedPeriod = period
+	ctx.LastRecipes = recipes
+	ctx.CurrentOffset = 0
+	ctx.UpdatedAt = time.Now()
+	cm.contexts[userID] = ctx
+}
+
 // UpdateMatchIngredients updates the match ingredients context
 func (cm *ConversationManager) UpdateMatchIngredients(userID shared.ID, ingredients []string) {
 	cm.mu.Lock()
@@ -193,6 +306,25 @@ func (cm *ConversationManager) UpdateMatchIngredients(userID shared.ID, ingredie
 	cm.contexts[userID] = ctx
 }
 
+// SetLastViewed remembers the recipe the user just opened
+func (cm *ConversationManager) SetLastViewed(userID shared.ID, rec *dto.RecipeDTO) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	ctx := cm.getOrCreateContext(userID)
+	ctx.LastViewedRecipe = rec
+	ctx.UpdatedAt = time.Now()
+}
+
+// GetLastViewed returns the recipe the user opened last, or nil
+func (cm *ConversationManager) GetLastViewed(userID shared.ID) *dto.RecipeDTO {
+	ctx := cm.GetContext(userID)
+	if ctx == nil {
+		return nil
+	}
+	return ctx.LastViewedRecipe
+}
+
 // IncrementOffset increments the pagination offset
 func (cm *ConversationManager) IncrementOffset(userID shared.ID, amount int) int {
 	cm.mu.Lock()
@@ -208,6 +340,42 @@ func (cm *ConversationManager) IncrementOffset(userID shared.ID, amount int) int
 	return ctx.CurrentOffset
 }
 
+// ConversationReset describes what resetting a conversation cleared
+type ConversationReset struct {
+	Clarification bool // a question was waiting for the user's answer
+	Filters       bool // a search was being refined
+	Results       int  // recipes of the last list, which "#1" and "show more" referred to
+	Turns         int  // messages remembered to understand follow-ups
+}
+
+// Empty reports whether there was nothing to clear
+func (r ConversationReset) Empty() bool {
+	return !r.Clarification && !r.Filters && r.Results == 0 && r.Turns == 0
+}
+
+// Reset clears the user's conversation, as if they had just started talking to the bot,
+// and reports what was cleared
+func (cm *ConversationManager) Reset(userID shared.ID) ConversationReset {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	ctx, exists := cm.contexts[userID]
+	if !exists {
+		return ConversationReset{}
+	}
+	delete(cm.contexts, userID)
+
+	if time.Since(ctx.UpdatedAt) > cm.ttl {
+		return ConversationReset{}
+	}
+	return ConversationReset{
+		Clarification: ctx.State == StateAwaitingClarification,
+		Filters:       ctx.ActiveFilters != nil,
+		Results:       len(ctx.LastRecipes),
+		Turns:         len(ctx.History),
+	}
+}
+
 // ClearContext clears the conversation context for a user
 func (cm *ConversationManager) ClearContext(userID shared.ID) {
 	cm.mu.Lock()
@@ -271,10 +439,18 @@ func (cm *ConversationManager) GetRemainingRecipes(userID shared.ID, pageSize in
 
 // === NEW: Conversation State and History Methods ===
 
+// OnTurn registers a function called with every turn added to a history, such as
+// one keeping the whole conversation for /transcript
+func (cm *ConversationManager) OnTurn(fn func(userID shared.ID, turn ports.ConversationTurn)) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	cm.onTurn = fn
+}
+
 // AddTurn adds a conversation turn to history
 func (cm *ConversationManager) AddTurn(userID shared.ID, role, content string) {
 	cm.mu.Lock()
-	defer cm.mu.Unlock()
 
 	ctx := cm.getOrCreateContext(userID)
 
@@ -286,12 +462,25 @@ func (cm *ConversationManager) AddTurn(userID shared.ID, role, content string) {
 
 	ctx.History = append(ctx.History, turn)
 
-	// Trim to max size (keep most recent turns)
+	// Trim to max size (keep most recent turns), keeping the rest for the summary
 	if len(ctx.History) > maxHistorySize {
+		trimmed := ctx.History[:len(ctx.History)-maxHistorySize]
 		ctx.History = ctx.History[len(ctx.History)-maxHistorySize:]
+		if cm.summarizer != nil {
+			ctx.unsummarized = append(ctx.unsummarized, trimmed...)
+			if len(ctx.unsummarized) > maxUnsummarized {
+				ctx.unsummarized = ctx.unsummarized[len(ctx.unsummarized)-maxUnsummarized:]
+			}
+		}
 	}
 
 	ctx.UpdatedAt = time.Now()
+	onTurn := cm.onTurn
+	cm.mu.Unlock()
+
+	if onTurn != nil {
+		onTurn(userID, turn)
+	}
 }
 
 // GetHistory returns the conversation history for a user
@@ -310,6 +499,65 @@ func (cm *ConversationManager) GetHistory(userID shared.ID) []ports.Conversation
 	return history
 }
 
+// SetSummarizer makes the turns trimmed from histories condensed into a summary
+// instead of forgotten
+func (cm *ConversationManager) SetSummarizer(summarizer ports.ConversationSummarizer) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	cm.summarizer = summarizer
+}
+
+// ContextHistory returns the history for intent detection: a summary of the older
+// turns, brought up to date first, followed by the recent turns
+func (cm *ConversationManager) ContextHistory(ctx context.Context, userID shared.ID) []ports.ConversationTurn {
+	cm.summarize(ctx, userID)
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	conv, exists := cm.contexts[userID]
+	if !exists {
+		return nil
+	}
+
+	history := make([]ports.ConversationTurn, 0, len(conv.History)+1)
+	if conv.Summary != "" {
+		history = append(history, ports.ConversationTurn{Role: ports.RoleSummary, Content: conv.Summary})
+	}
+	return append(history, conv.History...)
+}
+
+// summarize folds the turns trimmed from a user's history into its summary. The
+// turns wait for the next try when the summarizer fails.
+func (cm *ConversationManager) summarize(ctx context.Context, userID shared.ID) {
+	cm.mu.Lock()
+	conv, exists := cm.contexts[userID]
+	if !exists || cm.summarizer == nil || len(conv.unsummarized) == 0 {
+		cm.mu.Unlock()
+		return
+	}
+	summarizer, summary := cm.summarizer, conv.Summary
+	turns := append([]ports.ConversationTurn(nil), conv.unsummarized...)
+	cm.mu.Unlock()
+
+	updated, err := summarizer.SummarizeConversation(ctx, summary, turns)
+	if err != nil {
+		log.Printf("Failed to summarize conversation: %v", err)
+		return
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	// The conversation may have been reset meanwhile
+	if cm.contexts[userID] != conv {
+		return
+	}
+	conv.Summary = updated
+	conv.unsummarized = conv.unsummarized[min(len(turns), len(conv.unsummarized)):]
+}
+
 // GetHistoryForPrompt returns formatted history for LLM context
 func (cm *ConversationManager) GetHistoryForPrompt(userID shared.ID) string {
 	history := cm.GetHistory(userID)
@@ -353,11 +601,14 @@ func (cm *ConversationManager) GetState(userID shared.ID) ConversationState {
 	return ctx.State
 }
 
-// SetPendingClarification sets the pending clarification for a user
+// SetPendingClarification sets the pending clarification for a user and gives it an ID
 func (cm *ConversationManager) SetPendingClarification(userID shared.ID, pending *PendingClarification) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
+	cm.lastClarification++
+	pending.ID = cm.lastClarification
+
 	ctx := cm.getOrCreateContext(userID)
 	ctx.PendingClarification = pending
 	ctx.State = StateAwaitingClarification
@@ -390,6 +641,31 @@ func (cm *ConversationManager) ClearPendingClarification(userID shared.ID) {
 	ctx.UpdatedAt = time.Now()
 }
 
+// SetSkippedClarification remembers a question answered with the user's usual choice
+func (cm *ConversationManager) SetSkippedClarification(userID shared.ID, skipped *PendingClarification) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	ctx := cm.getOrCreateContext(userID)
+	ctx.SkippedClarification = skipped
+	ctx.UpdatedAt = time.Now()
+}
+
+// TakeSkippedClarification returns and clears the last question answered with the
+// user's usual choice, or nil if there is none
+func (cm *ConversationManager) TakeSkippedClarification(userID shared.ID) *PendingClarification {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	ctx, exists := cm.contexts[userID]
+	if !exists {
+		return nil
+	}
+	skipped := ctx.SkippedClarification
+	ctx.SkippedClarification = nil
+	return skipped
+}
+
 // SetActiveFilters sets the active filters for a user
 func (cm *ConversationManager) SetActiveFilters(userID shared.ID, filters *ActiveFilters) {
 	cm.mu.Lock()
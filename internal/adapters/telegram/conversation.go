@@ -58,7 +58,7 @@ func (h *ConversationHandler) HandleMessage(ctx context.Context, chatID int64, u
 	h.conversationManager.AddTurn(userID, "user", text)
 
 	// Get conversation history for context-aware detection
-	history := h.conversationManager.GetHistory(userID)
+	history := h.conversationManager.ContextHistory(ctx, userID)
 
 	// Detect intent with conversation context
 	intent, err := h.intentDetector.DetectIntentWithContext(ctx, text, history)
@@ -112,7 +112,7 @@ func (h *ConversationHandler) handleClarificationResponse(ctx context.Context, c
 	}
 
 	// Re-detect intent with the combined context
-	history := h.conversationManager.GetHistory(userID)
+	history := h.conversationManager.ContextHistory(ctx, userID)
 	intent, err := h.intentDetector.DetectIntentWithContext(ctx, combinedQuery, history)
 	if err != nil {
 		return "", fmt.Errorf("failed to detect intent after clarification: %w", err)
@@ -215,6 +215,16 @@ func (h *ConversationHandler) mergeFilters(intent *ports.Intent, activeFilters *
 		merged.IngredientFilter = mergedFilter
 	}
 
+	// Merge difficulty - new intent takes precedence if set
+	if merged.Difficulty == nil && activeFilters.Difficulty != nil {
+		merged.Difficulty = activeFilters.Difficulty
+	}
+
+	// Merge time limit - new intent takes precedence if set
+	if merged.MaxMinutes == 0 && activeFilters.MaxMinutes > 0 {
+		merged.MaxMinutes = activeFilters.MaxMinutes
+	}
+
 	// Merge search term - new intent takes precedence if set
 	if merged.SearchTerm == "" && activeFilters.SearchTerm != "" {
 		merged.SearchTerm = activeFilters.SearchTerm
@@ -234,6 +244,8 @@ func (h *ConversationHandler) intentToFilters(intent *ports.Intent) *ActiveFilte
 		DietaryTags:      intent.DietaryTags,
 		IngredientFilter: intent.IngredientFilter,
 		SearchTerm:       intent.SearchTerm,
+		Difficulty:       intent.Difficulty,
+		MaxMinutes:       intent.MaxMinutes,
 	}
 }
 
@@ -262,7 +274,7 @@ func (h *ConversationHandler) parseOptionSelection(text string, options []string
 
 // GetIntentWithHistory detects intent using conversation history
 func (h *ConversationHandler) GetIntentWithHistory(ctx context.Context, userID shared.ID, text string) (*ports.Intent, error) {
-	history := h.conversationManager.GetHistory(userID)
+	history := h.conversationManager.ContextHistory(ctx, userID)
 	return h.intentDetector.DetectIntentWithContext(ctx, text, history)
 }
 
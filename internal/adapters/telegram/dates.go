@@ -0,0 +1,133 @@
+package telegram
+
+import (
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/user"
+)
+
+// Dates writes dates and times the way a user reads them: in their time zone and
+// in the style of their locale. The zero value writes them in the server's time zone
+// in the default English style.
+type Dates struct {
+	Location *time.Location // nil for the server's time zone
+	Locale   user.Locale    // empty for the default English style
+	Now      time.Time      // what relative times such as "3 days ago" count from
+}
+
+// DatesFor returns how dates are written for a user at now
+func DatesFor(usr *user.User, now time.Time) Dates {
+	return Dates{Location: usr.Location(), Locale: usr.Locale(), Now: now}
+}
+
+// dateLayouts are the layouts of a locale for a full date, a day of the month,
+// a weekday and a time of day
+type dateLayouts struct {
+	date, day, weekday, clock string
+}
+
+var localeLayouts = map[user.Locale]dateLayouts{
+	user.LocaleEnglishUS:    {date: "Jan 2, 2006", day: "Jan 2", weekday: "Mon Jan 2", clock: "3:04 PM"},
+	user.LocaleEnglishUK:    {date: "02 Jan 2006", day: "02 Jan", weekday: "Mon 02 Jan", clock: "15:04"},
+	user.LocalePortugueseBR: {date: "02/01/2006", day: "02/01", weekday: "02/01", clock: "15:04"},
+	user.LocalePortuguesePT: {date: "02/01/2006", day: "02/01", weekday: "02/01", clock: "15:04"},
+}
+
+// layouts returns the date layouts of the locale
+func (d Dates) layouts() dateLayouts {
+	if layouts, ok := localeLayouts[d.Locale]; ok {
+		return layouts
+	}
+	return localeLayouts[user.LocaleEnglishUK]
+}
+
+// local returns t in the user's time zone
+func (d Dates) local(t time.Time) time.Time {
+	if d.Location == nil {
+		return t.In(time.Local)
+	}
+	return t.In(d.Location)
+}
+
+// Date writes a full date, such as "02 Jan 2006" or "Jan 2, 2006"
+func (d Dates) Date(t time.Time) string {
+	return d.local(t).Format(d.layouts().date)
+}
+
+// Day writes a day of the month without the year, such as "02 Jan"
+func (d Dates) Day(t time.Time) string {
+	return d.local(t).Format(d.layouts().day)
+}
+
+// Weekday writes a day along with its weekday where the locale writes one, such as "Mon 02 Jan"
+func (d Dates) Weekday(t time.Time) string {
+	return d.local(t).Format(d.layouts().weekday)
+}
+
+// DateTime writes a full date and time of day, such as "02 Jan 2006 15:04"
+func (d Dates) DateTime(t time.Time) string {
+	return d.Date(t) + " " + d.Clock(t)
+}
+
+// DayTime writes a day of the month and time of day, such as "02 Jan 15:04"
+func (d Dates) DayTime(t time.Time) string {
+	return d.Day(t) + " " + d.Clock(t)
+}
+
+// Clock writes a time of day, such as "15:04" or "3:04 PM"
+func (d Dates) Clock(t time.Time) string {
+	return d.local(t).Format(d.layouts().clock)
+}
+
+// Ago writes how long before now t was, such as "3 days ago" or "há 3 dias".
+// Days are counted on the user's calendar; after a month the date is written instead.
+func (d Dates) Ago(t time.Time) string {
+	pt := d.Locale.Language() == user.LanguagePortuguese
+	elapsed := d.Now.Sub(t)
+
+	switch {
+	case elapsed < time.Minute:
+		if pt {
+			return "agora mesmo"
+		}
+		return "just now"
+	case elapsed < time.Hour:
+		return agoUnit(int(elapsed/time.Minute), "minute", "minuto", pt)
+	}
+
+	now, then := d.local(d.Now), d.local(t)
+	days := int(civilDay(now).Sub(civilDay(then)).Hours() / 24)
+	switch {
+	case days == 0:
+		return agoUnit(int(elapsed/time.Hour), "hour", "hora", pt)
+	case days == 1:
+		if pt {
+			return "ontem"
+		}
+		return "yesterday"
+	case days <= 30:
+		return agoUnit(days, "day", "dia", pt)
+	}
+	if pt {
+		return "em " + d.Date(t)
+	}
+	return "on " + d.Date(t)
+}
+
+// agoUnit writes a count of units in the past, such as "3 days ago" or "há 3 dias"
+func agoUnit(n int, en, pt string, portuguese bool) string {
+	if n != 1 {
+		en += "s"
+		pt += "s"
+	}
+	if portuguese {
+		return fmt.Sprintf("há %d %s", n, pt)
+	}
+	return fmt.Sprintf("%d %s ago", n, en)
+}
+
+// civilDay returns midnight of t's day, in UTC so days are all 24 hours long
+func civilDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
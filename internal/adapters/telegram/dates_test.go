@@ -0,0 +1,69 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/user"
+)
+
+func TestDates_Layouts(t *testing.T) {
+	at := time.Date(2026, time.March, 4, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale   user.Locale
+		dateTime string
+	}{
+		{"", "04 Mar 2026 18:30"},
+		{user.LocaleEnglishUK, "04 Mar 2026 18:30"},
+		{user.LocaleEnglishUS, "Mar 4, 2026 6:30 PM"},
+		{user.LocalePortugueseBR, "04/03/2026 18:30"},
+	}
+
+	for _, tt := range tests {
+		d := Dates{Location: time.UTC, Locale: tt.locale}
+		if got := d.DateTime(at); got != tt.dateTime {
+			t.Errorf("DateTime() in %q = %q, want %q", tt.locale, got, tt.dateTime)
+		}
+	}
+
+	// Times are written in the user's time zone, which can move the date
+	tokyo := time.FixedZone("JST", 9*60*60)
+	if got := (Dates{Location: tokyo}).DateTime(at); got != "05 Mar 2026 03:30" {
+		t.Errorf("DateTime() in Tokyo = %q, want the next day", got)
+	}
+}
+
+func TestDates_Ago(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 9, 0, 0, 0, time.UTC)
+	en := Dates{Location: time.UTC, Locale: user.LocaleEnglishUS, Now: now}
+	pt := Dates{Location: time.UTC, Locale: user.LocalePortugueseBR, Now: now}
+
+	tests := []struct {
+		dates Dates
+		ago   time.Duration
+		want  string
+	}{
+		{en, 10 * time.Second, "just now"},
+		{en, time.Minute, "1 minute ago"},
+		{en, 3 * time.Hour, "3 hours ago"},
+		{en, 10 * time.Hour, "yesterday"},
+		{en, 3 * 24 * time.Hour, "3 days ago"},
+		{en, 60 * 24 * time.Hour, "on Jan 3, 2026"},
+		{pt, 5 * time.Minute, "há 5 minutos"},
+		{pt, 10 * time.Hour, "ontem"},
+		{pt, 3 * 24 * time.Hour, "há 3 dias"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.dates.Ago(now.Add(-tt.ago)); got != tt.want {
+			t.Errorf("Ago(%s) in %q = %q, want %q", tt.ago, tt.dates.Locale, got, tt.want)
+		}
+	}
+
+	// Yesterday on the user's calendar, not the server's
+	tokyo := time.FixedZone("JST", 9*60*60)
+	if got := (Dates{Location: tokyo, Now: now}).Ago(now.Add(-10 * time.Hour)); got != "10 hours ago" {
+		t.Errorf("Ago() in Tokyo = %q, want the same day", got)
+	}
+}
@@ -0,0 +1,117 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxUploadSize is the largest file Telegram takes from bots
+const maxUploadSize = 50 << 20
+
+// downloadLinkExpiry is how long the link to a file too large for Telegram works
+const downloadLinkExpiry = 7 * 24 * time.Hour
+
+// isParseError reports whether Telegram refused a message because its Markdown
+// is broken, e.g. a recipe title with an unmatched "_"
+func isParseError(err error) bool {
+	var apiErr *tgbotapi.Error
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.Message, "can't parse entities")
+}
+
+// isTooLarge reports whether Telegram refused a file for its size
+func isTooLarge(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == http.StatusRequestEntityTooLarge ||
+		strings.Contains(strings.ToLower(apiErr.Message), "too large") ||
+		strings.Contains(strings.ToLower(apiErr.Message), "too big")
+}
+
+// plainText turns Markdown into the text a reader would see: formatting marks and
+// escapes are dropped and links show their URL. It is what is sent when Telegram
+// cannot parse the Markdown.
+func plainText(markdown string) string {
+	var sb strings.Builder
+	for i := 0; i < len(markdown); i++ {
+		rest := markdown[i:]
+		switch c := markdown[i]; {
+		case c == '\\' && i+1 < len(markdown):
+			i++
+			sb.WriteByte(markdown[i])
+		case c == '*' || c == '_' || c == '`':
+		case strings.HasPrefix(rest, "http://") || strings.HasPrefix(rest, "https://"):
+			// URLs keep their underscores
+			end := strings.IndexAny(rest, " \n\t)")
+			if end < 0 {
+				end = len(rest)
+			}
+			sb.WriteString(rest[:end])
+			i += end - 1
+		case c == '[':
+			text, url, n, ok := markdownLink(rest)
+			if !ok {
+				sb.WriteByte(c)
+				continue
+			}
+			sb.WriteString(plainText(text) + " (" + url + ")")
+			i += n - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// markdownLink parses a [text](url) link at the start of s, returning the length
+// it spans
+func markdownLink(s string) (text, url string, n int, ok bool) {
+	closing := strings.Index(s, "](")
+	if closing < 0 || strings.Contains(s[:closing], "\n") {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(s[closing+2:], ')')
+	if end < 0 {
+		return "", "", 0, false
+	}
+	return s[1:closing], s[closing+2 : closing+2+end], closing + 3 + end, true
+}
+
+// sendDocumentLink stores a file too large for Telegram in blob storage and sends
+// a link to download it instead
+func (b *Bot) sendDocumentLink(ctx context.Context, chatID int64, filename string, data []byte, caption string) error {
+	if b.blobs == nil {
+		return fmt.Errorf("%s is too large to send (%d bytes) and no blob storage is configured", filename, len(data))
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	key := fmt.Sprintf("downloads/%d/%d-%s", chatID, time.Now().UnixNano(), filename)
+	if err := b.blobs.Put(ctx, key, contentType, data); err != nil {
+		return fmt.Errorf("failed to store %s: %w", filename, err)
+	}
+	url, err := b.blobs.SignedURL(ctx, key, downloadLinkExpiry)
+	if err != nil {
+		return fmt.Errorf("failed to link %s: %w", filename, err)
+	}
+	log.Printf("Sending %s (%d bytes) to chat %d as a download link", filename, len(data), chatID)
+
+	text := fmt.Sprintf("📎 %s is too large to send here: [download it](%s). The link works for %d days.",
+		escapeMarkdown(filename), url, int(downloadLinkExpiry/(24*time.Hour)))
+	if caption != "" {
+		text = caption + "\n\n" + text
+	}
+	return b.SendMessage(ctx, chatID, text)
+}
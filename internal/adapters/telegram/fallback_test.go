@@ -0,0 +1,99 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"receipt-bot/internal/adapters/memory"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"*Pasta* with _basil_", "Pasta with basil"},
+		{"Costs 5\\.50 \\(each\\)", "Costs 5.50 (each)"},
+		{"🔗 [Read the post](https://example.com/a_b)", "🔗 Read the post (https://example.com/a_b)"},
+		{"See https://example.com/snake_case_url now", "See https://example.com/snake_case_url now"},
+		{"unmatched [bracket and *star", "unmatched [bracket and star"},
+		{"`code`", "code"},
+	}
+
+	for _, tt := range tests {
+		if got := plainText(tt.in); got != tt.want {
+			t.Errorf("plainText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBot_PlainTextFallback(t *testing.T) {
+	bot, api, _ := newOutboxBot(t)
+	ctx := context.Background()
+
+	api.RejectMarkdownNext("sendMessage", 1)
+	if err := bot.SendMessage(ctx, 42, "*Tom_Yum* soup"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	msgs := api.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("sent %d messages, want the rejected one and its plain copy", len(msgs))
+	}
+	if msgs[1].Text != "TomYum soup" || msgs[1].ParseMode != "" {
+		t.Errorf("resent %q with parse mode %q, want plain text", msgs[1].Text, msgs[1].ParseMode)
+	}
+
+	// Captions fall back the same way, with the file sent again
+	api.Reset()
+	api.RejectMarkdownNext("sendDocument", 1)
+	if err := bot.SendDocument(ctx, 42, "soup.md", []byte("# Soup"), "_broken"); err != nil {
+		t.Fatalf("SendDocument() error = %v", err)
+	}
+	docs := api.CallsTo("sendDocument")
+	if len(docs) != 2 || docs[1].Params["caption"] != "broken" || docs[1].Params["parse_mode"] != "" {
+		t.Fatalf("sendDocument calls = %+v, want a plain caption on the second", docs)
+	}
+	if got := string(docs[1].Files["document"].Data); got != "# Soup" {
+		t.Errorf("resent document = %q, want %q", got, "# Soup")
+	}
+
+	if letters := bot.DeadLetters(); len(letters) != 0 {
+		t.Errorf("DeadLetters() = %+v, want none", letters)
+	}
+}
+
+func TestBot_DocumentTooLarge(t *testing.T) {
+	bot, api, _ := newOutboxBot(t)
+	ctx := context.Background()
+
+	// Without blob storage there is nowhere to link to
+	api.TooLargeNext("sendDocument", 1)
+	if err := bot.SendDocument(ctx, 42, "cookbook.pdf", []byte("%PDF"), "Your cookbook"); err == nil {
+		t.Fatal("SendDocument() error = nil, want too large")
+	}
+
+	blobs := memory.NewBlobStorage()
+	bot.blobs = blobs
+	api.Reset()
+	api.TooLargeNext("sendDocument", 1)
+	if err := bot.SendDocument(ctx, 42, "cookbook.pdf", []byte("%PDF"), "Your cookbook"); err != nil {
+		t.Fatalf("SendDocument() error = %v", err)
+	}
+
+	msgs := api.CallsTo("sendMessage")
+	if len(msgs) != 1 {
+		t.Fatalf("sent %d messages, want the download link", len(msgs))
+	}
+	text := msgs[0].Params["text"]
+	if !strings.HasPrefix(text, "Your cookbook\n\n📎 cookbook\\.pdf is too large") || !strings.Contains(text, "[download it](memory:///downloads") {
+		t.Errorf("link message = %q, want the caption and a download link", text)
+	}
+
+	start := strings.Index(text, "memory:///") + len("memory:///")
+	key := text[start : start+strings.IndexByte(text[start:], '?')]
+	key = strings.ReplaceAll(key, "%2F", "/")
+	if data, err := blobs.Get(ctx, key); err != nil || string(data) != "%PDF" {
+		t.Errorf("stored file %q = %q, %v, want the cookbook", key, data, err)
+	}
+}
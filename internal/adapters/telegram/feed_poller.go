@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/domain/user"
+)
+
+// feedPollInterval is how often followed feeds are read for new posts
+const feedPollInterval = time.Hour
+
+// FeedPollerConfig contains all dependencies for the FeedPoller
+type FeedPollerConfig struct {
+	Bot                        *Bot
+	UserRepo                   user.Repository
+	ManageSubscriptionsCommand *command.ManageSubscriptionsCommand
+}
+
+// FeedPoller reads the recipe blog feeds users follow and sends them the recipes
+// of new posts, with a button to save each one
+type FeedPoller struct {
+	bot                        *Bot
+	userRepo                   user.Repository
+	manageSubscriptionsCommand *command.ManageSubscriptionsCommand
+}
+
+// NewFeedPoller creates a new feed poller
+func NewFeedPoller(cfg FeedPollerConfig) *FeedPoller {
+	return &FeedPoller{
+		bot:                        cfg.Bot,
+		userRepo:                   cfg.UserRepo,
+		manageSubscriptionsCommand: cfg.ManageSubscriptionsCommand,
+	}
+}
+
+// Run polls the followed feeds until ctx is cancelled
+func (p *FeedPoller) Run(ctx context.Context) {
+	ticker := time.NewTicker(feedPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Poll(ctx)
+		}
+	}
+}
+
+// Poll reads the followed feeds once and sends the recipes of their new posts
+func (p *FeedPoller) Poll(ctx context.Context) {
+	posts, err := p.manageSubscriptionsCommand.Poll(ctx)
+	if err != nil {
+		log.Printf("Feed poller stopped early: %v", err)
+	}
+
+	for _, post := range posts {
+		usr, err := p.userRepo.FindByID(ctx, post.UserID)
+		if err != nil {
+			log.Printf("Feed poller failed to load user %s: %v", post.UserID, err)
+			continue
+		}
+		if err := p.bot.SendMessageWithKeyboard(ctx, usr.TelegramID(), FormatFeedPost(post), FeedPostKeyboard(post.ID)); err != nil {
+			log.Printf("Feed poller failed to send a post to user %s: %v", post.UserID, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"receipt-bot/internal/domain/user"
+)
+
+// FormatOptions are how the messages sent to a user are written. Formatters write
+// Markdown with emojis; the options decide how it reaches the user when it is sent.
+type FormatOptions struct {
+	// Plain drops emojis and formatting, numbers list items and spells out table
+	// separators, as screen readers read them best
+	Plain bool
+}
+
+// FormatOptionsFor returns how messages are written for a user
+func FormatOptionsFor(usr *user.User) FormatOptions {
+	return FormatOptions{Plain: usr.PlainMode()}
+}
+
+type formatOptionsContextKey struct{}
+
+// withFormatOptions returns a context whose messages are written with opts
+func withFormatOptions(ctx context.Context, opts FormatOptions) context.Context {
+	return context.WithValue(ctx, formatOptionsContextKey{}, opts)
+}
+
+// formatOptionsFrom returns how messages sent with ctx are written, the zero
+// FormatOptions when none were set
+func formatOptionsFrom(ctx context.Context) FormatOptions {
+	opts, _ := ctx.Value(formatOptionsContextKey{}).(FormatOptions)
+	return opts
+}
+
+// Render returns a message written in Markdown as the user reads it, and whether
+// it is still Markdown
+func (o FormatOptions) Render(markdown string) (string, bool) {
+	if !o.Plain {
+		return markdown, true
+	}
+
+	lines := strings.Split(plainText(markdown), "\n")
+	item := 0
+	for i, line := range lines {
+		line = strings.Join(strings.Fields(stripEmojis(line)), " ")
+		line = strings.ReplaceAll(line, " | ", ", ")
+
+		// Bulleted lists become numbered ones, counting from 1 in each list
+		if rest, ok := cutBullet(line); ok {
+			item++
+			line = fmt.Sprintf("%d. %s", item, rest)
+		} else {
+			item = 0
+		}
+		lines[i] = line
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), false
+}
+
+// cutBullet returns a line without the bullet it starts with
+func cutBullet(line string) (string, bool) {
+	for _, bullet := range []string{"• ", "- ", "◦ ", "▪ "} {
+		if rest, ok := strings.CutPrefix(line, bullet); ok {
+			return rest, true
+		}
+	}
+	return line, false
+}
+
+// stripEmojis removes emojis and the characters that join or style them, keeping
+// symbols that are read as words, like "°" or "→"
+func stripEmojis(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isEmoji(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// isEmoji reports whether r is an emoji or a character that joins or styles emojis
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, flags and the like
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols and dingbats, like ☀ or ✅
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // technical symbols used as emojis, like ⏱
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // ⭐ and arrows drawn as emojis
+		return true
+	case r == 0x200D || r == 0x20E3 || (r >= 0xFE00 && r <= 0xFE0F): // joiners, keycaps and variation selectors
+		return true
+	case r == 0x2139 || r == 0x203C || r == 0x2049: // ℹ ‼ ⁉
+		return true
+	default:
+		return false
+	}
+}
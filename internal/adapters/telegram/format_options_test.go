@@ -0,0 +1,26 @@
+package telegram
+
+import "testing"
+
+func TestFormatOptions_Render(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"🍳 *Pasta*\n\n📝 *Ingredients*\n• 200 g spaghetti\n• 2 eggs\n\n• salt", "Pasta\n\nIngredients\n1. 200 g spaghetti\n2. 2 eggs\n\n1. salt"},
+		{"   _Pasta_ \\| instagram", "Pasta, instagram"},
+		{"⏱️ Prep: 10 min at 180°C → done ✅", "Prep: 10 min at 180°C → done"},
+		{"👨‍🍳 Chef's pick ⭐", "Chef's pick"},
+	}
+
+	for _, tt := range tests {
+		got, markdown := FormatOptions{Plain: true}.Render(tt.in)
+		if got != tt.want || markdown {
+			t.Errorf("Render(%q) = %q, %v, want %q, false", tt.in, got, markdown, tt.want)
+		}
+	}
+
+	if got, markdown := (FormatOptions{}).Render("🍳 *Pasta*"); got != "🍳 *Pasta*" || !markdown {
+		t.Errorf("Render() without options = %q, %v, want the Markdown unchanged", got, markdown)
+	}
+}
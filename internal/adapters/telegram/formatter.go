@@ -2,11 +2,33 @@ package telegram
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"receipt-bot/internal/application/command"
 	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/claims"
+	"receipt-bot/internal/domain/conversation"
+	"receipt-bot/internal/domain/cooking"
+	"receipt-bot/internal/domain/diet"
+	"receipt-bot/internal/domain/feed"
+	"receipt-bot/internal/domain/freezer"
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/menu"
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/nutrition"
 	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/report"
+	"receipt-bot/internal/domain/requests"
+	"receipt-bot/internal/domain/share"
+	"receipt-bot/internal/domain/shopping"
+	"receipt-bot/internal/domain/stats"
 	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
 )
 
 // FormatRecipe formats a recipe for Telegram display
@@ -53,20 +75,27 @@ func FormatRecipe(rec *recipe.Recipe) string {
 	for _, ing := range rec.Ingredients() {
 		sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(ing.String())))
 	}
+	if rec.NeedsQuantityCheck() {
+		sb.WriteString(formatQuantityWarning(GetTranslations(user.LanguageEnglish).CheckQuantities))
+	}
 	sb.WriteString("\n")
 
 	// Instructions
 	sb.WriteString("👨‍🍳 *Instructions*\n")
 	for _, inst := range rec.Instructions() {
-		sb.WriteString(fmt.Sprintf("%s\n", escapeMarkdown(inst.String())))
+		var startSeconds *int
+		if inst.Timestamp() != nil {
+			seconds := int(inst.Timestamp().Seconds())
+			startSeconds = &seconds
+		}
+		link := formatStepLink(string(rec.Source().Platform()), rec.Source().URL(), startSeconds, "watch this step")
+		sb.WriteString(fmt.Sprintf("%s%s\n", escapeMarkdown(inst.String()), link))
 	}
 	sb.WriteString("\n")
 
 	// Source
 	sb.WriteString("🔗 *Source*\n")
-	sb.WriteString(fmt.Sprintf("[%s](%s)\n",
-		escapeMarkdown(string(rec.Source().Platform())),
-		rec.Source().URL()))
+	sb.WriteString(formatSource(string(rec.Source().Platform()), rec.Source().URL(), rec.Source().Segment()) + "\n")
 
 	if rec.Source().Author() != "" {
 		sb.WriteString(fmt.Sprintf("By: %s\n", escapeMarkdown(rec.Source().Author())))
@@ -83,7 +112,40 @@ type TranslatedRecipeDTO struct {
 }
 
 // FormatRecipeDTOWithTranslation formats a recipe DTO with optional translation
-func FormatRecipeDTOWithTranslation(rec *dto.RecipeDTO, translation *TranslatedRecipeDTO, lang user.Language) string {
+func FormatRecipeDTOWithTranslation(rec *dto.RecipeDTO, translation *TranslatedRecipeDTO, lang user.Language, dates Dates) string {
+	return formatRecipeDetails(rec, translation, nil, lang, dates)
+}
+
+// FormatSimplifiedRecipe formats a recipe DTO showing simplified steps instead of its instructions
+func FormatSimplifiedRecipe(rec *dto.RecipeDTO, translation *TranslatedRecipeDTO, steps []dto.InstructionDTO, lang user.Language, dates Dates) string {
+	t := GetTranslations(lang)
+	return formatRecipeDetails(rec, translation, &recipeView{heading: t.SimpleInstructions, steps: steps}, lang, dates)
+}
+
+// FormatConvertedRecipe formats a recipe showing its steps and times adapted to an appliance
+func FormatConvertedRecipe(converted *command.ConvertedRecipe, translation *TranslatedRecipeDTO, lang user.Language, dates Dates) string {
+	t := GetTranslations(lang)
+
+	rec := *converted.Recipe
+	rec.PrepTimeMinutes = converted.PrepTimeMinutes
+	rec.CookTimeMinutes = converted.CookTimeMinutes
+
+	return formatRecipeDetails(&rec, translation, &recipeView{
+		heading: fmt.Sprintf(t.ConvertedInstructions, converted.Appliance.String()),
+		steps:   converted.Steps,
+		notes:   converted.Notes,
+	}, lang, dates)
+}
+
+// recipeView replaces the instructions of a recipe when it is shown adapted, e.g. simplified
+type recipeView struct {
+	heading string
+	steps   []dto.InstructionDTO
+	notes   string // shown after the steps
+}
+
+// formatRecipeDetails formats a recipe, replacing its instructions with the view's steps when given
+func formatRecipeDetails(rec *dto.RecipeDTO, translation *TranslatedRecipeDTO, view *recipeView, lang user.Language, dates Dates) string {
 	var sb strings.Builder
 
 	// Use translation if available, otherwise original
@@ -91,7 +153,7 @@ func FormatRecipeDTOWithTranslation(rec *dto.RecipeDTO, translation *TranslatedR
 	ingredients := rec.Ingredients
 	instructions := rec.Instructions
 
-	if translation != nil && lang == user.LanguagePortuguese {
+	if translation != nil {
 		title = translation.Title
 		ingredients = translation.Ingredients
 		instructions = translation.Instructions
@@ -103,6 +165,10 @@ func FormatRecipeDTOWithTranslation(rec *dto.RecipeDTO, translation *TranslatedR
 	// Title
 	sb.WriteString(fmt.Sprintf("🍳 *%s*\n\n", escapeMarkdown(title)))
 
+	if rec.ParentRecipeID != "" {
+		sb.WriteString(fmt.Sprintf("🔀 %s\n\n", fmt.Sprintf(t.RemixOf, "*"+escapeMarkdown(rec.RemixOf)+"*", escapeMarkdown(rec.RemixGoal))))
+	}
+
 	// Metadata
 	sb.WriteString(fmt.Sprintf("📊 *%s*\n", t.Info))
 
@@ -128,6 +194,11 @@ func FormatRecipeDTOWithTranslation(rec *dto.RecipeDTO, translation *TranslatedR
 		sb.WriteString(fmt.Sprintf("🌍 %s: %s\n", t.Cuisine, escapeMarkdown(rec.Cuisine)))
 	}
 
+	if rec.Difficulty != "" {
+		sb.WriteString(fmt.Sprintf("%s %s: %s (%d/%d)\n", difficultyEmoji(rec.Difficulty), t.Difficulty,
+			TranslateDifficulty(rec.Difficulty, lang), rec.DifficultyScore, recipe.MaxDifficultyScore))
+	}
+
 	if len(rec.DietaryTags) > 0 {
 		tags := make([]string, len(rec.DietaryTags))
 		for i, tag := range rec.DietaryTags {
@@ -136,9 +207,21 @@ func FormatRecipeDTOWithTranslation(rec *dto.RecipeDTO, translation *TranslatedR
 		sb.WriteString(fmt.Sprintf("🏷️ %s: %s\n", t.Tags, escapeMarkdown(strings.Join(tags, " "))))
 	}
 
+	if len(rec.Flavors) > 0 {
+		flavors := make([]string, len(rec.Flavors))
+		for i, flavor := range rec.Flavors {
+			flavors[i] = TranslateFlavor(flavor, lang)
+		}
+		sb.WriteString(fmt.Sprintf("👅 %s: %s\n", t.Flavor, escapeMarkdown(strings.Join(flavors, ", "))))
+	}
+
+	if !rec.CreatedAt.IsZero() {
+		sb.WriteString(fmt.Sprintf("🗓️ %s\n", escapeMarkdown(fmt.Sprintf(t.SavedAgo, dates.Ago(rec.CreatedAt)))))
+	}
+
 	sb.WriteString("\n")
 
-	// Ingredients
+	// Ingredients, with the ones swapped in by a remix highlighted
 	sb.WriteString(fmt.Sprintf("📝 *%s*\n", t.Ingredients))
 	for _, ing := range ingredients {
 		ingStr := ing.Name
@@ -148,25 +231,61 @@ func FormatRecipeDTOWithTranslation(rec *dto.RecipeDTO, translation *TranslatedR
 		if ing.Notes != "" {
 			ingStr += " (" + ing.Notes + ")"
 		}
+		if ing.Replaces != "" {
+			sb.WriteString(fmt.Sprintf("🔄 _%s_\n", escapeMarkdown(ingStr)))
+			continue
+		}
 		sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(ingStr)))
 	}
+	if rec.CheckQuantities {
+		sb.WriteString(formatQuantityWarning(t.CheckQuantities))
+	}
 	sb.WriteString("\n")
 
+	if len(rec.Substitutions) > 0 {
+		sb.WriteString(fmt.Sprintf("🔄 *%s*\n", t.Substitutions))
+		for _, sub := range rec.Substitutions {
+			line := sub.Original + " → " + sub.Replacement
+			if sub.Reason != "" {
+				line += ": " + sub.Reason
+			}
+			sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(line)))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Instructions
-	sb.WriteString(fmt.Sprintf("👨‍🍳 *%s*\n", t.Instructions))
+	heading := t.Instructions
+	if view != nil {
+		heading = view.heading
+		instructions = view.steps
+	}
+	sb.WriteString(fmt.Sprintf("👨‍🍳 *%s*\n", heading))
+	// Adapted steps no longer match the video, so only the original ones link to it
+	startSeconds := make(map[int]*int)
+	if view == nil {
+		for _, inst := range rec.Instructions {
+			startSeconds[inst.StepNumber] = inst.StartSeconds
+		}
+	}
 	for _, inst := range instructions {
-		sb.WriteString(fmt.Sprintf("%d\\. %s\n", inst.StepNumber, escapeMarkdown(inst.Text)))
+		link := formatStepLink(rec.SourcePlatform, rec.SourceURL, startSeconds[inst.StepNumber], t.WatchStep)
+		sb.WriteString(fmt.Sprintf("%d\\. %s%s\n", inst.StepNumber, escapeMarkdown(inst.Text), link))
+	}
+	if view != nil && view.notes != "" {
+		sb.WriteString(fmt.Sprintf("\n💡 %s\n", escapeMarkdown(view.notes)))
 	}
 	sb.WriteString("\n")
 
 	// Source
 	sb.WriteString(fmt.Sprintf("🔗 *%s*\n", t.Source))
-	sb.WriteString(fmt.Sprintf("[%s](%s)\n",
-		escapeMarkdown(rec.SourcePlatform),
-		rec.SourceURL))
+	sb.WriteString(formatSource(rec.SourcePlatform, rec.SourceURL, rec.SourceSegment) + "\n")
 
-	if rec.SourceAuthor != "" {
-		sb.WriteString(fmt.Sprintf("%s: %s\n", t.By, escapeMarkdown(rec.SourceAuthor)))
+	if creator := formatCreator(rec); creator != "" {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", t.By, escapeMarkdown(creator)))
+	}
+	if !rec.SourcePublished.IsZero() {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", t.Published, rec.SourcePublished.Format("2 Jan 2006")))
 	}
 
 	return sb.String()
@@ -225,23 +344,28 @@ func FormatRecipeDTO(rec *dto.RecipeDTO) string {
 		}
 		sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(ingStr)))
 	}
+	if rec.CheckQuantities {
+		sb.WriteString(formatQuantityWarning(GetTranslations(user.LanguageEnglish).CheckQuantities))
+	}
 	sb.WriteString("\n")
 
 	// Instructions
 	sb.WriteString("👨‍🍳 *Instructions*\n")
 	for _, inst := range rec.Instructions {
-		sb.WriteString(fmt.Sprintf("%d\\. %s\n", inst.StepNumber, escapeMarkdown(inst.Text)))
+		link := formatStepLink(rec.SourcePlatform, rec.SourceURL, inst.StartSeconds, "watch this step")
+		sb.WriteString(fmt.Sprintf("%d\\. %s%s\n", inst.StepNumber, escapeMarkdown(inst.Text), link))
 	}
 	sb.WriteString("\n")
 
 	// Source
 	sb.WriteString("🔗 *Source*\n")
-	sb.WriteString(fmt.Sprintf("[%s](%s)\n",
-		escapeMarkdown(rec.SourcePlatform),
-		rec.SourceURL))
+	sb.WriteString(formatSource(rec.SourcePlatform, rec.SourceURL, rec.SourceSegment) + "\n")
 
-	if rec.SourceAuthor != "" {
-		sb.WriteString(fmt.Sprintf("By: %s\n", escapeMarkdown(rec.SourceAuthor)))
+	if creator := formatCreator(rec); creator != "" {
+		sb.WriteString(fmt.Sprintf("By: %s\n", escapeMarkdown(creator)))
+	}
+	if !rec.SourcePublished.IsZero() {
+		sb.WriteString(fmt.Sprintf("Published: %s\n", rec.SourcePublished.Format("2 Jan 2006")))
 	}
 
 	return sb.String()
@@ -287,8 +411,8 @@ func FormatRecipeListDTO(recipes []*dto.RecipeDTO) string {
 			break
 		}
 
-		sb.WriteString(fmt.Sprintf("%d\\. %s\n", i+1, escapeMarkdown(rec.Title)))
-		sb.WriteString(fmt.Sprintf("   _%s_ \\| %s\n", escapeMarkdown(rec.Category), rec.SourcePlatform))
+		sb.WriteString(fmt.Sprintf("%d\\. %s\n", i+1, escapeMarkdown(rec.DisplayName)))
+		sb.WriteString(fmt.Sprintf("   _%s_ \\| %s \\| %s\n", escapeMarkdown(rec.Category), rec.SourcePlatform, difficultyBadge(rec.Difficulty)))
 	}
 
 	sb.WriteString("\nUse /recipe <number> to view details")
@@ -351,187 +475,1964 @@ func FormatCategories(counts map[string]int, total int) string {
 	return sb.String()
 }
 
-// escapeMarkdown escapes special characters for Telegram Markdown
-func escapeMarkdown(text string) string {
-	// Escape special Markdown characters
-	replacer := strings.NewReplacer(
-		"_", "\\_",
-		"*", "\\*",
-		"[", "\\[",
-		"]", "\\]",
-		"(", "\\(",
-		")", "\\)",
-		"~", "\\~",
-		"`", "\\`",
-		">", "\\>",
-		"#", "\\#",
-		"+", "\\+",
-		"-", "\\-",
-		"=", "\\=",
-		"|", "\\|",
-		"{", "\\{",
-		"}", "\\}",
-		".", "\\.",
-		"!", "\\!",
-	)
-	return replacer.Replace(text)
-}
+// FormatRecipeHistory formats the stored versions of a recipe, oldest first
+func FormatRecipeHistory(recipeNumber int, history *command.RecipeHistory, dates Dates) string {
+	var sb strings.Builder
 
-// FormatWelcome formats the welcome message
-func FormatWelcome() string {
-	return `👋 *Welcome to Recipe Bot!*
+	sb.WriteString(fmt.Sprintf("📜 *History of recipe #%d*\n", recipeNumber))
+	sb.WriteString(escapeMarkdown(history.Recipe.Title()))
+	sb.WriteString("\n\n")
 
-I can help you extract recipes from:
-• 🎵 TikTok videos
-• 📺 YouTube videos
-• 📸 Instagram posts/reels
-• 🌐 Recipe websites
+	if len(history.Versions) == 0 {
+		sb.WriteString("This recipe has not changed since it was saved.")
+		return sb.String()
+	}
 
-*How to use:*
-Just send me a link to any recipe video or webpage, and I'll extract the ingredients and cooking instructions for you!
+	for _, v := range history.Versions {
+		sb.WriteString(fmt.Sprintf("*v%d* · %s · %s\n",
+			v.Number, dates.DateTime(v.CreatedAt), escapeMarkdown(v.Reason)))
+		for _, change := range v.Changes {
+			sb.WriteString(fmt.Sprintf("  • %s\n", escapeMarkdown(truncate(change.String(), 80))))
+		}
+	}
 
-*Commands:*
-/start - Show this message
-/help - Get help
-/recipes - List your saved recipes
-/recipe <number> - View a specific recipe
+	current := history.Versions[len(history.Versions)-1].Number + 1
+	sb.WriteString(fmt.Sprintf("*v%d* · current\n\n", current))
+	sb.WriteString(fmt.Sprintf("Use /revert %d <version> to restore a version", recipeNumber))
 
-Let's get cooking! 👨‍🍳`
+	return sb.String()
 }
 
-// FormatHelp formats the help message
-func FormatHelp() string {
-	return `🤖 *Recipe Bot Help*
+// aisleLabels maps shopping list aisles to their headings
+var aisleLabels = map[shopping.Aisle]string{
+	shopping.AisleProduce:   "🥬 Produce",
+	shopping.AisleBakery:    "🍞 Bakery",
+	shopping.AisleMeat:      "🥩 Meat",
+	shopping.AisleSeafood:   "🐟 Seafood",
+	shopping.AisleDairy:     "🧀 Dairy",
+	shopping.AislePantry:    "🥫 Pantry",
+	shopping.AisleSpices:    "🧂 Spices",
+	shopping.AisleFrozen:    "🧊 Frozen",
+	shopping.AisleBeverages: "🥤 Beverages",
+	shopping.AisleOther:     "📦 Other",
+}
 
-*Supported Platforms:*
-• TikTok \(tiktok\.com\)
-• YouTube \(youtube\.com, youtu\.be\)
-• Instagram \(instagram\.com\)
-• Recipe websites \(with schema\.org markup\)
+// FormatMealPlan formats a week's meal plan
+func FormatMealPlan(plan *dto.MealPlanDTO) string {
+	var sb strings.Builder
 
-*How it works:*
-1\. Send me a recipe link
-2\. I'll download and transcribe the video
-3\. AI extracts ingredients \& instructions
-4\. You get a formatted recipe\!
+	sb.WriteString(fmt.Sprintf("📅 *Meal plan* · week of %s\n\n", plan.WeekStart.Format("Mon 02 Jan")))
 
-*Tips:*
-• Make sure the link contains a recipe
-• Videos with clear audio work best
-• Written recipes are also supported
+	if len(plan.Entries) == 0 {
+		sb.WriteString("Nothing planned yet.\n\n")
+		sb.WriteString("Use /plan add <number> <day> to plan a recipe\n")
+		sb.WriteString("Example: /plan add 1 monday")
+		return sb.String()
+	}
 
-*Commands:*
-/start \- Welcome message
-/help \- This help message
-/recipes \- Your saved recipes
-/recipes <category> \- Filter by category
-/recipe <number> \- View a specific recipe
-/categories \- Show recipe categories
-/match <ingredients> \- Find recipes by ingredients
-/pantry \- Manage your pantry items
+	var lastDay time.Weekday = -1
+	for _, e := range plan.Entries {
+		if e.Day != lastDay {
+			sb.WriteString(fmt.Sprintf("*%s*\n", e.Day))
+			lastDay = e.Day
+		}
 
-*Having issues?*
-Make sure:
-• The link is valid
-• The content contains a recipe
-• The video has clear audio \(if applicable\)
+		title := e.Title
+		if title == "" {
+			title = "(deleted recipe)"
+		}
+		line := "• " + escapeMarkdown(title)
+		if e.Servings > 0 {
+			line += fmt.Sprintf(" · %d servings", e.Servings)
+		}
+		sb.WriteString(line + "\n")
+	}
 
-Happy cooking\! 🍳`
+	sb.WriteString("\nUse /shopping to get the shopping list for this week")
+
+	return sb.String()
 }
 
-// FormatMatchResults formats ingredient match results for Telegram display
-func FormatMatchResults(result *dto.MatchIngredientsResultDTO) string {
+// FormatShoppingList formats a shopping list grouped by aisle
+func FormatShoppingList(list *shopping.List) string {
 	var sb strings.Builder
 
-	if result.TotalMatches == 0 {
-		return "📭 No matching recipes found\\.\n\nTry adding more ingredients or use /recipes to see all your recipes\\."
+	sb.WriteString(fmt.Sprintf("🛒 *Shopping list* · week of %s\n", list.WeekStart().Format("Mon 02 Jan")))
+
+	items := list.Items()
+	if len(items) == 0 {
+		sb.WriteString("\nEverything you need is already in your pantry!")
+		return sb.String()
 	}
 
-	sb.WriteString("🍳 *Here's what you can make:*\n\n")
+	sb.WriteString(fmt.Sprintf("%d of %d items left\n", list.Remaining(), len(items)))
 
-	// Perfect matches
-	if len(result.PerfectMatches) > 0 {
-		sb.WriteString(fmt.Sprintf("✅ *Perfect Matches* \\(%d recipes\\):\n", len(result.PerfectMatches)))
-		for i, match := range result.PerfectMatches {
-			if i >= 5 {
-				sb.WriteString(fmt.Sprintf("   \\.\\.\\. and %d more\n", len(result.PerfectMatches)-5))
-				break
-			}
-			sb.WriteString(fmt.Sprintf("%d\\. %s\n", i+1, escapeMarkdown(match.Recipe.Title)))
+	var lastAisle shopping.Aisle
+	for _, item := range items {
+		if item.Aisle != lastAisle {
+			sb.WriteString(fmt.Sprintf("\n*%s*\n", aisleLabels[item.Aisle]))
+			lastAisle = item.Aisle
 		}
-		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("%s %s\n", checkMark(item.Checked), escapeMarkdown(item.String())))
 	}
 
-	// High matches (missing 1-2 items)
-	if len(result.HighMatches) > 0 {
-		sb.WriteString(fmt.Sprintf("🔸 *Almost There* \\(%d recipes\\):\n", len(result.HighMatches)))
-		startIndex := len(result.PerfectMatches)
-		for i, match := range result.HighMatches {
-			if i >= 5 {
-				sb.WriteString(fmt.Sprintf("   \\.\\.\\. and %d more\n", len(result.HighMatches)-5))
-				break
-			}
-			missing := formatMissingItems(match.MissingItems, 3)
-			sb.WriteString(fmt.Sprintf("%d\\. %s\n", startIndex+i+1, escapeMarkdown(match.Recipe.Title)))
-			sb.WriteString(fmt.Sprintf("   _Missing: %s_\n", escapeMarkdown(missing)))
-		}
-		sb.WriteString("\n")
+	sb.WriteString("\nTap an item to check it off")
+
+	return sb.String()
+}
+
+// RecipeViewKeyboard builds the inline keyboard that switches a recipe between its
+// original and simplified instructions
+func RecipeViewKeyboard(recipeID string, simplified bool, lang user.Language) tgbotapi.InlineKeyboardMarkup {
+	t := GetTranslations(lang)
+	button := tgbotapi.NewInlineKeyboardButtonData(t.SimplifyButton, callbackSimplify+":"+recipeID)
+	if simplified {
+		button = tgbotapi.NewInlineKeyboardButtonData(t.OriginalButton, callbackOriginal+":"+recipeID)
 	}
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(button))
+}
 
-	// Medium matches
-	if len(result.MediumMatches) > 0 {
-		sb.WriteString(fmt.Sprintf("🔹 *Partial Matches* \\(%d recipes\\):\n", len(result.MediumMatches)))
-		startIndex := len(result.PerfectMatches) + len(result.HighMatches)
-		for i, match := range result.MediumMatches {
-			if i >= 3 {
-				sb.WriteString(fmt.Sprintf("   \\.\\.\\. and %d more\n", len(result.MediumMatches)-3))
-				break
-			}
-			missing := formatMissingItems(match.MissingItems, 3)
-			sb.WriteString(fmt.Sprintf("%d\\. %s \\(%.0f%% match\\)\n", startIndex+i+1, escapeMarkdown(match.Recipe.Title), match.MatchPercentage))
-			sb.WriteString(fmt.Sprintf("   _Missing: %s_\n", escapeMarkdown(missing)))
+// CoverKeyboardRow builds the button that generates a cover image for a recipe
+func CoverKeyboardRow(recipeID string, lang user.Language) []tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(GetTranslations(lang).CoverButton, callbackCover+":"+recipeID))
+}
+
+// FormatDietReport formats what checking a recipe against the user's diet found:
+// the ingredients that break it, those to check, or that the recipe fits
+func FormatDietReport(report diet.Report, lang user.Language) string {
+	t := GetTranslations(lang)
+	name := escapeMarkdown(string(report.Diet))
+	if report.Clear() {
+		return fmt.Sprintf(t.DietFits, name)
+	}
+
+	var sb strings.Builder
+	write := func(header string, findings []diet.Finding) {
+		if len(findings) == 0 {
+			return
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf(header, name) + "\n")
+		for _, f := range findings {
+			sb.WriteString(fmt.Sprintf("• %s (%s)\n", escapeMarkdown(f.Ingredient), escapeMarkdown(f.Reason)))
 		}
-		sb.WriteString("\n")
 	}
+	write(t.DietViolations, report.Violations)
+	write(t.DietUncertain, report.Uncertain)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
 
-	sb.WriteString("Use /recipe <number> to view full recipe\\!")
+// FormatRecipeChoice asks which of the recipes at the 1-based positions of the last
+// results the user meant by a name
+func FormatRecipeChoice(name string, recipes []*dto.RecipeDTO, positions []int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🤔 %d recipes match \"%s\":\n\n", len(positions), escapeMarkdown(name)))
+	for _, position := range positions {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", position, escapeMarkdown(recipes[position-1].DisplayName)))
+	}
+	sb.WriteString("\nWhich one did you mean?")
+	return sb.String()
+}
+
+// RecipeChoiceKeyboard builds the inline keyboard with a button for each recipe
+// at the 1-based positions of the last results
+func RecipeChoiceKeyboard(recipes []*dto.RecipeDTO, positions []int) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(positions))
+	for _, position := range positions {
+		rec := recipes[position-1]
+		label := fmt.Sprintf("%d. %s", position, rec.DisplayName)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, callbackRecipeDetails+":"+rec.ID)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// ClarificationKeyboard builds the inline keyboard offering the options of a clarifying question
+func ClarificationKeyboard(pending *PendingClarification) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(pending.Options))
+	for i, option := range pending.Options {
+		data := fmt.Sprintf("%s:%d:%d", callbackClarify, pending.ID, i)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(truncate(option, 60), data)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// ShoppingListKeyboard builds the inline keyboard used to check items off a shopping list
+func ShoppingListKeyboard(list *shopping.List) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(list.Items()))
+	for i, item := range list.Items() {
+		label := fmt.Sprintf("%s %s", checkMark(item.Checked), truncate(item.String(), 40))
+		data := fmt.Sprintf("%s:%d", callbackShoppingToggle, i)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, data)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// FormatPantryPhoto formats the intro of the checklist of items recognized in a pantry photo
+func FormatPantryPhoto(items []command.PantryPhotoItem) string {
+	return fmt.Sprintf("📸 I spotted %d items\\.\n\nUntick anything I got wrong, then add the rest to your pantry\\.", len(items))
+}
+
+// PantryPhotoKeyboard builds the checklist of items recognized in a pantry photo,
+// with a button that adds the checked ones
+func PantryPhotoKeyboard(items []command.PantryPhotoItem) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items)+1)
+	checked := 0
+	for i, item := range items {
+		if item.Checked {
+			checked++
+		}
+		label := fmt.Sprintf("%s %s", checkMark(item.Checked), truncate(item.Name, 40))
+		data := fmt.Sprintf("%s:%d", callbackPantryPhoto, i)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, data)))
+	}
+	add := tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("➕ Add %d items to pantry", checked), callbackPantryPhotoAdd)
+	return tgbotapi.NewInlineKeyboardMarkup(append(rows, tgbotapi.NewInlineKeyboardRow(add))...)
+}
 
+// FormatCompilation lists the recipes found in a compilation that are not saved yet,
+// asking which to save
+func FormatCompilation(recipes []*recipe.Recipe) string {
+	var sb strings.Builder
+	var lines []string
+	for i, rec := range recipes {
+		if rec != nil {
+			lines = append(lines, fmt.Sprintf("%d\\. %s", i+1, escapeMarkdown(rec.Title())))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("📚 I found %d recipes — which do you want to save?\n\n", len(lines)))
+	sb.WriteString(strings.Join(lines, "\n"))
 	return sb.String()
 }
 
-// formatMissingItems formats missing items list
-func formatMissingItems(items []string, max int) string {
-	if len(items) == 0 {
-		return "none"
+// CompilationKeyboard builds a save button for each recipe of a compilation not saved
+// yet, with a button that saves them all
+func CompilationKeyboard(recipes []*recipe.Recipe) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(recipes)+1)
+	for i, rec := range recipes {
+		if rec == nil {
+			continue
+		}
+		label := fmt.Sprintf("💾 %d. %s", i+1, truncate(rec.Title(), 40))
+		data := fmt.Sprintf("%s:%d", callbackCompilationSave, i)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, data)))
 	}
+	all := tgbotapi.NewInlineKeyboardButtonData("💾 Save all", callbackCompilationSave+":all")
+	return tgbotapi.NewInlineKeyboardMarkup(append(rows, tgbotapi.NewInlineKeyboardRow(all))...)
+}
 
-	if len(items) <= max {
-		return strings.Join(items, ", ")
+// FormatBookmarkPreview formats what a bookmark import will do before it starts
+func FormatBookmarkPreview(total int, links []string, interval time.Duration) string {
+	var sb strings.Builder
+	sb.WriteString("📚 *Bookmark import*\n\n")
+	sb.WriteString(fmt.Sprintf("Found %d recipe links in your %d bookmarks", len(links), total))
+	if len(links) == command.MaxBookmarkImport {
+		sb.WriteString(fmt.Sprintf(" (only the first %d are imported at a time)", command.MaxBookmarkImport))
+	}
+	sb.WriteString(":\n")
+	for i, link := range links {
+		if i >= 5 {
+			sb.WriteString(fmt.Sprintf("... and %d more\n", len(links)-5))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(truncate(link, 60))))
 	}
+	if minutes := int((time.Duration(len(links)-1) * interval).Minutes()); minutes > 0 {
+		sb.WriteString(fmt.Sprintf("\nLinks are processed one at a time, so this takes about %d minutes. I'll message you when it's done.", minutes))
+	}
+	return sb.String()
+}
 
-	shown := items[:max]
-	remaining := len(items) - max
-	return fmt.Sprintf("%s +%d more", strings.Join(shown, ", "), remaining)
+// BookmarkImportKeyboard builds the buttons that start or drop a bookmark import
+func BookmarkImportKeyboard(links int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("📥 Import %d", links), callbackBookmarkImport),
+		tgbotapi.NewInlineKeyboardButtonData("Cancel", callbackBookmarkCancel),
+	))
 }
 
-// FormatPantry formats pantry items for Telegram display
-func FormatPantry(items []string) string {
-	if len(items) == 0 {
-		return "📭 Your pantry is empty\\.\n\nUse /pantry add <items> to add ingredients\\.\nExample: /pantry add butter, eggs, milk"
+// BookmarkStopKeyboard builds the button that stops a running bookmark import
+func BookmarkStopKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⏹ Stop import", callbackBookmarkCancel),
+	))
+}
+
+// FormatBookmarkImportResult formats the outcome of a bookmark import
+func FormatBookmarkImportResult(result *command.ImportResult) string {
+	var sb strings.Builder
+	if result.Canceled {
+		sb.WriteString("⏹ *Bookmark import stopped*\n\n")
+	} else {
+		sb.WriteString("✅ *Bookmark import finished*\n\n")
+	}
+
+	noun := "recipes"
+	if len(result.Saved) == 1 {
+		noun = "recipe"
+	}
+	sb.WriteString(fmt.Sprintf("Saved %d %s.\n", len(result.Saved), noun))
+	if result.Skipped > 0 {
+		sb.WriteString(fmt.Sprintf("Skipped %d videos with several recipes, send them one by one to pick the recipes.\n", result.Skipped))
+	}
+	if len(result.Failed) > 0 {
+		sb.WriteString(fmt.Sprintf("Could not read %d links:\n", len(result.Failed)))
+		for i, link := range result.Failed {
+			if i >= 5 {
+				sb.WriteString(fmt.Sprintf("... and %d more\n", len(result.Failed)-5))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(truncate(link, 60))))
+		}
 	}
+	sb.WriteString("\nUse /recipes to see your collection.")
+	return sb.String()
+}
+
+// maxNotionFailuresShown caps the failed recipes listed after a bulk Notion export
+const maxNotionFailuresShown = 20
 
+// FormatNotionExportReport formats the outcome of a bulk Notion export, with the
+// reason each failed recipe was not exported
+func FormatNotionExportReport(report *command.NotionExportReport) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("🥫 *Your Pantry* \\(%d items\\)\n\n", len(items)))
+	if report.Stopped {
+		sb.WriteString("⏸ *Notion export stopped*\n\n")
+	} else {
+		sb.WriteString("✅ *Notion export finished*\n\n")
+	}
 
-	for _, item := range items {
-		sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(item)))
+	sb.WriteString(fmt.Sprintf("Exported %d of %d recipes", report.Exported+report.Resumed, report.Total))
+	if report.Resumed > 0 {
+		sb.WriteString(fmt.Sprintf(" (%d by the earlier run)", report.Resumed))
 	}
+	sb.WriteString(".\n")
 
-	sb.WriteString("\n*Commands:*\n")
-	sb.WriteString("/pantry add <items> \\- Add items\n")
-	sb.WriteString("/pantry remove <items> \\- Remove items\n")
-	sb.WriteString("/pantry clear \\- Clear all items\n")
-	sb.WriteString("/match \\- Find recipes with pantry items")
+	if len(report.Failed) > 0 {
+		sb.WriteString(fmt.Sprintf("\n%d failed:\n", len(report.Failed)))
+		for i, failure := range report.Failed {
+			if i >= maxNotionFailuresShown {
+				sb.WriteString(fmt.Sprintf("... and %d more\n", len(report.Failed)-maxNotionFailuresShown))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("• %s: %s\n", escapeMarkdown(truncate(failure.Title, 40)), escapeMarkdown(truncate(failure.Reason, 80))))
+		}
+	}
 
+	if report.URL != "" {
+		sb.WriteString(fmt.Sprintf("\n[View in Notion](%s)\n", report.URL))
+	}
+	if report.Stopped || len(report.Failed) > 0 {
+		sb.WriteString("\nRun /export notion again to continue, recipes already exported are skipped.")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// FormatFeedPost formats the recipe of a new post of a followed feed, proposed for saving
+func FormatFeedPost(post *command.FeedPost) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🆕 *New from %s* — save it?\n\n", escapeMarkdown(post.FeedTitle)))
+	sb.WriteString(fmt.Sprintf("🍳 *%s*\n", escapeMarkdown(post.Recipe.Title())))
+
+	var details []string
+	if n := len(post.Recipe.Ingredients()); n > 0 {
+		details = append(details, fmt.Sprintf("%d ingredients", n))
+	}
+	var total time.Duration
+	if prep := post.Recipe.PrepTime(); prep != nil {
+		total += *prep
+	}
+	if cook := post.Recipe.CookTime(); cook != nil {
+		total += *cook
+	}
+	if total > 0 {
+		details = append(details, formatMinutes(total))
+	}
+	if servings := post.Recipe.Servings(); servings != nil {
+		details = append(details, fmt.Sprintf("serves %d", *servings))
+	}
+	if len(details) > 0 {
+		sb.WriteString(escapeMarkdown(strings.Join(details, " · ")) + "\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\n🔗 [Read the post](%s)", post.Item.Link))
+	return sb.String()
+}
+
+// FeedPostKeyboard builds the button that saves the recipe of a feed post
+func FeedPostKeyboard(postID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("💾 Save recipe", callbackFeedSave+":"+postID),
+	))
+}
+
+// FormatSubscriptions formats the numbered list of feeds a user follows
+func FormatSubscriptions(subscriptions []*feed.Subscription) string {
+	if len(subscriptions) == 0 {
+		return "📰 You don't follow any recipe blog yet.\n\n" +
+			"Send /subscribe <feed link> to get the recipes of new posts."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📰 *Followed recipe blogs*\n\n")
+	for i, s := range subscriptions {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, escapeMarkdown(s.Title)))
+	}
+	sb.WriteString("\nSend /unsubscribe <number> to stop following one.")
+	return sb.String()
+}
+
+// FormatShareScreening tells the owner of a new guest share which recipes guests won't see,
+// or returns "" when guests see them all
+func FormatShareScreening(result *command.ScreenResult) string {
+	review := 0
+	for _, d := range result.Held {
+		if d.Verdict == moderation.VerdictReview {
+			review++
+		}
+	}
+	if len(result.Held) == 0 && result.Unchecked == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🛡️ *Not shown to guests*\n\n")
+	for _, d := range result.Held {
+		if d.Verdict == moderation.VerdictBlocked {
+			sb.WriteString(fmt.Sprintf("🚫 %s — did not pass the content check\n", escapeMarkdown(truncate(d.Title, 60))))
+		}
+	}
+	if review > 0 {
+		sb.WriteString(fmt.Sprintf("⏳ Waiting for a review: %d, shown once approved\n", review))
+	}
+	if result.Unchecked > 0 {
+		sb.WriteString(fmt.Sprintf("⏳ Not checked yet: %d, shown once checked\n", result.Unchecked))
+	}
+	return sb.String()
+}
+
+// FormatModerationReview formats a shared recipe waiting for the admin's review
+func FormatModerationReview(d *moderation.Decision, waiting int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🛡️ *Review shared recipe* (%d waiting)\n\n", waiting))
+	sb.WriteString(fmt.Sprintf("*Title:* %s\n", escapeMarkdown(d.Title)))
+	if d.SourceURL != "" {
+		sb.WriteString(fmt.Sprintf("*Source:* `%s`\n", strings.ReplaceAll(d.SourceURL, "`", "")))
+	}
+	if d.Reason != "" {
+		sb.WriteString(fmt.Sprintf("*Reason:* %s\n", escapeMarkdown(d.Reason)))
+	}
+	sb.WriteString(fmt.Sprintf("*Checked at:* %s\n", d.CheckedAt.UTC().Format("02 Jan 15:04 MST")))
+	return sb.String()
+}
+
+// ModerationReviewKeyboard builds the buttons the admin approves or rejects a shared recipe with
+func ModerationReviewKeyboard(recipeID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ Approve", callbackModeration+":1:"+recipeID),
+		tgbotapi.NewInlineKeyboardButtonData("🚫 Reject", callbackModeration+":0:"+recipeID),
+	))
+}
+
+// IntentFeedbackKeyboard builds the thumbs up and down buttons users rate a detected intent with
+func IntentFeedbackKeyboard(intentType ports.IntentType) tgbotapi.InlineKeyboardMarkup {
+	data := callbackIntentFeedback + ":" + string(intentType)
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("👍", data+":1"),
+		tgbotapi.NewInlineKeyboardButtonData("👎", data+":0"),
+	))
+}
+
+// FormatErrorReport formats an error report for the admin chat
+func FormatErrorReport(rep *report.ErrorReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🐞 *Error report %s*\n\n", escapeMarkdown(rep.ID)))
+	sb.WriteString(fmt.Sprintf("*Link:* %s\n", escapeMarkdown(rep.Failure.URL)))
+	sb.WriteString(fmt.Sprintf("*Platform:* %s\n", escapeMarkdown(rep.Failure.Platform)))
+	sb.WriteString(fmt.Sprintf("*Stage:* %s\n", escapeMarkdown(string(rep.Failure.Stage))))
+	sb.WriteString(fmt.Sprintf("*Failed at:* %s\n", rep.Failure.OccurredAt.UTC().Format("02 Jan 15:04 MST")))
+	sb.WriteString(fmt.Sprintf("*Error:* %s\n", escapeMarkdown(rep.Failure.Error)))
+	if rep.Note != "" {
+		sb.WriteString(fmt.Sprintf("*User note:* %s\n", escapeMarkdown(rep.Note)))
+	}
+	if rep.Failure.LLMResponse != "" {
+		sb.WriteString(fmt.Sprintf("\n*LLM response:*\n%s", escapeMarkdown(rep.Failure.LLMResponse)))
+	}
+	return sb.String()
+}
+
+// FormatProcessingReport formats how long each stage of processing a recipe took and
+// what its LLM calls cost, for the admin
+func FormatProcessingReport(rep *recipe.ProcessingReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔬 *Processing of recipe %s*\n\n", escapeMarkdown(rep.RecipeID.String())))
+	sb.WriteString(fmt.Sprintf("*Platform:* %s\n", escapeMarkdown(string(rep.Platform))))
+	sb.WriteString(fmt.Sprintf("*Processed at:* %s\n\n", rep.ProcessedAt.UTC().Format("02 Jan 15:04 MST")))
+
+	switch {
+	case rep.Scrape == 0:
+		sb.WriteString("*Scrape:* skipped, the page was given\n")
+	case rep.Transcription > 0:
+		sb.WriteString(fmt.Sprintf("*Scrape:* %s (transcription %s)\n", formatStageDuration(rep.Scrape), formatStageDuration(rep.Transcription)))
+	default:
+		sb.WriteString(fmt.Sprintf("*Scrape:* %s\n", formatStageDuration(rep.Scrape)))
+	}
+	sb.WriteString(fmt.Sprintf("*On-screen text:* %s\n", formatStageDuration(rep.OnScreenText)))
+	sb.WriteString(fmt.Sprintf("*LLM:* %s\n", formatStageDuration(rep.LLM)))
+	sb.WriteString(fmt.Sprintf("*Total:* %s\n\n", formatStageDuration(rep.Total())))
+
+	model := rep.Model
+	if model == "" {
+		model = "unknown model"
+	}
+	sb.WriteString(fmt.Sprintf("*LLM calls:* %d to %s\n", rep.LLMCalls, escapeMarkdown(model)))
+	sb.WriteString(fmt.Sprintf("*Tokens:* %d in, %d out", rep.InputTokens, rep.OutputTokens))
+	return sb.String()
+}
+
+// formatStageDuration rounds the duration of a processing stage for display
+func formatStageDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// maxActivityShown caps the entries listed by /activity
+const maxActivityShown = 20
+
+// FormatActivity formats a user's audit trail, newest first
+func FormatActivity(entries []*activity.Entry, dates Dates) string {
+	days := int(activity.Retention.Hours() / 24)
+	if len(entries) == 0 {
+		return fmt.Sprintf("🗂️ *Your activity*\n\nNothing recorded in the last %d days.", days)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🗂️ *Your activity*\n\n")
+	for i, e := range entries {
+		if i >= maxActivityShown {
+			sb.WriteString(fmt.Sprintf("... and %d more\n", len(entries)-maxActivityShown))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("• %s — *%s* %s\n", dates.DayTime(e.CreatedAt), e.Action.Label(), escapeMarkdown(e.Detail)))
+	}
+	sb.WriteString(fmt.Sprintf("\nActivity is kept for %d days.", days))
+	return sb.String()
+}
+
+// FormatTranscript writes a conversation as plain text for a file, oldest first
+func FormatTranscript(turns []*conversation.Turn, dates Dates) string {
+	var sb strings.Builder
+	sb.WriteString("Conversation with Receipt Bot\n")
+	sb.WriteString(fmt.Sprintf("Exported %s\n", dates.DateTime(dates.Now)))
+	for _, turn := range turns {
+		who := "You"
+		if turn.Role == conversation.RoleAssistant {
+			who = "Bot"
+		}
+		sb.WriteString(fmt.Sprintf("\n[%s] %s:\n", dates.DateTime(turn.CreatedAt), who))
+		// What was said is indented under the speaker
+		for _, line := range strings.Split(strings.TrimSpace(turn.Text), "\n") {
+			if line != "" {
+				sb.WriteString("    " + line)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// courseNames and courseEmoji label the courses of a menu
+var (
+	courseNames = map[menu.Course]string{
+		menu.CourseAppetizer: "Appetizer",
+		menu.CourseMain:      "Main course",
+		menu.CourseSide:      "Side",
+		menu.CourseDessert:   "Dessert",
+	}
+	courseEmoji = map[menu.Course]string{
+		menu.CourseAppetizer: "🥗",
+		menu.CourseMain:      "🍖",
+		menu.CourseSide:      "🥔",
+		menu.CourseDessert:   "🍰",
+	}
+)
+
+// FormatMenu formats a menu in progress with the dish selected for each course
+func FormatMenu(m *menu.Menu) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🍽️ *Menu · %s*\n", escapeMarkdown(m.Occasion())))
+	sb.WriteString(fmt.Sprintf("%d guests · served from %s\n", m.Guests(), m.ServeAt().Format("15:04")))
+
+	selected := make(map[menu.Course]menu.Dish)
+	for _, dish := range m.Selected() {
+		selected[dish.Course] = dish
+	}
+
+	for _, course := range menu.AllCourses() {
+		sb.WriteString(fmt.Sprintf("\n%s *%s*\n", courseEmoji[course], courseNames[course]))
+
+		dish, ok := selected[course]
+		if !ok {
+			sb.WriteString("Nothing in your recipes for this course\n")
+			continue
+		}
+
+		line := "• " + escapeMarkdown(dish.Title)
+		if total := dish.PrepTime + dish.CookTime; total > 0 {
+			line += " · ⏱️ " + formatMinutes(total)
+		}
+		if dish.Uses(menu.ApplianceOven) {
+			line += " · oven"
+		}
+		if scale := m.Scale(dish); scale != 1 {
+			line += fmt.Sprintf(" · ×%s", formatScale(scale))
+		}
+		if dish.Suggested {
+			line += " · ✨ suggestion"
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString("\nTap 🔄 to try another dish, or get the cooking timeline")
+
+	return sb.String()
+}
+
+// MenuKeyboard builds the inline keyboard used to swap dishes and get the cooking timeline
+func MenuKeyboard(m *menu.Menu) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, course := range menu.AllCourses() {
+		if len(m.Options(course)) < 2 {
+			continue
+		}
+		label := "🔄 Another " + strings.ToLower(courseNames[course])
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, callbackMenuSwap+":"+course.String())))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("🕒 Cooking timeline", callbackMenuTimeline)))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// FormatMenuTimeline formats the combined cooking timeline of a menu
+func FormatMenuTimeline(timeline *menu.Timeline) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🕒 *Cooking timeline* · start at %s\n\n", timeline.Start.Format("15:04")))
+
+	for _, step := range timeline.Steps {
+		var action string
+		switch step.Kind {
+		case menu.StepPrep:
+			action = "🔪 Start preparing " + escapeMarkdown(step.Dish)
+		case menu.StepCook:
+			switch step.Appliance {
+			case menu.ApplianceOven:
+				action = "🔥 " + escapeMarkdown(step.Dish) + " into the oven"
+			case menu.ApplianceStove:
+				action = "🍳 " + escapeMarkdown(step.Dish) + " on the stove"
+			default:
+				action = "👩‍🍳 Start cooking " + escapeMarkdown(step.Dish)
+			}
+		case menu.StepServe:
+			action = "🍽️ Serve the " + strings.ToLower(courseNames[step.Course])
+		}
+		sb.WriteString(fmt.Sprintf("*%s* %s\n", step.At.Format("15:04"), action))
+	}
+
+	if len(timeline.CookAhead) > 0 || len(timeline.Conflicts) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, dish := range timeline.CookAhead {
+		sb.WriteString(fmt.Sprintf("⚠️ %s is cooked early to free the oven, keep it warm\n", escapeMarkdown(dish)))
+	}
+	for _, conflict := range timeline.Conflicts {
+		sb.WriteString(fmt.Sprintf("⚠️ %d dishes need the %s at %s: %s\n",
+			len(conflict.Dishes), conflict.Appliance, conflict.At.Format("15:04"), escapeMarkdown(strings.Join(conflict.Dishes, ", "))))
+	}
+
+	return sb.String()
+}
+
+// FormatCookingSchedule formats a cooking schedule as a timestamped checklist
+func FormatCookingSchedule(schedule *cooking.Schedule) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("⏲️ *Cooking timeline* · ready at %s\n", schedule.ServeAt().Format("15:04")))
+	sb.WriteString(fmt.Sprintf("Start at %s\n\n", schedule.Start().Format("15:04")))
+
+	for _, step := range schedule.Steps() {
+		if step.Serve {
+			sb.WriteString(fmt.Sprintf("🍽️ *%s* Serve!\n", step.At.Format("15:04")))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("☐ *%s* %s · %d. %s (%s)\n",
+			step.At.Format("15:04"), escapeMarkdown(step.Recipe), step.Number, escapeMarkdown(step.Text), formatMinutes(step.Duration)))
+	}
+
+	return sb.String()
+}
+
+// FormatCookingReminder formats the reminder sent when a step of a cooking schedule starts
+func FormatCookingReminder(step cooking.Step) string {
+	if step.Serve {
+		return "🍽️ Everything should be ready. Time to serve!"
+	}
+	return fmt.Sprintf("⏰ *%s* · step %d\n%s\n\n_Takes about %s_",
+		escapeMarkdown(step.Recipe), step.Number, escapeMarkdown(step.Text), formatMinutes(step.Duration))
+}
+
+// CookingScheduleKeyboard builds the inline keyboard that turns step reminders on or off
+func CookingScheduleKeyboard(remindersOn bool) tgbotapi.InlineKeyboardMarkup {
+	button := tgbotapi.NewInlineKeyboardButtonData("⏰ Remind me at each step", callbackReminders)
+	if remindersOn {
+		button = tgbotapi.NewInlineKeyboardButtonData("🔕 Cancel reminders", callbackNoReminders)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(button))
+}
+
+// maxCookSessionButtons caps the open steps with buttons on a cook-along board
+const maxCookSessionButtons = 10
+
+// FormatCookSession formats a cook-along as a board of steps showing who took each
+func FormatCookSession(session *cooking.Session) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("👩‍🍳 *Cooking together:* %s\n", escapeMarkdown(session.Recipe)))
+	sb.WriteString(fmt.Sprintf("_Started by %s_\n\n", escapeMarkdown(session.StartedBy.Name)))
+
+	for _, step := range session.Steps() {
+		switch {
+		case step.Done:
+			sb.WriteString(fmt.Sprintf("✅ %d\\. %s · _done by %s_\n", step.Number, escapeMarkdown(step.Text), escapeMarkdown(step.ClaimedBy.Name)))
+		case step.ClaimedBy != nil:
+			sb.WriteString(fmt.Sprintf("🙋 %d\\. %s · *%s*\n", step.Number, escapeMarkdown(step.Text), escapeMarkdown(step.ClaimedBy.Name)))
+		default:
+			sb.WriteString(fmt.Sprintf("☐ %d\\. %s\n", step.Number, escapeMarkdown(step.Text)))
+		}
+	}
+
+	done, total := session.Progress()
+	sb.WriteString(fmt.Sprintf("\n_%d of %d steps done_", done, total))
+	if !session.Finished() {
+		sb.WriteString("\n\nTake a step with /claim <number or words>, finish it with /done")
+	}
+	return sb.String()
+}
+
+// CookSessionKeyboard builds the inline keyboard that takes or finishes the open
+// steps of a cook-along
+func CookSessionKeyboard(session *cooking.Session) tgbotapi.InlineKeyboardMarkup {
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, step := range session.Steps() {
+		if step.Done {
+			continue
+		}
+		if len(rows) == maxCookSessionButtons {
+			break
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🙋 Take %d", step.Number), fmt.Sprintf("%s:claim:%d", callbackCook, step.Number)),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("✅ Done %d", step.Number), fmt.Sprintf("%s:done:%d", callbackCook, step.Number)),
+		))
+	}
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// formatCreator returns the author of a recipe's source with their handle, like
+// "Ana Silva (@chefana)", or "" when neither is known
+func formatCreator(rec *dto.RecipeDTO) string {
+	switch {
+	case rec.SourceHandle == "":
+		return rec.SourceAuthor
+	case rec.SourceAuthor == "" || rec.SourceAuthor == recipe.UnknownAuthor:
+		return rec.SourceHandle
+	case recipe.NormalizeAuthor(rec.SourceAuthor) == recipe.NormalizeAuthor(rec.SourceHandle):
+		return rec.SourceAuthor
+	default:
+		return rec.SourceAuthor + " (" + rec.SourceHandle + ")"
+	}
+}
+
+// formatSource links the source platform to the source URL.
+// Recipes without a URL, like AI-generated ones, show the platform only, and
+// recipes from a compilation show their position in it.
+func formatSource(platform, rawURL string, segment int) string {
+	source := escapeMarkdown(platform)
+	if rawURL != "" {
+		source = fmt.Sprintf("[%s](%s)", source, rawURL)
+	}
+	if segment > 0 {
+		source += fmt.Sprintf(" · \\#%d", segment)
+	}
+	return source
+}
+
+// formatQuantityWarning formats the subtle note shown under the ingredients of a
+// recipe the extraction is unsure of
+func formatQuantityWarning(warning string) string {
+	return fmt.Sprintf("_⚠️ %s_\n", escapeMarkdown(warning))
+}
+
+// formatStepLink links a step to where it starts in the source video, or returns ""
+// when the step has no timestamp or the platform can't start videos at a given point
+func formatStepLink(platform, rawURL string, startSeconds *int, label string) string {
+	if startSeconds == nil {
+		return ""
+	}
+	link := recipe.VideoTimestampURL(rawURL, recipe.Platform(platform), time.Duration(*startSeconds)*time.Second)
+	if link == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [▶️ %s](%s)", escapeMarkdown(label), link)
+}
+
+// FormatDishProposal formats a recipe the LLM proposed from a photo of a dish,
+// labeled so it is not mistaken for a published recipe
+func FormatDishProposal(rec *recipe.Recipe) string {
+	return "🤖 *AI\\-generated recipe*\n" +
+		"_Proposed from your photo, not taken from a published recipe\\. Quantities and steps are a best guess\\._\n\n" +
+		FormatRecipe(rec)
+}
+
+// DishProposalKeyboard builds the inline keyboard that saves or discards a proposed recipe
+func DishProposalKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("💾 Save recipe", callbackDishSave),
+		tgbotapi.NewInlineKeyboardButtonData("🗑️ Discard", callbackDishDiscard),
+	))
+}
+
+// claimIcons marks each kind of implausible claim
+var claimIcons = map[claims.Kind]string{
+	claims.KindTime:     "⏱️",
+	claims.KindServings: "🍽️",
+	claims.KindAmount:   "⚖️",
+}
+
+// FormatClaimCheck formats the implausible claims of an extracted recipe, asking the
+// user to check them before it is saved
+func FormatClaimCheck(rec *recipe.Recipe, issues []claims.Issue) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🧐 *%s* has some details that look off:\n\n", escapeMarkdown(rec.Title())))
+	for _, issue := range issues {
+		sb.WriteString(fmt.Sprintf("%s %s\n", claimIcons[issue.Kind], escapeMarkdown(issue.Detail)))
+	}
+	sb.WriteString("\nSave it as it is, let me fix these, or discard it\\.")
+	return sb.String()
+}
+
+// ClaimCheckKeyboard builds the inline keyboard that saves a recipe with implausible
+// claims as it is or fixed, or discards it
+func ClaimCheckKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💾 Save as is", callbackClaims+":keep"),
+			tgbotapi.NewInlineKeyboardButtonData("🔧 Save with fixes", callbackClaims+":fix"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🗑️ Discard", callbackClaims+":discard"),
+		),
+	)
+}
+
+// maxGuestButtons caps the recipe buttons of a guest share
+const maxGuestButtons = 10
+
+// FormatGuestShare formats the read-only recipe list of a guest share and
+// a keyboard to open its first recipes
+func FormatGuestShare(s *share.GuestShare, owner string, recipes []*dto.RecipeDTO) (string, tgbotapi.InlineKeyboardMarkup) {
+	var sb strings.Builder
+
+	title := "Recipes"
+	if s.Category != nil {
+		title = string(*s.Category) + " recipes"
+	}
+	sb.WriteString(fmt.Sprintf("👀 *%s shared by %s*\n", escapeMarkdown(title), escapeMarkdown(owner)))
+	sb.WriteString(fmt.Sprintf("_Read-only guest view until %s_\n\n", s.ExpiresAt.Format("02 Jan 15:04")))
+
+	if len(recipes) == 0 {
+		sb.WriteString("📭 Nothing has been shared here yet.")
+		return sb.String(), tgbotapi.InlineKeyboardMarkup{}
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, rec := range recipes {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, escapeMarkdown(rec.Title)))
+		if i < maxGuestButtons {
+			label := fmt.Sprintf("%d. %s", i+1, truncate(rec.Title, 40))
+			data := fmt.Sprintf("%s:%s:%d", callbackGuestRecipe, s.Token, i+1)
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, data)))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\nSend /guest %s <number> to open a recipe", s.Token))
+	return sb.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// maxAuthorsShown caps the /authors list
+const maxAuthorsShown = 15
+
+// FormatAuthors formats the creators a user saves recipes from, most saved first
+func FormatAuthors(authors []dto.AuthorCountDTO) string {
+	if len(authors) == 0 {
+		return "👤 None of your saved recipes has a known author yet.\n\nRecipes from TikTok, YouTube and Instagram usually keep their creator."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("👤 *Your Top Creators*\n\n")
+
+	for i, a := range authors {
+		if i >= maxAuthorsShown {
+			sb.WriteString(fmt.Sprintf("... and %d more\n", len(authors)-maxAuthorsShown))
+			break
+		}
+		recipes := "recipes"
+		if a.Count == 1 {
+			recipes = "recipe"
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s · %d %s\n", i+1, escapeMarkdown(a.Author), a.Count, recipes))
+	}
+
+	sb.WriteString("\nUse /authors <name> to see everything from a creator")
+	return sb.String()
+}
+
+// FormatPlatforms formats how many recipes a user saved from each platform, most saved first
+func FormatPlatforms(platforms []dto.PlatformCountDTO) string {
+	if len(platforms) == 0 {
+		return "📭 You don't have any saved recipes yet."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📱 *Where Your Recipes Come From*\n\n")
+
+	for _, p := range platforms {
+		recipes := "recipes"
+		if p.Count == 1 {
+			recipes = "recipe"
+		}
+		sb.WriteString(fmt.Sprintf("• %s · %d %s\n", platformName(recipe.Platform(p.Platform)), p.Count, recipes))
+	}
+
+	sb.WriteString("\nUse /platforms <name> to see the recipes from one, e.g. /platforms tiktok")
+	return sb.String()
+}
+
+// FormatFreezer formats the contents of a freezer, oldest first, with age warnings
+func FormatFreezer(f *freezer.Freezer, dates Dates) string {
+	var sb strings.Builder
+
+	sb.WriteString("🧊 *Freezer*\n\n")
+
+	if f.IsEmpty() {
+		sb.WriteString("Your freezer is empty.\n\n")
+		sb.WriteString("Use /freezer add <number> <portions> when you freeze a recipe\n")
+		sb.WriteString("Example: /freezer add 7 3")
+		return sb.String()
+	}
+
+	for _, b := range f.Batches() {
+		sb.WriteString(formatBatch(b, dates) + "\n")
+	}
+
+	if len(f.EatFirst(dates.Now)) > 0 {
+		sb.WriteString("\n⚠️ Some portions have been frozen for a while. Use /freezer eat <number> when you eat them")
+	}
+
+	return sb.String()
+}
+
+// FormatFreezerEatFirst formats the frozen batches to eat soon
+func FormatFreezerEatFirst(batches []freezer.Batch, dates Dates) string {
+	if len(batches) == 0 {
+		return "🧊 Nothing in your freezer is getting old. Use /freezer to see what's in it."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🧊 *Eat these first*\n\n")
+	for _, b := range batches {
+		sb.WriteString(formatBatch(b, dates) + "\n")
+	}
+	return sb.String()
+}
+
+// formatBatch formats a frozen batch with its age
+func formatBatch(b freezer.Batch, dates Dates) string {
+	now := dates.Now
+	portions := "portions"
+	if b.Portions == 1 {
+		portions = "portion"
+	}
+
+	line := fmt.Sprintf("• *%s* · %d %s · frozen %s (%s)",
+		escapeMarkdown(b.Title), b.Portions, portions, dates.Day(b.FrozenAt), formatDays(b.Days(now)))
+
+	switch b.Age(now) {
+	case freezer.AgeEatSoon:
+		line += " · ⚠️ eat soon"
+	case freezer.AgeTooOld:
+		line += " · ❗ past its best"
+	}
+	return line
+}
+
+// formatDays formats a number of days as an age
+func formatDays(days int) string {
+	switch {
+	case days < 1:
+		return "today"
+	case days == 1:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", days)
+	}
+}
+
+// formatMinutes formats a duration as minutes, or hours and minutes
+func formatMinutes(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%02d", minutes/60, minutes%60)
+}
+
+// formatScale formats a recipe scaling factor with at most one decimal
+func formatScale(scale float64) string {
+	return strconv.FormatFloat(math.Round(scale*10)/10, 'f', -1, 64)
+}
+
+// FormatPantryNutrition formats the nutrition of the pantry items identified as products
+func FormatPantryNutrition(products []command.PantryProductNutrition) string {
+	var sb strings.Builder
+
+	sb.WriteString("🥗 *Pantry nutrition* · per 100 g\n\n")
+
+	if len(products) == 0 {
+		sb.WriteString("No pantry items are linked to a product yet\\.\n\n")
+		sb.WriteString("Send a photo of a product barcode to add it with its nutrition data")
+		return sb.String()
+	}
+
+	for _, p := range products {
+		sb.WriteString(fmt.Sprintf("*%s*\n", escapeMarkdown(p.Item)))
+		if p.Product.Facts == nil {
+			sb.WriteString("  no nutrition data\n")
+			continue
+		}
+		sb.WriteString("  " + escapeMarkdown(formatFacts(p.Product.Facts.Round())) + "\n")
+	}
+
+	sb.WriteString("\nUse /nutrition <number> to estimate a recipe")
+
+	return sb.String()
+}
+
+// FormatNutritionEstimate formats a recipe nutrition estimate grounded in product data
+func FormatNutritionEstimate(recipeNumber int, estimate *command.NutritionEstimate) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🥗 *Nutrition of recipe #%d*\n", recipeNumber))
+	sb.WriteString(escapeMarkdown(estimate.Recipe.Title()))
+	sb.WriteString("\n\n")
+
+	if len(estimate.Grounded) == 0 {
+		sb.WriteString("None of the ingredients match a scanned pantry product with nutrition data\\.\n\n")
+		sb.WriteString("Send a photo of a product barcode to add it to your pantry")
+		return sb.String()
+	}
+
+	if estimate.PerServing != nil {
+		sb.WriteString(fmt.Sprintf("*Per serving:* %s\n", escapeMarkdown(formatFacts(estimate.PerServing.Round()))))
+	}
+	sb.WriteString(fmt.Sprintf("*Total:* %s\n\n", escapeMarkdown(formatFacts(estimate.Total.Round()))))
+
+	sb.WriteString("*From your products:*\n")
+	for _, g := range estimate.Grounded {
+		sb.WriteString(fmt.Sprintf("• %s · %g g of %s · %.0f kcal\n",
+			escapeMarkdown(g.Ingredient), g.Grams, escapeMarkdown(g.Product), g.Facts.EnergyKcal))
+	}
+
+	if len(estimate.Unknown) > 0 {
+		sb.WriteString(fmt.Sprintf("\n⚠️ Not counted \\(no product data\\): %s",
+			escapeMarkdown(formatMissingItems(estimate.Unknown, 5))))
+	}
+
+	return sb.String()
+}
+
+// formatFacts returns a one-line summary of nutrition facts
+func formatFacts(f nutrition.Facts) string {
+	return fmt.Sprintf("%g kcal · %g g protein · %g g carbs · %g g fat", f.EnergyKcal, f.Protein, f.Carbohydrates, f.Fat)
+}
+
+// formatTrackedMacros returns the facts of the macros the goals track, e.g. "2000 kcal · 150 g protein"
+func formatTrackedMacros(goals, f nutrition.Facts) string {
+	f = f.Round()
+	var parts []string
+	if goals.EnergyKcal > 0 {
+		parts = append(parts, fmt.Sprintf("%g kcal", f.EnergyKcal))
+	}
+	if goals.Protein > 0 {
+		parts = append(parts, fmt.Sprintf("%g g protein", f.Protein))
+	}
+	if goals.Carbohydrates > 0 {
+		parts = append(parts, fmt.Sprintf("%g g carbs", f.Carbohydrates))
+	}
+	if goals.Fat > 0 {
+		parts = append(parts, fmt.Sprintf("%g g fat", f.Fat))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatMacroBalance returns what is left of each tracked macro, or how far over it is,
+// e.g. "1641 kcal left · 10 g protein over"
+func formatMacroBalance(goals, left nutrition.Facts) string {
+	left = left.Round()
+	var parts []string
+	add := func(goal, value float64, unit string) {
+		switch {
+		case goal <= 0:
+		case value < 0:
+			parts = append(parts, fmt.Sprintf("%g%s over", -value, unit))
+		default:
+			parts = append(parts, fmt.Sprintf("%g%s left", value, unit))
+		}
+	}
+	add(goals.EnergyKcal, left.EnergyKcal, " kcal")
+	add(goals.Protein, left.Protein, " g protein")
+	add(goals.Carbohydrates, left.Carbohydrates, " g carbs")
+	add(goals.Fat, left.Fat, " g fat")
+	return strings.Join(parts, " · ")
+}
+
+// FormatMacroBudget formats a user's daily macro goals with what they ate today and what is left
+func FormatMacroBudget(budget *command.MacroBudget) string {
+	var sb strings.Builder
+	daily := budget.Goals.Daily
+
+	sb.WriteString("🎯 *Daily macro goals*\n")
+	sb.WriteString(formatTrackedMacros(daily, daily))
+	sb.WriteString("\n\n")
+
+	if len(budget.Meals) == 0 {
+		sb.WriteString("Nothing logged today.\n\n")
+	} else {
+		sb.WriteString("*Eaten today:*\n")
+		for _, meal := range budget.Meals {
+			if meal.Facts.IsEmpty() {
+				sb.WriteString(fmt.Sprintf("• %s · nutrition unknown\n", escapeMarkdown(meal.Title)))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("• %s · %s\n", escapeMarkdown(meal.Title), formatTrackedMacros(daily, meal.Facts)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("*Today:* %s\n\n", formatMacroBalance(daily, budget.Remaining)))
+	sb.WriteString("Use /ate <number> to log a recipe you ate, or /macros off to stop tracking.")
+	return sb.String()
+}
+
+// FormatLoggedMeal confirms a logged meal, with what is left of the day's goals when
+// the user tracks macros (budget is nil otherwise)
+func FormatLoggedMeal(estimate *command.NutritionEstimate, budget *command.MacroBudget) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🍽️ Logged *%s* for today.\n", escapeMarkdown(estimate.Recipe.Title())))
+
+	if len(estimate.Grounded) == 0 {
+		sb.WriteString("I couldn't estimate its nutrition, as none of its ingredients match a scanned pantry product, so it doesn't count toward your goals.")
+	} else {
+		sb.WriteString(fmt.Sprintf("One serving: %s", formatFacts(estimate.Serving().Round())))
+	}
+
+	if budget != nil {
+		sb.WriteString(fmt.Sprintf("\n\n*Today:* %s", formatMacroBalance(budget.Goals.Daily, budget.Remaining)))
+	}
+	return sb.String()
+}
+
+func checkMark(checked bool) string {
+	if checked {
+		return "✅"
+	}
+	return "⬜"
+}
+
+// pluralize returns one when n is 1 and many otherwise
+func pluralize(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// truncate shortens text to at most max runes, adding an ellipsis when cut
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// escapeMarkdown escapes special characters for Telegram Markdown
+func escapeMarkdown(text string) string {
+	// Escape special Markdown characters
+	replacer := strings.NewReplacer(
+		"_", "\\_",
+		"*", "\\*",
+		"[", "\\[",
+		"]", "\\]",
+		"(", "\\(",
+		")", "\\)",
+		"~", "\\~",
+		"`", "\\`",
+		">", "\\>",
+		"#", "\\#",
+		"+", "\\+",
+		"-", "\\-",
+		"=", "\\=",
+		"|", "\\|",
+		"{", "\\{",
+		"}", "\\}",
+		".", "\\.",
+		"!", "\\!",
+	)
+	return replacer.Replace(text)
+}
+
+// FormatWelcome formats the welcome message
+func FormatWelcome() string {
+	return `👋 *Welcome to Recipe Bot!*
+
+I can help you extract recipes from:
+• 🎵 TikTok videos
+• 📺 YouTube videos
+• 📸 Instagram posts/reels
+• 🌐 Recipe websites
+
+*How to use:*
+Just send me a link to any recipe video or webpage, and I'll extract the ingredients and cooking instructions for you!
+
+*Commands:*
+/start - Show this message
+/help - Get help
+/recipes - List your saved recipes
+/recipe <number> - View a specific recipe
+
+Let's get cooking! 👨‍🍳`
+}
+
+// FormatHelp formats the help message
+func FormatHelp() string {
+	return `🤖 *Recipe Bot Help*
+
+*Supported Platforms:*
+• TikTok \(tiktok\.com\)
+• YouTube \(youtube\.com, youtu\.be\)
+• Instagram \(instagram\.com\)
+• Recipe websites \(with schema\.org markup\)
+
+*How it works:*
+1\. Send me a recipe link
+2\. I'll download and transcribe the video
+3\. AI extracts ingredients \& instructions
+4\. You get a formatted recipe\!
+
+*Tips:*
+• Make sure the link contains a recipe
+• Videos with clear audio work best
+• Written recipes are also supported
+
+*Commands:*
+/start \- Welcome message
+/help \- This help message
+/recipes \- Your saved recipes
+/recipes <category> \- Filter by category
+/recipe <number> \- View a specific recipe
+/categories \- Show recipe categories
+/match <ingredients> \- Find recipes by ingredients
+/pantry \- Manage your pantry items
+
+*Having issues?*
+Make sure:
+• The link is valid
+• The content contains a recipe
+• The video has clear audio \(if applicable\)
+
+Happy cooking\! 🍳`
+}
+
+// FormatMatchResults formats ingredient match results for Telegram display
+func FormatMatchResults(result *dto.MatchIngredientsResultDTO) string {
+	var sb strings.Builder
+
+	if result.TotalMatches == 0 {
+		return "📭 No matching recipes found\\.\n\nTry adding more ingredients or use /recipes to see all your recipes\\."
+	}
+
+	sb.WriteString("🍳 *Here's what you can make:*\n\n")
+
+	// Perfect matches
+	if len(result.PerfectMatches) > 0 {
+		sb.WriteString(fmt.Sprintf("✅ *Perfect Matches* \\(%d recipes\\):\n", len(result.PerfectMatches)))
+		for i, match := range result.PerfectMatches {
+			if i >= 5 {
+				sb.WriteString(fmt.Sprintf("   \\.\\.\\. and %d more\n", len(result.PerfectMatches)-5))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("%d\\. %s\n", i+1, escapeMarkdown(match.Recipe.Title)))
+		}
+		sb.WriteString("\n")
+	}
+
+	// High matches (missing 1-2 items)
+	if len(result.HighMatches) > 0 {
+		sb.WriteString(fmt.Sprintf("🔸 *Almost There* \\(%d recipes\\):\n", len(result.HighMatches)))
+		startIndex := len(result.PerfectMatches)
+		for i, match := range result.HighMatches {
+			if i >= 5 {
+				sb.WriteString(fmt.Sprintf("   \\.\\.\\. and %d more\n", len(result.HighMatches)-5))
+				break
+			}
+			missing := formatMissingItems(match.MissingItems, 3)
+			sb.WriteString(fmt.Sprintf("%d\\. %s\n", startIndex+i+1, escapeMarkdown(match.Recipe.Title)))
+			sb.WriteString(fmt.Sprintf("   _Missing: %s_\n", escapeMarkdown(missing)))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Medium matches
+	if len(result.MediumMatches) > 0 {
+		sb.WriteString(fmt.Sprintf("🔹 *Partial Matches* \\(%d recipes\\):\n", len(result.MediumMatches)))
+		startIndex := len(result.PerfectMatches) + len(result.HighMatches)
+		for i, match := range result.MediumMatches {
+			if i >= 3 {
+				sb.WriteString(fmt.Sprintf("   \\.\\.\\. and %d more\n", len(result.MediumMatches)-3))
+				break
+			}
+			missing := formatMissingItems(match.MissingItems, 3)
+			sb.WriteString(fmt.Sprintf("%d\\. %s \\(%.0f%% match\\)\n", startIndex+i+1, escapeMarkdown(match.Recipe.Title), match.MatchPercentage))
+			sb.WriteString(fmt.Sprintf("   _Missing: %s_\n", escapeMarkdown(missing)))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Low matches, only listed when a few missing ingredients are allowed
+	if len(result.LowMatches) > 0 {
+		sb.WriteString(fmt.Sprintf("🛒 *Needs a Shopping Trip* \\(%d recipes\\):\n", len(result.LowMatches)))
+		startIndex := len(result.PerfectMatches) + len(result.HighMatches) + len(result.MediumMatches)
+		for i, match := range result.LowMatches {
+			if i >= 3 {
+				sb.WriteString(fmt.Sprintf("   \\.\\.\\. and %d more\n", len(result.LowMatches)-3))
+				break
+			}
+			missing := formatMissingItems(match.MissingItems, 3)
+			sb.WriteString(fmt.Sprintf("%d\\. %s \\(%.0f%% match\\)\n", startIndex+i+1, escapeMarkdown(match.Recipe.Title), match.MatchPercentage))
+			sb.WriteString(fmt.Sprintf("   _Missing: %s_\n", escapeMarkdown(missing)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("Use /recipe <number> to view full recipe\\!")
+
+	return sb.String()
+}
+
+// formatMissingItems formats missing items list
+func formatMissingItems(items []string, max int) string {
+	if len(items) == 0 {
+		return "none"
+	}
+
+	if len(items) <= max {
+		return strings.Join(items, ", ")
+	}
+
+	shown := items[:max]
+	remaining := len(items) - max
+	return fmt.Sprintf("%s +%d more", strings.Join(shown, ", "), remaining)
+}
+
+// maxExplainButtons caps the buttons explaining match results
+const maxExplainButtons = 8
+
+// maxMatchCategoryButtons caps the buttons narrowing match results to a category
+const maxMatchCategoryButtons = 3
+
+// MatchResultsKeyboard returns a button to explain each listed match, numbered as
+// FormatMatchResults numbers them, and buttons to narrow or widen the match
+func MatchResultsKeyboard(result *dto.MatchIngredientsResultDTO, filters *ActiveFilters) tgbotapi.InlineKeyboardMarkup {
+	groups := []struct {
+		matches []dto.MatchResultDTO
+		shown   int
+	}{
+		{result.PerfectMatches, 5},
+		{result.HighMatches, 5},
+		{result.MediumMatches, 3},
+		{result.LowMatches, 3},
+	}
+
+	var buttons []tgbotapi.InlineKeyboardButton
+	var categories []string
+	seen := make(map[string]bool)
+	number := 0
+	for _, g := range groups {
+		for i, match := range g.matches {
+			if i < g.shown && len(buttons) < maxExplainButtons {
+				label := fmt.Sprintf("🔎 Why %d?", number+i+1)
+				buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(label, callbackExplainMatch+":"+match.Recipe.ID))
+			}
+			if category := match.Recipe.Category; category != "" && !seen[category] {
+				seen[category] = true
+				categories = append(categories, category)
+			}
+		}
+		number += len(g.matches)
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for len(buttons) > 0 {
+		n := min(4, len(buttons))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(buttons[:n]...))
+		buttons = buttons[n:]
+	}
+
+	var refine []tgbotapi.InlineKeyboardButton
+	if !filters.PerfectOnly && result.TotalMatches > 0 {
+		refine = append(refine, tgbotapi.NewInlineKeyboardButtonData("✅ Perfect only", callbackMatchFilter+":perfect"))
+	}
+	for _, n := range []int{1, 2} {
+		if filters.MaxMissing != n {
+			refine = append(refine, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🛒 Allow %d missing", n), fmt.Sprintf("%s:missing:%d", callbackMatchFilter, n)))
+		}
+	}
+	rows = append(rows, refine)
+
+	if filters.Category == nil && len(categories) > 1 {
+		var row []tgbotapi.InlineKeyboardButton
+		for _, category := range categories[:min(maxMatchCategoryButtons, len(categories))] {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData("Only "+category, callbackMatchFilter+":cat:"+category))
+		}
+		rows = append(rows, row)
+	}
+
+	if describeMatchFilters(filters) != "" {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("✖️ Clear filters", callbackMatchFilter+":clear")))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// describeMatchFilters describes the match refinements in effect, if any
+func describeMatchFilters(filters *ActiveFilters) string {
+	var parts []string
+	if filters.PerfectOnly {
+		parts = append(parts, "perfect matches only")
+	}
+	if filters.MaxMissing > 0 {
+		parts = append(parts, fmt.Sprintf("up to %d missing", filters.MaxMissing))
+	}
+	if filters.Category != nil {
+		parts = append(parts, string(*filters.Category))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatMatchExplanation shows how each ingredient of a matched recipe was counted:
+// what the user has, what stands in for what, what is missing and which staples
+// were left out of the percentage
+func FormatMatchExplanation(match *dto.MatchResultDTO) string {
+	var have, substitutes, missing, staples []string
+	for _, d := range match.Details {
+		name := escapeMarkdown(d.Ingredient)
+		switch d.Kind {
+		case string(matching.MatchKindExact):
+			have = append(have, name)
+		case string(matching.MatchKindPartial):
+			have = append(have, fmt.Sprintf("%s \\(your %s\\)", name, escapeMarkdown(d.Via)))
+		case string(matching.MatchKindSubstitute):
+			substitutes = append(substitutes, fmt.Sprintf("%s ← your %s", name, escapeMarkdown(d.Via)))
+		case string(matching.MatchKindStaple):
+			staples = append(staples, name)
+		default:
+			missing = append(missing, name)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔎 *%s* · %.0f%% match\n", escapeMarkdown(match.Recipe.Title), match.MatchPercentage))
+
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{"✅ *You have*", have},
+		{"🔄 *Standing in*", substitutes},
+		{"❌ *Missing*", missing},
+		{"🧂 *Staples, not counted*", staples},
+	}
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		sb.WriteString("\n" + section.title + "\n")
+		for _, item := range section.items {
+			sb.WriteString("• " + item + "\n")
+		}
+	}
+
+	counted := len(have) + len(substitutes) + len(missing)
+	sb.WriteString(fmt.Sprintf("\n_%d of %d ingredients counted toward the match_", len(have)+len(substitutes), counted))
+	return sb.String()
+}
+
+// FormatPantry formats pantry items for Telegram display.
+// Items are grouped under aisle headings when aisles are known.
+func FormatPantry(items []string, aisles map[string]string) string {
+	if len(items) == 0 {
+		return "📭 Your pantry is empty\\.\n\nUse /pantry add <items> to add ingredients\\.\nExample: /pantry add butter, eggs, milk"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🥫 *Your Pantry* \\(%d items\\)\n\n", len(items)))
+
+	if len(aisles) == 0 {
+		for _, item := range items {
+			sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(item)))
+		}
+	} else {
+		byAisle := make(map[shopping.Aisle][]string)
+		for _, item := range items {
+			aisle := shopping.ParseAisle(aisles[item])
+			byAisle[aisle] = append(byAisle[aisle], item)
+		}
+		for _, aisle := range shopping.AllAisles() {
+			if len(byAisle[aisle]) == 0 {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("*%s*\n", aisleLabels[aisle]))
+			for _, item := range byAisle[aisle] {
+				sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(item)))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n*Commands:*\n")
+	sb.WriteString("/pantry add <items> \\- Add items\n")
+	sb.WriteString("/pantry remove <items> \\- Remove items\n")
+	sb.WriteString("/pantry clear \\- Clear all items\n")
+	sb.WriteString("/match \\- Find recipes with pantry items")
+
+	return sb.String()
+}
+
+// difficultyEmoji returns the traffic-light emoji for a difficulty level
+func difficultyEmoji(difficulty string) string {
+	switch difficulty {
+	case "easy":
+		return "🟢"
+	case "medium":
+		return "🟡"
+	case "hard":
+		return "🔴"
+	default:
+		return "⚪"
+	}
+}
+
+// difficultyBadge returns a short difficulty label for recipe lists
+func difficultyBadge(difficulty string) string {
+	return difficultyEmoji(difficulty) + " " + TranslateDifficulty(difficulty, user.LanguageEnglish)
+}
+
+// notificationLabels are the names and descriptions shown in /notifications
+var notificationLabels = map[user.Notification][2]string{
+	user.NotificationDailySuggestion: {"Daily suggestions", fmt.Sprintf("a dinner idea from your collection at %d:00", dailySuggestionHour)},
+	user.NotificationExpiryWarning:   {"Expiry warnings", fmt.Sprintf("frozen portions to eat soon, at %d:00", expiryWarningHour)},
+	user.NotificationTimerPing:       {"Timer pings", "a message when each /timeline step starts"},
+	user.NotificationWeeklyDigest:    {"Weekly digest", fmt.Sprintf("the recipes you saved, %ss at %d:00", weeklyDigestDay, weeklyDigestHour)},
+	user.NotificationAchievements:    {"Achievements", fmt.Sprintf("badges as you earn them and a monthly recap on the 1st at %d:00", monthlyRecapHour)},
+}
+
+// notificationFooter tells the user where scheduled messages are turned off
+const notificationFooter = "\n\n_Change what I send you with /notifications_"
+
+// FormatStaples formats the user's pantry staples
+func FormatStaples(staples []string) string {
+	var sb strings.Builder
+	sb.WriteString("🧂 *Your pantry staples*\n\n")
+	if len(staples) == 0 {
+		sb.WriteString("You have no staples, so recipe matches count every ingredient\\.\n")
+	} else {
+		sb.WriteString("Recipe matches don't count these as missing:\n")
+		for _, staple := range staples {
+			sb.WriteString("• " + escapeMarkdown(staple) + "\n")
+		}
+	}
+	sb.WriteString("\n*Usage:* /staples add salt, olive oil · /staples remove sugar · /staples reset")
+	return sb.String()
+}
+
+// FormatNotificationSettings formats the user's notification settings
+func FormatNotificationSettings(settings map[user.Notification]bool) string {
+	var sb strings.Builder
+	sb.WriteString("🔔 *Notifications*\n\n")
+	for _, n := range user.AllNotifications() {
+		status := "off"
+		if settings[n] {
+			status = "on"
+		}
+		label := notificationLabels[n]
+		sb.WriteString(fmt.Sprintf("• *%s* (%s): %s\n", label[0], status, escapeMarkdown(label[1])))
+	}
+	sb.WriteString("\nTap a button to turn a notification on or off.")
+	return sb.String()
+}
+
+// FormatQuietHours formats the user's quiet hours and the time zone scheduled messages follow
+func FormatQuietHours(quiet *user.QuietHours, timezone string) string {
+	var sb strings.Builder
+	sb.WriteString("🌙 *Quiet hours*\n\n")
+	if quiet == nil {
+		sb.WriteString("Off: scheduled messages arrive at their usual time\\.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("%s: digests, suggestions and expiry warnings due then wait until they end\\.\n", escapeMarkdown(quiet.String())))
+	}
+	if timezone == "" {
+		sb.WriteString("Time zone: the server's\\.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("Time zone: %s\\.\n", escapeMarkdown(timezone)))
+	}
+	sb.WriteString("\n*Usage:* /quiet 22:00\\-07:00 · /quiet off · /timezone Europe/Lisbon")
+	return sb.String()
+}
+
+// FormatAutoExport formats the user's auto-export, nil when it is off
+func FormatAutoExport(autoExport *user.AutoExport) string {
+	var sb strings.Builder
+	sb.WriteString("🔄 *Auto\\-export*\n\n")
+
+	if autoExport == nil {
+		sb.WriteString("Off: use /export to export recipes yourself\\.\n\n")
+		sb.WriteString("*Usage:* /autoexport notion · /autoexport obsidian nightly · /autoexport off")
+		return sb.String()
+	}
+
+	target := "Notion"
+	if autoExport.Target == user.AutoExportObsidian {
+		target = "Obsidian files sent here"
+	}
+	when := "shortly after you save each recipe"
+	if autoExport.Schedule == user.AutoExportNightly {
+		when = fmt.Sprintf("every night after %02d:00, with the recipes saved that day", autoExportHour)
+	}
+	sb.WriteString(fmt.Sprintf("On: recipes go to %s %s\\.\n\n", target, when))
+	sb.WriteString("Use /autoexport off to stop\\.")
+	return sb.String()
+}
+
+// FormatTimeSettings formats the time zone and locale a user's dates are written in
+func FormatTimeSettings(dates Dates) string {
+	var sb strings.Builder
+	sb.WriteString("🕰️ *Time and dates*\n\n")
+
+	zone := "the server's"
+	if dates.Location != nil && dates.Location != time.Local {
+		zone = dates.Location.String()
+	}
+	sb.WriteString(fmt.Sprintf("Time zone: %s, where it is %s now\n", escapeMarkdown(zone), escapeMarkdown(dates.Clock(dates.Now))))
+
+	locale := dates.Locale
+	if locale == "" {
+		locale = user.LocaleEnglishUK
+	}
+	sb.WriteString(fmt.Sprintf("Locale: %s, dates like %s\n", escapeMarkdown(string(locale)), escapeMarkdown(dates.Date(dates.Now))))
+
+	sb.WriteString(fmt.Sprintf("\n*Usage:* /timezone Europe/Lisbon, or share your location · /locale %s", escapeMarkdown(formatLocales())))
+	return sb.String()
+}
+
+// formatLocales lists the supported locales, like "en-US, en-GB"
+func formatLocales() string {
+	locales := make([]string, 0, len(user.AllLocales()))
+	for _, locale := range user.AllLocales() {
+		locales = append(locales, string(locale))
+	}
+	return strings.Join(locales, ", ")
+}
+
+// NotificationsKeyboard builds the inline keyboard that toggles each notification
+func NotificationsKeyboard(settings map[user.Notification]bool) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, n := range user.AllNotifications() {
+		icon := "🔕"
+		if settings[n] {
+			icon = "✅"
+		}
+		button := tgbotapi.NewInlineKeyboardButtonData(icon+" "+notificationLabels[n][0], callbackNotification+":"+string(n))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(button))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// FormatDailySuggestion formats the daily dinner idea for a recipe with its number
+func FormatDailySuggestion(rec *dto.RecipeDTO, number int) string {
+	return fmt.Sprintf("🍽️ *Dinner idea*\n\n*%s*\n_%s_ | %s\n\nUse /recipe %d to see it.",
+		escapeMarkdown(rec.Title), rec.Category, difficultyBadge(rec.Difficulty), number) + notificationFooter
+}
+
+// FormatDinnerSuggestion formats the dinner idea that best fits what is left of the
+// user's macro goals, with what a serving takes from them
+func FormatDinnerSuggestion(rec *dto.RecipeDTO, number int, suggestion *command.DinnerSuggestion) string {
+	var sb strings.Builder
+	daily := suggestion.Budget.Goals.Daily
+
+	sb.WriteString(fmt.Sprintf("🍽️ *Dinner idea*\n\n*%s*\n_%s_ | %s\n\n",
+		escapeMarkdown(rec.Title), rec.Category, difficultyBadge(rec.Difficulty)))
+	sb.WriteString(fmt.Sprintf("🎯 One serving: %s\n", formatTrackedMacros(daily, suggestion.Estimate.Serving())))
+	sb.WriteString(fmt.Sprintf("Today after it: %s\n", formatMacroBalance(daily, suggestion.After)))
+	if !suggestion.Fits {
+		sb.WriteString("Nothing in your collection fits what is left of your goals, this comes closest.\n")
+	}
+	sb.WriteString(fmt.Sprintf("\nUse /recipe %d to see it.", number))
+	return sb.String() + notificationFooter
+}
+
+// FormatWeeklyDigest formats the recipes saved in the last week and how many exports were made
+func FormatWeeklyDigest(saved []*dto.RecipeDTO, total int, exports int) string {
+	var sb strings.Builder
+	sb.WriteString("📬 *Your week in recipes*\n\n")
+
+	noun := "recipes"
+	if len(saved) == 1 {
+		noun = "recipe"
+	}
+	sb.WriteString(fmt.Sprintf("You saved %d %s this week:\n", len(saved), noun))
+	for i, rec := range saved {
+		if i >= 10 {
+			sb.WriteString(fmt.Sprintf("... and %d more\n", len(saved)-10))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("• %s\n", escapeMarkdown(rec.Title)))
+	}
+	if exports > 0 {
+		noun = "exports"
+		if exports == 1 {
+			noun = "export"
+		}
+		sb.WriteString(fmt.Sprintf("\nYou also made %d %s.\n", exports, noun))
+	}
+	sb.WriteString(fmt.Sprintf("\nYour collection now has %d recipes. Use /recipes to browse it.", total))
+	return sb.String() + notificationFooter
+}
+
+// FormatAchievements formats a user's cooking streak, badges and progress towards
+// the ones still to earn. enabled reports whether they get badges and recaps as messages.
+func FormatAchievements(cooking *stats.Stats, now time.Time, enabled bool) string {
+	var sb strings.Builder
+	sb.WriteString("🏅 *Achievements*\n\n")
+
+	streak := cooking.Streak(now)
+	sb.WriteString(fmt.Sprintf("🔥 Streak: %d %s (longest %d)\n", streak, pluralize(streak, "day", "days"), cooking.LongestStreak()))
+	sb.WriteString(fmt.Sprintf("🍽️ Recipes cooked: %d · Cuisines: %d\n\n", len(cooking.Recipes()), len(cooking.Cuisines())))
+
+	earned := make(map[string]bool)
+	for _, e := range cooking.Earned() {
+		earned[e.Badge.ID] = true
+	}
+	sb.WriteString("*Badges:*\n")
+	for _, b := range stats.AllBadges() {
+		if earned[b.ID] {
+			sb.WriteString(fmt.Sprintf("%s *%s*: %s\n", b.Emoji, escapeMarkdown(b.Name), b.Description()))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("🔒 %s: %s (%d/%d)\n", escapeMarkdown(b.Name), b.Description(), min(cooking.Progress(b, now), b.Goal), b.Goal))
+	}
+
+	sb.WriteString("\nLog what you cook with /ate <number>. ")
+	if enabled {
+		sb.WriteString("I'll tell you when you earn a badge and send a recap every month, /achievements off stops that.")
+	} else {
+		sb.WriteString("Use /achievements on to hear when you earn a badge and get a recap every month.")
+	}
+	return sb.String()
+}
+
+// FormatEarnedBadges announces badges just earned
+func FormatEarnedBadges(badges []stats.Badge) string {
+	var sb strings.Builder
+	for _, b := range badges {
+		sb.WriteString(fmt.Sprintf("\n\n🏅 New badge: %s *%s*! %s.", b.Emoji, escapeMarkdown(b.Name), b.Description()))
+	}
+	return sb.String()
+}
+
+// FormatMonthlyRecap formats the tally of a month of cooking at home
+func FormatMonthlyRecap(month time.Time, tally stats.Month, cooking *stats.Stats, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📆 *Your %s in the kitchen*\n\n", month.Format("January")))
+
+	sb.WriteString(fmt.Sprintf("You cooked %d %s on %d %s.\n",
+		tally.Cooked, pluralize(tally.Cooked, "meal", "meals"), tally.Days, pluralize(tally.Days, "day", "days")))
+	if tally.NewRecipes > 0 {
+		sb.WriteString(fmt.Sprintf("New recipes tried: %d\n", tally.NewRecipes))
+	}
+	if len(tally.NewCuisines) > 0 {
+		sb.WriteString(fmt.Sprintf("New cuisines: %s\n", escapeMarkdown(strings.Join(tally.NewCuisines, ", "))))
+	}
+	if len(tally.Badges) > 0 {
+		var names []string
+		for _, id := range tally.Badges {
+			if b, ok := stats.BadgeByID(id); ok {
+				names = append(names, b.Emoji+" "+escapeMarkdown(b.Name))
+			}
+		}
+		sb.WriteString(fmt.Sprintf("Badges earned: %s\n", strings.Join(names, ", ")))
+	}
+	if streak := cooking.Streak(now); streak > 1 {
+		sb.WriteString(fmt.Sprintf("🔥 You're on a %d-day streak, keep it going!\n", streak))
+	}
+
+	sb.WriteString("\nSee all your badges with /achievements.")
+	return sb.String()
+}
+
+// FormatConversationReset confirms a conversation reset, listing what was forgotten
+func FormatConversationReset(reset ConversationReset) string {
+	const untouched = "Your recipes, pantry and settings are untouched."
+	if reset.Empty() {
+		return "🔄 There was nothing to forget, we're starting fresh. " + untouched
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🔄 Starting over. I forgot:\n")
+	if reset.Clarification {
+		sb.WriteString("• the question I was waiting for you to answer\n")
+	}
+	if reset.Filters {
+		sb.WriteString("• the search you were refining\n")
+	}
+	if reset.Results > 0 {
+		noun := "recipes"
+		if reset.Results == 1 {
+			noun = "recipe"
+		}
+		sb.WriteString(fmt.Sprintf("• the last list of %d %s, so numbers like #1 point nowhere until you search again\n", reset.Results, noun))
+	}
+	if reset.Turns > 0 {
+		sb.WriteString(fmt.Sprintf("• what we said in the last %d messages\n", reset.Turns))
+	}
+	sb.WriteString("\n" + untouched)
+	return sb.String()
+}
+
+// FormatRequests formats a household's open dish requests, oldest first, with who plans to cook them
+func FormatRequests(l *requests.List) string {
+	var sb strings.Builder
+
+	sb.WriteString("🙏 *Requests*\n\n")
+
+	if l.IsEmpty() {
+		sb.WriteString("Nobody asked for anything yet.\n\n")
+		sb.WriteString("Use /requests add <dish> to ask someone to cook it, or just say \"someone please make lasagna this week\"")
+		return sb.String()
+	}
+
+	for i, req := range l.Requests() {
+		sb.WriteString(fmt.Sprintf("%d. *%s* · asked by %s\n", i+1, escapeMarkdown(req.Dish), escapeMarkdown(req.Requester.Name)))
+		if req.Plan != nil {
+			sb.WriteString(fmt.Sprintf("   📅 %s plans %s for %s\n", escapeMarkdown(req.Plan.Cook.Name), escapeMarkdown(req.Plan.Title), req.Plan.Day))
+		}
+	}
+
+	sb.WriteString("\nUse /requests plan <request> <recipe number> <day> to cook one, and /requests cooked <request> when it's done")
+	return sb.String()
+}
+
+// FormatPremium describes the premium tier and whether the user has it
+func FormatPremium(until *time.Time, dates Dates) string {
+	var sb strings.Builder
+	sb.WriteString("⭐ *Premium*\n\n")
+	sb.WriteString("Premium raises how many recipe links you can send an hour and unlocks premium features, like a PDF cookbook of all your recipes with /export cookbook.\n\n")
+	if until != nil {
+		sb.WriteString(fmt.Sprintf("You have premium until %s. Buying a plan again adds to it.", dates.Date(*until)))
+	} else {
+		sb.WriteString("Pick a plan to get it:")
+	}
+	return sb.String()
+}
+
+// PremiumKeyboard builds the inline keyboard that buys each premium plan
+func PremiumKeyboard(plans []command.PremiumPlan, currency string) tgbotapi.InlineKeyboardMarkup {
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, plan := range plans {
+		label := fmt.Sprintf("%d days for %s", plan.Days, formatPrice(plan.Price, currency))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, callbackPremium+":"+plan.ID),
+		))
+	}
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// formatPrice formats a price in a currency's smallest unit: Stars, or cents
+func formatPrice(price int, currency string) string {
+	if currency == command.CurrencyStars {
+		return fmt.Sprintf("%d ⭐", price)
+	}
+	return fmt.Sprintf("%.2f %s", float64(price)/100, currency)
+}
+
+// FormatDeadLetters formats the messages the bot gave up delivering, newest first
+func FormatDeadLetters(letters []DeadLetter) string {
+	if len(letters) == 0 {
+		return "📭 *Outbox*\n\nEvery message was delivered."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📮 *Outbox*: %d undelivered messages\n\n", len(letters)))
+	for _, letter := range letters {
+		sb.WriteString(fmt.Sprintf("%s %s to %d, %d attempts: %s\n", letter.At.UTC().Format("2 Jan 15:04"), letter.Method, letter.ChatID, letter.Attempts, escapeMarkdown(letter.Error)))
+		if letter.Preview != "" {
+			sb.WriteString(fmt.Sprintf("  %s\n", escapeMarkdown(letter.Preview)))
+		}
+	}
+	return sb.String()
+}
+
+// maxUsageRows is how many features /admin analytics lists before summing up the rest
+const maxUsageRows = 25
+
+// FormatUsageReport formats how often each feature was used, most used first
+func FormatUsageReport(report *command.UsageReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📊 *Feature usage*, %d weeks since %s\n\n", report.Weeks, report.From.Format("2 Jan 2006")))
+	if len(report.Features) == 0 {
+		sb.WriteString("Nothing was used yet.")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("%d uses of %d features. This week vs. last week in brackets.\n\n", report.Total, len(report.Features)))
+	for i, usage := range report.Features {
+		if i == maxUsageRows {
+			sb.WriteString(fmt.Sprintf("\n... and %d features used less", len(report.Features)-maxUsageRows))
+			break
+		}
+		trend := "➖"
+		switch usage.Trend() {
+		case "up":
+			trend = "📈"
+		case "down":
+			trend = "📉"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s: %d (%d vs. %d)\n", trend, escapeMarkdown(string(usage.Feature)), usage.Total, usage.ThisWeek, usage.LastWeek))
+	}
 	return sb.String()
 }
@@ -2,70 +2,328 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"regexp"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"receipt-bot/internal/adapters/bookmarks"
+	"receipt-bot/internal/adapters/printable"
 	"receipt-bot/internal/application/command"
 	"receipt-bot/internal/application/dto"
 	"receipt-bot/internal/application/query"
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/analytics"
+	"receipt-bot/internal/domain/conversation"
+	"receipt-bot/internal/domain/cooking"
+	"receipt-bot/internal/domain/diet"
+	"receipt-bot/internal/domain/feature"
+	"receipt-bot/internal/domain/feed"
+	"receipt-bot/internal/domain/mealplan"
+	"receipt-bot/internal/domain/menu"
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/nutrition"
 	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/requests"
+	"receipt-bot/internal/domain/share"
 	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/telemetry"
 	"receipt-bot/internal/domain/user"
 	"receipt-bot/internal/ports"
 )
 
 // Handler handles Telegram bot messages
 type Handler struct {
-	bot                      *Bot
-	processRecipeLinkCommand *command.ProcessRecipeLinkCommand
-	getOrCreateUserCommand   *command.GetOrCreateUserCommand
-	listRecipesQuery         *query.ListRecipesQuery
-	matchIngredientsCommand  *command.MatchIngredientsCommand
-	managePantryCommand      *command.ManagePantryCommand
-	exportRecipeCommand      *command.ExportRecipeCommand
-	intentDetector           ports.IntentDetector
-	conversationManager      *ConversationManager
-	userRepo                 user.Repository
-	llm                      ports.LLMPort
+	bot                        *Bot
+	processRecipeLinkCommand   *command.ProcessRecipeLinkCommand
+	getOrCreateUserCommand     *command.GetOrCreateUserCommand
+	listRecipesQuery           *query.ListRecipesQuery
+	matchIngredientsCommand    *command.MatchIngredientsCommand
+	managePantryCommand        *command.ManagePantryCommand
+	exportRecipeCommand        *command.ExportRecipeCommand
+	recipeHistoryCommand       *command.RecipeHistoryCommand
+	manageMealPlanCommand      *command.ManageMealPlanCommand
+	shoppingListCommand        *command.GenerateShoppingListCommand
+	scanBarcodeCommand         *command.ScanBarcodeCommand
+	nutritionCommand           *command.EstimateNutritionCommand
+	achievementsCommand        *command.TrackAchievementsCommand
+	simplifyRecipeCommand      *command.SimplifyRecipeCommand
+	planMenuCommand            *command.PlanMenuCommand
+	cookingTimelineCommand     *command.CookingTimelineCommand
+	cookTogetherCommand        *command.CookTogetherCommand
+	categorizeRecipeCommand    *command.CategorizeRecipeCommand
+	convertRecipeCommand       *command.ConvertRecipeCommand
+	remixRecipeCommand         *command.RemixRecipeCommand
+	recipeAudioCommand         *command.RecipeAudioCommand
+	generateCoverCommand       *command.GenerateCoverCommand
+	checkDietCommand           *command.CheckDietCommand
+	premiumCommand             *command.ManagePremiumCommand
+	linkQuota                  *command.LinkQuota
+	manageFreezerCommand       *command.ManageFreezerCommand
+	manageRequestsCommand      *command.ManageRequestsCommand
+	exportFieldsCommand        *command.ManageExportFieldsCommand
+	savedFiltersCommand        *command.ManageSavedFiltersCommand
+	shortcutsCommand           *command.ManageShortcutsCommand
+	learnClarificationsCommand *command.LearnClarificationsCommand
+	notificationsCommand       *command.ManageNotificationsCommand
+	staplesCommand             *command.ManageStaplesCommand
+	recreateDishCommand        *command.RecreateDishCommand
+	scanPantryPhotoCommand     *command.ScanPantryPhotoCommand
+	linkAccountCommand         *command.LinkAccountCommand
+	shareCollectionCommand     *command.ShareCollectionCommand
+	reportErrorCommand         *command.ReportErrorCommand
+	autoExportCommand          *command.AutoExportCommand
+	cloudStorageCommand        *command.CloudStorageCommand
+	activityLogCommand         *command.ActivityLogCommand
+	transcriptCommand          *command.ConversationTranscriptCommand
+	usageAnalyticsCommand      *command.UsageAnalyticsCommand
+	importBookmarksCommand     *command.ImportBookmarksCommand
+	clipRecipeCommand          *command.ClipRecipeCommand
+	forwardEmailCommand        *command.ForwardEmailCommand
+	manageSubscriptionsCommand *command.ManageSubscriptionsCommand
+	moderateContentCommand     *command.ModerateContentCommand
+	browseSharedQuery          *query.BrowseSharedQuery
+	webAppURL                  string
+	clipAPIURL                 string
+	paymentProviderToken       string
+	adminChatID                int64
+	groupPantry                bool
+	intentDetector             ports.IntentDetector
+	conversationManager        *ConversationManager
+	userRepo                   user.Repository
+	llm                        ports.LLMPort
+	features                   *feature.Service
+	telemetry                  *telemetry.Collector
+	durations                  *telemetry.Durations
+	processingReports          recipe.ProcessingRepository
+	updateTimeout              time.Duration
+	messageWindow              *messageWindow
 }
 
 // HandlerConfig contains all dependencies for the Handler
 type HandlerConfig struct {
-	Bot                      *Bot
-	ProcessRecipeLinkCommand *command.ProcessRecipeLinkCommand
-	GetOrCreateUserCommand   *command.GetOrCreateUserCommand
-	ListRecipesQuery         *query.ListRecipesQuery
-	MatchIngredientsCommand  *command.MatchIngredientsCommand
-	ManagePantryCommand      *command.ManagePantryCommand
-	ExportRecipeCommand      *command.ExportRecipeCommand
-	IntentDetector           ports.IntentDetector
-	UserRepo                 user.Repository
-	LLM                      ports.LLMPort
+	Bot                        *Bot
+	ProcessRecipeLinkCommand   *command.ProcessRecipeLinkCommand
+	GetOrCreateUserCommand     *command.GetOrCreateUserCommand
+	ListRecipesQuery           *query.ListRecipesQuery
+	MatchIngredientsCommand    *command.MatchIngredientsCommand
+	ManagePantryCommand        *command.ManagePantryCommand
+	ExportRecipeCommand        *command.ExportRecipeCommand
+	RecipeHistoryCommand       *command.RecipeHistoryCommand          // optional, disables /history, /revert and /reextract when nil
+	ManageMealPlanCommand      *command.ManageMealPlanCommand         // optional, disables /plan when nil
+	ShoppingListCommand        *command.GenerateShoppingListCommand   // optional, disables /shopping when nil
+	ScanBarcodeCommand         *command.ScanBarcodeCommand            // optional, disables barcode photos when nil
+	NutritionCommand           *command.EstimateNutritionCommand      // optional, disables /nutrition, /macros and /ate when nil
+	AchievementsCommand        *command.TrackAchievementsCommand      // optional, disables /achievements when nil
+	SimplifyRecipeCommand      *command.SimplifyRecipeCommand         // optional, disables the Simplify button when nil
+	PlanMenuCommand            *command.PlanMenuCommand               // optional, disables /menu when nil
+	CookingTimelineCommand     *command.CookingTimelineCommand        // optional, disables /timeline when nil
+	CookTogetherCommand        *command.CookTogetherCommand           // optional, disables /cook, /claim and /done when nil
+	CategorizeRecipeCommand    *command.CategorizeRecipeCommand       // optional, disables filing recipes saved in a forum topic under its category when nil
+	ConvertRecipeCommand       *command.ConvertRecipeCommand          // optional, disables /convert when nil
+	RemixRecipeCommand         *command.RemixRecipeCommand            // optional, disables /remix when nil
+	RecipeAudioCommand         *command.RecipeAudioCommand            // optional, disables /audio when nil
+	GenerateCoverCommand       *command.GenerateCoverCommand          // optional, disables generated cover images when nil
+	CheckDietCommand           *command.CheckDietCommand              // optional, disables /diet when nil
+	PremiumCommand             *command.ManagePremiumCommand          // optional, disables /premium when nil
+	LinkQuota                  *command.LinkQuota                     // optional, recipe links are unlimited when nil
+	ManageFreezerCommand       *command.ManageFreezerCommand          // optional, disables /freezer when nil
+	ManageRequestsCommand      *command.ManageRequestsCommand         // optional, disables /requests when nil
+	ExportFieldsCommand        *command.ManageExportFieldsCommand     // optional, exports use the default fields when nil
+	SavedFiltersCommand        *command.ManageSavedFiltersCommand     // optional, disables /filters when nil
+	ShortcutsCommand           *command.ManageShortcutsCommand        // optional, disables /shortcuts when nil
+	LearnClarificationsCommand *command.LearnClarificationsCommand    // optional, clarifying questions are always asked when nil
+	NotificationsCommand       *command.ManageNotificationsCommand    // optional, disables /notifications when nil
+	StaplesCommand             *command.ManageStaplesCommand          // optional, disables /staples when nil
+	RecreateDishCommand        *command.RecreateDishCommand           // optional, disables recreating dishes from photos when nil
+	ScanPantryPhotoCommand     *command.ScanPantryPhotoCommand        // optional, disables pantry shelf photos when nil
+	LinkAccountCommand         *command.LinkAccountCommand            // optional, disables /link and /unlink when nil
+	ShareCollectionCommand     *command.ShareCollectionCommand        // optional, disables /share when nil
+	ReportErrorCommand         *command.ReportErrorCommand            // optional, disables /report when nil
+	AutoExportCommand          *command.AutoExportCommand             // optional, disables /autoexport when nil
+	CloudStorageCommand        *command.CloudStorageCommand           // optional, disables /connect dropbox and drive when nil
+	ActivityLogCommand         *command.ActivityLogCommand            // optional, disables /activity when nil
+	TranscriptCommand          *command.ConversationTranscriptCommand // optional, disables /transcript when nil
+	UsageAnalyticsCommand      *command.UsageAnalyticsCommand         // optional, disables usage counts and /admin analytics when nil
+	ImportBookmarksCommand     *command.ImportBookmarksCommand        // optional, disables bookmark file imports when nil
+	ClipRecipeCommand          *command.ClipRecipeCommand             // optional, disables /clip when nil
+	ForwardEmailCommand        *command.ForwardEmailCommand           // optional, disables /email when nil
+	ManageSubscriptionsCommand *command.ManageSubscriptionsCommand    // optional, disables /subscribe when nil
+	ModerateContentCommand     *command.ModerateContentCommand        // optional, disables /review and the content check notes of /share when nil
+	BrowseSharedQuery          *query.BrowseSharedQuery               // optional, disables guest mode when nil
+	WebAppURL                  string                                 // optional, disables /app when empty
+	ClipAPIURL                 string                                 // optional, public URL of the clip API shown by /clip
+	PaymentProviderToken       string                                 // optional, /premium is paid in Telegram Stars when empty
+	AdminChatID                int64                                  // optional, error reports are only stored when 0
+	GroupPantry                bool                                   // groups share one pantry and shopping list instead of each member's own
+	IntentDetector             ports.IntentDetector
+	UserRepo                   user.Repository
+	LLM                        ports.LLMPort
+	Features                   *feature.Service            // optional, all defaults when nil
+	Telemetry                  *telemetry.Collector        // optional, disables quality metrics and intent feedback buttons when nil
+	Durations                  *telemetry.Durations        // optional, disables processing time estimates when nil
+	ProcessingReports          recipe.ProcessingRepository // optional, disables /inspect when nil
+	UpdateTimeout              time.Duration               // optional, how long handling one update may take, defaultUpdateTimeout when 0
+	MessageWindow              time.Duration               // optional, pause that ends a thought sent across quick messages, disables combining them when 0
 }
 
+// defaultUpdateTimeout is how long handling one update may take unless configured,
+// enough to scrape and extract a long video
+const defaultUpdateTimeout = 10 * time.Minute
+
 // NewHandler creates a new message handler
 func NewHandler(cfg HandlerConfig) *Handler {
-	return &Handler{
-		bot:                      cfg.Bot,
-		processRecipeLinkCommand: cfg.ProcessRecipeLinkCommand,
-		getOrCreateUserCommand:   cfg.GetOrCreateUserCommand,
-		listRecipesQuery:         cfg.ListRecipesQuery,
-		matchIngredientsCommand:  cfg.MatchIngredientsCommand,
-		managePantryCommand:      cfg.ManagePantryCommand,
-		exportRecipeCommand:      cfg.ExportRecipeCommand,
-		intentDetector:           cfg.IntentDetector,
-		conversationManager:      NewConversationManager(),
-		userRepo:                 cfg.UserRepo,
-		llm:                      cfg.LLM,
+	updateTimeout := cfg.UpdateTimeout
+	if updateTimeout <= 0 {
+		updateTimeout = defaultUpdateTimeout
+	}
+	var window *messageWindow
+	if cfg.MessageWindow > 0 {
+		window = newMessageWindow(cfg.MessageWindow)
+	}
+
+	h := &Handler{
+		bot:                        cfg.Bot,
+		processRecipeLinkCommand:   cfg.ProcessRecipeLinkCommand,
+		getOrCreateUserCommand:     cfg.GetOrCreateUserCommand,
+		listRecipesQuery:           cfg.ListRecipesQuery,
+		matchIngredientsCommand:    cfg.MatchIngredientsCommand,
+		managePantryCommand:        cfg.ManagePantryCommand,
+		exportRecipeCommand:        cfg.ExportRecipeCommand,
+		recipeHistoryCommand:       cfg.RecipeHistoryCommand,
+		manageMealPlanCommand:      cfg.ManageMealPlanCommand,
+		shoppingListCommand:        cfg.ShoppingListCommand,
+		scanBarcodeCommand:         cfg.ScanBarcodeCommand,
+		nutritionCommand:           cfg.NutritionCommand,
+		achievementsCommand:        cfg.AchievementsCommand,
+		simplifyRecipeCommand:      cfg.SimplifyRecipeCommand,
+		planMenuCommand:            cfg.PlanMenuCommand,
+		cookingTimelineCommand:     cfg.CookingTimelineCommand,
+		cookTogetherCommand:        cfg.CookTogetherCommand,
+		categorizeRecipeCommand:    cfg.CategorizeRecipeCommand,
+		convertRecipeCommand:       cfg.ConvertRecipeCommand,
+		remixRecipeCommand:         cfg.RemixRecipeCommand,
+		recipeAudioCommand:         cfg.RecipeAudioCommand,
+		generateCoverCommand:       cfg.GenerateCoverCommand,
+		checkDietCommand:           cfg.CheckDietCommand,
+		premiumCommand:             cfg.PremiumCommand,
+		linkQuota:                  cfg.LinkQuota,
+		manageFreezerCommand:       cfg.ManageFreezerCommand,
+		manageRequestsCommand:      cfg.ManageRequestsCommand,
+		exportFieldsCommand:        cfg.ExportFieldsCommand,
+		savedFiltersCommand:        cfg.SavedFiltersCommand,
+		shortcutsCommand:           cfg.ShortcutsCommand,
+		learnClarificationsCommand: cfg.LearnClarificationsCommand,
+		notificationsCommand:       cfg.NotificationsCommand,
+		staplesCommand:             cfg.StaplesCommand,
+		recreateDishCommand:        cfg.RecreateDishCommand,
+		scanPantryPhotoCommand:     cfg.ScanPantryPhotoCommand,
+		linkAccountCommand:         cfg.LinkAccountCommand,
+		shareCollectionCommand:     cfg.ShareCollectionCommand,
+		reportErrorCommand:         cfg.ReportErrorCommand,
+		autoExportCommand:          cfg.AutoExportCommand,
+		cloudStorageCommand:        cfg.CloudStorageCommand,
+		activityLogCommand:         cfg.ActivityLogCommand,
+		transcriptCommand:          cfg.TranscriptCommand,
+		usageAnalyticsCommand:      cfg.UsageAnalyticsCommand,
+		importBookmarksCommand:     cfg.ImportBookmarksCommand,
+		clipRecipeCommand:          cfg.ClipRecipeCommand,
+		forwardEmailCommand:        cfg.ForwardEmailCommand,
+		manageSubscriptionsCommand: cfg.ManageSubscriptionsCommand,
+		moderateContentCommand:     cfg.ModerateContentCommand,
+		browseSharedQuery:          cfg.BrowseSharedQuery,
+		webAppURL:                  cfg.WebAppURL,
+		clipAPIURL:                 cfg.ClipAPIURL,
+		paymentProviderToken:       cfg.PaymentProviderToken,
+		adminChatID:                cfg.AdminChatID,
+		groupPantry:                cfg.GroupPantry,
+		intentDetector:             cfg.IntentDetector,
+		conversationManager:        NewConversationManager(),
+		userRepo:                   cfg.UserRepo,
+		llm:                        cfg.LLM,
+		features:                   cfg.Features,
+		telemetry:                  cfg.Telemetry,
+		durations:                  cfg.Durations,
+		processingReports:          cfg.ProcessingReports,
+		updateTimeout:              updateTimeout,
+		messageWindow:              window,
+	}
+	if h.transcriptCommand != nil {
+		h.conversationManager.OnTurn(h.recordTurn)
+	}
+	if summarizer, ok := cfg.IntentDetector.(ports.ConversationSummarizer); ok {
+		h.conversationManager.SetSummarizer(summarizer)
+	}
+	return h
+}
+
+// isEnabled reports whether a feature flag is enabled for the user
+func (h *Handler) isEnabled(ctx context.Context, flag feature.Flag, userID shared.ID) bool {
+	if h.features == nil {
+		return feature.DefaultValues()[flag]
+	}
+	return h.features.IsEnabled(ctx, flag, userID)
+}
+
+type lifetimeContextKey struct{}
+
+// withLifetime keeps ctx, before an update's deadline is added to it, as the
+// context of the background work the update starts
+func withLifetime(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lifetimeContextKey{}, ctx)
+}
+
+// background returns the context for work an update leaves running, such as imports
+// and reminders: it keeps the forum topic and format options and is canceled at
+// shutdown, but outlives the update's deadline
+func background(ctx context.Context) context.Context {
+	lifetime, ok := ctx.Value(lifetimeContextKey{}).(context.Context)
+	if !ok {
+		lifetime = context.WithoutCancel(ctx)
 	}
+	return withFormatOptions(withTopic(lifetime, topicFrom(ctx)), formatOptionsFrom(ctx))
+}
+
+// HandleUpdate handles a single Telegram update. Canceling ctx, as at shutdown,
+// stops the update along with the background work it started.
+func (h *Handler) HandleUpdate(ctx context.Context, update tgbotapi.Update) {
+	h.HandleUpdateInTopic(ctx, update, Topic{})
 }
 
-// HandleUpdate handles a single Telegram update
-func (h *Handler) HandleUpdate(update tgbotapi.Update) {
-	ctx := context.Background()
+// HandleUpdateInTopic handles a single Telegram update sent in a forum topic,
+// posting the replies into the same topic
+func (h *Handler) HandleUpdateInTopic(ctx context.Context, update tgbotapi.Update, topic Topic) {
+	ctx, cancel := context.WithTimeout(withTopic(withLifetime(ctx), topic), h.updateTimeout)
+	defer cancel()
+	defer h.recoverPanic(ctx, update)
+
+	// Handle the messages the user sent just before first, unless this one joins them
+	if h.messageWindow != nil {
+		key := windowKeyOf(update)
+		unlock := h.messageWindow.lock(key)
+		defer unlock()
+		if !joinsWindow(update.Message) {
+			h.messageWindow.flush(key)
+		}
+	}
+
+	// Inline keyboard presses
+	if update.CallbackQuery != nil {
+		h.handleCallback(ctx, update.CallbackQuery)
+		return
+	}
+
+	// Payments Telegram asks to confirm before charging
+	if update.PreCheckoutQuery != nil {
+		h.handlePreCheckout(ctx, update.PreCheckoutQuery)
+		return
+	}
 
 	// Only process messages
 	if update.Message == nil {
@@ -96,12 +354,57 @@ func (h *Handler) HandleUpdate(update tgbotapi.Update) {
 		}
 	}
 
+	// Take the date style from Telegram settings that name a region, like "en-us"
+	if !usr.HasLocale() && h.userRepo != nil {
+		if locale, ok := user.ParseLocale(update.Message.From.LanguageCode); ok && locale != usr.Locale() {
+			if err := usr.SetLocale(locale); err == nil {
+				_ = h.userRepo.UpdateLocale(ctx, usr.ID(), locale)
+			}
+		}
+	}
+
+	// Reply in the language of the message when it is clearly not the stored one,
+	// e.g. a Portuguese question from an English user. The stored preference stays
+	// the default: only this update's copy of the user changes.
+	if update.Message.Text != "" && !update.Message.IsCommand() {
+		if lang, ok := user.DetectLanguage(update.Message.Text); ok && lang != usr.Language() {
+			usr.SetLanguage(lang)
+		}
+	}
+
+	// Write the replies the way the user reads them
+	ctx = withFormatOptions(ctx, FormatOptionsFor(usr))
+
+	// Handle payments that went through
+	if update.Message.SuccessfulPayment != nil {
+		h.handleSuccessfulPayment(ctx, chatID, usr.ID(), update.Message.SuccessfulPayment)
+		return
+	}
+
 	// Handle commands
 	if update.Message.IsCommand() {
 		h.handleCommand(ctx, update.Message, usr)
 		return
 	}
 
+	// Handle photos (dishes to recreate, pantry shelves and product barcodes)
+	if len(update.Message.Photo) > 0 {
+		h.handlePhoto(ctx, update.Message, usr)
+		return
+	}
+
+	// Handle shared locations (time zones)
+	if update.Message.Location != nil {
+		h.handleLocation(ctx, update.Message, usr.ID())
+		return
+	}
+
+	// Handle files (browser bookmark exports)
+	if update.Message.Document != nil {
+		h.handleDocument(ctx, update.Message, usr)
+		return
+	}
+
 	// Handle text messages (URLs)
 	if update.Message.Text != "" {
 		h.handleTextMessage(ctx, update.Message, usr)
@@ -109,6 +412,33 @@ func (h *Handler) HandleUpdate(update tgbotapi.Update) {
 	}
 }
 
+// recoverPanic keeps a panic while handling an update from stopping the bot: it logs
+// the stack, tells the admin chat and apologizes to the user. Deferred by HandleUpdateInTopic.
+func (h *Handler) recoverPanic(ctx context.Context, update tgbotapi.Update) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	chatID := int64(0)
+	if chat := update.FromChat(); chat != nil {
+		chatID = chat.ID
+	}
+	log.Printf("Panic handling update %d from chat %d: %v\n%s", update.UpdateID, chatID, r, debug.Stack())
+
+	// The update may have panicked past its deadline
+	ctx = context.WithoutCancel(ctx)
+	if h.adminChatID != 0 {
+		msg := fmt.Sprintf("💥 The bot crashed handling update %d from chat %d: %v\nThe stack is in the logs.", update.UpdateID, chatID, r)
+		if err := h.bot.SendMessage(ctx, h.adminChatID, msg); err != nil {
+			log.Printf("Failed to report panic: %v", err)
+		}
+	}
+	if chatID != 0 && chatID != h.adminChatID {
+		_ = h.bot.SendError(ctx, chatID, "Something went wrong on our side. Please try again, or send /report to tell us what happened.")
+	}
+}
+
 // handleCommand handles bot commands
 func (h *Handler) handleCommand(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
 	chatID := message.Chat.ID
@@ -117,8 +447,22 @@ func (h *Handler) handleCommand(ctx context.Context, message *tgbotapi.Message,
 	lang := usr.Language()
 	t := GetTranslations(lang)
 
+	// Counted once handled, when it is known whether the command exists
+	feature := analytics.CommandFeature(cmd)
+	defer func() { h.recordUsage(ctx, feature) }()
+
 	switch cmd {
 	case "start":
+		// Guest links open the bot with /start guest_<token>
+		if token, ok := strings.CutPrefix(message.CommandArguments(), guestStartPrefix); ok {
+			h.handleGuest(ctx, chatID, token, 0, lang)
+			return
+		}
+		// Recipes clipped from the browser extension open with /start recipe_<id>
+		if recipeID, ok := strings.CutPrefix(message.CommandArguments(), recipeStartPrefix); ok {
+			h.handleStartRecipe(ctx, chatID, userID, recipeID, lang)
+			return
+		}
 		_ = h.bot.SendMessage(ctx, chatID, t.Welcome)
 
 	case "help":
@@ -133,25 +477,211 @@ func (h *Handler) handleCommand(ctx context.Context, message *tgbotapi.Message,
 	case "categories":
 		h.handleCategories(ctx, chatID, userID)
 
+	case "authors":
+		if author := strings.TrimSpace(message.CommandArguments()); author != "" {
+			h.handleRecipesByAuthor(ctx, chatID, userID, author)
+		} else {
+			h.handleAuthors(ctx, chatID, userID)
+		}
+
+	case "platforms":
+		if name := strings.TrimSpace(message.CommandArguments()); name != "" {
+			h.handleRecipesByPlatform(ctx, chatID, userID, name)
+		} else {
+			h.handlePlatforms(ctx, chatID, userID)
+		}
+
+	case "flavor":
+		h.handleRecipesByFlavor(ctx, chatID, userID, strings.TrimSpace(message.CommandArguments()))
+
 	case "match":
 		h.handleMatch(ctx, message, userID)
 
 	case "pantry":
 		h.handlePantry(ctx, message, userID)
 
+	case "staples":
+		h.handleStaples(ctx, message, userID)
+
 	case "language", "lang", "idioma":
 		h.handleLanguage(ctx, message, usr)
 
 	case "export":
 		h.handleExport(ctx, message, userID)
 
+	case "autoexport":
+		h.handleAutoExport(ctx, message, userID)
+
 	case "connect":
 		h.handleConnect(ctx, message, userID)
 
 	case "disconnect":
 		h.handleDisconnect(ctx, message, userID)
 
+	case "history":
+		h.handleHistory(ctx, message, userID)
+
+	case "revert":
+		h.handleRevert(ctx, message, userID)
+
+	case "reextract":
+		h.handleReextract(ctx, message, userID, lang)
+
+	case "plan":
+		h.handlePlan(ctx, message, userID, usr.Language())
+
+	case "shopping":
+		h.handleShoppingList(ctx, chatID, userID)
+
+	case "nutrition":
+		h.handleNutrition(ctx, message, userID)
+
+	case "macros":
+		h.handleMacros(ctx, message, userID)
+
+	case "ate":
+		h.handleAte(ctx, message, usr)
+
+	case "achievements":
+		h.handleAchievements(ctx, message, usr)
+
+	case "menu":
+		h.handleMenu(ctx, message, usr)
+
+	case "timeline":
+		h.handleTimeline(ctx, message, userID)
+
+	case "cook":
+		h.handleCook(ctx, message, userID)
+
+	case "claim":
+		h.handleCookStep(ctx, message, userID, false)
+
+	case "done":
+		h.handleCookStep(ctx, message, userID, true)
+
+	case "convert":
+		h.handleConvert(ctx, message, userID, lang)
+
+	case "remix":
+		h.handleRemix(ctx, message, userID, lang)
+
+	case "print":
+		h.handlePrint(ctx, message, userID)
+
+	case "audio":
+		h.handleAudio(ctx, message, userID, lang)
+
+	case "premium":
+		h.handlePremium(ctx, chatID, userID)
+
+	case "app":
+		h.handleApp(ctx, chatID, userID)
+
+	case "filters":
+		h.handleFilters(ctx, message, userID)
+
+	case "shortcuts":
+		h.handleShortcuts(ctx, message, userID)
+
+	case "notifications":
+		h.handleNotifications(ctx, message, userID)
+
+	case "quiet":
+		h.handleQuietHours(ctx, message, userID)
+
+	case "timezone":
+		h.handleTimezone(ctx, message, userID)
+
+	case "locale":
+		h.handleLocale(ctx, message, userID)
+
+	case "plain":
+		h.handlePlainMode(ctx, message, usr)
+
+	case "transcripts":
+		h.handleTranscripts(ctx, message, usr)
+
+	case "diet":
+		h.handleDiet(ctx, message, usr)
+
+	case "freezer":
+		h.handleFreezer(ctx, message, userID)
+
+	case "requests":
+		h.handleRequests(ctx, message, usr)
+
+	case "link":
+		h.handleLink(ctx, message, usr)
+
+	case "unlink":
+		h.handleUnlink(ctx, message)
+
+	case "share":
+		h.handleShare(ctx, message, userID)
+
+	case "report":
+		h.handleReport(ctx, message, userID)
+
+	case "activity":
+		h.handleActivity(ctx, message, userID)
+
+	case "transcript":
+		h.handleTranscript(ctx, chatID, userID)
+
+	case "reset":
+		h.handleReset(ctx, chatID, userID)
+
+	case "clip":
+		h.handleClip(ctx, message, usr)
+
+	case "email":
+		h.handleEmail(ctx, chatID, usr)
+
+	case "subscribe":
+		h.handleSubscribe(ctx, message, userID)
+
+	case "unsubscribe":
+		h.handleUnsubscribe(ctx, message, userID)
+
+	case "guest":
+		args := strings.Fields(message.CommandArguments())
+		if len(args) == 0 {
+			_ = h.bot.SendMessage(ctx, chatID, "Usage: /guest <code> \\[number]\n\nAsk a friend to send /share to get a guest link to their recipes.")
+			return
+		}
+		index := 0
+		if len(args) > 1 {
+			index, _ = strconv.Atoi(args[1])
+		}
+		h.handleGuest(ctx, chatID, args[0], index, lang)
+
+	case "review":
+		if h.moderateContentCommand == nil || h.adminChatID == 0 || chatID != h.adminChatID {
+			feature = analytics.FeatureUnknownCommand
+			_ = h.bot.SendMessage(ctx, chatID, t.UnknownCommand+" "+t.UseHelpCmd)
+			return
+		}
+		h.sendNextReview(ctx, chatID)
+
+	case "inspect":
+		if h.processingReports == nil || h.adminChatID == 0 || chatID != h.adminChatID {
+			feature = analytics.FeatureUnknownCommand
+			_ = h.bot.SendMessage(ctx, chatID, t.UnknownCommand+" "+t.UseHelpCmd)
+			return
+		}
+		h.handleInspect(ctx, chatID, message.CommandArguments())
+
+	case "admin":
+		if h.adminChatID == 0 || chatID != h.adminChatID {
+			feature = analytics.FeatureUnknownCommand
+			_ = h.bot.SendMessage(ctx, chatID, t.UnknownCommand+" "+t.UseHelpCmd)
+			return
+		}
+		h.handleAdmin(ctx, chatID, strings.Fields(message.CommandArguments()))
+
 	default:
+		feature = analytics.FeatureUnknownCommand
 		_ = h.bot.SendMessage(ctx, chatID, t.UnknownCommand+" "+t.UseHelpCmd)
 	}
 }
@@ -161,14 +691,22 @@ func (h *Handler) handleTextMessage(ctx context.Context, message *tgbotapi.Messa
 	chatID := message.Chat.ID
 	userID := usr.ID()
 	text := strings.TrimSpace(message.Text)
-	t := GetTranslations(usr.Language())
 
 	// Check if it looks like a URL first
-	if strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://") {
-		h.handleRecipeLink(ctx, chatID, userID, text)
+	if isLink(text) {
+		h.handleRecipeLink(ctx, chatID, userID, text, usr.Language())
 		return
 	}
 
+	// Expand the user's shortcuts; one for a command runs it without detecting an intent
+	if expanded, ok := usr.ExpandShortcut(text); ok {
+		if strings.HasPrefix(expanded, "/") {
+			h.handleCommand(ctx, shortcutCommand(message, expanded), usr)
+			return
+		}
+		text = expanded
+	}
+
 	// Check conversation state first - handle clarification responses
 	state := h.conversationManager.GetState(userID)
 	if state == StateAwaitingClarification {
@@ -176,10 +714,42 @@ func (h *Handler) handleTextMessage(ctx context.Context, message *tgbotapi.Messa
 		return
 	}
 
+	// "options" after a question was answered with the usual choice asks it after all
+	if isOptionsRequest(text) {
+		if skipped := h.conversationManager.TakeSkippedClarification(userID); skipped != nil {
+			h.askSkippedClarification(ctx, chatID, userID, skipped)
+			return
+		}
+	}
+
+	// Gather a thought sent across quick messages before detecting its intent
+	if h.messageWindow != nil {
+		lifetime := background(ctx)
+		update := tgbotapi.Update{Message: message}
+		h.messageWindow.add(windowKeyOf(update), text, func(text string) {
+			ctx, cancel := context.WithTimeout(lifetime, h.updateTimeout)
+			defer cancel()
+			defer h.recoverPanic(ctx, update)
+			h.handleNaturalLanguage(ctx, chatID, usr, text)
+		})
+		return
+	}
+
+	h.handleNaturalLanguage(ctx, chatID, usr, text)
+}
+
+// handleNaturalLanguage detects the intent of a message and acts on it
+func (h *Handler) handleNaturalLanguage(ctx context.Context, chatID int64, usr *user.User, text string) {
+	userID := usr.ID()
+	t := GetTranslations(usr.Language())
+
+	// Detecting the intent and acting on it can take a few seconds
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionTyping)()
+
 	// Try to detect intent from natural language
-	if h.intentDetector != nil {
+	if h.intentDetector != nil && h.isEnabled(ctx, feature.FlagIntentDetection, userID) {
 		// Get conversation history for context-aware detection
-		history := h.conversationManager.GetHistory(userID)
+		history := h.conversationManager.ContextHistory(ctx, userID)
 
 		var intent *ports.Intent
 		var err error
@@ -194,6 +764,12 @@ func (h *Handler) handleTextMessage(ctx context.Context, message *tgbotapi.Messa
 		if err != nil {
 			log.Printf("Intent detection error: %v", err)
 			// Fall through to default message
+		} else if intent != nil && intent.NextAction == ports.ActionClarify && h.answerWithUsualChoice(ctx, chatID, usr, text, intent) {
+			// The user always answers this question the same way
+			return
+		} else if intent != nil && intent.NextAction == ports.ActionClarify && h.sendFlavorMatches(ctx, chatID, userID, text) {
+			// "something spicy" needs no clarifying when the user has spicy recipes
+			return
 		} else if intent != nil && intent.Type != ports.IntentUnknown && intent.Confidence >= 0.6 {
 			// Check NextAction to determine how to proceed
 			switch intent.NextAction {
@@ -205,6 +781,7 @@ func (h *Handler) handleTextMessage(ctx context.Context, message *tgbotapi.Messa
 				return
 			default: // ActionExecute or empty
 				h.handleIntent(ctx, chatID, userID, intent, usr.Language())
+				h.askIntentFeedback(ctx, chatID, intent, usr.Language())
 				return
 			}
 		}
@@ -223,9 +800,14 @@ func (h *Handler) handleTextMessage(ctx context.Context, message *tgbotapi.Messa
 // handleIntent routes detected intents to appropriate handlers
 func (h *Handler) handleIntent(ctx context.Context, chatID int64, userID shared.ID, intent *ports.Intent, lang user.Language) {
 	t := GetTranslations(lang)
+	h.recordUsage(ctx, analytics.IntentFeature(string(intent.Type)))
 
 	switch intent.Type {
 	case ports.IntentListRecipes:
+		if intent.SavedPeriod != "" {
+			h.handleRecipesSavedIn(ctx, chatID, userID, intent.SavedPeriod)
+			return
+		}
 		h.handleListRecipesNatural(ctx, chatID, userID, nil, "")
 
 	case ports.IntentFilterCategory:
@@ -240,6 +822,29 @@ func (h *Handler) handleIntent(ctx context.Context, chatID int64, userID shared.
 	case ports.IntentShowCategories:
 		h.handleCategories(ctx, chatID, userID)
 
+	case ports.IntentFilterAuthor:
+		if intent.Author == "" {
+			h.handleAuthors(ctx, chatID, userID)
+			return
+		}
+		h.handleRecipesByAuthor(ctx, chatID, userID, intent.Author)
+
+	case ports.IntentShowAuthors:
+		h.handleAuthors(ctx, chatID, userID)
+
+	case ports.IntentFilterPlatform:
+		if intent.Platform == "" {
+			h.handlePlatforms(ctx, chatID, userID)
+			return
+		}
+		h.handleRecipesByPlatform(ctx, chatID, userID, intent.Platform)
+
+	case ports.IntentShowPlatforms:
+		h.handlePlatforms(ctx, chatID, userID)
+
+	case ports.IntentFilterFlavor:
+		h.handleRecipesByFlavor(ctx, chatID, userID, intent.Flavor)
+
 	case ports.IntentManagePantry:
 		h.handlePantryNatural(ctx, chatID, userID, intent.PantryAction, intent.PantryItems)
 
@@ -258,16 +863,60 @@ func (h *Handler) handleIntent(ctx context.Context, chatID int64, userID shared.
 		h.handleShowMore(ctx, chatID, userID)
 
 	case ports.IntentShowDetails:
-		h.handleShowDetails(ctx, chatID, userID, intent.RecipeNumber, lang)
+		h.handleShowDetails(ctx, chatID, userID, intent.RecipeNumber, intent.RecipeName, lang)
 
 	case ports.IntentRepeatLast:
 		h.handleRepeatLast(ctx, chatID, userID)
 
 	case ports.IntentCompoundQuery:
-		h.handleCompoundQuery(ctx, chatID, userID, intent.Category, intent.DietaryTags)
+		h.handleCompoundQuery(ctx, chatID, userID, intent.Category, intent.DietaryTags, intent.Difficulty, intent.MaxMinutes)
 
 	case ports.IntentComplexSearch:
-		h.handleComplexSearch(ctx, chatID, userID, intent.IngredientFilter, intent.DietaryTags)
+		h.handleComplexSearch(ctx, chatID, userID, intent.IngredientFilter, intent.DietaryTags, intent.Difficulty, intent.MaxMinutes)
+
+	case ports.IntentShoppingList:
+		h.handleShoppingList(ctx, chatID, userID)
+
+	case ports.IntentPlanMenu:
+		guests := intent.Guests
+		if guests <= 0 {
+			guests = defaultMenuGuests
+		}
+		occasion := intent.Occasion
+		if occasion == "" {
+			occasion = defaultMenuOccasion
+		}
+		h.handleMenuStart(ctx, chatID, userID, occasion, guests, defaultServeTime(time.Now()), lang)
+
+	case ports.IntentConvertRecipe:
+		h.handleConvertRecipe(ctx, chatID, userID, intent.RecipeNumber, intent.Appliance, lang)
+
+	case ports.IntentRemixRecipe:
+		h.handleRemixRecipe(ctx, chatID, userID, intent.RecipeNumber, intent.RemixGoal, lang)
+
+	case ports.IntentFreezer:
+		h.handleFreezerNatural(ctx, chatID, userID, intent.FreezerAction, intent.RecipeNumber, intent.Portions)
+
+	case ports.IntentRequestDish:
+		h.handleRequestDish(ctx, chatID, h.requesterByID(ctx, userID), intent.Dish)
+
+	case ports.IntentSaveFilter:
+		h.handleSaveFilter(ctx, chatID, userID, intent.FilterName)
+
+	case ports.IntentRunFilter:
+		h.handleRunFilter(ctx, chatID, userID, intent.FilterName)
+
+	case ports.IntentStartOver:
+		h.handleReset(ctx, chatID, userID)
+
+	case ports.IntentExportRecipe:
+		h.handleExportRecipeNatural(ctx, chatID, userID, intent.RecipeNumber, intent.ExportFormat)
+
+	case ports.IntentAddToShoppingList:
+		h.handleAddToShoppingList(ctx, chatID, userID, intent.RecipeNumber)
+
+	case ports.IntentTranslateRecipe:
+		h.handleTranslateRecipe(ctx, chatID, userID, intent.RecipeNumber, intent.Language, lang)
 
 	default:
 		_ = h.bot.SendMessage(ctx, chatID,
@@ -278,6 +927,37 @@ func (h *Handler) handleIntent(ctx context.Context, chatID int64, userID shared.
 	}
 }
 
+// handleReset forgets the user's conversation, so they can escape follow-ups that went wrong
+func (h *Handler) handleReset(ctx context.Context, chatID int64, userID shared.ID) {
+	reset := h.conversationManager.Reset(userID)
+	_ = h.bot.SendMessage(ctx, chatID, FormatConversationReset(reset))
+}
+
+// askIntentFeedback asks the user to rate the detected intent when telemetry is enabled
+func (h *Handler) askIntentFeedback(ctx context.Context, chatID int64, intent *ports.Intent, lang user.Language) {
+	if h.telemetry == nil {
+		return
+	}
+	t := GetTranslations(lang)
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, t.IntentFeedback, IntentFeedbackKeyboard(intent.Type))
+}
+
+// handleIntentFeedback records a thumbs up or down for a detected intent
+func (h *Handler) handleIntentFeedback(ctx context.Context, cq *tgbotapi.CallbackQuery, lang user.Language, payload string) {
+	intentType, vote, ok := strings.Cut(payload, ":")
+	if h.telemetry == nil || !ok || intentType == "" {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	h.telemetry.RecordIntentFeedback(intentType, vote == "1")
+
+	t := GetTranslations(lang)
+	_ = h.bot.AnswerCallback(ctx, cq.ID, t.FeedbackThanks)
+	noButtons := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	_ = h.bot.EditMessageReplyMarkup(ctx, cq.Message.Chat.ID, cq.Message.MessageID, noButtons)
+}
+
 // handleListRecipesNatural handles natural language recipe listing
 func (h *Handler) handleListRecipesNatural(ctx context.Context, chatID int64, userID shared.ID, category *recipe.Category, _ string) {
 	var recipes []*dto.RecipeDTO
@@ -327,8 +1007,8 @@ func (h *Handler) handleListRecipesNatural(ctx context.Context, chatID int64, us
 				break
 			}
 
-			msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.Title)
-			msg += fmt.Sprintf("   _%s_ | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform)
+			msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.DisplayName)
+			msg += fmt.Sprintf("   _%s_ | %s | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform, difficultyBadge(recipeDTO.Difficulty))
 		}
 
 		msg += "\nSay \"details on #X\" to view a recipe"
@@ -351,6 +1031,7 @@ func (h *Handler) handleSearchByIngredient(ctx context.Context, chatID int64, us
 
 	// Store results in conversation context
 	h.conversationManager.UpdateIngredientSearch(userID, ingredient, recipes)
+	h.conversationManager.SetActiveFilters(userID, &ActiveFilters{SearchTerm: ingredient})
 
 	msg := fmt.Sprintf("🔍 *Recipes with %s* (%d found)\n\n", ingredient, len(recipes))
 
@@ -363,8 +1044,8 @@ func (h *Handler) handleSearchByIngredient(ctx context.Context, chatID int64, us
 				break
 			}
 
-			msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.Title)
-			msg += fmt.Sprintf("   _%s_ | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform)
+			msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.DisplayName)
+			msg += fmt.Sprintf("   _%s_ | %s | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform, difficultyBadge(recipeDTO.Difficulty))
 		}
 
 		msg += "\nSay \"details on #X\" to view a recipe"
@@ -373,1110 +1054,5899 @@ func (h *Handler) handleSearchByIngredient(ctx context.Context, chatID int64, us
 	_ = h.bot.SendMessage(ctx, chatID, msg)
 }
 
-// handleMatchNatural handles natural language ingredient matching
-func (h *Handler) handleMatchNatural(ctx context.Context, chatID int64, userID shared.ID, ingredients []string) {
-	if len(ingredients) == 0 {
-		// Check if user has pantry items
-		pantry, err := h.managePantryCommand.GetPantry(ctx, userID)
-		if err != nil || len(pantry.Items) == 0 {
-			_ = h.bot.SendMessage(ctx, chatID,
-				"Tell me what ingredients you have!\n\n"+
-					"Example: \"I have chicken, pasta, and garlic\"\n"+
-					"Or add items to your pantry: \"add chicken to pantry\"")
-			return
-		}
-		ingredients = pantry.Items
+// handleRecipesSavedIn handles listing the recipes saved in a period, such as "last month",
+// counted in the user's time zone
+func (h *Handler) handleRecipesSavedIn(ctx context.Context, chatID int64, userID shared.ID, period string) {
+	dates := h.datesFor(ctx, userID, time.Now())
+	saved, ok := recipe.ParsePeriod(period, dates.local(dates.Now))
+	if !ok {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🤔 I couldn't tell which dates \"%s\" means.\n\nTry \"last month\", \"in December\" or \"this week\".", escapeMarkdown(period)))
+		return
 	}
 
-	// Store ingredients in conversation context
-	h.conversationManager.UpdateMatchIngredients(userID, ingredients)
+	recipes, err := h.listRecipesQuery.ExecuteByDateRange(ctx, userID, saved)
+	if err != nil {
+		log.Printf("Error listing recipes by date: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to list recipes. Please try again.")
+		return
+	}
 
-	input := command.MatchIngredientsInput{
-		UserID:      userID,
-		Ingredients: ingredients,
+	// Store results in conversation context
+	h.conversationManager.UpdateSavedFilter(userID, period, recipes)
+
+	// The range ends at midnight after its last day
+	span := "on " + dates.Date(saved.From)
+	if last := saved.To.AddDate(0, 0, -1); !last.Equal(saved.From) {
+		span = fmt.Sprintf("from %s to %s", dates.Date(saved.From), dates.Date(last))
 	}
 
-	result, err := h.matchIngredientsCommand.Execute(ctx, input)
-	if err != nil {
-		log.Printf("Error matching ingredients: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to match ingredients. Please try again.")
+	if len(recipes) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📭 No recipes saved %s.", span))
 		return
 	}
 
-	msg := FormatMatchResults(result)
+	msg := fmt.Sprintf("🗓 *Saved %s* (%d found)\n\n", span, len(recipes))
+	for i, recipeDTO := range recipes {
+		if i >= 10 {
+			msg += fmt.Sprintf("\n... and %d more recipes. Say \"show more\" to see them.", len(recipes)-10)
+			break
+		}
+
+		msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.DisplayName)
+		msg += fmt.Sprintf("   _%s_ | %s | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform, difficultyBadge(recipeDTO.Difficulty))
+	}
+
+	msg += "\nSay \"details on #X\" to view a recipe"
+
 	_ = h.bot.SendMessage(ctx, chatID, msg)
 }
 
-// handleShowMore shows more results from the previous query
-func (h *Handler) handleShowMore(ctx context.Context, chatID int64, userID shared.ID) {
-	convCtx := h.conversationManager.GetContext(userID)
-	if convCtx == nil || len(convCtx.LastRecipes) == 0 {
-		_ = h.bot.SendMessage(ctx, chatID,
-			"I don't have any previous results to show more of.\n\n"+
-				"Try searching for recipes first, like:\n"+
-				"• \"Show my recipes\"\n"+
-				"• \"Pasta recipes\"")
+// handleRecipesByAuthor handles listing the recipes saved from a source author
+func (h *Handler) handleRecipesByAuthor(ctx context.Context, chatID int64, userID shared.ID, author string) {
+	recipes, err := h.listRecipesQuery.ExecuteByAuthor(ctx, userID, author)
+	if err != nil {
+		log.Printf("Error listing recipes by author: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to list recipes. Please try again.")
 		return
 	}
 
-	// Increment offset and get next page
-	pageSize := 10
-	newOffset := h.conversationManager.IncrementOffset(userID, pageSize)
-	recipes, hasMore := h.conversationManager.GetRemainingRecipes(userID, pageSize)
+	// Store results in conversation context
+	h.conversationManager.UpdateAuthorFilter(userID, author, recipes)
 
 	if len(recipes) == 0 {
-		_ = h.bot.SendMessage(ctx, chatID,
-			"You've seen all the recipes from your last search.\n\n"+
-				"Try a different search or say \"show again\" to repeat.")
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📭 No recipes found from %s.\n\nUse /authors to see the creators you save from.", escapeMarkdown(author)))
 		return
 	}
 
-	msg := fmt.Sprintf("📚 *More Recipes* (showing %d-%d of %d)\n\n",
-		newOffset-pageSize+1, newOffset-pageSize+len(recipes), len(convCtx.LastRecipes))
-
+	msg := fmt.Sprintf("👤 *Recipes from %s* (%d found)\n\n", escapeMarkdown(recipes[0].SourceAuthor), len(recipes))
 	for i, recipeDTO := range recipes {
-		idx := newOffset - pageSize + i + 1
-		msg += fmt.Sprintf("%d. %s\n", idx, recipeDTO.Title)
-		msg += fmt.Sprintf("   _%s_ | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform)
-	}
+		if i >= 10 {
+			msg += fmt.Sprintf("\n... and %d more recipes. Say \"show more\" to see them.", len(recipes)-10)
+			break
+		}
 
-	if hasMore {
-		msg += "\nSay \"show more\" for additional recipes"
-	} else {
-		msg += "\nThat's all! Say \"show again\" to see them from the beginning"
+		msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.DisplayName)
+		msg += fmt.Sprintf("   _%s_ | %s | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform, difficultyBadge(recipeDTO.Difficulty))
 	}
-	msg += "\nOr say \"details on #X\" to view a specific recipe"
+
+	msg += "\nSay \"details on #X\" to view a recipe"
 
 	_ = h.bot.SendMessage(ctx, chatID, msg)
 }
 
-// handleShowDetails shows details of a specific recipe from the last results
-func (h *Handler) handleShowDetails(ctx context.Context, chatID int64, userID shared.ID, recipeNumber int, lang user.Language) {
-	convCtx := h.conversationManager.GetContext(userID)
-	if convCtx == nil || len(convCtx.LastRecipes) == 0 {
-		_ = h.bot.SendMessage(ctx, chatID,
-			"I don't have any recent recipe results.\n\n"+
-				"Try searching for recipes first, like:\n"+
-				"• \"Show my recipes\"\n"+
-				"• \"Pasta recipes\"")
+// handleAuthors handles showing the creators a user saves recipes from
+func (h *Handler) handleAuthors(ctx context.Context, chatID int64, userID shared.ID) {
+	authors, err := h.listRecipesQuery.GetTopAuthors(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting author counts: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to get authors. Please try again.")
 		return
 	}
 
-	if recipeNumber < 1 || recipeNumber > len(convCtx.LastRecipes) {
-		_ = h.bot.SendMessage(ctx, chatID,
-			fmt.Sprintf("Recipe #%d not found. I have %d recipes from your last search.\n\n"+
-				"Try \"details on #1\" through \"details on #%d\"",
-				recipeNumber, len(convCtx.LastRecipes), len(convCtx.LastRecipes)))
+	_ = h.bot.SendMessage(ctx, chatID, FormatAuthors(authors))
+}
+
+// handleRecipesByPlatform handles listing the recipes saved from a source platform
+func (h *Handler) handleRecipesByPlatform(ctx context.Context, chatID int64, userID shared.ID, name string) {
+	platform, ok := recipe.ParsePlatform(name)
+	if !ok {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🤔 I don't know the platform %s.\n\nTry TikTok, YouTube, Instagram or web.", escapeMarkdown(name)))
 		return
 	}
 
-	recipeDTO := convCtx.LastRecipes[recipeNumber-1]
+	recipes, err := h.listRecipesQuery.ExecuteByPlatform(ctx, userID, platform)
+	if err != nil {
+		log.Printf("Error listing recipes by platform: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to list recipes. Please try again.")
+		return
+	}
 
-	// Translate recipe if user language is Portuguese and we have LLM
-	var translation *TranslatedRecipeDTO
-	if lang == user.LanguagePortuguese && h.llm != nil {
-		translated, err := h.translateRecipe(ctx, recipeDTO, "Portuguese")
-		if err != nil {
-			log.Printf("Translation error (showing original): %v", err)
-		} else {
-			translation = translated
+	// Store results in conversation context
+	h.conversationManager.UpdatePlatformFilter(userID, platform, recipes)
+
+	if len(recipes) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📭 No recipes saved from %s.\n\nUse /platforms to see where your recipes come from.", platformName(platform)))
+		return
+	}
+
+	msg := fmt.Sprintf("📱 *Recipes from %s* (%d found)\n\n", platformName(platform), len(recipes))
+	for i, recipeDTO := range recipes {
+		if i >= 10 {
+			msg += fmt.Sprintf("\n... and %d more recipes. Say \"show more\" to see them.", len(recipes)-10)
+			break
 		}
+
+		msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.DisplayName)
+		msg += fmt.Sprintf("   _%s_ | %s\n", recipeDTO.Category, difficultyBadge(recipeDTO.Difficulty))
 	}
 
-	messageText := FormatRecipeDTOWithTranslation(recipeDTO, translation, lang)
-	_ = h.bot.SendMessage(ctx, chatID, messageText)
+	msg += "\nSay \"details on #X\" to view a recipe"
 
-	// Update context to track that user viewed a recipe
-	h.conversationManager.SetContext(userID, &ConversationContext{
-		LastAction:  ActionViewRecipe,
-		LastRecipes: convCtx.LastRecipes,
-	})
+	_ = h.bot.SendMessage(ctx, chatID, msg)
 }
 
-// translateRecipe translates a recipe DTO to the target language using LLM
-func (h *Handler) translateRecipe(ctx context.Context, rec *dto.RecipeDTO, targetLang string) (*TranslatedRecipeDTO, error) {
-	// Build input for translation
-	input := &ports.RecipeTranslationInput{
-		Title:        rec.Title,
-		Ingredients:  make([]ports.IngredientData, len(rec.Ingredients)),
-		Instructions: make([]ports.InstructionData, len(rec.Instructions)),
+// handlePlatforms handles showing how many recipes a user saved from each platform
+func (h *Handler) handlePlatforms(ctx context.Context, chatID int64, userID shared.ID) {
+	platforms, err := h.listRecipesQuery.GetPlatformCounts(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting platform counts: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to get platforms. Please try again.")
+		return
 	}
 
-	for i, ing := range rec.Ingredients {
-		input.Ingredients[i] = ports.IngredientData{
-			Name:     ing.Name,
-			Quantity: ing.Quantity,
-			Unit:     ing.Unit,
-			Notes:    ing.Notes,
+	_ = h.bot.SendMessage(ctx, chatID, FormatPlatforms(platforms))
+}
+
+// handleRecipesByFlavor handles listing the recipes classified with a flavor profile
+func (h *Handler) handleRecipesByFlavor(ctx context.Context, chatID int64, userID shared.ID, name string) {
+	flavor, ok := recipe.ParseFlavor(name)
+	if !ok {
+		msg := "🤔 Which flavor are you in the mood for?\n\nTry /flavor with " + flavorNames()
+		if name != "" {
+			msg = fmt.Sprintf("🤔 I don't know the flavor %s.\n\nTry %s.", escapeMarkdown(name), flavorNames())
 		}
+		_ = h.bot.SendMessage(ctx, chatID, msg)
+		return
 	}
 
-	for i, inst := range rec.Instructions {
-		input.Instructions[i] = ports.InstructionData{
-			StepNumber: inst.StepNumber,
-			Text:       inst.Text,
-		}
+	recipes, err := h.listRecipesQuery.ExecuteByFlavor(ctx, userID, flavor)
+	if err != nil {
+		log.Printf("Error listing recipes by flavor: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to list recipes. Please try again.")
+		return
 	}
 
-	// Call LLM for translation
-	output, err := h.llm.TranslateRecipe(ctx, input, targetLang)
+	if len(recipes) == 0 {
+		h.conversationManager.UpdateFlavorFilter(userID, flavor, recipes)
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📭 No %s recipes yet.\n\nFlavors are classified when you save a recipe.", flavor))
+		return
+	}
+
+	h.sendFlavorList(ctx, chatID, userID, flavor, recipes)
+}
+
+// sendFlavorMatches lists the user's recipes with the flavor a message names, like
+// "I want something spicy", and reports whether there were any to list
+func (h *Handler) sendFlavorMatches(ctx context.Context, chatID int64, userID shared.ID, text string) bool {
+	flavor, ok := recipe.FlavorInText(text)
+	if !ok {
+		return false
+	}
+
+	recipes, err := h.listRecipesQuery.ExecuteByFlavor(ctx, userID, flavor)
 	if err != nil {
-		return nil, err
+		log.Printf("Error listing recipes by flavor: %v", err)
+		return false
+	}
+	if len(recipes) == 0 {
+		return false
 	}
 
-	// Convert to TranslatedRecipeDTO
-	result := &TranslatedRecipeDTO{
-		Title:        output.Title,
-		Ingredients:  make([]dto.IngredientDTO, len(output.Ingredients)),
-		Instructions: make([]dto.InstructionDTO, len(output.Instructions)),
+	h.sendFlavorList(ctx, chatID, userID, flavor, recipes)
+	return true
+}
+
+// sendFlavorList sends the recipes with a flavor profile and stores them in the conversation context
+func (h *Handler) sendFlavorList(ctx context.Context, chatID int64, userID shared.ID, flavor recipe.Flavor, recipes []*dto.RecipeDTO) {
+	h.conversationManager.UpdateFlavorFilter(userID, flavor, recipes)
+
+	msg := fmt.Sprintf("👅 *%s Recipes* (%d found)\n\n", strings.Title(flavor.String()), len(recipes))
+	for i, recipeDTO := range recipes {
+		if i >= 10 {
+			msg += fmt.Sprintf("\n... and %d more recipes. Say \"show more\" to see them.", len(recipes)-10)
+			break
+		}
+
+		msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.DisplayName)
+		msg += fmt.Sprintf("   _%s_ | %s\n", recipeDTO.Category, difficultyBadge(recipeDTO.Difficulty))
 	}
 
-	for i, ing := range output.Ingredients {
-		result.Ingredients[i] = dto.IngredientDTO{
-			Name:     ing.Name,
-			Quantity: ing.Quantity,
-			Unit:     ing.Unit,
-			Notes:    ing.Notes,
+	msg += "\nSay \"details on #X\" to view a recipe"
+
+	_ = h.bot.SendMessage(ctx, chatID, msg)
+}
+
+// flavorNames lists the flavor profiles for messages, e.g. "spicy, sweet, umami-rich or tangy"
+func flavorNames() string {
+	flavors := recipe.AllFlavors()
+	names := make([]string, len(flavors))
+	for i, f := range flavors {
+		names[i] = f.String()
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// handleMatchNatural handles natural language ingredient matching
+func (h *Handler) handleMatchNatural(ctx context.Context, chatID int64, userID shared.ID, ingredients []string) {
+	if len(ingredients) == 0 {
+		// Check if user has pantry items
+		owner, ok := h.pantryOwner(ctx, chatID, userID)
+		if !ok {
+			return
+		}
+		pantry, err := h.managePantryCommand.GetPantry(ctx, owner)
+		if err != nil || len(pantry.Items) == 0 {
+			_ = h.bot.SendMessage(ctx, chatID,
+				"Tell me what ingredients you have!\n\n"+
+					"Example: \"I have chicken, pasta, and garlic\"\n"+
+					"Or add items to your pantry: \"add chicken to pantry\"")
+			return
 		}
+		ingredients = pantry.Items
 	}
 
-	for i, inst := range output.Instructions {
-		result.Instructions[i] = dto.InstructionDTO{
-			StepNumber: inst.StepNumber,
-			Text:       inst.Text,
+	// Store ingredients in conversation context
+	h.conversationManager.UpdateMatchIngredients(userID, ingredients)
+	filters := &ActiveFilters{}
+	h.conversationManager.SetActiveFilters(userID, filters)
+
+	h.runMatch(ctx, chatID, userID, ingredients, filters)
+}
+
+// runMatch matches ingredients against the user's recipes with the match refinements
+// of the filters, and sends the results with buttons to explain and refine them
+func (h *Handler) runMatch(ctx context.Context, chatID int64, userID shared.ID, ingredients []string, filters *ActiveFilters) {
+	input := command.MatchIngredientsInput{
+		UserID:         userID,
+		Ingredients:    ingredients,
+		CategoryFilter: filters.Category,
+		StrictMatch:    filters.PerfectOnly,
+		MaxMissing:     filters.MaxMissing,
+	}
+
+	result, err := h.matchIngredientsCommand.Execute(ctx, input)
+	if err != nil {
+		log.Printf("Error matching ingredients: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to match ingredients. Please try again.")
+		return
+	}
+
+	msg := FormatMatchResults(result)
+	if desc := describeMatchFilters(filters); desc != "" {
+		msg = "🔎 _" + escapeMarkdown(desc) + "_\n\n" + msg
+	}
+	keyboard := MatchResultsKeyboard(result, filters)
+	if len(keyboard.InlineKeyboard) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, msg)
+		return
+	}
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, msg, keyboard)
+}
+
+// refineMatch narrows or widens the last ingredient match and runs it again
+func (h *Handler) refineMatch(ctx context.Context, chatID int64, userID shared.ID, apply func(*ActiveFilters)) bool {
+	convCtx := h.conversationManager.GetContext(userID)
+	if convCtx == nil || len(convCtx.LastMatchIngredients) == 0 {
+		return false
+	}
+
+	filters := &ActiveFilters{}
+	if convCtx.ActiveFilters != nil {
+		*filters = *convCtx.ActiveFilters
+	}
+	apply(filters)
+	h.conversationManager.SetActiveFilters(userID, filters)
+
+	h.runMatch(ctx, chatID, userID, convCtx.LastMatchIngredients, filters)
+	return true
+}
+
+// handleMatchFilter applies a match refinement button
+func (h *Handler) handleMatchFilter(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, payload string) {
+	apply := func(f *ActiveFilters) {
+		switch op, arg, _ := strings.Cut(payload, ":"); op {
+		case "perfect":
+			f.PerfectOnly, f.MaxMissing = true, 0
+		case "missing":
+			n, _ := strconv.Atoi(arg)
+			f.PerfectOnly, f.MaxMissing = false, n
+		case "cat":
+			category := recipe.ParseCategory(arg)
+			f.Category = &category
+		case "clear":
+			*f = ActiveFilters{}
 		}
 	}
 
-	return result, nil
+	if !h.refineMatch(ctx, cq.Message.Chat.ID, userID, apply) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This match has expired. Run /match again.")
+		return
+	}
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
 }
 
-// handleRepeatLast repeats the last action/query
-func (h *Handler) handleRepeatLast(ctx context.Context, chatID int64, userID shared.ID) {
+// handleExplainMatch shows how the ingredients of the last match were counted
+// against one of the matched recipes
+func (h *Handler) handleExplainMatch(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, recipeID recipe.RecipeID) {
 	convCtx := h.conversationManager.GetContext(userID)
-	if convCtx == nil {
+	if convCtx == nil || len(convCtx.LastMatchIngredients) == 0 {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This match has expired. Run /match again.")
+		return
+	}
+
+	match, err := h.matchIngredientsCommand.Explain(ctx, userID, convCtx.LastMatchIngredients, recipeID)
+	if errors.Is(err, shared.ErrRecipeNotFound) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This recipe no longer exists.")
+		return
+	}
+	if err != nil {
+		log.Printf("Error explaining match: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to explain the match. Please try again.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	_ = h.bot.SendMessage(ctx, cq.Message.Chat.ID, FormatMatchExplanation(match))
+}
+
+// handleShowMore shows more results from the previous query
+func (h *Handler) handleShowMore(ctx context.Context, chatID int64, userID shared.ID) {
+	convCtx := h.conversationManager.GetContext(userID)
+	if convCtx == nil || len(convCtx.LastRecipes) == 0 {
 		_ = h.bot.SendMessage(ctx, chatID,
-			"I don't have any previous action to repeat.\n\n"+
-				"Try something like:\n"+
+			"I don't have any previous results to show more of.\n\n"+
+				"Try searching for recipes first, like:\n"+
 				"• \"Show my recipes\"\n"+
 				"• \"Pasta recipes\"")
 		return
 	}
 
-	// Reset offset for repeat
-	h.conversationManager.SetContext(userID, &ConversationContext{
-		LastAction:           convCtx.LastAction,
-		LastRecipes:          convCtx.LastRecipes,
-		LastCategory:         convCtx.LastCategory,
-		LastSearchTerm:       convCtx.LastSearchTerm,
-		LastMatchIngredients: convCtx.LastMatchIngredients,
-		CurrentOffset:        0,
-	})
+	// Increment offset and get next page
+	pageSize := 10
+	newOffset := h.conversationManager.IncrementOffset(userID, pageSize)
+	recipes, hasMore := h.conversationManager.GetRemainingRecipes(userID, pageSize)
 
-	switch convCtx.LastAction {
-	case ActionListRecipes:
-		h.handleListRecipesNatural(ctx, chatID, userID, nil, "")
-	case ActionFilterCategory:
-		h.handleListRecipesNatural(ctx, chatID, userID, convCtx.LastCategory, "")
-	case ActionFilterIngredient:
-		h.handleSearchByIngredient(ctx, chatID, userID, convCtx.LastSearchTerm)
-	case ActionMatchIngredients:
-		h.handleMatchNatural(ctx, chatID, userID, convCtx.LastMatchIngredients)
-	default:
+	if len(recipes) == 0 {
 		_ = h.bot.SendMessage(ctx, chatID,
-			"I'm not sure what to repeat.\n\n"+
-				"Try a new search like \"pasta recipes\"")
+			"You've seen all the recipes from your last search.\n\n"+
+				"Try a different search or say \"show again\" to repeat.")
+		return
+	}
+
+	msg := fmt.Sprintf("📚 *More Recipes* (showing %d-%d of %d)\n\n",
+		newOffset-pageSize+1, newOffset-pageSize+len(recipes), len(convCtx.LastRecipes))
+
+	for i, recipeDTO := range recipes {
+		idx := newOffset - pageSize + i + 1
+		msg += fmt.Sprintf("%d. %s\n", idx, recipeDTO.DisplayName)
+		msg += fmt.Sprintf("   _%s_ | %s | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform, difficultyBadge(recipeDTO.Difficulty))
+	}
+
+	if hasMore {
+		msg += "\nSay \"show more\" for additional recipes"
+	} else {
+		msg += "\nThat's all! Say \"show again\" to see them from the beginning"
 	}
+	msg += "\nOr say \"details on #X\" to view a specific recipe"
+
+	_ = h.bot.SendMessage(ctx, chatID, msg)
 }
 
-// handleClarification sends a clarifying question to the user
-func (h *Handler) handleClarification(ctx context.Context, chatID int64, userID shared.ID, originalMessage string, intent *ports.Intent) {
-	// Set pending clarification in conversation manager
-	h.conversationManager.SetPendingClarification(userID, &PendingClarification{
-		OriginalMessage: originalMessage,
-		Question:        intent.ClarifyingQuestion,
-		Options:         intent.ClarifyingOptions,
+// handleShowDetails shows details of a specific recipe from the last results, picked by
+// its number or by words of its title. When a name fits several recipes the user picks one.
+func (h *Handler) handleShowDetails(ctx context.Context, chatID int64, userID shared.ID, recipeNumber int, recipeName string, lang user.Language) {
+	convCtx := h.conversationManager.GetContext(userID)
+	if convCtx == nil || len(convCtx.LastRecipes) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"I don't have any recent recipe results.\n\n"+
+				"Try searching for recipes first, like:\n"+
+				"• \"Show my recipes\"\n"+
+				"• \"Pasta recipes\"")
+		return
+	}
+
+	if recipeName != "" {
+		positions := recipesNamed(convCtx.LastRecipes, recipeName)
+		switch {
+		case len(positions) == 0:
+			_ = h.bot.SendMessage(ctx, chatID,
+				fmt.Sprintf("None of the %d recipes from your last search matches \"%s\".\n\n"+
+					"Try \"details on #1\" through \"details on #%d\"",
+					len(convCtx.LastRecipes), escapeMarkdown(recipeName), len(convCtx.LastRecipes)))
+			return
+		case len(positions) == 1:
+			recipeNumber = positions[0]
+		case recipeNumber >= 1 && recipeNumber <= len(positions):
+			// "the second salmon one"
+			recipeNumber = positions[recipeNumber-1]
+		default:
+			_ = h.bot.SendMessageWithKeyboard(ctx, chatID,
+				FormatRecipeChoice(recipeName, convCtx.LastRecipes, positions),
+				RecipeChoiceKeyboard(convCtx.LastRecipes, positions))
+			return
+		}
+	}
+
+	if recipeNumber < 1 || recipeNumber > len(convCtx.LastRecipes) {
+		_ = h.bot.SendMessage(ctx, chatID,
+			fmt.Sprintf("Recipe #%d not found. I have %d recipes from your last search.\n\n"+
+				"Try \"details on #1\" through \"details on #%d\"",
+				recipeNumber, len(convCtx.LastRecipes), len(convCtx.LastRecipes)))
+		return
+	}
+
+	recipeDTO := convCtx.LastRecipes[recipeNumber-1]
+	h.sendRecipeDetails(ctx, chatID, userID, recipeDTO, lang)
+
+	// Update context to track that user viewed a recipe
+	h.conversationManager.UpdateLastRecipes(userID, ActionViewRecipe, convCtx.LastRecipes)
+}
+
+// nameFillers are words that join a title to the author or date that tells it apart,
+// as in "the carbonara from pastaguy"
+var nameFillers = map[string]bool{"from": true, "by": true, "saved": true, "on": true, "de": true, "do": true, "da": true, "salva": true}
+
+// recipesNamed returns the 1-based positions of the recipes whose display name, the
+// title with the author or date when titles repeat, contains every word of a name,
+// ignoring case and punctuation
+func recipesNamed(recipes []*dto.RecipeDTO, name string) []int {
+	var words []string
+	for _, word := range nameWords(name) {
+		if !nameFillers[word] {
+			words = append(words, word)
+		}
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	var positions []int
+	for i, rec := range recipes {
+		title := strings.Join(nameWords(rec.DisplayName), " ")
+		named := true
+		for _, word := range words {
+			if !strings.Contains(title, word) {
+				named = false
+				break
+			}
+		}
+		if named {
+			positions = append(positions, i+1)
+		}
+	}
+	return positions
+}
+
+// nameWords splits a name into lowercase words, dropping punctuation but keeping the
+// dashes of dates like 2026-03-04
+func nameWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
 	})
+}
 
-	// Add the user's message to history
-	h.conversationManager.AddTurn(userID, "user", originalMessage)
+// handleRecipeDetailsButton shows the recipe picked from several that fit a name
+func (h *Handler) handleRecipeDetailsButton(ctx context.Context, cq *tgbotapi.CallbackQuery, usr *user.User, recipeID string) {
+	rec, err := h.listRecipesQuery.ExecuteByID(ctx, usr.ID(), shared.ID(recipeID))
+	if errors.Is(err, shared.ErrRecipeNotFound) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This recipe no longer exists.")
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading recipe %s: %v", recipeID, err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to load the recipe. Please try again.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	h.sendRecipeDetails(ctx, cq.Message.Chat.ID, usr.ID(), rec, usr.Language())
+}
+
+// sendRecipeDetails sends a recipe in the user's language, with the Simplify button when available.
+// Summaries from recipe lists are loaded in full first.
+func (h *Handler) sendRecipeDetails(ctx context.Context, chatID int64, userID shared.ID, recipeDTO *dto.RecipeDTO, lang user.Language) {
+	if recipeDTO.Summary {
+		full, err := h.listRecipesQuery.ExecuteByID(ctx, userID, shared.ID(recipeDTO.ID))
+		if err != nil {
+			log.Printf("Error loading recipe %s: %v", recipeDTO.ID, err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to load the recipe. Please try again.")
+			return
+		}
+		recipeDTO = full
+	}
+	h.conversationManager.SetLastViewed(userID, recipeDTO)
+
+	messageText := FormatRecipeDTOWithTranslation(recipeDTO, h.recipeTranslation(ctx, userID, recipeDTO, lang), lang, h.datesFor(ctx, userID, time.Now()))
+	if report, ok := h.dietReport(ctx, userID, recipeDTO); ok {
+		messageText += "\n\n" + FormatDietReport(report, lang)
+	}
+
+	if recipeDTO.CoverKey != "" {
+		h.sendCover(ctx, chatID, recipeDTO.CoverKey, recipeDTO.CoverGenerated, lang)
+	}
+
+	keyboard, ok := h.recipeKeyboard(recipeDTO, false, lang)
+	if !ok {
+		_ = h.bot.SendMessage(ctx, chatID, messageText)
+		return
+	}
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, messageText, keyboard)
+}
+
+// dietReport checks a recipe's ingredients against the diet the user keeps.
+// Returns false when they keep none.
+func (h *Handler) dietReport(ctx context.Context, userID shared.ID, recipeDTO *dto.RecipeDTO) (diet.Report, bool) {
+	if h.checkDietCommand == nil || len(recipeDTO.Ingredients) == 0 {
+		return diet.Report{}, false
+	}
+
+	names := make([]string, len(recipeDTO.Ingredients))
+	for i, ing := range recipeDTO.Ingredients {
+		names[i] = ing.Name
+	}
+	report, ok, err := h.checkDietCommand.Check(ctx, userID, names)
+	if err != nil {
+		log.Printf("Error checking diet: %v", err)
+		return diet.Report{}, false
+	}
+	return report, ok
+}
+
+// recipeKeyboard builds the buttons under a recipe: switching between its original
+// and simplified instructions, and generating a cover for a recipe whose source has
+// no image. Returns false when there are none.
+func (h *Handler) recipeKeyboard(recipeDTO *dto.RecipeDTO, simplified bool, lang user.Language) (tgbotapi.InlineKeyboardMarkup, bool) {
+	if recipeDTO.ID == "" {
+		return tgbotapi.InlineKeyboardMarkup{}, false
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if h.simplifyRecipeCommand != nil {
+		rows = append(rows, RecipeViewKeyboard(recipeDTO.ID, simplified, lang).InlineKeyboard...)
+	}
+	if h.generateCoverCommand != nil && recipeDTO.CoverKey == "" && recipe.SourceImageURL(recipeDTO.SourceURL) == "" {
+		rows = append(rows, CoverKeyboardRow(recipeDTO.ID, lang))
+	}
+	if len(rows) == 0 {
+		return tgbotapi.InlineKeyboardMarkup{}, false
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...), true
+}
+
+// sendCover sends a recipe's stored cover, saying when it was AI-generated
+func (h *Handler) sendCover(ctx context.Context, chatID int64, key string, aiGenerated bool, lang user.Language) {
+	if h.generateCoverCommand == nil {
+		return
+	}
+
+	image, err := h.generateCoverCommand.Image(ctx, key)
+	if err != nil {
+		log.Printf("Error loading cover %s: %v", key, err)
+		return
+	}
+	caption := ""
+	if aiGenerated {
+		caption = GetTranslations(lang).CoverCaption
+	}
+	if err := h.bot.SendPhoto(ctx, chatID, "cover.png", image, caption); err != nil {
+		log.Printf("Error sending cover %s: %v", key, err)
+	}
+}
+
+// handleGenerateCover draws a cover for a recipe whose source has no image and sends it
+func (h *Handler) handleGenerateCover(ctx context.Context, cq *tgbotapi.CallbackQuery, usr *user.User, recipeID recipe.RecipeID) {
+	if h.generateCoverCommand == nil || recipeID == "" {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	chatID := cq.Message.Chat.ID
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "🎨 Drawing a cover...")
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionUploadPhoto)()
+
+	cover, err := h.generateCoverCommand.Execute(ctx, usr.ID(), recipeID)
+	if err != nil {
+		if errors.Is(err, shared.ErrHasSourceImage) {
+			_ = h.bot.SendMessage(ctx, chatID, "This recipe already has a picture from its source.")
+			return
+		}
+		log.Printf("Error generating cover: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Couldn't generate a cover image. Please try again later.")
+		return
+	}
+
+	h.sendCover(ctx, chatID, cover.Key, cover.AIGenerated, usr.Language())
+}
+
+// recipeTranslation translates a recipe into the language of the reply when we have LLM:
+// into Portuguese, or into English when the recipe is known to be in another language.
+// It returns nil when the recipe should be shown as is.
+func (h *Handler) recipeTranslation(ctx context.Context, userID shared.ID, recipeDTO *dto.RecipeDTO, lang user.Language) *TranslatedRecipeDTO {
+	if h.llm == nil || !h.isEnabled(ctx, feature.FlagTranslation, userID) {
+		return nil
+	}
+
+	targetLang := "Portuguese"
+	if lang != user.LanguagePortuguese {
+		if recipeDTO.SourceLanguage == "" || recipeDTO.SourceLanguage == "en" {
+			return nil
+		}
+		targetLang = "English"
+	}
+
+	translated, err := h.translateRecipe(ctx, recipeDTO, targetLang)
+	if err != nil {
+		log.Printf("Translation error (showing original): %v", err)
+		return nil
+	}
+	return translated
+}
+
+// handleTranslateRecipe shows a recipe, by number or the last one viewed, translated into
+// a language, the user's own when none is named
+func (h *Handler) handleTranslateRecipe(ctx context.Context, chatID int64, userID shared.ID, recipeNumber int, language string, lang user.Language) {
+	if h.llm == nil || !h.isEnabled(ctx, feature.FlagTranslation, userID) {
+		_ = h.bot.SendError(ctx, chatID, "Translation is not available.")
+		return
+	}
+
+	rec, ok := h.recipeByReference(ctx, chatID, userID, recipeNumber)
+	if !ok {
+		return
+	}
+
+	if language == "" {
+		language = "English"
+		if lang == user.LanguagePortuguese {
+			language = "Portuguese"
+		}
+	}
+
+	_ = h.bot.SendProgress(ctx, chatID, fmt.Sprintf("Translating to %s...", language))
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionTyping)()
+
+	translated, err := h.translateRecipe(ctx, rec, language)
+	if err != nil {
+		log.Printf("Error translating recipe: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to translate this recipe. Please try again.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, FormatRecipeDTOWithTranslation(rec, translated, lang, h.datesFor(ctx, userID, time.Now())))
+}
+
+// translateRecipe translates a recipe DTO to the target language using LLM
+func (h *Handler) translateRecipe(ctx context.Context, rec *dto.RecipeDTO, targetLang string) (*TranslatedRecipeDTO, error) {
+	// Build input for translation
+	input := &ports.RecipeTranslationInput{
+		Title:        rec.Title,
+		Ingredients:  make([]ports.IngredientData, len(rec.Ingredients)),
+		Instructions: make([]ports.InstructionData, len(rec.Instructions)),
+	}
+
+	for i, ing := range rec.Ingredients {
+		input.Ingredients[i] = ports.IngredientData{
+			Name:     ing.Name,
+			Quantity: ing.Quantity,
+			Unit:     ing.Unit,
+			Notes:    ing.Notes,
+		}
+	}
+
+	for i, inst := range rec.Instructions {
+		input.Instructions[i] = ports.InstructionData{
+			StepNumber: inst.StepNumber,
+			Text:       inst.Text,
+		}
+	}
+
+	// Call LLM for translation
+	output, err := h.llm.TranslateRecipe(ctx, input, targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to TranslatedRecipeDTO
+	result := &TranslatedRecipeDTO{
+		Title:        output.Title,
+		Ingredients:  make([]dto.IngredientDTO, len(output.Ingredients)),
+		Instructions: make([]dto.InstructionDTO, len(output.Instructions)),
+	}
+
+	for i, ing := range output.Ingredients {
+		result.Ingredients[i] = dto.IngredientDTO{
+			Name:     ing.Name,
+			Quantity: ing.Quantity,
+			Unit:     ing.Unit,
+			Notes:    ing.Notes,
+		}
+	}
+
+	for i, inst := range output.Instructions {
+		result.Instructions[i] = dto.InstructionDTO{
+			StepNumber: inst.StepNumber,
+			Text:       inst.Text,
+		}
+	}
+
+	return result, nil
+}
+
+// handleRepeatLast repeats the last action/query
+func (h *Handler) handleRepeatLast(ctx context.Context, chatID int64, userID shared.ID) {
+	convCtx := h.conversationManager.GetContext(userID)
+	if convCtx == nil {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"I don't have any previous action to repeat.\n\n"+
+				"Try something like:\n"+
+				"• \"Show my recipes\"\n"+
+				"• \"Pasta recipes\"")
+		return
+	}
+
+	// Reset offset for repeat
+	h.conversationManager.SetContext(userID, &ConversationContext{
+		LastAction:           convCtx.LastAction,
+		LastRecipes:          convCtx.LastRecipes,
+		LastCategory:         convCtx.LastCategory,
+		LastSearchTerm:       convCtx.LastSearchTerm,
+		LastAuthor:           convCtx.LastAuthor,
+		LastPlatform:         convCtx.LastPlatform,
+		LastFlavor:           convCtx.LastFlavor,
+		LastSavedPeriod:      convCtx.LastSavedPeriod,
+		LastMatchIngredients: convCtx.LastMatchIngredients,
+		LastViewedRecipe:     convCtx.LastViewedRecipe,
+		CurrentOffset:        0,
+	})
+
+	switch convCtx.LastAction {
+	case ActionListRecipes:
+		h.handleListRecipesNatural(ctx, chatID, userID, nil, "")
+	case ActionFilterCategory:
+		h.handleListRecipesNatural(ctx, chatID, userID, convCtx.LastCategory, "")
+	case ActionFilterIngredient:
+		h.handleSearchByIngredient(ctx, chatID, userID, convCtx.LastSearchTerm)
+	case ActionFilterAuthor:
+		h.handleRecipesByAuthor(ctx, chatID, userID, convCtx.LastAuthor)
+	case ActionFilterPlatform:
+		h.handleRecipesByPlatform(ctx, chatID, userID, string(convCtx.LastPlatform))
+	case ActionFilterFlavor:
+		h.handleRecipesByFlavor(ctx, chatID, userID, string(convCtx.LastFlavor))
+	case ActionFilterSaved:
+		h.handleRecipesSavedIn(ctx, chatID, userID, convCtx.LastSavedPeriod)
+	case ActionMatchIngredients:
+		h.handleMatchNatural(ctx, chatID, userID, convCtx.LastMatchIngredients)
+	default:
+		_ = h.bot.SendMessage(ctx, chatID,
+			"I'm not sure what to repeat.\n\n"+
+				"Try a new search like \"pasta recipes\"")
+	}
+}
+
+// handleClarification sends a clarifying question to the user
+func (h *Handler) handleClarification(ctx context.Context, chatID int64, userID shared.ID, originalMessage string, intent *ports.Intent) {
+	// Set pending clarification in conversation manager
+	pending := &PendingClarification{
+		OriginalMessage: originalMessage,
+		Question:        intent.ClarifyingQuestion,
+		Options:         intent.ClarifyingOptions,
+	}
+	h.conversationManager.SetPendingClarification(userID, pending)
+
+	// Add the user's message to history
+	h.conversationManager.AddTurn(userID, "user", originalMessage)
+
+	// Add the assistant's clarifying question to history, with its numbered options
+	// so a typed number is understood
+	question := intent.ClarifyingQuestion
+	if len(intent.ClarifyingOptions) > 0 {
+		question += "\n\nOptions:\n"
+		for i, option := range intent.ClarifyingOptions {
+			question += fmt.Sprintf("%d. %s\n", i+1, option)
+		}
+	}
+	h.conversationManager.AddTurn(userID, "assistant", question)
+
+	if len(intent.ClarifyingOptions) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, intent.ClarifyingQuestion)
+		return
+	}
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID,
+		intent.ClarifyingQuestion+"\n\nTap an option or type your own answer.",
+		ClarificationKeyboard(pending))
+}
+
+// handleClarificationButton answers the pending clarification with the option tapped.
+// Buttons of a question that was answered or replaced since do nothing.
+func (h *Handler) handleClarificationButton(ctx context.Context, cq *tgbotapi.CallbackQuery, usr *user.User, payload string) {
+	id, index, ok := parseClarificationChoice(payload)
+	pending := h.conversationManager.GetPendingClarification(usr.ID())
+	if !ok || pending == nil || pending.ID != id || index >= len(pending.Options) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This question was already answered.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	noButtons := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	_ = h.bot.EditMessageReplyMarkup(ctx, cq.Message.Chat.ID, cq.Message.MessageID, noButtons)
+	h.handleClarificationResponse(ctx, cq.Message.Chat.ID, usr.ID(), pending.Options[index], usr.Language())
+}
+
+// parseClarificationChoice parses the "<clarification id>:<option index>" payload of an option button
+func parseClarificationChoice(payload string) (uint64, int, bool) {
+	idText, indexText, found := strings.Cut(payload, ":")
+	if !found {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(idText, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	index, err := strconv.Atoi(indexText)
+	if err != nil || index < 0 {
+		return 0, 0, false
+	}
+	return id, index, true
+}
+
+// handleClarificationResponse handles the user's response to a clarifying question
+func (h *Handler) handleClarificationResponse(ctx context.Context, chatID int64, userID shared.ID, text string, lang user.Language) {
+	pending := h.conversationManager.GetPendingClarification(userID)
+	if pending == nil {
+		// No pending clarification, treat as normal message
+		h.conversationManager.SetState(userID, StateIdle)
+		return
+	}
+
+	// Clear the pending clarification
+	h.conversationManager.ClearPendingClarification(userID)
+
+	// Add the user's response to history
+	h.conversationManager.AddTurn(userID, "user", text)
+
+	// Check if the user selected an option by number
+	selectedText := text
+	if num, err := strconv.Atoi(strings.TrimSpace(text)); err == nil && num > 0 && num <= len(pending.Options) {
+		selectedText = pending.Options[num-1]
+	}
+
+	// Learn the answer, so the question can be skipped once it is the usual one
+	if h.learnClarificationsCommand != nil {
+		if err := h.learnClarificationsCommand.Record(ctx, userID, pending.OriginalMessage, selectedText); err != nil {
+			log.Printf("Error recording clarification choice: %v", err)
+		}
+	}
+
+	h.resolveClarification(ctx, chatID, userID, pending.OriginalMessage, selectedText, lang)
+}
+
+// answerWithUsualChoice answers the clarifying question asked about a message with
+// the user's usual choice instead of asking it, and reports whether there was one
+func (h *Handler) answerWithUsualChoice(ctx context.Context, chatID int64, usr *user.User, text string, intent *ports.Intent) bool {
+	if h.learnClarificationsCommand == nil {
+		return false
+	}
+	choice, ok := usr.UsualClarificationChoice(text)
+	if !ok {
+		return false
+	}
+
+	h.conversationManager.SetSkippedClarification(usr.ID(), &PendingClarification{
+		OriginalMessage: text,
+		Question:        intent.ClarifyingQuestion,
+		Options:         intent.ClarifyingOptions,
+	})
+	h.conversationManager.AddTurn(usr.ID(), "user", text)
+
+	t := GetTranslations(usr.Language())
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(t.UsualChoice, escapeMarkdown(choice)))
+
+	h.resolveClarification(ctx, chatID, usr.ID(), text, choice, usr.Language())
+	return true
+}
+
+// askSkippedClarification asks a question that was answered with the user's usual
+// choice after all, and forgets the choice so it is learned again
+func (h *Handler) askSkippedClarification(ctx context.Context, chatID int64, userID shared.ID, skipped *PendingClarification) {
+	if err := h.learnClarificationsCommand.Forget(ctx, userID, skipped.OriginalMessage); err != nil {
+		log.Printf("Error forgetting clarification choice: %v", err)
+	}
+
+	h.handleClarification(ctx, chatID, userID, skipped.OriginalMessage, &ports.Intent{
+		ClarifyingQuestion: skipped.Question,
+		ClarifyingOptions:  skipped.Options,
+	})
+}
+
+// isOptionsRequest reports whether a message asks to see the options of a question again
+func isOptionsRequest(text string) bool {
+	switch strings.Trim(strings.ToLower(strings.TrimSpace(text)), ".!?") {
+	case "options", "show options", "opções", "opcoes", "mostrar opções":
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveClarification detects the intent of a message answered with a choice and acts on it
+func (h *Handler) resolveClarification(ctx context.Context, chatID int64, userID shared.ID, originalMessage, choice string, lang user.Language) {
+	// Combine the original message with the clarification response for intent detection
+	combinedQuery := originalMessage + " " + choice
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionTyping)()
+
+	// Re-run intent detection with the combined context
+	if h.intentDetector != nil && h.isEnabled(ctx, feature.FlagIntentDetection, userID) {
+		history := h.conversationManager.ContextHistory(ctx, userID)
+		intent, err := h.intentDetector.DetectIntentWithContext(ctx, combinedQuery, history)
+		if err != nil {
+			log.Printf("Intent detection error after clarification: %v", err)
+		} else if intent != nil && intent.Type != ports.IntentUnknown && intent.Confidence >= 0.5 {
+			h.handleIntent(ctx, chatID, userID, intent, lang)
+			return
+		}
+	}
+
+	// Fallback: couldn't understand the clarification response
+	t := GetTranslations(lang)
+	_ = h.bot.SendMessage(ctx, chatID,
+		"I still couldn't understand that. Let's start over.\n\n"+
+			t.UseHelpCmd)
+}
+
+// handleRefine refines previous search results with new filters
+func (h *Handler) handleRefine(ctx context.Context, chatID int64, userID shared.ID, intent *ports.Intent, lang user.Language) {
+	// Refining an ingredient match runs it again on the same ingredients
+	if convCtx := h.conversationManager.GetContext(userID); convCtx != nil && convCtx.LastAction == ActionMatchIngredients {
+		refined := h.refineMatch(ctx, chatID, userID, func(f *ActiveFilters) {
+			if intent.Category != nil {
+				f.Category = intent.Category
+			}
+			if intent.PerfectOnly {
+				f.PerfectOnly, f.MaxMissing = true, 0
+			}
+			if intent.MaxMissing > 0 {
+				f.PerfectOnly, f.MaxMissing = false, intent.MaxMissing
+			}
+		})
+		if refined {
+			return
+		}
+	}
+
+	// Get active filters from conversation manager
+	activeFilters := h.conversationManager.GetActiveFilters(userID)
+
+	// Merge new intent filters with existing active filters
+	mergedFilters := &ActiveFilters{}
+	if activeFilters != nil {
+		// Copy existing filters
+		mergedFilters.Category = activeFilters.Category
+		mergedFilters.DietaryTags = append([]recipe.DietaryTag{}, activeFilters.DietaryTags...)
+		mergedFilters.IngredientFilter = activeFilters.IngredientFilter
+		mergedFilters.SearchTerm = activeFilters.SearchTerm
+		mergedFilters.Difficulty = activeFilters.Difficulty
+		mergedFilters.MaxMinutes = activeFilters.MaxMinutes
+	}
+
+	// Apply new filters from intent
+	if intent.Category != nil {
+		mergedFilters.Category = intent.Category
+	}
+	if len(intent.DietaryTags) > 0 {
+		// Add new dietary tags without duplicates
+		existingTags := make(map[recipe.DietaryTag]bool)
+		for _, tag := range mergedFilters.DietaryTags {
+			existingTags[tag] = true
+		}
+		for _, tag := range intent.DietaryTags {
+			if !existingTags[tag] {
+				mergedFilters.DietaryTags = append(mergedFilters.DietaryTags, tag)
+			}
+		}
+	}
+	if intent.IngredientFilter != nil {
+		// Merge ingredient filters
+		if mergedFilters.IngredientFilter == nil {
+			mergedFilters.IngredientFilter = intent.IngredientFilter
+		} else {
+			// Combine Include (AND logic)
+			mergedFilters.IngredientFilter.Include = append(
+				mergedFilters.IngredientFilter.Include,
+				intent.IngredientFilter.Include...,
+			)
+			// Combine Optional (OR logic)
+			mergedFilters.IngredientFilter.Optional = append(
+				mergedFilters.IngredientFilter.Optional,
+				intent.IngredientFilter.Optional...,
+			)
+			// Combine Exclude (NOT logic)
+			mergedFilters.IngredientFilter.Exclude = append(
+				mergedFilters.IngredientFilter.Exclude,
+				intent.IngredientFilter.Exclude...,
+			)
+		}
+	}
+	if intent.SearchTerm != "" {
+		mergedFilters.SearchTerm = intent.SearchTerm
+	}
+	if intent.Difficulty != nil {
+		mergedFilters.Difficulty = intent.Difficulty
+	}
+	if intent.MaxMinutes > 0 {
+		mergedFilters.MaxMinutes = intent.MaxMinutes
+	}
+
+	// Update active filters
+	h.conversationManager.SetActiveFilters(userID, mergedFilters)
+
+	// Re-execute the search with merged filters
+	h.runFilters(ctx, chatID, userID, mergedFilters)
+}
+
+// runFilters executes a search for a set of filters
+func (h *Handler) runFilters(ctx context.Context, chatID int64, userID shared.ID, filters *ActiveFilters) {
+	if filters.IngredientFilter != nil {
+		h.handleComplexSearch(ctx, chatID, userID, filters.IngredientFilter, filters.DietaryTags, filters.Difficulty, filters.MaxMinutes)
+	} else if filters.Category != nil || len(filters.DietaryTags) > 0 || filters.Difficulty != nil || filters.MaxMinutes > 0 {
+		h.handleCompoundQuery(ctx, chatID, userID, filters.Category, filters.DietaryTags, filters.Difficulty, filters.MaxMinutes)
+	} else if filters.SearchTerm != "" {
+		h.handleSearchByIngredient(ctx, chatID, userID, filters.SearchTerm)
+	} else {
+		// No filters to refine, just list recipes
+		h.handleListRecipesNatural(ctx, chatID, userID, nil, "")
+	}
+}
+
+// handleCompoundQuery handles queries combining category, dietary tags, difficulty and a time limit
+func (h *Handler) handleCompoundQuery(ctx context.Context, chatID int64, userID shared.ID, category *recipe.Category, dietaryTags []recipe.DietaryTag, difficulty *recipe.Difficulty, maxMinutes int) {
+	recipes, err := h.listRecipesQuery.ExecuteByFilters(ctx, userID, category, dietaryTags, "", difficulty)
+	if err != nil {
+		log.Printf("Error filtering recipes: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to filter recipes. Please try again.")
+		return
+	}
+	recipes = withinMinutes(recipes, maxMinutes)
+
+	// Build filter description
+	var filterParts []string
+	if difficulty != nil {
+		filterParts = append(filterParts, string(*difficulty))
+	}
+	if len(dietaryTags) > 0 {
+		for _, tag := range dietaryTags {
+			filterParts = append(filterParts, string(tag))
+		}
+	}
+	if category != nil {
+		filterParts = append(filterParts, string(*category))
+	}
+	if maxMinutes > 0 {
+		filterParts = append(filterParts, fmt.Sprintf("under %d min", maxMinutes))
+	}
+	filterDesc := strings.Join(filterParts, " ")
+	if filterDesc == "" {
+		filterDesc = "filtered"
+	}
+
+	// Store in conversation context
+	h.conversationManager.UpdateCategoryFilter(userID, category, recipes)
+	h.conversationManager.SetActiveFilters(userID, &ActiveFilters{
+		Category:    category,
+		DietaryTags: dietaryTags,
+		Difficulty:  difficulty,
+		MaxMinutes:  maxMinutes,
+	})
+
+	msg := fmt.Sprintf("📚 *%s Recipes* (%d found)\n\n", strings.Title(filterDesc), len(recipes))
+
+	if len(recipes) == 0 {
+		msg = fmt.Sprintf("📭 No recipes found matching: %s\n\n"+
+			"Try a different combination or use /categories to see what you have.", filterDesc)
+	} else {
+		for i, recipeDTO := range recipes {
+			if i >= 10 {
+				msg += fmt.Sprintf("\n... and %d more recipes. Say \"show more\" to see them.", len(recipes)-10)
+				break
+			}
+
+			msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.DisplayName)
+			msg += fmt.Sprintf("   _%s_ | %s | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform, difficultyBadge(recipeDTO.Difficulty))
+		}
+
+		if len(recipes) <= 10 {
+			msg += "\nSay \"details on #X\" to view a recipe"
+		}
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, msg)
+}
+
+// handleComplexSearch handles complex ingredient searches with filters, dietary tags, difficulty and a time limit
+func (h *Handler) handleComplexSearch(ctx context.Context, chatID int64, userID shared.ID, filter *recipe.IngredientFilter, dietaryTags []recipe.DietaryTag, difficulty *recipe.Difficulty, maxMinutes int) {
+	recipes, err := h.listRecipesQuery.SearchByIngredientFilterWithTags(ctx, userID, filter, dietaryTags, difficulty)
+	if err != nil {
+		log.Printf("Error searching recipes with filter: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to search recipes. Please try again.")
+		return
+	}
+	recipes = withinMinutes(recipes, maxMinutes)
+
+	// Build filter description
+	var filterParts []string
+	if filter != nil {
+		if len(filter.Include) > 0 {
+			filterParts = append(filterParts, "with "+strings.Join(filter.Include, " and "))
+		}
+		if len(filter.Optional) > 0 {
+			filterParts = append(filterParts, "with any of: "+strings.Join(filter.Optional, ", "))
+		}
+		if len(filter.Exclude) > 0 {
+			filterParts = append(filterParts, "without "+strings.Join(filter.Exclude, ", "))
+		}
+	}
+	if len(dietaryTags) > 0 {
+		for _, tag := range dietaryTags {
+			filterParts = append(filterParts, string(tag))
+		}
+	}
+	if difficulty != nil {
+		filterParts = append(filterParts, string(*difficulty))
+	}
+	if maxMinutes > 0 {
+		filterParts = append(filterParts, fmt.Sprintf("under %d min", maxMinutes))
+	}
+	filterDesc := strings.Join(filterParts, ", ")
+	if filterDesc == "" {
+		filterDesc = "filtered"
+	}
+
+	// Store in conversation context
+	h.conversationManager.UpdateLastRecipes(userID, ActionFilterIngredient, recipes)
+	h.conversationManager.SetActiveFilters(userID, &ActiveFilters{
+		DietaryTags:      dietaryTags,
+		IngredientFilter: filter,
+		Difficulty:       difficulty,
+		MaxMinutes:       maxMinutes,
+	})
+
+	msg := fmt.Sprintf("🔍 *Recipes %s* (%d found)\n\n", filterDesc, len(recipes))
+
+	if len(recipes) == 0 {
+		msg = fmt.Sprintf("📭 No recipes found matching: %s\n\n"+
+			"Try a different combination or use /recipes to see all your recipes.", filterDesc)
+	} else {
+		for i, recipeDTO := range recipes {
+			if i >= 10 {
+				msg += fmt.Sprintf("\n... and %d more recipes. Say \"show more\" to see them.", len(recipes)-10)
+				break
+			}
+
+			msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.DisplayName)
+			msg += fmt.Sprintf("   _%s_ | %s | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform, difficultyBadge(recipeDTO.Difficulty))
+		}
+
+		if len(recipes) <= 10 {
+			msg += "\nSay \"details on #X\" to view a recipe"
+		}
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, msg)
+}
+
+// handlePantryNatural handles natural language pantry management
+func (h *Handler) handlePantryNatural(ctx context.Context, chatID int64, userID shared.ID, action ports.PantryAction, items []string) {
+	userID, ok := h.pantryOwner(ctx, chatID, userID)
+	if !ok {
+		return
+	}
+
+	switch action {
+	case ports.PantryActionAdd:
+		if len(items) == 0 {
+			_ = h.bot.SendMessage(ctx, chatID,
+				"What would you like to add to your pantry?\n\n"+
+					"Example: \"add chicken and rice to pantry\"")
+			return
+		}
+		pantry, err := h.managePantryCommand.AddItems(ctx, userID, items)
+		if err != nil {
+			log.Printf("Error adding pantry items: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to add items. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID,
+			fmt.Sprintf("✅ Added %d item(s) to your pantry.\n\nYour pantry now has %d items.\nSay \"what can I make\" to find recipes!",
+				len(items), len(pantry.Items)))
+
+	case ports.PantryActionRemove:
+		if len(items) == 0 {
+			_ = h.bot.SendMessage(ctx, chatID,
+				"What would you like to remove from your pantry?\n\n"+
+					"Example: \"remove chicken from pantry\"")
+			return
+		}
+		pantry, err := h.managePantryCommand.RemoveItems(ctx, userID, items)
+		if err != nil {
+			log.Printf("Error removing pantry items: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to remove items. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID,
+			fmt.Sprintf("✅ Removed item(s) from your pantry.\n\nYour pantry now has %d items.",
+				len(pantry.Items)))
+
+	case ports.PantryActionClear:
+		err := h.managePantryCommand.ClearPantry(ctx, userID)
+		if err != nil {
+			log.Printf("Error clearing pantry: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to clear pantry. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, "✅ Your pantry has been cleared.")
+
+	default: // PantryActionShow
+		h.handlePantryShow(ctx, chatID, userID)
+	}
+}
+
+// handleRecipeLink processes a recipe link
+func (h *Handler) handleRecipeLink(ctx context.Context, chatID int64, userID shared.ID, url string, lang user.Language) {
+	// Links are limited per hour, with a higher limit for premium users
+	if h.linkQuota != nil {
+		if wait := h.linkQuota.Allow(ctx, userID, time.Now()); wait > 0 {
+			msg := fmt.Sprintf("⏳ You've sent as many recipe links as you can this hour. Try again in %s.", formatETA(wait))
+			if h.premiumCommand != nil {
+				msg += "\n\nPremium raises the limit: /premium"
+			}
+			_ = h.bot.SendMessage(ctx, chatID, msg)
+			return
+		}
+	}
+
+	h.recordUsage(ctx, analytics.FeatureRecipeLink)
+
+	platform := recipe.DetectPlatform(url)
+	started := time.Now()
+
+	// Send initial acknowledgment, with how long links of the platform usually take
+	ack := "🔍 Processing your recipe link...\n\nThis may take a minute."
+	if h.durations != nil {
+		if usual, ok := h.durations.Average(string(platform)); ok {
+			ack = fmt.Sprintf("🔍 Processing your recipe link...\n\n%s links usually take ~%s.", platformName(platform), formatETA(usual))
+			ctx = withETA(ctx, started.Add(usual))
+		}
+	}
+	_ = h.bot.SendMessage(ctx, chatID, ack)
+
+	// Process the recipe
+	recipe, err := h.processRecipeLinkCommand.Execute(ctx, url, userID, chatID)
+	// Recipes already saved from the link come back at once and would skew the average
+	extracted := (err == nil && !recipe.CreatedAt().Before(started)) ||
+		errors.Is(err, shared.ErrMultipleRecipes) || errors.Is(err, shared.ErrImplausibleClaims)
+	if h.durations != nil && extracted {
+		h.durations.Record(string(platform), time.Since(started))
+	}
+	if h.telemetry != nil {
+		h.telemetry.RecordExtraction(string(platform),
+			err == nil || errors.Is(err, shared.ErrMultipleRecipes) || errors.Is(err, shared.ErrImplausibleClaims))
+	}
+	if errors.Is(err, shared.ErrMultipleRecipes) {
+		found := h.processRecipeLinkCommand.Pending(userID)
+		_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatCompilation(found), CompilationKeyboard(found))
+		return
+	}
+	if errors.Is(err, shared.ErrImplausibleClaims) {
+		held, issues := h.processRecipeLinkCommand.Held(userID)
+		if held != nil {
+			_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatClaimCheck(held, issues), ClaimCheckKeyboard())
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("Error processing recipe: %v", err)
+		errorMsg := h.formatError(err, lang)
+		if h.reportErrorCommand != nil {
+			h.reportErrorCommand.RecordFailure(userID, url, err)
+			errorMsg += "\n\n" + GetTranslations(lang).ReportHint
+		}
+		_ = h.bot.SendError(ctx, chatID, errorMsg)
+		return
+	}
+
+	h.recordActivity(ctx, userID, activity.ActionRecipeSaved, recipe.Title())
+
+	// Recipes saved in a forum topic named after a category, such as "Desserts", are filed under it
+	if category, ok := topicCategory(topicFrom(ctx)); ok && h.categorizeRecipeCommand != nil {
+		if filed, err := h.categorizeRecipeCommand.Execute(ctx, userID, recipe.ID(), category); err != nil {
+			log.Printf("Error filing recipe under topic category: %v", err)
+		} else {
+			recipe = filed
+		}
+	}
+
+	// Send the formatted recipe
+	if err := h.bot.SendRecipe(ctx, chatID, recipe); err != nil {
+		log.Printf("Error sending recipe: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to send recipe. Please try again.")
+	}
+}
+
+// handleCompilationSave saves one recipe found in a compilation, or all of them,
+// leaving buttons for those not saved yet
+func (h *Handler) handleCompilationSave(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, payload string) {
+	chatID := cq.Message.Chat.ID
+
+	index := -1
+	if payload != "all" {
+		var err error
+		if index, err = strconv.Atoi(payload); err != nil {
+			_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+			return
+		}
+	}
+
+	saved, err := h.processRecipeLinkCommand.SavePending(ctx, userID, index)
+	if errors.Is(err, shared.ErrNoPendingRecipes) || errors.Is(err, shared.ErrInvalidInput) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "These recipes are no longer available. Send the link again.")
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving compilation recipes: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to save. Please try again.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "Saved")
+	for _, rec := range saved {
+		h.recordActivity(ctx, userID, activity.ActionRecipeSaved, rec.Title())
+	}
+
+	keyboard := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	if remaining := h.processRecipeLinkCommand.Pending(userID); len(remaining) > 0 {
+		keyboard = CompilationKeyboard(remaining)
+	}
+	_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, keyboard)
+
+	if len(saved) == 1 {
+		if err := h.bot.SendRecipe(ctx, chatID, saved[0]); err != nil {
+			log.Printf("Error sending recipe: %v", err)
+		}
+		return
+	}
+
+	titles := make([]string, len(saved))
+	for i, rec := range saved {
+		titles[i] = "• " + escapeMarkdown(rec.Title())
+	}
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("✅ Saved %d recipes:\n%s\n\nUse /recipes to see them\\.", len(saved), strings.Join(titles, "\n")))
+}
+
+// handleClaimCheck saves the recipe held for its implausible claims, as it is or
+// fixed, or discards it
+func (h *Handler) handleClaimCheck(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, payload string) {
+	chatID := cq.Message.Chat.ID
+	noButtons := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+
+	if payload == "discard" {
+		h.processRecipeLinkCommand.DiscardHeld(userID)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Discarded")
+		_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, noButtons)
+		return
+	}
+
+	rec, err := h.processRecipeLinkCommand.SaveHeld(ctx, userID, payload == "fix")
+	if errors.Is(err, shared.ErrNoHeldRecipe) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This recipe is no longer available. Send the link again.")
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving checked recipe: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to save the recipe. Please try again.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "Saved")
+	h.recordActivity(ctx, userID, activity.ActionRecipeSaved, rec.Title())
+	_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, noButtons)
+	if err := h.bot.SendRecipe(ctx, chatID, rec); err != nil {
+		log.Printf("Error sending recipe: %v", err)
+	}
+}
+
+// handleGetRecipe shows a specific recipe by number
+func (h *Handler) handleGetRecipe(ctx context.Context, message *tgbotapi.Message, userID shared.ID, lang user.Language) {
+	chatID := message.Chat.ID
+	args := message.CommandArguments()
+
+	if args == "" {
+		_ = h.bot.SendMessage(ctx, chatID, "Please specify a recipe number.\n\nUsage: /recipe <number>\nExample: /recipe 1\n\nUse /recipes to see your recipe list.")
+		return
+	}
+
+	index, err := strconv.Atoi(strings.TrimSpace(args))
+	if err != nil {
+		_ = h.bot.SendMessage(ctx, chatID, "Invalid recipe number. Please use a number like: /recipe 1")
+		return
+	}
+
+	recipeDTO, err := h.listRecipesQuery.ExecuteByIndex(ctx, userID, index)
+	if err != nil {
+		log.Printf("Error getting recipe: %v", err)
+		_ = h.bot.SendError(ctx, chatID, err.Error())
+		return
+	}
+
+	h.sendRecipeDetails(ctx, chatID, userID, recipeDTO, lang)
+}
+
+// handleListRecipes lists user's recipes, optionally filtered by category
+func (h *Handler) handleListRecipes(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+
+	var recipes []*dto.RecipeDTO
+	var err error
+	var categoryFilter string
+
+	if difficulty, ok := recipe.ParseDifficulty(args); ok {
+		// Filter by difficulty
+		categoryFilter = TranslateDifficulty(string(difficulty), user.LanguageEnglish)
+		recipes, err = h.listRecipesQuery.ExecuteByFilters(ctx, userID, nil, nil, "", &difficulty)
+	} else if args != "" {
+		// Filter by category
+		category := recipe.ParseCategory(args)
+		categoryFilter = string(category)
+		recipes, err = h.listRecipesQuery.ExecuteByCategory(ctx, userID, category)
+	} else {
+		// List all recipes
+		recipes, err = h.listRecipesQuery.Execute(ctx, userID)
+	}
+
+	if err != nil {
+		log.Printf("Error listing recipes: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to list recipes\\. Please try again\\.")
+		return
+	}
+
+	var msg string
+	if categoryFilter != "" {
+		msg = fmt.Sprintf("📚 *%s Recipes* \\(%d found\\)\n\n", escapeMarkdown(categoryFilter), len(recipes))
+	} else {
+		msg = fmt.Sprintf("📚 *Your Recipes* \\(%d total\\)\n\n", len(recipes))
+	}
+
+	if len(recipes) == 0 {
+		if categoryFilter != "" {
+			msg = fmt.Sprintf("📭 No recipes found in category: %s\n\nUse /categories to see available categories\\.", escapeMarkdown(categoryFilter))
+		} else {
+			msg = "📭 You don't have any saved recipes yet\\.\n\nSend me a link to get started\\!"
+		}
+	} else {
+		for i, recipeDTO := range recipes {
+			if i >= 10 {
+				msg += fmt.Sprintf("\n\\.\\.\\. and %d more recipes", len(recipes)-10)
+				break
+			}
+
+			msg += fmt.Sprintf("%d\\. %s\n", i+1, escapeMarkdown(recipeDTO.DisplayName))
+			msg += fmt.Sprintf("   _%s_ \\| %s \\| %s\n", escapeMarkdown(recipeDTO.Category), recipeDTO.SourcePlatform, difficultyBadge(recipeDTO.Difficulty))
+		}
+
+		msg += "\nUse /recipe <number> to view details"
+		if categoryFilter == "" {
+			msg += "\nUse /recipes <category> to filter"
+		}
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, msg)
+}
+
+// handleCategories shows recipe category counts
+func (h *Handler) handleCategories(ctx context.Context, chatID int64, userID shared.ID) {
+	counts, err := h.listRecipesQuery.GetCategoryCounts(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting category counts: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to get categories\\. Please try again\\.")
+		return
+	}
+
+	// Calculate total
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	if total == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "📭 You don't have any saved recipes yet\\.\n\nSend me a link to get started\\!")
+		return
+	}
+
+	message := FormatCategories(counts, total)
+	_ = h.bot.SendMessage(ctx, chatID, message)
+}
+
+// handleMatch handles the /match command for ingredient matching
+func (h *Handler) handleMatch(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+
+	// If no ingredients provided, check if user has pantry items
+	if args == "" {
+		owner, ok := h.pantryOwner(ctx, chatID, userID)
+		if !ok {
+			return
+		}
+		pantry, err := h.managePantryCommand.GetPantry(ctx, owner)
+		if err != nil || len(pantry.Items) == 0 {
+			_ = h.bot.SendMessage(ctx, chatID,
+				"Please provide ingredients to match\\.\n\n"+
+					"*Usage:* /match chicken, pasta, garlic\n"+
+					"*Or:* Add items to your pantry with /pantry add")
+			return
+		}
+		args = strings.Join(pantry.Items, ", ")
+	}
+
+	// Parse ingredients from comma-separated list
+	ingredients := parseIngredientList(args)
+	if len(ingredients) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "Please provide at least one ingredient\\.\n\nExample: /match chicken, pasta, garlic")
+		return
+	}
+
+	// Check for flags
+	strictMatch := strings.Contains(args, "--strict")
+	var categoryFilter *recipe.Category
+	if strings.Contains(args, "--category") {
+		// Extract category from args (simple parsing)
+		parts := strings.Split(args, "--category")
+		if len(parts) > 1 {
+			catParts := strings.Fields(parts[1])
+			if len(catParts) > 0 {
+				cat := recipe.ParseCategory(catParts[0])
+				categoryFilter = &cat
+			}
+		}
+	}
+
+	// Store ingredients and flags in conversation context for refinement
+	h.conversationManager.UpdateMatchIngredients(userID, ingredients)
+	filters := &ActiveFilters{Category: categoryFilter, PerfectOnly: strictMatch}
+	h.conversationManager.SetActiveFilters(userID, filters)
+
+	h.runMatch(ctx, chatID, userID, ingredients, filters)
+}
+
+// handlePantry handles the /pantry command for pantry management
+func (h *Handler) handlePantry(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+
+	userID, ok := h.pantryOwner(ctx, chatID, userID)
+	if !ok {
+		return
+	}
+
+	// Parse subcommand
+	parts := strings.SplitN(args, " ", 2)
+	subcommand := ""
+	itemsArg := ""
+	if len(parts) > 0 {
+		subcommand = strings.ToLower(parts[0])
+	}
+	if len(parts) > 1 {
+		itemsArg = parts[1]
+	}
+
+	switch subcommand {
+	case "":
+		// Show current pantry
+		h.handlePantryShow(ctx, chatID, userID)
+
+	case "add":
+		h.handlePantryAdd(ctx, chatID, userID, itemsArg)
+
+	case "remove":
+		h.handlePantryRemove(ctx, chatID, userID, itemsArg)
+
+	case "clear":
+		h.handlePantryClear(ctx, chatID, userID)
+
+	default:
+		// Treat as items to add if no recognized subcommand
+		h.handlePantryAdd(ctx, chatID, userID, args)
+	}
+}
+
+// handleStaples shows or changes the ingredients the user always has, which
+// recipe matches do not count as missing
+func (h *Handler) handleStaples(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+
+	if h.staplesCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Pantry staples are not available\\.")
+		return
+	}
+
+	subcommand, itemsArg, _ := strings.Cut(strings.TrimSpace(message.CommandArguments()), " ")
+	items := parseIngredientList(itemsArg)
+
+	var staples []string
+	var err error
+	switch strings.ToLower(subcommand) {
+	case "":
+		staples, err = h.staplesCommand.Staples(ctx, userID)
+	case "add", "remove":
+		if len(items) == 0 {
+			_ = h.bot.SendMessage(ctx, chatID,
+				fmt.Sprintf("Please specify the staples to %s\\.\n\n*Usage:* /staples %s salt, olive oil", subcommand, subcommand))
+			return
+		}
+		if strings.EqualFold(subcommand, "add") {
+			staples, err = h.staplesCommand.Add(ctx, userID, items)
+		} else {
+			staples, err = h.staplesCommand.Remove(ctx, userID, items)
+		}
+	case "reset":
+		staples, err = h.staplesCommand.Reset(ctx, userID)
+	default:
+		_ = h.bot.SendMessage(ctx, chatID, "*Usage:* /staples add salt, olive oil · /staples remove sugar · /staples reset")
+		return
+	}
+	if err != nil {
+		log.Printf("Error managing staples: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to update your staples\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, FormatStaples(staples))
+}
+
+// handlePantryShow shows the user's pantry
+func (h *Handler) handlePantryShow(ctx context.Context, chatID int64, userID shared.ID) {
+	pantry, err := h.managePantryCommand.GetPantryByAisle(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting pantry: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to get pantry\\. Please try again\\.")
+		return
+	}
+
+	msg := FormatPantry(pantry.Items, pantry.Aisles)
+	_ = h.bot.SendMessage(ctx, chatID, msg)
+}
+
+// handlePantryAdd adds items to the pantry
+func (h *Handler) handlePantryAdd(ctx context.Context, chatID int64, userID shared.ID, itemsArg string) {
+	if itemsArg == "" {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"Please specify items to add\\.\n\n"+
+				"*Usage:* /pantry add butter, eggs, milk")
+		return
+	}
+
+	items := parseIngredientList(itemsArg)
+	if len(items) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "Please provide at least one item to add\\.")
+		return
+	}
+
+	pantry, err := h.managePantryCommand.AddItems(ctx, userID, items)
+	if err != nil {
+		log.Printf("Error adding pantry items: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to add items\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID,
+		fmt.Sprintf("✅ Added %d item\\(s\\) to your pantry\\.\n\nYour pantry now has %d items\\.\nUse /match to find recipes\\!",
+			len(items), len(pantry.Items)))
+}
+
+// handlePantryRemove removes items from the pantry
+func (h *Handler) handlePantryRemove(ctx context.Context, chatID int64, userID shared.ID, itemsArg string) {
+	if itemsArg == "" {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"Please specify items to remove\\.\n\n"+
+				"*Usage:* /pantry remove butter, eggs")
+		return
+	}
+
+	items := parseIngredientList(itemsArg)
+	if len(items) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "Please provide at least one item to remove\\.")
+		return
+	}
+
+	pantry, err := h.managePantryCommand.RemoveItems(ctx, userID, items)
+	if err != nil {
+		log.Printf("Error removing pantry items: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to remove items\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID,
+		fmt.Sprintf("✅ Removed item\\(s\\) from your pantry\\.\n\nYour pantry now has %d items\\.",
+			len(pantry.Items)))
+}
+
+// handlePantryClear clears all pantry items
+func (h *Handler) handlePantryClear(ctx context.Context, chatID int64, userID shared.ID) {
+	err := h.managePantryCommand.ClearPantry(ctx, userID)
+	if err != nil {
+		log.Printf("Error clearing pantry: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to clear pantry\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, "✅ Your pantry has been cleared\\.")
+}
+
+// handlePhoto reads the product barcode in a photo and adds the product to the pantry
+func (h *Handler) handlePhoto(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+	userID := usr.ID()
+
+	// Reading a photo takes a while, whatever it shows
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionTyping)()
+
+	// A dish photo captioned "recreate this" asks for a recipe, not a barcode scan
+	if recreateDishPattern.MatchString(message.Caption) {
+		h.handleRecreateDish(ctx, message, usr)
+		return
+	}
+
+	// A fridge or shelf photo captioned "pantry" lists the items on it
+	if pantryPhotoPattern.MatchString(message.Caption) {
+		h.handlePantryPhoto(ctx, message, usr)
+		return
+	}
+
+	if h.scanBarcodeCommand == nil {
+		_ = h.bot.SendMessage(ctx, chatID, "📷 Photos are not supported yet\\. Send me a recipe link instead\\.")
+		return
+	}
+
+	// Telegram sends several sizes, the last one is the largest
+	photo := message.Photo[len(message.Photo)-1]
+	data, err := h.bot.DownloadFile(ctx, photo.FileID)
+	if err != nil {
+		log.Printf("Error downloading photo: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to download the photo\\. Please try again\\.")
+		return
+	}
+
+	scanned, err := h.scanBarcodeCommand.Execute(ctx, userID, data)
+	switch {
+	case errors.Is(err, shared.ErrNoBarcode):
+		_ = h.bot.SendMessage(ctx, chatID,
+			"🔍 I couldn't find a barcode in that photo\\.\n\n"+
+				"Try again with the barcode filling most of the picture, straight and in good light\\.")
+		return
+	case errors.Is(err, shared.ErrProductNotFound):
+		_ = h.bot.SendMessage(ctx, chatID,
+			fmt.Sprintf("🤷 Barcode %s is not in Open Food Facts yet\\.\n\nAdd it by name instead: /pantry add <item>",
+				scanned.Product.Barcode))
+		return
+	case err != nil:
+		log.Printf("Error scanning barcode: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to add the product\\. Please try again\\.")
+		return
+	}
+
+	product := escapeMarkdown(scanned.Product.Name)
+	if scanned.Product.Brand != "" {
+		product += " · " + escapeMarkdown(scanned.Product.Brand)
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID,
+		fmt.Sprintf("✅ Added *%s* to your pantry\\.\n\n📦 %s\n\nYour pantry now has %d items\\.",
+			escapeMarkdown(scanned.Item), product, len(scanned.Pantry.Items)))
+}
+
+// recreateDishPattern matches photo captions asking to recreate the dish in the photo
+var recreateDishPattern = regexp.MustCompile(`(?i)\b(?:recreate|reverse[- ]engineer|recri(?:ar|e)|refazer|refa[çc]a)\b`)
+
+// handleRecreateDish proposes a recipe for the dish in a photo, labeled as AI-generated.
+// Unlike recipes from links, the proposal is only saved when the user asks.
+func (h *Handler) handleRecreateDish(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+
+	if h.recreateDishCommand == nil {
+		_ = h.bot.SendMessage(ctx, chatID, "📷 Recreating dishes from photos is not available\\.")
+		return
+	}
+
+	photo := message.Photo[len(message.Photo)-1]
+	data, err := h.bot.DownloadFile(ctx, photo.FileID)
+	if err != nil {
+		log.Printf("Error downloading photo: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to download the photo\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, "🤖 Working out how to cook this dish...")
+
+	targetLang := "English"
+	if usr.Language() == user.LanguagePortuguese {
+		targetLang = "Portuguese"
+	}
+
+	rec, err := h.recreateDishCommand.Propose(ctx, usr.ID(), data, message.Caption, targetLang)
+	if err != nil {
+		log.Printf("Error recreating dish: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "I couldn't work out a recipe for that dish\\. Try a clearer photo\\.")
+		return
+	}
+
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatDishProposal(rec), DishProposalKeyboard())
+}
+
+// handleDishProposal saves or discards the recipe proposed from a dish photo
+func (h *Handler) handleDishProposal(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, save bool) {
+	if h.recreateDishCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	chatID := cq.Message.Chat.ID
+	noButtons := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+
+	if !save {
+		h.recreateDishCommand.Discard(userID)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Discarded")
+		_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, noButtons)
+		return
+	}
+
+	rec, err := h.recreateDishCommand.Save(ctx, userID)
+	if errors.Is(err, shared.ErrNoDishProposal) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This recipe is no longer available. Send the photo again.")
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving recreated dish: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to save the recipe. Please try again.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "Saved")
+	h.recordActivity(ctx, userID, activity.ActionRecipeSaved, rec.Title())
+	_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, noButtons)
+	_ = h.bot.SendMessage(ctx, chatID,
+		fmt.Sprintf("✅ Saved *%s* to your recipes as an AI\\-generated recipe\\.\n\nUse /recipe 1 to see it\\.", escapeMarkdown(rec.Title())))
+}
+
+// pantryPhotoPattern matches photo captions asking to add what is in the photo to the pantry
+var pantryPhotoPattern = regexp.MustCompile(`(?i)\b(?:pantry|fridge|shelf|despensa|geladeira|arm[áa]rio)\b`)
+
+// handlePantryPhoto lists the items recognized in a photo of the fridge or a pantry shelf
+// as a checklist the user confirms before they are added
+func (h *Handler) handlePantryPhoto(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+
+	if h.scanPantryPhotoCommand == nil {
+		_ = h.bot.SendMessage(ctx, chatID, "📷 Adding pantry items from photos is not available\\. Use /pantry add <items> instead\\.")
+		return
+	}
+
+	photo := message.Photo[len(message.Photo)-1]
+	data, err := h.bot.DownloadFile(ctx, photo.FileID)
+	if err != nil {
+		log.Printf("Error downloading photo: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to download the photo\\. Please try again\\.")
+		return
+	}
+
+	targetLang := "English"
+	if usr.Language() == user.LanguagePortuguese {
+		targetLang = "Portuguese"
+	}
+
+	owner, ok := h.pantryOwner(ctx, chatID, usr.ID())
+	if !ok {
+		return
+	}
+
+	items, err := h.scanPantryPhotoCommand.Recognize(ctx, owner, data, targetLang)
+	if errors.Is(err, shared.ErrNoItemsFound) {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"🔍 I couldn't recognize any food in that photo\\.\n\nTry again closer to the shelf and in good light\\.")
+		return
+	}
+	if err != nil {
+		log.Printf("Error recognizing pantry photo: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to read the photo\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatPantryPhoto(items), PantryPhotoKeyboard(items))
+}
+
+// handlePantryPhotoToggle checks or unchecks an item of the pantry photo checklist
+func (h *Handler) handlePantryPhotoToggle(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, payload string) {
+	index, err := strconv.Atoi(payload)
+	if err != nil || h.scanPantryPhotoCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	owner, ok := h.pantryOwner(ctx, cq.Message.Chat.ID, userID)
+	if !ok {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	items, err := h.scanPantryPhotoCommand.Toggle(owner, index)
+	if err != nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This list is out of date. Send the photo again.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	_ = h.bot.EditMessageWithKeyboard(ctx, cq.Message.Chat.ID, cq.Message.MessageID, FormatPantryPhoto(items), PantryPhotoKeyboard(items))
+}
+
+// handlePantryPhotoConfirm adds the checked items of the pantry photo checklist to the pantry
+func (h *Handler) handlePantryPhotoConfirm(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID) {
+	if h.scanPantryPhotoCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	chatID := cq.Message.Chat.ID
+
+	owner, ok := h.pantryOwner(ctx, chatID, userID)
+	if !ok {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	added, pantry, err := h.scanPantryPhotoCommand.Confirm(ctx, owner)
+	if errors.Is(err, shared.ErrNoPantryPhoto) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This list is out of date. Send the photo again.")
+		return
+	}
+	if err != nil {
+		log.Printf("Error adding pantry photo items: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to update your pantry. Please try again.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	noButtons := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, noButtons)
+
+	if len(added) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "Nothing was added\\. Your pantry is unchanged\\.")
+		return
+	}
+
+	names := make([]string, len(added))
+	for i, name := range added {
+		names[i] = escapeMarkdown(name)
+	}
+	_ = h.bot.SendMessage(ctx, chatID,
+		fmt.Sprintf("✅ Added %d items to your pantry: %s\\.\n\nYour pantry now has %d items\\.",
+			len(added), strings.Join(names, ", "), len(pantry.Items)))
+}
+
+// handleNutrition handles the /nutrition command
+func (h *Handler) handleNutrition(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if h.nutritionCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Nutrition data is not available\\.")
+		return
+	}
+
+	// Without a recipe number, show what is known about the pantry products
+	if len(args) == 0 {
+		products, err := h.nutritionCommand.PantryProducts(ctx, userID)
+		if err != nil {
+			log.Printf("Pantry nutrition error: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to load pantry nutrition\\. Please try again\\.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatPantryNutrition(products))
+		return
+	}
+
+	recipeID, ok := h.recipeIDByNumber(ctx, chatID, userID, args[0])
+	if !ok {
+		return
+	}
+
+	estimate, err := h.nutritionCommand.EstimateRecipe(ctx, userID, recipeID)
+	if err != nil {
+		log.Printf("Nutrition estimate error: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to estimate nutrition\\. Please try again\\.")
+		return
+	}
+
+	recipeNum, _ := strconv.Atoi(args[0])
+	_ = h.bot.SendMessage(ctx, chatID, FormatNutritionEstimate(recipeNum, estimate))
+}
+
+const macrosUsage = "Set daily goals with /macros 2000 kcal 150 protein 200 carbs 70 fat, any of them will do. " +
+	"/macros off stops tracking, and /ate <number> logs a recipe you ate."
+
+var (
+	// "2000 kcal", "150g protein"
+	macroAfterPattern = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*g?\s*\b(kcal|calories|cal|protein|carbs?|carbohydrates|fat)\b`)
+	// "kcal 2000", "protein: 150g"
+	macroBeforePattern = regexp.MustCompile(`\b(kcal|calories|cal|protein|carbs?|carbohydrates|fat)\b\s*[:=]?\s*(\d+(?:[.,]\d+)?)`)
+)
+
+// parseMacroGoals reads daily macro goals such as "2000 kcal 150 protein 70g fat"
+// or "protein 150, fat 70". Returns false if no goal is given.
+func parseMacroGoals(text string) (nutrition.Facts, bool) {
+	text = strings.ToLower(text)
+
+	var pairs [][2]string
+	if matches := macroAfterPattern.FindAllStringSubmatch(text, -1); matches != nil {
+		for _, m := range matches {
+			pairs = append(pairs, [2]string{m[2], m[1]})
+		}
+	} else {
+		for _, m := range macroBeforePattern.FindAllStringSubmatch(text, -1) {
+			pairs = append(pairs, [2]string{m[1], m[2]})
+		}
+	}
+
+	var goals nutrition.Facts
+	for _, pair := range pairs {
+		value, err := strconv.ParseFloat(strings.Replace(pair[1], ",", ".", 1), 64)
+		if err != nil {
+			continue
+		}
+		switch pair[0] {
+		case "kcal", "calories", "cal":
+			goals.EnergyKcal = value
+		case "protein":
+			goals.Protein = value
+		case "carb", "carbs", "carbohydrates":
+			goals.Carbohydrates = value
+		case "fat":
+			goals.Fat = value
+		}
+	}
+	return goals, len(pairs) > 0
+}
+
+// handleMacros handles the /macros command for daily macro goals
+func (h *Handler) handleMacros(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+
+	if h.nutritionCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Nutrition data is not available.")
+		return
+	}
+
+	dates := h.datesFor(ctx, userID, time.Now())
+	now := dates.local(dates.Now)
+
+	switch strings.ToLower(args) {
+	case "":
+		budget, err := h.nutritionCommand.Budget(ctx, userID, now)
+		if errors.Is(err, shared.ErrNoMacroGoals) {
+			_ = h.bot.SendMessage(ctx, chatID, "🎯 You have no daily macro goals yet.\n\n"+macrosUsage)
+			return
+		}
+		if err != nil {
+			log.Printf("Macro budget error: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to load your macro goals. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatMacroBudget(budget))
+		return
+
+	case "off", "clear", "stop":
+		if err := h.nutritionCommand.ClearGoals(ctx, userID); err != nil {
+			log.Printf("Clear macro goals error: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to clear your macro goals. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, "🎯 Stopped tracking macros. Dinner ideas no longer look at your goals.")
+		return
+	}
+
+	daily, ok := parseMacroGoals(args)
+	if !ok {
+		_ = h.bot.SendMessage(ctx, chatID, macrosUsage)
+		return
+	}
+
+	goals, err := h.nutritionCommand.SetGoals(ctx, userID, daily, now)
+	if errors.Is(err, shared.ErrInvalidMacroGoal) {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("Goals must be positive, with at most %d kcal a day.\n\n%s", nutrition.MaxDailyKcal, macrosUsage))
+		return
+	}
+	if err != nil {
+		log.Printf("Set macro goals error: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to save your macro goals. Please try again.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🎯 Daily goals set: %s\n\nDinner ideas will now fit what is left of them. Use /ate <number> to log what you eat.",
+		formatTrackedMacros(goals.Daily, goals.Daily)))
+}
+
+// handleAte handles the /ate command, logging a serving of a recipe as eaten now
+// and counting it as cooked at home toward the user's achievements
+func (h *Handler) handleAte(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+	userID := usr.ID()
+	args := strings.Fields(message.CommandArguments())
+
+	if h.nutritionCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Nutrition data is not available.")
+		return
+	}
+	if len(args) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "Usage: /ate <number> logs a serving of a recipe you ate today")
+		return
+	}
+
+	recipeID, ok := h.recipeIDByNumber(ctx, chatID, userID, args[0])
+	if !ok {
+		return
+	}
+
+	dates := h.datesFor(ctx, userID, time.Now())
+	now := dates.local(dates.Now)
+
+	estimate, err := h.nutritionCommand.LogMeal(ctx, userID, recipeID, now)
+	if err != nil {
+		log.Printf("Log meal error: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to log the meal. Please try again.")
+		return
+	}
+
+	// The budget only matters to users tracking macros
+	budget, err := h.nutritionCommand.Budget(ctx, userID, now)
+	if err != nil && !errors.Is(err, shared.ErrNoMacroGoals) {
+		log.Printf("Macro budget error: %v", err)
+	}
+	text := FormatLoggedMeal(estimate, budget)
+
+	if h.achievementsCommand != nil {
+		badges, err := h.achievementsCommand.RecordCooking(ctx, userID, recipeID, now)
+		if err != nil {
+			log.Printf("Record cooking error: %v", err)
+		} else if usr.WantsNotification(user.NotificationAchievements) {
+			text += FormatEarnedBadges(badges)
+		}
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, text)
+}
+
+// handleAchievements handles the /achievements command: shows the user's streak
+// and badges, or turns badge messages and monthly recaps on or off
+func (h *Handler) handleAchievements(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+
+	if h.achievementsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Achievements are not available.")
+		return
+	}
+
+	enabled := usr.WantsNotification(user.NotificationAchievements)
+	switch arg := strings.ToLower(strings.TrimSpace(message.CommandArguments())); arg {
+	case "":
+		// Show the achievements below
+
+	case "on", "off":
+		if h.notificationsCommand == nil {
+			_ = h.bot.SendError(ctx, chatID, "Notifications are not available.")
+			return
+		}
+		enabled = arg == "on"
+		if err := h.notificationsCommand.Set(ctx, usr.ID(), user.NotificationAchievements, enabled); err != nil {
+			log.Printf("Error updating notification settings: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to update your notification settings. Please try again.")
+			return
+		}
+		if enabled {
+			_ = h.bot.SendMessage(ctx, chatID, "🏅 Achievements on: I'll tell you when you earn a badge and send a recap of your cooking every month.")
+		} else {
+			_ = h.bot.SendMessage(ctx, chatID, "🏅 Achievements off: no more badge messages or monthly recaps. /achievements still shows your progress.")
+		}
+		return
+
+	default:
+		_ = h.bot.SendMessage(ctx, chatID, "Usage: /achievements shows your streak and badges, /achievements on or off turns badge messages and monthly recaps on or off")
+		return
+	}
+
+	cooking, err := h.achievementsCommand.Stats(ctx, usr.ID())
+	if err != nil {
+		log.Printf("Error loading cooking stats: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load your achievements. Please try again.")
+		return
+	}
+
+	dates := h.datesFor(ctx, usr.ID(), time.Now())
+	_ = h.bot.SendMessage(ctx, chatID, FormatAchievements(cooking, dates.local(dates.Now), enabled))
+}
+
+// handleLanguage handles the /language command for changing user language preference
+func (h *Handler) handleLanguage(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+	t := GetTranslations(usr.Language())
+
+	// If no argument, show current language and options
+	if args == "" {
+		_ = h.bot.SendMessage(ctx, chatID,
+			t.LanguageCurrent+"\n\n"+
+				t.LanguageChoose+"\n"+
+				"• /language en \\- "+t.LanguageEnglish+"\n"+
+				"• /language pt \\- "+t.LanguagePortuguese)
+		return
+	}
+
+	// Parse the requested language
+	newLang := user.ParseLanguage(args)
+
+	// Update user's language preference
+	usr.SetLanguage(newLang)
+	if h.userRepo != nil {
+		if err := h.userRepo.UpdateLanguage(ctx, usr.ID(), newLang); err != nil {
+			log.Printf("Error updating language: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to update language\\. Please try again\\.")
+			return
+		}
+	}
+
+	// Send confirmation in the NEW language
+	newT := GetTranslations(newLang)
+	_ = h.bot.SendMessage(ctx, chatID, "✅ "+newT.LanguageSet)
+}
+
+// parseIngredientList parses a comma-separated list of ingredients
+func parseIngredientList(input string) []string {
+	// Remove any flags
+	input = strings.ReplaceAll(input, "--strict", "")
+	if idx := strings.Index(input, "--category"); idx != -1 {
+		// Remove --category and its argument
+		endIdx := strings.Index(input[idx:], ",")
+		if endIdx == -1 {
+			input = input[:idx]
+		} else {
+			input = input[:idx] + input[idx+endIdx:]
+		}
+	}
+
+	// Split by comma
+	parts := strings.Split(input, ",")
+	var ingredients []string
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			ingredients = append(ingredients, part)
+		}
+	}
+	return ingredients
+}
+
+// formatError maps a recipe processing error to a message in the user's language.
+// More specific causes are checked first, as they are wrapped by the general ones.
+func (h *Handler) formatError(err error, lang user.Language) string {
+	t := GetTranslations(lang)
+
+	switch {
+	case errors.Is(err, shared.ErrLLMTimeout):
+		return t.LLMTimeout
+	case errors.Is(err, shared.ErrQuotaExceeded):
+		return t.QuotaExceeded
+	case errors.Is(err, shared.ErrScrapeFailed):
+		return t.ScrapeFailed
+	case errors.Is(err, shared.ErrNoContent):
+		return t.NoContentFound
+	case errors.Is(err, shared.ErrNoIngredients):
+		return t.NoIngredientsFound
+	case errors.Is(err, shared.ErrNoInstructions):
+		return t.NoInstructionsFound
+	case errors.Is(err, shared.ErrExtractionFailed):
+		return t.ExtractionFailed
+	default:
+		return t.ProcessingError
+	}
+}
+
+// handleExport handles the /export command
+func (h *Handler) handleExport(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+
+	if h.exportRecipeCommand == nil || !h.isEnabled(ctx, feature.FlagExport, userID) {
+		_ = h.bot.SendError(ctx, chatID, "Export functionality is not available.")
+		return
+	}
+
+	// Parse arguments: /export <format> [recipe_number] [--fields=<fields>]
+	parts, fields, ok := h.exportFieldsFlag(ctx, chatID, userID, strings.Fields(args))
+	if !ok {
+		return
+	}
+	if len(parts) == 0 {
+		// Show export help
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Export Recipes*\n\n"+
+				"*Usage:*\n"+
+				"/export obsidian \\- Export all recipes as Markdown\n"+
+				"/export obsidian <number> \\- Export a specific recipe\n"+
+				"/export notion \\- Export all to Notion\n"+
+				"/export notion <number> \\- Export specific recipe to Notion\n"+
+				"/export crouton \\- Export as Crouton \\.crumb files\n"+
+				"/export anylist \\- Export as text for AnyList\n"+
+				"/export whisk \\- Export source links for Whisk / Samsung Food\n"+
+				"/export cookbook \\- All recipes as a PDF cookbook\n"+
+				"/export fields \\- Choose what exports include\n\n"+
+				"Add --fields=ingredients,steps or a profile like --fields=shopping to export only some fields\n\n"+
+				"*Obsidian:* Downloads a \\.md file with YAML frontmatter, or saves it in Dropbox or Google Drive after /connect dropbox or drive\n"+
+				"*Notion:* Requires /connect notion first\n"+
+				"*Crouton, AnyList, Samsung Food:* Add a recipe number to export just one")
+		return
+	}
+
+	format := strings.ToLower(parts[0])
+	if format == "fields" {
+		h.handleExportFields(ctx, chatID, userID, parts[1:])
+		return
+	}
+	if format == "cookbook" || format == "pdf" {
+		h.exportCookbook(ctx, chatID, userID, fields)
+		return
+	}
+	var recipeID *shared.ID
+	exported := "all recipes"
+
+	// Check if a recipe number was specified
+	if len(parts) > 1 {
+		recipeNum, err := strconv.Atoi(parts[1])
+		if err != nil {
+			_ = h.bot.SendError(ctx, chatID, "Invalid recipe number\\. Use /export <format> <number>")
+			return
+		}
+
+		// Get recipe by index to find its ID
+		recipeDTO, err := h.listRecipesQuery.ExecuteByIndex(ctx, userID, recipeNum)
+		if err != nil {
+			_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Recipe #%d not found\\.", recipeNum))
+			return
+		}
+
+		id := shared.ID(recipeDTO.ID)
+		recipeID = &id
+		exported = recipeDTO.Title
+	}
+
+	h.exportRecipes(ctx, chatID, userID, format, recipeID, exported, fields)
+}
+
+// exportFieldsFlag takes a --fields=<fields> flag out of the arguments of /export or
+// /print and returns the other arguments with the fields to export: those of the
+// flag, or the user's defaults without one. It tells the user about unknown fields.
+func (h *Handler) exportFieldsFlag(ctx context.Context, chatID int64, userID shared.ID, args []string) ([]string, recipe.ExportFields, bool) {
+	var rest []string
+	var fields recipe.ExportFields
+	for _, arg := range args {
+		value, found := strings.CutPrefix(strings.ToLower(arg), "--fields=")
+		if !found {
+			rest = append(rest, arg)
+			continue
+		}
+		parsed, ok := recipe.ParseExportFields(value)
+		if !ok {
+			_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Unknown fields: %s\n\n%s", escapeMarkdown(value), exportFieldsHint()))
+			return nil, nil, false
+		}
+		fields = parsed
+	}
+
+	if fields == nil {
+		fields = h.defaultExportFields(ctx, userID)
+	}
+	return rest, fields, true
+}
+
+// defaultExportFields returns the fields the user exports without choosing, nil for the defaults
+func (h *Handler) defaultExportFields(ctx context.Context, userID shared.ID) recipe.ExportFields {
+	if h.exportFieldsCommand == nil {
+		return nil
+	}
+	fields, err := h.exportFieldsCommand.Defaults(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting export fields: %v", err)
+		return nil
+	}
+	return fields
+}
+
+// handleExportFields handles /export fields [<fields> | reset]: shows or changes
+// what exports include when they don't choose with --fields
+func (h *Handler) handleExportFields(ctx context.Context, chatID int64, userID shared.ID, args []string) {
+	if h.exportFieldsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Choosing export fields is not available.")
+		return
+	}
+
+	if len(args) == 0 {
+		fields, err := h.exportFieldsCommand.Defaults(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting export fields: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to load your export fields. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📤 Your exports include: %s\n\n%s\nUse /export fields <fields> to change it, or /export fields reset.",
+			escapeMarkdown(strings.Join(fields.Names(), ", ")), exportFieldsHint()))
+		return
+	}
+
+	var fields recipe.ExportFields
+	if spec := strings.Join(args, ""); !strings.EqualFold(spec, "reset") {
+		parsed, ok := recipe.ParseExportFields(spec)
+		if !ok {
+			_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Unknown fields: %s\n\n%s", escapeMarkdown(spec), exportFieldsHint()))
+			return
+		}
+		fields = parsed
+	}
+
+	if err := h.exportFieldsCommand.SetDefaults(ctx, userID, fields); err != nil {
+		log.Printf("Error saving export fields: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to save your export fields. Please try again.")
+		return
+	}
+	if fields == nil {
+		fields = recipe.DefaultExportFields()
+	}
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("✅ From now on your exports include: %s", escapeMarkdown(strings.Join(fields.Names(), ", "))))
+}
+
+// exportFieldsHint lists the fields and profiles an export can choose from
+func exportFieldsHint() string {
+	return fmt.Sprintf("Fields: %s\nProfiles: %s\n",
+		strings.Join(recipe.ExportFields(recipe.AllExportFields()).Names(), ", "), strings.Join(recipe.ExportProfileNames(), ", "))
+}
+
+// handleExportRecipeNatural exports one recipe, by number or the last one viewed, from
+// a message like "export it to Notion"
+func (h *Handler) handleExportRecipeNatural(ctx context.Context, chatID int64, userID shared.ID, recipeNumber int, format string) {
+	if h.exportRecipeCommand == nil || !h.isEnabled(ctx, feature.FlagExport, userID) {
+		_ = h.bot.SendError(ctx, chatID, "Export functionality is not available.")
+		return
+	}
+	if format == "" {
+		_ = h.bot.SendMessage(ctx, chatID, "Where should I export it? Try \"export it to Notion\" or \"export it to Obsidian\".")
+		return
+	}
+
+	rec, ok := h.recipeByReference(ctx, chatID, userID, recipeNumber)
+	if !ok {
+		return
+	}
+
+	recipeID := shared.ID(rec.ID)
+	h.exportRecipes(ctx, chatID, userID, strings.ToLower(format), &recipeID, rec.Title, h.defaultExportFields(ctx, userID))
+}
+
+// exportRecipes exports the fields of one recipe, or all of them when recipeID is nil,
+// in the named format. exported describes what is exported for the activity log.
+func (h *Handler) exportRecipes(ctx context.Context, chatID int64, userID shared.ID, format string, recipeID *shared.ID, exported string, fields recipe.ExportFields) {
+	// Execute export
+	var exportFormat command.ExportFormat
+	switch format {
+	case "obsidian", "md", "markdown":
+		exportFormat = command.ExportFormatObsidian
+	case "notion":
+		exportFormat = command.ExportFormatNotion
+	case "crouton", "crumb":
+		exportFormat = command.ExportFormatCrouton
+	case "anylist":
+		exportFormat = command.ExportFormatAnyList
+	case "whisk", "samsung", "samsungfood":
+		exportFormat = command.ExportFormatWhisk
+	default:
+		_ = h.bot.SendError(ctx, chatID, "Unknown format\\. Use 'obsidian', 'notion', 'crouton', 'anylist' or 'whisk'\\.")
+		return
+	}
+
+	if exportFormat != command.ExportFormatObsidian && exportFormat != command.ExportFormatNotion &&
+		!h.exportRecipeCommand.HasAppExporter(exportFormat) {
+		_ = h.bot.SendError(ctx, chatID, "This export format is not configured\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, "📤 Exporting recipes...")
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionUploadDocument)()
+
+	input := command.ExportRecipeInput{
+		UserID:   userID,
+		RecipeID: recipeID,
+		Format:   exportFormat,
+		Location: h.datesFor(ctx, userID, time.Now()).Location,
+		Fields:   fields,
+	}
+
+	// All recipes take a while at Notion's rate limit, so they are exported in the background
+	if exportFormat == command.ExportFormatNotion && recipeID == nil {
+		h.exportAllToNotion(ctx, chatID, userID, input, exported)
+		return
+	}
+
+	// Markdown goes straight into the user's cloud storage folder once they connect one
+	if exportFormat == command.ExportFormatObsidian && h.cloudStorageCommand != nil {
+		storage, err := h.cloudStorageCommand.Connection(ctx, userID)
+		if err != nil {
+			log.Printf("Error loading cloud storage: %v", err)
+		}
+		if storage != nil {
+			h.exportToCloud(ctx, chatID, userID, storage, input, exported)
+			return
+		}
+	}
+
+	result, err := h.exportRecipeCommand.Execute(ctx, input)
+	if err != nil {
+		log.Printf("Export error: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Export failed\\. Please try again\\.")
+		return
+	}
+
+	if !result.Success {
+		_ = h.bot.SendMessage(ctx, chatID, result.Message)
+		return
+	}
+
+	h.recordActivity(ctx, userID, activity.ActionRecipeExported, fmt.Sprintf("%s to %s", exported, exportFormat))
+
+	// Handle result based on format
+	switch exportFormat {
+	case command.ExportFormatObsidian, command.ExportFormatCrouton, command.ExportFormatAnyList, command.ExportFormatWhisk:
+		// Send file as document
+		caption := fmt.Sprintf("✅ %s", result.Message)
+		if err := h.bot.SendDocument(ctx, chatID, result.Filename, result.Data, caption); err != nil {
+			log.Printf("Failed to send document: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to send file\\. Please try again\\.")
+		}
+	case command.ExportFormatNotion:
+		// Send success message with link
+		msg := fmt.Sprintf("✅ %s", result.Message)
+		if result.URL != "" {
+			msg += fmt.Sprintf("\n\n[View in Notion](%s)", result.URL)
+		}
+		_ = h.bot.SendMessage(ctx, chatID, msg)
+	}
+}
+
+// exportAllToNotion exports all the user's recipes to Notion in the background,
+// reporting progress after each batch and the recipes that failed at the end
+func (h *Handler) exportAllToNotion(ctx context.Context, chatID int64, userID shared.ID, input command.ExportRecipeInput, exported string) {
+	ctx = background(ctx)
+	go func() {
+		report, err := h.exportRecipeCommand.ExportAllToNotion(ctx, input, func(done, total int) {
+			if done < total {
+				_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📤 Exported %d of %d recipes to Notion...", done, total))
+			}
+		})
+		if errors.Is(err, shared.ErrNotionNotConnected) {
+			_ = h.bot.SendMessage(ctx, chatID, "Not connected to Notion. Use /connect notion to authorize.")
+			return
+		}
+		if err != nil {
+			log.Printf("Notion export error: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Export failed\\. Please try again\\.")
+			return
+		}
+		if report.Total == 0 {
+			_ = h.bot.SendMessage(ctx, chatID, "No recipes to export")
+			return
+		}
+
+		if report.Exported > 0 {
+			h.recordActivity(ctx, userID, activity.ActionRecipeExported, fmt.Sprintf("%s to %s", exported, input.Format))
+		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatNotionExportReport(report))
+	}()
+}
+
+// exportToCloud writes the Markdown export into the user's cloud storage folder
+func (h *Handler) exportToCloud(ctx context.Context, chatID int64, userID shared.ID, storage *user.CloudStorage, input command.ExportRecipeInput, exported string) {
+	provider := storage.Provider.Name()
+	written, err := h.cloudStorageCommand.Export(ctx, storage, input)
+	if err != nil {
+		log.Printf("Cloud export error: %v", err)
+		msg := fmt.Sprintf("Export to %s failed\\. Please try again, or /connect %s again if it keeps failing\\.", provider, storage.Provider)
+		if written > 0 {
+			msg = fmt.Sprintf("Export to %s stopped after %d recipes\\. Please try again\\.", provider, written)
+		}
+		_ = h.bot.SendError(ctx, chatID, msg)
+		return
+	}
+	if written == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "No recipes to export")
+		return
+	}
+
+	h.recordActivity(ctx, userID, activity.ActionRecipeExported, fmt.Sprintf("%s to %s", exported, storage.Provider))
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("✅ Saved %d recipe(s) to %s, in the folder %s", written, provider, escapeMarkdown(storage.Folder)))
+}
+
+// handleAutoExport handles /autoexport: shows the user's auto-export, turns it on with
+// "notion" or "obsidian", optionally "nightly", or off with "off"
+func (h *Handler) handleAutoExport(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+
+	if h.autoExportCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Auto-export is not available.")
+		return
+	}
+
+	var target user.AutoExportTarget
+	schedule := user.AutoExportOnSave
+	off := false
+	for _, arg := range strings.Fields(strings.ToLower(message.CommandArguments())) {
+		switch arg {
+		case "notion":
+			target = user.AutoExportNotion
+		case "obsidian", "md", "markdown":
+			target = user.AutoExportObsidian
+		case "nightly", "night", "daily":
+			schedule = user.AutoExportNightly
+		case "off", "stop":
+			off = true
+		default:
+			_ = h.bot.SendMessage(ctx, chatID, FormatAutoExport(nil))
+			return
+		}
+	}
+
+	var autoExport *user.AutoExport
+	var err error
+	switch {
+	case off:
+		err = h.autoExportCommand.Disable(ctx, userID)
+	case target != "":
+		autoExport, err = h.autoExportCommand.Enable(ctx, userID, target, schedule, time.Now())
+	default:
+		autoExport, err = h.autoExportCommand.Settings(ctx, userID)
+	}
+	if errors.Is(err, shared.ErrNotionNotConnected) {
+		_ = h.bot.SendMessage(ctx, chatID, "Connect Notion first with /connect notion, then turn on auto\\-export\\.")
+		return
+	}
+	if err != nil {
+		log.Printf("Error updating auto-export: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to update auto-export. Please try again.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, FormatAutoExport(autoExport))
+}
+
+// handleConnect handles the /connect command
+func (h *Handler) handleConnect(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+
+	if args == "" {
+		_ = h.bot.SendMessage(ctx, chatID, h.connectHelp(ctx, userID))
+		return
+	}
+
+	service, rest, _ := strings.Cut(args, " ")
+	service = strings.ToLower(service)
+	switch service {
+	case "notion":
+		h.handleConnectNotion(ctx, chatID, userID)
+	case "folder":
+		h.handleCloudFolder(ctx, chatID, userID, strings.TrimSpace(rest))
+	default:
+		provider, err := user.ParseCloudProvider(service)
+		if err != nil {
+			_ = h.bot.SendError(ctx, chatID, "Unknown service\\. Currently supported: notion, dropbox, drive")
+			return
+		}
+		h.handleConnectCloud(ctx, chatID, userID, provider)
+	}
+}
+
+// connectHelp lists the services /connect offers and the cloud storage the user connected
+func (h *Handler) connectHelp(ctx context.Context, userID shared.ID) string {
+	var sb strings.Builder
+	sb.WriteString("*Connect External Services*\n\n")
+	sb.WriteString("*Usage:*\n")
+	sb.WriteString("/connect notion \\- Connect to Notion\n")
+	if h.cloudStorageCommand != nil {
+		sb.WriteString("/connect dropbox \\- Save /export obsidian files in Dropbox\n")
+		sb.WriteString("/connect drive \\- Save /export obsidian files in Google Drive\n")
+		sb.WriteString("/connect folder <path> \\- Choose the folder they are saved in\n")
+	}
+	sb.WriteString("\n*Connected services:*\n")
+	sb.WriteString("• Notion \\- Sync recipes to your Notion database")
+
+	if h.cloudStorageCommand == nil {
+		return sb.String()
+	}
+	storage, err := h.cloudStorageCommand.Connection(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading cloud storage: %v", err)
+		return sb.String()
+	}
+	if storage != nil {
+		sb.WriteString(fmt.Sprintf("\n• %s \\- Markdown exports go to %s", storage.Provider.Name(), escapeMarkdown(storage.Folder)))
+	}
+	return sb.String()
+}
+
+// handleConnectCloud sends the link that connects the user's Dropbox or Google Drive
+func (h *Handler) handleConnectCloud(ctx context.Context, chatID int64, userID shared.ID, provider user.CloudProvider) {
+	if !h.isEnabled(ctx, feature.FlagExport, userID) {
+		_ = h.bot.SendError(ctx, chatID, "Export functionality is not available.")
+		return
+	}
+
+	if h.cloudStorageCommand == nil || !h.cloudStorageCommand.HasProvider(provider) {
+		_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("%s integration is not configured\\.", provider.Name()))
+		return
+	}
+
+	authURL, err := h.cloudStorageCommand.Connect(userID, provider, time.Now())
+	if err != nil {
+		log.Printf("Error starting %s connection: %v", provider, err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to create the connect link\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(
+		"*Connect to %s*\n\n"+
+			"[Open this link](%s) to let me save your /export obsidian files in %s\\. It works for 15 minutes\\.\n\n"+
+			"Files go to the folder %s; change it with /connect folder <path>\\.",
+		provider.Name(), authURL, provider.Name(), escapeMarkdown(user.DefaultCloudFolder)))
+}
+
+// handleCloudFolder changes the folder of the user's cloud storage exports
+func (h *Handler) handleCloudFolder(ctx context.Context, chatID int64, userID shared.ID, folder string) {
+	if h.cloudStorageCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Cloud storage is not configured\\.")
+		return
+	}
+
+	storage, err := h.cloudStorageCommand.SetFolder(ctx, userID, folder)
+	switch {
+	case errors.Is(err, shared.ErrCloudStorageNotConnected):
+		_ = h.bot.SendMessage(ctx, chatID, "Connect Dropbox or Google Drive first with /connect dropbox or /connect drive\\.")
+	case errors.Is(err, shared.ErrInvalidInput):
+		_ = h.bot.SendError(ctx, chatID, "Invalid folder\\. Use a path like Recipes/Mains\\.")
+	case err != nil:
+		log.Printf("Error updating cloud folder: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to change the folder\\. Please try again\\.")
+	default:
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📁 /export obsidian now saves to %s in %s\\.", escapeMarkdown(storage.Folder), storage.Provider.Name()))
+	}
+}
+
+// handleConnectNotion handles Notion OAuth connection
+func (h *Handler) handleConnectNotion(ctx context.Context, chatID int64, userID shared.ID) {
+	if !h.isEnabled(ctx, feature.FlagExport, userID) {
+		_ = h.bot.SendError(ctx, chatID, "Export functionality is not available.")
+		return
+	}
+
+	if h.exportRecipeCommand == nil || !h.exportRecipeCommand.HasNotionExporter() {
+		_ = h.bot.SendError(ctx, chatID, "Notion integration is not configured\\.")
+		return
+	}
+
+	// TODO: Implement OAuth flow - for now, show a placeholder message
+	_ = h.bot.SendMessage(ctx, chatID,
+		"*Connect to Notion*\n\n"+
+			"Notion integration requires OAuth authentication\\.\n\n"+
+			"This feature is coming soon\\! For now, use /export obsidian to export your recipes as Markdown files\\.")
+}
+
+// handleDisconnect handles the /disconnect command
+func (h *Handler) handleDisconnect(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+
+	if args == "" {
+		usage := "*Disconnect Services*\n\n" +
+			"*Usage:*\n" +
+			"/disconnect notion \\- Disconnect from Notion"
+		if h.cloudStorageCommand != nil {
+			usage += "\n/disconnect dropbox \\- Disconnect Dropbox\n" +
+				"/disconnect drive \\- Disconnect Google Drive"
+		}
+		_ = h.bot.SendMessage(ctx, chatID, usage)
+		return
+	}
+
+	service := strings.ToLower(args)
+	switch service {
+	case "notion":
+		h.handleDisconnectNotion(ctx, chatID, userID)
+	default:
+		provider, err := user.ParseCloudProvider(service)
+		if err != nil {
+			_ = h.bot.SendError(ctx, chatID, "Unknown service\\. Currently supported: notion, dropbox, drive")
+			return
+		}
+		h.handleDisconnectCloud(ctx, chatID, userID, provider)
+	}
+}
+
+// handleDisconnectCloud forgets the user's Dropbox or Google Drive, so exports are sent in chat again
+func (h *Handler) handleDisconnectCloud(ctx context.Context, chatID int64, userID shared.ID, provider user.CloudProvider) {
+	if h.cloudStorageCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Cloud storage is not configured\\.")
+		return
+	}
+
+	storage, err := h.cloudStorageCommand.Connection(ctx, userID)
+	if err == nil && (storage == nil || storage.Provider != provider) {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("%s is not connected\\.", provider.Name()))
+		return
+	}
+	if err == nil {
+		err = h.cloudStorageCommand.Disconnect(ctx, userID)
+	}
+	if err != nil {
+		log.Printf("Error disconnecting %s: %v", provider, err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to disconnect\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("%s disconnected\\. /export obsidian sends files in chat again\\.", provider.Name()))
+}
+
+// handleDisconnectNotion handles Notion disconnection
+func (h *Handler) handleDisconnectNotion(ctx context.Context, chatID int64, userID shared.ID) {
+	if !h.isEnabled(ctx, feature.FlagExport, userID) {
+		_ = h.bot.SendError(ctx, chatID, "Export functionality is not available.")
+		return
+	}
+
+	if h.exportRecipeCommand == nil || !h.exportRecipeCommand.HasNotionExporter() {
+		_ = h.bot.SendError(ctx, chatID, "Notion integration is not configured\\.")
+		return
+	}
+
+	// TODO: Implement disconnection
+	_ = h.bot.SendMessage(ctx, chatID, "Notion integration is not yet connected\\.")
+}
+
+// recipeIDByNumber resolves a recipe number from the user's list to its ID
+func (h *Handler) recipeIDByNumber(ctx context.Context, chatID int64, userID shared.ID, arg string) (recipe.RecipeID, bool) {
+	recipeNum, err := strconv.Atoi(arg)
+	if err != nil {
+		_ = h.bot.SendError(ctx, chatID, "Invalid recipe number\\.")
+		return "", false
+	}
+
+	recipeDTO, err := h.listRecipesQuery.ExecuteByIndex(ctx, userID, recipeNum)
+	if err != nil {
+		_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Recipe #%d not found\\.", recipeNum))
+		return "", false
+	}
+
+	return recipe.RecipeID(recipeDTO.ID), true
+}
+
+// recipeByReference resolves the recipe a message is about: the recipe with the number
+// in the user's list or, without a number, the one they opened last ("convert it").
+// It tells the user when there is no such recipe.
+func (h *Handler) recipeByReference(ctx context.Context, chatID int64, userID shared.ID, recipeNumber int) (*dto.RecipeDTO, bool) {
+	if recipeNumber > 0 {
+		recipeDTO, err := h.listRecipesQuery.ExecuteByIndex(ctx, userID, recipeNumber)
+		if err != nil {
+			_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Recipe #%d not found\\.", recipeNumber))
+			return nil, false
+		}
+		return recipeDTO, true
+	}
+
+	recipeDTO := h.conversationManager.GetLastViewed(userID)
+	if recipeDTO == nil {
+		_ = h.bot.SendMessage(ctx, chatID, "Which recipe do you mean? Open one first, like \"details on #1\", or give its number.")
+		return nil, false
+	}
+	return recipeDTO, true
+}
+
+// datesFor returns how dates are written for a user at now, in the server's time zone
+// and the default style when the user cannot be loaded
+func (h *Handler) datesFor(ctx context.Context, userID shared.ID, now time.Time) Dates {
+	if h.userRepo == nil {
+		return Dates{Now: now}
+	}
+	usr, err := h.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return Dates{Now: now}
+	}
+	return DatesFor(usr, now)
+}
+
+// pantryOwner returns whose pantry and shopping list a chat uses: the user's own or,
+// with group pantries on, the household of a group chat (group chat IDs are negative)
+func (h *Handler) pantryOwner(ctx context.Context, chatID int64, userID shared.ID) (shared.ID, bool) {
+	if !h.groupPantry || chatID >= 0 {
+		return userID, true
+	}
+
+	household, err := h.getOrCreateUserCommand.Household(ctx, chatID, "")
+	if err != nil {
+		log.Printf("Error getting household: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to get the group's pantry\\. Please try again\\.")
+		return "", false
+	}
+	return household.ID(), true
+}
+
+// handleHistory handles the /history command
+func (h *Handler) handleHistory(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if h.recipeHistoryCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Recipe history is not available.")
+		return
+	}
+
+	if len(args) != 1 {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Recipe History*\n\n"+
+				"*Usage:*\n"+
+				"/history <number> \\- List the versions of a recipe\n"+
+				"/revert <number> <version> \\- Restore a version")
+		return
+	}
+
+	recipeID, ok := h.recipeIDByNumber(ctx, chatID, userID, args[0])
+	if !ok {
+		return
+	}
+
+	history, err := h.recipeHistoryCommand.History(ctx, userID, recipeID)
+	if err != nil {
+		log.Printf("History error: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load recipe history\\. Please try again\\.")
+		return
+	}
+
+	recipeNum, _ := strconv.Atoi(args[0])
+	_ = h.bot.SendMessage(ctx, chatID, FormatRecipeHistory(recipeNum, history, h.datesFor(ctx, userID, time.Now())))
+}
+
+// handleRevert handles the /revert command
+func (h *Handler) handleRevert(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if h.recipeHistoryCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Recipe history is not available.")
+		return
+	}
+
+	if len(args) != 2 {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"Please specify a recipe number and a version\\.\n\n"+
+				"Usage: /revert <number> <version>\n"+
+				"Example: /revert 1 2\n\n"+
+				"Use /history <number> to see the versions of a recipe\\.")
+		return
+	}
+
+	versionNum, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(args[1]), "v"))
+	if err != nil {
+		_ = h.bot.SendError(ctx, chatID, "Invalid version number\\. Use /revert <number> <version>")
+		return
+	}
+
+	recipeID, ok := h.recipeIDByNumber(ctx, chatID, userID, args[0])
+	if !ok {
+		return
+	}
+
+	rec, err := h.recipeHistoryCommand.Revert(ctx, userID, recipeID, versionNum)
+	if err != nil {
+		if errors.Is(err, shared.ErrVersionNotFound) {
+			_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Version v%d not found\\. Use /history %s to see the versions\\.", versionNum, args[0]))
+			return
+		}
+		log.Printf("Revert error: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to revert recipe\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("✅ Recipe #%s reverted to v%d", args[0], versionNum))
+	_ = h.bot.SendRecipe(ctx, chatID, rec)
+}
+
+// handleReextract handles the /reextract command
+func (h *Handler) handleReextract(ctx context.Context, message *tgbotapi.Message, userID shared.ID, lang user.Language) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if h.recipeHistoryCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Recipe history is not available.")
+		return
+	}
+
+	if len(args) != 1 {
+		_ = h.bot.SendMessage(ctx, chatID, "Please specify a recipe number.\n\nUsage: /reextract <number>\nExample: /reextract 1")
+		return
+	}
+
+	recipeID, ok := h.recipeIDByNumber(ctx, chatID, userID, args[0])
+	if !ok {
+		return
+	}
+
+	updated, err := h.processRecipeLinkCommand.Reextract(ctx, recipeID, userID, chatID)
+	if err != nil {
+		log.Printf("Re-extraction error: %v", err)
+		_ = h.bot.SendError(ctx, chatID, h.formatError(err, lang))
+		return
+	}
+
+	version, err := h.recipeHistoryCommand.Update(ctx, updated, recipe.VersionReasonReextract)
+	if err != nil {
+		log.Printf("Failed to save re-extracted recipe: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to save recipe\\. Please try again\\.")
+		return
+	}
+
+	if version == nil {
+		_ = h.bot.SendMessage(ctx, chatID, "✅ Re-extraction finished. Nothing changed.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("✅ Recipe updated. The previous content was kept as v%d, use /revert %s %d to restore it.", version.Number, args[0], version.Number))
+	_ = h.bot.SendRecipe(ctx, chatID, updated)
+}
+
+// Callback data prefixes of inline keyboard buttons
+const (
+	callbackShoppingToggle  = "shop"      // shopping list item buttons
+	callbackSimplify        = "simplify"  // show a recipe with simplified steps
+	callbackOriginal        = "original"  // show a recipe with its original steps
+	callbackCover           = "cover"     // generate a cover image for a recipe whose source has none
+	callbackMenuSwap        = "menuswap"  // offer another dish for a menu course
+	callbackMenuTimeline    = "menutime"  // show the cooking timeline of a menu
+	callbackReminders       = "remind"    // remind the user when each cooking step starts
+	callbackNoReminders     = "noremind"  // cancel cooking step reminders
+	callbackGuestRecipe     = "guest"     // show a recipe of a guest share
+	callbackNotification    = "notify"    // turn a notification on or off
+	callbackDishSave        = "dishsave"  // save the recipe proposed from a dish photo
+	callbackDishDiscard     = "dishdrop"  // discard the recipe proposed from a dish photo
+	callbackPantryPhoto     = "shelf"     // pantry photo checklist buttons
+	callbackPantryPhotoAdd  = "shelfadd"  // add the checked pantry photo items
+	callbackCompilationSave = "compsave"  // save recipes found in a compilation video
+	callbackIntentFeedback  = "intentfb"  // thumbs up or down for a detected intent
+	callbackBookmarkImport  = "bmimport"  // start importing the recipe links of a bookmark file
+	callbackBookmarkCancel  = "bmcancel"  // drop or stop a bookmark import
+	callbackFeedSave        = "feedsave"  // save the recipe of a new post of a followed feed
+	callbackModeration      = "modreview" // approve or reject a shared recipe waiting for review
+	callbackClaims          = "claims"    // save or discard a recipe with implausible times, servings or amounts
+	callbackExplainMatch    = "why"       // explain how the ingredients of a matched recipe were counted
+	callbackMatchFilter     = "matchf"    // narrow or widen the last ingredient match
+	callbackCook            = "cook"      // take or finish a step of a cook-along
+	callbackRecipeDetails   = "details"   // show the recipe picked among several that fit a name
+	callbackClarify         = "clarify"   // answer a clarifying question with one of its options
+	callbackPremium         = "premium"   // send the invoice of a premium plan
+)
+
+// handleCallback handles inline keyboard button presses
+func (h *Handler) handleCallback(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	if cq.Message == nil || cq.From == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	usr, err := h.getOrCreateUserCommand.Execute(ctx, cq.From.ID, cq.From.UserName)
+	if err != nil {
+		log.Printf("Error getting/creating user: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to get user information. Please try again.")
+		return
+	}
+	ctx = withFormatOptions(ctx, FormatOptionsFor(usr))
+
+	action, payload, _ := strings.Cut(cq.Data, ":")
+	switch action {
+	case callbackShoppingToggle:
+		h.handleShoppingToggle(ctx, cq, usr.ID(), payload)
+	case callbackSimplify:
+		h.handleRecipeView(ctx, cq, usr, recipe.RecipeID(payload), true)
+	case callbackOriginal:
+		h.handleRecipeView(ctx, cq, usr, recipe.RecipeID(payload), false)
+	case callbackCover:
+		h.handleGenerateCover(ctx, cq, usr, recipe.RecipeID(payload))
+	case callbackMenuSwap:
+		h.handleMenuSwap(ctx, cq, usr.ID(), payload)
+	case callbackMenuTimeline:
+		h.handleMenuTimeline(ctx, cq, usr.ID())
+	case callbackReminders:
+		h.handleReminders(ctx, cq, usr.ID(), true)
+	case callbackNoReminders:
+		h.handleReminders(ctx, cq, usr.ID(), false)
+	case callbackGuestRecipe:
+		token, number, _ := strings.Cut(payload, ":")
+		index, _ := strconv.Atoi(number)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		h.handleGuest(ctx, cq.Message.Chat.ID, token, index, usr.Language())
+	case callbackNotification:
+		h.handleNotificationToggle(ctx, cq, usr.ID(), user.Notification(payload))
+	case callbackDishSave:
+		h.handleDishProposal(ctx, cq, usr.ID(), true)
+	case callbackDishDiscard:
+		h.handleDishProposal(ctx, cq, usr.ID(), false)
+	case callbackPantryPhoto:
+		h.handlePantryPhotoToggle(ctx, cq, usr.ID(), payload)
+	case callbackPantryPhotoAdd:
+		h.handlePantryPhotoConfirm(ctx, cq, usr.ID())
+	case callbackCompilationSave:
+		h.handleCompilationSave(ctx, cq, usr.ID(), payload)
+	case callbackIntentFeedback:
+		h.handleIntentFeedback(ctx, cq, usr.Language(), payload)
+	case callbackBookmarkImport:
+		h.handleBookmarkImport(ctx, cq, usr.ID())
+	case callbackBookmarkCancel:
+		h.handleBookmarkCancel(ctx, cq, usr.ID())
+	case callbackFeedSave:
+		h.handleFeedSave(ctx, cq, usr.ID(), payload)
+	case callbackClaims:
+		h.handleClaimCheck(ctx, cq, usr.ID(), payload)
+	case callbackModeration:
+		h.handleModerationReview(ctx, cq, payload)
+	case callbackExplainMatch:
+		h.handleExplainMatch(ctx, cq, usr.ID(), recipe.RecipeID(payload))
+	case callbackRecipeDetails:
+		h.handleRecipeDetailsButton(ctx, cq, usr, payload)
+	case callbackClarify:
+		h.handleClarificationButton(ctx, cq, usr, payload)
+	case callbackMatchFilter:
+		h.handleMatchFilter(ctx, cq, usr.ID(), payload)
+	case callbackCook:
+		h.handleCookButton(ctx, cq, usr.ID(), payload)
+	case callbackPremium:
+		h.handlePremiumButton(ctx, cq, usr.ID(), payload)
+	default:
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	}
+}
+
+// handlePlan handles the /plan command
+func (h *Handler) handlePlan(ctx context.Context, message *tgbotapi.Message, userID shared.ID, lang user.Language) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if h.manageMealPlanCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Meal planning is not available.")
+		return
+	}
+
+	now := time.Now()
+
+	if len(args) == 0 {
+		plan, err := h.manageMealPlanCommand.GetPlan(ctx, userID, now)
+		if err != nil {
+			log.Printf("Error getting meal plan: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to load your meal plan\\. Please try again\\.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatMealPlan(plan))
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		h.handlePlanAdd(ctx, chatID, userID, lang, now, args[1:])
+
+	case "remove":
+		if len(args) != 2 {
+			_ = h.bot.SendError(ctx, chatID, "Usage: /plan remove <day>")
+			return
+		}
+		day, ok := mealplan.ParseDay(args[1])
+		if !ok {
+			_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Unknown day: %s", escapeMarkdown(args[1])))
+			return
+		}
+		plan, err := h.manageMealPlanCommand.RemoveDay(ctx, userID, now, day)
+		if err != nil {
+			log.Printf("Error updating meal plan: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to update your meal plan\\. Please try again\\.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatMealPlan(plan))
+
+	case "clear":
+		if err := h.manageMealPlanCommand.Clear(ctx, userID, now); err != nil {
+			log.Printf("Error clearing meal plan: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to clear your meal plan\\. Please try again\\.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, "🗑️ Your meal plan for this week has been cleared.")
+
+	default:
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Meal Plan*\n\n"+
+				"*Usage:*\n"+
+				"/plan \\- Show this week's plan\n"+
+				"/plan add <number> <day> [servings] \\- Plan a recipe\n"+
+				"/plan remove <day> \\- Remove a day\n"+
+				"/plan clear \\- Clear the week\n"+
+				"/shopping \\- Shopping list for this week")
+	}
+}
+
+// handlePlanAdd handles /plan add <number> <day> [servings]
+func (h *Handler) handlePlanAdd(ctx context.Context, chatID int64, userID shared.ID, lang user.Language, now time.Time, args []string) {
+	if len(args) < 2 || len(args) > 3 {
+		_ = h.bot.SendError(ctx, chatID, "Usage: /plan add <number> <day> [servings]\nExample: /plan add 1 monday")
+		return
+	}
+
+	day, ok := mealplan.ParseDay(args[1])
+	if !ok {
+		_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Unknown day: %s", escapeMarkdown(args[1])))
+		return
+	}
+
+	servings := 0
+	if len(args) == 3 {
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n <= 0 {
+			_ = h.bot.SendError(ctx, chatID, "Servings must be a positive number\\.")
+			return
+		}
+		servings = n
+	}
+
+	recipeID, ok := h.recipeIDByNumber(ctx, chatID, userID, args[0])
+	if !ok {
+		return
+	}
+
+	// Planned recipes are checked against the user's diet first
+	if h.checkDietCommand != nil {
+		report, ok, err := h.checkDietCommand.CheckRecipe(ctx, userID, recipeID)
+		if err != nil {
+			log.Printf("Error checking diet: %v", err)
+		} else if ok && !report.Clear() {
+			_ = h.bot.SendMessage(ctx, chatID, FormatDietReport(report, lang))
+		}
+	}
+
+	plan, err := h.manageMealPlanCommand.AddRecipe(ctx, userID, now, day, recipeID, servings)
+	if err != nil {
+		log.Printf("Error updating meal plan: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to update your meal plan\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, FormatMealPlan(plan))
+}
+
+// handleShoppingList generates the shopping list for this week's meal plan
+func (h *Handler) handleShoppingList(ctx context.Context, chatID int64, userID shared.ID) {
+	if h.shoppingListCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Shopping lists are not available.")
+		return
+	}
+
+	owner, ok := h.pantryOwner(ctx, chatID, userID)
+	if !ok {
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, "🛒 Building your shopping list...")
+
+	list, err := h.shoppingListCommand.ExecuteFor(ctx, userID, owner, time.Now())
+	if err != nil {
+		if errors.Is(err, shared.ErrMealPlanNotFound) {
+			_ = h.bot.SendMessage(ctx, chatID, "Nothing is planned for this week yet.\n\nUse /plan add <number> <day> to plan a recipe first.")
+			return
+		}
+		log.Printf("Error generating shopping list: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to build your shopping list\\. Please try again\\.")
+		return
+	}
+
+	if len(list.Items()) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, FormatShoppingList(list))
+		return
+	}
+
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatShoppingList(list), ShoppingListKeyboard(list))
+}
+
+// handleAddToShoppingList puts the ingredients of a recipe, by number or the last one
+// viewed, on the shopping list
+func (h *Handler) handleAddToShoppingList(ctx context.Context, chatID int64, userID shared.ID, recipeNumber int) {
+	if h.shoppingListCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Shopping lists are not available.")
+		return
+	}
+
+	owner, ok := h.pantryOwner(ctx, chatID, userID)
+	if !ok {
+		return
+	}
+
+	rec, ok := h.recipeByReference(ctx, chatID, userID, recipeNumber)
+	if !ok {
+		return
+	}
+
+	list, added, err := h.shoppingListCommand.AddRecipe(ctx, userID, owner, shared.ID(rec.ID), time.Now())
+	if err != nil {
+		log.Printf("Error adding recipe to shopping list: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to update your shopping list. Please try again.")
+		return
+	}
+
+	if added == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("Your pantry already has everything *%s* needs.", escapeMarkdown(rec.Title)))
+		return
+	}
+
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID,
+		fmt.Sprintf("✅ Added %d ingredient(s) of *%s* to your shopping list.\n\n", added, escapeMarkdown(rec.Title))+FormatShoppingList(list),
+		ShoppingListKeyboard(list))
+}
+
+// handleShoppingToggle checks or unchecks a shopping list item from its button
+func (h *Handler) handleShoppingToggle(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, payload string) {
+	index, err := strconv.Atoi(payload)
+	if err != nil || h.shoppingListCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	owner, ok := h.pantryOwner(ctx, cq.Message.Chat.ID, userID)
+	if !ok {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	list, err := h.shoppingListCommand.ToggleItem(ctx, owner, index)
+	if err != nil {
+		log.Printf("Error updating shopping list: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This list is out of date. Use /shopping to get a new one.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	_ = h.bot.EditMessageWithKeyboard(ctx, cq.Message.Chat.ID, cq.Message.MessageID, FormatShoppingList(list), ShoppingListKeyboard(list))
+}
+
+// handleRecipeView switches a recipe message between its original and simplified instructions
+func (h *Handler) handleRecipeView(ctx context.Context, cq *tgbotapi.CallbackQuery, usr *user.User, recipeID recipe.RecipeID, simplified bool) {
+	if h.simplifyRecipeCommand == nil || recipeID == "" {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	userID := usr.ID()
+	lang := usr.Language()
+
+	var recipeDTO *dto.RecipeDTO
+	var steps []dto.InstructionDTO
+	if simplified {
+		defer h.bot.KeepChatAction(ctx, cq.Message.Chat.ID, ports.ChatActionTyping)()
+
+		targetLang := "English"
+		if lang == user.LanguagePortuguese {
+			targetLang = "Portuguese"
+		}
+
+		result, err := h.simplifyRecipeCommand.Execute(ctx, userID, recipeID, targetLang)
+		if err != nil {
+			log.Printf("Error simplifying recipe: %v", err)
+			_ = h.bot.AnswerCallback(ctx, cq.ID, "Couldn't simplify this recipe. Please try again.")
+			return
+		}
+		recipeDTO, steps = result.Recipe, result.Steps
+	} else {
+		rec, err := h.simplifyRecipeCommand.Recipe(ctx, userID, recipeID)
+		if err != nil {
+			log.Printf("Error getting recipe: %v", err)
+			_ = h.bot.AnswerCallback(ctx, cq.ID, "This recipe is no longer available.")
+			return
+		}
+		recipeDTO = rec
+	}
+
+	translation := h.recipeTranslation(ctx, userID, recipeDTO, lang)
+	dates := h.datesFor(ctx, userID, time.Now())
+	messageText := FormatRecipeDTOWithTranslation(recipeDTO, translation, lang, dates)
+	if simplified {
+		messageText = FormatSimplifiedRecipe(recipeDTO, translation, steps, lang, dates)
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	keyboard, _ := h.recipeKeyboard(recipeDTO, simplified, lang)
+	_ = h.bot.EditMessageWithKeyboard(ctx, cq.Message.Chat.ID, cq.Message.MessageID, messageText, keyboard)
+}
+
+const (
+	defaultMenuOccasion = "Dinner"
+	defaultMenuGuests   = 4
+)
+
+var (
+	menuGuestsPattern = regexp.MustCompile(`(?i)\s*\b(?:for|para)\s+(\d+)(?:\s+(?:people|guests|pessoas|convidados))?\b`)
+	menuTimePattern   = regexp.MustCompile(`(?i)\s*(?:\bat\b|\bàs\b|@)\s*(\d{1,2})(?:[:h](\d{2}))?\b`)
+)
+
+// defaultServeTime returns 19:00 on the day of now
+func defaultServeTime(now time.Time) time.Time {
+	y, m, d := now.Date()
+	return time.Date(y, m, d, 19, 0, 0, 0, now.Location())
+}
+
+// parseMenuRequest reads "<occasion> [for <guests>] [at <hh:mm>]", e.g. "Christmas dinner for 8 at 20:00"
+func parseMenuRequest(args string, now time.Time) (occasion string, guests int, serveAt time.Time) {
+	guests = defaultMenuGuests
+	serveAt = defaultServeTime(now)
+
+	if match := menuGuestsPattern.FindStringSubmatch(args); match != nil {
+		if n, err := strconv.Atoi(match[1]); err == nil && n > 0 {
+			guests = n
+		}
+		args = strings.Replace(args, match[0], "", 1)
+	}
+
+	if at, rest, ok := parseServeTime(args, now); ok {
+		serveAt, args = at, rest
+	}
+
+	occasion = strings.TrimSpace(args)
+	if occasion == "" {
+		occasion = defaultMenuOccasion
+	}
+	return occasion, guests, serveAt
+}
+
+// parseServeTime reads "at <hh:mm>" from the arguments, returning that time today
+// and the arguments without it
+func parseServeTime(args string, now time.Time) (time.Time, string, bool) {
+	match := menuTimePattern.FindStringSubmatch(args)
+	if match == nil {
+		return time.Time{}, args, false
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	if hour >= 24 || minute >= 60 {
+		return time.Time{}, args, false
+	}
+
+	y, m, d := now.Date()
+	at := time.Date(y, m, d, hour, minute, 0, 0, now.Location())
+	return at, strings.Replace(args, match[0], "", 1), true
+}
+
+// handleMenu handles the /menu command
+func (h *Handler) handleMenu(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+
+	if args == "" {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Menu Planner*\n\n"+
+				"Plan a menu for an occasion from your recipes, with a cooking timeline.\n\n"+
+				"*Usage:*\n"+
+				"/menu <occasion> \\[for <guests>] \\[at <time>]\n\n"+
+				"*Example:*\n"+
+				"/menu Christmas dinner for 8 at 20:00")
+		return
+	}
+
+	occasion, guests, serveAt := parseMenuRequest(args, time.Now())
+	h.handleMenuStart(ctx, chatID, usr.ID(), occasion, guests, serveAt, usr.Language())
+}
+
+// handleMenuStart plans a new menu and shows it with its buttons
+func (h *Handler) handleMenuStart(ctx context.Context, chatID int64, userID shared.ID, occasion string, guests int, serveAt time.Time, lang user.Language) {
+	if h.planMenuCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Menu planning is not available.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, "🍽️ Putting your menu together...")
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionTyping)()
+
+	targetLang := "English"
+	if lang == user.LanguagePortuguese {
+		targetLang = "Portuguese"
+	}
+
+	m, err := h.planMenuCommand.Start(ctx, userID, occasion, guests, serveAt, targetLang)
+	if err != nil {
+		log.Printf("Error planning menu: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to plan your menu\\. Please try again\\.")
+		return
+	}
+
+	if len(m.Selected()) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "I couldn't find any dishes for this menu.\n\nSave a few appetizers, mains, sides or desserts first, then try again.")
+		return
+	}
+
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatMenu(m), MenuKeyboard(m))
+}
+
+// handleMenuSwap offers another dish for a course of the menu from its button
+func (h *Handler) handleMenuSwap(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, payload string) {
+	course, ok := menu.ParseCourse(payload)
+	if !ok || h.planMenuCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	m, err := h.planMenuCommand.Swap(userID, course)
+	if err != nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This menu is out of date. Use /menu to plan a new one.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	_ = h.bot.EditMessageWithKeyboard(ctx, cq.Message.Chat.ID, cq.Message.MessageID, FormatMenu(m), MenuKeyboard(m))
+}
+
+// handleMenuTimeline sends the cooking timeline of the menu from its button
+func (h *Handler) handleMenuTimeline(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID) {
+	if h.planMenuCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	timeline, err := h.planMenuCommand.Timeline(userID)
+	if err != nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This menu is out of date. Use /menu to plan a new one.")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+	_ = h.bot.SendMessage(ctx, cq.Message.Chat.ID, FormatMenuTimeline(timeline))
+}
+
+// handleTimeline handles /timeline <number> <number> ... [at <hh:mm>]
+func (h *Handler) handleTimeline(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+
+	if h.cookingTimelineCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Cooking timelines are not available.")
+		return
+	}
+
+	now := time.Now()
+	serveAt, args, ok := parseServeTime(message.CommandArguments(), now)
+	if ok && serveAt.Before(now) {
+		serveAt = serveAt.AddDate(0, 0, 1)
+	}
+
+	numbers := strings.Fields(strings.ReplaceAll(args, ",", " "))
+	if len(numbers) < cooking.MinRecipes || len(numbers) > cooking.MaxRecipes {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Cooking Timeline*\n\n"+
+				fmt.Sprintf("Cook %d to %d recipes so they are all ready at the same time.\n\n", cooking.MinRecipes, cooking.MaxRecipes)+
+				"*Usage:*\n"+
+				"/timeline <number> <number> ... \\[at <time>]\n\n"+
+				"*Example:*\n"+
+				"/timeline 1 3 at 19:30")
+		return
+	}
+
+	recipeIDs := make([]recipe.RecipeID, 0, len(numbers))
+	for _, arg := range numbers {
+		recipeID, ok := h.recipeIDByNumber(ctx, chatID, userID, strings.TrimPrefix(arg, "#"))
+		if !ok {
+			return
+		}
+		recipeIDs = append(recipeIDs, recipeID)
+	}
+
+	schedule, err := h.cookingTimelineCommand.Execute(ctx, userID, recipeIDs, serveAt)
+	if err != nil {
+		log.Printf("Error building cooking timeline: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to build your cooking timeline\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatCookingSchedule(schedule), CookingScheduleKeyboard(false))
+}
+
+// handleReminders turns cooking step reminders on or off from the timeline buttons
+func (h *Handler) handleReminders(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, on bool) {
+	if h.cookingTimelineCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	chatID := cq.Message.Chat.ID
+
+	if !on {
+		h.cookingTimelineCommand.StopReminders(userID)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Reminders cancelled")
+		_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, CookingScheduleKeyboard(false))
+		return
+	}
+
+	if h.notificationsCommand != nil && !h.notificationsCommand.Wants(ctx, userID, user.NotificationTimerPing) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Timer pings are off. Turn them on in /notifications")
+		return
+	}
+
+	remindCtx := background(ctx)
+	count, err := h.cookingTimelineCommand.StartReminders(userID, func(step cooking.Step) {
+		_ = h.bot.SendMessage(remindCtx, chatID, FormatCookingReminder(step))
+	})
+	if err != nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This timeline is out of date. Use /timeline to make a new one.")
+		return
+	}
+	if count == 0 {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Every step has already started")
+		return
+	}
+
+	_ = h.bot.AnswerCallback(ctx, cq.ID, fmt.Sprintf("I'll remind you at %d steps", count))
+	_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, CookingScheduleKeyboard(true))
+}
+
+// handleCook handles /cook <number>, /cook and /cook stop: cooking a recipe together in a group
+func (h *Handler) handleCook(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.TrimSpace(message.CommandArguments())
+
+	if h.cookTogetherCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Cooking together is not available.")
+		return
+	}
+
+	switch strings.ToLower(args) {
+	case "":
+		session, err := h.cookTogetherCommand.Session(chatID)
+		if err != nil {
+			_ = h.bot.SendMessage(ctx, chatID,
+				"*Cook Together*\n\n"+
+					"Cook one of your recipes with your household: everyone in the chat sees the steps, takes the ones they do and ticks them off\\.\n\n"+
+					"*Usage:*\n"+
+					"/cook <number> \\- start cooking a recipe\n"+
+					"/claim <number or words> \\- take a step\n"+
+					"/done <number or words> \\- finish a step\n"+
+					"/cook stop \\- stop cooking\n\n"+
+					"*Example:*\n"+
+					"/claim chop the onions")
+			return
+		}
+		_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatCookSession(session), CookSessionKeyboard(session))
+		return
+
+	case "stop":
+		if h.cookTogetherCommand.Stop(chatID) {
+			_ = h.bot.SendMessage(ctx, chatID, "👋 Stopped cooking together\\.")
+		} else {
+			_ = h.bot.SendMessage(ctx, chatID, "Nothing is being cooked here\\. Start with /cook <number>\\.")
+		}
+		return
+	}
+
+	recipeID, ok := h.recipeIDByNumber(ctx, chatID, userID, strings.TrimPrefix(args, "#"))
+	if !ok {
+		return
+	}
+
+	session, err := h.cookTogetherCommand.Start(ctx, chatID, cookOf(message.From, userID), recipeID)
+	if err != nil {
+		log.Printf("Error starting cook-along: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to start cooking together\\. Please try again\\.")
+		return
+	}
+
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatCookSession(session), CookSessionKeyboard(session))
+}
+
+// handleCookStep handles /claim and /done <number or words> during a cook-along
+func (h *Handler) handleCookStep(ctx context.Context, message *tgbotapi.Message, userID shared.ID, done bool) {
+	chatID := message.Chat.ID
+	step := strings.TrimSpace(message.CommandArguments())
+
+	if h.cookTogetherCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Cooking together is not available.")
+		return
+	}
+	if step == "" {
+		_ = h.bot.SendMessage(ctx, chatID, "Name the step by its number or words, like /claim 2 or /done chop the onions\\.")
+		return
+	}
+
+	cook := cookOf(message.From, userID)
+	session, number, err := h.updateCookStep(chatID, step, cook, done)
+	if err != nil {
+		_ = h.bot.SendMessage(ctx, chatID, escapeMarkdown(h.cookStepError(chatID, number, err)))
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, formatCookStepUpdate(session, number, cook, done))
+	if done && session.Finished() {
+		h.finishCooking(ctx, chatID, session)
+	}
+}
+
+// handleCookButton takes or finishes a step from the buttons of a cook-along board
+func (h *Handler) handleCookButton(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, payload string) {
+	if h.cookTogetherCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	chatID := cq.Message.Chat.ID
+	op, step, _ := strings.Cut(payload, ":")
+	done := op == "done"
+
+	session, number, err := h.updateCookStep(chatID, step, cookOf(cq.From, userID), done)
+	if err != nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, h.cookStepError(chatID, number, err))
+		return
+	}
+
+	switch {
+	case done:
+		_ = h.bot.AnswerCallback(ctx, cq.ID, fmt.Sprintf("Step %d done", number))
+	case session.Steps()[number-1].ClaimedBy == nil:
+		_ = h.bot.AnswerCallback(ctx, cq.ID, fmt.Sprintf("You gave step %d back", number))
+	default:
+		_ = h.bot.AnswerCallback(ctx, cq.ID, fmt.Sprintf("Step %d is yours", number))
+	}
+	_ = h.bot.EditMessageWithKeyboard(ctx, chatID, cq.Message.MessageID, FormatCookSession(session), CookSessionKeyboard(session))
+	if done && session.Finished() {
+		h.finishCooking(ctx, chatID, session)
+	}
+}
+
+// updateCookStep claims or completes a step of the chat's cook-along
+func (h *Handler) updateCookStep(chatID int64, step string, cook cooking.Cook, done bool) (*cooking.Session, int, error) {
+	if done {
+		return h.cookTogetherCommand.Complete(chatID, step, cook)
+	}
+	return h.cookTogetherCommand.Claim(chatID, step, cook)
+}
+
+// cookStepError explains, in plain text, why a step could not be taken or finished
+func (h *Handler) cookStepError(chatID int64, number int, err error) string {
+	switch {
+	case errors.Is(err, shared.ErrCookSessionNotFound):
+		return "Nothing is being cooked here. Start with /cook <number>."
+	case errors.Is(err, shared.ErrNoSuchStep):
+		return "I couldn't find that step. Use its number or a few of its words."
+	case errors.Is(err, shared.ErrStepDone):
+		return fmt.Sprintf("Step %d is already done.", number)
+	case errors.Is(err, shared.ErrStepClaimed):
+		if session, err := h.cookTogetherCommand.Session(chatID); err == nil {
+			if claimer := session.Steps()[number-1].ClaimedBy; claimer != nil {
+				return fmt.Sprintf("%s already took step %d.", claimer.Name, number)
+			}
+		}
+		return fmt.Sprintf("Someone already took step %d.", number)
+	default:
+		log.Printf("Error updating cook-along step: %v", err)
+		return "Failed to update the step. Please try again."
+	}
+}
+
+// finishCooking announces a finished cook-along and ends it
+func (h *Handler) finishCooking(ctx context.Context, chatID int64, session *cooking.Session) {
+	h.cookTogetherCommand.Stop(chatID)
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🎉 Every step of *%s* is done\\. Enjoy your meal\\!", escapeMarkdown(session.Recipe)))
+}
+
+// formatCookStepUpdate tells the chat who took or finished a step
+func formatCookStepUpdate(session *cooking.Session, number int, cook cooking.Cook, done bool) string {
+	step := session.Steps()[number-1]
+	finished, total := session.Progress()
+	switch {
+	case done:
+		return fmt.Sprintf("✅ %s finished step %d: %s\n_%d of %d steps done_", escapeMarkdown(cook.Name), number, escapeMarkdown(step.Text), finished, total)
+	case step.ClaimedBy == nil:
+		return fmt.Sprintf("↩️ %s gave back step %d: %s", escapeMarkdown(cook.Name), number, escapeMarkdown(step.Text))
+	default:
+		return fmt.Sprintf("🙋 %s takes step %d: %s", escapeMarkdown(cook.Name), number, escapeMarkdown(step.Text))
+	}
+}
+
+// cookOf names the member cooking: their first name, or their username
+func cookOf(from *tgbotapi.User, userID shared.ID) cooking.Cook {
+	cook := cooking.Cook{ID: userID, Name: "Someone"}
+	if from != nil {
+		if from.FirstName != "" {
+			cook.Name = from.FirstName
+		} else if from.UserName != "" {
+			cook.Name = from.UserName
+		}
+	}
+	return cook
+}
+
+// handleConvert handles /convert <number> <appliance>
+func (h *Handler) handleConvert(ctx context.Context, message *tgbotapi.Message, userID shared.ID, lang user.Language) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if len(args) < 2 {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Convert a Recipe*\n\n"+
+				"Adapt a recipe to a slow cooker or Instant Pot. The converted steps are kept with the recipe.\n\n"+
+				"*Usage:*\n"+
+				"/convert <number> <appliance>\n\n"+
+				"*Example:*\n"+
+				"/convert 4 instant pot")
+		return
+	}
+
+	recipeNum, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil || recipeNum < 1 {
+		_ = h.bot.SendError(ctx, chatID, "Invalid recipe number\\.")
+		return
+	}
+
+	h.handleConvertRecipe(ctx, chatID, userID, recipeNum, strings.Join(args[1:], " "), lang)
+}
+
+// handleConvertRecipe converts a recipe, given by its number or the last one viewed, to the named appliance
+func (h *Handler) handleConvertRecipe(ctx context.Context, chatID int64, userID shared.ID, recipeNumber int, applianceName string, lang user.Language) {
+	if h.convertRecipeCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Recipe conversion is not available.")
+		return
+	}
+
+	appliance, ok := recipe.ParseAppliance(strings.TrimPrefix(strings.TrimSpace(strings.ToLower(applianceName)), "to "))
+	if !ok {
+		_ = h.bot.SendMessage(ctx, chatID, "I can convert recipes to a *slow cooker* or an *Instant Pot*.")
+		return
+	}
+
+	rec, ok := h.recipeByReference(ctx, chatID, userID, recipeNumber)
+	if !ok {
+		return
+	}
+	recipeID := recipe.RecipeID(rec.ID)
+
+	targetLang := "English"
+	if lang == user.LanguagePortuguese {
+		targetLang = "Portuguese"
+	}
+
+	_ = h.bot.SendProgress(ctx, chatID, fmt.Sprintf("Converting to %s...", appliance))
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionTyping)()
+
+	converted, err := h.convertRecipeCommand.Execute(ctx, userID, recipeID, appliance, targetLang)
+	if err != nil {
+		log.Printf("Error converting recipe: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to convert this recipe. Please try again.")
+		return
+	}
+
+	messageText := FormatConvertedRecipe(converted, h.recipeTranslation(ctx, userID, converted.Recipe, lang), lang, h.datesFor(ctx, userID, time.Now()))
+	if h.simplifyRecipeCommand == nil {
+		_ = h.bot.SendMessage(ctx, chatID, messageText)
+		return
+	}
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, messageText, RecipeViewKeyboard(converted.Recipe.ID, true, lang))
+}
+
+// handleRemix handles /remix <number> <goal>
+func (h *Handler) handleRemix(ctx context.Context, message *tgbotapi.Message, userID shared.ID, lang user.Language) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if len(args) < 2 {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Remix a Recipe*\n\n"+
+				"Get a version of a recipe adapted to a diet, with the ingredients swapped. The remix is saved as a new recipe linked to the original.\n\n"+
+				"*Usage:*\n"+
+				"/remix <number> <goal>\n\n"+
+				"*Examples:*\n"+
+				"/remix 3 vegan\n"+
+				"/remix 5 gluten-free")
+		return
+	}
+
+	recipeNum, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil || recipeNum < 1 {
+		_ = h.bot.SendError(ctx, chatID, "Invalid recipe number\\.")
+		return
+	}
+
+	h.handleRemixRecipe(ctx, chatID, userID, recipeNum, strings.Join(args[1:], " "), lang)
+}
+
+// handleRemixRecipe remixes a recipe, given by its number or the last one viewed, for a goal
+// such as a diet and shows the remix
+func (h *Handler) handleRemixRecipe(ctx context.Context, chatID int64, userID shared.ID, recipeNumber int, goal string, lang user.Language) {
+	if h.remixRecipeCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Recipe remixes are not available.")
+		return
+	}
+
+	goal = recipe.NormalizeRemixGoal(goal)
+	if goal == "" {
+		_ = h.bot.SendMessage(ctx, chatID, "What should the remix be? For example: /remix 3 vegan")
+		return
+	}
+
+	rec, ok := h.recipeByReference(ctx, chatID, userID, recipeNumber)
+	if !ok {
+		return
+	}
+
+	targetLang := "English"
+	if lang == user.LanguagePortuguese {
+		targetLang = "Portuguese"
+	}
+
+	_ = h.bot.SendProgress(ctx, chatID, fmt.Sprintf("Remixing %s to be %s...", rec.Title, goal))
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionTyping)()
+
+	remix, err := h.remixRecipeCommand.Execute(ctx, userID, recipe.RecipeID(rec.ID), goal, targetLang)
+	if errors.Is(err, shared.ErrNothingToRemix) {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("*%s* is already %s, nothing to swap.", escapeMarkdown(rec.Title), escapeMarkdown(goal)))
+		return
+	}
+	if err != nil {
+		log.Printf("Error remixing recipe: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to remix this recipe. Please try again.")
+		return
+	}
+
+	remixDTO, err := h.listRecipesQuery.ExecuteByID(ctx, userID, remix.ID())
+	if err != nil {
+		log.Printf("Error loading recipe %s: %v", remix.ID(), err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load the recipe. Please try again.")
+		return
+	}
+
+	h.recordActivity(ctx, userID, activity.ActionRecipeSaved, remix.Title())
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("✅ Saved *%s* as a remix of *%s*.", escapeMarkdown(remix.Title()), escapeMarkdown(rec.Title)))
+	h.sendRecipeDetails(ctx, chatID, userID, remixDTO, lang)
+}
+
+// handleApp handles /app by sending the button that opens the Mini App
+func (h *Handler) handleApp(ctx context.Context, chatID int64, userID shared.ID) {
+	if h.webAppURL == "" || !h.isEnabled(ctx, feature.FlagWebUI, userID) {
+		_ = h.bot.SendError(ctx, chatID, "The recipe web app is not available.")
+		return
+	}
+
+	_ = h.bot.SendWebAppButton(ctx, chatID,
+		"📱 Browse your recipes with pictures and filters.", "Open collection", h.webAppURL)
+}
+
+// handleAudio handles /audio <number>: reads the recipe aloud, ingredients and then
+// steps, in the user's language and sends it as voice messages
+func (h *Handler) handleAudio(ctx context.Context, message *tgbotapi.Message, userID shared.ID, lang user.Language) {
+	chatID := message.Chat.ID
+	if h.recipeAudioCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Recipe audio is not configured\\.")
+		return
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Listen to a Recipe*\n\n"+
+				"I read the ingredients and then the steps aloud, so you can cook with your hands busy.\n\n"+
+				"*Usage:*\n"+
+				"/audio <number>")
+		return
+	}
+
+	recipeNum, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		_ = h.bot.SendError(ctx, chatID, "Invalid recipe number\\.")
+		return
+	}
+	recipeDTO, err := h.listRecipesQuery.ExecuteByIndex(ctx, userID, recipeNum)
+	if err != nil {
+		_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Recipe #%d not found\\.", recipeNum))
+		return
+	}
+
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionRecordVoice)()
+
+	// Read in the user's language, as the recipe is shown to them
+	if translated := h.recipeTranslation(ctx, userID, recipeDTO, lang); translated != nil {
+		spoken := *recipeDTO
+		spoken.Title = translated.Title
+		spoken.Ingredients = translated.Ingredients
+		spoken.Instructions = translated.Instructions
+		recipeDTO = &spoken
+	}
+
+	parts, err := h.recipeAudioCommand.Execute(ctx, recipeDTO, lang)
+	if err != nil {
+		log.Printf("Error reading recipe aloud: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to read the recipe aloud. Please try again.")
+		return
+	}
+
+	for i, audio := range parts {
+		caption := "🔊 " + escapeMarkdown(recipeDTO.Title)
+		if len(parts) > 1 {
+			caption += fmt.Sprintf(" (%d/%d)", i+1, len(parts))
+		}
+		if err := h.bot.SendVoice(ctx, chatID, fmt.Sprintf("recipe-%d.ogg", i+1), audio, caption); err != nil {
+			log.Printf("Failed to send recipe audio: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to send the audio\\. Please try again\\.")
+			return
+		}
+	}
+}
+
+// exportCookbook sends all the user's recipes as a PDF cookbook, a premium feature
+// when premium is required for it
+func (h *Handler) exportCookbook(ctx context.Context, chatID int64, userID shared.ID, fields recipe.ExportFields) {
+	if !h.isEnabled(ctx, feature.FlagCookbookExport, userID) {
+		if h.features != nil && h.features.IsPremium(feature.FlagCookbookExport) && h.premiumCommand != nil {
+			_ = h.bot.SendMessage(ctx, chatID, "📖 The PDF cookbook is a premium feature. See /premium to get it.")
+			return
+		}
+		_ = h.bot.SendError(ctx, chatID, "The PDF cookbook is not available.")
+		return
+	}
+
+	recipes, err := h.listRecipesQuery.Execute(ctx, userID)
+	if err != nil {
+		log.Printf("Error listing recipes for cookbook: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load your recipes. Please try again.")
+		return
+	}
+	if len(recipes) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "You have no recipes to put in a cookbook yet. Send me a recipe link first.")
+		return
+	}
+
+	defer h.bot.KeepChatAction(ctx, chatID, ports.ChatActionUploadDocument)()
+	printed := make([]*printable.Recipe, 0, len(recipes))
+	for _, rec := range recipes {
+		p, err := printable.Prepare(rec, 0, fields)
+		if err != nil {
+			log.Printf("Error preparing %s for cookbook: %v", rec.ID, err)
+			continue
+		}
+		printed = append(printed, p)
+	}
+
+	pdf := printable.Cookbook("My Recipes", printed)
+	if err := h.bot.SendDocument(ctx, chatID, "cookbook.pdf", pdf, fmt.Sprintf("📖 Your cookbook, %d recipes", len(printed))); err != nil {
+		log.Printf("Failed to send cookbook: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to send file\\. Please try again\\.")
+		return
+	}
+	h.recordActivity(ctx, userID, activity.ActionRecipeExported, fmt.Sprintf("%d recipes to a PDF cookbook", len(printed)))
+}
+
+// handlePremium handles /premium: whether the user has the premium tier, with
+// buttons that buy each plan
+func (h *Handler) handlePremium(ctx context.Context, chatID int64, userID shared.ID) {
+	if h.premiumCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Premium is not available.")
+		return
+	}
+
+	now := time.Now()
+	until, err := h.premiumCommand.Status(ctx, userID, now)
+	if err != nil {
+		log.Printf("Error getting premium status: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load your premium status. Please try again.")
+		return
+	}
+
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatPremium(until, h.datesFor(ctx, userID, now)),
+		PremiumKeyboard(h.premiumCommand.Plans(), h.premiumCommand.Currency()))
+}
+
+// handlePremiumButton sends the invoice of the premium plan a /premium button is for
+func (h *Handler) handlePremiumButton(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, planID string) {
+	if h.premiumCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Premium is not available.")
+		return
+	}
+
+	invoice, err := h.premiumCommand.Invoice(userID, planID)
+	if err != nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This plan is no longer offered. Send /premium again.")
+		return
+	}
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+
+	title := fmt.Sprintf("Premium for %d days", invoice.Plan.Days)
+	description := "A higher hourly limit of recipe links and premium features like the PDF cookbook"
+	if err := h.bot.SendInvoice(ctx, cq.Message.Chat.ID, title, description, invoice.Payload,
+		h.paymentProviderToken, invoice.Currency, invoice.Plan.Price); err != nil {
+		log.Printf("Failed to send premium invoice: %v", err)
+		_ = h.bot.SendError(ctx, cq.Message.Chat.ID, "Failed to start the payment. Please try again.")
+	}
+}
+
+// handlePreCheckout confirms a payment for a premium plan before Telegram charges
+// it, or refuses it when the plan or its price changed since the invoice was sent
+func (h *Handler) handlePreCheckout(ctx context.Context, query *tgbotapi.PreCheckoutQuery) {
+	reason := ""
+	switch {
+	case h.premiumCommand == nil:
+		reason = "Premium is not available right now."
+	case h.premiumCommand.CheckPayment(query.Currency, query.TotalAmount, query.InvoicePayload) != nil:
+		reason = "This plan or its price has changed. Please send /premium again."
+	}
+
+	if err := h.bot.AnswerPreCheckout(ctx, query.ID, reason); err != nil {
+		log.Printf("Failed to answer pre-checkout query: %v", err)
+	}
+}
+
+// handleSuccessfulPayment gives the premium tier a payment was for
+func (h *Handler) handleSuccessfulPayment(ctx context.Context, chatID int64, userID shared.ID, payment *tgbotapi.SuccessfulPayment) {
+	if h.premiumCommand == nil {
+		log.Printf("Payment %s received with premium not configured", payment.TelegramPaymentChargeID)
+		return
+	}
+
+	now := time.Now()
+	paid, err := h.premiumCommand.CompletePayment(ctx, payment.TelegramPaymentChargeID, payment.Currency, payment.TotalAmount, payment.InvoicePayload, now)
+	if errors.Is(err, shared.ErrPaymentRecorded) {
+		log.Printf("Payment %s was delivered again and is already applied", payment.TelegramPaymentChargeID)
+		return
+	}
+	if err != nil {
+		// The user was charged: keep what is needed to refund them or grant premium by hand
+		log.Printf("Error completing payment %s (%s): %v", payment.TelegramPaymentChargeID, payment.InvoicePayload, err)
+		_ = h.bot.SendError(ctx, chatID, "Your payment went through but I couldn't activate premium. Please send /report so we can fix it.")
+		return
+	}
+
+	h.recordActivity(ctx, paid.UserID, activity.ActionPremiumPurchased,
+		fmt.Sprintf("%d-day plan, %d %s", paid.Days, paid.Amount, paid.Currency))
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("⭐ Thank you! You have premium until %s.", h.datesFor(ctx, userID, now).Date(paid.PremiumUntil)))
+}
+
+// handlePrint handles /print <number> [servings] [html] [--fields=<fields>]
+func (h *Handler) handlePrint(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args, fields, ok := h.exportFieldsFlag(ctx, chatID, userID, strings.Fields(message.CommandArguments()))
+	if !ok {
+		return
+	}
+
+	if len(args) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Print a Recipe*\n\n"+
+				"Get a clean plain-text copy of a recipe, ready to print on A4 or Letter paper.\n\n"+
+				"*Usage:*\n"+
+				"/print <number> \\[servings] \\[html] \\[--fields=<fields>]\n\n"+
+				"*Example:*\n"+
+				"/print 3 6 html")
+		return
+	}
+
+	recipeNum, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		_ = h.bot.SendError(ctx, chatID, "Invalid recipe number\\.")
+		return
+	}
+
+	servings := 0
+	withHTML := false
+	for _, arg := range args[1:] {
+		if strings.EqualFold(arg, "html") {
+			withHTML = true
+			continue
+		}
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			_ = h.bot.SendError(ctx, chatID, "Servings must be a positive number.")
+			return
+		}
+		servings = n
+	}
+
+	recipeDTO, err := h.listRecipesQuery.ExecuteByIndex(ctx, userID, recipeNum)
+	if err != nil {
+		_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Recipe #%d not found\\.", recipeNum))
+		return
+	}
+
+	printed, err := printable.Prepare(recipeDTO, servings, fields)
+	if err != nil {
+		_ = h.bot.SendMessage(ctx, chatID, "This recipe does not say how many servings it makes, so I can't scale it. Try /print without servings.")
+		return
+	}
+
+	if err := h.bot.SendDocument(ctx, chatID, printed.Filename("txt"), []byte(printed.Text()), "Printable copy of "+printed.Title); err != nil {
+		log.Printf("Failed to send printable recipe: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to send file\\. Please try again\\.")
+		return
+	}
+
+	if !withHTML {
+		return
+	}
+	page, err := printed.HTML()
+	if err != nil {
+		log.Printf("Failed to render printable page: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to create the HTML version.")
+		return
+	}
+	if err := h.bot.SendDocument(ctx, chatID, printed.Filename("html"), []byte(page), "Open in a browser to print"); err != nil {
+		log.Printf("Failed to send printable page: %v", err)
+	}
+}
+
+// handleFreezer handles /freezer [add <number> <portions> | eat <number> [portions] | first]
+func (h *Handler) handleFreezer(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if len(args) == 0 {
+		h.handleFreezerNatural(ctx, chatID, userID, ports.FreezerActionShow, 0, 0)
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add", "eat":
+		if len(args) < 2 || len(args) > 3 {
+			_ = h.bot.SendError(ctx, chatID, "Usage: /freezer add <number> <portions> or /freezer eat <number> [portions]")
+			return
+		}
+		recipeNumber, err := strconv.Atoi(strings.TrimPrefix(args[1], "#"))
+		if err != nil {
+			_ = h.bot.SendError(ctx, chatID, "Invalid recipe number.")
+			return
+		}
+		portions := 0
+		if len(args) == 3 {
+			portions, err = strconv.Atoi(args[2])
+			if err != nil || portions <= 0 {
+				_ = h.bot.SendError(ctx, chatID, "Portions must be a positive number.")
+				return
+			}
+		}
+		action := ports.FreezerActionAdd
+		if strings.ToLower(args[0]) == "eat" {
+			action = ports.FreezerActionRemove
+		}
+		h.handleFreezerNatural(ctx, chatID, userID, action, recipeNumber, portions)
+
+	case "first":
+		h.handleFreezerNatural(ctx, chatID, userID, ports.FreezerActionEatFirst, 0, 0)
+
+	default:
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Freezer*\n\n"+
+				"*Usage:*\n"+
+				"/freezer - Show what's in your freezer\n"+
+				"/freezer add <number> <portions> - Record frozen portions of a recipe\n"+
+				"/freezer eat <number> \\[portions] - Take portions out\n"+
+				"/freezer first - What to eat first")
+	}
+}
+
+// handleFreezerNatural runs a freezer action for a recipe number, from a command or natural language.
+// Portions default to 1 when eating and must be given when freezing.
+func (h *Handler) handleFreezerNatural(ctx context.Context, chatID int64, userID shared.ID, action ports.FreezerAction, recipeNumber int, portions int) {
+	if h.manageFreezerCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "The freezer inventory is not available.")
+		return
+	}
+
+	now := time.Now()
+
+	switch action {
+	case ports.FreezerActionAdd, ports.FreezerActionRemove:
+		if portions <= 0 {
+			if action == ports.FreezerActionAdd {
+				_ = h.bot.SendMessage(ctx, chatID, "How many portions did you freeze? Try \"/freezer add 7 3\".")
+				return
+			}
+			portions = 1
+		}
+
+		rec, ok := h.recipeByReference(ctx, chatID, userID, recipeNumber)
+		if !ok {
+			return
+		}
+		recipeID := recipe.RecipeID(rec.ID)
+
+		if action == ports.FreezerActionAdd {
+			f, err := h.manageFreezerCommand.Freeze(ctx, userID, recipeID, portions, now)
+			if err != nil {
+				log.Printf("Error updating freezer: %v", err)
+				_ = h.bot.SendError(ctx, chatID, "Failed to update your freezer. Please try again.")
+				return
+			}
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("✅ Added %d portion(s) to your freezer.\n\n", portions)+FormatFreezer(f, h.datesFor(ctx, userID, now)))
+			return
+		}
+
+		f, taken, err := h.manageFreezerCommand.Take(ctx, userID, recipeID, portions)
+		if err != nil {
+			if errors.Is(err, shared.ErrNotInFreezer) {
+				_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("%s isn't in your freezer.", escapeMarkdown(rec.Title)))
+				return
+			}
+			log.Printf("Error updating freezer: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to update your freezer. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("✅ Took %d portion(s) out of your freezer.\n\n", taken)+FormatFreezer(f, h.datesFor(ctx, userID, now)))
+
+	case ports.FreezerActionEatFirst:
+		batches, err := h.manageFreezerCommand.EatFirst(ctx, userID, now)
+		if err != nil {
+			log.Printf("Error getting freezer: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to load your freezer. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatFreezerEatFirst(batches, h.datesFor(ctx, userID, now)))
+
+	default: // FreezerActionShow
+		f, err := h.manageFreezerCommand.Contents(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting freezer: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to load your freezer. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatFreezer(f, h.datesFor(ctx, userID, now)))
+	}
+}
+
+// handleRequests handles the /requests command: the dishes household members
+// asked each other to cook, shared by everyone in a group chat
+func (h *Handler) handleRequests(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+	userID := usr.ID()
+	args := strings.Fields(message.CommandArguments())
+
+	if h.manageRequestsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Dish requests are not available.")
+		return
+	}
+
+	ownerID, ok := h.requestsOwner(ctx, chatID, userID)
+	if !ok {
+		return
+	}
+
+	if len(args) == 0 {
+		l, err := h.manageRequestsCommand.List(ctx, ownerID)
+		if err != nil {
+			log.Printf("Error getting dish requests: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to load the requests. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatRequests(l))
+		return
+	}
+
+	member := memberOf(message.From, usr)
+	now := time.Now()
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		h.handleRequestDish(ctx, chatID, member, strings.Join(args[1:], " "))
+
+	case "plan":
+		if len(args) != 4 {
+			_ = h.bot.SendError(ctx, chatID, "Usage: /requests plan <request> <recipe number> <day>\nExample: /requests plan 1 4 wednesday")
+			return
+		}
+		number, ok := h.requestNumber(ctx, chatID, args[1])
+		if !ok {
+			return
+		}
+		day, ok := mealplan.ParseDay(args[3])
+		if !ok {
+			_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Unknown day: %s", escapeMarkdown(args[3])))
+			return
+		}
+		recipeID, ok := h.recipeIDByNumber(ctx, chatID, userID, strings.TrimPrefix(args[2], "#"))
+		if !ok {
+			return
+		}
+
+		req, err := h.manageRequestsCommand.Plan(ctx, ownerID, number, member, recipeID, day, now)
+		if err != nil {
+			h.sendRequestError(ctx, chatID, number, err)
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📅 %s is on your meal plan for %s, for %s's request for %s.",
+			escapeMarkdown(req.Plan.Title), day, escapeMarkdown(req.Requester.Name), escapeMarkdown(req.Dish)))
+
+	case "cooked", "remove":
+		if len(args) != 2 {
+			_ = h.bot.SendError(ctx, chatID, "Usage: /requests cooked <request> or /requests remove <request>")
+			return
+		}
+		number, ok := h.requestNumber(ctx, chatID, args[1])
+		if !ok {
+			return
+		}
+
+		req, err := h.manageRequestsCommand.Remove(ctx, ownerID, number)
+		if err != nil {
+			h.sendRequestError(ctx, chatID, number, err)
+			return
+		}
+		if strings.ToLower(args[0]) == "remove" {
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🗑️ Removed the request for %s.", escapeMarkdown(req.Dish)))
+			return
+		}
+
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🍽️ %s cooked %s! I'll let %s know.",
+			escapeMarkdown(member.Name), escapeMarkdown(req.Dish), escapeMarkdown(req.Requester.Name)))
+		// The requester hears it privately, unless they marked it cooked themselves
+		if req.Requester.TelegramID != 0 && req.Requester.TelegramID != message.From.ID {
+			_ = h.bot.SendMessage(ctx, req.Requester.TelegramID, fmt.Sprintf("🍽️ %s cooked the %s you asked for. Enjoy!",
+				escapeMarkdown(member.Name), escapeMarkdown(req.Dish)))
+		}
+
+	default:
+		_ = h.bot.SendMessage(ctx, chatID,
+			"*Requests*\n\n"+
+				"*Usage:*\n"+
+				"/requests - Show what the household asked for\n"+
+				"/requests add <dish> - Ask someone to cook a dish\n"+
+				"/requests plan <request> <recipe number> <day> - Plan a recipe for a request\n"+
+				"/requests cooked <request> - Mark a request cooked and tell who asked\n"+
+				"/requests remove <request> - Call a request off")
+	}
+}
+
+// handleRequestDish adds a dish a member asked for to the chat's request list,
+// from a command or natural language ("someone please make lasagna this week")
+func (h *Handler) handleRequestDish(ctx context.Context, chatID int64, requester requests.Member, dish string) {
+	if h.manageRequestsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Dish requests are not available.")
+		return
+	}
+	if requests.NormalizeDish(dish) == "" {
+		_ = h.bot.SendMessage(ctx, chatID, "What should someone cook? Try \"/requests add lasagna\".")
+		return
+	}
+
+	ownerID, ok := h.requestsOwner(ctx, chatID, requester.ID)
+	if !ok {
+		return
+	}
+
+	req, err := h.manageRequestsCommand.Request(ctx, ownerID, dish, requester, time.Now())
+	if err != nil {
+		if errors.Is(err, shared.ErrInvalidInput) {
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("There are already %d open requests. Cook or remove some with /requests first.", requests.MaxOpen))
+			return
+		}
+		log.Printf("Error adding dish request: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to add the request. Please try again.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🙏 Added %s to the requests. Whoever cooks it can use /requests to plan it.", escapeMarkdown(req.Dish)))
+}
+
+// sendRequestError tells the user why a request with a number could not be changed
+func (h *Handler) sendRequestError(ctx context.Context, chatID int64, number int, err error) {
+	if errors.Is(err, shared.ErrNoSuchRequest) {
+		_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Request #%d not found. Use /requests to see the list.", number))
+		return
+	}
+	log.Printf("Error updating dish requests: %v", err)
+	_ = h.bot.SendError(ctx, chatID, "Failed to update the requests. Please try again.")
+}
+
+// requestsOwner returns whose request list a chat uses: the household of a group
+// chat (group chat IDs are negative), or the user's own in a private chat
+func (h *Handler) requestsOwner(ctx context.Context, chatID int64, userID shared.ID) (shared.ID, bool) {
+	if chatID >= 0 {
+		return userID, true
+	}
+
+	household, err := h.getOrCreateUserCommand.Household(ctx, chatID, "")
+	if err != nil {
+		log.Printf("Error getting household: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to get the group's requests. Please try again.")
+		return "", false
+	}
+	return household.ID(), true
+}
+
+// requesterByID names a member from their account, for messages without a sender
+func (h *Handler) requesterByID(ctx context.Context, userID shared.ID) requests.Member {
+	if h.userRepo == nil {
+		return requests.Member{ID: userID, Name: "Someone"}
+	}
+	usr, err := h.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return requests.Member{ID: userID, Name: "Someone"}
+	}
+	return memberOf(nil, usr)
+}
+
+// memberOf names a household member: their first name, or their username
+func memberOf(from *tgbotapi.User, usr *user.User) requests.Member {
+	member := requests.Member{ID: usr.ID(), TelegramID: usr.TelegramID(), Name: "Someone"}
+	if usr.Username() != "" {
+		member.Name = usr.Username()
+	}
+	if from != nil {
+		member.TelegramID = from.ID
+		if from.FirstName != "" {
+			member.Name = from.FirstName
+		} else if from.UserName != "" {
+			member.Name = from.UserName
+		}
+	}
+	return member
+}
+
+// requestNumber parses the number of a request, as listed by /requests
+func (h *Handler) requestNumber(ctx context.Context, chatID int64, arg string) (int, bool) {
+	number, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || number <= 0 {
+		_ = h.bot.SendError(ctx, chatID, "Invalid request number.")
+		return 0, false
+	}
+	return number, true
+}
+
+// handleLink handles /link [code]. Without a code it creates one for the
+// user's collection; with a code it links the sender's account to it
+func (h *Handler) handleLink(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+
+	if h.linkAccountCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Account linking is not available.")
+		return
+	}
+
+	code := strings.TrimSpace(message.CommandArguments())
+	if code == "" {
+		linkCode, err := h.linkAccountCommand.CreateCode(ctx, usr.ID(), time.Now())
+		if err != nil {
+			log.Printf("Error creating link code: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to create a link code. Please try again.")
+			return
+		}
+
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(
+			"🔗 *Link another account*\n\n"+
+				"On your other Telegram account, send:\n"+
+				"`/link %s`\n\n"+
+				"The code works once and expires in %d minutes. Both accounts will share this recipe collection.",
+			linkCode.Code, int(user.LinkCodeTTL.Minutes())))
+		return
+	}
+
+	owner, err := h.linkAccountCommand.Redeem(ctx, code, message.From.ID, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, shared.ErrInvalidLinkCode):
+			_ = h.bot.SendMessage(ctx, chatID, "That code is invalid or expired. Send /link on your other account to get a new one.")
+		case errors.Is(err, shared.ErrAlreadyLinked):
+			_ = h.bot.SendMessage(ctx, chatID, "This account already uses that collection.")
+		default:
+			log.Printf("Error linking account: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to link your account. Please try again.")
+		}
+		return
+	}
+
+	name := "your other account"
+	if owner.Username() != "" {
+		name = "@" + escapeMarkdown(owner.Username())
+	}
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(
+		"✅ Linked! This account now shares the recipe collection of %s.\n\n"+
+			"Send /unlink to go back to this account's own collection.", name))
+}
+
+// handleUnlink handles /unlink, detaching the sender's account from the collection it was linked to
+func (h *Handler) handleUnlink(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	if h.linkAccountCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Account linking is not available.")
+		return
+	}
+
+	if err := h.linkAccountCommand.Unlink(ctx, message.From.ID); err != nil {
+		if errors.Is(err, shared.ErrNotLinked) {
+			_ = h.bot.SendMessage(ctx, chatID, "This account isn't linked to another collection.")
+			return
+		}
+		log.Printf("Error unlinking account: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to unlink your account. Please try again.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, "✅ Unlinked. This account is back to its own recipe collection.")
+}
+
+// guestStartPrefix marks /start payloads that open a guest share
+const guestStartPrefix = "guest_"
+
+// handleShare handles /share [days] [category] and /share stop
+func (h *Handler) handleShare(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if h.shareCollectionCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Sharing is not available.")
+		return
+	}
+
+	if len(args) == 1 && strings.EqualFold(args[0], "stop") {
+		if err := h.shareCollectionCommand.Revoke(ctx, userID); err != nil {
+			log.Printf("Error revoking guest shares: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to close your guest links. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, "🔒 All your guest links are closed.")
+		return
+	}
+
+	ttl := share.DefaultTTL
+	if len(args) > 0 {
+		if days, err := strconv.Atoi(args[0]); err == nil {
+			ttl = time.Duration(days) * 24 * time.Hour
+			args = args[1:]
+		}
+	}
+
+	var category *recipe.Category
+	what := "all your recipes"
+	if len(args) > 0 {
+		name := strings.Join(args, " ")
+		c := recipe.ParseCategory(name)
+		if c == recipe.CategoryOther && !strings.EqualFold(name, "other") {
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("Unknown category: %s\n\nUse /categories to see your categories.", escapeMarkdown(name)))
+			return
+		}
+		category = &c
+		what = fmt.Sprintf("your %s recipes", escapeMarkdown(string(c)))
+	}
+
+	s, err := h.shareCollectionCommand.Create(ctx, userID, category, ttl, time.Now())
+	if err != nil {
+		if errors.Is(err, shared.ErrInvalidInput) {
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(
+				"A guest link can stay open for 1 to %d days.\n\nUsage: /share \\[days] \\[category]", int(share.MaxTTL.Hours()/24)))
+			return
+		}
+		log.Printf("Error creating guest share: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to create a guest link. Please try again.")
+		return
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(
+		"🔗 *Guest link ready*\n\n"+
+			"Friends can browse %s until %s, without being able to change anything:\n"+
+			"[Open the recipes](https://t.me/%s?start=%s%s)\n\n"+
+			"Or they can send this to me: /guest %s\n\n"+
+			"Send /share stop to close all your guest links.",
+		what, h.datesFor(ctx, userID, time.Now()).DayTime(s.ExpiresAt), h.bot.Username(), guestStartPrefix, s.Token, s.Token))
+
+	h.screenShare(ctx, chatID, s)
+}
+
+// screenShare checks the recipes of a new guest share, tells the owner which ones guests
+// won't see and lets the admin know when some wait for a review
+func (h *Handler) screenShare(ctx context.Context, chatID int64, s *share.GuestShare) {
+	if h.moderateContentCommand == nil {
+		return
+	}
+
+	result, err := h.moderateContentCommand.ScreenShare(ctx, s, time.Now())
+	if err != nil {
+		log.Printf("Error screening guest share: %v", err)
+		return
+	}
+	if note := FormatShareScreening(result); note != "" {
+		_ = h.bot.SendMessage(ctx, chatID, note)
+	}
+
+	if len(result.Queued) > 0 && h.adminChatID != 0 {
+		if err := h.bot.SendMessage(ctx, h.adminChatID, fmt.Sprintf("🛡️ %d shared recipes wait for a review. Send /review to check them.", len(result.Queued))); err != nil {
+			log.Printf("Failed to notify the admin chat of recipes to review: %v", err)
+		}
+	}
+}
+
+// sendNextReview shows the admin the oldest shared recipe waiting for a review
+func (h *Handler) sendNextReview(ctx context.Context, chatID int64) {
+	pending, err := h.moderateContentCommand.Pending(ctx)
+	if err != nil {
+		log.Printf("Error loading the review queue: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load the review queue. Please try again.")
+		return
+	}
+	if len(pending) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "✅ No shared recipes wait for a review.")
+		return
+	}
+
+	d := pending[0]
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatModerationReview(d, len(pending)), ModerationReviewKeyboard(d.RecipeID.String()))
+}
+
+// handleInspect shows the admin how long processing a recipe took and what it cost
+func (h *Handler) handleInspect(ctx context.Context, chatID int64, args string) {
+	recipeID := strings.TrimSpace(args)
+	if recipeID == "" {
+		_ = h.bot.SendMessage(ctx, chatID, "Usage: /inspect <recipe id>")
+		return
+	}
+
+	rep, err := h.processingReports.FindProcessingReport(ctx, recipe.RecipeID(recipeID))
+	if errors.Is(err, shared.ErrNoProcessingReport) {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("No processing report for recipe %s.", escapeMarkdown(recipeID)))
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading the processing report of recipe %s: %v", recipeID, err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load the processing report. Please try again.")
+		return
+	}
+	_ = h.bot.SendMessage(ctx, chatID, FormatProcessingReport(rep))
+}
+
+// handleModerationReview approves or rejects a shared recipe from the admin chat,
+// then shows the next one waiting
+func (h *Handler) handleModerationReview(ctx context.Context, cq *tgbotapi.CallbackQuery, payload string) {
+	chatID := cq.Message.Chat.ID
+	vote, recipeID, ok := strings.Cut(payload, ":")
+	if h.moderateContentCommand == nil || h.adminChatID == 0 || chatID != h.adminChatID || !ok {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	noButtons := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	d, err := h.moderateContentCommand.Review(ctx, recipe.RecipeID(recipeID), vote == "1", time.Now())
+	if errors.Is(err, shared.ErrModerationNotFound) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This recipe was already reviewed.")
+		_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, noButtons)
+		return
+	}
+	if err != nil {
+		log.Printf("Error reviewing shared recipe: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to save the review. Please try again.")
+		return
+	}
+
+	answer := "Rejected"
+	if d.Verdict == moderation.VerdictAllowed {
+		answer = "Approved"
+	}
+	_ = h.bot.AnswerCallback(ctx, cq.ID, answer)
+	_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, noButtons)
+	h.sendNextReview(ctx, chatID)
+}
+
+// handleReport handles /report [what happened], filing the user's last failed link
+// and forwarding it to the admin chat if one is configured
+func (h *Handler) handleReport(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+
+	if h.reportErrorCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Error reports are not available.")
+		return
+	}
+
+	rep, err := h.reportErrorCommand.Execute(ctx, userID, message.CommandArguments())
+	if errors.Is(err, shared.ErrNothingToReport) {
+		_ = h.bot.SendMessage(ctx, chatID, "Nothing to report. Send /report right after a link fails to process.")
+		return
+	}
+	if err != nil {
+		log.Printf("Error filing error report: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to send the report. Please try again.")
+		return
+	}
+
+	if h.adminChatID != 0 {
+		if err := h.bot.SendMessage(ctx, h.adminChatID, FormatErrorReport(rep)); err != nil {
+			log.Printf("Error forwarding error report %s: %v", rep.ID, err)
+		}
+	}
+
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📨 Thanks! Report %s was sent with the details of the failed link.", rep.ID))
+}
+
+// recipeStartPrefix marks /start payloads that open one of the user's recipes
+const recipeStartPrefix = "recipe_"
+
+// RecipeDeepLink returns the link opening a recipe in the bot
+func RecipeDeepLink(botUsername, recipeID string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s%s", botUsername, recipeStartPrefix, recipeID)
+}
+
+// handleStartRecipe shows the recipe a deep link points to
+func (h *Handler) handleStartRecipe(ctx context.Context, chatID int64, userID shared.ID, recipeID string, lang user.Language) {
+	recipes, err := h.listRecipesQuery.Execute(ctx, userID)
+	if err != nil {
+		log.Printf("Error listing recipes: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load the recipe. Please try again.")
+		return
+	}
+
+	for _, rec := range recipes {
+		if rec.ID == recipeID {
+			h.sendRecipeDetails(ctx, chatID, userID, rec, lang)
+			return
+		}
+	}
+	_ = h.bot.SendMessage(ctx, chatID, "That recipe isn't in your collection. Use /recipes to see your recipes.")
+}
+
+// handleClip handles /clip, giving the user the API token of the browser extension,
+// and /clip reset, replacing it with a new one
+func (h *Handler) handleClip(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
+	if h.clipRecipeCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "The browser extension is not available.")
+		return
+	}
+
+	server := ""
+	if h.clipAPIURL != "" {
+		server = fmt.Sprintf("Server: `%s`\n", h.clipAPIURL)
+	}
 
-	// Build the clarification message with options
-	msg := intent.ClarifyingQuestion
-	if len(intent.ClarifyingOptions) > 0 {
-		msg += "\n\nOptions:\n"
-		for i, option := range intent.ClarifyingOptions {
-			msg += fmt.Sprintf("%d. %s\n", i+1, option)
+	if strings.EqualFold(strings.TrimSpace(message.CommandArguments()), "reset") {
+		token, err := h.clipRecipeCommand.ResetToken(ctx, usr)
+		if err != nil {
+			log.Printf("Error resetting clip token: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to reset your token. Please try again.")
+			return
 		}
-		msg += "\nYou can reply with a number or type your preference."
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(
+			"🧩 *New browser extension token*\n\n"+
+				"Your previous token no longer works. Paste this one in the extension settings:\n"+
+				"`%s`\n%s",
+			token, server))
+		return
 	}
 
-	// Add the assistant's clarifying question to history
-	h.conversationManager.AddTurn(userID, "assistant", msg)
-
-	_ = h.bot.SendMessage(ctx, chatID, msg)
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(
+		"🧩 *Browser extension*\n\n"+
+			"Paste this token in the extension settings to save recipes from any page you are on:\n"+
+			"`%s`\n%s\n"+
+			"Anyone with this token can add recipes to your collection, so keep it private. "+
+			"If it leaks, send /clip reset to replace it.",
+		h.clipRecipeCommand.Token(usr), server))
 }
 
-// handleClarificationResponse handles the user's response to a clarifying question
-func (h *Handler) handleClarificationResponse(ctx context.Context, chatID int64, userID shared.ID, text string, lang user.Language) {
-	pending := h.conversationManager.GetPendingClarification(userID)
-	if pending == nil {
-		// No pending clarification, treat as normal message
-		h.conversationManager.SetState(userID, StateIdle)
+// handleEmail handles /email, giving the user the address they forward recipe emails to
+func (h *Handler) handleEmail(ctx context.Context, chatID int64, usr *user.User) {
+	if h.forwardEmailCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Email forwarding is not available.")
 		return
 	}
 
-	// Clear the pending clarification
-	h.conversationManager.ClearPendingClarification(userID)
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(
+		"📧 *Your recipe inbox*\n\n"+
+			"Forward newsletters and recipe emails to:\n`%s`\n\n"+
+			"I'll save the recipe they hold, or the recipes they link to, and send it to you here.",
+		h.forwardEmailCommand.Address(usr)))
+}
 
-	// Add the user's response to history
-	h.conversationManager.AddTurn(userID, "user", text)
+// handleSubscribe handles /subscribe <feed link>, following a recipe blog so the
+// recipes of its new posts are proposed. Without a link it lists the followed blogs.
+func (h *Handler) handleSubscribe(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
 
-	// Check if the user selected an option by number
-	selectedText := text
-	if num, err := strconv.Atoi(strings.TrimSpace(text)); err == nil && num > 0 && num <= len(pending.Options) {
-		selectedText = pending.Options[num-1]
+	if h.manageSubscriptionsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Recipe blog subscriptions are not available.")
+		return
 	}
 
-	// Combine the original message with the clarification response for intent detection
-	combinedQuery := pending.OriginalMessage + " " + selectedText
-
-	// Re-run intent detection with the combined context
-	if h.intentDetector != nil {
-		history := h.conversationManager.GetHistory(userID)
-		intent, err := h.intentDetector.DetectIntentWithContext(ctx, combinedQuery, history)
+	link := strings.TrimSpace(message.CommandArguments())
+	if link == "" {
+		subscriptions, err := h.manageSubscriptionsCommand.List(ctx, userID)
 		if err != nil {
-			log.Printf("Intent detection error after clarification: %v", err)
-		} else if intent != nil && intent.Type != ports.IntentUnknown && intent.Confidence >= 0.5 {
-			h.handleIntent(ctx, chatID, userID, intent, lang)
+			log.Printf("Error loading subscriptions: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to load your subscriptions. Please try again.")
 			return
 		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatSubscriptions(subscriptions))
+		return
 	}
 
-	// Fallback: couldn't understand the clarification response
-	t := GetTranslations(lang)
-	_ = h.bot.SendMessage(ctx, chatID,
-		"I still couldn't understand that. Let's start over.\n\n"+
-			t.UseHelpCmd)
+	_ = h.bot.SendProgress(ctx, chatID, "📰 Reading the feed...")
+
+	s, err := h.manageSubscriptionsCommand.Subscribe(ctx, userID, link, time.Now())
+	switch {
+	case errors.Is(err, shared.ErrInvalidURL):
+		_ = h.bot.SendMessage(ctx, chatID, "Usage: /subscribe <feed link>\n\nSend the RSS or Atom feed link of a recipe blog, such as https://smittenkitchen.com/feed/")
+	case errors.Is(err, shared.ErrAlreadySubscribed):
+		_ = h.bot.SendMessage(ctx, chatID, "You already follow this feed. Send /subscribe to see your subscriptions.")
+	case errors.Is(err, shared.ErrTooManySubscriptions):
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("You can follow up to %d feeds. Use /unsubscribe <number> to stop following one first.", feed.MaxSubscriptions))
+	case errors.Is(err, shared.ErrFeedUnreadable):
+		_ = h.bot.SendError(ctx, chatID, "I couldn't read a feed at this link. Check that it is the blog's RSS or Atom feed.")
+	case err != nil:
+		log.Printf("Error subscribing to feed: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to subscribe. Please try again.")
+	default:
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf(
+			"✅ Following *%s*\n\nI'll send you the recipes of its new posts to save in one tap.", escapeMarkdown(s.Title)))
+	}
 }
 
-// handleRefine refines previous search results with new filters
-func (h *Handler) handleRefine(ctx context.Context, chatID int64, userID shared.ID, intent *ports.Intent, lang user.Language) {
-	// Get active filters from conversation manager
-	activeFilters := h.conversationManager.GetActiveFilters(userID)
+// handleUnsubscribe handles /unsubscribe <number>, stopping following a recipe blog
+func (h *Handler) handleUnsubscribe(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
 
-	// Merge new intent filters with existing active filters
-	mergedFilters := &ActiveFilters{}
-	if activeFilters != nil {
-		// Copy existing filters
-		mergedFilters.Category = activeFilters.Category
-		mergedFilters.DietaryTags = append([]recipe.DietaryTag{}, activeFilters.DietaryTags...)
-		mergedFilters.IngredientFilter = activeFilters.IngredientFilter
-		mergedFilters.SearchTerm = activeFilters.SearchTerm
+	if h.manageSubscriptionsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Recipe blog subscriptions are not available.")
+		return
 	}
 
-	// Apply new filters from intent
-	if intent.Category != nil {
-		mergedFilters.Category = intent.Category
-	}
-	if len(intent.DietaryTags) > 0 {
-		// Add new dietary tags without duplicates
-		existingTags := make(map[recipe.DietaryTag]bool)
-		for _, tag := range mergedFilters.DietaryTags {
-			existingTags[tag] = true
-		}
-		for _, tag := range intent.DietaryTags {
-			if !existingTags[tag] {
-				mergedFilters.DietaryTags = append(mergedFilters.DietaryTags, tag)
-			}
-		}
+	index, err := strconv.Atoi(strings.TrimSpace(message.CommandArguments()))
+	if err != nil {
+		_ = h.bot.SendMessage(ctx, chatID, "Usage: /unsubscribe <number>\n\nSend /subscribe to see the numbers of the feeds you follow.")
+		return
 	}
-	if intent.IngredientFilter != nil {
-		// Merge ingredient filters
-		if mergedFilters.IngredientFilter == nil {
-			mergedFilters.IngredientFilter = intent.IngredientFilter
-		} else {
-			// Combine Include (AND logic)
-			mergedFilters.IngredientFilter.Include = append(
-				mergedFilters.IngredientFilter.Include,
-				intent.IngredientFilter.Include...,
-			)
-			// Combine Optional (OR logic)
-			mergedFilters.IngredientFilter.Optional = append(
-				mergedFilters.IngredientFilter.Optional,
-				intent.IngredientFilter.Optional...,
-			)
-			// Combine Exclude (NOT logic)
-			mergedFilters.IngredientFilter.Exclude = append(
-				mergedFilters.IngredientFilter.Exclude,
-				intent.IngredientFilter.Exclude...,
-			)
-		}
+
+	s, err := h.manageSubscriptionsCommand.Unsubscribe(ctx, userID, index)
+	if errors.Is(err, shared.ErrSubscriptionNotFound) {
+		_ = h.bot.SendMessage(ctx, chatID, "There is no feed with that number. Send /subscribe to see the feeds you follow.")
+		return
 	}
-	if intent.SearchTerm != "" {
-		mergedFilters.SearchTerm = intent.SearchTerm
+	if err != nil {
+		log.Printf("Error unsubscribing from feed: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to unsubscribe. Please try again.")
+		return
 	}
 
-	// Update active filters
-	h.conversationManager.SetActiveFilters(userID, mergedFilters)
-
-	// Re-execute the search with merged filters
-	if mergedFilters.IngredientFilter != nil {
-		h.handleComplexSearch(ctx, chatID, userID, mergedFilters.IngredientFilter, mergedFilters.DietaryTags)
-	} else if mergedFilters.Category != nil || len(mergedFilters.DietaryTags) > 0 {
-		h.handleCompoundQuery(ctx, chatID, userID, mergedFilters.Category, mergedFilters.DietaryTags)
-	} else if mergedFilters.SearchTerm != "" {
-		h.handleSearchByIngredient(ctx, chatID, userID, mergedFilters.SearchTerm)
-	} else {
-		// No filters to refine, just list recipes
-		h.handleListRecipesNatural(ctx, chatID, userID, nil, "")
-	}
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🗑️ Stopped following *%s*.", escapeMarkdown(s.Title)))
 }
 
-// handleCompoundQuery handles queries combining category and dietary tags
-func (h *Handler) handleCompoundQuery(ctx context.Context, chatID int64, userID shared.ID, category *recipe.Category, dietaryTags []recipe.DietaryTag) {
-	recipes, err := h.listRecipesQuery.ExecuteByFilters(ctx, userID, category, dietaryTags)
-	if err != nil {
-		log.Printf("Error filtering recipes: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to filter recipes. Please try again.")
+// handleFeedSave saves the recipe of a new post of a followed feed
+func (h *Handler) handleFeedSave(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, postID string) {
+	if h.manageSubscriptionsCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
 		return
 	}
 
-	// Build filter description
-	var filterParts []string
-	if len(dietaryTags) > 0 {
-		for _, tag := range dietaryTags {
-			filterParts = append(filterParts, string(tag))
-		}
-	}
-	if category != nil {
-		filterParts = append(filterParts, string(*category))
+	chatID := cq.Message.Chat.ID
+	noButtons := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+
+	rec, err := h.manageSubscriptionsCommand.Save(ctx, userID, postID)
+	if errors.Is(err, shared.ErrNoPendingRecipes) {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "This recipe is no longer available. Send the post link to save it.")
+		_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, noButtons)
+		return
 	}
-	filterDesc := strings.Join(filterParts, " ")
-	if filterDesc == "" {
-		filterDesc = "filtered"
+	if err != nil {
+		log.Printf("Error saving feed recipe: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to save the recipe. Please try again.")
+		return
 	}
 
-	// Store in conversation context
-	h.conversationManager.UpdateCategoryFilter(userID, category, recipes)
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "Saved")
+	h.recordActivity(ctx, userID, activity.ActionRecipeSaved, rec.Title())
+	_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, noButtons)
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("✅ Saved *%s* to your recipes. Use /recipes to see your collection.", escapeMarkdown(rec.Title())))
+}
 
-	msg := fmt.Sprintf("📚 *%s Recipes* (%d found)\n\n", strings.Title(filterDesc), len(recipes))
+// bookmarkProgressEvery is how many links of a bookmark import pass between progress messages
+const bookmarkProgressEvery = 25
 
-	if len(recipes) == 0 {
-		msg = fmt.Sprintf("📭 No recipes found matching: %s\n\n"+
-			"Try a different combination or use /categories to see what you have.", filterDesc)
-	} else {
-		for i, recipeDTO := range recipes {
-			if i >= 10 {
-				msg += fmt.Sprintf("\n... and %d more recipes. Say \"show more\" to see them.", len(recipes)-10)
-				break
-			}
+// handleDocument previews the recipe links of a bookmark file exported from a browser
+func (h *Handler) handleDocument(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
 
-			msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.Title)
-			msg += fmt.Sprintf("   _%s_ | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform)
-		}
+	if h.importBookmarksCommand == nil {
+		_ = h.bot.SendMessage(ctx, chatID, "Send me a recipe link, or use /help to see what I can do.")
+		return
+	}
 
-		if len(recipes) <= 10 {
-			msg += "\nSay \"details on #X\" to view a recipe"
-		}
+	name := strings.ToLower(message.Document.FileName)
+	if !strings.HasSuffix(name, ".html") && !strings.HasSuffix(name, ".htm") {
+		_ = h.bot.SendMessage(ctx, chatID, "I can only import bookmark files (bookmarks.html) exported from your browser.")
+		return
 	}
 
-	_ = h.bot.SendMessage(ctx, chatID, msg)
-}
+	data, err := h.bot.DownloadFile(ctx, message.Document.FileID)
+	if err != nil {
+		log.Printf("Error downloading bookmark file: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to download the file. Please try again.")
+		return
+	}
 
-// handleComplexSearch handles complex ingredient searches with filters and dietary tags
-func (h *Handler) handleComplexSearch(ctx context.Context, chatID int64, userID shared.ID, filter *recipe.IngredientFilter, dietaryTags []recipe.DietaryTag) {
-	recipes, err := h.listRecipesQuery.SearchByIngredientFilterWithTags(ctx, userID, filter, dietaryTags)
+	marks, err := bookmarks.ParseNetscape(data)
 	if err != nil {
-		log.Printf("Error searching recipes with filter: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to search recipes. Please try again.")
+		_ = h.bot.SendMessage(ctx, chatID, "This file is not a bookmark export. In your browser's bookmark manager, choose Export bookmarks and send me that file.")
 		return
 	}
 
-	// Build filter description
-	var filterParts []string
-	if filter != nil {
-		if len(filter.Include) > 0 {
-			filterParts = append(filterParts, "with "+strings.Join(filter.Include, " and "))
-		}
-		if len(filter.Optional) > 0 {
-			filterParts = append(filterParts, "with any of: "+strings.Join(filter.Optional, ", "))
-		}
-		if len(filter.Exclude) > 0 {
-			filterParts = append(filterParts, "without "+strings.Join(filter.Exclude, ", "))
-		}
+	links, err := h.importBookmarksCommand.Preview(usr.ID(), marks)
+	if errors.Is(err, shared.ErrNoRecipeLinks) {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("I read %d bookmarks, but none of them look like recipes.", len(marks)))
+		return
 	}
-	if len(dietaryTags) > 0 {
-		for _, tag := range dietaryTags {
-			filterParts = append(filterParts, string(tag))
-		}
+	if errors.Is(err, shared.ErrImportRunning) {
+		_ = h.bot.SendMessage(ctx, chatID, "Your previous bookmark import is still running. Stop it or wait for it to finish first.")
+		return
 	}
-	filterDesc := strings.Join(filterParts, ", ")
-	if filterDesc == "" {
-		filterDesc = "filtered"
+	if err != nil {
+		log.Printf("Error previewing bookmark import: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to read your bookmarks. Please try again.")
+		return
 	}
 
-	// Store in conversation context
-	h.conversationManager.UpdateLastRecipes(userID, ActionFilterIngredient, recipes)
-	h.conversationManager.SetActiveFilters(userID, &ActiveFilters{
-		DietaryTags:      dietaryTags,
-		IngredientFilter: filter,
-	})
-
-	msg := fmt.Sprintf("🔍 *Recipes %s* (%d found)\n\n", filterDesc, len(recipes))
-
-	if len(recipes) == 0 {
-		msg = fmt.Sprintf("📭 No recipes found matching: %s\n\n"+
-			"Try a different combination or use /recipes to see all your recipes.", filterDesc)
-	} else {
-		for i, recipeDTO := range recipes {
-			if i >= 10 {
-				msg += fmt.Sprintf("\n... and %d more recipes. Say \"show more\" to see them.", len(recipes)-10)
-				break
-			}
-
-			msg += fmt.Sprintf("%d. %s\n", i+1, recipeDTO.Title)
-			msg += fmt.Sprintf("   _%s_ | %s\n", recipeDTO.Category, recipeDTO.SourcePlatform)
-		}
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID,
+		FormatBookmarkPreview(len(marks), links, h.importBookmarksCommand.Interval()),
+		BookmarkImportKeyboard(len(links)))
+}
 
-		if len(recipes) <= 10 {
-			msg += "\nSay \"details on #X\" to view a recipe"
-		}
+// handleBookmarkImport starts importing the previewed bookmark links. The import runs
+// in the background and reports its progress and results in the chat.
+func (h *Handler) handleBookmarkImport(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID) {
+	if h.importBookmarksCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
 	}
 
-	_ = h.bot.SendMessage(ctx, chatID, msg)
-}
+	chatID := cq.Message.Chat.ID
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "Importing")
+	_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, BookmarkStopKeyboard())
 
-// handlePantryNatural handles natural language pantry management
-func (h *Handler) handlePantryNatural(ctx context.Context, chatID int64, userID shared.ID, action ports.PantryAction, items []string) {
-	switch action {
-	case ports.PantryActionAdd:
-		if len(items) == 0 {
-			_ = h.bot.SendMessage(ctx, chatID,
-				"What would you like to add to your pantry?\n\n"+
-					"Example: \"add chicken and rice to pantry\"")
-			return
-		}
-		pantry, err := h.managePantryCommand.AddItems(ctx, userID, items)
-		if err != nil {
-			log.Printf("Error adding pantry items: %v", err)
-			_ = h.bot.SendError(ctx, chatID, "Failed to add items. Please try again.")
+	ctx = background(ctx)
+	go func() {
+		result, err := h.importBookmarksCommand.Run(ctx, userID, func(done, total int) {
+			if done%bookmarkProgressEvery == 0 && done < total {
+				_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("📚 Imported %d of %d links...", done, total))
+			}
+		})
+		if errors.Is(err, shared.ErrNoPendingImport) {
+			_ = h.bot.SendMessage(ctx, chatID, "This import is no longer available. Send the bookmark file again.")
 			return
 		}
-		_ = h.bot.SendMessage(ctx, chatID,
-			fmt.Sprintf("✅ Added %d item(s) to your pantry.\n\nYour pantry now has %d items.\nSay \"what can I make\" to find recipes!",
-				len(items), len(pantry.Items)))
-
-	case ports.PantryActionRemove:
-		if len(items) == 0 {
-			_ = h.bot.SendMessage(ctx, chatID,
-				"What would you like to remove from your pantry?\n\n"+
-					"Example: \"remove chicken from pantry\"")
+		if errors.Is(err, shared.ErrImportRunning) {
+			_ = h.bot.SendMessage(ctx, chatID, "Your bookmark import is already running.")
 			return
 		}
-		pantry, err := h.managePantryCommand.RemoveItems(ctx, userID, items)
 		if err != nil {
-			log.Printf("Error removing pantry items: %v", err)
-			_ = h.bot.SendError(ctx, chatID, "Failed to remove items. Please try again.")
+			log.Printf("Error importing bookmarks: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "The bookmark import failed. Please try again.")
 			return
 		}
-		_ = h.bot.SendMessage(ctx, chatID,
-			fmt.Sprintf("✅ Removed item(s) from your pantry.\n\nYour pantry now has %d items.",
-				len(pantry.Items)))
 
-	case ports.PantryActionClear:
-		err := h.managePantryCommand.ClearPantry(ctx, userID)
-		if err != nil {
-			log.Printf("Error clearing pantry: %v", err)
-			_ = h.bot.SendError(ctx, chatID, "Failed to clear pantry. Please try again.")
+		for _, rec := range result.Saved {
+			h.recordActivity(ctx, userID, activity.ActionRecipeSaved, rec.Title())
+		}
+		_ = h.bot.EditMessageReplyMarkup(ctx, chatID, cq.Message.MessageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+		_ = h.bot.SendMessage(ctx, chatID, FormatBookmarkImportResult(result))
+	}()
+}
+
+// handleBookmarkCancel drops a previewed bookmark import, or stops a running one
+func (h *Handler) handleBookmarkCancel(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID) {
+	if h.importBookmarksCommand == nil {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	h.importBookmarksCommand.Cancel(userID)
+	_ = h.bot.AnswerCallback(ctx, cq.ID, "Canceled")
+	_ = h.bot.EditMessageReplyMarkup(ctx, cq.Message.Chat.ID, cq.Message.MessageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+}
+
+// recordActivity adds an action to the user's audit trail, if it is kept.
+// Failures are only logged, as the action itself already succeeded.
+func (h *Handler) recordActivity(ctx context.Context, userID shared.ID, action activity.Action, detail string) {
+	if h.activityLogCommand == nil {
+		return
+	}
+	if err := h.activityLogCommand.Record(ctx, userID, action, detail); err != nil {
+		log.Printf("Error recording %s activity: %v", action, err)
+	}
+}
+
+// recordUsage counts a use of a feature for /admin analytics
+func (h *Handler) recordUsage(ctx context.Context, feature analytics.Feature) {
+	if h.usageAnalyticsCommand == nil {
+		return
+	}
+	if err := h.usageAnalyticsCommand.Record(ctx, feature); err != nil {
+		log.Printf("Error recording %s usage: %v", feature, err)
+	}
+}
+
+// handleAdmin handles /admin in the admin chat: /admin analytics [weeks] reports
+// how often each command and intent was used, /admin outbox the messages that
+// could not be delivered
+func (h *Handler) handleAdmin(ctx context.Context, chatID int64, args []string) {
+	sub := ""
+	if len(args) > 0 {
+		sub = strings.ToLower(args[0])
+	}
+
+	switch sub {
+	case "analytics":
+		h.handleAdminAnalytics(ctx, chatID, args[1:])
+	case "outbox":
+		_ = h.bot.SendMessage(ctx, chatID, FormatDeadLetters(h.bot.DeadLetters()))
+	default:
+		_ = h.bot.SendMessage(ctx, chatID, "Usage: /admin analytics \\[weeks] or /admin outbox")
+	}
+}
+
+// handleAdminAnalytics handles /admin analytics [weeks], reporting feature usage
+func (h *Handler) handleAdminAnalytics(ctx context.Context, chatID int64, args []string) {
+	if h.usageAnalyticsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Usage analytics are not available.")
+		return
+	}
+
+	weeks := 4
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			_ = h.bot.SendError(ctx, chatID, "Weeks must be a positive number.")
 			return
 		}
-		_ = h.bot.SendMessage(ctx, chatID, "✅ Your pantry has been cleared.")
+		weeks = n
+	}
 
-	default: // PantryActionShow
-		h.handlePantryShow(ctx, chatID, userID)
+	report, err := h.usageAnalyticsCommand.Report(ctx, weeks)
+	if err != nil {
+		log.Printf("Error loading usage analytics: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load usage analytics. Please try again.")
+		return
 	}
+	_ = h.bot.SendMessage(ctx, chatID, FormatUsageReport(report))
 }
 
-// handleRecipeLink processes a recipe link
-func (h *Handler) handleRecipeLink(ctx context.Context, chatID int64, userID shared.ID, url string) {
-	// Send initial acknowledgment
-	_ = h.bot.SendMessage(ctx, chatID, "🔍 Processing your recipe link...\n\nThis may take a minute.")
+// handleActivity handles /activity, listing the user's recent actions
+func (h *Handler) handleActivity(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
 
-	// Process the recipe
-	recipe, err := h.processRecipeLinkCommand.Execute(ctx, url, userID, chatID)
-	if err != nil {
-		log.Printf("Error processing recipe: %v", err)
-		errorMsg := h.formatError(err)
-		_ = h.bot.SendError(ctx, chatID, errorMsg)
+	if h.activityLogCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Activity history is not available.")
 		return
 	}
 
-	// Send the formatted recipe
-	if err := h.bot.SendRecipe(ctx, chatID, recipe); err != nil {
-		log.Printf("Error sending recipe: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to send recipe. Please try again.")
+	entries, err := h.activityLogCommand.Recent(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading activity: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load your activity. Please try again.")
+		return
 	}
+
+	_ = h.bot.SendMessage(ctx, chatID, FormatActivity(entries, h.datesFor(ctx, userID, time.Now())))
 }
 
-// handleGetRecipe shows a specific recipe by number
-func (h *Handler) handleGetRecipe(ctx context.Context, message *tgbotapi.Message, userID shared.ID, lang user.Language) {
-	chatID := message.Chat.ID
-	args := message.CommandArguments()
+// recordTurn keeps a turn of the conversation for /transcript
+func (h *Handler) recordTurn(userID shared.ID, turn ports.ConversationTurn) {
+	// Turns are added without the update's context; recording one must not hang it
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	if args == "" {
-		_ = h.bot.SendMessage(ctx, chatID, "Please specify a recipe number.\n\nUsage: /recipe <number>\nExample: /recipe 1\n\nUse /recipes to see your recipe list.")
+	if err := h.transcriptCommand.Record(ctx, userID, conversation.Role(turn.Role), turn.Content); err != nil {
+		log.Printf("Error recording conversation: %v", err)
+	}
+}
+
+// handleTranscript handles /transcript, sending the user's recent conversation
+// with the bot as a text file
+func (h *Handler) handleTranscript(ctx context.Context, chatID int64, userID shared.ID) {
+	if h.transcriptCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Conversation transcripts are not available.")
 		return
 	}
 
-	index, err := strconv.Atoi(strings.TrimSpace(args))
+	turns, err := h.transcriptCommand.Transcript(ctx, userID)
 	if err != nil {
-		_ = h.bot.SendMessage(ctx, chatID, "Invalid recipe number. Please use a number like: /recipe 1")
+		log.Printf("Error loading conversation: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load your conversation. Please try again.")
+		return
+	}
+	days := int(conversation.Retention.Hours() / 24)
+	if len(turns) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🗒️ Nothing was said in the last %d days. Ask me something, like _what can I cook with chicken?_", days))
 		return
 	}
 
-	recipeDTO, err := h.listRecipesQuery.ExecuteByIndex(ctx, userID, index)
-	if err != nil {
-		log.Printf("Error getting recipe: %v", err)
-		_ = h.bot.SendError(ctx, chatID, err.Error())
+	now := time.Now()
+	transcript := FormatTranscript(turns, h.datesFor(ctx, userID, now))
+	caption := fmt.Sprintf("🗒️ Your conversation with me, %d messages. Messages are kept for %d days.", len(turns), days)
+	if err := h.bot.SendDocument(ctx, chatID, "transcript-"+now.Format("2006-01-02")+".txt", []byte(transcript), caption); err != nil {
+		log.Printf("Failed to send transcript: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to send file\\. Please try again\\.")
+	}
+}
+
+// handleGuest shows a guest share: its recipe list, or one recipe when index is set.
+// Guests only get read-only views, with no buttons that change anything.
+func (h *Handler) handleGuest(ctx context.Context, chatID int64, token string, index int, lang user.Language) {
+	if h.browseSharedQuery == nil {
+		_ = h.bot.SendError(ctx, chatID, "Guest mode is not available.")
 		return
 	}
 
-	// Translate recipe if user language is Portuguese and we have LLM
-	var translation *TranslatedRecipeDTO
-	if lang == user.LanguagePortuguese && h.llm != nil {
-		translated, err := h.translateRecipe(ctx, recipeDTO, "Portuguese")
+	now := time.Now()
+
+	if index > 0 {
+		recipeDTO, err := h.browseSharedQuery.ExecuteByIndex(ctx, token, index, now)
+		if errors.Is(err, shared.ErrShareNotFound) || errors.Is(err, shared.ErrShareExpired) {
+			h.sendGuestError(ctx, chatID, err)
+			return
+		}
 		if err != nil {
-			log.Printf("Translation error (showing original): %v", err)
-		} else {
-			translation = translated
+			log.Printf("Error getting shared recipe: %v", err)
+			_ = h.bot.SendError(ctx, chatID, err.Error())
+			return
 		}
+		_ = h.bot.SendMessage(ctx, chatID, FormatRecipeDTOWithTranslation(recipeDTO, nil, lang, Dates{Now: now}))
+		return
 	}
 
-	// Format and send the recipe
-	messageText := FormatRecipeDTOWithTranslation(recipeDTO, translation, lang)
-	_ = h.bot.SendMessage(ctx, chatID, messageText)
-}
+	s, recipes, err := h.browseSharedQuery.Execute(ctx, token, now)
+	if err != nil {
+		h.sendGuestError(ctx, chatID, err)
+		return
+	}
 
-// handleListRecipes lists user's recipes, optionally filtered by category
-func (h *Handler) handleListRecipes(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
-	chatID := message.Chat.ID
-	args := strings.TrimSpace(message.CommandArguments())
+	owner := "a friend"
+	if h.userRepo != nil {
+		if u, err := h.userRepo.FindByID(ctx, s.OwnerID); err == nil && u.Username() != "" {
+			owner = "@" + u.Username()
+		}
+	}
 
-	var recipes []*dto.RecipeDTO
-	var err error
-	var categoryFilter string
+	msg, keyboard := FormatGuestShare(s, owner, recipes)
+	if len(keyboard.InlineKeyboard) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, msg)
+		return
+	}
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, msg, keyboard)
+}
 
-	if args != "" {
-		// Filter by category
-		category := recipe.ParseCategory(args)
-		categoryFilter = string(category)
-		recipes, err = h.listRecipesQuery.ExecuteByCategory(ctx, userID, category)
-	} else {
-		// List all recipes
-		recipes, err = h.listRecipesQuery.Execute(ctx, userID)
+// sendGuestError explains why a guest share can't be opened
+func (h *Handler) sendGuestError(ctx context.Context, chatID int64, err error) {
+	switch {
+	case errors.Is(err, shared.ErrShareNotFound):
+		_ = h.bot.SendMessage(ctx, chatID, "This guest link doesn't exist or was closed by its owner.")
+	case errors.Is(err, shared.ErrShareExpired):
+		_ = h.bot.SendMessage(ctx, chatID, "This guest link has expired. Ask its owner for a new one.")
+	default:
+		log.Printf("Error opening guest share: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to open the shared recipes. Please try again.")
 	}
+}
 
-	if err != nil {
-		log.Printf("Error listing recipes: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to list recipes\\. Please try again\\.")
+// handleFilters handles the /filters command for saved searches
+func (h *Handler) handleFilters(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if h.savedFiltersCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Saved searches are not available.")
 		return
 	}
 
-	var msg string
-	if categoryFilter != "" {
-		msg = fmt.Sprintf("📚 *%s Recipes* \\(%d found\\)\n\n", escapeMarkdown(categoryFilter), len(recipes))
-	} else {
-		msg = fmt.Sprintf("📚 *Your Recipes* \\(%d total\\)\n\n", len(recipes))
+	if len(args) == 0 {
+		h.handleListFilters(ctx, chatID, userID)
+		return
 	}
 
-	if len(recipes) == 0 {
-		if categoryFilter != "" {
-			msg = fmt.Sprintf("📭 No recipes found in category: %s\n\nUse /categories to see available categories\\.", escapeMarkdown(categoryFilter))
-		} else {
-			msg = "📭 You don't have any saved recipes yet\\.\n\nSend me a link to get started\\!"
+	switch strings.ToLower(args[0]) {
+	case "delete", "remove":
+		name := strings.Join(args[1:], " ")
+		if name == "" {
+			_ = h.bot.SendError(ctx, chatID, "Usage: /filters delete <name>")
+			return
 		}
-	} else {
-		for i, recipeDTO := range recipes {
-			if i >= 10 {
-				msg += fmt.Sprintf("\n\\.\\.\\. and %d more recipes", len(recipes)-10)
-				break
-			}
-
-			msg += fmt.Sprintf("%d\\. %s\n", i+1, escapeMarkdown(recipeDTO.Title))
-			msg += fmt.Sprintf("   _%s_ \\| %s\n", escapeMarkdown(recipeDTO.Category), recipeDTO.SourcePlatform)
+		err := h.savedFiltersCommand.Delete(ctx, userID, name)
+		if errors.Is(err, shared.ErrFilterNotFound) {
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("I couldn't find a saved search called \"%s\". Use /filters to see yours.", name))
+			return
 		}
-
-		msg += "\nUse /recipe <number> to view details"
-		if categoryFilter == "" {
-			msg += "\nUse /recipes <category> to filter"
+		if err != nil {
+			log.Printf("Error deleting saved filter: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to delete the saved search. Please try again.")
+			return
 		}
-	}
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🗑 Deleted the saved search \"%s\".", user.NormalizeFilterName(name)))
 
-	_ = h.bot.SendMessage(ctx, chatID, msg)
+	default:
+		h.handleRunFilter(ctx, chatID, userID, strings.Join(args, " "))
+	}
 }
 
-// handleCategories shows recipe category counts
-func (h *Handler) handleCategories(ctx context.Context, chatID int64, userID shared.ID) {
-	counts, err := h.listRecipesQuery.GetCategoryCounts(ctx, userID)
+// handleListFilters lists the user's saved searches
+func (h *Handler) handleListFilters(ctx context.Context, chatID int64, userID shared.ID) {
+	filters, err := h.savedFiltersCommand.List(ctx, userID)
 	if err != nil {
-		log.Printf("Error getting category counts: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to get categories\\. Please try again\\.")
+		log.Printf("Error listing saved filters: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load your saved searches. Please try again.")
 		return
 	}
 
-	// Calculate total
-	total := 0
-	for _, count := range counts {
-		total += count
+	if len(filters) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID,
+			"📭 You don't have any saved searches yet.\n\n"+
+				"Search for something like \"quick vegetarian under 30 min\", then say \"save this search as weeknight\".")
+		return
 	}
 
-	if total == 0 {
-		_ = h.bot.SendMessage(ctx, chatID, "📭 You don't have any saved recipes yet\\.\n\nSend me a link to get started\\!")
-		return
+	var sb strings.Builder
+	sb.WriteString("🔖 *Saved Searches*\n\n")
+	for _, f := range filters {
+		sb.WriteString(fmt.Sprintf("• *%s*: %s\n", escapeMarkdown(f.Name), escapeMarkdown(describeSavedFilter(f))))
 	}
+	sb.WriteString("\nSay \"show my <name> recipes\" or use /filters <name> to run one.\n")
+	sb.WriteString("/filters delete <name> removes one.")
 
-	message := FormatCategories(counts, total)
-	_ = h.bot.SendMessage(ctx, chatID, message)
+	_ = h.bot.SendMessage(ctx, chatID, sb.String())
 }
 
-// handleMatch handles the /match command for ingredient matching
-func (h *Handler) handleMatch(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
-	chatID := message.Chat.ID
-	args := strings.TrimSpace(message.CommandArguments())
+// handleSaveFilter saves the current search under a name
+func (h *Handler) handleSaveFilter(ctx context.Context, chatID int64, userID shared.ID, name string) {
+	if h.savedFiltersCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Saved searches are not available.")
+		return
+	}
 
-	// If no ingredients provided, check if user has pantry items
-	if args == "" {
-		pantry, err := h.managePantryCommand.GetPantry(ctx, userID)
-		if err != nil || len(pantry.Items) == 0 {
-			_ = h.bot.SendMessage(ctx, chatID,
-				"Please provide ingredients to match\\.\n\n"+
-					"*Usage:* /match chicken, pasta, garlic\n"+
-					"*Or:* Add items to your pantry with /pantry add")
-			return
-		}
-		args = strings.Join(pantry.Items, ", ")
+	name = user.NormalizeFilterName(name)
+	if name == "" {
+		_ = h.bot.SendMessage(ctx, chatID, "What should I call this search? Try \"save this search as weeknight\".")
+		return
 	}
 
-	// Parse ingredients from comma-separated list
-	ingredients := parseIngredientList(args)
-	if len(ingredients) == 0 {
-		_ = h.bot.SendMessage(ctx, chatID, "Please provide at least one ingredient\\.\n\nExample: /match chicken, pasta, garlic")
+	noSearch := "Search for something first, like \"quick vegetarian under 30 min\", then say \"save this search as " + name + "\"."
+	active := h.conversationManager.GetActiveFilters(userID)
+	if active == nil {
+		_ = h.bot.SendMessage(ctx, chatID, noSearch)
 		return
 	}
 
-	// Check for flags
-	strictMatch := strings.Contains(args, "--strict")
-	var categoryFilter *recipe.Category
-	if strings.Contains(args, "--category") {
-		// Extract category from args (simple parsing)
-		parts := strings.Split(args, "--category")
-		if len(parts) > 1 {
-			catParts := strings.Fields(parts[1])
-			if len(catParts) > 0 {
-				cat := recipe.ParseCategory(catParts[0])
-				categoryFilter = &cat
-			}
-		}
+	filter := savedFilterFromActive(name, active)
+	err := h.savedFiltersCommand.Save(ctx, userID, filter)
+	switch {
+	case errors.Is(err, shared.ErrEmptyFilter):
+		_ = h.bot.SendMessage(ctx, chatID, noSearch)
+		return
+	case errors.Is(err, shared.ErrTooManyFilters):
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("You can keep up to %d saved searches. Remove one with /filters delete <name>.", user.MaxSavedFilters))
+		return
+	case err != nil:
+		log.Printf("Error saving filter: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to save the search. Please try again.")
+		return
 	}
 
-	// Execute matching
-	input := command.MatchIngredientsInput{
-		UserID:         userID,
-		Ingredients:    ingredients,
-		CategoryFilter: categoryFilter,
-		StrictMatch:    strictMatch,
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🔖 Saved *%s*: %s\n\nSay \"show my %s recipes\" to run it again.",
+		escapeMarkdown(name), escapeMarkdown(describeSavedFilter(filter)), name))
+}
+
+// handleRunFilter re-runs a saved search
+func (h *Handler) handleRunFilter(ctx context.Context, chatID int64, userID shared.ID, name string) {
+	if h.savedFiltersCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Saved searches are not available.")
+		return
 	}
 
-	result, err := h.matchIngredientsCommand.Execute(ctx, input)
+	if strings.TrimSpace(name) == "" {
+		h.handleListFilters(ctx, chatID, userID)
+		return
+	}
+
+	saved, err := h.savedFiltersCommand.Get(ctx, userID, name)
+	if errors.Is(err, shared.ErrFilterNotFound) {
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("I couldn't find a saved search called \"%s\". Use /filters to see yours.", user.NormalizeFilterName(name)))
+		return
+	}
 	if err != nil {
-		log.Printf("Error matching ingredients: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to match ingredients\\. Please try again\\.")
+		log.Printf("Error loading saved filter: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load the saved search. Please try again.")
 		return
 	}
 
-	// Format and send results
-	msg := FormatMatchResults(result)
-	_ = h.bot.SendMessage(ctx, chatID, msg)
+	filters := activeFromSavedFilter(saved)
+	h.conversationManager.SetActiveFilters(userID, filters)
+	h.runFilters(ctx, chatID, userID, filters)
 }
 
-// handlePantry handles the /pantry command for pantry management
-func (h *Handler) handlePantry(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
-	chatID := message.Chat.ID
-	args := strings.TrimSpace(message.CommandArguments())
-
-	// Parse subcommand
-	parts := strings.SplitN(args, " ", 2)
-	subcommand := ""
-	itemsArg := ""
-	if len(parts) > 0 {
-		subcommand = strings.ToLower(parts[0])
+// savedFilterFromActive converts the active search filters to a saved filter
+func savedFilterFromActive(name string, active *ActiveFilters) user.SavedFilter {
+	filter := user.SavedFilter{
+		Name:       name,
+		SearchTerm: active.SearchTerm,
+		MaxMinutes: active.MaxMinutes,
 	}
-	if len(parts) > 1 {
-		itemsArg = parts[1]
+	if active.Category != nil {
+		filter.Category = string(*active.Category)
 	}
+	for _, tag := range active.DietaryTags {
+		filter.DietaryTags = append(filter.DietaryTags, string(tag))
+	}
+	if active.Difficulty != nil {
+		filter.Difficulty = string(*active.Difficulty)
+	}
+	if active.IngredientFilter != nil {
+		filter.Include = active.IngredientFilter.Include
+		filter.Exclude = active.IngredientFilter.Exclude
+		filter.Optional = active.IngredientFilter.Optional
+	}
+	return filter
+}
 
-	switch subcommand {
-	case "":
-		// Show current pantry
-		h.handlePantryShow(ctx, chatID, userID)
-
-	case "add":
-		h.handlePantryAdd(ctx, chatID, userID, itemsArg)
+// activeFromSavedFilter converts a saved filter back to search filters
+func activeFromSavedFilter(filter user.SavedFilter) *ActiveFilters {
+	active := &ActiveFilters{
+		DietaryTags: recipe.ParseDietaryTags(filter.DietaryTags),
+		SearchTerm:  filter.SearchTerm,
+		MaxMinutes:  filter.MaxMinutes,
+	}
+	if filter.Category != "" {
+		category := recipe.ParseCategory(filter.Category)
+		active.Category = &category
+	}
+	if difficulty, ok := recipe.ParseDifficulty(filter.Difficulty); ok {
+		active.Difficulty = &difficulty
+	}
+	if len(filter.Include) > 0 || len(filter.Exclude) > 0 || len(filter.Optional) > 0 {
+		active.IngredientFilter = &recipe.IngredientFilter{
+			Include:  filter.Include,
+			Exclude:  filter.Exclude,
+			Optional: filter.Optional,
+		}
+	}
+	return active
+}
 
-	case "remove":
-		h.handlePantryRemove(ctx, chatID, userID, itemsArg)
+// describeSavedFilter describes a saved filter, e.g. "quick + Vegetarian + under 30 min"
+func describeSavedFilter(filter user.SavedFilter) string {
+	var parts []string
+	parts = append(parts, filter.DietaryTags...)
+	if filter.Difficulty != "" {
+		parts = append(parts, filter.Difficulty)
+	}
+	if filter.Category != "" {
+		parts = append(parts, filter.Category)
+	}
+	if filter.SearchTerm != "" {
+		parts = append(parts, "with "+filter.SearchTerm)
+	}
+	if len(filter.Include) > 0 {
+		parts = append(parts, "with "+strings.Join(filter.Include, " and "))
+	}
+	if len(filter.Optional) > 0 {
+		parts = append(parts, "with any of "+strings.Join(filter.Optional, ", "))
+	}
+	if len(filter.Exclude) > 0 {
+		parts = append(parts, "without "+strings.Join(filter.Exclude, ", "))
+	}
+	if filter.MaxMinutes > 0 {
+		parts = append(parts, fmt.Sprintf("under %d min", filter.MaxMinutes))
+	}
+	return strings.Join(parts, " + ")
+}
 
-	case "clear":
-		h.handlePantryClear(ctx, chatID, userID)
+// withinMinutes keeps the recipes whose prep and cook time fit the limit.
+// Recipes without a known time are left out; a limit of 0 keeps everything.
+func withinMinutes(recipes []*dto.RecipeDTO, maxMinutes int) []*dto.RecipeDTO {
+	if maxMinutes <= 0 {
+		return recipes
+	}
 
-	default:
-		// Treat as items to add if no recognized subcommand
-		h.handlePantryAdd(ctx, chatID, userID, args)
+	kept := make([]*dto.RecipeDTO, 0, len(recipes))
+	for _, rec := range recipes {
+		total := 0
+		if rec.PrepTimeMinutes != nil {
+			total += *rec.PrepTimeMinutes
+		}
+		if rec.CookTimeMinutes != nil {
+			total += *rec.CookTimeMinutes
+		}
+		if total > 0 && total <= maxMinutes {
+			kept = append(kept, rec)
+		}
 	}
+	return kept
 }
 
-// handlePantryShow shows the user's pantry
-func (h *Handler) handlePantryShow(ctx context.Context, chatID int64, userID shared.ID) {
-	pantry, err := h.managePantryCommand.GetPantry(ctx, userID)
-	if err != nil {
-		log.Printf("Error getting pantry: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to get pantry\\. Please try again\\.")
+// shortcutsUsage explains how to add and remove shortcuts
+const shortcutsUsage = "/shortcuts add wp = what can I make with my pantry adds one, " +
+	"/shortcuts add fr = /freezer runs a command, and /shortcuts delete wp removes one."
+
+// handleShortcuts handles the /shortcuts command for the user's text shortcuts
+func (h *Handler) handleShortcuts(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+
+	if h.shortcutsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Shortcuts are not available.")
 		return
 	}
 
-	msg := FormatPantry(pantry.Items)
-	_ = h.bot.SendMessage(ctx, chatID, msg)
+	subcommand, args, _ := strings.Cut(strings.TrimSpace(message.CommandArguments()), " ")
+	switch strings.ToLower(subcommand) {
+	case "":
+		h.handleListShortcuts(ctx, chatID, userID)
+
+	case "add", "set":
+		trigger, expansion, ok := strings.Cut(args, "=")
+		if !ok {
+			_ = h.bot.SendMessage(ctx, chatID, "Usage: /shortcuts add <shortcut> = <message or command>")
+			return
+		}
+		shortcut := user.Shortcut{Trigger: trigger, Expansion: expansion}
+		err := h.shortcutsCommand.Save(ctx, userID, shortcut)
+		switch {
+		case errors.Is(err, shared.ErrInvalidShortcut):
+			_ = h.bot.SendMessage(ctx, chatID, "A shortcut needs a word to type and a different message to send, like /shortcuts add wp = what can I make with my pantry")
+			return
+		case errors.Is(err, shared.ErrTooManyShortcuts):
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("You can keep up to %d shortcuts. Remove one with /shortcuts delete <shortcut>.", user.MaxShortcuts))
+			return
+		case err != nil:
+			log.Printf("Error saving shortcut: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to save the shortcut. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("⚡ Saved *%s*: typing it now sends \"%s\".",
+			escapeMarkdown(user.NormalizeShortcutTrigger(trigger)), escapeMarkdown(strings.TrimSpace(expansion))))
+
+	case "delete", "remove":
+		if args == "" {
+			_ = h.bot.SendError(ctx, chatID, "Usage: /shortcuts delete <shortcut>")
+			return
+		}
+		err := h.shortcutsCommand.Delete(ctx, userID, args)
+		if errors.Is(err, shared.ErrShortcutNotFound) {
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("I couldn't find a shortcut called \"%s\". Use /shortcuts to see yours.", args))
+			return
+		}
+		if err != nil {
+			log.Printf("Error deleting shortcut: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to delete the shortcut. Please try again.")
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🗑 Deleted the shortcut \"%s\".", user.NormalizeShortcutTrigger(args)))
+
+	default:
+		_ = h.bot.SendMessage(ctx, chatID, shortcutsUsage)
+	}
 }
 
-// handlePantryAdd adds items to the pantry
-func (h *Handler) handlePantryAdd(ctx context.Context, chatID int64, userID shared.ID, itemsArg string) {
-	if itemsArg == "" {
-		_ = h.bot.SendMessage(ctx, chatID,
-			"Please specify items to add\\.\n\n"+
-				"*Usage:* /pantry add butter, eggs, milk")
+// handleListShortcuts lists the user's text shortcuts
+func (h *Handler) handleListShortcuts(ctx context.Context, chatID int64, userID shared.ID) {
+	shortcuts, err := h.shortcutsCommand.List(ctx, userID)
+	if err != nil {
+		log.Printf("Error listing shortcuts: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load your shortcuts. Please try again.")
 		return
 	}
 
-	items := parseIngredientList(itemsArg)
-	if len(items) == 0 {
-		_ = h.bot.SendMessage(ctx, chatID, "Please provide at least one item to add\\.")
+	if len(shortcuts) == 0 {
+		_ = h.bot.SendMessage(ctx, chatID, "📭 You don't have any shortcuts yet.\n\n"+shortcutsUsage)
 		return
 	}
 
-	pantry, err := h.managePantryCommand.AddItems(ctx, userID, items)
-	if err != nil {
-		log.Printf("Error adding pantry items: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to add items\\. Please try again\\.")
-		return
+	var sb strings.Builder
+	sb.WriteString("⚡ *Shortcuts*\n\n")
+	for _, s := range shortcuts {
+		sb.WriteString(fmt.Sprintf("• *%s* → %s\n", escapeMarkdown(s.Trigger), escapeMarkdown(s.Expansion)))
 	}
+	sb.WriteString("\n" + shortcutsUsage)
 
-	_ = h.bot.SendMessage(ctx, chatID,
-		fmt.Sprintf("✅ Added %d item\\(s\\) to your pantry\\.\n\nYour pantry now has %d items\\.\nUse /match to find recipes\\!",
-			len(items), len(pantry.Items)))
+	_ = h.bot.SendMessage(ctx, chatID, sb.String())
 }
 
-// handlePantryRemove removes items from the pantry
-func (h *Handler) handlePantryRemove(ctx context.Context, chatID int64, userID shared.ID, itemsArg string) {
-	if itemsArg == "" {
-		_ = h.bot.SendMessage(ctx, chatID,
-			"Please specify items to remove\\.\n\n"+
-				"*Usage:* /pantry remove butter, eggs")
-		return
-	}
+// shortcutCommand turns the message that typed a shortcut into the command it expands to
+func shortcutCommand(message *tgbotapi.Message, expanded string) *tgbotapi.Message {
+	cmd := *message
+	cmd.Text = expanded
+	name, _, _ := strings.Cut(expanded, " ")
+	cmd.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(name)}}
+	return &cmd
+}
 
-	items := parseIngredientList(itemsArg)
-	if len(items) == 0 {
-		_ = h.bot.SendMessage(ctx, chatID, "Please provide at least one item to remove\\.")
+// handleNotifications handles the /notifications command with a toggle for each notification
+func (h *Handler) handleNotifications(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
+
+	if h.notificationsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Notification settings are not available.")
 		return
 	}
 
-	pantry, err := h.managePantryCommand.RemoveItems(ctx, userID, items)
+	settings, err := h.notificationsCommand.Settings(ctx, userID)
 	if err != nil {
-		log.Printf("Error removing pantry items: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to remove items\\. Please try again\\.")
+		log.Printf("Error loading notification settings: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load your notification settings. Please try again.")
 		return
 	}
 
-	_ = h.bot.SendMessage(ctx, chatID,
-		fmt.Sprintf("✅ Removed item\\(s\\) from your pantry\\.\n\nYour pantry now has %d items\\.",
-			len(pantry.Items)))
+	_ = h.bot.SendMessageWithKeyboard(ctx, chatID, FormatNotificationSettings(settings), NotificationsKeyboard(settings))
 }
 
-// handlePantryClear clears all pantry items
-func (h *Handler) handlePantryClear(ctx context.Context, chatID int64, userID shared.ID) {
-	err := h.managePantryCommand.ClearPantry(ctx, userID)
+// handleNotificationToggle turns a notification on or off from the /notifications buttons
+func (h *Handler) handleNotificationToggle(ctx context.Context, cq *tgbotapi.CallbackQuery, userID shared.ID, n user.Notification) {
+	if h.notificationsCommand == nil || !n.IsValid() {
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "")
+		return
+	}
+
+	settings, err := h.notificationsCommand.Toggle(ctx, userID, n)
 	if err != nil {
-		log.Printf("Error clearing pantry: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Failed to clear pantry\\. Please try again\\.")
+		log.Printf("Error updating notification settings: %v", err)
+		_ = h.bot.AnswerCallback(ctx, cq.ID, "Failed to update your notification settings. Please try again.")
 		return
 	}
 
-	_ = h.bot.SendMessage(ctx, chatID, "✅ Your pantry has been cleared\\.")
+	status := "off"
+	if settings[n] {
+		status = "on"
+	}
+	_ = h.bot.AnswerCallback(ctx, cq.ID, fmt.Sprintf("%s turned %s", notificationLabels[n][0], status))
+	_ = h.bot.EditMessageWithKeyboard(ctx, cq.Message.Chat.ID, cq.Message.MessageID, FormatNotificationSettings(settings), NotificationsKeyboard(settings))
 }
 
-// handleLanguage handles the /language command for changing user language preference
-func (h *Handler) handleLanguage(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+// handleQuietHours handles /quiet: shows the user's quiet hours, sets them with a
+// span like "22:00-07:00" or turns them off with "off"
+func (h *Handler) handleQuietHours(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
 	chatID := message.Chat.ID
-	args := strings.TrimSpace(message.CommandArguments())
-	t := GetTranslations(usr.Language())
 
-	// If no argument, show current language and options
-	if args == "" {
-		_ = h.bot.SendMessage(ctx, chatID,
-			t.LanguageCurrent+"\n\n"+
-				t.LanguageChoose+"\n"+
-				"• /language en \\- "+t.LanguageEnglish+"\n"+
-				"• /language pt \\- "+t.LanguagePortuguese)
+	if h.notificationsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Notification settings are not available.")
 		return
 	}
 
-	// Parse the requested language
-	newLang := user.ParseLanguage(args)
-
-	// Update user's language preference
-	usr.SetLanguage(newLang)
-	if h.userRepo != nil {
-		if err := h.userRepo.UpdateLanguage(ctx, usr.ID(), newLang); err != nil {
-			log.Printf("Error updating language: %v", err)
-			_ = h.bot.SendError(ctx, chatID, "Failed to update language\\. Please try again\\.")
+	args := strings.TrimSpace(message.CommandArguments())
+	switch {
+	case args == "":
+	case strings.EqualFold(args, "off"):
+		if err := h.notificationsCommand.SetQuietHours(ctx, userID, nil); err != nil {
+			log.Printf("Error clearing quiet hours: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to update your quiet hours. Please try again.")
 			return
 		}
-	}
-
-	// Send confirmation in the NEW language
-	newT := GetTranslations(newLang)
-	_ = h.bot.SendMessage(ctx, chatID, "✅ "+newT.LanguageSet)
-}
-
-// parseIngredientList parses a comma-separated list of ingredients
-func parseIngredientList(input string) []string {
-	// Remove any flags
-	input = strings.ReplaceAll(input, "--strict", "")
-	if idx := strings.Index(input, "--category"); idx != -1 {
-		// Remove --category and its argument
-		endIdx := strings.Index(input[idx:], ",")
-		if endIdx == -1 {
-			input = input[:idx]
-		} else {
-			input = input[:idx] + input[idx+endIdx:]
+	default:
+		quiet, err := user.ParseQuietHours(args)
+		if err != nil {
+			_ = h.bot.SendMessage(ctx, chatID, "Give quiet hours as a span of time, like `/quiet 22:00-07:00`, or `/quiet off`\\.")
+			return
+		}
+		if err := h.notificationsCommand.SetQuietHours(ctx, userID, &quiet); err != nil {
+			log.Printf("Error saving quiet hours: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to update your quiet hours. Please try again.")
+			return
 		}
 	}
 
-	// Split by comma
-	parts := strings.Split(input, ",")
-	var ingredients []string
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part != "" {
-			ingredients = append(ingredients, part)
-		}
+	quiet, timezone, err := h.notificationsCommand.QuietHours(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading quiet hours: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to load your quiet hours. Please try again.")
+		return
 	}
-	return ingredients
+	_ = h.bot.SendMessage(ctx, chatID, FormatQuietHours(quiet, timezone))
 }
 
-// formatError formats an error message for the user
-func (h *Handler) formatError(err error) string {
-	errMsg := err.Error()
+// handleTimezone handles /timezone: shows or sets the time zone scheduled messages follow
+func (h *Handler) handleTimezone(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
 
-	// Provide user-friendly error messages
-	if strings.Contains(errMsg, "scraping failed") {
-		return "Failed to download content from the URL. Please check:\n" +
-			"• The link is valid and accessible\n" +
-			"• The content is publicly available\n" +
-			"• The platform is supported"
+	if h.notificationsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Notification settings are not available.")
+		return
 	}
 
-	if strings.Contains(errMsg, "no content extracted") {
-		return "Could not extract any content from the URL.\n" +
-			"Please make sure the link contains a recipe."
+	if name := strings.TrimSpace(message.CommandArguments()); name != "" {
+		err := h.notificationsCommand.SetTimezone(ctx, userID, name)
+		if errors.Is(err, shared.ErrInvalidTimezone) {
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("I don't know the time zone %s\\. Use a name like `Europe/Lisbon` or `America/Sao_Paulo`\\.", escapeMarkdown(name)))
+			return
+		}
+		if err != nil {
+			log.Printf("Error saving timezone: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to update your time zone. Please try again.")
+			return
+		}
 	}
 
-	if strings.Contains(errMsg, "no ingredients found") {
-		return "Could not find any ingredients in the content.\n" +
-			"Please make sure the link contains a recipe with ingredients."
-	}
+	_ = h.bot.SendMessage(ctx, chatID, FormatTimeSettings(h.datesFor(ctx, userID, time.Now())))
+}
+
+// handleLocale handles /locale: shows or sets the regional style dates are written in
+func (h *Handler) handleLocale(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+	chatID := message.Chat.ID
 
-	if strings.Contains(errMsg, "no instructions found") {
-		return "Could not find any cooking instructions in the content.\n" +
-			"Please make sure the link contains a recipe with steps."
+	if h.notificationsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Date settings are not available.")
+		return
 	}
 
-	if strings.Contains(errMsg, "extraction failed") {
-		return "Failed to extract recipe from the content.\n" +
-			"The AI had trouble understanding this content. Please try a different recipe."
+	if code := strings.TrimSpace(message.CommandArguments()); code != "" {
+		err := h.notificationsCommand.SetLocale(ctx, userID, code)
+		if errors.Is(err, shared.ErrInvalidLocale) {
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("I don't know the locale %s\\. Choose one of %s\\.", escapeMarkdown(code), escapeMarkdown(formatLocales())))
+			return
+		}
+		if err != nil {
+			log.Printf("Error saving locale: %v", err)
+			_ = h.bot.SendError(ctx, chatID, "Failed to update your locale. Please try again.")
+			return
+		}
 	}
 
-	// Generic error
-	return "An error occurred while processing your recipe.\n" +
-		"Please try again or use /help for assistance."
+	_ = h.bot.SendMessage(ctx, chatID, FormatTimeSettings(h.datesFor(ctx, userID, time.Now())))
 }
 
-// handleExport handles the /export command
-func (h *Handler) handleExport(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+// handlePlainMode handles /plain: turns on or off writing messages without emojis or
+// formatting, for screen readers. With no argument it switches to the other mode.
+func (h *Handler) handlePlainMode(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
 	chatID := message.Chat.ID
-	args := strings.TrimSpace(message.CommandArguments())
 
-	if h.exportRecipeCommand == nil {
-		_ = h.bot.SendError(ctx, chatID, "Export functionality is not available.")
+	if h.notificationsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Plain mode is not available.")
 		return
 	}
 
-	// Parse arguments: /export <format> [recipe_number]
-	parts := strings.Fields(args)
-	if len(parts) == 0 {
-		// Show export help
-		_ = h.bot.SendMessage(ctx, chatID,
-			"*Export Recipes*\n\n"+
-				"*Usage:*\n"+
-				"/export obsidian \\- Export all recipes as Markdown\n"+
-				"/export obsidian <number> \\- Export a specific recipe\n"+
-				"/export notion \\- Export all to Notion\n"+
-				"/export notion <number> \\- Export specific recipe to Notion\n\n"+
-				"*Obsidian:* Downloads a \\.md file with YAML frontmatter\n"+
-				"*Notion:* Requires /connect notion first")
+	on := !usr.PlainMode()
+	switch arg := strings.ToLower(strings.TrimSpace(message.CommandArguments())); arg {
+	case "":
+	case "on":
+		on = true
+	case "off":
+		on = false
+	default:
+		_ = h.bot.SendMessage(ctx, chatID, "Usage: /plain on or /plain off")
 		return
 	}
 
-	format := strings.ToLower(parts[0])
-	var recipeID *shared.ID
-
-	// Check if a recipe number was specified
-	if len(parts) > 1 {
-		recipeNum, err := strconv.Atoi(parts[1])
-		if err != nil {
-			_ = h.bot.SendError(ctx, chatID, "Invalid recipe number\\. Use /export <format> <number>")
-			return
-		}
-
-		// Get recipe by index to find its ID
-		recipeDTO, err := h.listRecipesQuery.ExecuteByIndex(ctx, userID, recipeNum)
-		if err != nil {
-			_ = h.bot.SendError(ctx, chatID, fmt.Sprintf("Recipe #%d not found\\.", recipeNum))
-			return
-		}
-
-		id := shared.ID(recipeDTO.ID)
-		recipeID = &id
+	if err := h.notificationsCommand.SetPlainMode(ctx, usr.ID(), on); err != nil {
+		log.Printf("Error saving plain mode: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to update plain mode. Please try again.")
+		return
 	}
 
-	// Execute export
-	var exportFormat command.ExportFormat
-	switch format {
-	case "obsidian", "md", "markdown":
-		exportFormat = command.ExportFormatObsidian
-	case "notion":
-		exportFormat = command.ExportFormatNotion
-	default:
-		_ = h.bot.SendError(ctx, chatID, "Unknown format\\. Use 'obsidian' or 'notion'\\.")
+	// Answered in the mode just chosen
+	ctx = withFormatOptions(ctx, FormatOptions{Plain: on})
+	if on {
+		_ = h.bot.SendMessage(ctx, chatID, "♿ Plain mode is on: messages come without emojis or formatting, with numbered lists. Send /plain off to turn it off.")
 		return
 	}
+	_ = h.bot.SendMessage(ctx, chatID, "✨ Plain mode is off. Send /plain on to turn it back on.")
+}
 
-	_ = h.bot.SendMessage(ctx, chatID, "📤 Exporting recipes...")
+// handleTranscripts handles /transcripts: sets whether the recipes the user saves
+// keep the transcript and captions they were extracted from. With no argument it
+// shows the current setting.
+func (h *Handler) handleTranscripts(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
+	chatID := message.Chat.ID
 
-	input := command.ExportRecipeInput{
-		UserID:   userID,
-		RecipeID: recipeID,
-		Format:   exportFormat,
+	if h.notificationsCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Transcript settings are not available.")
+		return
 	}
 
-	result, err := h.exportRecipeCommand.Execute(ctx, input)
-	if err != nil {
-		log.Printf("Export error: %v", err)
-		_ = h.bot.SendError(ctx, chatID, "Export failed\\. Please try again\\.")
+	var keep bool
+	switch arg := strings.ToLower(strings.TrimSpace(message.CommandArguments())); arg {
+	case "":
+		if usr.KeepsTranscripts() {
+			_ = h.bot.SendMessage(ctx, chatID, "📝 Your recipes keep the transcript and captions they were read from. Send /transcripts off to stop keeping them.")
+		} else {
+			_ = h.bot.SendMessage(ctx, chatID, "📝 Your recipes don't keep transcripts or captions. Send /transcripts on to keep them.")
+		}
+		return
+	case "on":
+		keep = true
+	case "off":
+		keep = false
+	default:
+		_ = h.bot.SendMessage(ctx, chatID, "Usage: /transcripts on or /transcripts off")
 		return
 	}
 
-	if !result.Success {
-		_ = h.bot.SendMessage(ctx, chatID, result.Message)
+	if err := h.notificationsCommand.SetKeepTranscripts(ctx, usr.ID(), keep); err != nil {
+		log.Printf("Error saving transcript setting: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to update the transcript setting. Please try again.")
 		return
 	}
 
-	// Handle result based on format
-	switch exportFormat {
-	case command.ExportFormatObsidian:
-		// Send file as document
-		caption := fmt.Sprintf("✅ %s", result.Message)
-		if err := h.bot.SendDocument(ctx, chatID, result.Filename, result.Data, caption); err != nil {
-			log.Printf("Failed to send document: %v", err)
-			_ = h.bot.SendError(ctx, chatID, "Failed to send file\\. Please try again\\.")
-		}
-	case command.ExportFormatNotion:
-		// Send success message with link
-		msg := fmt.Sprintf("✅ %s", result.Message)
-		if result.URL != "" {
-			msg += fmt.Sprintf("\n\n[View in Notion](%s)", result.URL)
-		}
-		_ = h.bot.SendMessage(ctx, chatID, msg)
+	if keep {
+		_ = h.bot.SendMessage(ctx, chatID, "✅ Recipes you save from now on keep their transcript and captions.")
+		return
 	}
+	_ = h.bot.SendMessage(ctx, chatID, "✅ Recipes you save from now on won't keep their transcript or captions: they are only read to extract the recipe.")
 }
 
-// handleConnect handles the /connect command
-func (h *Handler) handleConnect(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+// handleDiet shows, sets or clears the diet the user's recipes are checked against
+func (h *Handler) handleDiet(ctx context.Context, message *tgbotapi.Message, usr *user.User) {
 	chatID := message.Chat.ID
-	args := strings.TrimSpace(message.CommandArguments())
 
-	if args == "" {
-		_ = h.bot.SendMessage(ctx, chatID,
-			"*Connect External Services*\n\n"+
-				"*Usage:*\n"+
-				"/connect notion \\- Connect to Notion\n\n"+
-				"*Connected services:*\n"+
-				"• Notion \\- Sync recipes to your Notion database")
+	if h.checkDietCommand == nil {
+		_ = h.bot.SendError(ctx, chatID, "Diet checks are not available.")
 		return
 	}
 
-	service := strings.ToLower(args)
-	switch service {
-	case "notion":
-		h.handleConnectNotion(ctx, chatID, userID)
-	default:
-		_ = h.bot.SendError(ctx, chatID, "Unknown service\\. Currently supported: notion")
+	var diets []string
+	for _, d := range h.checkDietCommand.Diets() {
+		diets = append(diets, string(d))
 	}
-}
+	usage := fmt.Sprintf("Usage: /diet <%s> or /diet off", strings.Join(diets, "|"))
 
-// handleConnectNotion handles Notion OAuth connection
-func (h *Handler) handleConnectNotion(ctx context.Context, chatID int64, userID shared.ID) {
-	if h.exportRecipeCommand == nil || !h.exportRecipeCommand.HasNotionExporter() {
-		_ = h.bot.SendError(ctx, chatID, "Notion integration is not configured\\.")
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+	switch arg {
+	case "":
+		if usr.Diet() == "" {
+			_ = h.bot.SendMessage(ctx, chatID, "🥗 You have no diet set.\n\n"+escapeMarkdown(usage))
+			return
+		}
+		_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🥗 Recipes are checked against your %s diet.\n\n%s",
+			escapeMarkdown(usr.Diet()), escapeMarkdown(usage)))
 		return
+	case "off", "none":
+		arg = ""
 	}
 
-	// TODO: Implement OAuth flow - for now, show a placeholder message
-	_ = h.bot.SendMessage(ctx, chatID,
-		"*Connect to Notion*\n\n"+
-			"Notion integration requires OAuth authentication\\.\n\n"+
-			"This feature is coming soon\\! For now, use /export obsidian to export your recipes as Markdown files\\.")
+	d, err := h.checkDietCommand.SetDiet(ctx, usr.ID(), arg)
+	if err != nil {
+		if errors.Is(err, shared.ErrInvalidInput) {
+			_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("Unknown diet: %s\n\n%s", escapeMarkdown(arg), escapeMarkdown(usage)))
+			return
+		}
+		log.Printf("Error saving diet: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to update your diet. Please try again.")
+		return
+	}
+
+	if d == "" {
+		_ = h.bot.SendMessage(ctx, chatID, "🥗 Diet checks are off.")
+		return
+	}
+	_ = h.bot.SendMessage(ctx, chatID, fmt.Sprintf("🥗 Recipes are now checked against your %s diet when you view or plan them. "+
+		"Ingredients the rules cannot settle, like wine or gelatin, are flagged for you to check.", escapeMarkdown(string(d))))
 }
 
-// handleDisconnect handles the /disconnect command
-func (h *Handler) handleDisconnect(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
+// handleLocation sets the user's time zone from a location they share in a private chat
+func (h *Handler) handleLocation(ctx context.Context, message *tgbotapi.Message, userID shared.ID) {
 	chatID := message.Chat.ID
-	args := strings.TrimSpace(message.CommandArguments())
 
-	if args == "" {
-		_ = h.bot.SendMessage(ctx, chatID,
-			"*Disconnect Services*\n\n"+
-				"*Usage:*\n"+
-				"/disconnect notion \\- Disconnect from Notion")
+	if h.notificationsCommand == nil || !message.Chat.IsPrivate() {
 		return
 	}
 
-	service := strings.ToLower(args)
-	switch service {
-	case "notion":
-		h.handleDisconnectNotion(ctx, chatID, userID)
-	default:
-		_ = h.bot.SendError(ctx, chatID, "Unknown service\\. Currently supported: notion")
-	}
-}
-
-// handleDisconnectNotion handles Notion disconnection
-func (h *Handler) handleDisconnectNotion(ctx context.Context, chatID int64, userID shared.ID) {
-	if h.exportRecipeCommand == nil || !h.exportRecipeCommand.HasNotionExporter() {
-		_ = h.bot.SendError(ctx, chatID, "Notion integration is not configured\\.")
+	if err := h.notificationsCommand.SetTimezone(ctx, userID, user.TimezoneAt(message.Location.Longitude)); err != nil {
+		log.Printf("Error saving timezone from location: %v", err)
+		_ = h.bot.SendError(ctx, chatID, "Failed to update your time zone. Please try again.")
 		return
 	}
 
-	// TODO: Implement disconnection
-	_ = h.bot.SendMessage(ctx, chatID, "Notion integration is not yet connected\\.")
+	_ = h.bot.SendMessage(ctx, chatID, "📍 Set your time zone from your location\\. Use /timezone with its name for daylight saving time\\.\n\n"+
+		FormatTimeSettings(h.datesFor(ctx, userID, time.Now())))
 }
@@ -0,0 +1,2275 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"receipt-bot/internal/adapters/telegram/telegramtest"
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/domain/feature"
+	"receipt-bot/internal/domain/feed"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+func TestHandler_StartAndHelp(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/start")
+	h.expectReply("Welcome to Recipe Bot")
+
+	h.send("/help")
+	if len(h.lastSent) != 1 {
+		t.Errorf("/help sent %d messages, want 1", len(h.lastSent))
+	}
+
+	h.send("/doesnotexist")
+	h.expectReply(GetTranslations("en").UnknownCommand)
+}
+
+func TestHandler_SaveRecipeFromLink(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send(carbonaraURL)
+	h.expectReply("Processing your recipe link")
+	h.expectReply("Spaghetti Carbonara")
+
+	usr, err := h.users.FindByTelegramID(context.Background(), h.from.ID)
+	if err != nil {
+		t.Fatalf("user was not created: %v", err)
+	}
+	saved, err := h.recipes.FindByUserID(context.Background(), usr.ID())
+	if err != nil || len(saved) != 1 {
+		t.Fatalf("FindByUserID() = %d recipes, %v; want 1", len(saved), err)
+	}
+
+	// Sending the same link again reuses the stored recipe
+	h.send(carbonaraURL)
+	h.expectReply("Found existing recipe")
+	saved, _ = h.recipes.FindByUserID(context.Background(), usr.ID())
+	if len(saved) != 1 {
+		t.Errorf("duplicate link stored %d recipes, want 1", len(saved))
+	}
+}
+
+func TestHandler_LinkProgressETA(t *testing.T) {
+	h := newTestHarness(t)
+
+	// Without history there is no estimate
+	h.send(carbonaraURL)
+	h.expectReply("This may take a minute")
+
+	for i := 0; i < 3; i++ {
+		h.durations.Record(string(recipe.PlatformTikTok), 40*time.Second)
+	}
+
+	h.send(curryURL)
+	h.expectReply("TikTok links usually take ~40s")
+	h.expectReply("Downloading content...", "left)")
+	h.expectNoReply("successfully! (~")
+}
+
+func TestHandler_InspectProcessing(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(curryURL)
+
+	curry, err := h.recipes.FindBySourceURL(context.Background(), curryURL)
+	if err != nil {
+		t.Fatalf("FindBySourceURL() error = %v", err)
+	}
+
+	// Only the admin chat can inspect recipes
+	h.send("/inspect " + curry.ID().String())
+	h.expectNoReply("Processing of recipe")
+
+	h.from = telegramtest.User{ID: adminChatID, Username: "admin", LanguageCode: "en"}
+	h.send("/inspect " + curry.ID().String())
+	h.expectReply("Processing of recipe", "*Platform:* tiktok", "*Scrape:*", "*LLM calls:*", "*Tokens:*")
+
+	h.send("/inspect nope")
+	h.expectReply("No processing report for recipe nope")
+}
+
+func TestHandler_AdminAnalytics(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+	h.send("/recipes")
+	h.send("/nope")
+
+	// Only the admin chat sees the analytics
+	h.send("/admin analytics")
+	h.expectNoReply("Feature usage")
+
+	h.from = telegramtest.User{ID: adminChatID, Username: "admin", LanguageCode: "en"}
+	h.send("/admin")
+	h.expectReply("Usage: /admin analytics")
+
+	h.send("/admin analytics 2")
+	h.expectReply("*Feature usage*, 2 weeks since", "link: 2 (2 vs. 0)", "/recipes: 1 (1 vs. 0)", "/admin: 1 (1 vs. 0)", "unknown\\_command: 2")
+}
+
+func TestHandler_AdminOutbox(t *testing.T) {
+	h := newTestHarness(t)
+	h.from = telegramtest.User{ID: adminChatID, Username: "admin", LanguageCode: "en"}
+
+	h.send("/admin outbox")
+	h.expectReply("Every message was delivered")
+
+	// A rate limited reply is retried; one Telegram refuses ends up in the outbox
+	h.api.RateLimitNext("sendMessage", 1, 3)
+	h.send("/help")
+	h.expectReply("/recipes")
+
+	h.api.FailNext("sendMessage", 1)
+	h.send("/help")
+
+	h.send("/admin outbox")
+	h.expectReply("1 undelivered messages", "sendMessage to 9000, 1 attempts: Bad Request: forced failure", "Recipe Bot Help")
+}
+
+func TestHandler_ListAndShowRecipes(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.send("/recipes")
+	h.expectReply("Spaghetti Carbonara")
+	h.expectReply("Chickpea Curry")
+
+	// Recipes are numbered newest first
+	h.send("/recipe 1")
+	h.expectReply("coconut milk")
+	// The curry's quantities were only partly shown on screen
+	h.expectReply("double\\-check quantities")
+
+	h.send("/recipe 2")
+	h.expectReply("guanciale")
+	h.expectNoReply("double\\-check quantities")
+
+	// Carbonara steps link to where they start in the video's chapters
+	h.expectReply("watch this step](https://www.youtube.com/watch?t=95s&v=sandbox-carbonara)")
+
+	h.send("/recipe 99")
+	h.expectNoReply("guanciale")
+}
+
+func TestHandler_SimplifiedRecipeView(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/recipe 1")
+	h.expectReply("Instructions", "al dente")
+
+	h.press("Simplify")
+	h.expectReply("Simple Steps", "Ask an adult to help you boil")
+	h.expectNoReply("al dente")
+
+	h.press("Original steps")
+	h.expectReply("Instructions", "al dente")
+}
+
+func TestHandler_ConvertRecipe(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/convert 1 air fryer")
+	h.expectReply("slow cooker", "Instant Pot")
+
+	h.send("/convert 1 instant pot")
+	h.expectReply("Instant Pot Steps", "pressure cook on HIGH for 4 minutes", "Break the spaghetti in half", "Cook: 20 min")
+
+	h.press("Original steps")
+	h.expectReply("Instructions", "al dente")
+
+	h.intents.on("convert #1 to a pressure cooker", ports.Intent{Type: ports.IntentConvertRecipe, RecipeNumber: 1, Appliance: "pressure cooker", Confidence: 0.95})
+	h.send("convert #1 to a pressure cooker")
+	h.expectReply("Instant Pot Steps", "pressure cook on HIGH")
+
+	// The conversion is kept with the recipe instead of adding a new one
+	h.send("/recipes")
+	h.expectReply("1\\. Spaghetti Carbonara")
+	h.expectNoReply("2\\. ")
+}
+
+func TestHandler_RemixRecipe(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/remix 1")
+	h.expectReply("Remix a Recipe", "/remix 3 vegan")
+
+	h.send("/remix 1 to be vegan")
+	h.expectReply("Saved *Vegan Spaghetti Carbonara* as a remix of *Spaghetti Carbonara*")
+	h.expectReply("Remix of *Spaghetti Carbonara* (vegan)",
+		"🔄 _100 g smoked tofu \\(diced\\)_", "• 200 g spaghetti",
+		"Substitutions", "guanciale → smoked tofu: smoky and crisp like the pork",
+		"Blend the silken tofu")
+
+	// The remix is a recipe of its own next to the original, which is unchanged
+	h.send("/recipes")
+	h.expectReply("Vegan Spaghetti Carbonara", "Spaghetti Carbonara")
+	h.send("/recipe 2")
+	h.expectReply("guanciale")
+	h.expectNoReply("Remix of")
+
+	// Nothing recorded for the goal, so there is nothing to swap
+	h.intents.on("make it gluten-free", ports.Intent{Type: ports.IntentRemixRecipe, RemixGoal: "gluten-free"})
+	h.send("make it gluten-free")
+	h.expectReply("already gluten\\-free")
+}
+
+func TestHandler_Freezer(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/freezer")
+	h.expectReply("Your freezer is empty")
+
+	h.send("/freezer add 1 3")
+	h.expectReply("Added 3 portion(s)", "Spaghetti Carbonara", "3 portions", "today")
+
+	h.send("/freezer eat 1 2")
+	h.expectReply("Took 2 portion(s)", "1 portion ")
+
+	h.intents.on("I ate the last carbonara", ports.Intent{Type: ports.IntentFreezer, FreezerAction: ports.FreezerActionRemove, RecipeNumber: 1})
+	h.send("I ate the last carbonara")
+	h.expectReply("Took 1 portion(s)", "Your freezer is empty")
+
+	h.send("/freezer eat 1")
+	h.expectReply("isn't in your freezer")
+
+	h.intents.on("what's in my freezer I should eat", ports.Intent{Type: ports.IntentFreezer, FreezerAction: ports.FreezerActionEatFirst})
+	h.send("what's in my freezer I should eat")
+	h.expectReply("Nothing in your freezer is getting old")
+}
+
+func TestHandler_SavedFilters(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.send("/filters")
+	h.expectReply("You don't have any saved searches yet")
+
+	h.intents.on("save this search as weeknight", ports.Intent{Type: ports.IntentSaveFilter, FilterName: "weeknight"})
+	h.send("save this search as weeknight")
+	h.expectReply("Search for something first")
+
+	h.intents.on("anything under 30 min", ports.Intent{Type: ports.IntentCompoundQuery, MaxMinutes: 30})
+	h.send("anything under 30 min")
+	h.expectReply("Under 30 Min Recipes", "Spaghetti Carbonara")
+	h.expectNoReply("Curry")
+
+	h.send("save this search as weeknight")
+	h.expectReply("Saved *weeknight*: under 30 min")
+
+	h.send("/filters")
+	h.expectReply("Saved Searches", "*weeknight*: under 30 min")
+
+	h.send("/recipes")
+	h.intents.on("show my weeknight recipes", ports.Intent{Type: ports.IntentRunFilter, FilterName: "Weeknight"})
+	h.send("show my weeknight recipes")
+	h.expectReply("Spaghetti Carbonara")
+	h.expectNoReply("Curry")
+
+	h.send("/filters delete weeknight")
+	h.expectReply("Deleted the saved search \"weeknight\"")
+
+	h.send("/filters weeknight")
+	h.expectReply("couldn't find a saved search called \"weeknight\"")
+}
+
+func TestHandler_Shortcuts(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/shortcuts")
+	h.expectReply("You don't have any shortcuts yet")
+
+	h.send("/shortcuts add wp = what's in my freezer I should eat")
+	h.expectReply("Saved *wp*")
+
+	h.intents.on("what's in my freezer I should eat", ports.Intent{Type: ports.IntentFreezer, FreezerAction: ports.FreezerActionEatFirst})
+	h.send("WP")
+	h.expectReply("Nothing in your freezer is getting old")
+
+	// A shortcut for a command runs it, keeping the words typed after it
+	h.send("/shortcuts add fe = /freezer eat")
+	h.send("fe 1")
+	h.expectReply("Recipe #1 not found")
+
+	h.send("/shortcuts")
+	h.expectReply("*wp* → what's in my freezer I should eat", "*fe* → /freezer eat")
+
+	h.send("/shortcuts delete wp")
+	h.expectReply("Deleted the shortcut \"wp\"")
+
+	h.send("/shortcuts delete wp")
+	h.expectReply("couldn't find a shortcut called \"wp\"")
+}
+
+func TestHandler_Notifications(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/notifications")
+	h.expectReply("*Daily suggestions* (off)", "*Expiry warnings* (on)", "*Timer pings* (on)", "*Weekly digest* (off)")
+
+	h.press("Daily suggestions")
+	h.expectReply("*Daily suggestions* (on)")
+
+	h.press("Timer pings")
+	h.expectReply("*Timer pings* (off)")
+
+	h.send("/timeline 1")
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), telegramtest.CallbackUpdate(h.from, 1, callbackReminders))
+	answers := h.api.CallsTo("answerCallbackQuery")
+	if len(answers) != 1 || !strings.Contains(answers[0].Params["text"], "Timer pings are off") {
+		t.Errorf("reminder answer = %+v, want timer pings to be off", answers)
+	}
+}
+
+func TestHandler_TimeAndDates(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/recipe 1")
+	h.expectReply("Saved just now")
+
+	// Telegram settings that name a region choose the date style
+	h.from.LanguageCode = "en-us"
+	h.send("/locale")
+	h.expectReply("Time and dates", "Time zone: the server's", "Locale: en\\-US")
+
+	h.send("/locale fr-FR")
+	h.expectReply("I don't know the locale fr\\-FR")
+
+	h.send("/locale pt_br")
+	h.expectReply("Locale: pt\\-BR")
+
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), telegramtest.LocationUpdate(h.from, -23.55, -46.63))
+	h.lastSent = h.api.Messages()
+	h.expectReply("Set your time zone from your location", "Time zone: Etc/GMT\\+3")
+
+	h.send("/timezone America/Sao_Paulo")
+	h.expectReply("Time zone: America/Sao\\_Paulo")
+}
+
+func TestHandler_Authors(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/authors")
+	h.expectReply("None of your saved recipes has a known author")
+
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.send("/authors")
+	h.expectReply("Your Top Creators", "sandbox\\-chef · 1 recipe", "sandbox\\-vegan · 1 recipe")
+
+	h.intents.on("show me everything from @Sandbox-Chef", ports.Intent{Type: ports.IntentFilterAuthor, Author: "@Sandbox-Chef"})
+	h.send("show me everything from @Sandbox-Chef")
+	h.expectReply("Recipes from sandbox\\-chef", "Spaghetti Carbonara")
+	h.expectNoReply("Curry")
+
+	h.send("/authors someone-else")
+	h.expectReply("No recipes found from someone\\-else")
+}
+
+func TestHandler_Platforms(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/platforms")
+	h.expectReply("don't have any saved recipes")
+
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.send("/platforms")
+	h.expectReply("Where Your Recipes Come From", "YouTube · 1 recipe", "TikTok · 1 recipe")
+
+	h.intents.on("show my TikTok recipes", ports.Intent{Type: ports.IntentFilterPlatform, Platform: "tiktok"})
+	h.send("show my TikTok recipes")
+	h.expectReply("Recipes from TikTok", "Curry")
+	h.expectNoReply("Carbonara")
+
+	h.send("/platforms insta")
+	h.expectReply("No recipes saved from Instagram")
+
+	h.send("/platforms myspace")
+	h.expectReply("don't know the platform myspace")
+}
+
+func TestHandler_Flavors(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.send("/recipe 1")
+	h.expectReply("👅 Flavor: spicy")
+
+	h.send("/flavor picante")
+	h.expectReply("Spicy Recipes", "Curry")
+	h.expectNoReply("Carbonara")
+
+	// A vague ask the user has recipes for is answered without clarifying
+	h.intents.on("I want something spicy", ports.Intent{
+		Type:               ports.IntentUnknown,
+		NextAction:         ports.ActionClarify,
+		Confidence:         0.9,
+		ClarifyingQuestion: "What kind of spicy food are you looking for?",
+	})
+	h.send("I want something spicy")
+	h.expectReply("Spicy Recipes", "Curry")
+	h.expectNoReply("What kind of spicy food")
+
+	h.intents.on("something sweet", ports.Intent{Type: ports.IntentFilterFlavor, Flavor: "sweet"})
+	h.send("something sweet")
+	h.expectReply("No sweet recipes yet")
+
+	h.send("/flavor bitter")
+	h.expectReply("don't know the flavor bitter")
+}
+
+func TestHandler_RecipesSavedInPeriod(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.intents.on("what did I save today", ports.Intent{Type: ports.IntentListRecipes, SavedPeriod: "today"})
+	h.send("what did I save today")
+	h.expectReply("Saved on", "Spaghetti Carbonara")
+
+	h.intents.on("what did I save in 2020", ports.Intent{Type: ports.IntentListRecipes, SavedPeriod: "2020"})
+	h.send("what did I save in 2020")
+	h.expectReply("No recipes saved from", "2020")
+
+	h.intents.on("recipes from someday", ports.Intent{Type: ports.IntentListRecipes, SavedPeriod: "someday"})
+	h.send("recipes from someday")
+	h.expectReply("couldn't tell which dates")
+}
+
+func TestHandler_LinkAccounts(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	alice := h.from
+	desktop := telegramtest.User{ID: 2002, Username: "alice_desktop", LanguageCode: "en"}
+
+	h.send("/link")
+	h.expectReply("Link another account", "expires in 15 minutes")
+	code := regexp.MustCompile(`/link (\d{6})`).FindStringSubmatch(h.lastSent[0].Text)
+	if code == nil {
+		t.Fatalf("no link code in %q", h.lastSent[0].Text)
+	}
+
+	h.from = desktop
+	h.send("/recipes")
+	h.expectReply("don't have any saved recipes")
+
+	h.send("/link 000000x")
+	h.expectReply("invalid or expired")
+
+	h.send("/link " + code[1])
+	h.expectReply("Linked!", "@alice")
+
+	h.send("/recipes")
+	h.expectReply("Spaghetti Carbonara")
+
+	// Codes work once
+	h.send("/link " + code[1])
+	h.expectReply("invalid or expired")
+
+	// Recipes saved from the linked account land in the shared collection
+	h.send(curryURL)
+	h.from = alice
+	h.send("/recipes")
+	h.expectReply("Spaghetti Carbonara", "Chickpea Curry")
+
+	h.send("/unlink")
+	h.expectReply("isn't linked")
+
+	h.from = desktop
+	h.send("/unlink")
+	h.expectReply("Unlinked")
+	h.send("/recipes")
+	h.expectReply("don't have any saved recipes")
+}
+
+func TestHandler_GuestShare(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	alice := h.from
+	guest := telegramtest.User{ID: 3003, Username: "bob", LanguageCode: "en"}
+
+	h.send("/share 45")
+	h.expectReply("1 to 30 days")
+
+	h.send("/share 3 pasta")
+	h.expectReply("Guest link ready", "your Pasta & Noodles recipes", "start=guest_")
+	token := regexp.MustCompile(`/guest ([0-9a-f]+)`).FindStringSubmatch(h.lastSent[0].Text)
+	if token == nil {
+		t.Fatalf("no guest token in %q", h.lastSent[0].Text)
+	}
+
+	h.from = guest
+	h.send("/start guest_" + token[1])
+	h.expectReply("shared by @alice", "Read-only", "Spaghetti Carbonara")
+	h.expectNoReply("Chickpea Curry")
+
+	h.press("1. Spaghetti Carbonara")
+	h.expectReply("Spaghetti Carbonara", "guanciale")
+
+	h.send("/guest " + token[1] + " 2")
+	h.expectReply("recipe #2 not found")
+
+	// Browsing doesn't add anything to the guest's own collection
+	h.send("/recipes")
+	h.expectReply("don't have any saved recipes")
+
+	h.from = alice
+	h.send("/share stop")
+	h.expectReply("guest links are closed")
+
+	h.from = guest
+	h.send("/guest " + token[1])
+	h.expectReply("doesn't exist or was closed")
+}
+
+func TestHandler_GuestShareModeration(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(curryURL)
+	h.send("https://" + blockedDomain + "/pasta")
+	h.send("https://bit.ly/carbonara")
+
+	alice := h.from
+	guest := telegramtest.User{ID: 3003, Username: "bob", LanguageCode: "en"}
+	admin := telegramtest.User{ID: adminChatID, Username: "admin", LanguageCode: "en"}
+
+	h.send("/share")
+	h.expectReply("Guest link ready")
+	h.expectReply("Not shown to guests", "did not pass the content check", "Waiting for a review: 1")
+	h.expectReply("1 shared recipes wait for a review")
+	token := regexp.MustCompile(`/guest ([0-9a-f]+)`).FindStringSubmatch(h.lastSent[0].Text)
+	if token == nil {
+		t.Fatalf("no guest token in %q", h.lastSent[0].Text)
+	}
+
+	h.from = guest
+	h.send("/guest " + token[1])
+	h.expectReply("Chickpea Curry")
+	h.expectNoReply("Spaghetti Carbonara")
+
+	// Only the admin chat has a review queue
+	h.send("/review")
+	h.expectNoReply("Review shared recipe")
+
+	h.from = admin
+	h.send("/review")
+	h.expectReply("Review shared recipe", "bit.ly", "shortened link")
+	h.press("Approve")
+	h.expectReply("No shared recipes wait for a review")
+
+	h.from = guest
+	h.send("/guest " + token[1])
+	h.expectReply("Chickpea Curry", "Spaghetti Carbonara")
+
+	// The blocked recipe stays hidden when the collection is shared again
+	h.from = alice
+	h.send("/share")
+	h.expectReply("did not pass the content check")
+	h.expectNoReply("Waiting for a review")
+}
+
+func TestHandler_RecipesByDifficulty(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/recipe 1")
+	h.expectReply("Difficulty: Easy")
+
+	h.send("/recipes easy")
+	h.expectReply("Easy Recipes", "Spaghetti Carbonara", "🟢 Easy")
+
+	h.send("/recipes hard")
+	h.expectReply("No recipes found")
+}
+
+func TestHandler_MenuPlanner(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.send("/menu Christmas dinner for 8 at 20:00")
+	h.expectReply("Menu · Christmas dinner", "8 guests · served from 20:00", "Spaghetti Carbonara · ⏱️ 20 min · ×4")
+
+	h.press("Another main course")
+	h.expectReply("One\\-Pot Chickpea Curry · ⏱️ 35 min · ×2")
+
+	h.press("Cooking timeline")
+	h.expectReply("start at 19:55", "*19:55* 🔪 Start preparing One\\-Pot Chickpea Curry", "*20:05* 🍳 One\\-Pot Chickpea Curry on the stove", "*20:30* 🍽️ Serve the main course")
+
+	h.intents.on("plan a dinner party", ports.Intent{Type: ports.IntentPlanMenu, Occasion: "Dinner party"})
+	h.send("plan a dinner party")
+	h.expectReply("Menu · Dinner party", "4 guests · served from 19:00")
+}
+
+func TestHandler_PantryAndMatch(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/pantry add spaghetti, guanciale, eggs, pecorino")
+	h.send("/pantry")
+	h.expectReply("🥫 Pantry*\n• spaghetti", "🧀 Dairy*\n• egg")
+
+	h.send("/match")
+	h.expectReply("Spaghetti Carbonara")
+}
+
+func TestHandler_GroupPantry(t *testing.T) {
+	h := newTestHarness(t)
+	h.handler.groupPantry = true
+
+	const family = int64(-5005)
+	bob := telegramtest.User{ID: 3003, Username: "bob", LanguageCode: "en"}
+
+	h.sendInGroup(family, h.from, "/pantry add spaghetti, eggs")
+	h.sendInGroup(family, bob, "/pantry")
+	h.expectReply("Your Pantry* \\(2 items\\)", "• spaghetti")
+
+	// The group's pantry is not the members' own
+	h.send("/pantry")
+	h.expectReply("Your pantry is empty")
+
+	h.handler.groupPantry = false
+	h.sendInGroup(family, bob, "/pantry")
+	h.expectReply("Your pantry is empty")
+}
+
+func TestHandler_Requests(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	const family = int64(-6006)
+	bob := telegramtest.User{ID: 3003, Username: "bob", LanguageCode: "en"}
+
+	h.sendInGroup(family, bob, "/requests")
+	h.expectReply("Nobody asked for anything yet")
+
+	h.sendInGroup(family, bob, "/requests add someone please make carbonara this week")
+	h.expectReply("Added carbonara to the requests")
+
+	h.sendInGroup(family, h.from, "/requests plan 1 1 wednesday")
+	h.expectReply("Spaghetti Carbonara is on your meal plan for Wednesday", "bob's request for carbonara")
+
+	h.sendInGroup(family, bob, "/requests")
+	h.expectReply("1. *carbonara* · asked by bob", "📅 alice plans Spaghetti Carbonara for Wednesday")
+
+	h.send("/plan")
+	h.expectReply("Spaghetti Carbonara")
+
+	// The requester hears about it in their private chat
+	h.sendInGroup(family, h.from, "/requests cooked 1")
+	h.expectReply("alice cooked carbonara! I'll let bob know.")
+	var notified bool
+	for _, msg := range h.lastSent {
+		if msg.ChatID == bob.ID && strings.Contains(msg.Text, "alice cooked the carbonara you asked for") {
+			notified = true
+		}
+	}
+	if !notified {
+		t.Errorf("bob was not told the carbonara was cooked; sent %+v", h.lastSent)
+	}
+
+	h.sendInGroup(family, bob, "/requests cooked 1")
+	h.expectReply("Request #1 not found")
+
+	// Natural language requests land in a private chat's own list
+	h.intents.on("someone please make lasagna this week", ports.Intent{Type: ports.IntentRequestDish, Dish: "lasagna"})
+	h.send("someone please make lasagna this week")
+	h.expectReply("Added lasagna to the requests")
+
+	h.send("/requests")
+	h.expectReply("1. *lasagna* · asked by alice")
+}
+
+func TestHandler_ForumTopic(t *testing.T) {
+	h := newTestHarness(t)
+	desserts := Topic{ID: 42, Name: "🍰 Desserts"}
+
+	h.api.Reset()
+	h.handler.HandleUpdateInTopic(context.Background(), telegramtest.InGroup(telegramtest.TextUpdate(h.from, curryURL), -100777), desserts)
+	h.lastSent = h.api.Messages()
+	h.expectReply("Chickpea Curry")
+
+	// Every reply goes to the topic the link was sent in
+	for _, call := range h.api.CallsTo("sendMessage") {
+		if call.Params["message_thread_id"] != "42" {
+			t.Errorf("reply %q sent to topic %q, want 42", call.Params["text"], call.Params["message_thread_id"])
+		}
+	}
+
+	// The recipe is filed under the topic's category
+	h.send("/categories")
+	h.expectReply("Desserts & Sweets")
+	h.expectNoReply("Vegetarian")
+	if topic := h.api.CallsTo("sendMessage")[0].Params["message_thread_id"]; topic != "" {
+		t.Errorf("private reply sent to topic %q", topic)
+	}
+}
+
+func TestHandler_StaplesChangeMatches(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send("/pantry add spaghetti, guanciale, eggs, pecorino")
+
+	h.send("/staples")
+	h.expectReply("Your pantry staples", "• black pepper", "• salt")
+
+	// Without pepper as a staple, the carbonara needs it
+	h.send("/staples remove black pepper")
+	h.expectReply("• salt")
+	if strings.Contains(h.lastSent[0].Text, "black pepper") {
+		t.Fatalf("staples still list black pepper:\n%s", h.lastSent[0].Text)
+	}
+	h.send("/match")
+	h.expectReply("Almost There", "Spaghetti Carbonara")
+
+	// Pecorino is always at hand, so only pepper is missing even without it in the pantry
+	h.send("/pantry remove pecorino")
+	h.send("/staples add pecorino")
+	h.expectReply("• pecorino")
+	h.send("/match")
+	h.expectReply("Spaghetti Carbonara \\(75% match\\)", "Missing: black pepper")
+
+	h.send("/staples reset")
+	h.expectReply("• black pepper")
+	h.send("/pantry add pecorino")
+	h.send("/match")
+	h.expectReply("Perfect Matches", "Spaghetti Carbonara")
+}
+
+func TestHandler_ExplainMatch(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/match pasta, pancetta, eggs")
+	h.expectReply("Spaghetti Carbonara \\(75% match\\)")
+
+	h.press("Why 1?")
+	h.expectReply(
+		"Spaghetti Carbonara* · 75% match",
+		"✅ *You have*\n• eggs",
+		"🔄 *Standing in*\n• spaghetti ← your pasta\n• guanciale ← your pancetta",
+		"❌ *Missing*\n• pecorino",
+		"🧂 *Staples, not counted*\n• black pepper",
+		"3 of 4 ingredients counted",
+	)
+}
+
+func TestHandler_CookTogether(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(curryURL)
+
+	const kitchen = int64(-4004)
+	alice := h.from
+	bob := telegramtest.User{ID: 3003, Username: "bob", LanguageCode: "en"}
+	inKitchen := func(update tgbotapi.Update) {
+		t.Helper()
+		h.api.Reset()
+		h.handler.HandleUpdate(context.Background(), telegramtest.InGroup(update, kitchen))
+		h.lastSent = h.api.Messages()
+		for _, msg := range h.lastSent {
+			if msg.ChatID != kitchen {
+				t.Fatalf("bot replied in chat %d, want the group", msg.ChatID)
+			}
+		}
+	}
+
+	inKitchen(telegramtest.TextUpdate(alice, "/cook 1"))
+	h.expectReply("Cooking together:* One\\-Pot Chickpea Curry", "_Started by alice_", "☐ 1\\. Fry the onion", "_0 of 3 steps done_")
+
+	inKitchen(telegramtest.CallbackUpdate(alice, 1, h.buttonData("Take 1")))
+	h.expectReply("🙋 1\\. Fry the onion, garlic and ginger until soft\\. · *alice*")
+
+	inKitchen(telegramtest.TextUpdate(bob, "/claim I'll do the curry powder"))
+	h.expectReply("🙋 bob takes step 2: Stir in the curry powder")
+
+	inKitchen(telegramtest.TextUpdate(alice, "/claim 2"))
+	h.expectReply("bob already took step 2\\.")
+
+	inKitchen(telegramtest.TextUpdate(bob, "/done 2"))
+	h.expectReply("✅ bob finished step 2", "_1 of 3 steps done_")
+
+	inKitchen(telegramtest.TextUpdate(alice, "/done 1"))
+	inKitchen(telegramtest.TextUpdate(bob, "/done simmer"))
+	h.expectReply("✅ bob finished step 3")
+	h.expectReply("🎉 Every step of *One\\-Pot Chickpea Curry* is done")
+
+	inKitchen(telegramtest.TextUpdate(alice, "/done 1"))
+	h.expectReply("Nothing is being cooked here")
+}
+
+func TestHandler_MatchFilters(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.send("/match spaghetti, guanciale, eggs, pecorino, onion, garlic, chickpeas, tomatoes, coconut milk")
+	h.expectReply("Perfect Matches", "Spaghetti Carbonara", "One\\-Pot Chickpea Curry \\(71% match\\)")
+
+	h.press("Perfect only")
+	h.expectReply("perfect matches only", "Spaghetti Carbonara")
+	h.expectNoReply("Chickpea Curry")
+
+	h.press("Clear filters")
+	h.expectReply("Spaghetti Carbonara", "Chickpea Curry")
+
+	h.press("Only Vegetarian")
+	h.expectReply("Vegetarian", "Chickpea Curry")
+	h.expectNoReply("Spaghetti Carbonara")
+
+	// The curry lacks ginger and curry powder
+	h.press("Allow 1 missing")
+	h.expectReply("up to 1 missing · Vegetarian", "No matching recipes")
+}
+
+func TestHandler_BarcodePhotoAddsToPantry(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.sendPhoto("8076800195057")
+	h.expectReply("Added *spaghetti \\(500 g\\)* to your pantry", "Spaghetti · Barilla", "1 items")
+
+	h.send("/pantry")
+	h.expectReply("🥫 Pantry*\n• spaghetti \\(500 g\\)")
+
+	// Adding by name does not duplicate the scanned item
+	h.send("/pantry add spaghetti")
+	h.expectReply("now has 1 items")
+
+	h.sendPhoto("0000000000000")
+	h.expectReply("Barcode 0000000000000 is not in Open Food Facts")
+
+	h.sendPhoto("blurry")
+	h.expectReply("couldn't find a barcode")
+
+	h.send("/pantry remove spaghetti")
+	h.expectReply("now has 0 items")
+}
+
+func TestHandler_RecreateDishFromPhoto(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.sendPhotoWithCaption("sandbox-curry-photo", "Recreate this please")
+	h.expectReply("🤖 *AI\\-generated recipe*", "not taken from a published recipe", "One\\-Pot Chickpea Curry", "ai\\-generated")
+
+	// Nothing is saved until the user asks
+	h.send("/recipes")
+	h.expectReply("Spaghetti Carbonara")
+	if strings.Contains(h.lastSent[0].Text, "Chickpea") {
+		t.Fatalf("proposal saved before pressing Save:\n%s", h.lastSent[0].Text)
+	}
+
+	h.sendPhotoWithCaption("sandbox-curry-photo", "recreate this")
+	h.press("Save recipe")
+	h.expectReply("Saved *One\\-Pot Chickpea Curry* to your recipes as an AI\\-generated recipe")
+
+	h.send("/recipe 1")
+	h.expectReply("One\\-Pot Chickpea Curry", "ai\\-generated")
+
+	// Discarded proposals are dropped
+	h.sendPhotoWithCaption("sandbox-curry-photo", "recreate this")
+	h.press("Discard")
+	if answers := h.api.CallsTo("answerCallbackQuery"); len(answers) != 1 || answers[0].Params["text"] != "Discarded" {
+		t.Errorf("discard answered %+v", answers)
+	}
+
+	// Photos without the caption are still barcode scans
+	h.sendPhoto("blurry")
+	h.expectReply("couldn't find a barcode")
+}
+
+func TestHandler_PantryPhotoChecklist(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.sendPhotoWithCaption("sandbox-curry-photo", "what's in my fridge?")
+	h.expectReply("I spotted 7 items")
+	h.buttonData("Add 7 items")
+
+	// Untick what the photo got wrong
+	h.press("ginger")
+	h.press("curry powder")
+	h.expectReply("I spotted 7 items")
+	h.buttonData("⬜ curry powder")
+	h.buttonData("Add 5 items")
+
+	h.press("Add 5 items")
+	h.expectReply("Added 5 items to your pantry: onion, garlic, chickpeas, diced tomatoes, coconut milk", "now has 5 items")
+
+	h.send("/pantry")
+	h.expectReply("onion")
+	if strings.Contains(h.lastSent[0].Text, "ginger") {
+		t.Errorf("unticked item added:\n%s", h.lastSent[0].Text)
+	}
+}
+
+func TestHandler_NutritionFromScannedProducts(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/nutrition 1")
+	h.expectReply("None of the ingredients match a scanned pantry product")
+
+	h.sendPhoto("8076800195057")
+
+	h.send("/nutrition")
+	h.expectReply("spaghetti \\(500 g\\)", "359 kcal · 13 g protein")
+
+	// 200 g of spaghetti for 2 servings
+	h.send("/nutrition 1")
+	h.expectReply("Per serving:* 359 kcal", "Total:* 718 kcal", "spaghetti · 200 g of Spaghetti", "Not counted", "guanciale")
+
+	// Links to items no longer in the pantry are ignored
+	h.send("/pantry remove spaghetti")
+	h.send("/nutrition")
+	h.expectReply("No pantry items are linked to a product yet")
+}
+
+func TestHandler_MacroGoals(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.sendPhoto("8076800195057")
+
+	h.send("/macros")
+	h.expectReply("no daily macro goals yet")
+
+	h.send("/macros lots of food")
+	h.expectReply("Set daily goals with /macros")
+
+	h.send("/macros 20000 kcal")
+	h.expectReply("at most 10000 kcal a day")
+
+	h.send("/macros 2000 kcal 150g protein")
+	h.expectReply("Daily goals set: 2000 kcal · 150 g protein")
+
+	// 200 g of spaghetti for 2 servings
+	h.send("/ate 1")
+	h.expectReply("Logged *Spaghetti Carbonara*", "One serving: 359 kcal", "1641 kcal left · 137 g protein left")
+
+	h.send("/macros")
+	h.expectReply("Eaten today:*\n• Spaghetti Carbonara · 359 kcal · 13 g protein", "1641 kcal left")
+
+	h.send("/macros off")
+	h.expectReply("Stopped tracking macros")
+
+	h.send("/ate 1")
+	h.expectReply("Logged *Spaghetti Carbonara*")
+	h.expectNoReply("left")
+}
+
+func TestHandler_Achievements(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+	h.sendPhoto("8076800195057")
+
+	h.send("/achievements")
+	h.expectReply("Streak: 0 days (longest 0)", "🔒 First dish: Cook your first recipe (0/1)", "/achievements on")
+
+	h.send("/achievements maybe")
+	h.expectReply("Usage: /achievements")
+
+	h.send("/achievements on")
+	h.expectReply("Achievements on")
+
+	h.send("/ate 1")
+	h.expectReply("Logged", "New badge: 🍳 *First dish*! Cook your first recipe.")
+
+	// Cooking again the same day earns nothing new
+	h.send("/ate 2")
+	h.expectNoReply("New badge")
+
+	h.send("/achievements")
+	h.expectReply("Streak: 1 day (longest 1)", "Recipes cooked: 2 · Cuisines: 2", "🍳 *First dish*", "🔒 Globetrotter: Cook 3 different cuisines (2/3)", "/achievements off stops")
+
+	// Opting out keeps the record but drops the announcements
+	h.send("/achievements off")
+	h.expectReply("Achievements off")
+}
+
+func TestHandler_NaturalLanguageConversation(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	vegetarian := recipe.CategoryVegetarian
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.intents.on("vegetarian ones", ports.Intent{Type: ports.IntentFilterCategory, Category: &vegetarian})
+	h.intents.on("details on #1", ports.Intent{Type: ports.IntentShowDetails, RecipeNumber: 1})
+
+	h.send("show my recipes")
+	h.expectReply("Spaghetti Carbonara", "Chickpea Curry")
+
+	// Lists hold recipe summaries, details load the steps
+	h.send("details on #1")
+	h.expectReply("Stir in the curry powder")
+
+	h.send("vegetarian ones")
+	h.expectReply("Chickpea Curry")
+	h.expectNoReply("Spaghetti Carbonara")
+
+	// "#1" refers to the last list shown, not the full collection
+	h.send("details on #1")
+	h.expectReply("coconut milk")
+}
+
+func TestHandler_MessageWindow(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+	h.handler.messageWindow = newMessageWindow(50 * time.Millisecond)
+
+	vegetarian := recipe.CategoryVegetarian
+	h.intents.on("show my vegetarian recipes", ports.Intent{Type: ports.IntentFilterCategory, Category: &vegetarian})
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+
+	// A thought sent across quick messages is understood as one
+	h.api.Reset()
+	for _, text := range []string{"show my", "vegetarian", "recipes"} {
+		h.handler.HandleUpdate(context.Background(), telegramtest.TextUpdate(h.from, text))
+	}
+	if sent := h.api.Messages(); len(sent) != 0 {
+		t.Fatalf("bot replied %q before the user paused", sent[0].Text)
+	}
+	h.waitForMessage("Chickpea Curry")
+	for _, msg := range h.api.Messages() {
+		if strings.Contains(msg.Text, "Spaghetti Carbonara") {
+			t.Errorf("combined message was not understood as one: %q", msg.Text)
+		}
+	}
+
+	// A command right after is handled once the waiting message is
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), telegramtest.TextUpdate(h.from, "show my recipes"))
+	h.handler.HandleUpdate(context.Background(), telegramtest.TextUpdate(h.from, "/start"))
+	list, welcome := -1, -1
+	for i, msg := range h.api.Messages() {
+		switch {
+		case strings.Contains(msg.Text, "Spaghetti Carbonara"):
+			list = i
+		case strings.Contains(msg.Text, "Welcome"):
+			welcome = i
+		}
+	}
+	if list < 0 || welcome < list {
+		t.Errorf("list sent as reply %d and welcome as reply %d, want the list first", list, welcome)
+	}
+}
+
+func TestHandler_ShowDetailsByName(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	usr, err := h.users.FindByTelegramID(context.Background(), h.from.ID)
+	if err != nil {
+		t.Fatalf("FindByTelegramID() error = %v", err)
+	}
+	ing, _ := recipe.NewIngredient("chickpeas", "1", "can", "")
+	inst, _ := recipe.NewInstruction(1, "Toss the chickpeas with the dressing", nil)
+	source, _ := recipe.NewSource("https://example.com/salad", recipe.PlatformWeb, "")
+	salad, _ := recipe.NewRecipe(recipe.UserID(usr.ID()), "Crunchy Chickpea Salad", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+	if err := h.recipes.Save(context.Background(), salad); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.intents.on("open the carbonara", ports.Intent{Type: ports.IntentShowDetails, RecipeName: "carbonara"})
+	h.intents.on("the chickpea one", ports.Intent{Type: ports.IntentShowDetails, RecipeName: "chickpea"})
+	h.intents.on("the second chickpea one", ports.Intent{Type: ports.IntentShowDetails, RecipeName: "chickpea", RecipeNumber: 2})
+	h.intents.on("open the lasagna", ports.Intent{Type: ports.IntentShowDetails, RecipeName: "lasagna"})
+	h.send("show my recipes")
+
+	h.send("open the carbonara")
+	h.expectReply("guanciale")
+
+	// Two recipes fit, so the user picks one
+	h.send("the chickpea one")
+	h.expectReply("2 recipes match", "Which one did you mean?")
+	h.press("Curry")
+	h.expectReply("Stir in the curry powder")
+
+	h.send("the second chickpea one")
+	h.expectNoReply("Which one did you mean?")
+	h.expectReply("Chickpea")
+
+	h.send("open the lasagna")
+	h.expectReply("None of the 3 recipes")
+}
+
+func TestHandler_ShowDetailsBySharedTitle(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	usr, err := h.users.FindByTelegramID(context.Background(), h.from.ID)
+	if err != nil {
+		t.Fatalf("FindByTelegramID() error = %v", err)
+	}
+	ing, _ := recipe.NewIngredient("pecorino", "50", "g", "")
+	inst, _ := recipe.NewInstruction(1, "Whisk the yolks with nonna's pecorino", nil)
+	source, _ := recipe.NewSource("https://example.com/carbonara", recipe.PlatformWeb, "nonna")
+	other, _ := recipe.NewRecipe(recipe.UserID(usr.ID()), "Spaghetti Carbonara", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+	if err := h.recipes.Save(context.Background(), other); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Recipes sharing a title are listed with their creators
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.send("show my recipes")
+	h.expectReply("Spaghetti Carbonara (sandbox-chef)", "Spaghetti Carbonara (nonna)")
+
+	h.intents.on("the carbonara from nonna", ports.Intent{Type: ports.IntentShowDetails, RecipeName: "carbonara from nonna"})
+	h.send("the carbonara from nonna")
+	h.expectNoReply("Which one did you mean?")
+	h.expectReply("nonna's pecorino")
+
+	h.intents.on("open the carbonara", ports.Intent{Type: ports.IntentShowDetails, RecipeName: "carbonara"})
+	h.send("open the carbonara")
+	h.expectReply("2 recipes match", "nonna", "sandbox")
+}
+
+func TestHandler_FollowUpsOnLastViewedRecipe(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.intents.on("open #1", ports.Intent{Type: ports.IntentShowDetails, RecipeNumber: 1})
+	h.intents.on("add its ingredients to my shopping list", ports.Intent{Type: ports.IntentAddToShoppingList})
+	h.intents.on("translate it", ports.Intent{Type: ports.IntentTranslateRecipe, Language: "Portuguese"})
+	h.intents.on("export it to obsidian", ports.Intent{Type: ports.IntentExportRecipe, ExportFormat: "obsidian"})
+
+	// "it" means nothing until a recipe is opened
+	h.send("add its ingredients to my shopping list")
+	h.expectReply("Open one first")
+
+	h.send("show my recipes")
+	h.send("open #1")
+
+	h.send("add its ingredients to my shopping list")
+	h.expectReply("to your shopping list", "guanciale")
+
+	h.send("translate it")
+	h.expectReply("Translating to Portuguese")
+	h.expectReply("Spaghetti Carbonara", "guanciale")
+
+	h.send("export it to obsidian")
+	for _, msg := range h.lastSent {
+		if msg.Method == "sendDocument" && msg.Document != nil && strings.Contains(string(msg.Document.Data), "Spaghetti Carbonara") {
+			return
+		}
+	}
+	t.Fatalf("expected the opened recipe exported, got %v", h.lastSent)
+}
+
+func TestHandler_ClarificationOptions(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	pasta := recipe.CategoryPasta
+	h.intents.on("what should I cook", ports.Intent{
+		Type:               ports.IntentListRecipes,
+		NextAction:         ports.ActionClarify,
+		ClarifyingQuestion: "What are you in the mood for?",
+		ClarifyingOptions:  []string{"A pasta dish", "Something with chickpeas"},
+	})
+	h.intents.on("what should I cook A pasta dish", ports.Intent{Type: ports.IntentFilterCategory, Category: &pasta})
+	h.intents.on("what should I cook Something with chickpeas", ports.Intent{Type: ports.IntentFilterIngredient, SearchTerm: "chickpeas"})
+
+	// One tap answers the question
+	h.send("what should I cook")
+	h.expectReply("What are you in the mood for?", "Tap an option")
+	pastaButton := h.buttonData("A pasta dish")
+	h.press("A pasta dish")
+	h.expectReply("Spaghetti Carbonara")
+
+	// The buttons of an answered question do nothing
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), telegramtest.CallbackUpdate(h.from, 1, pastaButton))
+	h.lastSent = h.api.Messages()
+	h.expectNoReply("Spaghetti Carbonara")
+
+	// Typing the number of an option still works
+	h.send("what should I cook")
+	h.send("2")
+	h.expectReply("Curry")
+}
+
+func TestHandler_ConversationSummary(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.intents.on("something for dinner", ports.Intent{
+		Type:               ports.IntentListRecipes,
+		NextAction:         ports.ActionClarify,
+		ClarifyingQuestion: "What are you in the mood for?",
+		ClarifyingOptions:  []string{"A pasta dish", "Something with chickpeas"},
+	})
+	h.intents.on("something for lunch", ports.Intent{
+		Type:               ports.IntentListRecipes,
+		NextAction:         ports.ActionClarify,
+		ClarifyingQuestion: "Hot or cold?",
+		ClarifyingOptions:  []string{"Hot", "Cold"},
+	})
+
+	// Each question and answer takes three turns of the five kept
+	h.send("something for dinner")
+	h.send("2")
+	h.send("something for lunch")
+	h.send("1")
+
+	// The turns that no longer fit are summarized ahead of the recent ones
+	h.send("something for dinner")
+	history := h.intents.lastHistory
+	if len(history) == 0 || history[0].Role != ports.RoleSummary || history[0].Content != "something for dinner" {
+		t.Fatalf("history = %+v, want a summary of the first question first", history)
+	}
+	if len(history) != 1+5 {
+		t.Errorf("history has %d turns, want the summary and five recent turns", len(history))
+	}
+
+	// Resetting the conversation forgets the summary too: the next message has no history
+	h.send("/reset")
+	h.intents.lastHistory = nil
+	h.send("something for lunch")
+	if len(h.intents.lastHistory) != 0 {
+		t.Errorf("history after /reset = %+v, want none", h.intents.lastHistory)
+	}
+}
+
+func TestHandler_Transcript(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/transcript")
+	h.expectReply("Nothing was said in the last 30 days")
+
+	h.intents.on("what should I cook", ports.Intent{
+		Type:               ports.IntentListRecipes,
+		NextAction:         ports.ActionClarify,
+		ClarifyingQuestion: "What are you in the mood for?",
+		ClarifyingOptions:  []string{"A pasta dish", "Something with chickpeas"},
+	})
+	h.send("what should I cook")
+	h.send("something else entirely")
+
+	h.send("/transcript")
+	h.expectReply("Your conversation with me, 3 messages")
+	var transcript string
+	for _, msg := range h.lastSent {
+		if msg.Method == "sendDocument" && msg.Document != nil {
+			transcript = string(msg.Document.Data)
+		}
+	}
+	for _, want := range []string{"You:\n    what should I cook", "Bot:\n    What are you in the mood for?\n\n    Options:\n    1. A pasta dish", "You:\n    something else entirely"} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("transcript = %q, want it to contain %q", transcript, want)
+		}
+	}
+	// The recipe link is not part of the conversation
+	if strings.Contains(transcript, "carbonara") {
+		t.Errorf("transcript = %q, want only the conversation", transcript)
+	}
+}
+
+func TestHandler_ClarificationLearnsUsualChoice(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.intents.on("something with a kick", ports.Intent{
+		Type:               ports.IntentListRecipes,
+		NextAction:         ports.ActionClarify,
+		ClarifyingQuestion: "What kind of kick?",
+		ClarifyingOptions:  []string{"Recipes with hot peppers", "Spicy seafood"},
+	})
+	h.intents.on("something with a kick Recipes with hot peppers", ports.Intent{Type: ports.IntentFilterIngredient, SearchTerm: "chickpeas"})
+
+	// Answered the same way enough times, the question is no longer asked
+	for i := 0; i < user.UsualChoiceAfter; i++ {
+		h.send("something with a kick")
+		h.expectReply("What kind of kick?")
+		h.press("Recipes with hot peppers")
+		h.expectReply("Curry")
+	}
+
+	h.send("something with a kick")
+	h.expectReply("Recipes with hot peppers", "based on your usual choice", "say 'options' to change")
+	h.expectReply("Curry")
+	h.expectNoReply("What kind of kick?")
+
+	// "options" asks the question after all, and the choice is learned again
+	h.send("options")
+	h.expectReply("What kind of kick?", "Tap an option")
+	h.press("Spicy seafood")
+
+	h.send("something with a kick")
+	h.expectReply("What kind of kick?")
+}
+
+func TestHandler_Reset(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.intents.on("never mind, start over", ports.Intent{Type: ports.IntentStartOver})
+
+	h.send("show my recipes")
+	h.send("/reset")
+	h.expectReply("Starting over", "the last list of 2 recipes", "untouched")
+
+	h.send("never mind, start over")
+	h.expectReply("nothing to forget")
+}
+
+func TestHandler_FallbackWhenIntentDetectionDisabled(t *testing.T) {
+	h := newTestHarness(t)
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+
+	h.flags.SetOverride(feature.Override{Flag: feature.FlagIntentDetection, Enabled: false})
+
+	h.send("show my recipes")
+	h.expectReply(GetTranslations("en").FallbackMessage)
+}
+
+func TestHandler_QualityTelemetry(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.send("show my recipes")
+	h.expectReply("Did I get that right?")
+	h.press("👎")
+
+	report := h.metrics.Flush()
+	for _, platform := range []recipe.Platform{recipe.PlatformYouTube, recipe.PlatformTikTok} {
+		if stats := report.Extractions[string(platform)]; stats.Attempts != 1 || stats.Successes != 1 {
+			t.Errorf("%s extractions = %+v, want 1 successful", platform, stats)
+		}
+	}
+	if list := report.Intents[string(ports.IntentListRecipes)]; list.Helpful != 0 || list.NotHelpful != 1 {
+		t.Errorf("LIST_RECIPES feedback = %+v, want one thumbs down", list)
+	}
+}
+
+func TestHandler_ErrorReport(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/report")
+	h.expectReply("Nothing to report")
+
+	h.send(vlogURL)
+	h.expectReply("Send /report to tell us about it")
+
+	h.send("/report it was just a vlog")
+	h.expectReply("Thanks! Report", "was sent")
+
+	var forwarded string
+	for _, msg := range h.lastSent {
+		if msg.ChatID == adminChatID {
+			forwarded = msg.Text
+		}
+	}
+	if !containsAll(forwarded, []string{"instagram\\.com/reel/sandbox\\-vlog/", "*Stage:* validate", "no ingredients found", "it was just a vlog"}) {
+		t.Errorf("report forwarded to the admin chat = %q", forwarded)
+	}
+
+	// A failure is reported once
+	h.send("/report")
+	h.expectReply("Nothing to report")
+}
+
+func TestHandler_RecoversFromPanics(t *testing.T) {
+	h := newTestHarness(t)
+	h.intents.crash("make it crash")
+
+	h.send("make it crash")
+	h.expectReply("Something went wrong on our side")
+
+	var alert string
+	for _, msg := range h.lastSent {
+		if msg.ChatID == adminChatID {
+			alert = msg.Text
+		}
+	}
+	if !strings.Contains(alert, "scripted crash on make it crash") {
+		t.Errorf("panic reported to the admin chat = %q", alert)
+	}
+
+	// The bot keeps handling updates
+	h.send("/start")
+	h.expectReply("Welcome to Recipe Bot")
+}
+
+func TestHandler_ProcessingErrorInUserLanguage(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send(vlogURL)
+	h.expectReply("Could not find any ingredients in the content", "Send /report")
+
+	h.send("/language pt")
+	h.send(vlogURL)
+	h.expectReply("Não encontrei ingredientes no conteúdo", "Envie /report")
+}
+
+func TestHandler_Activity(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/activity")
+	h.expectReply("Your activity", "Nothing recorded")
+
+	h.send(carbonaraURL)
+	h.send("/export obsidian 1")
+	h.send("/activity")
+	h.expectReply("*Exported* Spaghetti Carbonara to obsidian", "*Saved* Spaghetti Carbonara")
+
+	// Newest first
+	if text := h.lastSent[len(h.lastSent)-1].Text; strings.Index(text, "Exported") > strings.Index(text, "Saved") {
+		t.Errorf("activity = %q, want the export listed before the save", text)
+	}
+}
+
+func TestHandler_BookmarkImport(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.sendDocument("notes.txt", "hello")
+	h.expectReply("only import bookmark files")
+
+	h.sendDocument("bookmarks.html", `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<DL><p>
+    <DT><A HREF="`+carbonaraURL+`">Carbonara recipe</A>
+    <DT><A HREF="`+breakfastsURL+`">3 breakfast recipes</A>
+    <DT><A HREF="`+vlogURL+`">Recipe vlog</A>
+    <DT><A HREF="https://news.example.com/politics">Morning news</A>
+</DL><p>`)
+	h.expectReply("Found 3 recipe links in your 4 bookmarks")
+
+	h.press("Import 3")
+	result := h.waitForMessage("Bookmark import finished")
+	if !containsAll(result.Text, []string{"Saved 1 recipe.", "Skipped 1", "Could not read 1", "sandbox\\-vlog"}) {
+		t.Errorf("import result = %q", result.Text)
+	}
+
+	h.send("/recipes")
+	h.expectReply("Spaghetti Carbonara")
+
+	// The import is used up once run
+	h.handler.HandleUpdate(context.Background(), telegramtest.CallbackUpdate(h.from, 1, callbackBookmarkImport))
+	h.waitForMessage("no longer available")
+}
+
+func TestHandler_Clip(t *testing.T) {
+	h := newTestHarness(t)
+	ctx := context.Background()
+
+	h.send(carbonaraURL)
+	usr, err := h.users.FindByTelegramID(ctx, h.from.ID)
+	if err != nil {
+		t.Fatalf("FindByTelegramID() error = %v", err)
+	}
+
+	h.send("/clip")
+	token := command.NewClipRecipeCommand(nil, nil, clipSecret).Token(usr)
+	h.expectReply("Browser extension", token, "recipes.example.com/api/v1/clip")
+
+	// A reset token replaces the one given out before
+	h.send("/clip reset")
+	h.expectReply("New browser extension token", "no longer works")
+	h.expectNoReply(token)
+	if _, err := h.handler.clipRecipeCommand.Authenticate(ctx, token); !errors.Is(err, shared.ErrInvalidAPIToken) {
+		t.Errorf("Authenticate() with the previous token error = %v, want ErrInvalidAPIToken", err)
+	}
+	text := h.lastSent[len(h.lastSent)-1].Text
+	reset := text[strings.Index(text, "`")+1 : strings.Index(text, "`\n")]
+	if authenticated, err := h.handler.clipRecipeCommand.Authenticate(ctx, reset); err != nil || authenticated.ID() != usr.ID() {
+		t.Errorf("Authenticate() with the new token = %v, %v, want the test user", authenticated, err)
+	}
+
+	// The extension replies with a deep link opening the clipped recipe
+	rec, err := h.recipes.FindBySourceURL(ctx, carbonaraURL)
+	if err != nil {
+		t.Fatalf("FindBySourceURL() error = %v", err)
+	}
+	link := RecipeDeepLink(telegramtest.BotUsername, rec.ID().String())
+	payload := link[strings.Index(link, "=")+1:]
+
+	h.send("/start " + payload)
+	h.expectReply("Spaghetti Carbonara")
+
+	h.send("/start recipe_unknown")
+	h.expectReply("isn't in your collection")
+}
+
+func TestHandler_Email(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/email")
+	h.expectReply("Your recipe inbox", "@recipes.example.com")
+
+	// The address shown is the one forwarded emails are delivered to
+	text := h.lastSent[len(h.lastSent)-1].Text
+	address := text[strings.Index(text, "`")+1 : strings.LastIndex(text, "`")]
+	usr, err := h.handler.forwardEmailCommand.Resolve(context.Background(), address)
+	if err != nil || usr.TelegramID() != h.from.ID {
+		t.Errorf("Resolve(%q) = %v, %v, want the test user", address, usr, err)
+	}
+}
+
+func TestHandler_Subscribe(t *testing.T) {
+	h := newTestHarness(t)
+	const feedURL = "https://smittenkitchen.com/feed/"
+	h.feeds.publish(feedURL, "Smitten Kitchen", feed.Item{ID: "old", Title: "Old post", Link: "https://smittenkitchen.com/old/"})
+
+	h.send("/subscribe https://example.com/not-a-feed")
+	h.expectReply("couldn't read a feed")
+
+	h.send("/subscribe " + feedURL)
+	h.expectReply("Following", "Smitten Kitchen")
+	h.send("/subscribe " + feedURL)
+	h.expectReply("already follow")
+	h.send("/subscribe")
+	h.expectReply("1. Smitten Kitchen")
+
+	poller := NewFeedPoller(FeedPollerConfig{
+		Bot:                        h.handler.bot,
+		UserRepo:                   h.users,
+		ManageSubscriptionsCommand: h.handler.manageSubscriptionsCommand,
+	})
+	poll := func() {
+		h.api.Reset()
+		poller.Poll(context.Background())
+		h.lastSent = h.api.Messages()
+	}
+
+	// Posts published before subscribing are not proposed
+	poll()
+	if len(h.lastSent) != 0 {
+		t.Fatalf("sent %d messages for old posts", len(h.lastSent))
+	}
+
+	h.feeds.publish(feedURL, "Smitten Kitchen", feed.Item{ID: "new", Title: "Carbonara", Link: "https://smittenkitchen.com/carbonara/"})
+	poll()
+	h.expectReply("New from Smitten Kitchen", "Spaghetti Carbonara", "https://smittenkitchen.com/carbonara/")
+	if h.lastSent[0].ChatID != h.from.ID {
+		t.Errorf("post sent to chat %d, want %d", h.lastSent[0].ChatID, h.from.ID)
+	}
+	if _, err := h.recipes.FindBySourceURL(context.Background(), "https://smittenkitchen.com/carbonara/"); err == nil {
+		t.Fatalf("recipe saved before the user asked")
+	}
+
+	h.press("Save recipe")
+	h.expectReply("Saved", "Spaghetti Carbonara")
+	if _, err := h.recipes.FindBySourceURL(context.Background(), "https://smittenkitchen.com/carbonara/"); err != nil {
+		t.Errorf("FindBySourceURL() error = %v", err)
+	}
+
+	poll()
+	if len(h.lastSent) != 0 {
+		t.Errorf("post proposed twice")
+	}
+
+	h.send("/unsubscribe 1")
+	h.expectReply("Stopped following", "Smitten Kitchen")
+	h.send("/subscribe")
+	h.expectReply("don't follow any recipe blog")
+}
+
+func TestHandler_ExportObsidian(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/export obsidian 1")
+
+	var doc *string
+	for _, msg := range h.lastSent {
+		if msg.Method == "sendDocument" && msg.Document != nil {
+			name := msg.Document.Name
+			doc = &name
+			if !strings.Contains(string(msg.Document.Data), "Spaghetti Carbonara") {
+				t.Errorf("exported document does not contain the recipe title")
+			}
+		}
+	}
+	if doc == nil || !strings.HasSuffix(*doc, ".md") {
+		t.Fatalf("expected a .md document, got %v", h.lastSent)
+	}
+}
+
+func TestHandler_ChatActions(t *testing.T) {
+	h := newTestHarness(t)
+
+	actions := func() []string {
+		var sent []string
+		for _, call := range h.api.CallsTo("sendChatAction") {
+			sent = append(sent, call.Params["action"])
+		}
+		return sent
+	}
+
+	// The chat shows the bot typing while a link is read
+	h.send(carbonaraURL)
+	if got := actions(); len(got) == 0 || got[0] != "typing" {
+		t.Errorf("chat actions while reading a link = %q, want typing", got)
+	}
+
+	h.send("/export obsidian 1")
+	if got := actions(); len(got) == 0 || got[0] != "upload_document" {
+		t.Errorf("chat actions while exporting = %q, want upload_document", got)
+	}
+}
+
+func TestHandler_ExportToCloudStorage(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.send("/connect")
+	h.expectReply("/connect dropbox", "/connect folder")
+	h.send("/connect folder Recipes")
+	h.expectReply("Connect Dropbox or Google Drive first")
+	h.send("/connect drive")
+	h.expectReply("Google Drive integration is not configured")
+
+	h.send("/connect dropbox")
+	h.expectReply("Connect to Dropbox", "https://cloud.example.com/authorize?state=")
+	text := h.lastSent[0].Text
+	start := strings.Index(text, "state=") + len("state=")
+	end := start + strings.IndexByte(text[start:], ')')
+	if _, err := h.storage.Callback(context.Background(), text[start:end], "code", time.Now()); err != nil {
+		t.Fatalf("Callback() error = %v", err)
+	}
+	if _, err := h.storage.Callback(context.Background(), text[start:end], "code", time.Now()); err == nil {
+		t.Error("Callback() with a used state succeeded, want the link expired")
+	}
+
+	h.send("/export obsidian")
+	h.expectReply("Saved 2 recipe(s) to Dropbox", "Receipt Bot")
+	want := []string{"Receipt Bot/One-Pot_Chickpea_Curry.md", "Receipt Bot/Spaghetti_Carbonara.md"}
+	if got := h.cloud.uploaded(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("uploaded %v, want %v", got, want)
+	}
+
+	h.send("/connect folder Recipes/Mains")
+	h.expectReply("now saves to Recipes/Mains in Dropbox")
+	h.send("/export obsidian 1")
+	h.expectReply("Saved 1 recipe(s) to Dropbox")
+	if got := h.cloud.uploaded(); len(got) != 3 {
+		t.Errorf("uploaded %v, want a third file in Recipes/Mains", got)
+	}
+
+	h.send("/disconnect dropbox")
+	h.expectReply("Dropbox disconnected")
+	h.send("/export obsidian 1")
+	for _, msg := range h.lastSent {
+		if msg.Method == "sendDocument" {
+			return
+		}
+	}
+	t.Errorf("expected the export in chat after disconnecting, got %v", h.lastSent)
+}
+
+func TestHandler_ExportRecipeApps(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	sentDocument := func() *telegramtest.File {
+		t.Helper()
+		for _, msg := range h.lastSent {
+			if msg.Method == "sendDocument" && msg.Document != nil {
+				return msg.Document
+			}
+		}
+		t.Fatalf("expected a document, got %v", h.lastSent)
+		return nil
+	}
+
+	h.send("/export crouton 1")
+	doc := sentDocument()
+	if !strings.HasSuffix(doc.Name, ".crumb") {
+		t.Errorf("crouton export name = %q, want .crumb", doc.Name)
+	}
+	var crumb struct {
+		Name        string `json:"name"`
+		Ingredients []any  `json:"ingredients"`
+		Steps       []any  `json:"steps"`
+	}
+	if err := json.Unmarshal(doc.Data, &crumb); err != nil {
+		t.Fatalf("crumb is not valid JSON: %v", err)
+	}
+	if crumb.Name != "One-Pot Chickpea Curry" || len(crumb.Ingredients) == 0 || len(crumb.Steps) == 0 {
+		t.Errorf("unexpected crumb contents: %+v", crumb)
+	}
+
+	h.send("/export crouton")
+	if doc := sentDocument(); !strings.HasSuffix(doc.Name, ".zip") {
+		t.Errorf("crouton collection export name = %q, want .zip", doc.Name)
+	}
+
+	h.send("/export anylist")
+	doc = sentDocument()
+	for _, want := range []string{"Spaghetti Carbonara", "Chickpea Curry", "Ingredients\n", "Directions\n"} {
+		if !strings.Contains(string(doc.Data), want) {
+			t.Errorf("anylist export missing %q", want)
+		}
+	}
+
+	h.send("/export samsung")
+	doc = sentDocument()
+	if !strings.Contains(string(doc.Data), carbonaraURL) || !strings.Contains(string(doc.Data), curryURL) {
+		t.Errorf("samsung food export should list the source links, got %q", doc.Data)
+	}
+}
+
+func TestHandler_ExportFields(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	exported := func() string {
+		t.Helper()
+		for _, msg := range h.lastSent {
+			if msg.Method == "sendDocument" && msg.Document != nil {
+				return string(msg.Document.Data)
+			}
+		}
+		t.Fatalf("expected a document, got %v", h.lastSent)
+		return ""
+	}
+
+	h.send("/export obsidian 1 --fields=shopping")
+	doc := exported()
+	if !strings.Contains(doc, "## Ingredients") || strings.Contains(doc, "## Instructions") || strings.Contains(doc, "source_url") {
+		t.Errorf("shopping export should hold only the ingredients, got:\n%s", doc)
+	}
+
+	h.send("/export obsidian 1 --fields=steps,transcript")
+	doc = exported()
+	if !strings.Contains(doc, "## Transcript\n\nBoil the spaghetti.") || strings.Contains(doc, "## Ingredients") {
+		t.Errorf("export should hold the steps and transcript, got:\n%s", doc)
+	}
+
+	h.send("/export obsidian 1 --fields=pictures")
+	h.expectReply("Unknown fields: pictures", "Profiles: full, no-transcript, shopping, with-notes")
+
+	// Per-user defaults apply to exports and printing without --fields
+	h.send("/export fields shopping")
+	h.expectReply("your exports include: ingredients")
+
+	h.send("/export obsidian 1")
+	if doc := exported(); strings.Contains(doc, "## Instructions") {
+		t.Errorf("export should use the shopping default, got:\n%s", doc)
+	}
+	h.send("/print 1")
+	if doc := exported(); !strings.Contains(doc, "[ ] 200 g spaghetti") || strings.Contains(doc, "INSTRUCTIONS") || strings.Contains(doc, "Serves 2") {
+		t.Errorf("printable copy should use the shopping default, got:\n%s", doc)
+	}
+
+	h.send("/export fields reset")
+	h.expectReply("metadata, ingredients, steps, notes, source")
+
+	h.send("/export obsidian 1")
+	if doc := exported(); !strings.Contains(doc, "## Instructions") || strings.Contains(doc, "## Transcript") {
+		t.Errorf("export should use the default fields, got:\n%s", doc)
+	}
+}
+
+func TestHandler_Print(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	documents := func() map[string]string {
+		docs := make(map[string]string)
+		for _, msg := range h.lastSent {
+			if msg.Method == "sendDocument" && msg.Document != nil {
+				docs[msg.Document.Name] = string(msg.Document.Data)
+			}
+		}
+		return docs
+	}
+
+	h.send("/print 1")
+	text, ok := documents()["Spaghetti_Carbonara.txt"]
+	if !ok {
+		t.Fatalf("expected a plain-text document, got %v", h.lastSent)
+	}
+	for _, want := range []string{"Spaghetti Carbonara\n===", "Serves 2 | Prep 5 min", "[ ] 200 g spaghetti", "  1. Boil the spaghetti"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("printable text missing %q:\n%s", want, text)
+		}
+	}
+
+	h.send("/print 1 6 html")
+	docs := documents()
+	if !strings.Contains(docs["Spaghetti_Carbonara.txt"], "Serves 6 (scaled from 2)") ||
+		!strings.Contains(docs["Spaghetti_Carbonara.txt"], "600 g spaghetti") ||
+		!strings.Contains(docs["Spaghetti_Carbonara.txt"], "3 tsp black pepper") {
+		t.Errorf("expected quantities scaled to 6 servings, got:\n%s", docs["Spaghetti_Carbonara.txt"])
+	}
+	if page := docs["Spaghetti_Carbonara.html"]; !strings.Contains(page, "<li>600 g spaghetti</li>") {
+		t.Errorf("expected a scaled HTML page, got:\n%s", page)
+	}
+
+	h.send("/print 1 lots")
+	h.expectReply("Servings must be a positive number")
+}
+
+func TestHandler_Audio(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/audio 1")
+	var voice *telegramtest.Message
+	for i, msg := range h.lastSent {
+		if msg.Method == "sendVoice" {
+			voice = &h.lastSent[i]
+		}
+	}
+	if voice == nil || voice.Document == nil {
+		t.Fatalf("expected a voice message, got %v", h.lastSent)
+	}
+	audio := string(voice.Document.Data)
+	for _, want := range []string{"en: Spaghetti Carbonara.", "Ingredients:\n200 g spaghetti.", "Steps:\nStep 1. Boil the spaghetti"} {
+		if !strings.Contains(audio, want) {
+			t.Errorf("spoken text missing %q:\n%s", want, audio)
+		}
+	}
+	if strings.Index(audio, "Ingredients:") > strings.Index(audio, "Steps:") {
+		t.Errorf("expected the ingredients before the steps:\n%s", audio)
+	}
+	if !strings.Contains(voice.Text, "Spaghetti Carbonara") {
+		t.Errorf("voice caption = %q, want the recipe title", voice.Text)
+	}
+
+	h.send("/audio 9")
+	h.expectReply("Recipe #9 not found")
+}
+
+func TestHandler_Premium(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/export cookbook")
+	h.expectReply("premium feature", "/premium")
+
+	h.send("/premium")
+	h.expectReply("Pick a plan")
+	h.press("30 days for 100 ⭐")
+	invoices := h.api.CallsTo("sendInvoice")
+	if len(invoices) != 1 {
+		t.Fatalf("expected an invoice, got calls %v", h.api.Calls())
+	}
+	invoice := invoices[0].Params
+	if invoice["currency"] != "XTR" || invoice["provider_token"] != "" || !strings.Contains(invoice["prices"], `"amount":100`) {
+		t.Errorf("invoice = %v, want 100 Telegram Stars", invoice)
+	}
+	payload := invoice["payload"]
+
+	// A changed price is refused before the user is charged
+	h.deliver(telegramtest.PreCheckoutUpdate(h.from, "XTR", 50, payload))
+	if answers := h.api.CallsTo("answerPreCheckoutQuery"); len(answers) != 1 || answers[0].Params["ok"] != "false" {
+		t.Errorf("expected the pre-checkout query to be refused, got %v", answers)
+	}
+	h.deliver(telegramtest.PreCheckoutUpdate(h.from, "XTR", 100, payload))
+	if answers := h.api.CallsTo("answerPreCheckoutQuery"); len(answers) != 1 || answers[0].Params["ok"] != "true" {
+		t.Errorf("expected the pre-checkout query to be confirmed, got %v", answers)
+	}
+
+	paid := telegramtest.PaymentUpdate(h.from, "XTR", 100, payload)
+	h.deliver(paid)
+	h.expectReply("Thank you! You have premium until")
+	usr, err := h.users.FindByTelegramID(context.Background(), h.from.ID)
+	if err != nil {
+		t.Fatalf("FindByTelegramID() error = %v", err)
+	}
+	until := *usr.PremiumUntil()
+
+	// Telegram delivering the payment again does not extend premium twice
+	if sent := h.deliver(paid); len(sent) != 0 {
+		t.Errorf("expected no reply to a payment delivered again, got %v", sent)
+	}
+	usr, _ = h.users.FindByTelegramID(context.Background(), h.from.ID)
+	if !usr.PremiumUntil().Equal(until) {
+		t.Errorf("premium until %v after the payment was delivered again, want %v", usr.PremiumUntil(), until)
+	}
+
+	h.send("/premium")
+	h.expectReply("You have premium until")
+
+	h.send("/export cookbook")
+	var pdf *telegramtest.Message
+	for i, msg := range h.lastSent {
+		if msg.Method == "sendDocument" && msg.Document != nil && msg.Document.Name == "cookbook.pdf" {
+			pdf = &h.lastSent[i]
+		}
+	}
+	if pdf == nil || !strings.HasPrefix(string(pdf.Document.Data), "%PDF-") || !strings.Contains(string(pdf.Document.Data), "Spaghetti Carbonara") {
+		t.Fatalf("expected a PDF cookbook with the recipe, got %v", h.lastSent)
+	}
+}
+
+func TestHandler_LinkQuota(t *testing.T) {
+	h := newTestHarness(t)
+	h.quota.SetLimits(1, 2)
+
+	h.send(carbonaraURL)
+	h.send(carbonaraURL)
+	h.expectReply("as many recipe links as you can this hour", "/premium")
+
+	// Premium users get the higher limit
+	usr, err := h.users.FindByTelegramID(context.Background(), h.from.ID)
+	if err != nil {
+		t.Fatalf("FindByTelegramID() error = %v", err)
+	}
+	until := time.Now().Add(24 * time.Hour)
+	_ = h.users.UpdatePremium(context.Background(), usr.ID(), &until)
+	h.send(carbonaraURL)
+	for _, msg := range h.lastSent {
+		if strings.Contains(msg.Text, "this hour") {
+			t.Errorf("premium user hit the free limit: %q", msg.Text)
+		}
+	}
+}
+
+func TestHandler_WebAppButton(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/app")
+	h.expectReply("not available")
+
+	h.flags.SetOverride(feature.Override{Flag: feature.FlagWebUI, Enabled: true})
+	h.send("/app")
+	h.expectReply("Browse your recipes")
+	if markup := h.lastSent[0].ReplyMarkup; !strings.Contains(markup, `"web_app":{"url":"https://recipes.example.com/"}`) {
+		t.Errorf("expected an Open collection web app button, got %s", markup)
+	}
+}
+
+func TestHandler_RecipeHistoryAndRevert(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.send("/history 1")
+	h.expectReply("has not changed")
+
+	// Make the stored copy differ from what the fixture extracts
+	ctx := context.Background()
+	usr, _ := h.users.FindByTelegramID(ctx, h.from.ID)
+	saved, _ := h.recipes.FindByUserID(ctx, usr.ID())
+	stale := saved[0].Snapshot()
+	stale.Title = "Quick Carbonara"
+	saved[0].ApplySnapshot(stale)
+	if err := h.recipes.Update(ctx, saved[0]); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	h.send("/reextract 1")
+	h.expectReply("kept as v1")
+
+	h.send("/history 1")
+	h.expectReply("*v1*", "re\\-extracted", "Quick Carbonara → Spaghetti Carbonara")
+	h.expectReply("*v2* · current")
+
+	h.send("/revert 1 1")
+	h.expectReply("reverted to v1")
+
+	reverted, _ := h.recipes.FindByID(ctx, saved[0].ID())
+	if reverted.Title() != "Quick Carbonara" {
+		t.Errorf("Title() after revert = %q, want %q", reverted.Title(), "Quick Carbonara")
+	}
+
+	// The reverted-away content is kept too
+	h.send("/history 1")
+	h.expectReply("*v2*", "reverted to v1")
+
+	h.send("/revert 1 9")
+	h.expectReply("Version v9 not found")
+}
+
+func TestHandler_MealPlanShoppingList(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+
+	h.send("/shopping")
+	h.expectReply("Nothing is planned")
+
+	// #1 is the curry (newest), #2 the carbonara
+	h.send("/plan add 1 monday")
+	h.expectReply("Monday", "One\\-Pot Chickpea Curry")
+	h.send("/plan add 2 wednesday 4")
+	h.expectReply("Wednesday", "Spaghetti Carbonara · 4 servings")
+	h.send("/plan add 2 friday")
+
+	h.send("/pantry add eggs")
+
+	h.send("/shopping")
+	// Carbonara twice: once for 4 servings (double), once as written
+	h.expectReply("Shopping list", "Pantry", "600 g spaghetti", "Produce", "3 clove garlic")
+	h.expectNoReply("eggs")
+
+	h.press("spaghetti")
+	h.expectReply("✅ 600 g spaghetti")
+
+	h.send("/plan clear")
+	h.send("/plan")
+	h.expectReply("Nothing planned yet")
+}
+
+func TestHandler_ShoppingListIntent(t *testing.T) {
+	h := newTestHarness(t)
+	h.intents.on("what do I need to buy this week", ports.Intent{Type: ports.IntentShoppingList, Confidence: 0.95})
+
+	h.send(carbonaraURL)
+	h.send("/plan add 1 sun")
+
+	h.send("what do I need to buy this week")
+	h.expectReply("Shopping list", "200 g spaghetti")
+}
+
+func TestHandler_CompilationLink(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send(breakfastsURL)
+	h.expectReply("I found 3 recipes", "1\\. Overnight Oats", "2\\. Banana Pancakes", "3\\. Avocado Toast")
+	h.buttonData("Save all")
+
+	// Nothing is saved until the user chooses
+	h.send("/recipes")
+	h.expectReply("don't have any saved recipes")
+
+	h.send(breakfastsURL)
+	h.press("Banana Pancakes")
+	h.expectReply("Banana Pancakes", "\\#2")
+
+	edits := h.api.CallsTo("editMessageReplyMarkup")
+	if len(edits) != 1 {
+		t.Fatalf("got %d keyboard edits, want 1", len(edits))
+	}
+	remaining := edits[0].Params["reply_markup"]
+	if !containsAll(remaining, []string{"Overnight Oats", "Avocado Toast", "Save all"}) || strings.Contains(remaining, "Banana") {
+		t.Errorf("keyboard after saving one = %s, want the other two and Save all", remaining)
+	}
+}
+
+func TestHandler_ImplausibleClaims(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send(stewURL)
+	h.expectReply("*Sunday Beef Stew* has some details that look off",
+		"takes 10 min, but its steps add up to 3h 15 min", "serves 400", "1200 kg beef chuck")
+
+	// Nothing is saved until the user checks it
+	h.send("/recipes")
+	h.expectReply("don't have any saved recipes")
+
+	h.send(stewURL)
+	h.press("Save with fixes")
+	h.expectReply("Sunday Beef Stew", "1200 g beef chuck")
+	if strings.Contains(h.lastSent[0].Text, "400") {
+		t.Errorf("fixed recipe still serves 400:\n%s", h.lastSent[0].Text)
+	}
+
+	// Recipes that look right are saved straight away
+	h.send(carbonaraURL)
+	h.expectReply("Spaghetti Carbonara")
+	h.send("/recipes")
+	h.expectReply("Sunday Beef Stew", "Spaghetti Carbonara")
+}
+
+func TestHandler_CompilationLinkSaveAll(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send(breakfastsURL)
+	h.press("Save all")
+	h.expectReply("Saved 3 recipes", "Overnight Oats", "Banana Pancakes", "Avocado Toast")
+
+	// The buttons are gone once everything is saved
+	edits := h.api.CallsTo("editMessageReplyMarkup")
+	if len(edits) != 1 || strings.Contains(edits[0].Params["reply_markup"], "callback_data") {
+		t.Errorf("keyboard edits after saving all = %+v, want the buttons removed", edits)
+	}
+
+	h.send("/recipes")
+	h.expectReply("Overnight Oats", "Banana Pancakes", "Avocado Toast")
+}
+
+func TestHandler_RepliesInMessageLanguage(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.intents.on("mostra a receita 1", ports.Intent{Type: ports.IntentShowDetails, RecipeNumber: 1})
+	h.intents.on("show recipe 1", ports.Intent{Type: ports.IntentShowDetails, RecipeNumber: 1})
+
+	h.send("show my recipes")
+	h.send("mostra a receita 1")
+	h.expectReply("Spaghetti Carbonara", "Ingredientes")
+
+	// The stored preference is untouched
+	h.send("show recipe 1")
+	h.expectReply("Spaghetti Carbonara", "Ingredients")
+	h.send("/help")
+	h.expectReply("Recipe Bot Help")
+}
+
+func TestHandler_PlainMode(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.intents.on("show recipe 1", ports.Intent{Type: ports.IntentShowDetails, RecipeNumber: 1})
+
+	h.send("/plain on")
+	h.expectReply("Plain mode is on")
+
+	h.send("show my recipes")
+	h.send("show recipe 1")
+	var msg telegramtest.Message
+	for _, sent := range h.lastSent {
+		if strings.Contains(sent.Text, "Spaghetti Carbonara") {
+			msg = sent
+		}
+	}
+	if msg.ParseMode != "" || strings.ContainsAny(msg.Text, "🍳*_") {
+		t.Fatalf("expected the recipe without emojis or Markdown, got %q (parse mode %q)", msg.Text, msg.ParseMode)
+	}
+	if !strings.Contains(msg.Text, "\n1. ") {
+		t.Fatalf("expected the recipe with numbered ingredients, got %q", msg.Text)
+	}
+
+	h.send("/plain")
+	h.expectReply("Plain mode is off")
+	h.send("show recipe 1")
+	h.expectReply("🍳 *Spaghetti Carbonara*")
+}
+
+func TestHandler_GeneratesCoverForRecipeWithoutImage(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.intents.on("show recipe 1", ports.Intent{Type: ports.IntentShowDetails, RecipeNumber: 1})
+	h.intents.on("show recipe 2", ports.Intent{Type: ports.IntentShowDetails, RecipeNumber: 2})
+	const curry, carbonara = "show recipe 1", "show recipe 2" // newest first
+
+	// The YouTube recipe has the video's thumbnail
+	h.send("show my recipes")
+	h.send(carbonara)
+	h.expectReply("Carbonara")
+	for _, msg := range h.lastSent {
+		if strings.Contains(msg.ReplyMarkup, callbackCover+":") {
+			t.Fatalf("expected no cover button for a recipe with a source image, got %s", msg.ReplyMarkup)
+		}
+	}
+
+	h.send(curry)
+	h.press("Generate a cover image")
+	photos := 0
+	for _, msg := range h.lastSent {
+		if msg.Method != "sendPhoto" {
+			continue
+		}
+		photos++
+		if !strings.Contains(msg.Text, "AI-generated") {
+			t.Errorf("expected the cover flagged as AI-generated, got caption %q", msg.Text)
+		}
+		if msg.Document == nil || !strings.Contains(string(msg.Document.Data), "Chickpea Curry") {
+			t.Errorf("expected the cover drawn from the recipe, got %+v", msg.Document)
+		}
+	}
+	if photos != 1 {
+		t.Fatalf("expected one cover photo, got %d", photos)
+	}
+
+	// The cover is kept and shown with the recipe from then on
+	h.send(curry)
+	if h.lastSent[0].Method != "sendPhoto" || !strings.Contains(h.lastSent[0].Text, "AI-generated") {
+		t.Fatalf("expected the recipe to start with its cover, got %+v", h.lastSent[0])
+	}
+	for _, msg := range h.lastSent {
+		if strings.Contains(msg.ReplyMarkup, callbackCover+":") {
+			t.Fatalf("expected no cover button once the recipe has a cover, got %s", msg.ReplyMarkup)
+		}
+	}
+}
+
+func TestHandler_ChecksRecipesAgainstDiet(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.intents.on("show recipe 1", ports.Intent{Type: ports.IntentShowDetails, RecipeNumber: 1})
+
+	h.send("/diet paleo")
+	h.expectReply("Unknown diet")
+
+	h.send("/diet vegetarian")
+	h.expectReply("checked against your vegetarian diet")
+
+	// Guanciale is pork; pecorino is usually made with animal rennet
+	h.send("show my recipes")
+	h.send("show recipe 1")
+	h.expectReply("Spaghetti Carbonara", "Breaks your vegetarian diet", "guanciale", "Check for your vegetarian diet", "pecorino")
+
+	h.send("/plan add 1 monday")
+	h.expectReply("Breaks your vegetarian diet", "guanciale")
+
+	h.send("/diet off")
+	h.expectReply("Diet checks are off")
+	h.send("show recipe 1")
+	h.expectNoReply("diet")
+}
+
+func TestHandler_CreditsRecipeCreator(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.intents.on("show my recipes", ports.Intent{Type: ports.IntentListRecipes})
+	h.intents.on("show recipe 1", ports.Intent{Type: ports.IntentShowDetails, RecipeNumber: 1})
+
+	h.send("show my recipes")
+	h.send("show recipe 1")
+	h.expectReply("Carbonara", "@sandboxchef", "Published: 2 Mar 2024")
+}
+
+func TestHandler_TranscriptsOptOut(t *testing.T) {
+	h := newTestHarness(t)
+
+	h.send("/transcripts off")
+	h.expectReply("won't keep their transcript")
+
+	h.send(carbonaraURL)
+	saved, err := h.recipes.FindBySourceURL(context.Background(), carbonaraURL)
+	if err != nil {
+		t.Fatalf("FindBySourceURL() unexpected error = %v", err)
+	}
+	if saved.Transcript() != "" || saved.Captions() != "" {
+		t.Errorf("expected no transcript or captions kept, got %q and %q", saved.Transcript(), saved.Captions())
+	}
+
+	h.send("/transcripts")
+	h.expectReply("don't keep transcripts")
+}
@@ -0,0 +1,618 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"receipt-bot/internal/adapters/anylist"
+	"receipt-bot/internal/adapters/crouton"
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/adapters/obsidian"
+	"receipt-bot/internal/adapters/sandbox"
+	"receipt-bot/internal/adapters/telegram/telegramtest"
+	"receipt-bot/internal/adapters/whisk"
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/application/query"
+	"receipt-bot/internal/domain/bookmark"
+	"receipt-bot/internal/domain/diet"
+	"receipt-bot/internal/domain/feature"
+	"receipt-bot/internal/domain/feed"
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/nutrition"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/telemetry"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// Fixture URLs from the sandbox's built-in fixtures
+const (
+	carbonaraURL  = "https://www.youtube.com/watch?v=sandbox-carbonara"
+	curryURL      = "https://www.tiktok.com/@sandbox/video/1"
+	breakfastsURL = "https://www.youtube.com/watch?v=sandbox-breakfasts" // compilation of 3 recipes
+	vlogURL       = "https://www.instagram.com/reel/sandbox-vlog/"       // no recipe, fails to process
+	stewURL       = "https://www.youtube.com/watch?v=sandbox-stew"       // states implausible time, servings and amount
+)
+
+// Links the tests send that have no fixture of their own, replayed as the carbonara
+var carbonaraAliases = map[string]string{
+	"https://" + blockedDomain + "/pasta":   carbonaraURL,
+	"https://bit.ly/carbonara":              carbonaraURL,
+	"https://smittenkitchen.com/carbonara/": carbonaraURL,
+}
+
+// adminChatID receives the error reports filed with /report
+const adminChatID = 9000
+
+// blockedDomain is a site whose recipes are never shown to guests
+const blockedDomain = "blocked.example.com"
+
+// clipSecret signs the API tokens /clip gives out
+const clipSecret = "harness-clip-secret-of-32-characters"
+
+// scriptedIntentDetector returns pre-recorded intents keyed by message text
+type scriptedIntentDetector struct {
+	mu          sync.Mutex
+	intents     map[string]*ports.Intent
+	crashes     map[string]bool
+	lastHistory []ports.ConversationTurn // the history of the latest detection in context
+}
+
+func newScriptedIntentDetector() *scriptedIntentDetector {
+	return &scriptedIntentDetector{intents: make(map[string]*ports.Intent), crashes: make(map[string]bool)}
+}
+
+// crash makes detecting the intent of a message panic, standing in for a bug in any handler
+func (d *scriptedIntentDetector) crash(text string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.crashes[text] = true
+}
+
+// on registers the intent returned for a message
+func (d *scriptedIntentDetector) on(text string, intent ports.Intent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if intent.Confidence == 0 {
+		intent.Confidence = 0.95
+	}
+	if intent.NextAction == "" {
+		intent.NextAction = ports.ActionExecute
+	}
+	d.intents[text] = &intent
+}
+
+func (d *scriptedIntentDetector) DetectIntent(ctx context.Context, text string) (*ports.Intent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.crashes[text] {
+		panic("scripted crash on " + text)
+	}
+	if intent, ok := d.intents[text]; ok {
+		cp := *intent
+		return &cp, nil
+	}
+	return &ports.Intent{Type: ports.IntentUnknown}, nil
+}
+
+func (d *scriptedIntentDetector) DetectIntentWithContext(ctx context.Context, text string, history []ports.ConversationTurn) (*ports.Intent, error) {
+	d.mu.Lock()
+	d.lastHistory = history
+	d.mu.Unlock()
+	return d.DetectIntent(ctx, text)
+}
+
+// SummarizeConversation condenses turns to what the user said, separated by ";"
+func (d *scriptedIntentDetector) SummarizeConversation(ctx context.Context, summary string, turns []ports.ConversationTurn) (string, error) {
+	said := []string{}
+	if summary != "" {
+		said = append(said, summary)
+	}
+	for _, turn := range turns {
+		if turn.Role == "user" {
+			said = append(said, turn.Content)
+		}
+	}
+	return strings.Join(said, "; "), nil
+}
+
+// scriptedBarcodes stands in for the barcode decoder and Open Food Facts:
+// a "photo" contains its barcode as plain text, looked up in a fixed catalog
+type scriptedBarcodes map[string]*ports.Product
+
+func (b scriptedBarcodes) DecodeBarcode(image []byte) (string, error) {
+	code := string(image)
+	if _, err := strconv.Atoi(code); err != nil {
+		return "", shared.ErrNoBarcode
+	}
+	return code, nil
+}
+
+func (b scriptedBarcodes) LookupBarcode(ctx context.Context, code string) (*ports.Product, error) {
+	if product, ok := b[code]; ok {
+		cp := *product
+		return &cp, nil
+	}
+	return nil, shared.ErrProductNotFound
+}
+
+// scriptedFeeds stands in for the feed reader with feeds keyed by URL
+type scriptedFeeds struct {
+	mu    sync.Mutex
+	feeds map[string]*feed.Feed
+}
+
+// publish puts a post at the top of a feed, creating the feed if needed
+func (f *scriptedFeeds) publish(url, title string, item feed.Item) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.feeds[url] == nil {
+		f.feeds[url] = &feed.Feed{Title: title}
+	}
+	f.feeds[url].Items = append([]feed.Item{item}, f.feeds[url].Items...)
+}
+
+func (f *scriptedFeeds) Read(ctx context.Context, url string) (*feed.Feed, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	current, ok := f.feeds[url]
+	if !ok {
+		return nil, shared.ErrFeedUnreadable
+	}
+	cp := *current
+	cp.Items = append([]feed.Item(nil), current.Items...)
+	return &cp, nil
+}
+
+// scriptedSpeech stands in for text-to-speech: the audio is the text it was asked to speak
+type scriptedSpeech struct{}
+
+func (scriptedSpeech) Synthesize(ctx context.Context, text string, language string) ([]byte, error) {
+	return []byte(language + ": " + text), nil
+}
+
+// scriptedImages stands in for an image generator: the image is the description it was given
+type scriptedImages struct{}
+
+func (scriptedImages) GenerateImage(ctx context.Context, description string) ([]byte, error) {
+	return []byte(description), nil
+}
+
+// scriptedCloud stands in for Dropbox: its authorization URL hands the state
+// straight back and uploads are kept by path
+type scriptedCloud struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func (c *scriptedCloud) AuthURL(state string) string {
+	return "https://cloud.example.com/authorize?state=" + state
+}
+
+func (c *scriptedCloud) ExchangeCode(ctx context.Context, code string) (string, error) {
+	return "refresh-" + code, nil
+}
+
+func (c *scriptedCloud) Upload(ctx context.Context, refreshToken, folder, filename string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files[folder+"/"+filename] = data
+	return nil
+}
+
+// uploaded returns the paths of the uploaded files
+func (c *scriptedCloud) uploaded() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	paths := make([]string, 0, len(c.files))
+	for path := range c.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// testHarness drives a fully wired Handler against a fake Telegram API,
+// in-memory repositories and the sandbox fixtures
+type testHarness struct {
+	t         *testing.T
+	api       *telegramtest.Server
+	handler   *Handler
+	recipes   *memory.RecipeRepository
+	users     *memory.UserRepository
+	flags     *memory.FeatureFlagRepository
+	intents   *scriptedIntentDetector
+	feeds     *scriptedFeeds
+	cloud     *scriptedCloud
+	storage   *command.CloudStorageCommand
+	metrics   *telemetry.Collector
+	durations *telemetry.Durations
+	reports   *memory.ProcessingReportRepository
+	premium   *command.ManagePremiumCommand
+	quota     *command.LinkQuota
+	from      telegramtest.User
+	lastSent  []telegramtest.Message
+}
+
+func newTestHarness(t *testing.T) *testHarness {
+	t.Helper()
+
+	api := telegramtest.NewServer(t)
+	bot, err := NewBot(Config{
+		BotToken:    telegramtest.Token,
+		APIEndpoint: api.Endpoint(),
+	})
+	if err != nil {
+		t.Fatalf("NewBot() error = %v", err)
+	}
+	// Retries of failed sends do not wait
+	bot.outbox.wait = func(context.Context, time.Duration) error { return nil }
+
+	fixtures := loadFixtures(t, carbonaraAliases)
+
+	recipes := memory.NewRecipeRepository()
+	users := memory.NewUserRepository()
+	flags := memory.NewFeatureFlagRepository()
+	mealPlans := memory.NewMealPlanRepository()
+	shares := memory.NewGuestShareRepository()
+	intents := newScriptedIntentDetector()
+	feeds := &scriptedFeeds{feeds: make(map[string]*feed.Feed)}
+	cloud := &scriptedCloud{files: make(map[string][]byte)}
+	storage := command.NewCloudStorageCommand(users, recipes, obsidian.NewExporter(), map[user.CloudProvider]ports.CloudStorage{
+		user.CloudDropbox: cloud,
+	})
+	metrics := telemetry.NewCollector()
+	durations := telemetry.NewDurations()
+	fixtureLLM := sandbox.NewLLM(fixtures)
+	reports := memory.NewProcessingReportRepository()
+	processLinks := command.NewProcessRecipeLinkCommand(sandbox.NewScraper(fixtures), fixtureLLM, recipe.NewService(), recipes, bot)
+	processLinks.SetProcessingReports(reports)
+	processLinks.SetUserRepository(users)
+	moderate := command.NewModerateContentCommand(
+		memory.NewModerationRepository(), recipes, moderation.NewLinkPolicy([]string{blockedDomain}), fixtureLLM,
+	)
+	aisles := command.NewIngredientClassifier(fixtureLLM)
+	pantry := command.NewManagePantryCommand(users, aisles)
+	barcodes := scriptedBarcodes{
+		"8076800195057": {
+			Barcode: "8076800195057", Name: "Spaghetti", Brand: "Barilla", Quantity: "500 g",
+			Nutrition: &nutrition.Facts{EnergyKcal: 359, Protein: 13, Carbohydrates: 70.2, Fat: 2},
+		},
+	}
+	nutritionRepo := memory.NewNutritionRepository()
+	mealPlan := command.NewManageMealPlanCommand(mealPlans, recipes)
+	catalog := command.NewProductCatalog(nutritionRepo, barcodes)
+	premium := command.NewManagePremiumCommand(users, command.CurrencyStars, command.PremiumPlans(100, 1000))
+	features := feature.NewService(nil, flags)
+	features.RequirePremium(premium, feature.FlagCookbookExport)
+	quota := command.NewLinkQuota(0, 0, premium)
+
+	handler := NewHandler(HandlerConfig{
+		Bot:                      bot,
+		ProcessRecipeLinkCommand: processLinks,
+		GetOrCreateUserCommand:   command.NewGetOrCreateUserCommand(users),
+		ListRecipesQuery:         query.NewListRecipesQuery(recipes),
+		MatchIngredientsCommand:  command.NewMatchIngredientsCommand(recipes, users, matching.DefaultSpecificity),
+		ManagePantryCommand:      pantry,
+		ExportRecipeCommand: command.NewExportRecipeCommand(recipes, obsidian.NewExporter(), nil, map[command.ExportFormat]ports.AppExporter{
+			command.ExportFormatCrouton: crouton.NewExporter(),
+			command.ExportFormatAnyList: anylist.NewExporter(),
+			command.ExportFormatWhisk:   whisk.NewExporter(),
+		}),
+		RecipeHistoryCommand:  command.NewRecipeHistoryCommand(recipes, memory.NewRecipeVersionRepository()),
+		ManageMealPlanCommand: mealPlan,
+		ShoppingListCommand: command.NewGenerateShoppingListCommand(
+			mealPlans, recipes, users, memory.NewShoppingListRepository(), aisles,
+		),
+		ScanBarcodeCommand:         command.NewScanBarcodeCommand(barcodes, catalog, pantry, nutritionRepo),
+		NutritionCommand:           command.NewEstimateNutritionCommand(nutritionRepo, catalog, users, recipes),
+		AchievementsCommand:        command.NewTrackAchievementsCommand(memory.NewStatsRepository(), recipes),
+		SimplifyRecipeCommand:      command.NewSimplifyRecipeCommand(recipes, fixtureLLM),
+		PlanMenuCommand:            command.NewPlanMenuCommand(recipes, fixtureLLM),
+		CookingTimelineCommand:     command.NewCookingTimelineCommand(recipes),
+		CookTogetherCommand:        command.NewCookTogetherCommand(recipes),
+		CategorizeRecipeCommand:    command.NewCategorizeRecipeCommand(recipes),
+		ConvertRecipeCommand:       command.NewConvertRecipeCommand(recipes, memory.NewRecipeVariantRepository(), fixtureLLM),
+		RemixRecipeCommand:         command.NewRemixRecipeCommand(recipes, fixtureLLM),
+		RecipeAudioCommand:         command.NewRecipeAudioCommand(scriptedSpeech{}),
+		GenerateCoverCommand:       command.NewGenerateCoverCommand(recipes, scriptedImages{}, memory.NewBlobStorage()),
+		PremiumCommand:             premium,
+		LinkQuota:                  quota,
+		ManageFreezerCommand:       command.NewManageFreezerCommand(memory.NewFreezerRepository(), recipes),
+		ManageRequestsCommand:      command.NewManageRequestsCommand(memory.NewRequestRepository(), recipes, mealPlan),
+		SavedFiltersCommand:        command.NewManageSavedFiltersCommand(users),
+		ShortcutsCommand:           command.NewManageShortcutsCommand(users),
+		LearnClarificationsCommand: command.NewLearnClarificationsCommand(users),
+		NotificationsCommand:       command.NewManageNotificationsCommand(users),
+		StaplesCommand:             command.NewManageStaplesCommand(users),
+		CheckDietCommand:           command.NewCheckDietCommand(users, recipes, diet.NewChecker()),
+		ExportFieldsCommand:        command.NewManageExportFieldsCommand(users),
+		RecreateDishCommand:        command.NewRecreateDishCommand(recipes, fixtureLLM),
+		ScanPantryPhotoCommand:     command.NewScanPantryPhotoCommand(fixtureLLM, pantry),
+		LinkAccountCommand:         command.NewLinkAccountCommand(users, memory.NewLinkCodeRepository()),
+		ShareCollectionCommand:     command.NewShareCollectionCommand(shares),
+		ReportErrorCommand:         command.NewReportErrorCommand(memory.NewErrorReportRepository()),
+		AutoExportCommand:          command.NewAutoExportCommand(users, recipes, obsidian.NewExporter(), nil),
+		CloudStorageCommand:        storage,
+		ActivityLogCommand:         command.NewActivityLogCommand(memory.NewActivityRepository()),
+		TranscriptCommand:          command.NewConversationTranscriptCommand(memory.NewConversationRepository()),
+		UsageAnalyticsCommand:      command.NewUsageAnalyticsCommand(memory.NewUsageRepository()),
+		ImportBookmarksCommand: command.NewImportBookmarksCommand(
+			command.NewProcessRecipeLinkCommand(sandbox.NewScraper(fixtures), fixtureLLM, recipe.NewService(), recipes, nil),
+			bookmark.NewSelector(nil), 0,
+		),
+		ClipRecipeCommand: command.NewClipRecipeCommand(
+			command.NewProcessRecipeLinkCommand(sandbox.NewScraper(fixtures), fixtureLLM, recipe.NewService(), recipes, nil),
+			users, clipSecret,
+		),
+		ForwardEmailCommand: command.NewForwardEmailCommand(
+			command.NewProcessRecipeLinkCommand(sandbox.NewScraper(fixtures), fixtureLLM, recipe.NewService(), recipes, nil),
+			users, bookmark.NewSelector(nil), "recipes.example.com", clipSecret,
+		),
+		ManageSubscriptionsCommand: command.NewManageSubscriptionsCommand(
+			memory.NewFeedRepository(), feeds,
+			command.NewProcessRecipeLinkCommand(sandbox.NewScraper(fixtures), fixtureLLM, recipe.NewService(), recipes, nil),
+			recipes,
+		),
+		ModerateContentCommand: moderate,
+		BrowseSharedQuery:      query.NewBrowseSharedQuery(shares, recipes, moderate),
+		WebAppURL:              "https://recipes.example.com/",
+		ClipAPIURL:             "https://recipes.example.com/api/v1/clip",
+		AdminChatID:            adminChatID,
+		IntentDetector:         intents,
+		UserRepo:               users,
+		LLM:                    fixtureLLM,
+		Features:               features,
+		Telemetry:              metrics,
+		Durations:              durations,
+		ProcessingReports:      reports,
+	})
+
+	// getMe from NewBot is not part of any conversation
+	api.Reset()
+
+	return &testHarness{
+		t:         t,
+		api:       api,
+		handler:   handler,
+		recipes:   recipes,
+		users:     users,
+		flags:     flags,
+		intents:   intents,
+		feeds:     feeds,
+		cloud:     cloud,
+		storage:   storage,
+		metrics:   metrics,
+		durations: durations,
+		reports:   reports,
+		premium:   premium,
+		quota:     quota,
+		from:      telegramtest.User{ID: 1001, Username: "alice", LanguageCode: "en"},
+	}
+}
+
+// send delivers a text message from the test user and returns what the bot sent back
+func (h *testHarness) send(text string) []telegramtest.Message {
+	h.t.Helper()
+
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), telegramtest.TextUpdate(h.from, text))
+	h.lastSent = h.api.Messages()
+
+	if len(h.lastSent) == 0 {
+		h.t.Fatalf("bot sent nothing in reply to %q", text)
+	}
+	return h.lastSent
+}
+
+// deliver hands the bot an update from the test user and returns what it sent back
+func (h *testHarness) deliver(update tgbotapi.Update) []telegramtest.Message {
+	h.t.Helper()
+
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), update)
+	h.lastSent = h.api.Messages()
+	return h.lastSent
+}
+
+// sendInGroup delivers a text message from a member of a group chat
+func (h *testHarness) sendInGroup(chatID int64, from telegramtest.User, text string) []telegramtest.Message {
+	h.t.Helper()
+
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), telegramtest.InGroup(telegramtest.TextUpdate(from, text), chatID))
+	h.lastSent = h.api.Messages()
+
+	if len(h.lastSent) == 0 {
+		h.t.Fatalf("bot sent nothing in reply to %q in the group", text)
+	}
+	return h.lastSent
+}
+
+// sendPhoto delivers a photo message whose file holds content
+func (h *testHarness) sendPhoto(content string) []telegramtest.Message {
+	h.t.Helper()
+	return h.sendPhotoWithCaption(content, "")
+}
+
+// sendPhotoWithCaption delivers a captioned photo message whose file holds content
+func (h *testHarness) sendPhotoWithCaption(content, caption string) []telegramtest.Message {
+	h.t.Helper()
+
+	fileID := "photo-" + content
+	h.api.AddFile(fileID, []byte(content))
+
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), telegramtest.PhotoUpdate(h.from, fileID, caption))
+	h.lastSent = h.api.Messages()
+
+	if len(h.lastSent) == 0 {
+		h.t.Fatalf("bot sent nothing in reply to photo %q", content)
+	}
+	return h.lastSent
+}
+
+// sendDocument delivers a file message named name whose file holds content
+func (h *testHarness) sendDocument(name, content string) []telegramtest.Message {
+	h.t.Helper()
+
+	fileID := "document-" + name
+	h.api.AddFile(fileID, []byte(content))
+
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), telegramtest.DocumentUpdate(h.from, fileID, name))
+	h.lastSent = h.api.Messages()
+
+	if len(h.lastSent) == 0 {
+		h.t.Fatalf("bot sent nothing in reply to file %q", name)
+	}
+	return h.lastSent
+}
+
+// waitForMessage waits for a message sent in the background that contains fragment
+func (h *testHarness) waitForMessage(fragment string) telegramtest.Message {
+	h.t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, msg := range h.api.Messages() {
+			if strings.Contains(msg.Text, fragment) {
+				return msg
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	h.t.Fatalf("no message containing %q was sent", fragment)
+	return telegramtest.Message{}
+}
+
+// press taps the inline keyboard button of the last replies whose label contains fragment
+func (h *testHarness) press(fragment string) []telegramtest.Message {
+	h.t.Helper()
+
+	data := h.buttonData(fragment)
+	h.api.Reset()
+	h.handler.HandleUpdate(context.Background(), telegramtest.CallbackUpdate(h.from, 1, data))
+	h.lastSent = h.api.Messages()
+	return h.lastSent
+}
+
+// buttonData returns the callback data of the first button whose label contains fragment
+func (h *testHarness) buttonData(fragment string) string {
+	h.t.Helper()
+
+	for _, msg := range h.lastSent {
+		if msg.ReplyMarkup == "" {
+			continue
+		}
+		var markup tgbotapi.InlineKeyboardMarkup
+		if err := json.Unmarshal([]byte(msg.ReplyMarkup), &markup); err != nil {
+			h.t.Fatalf("invalid reply markup %q: %v", msg.ReplyMarkup, err)
+		}
+		for _, row := range markup.InlineKeyboard {
+			for _, button := range row {
+				if strings.Contains(button.Text, fragment) && button.CallbackData != nil {
+					return *button.CallbackData
+				}
+			}
+		}
+	}
+
+	h.t.Fatalf("no button containing %q in the last replies", fragment)
+	return ""
+}
+
+// expectReply asserts that some reply to the last message contains all fragments
+func (h *testHarness) expectReply(fragments ...string) {
+	h.t.Helper()
+
+	for _, msg := range h.lastSent {
+		if containsAll(msg.Text, fragments) {
+			return
+		}
+	}
+
+	var texts []string
+	for _, msg := range h.lastSent {
+		texts = append(texts, msg.Text)
+	}
+	h.t.Fatalf("no reply contains %q; got:\n%s", fragments, strings.Join(texts, "\n---\n"))
+}
+
+// expectNoReply asserts that no reply to the last message contains the fragment
+func (h *testHarness) expectNoReply(fragment string) {
+	h.t.Helper()
+
+	for _, msg := range h.lastSent {
+		if strings.Contains(msg.Text, fragment) {
+			h.t.Fatalf("unexpected reply containing %q: %s", fragment, msg.Text)
+		}
+	}
+}
+
+func containsAll(text string, fragments []string) bool {
+	for _, f := range fragments {
+		if !strings.Contains(text, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadFixtures loads the sandbox's built-in fixtures, with each URL of aliases
+// also recorded as a copy of the fixture of the URL it maps to
+func loadFixtures(t *testing.T, aliases map[string]string) *sandbox.Fixtures {
+	t.Helper()
+
+	data, err := os.ReadFile("../sandbox/fixtures/default.json")
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("failed to parse fixtures: %v", err)
+	}
+
+	recorded := make(map[string]map[string]any, len(entries))
+	for _, entry := range entries {
+		recorded[entry["url"].(string)] = entry
+	}
+	for alias, url := range aliases {
+		entry, ok := recorded[url]
+		if !ok {
+			t.Fatalf("no fixture recorded for %s", url)
+		}
+		copied := make(map[string]any, len(entry))
+		for k, v := range entry {
+			copied[k] = v
+		}
+		copied["url"] = alias
+		entries = append(entries, copied)
+	}
+
+	data, err = json.Marshal(entries)
+	if err != nil {
+		t.Fatalf("failed to encode fixtures: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write fixtures: %v", err)
+	}
+
+	fixtures, err := sandbox.LoadFixtures(path)
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+	return fixtures
+}
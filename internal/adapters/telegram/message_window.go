@@ -0,0 +1,156 @@
+package telegram
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// windowKey identifies a user in a chat; members of a group are windowed separately
+type windowKey struct {
+	chatID int64
+	userID int64
+}
+
+// windowKeyOf returns the key of the sender of an update
+func windowKeyOf(update tgbotapi.Update) windowKey {
+	var key windowKey
+	if chat := update.FromChat(); chat != nil {
+		key.chatID = chat.ID
+	}
+	if sender := update.SentFrom(); sender != nil {
+		key.userID = sender.ID
+	}
+	return key
+}
+
+// pendingText holds the messages a user sent within the window
+type pendingText struct {
+	texts  []string
+	handle func(text string) // of the latest message
+	timer  *time.Timer
+}
+
+// messageWindow combines the text messages a user sends in quick succession, like
+// "I have chicken", "and rice", "and broccoli", into one before their intent is
+// detected. The messages are handled once the user pauses for the window, or right
+// before any other update of theirs so that update is not handled first. Messages
+// handled after a pause run alongside the user's later updates, so the replies to
+// them can arrive in either order.
+type messageWindow struct {
+	wait time.Duration
+
+	mu      sync.Mutex
+	pending map[windowKey]*pendingText
+	locks   map[windowKey]*windowLock // of users with an update being handled
+}
+
+// windowLock is held while an update of the user is handled
+type windowLock struct {
+	sync.Mutex
+	holders int // holding or waiting for the lock
+}
+
+// newMessageWindow creates a window that waits for a pause of wait
+func newMessageWindow(wait time.Duration) *messageWindow {
+	return &messageWindow{
+		wait:    wait,
+		pending: make(map[windowKey]*pendingText),
+		locks:   make(map[windowKey]*windowLock),
+	}
+}
+
+// lock keeps the user's messages from being taken by the timer while one of
+// their updates is handled, returning the unlock function. The lock is dropped
+// once no one holds or waits for it.
+func (w *messageWindow) lock(key windowKey) func() {
+	w.mu.Lock()
+	l, ok := w.locks[key]
+	if !ok {
+		l = &windowLock{}
+		w.locks[key] = l
+	}
+	l.holders++
+	w.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		l.holders--
+		if l.holders == 0 {
+			delete(w.locks, key)
+		}
+	}
+}
+
+// add queues a message and restarts the window. handle is called with the text of
+// the queued messages joined together; the handle of the latest message is used.
+// The caller holds the user's lock.
+func (w *messageWindow) add(key windowKey, text string, handle func(text string)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p, ok := w.pending[key]
+	if !ok {
+		p = &pendingText{}
+		w.pending[key] = p
+	}
+	p.texts = append(p.texts, text)
+	p.handle = handle
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.timer = time.AfterFunc(w.wait, func() {
+		// The messages are taken once an update being handled is done, but are
+		// handled without the lock: detecting their intent calls the LLM, which
+		// must not hold up the user's next update
+		unlock := w.lock(key)
+		p, ok := w.take(key)
+		unlock()
+
+		if ok {
+			p.handle(strings.Join(p.texts, " "))
+		}
+	})
+}
+
+// flush handles the user's queued messages right away, if there are any.
+// The caller holds the user's lock.
+func (w *messageWindow) flush(key windowKey) {
+	if p, ok := w.take(key); ok {
+		p.handle(strings.Join(p.texts, " "))
+	}
+}
+
+// take removes the user's queued messages and stops their timer
+func (w *messageWindow) take(key windowKey) (*pendingText, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p, ok := w.pending[key]
+	if ok {
+		delete(w.pending, key)
+		p.timer.Stop()
+	}
+	return p, ok
+}
+
+// joinsWindow reports whether a message joins the window: plain text that is
+// neither a command nor a link
+func joinsWindow(message *tgbotapi.Message) bool {
+	if message == nil || message.IsCommand() || len(message.Photo) > 0 || message.Location != nil || message.Document != nil {
+		return false
+	}
+	text := strings.TrimSpace(message.Text)
+	return text != "" && !isLink(text)
+}
+
+// isLink reports whether a message is a link to process rather than a sentence
+func isLink(text string) bool {
+	return strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://")
+}
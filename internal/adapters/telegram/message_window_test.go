@@ -0,0 +1,66 @@
+package telegram
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMessageWindow_DropsLocks(t *testing.T) {
+	w := newMessageWindow(10 * time.Millisecond)
+	key := windowKey{chatID: 1, userID: 1}
+
+	handled := make(chan string, 1)
+	unlock := w.lock(key)
+	w.add(key, "show my", func(text string) { handled <- text })
+	w.add(key, "recipes", func(text string) { handled <- text })
+	unlock()
+
+	select {
+	case text := <-handled:
+		if text != "show my recipes" {
+			t.Errorf("handled %q, want the messages joined", text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued messages were not handled after the window")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			w.lock(windowKey{chatID: 1, userID: userID})()
+		}(int64(i % 3))
+	}
+	wg.Wait()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.locks) != 0 || len(w.pending) != 0 {
+		t.Errorf("window keeps %d locks and %d pending users, want none", len(w.locks), len(w.pending))
+	}
+}
+
+func TestMessageWindow_HandlesWithoutLock(t *testing.T) {
+	w := newMessageWindow(10 * time.Millisecond)
+	key := windowKey{chatID: 1, userID: 1}
+
+	// The user's next update is handled while the queued messages still are
+	release := make(chan struct{})
+	locked := make(chan struct{})
+	unlock := w.lock(key)
+	w.add(key, "I have chicken", func(string) {
+		w.lock(key)()
+		close(locked)
+		<-release
+	})
+	unlock()
+
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("the user's lock is held while their queued messages are handled")
+	}
+	close(release)
+}
@@ -0,0 +1,217 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// outboxAttempts is how many times a message is sent before it is given up
+	outboxAttempts = 4
+	// outboxBackoff is the wait before the first retry; it doubles on each retry
+	outboxBackoff = time.Second
+	// maxRetryAfter caps how long a rate limited send waits for Telegram
+	maxRetryAfter = time.Minute
+	// maxDeadLetters is how many permanently failed sends are kept for /admin outbox
+	maxDeadLetters = 50
+	// deadLetterPreview is how much of a failed message's text is kept
+	deadLetterPreview = 80
+)
+
+// DeadLetter is a message that could not be delivered
+type DeadLetter struct {
+	At       time.Time
+	Method   string // e.g. sendMessage
+	ChatID   int64
+	Preview  string // the start of the text, or the file name
+	Attempts int
+	Error    string
+}
+
+// outboundJob is a message waiting in a chat's queue
+type outboundJob struct {
+	ctx     context.Context
+	method  string
+	chatID  int64
+	preview string
+	send    func() error
+	done    chan error
+}
+
+// outbox sends messages through a queue per chat, so they arrive in order, and
+// retries those Telegram failed to take for a transient reason: network errors,
+// server errors and rate limits. Messages it gives up on are logged as dead letters.
+type outbox struct {
+	attempts int
+	backoff  time.Duration
+	wait     func(ctx context.Context, d time.Duration) error
+
+	mu     sync.Mutex
+	queues map[int64][]*outboundJob // chat -> jobs waiting behind the one being sent
+	dead   []DeadLetter             // newest last
+}
+
+func newOutbox() *outbox {
+	return &outbox{
+		attempts: outboxAttempts,
+		backoff:  outboxBackoff,
+		wait:     sleep,
+		queues:   make(map[int64][]*outboundJob),
+	}
+}
+
+// deliver queues a send behind the chat's other messages and waits until it is
+// sent or given up. A send keeps its place when the caller stops waiting.
+func (o *outbox) deliver(ctx context.Context, chatID int64, method, preview string, send func() error) error {
+	job := &outboundJob{
+		// Retries outlive the caller, e.g. an update whose handling timed out
+		ctx:     context.WithoutCancel(ctx),
+		method:  method,
+		chatID:  chatID,
+		preview: preview,
+		send:    send,
+		done:    make(chan error, 1),
+	}
+
+	o.mu.Lock()
+	jobs, busy := o.queues[chatID]
+	o.queues[chatID] = append(jobs, job)
+	o.mu.Unlock()
+	if !busy {
+		go o.run(chatID)
+	}
+
+	select {
+	case err := <-job.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run sends a chat's queued messages one at a time until the queue is empty
+func (o *outbox) run(chatID int64) {
+	for {
+		o.mu.Lock()
+		jobs := o.queues[chatID]
+		if len(jobs) == 0 {
+			delete(o.queues, chatID)
+			o.mu.Unlock()
+			return
+		}
+		job := jobs[0]
+		o.queues[chatID] = jobs[1:]
+		o.mu.Unlock()
+
+		job.done <- o.attempt(job)
+	}
+}
+
+// attempt sends a message, retrying transient failures
+func (o *outbox) attempt(job *outboundJob) error {
+	for attempt := 1; ; attempt++ {
+		err := job.send()
+		if err == nil {
+			return nil
+		}
+
+		delay, retry := o.retryDelay(err, attempt)
+		if !retry || attempt >= o.attempts {
+			o.deadLetter(job, attempt, err)
+			return err
+		}
+		log.Printf("Retrying %s to chat %d in %s after attempt %d: %v", job.method, job.chatID, delay, attempt, err)
+		if werr := o.wait(job.ctx, delay); werr != nil {
+			o.deadLetter(job, attempt, err)
+			return err
+		}
+	}
+}
+
+// retryDelay returns how long to wait before sending again after a failure, or
+// false if sending again cannot help, e.g. the user blocked the bot
+func (o *outbox) retryDelay(err error, attempt int) (time.Duration, bool) {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		// The request never got an answer from Telegram
+		return o.backoff << (attempt - 1), true
+	}
+
+	switch {
+	case apiErr.Code == http.StatusTooManyRequests:
+		delay := time.Duration(apiErr.RetryAfter) * time.Second
+		if delay <= 0 {
+			delay = o.backoff << (attempt - 1)
+		}
+		return min(delay, maxRetryAfter), true
+	case apiErr.Code >= http.StatusInternalServerError:
+		return o.backoff << (attempt - 1), true
+	default:
+		return 0, false
+	}
+}
+
+// deadLetter logs a message that could not be delivered and keeps it for /admin outbox
+func (o *outbox) deadLetter(job *outboundJob, attempts int, err error) {
+	log.Printf("Dead letter: %s to chat %d failed after %d attempt(s): %v", job.method, job.chatID, attempts, err)
+
+	preview := []rune(job.preview)
+	if len(preview) > deadLetterPreview {
+		preview = append(preview[:deadLetterPreview-1], '…')
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.dead = append(o.dead, DeadLetter{
+		At:       time.Now(),
+		Method:   job.method,
+		ChatID:   job.chatID,
+		Preview:  string(preview),
+		Attempts: attempts,
+		Error:    err.Error(),
+	})
+	if len(o.dead) > maxDeadLetters {
+		o.dead = o.dead[len(o.dead)-maxDeadLetters:]
+	}
+}
+
+// deadLetters returns the messages that could not be delivered, newest first
+func (o *outbox) deadLetters() []DeadLetter {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	letters := make([]DeadLetter, len(o.dead))
+	for i, letter := range o.dead {
+		letters[len(o.dead)-1-i] = letter
+	}
+	return letters
+}
+
+// withErrorCode fills in the error code the Telegram library leaves out of the
+// errors of file uploads, so the outbox can tell which are worth retrying
+func withErrorCode(resp *tgbotapi.APIResponse, err error) error {
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == 0 && resp != nil {
+		apiErr.Code = resp.ErrorCode
+	}
+	return err
+}
+
+// sleep waits for d, or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,155 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/telegram/telegramtest"
+)
+
+// newOutboxBot returns a bot on a fake API whose retries record their waits
+// instead of sleeping
+func newOutboxBot(t *testing.T) (*Bot, *telegramtest.Server, *[]time.Duration) {
+	t.Helper()
+
+	api := telegramtest.NewServer(t)
+	bot, err := NewBot(Config{
+		BotToken:    telegramtest.Token,
+		APIEndpoint: api.Endpoint(),
+	})
+	if err != nil {
+		t.Fatalf("NewBot() error = %v", err)
+	}
+
+	var waits []time.Duration
+	bot.outbox.wait = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	api.Reset()
+	return bot, api, &waits
+}
+
+func TestOutbox_RetriesTransientFailures(t *testing.T) {
+	bot, api, waits := newOutboxBot(t)
+	ctx := context.Background()
+
+	// Rate limits wait as long as Telegram asks
+	api.RateLimitNext("sendMessage", 1, 7)
+	if err := bot.SendMessage(ctx, 42, "hello"); err != nil {
+		t.Fatalf("SendMessage() after a rate limit error = %v", err)
+	}
+	if got := len(api.CallsTo("sendMessage")); got != 2 {
+		t.Errorf("sendMessage calls = %d, want 2", got)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Errorf("waits = %v, want [7s]", *waits)
+	}
+
+	// Server errors back off, doubling each time
+	*waits = nil
+	api.UnavailableNext("sendDocument", 2)
+	if err := bot.SendDocument(ctx, 42, "recipe.md", []byte("# Soup"), ""); err != nil {
+		t.Fatalf("SendDocument() after server errors error = %v", err)
+	}
+	if len(*waits) != 2 || (*waits)[0] != outboxBackoff || (*waits)[1] != 2*outboxBackoff {
+		t.Errorf("waits = %v, want [%s %s]", *waits, outboxBackoff, 2*outboxBackoff)
+	}
+	// The file is uploaded whole on the last attempt
+	docs := api.CallsTo("sendDocument")
+	if got := string(docs[len(docs)-1].Files["document"].Data); got != "# Soup" {
+		t.Errorf("uploaded document = %q, want %q", got, "# Soup")
+	}
+
+	if letters := bot.DeadLetters(); len(letters) != 0 {
+		t.Errorf("DeadLetters() = %+v, want none", letters)
+	}
+}
+
+func TestOutbox_DeadLetters(t *testing.T) {
+	bot, api, waits := newOutboxBot(t)
+	ctx := context.Background()
+
+	// Telegram refusing a message is not retried
+	api.FailNext("sendMessage", 1)
+	if err := bot.SendMessage(ctx, 42, "*broken markdown"); err == nil {
+		t.Fatal("SendMessage() error = nil, want the Telegram error")
+	}
+	if got := len(api.CallsTo("sendMessage")); got != 1 {
+		t.Errorf("sendMessage calls = %d, want 1", got)
+	}
+	if len(*waits) != 0 {
+		t.Errorf("waits = %v, want none", *waits)
+	}
+
+	// A send that keeps failing is given up after the last attempt
+	api.UnavailableNext("sendMessage", outboxAttempts)
+	if err := bot.SendMessage(ctx, 43, "still down"); err == nil {
+		t.Fatal("SendMessage() error = nil, want the last error")
+	}
+	if got := len(api.CallsTo("sendMessage")); got != 1+outboxAttempts {
+		t.Errorf("sendMessage calls = %d, want %d", got, 1+outboxAttempts)
+	}
+
+	letters := bot.DeadLetters()
+	if len(letters) != 2 {
+		t.Fatalf("DeadLetters() = %+v, want 2", letters)
+	}
+	if letters[0].ChatID != 43 || letters[0].Attempts != outboxAttempts || letters[0].Preview != "still down" {
+		t.Errorf("newest dead letter = %+v, want the message to chat 43", letters[0])
+	}
+	if letters[1].Attempts != 1 || !strings.Contains(letters[1].Error, "forced failure") {
+		t.Errorf("oldest dead letter = %+v, want the refused message", letters[1])
+	}
+}
+
+func TestOutbox_KeepsChatOrder(t *testing.T) {
+	bot, api, _ := newOutboxBot(t)
+	ctx := context.Background()
+
+	// The first message fails, and its retry waits until the others are queued
+	release := make(chan struct{})
+	bot.outbox.wait = func(context.Context, time.Duration) error {
+		<-release
+		return nil
+	}
+	queued := func() int {
+		bot.outbox.mu.Lock()
+		defer bot.outbox.mu.Unlock()
+		return len(bot.outbox.queues[42])
+	}
+
+	api.UnavailableNext("sendMessage", 1)
+	done := make(chan struct{})
+	send := func(text string) {
+		_ = bot.SendMessage(ctx, 42, text)
+		done <- struct{}{}
+	}
+	go send("first")
+	for len(api.CallsTo("sendMessage")) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	go send("second")
+	for queued() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	go send("third")
+	for queued() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	for i := 0; i < 3; i++ {
+		<-done
+	}
+
+	var texts []string
+	for _, msg := range api.Messages() {
+		texts = append(texts, msg.Text)
+	}
+	// The failed attempt is recorded too
+	if got := strings.Join(texts, " "); got != "first first second third" {
+		t.Errorf("sent %q, want first first second third", got)
+	}
+}
@@ -0,0 +1,64 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+)
+
+// minETA is the least time left worth mentioning in a progress message
+const minETA = 5 * time.Second
+
+type etaContextKey struct{}
+
+// withETA returns a context whose progress messages say how long is left until done
+func withETA(ctx context.Context, done time.Time) context.Context {
+	return context.WithValue(ctx, etaContextKey{}, done)
+}
+
+// etaFrom returns when the operation of ctx is expected to be done
+func etaFrom(ctx context.Context) (time.Time, bool) {
+	done, ok := ctx.Value(etaContextKey{}).(time.Time)
+	return done, ok
+}
+
+// withTimeLeft adds the time left to a progress message of an ongoing phase, one ending
+// in "...", such as "🤖 Extracting recipe... (~25s left)". Other messages are unchanged.
+func withTimeLeft(ctx context.Context, message string) string {
+	done, ok := etaFrom(ctx)
+	if !ok || !strings.HasSuffix(message, "...") {
+		return message
+	}
+	left := time.Until(done)
+	if left < minETA {
+		return message
+	}
+	return fmt.Sprintf("%s (~%s left)", message, formatETA(left))
+}
+
+// formatETA rounds an estimate the way people say it: "40s", "2 min"
+func formatETA(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Round(5*time.Second).Seconds()))
+	}
+	return fmt.Sprintf("%d min", int(d.Round(time.Minute).Minutes()))
+}
+
+// platformName returns how a platform is written in messages
+func platformName(p recipe.Platform) string {
+	switch p {
+	case recipe.PlatformTikTok:
+		return "TikTok"
+	case recipe.PlatformYouTube:
+		return "YouTube"
+	case recipe.PlatformInstagram:
+		return "Instagram"
+	case recipe.PlatformAIGenerated:
+		return "AI suggestions"
+	default:
+		return "Website"
+	}
+}
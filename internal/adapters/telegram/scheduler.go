@@ -0,0 +1,272 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/application/command"
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/application/query"
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/user"
+)
+
+// Send times of scheduled notifications, in each user's local time
+const (
+	dailySuggestionHour = 17
+	expiryWarningHour   = 10
+	weeklyDigestHour    = 18
+	weeklyDigestDay     = time.Sunday
+	monthlyRecapHour    = 11 // on the first of the month
+)
+
+// schedulerInterval is how often the scheduler checks for due notifications.
+// It must be shorter than an hour so every send hour is seen.
+const schedulerInterval = 10 * time.Minute
+
+// SchedulerConfig contains all dependencies for the Scheduler
+type SchedulerConfig struct {
+	Bot                  *Bot
+	UserRepo             user.Repository
+	ListRecipesQuery     *query.ListRecipesQuery
+	ManageFreezerCommand *command.ManageFreezerCommand     // optional, disables expiry warnings when nil
+	ActivityLogCommand   *command.ActivityLogCommand       // optional, leaves exports out of the weekly digest when nil
+	NutritionCommand     *command.EstimateNutritionCommand // optional, ignores macro goals in dinner ideas when nil
+	AchievementsCommand  *command.TrackAchievementsCommand // optional, disables monthly recaps when nil
+}
+
+// Scheduler sends the notifications users opted into in /notifications.
+// Timer pings are not scheduled here; they are sent by /timeline reminders.
+type Scheduler struct {
+	bot                  *Bot
+	userRepo             user.Repository
+	listRecipesQuery     *query.ListRecipesQuery
+	manageFreezerCommand *command.ManageFreezerCommand
+	activityLogCommand   *command.ActivityLogCommand
+	nutritionCommand     *command.EstimateNutritionCommand
+	achievementsCommand  *command.TrackAchievementsCommand
+
+	mu       sync.Mutex
+	sent     map[string]string // user ID and notification -> day it was last sent
+	deferred map[string]string // user ID and notification -> day it came due in quiet hours
+}
+
+// NewScheduler creates a new notification scheduler
+func NewScheduler(cfg SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		bot:                  cfg.Bot,
+		userRepo:             cfg.UserRepo,
+		listRecipesQuery:     cfg.ListRecipesQuery,
+		manageFreezerCommand: cfg.ManageFreezerCommand,
+		activityLogCommand:   cfg.ActivityLogCommand,
+		nutritionCommand:     cfg.NutritionCommand,
+		achievementsCommand:  cfg.AchievementsCommand,
+		sent:                 make(map[string]string),
+		deferred:             make(map[string]string),
+	}
+}
+
+// Run sends due notifications until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.SendDue(ctx, now)
+		}
+	}
+}
+
+// SendDue sends every notification due at now to the users who want it, in each
+// user's time zone. A notification due in the user's quiet hours waits until they end.
+// Each notification is sent at most once a day per user.
+func (s *Scheduler) SendDue(ctx context.Context, now time.Time) {
+	users, err := s.userRepo.FindAll(ctx)
+	if err != nil {
+		log.Printf("Scheduler failed to load users: %v", err)
+		return
+	}
+
+	for _, usr := range users {
+		local := now.In(usr.Location())
+		quiet := usr.InQuietHours(now)
+		for _, n := range []user.Notification{user.NotificationDailySuggestion, user.NotificationExpiryWarning, user.NotificationWeeklyDigest, user.NotificationAchievements} {
+			if !usr.WantsNotification(n) {
+				continue
+			}
+			key := usr.ID().String() + "/" + string(n)
+			day, ok := s.dueDay(key, n, local, quiet)
+			if !ok || !s.claim(key, day) {
+				continue
+			}
+
+			text, err := s.compose(ctx, usr, n, local)
+			if err != nil {
+				log.Printf("Scheduler failed to prepare %s for user %s: %v", n, usr.ID(), err)
+				continue
+			}
+			if text == "" {
+				continue // Nothing worth sending today
+			}
+
+			if err := s.bot.SendMessage(withFormatOptions(ctx, FormatOptionsFor(usr)), usr.TelegramID(), text); err != nil {
+				log.Printf("Scheduler failed to send %s to user %s: %v", n, usr.ID(), err)
+			}
+		}
+	}
+}
+
+// dueDay returns the day a notification is sent for when it should go out at the
+// user's local time. One that comes due in quiet hours is deferred until they end.
+func (s *Scheduler) dueDay(key string, n user.Notification, local time.Time, quiet bool) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	day := local.Format("2006-01-02")
+	if isDue(n, local) {
+		if quiet {
+			if s.sent[key] != day {
+				s.deferred[key] = day
+			}
+			return "", false
+		}
+		delete(s.deferred, key)
+		return day, true
+	}
+
+	deferredDay, ok := s.deferred[key]
+	if !ok || quiet {
+		return "", false
+	}
+	delete(s.deferred, key)
+	return deferredDay, true
+}
+
+// claim marks a notification as sent on day and reports whether it was not sent yet
+func (s *Scheduler) claim(key, day string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sent[key] == day {
+		return false
+	}
+	s.sent[key] = day
+	return true
+}
+
+// compose builds the message for a notification, empty if there is nothing to say
+func (s *Scheduler) compose(ctx context.Context, usr *user.User, n user.Notification, now time.Time) (string, error) {
+	switch n {
+	case user.NotificationDailySuggestion:
+		recipes, err := s.listRecipesQuery.Execute(ctx, usr.ID())
+		if err != nil || len(recipes) == 0 {
+			return "", err
+		}
+		if text := s.composeDinnerForGoals(ctx, usr, recipes, now); text != "" {
+			return text, nil
+		}
+		// A different recipe every day, cycling through the collection
+		index := now.YearDay() % len(recipes)
+		return FormatDailySuggestion(recipes[index], index+1), nil
+
+	case user.NotificationExpiryWarning:
+		if s.manageFreezerCommand == nil {
+			return "", nil
+		}
+		batches, err := s.manageFreezerCommand.EatFirst(ctx, usr.ID(), now)
+		if err != nil || len(batches) == 0 {
+			return "", err
+		}
+		return FormatFreezerEatFirst(batches, DatesFor(usr, now)) + notificationFooter, nil
+
+	case user.NotificationAchievements:
+		if s.achievementsCommand == nil {
+			return "", nil
+		}
+		cooking, err := s.achievementsCommand.Stats(ctx, usr.ID())
+		if err != nil {
+			return "", err
+		}
+		// The recap of the month that ended yesterday
+		lastMonth := now.AddDate(0, 0, -1)
+		month, ok := cooking.Recap(lastMonth)
+		if !ok {
+			return "", nil
+		}
+		return FormatMonthlyRecap(lastMonth, month, cooking, now) + notificationFooter, nil
+
+	case user.NotificationWeeklyDigest:
+		recipes, err := s.listRecipesQuery.Execute(ctx, usr.ID())
+		if err != nil {
+			return "", err
+		}
+		weekStart := now.Add(-7 * 24 * time.Hour)
+		var saved []*dto.RecipeDTO
+		for _, rec := range recipes {
+			if !rec.CreatedAt.Before(weekStart) {
+				saved = append(saved, rec)
+			}
+		}
+		if len(saved) == 0 {
+			return "", nil
+		}
+
+		exports := 0
+		if s.activityLogCommand != nil {
+			entries, err := s.activityLogCommand.Since(ctx, usr.ID(), weekStart)
+			if err != nil {
+				return "", err
+			}
+			exports = activity.Count(entries)[activity.ActionRecipeExported]
+		}
+		return FormatWeeklyDigest(saved, len(recipes), exports), nil
+	}
+
+	return "", nil
+}
+
+// composeDinnerForGoals suggests the recipe that best fits what is left of the
+// user's macro goals today, or returns "" when they have none or no recipe can be
+// estimated
+func (s *Scheduler) composeDinnerForGoals(ctx context.Context, usr *user.User, recipes []*dto.RecipeDTO, now time.Time) string {
+	if s.nutritionCommand == nil {
+		return ""
+	}
+	suggestion, err := s.nutritionCommand.SuggestDinner(ctx, usr.ID(), now)
+	if err != nil {
+		log.Printf("Scheduler failed to fit dinner to macro goals for user %s: %v", usr.ID(), err)
+		return ""
+	}
+	if suggestion == nil {
+		return ""
+	}
+
+	id := suggestion.Estimate.Recipe.ID().String()
+	for i, rec := range recipes {
+		if rec.ID == id {
+			return FormatDinnerSuggestion(rec, i+1, suggestion)
+		}
+	}
+	return ""
+}
+
+// isDue reports whether a scheduled notification is sent at now
+func isDue(n user.Notification, now time.Time) bool {
+	switch n {
+	case user.NotificationDailySuggestion:
+		return now.Hour() == dailySuggestionHour
+	case user.NotificationExpiryWarning:
+		return now.Hour() == expiryWarningHour
+	case user.NotificationWeeklyDigest:
+		return now.Weekday() == weeklyDigestDay && now.Hour() == weeklyDigestHour
+	case user.NotificationAchievements:
+		return now.Day() == 1 && now.Hour() == monthlyRecapHour
+	}
+	return false
+}
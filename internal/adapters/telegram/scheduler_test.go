@@ -0,0 +1,187 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_SendDue(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+
+	scheduler := NewScheduler(SchedulerConfig{
+		Bot:                h.handler.bot,
+		UserRepo:           h.users,
+		ListRecipesQuery:   h.handler.listRecipesQuery,
+		ActivityLogCommand: h.handler.activityLogCommand,
+	})
+	ctx := context.Background()
+
+	// The coming Sunday, so the recipe above counts as saved this week
+	today := time.Now()
+	sunday := time.Date(today.Year(), today.Month(), today.Day()+(7-int(today.Weekday()))%7, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return sunday.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	sendDue := func(now time.Time) []string {
+		t.Helper()
+		h.api.Reset()
+		scheduler.SendDue(ctx, now)
+		var texts []string
+		for _, msg := range h.api.Messages() {
+			if msg.ChatID != h.from.ID {
+				t.Errorf("notification sent to chat %d, want %d", msg.ChatID, h.from.ID)
+			}
+			texts = append(texts, msg.Text)
+		}
+		return texts
+	}
+
+	if sent := sendDue(at(dailySuggestionHour, 0)); len(sent) != 0 {
+		t.Fatalf("sent %q before the user opted in", sent)
+	}
+
+	h.send("/export obsidian 1")
+	h.send("/notifications")
+	h.press("Daily suggestions")
+	h.press("Weekly digest")
+
+	if sent := sendDue(at(dailySuggestionHour, 0)); len(sent) != 1 || !containsAll(sent[0], []string{"Dinner idea", "Spaghetti Carbonara", "/recipe 1"}) {
+		t.Errorf("daily suggestion = %q", sent)
+	}
+	if sent := sendDue(at(dailySuggestionHour, 30)); len(sent) != 0 {
+		t.Errorf("daily suggestion sent twice: %q", sent)
+	}
+	if sent := sendDue(at(weeklyDigestHour, 0)); len(sent) != 1 || !containsAll(sent[0], []string{"Your week in recipes", "You saved 1 recipe this week", "Spaghetti Carbonara", "You also made 1 export"}) {
+		t.Errorf("weekly digest = %q", sent)
+	}
+	if sent := sendDue(at(9, 0)); len(sent) != 0 {
+		t.Errorf("sent %q outside the send hours", sent)
+	}
+}
+
+func TestScheduler_DinnerFitsMacroGoals(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send(curryURL)
+	h.sendPhoto("8076800195057")
+	h.send("/macros 1000 kcal 60 protein")
+	h.send("/notifications")
+	h.press("Daily suggestions")
+
+	scheduler := NewScheduler(SchedulerConfig{
+		Bot:              h.handler.bot,
+		UserRepo:         h.users,
+		ListRecipesQuery: h.handler.listRecipesQuery,
+		NutritionCommand: h.handler.nutritionCommand,
+	})
+	today := time.Now()
+	h.api.Reset()
+	scheduler.SendDue(context.Background(), time.Date(today.Year(), today.Month(), today.Day(), dailySuggestionHour, 0, 0, 0, time.Local))
+
+	// Only the carbonara has an ingredient with nutrition data, whatever day it is
+	sent := h.api.Messages()
+	if len(sent) != 1 || !containsAll(sent[0].Text, []string{"Dinner idea", "Spaghetti Carbonara", "One serving: 359 kcal · 13 g protein", "641 kcal left · 47 g protein left"}) {
+		t.Errorf("dinner suggestion = %+v", sent)
+	}
+}
+
+func TestScheduler_MonthlyRecap(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.sendPhoto("8076800195057")
+	h.send("/ate 1")
+	h.send("/achievements on")
+
+	scheduler := NewScheduler(SchedulerConfig{
+		Bot:                 h.handler.bot,
+		UserRepo:            h.users,
+		ListRecipesQuery:    h.handler.listRecipesQuery,
+		AchievementsCommand: h.handler.achievementsCommand,
+	})
+	sendDue := func(now time.Time) []string {
+		t.Helper()
+		h.api.Reset()
+		scheduler.SendDue(context.Background(), now)
+		var texts []string
+		for _, msg := range h.api.Messages() {
+			texts = append(texts, msg.Text)
+		}
+		return texts
+	}
+
+	// The first of next month, recapping this one
+	today := time.Now()
+	first := time.Date(today.Year(), today.Month()+1, 1, monthlyRecapHour, 0, 0, 0, time.Local)
+
+	if sent := sendDue(first.AddDate(0, 0, 1)); len(sent) != 0 {
+		t.Errorf("recap sent on the 2nd: %q", sent)
+	}
+	want := []string{"Your " + today.Month().String() + " in the kitchen", "You cooked 1 meal on 1 day", "New cuisines: Italian", "🍳 First dish"}
+	if sent := sendDue(first); len(sent) != 1 || !containsAll(sent[0], want) {
+		t.Errorf("monthly recap = %q", sent)
+	}
+
+	// Nothing was cooked next month
+	if sent := sendDue(first.AddDate(0, 1, 0)); len(sent) != 0 {
+		t.Errorf("recap of an empty month = %q", sent)
+	}
+}
+
+func TestScheduler_QuietHours(t *testing.T) {
+	h := newTestHarness(t)
+	h.send(carbonaraURL)
+	h.send("/notifications")
+	h.press("Daily suggestions")
+
+	h.send("/timezone Mars/Olympus")
+	h.expectReply("I don't know the time zone Mars/Olympus")
+
+	h.send("/timezone Asia/Tokyo")
+	h.expectReply("Time zone: Asia/Tokyo")
+	h.send("/quiet 16:30-18:00")
+	h.expectReply("16:30–18:00", "wait until they end")
+
+	scheduler := NewScheduler(SchedulerConfig{
+		Bot:              h.handler.bot,
+		UserRepo:         h.users,
+		ListRecipesQuery: h.handler.listRecipesQuery,
+	})
+	ctx := context.Background()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	// Hours of the user's day in Tokyo, sent to the scheduler in UTC
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.March, 4, hour, minute, 0, 0, tokyo).UTC()
+	}
+	sendDue := func(now time.Time) int {
+		t.Helper()
+		h.api.Reset()
+		scheduler.SendDue(ctx, now)
+		return len(h.api.Messages())
+	}
+
+	if sent := sendDue(at(dailySuggestionHour, 0)); sent != 0 {
+		t.Errorf("sent %d messages during quiet hours", sent)
+	}
+	if sent := sendDue(at(dailySuggestionHour, 50)); sent != 0 {
+		t.Errorf("sent %d messages before quiet hours ended", sent)
+	}
+	if sent := sendDue(at(18, 0)); sent != 1 {
+		t.Errorf("sent %d messages when quiet hours ended, want the deferred suggestion", sent)
+	}
+	if sent := sendDue(at(18, 10)); sent != 0 {
+		t.Errorf("deferred suggestion sent %d more times", sent)
+	}
+
+	h.send("/quiet off")
+	h.expectReply("Off: scheduled messages arrive at their usual time")
+	if sent := sendDue(at(dailySuggestionHour, 0).Add(24 * time.Hour)); sent != 1 {
+		t.Errorf("sent %d messages the next day without quiet hours, want 1", sent)
+	}
+}
@@ -0,0 +1,353 @@
+// Package telegramtest provides a fake Telegram Bot API server for tests.
+//
+// The server records every outgoing call (messages, documents, callback
+// answers, ...) so tests can drive Handler.HandleUpdate end-to-end and
+// assert on what the bot sent back.
+package telegramtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Token is the bot token accepted by the fake server
+const Token = "123456:TEST-TOKEN"
+
+// BotUsername is the username reported by getMe
+const BotUsername = "receipt_test_bot"
+
+// Call is a single request the bot made to the Telegram API
+type Call struct {
+	Method string
+	Params map[string]string
+	Files  map[string]File
+}
+
+// File is an uploaded file captured from a multipart request
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Message is an outgoing message captured from sendMessage, editMessageText, sendDocument,
+// sendVoice or sendPhoto
+type Message struct {
+	Method      string
+	ChatID      int64
+	Text        string // message text, or caption for documents
+	ParseMode   string
+	ReplyMarkup string // raw JSON of the reply markup, if any
+	Document    *File  // the document, the audio of a voice message or the photo
+}
+
+// Server is a fake Telegram Bot API backed by httptest
+type Server struct {
+	t      testing.TB
+	server *httptest.Server
+
+	mu        sync.Mutex
+	calls     []Call
+	nextMsgID int
+	failures  map[string][]failure // method -> forced failures, in order
+	files     map[string][]byte    // file ID -> content served by getFile
+}
+
+// NewServer starts a fake Telegram API server that is closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		t:         t,
+		nextMsgID: 1,
+		failures:  make(map[string][]failure),
+		files:     make(map[string][]byte),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// Endpoint returns the API endpoint format to pass to the bot (tgbotapi style)
+func (s *Server) Endpoint() string {
+	return s.server.URL + "/bot%s/%s"
+}
+
+// Calls returns every request received so far
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns the requests received for one API method
+func (s *Server) CallsTo(method string) []Call {
+	var calls []Call
+	for _, c := range s.Calls() {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Messages returns the outgoing messages, documents and edits in order
+func (s *Server) Messages() []Message {
+	var messages []Message
+	for _, c := range s.Calls() {
+		switch c.Method {
+		case "sendMessage", "editMessageText", "sendDocument", "sendVoice", "sendPhoto":
+		default:
+			continue
+		}
+
+		chatID, _ := strconv.ParseInt(c.Params["chat_id"], 10, 64)
+		msg := Message{
+			Method:      c.Method,
+			ChatID:      chatID,
+			Text:        c.Params["text"],
+			ParseMode:   c.Params["parse_mode"],
+			ReplyMarkup: c.Params["reply_markup"],
+		}
+		if c.Method == "sendDocument" || c.Method == "sendVoice" || c.Method == "sendPhoto" {
+			msg.Text = c.Params["caption"]
+			if doc, ok := c.Files["document"]; ok {
+				msg.Document = &doc
+			}
+			if voice, ok := c.Files["voice"]; ok {
+				msg.Document = &voice
+			}
+			if photo, ok := c.Files["photo"]; ok {
+				msg.Document = &photo
+			}
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// Reset forgets all recorded calls
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+// failure is a forced Bot API error
+type failure struct {
+	code        int
+	description string
+	retryAfter  int // seconds, for rate limits
+}
+
+// FailNext makes the next n calls to method return a Telegram error
+func (s *Server) FailNext(method string, n int) {
+	s.failNext(method, n, failure{code: http.StatusBadRequest, description: "Bad Request: forced failure"})
+}
+
+// RateLimitNext makes the next n calls to method return Too Many Requests,
+// asking to retry after retryAfter seconds
+func (s *Server) RateLimitNext(method string, n int, retryAfter int) {
+	s.failNext(method, n, failure{
+		code:        http.StatusTooManyRequests,
+		description: fmt.Sprintf("Too Many Requests: retry after %d", retryAfter),
+		retryAfter:  retryAfter,
+	})
+}
+
+// UnavailableNext makes the next n calls to method return a server error
+func (s *Server) UnavailableNext(method string, n int) {
+	s.failNext(method, n, failure{code: http.StatusBadGateway, description: "Bad Gateway"})
+}
+
+// RejectMarkdownNext makes the next n calls to method fail as if their Markdown
+// could not be parsed
+func (s *Server) RejectMarkdownNext(method string, n int) {
+	s.failNext(method, n, failure{
+		code:        http.StatusBadRequest,
+		description: "Bad Request: can't parse entities: Can't find end of the entity starting at byte offset 0",
+	})
+}
+
+// TooLargeNext makes the next n calls to method refuse their file as too large
+func (s *Server) TooLargeNext(method string, n int) {
+	s.failNext(method, n, failure{code: http.StatusRequestEntityTooLarge, description: "Request Entity Too Large"})
+}
+
+func (s *Server) failNext(method string, n int, f failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures[method] = append(s.failures[method], f)
+	}
+}
+
+// AddFile makes a file available for download, as if a user had sent it
+func (s *Server) AddFile(fileID string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[fileID] = data
+}
+
+// handle serves a single Bot API request
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	// File downloads use /file/bot<token>/<path>
+	if path, ok := strings.CutPrefix(r.URL.Path, "/file/bot"+Token+"/"); ok {
+		s.serveFile(w, path)
+		return
+	}
+
+	// Path is /bot<token>/<method>
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] != "bot"+Token {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	method := parts[1]
+
+	call := Call{
+		Method: method,
+		Params: make(map[string]string),
+		Files:  make(map[string]File),
+	}
+	if err := parseRequest(r, &call); err != nil {
+		s.t.Errorf("telegramtest: failed to parse %s request: %v", method, err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, call)
+	var fail *failure
+	if pending := s.failures[method]; len(pending) > 0 {
+		fail = &pending[0]
+		s.failures[method] = pending[1:]
+	}
+	msgID := s.nextMsgID
+	s.nextMsgID++
+	s.mu.Unlock()
+
+	if fail != nil {
+		writeFailure(w, *fail)
+		return
+	}
+
+	switch method {
+	case "getMe":
+		writeResult(w, map[string]interface{}{
+			"id":         1,
+			"is_bot":     true,
+			"first_name": "Receipt Test Bot",
+			"username":   BotUsername,
+		})
+	case "sendMessage", "editMessageText", "sendDocument", "sendPhoto", "sendAudio", "sendVoice", "sendInvoice":
+		chatID, _ := strconv.ParseInt(call.Params["chat_id"], 10, 64)
+		writeResult(w, map[string]interface{}{
+			"message_id": msgID,
+			"date":       time.Now().Unix(),
+			"chat":       map[string]interface{}{"id": chatID, "type": "private"},
+			"text":       call.Params["text"],
+		})
+	case "getFile":
+		fileID := call.Params["file_id"]
+		s.mu.Lock()
+		data, ok := s.files[fileID]
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Bad Request: invalid file_id")
+			return
+		}
+		writeResult(w, map[string]interface{}{
+			"file_id":   fileID,
+			"file_size": len(data),
+			"file_path": "photos/" + fileID,
+		})
+	default:
+		// sendChatAction, answerCallbackQuery, setMyCommands, ...
+		writeResult(w, true)
+	}
+}
+
+// serveFile serves the content of a file added with AddFile
+func (s *Server) serveFile(w http.ResponseWriter, path string) {
+	s.mu.Lock()
+	data, ok := s.files[strings.TrimPrefix(path, "photos/")]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "file not found", http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+// parseRequest reads url-encoded or multipart parameters into the call
+func parseRequest(r *http.Request, call *Call) error {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return err
+		}
+		for key, values := range r.MultipartForm.Value {
+			call.Params[key] = values[0]
+		}
+		for key, headers := range r.MultipartForm.File {
+			f, err := headers[0].Open()
+			if err != nil {
+				return err
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return err
+			}
+			call.Files[key] = File{Name: headers[0].Filename, Data: data}
+		}
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	for key, values := range r.PostForm {
+		call.Params[key] = values[0]
+	}
+	return nil
+}
+
+// writeResult writes a successful Bot API response
+func writeResult(w http.ResponseWriter, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":     true,
+		"result": result,
+	})
+}
+
+// writeError writes a failed Bot API response
+func writeError(w http.ResponseWriter, code int, description string) {
+	writeFailure(w, failure{code: code, description: description})
+}
+
+// writeFailure writes a failed Bot API response, with the retry delay of a rate limit
+func writeFailure(w http.ResponseWriter, f failure) {
+	resp := map[string]interface{}{
+		"ok":          false,
+		"error_code":  f.code,
+		"description": f.description,
+	}
+	if f.retryAfter > 0 {
+		resp["parameters"] = map[string]int{"retry_after": f.retryAfter}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(f.code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
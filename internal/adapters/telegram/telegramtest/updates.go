@@ -0,0 +1,166 @@
+package telegramtest
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// updateSeq generates unique update and message IDs
+var updateSeq int64
+
+// User describes the Telegram user that sends updates
+type User struct {
+	ID           int64
+	Username     string
+	LanguageCode string
+}
+
+// TextUpdate builds an update for a private text message.
+// Messages starting with "/" are marked as bot commands, like Telegram does.
+func TextUpdate(from User, text string) tgbotapi.Update {
+	id := int(atomic.AddInt64(&updateSeq, 1))
+
+	msg := &tgbotapi.Message{
+		MessageID: id,
+		From: &tgbotapi.User{
+			ID:           from.ID,
+			UserName:     from.Username,
+			FirstName:    from.Username,
+			LanguageCode: from.LanguageCode,
+		},
+		Chat: &tgbotapi.Chat{
+			ID:   from.ID,
+			Type: "private",
+		},
+		Date: int(time.Now().Unix()),
+		Text: text,
+	}
+
+	if strings.HasPrefix(text, "/") {
+		length := len(text)
+		if i := strings.IndexAny(text, " \n"); i != -1 {
+			length = i
+		}
+		msg.Entities = []tgbotapi.MessageEntity{{
+			Type:   "bot_command",
+			Offset: 0,
+			Length: length,
+		}}
+	}
+
+	return tgbotapi.Update{
+		UpdateID: id,
+		Message:  msg,
+	}
+}
+
+// PhotoUpdate builds an update for a private photo message.
+// The photo must be registered with Server.AddFile under fileID to be downloadable.
+func PhotoUpdate(from User, fileID, caption string) tgbotapi.Update {
+	update := TextUpdate(from, "")
+	update.Message.Caption = caption
+	update.Message.Photo = []tgbotapi.PhotoSize{
+		{FileID: fileID + "-thumb", FileUniqueID: fileID + "-thumb", Width: 90, Height: 90},
+		{FileID: fileID, FileUniqueID: fileID, Width: 1280, Height: 960},
+	}
+	return update
+}
+
+// DocumentUpdate builds an update for a private file message.
+// The file must be registered with Server.AddFile under fileID to be downloadable.
+func DocumentUpdate(from User, fileID, fileName string) tgbotapi.Update {
+	update := TextUpdate(from, "")
+	update.Message.Document = &tgbotapi.Document{
+		FileID:       fileID,
+		FileUniqueID: fileID,
+		FileName:     fileName,
+		MimeType:     "text/html",
+	}
+	return update
+}
+
+// LocationUpdate builds an update for a private message sharing a location
+func LocationUpdate(from User, latitude, longitude float64) tgbotapi.Update {
+	update := TextUpdate(from, "")
+	update.Message.Location = &tgbotapi.Location{Latitude: latitude, Longitude: longitude}
+	return update
+}
+
+// CallbackUpdate builds an update for a press on an inline keyboard button
+// attached to the bot message with the given ID
+func CallbackUpdate(from User, messageID int, data string) tgbotapi.Update {
+	id := int(atomic.AddInt64(&updateSeq, 1))
+
+	tgUser := &tgbotapi.User{
+		ID:           from.ID,
+		UserName:     from.Username,
+		FirstName:    from.Username,
+		LanguageCode: from.LanguageCode,
+	}
+
+	return tgbotapi.Update{
+		UpdateID: id,
+		CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:   strconv.Itoa(id),
+			From: tgUser,
+			Message: &tgbotapi.Message{
+				MessageID: messageID,
+				Chat: &tgbotapi.Chat{
+					ID:   from.ID,
+					Type: "private",
+				},
+				Date: int(time.Now().Unix()),
+			},
+			Data: data,
+		},
+	}
+}
+
+// PreCheckoutUpdate builds an update asking the bot to confirm a payment before it is charged
+func PreCheckoutUpdate(from User, currency string, amount int, payload string) tgbotapi.Update {
+	id := int(atomic.AddInt64(&updateSeq, 1))
+
+	return tgbotapi.Update{
+		UpdateID: id,
+		PreCheckoutQuery: &tgbotapi.PreCheckoutQuery{
+			ID: strconv.Itoa(id),
+			From: &tgbotapi.User{
+				ID:           from.ID,
+				UserName:     from.Username,
+				FirstName:    from.Username,
+				LanguageCode: from.LanguageCode,
+			},
+			Currency:       currency,
+			TotalAmount:    amount,
+			InvoicePayload: payload,
+		},
+	}
+}
+
+// PaymentUpdate builds an update for the service message of a successful payment
+func PaymentUpdate(from User, currency string, amount int, payload string) tgbotapi.Update {
+	update := TextUpdate(from, "")
+	update.Message.SuccessfulPayment = &tgbotapi.SuccessfulPayment{
+		Currency:                currency,
+		TotalAmount:             amount,
+		InvoicePayload:          payload,
+		TelegramPaymentChargeID: "charge-" + strconv.Itoa(update.UpdateID),
+	}
+	return update
+}
+
+// InGroup moves an update's message, or the message of its button press, to a group chat
+func InGroup(update tgbotapi.Update, chatID int64) tgbotapi.Update {
+	chat := &tgbotapi.Chat{ID: chatID, Type: "group", Title: "Kitchen"}
+	if update.Message != nil {
+		update.Message.Chat = chat
+	}
+	if update.CallbackQuery != nil && update.CallbackQuery.Message != nil {
+		update.CallbackQuery.Message.Chat = chat
+	}
+	return update
+}
@@ -0,0 +1,145 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"receipt-bot/internal/domain/recipe"
+)
+
+// Topic is the forum topic of a supergroup a message was sent in
+type Topic struct {
+	ID   int    // message_thread_id, 0 outside forum topics
+	Name string // empty until the bot has seen the topic created or renamed
+}
+
+// Update is an incoming Telegram update along with the forum topic it was sent in.
+// The Telegram library predates forum topics, so they are decoded by hand.
+type Update struct {
+	tgbotapi.Update
+	Topic Topic
+}
+
+type topicContextKey struct{}
+
+// withTopic returns a context whose messages are posted into a forum topic
+func withTopic(ctx context.Context, topic Topic) context.Context {
+	if topic.ID == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, topicContextKey{}, topic)
+}
+
+// topicFrom returns the forum topic messages sent with ctx are posted into,
+// the zero Topic outside forum topics
+func topicFrom(ctx context.Context) Topic {
+	topic, _ := ctx.Value(topicContextKey{}).(Topic)
+	return topic
+}
+
+// topicCategory returns the recipe category a forum topic is named after, such as
+// "🍰 Desserts", and false when its name is not a category
+func topicCategory(topic Topic) (recipe.Category, bool) {
+	name := strings.TrimFunc(topic.Name, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	category := recipe.ParseCategory(name)
+	if category == recipe.CategoryOther && !strings.EqualFold(name, string(recipe.CategoryOther)) {
+		return "", false
+	}
+	return category, true
+}
+
+// topicMessage holds the forum topic fields of a message that the Telegram library drops
+type topicMessage struct {
+	MessageID       int  `json:"message_id"`
+	MessageThreadID int  `json:"message_thread_id"`
+	IsTopicMessage  bool `json:"is_topic_message"`
+	Chat            struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	ForumTopicCreated *forumTopic   `json:"forum_topic_created"`
+	ForumTopicEdited  *forumTopic   `json:"forum_topic_edited"`
+	ReplyToMessage    *topicMessage `json:"reply_to_message"`
+}
+
+type forumTopic struct {
+	Name string `json:"name"`
+}
+
+type topicKey struct {
+	chatID   int64
+	threadID int
+}
+
+// topicNames remembers the names of forum topics from the service messages that
+// create or rename them. Messages in a topic reply to the one that created it,
+// so names are learned again after a restart.
+type topicNames struct {
+	mu    sync.Mutex
+	names map[topicKey]string
+}
+
+// decode decodes the result of getUpdates along with the forum topic of each update
+func (n *topicNames) decode(result json.RawMessage) ([]Update, error) {
+	var updates []tgbotapi.Update
+	if err := json.Unmarshal(result, &updates); err != nil {
+		return nil, fmt.Errorf("failed to decode updates: %w", err)
+	}
+
+	var fields []struct {
+		Message       *topicMessage `json:"message"`
+		CallbackQuery *struct {
+			Message *topicMessage `json:"message"`
+		} `json:"callback_query"`
+	}
+	if err := json.Unmarshal(result, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode update topics: %w", err)
+	}
+
+	decoded := make([]Update, len(updates))
+	for i, update := range updates {
+		decoded[i].Update = update
+
+		msg := fields[i].Message
+		if cq := fields[i].CallbackQuery; cq != nil {
+			msg = cq.Message
+		}
+		if msg != nil {
+			decoded[i].Topic = n.topic(msg)
+		}
+	}
+	return decoded, nil
+}
+
+// topic returns the forum topic of a message, learning topic names on the way
+func (n *topicNames) topic(msg *topicMessage) Topic {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.names == nil {
+		n.names = make(map[topicKey]string)
+	}
+
+	// A topic's ID is the ID of the message that created it
+	chatID := msg.Chat.ID
+	if msg.ForumTopicCreated != nil {
+		n.names[topicKey{chatID, msg.MessageID}] = msg.ForumTopicCreated.Name
+	}
+	if reply := msg.ReplyToMessage; reply != nil && reply.ForumTopicCreated != nil {
+		n.names[topicKey{chatID, reply.MessageID}] = reply.ForumTopicCreated.Name
+	}
+	if msg.ForumTopicEdited != nil && msg.ForumTopicEdited.Name != "" && msg.MessageThreadID != 0 {
+		n.names[topicKey{chatID, msg.MessageThreadID}] = msg.ForumTopicEdited.Name
+	}
+
+	if !msg.IsTopicMessage || msg.MessageThreadID == 0 {
+		return Topic{}
+	}
+	return Topic{ID: msg.MessageThreadID, Name: n.names[topicKey{chatID, msg.MessageThreadID}]}
+}
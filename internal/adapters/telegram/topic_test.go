@@ -0,0 +1,72 @@
+package telegram
+
+import (
+	"encoding/json"
+	"testing"
+
+	"receipt-bot/internal/domain/recipe"
+)
+
+func TestTopicNames_Decode(t *testing.T) {
+	var topics topicNames
+
+	// A topic is created, then a member posts in it and presses a button there
+	result := json.RawMessage(`[
+		{"update_id": 1, "message": {"message_id": 40, "message_thread_id": 40, "is_topic_message": true,
+			"chat": {"id": -100, "type": "supergroup"}, "forum_topic_created": {"name": "Desserts"}}},
+		{"update_id": 2, "message": {"message_id": 41, "message_thread_id": 40, "is_topic_message": true,
+			"chat": {"id": -100, "type": "supergroup"}, "text": "hi",
+			"reply_to_message": {"message_id": 40, "chat": {"id": -100}, "forum_topic_created": {"name": "Desserts"}}}},
+		{"update_id": 3, "callback_query": {"id": "7", "data": "x", "message": {"message_id": 42,
+			"message_thread_id": 40, "is_topic_message": true, "chat": {"id": -100, "type": "supergroup"}}}},
+		{"update_id": 4, "message": {"message_id": 43, "chat": {"id": -100, "type": "supergroup"}, "text": "general"}}
+	]`)
+
+	updates, err := topics.decode(result)
+	if err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	if len(updates) != 4 {
+		t.Fatalf("decode() = %d updates, want 4", len(updates))
+	}
+
+	want := Topic{ID: 40, Name: "Desserts"}
+	if updates[1].Topic != want || updates[1].Message.Text != "hi" {
+		t.Errorf("message in topic = %+v, text %q, want %+v", updates[1].Topic, updates[1].Message.Text, want)
+	}
+	if updates[2].Topic != want || updates[2].CallbackQuery == nil {
+		t.Errorf("button press in topic = %+v, want %+v", updates[2].Topic, want)
+	}
+	if updates[3].Topic != (Topic{}) {
+		t.Errorf("message outside topics = %+v, want no topic", updates[3].Topic)
+	}
+
+	// Renaming the topic renames it for later messages
+	renamed := json.RawMessage(`[{"update_id": 5, "message": {"message_id": 44, "message_thread_id": 40,
+		"is_topic_message": true, "chat": {"id": -100}, "forum_topic_edited": {"name": "Sweets"}}}]`)
+	if updates, _ := topics.decode(renamed); updates[0].Topic.Name != "Sweets" {
+		t.Errorf("renamed topic = %+v, want Sweets", updates[0].Topic)
+	}
+}
+
+func TestTopicCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		want recipe.Category
+		ok   bool
+	}{
+		{"Desserts", recipe.CategoryDesserts, true},
+		{"🍰 Desserts", recipe.CategoryDesserts, true},
+		{"Soups & Stews", recipe.CategorySoups, true},
+		{"Other", recipe.CategoryOther, true},
+		{"Weeknight ideas", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := topicCategory(Topic{ID: 1, Name: tt.name})
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("topicCategory(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -22,6 +22,37 @@ type Translations struct {
 	Instructions string
 	Source       string
 	By           string
+	Published    string
+
+	// Simplified view
+	SimpleInstructions string
+	SimplifyButton     string
+	OriginalButton     string
+
+	// Generated covers
+	CoverButton  string
+	CoverCaption string
+
+	// Diet checks, formatted with the diet
+	DietFits       string
+	DietViolations string
+	DietUncertain  string
+
+	// Appliance conversion
+	ConvertedInstructions string // formatted with the appliance name
+
+	// Links from steps to the source video
+	WatchStep string
+
+	// Remixes
+	RemixOf       string // formatted with the parent title and the goal
+	Substitutions string
+
+	// Warning on recipes the extraction is unsure of
+	CheckQuantities string
+
+	// When a recipe was saved, formatted with a relative time such as "3 days ago"
+	SavedAgo string
 
 	// Recipe list
 	YourRecipes       string
@@ -80,6 +111,17 @@ type Translations struct {
 	SpecifyRecipeNum   string
 	SpecifyItems       string
 
+	// Recipe processing errors
+	ScrapeFailed        string
+	NoContentFound      string
+	NoIngredientsFound  string
+	NoInstructionsFound string
+	ExtractionFailed    string
+	LLMTimeout          string
+	QuotaExceeded       string
+	ProcessingError     string
+	ReportHint          string
+
 	// Commands
 	UnknownCommand   string
 	UseHelpCmd       string
@@ -98,6 +140,11 @@ type Translations struct {
 	GreetingHint      string
 	FallbackMessage   string
 	NotSureWhatYouMean string
+	IntentFeedback     string
+	FeedbackThanks     string
+
+	// Clarifying questions skipped for the user's usual choice, formatted with the choice
+	UsualChoice string
 
 	// Language
 	LanguageSet      string
@@ -138,6 +185,19 @@ type Translations struct {
 	TagOnePot      string
 	TagKidFriendly string
 
+	// Difficulty levels
+	Difficulty       string
+	DifficultyEasy   string
+	DifficultyMedium string
+	DifficultyHard   string
+
+	// Flavor profiles
+	Flavor      string
+	FlavorSpicy string
+	FlavorSweet string
+	FlavorUmami string
+	FlavorTangy string
+
 	// Export
 	ExportCmd           string
 	ExportHelp          string
@@ -199,6 +259,10 @@ Let's get cooking!`,
 • Make sure the link contains a recipe
 • Videos with clear audio work best
 • Written recipes are also supported
+• Send a photo of a product barcode to add it to your pantry
+• Send a photo of a dish captioned "recreate this" for an AI-generated recipe
+• Send a photo of your fridge or a shelf captioned "pantry" to add what's on it
+• Send the bookmarks.html exported from your browser to import its recipe links
 
 *Commands:*
 /start - Welcome message
@@ -207,8 +271,51 @@ Let's get cooking!`,
 /recipes <category> - Filter by category
 /recipe <number> - View a specific recipe
 /categories - Show recipe categories
+/authors \[name] - Creators you save most, or everything from one
+/platforms \[name] - Where you save recipes from, or everything from one
+/flavor <profile> - Recipes that are spicy, sweet, umami-rich or tangy
 /match <ingredients> - Find recipes by ingredients
 /pantry - Manage your pantry items
+/staples - Ingredients you always have, left out of matches
+/filters - Your saved searches
+/shortcuts - Your text shortcuts
+/history <number> - See earlier versions of a recipe
+/revert <number> <version> - Restore an earlier version
+/reextract <number> - Extract a recipe again from its source
+/plan - Plan your meals for the week
+/shopping - Shopping list for this week's plan
+/nutrition <number> - Nutrition from your scanned products
+/macros - Daily macro goals and what is left today
+/ate <number> - Log a recipe you cooked and ate
+/achievements - Cooking streak, badges and monthly recaps
+/menu <occasion> - Plan a menu with a cooking timeline
+/timeline <numbers> \[at <time>] - Cook several recipes to be ready together
+/cook <number> - Cook a recipe together in a group, with /claim and /done for steps
+/convert <number> <appliance> - Adapt a recipe to a slow cooker or Instant Pot
+/remix <number> <goal> - A vegan, gluten-free or other version of a recipe
+/print <number> \[servings] - Printable copy, scaled if you like
+/audio <number> - Listen to a recipe while you cook
+/premium - More links an hour and a PDF cookbook
+/autoexport notion - Export recipes to Notion or Obsidian as you save them
+/app - Browse your collection in the web app
+/freezer - Frozen portions and what to eat first
+/requests - Dishes the household asked someone to cook
+/notifications - Choose what I message you about
+/quiet 22:00-07:00 - Hold scheduled messages at night, /timezone to set yours
+/locale en-US - How I write dates for you
+/plain on - Messages without emojis or formatting, for screen readers
+/transcripts off - Don't keep video transcripts and captions with recipes
+/diet vegan - Check recipes against a diet: vegetarian, vegan, kosher or halal
+/link - Share your collection with another Telegram account
+/share \[days] \[category] - Read-only guest link for friends
+/report \[what happened] - Send us the details of the last link that failed
+/activity - What you saved and exported recently
+/reset - Start our conversation over when I get confused
+/transcript - Download our recent conversation as a text file
+/clip - Token to save recipes with the browser extension (/clip reset for a new one)
+/email - Address to forward recipe newsletters to
+/subscribe <feed link> - Get the recipes of new posts of a recipe blog
+/unsubscribe <number> - Stop following a recipe blog
 /language - Change language
 
 *Having issues?*
@@ -231,6 +338,36 @@ Happy cooking!`,
 	Instructions: "Instructions",
 	Source:       "Source",
 	By:           "By",
+	Published:    "Published",
+
+	// Simplified view
+	SimpleInstructions: "Simple Steps",
+	SimplifyButton:     "🧒 Simplify",
+	OriginalButton:     "📖 Original steps",
+
+	// Generated covers
+	CoverButton:  "🎨 Generate a cover image",
+	CoverCaption: "🤖 AI-generated image, not a photo of the actual dish",
+
+	// Diet checks
+	DietFits:       "✅ Fits your %s diet",
+	DietViolations: "⛔ Breaks your %s diet:",
+	DietUncertain:  "❓ Check for your %s diet:",
+
+	// Appliance conversion
+	ConvertedInstructions: "%s Steps",
+
+	// Links from steps to the source video
+	WatchStep: "watch this step",
+
+	// Remixes
+	RemixOf:       "Remix of %s (%s)",
+	Substitutions: "Substitutions",
+
+	// Warning on recipes the extraction is unsure of
+	CheckQuantities: "low confidence — double-check quantities",
+
+	SavedAgo: "Saved %s",
 
 	// Recipe list
 	YourRecipes:      "Your Recipes",
@@ -289,6 +426,20 @@ Happy cooking!`,
 	SpecifyRecipeNum:  "Please specify a recipe number.",
 	SpecifyItems:      "Please specify items.",
 
+	// Recipe processing errors
+	ScrapeFailed: "Failed to download content from the URL. Please check:\n" +
+		"• The link is valid and accessible\n" +
+		"• The content is publicly available\n" +
+		"• The platform is supported",
+	NoContentFound:      "Could not extract any content from the URL.\nPlease make sure the link contains a recipe.",
+	NoIngredientsFound:  "Could not find any ingredients in the content.\nPlease make sure the link contains a recipe with ingredients.",
+	NoInstructionsFound: "Could not find any cooking instructions in the content.\nPlease make sure the link contains a recipe with steps.",
+	ExtractionFailed:    "Failed to extract recipe from the content.\nThe AI had trouble understanding this content. Please try a different recipe.",
+	LLMTimeout:          "The recipe assistant took too long to answer.\nPlease try again in a moment.",
+	QuotaExceeded:       "The recipe assistant is busy right now.\nPlease try again in a few minutes.",
+	ProcessingError:     "An error occurred while processing your recipe.\nPlease try again or use /help for assistance.",
+	ReportHint:          "Send /report to tell us about it.",
+
 	// Commands
 	UnknownCommand: "Unknown command.",
 	UseHelpCmd:     "Use /help to see available commands.",
@@ -307,6 +458,11 @@ Happy cooking!`,
 	GreetingHint:      "Send me a recipe link to save it, or try:",
 	FallbackMessage:   "I can help you with recipes! Try:",
 	NotSureWhatYouMean: "I'm not sure what you mean. Try:",
+	IntentFeedback:     "Did I get that right?",
+	FeedbackThanks:     "Thanks for the feedback!",
+
+	// Clarifying questions skipped for the user's usual choice
+	UsualChoice: "🔁 %s\n_(based on your usual choice — say 'options' to change)_",
 
 	// Language
 	LanguageSet:        "Language set to English.",
@@ -347,6 +503,19 @@ Happy cooking!`,
 	TagOnePot:      "one-pot",
 	TagKidFriendly: "kid-friendly",
 
+	// Difficulty levels
+	Difficulty:       "Difficulty",
+	DifficultyEasy:   "Easy",
+	DifficultyMedium: "Medium",
+	DifficultyHard:   "Hard",
+
+	// Flavor profiles
+	Flavor:      "Flavor",
+	FlavorSpicy: "spicy",
+	FlavorSweet: "sweet",
+	FlavorUmami: "umami-rich",
+	FlavorTangy: "tangy",
+
 	// Export
 	ExportCmd:           "/export - Export recipes",
 	ExportHelp:          "Export your recipes to other apps",
@@ -408,6 +577,10 @@ Vamos cozinhar!`,
 • Certifique-se de que o link contém uma receita
 • Vídeos com áudio claro funcionam melhor
 • Receitas escritas também são suportadas
+• Envie uma foto do código de barras de um produto para adicioná-lo à despensa
+• Envie uma foto de um prato com a legenda "recriar" para uma receita gerada por IA
+• Envie uma foto da geladeira ou de uma prateleira com a legenda "despensa" para adicionar o que há nela
+• Envie o bookmarks.html exportado do seu navegador para importar os links de receitas
 
 *Comandos:*
 /start - Mensagem de boas-vindas
@@ -416,8 +589,51 @@ Vamos cozinhar!`,
 /recipes <categoria> - Filtrar por categoria
 /recipe <número> - Ver uma receita específica
 /categories - Mostrar categorias
+/authors \[nome] - Criadores que você mais salva, ou tudo de um deles
+/platforms \[nome] - De onde você salva receitas, ou tudo de uma delas
+/flavor <perfil> - Receitas picantes, doces, ricas em umami ou azedinhas
 /match <ingredientes> - Encontrar receitas por ingredientes
 /pantry - Gerenciar sua despensa
+/staples - Ingredientes que você sempre tem, ignorados nas buscas
+/filters - Suas buscas salvas
+/shortcuts - Seus atalhos de texto
+/history <número> - Ver versões anteriores de uma receita
+/revert <número> <versão> - Restaurar uma versão anterior
+/reextract <número> - Extrair uma receita novamente da fonte
+/plan - Planejar as refeições da semana
+/shopping - Lista de compras do plano da semana
+/nutrition <número> - Nutrição a partir dos produtos escaneados
+/macros - Metas diárias de macros e o que resta hoje
+/ate <número> - Registrar uma receita que você preparou e comeu
+/achievements - Sequência de dias cozinhando, conquistas e resumos mensais
+/menu <ocasião> - Planejar um cardápio com cronograma de preparo
+/timeline <números> \[at <hora>] - Preparar várias receitas para ficarem prontas juntas
+/cook <número> - Cozinhar uma receita em grupo, com /claim e /done para os passos
+/convert <número> <aparelho> - Adaptar uma receita para panela elétrica ou Instant Pot
+/remix <número> <objetivo> - Uma versão vegana, sem glúten ou outra de uma receita
+/print <número> \[porções] - Cópia para imprimir, com ajuste de porções
+/audio <número> - Ouvir uma receita enquanto cozinha
+/premium - Mais links por hora e um livro de receitas em PDF
+/autoexport notion - Exporte receitas para o Notion ou Obsidian ao salvar
+/app - Navegar pela sua coleção no app web
+/freezer - Porções congeladas e o que comer primeiro
+/requests - Pratos que a casa pediu para alguém fazer
+/notifications - Escolha sobre o que eu te aviso
+/quiet 22:00-07:00 - Segure as mensagens agendadas à noite, /timezone para o seu fuso
+/locale pt-BR - Como eu escrevo as datas para você
+/plain on - Mensagens sem emojis nem formatação, para leitores de tela
+/transcripts off - Não guardar transcrições e legendas dos vídeos nas receitas
+/diet vegan - Verificar receitas para uma dieta: vegetarian, vegan, kosher ou halal
+/link - Compartilhar sua coleção com outra conta do Telegram
+/share \[dias] \[categoria] - Link de convidado somente leitura
+/report \[o que aconteceu] - Nos enviar os detalhes do último link que falhou
+/activity - O que você salvou e exportou recentemente
+/reset - Recomeçar a conversa quando eu me confundir
+/transcript - Baixar nossa conversa recente como arquivo de texto
+/clip - Token para salvar receitas com a extensão do navegador (/clip reset para um novo)
+/email - Endereço para encaminhar newsletters de receitas
+/subscribe <link do feed> - Receba as receitas dos novos posts de um blog
+/unsubscribe <número> - Pare de seguir um blog de receitas
 /language - Mudar idioma
 
 *Tendo problemas?*
@@ -440,6 +656,36 @@ Bom apetite!`,
 	Instructions: "Modo de Preparo",
 	Source:       "Fonte",
 	By:           "Por",
+	Published:    "Publicado em",
+
+	// Simplified view
+	SimpleInstructions: "Passos Simples",
+	SimplifyButton:     "🧒 Simplificar",
+	OriginalButton:     "📖 Passos originais",
+
+	// Generated covers
+	CoverButton:  "🎨 Gerar imagem de capa",
+	CoverCaption: "🤖 Imagem gerada por IA, não é uma foto do prato real",
+
+	// Diet checks
+	DietFits:       "✅ Compatível com sua dieta %s",
+	DietViolations: "⛔ Não é compatível com sua dieta %s:",
+	DietUncertain:  "❓ Verifique para sua dieta %s:",
+
+	// Appliance conversion
+	ConvertedInstructions: "Passos para %s",
+
+	// Links from steps to the source video
+	WatchStep: "ver este passo",
+
+	// Remixes
+	RemixOf:       "Remix de %s (%s)",
+	Substitutions: "Substituições",
+
+	// Warning on recipes the extraction is unsure of
+	CheckQuantities: "baixa confiança — confira as quantidades",
+
+	SavedAgo: "Salva %s",
 
 	// Recipe list
 	YourRecipes:      "Suas Receitas",
@@ -498,6 +744,20 @@ Bom apetite!`,
 	SpecifyRecipeNum:  "Por favor, especifique um número de receita.",
 	SpecifyItems:      "Por favor, especifique os itens.",
 
+	// Recipe processing errors
+	ScrapeFailed: "Falha ao baixar o conteúdo do link. Verifique se:\n" +
+		"• O link é válido e acessível\n" +
+		"• O conteúdo é público\n" +
+		"• A plataforma é suportada",
+	NoContentFound:      "Não foi possível extrair nenhum conteúdo do link.\nVerifique se o link contém uma receita.",
+	NoIngredientsFound:  "Não encontrei ingredientes no conteúdo.\nVerifique se o link contém uma receita com ingredientes.",
+	NoInstructionsFound: "Não encontrei o modo de preparo no conteúdo.\nVerifique se o link contém uma receita com os passos.",
+	ExtractionFailed:    "Falha ao extrair a receita do conteúdo.\nA IA teve dificuldade para entender este conteúdo. Tente outra receita.",
+	LLMTimeout:          "O assistente de receitas demorou demais para responder.\nTente novamente em instantes.",
+	QuotaExceeded:       "O assistente de receitas está ocupado agora.\nTente novamente em alguns minutos.",
+	ProcessingError:     "Ocorreu um erro ao processar sua receita.\nTente novamente ou use /help para ajuda.",
+	ReportHint:          "Envie /report para nos contar sobre isso.",
+
 	// Commands
 	UnknownCommand: "Comando desconhecido.",
 	UseHelpCmd:     "Use /help para ver os comandos disponíveis.",
@@ -516,6 +776,11 @@ Bom apetite!`,
 	GreetingHint:      "Me envie um link de receita para salvar, ou tente:",
 	FallbackMessage:   "Posso te ajudar com receitas! Tente:",
 	NotSureWhatYouMean: "Não tenho certeza do que você quer dizer. Tente:",
+	IntentFeedback:     "Entendi certo?",
+	FeedbackThanks:     "Obrigado pelo retorno!",
+
+	// Clarifying questions skipped for the user's usual choice
+	UsualChoice: "🔁 %s\n_(com base na sua escolha de sempre — diga 'opções' para mudar)_",
 
 	// Language
 	LanguageSet:        "Idioma definido para Português (BR).",
@@ -556,6 +821,19 @@ Bom apetite!`,
 	TagOnePot:      "panela única",
 	TagKidFriendly: "para crianças",
 
+	// Difficulty levels
+	Difficulty:       "Dificuldade",
+	DifficultyEasy:   "Fácil",
+	DifficultyMedium: "Média",
+	DifficultyHard:   "Difícil",
+
+	// Flavor profiles
+	Flavor:      "Sabor",
+	FlavorSpicy: "picante",
+	FlavorSweet: "doce",
+	FlavorUmami: "rico em umami",
+	FlavorTangy: "azedinho",
+
 	// Export
 	ExportCmd:           "/export - Exportar receitas",
 	ExportHelp:          "Exporte suas receitas para outros apps",
@@ -647,3 +925,35 @@ func TranslateDietaryTag(tag string, lang user.Language) string {
 		return tag
 	}
 }
+
+// TranslateFlavor translates a flavor profile to the given language
+func TranslateFlavor(flavor string, lang user.Language) string {
+	t := GetTranslations(lang)
+	switch flavor {
+	case "spicy":
+		return t.FlavorSpicy
+	case "sweet":
+		return t.FlavorSweet
+	case "umami-rich":
+		return t.FlavorUmami
+	case "tangy":
+		return t.FlavorTangy
+	default:
+		return flavor
+	}
+}
+
+// TranslateDifficulty translates a difficulty level to the given language
+func TranslateDifficulty(difficulty string, lang user.Language) string {
+	t := GetTranslations(lang)
+	switch difficulty {
+	case "easy":
+		return t.DifficultyEasy
+	case "medium":
+		return t.DifficultyMedium
+	case "hard":
+		return t.DifficultyHard
+	default:
+		return difficulty
+	}
+}
@@ -0,0 +1,73 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+)
+
+// workerQueueSize is how many updates may wait for each worker
+const workerQueueSize = 16
+
+// Serve handles updates until the channel is closed or ctx is canceled, on up to
+// workers goroutines at a time. The updates of one sender always go to the same
+// worker, so each user's messages are handled one at a time in the order they were
+// sent while other users are served alongside them. Serve returns once the updates
+// being handled are done; updates still queued at shutdown are dropped.
+func (h *Handler) Serve(ctx context.Context, updates <-chan Update, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	queues := make([]chan Update, workers)
+	for i := range queues {
+		queues[i] = make(chan Update, workerQueueSize)
+		wg.Add(1)
+		go func(queue <-chan Update) {
+			defer wg.Done()
+			for update := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				h.HandleUpdateInTopic(ctx, update.Update, update.Topic)
+			}
+		}(queues[i])
+	}
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+		wg.Wait()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			queue := queues[workerOf(update, workers)]
+			select {
+			case queue <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// workerOf picks the worker for an update by its sender, falling back to its chat
+func workerOf(update Update, workers int) int {
+	var key int64
+	if sender := update.SentFrom(); sender != nil {
+		key = sender.ID
+	} else if chat := update.FromChat(); chat != nil {
+		key = chat.ID
+	}
+	if key < 0 {
+		key = -key
+	}
+	return int(key % int64(workers))
+}
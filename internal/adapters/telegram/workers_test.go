@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"receipt-bot/internal/adapters/telegram/telegramtest"
+)
+
+func TestHandler_Serve(t *testing.T) {
+	h := newTestHarness(t)
+	h.intents.crash("make it crash")
+	bob := telegramtest.User{ID: 2002, Username: "bob", LanguageCode: "en"}
+
+	updates := make(chan Update, 4)
+	updates <- Update{Update: telegramtest.TextUpdate(h.from, "/start")}
+	updates <- Update{Update: telegramtest.TextUpdate(bob, "/start")}
+	updates <- Update{Update: telegramtest.TextUpdate(h.from, "make it crash")}
+	updates <- Update{Update: telegramtest.TextUpdate(bob, "/start")}
+	close(updates)
+
+	// Serve returns once the channel is closed and every update was handled
+	h.handler.Serve(context.Background(), updates, 4)
+
+	var alice, bobs []string
+	for _, msg := range h.api.Messages() {
+		switch msg.ChatID {
+		case h.from.ID:
+			alice = append(alice, msg.Text)
+		case bob.ID:
+			bobs = append(bobs, msg.Text)
+		}
+	}
+
+	// Each user's updates are handled in order, and a panic spares the others
+	if len(alice) != 2 || !strings.Contains(alice[0], "Welcome") || !strings.Contains(alice[1], "Something went wrong") {
+		t.Errorf("replies to alice = %q, want the welcome then the crash apology", alice)
+	}
+	if len(bobs) != 2 {
+		t.Errorf("bob got %d replies, want 2", len(bobs))
+	}
+}
+
+func TestWorkerOf(t *testing.T) {
+	alice := telegramtest.User{ID: 1001}
+
+	// A sender's updates always go to the same worker, even from a group
+	private := workerOf(Update{Update: telegramtest.TextUpdate(alice, "hi")}, 8)
+	group := workerOf(Update{Update: telegramtest.InGroup(telegramtest.TextUpdate(alice, "hi"), -100777)}, 8)
+	if private != group {
+		t.Errorf("workerOf() = %d in private and %d in a group, want the same worker", private, group)
+	}
+	if private < 0 || private >= 8 {
+		t.Errorf("workerOf() = %d, want a worker between 0 and 7", private)
+	}
+}
@@ -0,0 +1,100 @@
+package webapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxInitDataAge is how long the data Telegram hands the Mini App stays valid
+const MaxInitDataAge = 24 * time.Hour
+
+var (
+	errMissingInitData = errors.New("missing Telegram init data")
+	errBadSignature    = errors.New("init data signature does not match")
+	errExpiredInitData = errors.New("init data has expired")
+)
+
+// InitData is the verified part of the data Telegram passes to a Mini App
+type InitData struct {
+	UserID       int64
+	Username     string
+	LanguageCode string
+	AuthDate     time.Time
+}
+
+// ValidateInitData checks the signature Telegram put on a Mini App's init data
+// (https://core.telegram.org/bots/webapps#validating-data-received-via-the-mini-app)
+// and returns the user it was issued for
+func ValidateInitData(raw, botToken string, now time.Time) (*InitData, error) {
+	if raw == "" {
+		return nil, errMissingInitData
+	}
+
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid init data: %w", err)
+	}
+
+	hash := values.Get("hash")
+	if hash == "" {
+		return nil, errBadSignature
+	}
+
+	// Every field except the hash, sorted by key, one key=value per line
+	pairs := make([]string, 0, len(values))
+	for key := range values {
+		if key == "hash" {
+			continue
+		}
+		pairs = append(pairs, key+"="+values.Get(key))
+	}
+	sort.Strings(pairs)
+
+	expected, err := hex.DecodeString(hash)
+	if err != nil || !hmac.Equal(signature(botToken, pairs), expected) {
+		return nil, errBadSignature
+	}
+
+	authUnix, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid init data auth_date: %w", err)
+	}
+	authDate := time.Unix(authUnix, 0)
+	if now.Sub(authDate) > MaxInitDataAge {
+		return nil, errExpiredInitData
+	}
+
+	var tgUser struct {
+		ID           int64  `json:"id"`
+		Username     string `json:"username"`
+		LanguageCode string `json:"language_code"`
+	}
+	if err := json.Unmarshal([]byte(values.Get("user")), &tgUser); err != nil || tgUser.ID == 0 {
+		return nil, fmt.Errorf("init data has no user")
+	}
+
+	return &InitData{
+		UserID:       tgUser.ID,
+		Username:     tgUser.Username,
+		LanguageCode: tgUser.LanguageCode,
+		AuthDate:     authDate,
+	}, nil
+}
+
+// signature computes the init data hash for the sorted key=value pairs
+func signature(botToken string, pairs []string) []byte {
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+	mac := hmac.New(sha256.New, secret.Sum(nil))
+	mac.Write([]byte(strings.Join(pairs, "\n")))
+	return mac.Sum(nil)
+}
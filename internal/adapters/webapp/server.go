@@ -0,0 +1,357 @@
+// Package webapp serves the Telegram Mini App: a static front end showing a
+// user's recipe collection and the REST API it reads from. Requests are
+// authenticated with the init data Telegram signs for the Mini App.
+package webapp
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/application/query"
+	"receipt-bot/internal/domain/feature"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// coverLinkExpiry is how long the links to generated covers work, longer than
+// the Mini App is kept open
+const coverLinkExpiry = 12 * time.Hour
+
+//go:embed static
+var staticFiles embed.FS
+
+// Config holds Mini App server configuration
+type Config struct {
+	BotToken string
+	Blobs    ports.BlobStorage // optional, generated covers are not shown when nil
+}
+
+// Server serves the Mini App and its REST API
+type Server struct {
+	botToken         string
+	userRepo         user.Repository
+	listRecipesQuery *query.ListRecipesQuery
+	features         *feature.Service // optional, all defaults when nil
+	blobs            ports.BlobStorage
+	now              func() time.Time
+}
+
+// NewServer creates a new Mini App server
+func NewServer(config Config, userRepo user.Repository, listRecipesQuery *query.ListRecipesQuery, features *feature.Service) *Server {
+	return &Server{
+		botToken:         config.BotToken,
+		userRepo:         userRepo,
+		listRecipesQuery: listRecipesQuery,
+		features:         features,
+		blobs:            config.Blobs,
+		now:              time.Now,
+	}
+}
+
+// Handler returns the HTTP handler for the front end and the API
+func (s *Server) Handler() http.Handler {
+	static, _ := fs.Sub(staticFiles, "static")
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /api/recipes", s.authenticated(s.handleListRecipes))
+	mux.HandleFunc("GET /api/recipes/{id}", s.authenticated(s.handleGetRecipe))
+	mux.HandleFunc("GET /api/categories", s.authenticated(s.handleCategories))
+	return mux
+}
+
+// recipeSummary is a recipe card in the grid
+type recipeSummary struct {
+	ID           string   `json:"id"`
+	Number       int      `json:"number"` // the number the bot uses for the recipe
+	Title        string   `json:"title"`
+	Category     string   `json:"category"`
+	Cuisine      string   `json:"cuisine,omitempty"`
+	DietaryTags  []string `json:"dietaryTags"`
+	Difficulty   string   `json:"difficulty,omitempty"`
+	TotalMinutes int      `json:"totalMinutes,omitempty"`
+	ImageURL     string   `json:"imageUrl,omitempty"`
+	// ImageAIGenerated marks an image drawn by an image generator, not a picture of the dish
+	ImageAIGenerated bool `json:"imageAiGenerated,omitempty"`
+}
+
+// recipeDetail is a full recipe for the detail view
+type recipeDetail struct {
+	recipeSummary
+	Servings        *int          `json:"servings,omitempty"`
+	PrepTimeMinutes *int          `json:"prepTimeMinutes,omitempty"`
+	CookTimeMinutes *int          `json:"cookTimeMinutes,omitempty"`
+	Ingredients     []ingredient  `json:"ingredients"`
+	Instructions    []instruction `json:"instructions"`
+	Tags            []string      `json:"tags"`
+	SourceURL       string        `json:"sourceUrl"`
+	SourceAuthor    string        `json:"sourceAuthor,omitempty"`
+	SourceCredit    string        `json:"sourceCredit,omitempty"`    // attribution footer crediting the creator
+	CheckQuantities bool          `json:"checkQuantities,omitempty"` // the extraction is unsure of the quantities
+}
+
+// ingredient is an ingredient line in the detail view
+type ingredient struct {
+	Name     string `json:"name"`
+	Quantity string `json:"quantity,omitempty"`
+	Unit     string `json:"unit,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// instruction is a step in the detail view
+type instruction struct {
+	Text            string `json:"text"`
+	DurationMinutes *int   `json:"durationMinutes,omitempty"`
+}
+
+// categoryCount is a category filter with the number of recipes in it
+type categoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// authedHandler is an API handler for a verified user
+type authedHandler func(w http.ResponseWriter, r *http.Request, userID shared.ID)
+
+// authenticated verifies the Telegram init data sent as "Authorization: tma <init data>"
+// and resolves the bot user it belongs to
+func (s *Server) authenticated(next authedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initData, err := ValidateInitData(strings.TrimPrefix(r.Header.Get("Authorization"), "tma "), s.botToken, s.now())
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		usr, err := s.userRepo.FindByTelegramID(r.Context(), initData.UserID)
+		if errors.Is(err, shared.ErrUserNotFound) {
+			writeError(w, http.StatusForbidden, "send /start to the bot first")
+			return
+		}
+		if err != nil {
+			log.Printf("Mini App user lookup failed: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to load user")
+			return
+		}
+
+		if !s.isEnabled(r.Context(), usr.ID()) {
+			writeError(w, http.StatusForbidden, "the web app is not available")
+			return
+		}
+
+		next(w, r, usr.ID())
+	}
+}
+
+// handleListRecipes lists recipes, filtered by ?category=, ?diet=, ?lang=, ?difficulty= and ?q=
+func (s *Server) handleListRecipes(w http.ResponseWriter, r *http.Request, userID shared.ID) {
+	params := r.URL.Query()
+
+	var category *recipe.Category
+	if raw := params.Get("category"); raw != "" {
+		cat := recipe.ParseCategory(raw)
+		category = &cat
+	}
+	var difficulty *recipe.Difficulty
+	if raw := params.Get("difficulty"); raw != "" {
+		if d, ok := recipe.ParseDifficulty(raw); ok {
+			difficulty = &d
+		}
+	}
+	dietaryTags := recipe.ParseDietaryTags(params["diet"])
+	language := strings.ToLower(strings.TrimSpace(params.Get("lang")))
+
+	recipes, err := s.listRecipesQuery.ExecuteByFilters(r.Context(), userID, category, dietaryTags, language, difficulty)
+	if err != nil {
+		log.Printf("Mini App recipe list failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load recipes")
+		return
+	}
+
+	numbers, err := s.recipeNumbers(r.Context(), userID)
+	if err != nil {
+		log.Printf("Mini App recipe list failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load recipes")
+		return
+	}
+
+	search := strings.ToLower(strings.TrimSpace(params.Get("q")))
+	summaries := make([]recipeSummary, 0, len(recipes))
+	for _, rec := range recipes {
+		if search != "" && !matchesSearch(rec, search) {
+			continue
+		}
+		summary := summarize(rec, numbers[rec.ID])
+		s.addCover(r.Context(), &summary, rec)
+		summaries = append(summaries, summary)
+	}
+
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// handleGetRecipe returns one of the user's recipes
+func (s *Server) handleGetRecipe(w http.ResponseWriter, r *http.Request, userID shared.ID) {
+	recipes, err := s.listRecipesQuery.Execute(r.Context(), userID)
+	if err != nil {
+		log.Printf("Mini App recipe lookup failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load recipe")
+		return
+	}
+
+	id := r.PathValue("id")
+	for i, rec := range recipes {
+		if rec.ID != id {
+			continue
+		}
+		full, err := s.listRecipesQuery.ExecuteByID(r.Context(), userID, shared.ID(id))
+		if err != nil {
+			log.Printf("Mini App recipe lookup failed: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to load recipe")
+			return
+		}
+		d := detail(full, i+1)
+		s.addCover(r.Context(), &d.recipeSummary, full)
+		writeJSON(w, http.StatusOK, d)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "recipe not found")
+}
+
+// handleCategories returns the categories the user has recipes in
+func (s *Server) handleCategories(w http.ResponseWriter, r *http.Request, userID shared.ID) {
+	counts, err := s.listRecipesQuery.GetCategoryCounts(r.Context(), userID)
+	if err != nil {
+		log.Printf("Mini App category counts failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load categories")
+		return
+	}
+
+	categories := make([]categoryCount, 0, len(counts))
+	for _, cat := range recipe.AllCategories() {
+		if count := counts[string(cat)]; count > 0 {
+			categories = append(categories, categoryCount{Category: string(cat), Count: count})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, categories)
+}
+
+// recipeNumbers maps recipe IDs to the numbers the bot shows for them
+func (s *Server) recipeNumbers(ctx context.Context, userID shared.ID) (map[string]int, error) {
+	recipes, err := s.listRecipesQuery.Execute(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := make(map[string]int, len(recipes))
+	for i, rec := range recipes {
+		numbers[rec.ID] = i + 1
+	}
+	return numbers, nil
+}
+
+// addCover shows a recipe's stored cover on its card, for recipes whose source
+// has no image
+func (s *Server) addCover(ctx context.Context, summary *recipeSummary, rec *dto.RecipeDTO) {
+	if rec.CoverKey == "" || s.blobs == nil {
+		return
+	}
+	url, err := s.blobs.SignedURL(ctx, rec.CoverKey, coverLinkExpiry)
+	if err != nil {
+		log.Printf("Mini App cover link for recipe %s failed: %v", rec.ID, err)
+		return
+	}
+	summary.ImageURL = url
+	summary.ImageAIGenerated = rec.CoverGenerated
+}
+
+// isEnabled reports whether the web UI flag is enabled for the user
+func (s *Server) isEnabled(ctx context.Context, userID shared.ID) bool {
+	if s.features == nil {
+		return feature.DefaultValues()[feature.FlagWebUI]
+	}
+	return s.features.IsEnabled(ctx, feature.FlagWebUI, userID)
+}
+
+// summarize converts a recipe to a grid card
+func summarize(rec *dto.RecipeDTO, number int) recipeSummary {
+	summary := recipeSummary{
+		ID:          rec.ID,
+		Number:      number,
+		Title:       rec.Title,
+		Category:    rec.Category,
+		Cuisine:     rec.Cuisine,
+		DietaryTags: rec.DietaryTags,
+		Difficulty:  rec.Difficulty,
+		ImageURL:    recipe.SourceImageURL(rec.SourceURL),
+	}
+	if rec.PrepTimeMinutes != nil {
+		summary.TotalMinutes += *rec.PrepTimeMinutes
+	}
+	if rec.CookTimeMinutes != nil {
+		summary.TotalMinutes += *rec.CookTimeMinutes
+	}
+	return summary
+}
+
+// detail converts a recipe to the detail view
+func detail(rec *dto.RecipeDTO, number int) recipeDetail {
+	d := recipeDetail{
+		recipeSummary:   summarize(rec, number),
+		Servings:        rec.Servings,
+		PrepTimeMinutes: rec.PrepTimeMinutes,
+		CookTimeMinutes: rec.CookTimeMinutes,
+		Ingredients:     make([]ingredient, len(rec.Ingredients)),
+		Instructions:    make([]instruction, len(rec.Instructions)),
+		Tags:            rec.Tags,
+		SourceURL:       rec.SourceURL,
+		SourceAuthor:    rec.SourceAuthor,
+		SourceCredit:    rec.SourceCredit,
+		CheckQuantities: rec.CheckQuantities,
+	}
+	for i, ing := range rec.Ingredients {
+		d.Ingredients[i] = ingredient{Name: ing.Name, Quantity: ing.Quantity, Unit: ing.Unit, Notes: ing.Notes}
+	}
+	for i, inst := range rec.Instructions {
+		d.Instructions[i] = instruction{Text: inst.Text, DurationMinutes: inst.DurationMinutes}
+	}
+	return d
+}
+
+// matchesSearch reports whether the search text appears in the title or an ingredient
+func matchesSearch(rec *dto.RecipeDTO, search string) bool {
+	if strings.Contains(strings.ToLower(rec.Title), search) {
+		return true
+	}
+	for _, ing := range rec.Ingredients {
+		if strings.Contains(strings.ToLower(ing.Name), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Failed to write Mini App response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
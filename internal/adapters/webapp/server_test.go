@@ -0,0 +1,214 @@
+package webapp
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/application/query"
+	"receipt-bot/internal/domain/feature"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/user"
+)
+
+const testBotToken = "123456:test-token"
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// signInitData builds init data signed the way Telegram signs it
+func signInitData(botToken string, telegramID int64, authDate time.Time) string {
+	values := url.Values{}
+	values.Set("auth_date", strconv.FormatInt(authDate.Unix(), 10))
+	values.Set("user", `{"id":`+strconv.FormatInt(telegramID, 10)+`,"username":"alice"}`)
+
+	pairs := []string{"auth_date=" + values.Get("auth_date"), "user=" + values.Get("user")}
+	sort.Strings(pairs)
+	values.Set("hash", hex.EncodeToString(signature(botToken, pairs)))
+	return values.Encode()
+}
+
+type testServer struct {
+	handler http.Handler
+	flags   *memory.FeatureFlagRepository
+	recipes map[string]*recipe.Recipe
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	ctx := context.Background()
+
+	users := memory.NewUserRepository()
+	usr, _ := user.NewUser(1001, "alice")
+	if err := users.Save(ctx, usr); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	recipes := memory.NewRecipeRepository()
+	saved := make(map[string]*recipe.Recipe)
+	add := func(title, sourceURL, language string, category recipe.Category, tags []recipe.DietaryTag, ingredient string) {
+		ing, _ := recipe.NewIngredient(ingredient, "200", "g", "")
+		inst, _ := recipe.NewInstruction(1, "Cook it.", nil)
+		source, _ := recipe.NewSource(sourceURL, recipe.PlatformWeb, "Chef")
+		rec, err := recipe.NewRecipe(usr.ID(), title, []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+		if err != nil {
+			t.Fatalf("NewRecipe() error = %v", err)
+		}
+		rec.SetSourceLanguage(language)
+		rec.SetCategory(category)
+		rec.SetDietaryTags(tags)
+		rec.SetServings(2)
+		if err := recipes.Save(ctx, rec); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		saved[title] = rec
+	}
+	add("Carbonara", "https://www.youtube.com/watch?v=abc123XYZ", "it", recipe.CategoryPasta, nil, "spaghetti")
+	add("Chickpea Curry", "https://example.com/curry", "en", recipe.CategoryVegetarian, []recipe.DietaryTag{recipe.TagVegan}, "chickpeas")
+
+	// The curry's source has no image, so it has a generated cover
+	blobs := memory.NewBlobStorage()
+	curry := saved["Chickpea Curry"]
+	curry.SetCover(&recipe.Cover{Key: recipe.CoverKey(curry.ID()), AIGenerated: true, CreatedAt: testNow})
+	if err := blobs.Put(ctx, recipe.CoverKey(curry.ID()), "image/png", []byte("png")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := recipes.Update(ctx, curry); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	flags := memory.NewFeatureFlagRepository()
+	flags.SetOverride(feature.Override{Flag: feature.FlagWebUI, Enabled: true})
+
+	server := NewServer(Config{BotToken: testBotToken, Blobs: blobs}, users, query.NewListRecipesQuery(recipes), feature.NewService(nil, flags))
+	server.now = func() time.Time { return testNow }
+
+	return &testServer{handler: server.Handler(), flags: flags, recipes: saved}
+}
+
+// get calls the API as the given Telegram user and decodes the JSON response
+func (s *testServer) get(t *testing.T, path string, initData string, into any) int {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if initData != "" {
+		req.Header.Set("Authorization", "tma "+initData)
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+
+	if into != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), into); err != nil {
+			t.Fatalf("GET %s: invalid JSON: %v", path, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestServer_Auth(t *testing.T) {
+	s := newTestServer(t)
+	valid := signInitData(testBotToken, 1001, testNow.Add(-time.Hour))
+
+	tests := []struct {
+		name     string
+		initData string
+		want     int
+	}{
+		{"valid", valid, http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"signed for another bot", signInitData("999:other", 1001, testNow), http.StatusUnauthorized},
+		{"tampered user", strings.Replace(valid, "1001", "1002", 1), http.StatusUnauthorized},
+		{"expired", signInitData(testBotToken, 1001, testNow.Add(-MaxInitDataAge-time.Minute)), http.StatusUnauthorized},
+		{"unknown user", signInitData(testBotToken, 4242, testNow), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.get(t, "/api/recipes", tt.initData, nil); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_RequiresWebUIFlag(t *testing.T) {
+	s := newTestServer(t)
+	s.flags.SetOverride(feature.Override{Flag: feature.FlagWebUI, Enabled: false})
+
+	if got := s.get(t, "/api/recipes", signInitData(testBotToken, 1001, testNow), nil); got != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", got, http.StatusForbidden)
+	}
+}
+
+func TestServer_Recipes(t *testing.T) {
+	s := newTestServer(t)
+	initData := signInitData(testBotToken, 1001, testNow)
+
+	var all []recipeSummary
+	s.get(t, "/api/recipes", initData, &all)
+	if len(all) != 2 {
+		t.Fatalf("got %d recipes, want 2", len(all))
+	}
+	for _, r := range all {
+		if r.Number == 0 {
+			t.Errorf("recipe %q has no bot number", r.Title)
+		}
+		if r.Title == "Carbonara" && (r.ImageURL != "https://img.youtube.com/vi/abc123XYZ/hqdefault.jpg" || r.ImageAIGenerated) {
+			t.Errorf("Carbonara image = %q (AI-generated %v), want the YouTube thumbnail", r.ImageURL, r.ImageAIGenerated)
+		}
+		if r.Title == "Chickpea Curry" && (!strings.HasPrefix(r.ImageURL, "memory:///covers") || !r.ImageAIGenerated) {
+			t.Errorf("Chickpea Curry image = %q (AI-generated %v), want its generated cover", r.ImageURL, r.ImageAIGenerated)
+		}
+	}
+
+	filters := map[string]string{
+		"/api/recipes?diet=vegan":          "Chickpea Curry",
+		"/api/recipes?q=spaghetti":         "Carbonara",
+		"/api/recipes?category=Vegetarian": "Chickpea Curry",
+		"/api/recipes?lang=it":             "Carbonara",
+	}
+	for path, want := range filters {
+		var got []recipeSummary
+		s.get(t, path, initData, &got)
+		if len(got) != 1 || got[0].Title != want {
+			t.Errorf("GET %s = %+v, want only %q", path, got, want)
+		}
+	}
+
+	var detail recipeDetail
+	if code := s.get(t, "/api/recipes/"+s.recipes["Chickpea Curry"].ID().String(), initData, &detail); code != http.StatusOK {
+		t.Fatalf("detail status = %d", code)
+	}
+	if detail.Title != "Chickpea Curry" || len(detail.Ingredients) != 1 || detail.Ingredients[0].Name != "chickpeas" {
+		t.Errorf("unexpected detail: %+v", detail)
+	}
+
+	if code := s.get(t, "/api/recipes/missing", initData, nil); code != http.StatusNotFound {
+		t.Errorf("missing recipe status = %d, want 404", code)
+	}
+
+	var categories []categoryCount
+	s.get(t, "/api/categories", initData, &categories)
+	if len(categories) != 2 {
+		t.Errorf("categories = %+v, want 2", categories)
+	}
+}
+
+func TestServer_ServesFrontEnd(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "telegram-web-app.js") {
+		t.Errorf("GET / = %d, want the Mini App page", rec.Code)
+	}
+}
@@ -0,0 +1,103 @@
+// Package webhook posts data to HTTP endpoints configured by the bot operator.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"receipt-bot/internal/domain/telemetry"
+)
+
+// schemaVersion identifies the layout of the telemetry payload
+const schemaVersion = 1
+
+// TelemetrySender implements the ports.TelemetrySender interface by posting
+// each batch as JSON to the configured endpoint
+type TelemetrySender struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewTelemetrySender creates a new sender for the endpoint
+func NewTelemetrySender(endpoint string) *TelemetrySender {
+	return &TelemetrySender{
+		endpoint: endpoint,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// telemetryPayload is the JSON body posted to the endpoint
+type telemetryPayload struct {
+	Schema      int                          `json:"schema"`
+	From        time.Time                    `json:"from"`
+	To          time.Time                    `json:"to"`
+	Extractions map[string]extractionPayload `json:"extractions"` // by platform
+	Intents     map[string]intentPayload     `json:"intents"`     // by intent type
+}
+
+// extractionPayload holds the extraction counts of one platform
+type extractionPayload struct {
+	Attempts    int     `json:"attempts"`
+	Successes   int     `json:"successes"`
+	SuccessRate float64 `json:"successRate"`
+}
+
+// intentPayload holds the thumbs feedback of one intent type
+type intentPayload struct {
+	Helpful    int     `json:"helpful"`
+	NotHelpful int     `json:"notHelpful"`
+	Accuracy   float64 `json:"accuracy"`
+}
+
+// Send implements the TelemetrySender interface
+func (s *TelemetrySender) Send(ctx context.Context, report telemetry.Report) error {
+	payload := telemetryPayload{
+		Schema:      schemaVersion,
+		From:        report.From.UTC(),
+		To:          report.To.UTC(),
+		Extractions: make(map[string]extractionPayload, len(report.Extractions)),
+		Intents:     make(map[string]intentPayload, len(report.Intents)),
+	}
+	for platform, stats := range report.Extractions {
+		payload.Extractions[platform] = extractionPayload{
+			Attempts:    stats.Attempts,
+			Successes:   stats.Successes,
+			SuccessRate: stats.SuccessRate(),
+		}
+	}
+	for intent, stats := range report.Intents {
+		payload.Intents[intent] = intentPayload{
+			Helpful:    stats.Helpful,
+			NotHelpful: stats.NotHelpful,
+			Accuracy:   stats.Accuracy(),
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
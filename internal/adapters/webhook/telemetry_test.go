@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/telemetry"
+)
+
+func TestTelemetrySender_Send(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	from := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := telemetry.Report{
+		From:        from,
+		To:          from.Add(time.Hour),
+		Extractions: map[string]telemetry.ExtractionStats{"tiktok": {Attempts: 4, Successes: 3}},
+		Intents:     map[string]telemetry.FeedbackStats{"LIST_RECIPES": {Helpful: 1, NotHelpful: 1}},
+	}
+
+	sender := NewTelemetrySender(server.URL)
+	if err := sender.Send(context.Background(), report); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	tiktok := received["extractions"].(map[string]any)["tiktok"].(map[string]any)
+	if tiktok["attempts"] != 4.0 || tiktok["successRate"] != 0.75 {
+		t.Errorf("tiktok payload = %v", tiktok)
+	}
+	intent := received["intents"].(map[string]any)["LIST_RECIPES"].(map[string]any)
+	if intent["accuracy"] != 0.5 {
+		t.Errorf("LIST_RECIPES payload = %v", intent)
+	}
+	if received["schema"] != 1.0 {
+		t.Errorf("schema = %v, want 1", received["schema"])
+	}
+}
+
+func TestTelemetrySender_SendRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := NewTelemetrySender(server.URL)
+	if err := sender.Send(context.Background(), telemetry.Report{}); err == nil {
+		t.Error("Send() error = nil, want the rejected status reported")
+	}
+}
@@ -0,0 +1,75 @@
+package whisk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/ports"
+)
+
+// Exporter implements the AppExporter interface for Whisk / Samsung Food.
+// Samsung Food imports recipes by URL, so the export is the list of source
+// links for the app to re-import
+type Exporter struct{}
+
+// NewExporter creates a new Whisk / Samsung Food exporter
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// ExportRecipe exports the source link of a single recipe
+func (e *Exporter) ExportRecipe(rec *recipe.Recipe) (*ports.ExportResult, error) {
+	if rec.Source().URL() == "" {
+		return &ports.ExportResult{
+			Success: false,
+			Format:  "whisk",
+			Message: "This recipe has no source link Samsung Food can import.",
+		}, nil
+	}
+
+	return &ports.ExportResult{
+		Success:  true,
+		Format:   "whisk",
+		Filename: "samsung_food_links.txt",
+		Data:     []byte(formatLink(rec)),
+		URL:      rec.Source().OriginalURL(),
+		Message:  fmt.Sprintf("Link ready for Samsung Food: %s. Paste it into Save Recipe → From URL.", rec.Title()),
+	}, nil
+}
+
+// ExportRecipes exports the source links of multiple recipes as one text file
+func (e *Exporter) ExportRecipes(recipes []*recipe.Recipe, loc *time.Location) (*ports.ExportResult, error) {
+	var sb strings.Builder
+	count := 0
+	for _, rec := range recipes {
+		if rec.Source().URL() == "" {
+			continue
+		}
+		sb.WriteString(formatLink(rec))
+		count++
+	}
+
+	if count == 0 {
+		return &ports.ExportResult{
+			Success: false,
+			Format:  "whisk",
+			Message: "No recipes with source links to export",
+		}, nil
+	}
+
+	return &ports.ExportResult{
+		Success:  true,
+		Format:   "whisk",
+		Filename: fmt.Sprintf("samsung_food_links_%s.txt", time.Now().In(loc).Format("2006-01-02")),
+		Data:     []byte(sb.String()),
+		Message:  fmt.Sprintf("Exported %d recipe links for Samsung Food. Paste each into Save Recipe → From URL.", count),
+	}, nil
+}
+
+// formatLink writes a recipe as its title followed by its source link and the
+// credit of its creator
+func formatLink(rec *recipe.Recipe) string {
+	return fmt.Sprintf("%s\n%s\n%s\n\n", rec.Title(), rec.Source().OriginalURL(), rec.Source().Credit())
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ActivityLogCommand records the actions that affect a user's data and lists them
+// back for /activity, support and the weekly digest
+type ActivityLogCommand struct {
+	activityRepo activity.Repository
+	now          func() time.Time
+}
+
+// NewActivityLogCommand creates a new command
+func NewActivityLogCommand(activityRepo activity.Repository) *ActivityLogCommand {
+	return &ActivityLogCommand{
+		activityRepo: activityRepo,
+		now:          time.Now,
+	}
+}
+
+// Record appends an action to the user's audit trail
+func (c *ActivityLogCommand) Record(ctx context.Context, userID shared.ID, action activity.Action, detail string) error {
+	entry, err := activity.NewEntry(userID, action, detail, c.now())
+	if err != nil {
+		return err
+	}
+	if err := c.activityRepo.Append(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
+
+// Recent returns the user's audit trail within the retention period, newest first.
+// Entries past retention are deleted first.
+func (c *ActivityLogCommand) Recent(ctx context.Context, userID shared.ID) ([]*activity.Entry, error) {
+	cutoff := c.now().Add(-activity.Retention)
+	if _, err := c.activityRepo.DeleteBefore(ctx, userID, cutoff); err != nil {
+		return nil, fmt.Errorf("failed to apply activity retention: %w", err)
+	}
+	return c.activityRepo.FindByUser(ctx, userID, cutoff)
+}
+
+// Since returns the user's actions recorded since the given time, newest first
+func (c *ActivityLogCommand) Since(ctx context.Context, userID shared.ID, since time.Time) ([]*activity.Entry, error) {
+	return c.activityRepo.FindByUser(ctx, userID, since)
+}
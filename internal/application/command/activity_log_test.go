@@ -0,0 +1,70 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/activity"
+	"receipt-bot/internal/domain/shared"
+)
+
+type mockActivityRepository struct {
+	entries []*activity.Entry
+}
+
+func (m *mockActivityRepository) Append(ctx context.Context, e *activity.Entry) error {
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+func (m *mockActivityRepository) FindByUser(ctx context.Context, userID activity.UserID, since time.Time) ([]*activity.Entry, error) {
+	var found []*activity.Entry
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if e := m.entries[i]; e.UserID == userID && !e.CreatedAt.Before(since) {
+			found = append(found, e)
+		}
+	}
+	return found, nil
+}
+
+func (m *mockActivityRepository) DeleteBefore(ctx context.Context, userID activity.UserID, cutoff time.Time) (int, error) {
+	var kept []*activity.Entry
+	for _, e := range m.entries {
+		if e.UserID != userID || !e.CreatedAt.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(m.entries) - len(kept)
+	m.entries = kept
+	return removed, nil
+}
+
+func TestActivityLogCommand_Recent(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := &mockActivityRepository{}
+	cmd := NewActivityLogCommand(repo)
+
+	cmd.now = func() time.Time { return now.Add(-activity.Retention - time.Hour) }
+	if err := cmd.Record(ctx, userID, activity.ActionRecipeSaved, "Old soup"); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	cmd.now = func() time.Time { return now.Add(-time.Hour) }
+	_ = cmd.Record(ctx, userID, activity.ActionRecipeSaved, "Carbonara")
+	cmd.now = func() time.Time { return now }
+	_ = cmd.Record(ctx, userID, activity.ActionRecipeExported, "Carbonara to obsidian")
+
+	entries, err := cmd.Recent(ctx, userID)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != activity.ActionRecipeExported {
+		t.Errorf("Recent() = %+v, want the export then the save", entries)
+	}
+	if len(repo.entries) != 2 {
+		t.Errorf("repository keeps %d entries, want the expired one deleted", len(repo.entries))
+	}
+}
@@ -0,0 +1,133 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// AutoExportCommand keeps users' recipes exported to Notion or Obsidian as they save
+// them, so they do not have to run /export. Each user's auto-export holds a cursor;
+// the recipes saved after it are exported together and the cursor moves past them.
+type AutoExportCommand struct {
+	userRepo         user.Repository
+	recipeRepo       recipe.Repository
+	obsidianExporter ports.ObsidianExporter
+	notionExporter   ports.NotionExporter // optional, Notion auto-export is refused when nil
+}
+
+// NewAutoExportCommand creates a new command
+func NewAutoExportCommand(userRepo user.Repository, recipeRepo recipe.Repository, obsidianExporter ports.ObsidianExporter, notionExporter ports.NotionExporter) *AutoExportCommand {
+	return &AutoExportCommand{
+		userRepo:         userRepo,
+		recipeRepo:       recipeRepo,
+		obsidianExporter: obsidianExporter,
+		notionExporter:   notionExporter,
+	}
+}
+
+// Settings returns the user's auto-export, nil when it is off
+func (c *AutoExportCommand) Settings(ctx context.Context, userID shared.ID) (*user.AutoExport, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return usr.AutoExport(), nil
+}
+
+// Enable turns on auto-export of the recipes the user saves from now on.
+// Returns shared.ErrNotionNotConnected for Notion before the user connects it.
+func (c *AutoExportCommand) Enable(ctx context.Context, userID shared.ID, target user.AutoExportTarget, schedule user.AutoExportSchedule, now time.Time) (*user.AutoExport, error) {
+	autoExport, err := user.NewAutoExport(target, schedule, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if target == user.AutoExportNotion {
+		if c.notionExporter == nil {
+			return nil, shared.ErrNotionNotConnected
+		}
+		connected, err := c.notionExporter.IsConnected(ctx, userID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check notion connection: %w", err)
+		}
+		if !connected {
+			return nil, shared.ErrNotionNotConnected
+		}
+	}
+
+	if err := c.userRepo.UpdateAutoExport(ctx, user.UserID(userID), autoExport); err != nil {
+		return nil, fmt.Errorf("failed to save auto-export: %w", err)
+	}
+	return autoExport, nil
+}
+
+// Disable turns auto-export off
+func (c *AutoExportCommand) Disable(ctx context.Context, userID shared.ID) error {
+	if err := c.userRepo.UpdateAutoExport(ctx, user.UserID(userID), nil); err != nil {
+		return fmt.Errorf("failed to save auto-export: %w", err)
+	}
+	return nil
+}
+
+// Export exports the recipes the user saved since their last auto-export and moves
+// the cursor to now. Returns a nil result when nothing is waiting.
+// When the export fails the cursor stays put, so the recipes are tried again.
+func (c *AutoExportCommand) Export(ctx context.Context, usr *user.User, now time.Time) (*ports.ExportResult, error) {
+	autoExport := usr.AutoExport()
+	if autoExport == nil {
+		return nil, nil
+	}
+
+	recipes, err := c.recipeRepo.FindByUserID(ctx, recipe.UserID(usr.ID()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recipes: %w", err)
+	}
+	var pending []*recipe.Recipe
+	for _, rec := range recipes {
+		if rec.CreatedAt().After(autoExport.Since) && !rec.CreatedAt().After(now) {
+			pending = append(pending, rec)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	pending = withFields(pending, userExportFields(usr))
+
+	var result *ports.ExportResult
+	switch autoExport.Target {
+	case user.AutoExportNotion:
+		if c.notionExporter == nil {
+			return nil, shared.ErrNotionNotConnected
+		}
+		result, err = c.notionExporter.ExportRecipes(ctx, usr.ID().String(), pending)
+	case user.AutoExportObsidian:
+		if len(pending) == 1 {
+			result, err = c.obsidianExporter.ExportRecipe(pending[0], usr.Location())
+		} else {
+			result, err = c.obsidianExporter.ExportRecipes(pending, usr.Location())
+		}
+	default:
+		return nil, shared.ErrInvalidAutoExport
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to export to %s: %w", autoExport.Target, err)
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("failed to export to %s: %s", autoExport.Target, result.Message)
+	}
+
+	moved := *autoExport
+	moved.Since = now
+	if err := c.userRepo.UpdateAutoExport(ctx, usr.ID(), &moved); err != nil {
+		return nil, fmt.Errorf("failed to save auto-export: %w", err)
+	}
+	usr.SetAutoExport(&moved)
+	return result, nil
+}
@@ -56,6 +56,10 @@ func (c *BackfillCategoriesCommand) Execute(ctx context.Context, userID recipe.U
 		Details: make([]BackfillDetail, 0, len(recipes)),
 	}
 
+	// Changed recipes are saved together at the end, with their details
+	var changed []*recipe.Recipe
+	var changedDetails []int
+
 	for _, rec := range recipes {
 		result.TotalProcessed++
 		detail := BackfillDetail{
@@ -107,13 +111,8 @@ func (c *BackfillCategoriesCommand) Execute(ctx context.Context, userID recipe.U
 				rec.SetTags(extraction.Tags)
 			}
 
-			if err := c.recipeRepo.Update(ctx, rec); err != nil {
-				detail.Error = err
-				result.Errors++
-				log.Printf("Failed to update recipe %s: %v", rec.ID().String(), err)
-			} else {
-				result.Updated++
-			}
+			changed = append(changed, rec)
+			changedDetails = append(changedDetails, len(result.Details))
 		} else {
 			result.Skipped++
 		}
@@ -121,6 +120,16 @@ func (c *BackfillCategoriesCommand) Execute(ctx context.Context, userID recipe.U
 		result.Details = append(result.Details, detail)
 	}
 
+	saved, err := c.recipeRepo.SaveAll(ctx, changed)
+	result.Updated = saved
+	if err != nil {
+		log.Printf("Failed to update recipes: %v", err)
+		for _, i := range changedDetails[saved:] {
+			result.Details[i].Error = err
+			result.Errors++
+		}
+	}
+
 	return result, nil
 }
 
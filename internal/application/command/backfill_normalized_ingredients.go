@@ -21,10 +21,16 @@ func NewBackfillNormalizedIngredientsCommand(
 ) *BackfillNormalizedIngredientsCommand {
 	return &BackfillNormalizedIngredientsCommand{
 		recipeRepo: recipeRepo,
-		normalizer: matching.NewRuleBasedNormalizer(),
+		normalizer: matching.NewRuleBasedNormalizer(nil),
 	}
 }
 
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *BackfillNormalizedIngredientsCommand) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+}
+
 // BackfillNormalizedResult contains the result of a backfill operation
 type BackfillNormalizedResult struct {
 	TotalProcessed int
@@ -43,6 +49,9 @@ func (c *BackfillNormalizedIngredientsCommand) Execute(ctx context.Context, user
 
 	result := &BackfillNormalizedResult{}
 
+	// Updated recipes are saved together at the end
+	var changed []*recipe.Recipe
+
 	for _, rec := range recipes {
 		result.TotalProcessed++
 
@@ -63,14 +72,14 @@ func (c *BackfillNormalizedIngredientsCommand) Execute(ctx context.Context, user
 
 		// Update the recipe with normalized ingredients
 		rec.SetNormalizedIngredients(normalizedIngredients)
+		changed = append(changed, rec)
+	}
 
-		if err := c.recipeRepo.Update(ctx, rec); err != nil {
-			result.Errors++
-			log.Printf("Failed to update recipe %s: %v", rec.ID().String(), err)
-			continue
-		}
-
-		result.Updated++
+	saved, err := c.recipeRepo.SaveAll(ctx, changed)
+	result.Updated = saved
+	if err != nil {
+		result.Errors += len(changed) - saved
+		log.Printf("Failed to update recipes: %v", err)
 	}
 
 	return result, nil
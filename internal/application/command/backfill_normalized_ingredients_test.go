@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"receipt-bot/internal/domain/recipe"
+)
+
+func TestBackfillNormalizedIngredients_SavesChangedRecipes(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockRecipeRepository()
+	userID := recipe.UserID("user-1")
+
+	add := func(title string, normalized []string) *recipe.Recipe {
+		ing, _ := recipe.NewIngredient("Spaghetti", "200", "g", "")
+		inst, _ := recipe.NewInstruction(1, "Boil the pasta", nil)
+		source, _ := recipe.NewSource("https://example.com/"+title, recipe.PlatformWeb, "Chef")
+		rec, err := recipe.NewRecipe(userID, title, []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+		if err != nil {
+			t.Fatalf("NewRecipe() error = %v", err)
+		}
+		if normalized != nil {
+			rec.SetNormalizedIngredients(normalized)
+		}
+		if err := repo.Save(ctx, rec); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		return rec
+	}
+	pending := add("pasta", nil)
+	add("cached", []string{"spaghetti"})
+
+	result, err := NewBackfillNormalizedIngredientsCommand(repo).Execute(ctx, userID)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.TotalProcessed != 2 || result.Updated != 1 || result.Skipped != 1 || result.Errors != 0 {
+		t.Errorf("result = %+v, want 2 processed, 1 updated, 1 skipped", result)
+	}
+
+	saved, _ := repo.FindByID(ctx, pending.ID())
+	if got := saved.NormalizedIngredients(); len(got) != 1 || got[0] != "spaghetti" {
+		t.Errorf("normalized ingredients = %v, want [spaghetti]", got)
+	}
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// CategorizeRecipeCommand files one of a user's recipes under a category,
+// overriding the category found when it was extracted
+type CategorizeRecipeCommand struct {
+	recipeRepo recipe.Repository
+}
+
+// NewCategorizeRecipeCommand creates a new command
+func NewCategorizeRecipeCommand(recipeRepo recipe.Repository) *CategorizeRecipeCommand {
+	return &CategorizeRecipeCommand{
+		recipeRepo: recipeRepo,
+	}
+}
+
+// Execute sets the category of one of the user's recipes and returns the recipe
+func (c *CategorizeRecipeCommand) Execute(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID, category recipe.Category) (*recipe.Recipe, error) {
+	if !category.IsValid() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("recipe not found: %w", err)
+	}
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+	if rec.Category() == category {
+		return rec, nil
+	}
+
+	rec.SetCategory(category)
+	if err := c.recipeRepo.Update(ctx, rec); err != nil {
+		return nil, fmt.Errorf("failed to update recipe: %w", err)
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"receipt-bot/internal/domain/diet"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// CheckDietCommand reads and changes the diet a user keeps, and checks recipes'
+// ingredients against its rules
+type CheckDietCommand struct {
+	userRepo   user.Repository
+	recipeRepo recipe.Repository
+	checker    *diet.Checker
+}
+
+// NewCheckDietCommand creates a new command
+func NewCheckDietCommand(userRepo user.Repository, recipeRepo recipe.Repository, checker *diet.Checker) *CheckDietCommand {
+	return &CheckDietCommand{
+		userRepo:   userRepo,
+		recipeRepo: recipeRepo,
+		checker:    checker,
+	}
+}
+
+// Diets returns the diets recipes can be checked against
+func (c *CheckDietCommand) Diets() []diet.Diet {
+	return c.checker.Diets()
+}
+
+// SetDiet sets the diet the user's recipes are checked against; empty clears it.
+// Returns shared.ErrInvalidInput for a diet there are no rules for.
+func (c *CheckDietCommand) SetDiet(ctx context.Context, userID shared.ID, name string) (diet.Diet, error) {
+	d := diet.Diet(name)
+	if parsed, ok := diet.Parse(name); ok {
+		d = parsed
+	}
+	if name != "" && !c.checker.Has(d) {
+		return "", shared.ErrInvalidInput
+	}
+
+	if err := c.userRepo.UpdateDiet(ctx, user.UserID(userID), string(d)); err != nil {
+		return "", fmt.Errorf("failed to save diet: %w", err)
+	}
+	return d, nil
+}
+
+// Check checks ingredients against the diet the user keeps. Returns false when
+// they keep none.
+func (c *CheckDietCommand) Check(ctx context.Context, userID shared.ID, ingredients []string) (diet.Report, bool, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return diet.Report{}, false, fmt.Errorf("failed to get user: %w", err)
+	}
+	if usr.Diet() == "" {
+		return diet.Report{}, false, nil
+	}
+	return c.checker.Check(diet.Diet(usr.Diet()), ingredients), true, nil
+}
+
+// CheckRecipe checks a recipe of the user against the diet they keep. Returns
+// false when they keep none.
+func (c *CheckDietCommand) CheckRecipe(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID) (diet.Report, bool, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return diet.Report{}, false, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != recipe.UserID(userID) {
+		return diet.Report{}, false, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	names := make([]string, len(rec.Ingredients()))
+	for i, ing := range rec.Ingredients() {
+		names[i] = ing.Name()
+	}
+	return c.Check(ctx, userID, names)
+}
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/shopping"
+	"receipt-bot/internal/ports"
+)
+
+// IngredientClassifier maps ingredients to grocery aisles.
+// Keyword rules are tried first; the rest is sent to the fallback classifier
+// (usually the LLM) in a single batch, and every answer is cached.
+type IngredientClassifier struct {
+	fallback   ports.AisleClassifier
+	normalizer matching.IngredientNormalizer
+
+	mu    sync.RWMutex
+	cache map[string]shopping.Aisle // normalized name -> aisle
+}
+
+// NewIngredientClassifier creates a new ingredient classifier.
+// The fallback is optional; without it unknown ingredients are classified as "other".
+func NewIngredientClassifier(fallback ports.AisleClassifier) *IngredientClassifier {
+	return &IngredientClassifier{
+		fallback:   fallback,
+		normalizer: matching.NewRuleBasedNormalizer(nil),
+		cache:      make(map[string]shopping.Aisle),
+	}
+}
+
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *IngredientClassifier) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+}
+
+// ClassifyAisles implements the AisleClassifier interface.
+// Every ingredient gets an aisle; fallback failures are logged, not returned.
+func (c *IngredientClassifier) ClassifyAisles(ctx context.Context, ingredients []string) (map[string]shopping.Aisle, error) {
+	aisles := make(map[string]shopping.Aisle, len(ingredients))
+	keys := make(map[string]string, len(ingredients)) // ingredient -> normalized name
+
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, ing := range ingredients {
+		key := c.normalizer.Normalize(ing)
+		if key == "" {
+			aisles[ing] = shopping.AisleOther
+			continue
+		}
+		keys[ing] = key
+
+		if _, ok := c.lookup(key); ok || seen[key] {
+			continue
+		}
+		if aisle, ok := shopping.ClassifyByRules(key); ok {
+			c.store(key, aisle)
+			continue
+		}
+		unknown = append(unknown, key)
+		seen[key] = true
+	}
+
+	if len(unknown) > 0 && c.fallback != nil {
+		c.classifyWithFallback(ctx, unknown)
+	}
+
+	for ing, key := range keys {
+		aisle, ok := c.lookup(key)
+		if !ok {
+			aisle = shopping.AisleOther
+		}
+		aisles[ing] = aisle
+	}
+
+	return aisles, nil
+}
+
+// classifyWithFallback asks the fallback classifier about names the rules do not know.
+// Names it leaves out are cached as "other" so they are not asked about again.
+func (c *IngredientClassifier) classifyWithFallback(ctx context.Context, names []string) {
+	result, err := c.fallback.ClassifyAisles(ctx, names)
+	if err != nil {
+		log.Printf("Aisle fallback classification failed for %d ingredients: %v", len(names), err)
+		return
+	}
+
+	for _, name := range names {
+		aisle, ok := result[name]
+		if !ok {
+			aisle = shopping.AisleOther
+		}
+		c.store(name, aisle)
+	}
+}
+
+func (c *IngredientClassifier) lookup(key string) (shopping.Aisle, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	aisle, ok := c.cache[key]
+	return aisle, ok
+}
+
+func (c *IngredientClassifier) store(key string, aisle shopping.Aisle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache[key] = aisle
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"receipt-bot/internal/domain/shopping"
+)
+
+type mockAisleClassifier struct {
+	aisles map[string]shopping.Aisle
+	err    error
+	asked  [][]string
+}
+
+func (m *mockAisleClassifier) ClassifyAisles(ctx context.Context, ingredients []string) (map[string]shopping.Aisle, error) {
+	m.asked = append(m.asked, ingredients)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.aisles, nil
+}
+
+func TestIngredientClassifier_RulesThenCachedFallback(t *testing.T) {
+	fallback := &mockAisleClassifier{aisles: map[string]shopping.Aisle{"guanciale": shopping.AisleMeat}}
+	c := NewIngredientClassifier(fallback)
+	ctx := context.Background()
+
+	aisles, err := c.ClassifyAisles(ctx, []string{"garlic", "guanciale", "Guanciale, diced", "gochujang"})
+	if err != nil {
+		t.Fatalf("ClassifyAisles() error = %v", err)
+	}
+
+	want := map[string]shopping.Aisle{
+		"garlic":           shopping.AisleProduce,
+		"guanciale":        shopping.AisleMeat,
+		"Guanciale, diced": shopping.AisleMeat,
+		"gochujang":        shopping.AisleOther,
+	}
+	for name, aisle := range want {
+		if aisles[name] != aisle {
+			t.Errorf("aisle of %q = %q, want %q", name, aisles[name], aisle)
+		}
+	}
+
+	if len(fallback.asked) != 1 || len(fallback.asked[0]) != 2 {
+		t.Fatalf("fallback asked %v, want one batch with guanciale and gochujang", fallback.asked)
+	}
+
+	// Answers, including ones the fallback left out, are cached
+	if _, err := c.ClassifyAisles(ctx, []string{"guanciale", "gochujang"}); err != nil {
+		t.Fatalf("ClassifyAisles() error = %v", err)
+	}
+	if len(fallback.asked) != 1 {
+		t.Errorf("fallback asked again: %v", fallback.asked)
+	}
+}
+
+func TestIngredientClassifier_FallbackFailure(t *testing.T) {
+	fallback := &mockAisleClassifier{err: errors.New("quota exceeded")}
+	c := NewIngredientClassifier(fallback)
+	ctx := context.Background()
+
+	aisles, err := c.ClassifyAisles(ctx, []string{"milk", "guanciale"})
+	if err != nil {
+		t.Fatalf("ClassifyAisles() error = %v", err)
+	}
+	if aisles["milk"] != shopping.AisleDairy || aisles["guanciale"] != shopping.AisleOther {
+		t.Errorf("ClassifyAisles() = %v", aisles)
+	}
+
+	// Failures are not cached, so the next call retries
+	_, _ = c.ClassifyAisles(ctx, []string{"guanciale"})
+	if len(fallback.asked) != 2 {
+		t.Errorf("fallback asked %d times, want 2", len(fallback.asked))
+	}
+}
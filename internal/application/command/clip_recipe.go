@@ -0,0 +1,104 @@
+package command
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// ClipRecipeCommand saves recipes clipped from the browser extension.
+// The extension authenticates with an API token the bot gives the user: their ID
+// and token version signed with a server secret, so only the version is stored.
+// Resetting the token raises the version, which revokes the tokens given out before.
+type ClipRecipeCommand struct {
+	processRecipeLink *ProcessRecipeLinkCommand
+	userRepo          user.Repository
+	secret            []byte
+}
+
+// NewClipRecipeCommand creates a new command signing tokens with secret
+func NewClipRecipeCommand(processRecipeLink *ProcessRecipeLinkCommand, userRepo user.Repository, secret string) *ClipRecipeCommand {
+	return &ClipRecipeCommand{
+		processRecipeLink: processRecipeLink,
+		userRepo:          userRepo,
+		secret:            []byte(secret),
+	}
+}
+
+// Token returns the API token of a user, as "<user ID>.<signature>"
+func (c *ClipRecipeCommand) Token(usr *user.User) string {
+	return usr.ID().String() + "." + c.sign(usr.ID(), usr.ClipTokenVersion())
+}
+
+// ResetToken revokes the user's API token and returns the new one
+func (c *ClipRecipeCommand) ResetToken(ctx context.Context, usr *user.User) (string, error) {
+	version := usr.ClipTokenVersion() + 1
+	if err := c.userRepo.UpdateClipTokenVersion(ctx, usr.ID(), version); err != nil {
+		return "", fmt.Errorf("failed to reset token: %w", err)
+	}
+
+	reset := usr.Clone()
+	reset.SetClipTokenVersion(version)
+	return c.Token(reset), nil
+}
+
+// Authenticate returns the user an API token was issued to.
+// It returns shared.ErrInvalidAPIToken for tokens that were not signed by this
+// server, or were revoked by resetting them.
+func (c *ClipRecipeCommand) Authenticate(ctx context.Context, token string) (*user.User, error) {
+	id, sig, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || id == "" {
+		return nil, shared.ErrInvalidAPIToken
+	}
+
+	usr, err := c.userRepo.FindByID(ctx, shared.ID(id))
+	if errors.Is(err, shared.ErrUserNotFound) {
+		return nil, shared.ErrInvalidAPIToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(c.sign(usr.ID(), usr.ClipTokenVersion()))) {
+		return nil, shared.ErrInvalidAPIToken
+	}
+	return usr, nil
+}
+
+// Execute runs the recipe pipeline on a clipped link. The page is the content the
+// extension read from the user's browser; the link is scraped when it is nil.
+func (c *ClipRecipeCommand) Execute(ctx context.Context, userID user.UserID, link string, page *ports.ScrapeResult) (*recipe.Recipe, error) {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, shared.ErrInvalidURL
+	}
+
+	if page == nil {
+		return c.processRecipeLink.Execute(ctx, parsed.String(), userID, 0)
+	}
+	return c.processRecipeLink.ExecutePage(ctx, parsed.String(), page, userID)
+}
+
+// sign computes the token signature of a user ID at a token version. Version 0
+// signs the ID alone, so tokens given out before versions existed stay valid.
+func (c *ClipRecipeCommand) sign(userID user.UserID, version int) string {
+	message := "clip:" + userID.String()
+	if version > 0 {
+		message += ":" + strconv.Itoa(version)
+	}
+
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(message))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,188 @@
+package command
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// connectLinkTTL is how long a /connect link can be followed after it is sent
+const connectLinkTTL = 15 * time.Minute
+
+// pendingConnection is a /connect link waiting for its OAuth callback
+type pendingConnection struct {
+	userID    user.UserID
+	provider  user.CloudProvider
+	expiresAt time.Time
+}
+
+// CloudStorageCommand connects users' Dropbox or Google Drive and writes their
+// Markdown exports into a folder there instead of sending files in chat.
+// Connect links wait for their OAuth callback in memory.
+type CloudStorageCommand struct {
+	userRepo         user.Repository
+	recipeRepo       recipe.Repository
+	obsidianExporter ports.ObsidianExporter
+	providers        map[user.CloudProvider]ports.CloudStorage
+
+	mu      sync.Mutex
+	pending map[string]pendingConnection // by OAuth state
+}
+
+// NewCloudStorageCommand creates a new command for the providers that are configured
+func NewCloudStorageCommand(userRepo user.Repository, recipeRepo recipe.Repository, obsidianExporter ports.ObsidianExporter, providers map[user.CloudProvider]ports.CloudStorage) *CloudStorageCommand {
+	return &CloudStorageCommand{
+		userRepo:         userRepo,
+		recipeRepo:       recipeRepo,
+		obsidianExporter: obsidianExporter,
+		providers:        providers,
+		pending:          make(map[string]pendingConnection),
+	}
+}
+
+// HasProvider reports whether a provider is configured
+func (c *CloudStorageCommand) HasProvider(provider user.CloudProvider) bool {
+	_, ok := c.providers[provider]
+	return ok
+}
+
+// Connect starts connecting a provider and returns the authorization URL to send
+// the user to. Returns shared.ErrInvalidCloudProvider for providers not configured.
+func (c *CloudStorageCommand) Connect(userID shared.ID, provider user.CloudProvider, now time.Time) (string, error) {
+	storage, ok := c.providers[provider]
+	if !ok {
+		return "", shared.ErrInvalidCloudProvider
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	state := hex.EncodeToString(b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for s, p := range c.pending {
+		if now.After(p.expiresAt) {
+			delete(c.pending, s)
+		}
+	}
+	c.pending[state] = pendingConnection{userID: user.UserID(userID), provider: provider, expiresAt: now.Add(connectLinkTTL)}
+
+	return storage.AuthURL(state), nil
+}
+
+// Callback finishes connecting the provider a /connect link was sent for, with the
+// code its OAuth callback received. Returns the user it was connected for, or
+// shared.ErrConnectLinkExpired for unknown and expired states.
+func (c *CloudStorageCommand) Callback(ctx context.Context, state, code string, now time.Time) (*user.User, error) {
+	c.mu.Lock()
+	pending, ok := c.pending[state]
+	delete(c.pending, state)
+	c.mu.Unlock()
+	if !ok || now.After(pending.expiresAt) {
+		return nil, shared.ErrConnectLinkExpired
+	}
+
+	refreshToken, err := c.providers[pending.provider].ExchangeCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+	storage, err := user.NewCloudStorage(pending.provider, refreshToken, now)
+	if err != nil {
+		return nil, err
+	}
+
+	usr, err := c.userRepo.FindByID(ctx, pending.userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := c.userRepo.UpdateCloudStorage(ctx, usr.ID(), storage); err != nil {
+		return nil, fmt.Errorf("failed to save cloud storage: %w", err)
+	}
+	usr.SetCloudStorage(storage)
+	return usr, nil
+}
+
+// Connection returns the user's connected cloud storage, nil when none is
+func (c *CloudStorageCommand) Connection(ctx context.Context, userID shared.ID) (*user.CloudStorage, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return usr.CloudStorage(), nil
+}
+
+// SetFolder changes the folder the user's exports are written to.
+// Returns shared.ErrCloudStorageNotConnected before the user connects a provider.
+func (c *CloudStorageCommand) SetFolder(ctx context.Context, userID shared.ID, folder string) (*user.CloudStorage, error) {
+	storage, err := c.Connection(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if storage == nil {
+		return nil, shared.ErrCloudStorageNotConnected
+	}
+	if err := storage.SetFolder(folder); err != nil {
+		return nil, err
+	}
+	if err := c.userRepo.UpdateCloudStorage(ctx, user.UserID(userID), storage); err != nil {
+		return nil, fmt.Errorf("failed to save cloud storage: %w", err)
+	}
+	return storage, nil
+}
+
+// Disconnect forgets the user's cloud storage, so exports are sent in chat again
+func (c *CloudStorageCommand) Disconnect(ctx context.Context, userID shared.ID) error {
+	if err := c.userRepo.UpdateCloudStorage(ctx, user.UserID(userID), nil); err != nil {
+		return fmt.Errorf("failed to save cloud storage: %w", err)
+	}
+	return nil
+}
+
+// Export writes each recipe of the input as a Markdown file into the user's cloud
+// storage folder and returns how many were written. Files of the same name are
+// replaced, so exporting again updates them.
+func (c *CloudStorageCommand) Export(ctx context.Context, storage *user.CloudStorage, input ExportRecipeInput) (int, error) {
+	provider, ok := c.providers[storage.Provider]
+	if !ok {
+		return 0, shared.ErrCloudStorageNotConnected
+	}
+
+	var recipes []*recipe.Recipe
+	if input.RecipeID != nil {
+		rec, err := c.recipeRepo.FindByID(ctx, recipe.RecipeID(*input.RecipeID))
+		if err != nil {
+			return 0, fmt.Errorf("recipe not found: %w", err)
+		}
+		if rec.UserID() != recipe.UserID(input.UserID) {
+			return 0, fmt.Errorf("unauthorized: recipe belongs to another user")
+		}
+		recipes = []*recipe.Recipe{rec}
+	} else {
+		var err error
+		recipes, err = c.recipeRepo.FindByUserID(ctx, recipe.UserID(input.UserID))
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch recipes: %w", err)
+		}
+	}
+
+	for i, rec := range recipes {
+		result, err := c.obsidianExporter.ExportRecipe(rec.WithFields(input.Fields), input.location())
+		if err != nil {
+			return i, fmt.Errorf("failed to export %q: %w", rec.Title(), err)
+		}
+		if err := provider.Upload(ctx, storage.RefreshToken, storage.Folder, result.Filename, result.Data); err != nil {
+			return i, fmt.Errorf("failed to upload to %s: %w", storage.Provider, err)
+		}
+	}
+	return len(recipes), nil
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/conversation"
+	"receipt-bot/internal/domain/shared"
+)
+
+// MaxTranscriptTurns caps how many of the latest turns a transcript holds
+const MaxTranscriptTurns = 500
+
+// ConversationTranscriptCommand keeps the user's natural-language conversation with
+// the bot and hands it back for /transcript
+type ConversationTranscriptCommand struct {
+	conversationRepo conversation.Repository
+	now              func() time.Time
+}
+
+// NewConversationTranscriptCommand creates a new command
+func NewConversationTranscriptCommand(conversationRepo conversation.Repository) *ConversationTranscriptCommand {
+	return &ConversationTranscriptCommand{
+		conversationRepo: conversationRepo,
+		now:              time.Now,
+	}
+}
+
+// Record appends what the user or the bot said to the user's conversation
+func (c *ConversationTranscriptCommand) Record(ctx context.Context, userID shared.ID, role conversation.Role, text string) error {
+	turn, err := conversation.NewTurn(userID, role, text, c.now())
+	if err != nil {
+		return err
+	}
+	if err := c.conversationRepo.Append(ctx, turn); err != nil {
+		return fmt.Errorf("failed to record conversation: %w", err)
+	}
+	return nil
+}
+
+// Transcript returns the user's latest turns within the retention period, oldest
+// first. Turns past retention are deleted first.
+func (c *ConversationTranscriptCommand) Transcript(ctx context.Context, userID shared.ID) ([]*conversation.Turn, error) {
+	cutoff := c.now().Add(-conversation.Retention)
+	if _, err := c.conversationRepo.DeleteBefore(ctx, userID, cutoff); err != nil {
+		return nil, fmt.Errorf("failed to apply conversation retention: %w", err)
+	}
+
+	turns, err := c.conversationRepo.FindByUser(ctx, userID, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conversation: %w", err)
+	}
+	if len(turns) > MaxTranscriptTurns {
+		turns = turns[:MaxTranscriptTurns]
+	}
+
+	// Stored newest first; transcripts read top to bottom
+	for i, j := 0, len(turns)-1; i < j; i, j = i+1, j-1 {
+		turns[i], turns[j] = turns[j], turns[i]
+	}
+	return turns, nil
+}
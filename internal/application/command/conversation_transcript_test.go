@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/conversation"
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestConversationTranscriptCommand(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cmd := NewConversationTranscriptCommand(memory.NewConversationRepository())
+
+	cmd.now = func() time.Time { return now.Add(-conversation.Retention - time.Hour) }
+	if err := cmd.Record(ctx, userID, conversation.RoleUser, "long forgotten"); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	cmd.now = func() time.Time { return now.Add(-time.Minute) }
+	_ = cmd.Record(ctx, userID, conversation.RoleUser, "something with chicken")
+	_ = cmd.Record(ctx, userID, conversation.RoleAssistant, "Which one?")
+	cmd.now = func() time.Time { return now }
+	_ = cmd.Record(ctx, userID, conversation.RoleUser, "the curry")
+	_ = cmd.Record(ctx, shared.NewID(), conversation.RoleUser, "someone else")
+
+	if err := cmd.Record(ctx, userID, conversation.RoleUser, " "); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Errorf("Record() of nothing error = %v, want ErrInvalidInput", err)
+	}
+
+	turns, err := cmd.Transcript(ctx, userID)
+	if err != nil {
+		t.Fatalf("Transcript() error = %v", err)
+	}
+	var texts []string
+	for _, turn := range turns {
+		texts = append(texts, turn.Text)
+	}
+	want := []string{"something with chicken", "Which one?", "the curry"}
+	if len(texts) != len(want) {
+		t.Fatalf("Transcript() = %q, want %q", texts, want)
+	}
+	for i := range want {
+		if texts[i] != want[i] {
+			t.Errorf("Transcript()[%d] = %q, want %q", i, texts[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// ConvertedRecipe is a recipe together with its instructions and times adapted to an appliance
+type ConvertedRecipe struct {
+	Recipe          *dto.RecipeDTO // the original recipe
+	Appliance       recipe.Appliance
+	Steps           []dto.InstructionDTO
+	PrepTimeMinutes *int
+	CookTimeMinutes *int
+	Notes           string
+}
+
+// ConvertRecipeCommand adapts recipes to a slow cooker or Instant Pot.
+// Conversions are saved as variants of the recipe and reused until the recipe changes.
+type ConvertRecipeCommand struct {
+	recipeRepo  recipe.Repository
+	variantRepo recipe.VariantRepository
+	converter   ports.RecipeConverter
+}
+
+// NewConvertRecipeCommand creates a new command
+func NewConvertRecipeCommand(recipeRepo recipe.Repository, variantRepo recipe.VariantRepository, converter ports.RecipeConverter) *ConvertRecipeCommand {
+	return &ConvertRecipeCommand{
+		recipeRepo:  recipeRepo,
+		variantRepo: variantRepo,
+		converter:   converter,
+	}
+}
+
+// Execute returns the recipe converted to the appliance, written in the target language
+// when it has to be converted again
+func (c *ConvertRecipeCommand) Execute(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID, appliance recipe.Appliance, targetLang string) (*ConvertedRecipe, error) {
+	if !appliance.IsValid() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, shared.ErrRecipeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	variant, err := c.variantRepo.FindVariant(ctx, recipeID, appliance)
+	if err != nil && !errors.Is(err, shared.ErrVariantNotFound) {
+		return nil, fmt.Errorf("failed to get recipe variant: %w", err)
+	}
+
+	if variant == nil || variant.IsStale(rec) {
+		variant, err = c.convert(ctx, rec, appliance, targetLang)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.variantRepo.SaveVariant(ctx, variant); err != nil {
+			return nil, fmt.Errorf("failed to save recipe variant: %w", err)
+		}
+	}
+
+	return toConvertedRecipe(rec, variant), nil
+}
+
+// convert asks the converter for a new variant of the recipe
+func (c *ConvertRecipeCommand) convert(ctx context.Context, rec *recipe.Recipe, appliance recipe.Appliance, targetLang string) (*recipe.Variant, error) {
+	input := &ports.RecipeConversionInput{
+		Title:    rec.Title(),
+		PrepTime: rec.PrepTime(),
+		CookTime: rec.CookTime(),
+		Servings: rec.Servings(),
+	}
+	for _, ing := range rec.Ingredients() {
+		input.Ingredients = append(input.Ingredients, ports.IngredientData{
+			Name:     ing.Name(),
+			Quantity: ing.Quantity(),
+			Unit:     ing.Unit(),
+			Notes:    ing.Notes(),
+		})
+	}
+	for _, inst := range rec.Instructions() {
+		input.Instructions = append(input.Instructions, ports.InstructionData{
+			StepNumber: inst.StepNumber(),
+			Text:       inst.Text(),
+			Duration:   inst.Duration(),
+		})
+	}
+
+	output, err := c.converter.ConvertRecipe(ctx, input, appliance, targetLang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert recipe: %w", err)
+	}
+
+	instructions := make([]recipe.Instruction, 0, len(output.Instructions))
+	for _, data := range output.Instructions {
+		inst, err := recipe.NewInstruction(len(instructions)+1, data.Text, data.Duration)
+		if err != nil {
+			continue
+		}
+		instructions = append(instructions, inst)
+	}
+
+	variant, err := recipe.NewVariant(rec.ID(), appliance, instructions, output.PrepTime, output.CookTime, output.Notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recipe variant: %w", err)
+	}
+	return variant, nil
+}
+
+// toConvertedRecipe converts a recipe and its variant to the converted view
+func toConvertedRecipe(rec *recipe.Recipe, variant *recipe.Variant) *ConvertedRecipe {
+	converted := &ConvertedRecipe{
+		Recipe:          convertRecipeToDTO(rec),
+		Appliance:       variant.Appliance,
+		PrepTimeMinutes: durationMinutes(variant.PrepTime),
+		CookTimeMinutes: durationMinutes(variant.CookTime),
+		Notes:           variant.Notes,
+	}
+	for _, inst := range variant.Instructions {
+		converted.Steps = append(converted.Steps, dto.InstructionDTO{
+			StepNumber:      inst.StepNumber(),
+			Text:            inst.Text(),
+			DurationMinutes: durationMinutes(inst.Duration()),
+		})
+	}
+	return converted
+}
+
+// durationMinutes converts an optional duration to whole minutes
+func durationMinutes(d *time.Duration) *int {
+	if d == nil {
+		return nil
+	}
+	minutes := int(d.Minutes())
+	return &minutes
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+type mockVariantRepository struct {
+	variants map[recipe.Appliance]*recipe.Variant
+}
+
+func (m *mockVariantRepository) SaveVariant(ctx context.Context, variant *recipe.Variant) error {
+	m.variants[variant.Appliance] = variant
+	return nil
+}
+
+func (m *mockVariantRepository) FindVariants(ctx context.Context, recipeID recipe.RecipeID) ([]*recipe.Variant, error) {
+	var variants []*recipe.Variant
+	for _, v := range m.variants {
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+func (m *mockVariantRepository) FindVariant(ctx context.Context, recipeID recipe.RecipeID, appliance recipe.Appliance) (*recipe.Variant, error) {
+	if v, ok := m.variants[appliance]; ok {
+		return v, nil
+	}
+	return nil, shared.ErrVariantNotFound
+}
+
+type mockRecipeConverter struct {
+	calls int
+}
+
+func (m *mockRecipeConverter) ConvertRecipe(ctx context.Context, input *ports.RecipeConversionInput, appliance recipe.Appliance, targetLang string) (*ports.RecipeConversionOutput, error) {
+	m.calls++
+	cook := 6 * time.Hour
+	return &ports.RecipeConversionOutput{
+		Instructions: []ports.InstructionData{
+			{StepNumber: 1, Text: appliance.String() + ": " + input.Instructions[0].Text},
+			{StepNumber: 2, Text: "  "},
+			{StepNumber: 3, Text: "Cook on LOW", Duration: &cook},
+		},
+		CookTime: &cook,
+		Notes:    "Use less water",
+	}, nil
+}
+
+func TestConvertRecipeCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+
+	ing, _ := recipe.NewIngredient("beef", "1", "kg", "")
+	inst, _ := recipe.NewInstruction(1, "Braise the beef", nil)
+	source, _ := recipe.NewSource("https://example.com", recipe.PlatformWeb, "Chef")
+	rec, _ := recipe.NewRecipe(userID, "Stew", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+	rec.SetCookTime(2 * time.Hour)
+
+	repo := newMockRecipeRepository()
+	_ = repo.Save(ctx, rec)
+	variants := &mockVariantRepository{variants: make(map[recipe.Appliance]*recipe.Variant)}
+	converter := &mockRecipeConverter{}
+	cmd := NewConvertRecipeCommand(repo, variants, converter)
+
+	result, err := cmd.Execute(ctx, userID, rec.ID(), recipe.ApplianceSlowCooker, "English")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(result.Steps) != 2 || result.Steps[0].Text != "Slow cooker: Braise the beef" || result.Steps[1].StepNumber != 2 {
+		t.Errorf("Execute() steps = %+v, want 2 renumbered steps", result.Steps)
+	}
+	if result.CookTimeMinutes == nil || *result.CookTimeMinutes != 360 || result.Notes != "Use less water" {
+		t.Errorf("Execute() cook time = %v, notes = %q", result.CookTimeMinutes, result.Notes)
+	}
+	if *result.Recipe.CookTimeMinutes != 120 || result.Recipe.Instructions[0].Text != "Braise the beef" {
+		t.Errorf("Execute() recipe = %+v, want the original recipe unchanged", result.Recipe)
+	}
+	if _, ok := variants.variants[recipe.ApplianceSlowCooker]; !ok {
+		t.Error("Execute() did not save the variant")
+	}
+
+	// The saved variant is reused while the recipe is unchanged
+	if _, err := cmd.Execute(ctx, userID, rec.ID(), recipe.ApplianceSlowCooker, "English"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if converter.calls != 1 {
+		t.Errorf("converter called %d times, want 1", converter.calls)
+	}
+
+	// Another appliance is a separate variant
+	if _, err := cmd.Execute(ctx, userID, rec.ID(), recipe.ApplianceInstantPot, "English"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if converter.calls != 2 || len(variants.variants) != 2 {
+		t.Errorf("after a second appliance: %d calls, %d variants; want 2, 2", converter.calls, len(variants.variants))
+	}
+
+	// A changed recipe is converted again
+	time.Sleep(time.Millisecond)
+	rec.SetServings(6)
+	if _, err := cmd.Execute(ctx, userID, rec.ID(), recipe.ApplianceSlowCooker, "English"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if converter.calls != 3 {
+		t.Errorf("converter called %d times after a change, want 3", converter.calls)
+	}
+
+	if _, err := cmd.Execute(ctx, shared.NewID(), rec.ID(), recipe.ApplianceSlowCooker, "English"); err == nil {
+		t.Error("Execute() for another user's recipe should fail")
+	}
+	if _, err := cmd.Execute(ctx, userID, rec.ID(), "air_fryer", "English"); err == nil {
+		t.Error("Execute() for an unknown appliance should fail")
+	}
+}
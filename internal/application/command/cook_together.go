@@ -0,0 +1,116 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"receipt-bot/internal/domain/cooking"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// CookTogetherCommand runs cook-alongs in group chats: one member posts the steps of
+// a recipe, members claim the steps they do and mark them done.
+// Each chat has one cook-along, kept in memory.
+type CookTogetherCommand struct {
+	recipeRepo recipe.Repository
+
+	mu       sync.Mutex
+	sessions map[int64]*cooking.Session
+}
+
+// NewCookTogetherCommand creates a new command
+func NewCookTogetherCommand(recipeRepo recipe.Repository) *CookTogetherCommand {
+	return &CookTogetherCommand{
+		recipeRepo: recipeRepo,
+		sessions:   make(map[int64]*cooking.Session),
+	}
+}
+
+// Start begins cooking one of the starter's recipes in a chat, replacing the
+// chat's previous cook-along
+func (c *CookTogetherCommand) Start(ctx context.Context, chatID int64, starter cooking.Cook, recipeID recipe.RecipeID) (*cooking.Session, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, shared.ErrRecipeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != recipe.UserID(starter.ID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	steps := make([]string, 0, len(rec.Instructions()))
+	for _, inst := range rec.Instructions() {
+		steps = append(steps, inst.Text())
+	}
+	session, err := cooking.NewSession(rec.Title(), starter, steps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start cook-along: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions[chatID] = session
+	return session.Clone(), nil
+}
+
+// Session returns the chat's cook-along, or shared.ErrCookSessionNotFound
+func (c *CookTogetherCommand) Session(chatID int64) (*cooking.Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	session, ok := c.sessions[chatID]
+	if !ok {
+		return nil, shared.ErrCookSessionNotFound
+	}
+	return session.Clone(), nil
+}
+
+// Claim gives the step a member names, by number or words ("chop the onions"), to them.
+// Returns the step number with the updated cook-along.
+func (c *CookTogetherCommand) Claim(chatID int64, step string, cook cooking.Cook) (*cooking.Session, int, error) {
+	return c.update(chatID, step, func(s *cooking.Session, number int) error {
+		return s.Claim(number, cook)
+	})
+}
+
+// Complete marks the step a member names, by number or words, done.
+// Returns the step number with the updated cook-along.
+func (c *CookTogetherCommand) Complete(chatID int64, step string, cook cooking.Cook) (*cooking.Session, int, error) {
+	return c.update(chatID, step, func(s *cooking.Session, number int) error {
+		return s.Complete(number, cook)
+	})
+}
+
+// Stop ends the chat's cook-along, reporting whether there was one
+func (c *CookTogetherCommand) Stop(chatID int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.sessions[chatID]
+	delete(c.sessions, chatID)
+	return ok
+}
+
+// update finds the named step of the chat's cook-along and changes it
+func (c *CookTogetherCommand) update(chatID int64, step string, change func(s *cooking.Session, number int) error) (*cooking.Session, int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	session, ok := c.sessions[chatID]
+	if !ok {
+		return nil, 0, shared.ErrCookSessionNotFound
+	}
+	number, ok := session.FindStep(step)
+	if !ok {
+		return nil, 0, shared.ErrNoSuchStep
+	}
+	if err := change(session, number); err != nil {
+		return nil, number, err
+	}
+	return session.Clone(), number, nil
+}
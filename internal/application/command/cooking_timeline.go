@@ -0,0 +1,146 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/cooking"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// afterFunc runs f after d and returns a function that cancels it
+type afterFunc func(d time.Duration, f func()) (stop func() bool)
+
+// CookingTimelineCommand schedules several recipes to be ready at the same time
+// and can remind the user when each step starts.
+// Each user has one schedule, kept in memory together with its timers.
+type CookingTimelineCommand struct {
+	recipeRepo recipe.Repository
+	now        func() time.Time
+	after      afterFunc
+
+	mu        sync.Mutex
+	schedules map[shared.ID]*cooking.Schedule
+	timers    map[shared.ID][]func() bool
+}
+
+// NewCookingTimelineCommand creates a new command
+func NewCookingTimelineCommand(recipeRepo recipe.Repository) *CookingTimelineCommand {
+	return &CookingTimelineCommand{
+		recipeRepo: recipeRepo,
+		now:        time.Now,
+		after: func(d time.Duration, f func()) func() bool {
+			return time.AfterFunc(d, f).Stop
+		},
+		schedules: make(map[shared.ID]*cooking.Schedule),
+		timers:    make(map[shared.ID][]func() bool),
+	}
+}
+
+// Execute schedules the recipes to be served at serveAt, replacing the user's previous
+// schedule and cancelling its reminders. A zero serveAt serves as soon as possible.
+func (c *CookingTimelineCommand) Execute(ctx context.Context, userID shared.ID, recipeIDs []recipe.RecipeID, serveAt time.Time) (*cooking.Schedule, error) {
+	plans := make([]cooking.Plan, 0, len(recipeIDs))
+	for _, id := range recipeIDs {
+		rec, err := c.recipeRepo.FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, shared.ErrRecipeNotFound) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to get recipe: %w", err)
+		}
+		if rec.UserID() != recipe.UserID(userID) {
+			return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+		}
+		plans = append(plans, cookingPlan(rec))
+	}
+
+	asap := serveAt.IsZero()
+	if asap {
+		serveAt = c.now()
+	}
+
+	schedule, err := cooking.NewSchedule(plans, serveAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build cooking schedule: %w", err)
+	}
+
+	// Start now instead of in the past
+	if asap {
+		schedule, err = cooking.NewSchedule(plans, serveAt.Add(serveAt.Sub(schedule.Start())))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build cooking schedule: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	c.stopLocked(userID)
+	c.schedules[userID] = schedule
+	c.mu.Unlock()
+
+	return schedule, nil
+}
+
+// StartReminders calls notify when each remaining step of the user's schedule starts.
+// Returns the number of reminders set.
+func (c *CookingTimelineCommand) StartReminders(userID shared.ID, notify func(step cooking.Step)) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	schedule, ok := c.schedules[userID]
+	if !ok {
+		return 0, shared.ErrScheduleNotFound
+	}
+
+	c.stopLocked(userID)
+
+	now := c.now()
+	upcoming := schedule.Upcoming(now)
+	for _, step := range upcoming {
+		step := step
+		c.timers[userID] = append(c.timers[userID], c.after(step.At.Sub(now), func() { notify(step) }))
+	}
+	return len(upcoming), nil
+}
+
+// StopReminders cancels the user's pending reminders and returns how many were cancelled
+func (c *CookingTimelineCommand) StopReminders(userID shared.ID) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stopLocked(userID)
+}
+
+// stopLocked cancels the user's pending reminders. c.mu must be held.
+func (c *CookingTimelineCommand) stopLocked(userID shared.ID) int {
+	stopped := 0
+	for _, stop := range c.timers[userID] {
+		if stop() {
+			stopped++
+		}
+	}
+	delete(c.timers, userID)
+	return stopped
+}
+
+// cookingPlan turns a recipe into the steps to schedule
+func cookingPlan(rec *recipe.Recipe) cooking.Plan {
+	plan := cooking.Plan{Title: rec.Title()}
+	for _, inst := range rec.Instructions() {
+		task := cooking.Task{Text: inst.Text()}
+		if inst.Duration() != nil {
+			task.Duration = *inst.Duration()
+		}
+		plan.Tasks = append(plan.Tasks, task)
+	}
+	if rec.PrepTime() != nil {
+		plan.TotalTime += *rec.PrepTime()
+	}
+	if rec.CookTime() != nil {
+		plan.TotalTime += *rec.CookTime()
+	}
+	return plan
+}
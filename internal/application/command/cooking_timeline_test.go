@@ -0,0 +1,89 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/cooking"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// fakeTimers records scheduled reminders instead of waiting for them
+type fakeTimers struct {
+	delays  []time.Duration
+	funcs   []func()
+	stopped int
+}
+
+func (f *fakeTimers) after(d time.Duration, fn func()) func() bool {
+	f.delays = append(f.delays, d)
+	f.funcs = append(f.funcs, fn)
+	return func() bool {
+		f.stopped++
+		return true
+	}
+}
+
+func TestCookingTimelineCommand(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+	serveAt := time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)
+
+	repo := newMockRecipeRepository()
+	rice := newMenuRecipe(t, userID, "Rice", recipe.CategoryRice, "Simmer the rice.", 20*time.Minute)
+	salad := newMenuRecipe(t, userID, "Salad", recipe.CategorySalads, "Toss the salad.", 0)
+	_ = repo.Save(ctx, rice)
+	_ = repo.Save(ctx, salad)
+
+	timers := &fakeTimers{}
+	cmd := NewCookingTimelineCommand(repo)
+	cmd.after = timers.after
+	cmd.now = func() time.Time { return serveAt.Add(-10 * time.Minute) }
+
+	if _, err := cmd.StartReminders(userID, func(cooking.Step) {}); !errors.Is(err, shared.ErrScheduleNotFound) {
+		t.Fatalf("StartReminders() before Execute error = %v, want ErrScheduleNotFound", err)
+	}
+
+	schedule, err := cmd.Execute(ctx, userID, []recipe.RecipeID{rice.ID(), salad.ID()}, serveAt)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := schedule.Start().Format("15:04"); got != "18:40" {
+		t.Errorf("Start() = %s, want 18:40", got)
+	}
+
+	// At 18:50 the rice has started; the salad (18:55) and serving (19:00) are still ahead
+	var notified []cooking.Step
+	n, err := cmd.StartReminders(userID, func(step cooking.Step) { notified = append(notified, step) })
+	if err != nil {
+		t.Fatalf("StartReminders() error = %v", err)
+	}
+	if n != 2 || len(timers.delays) != 2 || timers.delays[0] != 5*time.Minute || timers.delays[1] != 10*time.Minute {
+		t.Fatalf("StartReminders() = %d with delays %v, want 2 at 5m and 10m", n, timers.delays)
+	}
+
+	timers.funcs[0]()
+	if len(notified) != 1 || notified[0].Recipe != "Salad" {
+		t.Errorf("first reminder = %+v, want the salad step", notified)
+	}
+
+	if stopped := cmd.StopReminders(userID); stopped != 2 {
+		t.Errorf("StopReminders() = %d, want 2", stopped)
+	}
+
+	// Without a serving time, cooking starts now
+	schedule, err = cmd.Execute(ctx, userID, []recipe.RecipeID{rice.ID(), salad.ID()}, time.Time{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := schedule.Start().Format("15:04"); got != "18:50" {
+		t.Errorf("Start() as soon as possible = %s, want 18:50", got)
+	}
+
+	if _, err := cmd.Execute(ctx, shared.NewID(), []recipe.RecipeID{rice.ID(), salad.ID()}, serveAt); err == nil {
+		t.Error("Execute() with another user's recipes should fail")
+	}
+}
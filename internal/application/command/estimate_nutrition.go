@@ -0,0 +1,201 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/nutrition"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/shopping"
+	"receipt-bot/internal/domain/user"
+)
+
+// PantryProductNutrition is a pantry item identified as a product, with its nutrition per 100 g
+type PantryProductNutrition struct {
+	Item    string
+	Product *nutrition.Product
+}
+
+// GroundedIngredient is a recipe ingredient whose nutrition comes from real product data
+type GroundedIngredient struct {
+	Ingredient string
+	Product    string
+	Grams      float64
+	Facts      nutrition.Facts
+}
+
+// NutritionEstimate is the nutrition of a recipe computed from product data.
+// It only covers the grounded ingredients; the others are listed as unknown.
+type NutritionEstimate struct {
+	Recipe     *recipe.Recipe
+	Total      nutrition.Facts
+	PerServing *nutrition.Facts // nil if the recipe has no servings
+	Grounded   []GroundedIngredient
+	Unknown    []string
+}
+
+// EstimateNutritionCommand estimates nutrition from the products identified in the pantry
+type EstimateNutritionCommand struct {
+	nutritionRepo nutrition.Repository
+	catalog       *ProductCatalog
+	userRepo      user.Repository
+	recipeRepo    recipe.Repository
+	normalizer    matching.IngredientNormalizer
+}
+
+// NewEstimateNutritionCommand creates a new command
+func NewEstimateNutritionCommand(
+	nutritionRepo nutrition.Repository,
+	catalog *ProductCatalog,
+	userRepo user.Repository,
+	recipeRepo recipe.Repository,
+) *EstimateNutritionCommand {
+	return &EstimateNutritionCommand{
+		nutritionRepo: nutritionRepo,
+		catalog:       catalog,
+		userRepo:      userRepo,
+		recipeRepo:    recipeRepo,
+		normalizer:    matching.NewRuleBasedNormalizer(nil),
+	}
+}
+
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *EstimateNutritionCommand) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+}
+
+// PantryProducts returns the pantry items identified as products, with their nutrition data.
+// Links to items that have since been removed from the pantry are ignored.
+func (c *EstimateNutritionCommand) PantryProducts(ctx context.Context, userID shared.ID) ([]PantryProductNutrition, error) {
+	pantry, err := c.userRepo.GetPantry(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pantry: %w", err)
+	}
+	inPantry := make(map[string]bool, len(pantry))
+	for _, item := range pantry {
+		inPantry[item] = true
+	}
+
+	links, err := c.nutritionRepo.FindPantryProducts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pantry products: %w", err)
+	}
+
+	var products []PantryProductNutrition
+	for _, link := range links {
+		if !inPantry[link.Item] {
+			continue
+		}
+
+		product, err := c.catalog.Product(ctx, link.Barcode)
+		if err != nil {
+			log.Printf("Skipping pantry product %s: %v", link.Barcode, err)
+			continue
+		}
+		products = append(products, PantryProductNutrition{Item: link.Item, Product: product})
+	}
+
+	return products, nil
+}
+
+// EstimateRecipe estimates the nutrition of a recipe from the products in the user's pantry.
+// Ingredients are grounded when they match a pantry product with nutrition data and
+// their quantity is given in grams or millilitres.
+func (c *EstimateNutritionCommand) EstimateRecipe(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID) (*NutritionEstimate, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, shared.ErrRecipeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	byName, err := c.productsByName(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.estimate(rec, byName), nil
+}
+
+// productsByName returns the pantry products with nutrition data by normalized item name
+func (c *EstimateNutritionCommand) productsByName(ctx context.Context, userID shared.ID) (map[string]*nutrition.Product, error) {
+	products, err := c.PantryProducts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*nutrition.Product, len(products))
+	for _, p := range products {
+		if p.Product.Facts != nil {
+			byName[c.normalizer.Normalize(p.Item)] = p.Product
+		}
+	}
+	return byName, nil
+}
+
+// estimate estimates the nutrition of a recipe from pantry products by normalized item name
+func (c *EstimateNutritionCommand) estimate(rec *recipe.Recipe, byName map[string]*nutrition.Product) *NutritionEstimate {
+	estimate := &NutritionEstimate{Recipe: rec}
+	for _, ing := range rec.Ingredients() {
+		product, ok := byName[c.normalizer.Normalize(ing.Name())]
+		if !ok {
+			estimate.Unknown = append(estimate.Unknown, ing.Name())
+			continue
+		}
+
+		grams, ok := ingredientGrams(ing)
+		if !ok {
+			estimate.Unknown = append(estimate.Unknown, ing.Name())
+			continue
+		}
+
+		facts := product.Facts.ForAmount(grams)
+		estimate.Total = estimate.Total.Add(facts)
+		estimate.Grounded = append(estimate.Grounded, GroundedIngredient{
+			Ingredient: ing.Name(),
+			Product:    product.Name,
+			Grams:      grams,
+			Facts:      facts,
+		})
+	}
+
+	if servings := rec.Servings(); servings != nil && *servings > 0 {
+		perServing := estimate.Total.Divide(*servings)
+		estimate.PerServing = &perServing
+	}
+
+	return estimate
+}
+
+// Serving returns the estimated nutrition of one serving, or of the whole recipe
+// when it doesn't say how many it serves
+func (e *NutritionEstimate) Serving() nutrition.Facts {
+	if e.PerServing != nil {
+		return *e.PerServing
+	}
+	return e.Total
+}
+
+// ingredientGrams returns the weight of an ingredient when it is given by weight or volume.
+// Volumes are counted as grams, which is close enough for most cooking liquids.
+func ingredientGrams(ing recipe.Ingredient) (float64, bool) {
+	amount, ok := shopping.ParseAmount(ing.Quantity(), ing.Unit())
+	if !ok {
+		return 0, false
+	}
+
+	switch amount.Unit {
+	case "g", "ml":
+		return amount.Value, true
+	default:
+		return 0, false
+	}
+}
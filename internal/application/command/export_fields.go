@@ -0,0 +1,68 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// ManageExportFieldsCommand reads and changes which recipe fields a user exports
+// when an export does not choose its own, e.g. only ingredients for shopping
+type ManageExportFieldsCommand struct {
+	userRepo user.Repository
+}
+
+// NewManageExportFieldsCommand creates a new command
+func NewManageExportFieldsCommand(userRepo user.Repository) *ManageExportFieldsCommand {
+	return &ManageExportFieldsCommand{
+		userRepo: userRepo,
+	}
+}
+
+// Defaults returns the fields the user exports by default
+func (c *ManageExportFieldsCommand) Defaults(ctx context.Context, userID shared.ID) (recipe.ExportFields, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return userExportFields(usr), nil
+}
+
+// SetDefaults replaces the fields the user exports by default; nil goes back to the defaults
+func (c *ManageExportFieldsCommand) SetDefaults(ctx context.Context, userID shared.ID, fields recipe.ExportFields) error {
+	var names []string
+	if fields != nil {
+		names = fields.Names()
+	}
+	if err := c.userRepo.UpdateExportFields(ctx, user.UserID(userID), names); err != nil {
+		return fmt.Errorf("failed to save export fields: %w", err)
+	}
+	return nil
+}
+
+// userExportFields returns the fields a user exports by default, ignoring
+// stored fields that are no longer known
+func userExportFields(usr *user.User) recipe.ExportFields {
+	var fields recipe.ExportFields
+	for _, name := range usr.ExportFields() {
+		if field := recipe.ExportField(name); field.IsValid() {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return recipe.DefaultExportFields()
+	}
+	return fields
+}
+
+// withFields returns copies of the recipes with only the fields to export
+func withFields(recipes []*recipe.Recipe, fields recipe.ExportFields) []*recipe.Recipe {
+	selected := make([]*recipe.Recipe, len(recipes))
+	for i, rec := range recipes {
+		selected[i] = rec.WithFields(fields)
+	}
+	return selected
+}
@@ -0,0 +1,159 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+const (
+	// NotionBatchSize is how many recipes a bulk Notion export sends between progress updates
+	NotionBatchSize = 25
+
+	// notionMaxFailuresInRow stops a bulk Notion export that keeps failing, as when
+	// Notion is down or the user revoked access, rather than failing every recipe
+	notionMaxFailuresInRow = 3
+
+	// notionCheckpointTarget is the export target of bulk Notion export checkpoints
+	notionCheckpointTarget = "notion"
+)
+
+// NotionExportFailure is a recipe a bulk Notion export could not send
+type NotionExportFailure struct {
+	Title  string
+	Reason string
+}
+
+// NotionExportReport is the outcome of a bulk Notion export
+type NotionExportReport struct {
+	Total    int // recipes the user has
+	Exported int // recipes sent by this run
+	Resumed  int // recipes an earlier, unfinished run already sent, not sent again
+	Failed   []NotionExportFailure
+	Stopped  bool   // the run stopped early; running it again resumes it
+	URL      string // the last page created
+}
+
+// ExportAllToNotion exports all the user's recipes to Notion in batches of
+// NotionBatchSize, calling progress after each batch and when it stops early. The
+// Notion client paces its requests to Notion's rate limit. A run that stops early,
+// because Notion kept refusing requests or ctx was canceled, is resumed by the next
+// one, which skips the recipes already sent; so is a run that finished with failed
+// recipes, which the next one retries. The recipes sent are checkpointed after each
+// batch when export checkpoints are set. It returns shared.ErrNotionNotConnected
+// when the user has not connected Notion.
+func (c *ExportRecipeCommand) ExportAllToNotion(ctx context.Context, input ExportRecipeInput, progress func(done, total int)) (*NotionExportReport, error) {
+	if c.notionExporter == nil {
+		return nil, fmt.Errorf("notion exporter not configured")
+	}
+
+	connected, err := c.notionExporter.IsConnected(ctx, input.UserID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check Notion connection: %w", err)
+	}
+	if !connected {
+		return nil, shared.ErrNotionNotConnected
+	}
+
+	recipes, err := c.recipeRepo.FindByUserID(ctx, recipe.UserID(input.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recipes: %w", err)
+	}
+
+	report := &NotionExportReport{Total: len(recipes)}
+	exported, err := c.notionCheckpoint(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	var pending []*recipe.Recipe
+	for _, rec := range recipes {
+		if exported[rec.ID()] {
+			report.Resumed++
+			continue
+		}
+		pending = append(pending, rec)
+	}
+
+	failuresInRow := 0
+	for i, rec := range pending {
+		result, err := c.notionExporter.ExportRecipe(ctx, input.UserID.String(), rec.WithFields(input.Fields))
+		switch {
+		case ctx.Err() != nil:
+			report.Stopped = true
+		case err != nil:
+			report.Failed = append(report.Failed, NotionExportFailure{Title: rec.Title(), Reason: err.Error()})
+			failuresInRow++
+		case !result.Success:
+			report.Failed = append(report.Failed, NotionExportFailure{Title: rec.Title(), Reason: result.Message})
+			failuresInRow++
+		default:
+			exported[rec.ID()] = true
+			report.Exported++
+			report.URL = result.URL
+			failuresInRow = 0
+		}
+		if errors.Is(err, shared.ErrNotionRateLimited) || failuresInRow == notionMaxFailuresInRow {
+			report.Stopped = true
+		}
+		if report.Stopped {
+			if progress != nil {
+				progress(report.Resumed+report.Exported, report.Total)
+			}
+			break
+		}
+
+		if (i+1)%NotionBatchSize == 0 || i+1 == len(pending) {
+			if i+1 < len(pending) {
+				c.saveNotionCheckpoint(ctx, input.UserID, exported)
+			}
+			if progress != nil {
+				progress(report.Resumed+i+1, report.Total)
+			}
+		}
+	}
+
+	if report.Stopped || len(report.Failed) > 0 {
+		c.saveNotionCheckpoint(ctx, input.UserID, exported)
+	} else {
+		c.saveNotionCheckpoint(ctx, input.UserID, nil)
+	}
+	return report, nil
+}
+
+// notionCheckpoint returns the recipes the user's unfinished bulk Notion export already sent
+func (c *ExportRecipeCommand) notionCheckpoint(ctx context.Context, userID shared.ID) (map[recipe.RecipeID]bool, error) {
+	exported := make(map[recipe.RecipeID]bool)
+	if c.checkpoints == nil {
+		return exported, nil
+	}
+
+	sent, err := c.checkpoints.FindExportCheckpoint(ctx, recipe.UserID(userID), notionCheckpointTarget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export checkpoint: %w", err)
+	}
+	for _, id := range sent {
+		exported[id] = true
+	}
+	return exported, nil
+}
+
+// saveNotionCheckpoint stores the recipes a bulk Notion export sent so far; none
+// remove the checkpoint. It is saved even when ctx was canceled, so a stopped run
+// can be resumed.
+func (c *ExportRecipeCommand) saveNotionCheckpoint(ctx context.Context, userID shared.ID, exported map[recipe.RecipeID]bool) {
+	if c.checkpoints == nil {
+		return
+	}
+
+	sent := make([]recipe.RecipeID, 0, len(exported))
+	for id := range exported {
+		sent = append(sent, id)
+	}
+	if err := c.checkpoints.SaveExportCheckpoint(context.WithoutCancel(ctx), recipe.UserID(userID), notionCheckpointTarget, sent); err != nil {
+		log.Printf("Warning: failed to save Notion export checkpoint: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// fakeNotionExporter creates pages until limitAfter pages, then refuses every
+// request as rate limited, and always fails recipes titled "Broken"
+type fakeNotionExporter struct {
+	limitAfter int // 0 never limits
+	pages      []string
+}
+
+func (f *fakeNotionExporter) GetAuthURL(userID string, state string) string { return "" }
+
+func (f *fakeNotionExporter) HandleCallback(ctx context.Context, userID string, code string) error {
+	return nil
+}
+
+func (f *fakeNotionExporter) ExportRecipe(ctx context.Context, userID string, rec *recipe.Recipe) (*ports.ExportResult, error) {
+	if f.limitAfter > 0 && len(f.pages) >= f.limitAfter {
+		return nil, fmt.Errorf("failed to create page: %w", shared.ErrNotionRateLimited)
+	}
+	if rec.Title() == "Broken" {
+		return nil, errors.New("page creation failed: validation_error")
+	}
+	f.pages = append(f.pages, rec.Title())
+	return &ports.ExportResult{Success: true, Format: "notion", URL: "https://notion.so/" + rec.Title()}, nil
+}
+
+func (f *fakeNotionExporter) ExportRecipes(ctx context.Context, userID string, recipes []*recipe.Recipe) (*ports.ExportResult, error) {
+	return nil, errors.New("not used")
+}
+
+func (f *fakeNotionExporter) IsConnected(ctx context.Context, userID string) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeNotionExporter) Disconnect(ctx context.Context, userID string) error { return nil }
+
+func TestExportRecipeCommand_ExportAllToNotion_Resumes(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+	repo := newMockRecipeRepository()
+	for i := 1; i <= 5; i++ {
+		ing, _ := recipe.NewIngredient("flour", "200g", "", "")
+		inst, _ := recipe.NewInstruction(1, "Mix", nil)
+		source, _ := recipe.NewSource(fmt.Sprintf("https://example.com/%d", i), recipe.PlatformWeb, "")
+		rec, _ := recipe.NewRecipe(recipe.UserID(userID), fmt.Sprintf("Recipe %d", i), []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+		_ = repo.Save(ctx, rec)
+	}
+	notion := &fakeNotionExporter{limitAfter: 2}
+	checkpoints := memory.NewExportCheckpointRepository()
+	cmd := NewExportRecipeCommand(repo, nil, notion, nil)
+	cmd.SetExportCheckpoints(checkpoints)
+	input := ExportRecipeInput{UserID: userID, Format: ExportFormatNotion}
+
+	// Notion keeps refusing requests after two pages
+	var done []int
+	report, err := cmd.ExportAllToNotion(ctx, input, func(n, total int) { done = append(done, n) })
+	if err != nil {
+		t.Fatalf("ExportAllToNotion() error = %v", err)
+	}
+	if !report.Stopped || report.Exported != 2 || report.Total != 5 {
+		t.Fatalf("ExportAllToNotion() = %+v, want stopped after 2 of 5", report)
+	}
+	if len(done) != 1 || done[0] != 2 {
+		t.Errorf("progress = %v, want one call at 2 of 5 when stopping", done)
+	}
+
+	// The next run, even after a restart, only sends the other three
+	notion.limitAfter = 0
+	cmd = NewExportRecipeCommand(repo, nil, notion, nil)
+	cmd.SetExportCheckpoints(checkpoints)
+	done = nil
+	report, err = cmd.ExportAllToNotion(ctx, input, func(n, total int) { done = append(done, n) })
+	if err != nil {
+		t.Fatalf("ExportAllToNotion() resumed error = %v", err)
+	}
+	if report.Stopped || report.Exported != 3 || report.Resumed != 2 {
+		t.Errorf("ExportAllToNotion() resumed = %+v, want the other 3 exported", report)
+	}
+	if len(notion.pages) != 5 {
+		t.Errorf("pages = %v, want each recipe once", notion.pages)
+	}
+	if len(done) != 1 || done[0] != 5 {
+		t.Errorf("progress = %v, want one call at 5 of 5", done)
+	}
+
+	// A finished export starts over
+	if sent, _ := checkpoints.FindExportCheckpoint(ctx, recipe.UserID(userID), "notion"); len(sent) != 0 {
+		t.Errorf("checkpoint after finishing = %v, want none", sent)
+	}
+	report, _ = cmd.ExportAllToNotion(ctx, input, nil)
+	if report.Exported != 5 || report.Resumed != 0 {
+		t.Errorf("ExportAllToNotion() after finishing = %+v, want all 5 exported again", report)
+	}
+}
+
+func TestExportRecipeCommand_ExportAllToNotion_ReportsFailures(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+	repo := newMockRecipeRepository()
+	for _, title := range []string{"Soup", "Broken"} {
+		ing, _ := recipe.NewIngredient("water", "1l", "", "")
+		inst, _ := recipe.NewInstruction(1, "Boil", nil)
+		source, _ := recipe.NewSource("https://example.com/"+title, recipe.PlatformWeb, "")
+		rec, _ := recipe.NewRecipe(recipe.UserID(userID), title, []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+		_ = repo.Save(ctx, rec)
+	}
+	cmd := NewExportRecipeCommand(repo, nil, &fakeNotionExporter{}, nil)
+	cmd.SetExportCheckpoints(memory.NewExportCheckpointRepository())
+	input := ExportRecipeInput{UserID: userID, Format: ExportFormatNotion}
+
+	report, err := cmd.ExportAllToNotion(ctx, input, nil)
+	if err != nil {
+		t.Fatalf("ExportAllToNotion() error = %v", err)
+	}
+	if report.Stopped || report.Exported != 1 || len(report.Failed) != 1 || report.Failed[0].Title != "Broken" {
+		t.Fatalf("ExportAllToNotion() = %+v, want Soup exported and Broken reported", report)
+	}
+
+	// Running it again retries only the failed recipe
+	report, _ = cmd.ExportAllToNotion(ctx, input, nil)
+	if report.Resumed != 1 || report.Exported != 0 || len(report.Failed) != 1 {
+		t.Errorf("ExportAllToNotion() again = %+v, want only Broken retried", report)
+	}
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"receipt-bot/internal/domain/recipe"
 	"receipt-bot/internal/domain/shared"
@@ -15,13 +16,26 @@ type ExportFormat string
 const (
 	ExportFormatObsidian ExportFormat = "obsidian"
 	ExportFormatNotion   ExportFormat = "notion"
+	ExportFormatCrouton  ExportFormat = "crouton"
+	ExportFormatAnyList  ExportFormat = "anylist"
+	ExportFormatWhisk    ExportFormat = "whisk"
 )
 
 // ExportRecipeInput contains input for exporting recipes
 type ExportRecipeInput struct {
 	UserID   shared.ID
-	RecipeID *shared.ID   // If nil, export all recipes
+	RecipeID *shared.ID // If nil, export all recipes
 	Format   ExportFormat
+	Location *time.Location      // user's time zone for dates in the export, nil for the server's
+	Fields   recipe.ExportFields // the recipe fields to export, nil for the default fields
+}
+
+// location returns the time zone dates in the export are written in
+func (in ExportRecipeInput) location() *time.Location {
+	if in.Location == nil {
+		return time.Local
+	}
+	return in.Location
 }
 
 // ExportRecipeCommand handles recipe export operations
@@ -29,6 +43,8 @@ type ExportRecipeCommand struct {
 	recipeRepo       recipe.Repository
 	obsidianExporter ports.ObsidianExporter
 	notionExporter   ports.NotionExporter
+	appExporters     map[ExportFormat]ports.AppExporter
+	checkpoints      recipe.ExportCheckpointRepository // optional, bulk exports are not resumed when nil
 }
 
 // NewExportRecipeCommand creates a new export recipe command
@@ -36,14 +52,22 @@ func NewExportRecipeCommand(
 	recipeRepo recipe.Repository,
 	obsidianExporter ports.ObsidianExporter,
 	notionExporter ports.NotionExporter,
+	appExporters map[ExportFormat]ports.AppExporter,
 ) *ExportRecipeCommand {
 	return &ExportRecipeCommand{
 		recipeRepo:       recipeRepo,
 		obsidianExporter: obsidianExporter,
 		notionExporter:   notionExporter,
+		appExporters:     appExporters,
 	}
 }
 
+// SetExportCheckpoints keeps how far each unfinished bulk Notion export got, so the
+// next run resumes it instead of sending every recipe again
+func (c *ExportRecipeCommand) SetExportCheckpoints(repo recipe.ExportCheckpointRepository) {
+	c.checkpoints = repo
+}
+
 // Execute exports recipes based on the input parameters
 func (c *ExportRecipeCommand) Execute(ctx context.Context, input ExportRecipeInput) (*ports.ExportResult, error) {
 	switch input.Format {
@@ -51,6 +75,8 @@ func (c *ExportRecipeCommand) Execute(ctx context.Context, input ExportRecipeInp
 		return c.exportToObsidian(ctx, input)
 	case ExportFormatNotion:
 		return c.exportToNotion(ctx, input)
+	case ExportFormatCrouton, ExportFormatAnyList, ExportFormatWhisk:
+		return c.exportToApp(ctx, input)
 	default:
 		return nil, fmt.Errorf("unsupported export format: %s", input.Format)
 	}
@@ -74,7 +100,7 @@ func (c *ExportRecipeCommand) exportToObsidian(ctx context.Context, input Export
 			return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
 		}
 
-		return c.obsidianExporter.ExportRecipe(rec)
+		return c.obsidianExporter.ExportRecipe(rec.WithFields(input.Fields), input.location())
 	}
 
 	// Export all recipes for user
@@ -91,7 +117,46 @@ func (c *ExportRecipeCommand) exportToObsidian(ctx context.Context, input Export
 		}, nil
 	}
 
-	return c.obsidianExporter.ExportRecipes(recipes)
+	return c.obsidianExporter.ExportRecipes(withFields(recipes, input.Fields), input.location())
+}
+
+// exportToApp handles exports to other recipe apps' import formats
+func (c *ExportRecipeCommand) exportToApp(ctx context.Context, input ExportRecipeInput) (*ports.ExportResult, error) {
+	exporter, ok := c.appExporters[input.Format]
+	if !ok {
+		return nil, fmt.Errorf("%s exporter not configured", input.Format)
+	}
+
+	// Export single recipe
+	if input.RecipeID != nil {
+		rec, err := c.recipeRepo.FindByID(ctx, recipe.RecipeID(*input.RecipeID))
+		if err != nil {
+			return nil, fmt.Errorf("recipe not found: %w", err)
+		}
+
+		// Verify ownership
+		if rec.UserID() != recipe.UserID(input.UserID) {
+			return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+		}
+
+		return exporter.ExportRecipe(rec.WithFields(input.Fields))
+	}
+
+	// Export all recipes for user
+	recipes, err := c.recipeRepo.FindByUserID(ctx, recipe.UserID(input.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recipes: %w", err)
+	}
+
+	if len(recipes) == 0 {
+		return &ports.ExportResult{
+			Success: false,
+			Format:  string(input.Format),
+			Message: "No recipes to export",
+		}, nil
+	}
+
+	return exporter.ExportRecipes(withFields(recipes, input.Fields), input.location())
 }
 
 // exportToNotion handles Notion export
@@ -126,7 +191,7 @@ func (c *ExportRecipeCommand) exportToNotion(ctx context.Context, input ExportRe
 			return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
 		}
 
-		return c.notionExporter.ExportRecipe(ctx, input.UserID.String(), rec)
+		return c.notionExporter.ExportRecipe(ctx, input.UserID.String(), rec.WithFields(input.Fields))
 	}
 
 	// Export all recipes for user
@@ -143,7 +208,7 @@ func (c *ExportRecipeCommand) exportToNotion(ctx context.Context, input ExportRe
 		}, nil
 	}
 
-	return c.notionExporter.ExportRecipes(ctx, input.UserID.String(), recipes)
+	return c.notionExporter.ExportRecipes(ctx, input.UserID.String(), withFields(recipes, input.Fields))
 }
 
 // HasObsidianExporter returns true if Obsidian export is available
@@ -155,3 +220,9 @@ func (c *ExportRecipeCommand) HasObsidianExporter() bool {
 func (c *ExportRecipeCommand) HasNotionExporter() bool {
 	return c.notionExporter != nil
 }
+
+// HasAppExporter returns true if export to the given recipe app is available
+func (c *ExportRecipeCommand) HasAppExporter(format ExportFormat) bool {
+	_, ok := c.appExporters[format]
+	return ok
+}
@@ -0,0 +1,142 @@
+package command
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"receipt-bot/internal/domain/bookmark"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// MaxEmailLinks caps the recipe links processed from one email
+const MaxEmailLinks = 5
+
+// emailCodeLength is the length of the code that keeps personal addresses from being guessed
+const emailCodeLength = 10
+
+// InboundEmail is an email a user forwarded to their personal address
+type InboundEmail struct {
+	Text  string   // the body as plain text
+	Links []string // the http(s) links of the body, in order
+}
+
+// EmailResult is the outcome of a forwarded email
+type EmailResult struct {
+	Saved  []*recipe.Recipe
+	Failed []string // recipe links that could not be processed
+}
+
+// ForwardEmailCommand saves the recipes of emails users forward, such as newsletters,
+// to their personal address "u<Telegram ID>.<code>@<domain>". The code is the user's
+// ID signed with a server secret, so addresses need no storage.
+type ForwardEmailCommand struct {
+	processRecipeLink *ProcessRecipeLinkCommand // without a messenger, so emails are processed quietly
+	userRepo          user.Repository
+	selector          *bookmark.Selector
+	domain            string
+	secret            []byte
+}
+
+// NewForwardEmailCommand creates a new command receiving mail for domain
+func NewForwardEmailCommand(processRecipeLink *ProcessRecipeLinkCommand, userRepo user.Repository, selector *bookmark.Selector, domain, secret string) *ForwardEmailCommand {
+	return &ForwardEmailCommand{
+		processRecipeLink: processRecipeLink,
+		userRepo:          userRepo,
+		selector:          selector,
+		domain:            strings.ToLower(strings.TrimSpace(domain)),
+		secret:            []byte(secret),
+	}
+}
+
+// Address returns the personal address a user forwards emails to
+func (c *ForwardEmailCommand) Address(usr *user.User) string {
+	return fmt.Sprintf("u%d.%s@%s", usr.TelegramID(), c.code(usr.ID()), c.domain)
+}
+
+// Resolve returns the user a personal address belongs to.
+// It returns shared.ErrUserNotFound for addresses this server did not give out.
+func (c *ForwardEmailCommand) Resolve(ctx context.Context, address string) (*user.User, error) {
+	local, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(address)), "@")
+	if !ok || domain != c.domain {
+		return nil, shared.ErrUserNotFound
+	}
+	rest, ok := strings.CutPrefix(local, "u")
+	if !ok {
+		return nil, shared.ErrUserNotFound
+	}
+	rawID, code, ok := strings.Cut(rest, ".")
+	telegramID, err := strconv.ParseInt(rawID, 10, 64)
+	if !ok || err != nil {
+		return nil, shared.ErrUserNotFound
+	}
+
+	usr, err := c.userRepo.FindByTelegramID(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(code), []byte(c.code(usr.ID()))) {
+		return nil, shared.ErrUserNotFound
+	}
+	return usr, nil
+}
+
+// Execute saves the recipe of an email. A newsletter usually holds the whole recipe,
+// which is saved with its first recipe link (or first link) as the source; otherwise
+// the recipe links of the email are processed one by one. It returns
+// shared.ErrNoRecipeLinks if the email holds no recipe and links to none.
+func (c *ForwardEmailCommand) Execute(ctx context.Context, userID user.UserID, email InboundEmail) (*EmailResult, error) {
+	candidates := make([]bookmark.Bookmark, len(email.Links))
+	for i, link := range email.Links {
+		candidates[i] = bookmark.Bookmark{URL: link}
+	}
+	links := c.selector.RecipeLinks(candidates)
+	if len(links) > MaxEmailLinks {
+		links = links[:MaxEmailLinks]
+	}
+
+	result := &EmailResult{}
+	if text := strings.TrimSpace(email.Text); text != "" && len(email.Links) > 0 {
+		source := email.Links[0]
+		if len(links) > 0 {
+			source = links[0]
+		}
+		rec, err := c.processRecipeLink.ExecutePage(ctx, source, &ports.ScrapeResult{Captions: text, OriginalURL: source}, userID)
+		if err == nil {
+			result.Saved = append(result.Saved, rec)
+			return result, nil
+		}
+		if errors.Is(err, shared.ErrLLMTimeout) || errors.Is(err, shared.ErrQuotaExceeded) {
+			return nil, err
+		}
+	}
+
+	if len(links) == 0 {
+		return nil, shared.ErrNoRecipeLinks
+	}
+	for _, link := range links {
+		rec, err := c.processRecipeLink.Execute(ctx, link, userID, 0)
+		if err != nil {
+			result.Failed = append(result.Failed, link)
+			continue
+		}
+		result.Saved = append(result.Saved, rec)
+	}
+	return result, nil
+}
+
+// code computes the address code of a user ID
+func (c *ForwardEmailCommand) code(userID user.UserID) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte("email:" + userID.String()))
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(mac.Sum(nil))
+	return strings.ToLower(encoded[:emailCodeLength])
+}
@@ -0,0 +1,104 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// coverIngredients is how many ingredients a cover's description names
+const coverIngredients = 6
+
+// GenerateCoverCommand draws cover images for recipes whose source has no picture
+// of the dish. Covers are kept in blob storage and always marked as AI-generated.
+type GenerateCoverCommand struct {
+	recipeRepo recipe.Repository
+	generator  ports.ImageGenerator
+	blobs      ports.BlobStorage
+	now        func() time.Time
+}
+
+// NewGenerateCoverCommand creates a new command
+func NewGenerateCoverCommand(recipeRepo recipe.Repository, generator ports.ImageGenerator, blobs ports.BlobStorage) *GenerateCoverCommand {
+	return &GenerateCoverCommand{
+		recipeRepo: recipeRepo,
+		generator:  generator,
+		blobs:      blobs,
+		now:        time.Now,
+	}
+}
+
+// Execute draws a cover for the recipe and saves it; a recipe that has a cover keeps
+// it. Returns shared.ErrHasSourceImage for a recipe whose source has an image.
+func (c *GenerateCoverCommand) Execute(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID) (*recipe.Cover, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, shared.ErrRecipeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+	if cover := rec.Cover(); cover != nil {
+		return cover, nil
+	}
+	if recipe.SourceImageURL(rec.Source().URL()) != "" {
+		return nil, shared.ErrHasSourceImage
+	}
+
+	image, err := c.generator.GenerateImage(ctx, coverPrompt(rec))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate cover: %w", err)
+	}
+
+	cover := &recipe.Cover{Key: recipe.CoverKey(rec.ID()), AIGenerated: true, CreatedAt: c.now()}
+	if err := c.blobs.Put(ctx, cover.Key, "image/png", image); err != nil {
+		return nil, fmt.Errorf("failed to store cover: %w", err)
+	}
+	rec.SetCover(cover)
+	if err := c.recipeRepo.Update(ctx, rec); err != nil {
+		return nil, fmt.Errorf("failed to save recipe: %w", err)
+	}
+	return cover, nil
+}
+
+// Image returns the image stored under a cover's key
+func (c *GenerateCoverCommand) Image(ctx context.Context, key string) ([]byte, error) {
+	image, err := c.blobs.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cover: %w", err)
+	}
+	return image, nil
+}
+
+// coverPrompt describes a recipe's finished dish for the image generator
+func coverPrompt(rec *recipe.Recipe) string {
+	dish := rec.Title()
+	if rec.Cuisine() != "" {
+		dish += " (" + rec.Cuisine() + " cuisine)"
+	}
+
+	var ingredients []string
+	for _, ing := range rec.Ingredients() {
+		if len(ingredients) == coverIngredients {
+			break
+		}
+		ingredients = append(ingredients, ing.Name())
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Appetizing food photograph of %s", dish)
+	if len(ingredients) > 0 {
+		fmt.Fprintf(&sb, ", made with %s", strings.Join(ingredients, ", "))
+	}
+	sb.WriteString(". The finished dish plated on a table, seen from above in natural light. No text, no people.")
+	return sb.String()
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+type mockImageGenerator struct {
+	descriptions []string
+}
+
+func (m *mockImageGenerator) GenerateImage(ctx context.Context, description string) ([]byte, error) {
+	m.descriptions = append(m.descriptions, description)
+	return []byte("png"), nil
+}
+
+func TestGenerateCoverCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+
+	ing, _ := recipe.NewIngredient("chickpeas", "1", "can", "")
+	inst, _ := recipe.NewInstruction(1, "Simmer the chickpeas", nil)
+	source, _ := recipe.NewSource("https://instagram.com/p/abc", recipe.PlatformInstagram, "Chef")
+	rec, _ := recipe.NewRecipe(userID, "Chana Masala", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+	rec.SetCuisine("Indian")
+
+	repo := newMockRecipeRepository()
+	_ = repo.Save(ctx, rec)
+	generator := &mockImageGenerator{}
+	blobs := memory.NewBlobStorage()
+	cmd := NewGenerateCoverCommand(repo, generator, blobs)
+
+	cover, err := cmd.Execute(ctx, userID, rec.ID())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !cover.AIGenerated || cover.Key != recipe.CoverKey(rec.ID()) {
+		t.Errorf("Execute() = %+v, want an AI-generated cover", cover)
+	}
+	if len(generator.descriptions) != 1 || !strings.Contains(generator.descriptions[0], "Chana Masala (Indian cuisine), made with chickpeas") {
+		t.Errorf("generator got %q", generator.descriptions)
+	}
+	if image, err := cmd.Image(ctx, cover.Key); err != nil || string(image) != "png" {
+		t.Errorf("Image() = %q, %v", image, err)
+	}
+	if saved, _ := repo.FindByID(ctx, rec.ID()); saved.Cover() == nil {
+		t.Error("expected the cover saved with the recipe")
+	}
+
+	// A recipe keeps its cover
+	if _, err := cmd.Execute(ctx, userID, rec.ID()); err != nil || len(generator.descriptions) != 1 {
+		t.Errorf("second Execute() error = %v after %d generations, want the saved cover", err, len(generator.descriptions))
+	}
+}
+
+func TestGenerateCoverCommand_SourceImage(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+
+	ing, _ := recipe.NewIngredient("spaghetti", "200", "g", "")
+	inst, _ := recipe.NewInstruction(1, "Boil the spaghetti", nil)
+	source, _ := recipe.NewSource("https://youtube.com/watch?v=dQw4w9WgXcQ", recipe.PlatformYouTube, "Chef")
+	rec, _ := recipe.NewRecipe(userID, "Carbonara", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+
+	repo := newMockRecipeRepository()
+	_ = repo.Save(ctx, rec)
+	cmd := NewGenerateCoverCommand(repo, &mockImageGenerator{}, memory.NewBlobStorage())
+
+	if _, err := cmd.Execute(ctx, userID, rec.ID()); !errors.Is(err, shared.ErrHasSourceImage) {
+		t.Errorf("Execute() error = %v, want ErrHasSourceImage", err)
+	}
+}
@@ -0,0 +1,204 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/mealplan"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/shopping"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// GenerateShoppingListCommand builds a shopping list from a week's meal plan
+type GenerateShoppingListCommand struct {
+	mealPlanRepo     mealplan.Repository
+	recipeRepo       recipe.Repository
+	userRepo         user.Repository
+	shoppingListRepo shopping.Repository
+	classifier       ports.AisleClassifier
+	normalizer       matching.IngredientNormalizer
+}
+
+// NewGenerateShoppingListCommand creates a new command.
+// The classifier is optional; without it every item is listed under "other".
+func NewGenerateShoppingListCommand(
+	mealPlanRepo mealplan.Repository,
+	recipeRepo recipe.Repository,
+	userRepo user.Repository,
+	shoppingListRepo shopping.Repository,
+	classifier ports.AisleClassifier,
+) *GenerateShoppingListCommand {
+	return &GenerateShoppingListCommand{
+		mealPlanRepo:     mealPlanRepo,
+		recipeRepo:       recipeRepo,
+		userRepo:         userRepo,
+		shoppingListRepo: shoppingListRepo,
+		classifier:       classifier,
+		normalizer:       matching.NewRuleBasedNormalizer(nil),
+	}
+}
+
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *GenerateShoppingListCommand) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+}
+
+// Execute generates and stores the shopping list for the week containing day.
+// It returns shared.ErrMealPlanNotFound if nothing is planned that week.
+func (c *GenerateShoppingListCommand) Execute(ctx context.Context, userID shared.ID, day time.Time) (*shopping.List, error) {
+	return c.ExecuteFor(ctx, userID, userID, day)
+}
+
+// ExecuteFor generates the shopping list of the user's meal plan for another pantry,
+// such as the one a group shares, and stores it as the pantry owner's list
+func (c *GenerateShoppingListCommand) ExecuteFor(ctx context.Context, userID, ownerID shared.ID, day time.Time) (*shopping.List, error) {
+	plan, err := c.mealPlanRepo.FindByWeek(ctx, userID, mealplan.WeekStart(day))
+	if err != nil {
+		if errors.Is(err, shared.ErrMealPlanNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get meal plan: %w", err)
+	}
+	if plan.IsEmpty() {
+		return nil, shared.ErrMealPlanNotFound
+	}
+
+	// Aggregate ingredients across planned recipes
+	builder := shopping.NewBuilder(c.normalizer)
+	for _, entry := range plan.Entries() {
+		rec, err := c.recipeRepo.FindByID(ctx, entry.RecipeID)
+		if err != nil {
+			log.Printf("Skipping planned recipe %s: %v", entry.RecipeID, err)
+			continue
+		}
+
+		scale := servingsScale(rec, entry.Servings)
+		for _, ing := range rec.Ingredients() {
+			builder.Add(ing, scale)
+		}
+	}
+
+	// Skip what is already in the pantry
+	pantry, err := c.userRepo.GetPantry(ctx, user.UserID(ownerID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pantry: %w", err)
+	}
+	builder.RemovePantry(pantry)
+
+	items := builder.Items()
+	c.classify(ctx, items)
+
+	list, err := shopping.NewList(ownerID, plan.WeekStart(), items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shopping list: %w", err)
+	}
+
+	if err := c.shoppingListRepo.Save(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to save shopping list: %w", err)
+	}
+
+	return list, nil
+}
+
+// AddRecipe puts the ingredients of a recipe on the pantry owner's current shopping
+// list, skipping what is already in the pantry. A list is started for the week
+// containing day when there is none yet. It returns the list and how many
+// ingredients were added.
+func (c *GenerateShoppingListCommand) AddRecipe(ctx context.Context, userID, ownerID shared.ID, recipeID shared.ID, day time.Time) (*shopping.List, int, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != userID {
+		return nil, 0, shared.ErrRecipeNotFound
+	}
+
+	builder := shopping.NewBuilder(c.normalizer)
+	for _, ing := range rec.Ingredients() {
+		builder.Add(ing, 1)
+	}
+
+	pantry, err := c.userRepo.GetPantry(ctx, user.UserID(ownerID))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get pantry: %w", err)
+	}
+	builder.RemovePantry(pantry)
+
+	items := builder.Items()
+	c.classify(ctx, items)
+
+	list, err := c.shoppingListRepo.Modify(ctx, ownerID, func(list *shopping.List) error {
+		list.Add(items)
+		return nil
+	})
+	if errors.Is(err, shared.ErrShoppingListNotFound) {
+		if list, err = shopping.NewList(ownerID, mealplan.WeekStart(day), items); err != nil {
+			return nil, 0, fmt.Errorf("failed to create shopping list: %w", err)
+		}
+		err = c.shoppingListRepo.Save(ctx, list)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to update shopping list: %w", err)
+	}
+
+	return list, len(items), nil
+}
+
+// ToggleItem checks or unchecks an item on the user's current shopping list
+func (c *GenerateShoppingListCommand) ToggleItem(ctx context.Context, userID shared.ID, index int) (*shopping.List, error) {
+	list, err := c.shoppingListRepo.Modify(ctx, userID, func(list *shopping.List) error {
+		if err := list.Toggle(index); err != nil {
+			return fmt.Errorf("invalid shopping list item: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, shared.ErrShoppingListNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update shopping list: %w", err)
+	}
+
+	return list, nil
+}
+
+// classify assigns aisles in place. Classification is best-effort: when the
+// classifier is missing or fails, items stay under "other".
+func (c *GenerateShoppingListCommand) classify(ctx context.Context, items []shopping.Item) {
+	if c.classifier == nil || len(items) == 0 {
+		return
+	}
+
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+
+	aisles, err := c.classifier.ClassifyAisles(ctx, names)
+	if err != nil {
+		log.Printf("Aisle classification failed, listing items unsorted: %v", err)
+		return
+	}
+
+	for i := range items {
+		if aisle, ok := aisles[items[i].Name]; ok {
+			items[i].Aisle = aisle
+		}
+	}
+}
+
+// servingsScale returns the factor to apply to a recipe's quantities to cook the planned servings
+func servingsScale(rec *recipe.Recipe, planned int) float64 {
+	if planned <= 0 || rec.Servings() == nil || *rec.Servings() <= 0 {
+		return 1
+	}
+	return float64(planned) / float64(*rec.Servings())
+}
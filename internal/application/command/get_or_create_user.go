@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"receipt-bot/internal/domain/shared"
@@ -25,8 +26,9 @@ func (c *GetOrCreateUserCommand) Execute(ctx context.Context, telegramID int64,
 	// Try to find existing user
 	existingUser, err := c.userRepo.FindByTelegramID(ctx, telegramID)
 	if err == nil {
-		// User exists, update username if changed
-		if existingUser.Username() != username && username != "" {
+		// User exists, update username if changed. A linked account has a
+		// username of its own, which must not replace the owner's
+		if existingUser.TelegramID() == telegramID && existingUser.Username() != username && username != "" {
 			existingUser.UpdateUsername(username)
 			if err := c.userRepo.Update(ctx, existingUser); err != nil {
 				// Log error but don't fail - username update is not critical
@@ -53,3 +55,23 @@ func (c *GetOrCreateUserCommand) Execute(ctx context.Context, telegramID int64,
 	// Other error occurred
 	return nil, fmt.Errorf("failed to find user: %w", err)
 }
+
+// Household gets or creates the account a group chat shares
+func (c *GetOrCreateUserCommand) Household(ctx context.Context, chatID int64, title string) (*user.User, error) {
+	household, err := c.userRepo.FindByTelegramID(ctx, chatID)
+	if err == nil {
+		return household, nil
+	}
+	if !errors.Is(err, shared.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to find household: %w", err)
+	}
+
+	household, err = user.NewHousehold(chatID, title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create household: %w", err)
+	}
+	if err := c.userRepo.Save(ctx, household); err != nil {
+		return nil, fmt.Errorf("failed to save household: %w", err)
+	}
+	return household, nil
+}
@@ -0,0 +1,159 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/bookmark"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// MaxBookmarkImport caps the links imported from one bookmark file
+const MaxBookmarkImport = 300
+
+// ImportResult is the outcome of a bookmark import
+type ImportResult struct {
+	Saved    []*recipe.Recipe
+	Skipped  int // compilations, which need the user to pick recipes one link at a time
+	Failed   []string
+	Canceled bool
+}
+
+// ImportBookmarksCommand imports the recipe links found in a user's browser bookmarks.
+// Links are previewed first, then processed one at a time with a pause between them
+// so a large import does not exhaust the scraper or the LLM quota.
+// Each user has at most one import, kept in memory.
+type ImportBookmarksCommand struct {
+	processRecipeLink *ProcessRecipeLinkCommand // without a messenger, so links are processed quietly
+	selector          *bookmark.Selector
+	interval          time.Duration
+	wait              func(ctx context.Context, d time.Duration) error
+
+	mu      sync.Mutex
+	pending map[shared.ID][]string
+	running map[shared.ID]context.CancelFunc // stops the running import
+}
+
+// NewImportBookmarksCommand creates a new command that processes a link every interval
+func NewImportBookmarksCommand(processRecipeLink *ProcessRecipeLinkCommand, selector *bookmark.Selector, interval time.Duration) *ImportBookmarksCommand {
+	return &ImportBookmarksCommand{
+		processRecipeLink: processRecipeLink,
+		selector:          selector,
+		interval:          interval,
+		wait: func(ctx context.Context, d time.Duration) error {
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+				return nil
+			}
+		},
+		pending: make(map[shared.ID][]string),
+		running: make(map[shared.ID]context.CancelFunc),
+	}
+}
+
+// Interval returns the pause between two links of an import
+func (c *ImportBookmarksCommand) Interval() time.Duration {
+	return c.interval
+}
+
+// Preview picks the recipe links out of the bookmarks and keeps them until the user
+// starts the import, replacing any previous preview. At most MaxBookmarkImport links
+// are kept. It returns shared.ErrNoRecipeLinks if no bookmark looks like a recipe.
+func (c *ImportBookmarksCommand) Preview(userID shared.ID, bookmarks []bookmark.Bookmark) ([]string, error) {
+	links := c.selector.RecipeLinks(bookmarks)
+	if len(links) == 0 {
+		return nil, shared.ErrNoRecipeLinks
+	}
+	if len(links) > MaxBookmarkImport {
+		links = links[:MaxBookmarkImport]
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.running[userID]; ok {
+		return nil, shared.ErrImportRunning
+	}
+	c.pending[userID] = links
+	return links, nil
+}
+
+// Run processes the previewed links, calling progress after each one. The import
+// stops when the user cancels it or ctx is canceled, interrupting the current link.
+// It returns shared.ErrNoPendingImport without a preview and shared.ErrImportRunning
+// if the user's previous import has not finished.
+func (c *ImportBookmarksCommand) Run(ctx context.Context, userID shared.ID, progress func(done, total int)) (*ImportResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c.mu.Lock()
+	if _, ok := c.running[userID]; ok {
+		c.mu.Unlock()
+		return nil, shared.ErrImportRunning
+	}
+	links, ok := c.pending[userID]
+	if !ok {
+		c.mu.Unlock()
+		return nil, shared.ErrNoPendingImport
+	}
+	delete(c.pending, userID)
+	c.running[userID] = cancel
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.running, userID)
+		c.mu.Unlock()
+	}()
+
+	result := &ImportResult{}
+	for i, link := range links {
+		if i > 0 && c.interval > 0 {
+			if err := c.wait(ctx, c.interval); err != nil {
+				result.Canceled = true
+				return result, nil
+			}
+		}
+		if ctx.Err() != nil {
+			result.Canceled = true
+			return result, nil
+		}
+
+		rec, err := c.processRecipeLink.Execute(ctx, link, recipe.UserID(userID), 0)
+		switch {
+		case ctx.Err() != nil:
+			// The link was interrupted rather than failed
+			result.Canceled = true
+			return result, nil
+		case errors.Is(err, shared.ErrMultipleRecipes):
+			result.Skipped++
+		case err != nil:
+			result.Failed = append(result.Failed, link)
+		default:
+			result.Saved = append(result.Saved, rec)
+		}
+
+		if progress != nil {
+			progress(i+1, len(links))
+		}
+	}
+
+	return result, nil
+}
+
+// Cancel drops the user's preview, or stops their running import, interrupting the current link
+func (c *ImportBookmarksCommand) Cancel(userID shared.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, userID)
+	if cancel, ok := c.running[userID]; ok {
+		cancel()
+	}
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/bookmark"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+func newTestImportBookmarksCommand() *ImportBookmarksCommand {
+	scraper := &mockScraperPort{result: &ports.ScrapeResult{
+		Captions: "Pancakes", Transcript: "Mix and fry", Metadata: map[string]string{},
+	}}
+	llm := &mockLLMPort{extraction: &ports.RecipeExtraction{
+		Title:        "Pancakes",
+		Ingredients:  []ports.IngredientData{{Name: "flour", Quantity: "200g"}},
+		Instructions: []ports.InstructionData{{StepNumber: 1, Text: "Mix and fry"}},
+	}}
+	process := NewProcessRecipeLinkCommand(scraper, llm, recipe.NewService(), newMockRecipeRepository(), nil)
+	return NewImportBookmarksCommand(process, bookmark.NewSelector(nil), time.Minute)
+}
+
+func TestImportBookmarksCommand_Run(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+	cmd := newTestImportBookmarksCommand()
+
+	var waits []time.Duration
+	cmd.wait = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	if _, err := cmd.Run(ctx, userID, nil); !errors.Is(err, shared.ErrNoPendingImport) {
+		t.Fatalf("Run() without preview error = %v, want %v", err, shared.ErrNoPendingImport)
+	}
+
+	links, err := cmd.Preview(userID, []bookmark.Bookmark{
+		{URL: "https://www.allrecipes.com/recipe/1/"},
+		{URL: "https://www.example.com/weather"},
+		{URL: "https://food52.com/recipes/2"},
+	})
+	if err != nil || len(links) != 2 {
+		t.Fatalf("Preview() = %v, %v, want 2 links", links, err)
+	}
+
+	var done []int
+	result, err := cmd.Run(ctx, userID, func(n, total int) { done = append(done, n) })
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Saved) != 2 || len(result.Failed) != 0 || result.Canceled {
+		t.Errorf("Run() = %+v, want both links saved", result)
+	}
+	if len(waits) != 1 || waits[0] != time.Minute {
+		t.Errorf("waited %v, want one pause of a minute between the two links", waits)
+	}
+	if len(done) != 2 || done[1] != 2 {
+		t.Errorf("progress = %v, want a call after each link", done)
+	}
+}
+
+func TestImportBookmarksCommand_Cancel(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+	cmd := newTestImportBookmarksCommand()
+
+	// The user stops the import during the pause after the first link
+	cmd.wait = func(ctx context.Context, d time.Duration) error {
+		cmd.Cancel(userID)
+		return nil
+	}
+
+	_, _ = cmd.Preview(userID, []bookmark.Bookmark{
+		{URL: "https://www.allrecipes.com/recipe/1/"},
+		{URL: "https://food52.com/recipes/2"},
+	})
+	result, err := cmd.Run(ctx, userID, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Canceled || len(result.Saved) != 1 {
+		t.Errorf("Run() = %+v, want it stopped after the first link", result)
+	}
+
+	if _, err := cmd.Preview(userID, nil); !errors.Is(err, shared.ErrNoRecipeLinks) {
+		t.Errorf("Preview() without recipes error = %v, want %v", err, shared.ErrNoRecipeLinks)
+	}
+}
+
+func TestImportBookmarksCommand_Shutdown(t *testing.T) {
+	ctx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+	userID := shared.NewID()
+	cmd := newTestImportBookmarksCommand()
+
+	// The bot shuts down during the pause after the first link
+	cmd.wait = func(context.Context, time.Duration) error {
+		shutdown()
+		return nil
+	}
+
+	_, _ = cmd.Preview(userID, []bookmark.Bookmark{
+		{URL: "https://www.allrecipes.com/recipe/1/"},
+		{URL: "https://food52.com/recipes/2"},
+	})
+	result, err := cmd.Run(ctx, userID, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Canceled || len(result.Saved) != 1 || len(result.Failed) != 0 {
+		t.Errorf("Run() = %+v, want it stopped after the first link", result)
+	}
+}
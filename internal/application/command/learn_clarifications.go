@@ -0,0 +1,55 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// errNothingLearned stops a change to the clarification choices that changes nothing,
+// so nothing is saved
+var errNothingLearned = errors.New("clarification choices unchanged")
+
+// LearnClarificationsCommand remembers how a user answers clarifying questions,
+// so a question they always answer the same way can be skipped
+type LearnClarificationsCommand struct {
+	userRepo user.Repository
+}
+
+// NewLearnClarificationsCommand creates a new command
+func NewLearnClarificationsCommand(userRepo user.Repository) *LearnClarificationsCommand {
+	return &LearnClarificationsCommand{
+		userRepo: userRepo,
+	}
+}
+
+// Record records the answer to the question asked about a message
+func (c *LearnClarificationsCommand) Record(ctx context.Context, userID shared.ID, message, choice string) error {
+	_, err := c.userRepo.ModifyClarificationChoices(ctx, user.UserID(userID), func(u *user.User) error {
+		if !u.RecordClarificationChoice(message, choice) {
+			return errNothingLearned
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNothingLearned) {
+		return fmt.Errorf("failed to record clarification choice: %w", err)
+	}
+	return nil
+}
+
+// Forget forgets the usual answer to the question asked about a message, so it is asked again
+func (c *LearnClarificationsCommand) Forget(ctx context.Context, userID shared.ID, message string) error {
+	_, err := c.userRepo.ModifyClarificationChoices(ctx, user.UserID(userID), func(u *user.User) error {
+		if !u.ForgetClarificationChoice(message) {
+			return errNothingLearned
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNothingLearned) {
+		return fmt.Errorf("failed to forget clarification choice: %w", err)
+	}
+	return nil
+}
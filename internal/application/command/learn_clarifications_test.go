@@ -0,0 +1,53 @@
+package command
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+func TestLearnClarifications_ConcurrentAnswersAreAllCounted(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository()
+	usr, _ := user.NewUser(42, "cook")
+	_ = repo.Save(ctx, usr)
+	cmd := NewLearnClarificationsCommand(repo)
+	userID := shared.ID(usr.ID())
+
+	// Answers from messages handled at the same time all count towards the usual choice
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cmd.Record(ctx, userID, "pasta", "search"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	saved, _ := repo.FindByID(ctx, usr.ID())
+	choices := saved.ClarificationChoices()
+	if len(choices) != 1 || choices[0].Count != 20 {
+		t.Fatalf("clarification choices = %+v, want one answered 20 times", choices)
+	}
+
+	// Nothing to record or forget is not an error
+	if err := cmd.Record(ctx, userID, "pasta", " "); err != nil {
+		t.Errorf("Record() of an empty answer error = %v", err)
+	}
+	if err := cmd.Forget(ctx, userID, "soup"); err != nil {
+		t.Errorf("Forget() of an unknown message error = %v", err)
+	}
+	if err := cmd.Forget(ctx, userID, "pasta"); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+	if saved, _ := repo.FindByID(ctx, usr.ID()); len(saved.ClarificationChoices()) != 0 {
+		t.Errorf("clarification choices after Forget() = %+v, want none", saved.ClarificationChoices())
+	}
+}
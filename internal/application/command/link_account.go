@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// LinkAccountCommand links several Telegram accounts to one user's collection
+// using one-time codes
+type LinkAccountCommand struct {
+	userRepo     user.Repository
+	linkCodeRepo user.LinkCodeRepository
+}
+
+// NewLinkAccountCommand creates a new command
+func NewLinkAccountCommand(userRepo user.Repository, linkCodeRepo user.LinkCodeRepository) *LinkAccountCommand {
+	return &LinkAccountCommand{
+		userRepo:     userRepo,
+		linkCodeRepo: linkCodeRepo,
+	}
+}
+
+// CreateCode creates a one-time code another account can redeem to join the user's collection
+func (c *LinkAccountCommand) CreateCode(ctx context.Context, userID user.UserID, now time.Time) (*user.LinkCode, error) {
+	code, err := user.NewLinkCode(userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.linkCodeRepo.SaveLinkCode(ctx, code); err != nil {
+		return nil, fmt.Errorf("failed to save link code: %w", err)
+	}
+
+	return code, nil
+}
+
+// Redeem links a Telegram account to the collection of the user who created the code.
+// The account's previous collection is kept and comes back if it is unlinked.
+// Returns the user the account now belongs to
+func (c *LinkAccountCommand) Redeem(ctx context.Context, code string, telegramID int64, now time.Time) (*user.User, error) {
+	linkCode, err := c.linkCodeRepo.TakeLinkCode(ctx, user.NormalizeLinkCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if linkCode.IsExpired(now) {
+		return nil, shared.ErrInvalidLinkCode
+	}
+
+	// An account can only be linked to one collection at a time
+	current, err := c.userRepo.FindByTelegramID(ctx, telegramID)
+	if err != nil && !errors.Is(err, shared.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if current != nil && current.ID() != linkCode.UserID && current.TelegramID() != telegramID {
+		_, err := c.userRepo.ModifyLinkedTelegramIDs(ctx, current.ID(), func(u *user.User) error {
+			return u.UnlinkTelegramID(telegramID)
+		})
+		if err != nil && !errors.Is(err, shared.ErrNotLinked) {
+			return nil, fmt.Errorf("failed to unlink account: %w", err)
+		}
+	}
+
+	// Only the linked accounts are written, so changes made to the owner's
+	// pantry or settings in the meantime are kept
+	owner, err := c.userRepo.ModifyLinkedTelegramIDs(ctx, linkCode.UserID, func(u *user.User) error {
+		return u.LinkTelegramID(telegramID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to link account: %w", err)
+	}
+
+	return owner, nil
+}
+
+// Unlink detaches a linked Telegram account from the collection it shares.
+// A user's own account cannot be unlinked
+func (c *LinkAccountCommand) Unlink(ctx context.Context, telegramID int64) error {
+	u, err := c.userRepo.FindByTelegramID(ctx, telegramID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	_, err = c.userRepo.ModifyLinkedTelegramIDs(ctx, u.ID(), func(u *user.User) error {
+		return u.UnlinkTelegramID(telegramID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unlink account: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// pantryChangingUsers adds to the owner's pantry whenever an account is looked
+// up, like a pantry change arriving while a link code is redeemed
+type pantryChangingUsers struct {
+	*memory.UserRepository
+	owner user.UserID
+}
+
+func (r *pantryChangingUsers) FindByTelegramID(ctx context.Context, telegramID int64) (*user.User, error) {
+	if _, err := r.ModifyPantry(ctx, r.owner, func(items []string) []string { return append(items, "saffron") }); err != nil {
+		return nil, err
+	}
+	return r.UserRepository.FindByTelegramID(ctx, telegramID)
+}
+
+func TestLinkAccount_KeepsConcurrentChanges(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	owner, _ := user.NewUser(1001, "alice")
+	users := &pantryChangingUsers{UserRepository: memory.NewUserRepository(), owner: owner.ID()}
+	if err := users.Save(ctx, owner); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewLinkAccountCommand(users, memory.NewLinkCodeRepository())
+
+	code, err := cmd.CreateCode(ctx, owner.ID(), now)
+	if err != nil {
+		t.Fatalf("CreateCode() error = %v", err)
+	}
+	if _, err := cmd.Redeem(ctx, code.Code, 2002, now); err != nil {
+		t.Fatalf("Redeem() error = %v", err)
+	}
+	if err := cmd.Unlink(ctx, 2002); err != nil {
+		t.Fatalf("Unlink() error = %v", err)
+	}
+
+	saved, err := users.FindByID(ctx, owner.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.PantryItems()) != 2 {
+		t.Errorf("pantry = %v, want both items added while linking and unlinking", saved.PantryItems())
+	}
+	if len(saved.LinkedTelegramIDs()) != 0 {
+		t.Errorf("linked accounts = %v, want none after unlinking", saved.LinkedTelegramIDs())
+	}
+}
+
+func TestLinkAccount_Redeem(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	users := memory.NewUserRepository()
+	owner, _ := user.NewUser(1001, "alice")
+	other, _ := user.NewUser(3003, "carol")
+	for _, u := range []*user.User{owner, other} {
+		if err := users.Save(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := NewLinkAccountCommand(users, memory.NewLinkCodeRepository())
+
+	redeem := func(creator user.UserID, telegramID int64) (*user.User, error) {
+		t.Helper()
+		code, err := cmd.CreateCode(ctx, creator, now)
+		if err != nil {
+			t.Fatalf("CreateCode() error = %v", err)
+		}
+		return cmd.Redeem(ctx, code.Code, telegramID, now)
+	}
+
+	linked, err := redeem(owner.ID(), 2002)
+	if err != nil {
+		t.Fatalf("Redeem() error = %v", err)
+	}
+	if !linked.HasTelegramID(2002) {
+		t.Errorf("Redeem() returned linked accounts %v, want 2002", linked.LinkedTelegramIDs())
+	}
+	if _, err := redeem(owner.ID(), 2002); !errors.Is(err, shared.ErrAlreadyLinked) {
+		t.Errorf("Redeem() again error = %v, want ErrAlreadyLinked", err)
+	}
+
+	// Joining another collection leaves the first one
+	if _, err := redeem(other.ID(), 2002); err != nil {
+		t.Fatalf("Redeem() of another collection error = %v", err)
+	}
+	saved, _ := users.FindByID(ctx, owner.ID())
+	if saved.HasTelegramID(2002) {
+		t.Error("account is still linked to its previous collection")
+	}
+
+	if err := cmd.Unlink(ctx, other.TelegramID()); !errors.Is(err, shared.ErrNotLinked) {
+		t.Errorf("Unlink() of an own account error = %v, want ErrNotLinked", err)
+	}
+}
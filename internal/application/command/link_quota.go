@@ -0,0 +1,75 @@
+package command
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/feature"
+	"receipt-bot/internal/domain/shared"
+)
+
+// linkQuotaWindow is the period link quotas count links over
+const linkQuotaWindow = time.Hour
+
+// LinkQuota limits how many recipe links each user can send an hour, with a
+// higher limit for premium users. Counts are kept in memory, so they start
+// over when the bot restarts.
+type LinkQuota struct {
+	entitlements feature.Entitlements
+
+	mu      sync.Mutex
+	free    int // 0 = unlimited
+	premium int // 0 = unlimited
+	sent    map[shared.ID][]time.Time
+}
+
+// NewLinkQuota creates a new quota. entitlements may be nil, in which case every
+// user gets the free limit.
+func NewLinkQuota(free, premium int, entitlements feature.Entitlements) *LinkQuota {
+	return &LinkQuota{
+		entitlements: entitlements,
+		free:         free,
+		premium:      premium,
+		sent:         make(map[shared.ID][]time.Time),
+	}
+}
+
+// SetLimits changes the limits, e.g. when the configuration is reloaded
+func (q *LinkQuota) SetLimits(free, premium int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.free, q.premium = free, premium
+}
+
+// Allow counts a link from the user. Returns 0 if they are within their limit,
+// otherwise how long until they can send another; links over the limit are not counted.
+func (q *LinkQuota) Allow(ctx context.Context, userID shared.ID, now time.Time) time.Duration {
+	q.mu.Lock()
+	limit := q.free
+	premium := q.premium
+	q.mu.Unlock()
+
+	// Looking up entitlements may hit the database, so it is done outside the lock
+	if q.entitlements != nil && limit > 0 && q.entitlements.IsPremium(ctx, userID) {
+		limit = premium
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	recent := q.sent[userID][:0]
+	for _, at := range q.sent[userID] {
+		if now.Sub(at) < linkQuotaWindow {
+			recent = append(recent, at)
+		}
+	}
+
+	if limit > 0 && len(recent) >= limit {
+		q.sent[userID] = recent
+		return recent[0].Add(linkQuotaWindow).Sub(now)
+	}
+
+	q.sent[userID] = append(recent, now)
+	return 0
+}
@@ -0,0 +1,149 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/nutrition"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// MacroBudget is what is left of a user's daily macro goals after the meals they logged today
+type MacroBudget struct {
+	Goals     *nutrition.Goals
+	Meals     []*nutrition.Meal // logged today, oldest first
+	Remaining nutrition.Facts
+}
+
+// DinnerSuggestion is the recipe whose estimated serving best fits what is left of
+// a user's macro goals for the day
+type DinnerSuggestion struct {
+	Estimate *NutritionEstimate
+	Budget   *MacroBudget
+	After    nutrition.Facts // what would be left after a serving
+	Fits     bool            // the serving stays within every tracked macro
+}
+
+// Goals returns a user's daily macro goals, or shared.ErrNoMacroGoals
+func (c *EstimateNutritionCommand) Goals(ctx context.Context, userID shared.ID) (*nutrition.Goals, error) {
+	return c.nutritionRepo.FindGoals(ctx, userID)
+}
+
+// SetGoals sets a user's daily macro goals
+func (c *EstimateNutritionCommand) SetGoals(ctx context.Context, userID shared.ID, daily nutrition.Facts, now time.Time) (*nutrition.Goals, error) {
+	goals, err := nutrition.NewGoals(userID, daily, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.nutritionRepo.SaveGoals(ctx, goals); err != nil {
+		return nil, fmt.Errorf("failed to save macro goals: %w", err)
+	}
+	return goals, nil
+}
+
+// ClearGoals stops tracking a user's macros
+func (c *EstimateNutritionCommand) ClearGoals(ctx context.Context, userID shared.ID) error {
+	if err := c.nutritionRepo.DeleteGoals(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete macro goals: %w", err)
+	}
+	return nil
+}
+
+// LogMeal logs a serving of a recipe as eaten at now, with its nutrition estimated
+// from the pantry products, and returns the estimate. A recipe whose nutrition is
+// unknown is logged all the same, counting for nothing.
+func (c *EstimateNutritionCommand) LogMeal(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID, now time.Time) (*NutritionEstimate, error) {
+	estimate, err := c.EstimateRecipe(ctx, userID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	meal, err := nutrition.NewMeal(userID, recipeID, estimate.Recipe.Title(), estimate.Serving(), now)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.nutritionRepo.SaveMeal(ctx, meal); err != nil {
+		return nil, fmt.Errorf("failed to log meal: %w", err)
+	}
+	return estimate, nil
+}
+
+// Budget returns what is left of a user's macro goals on the day of now, which
+// starts at midnight in now's time zone. Returns shared.ErrNoMacroGoals when the
+// user has none.
+func (c *EstimateNutritionCommand) Budget(ctx context.Context, userID shared.ID, now time.Time) (*MacroBudget, error) {
+	goals, err := c.nutritionRepo.FindGoals(ctx, userID)
+	if err != nil {
+		if errors.Is(err, shared.ErrNoMacroGoals) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get macro goals: %w", err)
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	meals, err := c.nutritionRepo.FindMeals(ctx, userID, midnight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meals: %w", err)
+	}
+
+	return &MacroBudget{Goals: goals, Meals: meals, Remaining: goals.Remaining(meals)}, nil
+}
+
+// SuggestDinner picks the recipe whose estimated serving best fits what is left of
+// a user's macro goals on the day of now, preferring the ones that stay within
+// every tracked macro. Only recipes not eaten today with an ingredient matched to
+// a scanned product are considered. Returns nil when the user has no goals or no
+// recipe can be estimated.
+func (c *EstimateNutritionCommand) SuggestDinner(ctx context.Context, userID shared.ID, now time.Time) (*DinnerSuggestion, error) {
+	budget, err := c.Budget(ctx, userID, now)
+	if errors.Is(err, shared.ErrNoMacroGoals) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byName, err := c.productsByName(ctx, userID)
+	if err != nil || len(byName) == 0 {
+		return nil, err
+	}
+
+	recipes, err := c.recipeRepo.FindByUserID(ctx, recipe.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipes: %w", err)
+	}
+
+	eaten := make(map[shared.ID]bool, len(budget.Meals))
+	for _, meal := range budget.Meals {
+		eaten[meal.RecipeID] = true
+	}
+
+	var best *DinnerSuggestion
+	var bestMisfit float64
+	for _, rec := range recipes {
+		if eaten[rec.ID()] {
+			continue
+		}
+		estimate := c.estimate(rec, byName)
+		if len(estimate.Grounded) == 0 {
+			continue
+		}
+
+		serving := estimate.Serving()
+		fits := budget.Goals.Fits(budget.Remaining, serving)
+		misfit := budget.Goals.Misfit(budget.Remaining, serving)
+		if best == nil || (fits && !best.Fits) || (fits == best.Fits && misfit < bestMisfit) {
+			best = &DinnerSuggestion{Estimate: estimate, Budget: budget, Fits: fits}
+			bestMisfit = misfit
+		}
+	}
+
+	if best != nil {
+		meals := append(append([]*nutrition.Meal{}, budget.Meals...), &nutrition.Meal{Facts: best.Estimate.Serving()})
+		best.After = budget.Goals.Remaining(meals)
+	}
+	return best, nil
+}
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/freezer"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ManageFreezerCommand keeps track of the portions users freeze and eat
+type ManageFreezerCommand struct {
+	freezerRepo freezer.Repository
+	recipeRepo  recipe.Repository
+}
+
+// NewManageFreezerCommand creates a new command
+func NewManageFreezerCommand(freezerRepo freezer.Repository, recipeRepo recipe.Repository) *ManageFreezerCommand {
+	return &ManageFreezerCommand{
+		freezerRepo: freezerRepo,
+		recipeRepo:  recipeRepo,
+	}
+}
+
+// Contents returns the user's freezer, empty if nothing was frozen yet
+func (c *ManageFreezerCommand) Contents(ctx context.Context, userID shared.ID) (*freezer.Freezer, error) {
+	return c.findOrCreate(ctx, userID)
+}
+
+// Freeze records portions of a recipe frozen at frozenAt
+func (c *ManageFreezerCommand) Freeze(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID, portions int, frozenAt time.Time) (*freezer.Freezer, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("recipe not found: %w", err)
+	}
+
+	// Verify ownership
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	f, err := c.findOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.Add(recipeID, rec.Title(), portions, frozenAt); err != nil {
+		return nil, fmt.Errorf("failed to add to freezer: %w", err)
+	}
+
+	if err := c.freezerRepo.Save(ctx, f); err != nil {
+		return nil, fmt.Errorf("failed to save freezer: %w", err)
+	}
+
+	return f, nil
+}
+
+// Take removes eaten portions of a recipe, oldest first, and returns how many were taken
+func (c *ManageFreezerCommand) Take(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID, portions int) (*freezer.Freezer, int, error) {
+	f, err := c.findOrCreate(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	taken, err := f.Take(recipeID, portions)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := c.freezerRepo.Save(ctx, f); err != nil {
+		return nil, 0, fmt.Errorf("failed to save freezer: %w", err)
+	}
+
+	return f, taken, nil
+}
+
+// EatFirst returns the frozen batches the user should eat soon, oldest first
+func (c *ManageFreezerCommand) EatFirst(ctx context.Context, userID shared.ID, now time.Time) ([]freezer.Batch, error) {
+	f, err := c.findOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return f.EatFirst(now), nil
+}
+
+func (c *ManageFreezerCommand) findOrCreate(ctx context.Context, userID shared.ID) (*freezer.Freezer, error) {
+	f, err := c.freezerRepo.FindByUser(ctx, userID)
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, shared.ErrFreezerNotFound) {
+		return nil, fmt.Errorf("failed to get freezer: %w", err)
+	}
+	return freezer.NewFreezer(userID)
+}
@@ -0,0 +1,127 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/mealplan"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ManageMealPlanCommand handles weekly meal plan operations
+type ManageMealPlanCommand struct {
+	mealPlanRepo mealplan.Repository
+	recipeRepo   recipe.Repository
+}
+
+// NewManageMealPlanCommand creates a new command
+func NewManageMealPlanCommand(mealPlanRepo mealplan.Repository, recipeRepo recipe.Repository) *ManageMealPlanCommand {
+	return &ManageMealPlanCommand{
+		mealPlanRepo: mealPlanRepo,
+		recipeRepo:   recipeRepo,
+	}
+}
+
+// GetPlan returns the plan for the week containing day
+func (c *ManageMealPlanCommand) GetPlan(ctx context.Context, userID shared.ID, day time.Time) (*dto.MealPlanDTO, error) {
+	plan, err := c.findOrCreate(ctx, userID, day)
+	if err != nil {
+		return nil, err
+	}
+	return c.toDTO(ctx, plan), nil
+}
+
+// AddRecipe plans a recipe on a weekday of the week containing day.
+// A servings value of 0 keeps the recipe's own servings.
+func (c *ManageMealPlanCommand) AddRecipe(ctx context.Context, userID shared.ID, day time.Time, weekday time.Weekday, recipeID recipe.RecipeID, servings int) (*dto.MealPlanDTO, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("recipe not found: %w", err)
+	}
+
+	// Verify ownership
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	plan, err := c.findOrCreate(ctx, userID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := plan.Add(weekday, recipeID, servings); err != nil {
+		return nil, fmt.Errorf("failed to add recipe to plan: %w", err)
+	}
+
+	if err := c.mealPlanRepo.Save(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to save meal plan: %w", err)
+	}
+
+	return c.toDTO(ctx, plan), nil
+}
+
+// RemoveDay removes everything planned on a weekday of the week containing day
+func (c *ManageMealPlanCommand) RemoveDay(ctx context.Context, userID shared.ID, day time.Time, weekday time.Weekday) (*dto.MealPlanDTO, error) {
+	plan, err := c.findOrCreate(ctx, userID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.RemoveDay(weekday) > 0 {
+		if err := c.mealPlanRepo.Save(ctx, plan); err != nil {
+			return nil, fmt.Errorf("failed to save meal plan: %w", err)
+		}
+	}
+
+	return c.toDTO(ctx, plan), nil
+}
+
+// Clear removes everything planned in the week containing day
+func (c *ManageMealPlanCommand) Clear(ctx context.Context, userID shared.ID, day time.Time) error {
+	plan, err := c.findOrCreate(ctx, userID, day)
+	if err != nil {
+		return err
+	}
+
+	plan.Clear()
+	if err := c.mealPlanRepo.Save(ctx, plan); err != nil {
+		return fmt.Errorf("failed to save meal plan: %w", err)
+	}
+	return nil
+}
+
+func (c *ManageMealPlanCommand) findOrCreate(ctx context.Context, userID shared.ID, day time.Time) (*mealplan.MealPlan, error) {
+	plan, err := c.mealPlanRepo.FindByWeek(ctx, userID, mealplan.WeekStart(day))
+	if err == nil {
+		return plan, nil
+	}
+	if !errors.Is(err, shared.ErrMealPlanNotFound) {
+		return nil, fmt.Errorf("failed to get meal plan: %w", err)
+	}
+	return mealplan.NewMealPlan(userID, day)
+}
+
+func (c *ManageMealPlanCommand) toDTO(ctx context.Context, plan *mealplan.MealPlan) *dto.MealPlanDTO {
+	result := &dto.MealPlanDTO{
+		WeekStart: plan.WeekStart(),
+		Entries:   make([]dto.MealPlanEntryDTO, 0, len(plan.Entries())),
+	}
+
+	for _, e := range plan.Entries() {
+		entry := dto.MealPlanEntryDTO{
+			Day:      e.Day,
+			RecipeID: e.RecipeID.String(),
+			Servings: e.Servings,
+		}
+		if rec, err := c.recipeRepo.FindByID(ctx, e.RecipeID); err == nil {
+			entry.Title = rec.Title()
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result
+}
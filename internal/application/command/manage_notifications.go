@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// ManageNotificationsCommand reads and changes which notifications a user receives
+// and when: their quiet hours, time zone and locale. It also sets whether their
+// messages are written plainly and whether their recipes keep their transcripts.
+type ManageNotificationsCommand struct {
+	userRepo user.Repository
+}
+
+// NewManageNotificationsCommand creates a new command
+func NewManageNotificationsCommand(userRepo user.Repository) *ManageNotificationsCommand {
+	return &ManageNotificationsCommand{
+		userRepo: userRepo,
+	}
+}
+
+// Settings returns whether each kind of notification is on for the user, defaults included
+func (c *ManageNotificationsCommand) Settings(ctx context.Context, userID shared.ID) (map[user.Notification]bool, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return effectiveSettings(usr), nil
+}
+
+// Toggle turns a kind of notification on if it was off and off if it was on.
+// Returns the updated settings.
+func (c *ManageNotificationsCommand) Toggle(ctx context.Context, userID shared.ID, n user.Notification) (map[user.Notification]bool, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := usr.SetNotification(n, !usr.WantsNotification(n)); err != nil {
+		return nil, err
+	}
+
+	if err := c.userRepo.UpdateNotificationSettings(ctx, usr.ID(), usr.NotificationSettings()); err != nil {
+		return nil, fmt.Errorf("failed to save notification settings: %w", err)
+	}
+
+	return effectiveSettings(usr), nil
+}
+
+// Set turns a kind of notification on or off
+func (c *ManageNotificationsCommand) Set(ctx context.Context, userID shared.ID, n user.Notification, enabled bool) error {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := usr.SetNotification(n, enabled); err != nil {
+		return err
+	}
+
+	if err := c.userRepo.UpdateNotificationSettings(ctx, usr.ID(), usr.NotificationSettings()); err != nil {
+		return fmt.Errorf("failed to save notification settings: %w", err)
+	}
+	return nil
+}
+
+// Wants reports whether the user receives a kind of notification.
+// Falls back to the default when the user cannot be loaded.
+func (c *ManageNotificationsCommand) Wants(ctx context.Context, userID shared.ID, n user.Notification) bool {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return n.DefaultEnabled()
+	}
+	return usr.WantsNotification(n)
+}
+
+// effectiveSettings resolves every notification kind for the user
+func effectiveSettings(usr *user.User) map[user.Notification]bool {
+	settings := make(map[user.Notification]bool)
+	for _, n := range user.AllNotifications() {
+		settings[n] = usr.WantsNotification(n)
+	}
+	return settings
+}
+
+// QuietHours returns the user's quiet hours, nil when off, and the name of their time zone
+func (c *ManageNotificationsCommand) QuietHours(ctx context.Context, userID shared.ID) (*user.QuietHours, string, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get user: %w", err)
+	}
+	return usr.QuietHours(), usr.Timezone(), nil
+}
+
+// SetQuietHours replaces the user's quiet hours; nil turns them off
+func (c *ManageNotificationsCommand) SetQuietHours(ctx context.Context, userID shared.ID, quiet *user.QuietHours) error {
+	if err := c.userRepo.UpdateQuietHours(ctx, user.UserID(userID), quiet); err != nil {
+		return fmt.Errorf("failed to save quiet hours: %w", err)
+	}
+	return nil
+}
+
+// SetTimezone sets the user's time zone from its IANA name, such as "Europe/Lisbon".
+// Returns shared.ErrInvalidTimezone for a name that is not a time zone.
+func (c *ManageNotificationsCommand) SetTimezone(ctx context.Context, userID shared.ID, name string) error {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := usr.SetTimezone(name); err != nil {
+		return err
+	}
+	if err := c.userRepo.UpdateTimezone(ctx, usr.ID(), usr.Timezone()); err != nil {
+		return fmt.Errorf("failed to save timezone: %w", err)
+	}
+	return nil
+}
+
+// SetLocale sets the regional style the user reads dates in from a tag like "en-US".
+// Returns shared.ErrInvalidLocale for a locale that is not supported.
+func (c *ManageNotificationsCommand) SetLocale(ctx context.Context, userID shared.ID, code string) error {
+	locale, ok := user.ParseLocale(code)
+	if !ok {
+		return shared.ErrInvalidLocale
+	}
+	if err := c.userRepo.UpdateLocale(ctx, user.UserID(userID), locale); err != nil {
+		return fmt.Errorf("failed to save locale: %w", err)
+	}
+	return nil
+}
+
+// SetPlainMode turns on or off writing the user's messages without emojis or formatting
+func (c *ManageNotificationsCommand) SetPlainMode(ctx context.Context, userID shared.ID, on bool) error {
+	if err := c.userRepo.UpdatePlainMode(ctx, user.UserID(userID), on); err != nil {
+		return fmt.Errorf("failed to save plain mode: %w", err)
+	}
+	return nil
+}
+
+// SetKeepTranscripts sets whether the recipes the user saves from now on keep the
+// transcript and captions they were extracted from
+func (c *ManageNotificationsCommand) SetKeepTranscripts(ctx context.Context, userID shared.ID, keep bool) error {
+	if err := c.userRepo.UpdateKeepTranscripts(ctx, user.UserID(userID), keep); err != nil {
+		return fmt.Errorf("failed to save transcript setting: %w", err)
+	}
+	return nil
+}
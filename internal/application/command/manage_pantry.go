@@ -3,28 +3,39 @@ package command
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
 
 	"receipt-bot/internal/application/dto"
 	"receipt-bot/internal/domain/matching"
 	"receipt-bot/internal/domain/shared"
 	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
 )
 
 // ManagePantryCommand handles pantry operations
 type ManagePantryCommand struct {
 	userRepo   user.Repository
+	classifier ports.AisleClassifier
 	normalizer matching.IngredientNormalizer
 }
 
-// NewManagePantryCommand creates a new command
-func NewManagePantryCommand(userRepo user.Repository) *ManagePantryCommand {
+// NewManagePantryCommand creates a new command.
+// The classifier is optional and only used to group the pantry by aisle.
+func NewManagePantryCommand(userRepo user.Repository, classifier ports.AisleClassifier) *ManagePantryCommand {
 	return &ManagePantryCommand{
 		userRepo:   userRepo,
-		normalizer: matching.NewRuleBasedNormalizer(),
+		classifier: classifier,
+		normalizer: matching.NewRuleBasedNormalizer(nil),
 	}
 }
 
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *ManagePantryCommand) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+}
+
 // GetPantry retrieves the user's pantry items
 func (c *ManagePantryCommand) GetPantry(ctx context.Context, userID shared.ID) (*dto.PantryDTO, error) {
 	items, err := c.userRepo.GetPantry(ctx, user.UserID(userID))
@@ -37,65 +48,82 @@ func (c *ManagePantryCommand) GetPantry(ctx context.Context, userID shared.ID) (
 	}, nil
 }
 
-// AddItems adds items to the user's pantry
-func (c *ManagePantryCommand) AddItems(ctx context.Context, userID shared.ID, items []string) (*dto.PantryDTO, error) {
-	// Get current pantry
-	currentItems, err := c.userRepo.GetPantry(ctx, user.UserID(userID))
+// GetPantryByAisle retrieves the user's pantry items along with the aisle of each item.
+// Aisles are left empty when no classifier is configured or classification fails.
+func (c *ManagePantryCommand) GetPantryByAisle(ctx context.Context, userID shared.ID) (*dto.PantryDTO, error) {
+	pantry, err := c.GetPantry(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current pantry: %w", err)
+		return nil, err
 	}
 
-	// Normalize and deduplicate new items
-	normalized := c.normalizeItems(items)
+	if c.classifier == nil || len(pantry.Items) == 0 {
+		return pantry, nil
+	}
 
-	// Create a set of existing items for deduplication
-	existing := make(map[string]bool)
-	for _, item := range currentItems {
-		existing[item] = true
+	aisles, err := c.classifier.ClassifyAisles(ctx, pantry.Items)
+	if err != nil {
+		log.Printf("Pantry aisle classification failed: %v", err)
+		return pantry, nil
 	}
 
-	// Add new items
-	for _, item := range normalized {
-		if !existing[item] {
-			currentItems = append(currentItems, item)
+	pantry.Aisles = make(map[string]string, len(aisles))
+	for item, aisle := range aisles {
+		pantry.Aisles[item] = aisle.String()
+	}
+
+	return pantry, nil
+}
+
+// AddItems adds items to the user's pantry
+func (c *ManagePantryCommand) AddItems(ctx context.Context, userID shared.ID, items []string) (*dto.PantryDTO, error) {
+	// Normalize and deduplicate new items
+	normalized := c.normalizeItems(items)
+
+	newItems, err := c.userRepo.ModifyPantry(ctx, user.UserID(userID), func(currentItems []string) []string {
+		// Create a set of existing items for deduplication
+		existing := make(map[string]bool)
+		for _, item := range currentItems {
 			existing[item] = true
+			existing[c.normalizer.Normalize(item)] = true
 		}
-	}
 
-	// Save updated pantry
-	if err := c.userRepo.UpdatePantry(ctx, user.UserID(userID), currentItems); err != nil {
+		// Add new items
+		for _, item := range normalized {
+			if !existing[item] {
+				currentItems = append(currentItems, item)
+				existing[item] = true
+			}
+		}
+		return currentItems
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to update pantry: %w", err)
 	}
 
 	return &dto.PantryDTO{
-		Items: currentItems,
+		Items: newItems,
 	}, nil
 }
 
 // RemoveItems removes items from the user's pantry
 func (c *ManagePantryCommand) RemoveItems(ctx context.Context, userID shared.ID, items []string) (*dto.PantryDTO, error) {
-	// Get current pantry
-	currentItems, err := c.userRepo.GetPantry(ctx, user.UserID(userID))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current pantry: %w", err)
-	}
-
 	// Normalize items to remove
 	toRemove := make(map[string]bool)
 	for _, item := range c.normalizeItems(items) {
 		toRemove[item] = true
 	}
 
-	// Filter out removed items
-	newItems := make([]string, 0, len(currentItems))
-	for _, item := range currentItems {
-		if !toRemove[item] {
-			newItems = append(newItems, item)
+	newItems, err := c.userRepo.ModifyPantry(ctx, user.UserID(userID), func(currentItems []string) []string {
+		// Filter out removed items, matching scanned items such as "spaghetti (500 g)" by name
+		newItems := make([]string, 0, len(currentItems))
+		for _, item := range currentItems {
+			if !toRemove[item] && !toRemove[c.normalizer.Normalize(item)] {
+				newItems = append(newItems, item)
+			}
 		}
-	}
-
-	// Save updated pantry
-	if err := c.userRepo.UpdatePantry(ctx, user.UserID(userID), newItems); err != nil {
+		return newItems
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to update pantry: %w", err)
 	}
 
@@ -104,6 +132,37 @@ func (c *ManagePantryCommand) RemoveItems(ctx context.Context, userID shared.ID,
 	}, nil
 }
 
+// AddProduct adds a packaged product to the user's pantry, keeping its package size.
+// The item is stored as "name (size)" and replaces an item with the same name.
+func (c *ManagePantryCommand) AddProduct(ctx context.Context, userID shared.ID, name, packageSize string) (string, *dto.PantryDTO, error) {
+	base := c.normalizer.Normalize(name)
+	if base == "" {
+		return "", nil, shared.ErrInvalidInput
+	}
+
+	item := base
+	if size := strings.TrimSpace(packageSize); size != "" {
+		item = fmt.Sprintf("%s (%s)", base, size)
+	}
+
+	newItems, err := c.userRepo.ModifyPantry(ctx, user.UserID(userID), func(currentItems []string) []string {
+		newItems := make([]string, 0, len(currentItems)+1)
+		for _, existing := range currentItems {
+			if c.normalizer.Normalize(existing) != base {
+				newItems = append(newItems, existing)
+			}
+		}
+		return append(newItems, item)
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to update pantry: %w", err)
+	}
+
+	return item, &dto.PantryDTO{
+		Items: newItems,
+	}, nil
+}
+
 // ClearPantry removes all items from the user's pantry
 func (c *ManagePantryCommand) ClearPantry(ctx context.Context, userID shared.ID) error {
 	if err := c.userRepo.UpdatePantry(ctx, user.UserID(userID), []string{}); err != nil {
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+func TestManagePantry_ConcurrentAddsKeepEveryItem(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository()
+	usr, err := user.NewUser(42, "cook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(ctx, usr); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewManagePantryCommand(repo, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := cmd.AddItems(ctx, shared.ID(usr.ID()), []string{fmt.Sprintf("spice %d", i)}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	pantry, err := cmd.GetPantry(ctx, shared.ID(usr.ID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pantry.Items) != 20 {
+		t.Fatalf("pantry has %d items, want 20: %v", len(pantry.Items), pantry.Items)
+	}
+}
@@ -0,0 +1,168 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// CurrencyStars is the currency of payments in Telegram Stars
+const CurrencyStars = "XTR"
+
+// PremiumPlan is a premium tier users can buy
+type PremiumPlan struct {
+	ID    string // "month" or "year"
+	Days  int
+	Price int // in the currency's smallest unit, Stars for XTR
+}
+
+// PremiumPlans returns the month and year plans at the given prices; plans
+// priced 0 are left out
+func PremiumPlans(monthPrice, yearPrice int) []PremiumPlan {
+	var plans []PremiumPlan
+	if monthPrice > 0 {
+		plans = append(plans, PremiumPlan{ID: "month", Days: 30, Price: monthPrice})
+	}
+	if yearPrice > 0 {
+		plans = append(plans, PremiumPlan{ID: "year", Days: 365, Price: yearPrice})
+	}
+	return plans
+}
+
+// PremiumInvoice is what a user is asked to pay for a plan
+type PremiumInvoice struct {
+	Plan     PremiumPlan
+	Currency string
+	Payload  string // comes back with the payment, naming the plan and the user
+}
+
+// ManagePremiumCommand sells the premium tier, paid in Telegram Stars or through a
+// payment provider, and tells quotas and feature flags who has it
+type ManagePremiumCommand struct {
+	userRepo user.Repository
+	currency string
+	plans    []PremiumPlan
+}
+
+// NewManagePremiumCommand creates a new command selling plans in a currency
+func NewManagePremiumCommand(userRepo user.Repository, currency string, plans []PremiumPlan) *ManagePremiumCommand {
+	return &ManagePremiumCommand{
+		userRepo: userRepo,
+		currency: currency,
+		plans:    plans,
+	}
+}
+
+// Plans returns the plans on offer
+func (c *ManagePremiumCommand) Plans() []PremiumPlan {
+	return c.plans
+}
+
+// Currency returns the currency plans are priced in
+func (c *ManagePremiumCommand) Currency() string {
+	return c.currency
+}
+
+// Status returns when the user's premium tier ends, nil if they don't have it
+func (c *ManagePremiumCommand) Status(ctx context.Context, userID shared.ID, now time.Time) (*time.Time, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !usr.IsPremium(now) {
+		return nil, nil
+	}
+	return usr.PremiumUntil(), nil
+}
+
+// IsPremium reports whether the user has the premium tier. Users that cannot
+// be loaded are treated as not having it.
+func (c *ManagePremiumCommand) IsPremium(ctx context.Context, userID shared.ID) bool {
+	until, err := c.Status(ctx, userID, time.Now())
+	if err != nil {
+		log.Printf("Error checking premium for %s: %v", userID, err)
+		return false
+	}
+	return until != nil
+}
+
+// Invoice returns the invoice for a plan. Returns shared.ErrUnknownPlan for plans
+// not on offer.
+func (c *ManagePremiumCommand) Invoice(userID shared.ID, planID string) (*PremiumInvoice, error) {
+	plan, ok := c.plan(planID)
+	if !ok {
+		return nil, shared.ErrUnknownPlan
+	}
+	return &PremiumInvoice{
+		Plan:     plan,
+		Currency: c.currency,
+		Payload:  "premium:" + plan.ID + ":" + userID.String(),
+	}, nil
+}
+
+// CheckPayment checks a payment Telegram asks to confirm before charging the user.
+// Returns shared.ErrPaymentMismatch if it is not for a plan at its current price.
+func (c *ManagePremiumCommand) CheckPayment(currency string, amount int, payload string) error {
+	_, _, err := c.paidPlan(currency, amount, payload)
+	return err
+}
+
+// CompletePayment gives the user an invoice was for the days of its plan, after
+// the tier they already have, and records the charge. A charge Telegram delivers
+// again is applied once: its recorded payment is returned with
+// shared.ErrPaymentRecorded. Returns the payment, with when the user's tier now ends.
+func (c *ManagePremiumCommand) CompletePayment(ctx context.Context, chargeID, currency string, amount int, payload string, now time.Time) (*user.Payment, error) {
+	plan, userID, err := c.paidPlan(currency, amount, payload)
+	if err != nil {
+		return nil, err
+	}
+	if chargeID == "" {
+		return nil, shared.ErrPaymentMismatch
+	}
+
+	payment, err := c.userRepo.RecordPayment(ctx, user.Payment{
+		ChargeID: chargeID,
+		UserID:   user.UserID(userID),
+		PlanID:   plan.ID,
+		Days:     plan.Days,
+		Amount:   amount,
+		Currency: currency,
+		PaidAt:   now,
+	})
+	if errors.Is(err, shared.ErrPaymentRecorded) {
+		return payment, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save premium: %w", err)
+	}
+	return payment, nil
+}
+
+// paidPlan returns the plan and user of a payment, checking it pays the plan's price
+func (c *ManagePremiumCommand) paidPlan(currency string, amount int, payload string) (PremiumPlan, shared.ID, error) {
+	parts := strings.SplitN(payload, ":", 3)
+	if len(parts) != 3 || parts[0] != "premium" || parts[2] == "" {
+		return PremiumPlan{}, "", shared.ErrPaymentMismatch
+	}
+	plan, ok := c.plan(parts[1])
+	if !ok || currency != c.currency || amount != plan.Price {
+		return PremiumPlan{}, "", shared.ErrPaymentMismatch
+	}
+	return plan, shared.ID(parts[2]), nil
+}
+
+// plan returns the plan on offer with an ID
+func (c *ManagePremiumCommand) plan(id string) (PremiumPlan, bool) {
+	for _, plan := range c.plans {
+		if plan.ID == id {
+			return plan, true
+		}
+	}
+	return PremiumPlan{}, false
+}
@@ -0,0 +1,158 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+func TestManagePremiumCommand_Payment(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	repo := memory.NewUserRepository()
+	usr, _ := user.NewUser(1001, "alice")
+	_ = repo.Save(ctx, usr)
+
+	cmd := NewManagePremiumCommand(repo, CurrencyStars, PremiumPlans(100, 0))
+	if _, err := cmd.Invoice(usr.ID(), "year"); !errors.Is(err, shared.ErrUnknownPlan) {
+		t.Errorf("Invoice(year) error = %v, want ErrUnknownPlan", err)
+	}
+
+	invoice, err := cmd.Invoice(usr.ID(), "month")
+	if err != nil {
+		t.Fatalf("Invoice() error = %v", err)
+	}
+	if err := cmd.CheckPayment(CurrencyStars, 99, invoice.Payload); !errors.Is(err, shared.ErrPaymentMismatch) {
+		t.Errorf("CheckPayment() of the wrong amount error = %v, want ErrPaymentMismatch", err)
+	}
+	if err := cmd.CheckPayment(CurrencyStars, 100, invoice.Payload); err != nil {
+		t.Errorf("CheckPayment() error = %v", err)
+	}
+
+	paid, err := cmd.CompletePayment(ctx, "charge-1", CurrencyStars, 100, invoice.Payload, now)
+	if err != nil {
+		t.Fatalf("CompletePayment() error = %v", err)
+	}
+	if want := now.AddDate(0, 0, 30); !paid.PremiumUntil.Equal(want) {
+		t.Errorf("CompletePayment() until = %v, want %v", paid.PremiumUntil, want)
+	}
+	if paid.ChargeID != "charge-1" || paid.UserID != usr.ID() || paid.PlanID != "month" || paid.Amount != 100 || paid.Currency != CurrencyStars {
+		t.Errorf("CompletePayment() recorded %+v, want the month plan paid by the user", paid)
+	}
+
+	// A second month starts when the first ends
+	paid, _ = cmd.CompletePayment(ctx, "charge-2", CurrencyStars, 100, invoice.Payload, now.Add(24*time.Hour))
+	if want := now.AddDate(0, 0, 60); !paid.PremiumUntil.Equal(want) {
+		t.Errorf("CompletePayment() of a second charge until = %v, want %v", paid.PremiumUntil, want)
+	}
+	if !cmd.IsPremium(ctx, usr.ID()) {
+		t.Error("IsPremium() = false after paying, want true")
+	}
+
+	status, _ := cmd.Status(ctx, usr.ID(), now.AddDate(0, 0, 61))
+	if status != nil {
+		t.Errorf("Status() after the tier ended = %v, want nil", status)
+	}
+}
+
+func TestManagePremiumCommand_PaymentDeliveredTwice(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	repo := memory.NewUserRepository()
+	usr, _ := user.NewUser(1001, "alice")
+	_ = repo.Save(ctx, usr)
+
+	cmd := NewManagePremiumCommand(repo, CurrencyStars, PremiumPlans(100, 0))
+	invoice, _ := cmd.Invoice(usr.ID(), "month")
+
+	first, err := cmd.CompletePayment(ctx, "charge-1", CurrencyStars, 100, invoice.Payload, now)
+	if err != nil {
+		t.Fatalf("CompletePayment() error = %v", err)
+	}
+	again, err := cmd.CompletePayment(ctx, "charge-1", CurrencyStars, 100, invoice.Payload, now.Add(time.Minute))
+	if !errors.Is(err, shared.ErrPaymentRecorded) {
+		t.Fatalf("CompletePayment() of the same charge error = %v, want ErrPaymentRecorded", err)
+	}
+	if again == nil || !again.PremiumUntil.Equal(first.PremiumUntil) || !again.PaidAt.Equal(now) {
+		t.Errorf("CompletePayment() of the same charge = %+v, want the recorded payment %+v", again, first)
+	}
+
+	status, _ := cmd.Status(ctx, usr.ID(), now)
+	if status == nil || !status.Equal(now.AddDate(0, 0, 30)) {
+		t.Errorf("Status() = %v, want one month of premium", status)
+	}
+}
+
+func TestManagePremiumCommand_ConcurrentPayments(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	repo := memory.NewUserRepository()
+	usr, _ := user.NewUser(1001, "alice")
+	_ = repo.Save(ctx, usr)
+
+	cmd := NewManagePremiumCommand(repo, CurrencyStars, PremiumPlans(100, 0))
+	invoice, _ := cmd.Invoice(usr.ID(), "month")
+
+	// Linked accounts paying at the same time each add their month
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := cmd.CompletePayment(ctx, fmt.Sprintf("charge-%d", i), CurrencyStars, 100, invoice.Payload, now); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	status, _ := cmd.Status(ctx, usr.ID(), now)
+	if status == nil || !status.Equal(now.AddDate(0, 0, 150)) {
+		t.Errorf("Status() = %v, want five months of premium", status)
+	}
+}
+
+type premiumSet map[shared.ID]bool
+
+func (p premiumSet) IsPremium(ctx context.Context, userID shared.ID) bool {
+	return p[userID]
+}
+
+func TestLinkQuota_Allow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	quota := NewLinkQuota(2, 3, premiumSet{"paid": true})
+
+	for i := 0; i < 2; i++ {
+		if wait := quota.Allow(ctx, "free", now.Add(time.Duration(i)*time.Minute)); wait != 0 {
+			t.Fatalf("Allow() #%d = %v, want 0", i+1, wait)
+		}
+	}
+	if wait := quota.Allow(ctx, "free", now.Add(10*time.Minute)); wait != 50*time.Minute {
+		t.Errorf("Allow() over the limit = %v, want 50m until the first link expires", wait)
+	}
+	if wait := quota.Allow(ctx, "free", now.Add(time.Hour)); wait != 0 {
+		t.Errorf("Allow() an hour later = %v, want 0", wait)
+	}
+
+	for i := 0; i < 3; i++ {
+		if wait := quota.Allow(ctx, "paid", now); wait != 0 {
+			t.Fatalf("Allow() premium #%d = %v, want 0", i+1, wait)
+		}
+	}
+	if wait := quota.Allow(ctx, "paid", now); wait == 0 {
+		t.Error("Allow() over the premium limit = 0, want a wait")
+	}
+
+	quota.SetLimits(0, 0)
+	if wait := quota.Allow(ctx, "free", now.Add(time.Hour)); wait != 0 {
+		t.Errorf("Allow() without a limit = %v, want 0", wait)
+	}
+}
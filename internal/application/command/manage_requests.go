@@ -0,0 +1,111 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/requests"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ManageRequestsCommand keeps the dishes household members ask each other to cook
+type ManageRequestsCommand struct {
+	requestRepo requests.Repository
+	recipeRepo  recipe.Repository
+	mealPlan    *ManageMealPlanCommand
+}
+
+// NewManageRequestsCommand creates a new command
+func NewManageRequestsCommand(requestRepo requests.Repository, recipeRepo recipe.Repository, mealPlan *ManageMealPlanCommand) *ManageRequestsCommand {
+	return &ManageRequestsCommand{
+		requestRepo: requestRepo,
+		recipeRepo:  recipeRepo,
+		mealPlan:    mealPlan,
+	}
+}
+
+// List returns the household's request list, empty if nothing was requested yet
+func (c *ManageRequestsCommand) List(ctx context.Context, ownerID shared.ID) (*requests.List, error) {
+	return c.findOrCreate(ctx, ownerID)
+}
+
+// Request adds a dish a member asked for to the household's list
+func (c *ManageRequestsCommand) Request(ctx context.Context, ownerID shared.ID, dish string, requester requests.Member, now time.Time) (requests.Request, error) {
+	l, err := c.findOrCreate(ctx, ownerID)
+	if err != nil {
+		return requests.Request{}, err
+	}
+
+	req, err := l.Add(dish, requester, now)
+	if err != nil {
+		return requests.Request{}, err
+	}
+
+	if err := c.requestRepo.Save(ctx, l); err != nil {
+		return requests.Request{}, fmt.Errorf("failed to save dish requests: %w", err)
+	}
+	return req, nil
+}
+
+// Plan attaches one of the cook's recipes to the request with a number, and plans
+// it on a weekday of the cook's meal plan for the week containing now
+func (c *ManageRequestsCommand) Plan(ctx context.Context, ownerID shared.ID, number int, cook requests.Member, recipeID recipe.RecipeID, day time.Weekday, now time.Time) (requests.Request, error) {
+	l, err := c.findOrCreate(ctx, ownerID)
+	if err != nil {
+		return requests.Request{}, err
+	}
+	if _, err := l.Get(number); err != nil {
+		return requests.Request{}, err
+	}
+
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return requests.Request{}, fmt.Errorf("recipe not found: %w", err)
+	}
+	if _, err := c.mealPlan.AddRecipe(ctx, cook.ID, now, day, recipeID, 0); err != nil {
+		return requests.Request{}, err
+	}
+
+	req, err := l.Attach(number, requests.Plan{Cook: cook, RecipeID: recipeID, Title: rec.Title(), Day: day})
+	if err != nil {
+		return requests.Request{}, err
+	}
+
+	if err := c.requestRepo.Save(ctx, l); err != nil {
+		return requests.Request{}, fmt.Errorf("failed to save dish requests: %w", err)
+	}
+	return req, nil
+}
+
+// Remove takes the request with a number off the household's list, once cooked
+// or called off, and returns it
+func (c *ManageRequestsCommand) Remove(ctx context.Context, ownerID shared.ID, number int) (requests.Request, error) {
+	l, err := c.findOrCreate(ctx, ownerID)
+	if err != nil {
+		return requests.Request{}, err
+	}
+
+	req, err := l.Remove(number)
+	if err != nil {
+		return requests.Request{}, err
+	}
+
+	if err := c.requestRepo.Save(ctx, l); err != nil {
+		return requests.Request{}, fmt.Errorf("failed to save dish requests: %w", err)
+	}
+	return req, nil
+}
+
+func (c *ManageRequestsCommand) findOrCreate(ctx context.Context, ownerID shared.ID) (*requests.List, error) {
+	l, err := c.requestRepo.FindByOwner(ctx, ownerID)
+	if err == nil {
+		return l, nil
+	}
+	if !errors.Is(err, shared.ErrRequestsNotFound) {
+		return nil, fmt.Errorf("failed to get dish requests: %w", err)
+	}
+	return requests.NewList(ownerID)
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// ManageSavedFiltersCommand saves, lists and deletes the named searches stored on a user
+type ManageSavedFiltersCommand struct {
+	userRepo user.Repository
+}
+
+// NewManageSavedFiltersCommand creates a new command
+func NewManageSavedFiltersCommand(userRepo user.Repository) *ManageSavedFiltersCommand {
+	return &ManageSavedFiltersCommand{
+		userRepo: userRepo,
+	}
+}
+
+// List returns the user's saved filters
+func (c *ManageSavedFiltersCommand) List(ctx context.Context, userID shared.ID) ([]user.SavedFilter, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return usr.SavedFilters(), nil
+}
+
+// Get finds a saved filter by name
+func (c *ManageSavedFiltersCommand) Get(ctx context.Context, userID shared.ID, name string) (user.SavedFilter, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return user.SavedFilter{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	filter, ok := usr.SavedFilter(name)
+	if !ok {
+		return user.SavedFilter{}, shared.ErrFilterNotFound
+	}
+	return filter, nil
+}
+
+// Save stores a filter under its name, replacing any filter with the same name
+func (c *ManageSavedFiltersCommand) Save(ctx context.Context, userID shared.ID, filter user.SavedFilter) error {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := usr.SaveFilter(filter); err != nil {
+		return err
+	}
+
+	if err := c.userRepo.UpdateSavedFilters(ctx, usr.ID(), usr.SavedFilters()); err != nil {
+		return fmt.Errorf("failed to save filter: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a saved filter by name
+func (c *ManageSavedFiltersCommand) Delete(ctx context.Context, userID shared.ID, name string) error {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := usr.DeleteFilter(name); err != nil {
+		return err
+	}
+
+	if err := c.userRepo.UpdateSavedFilters(ctx, usr.ID(), usr.SavedFilters()); err != nil {
+		return fmt.Errorf("failed to delete filter: %w", err)
+	}
+	return nil
+}
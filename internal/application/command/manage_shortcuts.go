@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// ManageShortcutsCommand saves, lists and deletes the text shortcuts stored on a user
+type ManageShortcutsCommand struct {
+	userRepo user.Repository
+}
+
+// NewManageShortcutsCommand creates a new command
+func NewManageShortcutsCommand(userRepo user.Repository) *ManageShortcutsCommand {
+	return &ManageShortcutsCommand{
+		userRepo: userRepo,
+	}
+}
+
+// List returns the user's shortcuts
+func (c *ManageShortcutsCommand) List(ctx context.Context, userID shared.ID) ([]user.Shortcut, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return usr.Shortcuts(), nil
+}
+
+// Save stores a shortcut under its trigger, replacing any shortcut with the same trigger
+func (c *ManageShortcutsCommand) Save(ctx context.Context, userID shared.ID, shortcut user.Shortcut) error {
+	_, err := c.userRepo.ModifyShortcuts(ctx, user.UserID(userID), func(u *user.User) error {
+		return u.SaveShortcut(shortcut)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save shortcut: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a shortcut by its trigger
+func (c *ManageShortcutsCommand) Delete(ctx context.Context, userID shared.ID, trigger string) error {
+	_, err := c.userRepo.ModifyShortcuts(ctx, user.UserID(userID), func(u *user.User) error {
+		return u.DeleteShortcut(trigger)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete shortcut: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+func TestManageShortcuts_ConcurrentSavesKeepEveryShortcut(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository()
+	usr, _ := user.NewUser(42, "cook")
+	_ = repo.Save(ctx, usr)
+	cmd := NewManageShortcutsCommand(repo)
+	userID := shared.ID(usr.ID())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			shortcut := user.Shortcut{Trigger: fmt.Sprintf("s%d", i), Expansion: fmt.Sprintf("shortcut %d", i)}
+			if err := cmd.Save(ctx, userID, shortcut); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	shortcuts, err := cmd.List(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shortcuts) != 20 {
+		t.Fatalf("user has %d shortcuts, want 20: %v", len(shortcuts), shortcuts)
+	}
+
+	// A failed change saves nothing
+	if err := cmd.Delete(ctx, userID, "missing"); !errors.Is(err, shared.ErrShortcutNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrShortcutNotFound", err)
+	}
+	if err := cmd.Save(ctx, userID, user.Shortcut{Trigger: "s0"}); !errors.Is(err, shared.ErrInvalidShortcut) {
+		t.Errorf("Save() without an expansion error = %v, want ErrInvalidShortcut", err)
+	}
+	if shortcuts, _ := cmd.List(ctx, userID); len(shortcuts) != 20 {
+		t.Errorf("user has %d shortcuts after failed changes, want 20", len(shortcuts))
+	}
+}
@@ -0,0 +1,118 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+// ManageStaplesCommand reads and changes the pantry staples of a user: the
+// ingredients they always have, which recipe matching does not count as missing
+type ManageStaplesCommand struct {
+	userRepo   user.Repository
+	normalizer matching.IngredientNormalizer
+}
+
+// NewManageStaplesCommand creates a new command
+func NewManageStaplesCommand(userRepo user.Repository) *ManageStaplesCommand {
+	return &ManageStaplesCommand{
+		userRepo:   userRepo,
+		normalizer: matching.NewRuleBasedNormalizer(nil),
+	}
+}
+
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *ManageStaplesCommand) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+}
+
+// Staples returns the user's staples, sorted; the defaults until they choose their own
+func (c *ManageStaplesCommand) Staples(ctx context.Context, userID shared.ID) ([]string, error) {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return effectiveStaples(usr), nil
+}
+
+// Add adds items to the user's staples, starting from the defaults if the user
+// never changed them. Returns the updated staples.
+func (c *ManageStaplesCommand) Add(ctx context.Context, userID shared.ID, items []string) ([]string, error) {
+	return c.update(ctx, userID, func(staples map[string]bool) {
+		for _, item := range c.normalizeItems(items) {
+			staples[item] = true
+		}
+	})
+}
+
+// Remove removes items from the user's staples. Returns the updated staples.
+func (c *ManageStaplesCommand) Remove(ctx context.Context, userID shared.ID, items []string) ([]string, error) {
+	return c.update(ctx, userID, func(staples map[string]bool) {
+		for _, item := range c.normalizeItems(items) {
+			delete(staples, item)
+		}
+	})
+}
+
+// Reset goes back to the default staples. Returns them.
+func (c *ManageStaplesCommand) Reset(ctx context.Context, userID shared.ID) ([]string, error) {
+	_, err := c.userRepo.ModifyStaples(ctx, user.UserID(userID), func([]string) []string {
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save staples: %w", err)
+	}
+	return matching.DefaultStaples(), nil
+}
+
+// update applies a change to the user's staples and saves them in one transaction
+func (c *ManageStaplesCommand) update(ctx context.Context, userID shared.ID, change func(map[string]bool)) ([]string, error) {
+	staples, err := c.userRepo.ModifyStaples(ctx, user.UserID(userID), func(current []string) []string {
+		if current == nil {
+			current = matching.DefaultStaples()
+		}
+		set := make(map[string]bool)
+		for _, staple := range current {
+			set[staple] = true
+		}
+		change(set)
+
+		staples := make([]string, 0, len(set))
+		for staple := range set {
+			staples = append(staples, staple)
+		}
+		sort.Strings(staples)
+		return staples
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save staples: %w", err)
+	}
+	return staples, nil
+}
+
+// normalizeItems normalizes staples the way recipe ingredients are matched
+func (c *ManageStaplesCommand) normalizeItems(items []string) []string {
+	normalized := make([]string, 0, len(items))
+	for _, item := range items {
+		if norm := c.normalizer.Normalize(strings.TrimSpace(item)); norm != "" {
+			normalized = append(normalized, norm)
+		}
+	}
+	return normalized
+}
+
+// effectiveStaples resolves the user's staples, defaults included
+func effectiveStaples(usr *user.User) []string {
+	if usr.Staples() == nil {
+		return matching.DefaultStaples()
+	}
+	staples := append([]string(nil), usr.Staples()...)
+	sort.Strings(staples)
+	return staples
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+)
+
+func TestManageStaples_ConcurrentAddsKeepEveryStaple(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository()
+	usr, _ := user.NewUser(42, "cook")
+	_ = repo.Save(ctx, usr)
+	cmd := NewManageStaplesCommand(repo)
+	userID := shared.ID(usr.ID())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := cmd.Add(ctx, userID, []string{fmt.Sprintf("spice %d", i)}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	staples, err := cmd.Staples(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(matching.DefaultStaples()) + 20; len(staples) != want {
+		t.Fatalf("user has %d staples, want the defaults and 20 added (%d): %v", len(staples), want, staples)
+	}
+
+	if _, err := cmd.Reset(ctx, userID); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if saved, _ := repo.FindByID(ctx, usr.ID()); saved.Staples() != nil {
+		t.Errorf("staples after Reset() = %v, want the defaults", saved.Staples())
+	}
+}
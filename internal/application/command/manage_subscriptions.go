@@ -0,0 +1,211 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/feed"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// maxPostsPerPoll caps the new posts of one feed proposed per poll, so a feed
+// republishing its archive does not flood the user
+const maxPostsPerPoll = 3
+
+// maxPendingPosts caps the proposed recipes kept for the user to save
+const maxPendingPosts = 500
+
+// FeedPost is a new post of a followed feed, with the recipe extracted from it
+type FeedPost struct {
+	ID        string
+	UserID    user.UserID
+	FeedTitle string
+	Item      feed.Item
+	Recipe    *recipe.Recipe // not saved until the user asks
+}
+
+// ManageSubscriptionsCommand manages the recipe blog feeds users follow and proposes
+// the recipes of their new posts. Proposals are kept in memory until saved.
+type ManageSubscriptionsCommand struct {
+	repo              feed.Repository
+	reader            ports.FeedReader
+	processRecipeLink *ProcessRecipeLinkCommand // without a messenger, so posts are processed quietly
+	recipeRepo        recipe.Repository
+
+	mu      sync.Mutex
+	pending map[string]*FeedPost
+	order   []string // pending post IDs, oldest first
+}
+
+// NewManageSubscriptionsCommand creates a new command
+func NewManageSubscriptionsCommand(repo feed.Repository, reader ports.FeedReader, processRecipeLink *ProcessRecipeLinkCommand, recipeRepo recipe.Repository) *ManageSubscriptionsCommand {
+	return &ManageSubscriptionsCommand{
+		repo:              repo,
+		reader:            reader,
+		processRecipeLink: processRecipeLink,
+		recipeRepo:        recipeRepo,
+		pending:           make(map[string]*FeedPost),
+	}
+}
+
+// Subscribe follows the feed at rawURL. Its current posts are not proposed, only
+// the ones published afterwards.
+func (c *ManageSubscriptionsCommand) Subscribe(ctx context.Context, userID user.UserID, rawURL string, now time.Time) (*feed.Subscription, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, shared.ErrInvalidURL
+	}
+	feedURL := parsed.String()
+
+	existing, err := c.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	for _, s := range existing {
+		if s.URL == feedURL {
+			return nil, shared.ErrAlreadySubscribed
+		}
+	}
+	if len(existing) >= feed.MaxSubscriptions {
+		return nil, shared.ErrTooManySubscriptions
+	}
+
+	current, err := c.reader.Read(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := feed.NewSubscription(userID, feedURL, current, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.repo.Save(ctx, s); err != nil {
+		return nil, fmt.Errorf("failed to save subscription: %w", err)
+	}
+	return s, nil
+}
+
+// List returns the feeds a user follows, oldest first
+func (c *ManageSubscriptionsCommand) List(ctx context.Context, userID user.UserID) ([]*feed.Subscription, error) {
+	return c.repo.FindByUser(ctx, userID)
+}
+
+// Unsubscribe stops following the feed at a 1-based position of List
+func (c *ManageSubscriptionsCommand) Unsubscribe(ctx context.Context, userID user.UserID, index int) (*feed.Subscription, error) {
+	subscriptions, err := c.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	if index < 1 || index > len(subscriptions) {
+		return nil, shared.ErrSubscriptionNotFound
+	}
+
+	s := subscriptions[index-1]
+	if err := c.repo.Delete(ctx, s.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return s, nil
+}
+
+// Poll reads every followed feed and returns the new posts holding a recipe,
+// oldest first for each feed. Posts are marked seen even when they hold none.
+func (c *ManageSubscriptionsCommand) Poll(ctx context.Context) ([]*FeedPost, error) {
+	subscriptions, err := c.repo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+
+	var posts []*FeedPost
+	for _, s := range subscriptions {
+		if ctx.Err() != nil {
+			break
+		}
+
+		current, err := c.reader.Read(ctx, s.URL)
+		if err != nil {
+			log.Printf("Failed to read feed %s: %v", s.URL, err)
+			continue
+		}
+		items := s.NewItems(current)
+		if len(items) == 0 {
+			continue
+		}
+
+		s.MarkSeen(current.Items)
+		if err := c.repo.Save(ctx, s); err != nil {
+			log.Printf("Failed to save feed subscription %s: %v", s.ID, err)
+			continue
+		}
+
+		if len(items) > maxPostsPerPoll {
+			items = items[len(items)-maxPostsPerPoll:]
+		}
+		for _, item := range items {
+			rec, err := c.processRecipeLink.Extract(ctx, item.Link, s.UserID)
+			if err != nil {
+				if errors.Is(err, shared.ErrQuotaExceeded) {
+					return posts, err
+				}
+				continue // Not every post is a recipe
+			}
+
+			post := &FeedPost{
+				ID:        shared.NewID().String(),
+				UserID:    s.UserID,
+				FeedTitle: s.Title,
+				Item:      item,
+				Recipe:    rec,
+			}
+			c.keep(post)
+			posts = append(posts, post)
+		}
+	}
+
+	return posts, nil
+}
+
+// Save saves the recipe of a proposed post. It returns shared.ErrNoPendingRecipes
+// once the proposal is gone, after saving it or a restart.
+func (c *ManageSubscriptionsCommand) Save(ctx context.Context, userID user.UserID, postID string) (*recipe.Recipe, error) {
+	c.mu.Lock()
+	post, ok := c.pending[postID]
+	if ok && post.UserID == userID {
+		delete(c.pending, postID)
+	}
+	c.mu.Unlock()
+
+	if !ok || post.UserID != userID {
+		return nil, shared.ErrNoPendingRecipes
+	}
+
+	// The user may have sent the link in the meantime
+	if existing, err := c.recipeRepo.FindBySourceURL(ctx, post.Item.Link); err == nil && existing != nil && existing.UserID() == userID {
+		return existing, nil
+	}
+	if err := c.recipeRepo.Save(ctx, post.Recipe); err != nil {
+		return nil, fmt.Errorf("failed to save recipe: %w", err)
+	}
+	return post.Recipe, nil
+}
+
+// keep stores a proposal, dropping the oldest beyond maxPendingPosts
+func (c *ManageSubscriptionsCommand) keep(post *FeedPost) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending[post.ID] = post
+	c.order = append(c.order, post.ID)
+	for len(c.order) > maxPendingPosts {
+		delete(c.pending, c.order[0])
+		c.order = c.order[1:]
+	}
+}
@@ -2,43 +2,59 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 
 	"receipt-bot/internal/application/dto"
 	"receipt-bot/internal/domain/matching"
 	"receipt-bot/internal/domain/recipe"
 	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
 )
 
 // MatchIngredientsCommand handles matching user ingredients to recipes
 type MatchIngredientsCommand struct {
 	recipeRepo recipe.Repository
+	userRepo   user.Repository
 	normalizer matching.IngredientNormalizer
 	matcher    *matching.IngredientMatcher
+
+	specificity int // how far up the ingredient hierarchy a user ingredient may stand in
 }
 
 // NewMatchIngredientsCommand creates a new command
-func NewMatchIngredientsCommand(recipeRepo recipe.Repository) *MatchIngredientsCommand {
-	normalizer := matching.NewRuleBasedNormalizer()
+func NewMatchIngredientsCommand(recipeRepo recipe.Repository, userRepo user.Repository, specificity int) *MatchIngredientsCommand {
+	normalizer := matching.NewRuleBasedNormalizer(nil)
 	return &MatchIngredientsCommand{
-		recipeRepo: recipeRepo,
-		normalizer: normalizer,
-		matcher:    matching.NewIngredientMatcher(normalizer),
+		recipeRepo:  recipeRepo,
+		userRepo:    userRepo,
+		normalizer:  normalizer,
+		matcher:     matching.NewIngredientMatcher(normalizer),
+		specificity: specificity,
 	}
 }
 
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *MatchIngredientsCommand) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+	c.matcher = matching.NewIngredientMatcher(normalizer)
+}
+
 // MatchIngredientsInput holds the input parameters
 type MatchIngredientsInput struct {
 	UserID         shared.ID
 	Ingredients    []string
 	CategoryFilter *recipe.Category
 	StrictMatch    bool
+	MaxMissing     int // most ingredients a recipe may lack, whatever its match level; 0 for no limit
 }
 
 // Execute finds recipes matching the given ingredients
 func (c *MatchIngredientsCommand) Execute(ctx context.Context, input MatchIngredientsInput) (*dto.MatchIngredientsResultDTO, error) {
-	// Fetch user's recipes
-	recipes, err := c.recipeRepo.FindByUserID(ctx, recipe.UserID(input.UserID))
+	// Fetch summaries of the user's recipes, which hold all the matcher needs
+	recipes, err := c.recipeRepo.FindSummariesByUserID(ctx, recipe.UserID(input.UserID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch recipes: %w", err)
 	}
@@ -48,6 +64,7 @@ func (c *MatchIngredientsCommand) Execute(ctx context.Context, input MatchIngred
 			PerfectMatches: []dto.MatchResultDTO{},
 			HighMatches:    []dto.MatchResultDTO{},
 			MediumMatches:  []dto.MatchResultDTO{},
+			LowMatches:     []dto.MatchResultDTO{},
 			TotalMatches:   0,
 		}, nil
 	}
@@ -56,6 +73,12 @@ func (c *MatchIngredientsCommand) Execute(ctx context.Context, input MatchIngred
 	options := matching.DefaultMatchOptions()
 	options.StrictMatch = input.StrictMatch
 	options.CategoryFilter = input.CategoryFilter
+	options.Staples = c.staples(ctx, input.UserID)
+	options.Specificity = c.specificity
+	if input.MaxMissing > 0 {
+		options.MaxMissing = input.MaxMissing
+		options.MinMatchLevel = matching.MatchLevelLow
+	}
 
 	// Perform matching
 	results := c.matcher.Match(input.Ingredients, recipes, options)
@@ -68,12 +91,50 @@ func (c *MatchIngredientsCommand) Execute(ctx context.Context, input MatchIngred
 		PerfectMatches: convertMatchResults(grouped[matching.MatchLevelPerfect]),
 		HighMatches:    convertMatchResults(grouped[matching.MatchLevelHigh]),
 		MediumMatches:  convertMatchResults(grouped[matching.MatchLevelMedium]),
+		LowMatches:     convertMatchResults(grouped[matching.MatchLevelLow]),
 		TotalMatches:   len(results),
 	}
 
 	return resultDTO, nil
 }
 
+// Explain matches the ingredients against one recipe of the user, whatever the
+// match level, so every recipe ingredient can be shown with how it was counted
+func (c *MatchIngredientsCommand) Explain(ctx context.Context, userID shared.ID, ingredients []string, recipeID recipe.RecipeID) (*dto.MatchResultDTO, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, shared.ErrRecipeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	options := matching.DefaultMatchOptions()
+	options.MinMatchLevel = matching.MatchLevelLow
+	options.Staples = c.staples(ctx, userID)
+	options.Specificity = c.specificity
+
+	results := c.matcher.Match(ingredients, []*recipe.Recipe{rec}, options)
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no ingredients to match")
+	}
+	return &convertMatchResults(results)[0], nil
+}
+
+// staples returns the user's pantry staples, or nil for the defaults when the
+// user never chose their own or cannot be loaded
+func (c *MatchIngredientsCommand) staples(ctx context.Context, userID shared.ID) []string {
+	usr, err := c.userRepo.FindByID(ctx, user.UserID(userID))
+	if err != nil {
+		log.Printf("Failed to load staples of user %s: %v", userID, err)
+		return nil
+	}
+	return usr.Staples()
+}
+
 // convertMatchResults converts domain match results to DTOs
 func convertMatchResults(results []matching.MatchResult) []dto.MatchResultDTO {
 	if results == nil {
@@ -89,6 +150,13 @@ func convertMatchResults(results []matching.MatchResult) []dto.MatchResultDTO {
 			MissingItems:    result.MissingItems,
 			MatchLevel:      matching.MatchLevelString(result.MatchLevel),
 		}
+		for _, d := range result.Details {
+			dtos[i].Details = append(dtos[i].Details, dto.MatchDetailDTO{
+				Ingredient: d.Ingredient,
+				Kind:       string(d.Kind),
+				Via:        d.Via,
+			})
+		}
 	}
 	return dtos
 }
@@ -96,16 +164,28 @@ func convertMatchResults(results []matching.MatchResult) []dto.MatchResultDTO {
 // convertRecipeToDTO converts a recipe to DTO (simplified version)
 func convertRecipeToDTO(rec *recipe.Recipe) *dto.RecipeDTO {
 	recipeDTO := &dto.RecipeDTO{
-		ID:             rec.ID().String(),
-		UserID:         rec.UserID().String(),
-		Title:          rec.Title(),
-		SourceURL:      rec.Source().URL(),
-		SourcePlatform: string(rec.Source().Platform()),
-		SourceAuthor:   rec.Source().Author(),
-		Category:       string(rec.Category()),
-		Cuisine:        rec.Cuisine(),
-		CreatedAt:      rec.CreatedAt(),
-		UpdatedAt:      rec.UpdatedAt(),
+		ID:              rec.ID().String(),
+		UserID:          rec.UserID().String(),
+		Title:           rec.Title(),
+		DisplayName:     rec.Title(),
+		SourceURL:       rec.Source().URL(),
+		SourcePlatform:  string(rec.Source().Platform()),
+		SourceAuthor:    rec.Source().Author(),
+		SourceSegment:   rec.Source().Segment(),
+		SourceHandle:    rec.Source().Attribution().Handle,
+		SourcePublished: rec.Source().Attribution().PublishedAt,
+		SourceCredit:    rec.Source().Credit(),
+		Category:        string(rec.Category()),
+		Cuisine:         rec.Cuisine(),
+		CreatedAt:       rec.CreatedAt(),
+		UpdatedAt:       rec.UpdatedAt(),
+		Summary:         rec.IsSummary(),
+		SourceLanguage:  rec.SourceLanguage(),
+	}
+
+	if cover := rec.Cover(); cover != nil {
+		recipeDTO.CoverKey = cover.Key
+		recipeDTO.CoverGenerated = cover.AIGenerated
 	}
 
 	// Convert ingredients
@@ -132,6 +212,10 @@ func convertRecipeToDTO(rec *recipe.Recipe) *dto.RecipeDTO {
 			Text:            inst.Text(),
 			DurationMinutes: durationMinutes,
 		}
+		if inst.Timestamp() != nil {
+			seconds := int(inst.Timestamp().Seconds())
+			recipeDTO.Instructions[i].StartSeconds = &seconds
+		}
 	}
 
 	// Convert optional times
@@ -154,5 +238,33 @@ func convertRecipeToDTO(rec *recipe.Recipe) *dto.RecipeDTO {
 
 	recipeDTO.Tags = rec.Tags()
 
+	recipeDTO.Flavors = make([]string, len(rec.Flavors()))
+	for i, f := range rec.Flavors() {
+		recipeDTO.Flavors[i] = string(f)
+	}
+
+	recipeDTO.Difficulty = string(rec.Difficulty())
+	recipeDTO.DifficultyScore = rec.DifficultyScore()
+	recipeDTO.CheckQuantities = rec.NeedsQuantityCheck()
+
+	// Convert remix
+	if remix := rec.Remix(); remix != nil {
+		recipeDTO.ParentRecipeID = remix.ParentID.String()
+		recipeDTO.RemixOf = remix.ParentTitle
+		recipeDTO.RemixGoal = remix.Goal
+		for _, sub := range remix.Substitutions {
+			recipeDTO.Substitutions = append(recipeDTO.Substitutions, dto.SubstitutionDTO{
+				Original:    sub.Original,
+				Replacement: sub.Replacement,
+				Reason:      sub.Reason,
+			})
+		}
+		for i, ing := range recipeDTO.Ingredients {
+			if sub, ok := remix.SubstitutionFor(ing.Name); ok {
+				recipeDTO.Ingredients[i].Replaces = sub.Original
+			}
+		}
+	}
+
 	return recipeDTO
 }
@@ -0,0 +1,178 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/share"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// ScreenResult is the outcome of checking recipes before they are shown to others
+type ScreenResult struct {
+	Allowed   []*recipe.Recipe
+	Held      []*moderation.Decision // blocked or waiting for a review
+	Queued    []*moderation.Decision // held recipes newly put in the review queue
+	Unchecked int                    // recipes whose check failed, hidden until it succeeds
+}
+
+// ModerateContentCommand checks recipes before people other than their owner see them:
+// the link a recipe was saved from must have a good reputation and, when the LLM can
+// judge it, its content must be safe. Borderline recipes wait for an admin review.
+// Decisions are kept until the recipe changes.
+type ModerateContentCommand struct {
+	repo       moderation.Repository
+	recipeRepo recipe.Repository
+	policy     *moderation.LinkPolicy
+	moderator  ports.ContentModerator // optional, only links are checked when nil
+}
+
+// NewModerateContentCommand creates a new command
+func NewModerateContentCommand(repo moderation.Repository, recipeRepo recipe.Repository, policy *moderation.LinkPolicy, moderator ports.ContentModerator) *ModerateContentCommand {
+	return &ModerateContentCommand{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		policy:     policy,
+		moderator:  moderator,
+	}
+}
+
+// ScreenShare checks the recipes a guest share shows
+func (c *ModerateContentCommand) ScreenShare(ctx context.Context, s *share.GuestShare, now time.Time) (*ScreenResult, error) {
+	recipes, err := c.recipeRepo.FindByUserID(ctx, s.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared recipes: %w", err)
+	}
+
+	var included []*recipe.Recipe
+	for _, rec := range recipes {
+		if s.Includes(rec) {
+			included = append(included, rec)
+		}
+	}
+	return c.Screen(ctx, included, now)
+}
+
+// Screen checks recipes, reusing the decisions made on their current content
+func (c *ModerateContentCommand) Screen(ctx context.Context, recipes []*recipe.Recipe, now time.Time) (*ScreenResult, error) {
+	result := &ScreenResult{}
+	for _, rec := range recipes {
+		d, err := c.repo.FindByRecipe(ctx, rec.ID())
+		if err != nil && !errors.Is(err, shared.ErrModerationNotFound) {
+			return nil, fmt.Errorf("failed to load moderation decision: %w", err)
+		}
+
+		if d == nil || !d.Covers(rec) {
+			d, err = c.check(ctx, rec, now)
+			if err != nil {
+				log.Printf("Moderation check of recipe %s failed: %v", rec.ID(), err)
+				result.Unchecked++
+				continue
+			}
+			if err := c.repo.Save(ctx, d); err != nil {
+				return nil, fmt.Errorf("failed to save moderation decision: %w", err)
+			}
+			if d.Verdict == moderation.VerdictReview {
+				result.Queued = append(result.Queued, d)
+			}
+		}
+
+		if d.Verdict == moderation.VerdictAllowed {
+			result.Allowed = append(result.Allowed, rec)
+		} else {
+			result.Held = append(result.Held, d)
+		}
+	}
+	return result, nil
+}
+
+// Visible returns the recipes that may be shown to others, in order
+func (c *ModerateContentCommand) Visible(ctx context.Context, recipes []*recipe.Recipe) ([]*recipe.Recipe, error) {
+	result, err := c.Screen(ctx, recipes, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return result.Allowed, nil
+}
+
+// Pending returns the review queue, oldest first
+func (c *ModerateContentCommand) Pending(ctx context.Context) ([]*moderation.Decision, error) {
+	return c.repo.FindPending(ctx)
+}
+
+// Review settles a recipe waiting for a review: approved recipes are shared, rejected
+// ones are blocked. It returns shared.ErrModerationNotFound if the recipe is not waiting.
+func (c *ModerateContentCommand) Review(ctx context.Context, recipeID recipe.RecipeID, approve bool, now time.Time) (*moderation.Decision, error) {
+	d, err := c.repo.FindByRecipe(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Verdict != moderation.VerdictReview {
+		return nil, shared.ErrModerationNotFound
+	}
+
+	d.Verdict = moderation.VerdictBlocked
+	if approve {
+		d.Verdict = moderation.VerdictAllowed
+	}
+	d.Reviewed = true
+	d.CheckedAt = now
+	if err := c.repo.Save(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to save moderation decision: %w", err)
+	}
+	return d, nil
+}
+
+// check runs the link and content checks on a recipe
+func (c *ModerateContentCommand) check(ctx context.Context, rec *recipe.Recipe, now time.Time) (*moderation.Decision, error) {
+	d := &moderation.Decision{
+		RecipeID:    rec.ID(),
+		OwnerID:     rec.UserID(),
+		Title:       rec.Title(),
+		SourceURL:   rec.Source().URL(),
+		Fingerprint: moderation.Fingerprint(rec),
+		CheckedAt:   now,
+	}
+
+	d.Verdict, d.Reason = c.policy.Check(rec.Source().URL())
+	if d.Verdict == moderation.VerdictBlocked || c.moderator == nil {
+		return d, nil
+	}
+
+	judged, err := c.moderator.ModerateRecipe(ctx, moderationText(rec))
+	if err != nil {
+		return nil, err
+	}
+	if verdict := moderation.Stricter(d.Verdict, judged.Verdict); verdict != d.Verdict {
+		d.Verdict, d.Reason = verdict, judged.Reason
+	}
+	return d, nil
+}
+
+// moderationText writes out the parts of a recipe others would see
+func moderationText(rec *recipe.Recipe) string {
+	var sb strings.Builder
+	sb.WriteString("Title: " + rec.Title() + "\n")
+	if url := rec.Source().URL(); url != "" {
+		sb.WriteString("Source: " + url + "\n")
+	}
+	if author := rec.Source().Author(); author != "" {
+		sb.WriteString("Author: " + author + "\n")
+	}
+	sb.WriteString("\nIngredients:\n")
+	for _, ing := range rec.Ingredients() {
+		sb.WriteString("- " + ing.String() + "\n")
+	}
+	sb.WriteString("\nInstructions:\n")
+	for _, inst := range rec.Instructions() {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", inst.StepNumber(), inst.Text()))
+	}
+	return sb.String()
+}
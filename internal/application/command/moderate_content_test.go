@@ -0,0 +1,154 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+type mockModerationRepository struct {
+	decisions map[recipe.RecipeID]moderation.Decision
+}
+
+func (m *mockModerationRepository) Save(ctx context.Context, d *moderation.Decision) error {
+	m.decisions[d.RecipeID] = *d
+	return nil
+}
+
+func (m *mockModerationRepository) FindByRecipe(ctx context.Context, recipeID recipe.RecipeID) (*moderation.Decision, error) {
+	d, ok := m.decisions[recipeID]
+	if !ok {
+		return nil, shared.ErrModerationNotFound
+	}
+	return &d, nil
+}
+
+func (m *mockModerationRepository) FindPending(ctx context.Context) ([]*moderation.Decision, error) {
+	var pending []*moderation.Decision
+	for _, d := range m.decisions {
+		if d.Verdict == moderation.VerdictReview {
+			pending = append(pending, &d)
+		}
+	}
+	return pending, nil
+}
+
+// mockContentModerator judges recipes by title
+type mockContentModerator struct {
+	verdicts map[string]moderation.Verdict
+	calls    int
+	err      error
+}
+
+func (m *mockContentModerator) ModerateRecipe(ctx context.Context, text string) (*ports.ModerationResult, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	for title, verdict := range m.verdicts {
+		if strings.HasPrefix(text, "Title: "+title) {
+			return &ports.ModerationResult{Verdict: verdict, Reason: "judged by title"}, nil
+		}
+	}
+	return &ports.ModerationResult{Verdict: moderation.VerdictAllowed}, nil
+}
+
+func newModerationTestRecipe(t *testing.T, title, link string) *recipe.Recipe {
+	t.Helper()
+	ing, _ := recipe.NewIngredient("flour", "2", "cups", "")
+	inst, _ := recipe.NewInstruction(1, "Mix everything", nil)
+	source, _ := recipe.NewSource(link, recipe.PlatformWeb, "Chef")
+	rec, err := recipe.NewRecipe(shared.NewID(), title, []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+	if err != nil {
+		t.Fatalf("NewRecipe() error = %v", err)
+	}
+	return rec
+}
+
+func TestModerateContentCommand_Screen(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	bread := newModerationTestRecipe(t, "Bread", "https://example.com/bread")
+	spam := newModerationTestRecipe(t, "Cheap pills", "https://example.com/pills")
+	foraged := newModerationTestRecipe(t, "Wild mushroom salad", "https://example.com/mushrooms")
+	shortened := newModerationTestRecipe(t, "Cake", "https://bit.ly/cake")
+	blockedSite := newModerationTestRecipe(t, "Soup", "https://spam.example/soup")
+
+	repo := &mockModerationRepository{decisions: make(map[recipe.RecipeID]moderation.Decision)}
+	moderator := &mockContentModerator{verdicts: map[string]moderation.Verdict{
+		"Cheap pills":         moderation.VerdictBlocked,
+		"Wild mushroom salad": moderation.VerdictReview,
+	}}
+	cmd := NewModerateContentCommand(repo, newMockRecipeRepository(), moderation.NewLinkPolicy([]string{"spam.example"}), moderator)
+
+	result, err := cmd.Screen(ctx, []*recipe.Recipe{bread, spam, foraged, shortened, blockedSite}, now)
+	if err != nil {
+		t.Fatalf("Screen() error = %v", err)
+	}
+	if len(result.Allowed) != 1 || result.Allowed[0] != bread {
+		t.Errorf("Allowed = %v, want only the bread", result.Allowed)
+	}
+	if len(result.Held) != 4 || len(result.Queued) != 2 {
+		t.Errorf("Held = %d, Queued = %d, want 4 and 2", len(result.Held), len(result.Queued))
+	}
+	// Blocked sites are not sent to the LLM
+	if moderator.calls != 4 {
+		t.Errorf("moderator called %d times, want 4", moderator.calls)
+	}
+
+	// Decisions are reused until the recipe changes
+	result, err = cmd.Screen(ctx, []*recipe.Recipe{bread, foraged}, now)
+	if err != nil {
+		t.Fatalf("Screen() error = %v", err)
+	}
+	if moderator.calls != 4 || len(result.Queued) != 0 || len(result.Held) != 1 {
+		t.Errorf("calls = %d, result = %+v, want the stored decisions", moderator.calls, result)
+	}
+
+	if _, err := cmd.Review(ctx, foraged.ID(), true, now); err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if _, err := cmd.Review(ctx, foraged.ID(), false, now); !errors.Is(err, shared.ErrModerationNotFound) {
+		t.Errorf("Review() twice error = %v, want ErrModerationNotFound", err)
+	}
+	if _, err := cmd.Review(ctx, spam.ID(), true, now); !errors.Is(err, shared.ErrModerationNotFound) {
+		t.Errorf("Review() of a blocked recipe error = %v, want ErrModerationNotFound", err)
+	}
+
+	visible, err := cmd.Visible(ctx, []*recipe.Recipe{bread, foraged, spam})
+	if err != nil || len(visible) != 2 {
+		t.Errorf("Visible() = %d recipes, %v, want the bread and the approved salad", len(visible), err)
+	}
+}
+
+func TestModerateContentCommand_ScreenFailsClosed(t *testing.T) {
+	ctx := context.Background()
+	rec := newModerationTestRecipe(t, "Bread", "https://example.com/bread")
+
+	repo := &mockModerationRepository{decisions: make(map[recipe.RecipeID]moderation.Decision)}
+	moderator := &mockContentModerator{err: shared.ErrQuotaExceeded}
+	cmd := NewModerateContentCommand(repo, newMockRecipeRepository(), moderation.NewLinkPolicy(nil), moderator)
+
+	result, err := cmd.Screen(ctx, []*recipe.Recipe{rec}, time.Now())
+	if err != nil {
+		t.Fatalf("Screen() error = %v", err)
+	}
+	if len(result.Allowed) != 0 || result.Unchecked != 1 {
+		t.Errorf("result = %+v, want the recipe hidden until it is checked", result)
+	}
+
+	// Checked again once the LLM answers
+	moderator.err = nil
+	result, _ = cmd.Screen(ctx, []*recipe.Recipe{rec}, time.Now())
+	if len(result.Allowed) != 1 {
+		t.Errorf("Allowed = %d, want the recipe once checked", len(result.Allowed))
+	}
+}
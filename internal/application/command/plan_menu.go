@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/menu"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// PlanMenuCommand plans multi-course menus for an occasion from the user's recipes,
+// filling courses the collection can't cover with suggested dishes.
+// Each user has one menu in progress, kept in memory while they pick dishes.
+type PlanMenuCommand struct {
+	recipeRepo recipe.Repository
+	suggester  ports.MenuSuggester // optional, no suggestions when nil
+
+	mu     sync.Mutex
+	drafts map[shared.ID]*menu.Menu
+}
+
+// NewPlanMenuCommand creates a new command
+func NewPlanMenuCommand(recipeRepo recipe.Repository, suggester ports.MenuSuggester) *PlanMenuCommand {
+	return &PlanMenuCommand{
+		recipeRepo: recipeRepo,
+		suggester:  suggester,
+		drafts:     make(map[shared.ID]*menu.Menu),
+	}
+}
+
+// Start plans a new menu, replacing the one in progress.
+// Each course offers the user's matching recipes, easiest first.
+func (c *PlanMenuCommand) Start(ctx context.Context, userID shared.ID, occasion string, guests int, serveAt time.Time, targetLang string) (*menu.Menu, error) {
+	m, err := menu.NewMenu(occasion, guests, serveAt)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes, err := c.recipeRepo.FindByUserID(ctx, recipe.UserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipes: %w", err)
+	}
+
+	sort.SliceStable(recipes, func(i, j int) bool {
+		return recipes[i].DifficultyScore() < recipes[j].DifficultyScore()
+	})
+
+	for _, rec := range recipes {
+		if dish, ok := recipeDish(rec); ok {
+			_ = m.AddOption(dish)
+		}
+	}
+
+	if missing := m.MissingCourses(); len(missing) > 0 && c.suggester != nil {
+		suggestions, err := c.suggester.SuggestDishes(ctx, occasion, missing, guests, targetLang)
+		if err != nil {
+			log.Printf("Menu suggestions unavailable: %v", err)
+		}
+		for _, s := range suggestions {
+			_ = m.AddOption(suggestedDish(s))
+		}
+	}
+
+	c.mu.Lock()
+	c.drafts[userID] = m
+	c.mu.Unlock()
+
+	return m.Clone(), nil
+}
+
+// Swap offers the next dish for a course of the menu in progress
+func (c *PlanMenuCommand) Swap(userID shared.ID, course menu.Course) (*menu.Menu, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.drafts[userID]
+	if !ok {
+		return nil, shared.ErrMenuNotFound
+	}
+	m.Swap(course)
+	return m.Clone(), nil
+}
+
+// Timeline schedules the cooking of the menu in progress
+func (c *PlanMenuCommand) Timeline(userID shared.ID) (*menu.Timeline, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.drafts[userID]
+	if !ok {
+		return nil, shared.ErrMenuNotFound
+	}
+	return m.Timeline(menu.DefaultBurners), nil
+}
+
+// recipeDish turns a saved recipe into a menu dish, if its category belongs on a menu
+func recipeDish(rec *recipe.Recipe) (menu.Dish, bool) {
+	course, ok := menu.CourseForCategory(rec.Category())
+	if !ok {
+		return menu.Dish{}, false
+	}
+
+	instructions := make([]string, len(rec.Instructions()))
+	for i, inst := range rec.Instructions() {
+		instructions[i] = inst.Text()
+	}
+
+	dish := menu.Dish{
+		Course:     course,
+		RecipeID:   rec.ID(),
+		Title:      rec.Title(),
+		Appliances: menu.DetectAppliances(instructions),
+	}
+	if rec.Servings() != nil {
+		dish.Servings = *rec.Servings()
+	}
+	if rec.PrepTime() != nil {
+		dish.PrepTime = *rec.PrepTime()
+	}
+	if rec.CookTime() != nil {
+		dish.CookTime = *rec.CookTime()
+	}
+	return dish, true
+}
+
+// suggestedDish turns an LLM suggestion into a menu dish
+func suggestedDish(s ports.DishSuggestion) menu.Dish {
+	dish := menu.Dish{
+		Course:     s.Course,
+		Title:      s.Title,
+		Appliances: s.Appliances,
+		Suggested:  true,
+	}
+	if s.Servings != nil {
+		dish.Servings = *s.Servings
+	}
+	if s.PrepTime != nil {
+		dish.PrepTime = *s.PrepTime
+	}
+	if s.CookTime != nil {
+		dish.CookTime = *s.CookTime
+	}
+	return dish
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/menu"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+type mockMenuSuggester struct {
+	courses []menu.Course
+}
+
+func (m *mockMenuSuggester) SuggestDishes(ctx context.Context, occasion string, courses []menu.Course, guests int, targetLang string) ([]ports.DishSuggestion, error) {
+	m.courses = courses
+	var dishes []ports.DishSuggestion
+	for _, course := range courses {
+		dishes = append(dishes, ports.DishSuggestion{Course: course, Title: "Suggested " + course.String()})
+	}
+	return dishes, nil
+}
+
+func newMenuRecipe(t *testing.T, userID shared.ID, title string, category recipe.Category, instruction string, cook time.Duration) *recipe.Recipe {
+	t.Helper()
+	ing, _ := recipe.NewIngredient("salt", "1", "pinch", "")
+	inst, _ := recipe.NewInstruction(1, instruction, nil)
+	source, _ := recipe.NewSource("https://example.com/"+title, recipe.PlatformWeb, "Chef")
+	rec, err := recipe.NewRecipe(userID, title, []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+	if err != nil {
+		t.Fatalf("NewRecipe() error = %v", err)
+	}
+	rec.SetCategory(category)
+	rec.SetCookTime(cook)
+	rec.SetServings(4)
+	return rec
+}
+
+func TestPlanMenuCommand(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+
+	repo := newMockRecipeRepository()
+	_ = repo.Save(ctx, newMenuRecipe(t, userID, "Beef Wellington", recipe.CategoryMeat, "Sear the beef, wrap in pastry, then bake in the oven.", 3*time.Hour))
+	_ = repo.Save(ctx, newMenuRecipe(t, userID, "Roast Chicken", recipe.CategoryMeat, "Roast in the oven.", time.Hour))
+	_ = repo.Save(ctx, newMenuRecipe(t, userID, "Apple Pie", recipe.CategoryDesserts, "Bake in the oven.", 45*time.Minute))
+	_ = repo.Save(ctx, newMenuRecipe(t, userID, "Lemonade", recipe.CategoryBeverages, "Stir.", 0))
+
+	suggester := &mockMenuSuggester{}
+	cmd := NewPlanMenuCommand(repo, suggester)
+	serveAt := time.Date(2026, 12, 24, 19, 0, 0, 0, time.UTC)
+
+	m, err := cmd.Start(ctx, userID, "Christmas dinner", 8, serveAt, "English")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if len(suggester.courses) != 2 || suggester.courses[0] != menu.CourseAppetizer || suggester.courses[1] != menu.CourseSide {
+		t.Errorf("suggestions asked for %v, want [appetizer side]", suggester.courses)
+	}
+
+	var titles []string
+	for _, dish := range m.Selected() {
+		titles = append(titles, dish.Title)
+	}
+	want := []string{"Suggested appetizer", "Roast Chicken", "Suggested side", "Apple Pie"}
+	if len(titles) != len(want) {
+		t.Fatalf("Selected() = %v, want %v", titles, want)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Fatalf("Selected() = %v, want %v", titles, want)
+		}
+	}
+	if got := m.Scale(m.Selected()[1]); got != 2 {
+		t.Errorf("Scale(main) = %v, want 2", got)
+	}
+
+	swapped, err := cmd.Swap(userID, menu.CourseMain)
+	if err != nil {
+		t.Fatalf("Swap() error = %v", err)
+	}
+	if got := swapped.Selected()[1].Title; got != "Beef Wellington" {
+		t.Errorf("after Swap(main) selected %q, want Beef Wellington", got)
+	}
+
+	timeline, err := cmd.Timeline(userID)
+	if err != nil {
+		t.Fatalf("Timeline() error = %v", err)
+	}
+	if !timeline.Start.Equal(serveAt.Add(-150 * time.Minute)) {
+		t.Errorf("timeline starts at %v, want 16:30", timeline.Start)
+	}
+
+	if _, err := cmd.Timeline(shared.NewID()); !errors.Is(err, shared.ErrMenuNotFound) {
+		t.Errorf("Timeline() without a menu error = %v, want ErrMenuNotFound", err)
+	}
+}
@@ -3,19 +3,38 @@ package command
 import (
 	"context"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"receipt-bot/internal/domain/claims"
 	"receipt-bot/internal/domain/matching"
 	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/report"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/user"
 	"receipt-bot/internal/ports"
 )
 
 // ProcessRecipeLinkCommand orchestrates the entire recipe extraction flow
 type ProcessRecipeLinkCommand struct {
-	scraper       ports.ScraperPort
-	llm           ports.LLMPort
-	recipeService *recipe.Service
-	recipeRepo    recipe.Repository
-	messenger     ports.MessengerPort
+	scraper        ports.ScraperPort
+	llm            ports.LLMPort
+	recipeService  *recipe.Service
+	recipeRepo     recipe.Repository
+	messenger      ports.MessengerPort
+	processing     recipe.ProcessingRepository // nil unless processing reports are kept
+	dropTranscript bool                        // transcripts are used for extraction but not stored
+	userRepo       user.Repository             // nil unless users can opt out of keeping transcripts
+	normalizer     matching.IngredientNormalizer
+
+	mu             sync.Mutex
+	pending        map[recipe.UserID][]*recipe.Recipe         // user ID -> recipes found in a compilation, nil once saved
+	held           map[recipe.UserID]*recipe.Recipe           // user ID -> recipe with implausible claims waiting for a check
+	pendingReports map[recipe.UserID]*recipe.ProcessingReport // user ID -> processing report of the pending recipes
+	heldReports    map[recipe.UserID]*recipe.ProcessingReport // user ID -> processing report of the held recipe
 }
 
 // NewProcessRecipeLinkCommand creates a new command
@@ -27,16 +46,62 @@ func NewProcessRecipeLinkCommand(
 	messenger ports.MessengerPort,
 ) *ProcessRecipeLinkCommand {
 	return &ProcessRecipeLinkCommand{
-		scraper:       scraper,
-		llm:           llm,
-		recipeService: recipeService,
-		recipeRepo:    recipeRepo,
-		messenger:     messenger,
+		scraper:        scraper,
+		llm:            llm,
+		recipeService:  recipeService,
+		recipeRepo:     recipeRepo,
+		messenger:      messenger,
+		normalizer:     matching.NewRuleBasedNormalizer(nil),
+		pending:        make(map[recipe.UserID][]*recipe.Recipe),
+		held:           make(map[recipe.UserID]*recipe.Recipe),
+		pendingReports: make(map[recipe.UserID]*recipe.ProcessingReport),
+		heldReports:    make(map[recipe.UserID]*recipe.ProcessingReport),
 	}
 }
 
-// Execute processes a recipe link end-to-end
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *ProcessRecipeLinkCommand) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+}
+
+// SetProcessingReports keeps a report of how long each stage of processing a saved
+// recipe took and what its LLM calls cost
+func (c *ProcessRecipeLinkCommand) SetProcessingReports(repo recipe.ProcessingRepository) {
+	c.processing = repo
+}
+
+// SetStoreTranscripts decides whether saved recipes keep the transcript of their
+// video. Deployments wary of storing copyrighted speech turn it off; transcripts
+// are still used to extract the recipe.
+func (c *ProcessRecipeLinkCommand) SetStoreTranscripts(store bool) {
+	c.dropTranscript = !store
+}
+
+// SetUserRepository lets users opt out of their recipes keeping the transcript and
+// captions they were extracted from
+func (c *ProcessRecipeLinkCommand) SetUserRepository(userRepo user.Repository) {
+	c.userRepo = userRepo
+}
+
+// Execute processes a recipe link end-to-end. When the content holds several recipes,
+// such as a compilation video, none is saved: they are kept for the user to choose
+// from with Pending and SavePending, and shared.ErrMultipleRecipes is returned.
+// When the chat user's recipe states implausible times, servings or amounts, it is not
+// saved either: it is kept for the user to check with Held and SaveHeld, and
+// shared.ErrImplausibleClaims is returned.
 func (c *ProcessRecipeLinkCommand) Execute(ctx context.Context, url string, userID recipe.UserID, chatID int64) (*recipe.Recipe, error) {
+	return c.execute(ctx, url, nil, userID, chatID)
+}
+
+// ExecutePage processes a page the caller already downloaded, such as one clipped
+// by a browser extension, instead of scraping the URL. It behaves like Execute.
+func (c *ProcessRecipeLinkCommand) ExecutePage(ctx context.Context, url string, page *ports.ScrapeResult, userID recipe.UserID) (*recipe.Recipe, error) {
+	return c.execute(ctx, url, page, userID, 0)
+}
+
+// execute processes a link, scraping it unless its page is given
+func (c *ProcessRecipeLinkCommand) execute(ctx context.Context, url string, page *ports.ScrapeResult, userID recipe.UserID, chatID int64) (*recipe.Recipe, error) {
 	// Step 1: Send progress update
 	if c.messenger != nil {
 		_ = c.messenger.SendProgress(ctx, chatID, "🔍 Analyzing link...")
@@ -55,17 +120,243 @@ func (c *ProcessRecipeLinkCommand) Execute(ctx context.Context, url string, user
 		return existingRecipe, nil
 	}
 
+	recipes, processing, err := c.extract(ctx, url, platform, page, userID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 11: Let the user choose what to save from a compilation
+	if len(recipes) > 1 {
+		c.mu.Lock()
+		c.pending[userID] = recipes
+		c.pendingReports[userID] = processing
+		c.mu.Unlock()
+		return nil, shared.ErrMultipleRecipes
+	}
+	rec := recipes[0]
+
+	// Step 12: Let the user check implausible claims; quiet imports save as they are
+	if chatID != 0 && len(claims.Check(rec)) > 0 {
+		c.mu.Lock()
+		c.held[userID] = rec
+		c.heldReports[userID] = processing
+		c.mu.Unlock()
+		return nil, shared.ErrImplausibleClaims
+	}
+
+	// Step 13: Save recipe
+	if err := c.recipeRepo.Save(ctx, rec); err != nil {
+		return nil, &report.StageError{Stage: report.StageSave, Err: fmt.Errorf("failed to save recipe: %w", err)}
+	}
+	c.saveProcessingReport(ctx, processing, rec.ID())
+
+	// Step 14: Success!
+	if c.messenger != nil {
+		_ = c.messenger.SendProgress(ctx, chatID, "✨ Recipe extracted successfully!")
+	}
+
+	return rec, nil
+}
+
+// Extract runs the extraction for a link without saving the recipe, so the user can
+// decide later. Content holding several recipes returns shared.ErrMultipleRecipes.
+func (c *ProcessRecipeLinkCommand) Extract(ctx context.Context, url string, userID recipe.UserID) (*recipe.Recipe, error) {
+	recipes, _, err := c.extract(ctx, url, recipe.DetectPlatform(url), nil, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipes) > 1 {
+		return nil, shared.ErrMultipleRecipes
+	}
+	return recipes[0], nil
+}
+
+// Pending returns the recipes found in the user's last compilation link that are not
+// saved yet, by position; saved ones are nil
+func (c *ProcessRecipeLinkCommand) Pending(userID recipe.UserID) []*recipe.Recipe {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*recipe.Recipe(nil), c.pending[userID]...)
+}
+
+// SavePending saves the recipe at index of the user's pending compilation, or all
+// the recipes not saved yet when index is -1. It returns the saved recipes.
+func (c *ProcessRecipeLinkCommand) SavePending(ctx context.Context, userID recipe.UserID, index int) ([]*recipe.Recipe, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	recipes, ok := c.pending[userID]
+	if !ok {
+		return nil, shared.ErrNoPendingRecipes
+	}
+	if index < -1 || index >= len(recipes) {
+		return nil, shared.ErrInvalidInput
+	}
+
+	var toSave []*recipe.Recipe
+	var positions []int
+	for i, rec := range recipes {
+		if rec == nil || (index != -1 && i != index) {
+			continue
+		}
+		toSave = append(toSave, rec)
+		positions = append(positions, i)
+	}
+	if len(toSave) == 0 {
+		return nil, shared.ErrNoPendingRecipes
+	}
+
+	n, err := c.recipeRepo.SaveAll(ctx, toSave)
+	for _, i := range positions[:n] {
+		recipes[i] = nil
+	}
+	for _, rec := range toSave[:n] {
+		c.saveProcessingReport(ctx, c.pendingReports[userID], rec.ID())
+	}
+	if err != nil {
+		return toSave[:n], fmt.Errorf("failed to save recipe: %w", err)
+	}
+	saved := toSave
+
+	if pendingCount(recipes) == 0 {
+		delete(c.pending, userID)
+		delete(c.pendingReports, userID)
+	}
+	return saved, nil
+}
+
+// pendingCount counts the recipes not saved yet
+func pendingCount(recipes []*recipe.Recipe) int {
+	count := 0
+	for _, rec := range recipes {
+		if rec != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Held returns the user's recipe waiting for a check of its implausible claims, if any
+func (c *ProcessRecipeLinkCommand) Held(userID recipe.UserID) (*recipe.Recipe, []claims.Issue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rec, ok := c.held[userID]
+	if !ok {
+		return nil, nil
+	}
+	return rec, claims.Check(rec)
+}
+
+// SaveHeld saves the user's recipe waiting for a check, as extracted or with its
+// implausible claims fixed
+func (c *ProcessRecipeLinkCommand) SaveHeld(ctx context.Context, userID recipe.UserID, fix bool) (*recipe.Recipe, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rec, ok := c.held[userID]
+	if !ok {
+		return nil, shared.ErrNoHeldRecipe
+	}
+	if fix {
+		rec = claims.Fix(rec)
+	}
+	if err := c.recipeRepo.Save(ctx, rec); err != nil {
+		return nil, fmt.Errorf("failed to save recipe: %w", err)
+	}
+	c.saveProcessingReport(ctx, c.heldReports[userID], rec.ID())
+	delete(c.held, userID)
+	delete(c.heldReports, userID)
+	return rec, nil
+}
+
+// DiscardHeld drops the user's recipe waiting for a check
+func (c *ProcessRecipeLinkCommand) DiscardHeld(userID recipe.UserID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.held, userID)
+	delete(c.heldReports, userID)
+}
+
+// saveProcessingReport keeps the report of an extraction for a recipe that was saved.
+// The report is only for diagnosis, so failing to keep it is logged and ignored.
+func (c *ProcessRecipeLinkCommand) saveProcessingReport(ctx context.Context, processing *recipe.ProcessingReport, recipeID recipe.RecipeID) {
+	if c.processing == nil || processing == nil {
+		return
+	}
+	rep := *processing
+	rep.RecipeID = recipeID
+	if err := c.processing.SaveProcessingReport(ctx, &rep); err != nil {
+		log.Printf("Failed to save processing report of recipe %s: %v", recipeID.String(), err)
+	}
+}
+
+// Reextract runs the extraction again for the source of an existing recipe.
+// It returns an updated copy of the recipe that has not been saved yet, so the
+// caller can record the previous content as a version before persisting it.
+func (c *ProcessRecipeLinkCommand) Reextract(ctx context.Context, recipeID recipe.RecipeID, userID recipe.UserID, chatID int64) (*recipe.Recipe, error) {
+	existing, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("recipe not found: %w", err)
+	}
+	if existing.UserID() != userID {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	if c.messenger != nil {
+		_ = c.messenger.SendProgress(ctx, chatID, "🔁 Re-extracting recipe...")
+	}
+
+	source := existing.Source()
+	found, processing, err := c.extract(ctx, source.URL(), source.Platform(), nil, existing.UserID(), chatID)
+	if err != nil {
+		return nil, err
+	}
+	c.saveProcessingReport(ctx, processing, existing.ID())
+
+	// A recipe from a compilation is re-extracted from its own segment
+	fresh := found[0]
+	if segment := source.Segment(); segment > 0 && segment <= len(found) {
+		fresh = found[segment-1]
+	}
+
+	updated := existing.Clone()
+	updated.ApplySnapshot(fresh.Snapshot())
+	updated.SetProvenance(fresh.Provenance())
+	return updated, nil
+}
+
+// extract scrapes the URL, unless its page is given, and turns its content into validated,
+// unsaved recipes: one, or one per dish for content holding several, marked with their segment.
+// It also returns the report of how long each stage took, without a recipe ID.
+func (c *ProcessRecipeLinkCommand) extract(ctx context.Context, url string, platform recipe.Platform, page *ports.ScrapeResult, userID recipe.UserID, chatID int64) ([]*recipe.Recipe, *recipe.ProcessingReport, error) {
 	// Step 4: Scrape content from URL
 	if c.messenger != nil {
 		_ = c.messenger.SendProgress(ctx, chatID, "📥 Downloading content...")
 	}
 
-	scrapeResult, err := c.scraper.Scrape(ctx, ports.ScrapeRequest{
-		URL:      url,
-		Platform: platform,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("scraping failed: %w", err)
+	// Keep the chat showing "typing…" while the content is scraped and read
+	if c.messenger != nil && chatID != 0 {
+		defer c.messenger.KeepChatAction(ctx, chatID, ports.ChatActionTyping)()
+	}
+
+	processing := &recipe.ProcessingReport{Platform: platform, ProcessedAt: time.Now()}
+	ctx, llmUsage := ports.MeterLLMUsage(ctx)
+
+	scrapeResult := page
+	if scrapeResult == nil {
+		var err error
+		started := time.Now()
+		scrapeResult, err = c.scraper.Scrape(ctx, ports.ScrapeRequest{
+			URL:      url,
+			Platform: platform,
+		})
+		if err != nil {
+			return nil, nil, &report.StageError{Stage: report.StageScrape, Err: fmt.Errorf("%w: %w", shared.ErrScrapeFailed, err)}
+		}
+		processing.Scrape = time.Since(started)
+	}
+	if ms, err := strconv.Atoi(scrapeResult.Metadata["transcription_ms"]); err == nil {
+		processing.Transcription = time.Duration(ms) * time.Millisecond
 	}
 
 	// Step 5: Merge text sources
@@ -73,9 +364,13 @@ func (c *ProcessRecipeLinkCommand) Execute(ctx context.Context, url string, user
 		_ = c.messenger.SendProgress(ctx, chatID, "🎤 Processing audio...")
 	}
 
-	combinedText := c.recipeService.MergeTextSources(scrapeResult.Captions, scrapeResult.Transcript)
+	started := time.Now()
+	onScreenText := c.readOnScreenText(ctx, scrapeResult.KeyFrames, chatID)
+	processing.OnScreenText = time.Since(started)
+	timeline := formatTimeline(scrapeResult.Timeline)
+	combinedText := c.recipeService.MergeTextSources(scrapeResult.Captions, onScreenText, timeline, scrapeResult.Transcript)
 	if combinedText == "" {
-		return nil, fmt.Errorf("no content extracted from URL")
+		return nil, nil, &report.StageError{Stage: report.StageScrape, Err: shared.ErrNoContent}
 	}
 
 	// Log what we're sending to LLM (first 500 chars for debugging)
@@ -83,34 +378,242 @@ func (c *ProcessRecipeLinkCommand) Execute(ctx context.Context, url string, user
 	if len(textPreview) > 500 {
 		textPreview = textPreview[:500] + "..."
 	}
-	fmt.Printf("[DEBUG] Sending to LLM (preview): %s\n", textPreview)
-	fmt.Printf("[DEBUG] Captions length: %d, Transcript length: %d\n", len(scrapeResult.Captions), len(scrapeResult.Transcript))
+	log.Printf("[DEBUG] Sending to LLM (preview): %s\n", textPreview)
+	log.Printf("[DEBUG] Captions length: %d, Transcript length: %d\n", len(scrapeResult.Captions), len(scrapeResult.Transcript))
 
-	// Step 6: Extract recipe using LLM
-	if c.messenger != nil {
-		_ = c.messenger.SendProgress(ctx, chatID, "🤖 Extracting recipe...")
+	// Step 6: Extract recipes using LLM
+	started = time.Now()
+	extractions, err := c.extractRecipes(ctx, scrapeResult, onScreenText, timeline, combinedText, chatID)
+	if err != nil {
+		return nil, nil, &report.StageError{Stage: report.StageExtract, Err: fmt.Errorf("%w: %w", shared.ErrExtractionFailed, err)}
+	}
+	processing.LLM = time.Since(started)
+	usage := llmUsage()
+	processing.Model = usage.Model
+	processing.LLMCalls = usage.Calls
+	processing.InputTokens = usage.InputTokens
+	processing.OutputTokens = usage.OutputTokens
+
+	// Step 7: Validate extractions, keeping the complete ones of a compilation
+	var complete []*ports.RecipeExtraction
+	var invalid error
+	for _, extraction := range extractions {
+		// Log what we got back
+		log.Printf("[DEBUG] LLM returned: %d ingredients, %d instructions, title: %s, variant: %s\n",
+			len(extraction.Ingredients), len(extraction.Instructions), extraction.Title, extraction.Variant)
+
+		if err := validateExtraction(extraction, scrapeResult); err != nil {
+			if invalid == nil {
+				invalid = err
+			}
+			continue
+		}
+		complete = append(complete, extraction)
+	}
+	if len(complete) == 0 {
+		if invalid == nil {
+			invalid = validateExtraction(&ports.RecipeExtraction{}, scrapeResult)
+		}
+		return nil, nil, &report.StageError{Stage: report.StageValidate, Err: invalid}
 	}
 
-	extraction, err := c.llm.ExtractRecipe(ctx, combinedText)
+	// Get author from metadata
+	author := scrapeResult.Metadata["author"]
+	if author == "" {
+		author = recipe.UnknownAuthor
+	}
+
+	// Create source, crediting its creator
+	source, err := recipe.NewSource(url, platform, author)
 	if err != nil {
-		return nil, fmt.Errorf("recipe extraction failed: %w", err)
+		return nil, nil, &report.StageError{Stage: report.StageValidate, Err: fmt.Errorf("failed to create source: %w", err)}
+	}
+	source = source.WithAttribution(attributionFrom(scrapeResult.Metadata))
+
+	transcript, captions := scrapeResult.Transcript, scrapeResult.Captions
+	if c.dropTranscript {
+		transcript = ""
+	}
+	if !c.keepsTranscripts(ctx, userID) {
+		transcript, captions = "", ""
 	}
 
-	// Log what we got back
-	fmt.Printf("[DEBUG] LLM returned: %d ingredients, %d instructions, title: %s\n", 
-		len(extraction.Ingredients), len(extraction.Instructions), extraction.Title)
+	// Step 9: Create recipe entities
+	if c.messenger != nil {
+		_ = c.messenger.SendProgress(ctx, chatID, "💾 Saving recipe...")
+	}
+
+	recipes := make([]*recipe.Recipe, 0, len(complete))
+	for i, extraction := range complete {
+		recipeSource := source
+		if len(complete) > 1 {
+			recipeSource = source.WithSegment(i + 1)
+		}
 
-	// Step 7: Validate extraction
+		rec, err := buildRecipe(userID, extraction, recipeSource, transcript, captions, c.normalizer)
+		if err != nil {
+			return nil, nil, &report.StageError{Stage: report.StageValidate, Err: err}
+		}
+
+		// Step 10: Validate recipe
+		if err := c.recipeService.ValidateRecipe(rec); err != nil {
+			return nil, nil, &report.StageError{Stage: report.StageValidate, Err: fmt.Errorf("recipe validation failed: %w", err)}
+		}
+		recipes = append(recipes, rec)
+	}
+
+	return recipes, processing, nil
+}
+
+// validateExtraction checks the LLM found ingredients and instructions
+func validateExtraction(extraction *ports.RecipeExtraction, scrapeResult *ports.ScrapeResult) error {
 	if len(extraction.Ingredients) == 0 {
 		// Provide more context in the error
-		return nil, fmt.Errorf("no ingredients found in content. Captions had %d chars, transcript had %d chars. LLM may have failed to parse the format", 
-			len(scrapeResult.Captions), len(scrapeResult.Transcript))
+		return fmt.Errorf("no ingredients found in content. Captions had %d chars, transcript had %d chars. LLM may have failed to parse the format: %w",
+			len(scrapeResult.Captions), len(scrapeResult.Transcript), shared.ErrNoIngredients)
 	}
 	if len(extraction.Instructions) == 0 {
-		return nil, fmt.Errorf("no instructions found in content")
+		return fmt.Errorf("no instructions found in content: %w", shared.ErrNoInstructions)
+	}
+	return nil
+}
+
+// extractRecipes runs the LLM extraction, returning every recipe in the content when
+// the LLM can tell several apart. Long transcripts are extracted as a single recipe.
+func (c *ProcessRecipeLinkCommand) extractRecipes(ctx context.Context, scrapeResult *ports.ScrapeResult, onScreenText, timeline, combinedText string, chatID int64) ([]*ports.RecipeExtraction, error) {
+	extractor, ok := c.llm.(ports.MultiRecipeExtractor)
+	if !ok || len(scrapeResult.Transcript) > longTranscriptChars {
+		extraction, err := c.extractRecipe(ctx, scrapeResult, onScreenText, timeline, combinedText, chatID)
+		if err != nil {
+			return nil, err
+		}
+		return []*ports.RecipeExtraction{extraction}, nil
 	}
 
-	// Step 8: Build domain objects
+	if c.messenger != nil {
+		_ = c.messenger.SendProgress(ctx, chatID, "🤖 Extracting recipe...")
+	}
+	return extractor.ExtractRecipes(ctx, combinedText)
+}
+
+// Transcripts longer than longTranscriptChars (about half an hour of speech) are
+// extracted in chunks of transcriptChunkChars, since single prompts truncate or miss items
+const (
+	longTranscriptChars  = 25000
+	transcriptChunkChars = 12000
+)
+
+// extractRecipe runs the LLM extraction on the merged text. When the LLM can consolidate
+// recipes, long transcripts are extracted chunk by chunk, each with the captions and
+// on-screen text, and the partial recipes are then merged into one.
+func (c *ProcessRecipeLinkCommand) extractRecipe(ctx context.Context, scrapeResult *ports.ScrapeResult, onScreenText, timeline, combinedText string, chatID int64) (*ports.RecipeExtraction, error) {
+	consolidator, ok := c.llm.(ports.RecipeConsolidator)
+	if !ok || len(scrapeResult.Transcript) <= longTranscriptChars {
+		if c.messenger != nil {
+			_ = c.messenger.SendProgress(ctx, chatID, "🤖 Extracting recipe...")
+		}
+		return c.llm.ExtractRecipe(ctx, combinedText)
+	}
+
+	chunks := c.recipeService.SplitTranscript(scrapeResult.Transcript, transcriptChunkChars)
+	if c.messenger != nil {
+		_ = c.messenger.SendProgress(ctx, chatID, fmt.Sprintf("🤖 Long video, extracting recipe in %d parts...", len(chunks)))
+	}
+
+	var candidates []*ports.RecipeExtraction
+	for i, chunk := range chunks {
+		text := c.recipeService.MergeTextSources(scrapeResult.Captions, onScreenText, timeline, chunk)
+		candidate, err := c.llm.ExtractRecipe(ctx, text)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Printf("[DEBUG] Extracting part %d/%d failed: %v\n", i+1, len(chunks), err)
+			continue
+		}
+		if len(candidate.Ingredients) == 0 && len(candidate.Instructions) == 0 {
+			continue // Part without recipe content, e.g. an intro or sponsor segment
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no recipe found in any of the %d transcript parts", len(chunks))
+	case 1:
+		return candidates[0], nil
+	}
+
+	if c.messenger != nil {
+		_ = c.messenger.SendProgress(ctx, chatID, "🧩 Combining parts...")
+	}
+	return consolidator.ConsolidateRecipe(ctx, candidates)
+}
+
+// formatTimeline lists the timeline entries as "[seconds] text" lines for the LLM
+func formatTimeline(timeline []ports.TimelineEntry) string {
+	lines := make([]string, 0, len(timeline))
+	for _, entry := range timeline {
+		lines = append(lines, fmt.Sprintf("[%ds] %s", int(entry.Start.Seconds()), entry.Text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// readOnScreenText reads the text shown in the video's key frames. It returns "" when
+// there are no frames, the LLM can't read images or reading fails, since captions
+// and transcript are usually enough on their own.
+func (c *ProcessRecipeLinkCommand) readOnScreenText(ctx context.Context, frames [][]byte, chatID int64) string {
+	reader, ok := c.llm.(ports.FrameReader)
+	if !ok || len(frames) == 0 {
+		return ""
+	}
+
+	if c.messenger != nil {
+		_ = c.messenger.SendProgress(ctx, chatID, "🎞️ Reading on-screen text...")
+	}
+
+	text, err := reader.ReadOnScreenText(ctx, frames)
+	if err != nil {
+		log.Printf("[DEBUG] Reading on-screen text failed: %v\n", err)
+		return ""
+	}
+	return text
+}
+
+// keepsTranscripts reports whether the user's recipes keep the transcript and
+// captions they were extracted from. They do when the user cannot be loaded.
+func (c *ProcessRecipeLinkCommand) keepsTranscripts(ctx context.Context, userID recipe.UserID) bool {
+	if c.userRepo == nil {
+		return true
+	}
+	usr, err := c.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return true
+	}
+	return usr.KeepsTranscripts()
+}
+
+// publishedLayouts are the ways scrapers write the date content was published:
+// dates, timestamps and yt-dlp's upload dates
+var publishedLayouts = []string{"2006-01-02", time.RFC3339, "20060102"}
+
+// attributionFrom reads who published the content and where from the scrape metadata
+func attributionFrom(metadata map[string]string) recipe.Attribution {
+	var published time.Time
+	if raw := strings.TrimSpace(metadata["published"]); raw != "" {
+		for _, layout := range publishedLayouts {
+			if t, err := time.Parse(layout, raw); err == nil {
+				published = t.UTC()
+				break
+			}
+		}
+	}
+	return recipe.NewAttribution(metadata["handle"], metadata["canonical_url"], published)
+}
+
+// buildRecipe creates a recipe entity from the LLM extraction, with its
+// optional fields, translations, normalized ingredients and difficulty set
+func buildRecipe(userID recipe.UserID, extraction *ports.RecipeExtraction, source recipe.Source, transcript, captions string, normalizer matching.IngredientNormalizer) (*recipe.Recipe, error) {
 	ingredients := make([]recipe.Ingredient, 0, len(extraction.Ingredients))
 	for _, ingData := range extraction.Ingredients {
 		ing, err := recipe.NewIngredient(ingData.Name, ingData.Quantity, ingData.Unit, ingData.Notes)
@@ -126,34 +629,20 @@ func (c *ProcessRecipeLinkCommand) Execute(ctx context.Context, url string, user
 		if err != nil {
 			continue // Skip invalid instructions
 		}
+		if instData.Timestamp != nil {
+			inst = inst.WithTimestamp(*instData.Timestamp)
+		}
 		instructions = append(instructions, inst)
 	}
 
-	// Get author from metadata
-	author := scrapeResult.Metadata["author"]
-	if author == "" {
-		author = "Unknown"
-	}
-
-	// Create source
-	source, err := recipe.NewSource(url, platform, author)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create source: %w", err)
-	}
-
-	// Step 9: Create recipe entity
-	if c.messenger != nil {
-		_ = c.messenger.SendProgress(ctx, chatID, "💾 Saving recipe...")
-	}
-
 	rec, err := recipe.NewRecipe(
 		userID,
 		extraction.Title,
 		ingredients,
 		instructions,
 		source,
-		scrapeResult.Transcript,
-		scrapeResult.Captions,
+		transcript,
+		captions,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create recipe: %w", err)
@@ -181,6 +670,9 @@ func (c *ProcessRecipeLinkCommand) Execute(ctx context.Context, url string, user
 		dietaryTags := recipe.ParseDietaryTags(extraction.DietaryTags)
 		rec.SetDietaryTags(dietaryTags)
 	}
+	if len(extraction.Flavors) > 0 {
+		rec.SetFlavors(recipe.ParseFlavors(extraction.Flavors))
+	}
 	if len(extraction.Tags) > 0 {
 		rec.SetTags(extraction.Tags)
 	}
@@ -208,6 +700,9 @@ func (c *ProcessRecipeLinkCommand) Execute(ctx context.Context, url string, user
 			for _, instData := range extraction.TranslatedInstructions {
 				inst, err := recipe.NewInstruction(instData.StepNumber, instData.Text, instData.Duration)
 				if err == nil {
+					if instData.Timestamp != nil {
+						inst = inst.WithTimestamp(*instData.Timestamp)
+					}
 					translatedInsts = append(translatedInsts, inst)
 				}
 			}
@@ -215,8 +710,23 @@ func (c *ProcessRecipeLinkCommand) Execute(ctx context.Context, url string, user
 		rec.SetTranslations(extraction.TranslatedTitle, translatedIngs, translatedInsts)
 	}
 
-	// Step 10: Normalize and cache ingredients for faster matching
-	normalizer := matching.NewRuleBasedNormalizer()
+	// Record where the fields came from; the text of a web page is the page itself
+	if len(extraction.Provenance) > 0 {
+		provenance := make([]recipe.FieldProvenance, 0, len(extraction.Provenance))
+		for _, data := range extraction.Provenance {
+			p, err := recipe.NewFieldProvenance(data.Field, recipe.TextSource(data.Source), data.Confidence)
+			if err != nil {
+				continue
+			}
+			if p.Source() == recipe.TextSourceCaptions && source.Platform() == recipe.PlatformWeb {
+				p = p.WithSource(recipe.TextSourceWebpage)
+			}
+			provenance = append(provenance, p)
+		}
+		rec.SetProvenance(provenance)
+	}
+
+	// Normalize and cache ingredients for faster matching
 	normalizedIngredients := make([]string, 0, len(ingredients))
 	for _, ing := range ingredients {
 		normalized := normalizer.Normalize(ing.Name())
@@ -226,20 +736,8 @@ func (c *ProcessRecipeLinkCommand) Execute(ctx context.Context, url string, user
 	}
 	rec.SetNormalizedIngredients(normalizedIngredients)
 
-	// Step 11: Validate recipe
-	if err := c.recipeService.ValidateRecipe(rec); err != nil {
-		return nil, fmt.Errorf("recipe validation failed: %w", err)
-	}
-
-	// Step 13: Save recipe
-	if err := c.recipeRepo.Save(ctx, rec); err != nil {
-		return nil, fmt.Errorf("failed to save recipe: %w", err)
-	}
-
-	// Step 14: Success!
-	if c.messenger != nil {
-		_ = c.messenger.SendProgress(ctx, chatID, "✨ Recipe extracted successfully!")
-	}
+	// Score difficulty from ingredient count, steps, total time and techniques
+	rec.UpdateDifficulty()
 
 	return rec, nil
 }
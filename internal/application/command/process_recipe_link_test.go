@@ -2,9 +2,13 @@ package command
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"receipt-bot/internal/adapters/memory"
 	"receipt-bot/internal/domain/recipe"
 	"receipt-bot/internal/domain/shared"
 	"receipt-bot/internal/ports"
@@ -36,6 +40,10 @@ func (m *mockLLMPort) ExtractRecipe(ctx context.Context, text string) (*ports.Re
 	return m.extraction, nil
 }
 
+func (m *mockLLMPort) TranslateRecipe(ctx context.Context, input *ports.RecipeTranslationInput, targetLang string) (*ports.RecipeTranslationOutput, error) {
+	return nil, nil
+}
+
 type mockRecipeRepository struct {
 	recipes map[string]*recipe.Recipe
 }
@@ -51,6 +59,15 @@ func (m *mockRecipeRepository) Save(ctx context.Context, rec *recipe.Recipe) err
 	return nil
 }
 
+func (m *mockRecipeRepository) SaveAll(ctx context.Context, recipes []*recipe.Recipe) (int, error) {
+	for i, rec := range recipes {
+		if err := m.Save(ctx, rec); err != nil {
+			return i, err
+		}
+	}
+	return len(recipes), nil
+}
+
 func (m *mockRecipeRepository) FindByID(ctx context.Context, id recipe.RecipeID) (*recipe.Recipe, error) {
 	if rec, ok := m.recipes[id.String()]; ok {
 		return rec, nil
@@ -68,6 +85,116 @@ func (m *mockRecipeRepository) FindByUserID(ctx context.Context, userID recipe.U
 	return results, nil
 }
 
+func (m *mockRecipeRepository) FindSummariesByUserID(ctx context.Context, userID recipe.UserID) ([]*recipe.Recipe, error) {
+	recipes, err := m.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]*recipe.Recipe, len(recipes))
+	for i, rec := range recipes {
+		summaries[i] = rec.Summary()
+	}
+	return summaries, nil
+}
+
+func (m *mockRecipeRepository) FindByUserIDAndCategory(ctx context.Context, userID recipe.UserID, category recipe.Category) ([]*recipe.Recipe, error) {
+	var results []*recipe.Recipe
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID && rec.Category() == category {
+			results = append(results, rec)
+		}
+	}
+	return results, nil
+}
+
+func (m *mockRecipeRepository) FindByUserIDAndFilters(ctx context.Context, userID recipe.UserID, category *recipe.Category, dietaryTags []recipe.DietaryTag, language string) ([]*recipe.Recipe, error) {
+	return m.FindByUserID(ctx, userID)
+}
+
+func (m *mockRecipeRepository) SearchByIngredient(ctx context.Context, userID recipe.UserID, ingredient string) ([]*recipe.Recipe, error) {
+	return nil, nil
+}
+
+func (m *mockRecipeRepository) SearchByIngredientFilter(ctx context.Context, userID recipe.UserID, filter recipe.IngredientFilter) ([]*recipe.Recipe, error) {
+	return nil, nil
+}
+
+func (m *mockRecipeRepository) GetCategoryCounts(ctx context.Context, userID recipe.UserID) (map[recipe.Category]int, error) {
+	counts := make(map[recipe.Category]int)
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID {
+			counts[rec.Category()]++
+		}
+	}
+	return counts, nil
+}
+
+func (m *mockRecipeRepository) FindByUserIDAndAuthor(ctx context.Context, userID recipe.UserID, author string) ([]*recipe.Recipe, error) {
+	var result []*recipe.Recipe
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID && rec.Source().IsFrom(author) {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRecipeRepository) GetAuthorCounts(ctx context.Context, userID recipe.UserID) (map[string]int, error) {
+	recipes, err := m.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return recipe.CountAuthors(recipes), nil
+}
+
+func (m *mockRecipeRepository) FindByUserIDAndPlatform(ctx context.Context, userID recipe.UserID, platform recipe.Platform) ([]*recipe.Recipe, error) {
+	var result []*recipe.Recipe
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID && rec.Source().Platform() == platform {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRecipeRepository) GetPlatformCounts(ctx context.Context, userID recipe.UserID) (map[recipe.Platform]int, error) {
+	recipes, err := m.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return recipe.CountPlatforms(recipes), nil
+}
+
+func (m *mockRecipeRepository) FindByUserIDAndFlavor(ctx context.Context, userID recipe.UserID, flavor recipe.Flavor) ([]*recipe.Recipe, error) {
+	var result []*recipe.Recipe
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID && rec.HasFlavor(flavor) {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRecipeRepository) FindByUserIDAndDateRange(ctx context.Context, userID recipe.UserID, saved recipe.DateRange) ([]*recipe.Recipe, error) {
+	var result []*recipe.Recipe
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID && saved.Contains(rec.CreatedAt()) {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRecipeRepository) StripTranscripts(ctx context.Context, savedBefore time.Time) (int, error) {
+	stripped := 0
+	for _, rec := range m.recipes {
+		if rec.CreatedAt().Before(savedBefore) && rec.StripTranscript() {
+			stripped++
+		}
+	}
+	return stripped, nil
+}
+
 func (m *mockRecipeRepository) FindBySourceURL(ctx context.Context, sourceURL string) (*recipe.Recipe, error) {
 	for _, rec := range m.recipes {
 		if rec.Source().URL() == sourceURL {
@@ -111,6 +238,10 @@ func (m *mockMessengerPort) SendError(ctx context.Context, chatID int64, errorMs
 	return nil
 }
 
+func (m *mockMessengerPort) KeepChatAction(ctx context.Context, chatID int64, action ports.ChatAction) func() {
+	return func() {}
+}
+
 func TestProcessRecipeLinkCommand_Execute(t *testing.T) {
 	ctx := context.Background()
 	userID := shared.NewID()
@@ -234,7 +365,435 @@ func TestProcessRecipeLinkCommand_Execute_NoIngredients(t *testing.T) {
 	_, err := cmd.Execute(ctx, "https://youtube.com/watch?v=abc", userID, 12345)
 
 	// Should fail because no ingredients
-	if err == nil {
-		t.Error("Execute() expected error for empty ingredients, got nil")
+	if !errors.Is(err, shared.ErrNoIngredients) {
+		t.Errorf("Execute() error = %v, want %v", err, shared.ErrNoIngredients)
+	}
+}
+
+func TestProcessRecipeLinkCommand_Execute_HoldsImplausibleClaims(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+
+	cookTime := 10 * time.Minute
+	braise := 3 * time.Hour
+	servings := 400
+
+	newCommand := func(repo *mockRecipeRepository) *ProcessRecipeLinkCommand {
+		return NewProcessRecipeLinkCommand(
+			&mockScraperPort{result: &ports.ScrapeResult{
+				Captions:    "Sunday beef stew",
+				OriginalURL: "https://youtube.com/watch?v=stew",
+				Metadata:    map[string]string{},
+			}},
+			&mockLLMPort{extraction: &ports.RecipeExtraction{
+				Title: "Beef Stew",
+				Ingredients: []ports.IngredientData{
+					{Name: "beef chuck", Quantity: "1200", Unit: "kg"},
+					{Name: "carrots", Quantity: "3"},
+				},
+				Instructions: []ports.InstructionData{
+					{StepNumber: 1, Text: "Brown the beef"},
+					{StepNumber: 2, Text: "Braise", Duration: &braise},
+				},
+				CookTime: &cookTime,
+				Servings: &servings,
+			}},
+			recipe.NewService(),
+			repo,
+			nil,
+		)
+	}
+
+	repo := newMockRecipeRepository()
+	cmd := newCommand(repo)
+
+	_, err := cmd.Execute(ctx, "https://youtube.com/watch?v=stew", userID, 12345)
+	if !errors.Is(err, shared.ErrImplausibleClaims) {
+		t.Fatalf("Execute() error = %v, want %v", err, shared.ErrImplausibleClaims)
+	}
+	if len(repo.recipes) != 0 {
+		t.Fatal("Execute() saved a recipe with implausible claims before the user checked it")
+	}
+
+	held, issues := cmd.Held(userID)
+	if held == nil || len(issues) != 3 {
+		t.Fatalf("Held() = %v, %d issues, want the recipe with 3 issues", held, len(issues))
+	}
+
+	saved, err := cmd.SaveHeld(ctx, userID, true)
+	if err != nil {
+		t.Fatalf("SaveHeld() error = %v", err)
+	}
+	if saved.Ingredients()[0].Unit() != "g" || saved.Servings() != nil || *saved.CookTime() != braise {
+		t.Errorf("SaveHeld(fix) = %s, %v servings, %v cook time, want the claims fixed",
+			saved.Ingredients()[0], saved.Servings(), *saved.CookTime())
+	}
+	if len(repo.recipes) != 1 {
+		t.Errorf("repository has %d recipes, want 1", len(repo.recipes))
+	}
+	if _, err := cmd.SaveHeld(ctx, userID, true); !errors.Is(err, shared.ErrNoHeldRecipe) {
+		t.Errorf("second SaveHeld() error = %v, want %v", err, shared.ErrNoHeldRecipe)
+	}
+
+	// Quiet imports have nobody to ask, so the recipe is saved as extracted
+	repo = newMockRecipeRepository()
+	rec, err := newCommand(repo).Execute(ctx, "https://youtube.com/watch?v=stew", userID, 0)
+	if err != nil || rec.Ingredients()[0].Unit() != "kg" {
+		t.Errorf("quiet Execute() = %v, %v, want the recipe saved as extracted", rec, err)
+	}
+}
+
+func TestProcessRecipeLinkCommand_Execute_ScrapeFailed(t *testing.T) {
+	mockScraper := &mockScraperPort{err: fmt.Errorf("connection refused")}
+	cmd := NewProcessRecipeLinkCommand(mockScraper, &mockLLMPort{}, recipe.NewService(), newMockRecipeRepository(), nil)
+
+	_, err := cmd.Execute(context.Background(), "https://youtube.com/watch?v=abc", shared.NewID(), 12345)
+
+	if !errors.Is(err, shared.ErrScrapeFailed) {
+		t.Errorf("Execute() error = %v, want %v", err, shared.ErrScrapeFailed)
+	}
+	if !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Execute() error = %q, want the scraper error kept", err)
+	}
+}
+
+// mockFrameReadingLLM reads frames as text and records what it was asked to extract from
+type mockFrameReadingLLM struct {
+	mockLLMPort
+	extractedFrom string
+}
+
+func (m *mockFrameReadingLLM) ExtractRecipe(ctx context.Context, text string) (*ports.RecipeExtraction, error) {
+	m.extractedFrom = text
+	return m.mockLLMPort.ExtractRecipe(ctx, text)
+}
+
+func (m *mockFrameReadingLLM) ReadOnScreenText(ctx context.Context, frames [][]byte) (string, error) {
+	var text string
+	for _, frame := range frames {
+		text += string(frame) + "\n"
+	}
+	return text, nil
+}
+
+func TestProcessRecipeLinkCommand_Execute_MergesOnScreenText(t *testing.T) {
+	ctx := context.Background()
+
+	mockScraper := &mockScraperPort{
+		result: &ports.ScrapeResult{
+			Captions:    "Chocolate cake",
+			Transcript:  "Mix everything and bake.",
+			KeyFrames:   [][]byte{[]byte("2 cups flour"), []byte("1 cup sugar")},
+			OriginalURL: "https://www.tiktok.com/@chef/video/1",
+			Metadata:    map[string]string{},
+		},
+	}
+
+	mockLLM := &mockFrameReadingLLM{
+		mockLLMPort: mockLLMPort{
+			extraction: &ports.RecipeExtraction{
+				Title:        "Chocolate Cake",
+				Ingredients:  []ports.IngredientData{{Name: "flour", Quantity: "2", Unit: "cups"}},
+				Instructions: []ports.InstructionData{{StepNumber: 1, Text: "Mix and bake"}},
+			},
+		},
+	}
+
+	cmd := NewProcessRecipeLinkCommand(
+		mockScraper,
+		mockLLM,
+		recipe.NewService(),
+		newMockRecipeRepository(),
+		nil, // No messenger
+	)
+
+	if _, err := cmd.Execute(ctx, "https://www.tiktok.com/@chef/video/1", shared.NewID(), 12345); err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+
+	for _, want := range []string{"ON-SCREEN TEXT:", "2 cups flour", "1 cup sugar", "Mix everything and bake."} {
+		if !strings.Contains(mockLLM.extractedFrom, want) {
+			t.Errorf("extraction input missing %q:\n%s", want, mockLLM.extractedFrom)
+		}
+	}
+}
+
+// mockMeteredLLM records the tokens of its calls like the real adapters do
+type mockMeteredLLM struct {
+	mockFrameReadingLLM
+}
+
+func (m *mockMeteredLLM) ExtractRecipe(ctx context.Context, text string) (*ports.RecipeExtraction, error) {
+	ports.RecordLLMUsage(ctx, "test-model", 1200, 300)
+	return m.mockFrameReadingLLM.ExtractRecipe(ctx, text)
+}
+
+func (m *mockMeteredLLM) ReadOnScreenText(ctx context.Context, frames [][]byte) (string, error) {
+	ports.RecordLLMUsage(ctx, "test-model", 800, 50)
+	return m.mockFrameReadingLLM.ReadOnScreenText(ctx, frames)
+}
+
+func TestProcessRecipeLinkCommand_Execute_KeepsProcessingReport(t *testing.T) {
+	ctx := context.Background()
+
+	mockScraper := &mockScraperPort{
+		result: &ports.ScrapeResult{
+			Captions:    "Chocolate cake",
+			Transcript:  "Mix everything and bake.",
+			KeyFrames:   [][]byte{[]byte("2 cups flour")},
+			OriginalURL: "https://www.tiktok.com/@chef/video/1",
+			Metadata:    map[string]string{"transcription_ms": "4200"},
+		},
+	}
+	mockLLM := &mockMeteredLLM{mockFrameReadingLLM{
+		mockLLMPort: mockLLMPort{
+			extraction: &ports.RecipeExtraction{
+				Title:        "Chocolate Cake",
+				Ingredients:  []ports.IngredientData{{Name: "flour", Quantity: "2", Unit: "cups"}},
+				Instructions: []ports.InstructionData{{StepNumber: 1, Text: "Mix and bake"}},
+			},
+		},
+	}}
+	reports := memory.NewProcessingReportRepository()
+
+	cmd := NewProcessRecipeLinkCommand(mockScraper, mockLLM, recipe.NewService(), newMockRecipeRepository(), nil)
+	cmd.SetProcessingReports(reports)
+
+	rec, err := cmd.Execute(ctx, "https://www.tiktok.com/@chef/video/1", shared.NewID(), 12345)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+
+	rep, err := reports.FindProcessingReport(ctx, rec.ID())
+	if err != nil {
+		t.Fatalf("FindProcessingReport() unexpected error = %v", err)
+	}
+	if rep.Platform != recipe.PlatformTikTok {
+		t.Errorf("Platform = %v, want %v", rep.Platform, recipe.PlatformTikTok)
+	}
+	if rep.Transcription != 4200*time.Millisecond {
+		t.Errorf("Transcription = %v, want 4.2s", rep.Transcription)
+	}
+	// The on-screen text and the extraction calls add up
+	if rep.Model != "test-model" || rep.LLMCalls != 2 || rep.InputTokens != 2000 || rep.OutputTokens != 350 {
+		t.Errorf("LLM usage = %s, %d calls, %d in, %d out; want test-model, 2 calls, 2000 in, 350 out",
+			rep.Model, rep.LLMCalls, rep.InputTokens, rep.OutputTokens)
+	}
+}
+
+func TestProcessRecipeLinkCommand_Execute_CreditsCreatorWithoutStoringTranscript(t *testing.T) {
+	mockScraper := &mockScraperPort{
+		result: &ports.ScrapeResult{
+			Captions:    "Chocolate cake",
+			Transcript:  "Mix flour and sugar, then bake.",
+			OriginalURL: "https://vm.tiktok.com/abc",
+			Metadata: map[string]string{
+				"author":        "Chef Ana",
+				"handle":        "chefana",
+				"canonical_url": "https://www.tiktok.com/@chefana/video/1",
+				"published":     "20240302",
+			},
+		},
+	}
+	mockLLM := &mockFrameReadingLLM{
+		mockLLMPort: mockLLMPort{
+			extraction: &ports.RecipeExtraction{
+				Title:        "Chocolate Cake",
+				Ingredients:  []ports.IngredientData{{Name: "flour", Quantity: "2", Unit: "cups"}},
+				Instructions: []ports.InstructionData{{StepNumber: 1, Text: "Mix and bake"}},
+			},
+		},
+	}
+
+	cmd := NewProcessRecipeLinkCommand(mockScraper, mockLLM, recipe.NewService(), newMockRecipeRepository(), nil)
+	cmd.SetStoreTranscripts(false)
+
+	rec, err := cmd.Execute(context.Background(), "https://vm.tiktok.com/abc", shared.NewID(), 12345)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+
+	want := recipe.NewAttribution("@chefana", "https://www.tiktok.com/@chefana/video/1", time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+	if got := rec.Source().Attribution(); got != want {
+		t.Errorf("Attribution = %+v, want %+v", got, want)
+	}
+	if rec.Transcript() != "" {
+		t.Errorf("Transcript = %q, want it not stored", rec.Transcript())
+	}
+	// The transcript is still read for the extraction
+	if !strings.Contains(mockLLM.extractedFrom, "Mix flour and sugar") {
+		t.Errorf("expected the transcript in the extraction input, got %q", mockLLM.extractedFrom)
+	}
+}
+
+func TestProcessRecipeLinkCommand_Execute_LinksStepsToTimeline(t *testing.T) {
+	ctx := context.Background()
+
+	mockScraper := &mockScraperPort{
+		result: &ports.ScrapeResult{
+			Captions:   "Pancakes",
+			Transcript: "Mix the batter, then fry the pancakes.",
+			Timeline: []ports.TimelineEntry{
+				{Start: 0, Text: "Intro"},
+				{Start: 75 * time.Second, Text: "Frying"},
+			},
+			OriginalURL: "https://www.youtube.com/watch?v=pancakes",
+			Metadata:    map[string]string{},
+		},
+	}
+
+	frying := 75 * time.Second
+	mockLLM := &mockFrameReadingLLM{
+		mockLLMPort: mockLLMPort{
+			extraction: &ports.RecipeExtraction{
+				Title:       "Pancakes",
+				Ingredients: []ports.IngredientData{{Name: "flour", Quantity: "200", Unit: "g"}},
+				Instructions: []ports.InstructionData{
+					{StepNumber: 1, Text: "Mix the batter"},
+					{StepNumber: 2, Text: "Fry the pancakes", Timestamp: &frying},
+				},
+			},
+		},
+	}
+
+	cmd := NewProcessRecipeLinkCommand(
+		mockScraper,
+		mockLLM,
+		recipe.NewService(),
+		newMockRecipeRepository(),
+		nil, // No messenger
+	)
+
+	rec, err := cmd.Execute(ctx, "https://www.youtube.com/watch?v=pancakes", shared.NewID(), 12345)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+
+	for _, want := range []string{"VIDEO TIMELINE:", "[0s] Intro", "[75s] Frying"} {
+		if !strings.Contains(mockLLM.extractedFrom, want) {
+			t.Errorf("extraction input missing %q:\n%s", want, mockLLM.extractedFrom)
+		}
+	}
+
+	steps := rec.Instructions()
+	if steps[0].Timestamp() != nil {
+		t.Errorf("step 1 Timestamp() = %v, want nil", *steps[0].Timestamp())
+	}
+	if steps[1].Timestamp() == nil || *steps[1].Timestamp() != frying {
+		t.Errorf("step 2 Timestamp() = %v, want %v", steps[1].Timestamp(), frying)
+	}
+}
+
+func TestProcessRecipeLinkCommand_Execute_RecordsProvenance(t *testing.T) {
+	ctx := context.Background()
+
+	mockScraper := &mockScraperPort{
+		result: &ports.ScrapeResult{
+			Captions:    "INGREDIENTS:\n200 g flour\nINSTRUCTIONS:\n1. Bake",
+			OriginalURL: "https://example.com/bread",
+			Metadata:    map[string]string{},
+		},
+	}
+
+	mockLLM := &mockLLMPort{
+		extraction: &ports.RecipeExtraction{
+			Title:        "Bread",
+			Ingredients:  []ports.IngredientData{{Name: "flour", Quantity: "200", Unit: "g"}},
+			Instructions: []ports.InstructionData{{StepNumber: 1, Text: "Bake"}},
+			Provenance: []ports.ProvenanceData{
+				{Field: recipe.FieldQuantities, Source: "captions", Confidence: 0.95},
+				{Field: recipe.FieldServings, Source: "made-up", Confidence: 0.2},
+			},
+		},
+	}
+
+	cmd := NewProcessRecipeLinkCommand(
+		mockScraper,
+		mockLLM,
+		recipe.NewService(),
+		newMockRecipeRepository(),
+		nil, // No messenger
+	)
+
+	rec, err := cmd.Execute(ctx, "https://example.com/bread", shared.NewID(), 12345)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+
+	// The text of a web page comes from the page itself
+	quantities, ok := rec.ProvenanceOf(recipe.FieldQuantities)
+	if !ok || quantities.Source() != recipe.TextSourceWebpage {
+		t.Errorf("quantities provenance = %+v, %v; want webpage", quantities, ok)
+	}
+	servings, ok := rec.ProvenanceOf(recipe.FieldServings)
+	if !ok || servings.Source() != recipe.TextSourceInferred {
+		t.Errorf("servings provenance = %+v, %v; want inferred", servings, ok)
+	}
+	if rec.NeedsQuantityCheck() {
+		t.Error("NeedsQuantityCheck() = true, want false")
+	}
+}
+
+// mockConsolidatingLLM extracts one ingredient per transcript part and merges the parts
+type mockConsolidatingLLM struct {
+	mockLLMPort
+	parts        int
+	consolidated []*ports.RecipeExtraction
+}
+
+func (m *mockConsolidatingLLM) ExtractRecipe(ctx context.Context, text string) (*ports.RecipeExtraction, error) {
+	m.parts++
+	return &ports.RecipeExtraction{
+		Title:        "Slow Roast Lamb",
+		Ingredients:  []ports.IngredientData{{Name: fmt.Sprintf("ingredient %d", m.parts), Quantity: "1"}},
+		Instructions: []ports.InstructionData{{StepNumber: 1, Text: fmt.Sprintf("step %d", m.parts)}},
+	}, nil
+}
+
+func (m *mockConsolidatingLLM) ConsolidateRecipe(ctx context.Context, candidates []*ports.RecipeExtraction) (*ports.RecipeExtraction, error) {
+	m.consolidated = candidates
+	merged := &ports.RecipeExtraction{Title: candidates[0].Title}
+	for _, candidate := range candidates {
+		merged.Ingredients = append(merged.Ingredients, candidate.Ingredients...)
+		merged.Instructions = append(merged.Instructions, candidate.Instructions...)
+	}
+	return merged, nil
+}
+
+func TestProcessRecipeLinkCommand_Execute_LongTranscriptInParts(t *testing.T) {
+	ctx := context.Background()
+
+	transcript := strings.Repeat("Now we keep basting the lamb with its juices. ", longTranscriptChars/40)
+	mockScraper := &mockScraperPort{
+		result: &ports.ScrapeResult{
+			Captions:    "Slow roast lamb",
+			Transcript:  transcript,
+			OriginalURL: "https://youtube.com/watch?v=long",
+			Metadata:    map[string]string{},
+		},
+	}
+	mockLLM := &mockConsolidatingLLM{}
+
+	cmd := NewProcessRecipeLinkCommand(
+		mockScraper,
+		mockLLM,
+		recipe.NewService(),
+		newMockRecipeRepository(),
+		nil, // No messenger
+	)
+
+	rec, err := cmd.Execute(ctx, "https://youtube.com/watch?v=long", shared.NewID(), 12345)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+
+	if mockLLM.parts < 2 {
+		t.Fatalf("transcript of %d chars extracted in %d parts, want several", len(transcript), mockLLM.parts)
+	}
+	if len(mockLLM.consolidated) != mockLLM.parts {
+		t.Errorf("consolidated %d parts, want %d", len(mockLLM.consolidated), mockLLM.parts)
+	}
+	if len(rec.Ingredients()) != mockLLM.parts {
+		t.Errorf("Ingredients count = %d, want one from each of the %d parts", len(rec.Ingredients()), mockLLM.parts)
 	}
 }
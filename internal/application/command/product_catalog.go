@@ -0,0 +1,80 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"receipt-bot/internal/domain/nutrition"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// ProductCatalog looks products up by barcode, caching what Open Food Facts
+// (or another lookup) returns so each product is fetched at most once per nutrition.ProductTTL
+type ProductCatalog struct {
+	repo   nutrition.Repository
+	lookup ports.ProductLookup
+}
+
+// NewProductCatalog creates a new product catalog
+func NewProductCatalog(repo nutrition.Repository, lookup ports.ProductLookup) *ProductCatalog {
+	return &ProductCatalog{
+		repo:   repo,
+		lookup: lookup,
+	}
+}
+
+// LookupBarcode implements the ProductLookup interface
+func (c *ProductCatalog) LookupBarcode(ctx context.Context, barcode string) (*ports.Product, error) {
+	product, err := c.Product(ctx, barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ports.Product{
+		Barcode:   product.Barcode,
+		Name:      product.Name,
+		Brand:     product.Brand,
+		Quantity:  product.Quantity,
+		Nutrition: product.Facts,
+	}, nil
+}
+
+// Product returns the product data for a barcode, from the cache when it is fresh.
+// Stale data is still returned if the lookup fails.
+func (c *ProductCatalog) Product(ctx context.Context, barcode string) (*nutrition.Product, error) {
+	cached, err := c.repo.FindProduct(ctx, barcode)
+	if err != nil && !errors.Is(err, shared.ErrProductNotFound) {
+		return nil, fmt.Errorf("failed to get cached product: %w", err)
+	}
+	if cached != nil && !cached.IsStale(time.Now()) {
+		return cached, nil
+	}
+
+	found, err := c.lookup.LookupBarcode(ctx, barcode)
+	if err != nil {
+		if cached != nil && !errors.Is(err, shared.ErrProductNotFound) {
+			log.Printf("Product lookup for %s failed, using cached data: %v", barcode, err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	product := &nutrition.Product{
+		Barcode:   barcode,
+		Name:      found.Name,
+		Brand:     found.Brand,
+		Quantity:  found.Quantity,
+		Facts:     found.Nutrition,
+		FetchedAt: time.Now(),
+	}
+	if err := c.repo.SaveProduct(ctx, product); err != nil {
+		// The product is still usable, it will just be fetched again next time
+		log.Printf("Failed to cache product %s: %v", barcode, err)
+	}
+
+	return product, nil
+}
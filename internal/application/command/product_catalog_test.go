@@ -0,0 +1,119 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/nutrition"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+type mockProductLookup struct {
+	products map[string]*ports.Product
+	err      error
+	calls    int
+}
+
+func (m *mockProductLookup) LookupBarcode(ctx context.Context, barcode string) (*ports.Product, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	if p, ok := m.products[barcode]; ok {
+		return p, nil
+	}
+	return nil, shared.ErrProductNotFound
+}
+
+type mockNutritionRepo struct {
+	products map[string]*nutrition.Product
+}
+
+func (m *mockNutritionRepo) SaveProduct(ctx context.Context, product *nutrition.Product) error {
+	m.products[product.Barcode] = product
+	return nil
+}
+
+func (m *mockNutritionRepo) FindProduct(ctx context.Context, barcode string) (*nutrition.Product, error) {
+	if p, ok := m.products[barcode]; ok {
+		return p, nil
+	}
+	return nil, shared.ErrProductNotFound
+}
+
+func (m *mockNutritionRepo) SavePantryProduct(ctx context.Context, link *nutrition.PantryProduct) error {
+	return nil
+}
+
+func (m *mockNutritionRepo) FindPantryProducts(ctx context.Context, userID nutrition.UserID) ([]*nutrition.PantryProduct, error) {
+	return nil, nil
+}
+
+func (m *mockNutritionRepo) SaveGoals(ctx context.Context, goals *nutrition.Goals) error {
+	return nil
+}
+
+func (m *mockNutritionRepo) FindGoals(ctx context.Context, userID nutrition.UserID) (*nutrition.Goals, error) {
+	return nil, shared.ErrNoMacroGoals
+}
+
+func (m *mockNutritionRepo) DeleteGoals(ctx context.Context, userID nutrition.UserID) error {
+	return nil
+}
+
+func (m *mockNutritionRepo) SaveMeal(ctx context.Context, meal *nutrition.Meal) error {
+	return nil
+}
+
+func (m *mockNutritionRepo) FindMeals(ctx context.Context, userID nutrition.UserID, since time.Time) ([]*nutrition.Meal, error) {
+	return nil, nil
+}
+
+func TestProductCatalog_CachesLookups(t *testing.T) {
+	lookup := &mockProductLookup{products: map[string]*ports.Product{
+		"8076800195057": {Name: "Spaghetti", Quantity: "500 g", Nutrition: &nutrition.Facts{EnergyKcal: 359}},
+	}}
+	repo := &mockNutritionRepo{products: make(map[string]*nutrition.Product)}
+	catalog := NewProductCatalog(repo, lookup)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		product, err := catalog.LookupBarcode(ctx, "8076800195057")
+		if err != nil {
+			t.Fatalf("LookupBarcode() error = %v", err)
+		}
+		if product.Name != "Spaghetti" || product.Nutrition.EnergyKcal != 359 {
+			t.Errorf("LookupBarcode() = %+v", product)
+		}
+	}
+	if lookup.calls != 1 {
+		t.Errorf("lookup called %d times, want 1", lookup.calls)
+	}
+
+	if _, err := catalog.LookupBarcode(ctx, "0000000000000"); !errors.Is(err, shared.ErrProductNotFound) {
+		t.Errorf("LookupBarcode() error = %v, want ErrProductNotFound", err)
+	}
+}
+
+func TestProductCatalog_StaleCacheOnLookupFailure(t *testing.T) {
+	lookup := &mockProductLookup{err: errors.New("service unavailable")}
+	repo := &mockNutritionRepo{products: map[string]*nutrition.Product{
+		"8076800195057": {
+			Barcode:   "8076800195057",
+			Name:      "Spaghetti",
+			FetchedAt: time.Now().Add(-nutrition.ProductTTL - time.Hour),
+		},
+	}}
+	catalog := NewProductCatalog(repo, lookup)
+
+	product, err := catalog.Product(context.Background(), "8076800195057")
+	if err != nil {
+		t.Fatalf("Product() error = %v", err)
+	}
+	if product.Name != "Spaghetti" || lookup.calls != 1 {
+		t.Errorf("Product() = %+v after %d lookups, want stale cached data after 1", product, lookup.calls)
+	}
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/user"
+	"receipt-bot/internal/ports"
+)
+
+// audioWords are the words a recipe is read aloud with in one language
+type audioWords struct {
+	ingredients string
+	steps       string
+	step        string // followed by the step number
+}
+
+var recipeAudioWords = map[user.Language]audioWords{
+	user.LanguageEnglish:    {ingredients: "Ingredients", steps: "Steps", step: "Step"},
+	user.LanguagePortuguese: {ingredients: "Ingredientes", steps: "Modo de preparo", step: "Passo"},
+}
+
+// RecipeAudioCommand reads recipes aloud, for cooking with busy hands
+type RecipeAudioCommand struct {
+	synthesizer ports.SpeechSynthesizer
+}
+
+// NewRecipeAudioCommand creates a new command
+func NewRecipeAudioCommand(synthesizer ports.SpeechSynthesizer) *RecipeAudioCommand {
+	return &RecipeAudioCommand{synthesizer: synthesizer}
+}
+
+// Execute reads the recipe's title, ingredients and then steps aloud in the language.
+// A long recipe is read in several parts, each at most ports.MaxSpeechText long;
+// the audio of each part is returned in order.
+func (c *RecipeAudioCommand) Execute(ctx context.Context, rec *dto.RecipeDTO, language user.Language) ([][]byte, error) {
+	var parts [][]byte
+	for _, text := range splitSpeech(recipeScript(rec, language), ports.MaxSpeechText) {
+		audio, err := c.synthesizer.Synthesize(ctx, text, string(language))
+		if err != nil {
+			return nil, fmt.Errorf("failed to synthesize recipe audio: %w", err)
+		}
+		parts = append(parts, audio)
+	}
+	return parts, nil
+}
+
+// recipeScript returns what is read aloud, one sentence per line
+func recipeScript(rec *dto.RecipeDTO, language user.Language) string {
+	words, ok := recipeAudioWords[language]
+	if !ok {
+		words = recipeAudioWords[user.LanguageEnglish]
+	}
+
+	lines := []string{spokenSentence(rec.Title)}
+	if len(rec.Ingredients) > 0 {
+		lines = append(lines, words.ingredients+":")
+		for _, ing := range rec.Ingredients {
+			spoken := strings.Join(strings.Fields(strings.Join([]string{ing.Quantity, ing.Unit, ing.Name}, " ")), " ")
+			if ing.Notes != "" {
+				spoken += ", " + ing.Notes
+			}
+			lines = append(lines, spokenSentence(spoken))
+		}
+	}
+	if len(rec.Instructions) > 0 {
+		lines = append(lines, words.steps+":")
+		for _, inst := range rec.Instructions {
+			lines = append(lines, fmt.Sprintf("%s %d. %s", words.step, inst.StepNumber, spokenSentence(inst.Text)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// spokenSentence ends text with a full stop, so the voice pauses after it
+func spokenSentence(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.ContainsAny(text[len(text)-1:], ".!?:;") {
+		return text
+	}
+	return text + "."
+}
+
+// splitSpeech splits a script into parts of at most max bytes, between lines where
+// possible and between words for a line longer than max
+func splitSpeech(script string, max int) []string {
+	var parts []string
+	var current strings.Builder
+	add := func(piece string) {
+		if current.Len() > 0 && current.Len()+1+len(piece) > max {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteByte('\n')
+		}
+		current.WriteString(piece)
+	}
+
+	for _, line := range strings.Split(script, "\n") {
+		if len(line) <= max {
+			add(line)
+			continue
+		}
+		var chunk string
+		for _, word := range strings.Fields(line) {
+			if chunk != "" && len(chunk)+1+len(word) > max {
+				add(chunk)
+				chunk = ""
+			}
+			if chunk != "" {
+				chunk += " "
+			}
+			chunk += word
+		}
+		if chunk != "" {
+			add(chunk)
+		}
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
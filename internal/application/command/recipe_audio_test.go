@@ -0,0 +1,67 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/user"
+)
+
+// recordingSynthesizer returns the text it was asked to speak as the audio
+type recordingSynthesizer struct {
+	languages []string
+}
+
+func (s *recordingSynthesizer) Synthesize(ctx context.Context, text string, language string) ([]byte, error) {
+	s.languages = append(s.languages, language)
+	return []byte(text), nil
+}
+
+func TestRecipeAudioCommand_Execute(t *testing.T) {
+	rec := &dto.RecipeDTO{
+		Title: "Pão de queijo",
+		Ingredients: []dto.IngredientDTO{
+			{Name: "polvilho", Quantity: "500", Unit: "g"},
+			{Name: "queijo", Quantity: "200", Unit: "g", Notes: "ralado"},
+		},
+		Instructions: []dto.InstructionDTO{
+			{StepNumber: 1, Text: "Misture tudo"},
+			{StepNumber: 2, Text: "Asse por 25 minutos."},
+		},
+	}
+	synthesizer := &recordingSynthesizer{}
+	cmd := NewRecipeAudioCommand(synthesizer)
+
+	parts, err := cmd.Execute(context.Background(), rec, user.LanguagePortuguese)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := "Pão de queijo.\nIngredientes:\n500 g polvilho.\n200 g queijo, ralado.\n" +
+		"Modo de preparo:\nPasso 1. Misture tudo.\nPasso 2. Asse por 25 minutos."
+	if len(parts) != 1 || string(parts[0]) != want {
+		t.Errorf("Execute() = %q, want %q", parts, want)
+	}
+	if len(synthesizer.languages) != 1 || synthesizer.languages[0] != "pt-BR" {
+		t.Errorf("spoken in %v, want pt-BR", synthesizer.languages)
+	}
+}
+
+func TestSplitSpeech(t *testing.T) {
+	script := "Title.\n" + strings.Repeat("word ", 30) + "\nStep 1. Mix."
+
+	parts := splitSpeech(script, 40)
+	if len(parts) < 4 {
+		t.Fatalf("splitSpeech() = %q, want the long line split", parts)
+	}
+	for _, part := range parts {
+		if len(part) > 40 {
+			t.Errorf("part %q is longer than 40 bytes", part)
+		}
+	}
+	joined := strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+	if joined != strings.Join(strings.Fields(script), " ") {
+		t.Errorf("splitSpeech() lost words: %q", joined)
+	}
+}
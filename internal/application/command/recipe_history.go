@@ -0,0 +1,136 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// RecipeHistory holds a recipe together with its stored versions
+type RecipeHistory struct {
+	Recipe   *recipe.Recipe
+	Versions []*recipe.Version // oldest first
+}
+
+// RecipeHistoryCommand keeps prior versions of recipes when they change
+type RecipeHistoryCommand struct {
+	recipeRepo  recipe.Repository
+	versionRepo recipe.VersionRepository
+}
+
+// NewRecipeHistoryCommand creates a new recipe history command
+func NewRecipeHistoryCommand(
+	recipeRepo recipe.Repository,
+	versionRepo recipe.VersionRepository,
+) *RecipeHistoryCommand {
+	return &RecipeHistoryCommand{
+		recipeRepo:  recipeRepo,
+		versionRepo: versionRepo,
+	}
+}
+
+// Update persists a changed recipe, first storing its previous content as a new version.
+// It returns the stored version, or nil if the recipe content did not change.
+func (c *RecipeHistoryCommand) Update(ctx context.Context, rec *recipe.Recipe, reason string) (*recipe.Version, error) {
+	stored, err := c.recipeRepo.FindByID(ctx, rec.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recipe: %w", err)
+	}
+
+	previous := stored.Snapshot()
+	next := rec.Snapshot()
+	if len(recipe.Diff(previous, next)) == 0 {
+		return nil, nil
+	}
+
+	number, err := c.nextVersionNumber(ctx, rec.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := recipe.NewVersion(number, rec.ID(), previous, next, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create version: %w", err)
+	}
+
+	if err := c.versionRepo.SaveVersion(ctx, version); err != nil {
+		return nil, fmt.Errorf("failed to save version: %w", err)
+	}
+
+	if err := c.recipeRepo.Update(ctx, rec); err != nil {
+		return nil, fmt.Errorf("failed to update recipe: %w", err)
+	}
+
+	return version, nil
+}
+
+// History returns the recipe and all of its stored versions
+func (c *RecipeHistoryCommand) History(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID) (*RecipeHistory, error) {
+	rec, err := c.findOwned(ctx, userID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := c.versionRepo.FindVersions(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions: %w", err)
+	}
+
+	return &RecipeHistory{Recipe: rec, Versions: versions}, nil
+}
+
+// Revert restores a recipe to the content of a stored version.
+// The content being replaced is itself kept as a new version, so reverts can be undone.
+func (c *RecipeHistoryCommand) Revert(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID, number int) (*recipe.Recipe, error) {
+	rec, err := c.findOwned(ctx, userID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := c.versionRepo.FindVersion(ctx, recipeID, number)
+	if err != nil {
+		if errors.Is(err, shared.ErrVersionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get version: %w", err)
+	}
+
+	rec.ApplySnapshot(version.Snapshot)
+	if _, err := c.Update(ctx, rec, fmt.Sprintf("%s to v%d", recipe.VersionReasonRevert, number)); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+func (c *RecipeHistoryCommand) findOwned(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID) (*recipe.Recipe, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("recipe not found: %w", err)
+	}
+
+	// Verify ownership
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	return rec, nil
+}
+
+func (c *RecipeHistoryCommand) nextVersionNumber(ctx context.Context, recipeID recipe.RecipeID) (int, error) {
+	versions, err := c.versionRepo.FindVersions(ctx, recipeID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get versions: %w", err)
+	}
+
+	next := 1
+	for _, v := range versions {
+		if v.Number >= next {
+			next = v.Number + 1
+		}
+	}
+	return next, nil
+}
@@ -0,0 +1,83 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// RecreateDishCommand proposes recipes for dishes in photos, such as a plate at a restaurant.
+// A proposal is only kept in memory until the user saves it or sends another photo.
+type RecreateDishCommand struct {
+	recipeRepo recipe.Repository
+	recreator  ports.DishRecreator
+	normalizer matching.IngredientNormalizer
+
+	mu        sync.Mutex
+	proposals map[shared.ID]*recipe.Recipe // user ID -> unsaved proposal
+}
+
+// NewRecreateDishCommand creates a new command
+func NewRecreateDishCommand(recipeRepo recipe.Repository, recreator ports.DishRecreator) *RecreateDishCommand {
+	return &RecreateDishCommand{
+		recipeRepo: recipeRepo,
+		recreator:  recreator,
+		normalizer: matching.NewRuleBasedNormalizer(nil),
+		proposals:  make(map[shared.ID]*recipe.Recipe),
+	}
+}
+
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *RecreateDishCommand) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+}
+
+// Propose asks the LLM for a recipe that recreates the dish in the photo.
+// The proposal replaces any earlier unsaved proposal of the user.
+func (c *RecreateDishCommand) Propose(ctx context.Context, userID shared.ID, image []byte, hint string, targetLang string) (*recipe.Recipe, error) {
+	extraction, err := c.recreator.RecreateDish(ctx, image, hint, targetLang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recreate dish: %w", err)
+	}
+
+	rec, err := buildRecipe(recipe.UserID(userID), extraction, recipe.NewGeneratedSource(), "", hint, c.normalizer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recipe: %w", err)
+	}
+
+	c.mu.Lock()
+	c.proposals[userID] = rec
+	c.mu.Unlock()
+
+	return rec, nil
+}
+
+// Save stores the user's pending proposal in their collection
+func (c *RecreateDishCommand) Save(ctx context.Context, userID shared.ID) (*recipe.Recipe, error) {
+	c.mu.Lock()
+	rec, ok := c.proposals[userID]
+	c.mu.Unlock()
+	if !ok {
+		return nil, shared.ErrNoDishProposal
+	}
+
+	if err := c.recipeRepo.Save(ctx, rec); err != nil {
+		return nil, fmt.Errorf("failed to save recipe: %w", err)
+	}
+
+	c.Discard(userID)
+	return rec, nil
+}
+
+// Discard drops the user's pending proposal
+func (c *RecreateDishCommand) Discard(userID shared.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.proposals, userID)
+}
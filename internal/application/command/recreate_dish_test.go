@@ -0,0 +1,67 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+type mockDishRecreator struct {
+	hint string
+}
+
+func (m *mockDishRecreator) RecreateDish(ctx context.Context, image []byte, hint string, targetLang string) (*ports.RecipeExtraction, error) {
+	m.hint = hint
+	return &ports.RecipeExtraction{
+		Title:        "Cacio e Pepe",
+		Ingredients:  []ports.IngredientData{{Name: "spaghetti", Quantity: "200", Unit: "g"}, {Name: "pecorino", Quantity: "80", Unit: "g"}},
+		Instructions: []ports.InstructionData{{StepNumber: 1, Text: "Boil the pasta"}, {StepNumber: 2, Text: "Toss with cheese and pepper"}},
+		Category:     "Pasta & Noodles",
+	}, nil
+}
+
+func TestRecreateDishCommand_ProposeAndSave(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+
+	repo := newMockRecipeRepository()
+	recreator := &mockDishRecreator{}
+	cmd := NewRecreateDishCommand(repo, recreator)
+
+	// Nothing to save before a photo
+	if _, err := cmd.Save(ctx, userID); !errors.Is(err, shared.ErrNoDishProposal) {
+		t.Fatalf("Save() error = %v, want ErrNoDishProposal", err)
+	}
+
+	rec, err := cmd.Propose(ctx, userID, []byte("photo"), "recreate this", "English")
+	if err != nil {
+		t.Fatalf("Propose() error = %v", err)
+	}
+	if recreator.hint != "recreate this" {
+		t.Errorf("recreator got hint %q", recreator.hint)
+	}
+	if rec.Title() != "Cacio e Pepe" || !rec.Source().IsGenerated() || rec.Category() != recipe.CategoryPasta {
+		t.Errorf("Propose() = %q from %s in %s", rec.Title(), rec.Source().Platform(), rec.Category())
+	}
+
+	// Proposals are not saved until asked
+	if saved, _ := repo.FindByUserID(ctx, recipe.UserID(userID)); len(saved) != 0 {
+		t.Fatalf("proposal saved before Save(): %d recipes", len(saved))
+	}
+
+	if _, err := cmd.Save(ctx, userID); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if saved, _ := repo.FindByUserID(ctx, recipe.UserID(userID)); len(saved) != 1 || saved[0].ID() != rec.ID() {
+		t.Errorf("saved recipes = %d, want the proposal", len(saved))
+	}
+
+	// A proposal is saved once
+	if _, err := cmd.Save(ctx, userID); !errors.Is(err, shared.ErrNoDishProposal) {
+		t.Errorf("second Save() error = %v, want ErrNoDishProposal", err)
+	}
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// RemixRecipeCommand adapts recipes to a goal such as a diet, e.g. "vegan" or
+// "gluten-free". Unlike appliance conversions, remixes are saved as recipes of
+// their own, linked to the recipe they were remixed from.
+type RemixRecipeCommand struct {
+	recipeRepo recipe.Repository
+	remixer    ports.RecipeRemixer
+	normalizer matching.IngredientNormalizer
+}
+
+// NewRemixRecipeCommand creates a new command
+func NewRemixRecipeCommand(recipeRepo recipe.Repository, remixer ports.RecipeRemixer) *RemixRecipeCommand {
+	return &RemixRecipeCommand{
+		recipeRepo: recipeRepo,
+		remixer:    remixer,
+		normalizer: matching.NewRuleBasedNormalizer(nil),
+	}
+}
+
+// SetNormalizer replaces the built-in ingredient normalizer, such as with one that
+// also resolves the operator's synonyms
+func (c *RemixRecipeCommand) SetNormalizer(normalizer matching.IngredientNormalizer) {
+	c.normalizer = normalizer
+}
+
+// Execute remixes the recipe for the goal, written in the target language, and saves
+// the remix. Returns shared.ErrNothingToRemix when no ingredient had to be swapped.
+func (c *RemixRecipeCommand) Execute(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID, goal string, targetLang string) (*recipe.Recipe, error) {
+	goal = recipe.NormalizeRemixGoal(goal)
+	if goal == "" {
+		return nil, shared.ErrInvalidInput
+	}
+
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, shared.ErrRecipeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	output, err := c.remixer.RemixRecipe(ctx, toExtraction(rec), goal, targetLang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remix recipe: %w", err)
+	}
+	if len(output.Substitutions) == 0 {
+		return nil, shared.ErrNothingToRemix
+	}
+
+	substitutions := make([]recipe.Substitution, len(output.Substitutions))
+	for i, s := range output.Substitutions {
+		substitutions[i] = recipe.Substitution{Original: s.Original, Replacement: s.Replacement, Reason: s.Reason}
+	}
+	link, err := recipe.NewRemix(rec.ID(), rec.Title(), goal, substitutions)
+	if err != nil {
+		return nil, err
+	}
+
+	remix, err := buildRecipe(recipe.UserID(userID), output.Recipe, recipe.NewGeneratedSource(), "", "", c.normalizer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recipe: %w", err)
+	}
+	remix.SetRemix(link)
+
+	if err := c.recipeRepo.Save(ctx, remix); err != nil {
+		return nil, fmt.Errorf("failed to save recipe: %w", err)
+	}
+	return remix, nil
+}
+
+// toExtraction returns the recipe in the form the LLM extracts recipes in
+func toExtraction(rec *recipe.Recipe) *ports.RecipeExtraction {
+	extraction := &ports.RecipeExtraction{
+		Title:          rec.Title(),
+		PrepTime:       rec.PrepTime(),
+		CookTime:       rec.CookTime(),
+		Servings:       rec.Servings(),
+		Category:       string(rec.Category()),
+		Cuisine:        rec.Cuisine(),
+		Tags:           rec.Tags(),
+		SourceLanguage: rec.SourceLanguage(),
+	}
+	for _, tag := range rec.DietaryTags() {
+		extraction.DietaryTags = append(extraction.DietaryTags, string(tag))
+	}
+	for _, f := range rec.Flavors() {
+		extraction.Flavors = append(extraction.Flavors, string(f))
+	}
+	for _, ing := range rec.Ingredients() {
+		extraction.Ingredients = append(extraction.Ingredients, ports.IngredientData{
+			Name:     ing.Name(),
+			Quantity: ing.Quantity(),
+			Unit:     ing.Unit(),
+			Notes:    ing.Notes(),
+		})
+	}
+	for _, inst := range rec.Instructions() {
+		extraction.Instructions = append(extraction.Instructions, ports.InstructionData{
+			StepNumber: inst.StepNumber(),
+			Text:       inst.Text(),
+			Duration:   inst.Duration(),
+		})
+	}
+	return extraction
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+type mockRecipeRemixer struct {
+	goal string
+}
+
+func (m *mockRecipeRemixer) RemixRecipe(ctx context.Context, original *ports.RecipeExtraction, goal string, targetLang string) (*ports.RecipeRemix, error) {
+	m.goal = goal
+	if goal != "vegan" {
+		return &ports.RecipeRemix{Recipe: original}, nil
+	}
+	return &ports.RecipeRemix{
+		Recipe: &ports.RecipeExtraction{
+			Title:        "Vegan " + original.Title,
+			Ingredients:  []ports.IngredientData{{Name: "seitan", Quantity: "1", Unit: "kg"}},
+			Instructions: []ports.InstructionData{{StepNumber: 1, Text: "Braise the seitan"}},
+			DietaryTags:  []string{"vegan"},
+		},
+		Substitutions: []ports.SubstitutionData{{Original: "beef", Replacement: "seitan", Reason: "chewy and savory"}},
+	}, nil
+}
+
+func TestRemixRecipeCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+
+	ing, _ := recipe.NewIngredient("beef", "1", "kg", "")
+	inst, _ := recipe.NewInstruction(1, "Braise the beef", nil)
+	source, _ := recipe.NewSource("https://example.com", recipe.PlatformWeb, "Chef")
+	rec, _ := recipe.NewRecipe(userID, "Stew", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+
+	repo := newMockRecipeRepository()
+	_ = repo.Save(ctx, rec)
+	remixer := &mockRecipeRemixer{}
+	cmd := NewRemixRecipeCommand(repo, remixer)
+
+	remix, err := cmd.Execute(ctx, userID, rec.ID(), "to be Vegan", "English")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if remixer.goal != "vegan" {
+		t.Errorf("remixer got goal %q, want vegan", remixer.goal)
+	}
+	if remix.Title() != "Vegan Stew" || remix.ParentRecipeID() != rec.ID() || !remix.Source().IsGenerated() {
+		t.Errorf("Execute() = %q remixed from %q, want a generated remix of the stew", remix.Title(), remix.ParentRecipeID())
+	}
+	if subs := remix.Remix().Substitutions; len(subs) != 1 || subs[0].Original != "beef" || remix.Remix().ParentTitle != "Stew" {
+		t.Errorf("remix = %+v", remix.Remix())
+	}
+
+	// The remix is saved next to the original, which is unchanged
+	saved, _ := repo.FindByUserID(ctx, recipe.UserID(userID))
+	if len(saved) != 2 {
+		t.Errorf("saved recipes = %d, want the original and the remix", len(saved))
+	}
+	if original, _ := repo.FindByID(ctx, rec.ID()); original.Remix() != nil || original.Ingredients()[0].Name() != "beef" {
+		t.Error("Execute() changed the original recipe")
+	}
+
+	if _, err := cmd.Execute(ctx, userID, rec.ID(), "keto", "English"); !errors.Is(err, shared.ErrNothingToRemix) {
+		t.Errorf("Execute() without substitutions error = %v, want ErrNothingToRemix", err)
+	}
+	if _, err := cmd.Execute(ctx, shared.NewID(), rec.ID(), "vegan", "English"); err == nil {
+		t.Error("Execute() for another user's recipe should fail")
+	}
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/report"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// ReportErrorCommand keeps the context of each user's last failed operation and
+// files it as an error report when the user asks for it
+type ReportErrorCommand struct {
+	reportRepo report.Repository
+
+	mu       sync.Mutex
+	failures map[shared.ID]report.Failure // user ID -> last failure, removed once reported
+	now      func() time.Time
+}
+
+// NewReportErrorCommand creates a new command
+func NewReportErrorCommand(reportRepo report.Repository) *ReportErrorCommand {
+	return &ReportErrorCommand{
+		reportRepo: reportRepo,
+		failures:   make(map[shared.ID]report.Failure),
+		now:        time.Now,
+	}
+}
+
+// RecordFailure keeps the context of a link that failed to process, replacing the
+// user's previous failure
+func (c *ReportErrorCommand) RecordFailure(userID shared.ID, url string, err error) {
+	failure := report.Failure{
+		URL:        url,
+		Platform:   string(recipe.DetectPlatform(url)),
+		Stage:      report.StageOf(err),
+		Error:      err.Error(),
+		OccurredAt: c.now(),
+	}
+
+	var responseErr *ports.LLMResponseError
+	if errors.As(err, &responseErr) {
+		failure.LLMResponse = responseErr.Response
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures[userID] = failure
+}
+
+// Execute files a report of the user's last failure, with an optional note from the user.
+// It returns shared.ErrNothingToReport if no operation failed since the last report.
+func (c *ReportErrorCommand) Execute(ctx context.Context, userID shared.ID, note string) (*report.ErrorReport, error) {
+	c.mu.Lock()
+	failure, ok := c.failures[userID]
+	c.mu.Unlock()
+	if !ok {
+		return nil, shared.ErrNothingToReport
+	}
+
+	rep, err := report.NewErrorReport(userID, failure, note, c.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := c.reportRepo.Save(ctx, rep); err != nil {
+		return nil, fmt.Errorf("failed to save error report: %w", err)
+	}
+
+	c.mu.Lock()
+	delete(c.failures, userID)
+	c.mu.Unlock()
+
+	return rep, nil
+}
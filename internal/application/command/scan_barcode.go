@@ -0,0 +1,88 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/nutrition"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// ScannedProduct is the result of adding a product to the pantry from a barcode photo
+type ScannedProduct struct {
+	Product *ports.Product
+	Item    string // pantry item that was added, e.g. "spaghetti (500 g)"
+	Pantry  *dto.PantryDTO
+}
+
+// ScanBarcodeCommand adds packaged products to the pantry from photos of their barcode
+type ScanBarcodeCommand struct {
+	decoder       ports.BarcodeDecoder
+	products      ports.ProductLookup
+	pantry        *ManagePantryCommand
+	nutritionRepo nutrition.Repository
+}
+
+// NewScanBarcodeCommand creates a new command.
+// The nutrition repository is optional; with it, scanned pantry items are
+// linked to their product so nutrition estimates can use the product data.
+func NewScanBarcodeCommand(
+	decoder ports.BarcodeDecoder,
+	products ports.ProductLookup,
+	pantry *ManagePantryCommand,
+	nutritionRepo nutrition.Repository,
+) *ScanBarcodeCommand {
+	return &ScanBarcodeCommand{
+		decoder:       decoder,
+		products:      products,
+		pantry:        pantry,
+		nutritionRepo: nutritionRepo,
+	}
+}
+
+// Execute decodes the barcode in the photo, looks the product up and adds it to the pantry.
+// It returns shared.ErrNoBarcode or shared.ErrProductNotFound when either step finds nothing;
+// in the latter case the decoded barcode is still reported in the result.
+func (c *ScanBarcodeCommand) Execute(ctx context.Context, userID shared.ID, photo []byte) (*ScannedProduct, error) {
+	code, err := c.decoder.DecodeBarcode(photo)
+	if err != nil {
+		if errors.Is(err, shared.ErrNoBarcode) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read barcode: %w", err)
+	}
+
+	product, err := c.products.LookupBarcode(ctx, code)
+	if err != nil {
+		if errors.Is(err, shared.ErrProductNotFound) {
+			return &ScannedProduct{Product: &ports.Product{Barcode: code}}, err
+		}
+		return nil, fmt.Errorf("failed to look up product %s: %w", code, err)
+	}
+
+	item, pantry, err := c.pantry.AddProduct(ctx, userID, product.Name, product.Quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add product to pantry: %w", err)
+	}
+
+	if c.nutritionRepo != nil {
+		link, err := nutrition.NewPantryProduct(userID, item, product.Barcode)
+		if err == nil {
+			err = c.nutritionRepo.SavePantryProduct(ctx, link)
+		}
+		if err != nil {
+			// The item is in the pantry, only nutrition estimates will miss it
+			log.Printf("Failed to link pantry item %q to product %s: %v", item, product.Barcode, err)
+		}
+	}
+
+	return &ScannedProduct{
+		Product: product,
+		Item:    item,
+		Pantry:  pantry,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// PantryPhotoItem is an item recognized in a pantry photo, checked while it is to be added
+type PantryPhotoItem struct {
+	Name    string
+	Checked bool
+}
+
+// ScanPantryPhotoCommand adds the items recognized in a photo of the fridge or a pantry
+// shelf to the pantry. The user reviews the items before they are added; the checklist
+// is only kept in memory until it is confirmed or another photo is sent.
+type ScanPantryPhotoCommand struct {
+	recognizer ports.PantryRecognizer
+	pantry     *ManagePantryCommand
+
+	mu        sync.Mutex
+	checklist map[shared.ID][]PantryPhotoItem // user ID -> items waiting for confirmation
+}
+
+// NewScanPantryPhotoCommand creates a new command
+func NewScanPantryPhotoCommand(recognizer ports.PantryRecognizer, pantry *ManagePantryCommand) *ScanPantryPhotoCommand {
+	return &ScanPantryPhotoCommand{
+		recognizer: recognizer,
+		pantry:     pantry,
+		checklist:  make(map[shared.ID][]PantryPhotoItem),
+	}
+}
+
+// Recognize lists the items in the photo, all checked, replacing the user's previous checklist.
+// It returns shared.ErrNoItemsFound when nothing is recognized.
+func (c *ScanPantryPhotoCommand) Recognize(ctx context.Context, userID shared.ID, photo []byte, targetLang string) ([]PantryPhotoItem, error) {
+	names, err := c.recognizer.RecognizeItems(ctx, photo, targetLang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recognize items: %w", err)
+	}
+	if len(names) == 0 {
+		return nil, shared.ErrNoItemsFound
+	}
+
+	items := make([]PantryPhotoItem, len(names))
+	for i, name := range names {
+		items[i] = PantryPhotoItem{Name: name, Checked: true}
+	}
+
+	c.mu.Lock()
+	c.checklist[userID] = items
+	c.mu.Unlock()
+
+	return items, nil
+}
+
+// Toggle checks or unchecks an item of the user's checklist
+func (c *ScanPantryPhotoCommand) Toggle(userID shared.ID, index int) ([]PantryPhotoItem, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	items, ok := c.checklist[userID]
+	if !ok {
+		return nil, shared.ErrNoPantryPhoto
+	}
+	if index < 0 || index >= len(items) {
+		return nil, shared.ErrInvalidInput
+	}
+
+	items[index].Checked = !items[index].Checked
+	return append([]PantryPhotoItem(nil), items...), nil
+}
+
+// Confirm adds the checked items to the pantry and clears the checklist.
+// Returns the names of the added items and the updated pantry.
+func (c *ScanPantryPhotoCommand) Confirm(ctx context.Context, userID shared.ID) ([]string, *dto.PantryDTO, error) {
+	c.mu.Lock()
+	items, ok := c.checklist[userID]
+	var names []string
+	for _, item := range items {
+		if item.Checked {
+			names = append(names, item.Name)
+		}
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, nil, shared.ErrNoPantryPhoto
+	}
+
+	pantry, err := c.pantry.AddItems(ctx, userID, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	delete(c.checklist, userID)
+	c.mu.Unlock()
+
+	return names, pantry, nil
+}
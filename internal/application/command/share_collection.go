@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/share"
+	"receipt-bot/internal/domain/shared"
+)
+
+// ShareCollectionCommand hands out and revokes read-only guest access to a user's recipes
+type ShareCollectionCommand struct {
+	shareRepo share.Repository
+}
+
+// NewShareCollectionCommand creates a new command
+func NewShareCollectionCommand(shareRepo share.Repository) *ShareCollectionCommand {
+	return &ShareCollectionCommand{
+		shareRepo: shareRepo,
+	}
+}
+
+// Create opens a guest share of the user's collection, or of one category of it, for ttl
+func (c *ShareCollectionCommand) Create(ctx context.Context, userID shared.ID, category *recipe.Category, ttl time.Duration, now time.Time) (*share.GuestShare, error) {
+	s, err := share.NewGuestShare(userID, category, ttl, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.shareRepo.Save(ctx, s); err != nil {
+		return nil, fmt.Errorf("failed to save guest share: %w", err)
+	}
+
+	return s, nil
+}
+
+// Revoke closes all guest shares of the user
+func (c *ShareCollectionCommand) Revoke(ctx context.Context, userID shared.ID) error {
+	if err := c.shareRepo.DeleteByOwner(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke guest shares: %w", err)
+	}
+	return nil
+}
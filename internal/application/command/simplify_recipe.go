@@ -0,0 +1,167 @@
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+// maxSimplifiedRecipes caps the simplified views kept in memory
+const maxSimplifiedRecipes = 500
+
+// SimplifiedRecipe is a recipe together with its instructions rewritten for kids and beginner cooks
+type SimplifiedRecipe struct {
+	Recipe *dto.RecipeDTO
+	Steps  []dto.InstructionDTO
+}
+
+// simplifiedKey identifies a simplified view of a recipe in one language
+type simplifiedKey struct {
+	recipeID recipe.RecipeID
+	language string
+}
+
+// simplifiedEntry is a cached simplified view, valid while the original steps are unchanged
+type simplifiedEntry struct {
+	original string // hash of the original steps
+	steps    []dto.InstructionDTO
+}
+
+// SimplifyRecipeCommand rewrites recipe instructions into shorter, simpler steps.
+// Results are cached per recipe and language until the recipe's instructions change,
+// keeping the maxSimplifiedRecipes most recently used.
+type SimplifyRecipeCommand struct {
+	recipeRepo recipe.Repository
+	simplifier ports.InstructionSimplifier
+
+	mu    sync.Mutex
+	cache map[simplifiedKey]simplifiedEntry
+	order []simplifiedKey // cached views, least recently used first
+}
+
+// NewSimplifyRecipeCommand creates a new command
+func NewSimplifyRecipeCommand(recipeRepo recipe.Repository, simplifier ports.InstructionSimplifier) *SimplifyRecipeCommand {
+	return &SimplifyRecipeCommand{
+		recipeRepo: recipeRepo,
+		simplifier: simplifier,
+		cache:      make(map[simplifiedKey]simplifiedEntry),
+	}
+}
+
+// Recipe returns one of the user's recipes, for switching back to the original view
+func (c *SimplifyRecipeCommand) Recipe(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID) (*dto.RecipeDTO, error) {
+	rec, err := c.findOwned(ctx, userID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	return convertRecipeToDTO(rec), nil
+}
+
+// Execute returns the recipe with simplified instructions written in the target language
+func (c *SimplifyRecipeCommand) Execute(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID, targetLang string) (*SimplifiedRecipe, error) {
+	rec, err := c.findOwned(ctx, userID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	recipeDTO := convertRecipeToDTO(rec)
+
+	key := simplifiedKey{recipeID: recipeID, language: targetLang}
+	original := instructionsFingerprint(recipeDTO.Instructions)
+
+	if steps, ok := c.cached(key, original); ok {
+		return &SimplifiedRecipe{Recipe: recipeDTO, Steps: steps}, nil
+	}
+
+	input := make([]ports.InstructionData, len(recipeDTO.Instructions))
+	for i, inst := range recipeDTO.Instructions {
+		input[i] = ports.InstructionData{StepNumber: inst.StepNumber, Text: inst.Text}
+	}
+
+	output, err := c.simplifier.SimplifyInstructions(ctx, input, targetLang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simplify instructions: %w", err)
+	}
+
+	steps := make([]dto.InstructionDTO, len(output))
+	for i, inst := range output {
+		steps[i] = dto.InstructionDTO{StepNumber: inst.StepNumber, Text: inst.Text}
+	}
+
+	c.keep(key, simplifiedEntry{original: original, steps: steps})
+
+	return &SimplifiedRecipe{Recipe: recipeDTO, Steps: steps}, nil
+}
+
+// cached returns the simplified steps of a view made from the original steps,
+// marking it recently used
+func (c *SimplifyRecipeCommand) cached(key simplifiedKey, original string) ([]dto.InstructionDTO, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.cache[key]
+	if !ok || entry.original != original {
+		return nil, false
+	}
+	c.touch(key)
+	return entry.steps, true
+}
+
+// keep stores a simplified view, dropping the least recently used beyond maxSimplifiedRecipes
+func (c *SimplifyRecipeCommand) keep(key simplifiedKey, entry simplifiedEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.cache[key]; ok {
+		c.touch(key)
+	} else {
+		c.order = append(c.order, key)
+	}
+	c.cache[key] = entry
+	for len(c.order) > maxSimplifiedRecipes {
+		delete(c.cache, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// touch moves a cached view to the most recently used end. The lock must be held.
+func (c *SimplifyRecipeCommand) touch(key simplifiedKey) {
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(append(c.order[:i:i], c.order[i+1:]...), key)
+			return
+		}
+	}
+}
+
+// findOwned loads a recipe and checks that it belongs to the user
+func (c *SimplifyRecipeCommand) findOwned(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID) (*recipe.Recipe, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, shared.ErrRecipeNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+	return rec, nil
+}
+
+// instructionsFingerprint identifies the original steps a simplification was made from
+func instructionsFingerprint(instructions []dto.InstructionDTO) string {
+	hash := sha256.New()
+	for _, inst := range instructions {
+		hash.Write([]byte(inst.Text))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/ports"
+)
+
+type mockInstructionSimplifier struct {
+	calls int
+}
+
+func (m *mockInstructionSimplifier) SimplifyInstructions(ctx context.Context, instructions []ports.InstructionData, targetLang string) ([]ports.InstructionData, error) {
+	m.calls++
+	steps := make([]ports.InstructionData, len(instructions))
+	for i, inst := range instructions {
+		steps[i] = ports.InstructionData{StepNumber: inst.StepNumber, Text: targetLang + ": " + inst.Text}
+	}
+	return steps, nil
+}
+
+func TestSimplifyRecipeCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+
+	ing, _ := recipe.NewIngredient("flour", "2", "cups", "")
+	inst, _ := recipe.NewInstruction(1, "Sift the flour into a large bowl", nil)
+	source, _ := recipe.NewSource("https://example.com", recipe.PlatformWeb, "Chef")
+	rec, _ := recipe.NewRecipe(userID, "Bread", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+
+	repo := newMockRecipeRepository()
+	_ = repo.Save(ctx, rec)
+	simplifier := &mockInstructionSimplifier{}
+	cmd := NewSimplifyRecipeCommand(repo, simplifier)
+
+	result, err := cmd.Execute(ctx, userID, rec.ID(), "English")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(result.Steps) != 1 || result.Steps[0].Text != "English: Sift the flour into a large bowl" {
+		t.Errorf("Execute() steps = %+v", result.Steps)
+	}
+	if result.Recipe.Title != "Bread" || result.Recipe.Instructions[0].Text != "Sift the flour into a large bowl" {
+		t.Errorf("Execute() recipe = %+v, want the original recipe", result.Recipe)
+	}
+
+	// Cached per recipe and language
+	if _, err := cmd.Execute(ctx, userID, rec.ID(), "English"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if simplifier.calls != 1 {
+		t.Errorf("simplifier called %d times, want 1", simplifier.calls)
+	}
+	if _, err := cmd.Execute(ctx, userID, rec.ID(), "Portuguese"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if simplifier.calls != 2 {
+		t.Errorf("simplifier called %d times, want 2 after a new language", simplifier.calls)
+	}
+
+	// Changed instructions are simplified again
+	next, _ := recipe.NewInstruction(2, "Knead for ten minutes", nil)
+	_ = rec.AddInstruction(next)
+	result, err = cmd.Execute(ctx, userID, rec.ID(), "English")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if simplifier.calls != 3 || len(result.Steps) != 2 {
+		t.Errorf("after changing the recipe: %d calls, %d steps; want 3 calls, 2 steps", simplifier.calls, len(result.Steps))
+	}
+
+	if _, err := cmd.Execute(ctx, shared.NewID(), rec.ID(), "English"); err == nil {
+		t.Error("Execute() for another user's recipe should fail")
+	}
+}
+
+func TestSimplifyRecipeCommand_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+	repo := newMockRecipeRepository()
+	simplifier := &mockInstructionSimplifier{}
+	cmd := NewSimplifyRecipeCommand(repo, simplifier)
+
+	recipes := make([]*recipe.Recipe, maxSimplifiedRecipes+1)
+	for i := range recipes {
+		ing, _ := recipe.NewIngredient("flour", "2", "cups", "")
+		inst, _ := recipe.NewInstruction(1, "Sift the flour", nil)
+		source, _ := recipe.NewSource("https://example.com", recipe.PlatformWeb, "Chef")
+		recipes[i], _ = recipe.NewRecipe(userID, "Bread", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+		_ = repo.Save(ctx, recipes[i])
+	}
+
+	// Fill the cache, then use the first view again so the second is the least recently used
+	for _, rec := range recipes[:maxSimplifiedRecipes] {
+		if _, err := cmd.Execute(ctx, userID, rec.ID(), "English"); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	if _, err := cmd.Execute(ctx, userID, recipes[0].ID(), "English"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, err := cmd.Execute(ctx, userID, recipes[maxSimplifiedRecipes].ID(), "English"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(cmd.cache) != maxSimplifiedRecipes || len(cmd.order) != maxSimplifiedRecipes {
+		t.Fatalf("cache holds %d views (%d ordered), want %d", len(cmd.cache), len(cmd.order), maxSimplifiedRecipes)
+	}
+
+	calls := simplifier.calls
+	if _, err := cmd.Execute(ctx, userID, recipes[0].ID(), "English"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if simplifier.calls != calls {
+		t.Error("recently used view was evicted")
+	}
+	if _, err := cmd.Execute(ctx, userID, recipes[1].ID(), "English"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if simplifier.calls != calls+1 {
+		t.Error("least recently used view was not evicted")
+	}
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+	"receipt-bot/internal/domain/stats"
+)
+
+// TrackAchievementsCommand keeps the cooking stats behind streaks, badges and monthly recaps
+type TrackAchievementsCommand struct {
+	statsRepo  stats.Repository
+	recipeRepo recipe.Repository
+}
+
+// NewTrackAchievementsCommand creates a new command
+func NewTrackAchievementsCommand(statsRepo stats.Repository, recipeRepo recipe.Repository) *TrackAchievementsCommand {
+	return &TrackAchievementsCommand{
+		statsRepo:  statsRepo,
+		recipeRepo: recipeRepo,
+	}
+}
+
+// Stats returns the user's cooking stats, empty if nothing was cooked yet
+func (c *TrackAchievementsCommand) Stats(ctx context.Context, userID shared.ID) (*stats.Stats, error) {
+	s, err := c.statsRepo.FindByUser(ctx, userID)
+	if err == nil {
+		return s, nil
+	}
+	if !errors.Is(err, shared.ErrStatsNotFound) {
+		return nil, fmt.Errorf("failed to get cooking stats: %w", err)
+	}
+	return stats.NewStats(userID)
+}
+
+// RecordCooking records a recipe cooked at home at cookedAt, in the user's time
+// zone, and returns the badges it earned
+func (c *TrackAchievementsCommand) RecordCooking(ctx context.Context, userID shared.ID, recipeID recipe.RecipeID, cookedAt time.Time) ([]stats.Badge, error) {
+	rec, err := c.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("recipe not found: %w", err)
+	}
+
+	// Verify ownership
+	if rec.UserID() != recipe.UserID(userID) {
+		return nil, fmt.Errorf("unauthorized: recipe belongs to another user")
+	}
+
+	var earned []stats.Badge
+	_, err = c.statsRepo.Modify(ctx, userID, func(s *stats.Stats) error {
+		var err error
+		earned, err = s.RecordCooking(recipeID, rec.Cuisine(), cookedAt)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record cooking: %w", err)
+	}
+
+	return earned, nil
+}
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestTrackAchievements_ConcurrentCookingIsAllRecorded(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.NewID()
+	recipeRepo := memory.NewRecipeRepository()
+	cmd := NewTrackAchievementsCommand(memory.NewStatsRepository(), recipeRepo)
+	cookedAt := time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)
+
+	recipes := make([]*recipe.Recipe, 20)
+	for i := range recipes {
+		ing, _ := recipe.NewIngredient("rice", "1", "cup", "")
+		inst, _ := recipe.NewInstruction(1, "Cook the rice", nil)
+		source, _ := recipe.NewSource("https://example.com", recipe.PlatformWeb, "Chef")
+		recipes[i], _ = recipe.NewRecipe(userID, fmt.Sprintf("Rice %d", i), []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+		if err := recipeRepo.Save(ctx, recipes[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Cooking marked from two devices at once is all counted
+	var wg sync.WaitGroup
+	for _, rec := range recipes {
+		wg.Add(1)
+		go func(rec *recipe.Recipe) {
+			defer wg.Done()
+			if _, err := cmd.RecordCooking(ctx, userID, rec.ID(), cookedAt); err != nil {
+				t.Error(err)
+			}
+		}(rec)
+	}
+	wg.Wait()
+
+	s, err := cmd.Stats(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(s.Recipes()); got != 20 {
+		t.Errorf("stats hold %d cooked recipes, want 20", got)
+	}
+	if month := s.Months()["2026-10"]; month.Cooked != 20 {
+		t.Errorf("October recap counts %d cooked meals, want 20", month.Cooked)
+	}
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+)
+
+// TranscriptRetentionCommand strips the transcripts and captions of recipes saved
+// longer ago than the retention period. They are only needed to extract recipes
+// and make up most of a recipe's size.
+type TranscriptRetentionCommand struct {
+	recipeRepo recipe.Repository
+	retention  time.Duration
+	now        func() time.Time
+}
+
+// NewTranscriptRetentionCommand creates a new command keeping transcripts for retention
+func NewTranscriptRetentionCommand(recipeRepo recipe.Repository, retention time.Duration) *TranscriptRetentionCommand {
+	return &TranscriptRetentionCommand{
+		recipeRepo: recipeRepo,
+		retention:  retention,
+		now:        time.Now,
+	}
+}
+
+// Execute strips the transcripts and captions past retention and returns how many
+// recipes lost them
+func (c *TranscriptRetentionCommand) Execute(ctx context.Context) (int, error) {
+	stripped, err := c.recipeRepo.StripTranscripts(ctx, c.now().Add(-c.retention))
+	if err != nil {
+		return stripped, fmt.Errorf("failed to apply transcript retention: %w", err)
+	}
+	return stripped, nil
+}
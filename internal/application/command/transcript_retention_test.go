@@ -0,0 +1,85 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestTranscriptRetentionCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRecipeRepository()
+
+	source, _ := recipe.NewSource("https://youtube.com/watch?v=abc", recipe.PlatformYouTube, "Chef")
+	ing, _ := recipe.NewIngredient("flour", "2", "cups", "")
+	inst, _ := recipe.NewInstruction(1, "Mix and bake", nil)
+	rec, err := recipe.NewRecipe(recipe.UserID(shared.NewID()), "Cake", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "Mix flour and bake.", "Cake #baking")
+	if err != nil {
+		t.Fatalf("NewRecipe() unexpected error = %v", err)
+	}
+	if err := repo.Save(ctx, rec); err != nil {
+		t.Fatalf("Save() unexpected error = %v", err)
+	}
+
+	cmd := NewTranscriptRetentionCommand(repo, 30*24*time.Hour)
+
+	// A recipe saved just now keeps its transcript and captions
+	if stripped, err := cmd.Execute(ctx); err != nil || stripped != 0 {
+		t.Fatalf("Execute() = %d, %v; want 0 recipes stripped", stripped, err)
+	}
+
+	cmd.now = func() time.Time { return time.Now().Add(31 * 24 * time.Hour) }
+	if stripped, err := cmd.Execute(ctx); err != nil || stripped != 1 {
+		t.Fatalf("Execute() = %d, %v; want 1 recipe stripped", stripped, err)
+	}
+
+	saved, err := repo.FindByID(ctx, rec.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error = %v", err)
+	}
+	if saved.Transcript() != "" {
+		t.Errorf("Transcript = %q, want it stripped", saved.Transcript())
+	}
+	if saved.Captions() != "" {
+		t.Errorf("Captions = %q, want them stripped", saved.Captions())
+	}
+
+	// Recipes already stripped are not counted again
+	if stripped, err := cmd.Execute(ctx); err != nil || stripped != 0 {
+		t.Fatalf("Execute() = %d, %v; want 0 recipes stripped", stripped, err)
+	}
+}
+
+func TestTranscriptRetentionCommand_StripsCaptionsWithoutTranscript(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRecipeRepository()
+
+	source, _ := recipe.NewSource("https://instagram.com/p/abc", recipe.PlatformInstagram, "Chef")
+	ing, _ := recipe.NewIngredient("flour", "2", "cups", "")
+	inst, _ := recipe.NewInstruction(1, "Mix and bake", nil)
+	rec, err := recipe.NewRecipe(recipe.UserID(shared.NewID()), "Cake", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "Cake #baking")
+	if err != nil {
+		t.Fatalf("NewRecipe() unexpected error = %v", err)
+	}
+	if err := repo.Save(ctx, rec); err != nil {
+		t.Fatalf("Save() unexpected error = %v", err)
+	}
+
+	cmd := NewTranscriptRetentionCommand(repo, 30*24*time.Hour)
+	cmd.now = func() time.Time { return time.Now().Add(31 * 24 * time.Hour) }
+	if stripped, err := cmd.Execute(ctx); err != nil || stripped != 1 {
+		t.Fatalf("Execute() = %d, %v; want 1 recipe stripped", stripped, err)
+	}
+
+	saved, err := repo.FindByID(ctx, rec.ID())
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error = %v", err)
+	}
+	if saved.Captions() != "" {
+		t.Errorf("Captions = %q, want them stripped", saved.Captions())
+	}
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/analytics"
+)
+
+// MaxAnalyticsWeeks is the longest period /admin analytics reports on
+const MaxAnalyticsWeeks = 26
+
+// UsageReport is the use of each feature over recent weeks, for the owner
+type UsageReport struct {
+	From     time.Time // start of the first week reported
+	Weeks    int
+	Total    int // uses of every feature together
+	Features []analytics.Usage
+}
+
+// UsageAnalyticsCommand counts how often each command and intent is used, per
+// week, and reports it to the owner
+type UsageAnalyticsCommand struct {
+	usageRepo analytics.Repository
+	now       func() time.Time
+}
+
+// NewUsageAnalyticsCommand creates a new command
+func NewUsageAnalyticsCommand(usageRepo analytics.Repository) *UsageAnalyticsCommand {
+	return &UsageAnalyticsCommand{
+		usageRepo: usageRepo,
+		now:       time.Now,
+	}
+}
+
+// Record counts one use of a feature this week
+func (c *UsageAnalyticsCommand) Record(ctx context.Context, feature analytics.Feature) error {
+	if err := c.usageRepo.Increment(ctx, analytics.WeekOf(c.now()), feature); err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// Report sums up the use of each feature over the last weeks, this one included,
+// most used first. Weeks are capped at MaxAnalyticsWeeks.
+func (c *UsageAnalyticsCommand) Report(ctx context.Context, weeks int) (*UsageReport, error) {
+	weeks = max(1, min(weeks, MaxAnalyticsWeeks))
+	thisWeek := analytics.WeekOf(c.now())
+	from := thisWeek.AddDate(0, 0, -7*(weeks-1))
+
+	found, err := c.usageRepo.FindSince(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load usage: %w", err)
+	}
+
+	report := &UsageReport{
+		From:     from,
+		Weeks:    weeks,
+		Features: analytics.Summarize(found, thisWeek),
+	}
+	for _, usage := range report.Features {
+		report.Total += usage.Total
+	}
+	return report, nil
+}
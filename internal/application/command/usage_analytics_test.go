@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/adapters/memory"
+	"receipt-bot/internal/domain/analytics"
+)
+
+func TestUsageAnalyticsCommand_Report(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cmd := NewUsageAnalyticsCommand(memory.NewUsageRepository())
+
+	record := func(at time.Time, feature analytics.Feature, times int) {
+		cmd.now = func() time.Time { return at }
+		for i := 0; i < times; i++ {
+			if err := cmd.Record(ctx, feature); err != nil {
+				t.Fatalf("Record() error = %v", err)
+			}
+		}
+	}
+	record(now.AddDate(0, 0, -30), "/plan", 5) // before the period reported
+	record(now.AddDate(0, 0, -7), analytics.FeatureRecipeLink, 3)
+	record(now, analytics.FeatureRecipeLink, 2)
+	record(now, analytics.CommandFeature("Export"), 1)
+
+	cmd.now = func() time.Time { return now }
+	report, err := cmd.Report(ctx, 4)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	if want := time.Date(2026, 9, 21, 0, 0, 0, 0, time.UTC); !report.From.Equal(want) {
+		t.Errorf("From = %v, want %v", report.From, want)
+	}
+	if report.Total != 6 || len(report.Features) != 2 {
+		t.Fatalf("Report() = %+v, want 6 uses of 2 features", report)
+	}
+	if link := report.Features[0]; link.Feature != analytics.FeatureRecipeLink || link.ThisWeek != 2 || link.LastWeek != 3 {
+		t.Errorf("Features[0] = %+v, want links used 2 times this week and 3 last week", link)
+	}
+	if report.Features[1].Feature != "/export" {
+		t.Errorf("Features[1] = %+v, want /export", report.Features[1])
+	}
+
+	if report, _ := cmd.Report(ctx, 1000); report.Weeks != MaxAnalyticsWeeks {
+		t.Errorf("Report(1000).Weeks = %d, want %d", report.Weeks, MaxAnalyticsWeeks)
+	}
+}
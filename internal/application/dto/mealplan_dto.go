@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// MealPlanDTO is a data transfer object for a week's meal plan
+type MealPlanDTO struct {
+	WeekStart time.Time
+	Entries   []MealPlanEntryDTO // ordered Monday to Sunday
+}
+
+// MealPlanEntryDTO represents a recipe planned for a day
+type MealPlanEntryDTO struct {
+	Day      time.Weekday
+	RecipeID string
+	Title    string // empty if the recipe was deleted
+	Servings int    // 0 means the recipe's own servings
+}
@@ -7,11 +7,16 @@ type RecipeDTO struct {
 	ID              string
 	UserID          string
 	Title           string
+	DisplayName     string // title, with the author or saved date when other listed recipes share it
 	Ingredients     []IngredientDTO
 	Instructions    []InstructionDTO
 	SourceURL       string
 	SourcePlatform  string
 	SourceAuthor    string
+	SourceSegment   int // position of the recipe in a compilation, 0 for a single recipe
+	SourceHandle    string    // the creator's account on the platform, like "@chefana"
+	SourcePublished time.Time // when the content was published, zero if unknown
+	SourceCredit    string    // attribution footer of exports and shares, empty for generated recipes
 	Transcript      string
 	Captions        string
 	PrepTimeMinutes *int
@@ -21,14 +26,27 @@ type RecipeDTO struct {
 	Cuisine         string
 	DietaryTags     []string
 	Tags            []string
+	Flavors         []string // e.g. "spicy" or "tangy"
+	Difficulty      string // easy, medium or hard
+	DifficultyScore int    // 1 (trivial) to 10 (demanding)
+	CheckQuantities bool   // the extraction is unsure of the ingredient quantities
+	CoverKey        string // blob storage key of the cover image, empty if none
+	CoverGenerated  bool   // the cover was drawn by AI
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	Summary         bool // only list fields are loaded: no instructions, transcript, captions or translations
 
 	// Multilingual support
 	SourceLanguage         string
 	TranslatedTitle        *string
 	TranslatedIngredients  []IngredientDTO
 	TranslatedInstructions []InstructionDTO
+
+	// Remix of another recipe, empty when the recipe isn't a remix
+	ParentRecipeID string
+	RemixOf        string // title of the parent recipe
+	RemixGoal      string // e.g. "vegan"
+	Substitutions  []SubstitutionDTO
 }
 
 // IngredientDTO represents an ingredient
@@ -37,6 +55,14 @@ type IngredientDTO struct {
 	Quantity string
 	Unit     string
 	Notes    string
+	Replaces string // the ingredient it was swapped for in a remix, empty if none
+}
+
+// SubstitutionDTO represents an ingredient swapped for another in a remix
+type SubstitutionDTO struct {
+	Original    string
+	Replacement string
+	Reason      string
 }
 
 // InstructionDTO represents a cooking instruction
@@ -44,6 +70,7 @@ type InstructionDTO struct {
 	StepNumber      int
 	Text            string
 	DurationMinutes *int
+	StartSeconds    *int // where the step starts in the source video, nil if unknown
 }
 
 // ProcessRecipeLinkRequest is the request for processing a recipe link
@@ -67,6 +94,14 @@ type MatchResultDTO struct {
 	MatchedItems    []string
 	MissingItems    []string
 	MatchLevel      string
+	Details         []MatchDetailDTO // how each recipe ingredient was counted
+}
+
+// MatchDetailDTO explains how one recipe ingredient was counted in a match
+type MatchDetailDTO struct {
+	Ingredient string
+	Kind       string // exact, partial, substitute, staple or missing
+	Via        string // the user ingredient it was matched with, if any
 }
 
 // MatchIngredientsResultDTO contains grouped match results
@@ -74,11 +109,25 @@ type MatchIngredientsResultDTO struct {
 	PerfectMatches []MatchResultDTO
 	HighMatches    []MatchResultDTO
 	MediumMatches  []MatchResultDTO
+	LowMatches     []MatchResultDTO // only when a few missing ingredients are allowed
 	TotalMatches   int
 }
 
 // PantryDTO represents user pantry data
 type PantryDTO struct {
 	Items     []string
+	Aisles    map[string]string // item -> grocery aisle, set by GetPantryByAisle
 	UpdatedAt *time.Time
 }
+
+// AuthorCountDTO is the number of recipes a user saved from a source author
+type AuthorCountDTO struct {
+	Author string
+	Count  int
+}
+
+// PlatformCountDTO is the number of recipes a user saved from a source platform
+type PlatformCountDTO struct {
+	Platform string
+	Count    int
+}
@@ -0,0 +1,85 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/application/dto"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/share"
+	"receipt-bot/internal/domain/shared"
+)
+
+// RecipeScreen keeps the recipes guests may not see out of a share
+type RecipeScreen interface {
+	// Visible returns the recipes that may be shown to guests, in order
+	Visible(ctx context.Context, recipes []*recipe.Recipe) ([]*recipe.Recipe, error)
+}
+
+// BrowseSharedQuery lets guests read the recipes of a guest share.
+// It only ever reads, so a guest can't change anything in the collection.
+type BrowseSharedQuery struct {
+	shareRepo  share.Repository
+	recipeRepo recipe.Repository
+	screen     RecipeScreen // optional, guests see every shared recipe when nil
+}
+
+// NewBrowseSharedQuery creates a new query
+func NewBrowseSharedQuery(shareRepo share.Repository, recipeRepo recipe.Repository, screen RecipeScreen) *BrowseSharedQuery {
+	return &BrowseSharedQuery{
+		shareRepo:  shareRepo,
+		recipeRepo: recipeRepo,
+		screen:     screen,
+	}
+}
+
+// Execute retrieves a guest share and the recipes it shows.
+// Returns shared.ErrShareExpired once the share is no longer open
+func (q *BrowseSharedQuery) Execute(ctx context.Context, token string, now time.Time) (*share.GuestShare, []*dto.RecipeDTO, error) {
+	s, err := q.shareRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.IsExpired(now) {
+		return nil, nil, shared.ErrShareExpired
+	}
+
+	recipes, err := q.recipeRepo.FindByUserID(ctx, s.OwnerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list shared recipes: %w", err)
+	}
+
+	var included []*recipe.Recipe
+	for _, rec := range recipes {
+		if s.Includes(rec) {
+			included = append(included, rec)
+		}
+	}
+	if q.screen != nil {
+		if included, err = q.screen.Visible(ctx, included); err != nil {
+			return nil, nil, fmt.Errorf("failed to screen shared recipes: %w", err)
+		}
+	}
+
+	dtos := make([]*dto.RecipeDTO, 0, len(included))
+	for _, rec := range included {
+		dtos = append(dtos, convertToDTO(rec))
+	}
+
+	return s, dtos, nil
+}
+
+// ExecuteByIndex retrieves a recipe of a guest share by its index (1-based) in the shared list
+func (q *BrowseSharedQuery) ExecuteByIndex(ctx context.Context, token string, index int, now time.Time) (*dto.RecipeDTO, error) {
+	_, recipes, err := q.Execute(ctx, token, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 1 || index > len(recipes) {
+		return nil, fmt.Errorf("recipe #%d not found (the share has %d recipes)", index, len(recipes))
+	}
+
+	return recipes[index-1], nil
+}
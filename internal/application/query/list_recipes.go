@@ -3,9 +3,12 @@ package query
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"receipt-bot/internal/application/dto"
 	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
 )
 
 // ListRecipesQuery handles retrieving recipes for a user
@@ -20,9 +23,10 @@ func NewListRecipesQuery(recipeRepo recipe.Repository) *ListRecipesQuery {
 	}
 }
 
-// Execute retrieves all recipes for a user
+// Execute retrieves summaries of all recipes for a user; use ExecuteByID or
+// ExecuteByIndex for a recipe's instructions
 func (q *ListRecipesQuery) Execute(ctx context.Context, userID recipe.UserID) ([]*dto.RecipeDTO, error) {
-	recipes, err := q.recipeRepo.FindByUserID(ctx, userID)
+	recipes, err := q.recipeRepo.FindSummariesByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list recipes: %w", err)
 	}
@@ -33,12 +37,12 @@ func (q *ListRecipesQuery) Execute(ctx context.Context, userID recipe.UserID) ([
 		dtos[i] = convertToDTO(rec)
 	}
 
-	return dtos, nil
+	return disambiguate(dtos), nil
 }
 
 // ExecuteByIndex retrieves a specific recipe by its index (1-based) for a user
 func (q *ListRecipesQuery) ExecuteByIndex(ctx context.Context, userID recipe.UserID, index int) (*dto.RecipeDTO, error) {
-	recipes, err := q.recipeRepo.FindByUserID(ctx, userID)
+	recipes, err := q.recipeRepo.FindSummariesByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recipes: %w", err)
 	}
@@ -47,7 +51,21 @@ func (q *ListRecipesQuery) ExecuteByIndex(ctx context.Context, userID recipe.Use
 		return nil, fmt.Errorf("recipe #%d not found (you have %d recipes)", index, len(recipes))
 	}
 
-	return convertToDTO(recipes[index-1]), nil
+	return q.ExecuteByID(ctx, userID, recipes[index-1].ID())
+}
+
+// ExecuteByID retrieves one of the user's recipes in full.
+// Returns shared.ErrRecipeNotFound for recipes of other users.
+func (q *ListRecipesQuery) ExecuteByID(ctx context.Context, userID recipe.UserID, recipeID recipe.RecipeID) (*dto.RecipeDTO, error) {
+	rec, err := q.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	if rec.UserID() != userID {
+		return nil, shared.ErrRecipeNotFound
+	}
+
+	return convertToDTO(rec), nil
 }
 
 // ExecuteByCategory retrieves recipes filtered by category
@@ -62,7 +80,7 @@ func (q *ListRecipesQuery) ExecuteByCategory(ctx context.Context, userID recipe.
 		dtos[i] = convertToDTO(rec)
 	}
 
-	return dtos, nil
+	return disambiguate(dtos), nil
 }
 
 // GetCategoryCounts returns the count of recipes per category
@@ -81,6 +99,108 @@ func (q *ListRecipesQuery) GetCategoryCounts(ctx context.Context, userID recipe.
 	return result, nil
 }
 
+// ExecuteByAuthor retrieves recipes saved from a source author
+func (q *ListRecipesQuery) ExecuteByAuthor(ctx context.Context, userID recipe.UserID, author string) ([]*dto.RecipeDTO, error) {
+	recipes, err := q.recipeRepo.FindByUserIDAndAuthor(ctx, userID, author)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipes by author: %w", err)
+	}
+
+	dtos := make([]*dto.RecipeDTO, len(recipes))
+	for i, rec := range recipes {
+		dtos[i] = convertToDTO(rec)
+	}
+
+	return disambiguate(dtos), nil
+}
+
+// GetTopAuthors returns the source authors a user saves recipes from, most saved first
+func (q *ListRecipesQuery) GetTopAuthors(ctx context.Context, userID recipe.UserID) ([]dto.AuthorCountDTO, error) {
+	counts, err := q.recipeRepo.GetAuthorCounts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author counts: %w", err)
+	}
+
+	authors := make([]dto.AuthorCountDTO, 0, len(counts))
+	for author, count := range counts {
+		authors = append(authors, dto.AuthorCountDTO{Author: author, Count: count})
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Count != authors[j].Count {
+			return authors[i].Count > authors[j].Count
+		}
+		return authors[i].Author < authors[j].Author
+	})
+
+	return authors, nil
+}
+
+// ExecuteByPlatform retrieves recipes saved from a source platform
+func (q *ListRecipesQuery) ExecuteByPlatform(ctx context.Context, userID recipe.UserID, platform recipe.Platform) ([]*dto.RecipeDTO, error) {
+	recipes, err := q.recipeRepo.FindByUserIDAndPlatform(ctx, userID, platform)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipes by platform: %w", err)
+	}
+
+	dtos := make([]*dto.RecipeDTO, len(recipes))
+	for i, rec := range recipes {
+		dtos[i] = convertToDTO(rec)
+	}
+
+	return disambiguate(dtos), nil
+}
+
+// ExecuteByFlavor retrieves recipes classified with a flavor profile
+func (q *ListRecipesQuery) ExecuteByFlavor(ctx context.Context, userID recipe.UserID, flavor recipe.Flavor) ([]*dto.RecipeDTO, error) {
+	recipes, err := q.recipeRepo.FindByUserIDAndFlavor(ctx, userID, flavor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipes by flavor: %w", err)
+	}
+
+	dtos := make([]*dto.RecipeDTO, len(recipes))
+	for i, rec := range recipes {
+		dtos[i] = convertToDTO(rec)
+	}
+
+	return disambiguate(dtos), nil
+}
+
+// ExecuteByDateRange retrieves recipes saved within a date range, newest first
+func (q *ListRecipesQuery) ExecuteByDateRange(ctx context.Context, userID recipe.UserID, saved recipe.DateRange) ([]*dto.RecipeDTO, error) {
+	recipes, err := q.recipeRepo.FindByUserIDAndDateRange(ctx, userID, saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipes by date: %w", err)
+	}
+
+	dtos := make([]*dto.RecipeDTO, len(recipes))
+	for i, rec := range recipes {
+		dtos[i] = convertToDTO(rec)
+	}
+
+	return disambiguate(dtos), nil
+}
+
+// GetPlatformCounts returns the source platforms a user saves recipes from, most saved first
+func (q *ListRecipesQuery) GetPlatformCounts(ctx context.Context, userID recipe.UserID) ([]dto.PlatformCountDTO, error) {
+	counts, err := q.recipeRepo.GetPlatformCounts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get platform counts: %w", err)
+	}
+
+	platforms := make([]dto.PlatformCountDTO, 0, len(counts))
+	for platform, count := range counts {
+		platforms = append(platforms, dto.PlatformCountDTO{Platform: string(platform), Count: count})
+	}
+	sort.Slice(platforms, func(i, j int) bool {
+		if platforms[i].Count != platforms[j].Count {
+			return platforms[i].Count > platforms[j].Count
+		}
+		return platforms[i].Platform < platforms[j].Platform
+	})
+
+	return platforms, nil
+}
+
 // SearchByIngredient searches recipes containing a specific ingredient
 func (q *ListRecipesQuery) SearchByIngredient(ctx context.Context, userID recipe.UserID, ingredient string) ([]*dto.RecipeDTO, error) {
 	recipes, err := q.recipeRepo.SearchByIngredient(ctx, userID, ingredient)
@@ -93,22 +213,25 @@ func (q *ListRecipesQuery) SearchByIngredient(ctx context.Context, userID recipe
 		dtos[i] = convertToDTO(rec)
 	}
 
-	return dtos, nil
+	return disambiguate(dtos), nil
 }
 
-// ExecuteByFilters retrieves recipes filtered by optional category and dietary tags
-func (q *ListRecipesQuery) ExecuteByFilters(ctx context.Context, userID recipe.UserID, category *recipe.Category, dietaryTags []recipe.DietaryTag) ([]*dto.RecipeDTO, error) {
-	recipes, err := q.recipeRepo.FindByUserIDAndFilters(ctx, userID, category, dietaryTags)
+// ExecuteByFilters retrieves recipes filtered by optional category, dietary tags, source language and difficulty
+func (q *ListRecipesQuery) ExecuteByFilters(ctx context.Context, userID recipe.UserID, category *recipe.Category, dietaryTags []recipe.DietaryTag, language string, difficulty *recipe.Difficulty) ([]*dto.RecipeDTO, error) {
+	recipes, err := q.recipeRepo.FindByUserIDAndFilters(ctx, userID, category, dietaryTags, language)
 	if err != nil {
 		return nil, fmt.Errorf("failed to filter recipes: %w", err)
 	}
 
-	dtos := make([]*dto.RecipeDTO, len(recipes))
-	for i, rec := range recipes {
-		dtos[i] = convertToDTO(rec)
+	dtos := make([]*dto.RecipeDTO, 0, len(recipes))
+	for _, rec := range recipes {
+		if difficulty != nil && rec.Difficulty() != *difficulty {
+			continue
+		}
+		dtos = append(dtos, convertToDTO(rec))
 	}
 
-	return dtos, nil
+	return disambiguate(dtos), nil
 }
 
 // SearchByIngredientFilter searches recipes using complex ingredient filters (AND/OR/NOT logic)
@@ -128,31 +251,34 @@ func (q *ListRecipesQuery) SearchByIngredientFilter(ctx context.Context, userID
 		dtos[i] = convertToDTO(rec)
 	}
 
-	return dtos, nil
+	return disambiguate(dtos), nil
 }
 
-// SearchByIngredientFilterWithTags combines ingredient filter with dietary tag filtering
-func (q *ListRecipesQuery) SearchByIngredientFilterWithTags(ctx context.Context, userID recipe.UserID, filter *recipe.IngredientFilter, dietaryTags []recipe.DietaryTag) ([]*dto.RecipeDTO, error) {
+// SearchByIngredientFilterWithTags combines ingredient filter with dietary tag and difficulty filtering
+func (q *ListRecipesQuery) SearchByIngredientFilterWithTags(ctx context.Context, userID recipe.UserID, filter *recipe.IngredientFilter, dietaryTags []recipe.DietaryTag, difficulty *recipe.Difficulty) ([]*dto.RecipeDTO, error) {
 	// First apply ingredient filter
 	recipes, err := q.SearchByIngredientFilter(ctx, userID, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	// If no dietary tags, return as-is
-	if len(dietaryTags) == 0 {
+	// If no dietary tags or difficulty, return as-is
+	if len(dietaryTags) == 0 && difficulty == nil {
 		return recipes, nil
 	}
 
-	// Filter by dietary tags
+	// Filter by dietary tags and difficulty
 	var filtered []*dto.RecipeDTO
 	for _, rec := range recipes {
+		if difficulty != nil && rec.Difficulty != string(*difficulty) {
+			continue
+		}
 		if hasAllDietaryTags(rec, dietaryTags) {
 			filtered = append(filtered, rec)
 		}
 	}
 
-	return filtered, nil
+	return disambiguate(filtered), nil
 }
 
 // hasAllDietaryTags checks if a recipe DTO has all the specified dietary tags
@@ -174,16 +300,28 @@ func hasAllDietaryTags(rec *dto.RecipeDTO, requiredTags []recipe.DietaryTag) boo
 // convertToDTO converts a domain Recipe to a DTO
 func convertToDTO(rec *recipe.Recipe) *dto.RecipeDTO {
 	recipeDTO := &dto.RecipeDTO{
-		ID:             rec.ID().String(),
-		UserID:         rec.UserID().String(),
-		Title:          rec.Title(),
-		SourceURL:      rec.Source().URL(),
-		SourcePlatform: string(rec.Source().Platform()),
-		SourceAuthor:   rec.Source().Author(),
-		Transcript:     rec.Transcript(),
-		Captions:       rec.Captions(),
-		CreatedAt:      rec.CreatedAt(),
-		UpdatedAt:      rec.UpdatedAt(),
+		ID:              rec.ID().String(),
+		UserID:          rec.UserID().String(),
+		Title:           rec.Title(),
+		DisplayName:     rec.Title(),
+		SourceURL:       rec.Source().URL(),
+		SourcePlatform:  string(rec.Source().Platform()),
+		SourceAuthor:    rec.Source().Author(),
+		SourceSegment:   rec.Source().Segment(),
+		SourceHandle:    rec.Source().Attribution().Handle,
+		SourcePublished: rec.Source().Attribution().PublishedAt,
+		SourceCredit:    rec.Source().Credit(),
+		Transcript:      rec.Transcript(),
+		Captions:        rec.Captions(),
+		CreatedAt:       rec.CreatedAt(),
+		UpdatedAt:       rec.UpdatedAt(),
+		Summary:         rec.IsSummary(),
+		SourceLanguage:  rec.SourceLanguage(),
+	}
+
+	if cover := rec.Cover(); cover != nil {
+		recipeDTO.CoverKey = cover.Key
+		recipeDTO.CoverGenerated = cover.AIGenerated
 	}
 
 	// Convert ingredients
@@ -211,6 +349,10 @@ func convertToDTO(rec *recipe.Recipe) *dto.RecipeDTO {
 			Text:            inst.Text(),
 			DurationMinutes: durationMinutes,
 		}
+		if inst.Timestamp() != nil {
+			seconds := int(inst.Timestamp().Seconds())
+			recipeDTO.Instructions[i].StartSeconds = &seconds
+		}
 	}
 
 	// Convert optional times
@@ -236,5 +378,77 @@ func convertToDTO(rec *recipe.Recipe) *dto.RecipeDTO {
 
 	recipeDTO.Tags = rec.Tags()
 
+	recipeDTO.Flavors = make([]string, len(rec.Flavors()))
+	for i, f := range rec.Flavors() {
+		recipeDTO.Flavors[i] = string(f)
+	}
+
+	recipeDTO.Difficulty = string(rec.Difficulty())
+	recipeDTO.DifficultyScore = rec.DifficultyScore()
+	recipeDTO.CheckQuantities = rec.NeedsQuantityCheck()
+
+	// Convert remix
+	if remix := rec.Remix(); remix != nil {
+		recipeDTO.ParentRecipeID = remix.ParentID.String()
+		recipeDTO.RemixOf = remix.ParentTitle
+		recipeDTO.RemixGoal = remix.Goal
+		for _, sub := range remix.Substitutions {
+			recipeDTO.Substitutions = append(recipeDTO.Substitutions, dto.SubstitutionDTO{
+				Original:    sub.Original,
+				Replacement: sub.Replacement,
+				Reason:      sub.Reason,
+			})
+		}
+		for i, ing := range recipeDTO.Ingredients {
+			if sub, ok := remix.SubstitutionFor(ing.Name); ok {
+				recipeDTO.Ingredients[i].Replaces = sub.Original
+			}
+		}
+	}
+
 	return recipeDTO
 }
+
+// disambiguate gives recipes that share a title display names that tell them apart:
+// the title followed by the author, then by the date each was saved when the author
+// doesn't tell them apart either, e.g. "Carbonara (pastaguy)" and
+// "Carbonara (nonna, saved 2026-03-04)". Other recipes are shown by their title.
+func disambiguate(recipes []*dto.RecipeDTO) []*dto.RecipeDTO {
+	byTitle := make(map[string][]*dto.RecipeDTO)
+	for _, rec := range recipes {
+		rec.DisplayName = rec.Title
+		key := strings.ToLower(strings.TrimSpace(rec.Title))
+		byTitle[key] = append(byTitle[key], rec)
+	}
+
+	for _, same := range byTitle {
+		if len(same) < 2 {
+			continue
+		}
+
+		authors := make(map[string]int)
+		for _, rec := range same {
+			authors[rec.SourceAuthor]++
+		}
+
+		names := make(map[string]int)
+		for _, rec := range same {
+			var details []string
+			if rec.SourceAuthor != "" {
+				details = append(details, rec.SourceAuthor)
+			}
+			if rec.SourceAuthor == "" || authors[rec.SourceAuthor] > 1 {
+				details = append(details, "saved "+rec.CreatedAt.Format("2006-01-02"))
+			}
+			name := fmt.Sprintf("%s (%s)", strings.TrimSpace(rec.Title), strings.Join(details, ", "))
+
+			// Recipes by one author saved the same day are numbered
+			names[name]++
+			if n := names[name]; n > 1 {
+				name = fmt.Sprintf("%s (%s, %d)", strings.TrimSpace(rec.Title), strings.Join(details, ", "), n)
+			}
+			rec.DisplayName = name
+		}
+	}
+	return recipes
+}
@@ -3,7 +3,9 @@ package query
 import (
 	"context"
 	"testing"
+	"time"
 
+	"receipt-bot/internal/application/dto"
 	"receipt-bot/internal/domain/recipe"
 	"receipt-bot/internal/domain/shared"
 )
@@ -23,6 +25,15 @@ func (m *mockRecipeRepository) Save(ctx context.Context, rec *recipe.Recipe) err
 	return m.err
 }
 
+func (m *mockRecipeRepository) SaveAll(ctx context.Context, recipes []*recipe.Recipe) (int, error) {
+	for i, rec := range recipes {
+		if err := m.Save(ctx, rec); err != nil {
+			return i, err
+		}
+	}
+	return len(recipes), nil
+}
+
 func (m *mockRecipeRepository) FindByID(ctx context.Context, id recipe.RecipeID) (*recipe.Recipe, error) {
 	for _, rec := range m.recipes {
 		if rec.ID() == id {
@@ -45,6 +56,18 @@ func (m *mockRecipeRepository) FindByUserID(ctx context.Context, userID recipe.U
 	return result, nil
 }
 
+func (m *mockRecipeRepository) FindSummariesByUserID(ctx context.Context, userID recipe.UserID) ([]*recipe.Recipe, error) {
+	recipes, err := m.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]*recipe.Recipe, len(recipes))
+	for i, rec := range recipes {
+		summaries[i] = rec.Summary()
+	}
+	return summaries, nil
+}
+
 func (m *mockRecipeRepository) FindByUserIDAndCategory(ctx context.Context, userID recipe.UserID, category recipe.Category) ([]*recipe.Recipe, error) {
 	if m.err != nil {
 		return nil, m.err
@@ -58,7 +81,7 @@ func (m *mockRecipeRepository) FindByUserIDAndCategory(ctx context.Context, user
 	return result, nil
 }
 
-func (m *mockRecipeRepository) FindByUserIDAndFilters(ctx context.Context, userID recipe.UserID, category *recipe.Category, dietaryTags []recipe.DietaryTag) ([]*recipe.Recipe, error) {
+func (m *mockRecipeRepository) FindByUserIDAndFilters(ctx context.Context, userID recipe.UserID, category *recipe.Category, dietaryTags []recipe.DietaryTag, language string) ([]*recipe.Recipe, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -70,6 +93,9 @@ func (m *mockRecipeRepository) FindByUserIDAndFilters(ctx context.Context, userI
 		if category != nil && rec.Category() != *category {
 			continue
 		}
+		if language != "" && rec.SourceLanguage() != language {
+			continue
+		}
 		if len(dietaryTags) > 0 && !hasAllTags(rec, dietaryTags) {
 			continue
 		}
@@ -99,6 +125,84 @@ func (m *mockRecipeRepository) SearchByIngredientFilter(ctx context.Context, use
 	return m.FindByUserID(ctx, userID) // Simplified for testing
 }
 
+func (m *mockRecipeRepository) FindByUserIDAndAuthor(ctx context.Context, userID recipe.UserID, author string) ([]*recipe.Recipe, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*recipe.Recipe
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID && rec.Source().IsFrom(author) {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRecipeRepository) GetAuthorCounts(ctx context.Context, userID recipe.UserID) (map[string]int, error) {
+	recipes, err := m.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return recipe.CountAuthors(recipes), nil
+}
+
+func (m *mockRecipeRepository) FindByUserIDAndPlatform(ctx context.Context, userID recipe.UserID, platform recipe.Platform) ([]*recipe.Recipe, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*recipe.Recipe
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID && rec.Source().Platform() == platform {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRecipeRepository) GetPlatformCounts(ctx context.Context, userID recipe.UserID) (map[recipe.Platform]int, error) {
+	recipes, err := m.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return recipe.CountPlatforms(recipes), nil
+}
+
+func (m *mockRecipeRepository) FindByUserIDAndFlavor(ctx context.Context, userID recipe.UserID, flavor recipe.Flavor) ([]*recipe.Recipe, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*recipe.Recipe
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID && rec.HasFlavor(flavor) {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRecipeRepository) FindByUserIDAndDateRange(ctx context.Context, userID recipe.UserID, saved recipe.DateRange) ([]*recipe.Recipe, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*recipe.Recipe
+	for _, rec := range m.recipes {
+		if rec.UserID() == userID && saved.Contains(rec.CreatedAt()) {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRecipeRepository) StripTranscripts(ctx context.Context, savedBefore time.Time) (int, error) {
+	stripped := 0
+	for _, rec := range m.recipes {
+		if rec.CreatedAt().Before(savedBefore) && rec.StripTranscript() {
+			stripped++
+		}
+	}
+	return stripped, nil
+}
+
 func (m *mockRecipeRepository) FindBySourceURL(ctx context.Context, sourceURL string) (*recipe.Recipe, error) {
 	return nil, shared.ErrRecipeNotFound
 }
@@ -233,6 +337,118 @@ func TestListRecipesQuery_GetCategoryCounts(t *testing.T) {
 	}
 }
 
+func TestListRecipesQuery_Authors(t *testing.T) {
+	userID := shared.NewID()
+
+	fromAuthor := func(title, author string) *recipe.Recipe {
+		ing, _ := recipe.NewIngredient("pasta", "200", "g", "")
+		inst, _ := recipe.NewInstruction(1, "Cook", nil)
+		source, _ := recipe.NewSource("https://www.tiktok.com/@x/video/1", recipe.PlatformTikTok, author)
+		rec, _ := recipe.NewRecipe(userID, title, []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+		return rec
+	}
+
+	recipes := []*recipe.Recipe{
+		fromAuthor("Cacio e Pepe", "@thatpastaguy"),
+		fromAuthor("Carbonara", "ThatPastaGuy"),
+		fromAuthor("Pesto", "@thatpastaguy"),
+		fromAuthor("Tacos", "@tacoqueen"),
+		fromAuthor("Toast", ""),
+	}
+
+	query := NewListRecipesQuery(newMockRepo(recipes))
+
+	authors, err := query.GetTopAuthors(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetTopAuthors() error = %v", err)
+	}
+	if len(authors) != 2 {
+		t.Fatalf("GetTopAuthors() returned %d authors, want 2: %+v", len(authors), authors)
+	}
+	if authors[0].Author != "@thatpastaguy" || authors[0].Count != 3 {
+		t.Errorf("GetTopAuthors()[0] = %+v, want @thatpastaguy with 3", authors[0])
+	}
+	if authors[1].Author != "@tacoqueen" || authors[1].Count != 1 {
+		t.Errorf("GetTopAuthors()[1] = %+v, want @tacoqueen with 1", authors[1])
+	}
+
+	result, err := query.ExecuteByAuthor(context.Background(), userID, "thatpastaguy")
+	if err != nil {
+		t.Fatalf("ExecuteByAuthor() error = %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("ExecuteByAuthor() returned %d recipes, want 3", len(result))
+	}
+}
+
+func TestListRecipesQuery_Platforms(t *testing.T) {
+	userID := shared.NewID()
+
+	fromPlatform := func(title, rawURL string) *recipe.Recipe {
+		ing, _ := recipe.NewIngredient("pasta", "200", "g", "")
+		inst, _ := recipe.NewInstruction(1, "Cook", nil)
+		source, _ := recipe.NewSource(rawURL, recipe.DetectPlatform(rawURL), "")
+		rec, _ := recipe.NewRecipe(userID, title, []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+		return rec
+	}
+
+	recipes := []*recipe.Recipe{
+		fromPlatform("Cacio e Pepe", "https://www.tiktok.com/@x/video/1"),
+		fromPlatform("Carbonara", "https://www.tiktok.com/@x/video/2"),
+		fromPlatform("Pesto", "https://www.youtube.com/watch?v=1"),
+		fromPlatform("Tacos", "https://example.com/tacos"),
+		fromPlatform("Toast", "https://www.youtube.com/watch?v=2"),
+		fromPlatform("Ramen", "https://www.tiktok.com/@x/video/3"),
+	}
+
+	query := NewListRecipesQuery(newMockRepo(recipes))
+
+	platforms, err := query.GetPlatformCounts(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetPlatformCounts() error = %v", err)
+	}
+	want := []dto.PlatformCountDTO{{Platform: "tiktok", Count: 3}, {Platform: "youtube", Count: 2}, {Platform: "web", Count: 1}}
+	if len(platforms) != len(want) {
+		t.Fatalf("GetPlatformCounts() = %+v, want %+v", platforms, want)
+	}
+	for i := range want {
+		if platforms[i] != want[i] {
+			t.Errorf("GetPlatformCounts()[%d] = %+v, want %+v", i, platforms[i], want[i])
+		}
+	}
+
+	result, err := query.ExecuteByPlatform(context.Background(), userID, recipe.PlatformTikTok)
+	if err != nil {
+		t.Fatalf("ExecuteByPlatform() error = %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("ExecuteByPlatform() returned %d recipes, want 3", len(result))
+	}
+}
+
+func TestListRecipesQuery_ExecuteByFlavor(t *testing.T) {
+	userID := shared.NewID()
+
+	curry := createTestRecipe(userID, "Curry", recipe.CategoryMeat, nil)
+	curry.SetFlavors([]recipe.Flavor{recipe.FlavorSpicy, recipe.FlavorUmami})
+	pancakes := createTestRecipe(userID, "Pancakes", recipe.CategoryBreakfast, nil)
+	pancakes.SetFlavors([]recipe.Flavor{recipe.FlavorSweet})
+	salad := createTestRecipe(userID, "Salad", recipe.CategorySalads, nil)
+
+	query := NewListRecipesQuery(newMockRepo([]*recipe.Recipe{curry, pancakes, salad}))
+
+	result, err := query.ExecuteByFlavor(context.Background(), userID, recipe.FlavorSpicy)
+	if err != nil {
+		t.Fatalf("ExecuteByFlavor() error = %v", err)
+	}
+	if len(result) != 1 || result[0].Title != "Curry" {
+		t.Fatalf("ExecuteByFlavor() = %+v, want only the curry", result)
+	}
+	if got := result[0].Flavors; len(got) != 2 || got[0] != "spicy" || got[1] != "umami-rich" {
+		t.Errorf("Flavors = %v, want [spicy umami-rich]", got)
+	}
+}
+
 func TestListRecipesQuery_ExecuteByFilters(t *testing.T) {
 	userID := shared.NewID()
 
@@ -250,6 +466,7 @@ func TestListRecipesQuery_ExecuteByFilters(t *testing.T) {
 		name        string
 		category    *recipe.Category
 		dietaryTags []recipe.DietaryTag
+		difficulty  *recipe.Difficulty
 		wantCount   int
 	}{
 		{
@@ -282,11 +499,22 @@ func TestListRecipesQuery_ExecuteByFilters(t *testing.T) {
 			dietaryTags: nil,
 			wantCount:   4,
 		},
+		{
+			name:       "easy pasta",
+			category:   categoryPtr(recipe.CategoryPasta),
+			difficulty: difficultyPtr(recipe.DifficultyEasy),
+			wantCount:  2,
+		},
+		{
+			name:       "hard",
+			difficulty: difficultyPtr(recipe.DifficultyHard),
+			wantCount:  0,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := query.ExecuteByFilters(context.Background(), userID, tt.category, tt.dietaryTags)
+			result, err := query.ExecuteByFilters(context.Background(), userID, tt.category, tt.dietaryTags, "", tt.difficulty)
 			if err != nil {
 				t.Fatalf("ExecuteByFilters() error = %v", err)
 			}
@@ -346,6 +574,35 @@ func TestListRecipesQuery_ExecuteByIndex(t *testing.T) {
 	}
 }
 
+func TestDisambiguate(t *testing.T) {
+	march := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	april := time.Date(2026, time.April, 1, 12, 0, 0, 0, time.UTC)
+	recipes := []*dto.RecipeDTO{
+		{Title: "Carbonara", SourceAuthor: "pastaguy", CreatedAt: march},
+		{Title: "carbonara ", SourceAuthor: "nonna", CreatedAt: march},
+		{Title: "Carbonara", SourceAuthor: "nonna", CreatedAt: april},
+		{Title: "Carbonara", SourceAuthor: "nonna", CreatedAt: april},
+		{Title: "Curry", SourceAuthor: "nonna", CreatedAt: march},
+	}
+
+	want := []string{
+		"Carbonara (pastaguy)",
+		"carbonara (nonna, saved 2026-03-04)",
+		"Carbonara (nonna, saved 2026-04-01)",
+		"Carbonara (nonna, saved 2026-04-01, 2)",
+		"Curry",
+	}
+	for i, rec := range disambiguate(recipes) {
+		if rec.DisplayName != want[i] {
+			t.Errorf("DisplayName of recipe %d = %q, want %q", i, rec.DisplayName, want[i])
+		}
+	}
+}
+
 func categoryPtr(c recipe.Category) *recipe.Category {
 	return &c
 }
+
+func difficultyPtr(d recipe.Difficulty) *recipe.Difficulty {
+	return &d
+}
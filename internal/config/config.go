@@ -2,36 +2,102 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/viper"
 )
 
+// defaultConfigFile is the YAML file picked up when CONFIG_FILE is not set
+const defaultConfigFile = "config.yaml"
+
 // Config holds all configuration for the application
 type Config struct {
-	Telegram TelegramConfig
-	Firebase FirebaseConfig
-	LLM      LLMConfig
-	Python   PythonServiceConfig
-	App      AppConfig
-	Notion   NotionConfig
+	Telegram  TelegramConfig
+	Firebase  FirebaseConfig
+	LLM       LLMConfig
+	Python    PythonServiceConfig
+	App       AppConfig
+	Notion    NotionConfig
+	Cloud     CloudStorageConfig
+	Speech    SpeechConfig
+	Images    ImagesConfig
+	Payments  PaymentsConfig
+	RateLimit RateLimitConfig
+	Features  FeaturesConfig
+	Telemetry TelemetryConfig
+	Bookmarks BookmarkImportConfig
+	Clip      ClipConfig
+	Email     EmailConfig
+	Blobs     BlobStorageConfig
+
+	Moderation ModerationConfig
+	Matching   MatchingConfig
+	Diets      DietConfig
 }
 
 // TelegramConfig holds Telegram bot configuration
 type TelegramConfig struct {
-	BotToken string
-	Debug    bool
+	BotToken  string
+	Debug     bool
+	WebAppURL string // public HTTPS URL of the Mini App, disables it when empty
+
+	AdminChatID int64 // chat that receives /report error reports, 0 to only store them
+	GroupPantry bool  // groups share one pantry and shopping list instead of each member's own
+
+	TopicCollections bool // recipes saved in a forum topic named after a category are filed under it
+
+	UpdateTimeout int // in seconds, how long handling one update may take
+	UpdateWorkers int // updates handled at the same time
+	MessageWindow int // in milliseconds, pause that ends a thought sent across quick messages, 0 to handle each alone
 }
 
 // FirebaseConfig holds Firebase configuration
 type FirebaseConfig struct {
 	ProjectID       string
 	CredentialsPath string
+	EmulatorHost    string // Firestore emulator, used in sandbox mode
+	QueryStats      bool   // log how each recipe query ran, with the indexes it used
 }
 
 // LLMConfig holds LLM provider configuration
 type LLMConfig struct {
-	Provider string // "gemini", "openai", "anthropic"
-	APIKey   string
+	Provider string // "gemini", "openai" or "ollama"
+	APIKey   string // optional for ollama
 	Model    string
+	BaseURL  string // OpenAI-compatible endpoint of a local server for ollama, its default when empty
+
+	// Version of the extraction instructions (hot-reloadable)
+	PromptVersion string
+
+	// Minutes Gemini keeps the static prompts cached between calls, 0 to resend them every call
+	PromptCacheMinutes int
+
+	// Model and temperature of each kind of call, overriding Model
+	IntentTask    TaskConfig
+	ExtractTask   TaskConfig
+	TranslateTask TaskConfig
+
+	// Prompt experiments for recipe extraction and intent detection
+	ExtractionExperiment ExperimentConfig
+	IntentExperiment     ExperimentConfig
+}
+
+// TaskConfig picks the model and temperature of one kind of LLM call, e.g.
+// LLM_MODEL_INTENT and LLM_TEMPERATURE_INTENT
+type TaskConfig struct {
+	Model       string   // LLM_MODEL when empty
+	Temperature *float32 // the call's built-in temperature when nil
+	invalid     string   // malformed temperature, reported by Validate
+}
+
+// ExperimentConfig routes a share of LLM calls through an alternate prompt or model
+type ExperimentConfig struct {
+	Variant    string // name results are tagged with, the experiment is off when empty
+	Percent    int    // share of calls routed through the variant, 0-100
+	Model      string // alternate model, optional
+	PromptFile string // file holding the alternate prompt, optional
 }
 
 // PythonServiceConfig holds Python service configuration
@@ -42,8 +108,23 @@ type PythonServiceConfig struct {
 
 // AppConfig holds general application configuration
 type AppConfig struct {
-	LogLevel string
-	Port     int
+	LogLevel   string // hot-reloadable
+	Port       int
+	ConfigFile string // YAML file that was loaded, empty if none
+
+	// StoreTranscripts keeps the transcript of each recipe's video with the recipe.
+	// Copyright-sensitive deployments turn it off: transcripts are then only used
+	// to extract recipes.
+	StoreTranscripts bool
+	// TranscriptRetentionDays strips transcripts and captions from recipes saved
+	// longer ago, 0 keeps them for good
+	TranscriptRetentionDays int
+
+	// Sandbox replaces scraping and LLM calls with recorded fixtures and
+	// stores data in the Firestore emulator (if configured) or in memory,
+	// so no credentials are needed
+	Sandbox         bool
+	SandboxFixtures string // optional fixtures file, built-in fixtures if empty
 }
 
 // NotionConfig holds Notion OAuth configuration
@@ -53,7 +134,111 @@ type NotionConfig struct {
 	RedirectURI  string
 }
 
-// Load loads configuration from environment variables and config file
+// CloudStorageConfig holds the Dropbox and Google Drive OAuth configuration;
+// each provider is offered by /connect when its credentials are set.
+// Redirect URIs point to /oauth/dropbox/callback and /oauth/drive/callback on APP_PORT.
+type CloudStorageConfig struct {
+	DropboxAppKey      string
+	DropboxAppSecret   string
+	DropboxRedirectURI string
+
+	DriveClientID     string
+	DriveClientSecret string
+	DriveRedirectURI  string
+}
+
+// SpeechConfig holds the Google Cloud Text-to-Speech settings of /audio
+type SpeechConfig struct {
+	APIKey string // disables /audio when empty
+}
+
+// ImagesConfig holds the OpenAI Images settings of generated recipe covers
+type ImagesConfig struct {
+	APIKey string // disables cover generation when empty
+	Model  string // gpt-image-1 when empty
+}
+
+// PaymentsConfig holds the premium tiers users can pay for in Telegram
+type PaymentsConfig struct {
+	Enabled       bool     // disables /premium when false; everyone then gets every feature
+	ProviderToken string   // payment provider token from @BotFather, Telegram Stars when empty
+	Currency      string   // XTR for Stars, otherwise a currency the provider supports
+	MonthPrice    int      // in the currency's smallest unit, 0 to not offer the plan
+	YearPrice     int      // in the currency's smallest unit, 0 to not offer the plan
+	Features      []string // feature flags only premium users get, e.g. cookbook_export
+}
+
+// FeaturesConfig holds the feature flag defaults for this environment.
+// Firestore overrides (featureFlags collection) take precedence at runtime.
+type FeaturesConfig struct {
+	Flags map[string]bool // e.g. FEATURE_FLAGS="translation=false,web_ui=true"
+
+	invalid []string // malformed FEATURE_FLAGS entries, reported by Validate
+}
+
+// TelemetryConfig holds the opt-in anonymous quality metrics settings
+type TelemetryConfig struct {
+	Enabled  bool   // off unless the operator opts in
+	Endpoint string // URL the batches are posted to
+	Interval int    // minutes between batches
+}
+
+// BookmarkImportConfig holds the settings of imports from browser bookmark files
+type BookmarkImportConfig struct {
+	Domains  []string // recipe sites imported without further checks, built-in list if empty
+	Interval int      // seconds between two links of an import
+}
+
+// ClipConfig holds the settings of the browser extension API
+type ClipConfig struct {
+	Secret string // signs the users' API tokens, disables /api/v1/clip when empty
+	URL    string // public URL of the API, shown to users by /clip
+}
+
+// BlobStorageConfig holds the bucket large content is stored in instead of Firestore
+type BlobStorageConfig struct {
+	Provider string // "gcs" or "s3", keeps everything in Firestore when empty
+	Bucket   string
+
+	// S3 only; GCS uses the application default credentials
+	Region          string
+	Endpoint        string // S3-compatible services such as R2 or MinIO, AWS when empty
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// EmailConfig holds the settings of the personal addresses users forward emails to
+type EmailConfig struct {
+	Domain     string // domain the provider receives mail for, disables forwarding when empty
+	Secret     string // signs the personal addresses
+	SigningKey string // the provider's webhook signing key
+}
+
+// ModerationConfig holds the checks run on recipes before guests see them
+type ModerationConfig struct {
+	BlockedDomains []string // sites whose recipes are never shared
+}
+
+// MatchingConfig holds the settings of ingredient matching
+type MatchingConfig struct {
+	SynonymsFile string // extra ingredient synonyms, optional
+	Specificity  int    // levels up the ingredient hierarchy an ingredient may stand in for another
+}
+
+// DietConfig holds the rules recipes are checked against for /diet
+type DietConfig struct {
+	RulesFile string // rules replacing or adding to the built-in ones, optional
+}
+
+// RateLimitConfig holds per-user rate limits (hot-reloadable, 0 = unlimited)
+type RateLimitConfig struct {
+	LinksPerHour        int
+	PremiumLinksPerHour int // for users with the premium tier
+}
+
+// Load loads configuration from environment variables and config files.
+// Values are resolved in order of precedence: environment variables, the YAML
+// config file (CONFIG_FILE or config.yaml), the .env file, then defaults.
 func Load() (*Config, error) {
 	viper.SetConfigName(".env")
 	viper.SetConfigType("env")
@@ -63,49 +248,337 @@ func Load() (*Config, error) {
 	// Set defaults
 	viper.SetDefault("APP_LOG_LEVEL", "info")
 	viper.SetDefault("APP_PORT", 8080)
+	viper.SetDefault("STORE_TRANSCRIPTS", true)
 	viper.SetDefault("LLM_PROVIDER", "gemini")
-	viper.SetDefault("LLM_MODEL", "gemini-pro")
+	viper.SetDefault("LLM_PROMPT_VERSION", "v1")
+	viper.SetDefault("LLM_PROMPT_CACHE_MINUTES", 60)
 	viper.SetDefault("PYTHON_SERVICE_URL", "localhost:50051")
 	viper.SetDefault("PYTHON_SERVICE_TIMEOUT", 300)
 	viper.SetDefault("TELEGRAM_DEBUG", false)
+	viper.SetDefault("TELEGRAM_GROUP_PANTRY", false)
+	viper.SetDefault("TELEGRAM_TOPIC_COLLECTIONS", false)
+	viper.SetDefault("TELEGRAM_UPDATE_TIMEOUT", 600)
+	viper.SetDefault("TELEGRAM_UPDATE_WORKERS", 8)
+	viper.SetDefault("TELEGRAM_MESSAGE_WINDOW_MS", 1500)
+	viper.SetDefault("SANDBOX", false)
+	viper.SetDefault("RATE_LIMIT_LINKS_PER_HOUR", 20)
+	viper.SetDefault("RATE_LIMIT_PREMIUM_LINKS_PER_HOUR", 200)
+	viper.SetDefault("PAYMENTS_ENABLED", false)
+	viper.SetDefault("PAYMENTS_CURRENCY", "XTR")
+	viper.SetDefault("PREMIUM_MONTH_PRICE", 100)
+	viper.SetDefault("PREMIUM_YEAR_PRICE", 1000)
+	viper.SetDefault("PREMIUM_FEATURES", "cookbook_export")
+	viper.SetDefault("TELEMETRY_ENABLED", false)
+	viper.SetDefault("TELEMETRY_INTERVAL_MINUTES", 60)
+	viper.SetDefault("BOOKMARK_IMPORT_INTERVAL_SECONDS", 30)
+	viper.SetDefault("INGREDIENT_MATCH_SPECIFICITY", 1)
 
 	// Read config file (optional, won't error if not found)
 	_ = viper.ReadInConfig()
 
-	cfg := &Config{
+	configFile, err := mergeYAMLConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := build(configFile)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// mergeYAMLConfig merges the YAML config file on top of the .env values.
+// The file uses the same keys as the environment variables, e.g.
+//
+//	app_log_level: debug
+//	rate_limit_links_per_hour: 10
+//
+// It returns the path of the merged file, or "" if there is none.
+func mergeYAMLConfig() (string, error) {
+	path := viper.GetString("CONFIG_FILE")
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigFile
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if explicit {
+			return "", fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+		return "", nil
+	}
+
+	viper.SetConfigFile(path)
+	viper.SetConfigType("yaml")
+	if err := viper.MergeInConfig(); err != nil {
+		return "", fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return path, nil
+}
+
+// build creates a Config from the values currently held by viper
+func build(configFile string) *Config {
+	return &Config{
 		Telegram: TelegramConfig{
-			BotToken: viper.GetString("TELEGRAM_BOT_TOKEN"),
-			Debug:    viper.GetBool("TELEGRAM_DEBUG"),
+			BotToken:  viper.GetString("TELEGRAM_BOT_TOKEN"),
+			Debug:     viper.GetBool("TELEGRAM_DEBUG"),
+			WebAppURL: viper.GetString("TELEGRAM_WEBAPP_URL"),
+
+			AdminChatID: viper.GetInt64("TELEGRAM_ADMIN_CHAT_ID"),
+			GroupPantry: viper.GetBool("TELEGRAM_GROUP_PANTRY"),
+
+			TopicCollections: viper.GetBool("TELEGRAM_TOPIC_COLLECTIONS"),
+
+			UpdateTimeout: viper.GetInt("TELEGRAM_UPDATE_TIMEOUT"),
+			UpdateWorkers: viper.GetInt("TELEGRAM_UPDATE_WORKERS"),
+			MessageWindow: viper.GetInt("TELEGRAM_MESSAGE_WINDOW_MS"),
 		},
 		Firebase: FirebaseConfig{
 			ProjectID:       viper.GetString("FIREBASE_PROJECT_ID"),
 			CredentialsPath: viper.GetString("FIREBASE_CREDENTIALS_PATH"),
+			EmulatorHost:    viper.GetString("FIRESTORE_EMULATOR_HOST"),
+			QueryStats:      viper.GetBool("FIRESTORE_QUERY_STATS"),
 		},
 		LLM: LLMConfig{
 			Provider: viper.GetString("LLM_PROVIDER"),
 			APIKey:   getLLMAPIKey(viper.GetString("LLM_PROVIDER")),
 			Model:    viper.GetString("LLM_MODEL"),
+			BaseURL:  strings.TrimSpace(viper.GetString("LLM_BASE_URL")),
+
+			PromptVersion: strings.TrimSpace(viper.GetString("LLM_PROMPT_VERSION")),
+
+			PromptCacheMinutes: viper.GetInt("LLM_PROMPT_CACHE_MINUTES"),
+
+			IntentTask:    taskConfig("INTENT"),
+			ExtractTask:   taskConfig("EXTRACT"),
+			TranslateTask: taskConfig("TRANSLATE"),
+
+			ExtractionExperiment: experimentConfig("EXTRACTION_EXPERIMENT"),
+			IntentExperiment:     experimentConfig("INTENT_EXPERIMENT"),
 		},
 		Python: PythonServiceConfig{
 			URL:     viper.GetString("PYTHON_SERVICE_URL"),
 			Timeout: viper.GetInt("PYTHON_SERVICE_TIMEOUT"),
 		},
 		App: AppConfig{
-			LogLevel: viper.GetString("APP_LOG_LEVEL"),
-			Port:     viper.GetInt("APP_PORT"),
+			LogLevel:   strings.ToLower(viper.GetString("APP_LOG_LEVEL")),
+			Port:       viper.GetInt("APP_PORT"),
+			ConfigFile: configFile,
+
+			StoreTranscripts:        viper.GetBool("STORE_TRANSCRIPTS"),
+			TranscriptRetentionDays: viper.GetInt("TRANSCRIPT_RETENTION_DAYS"),
+
+			Sandbox:         viper.GetBool("SANDBOX"),
+			SandboxFixtures: viper.GetString("SANDBOX_FIXTURES"),
 		},
 		Notion: NotionConfig{
 			ClientID:     viper.GetString("NOTION_CLIENT_ID"),
 			ClientSecret: viper.GetString("NOTION_CLIENT_SECRET"),
 			RedirectURI:  viper.GetString("NOTION_REDIRECT_URI"),
 		},
+		Cloud: CloudStorageConfig{
+			DropboxAppKey:      viper.GetString("DROPBOX_APP_KEY"),
+			DropboxAppSecret:   viper.GetString("DROPBOX_APP_SECRET"),
+			DropboxRedirectURI: viper.GetString("DROPBOX_REDIRECT_URI"),
+			DriveClientID:      viper.GetString("GOOGLE_DRIVE_CLIENT_ID"),
+			DriveClientSecret:  viper.GetString("GOOGLE_DRIVE_CLIENT_SECRET"),
+			DriveRedirectURI:   viper.GetString("GOOGLE_DRIVE_REDIRECT_URI"),
+		},
+		Speech: SpeechConfig{
+			APIKey: viper.GetString("TEXT_TO_SPEECH_API_KEY"),
+		},
+		Images: ImagesConfig{
+			APIKey: viper.GetString("IMAGE_GENERATION_API_KEY"),
+			Model:  viper.GetString("IMAGE_GENERATION_MODEL"),
+		},
+		Payments: PaymentsConfig{
+			Enabled:       viper.GetBool("PAYMENTS_ENABLED"),
+			ProviderToken: viper.GetString("PAYMENTS_PROVIDER_TOKEN"),
+			Currency:      strings.ToUpper(viper.GetString("PAYMENTS_CURRENCY")),
+			MonthPrice:    viper.GetInt("PREMIUM_MONTH_PRICE"),
+			YearPrice:     viper.GetInt("PREMIUM_YEAR_PRICE"),
+			Features:      parseList(viper.GetString("PREMIUM_FEATURES")),
+		},
+		RateLimit: RateLimitConfig{
+			LinksPerHour:        viper.GetInt("RATE_LIMIT_LINKS_PER_HOUR"),
+			PremiumLinksPerHour: viper.GetInt("RATE_LIMIT_PREMIUM_LINKS_PER_HOUR"),
+		},
+		Features: parseFeatureFlags(viper.GetString("FEATURE_FLAGS")),
+		Telemetry: TelemetryConfig{
+			Enabled:  viper.GetBool("TELEMETRY_ENABLED"),
+			Endpoint: viper.GetString("TELEMETRY_ENDPOINT"),
+			Interval: viper.GetInt("TELEMETRY_INTERVAL_MINUTES"),
+		},
+		Bookmarks: BookmarkImportConfig{
+			Domains:  parseList(viper.GetString("BOOKMARK_IMPORT_DOMAINS")),
+			Interval: viper.GetInt("BOOKMARK_IMPORT_INTERVAL_SECONDS"),
+		},
+		Clip: ClipConfig{
+			Secret: viper.GetString("CLIP_API_SECRET"),
+			URL:    viper.GetString("CLIP_API_URL"),
+		},
+		Email: EmailConfig{
+			Domain:     viper.GetString("INBOUND_EMAIL_DOMAIN"),
+			Secret:     viper.GetString("INBOUND_EMAIL_SECRET"),
+			SigningKey: viper.GetString("INBOUND_EMAIL_SIGNING_KEY"),
+		},
+		Blobs: BlobStorageConfig{
+			Provider:        strings.ToLower(viper.GetString("BLOB_STORAGE")),
+			Bucket:          viper.GetString("BLOB_BUCKET"),
+			Region:          viper.GetString("S3_REGION"),
+			Endpoint:        viper.GetString("S3_ENDPOINT"),
+			AccessKeyID:     viper.GetString("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: viper.GetString("S3_SECRET_ACCESS_KEY"),
+		},
+		Moderation: ModerationConfig{
+			BlockedDomains: parseList(viper.GetString("MODERATION_BLOCKED_DOMAINS")),
+		},
+		Matching: MatchingConfig{
+			SynonymsFile: viper.GetString("INGREDIENT_SYNONYMS_FILE"),
+			Specificity:  viper.GetInt("INGREDIENT_MATCH_SPECIFICITY"),
+		},
+		Diets: DietConfig{
+			RulesFile: viper.GetString("DIET_RULES_FILE"),
+		},
 	}
+}
 
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+// maxMatchSpecificity is the most general an ingredient may get before it stands in
+// for nearly anything of its kind
+const maxMatchSpecificity = 3
+
+// minClipSecretLength keeps API token and address signatures from being guessed
+const minClipSecretLength = 32
+
+// parseList parses comma-separated values, dropping empty ones
+func parseList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
 	}
+	return values
+}
 
-	return cfg, nil
+// taskConfig reads the settings of a kind of LLM call, e.g. LLM_MODEL_INTENT
+func taskConfig(task string) TaskConfig {
+	cfg := TaskConfig{Model: strings.TrimSpace(viper.GetString("LLM_MODEL_" + task))}
+
+	raw := strings.TrimSpace(viper.GetString("LLM_TEMPERATURE_" + task))
+	if raw == "" {
+		return cfg
+	}
+	temperature, err := strconv.ParseFloat(raw, 32)
+	if err != nil {
+		cfg.invalid = raw
+		return cfg
+	}
+	t := float32(temperature)
+	cfg.Temperature = &t
+	return cfg
+}
+
+// maxTemperature is the highest temperature the LLM providers accept
+const maxTemperature = 2
+
+// validate records problems with the settings of a kind of LLM call
+func (t TaskConfig) validate(task string, v *ValidationError) {
+	key := "LLM_TEMPERATURE_" + task
+	if t.invalid != "" {
+		v.add(key, fmt.Sprintf("must be a number, got %q", t.invalid))
+		return
+	}
+	if t.Temperature != nil && (*t.Temperature < 0 || *t.Temperature > maxTemperature) {
+		v.add(key, fmt.Sprintf("must be between 0 and %d, got %g", maxTemperature, *t.Temperature))
+	}
+}
+
+// experimentConfig reads the settings of a prompt experiment, e.g. EXTRACTION_EXPERIMENT_VARIANT
+func experimentConfig(prefix string) ExperimentConfig {
+	return ExperimentConfig{
+		Variant:    strings.TrimSpace(viper.GetString(prefix + "_VARIANT")),
+		Percent:    viper.GetInt(prefix + "_PERCENT"),
+		Model:      viper.GetString(prefix + "_MODEL"),
+		PromptFile: viper.GetString(prefix + "_PROMPT_FILE"),
+	}
+}
+
+// validate records problems with a prompt experiment
+func (e ExperimentConfig) validate(prefix string, v *ValidationError) {
+	if e.Variant == "" {
+		return
+	}
+
+	if e.Percent < 0 || e.Percent > 100 {
+		v.add(prefix+"_PERCENT", fmt.Sprintf("must be between 0 and 100, got %d", e.Percent))
+	}
+
+	if e.Model == "" && e.PromptFile == "" {
+		v.add(prefix+"_VARIANT", "needs "+prefix+"_MODEL or "+prefix+"_PROMPT_FILE")
+	}
+
+	if e.PromptFile != "" {
+		if _, err := os.Stat(e.PromptFile); err != nil {
+			v.add(prefix+"_PROMPT_FILE", fmt.Sprintf("cannot be read: %v", err))
+		}
+	}
+}
+
+// parseFeatureFlags parses "name=bool" pairs separated by commas.
+// A bare name enables the flag; malformed values are reported by Validate.
+func parseFeatureFlags(raw string) FeaturesConfig {
+	features := FeaturesConfig{Flags: make(map[string]bool)}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !found {
+			features.Flags[name] = true
+			continue
+		}
+
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			features.invalid = append(features.invalid, entry)
+			continue
+		}
+		features.Flags[name] = enabled
+	}
+	return features
+}
+
+// validateServices records problems with the external service settings
+func (c *Config) validateServices(v *ValidationError) {
+	if c.Firebase.ProjectID == "" {
+		v.add("FIREBASE_PROJECT_ID", "is required")
+	}
+
+	// Firebase credentials can come from either file path or JSON environment variable
+	if c.Firebase.CredentialsPath == "" && viper.GetString("GOOGLE_APPLICATION_CREDENTIALS_JSON") == "" {
+		v.add("FIREBASE_CREDENTIALS_PATH", "or GOOGLE_APPLICATION_CREDENTIALS_JSON is required")
+	}
+
+	if !contains(LLMProviders, c.LLM.Provider) {
+		v.add("LLM_PROVIDER", fmt.Sprintf("must be one of %s, got %q", strings.Join(LLMProviders, ", "), c.LLM.Provider))
+	} else if c.LLM.APIKey == "" && c.LLM.Provider != "ollama" {
+		v.add(strings.ToUpper(c.LLM.Provider)+"_API_KEY", "is required for LLM_PROVIDER="+c.LLM.Provider)
+	}
+	if c.LLM.BaseURL != "" && !strings.HasPrefix(c.LLM.BaseURL, "http://") && !strings.HasPrefix(c.LLM.BaseURL, "https://") {
+		v.add("LLM_BASE_URL", fmt.Sprintf("must be an http(s):// URL, got %q", c.LLM.BaseURL))
+	}
+
+	if c.Python.URL == "" {
+		v.add("PYTHON_SERVICE_URL", "is required")
+	}
 }
 
 // getLLMAPIKey gets the appropriate API key based on the provider
@@ -115,35 +588,149 @@ func getLLMAPIKey(provider string) string {
 		return viper.GetString("GEMINI_API_KEY")
 	case "openai":
 		return viper.GetString("OPENAI_API_KEY")
-	case "anthropic":
-		return viper.GetString("ANTHROPIC_API_KEY")
+	case "ollama":
+		return viper.GetString("OLLAMA_API_KEY")
 	default:
 		return viper.GetString("GEMINI_API_KEY")
 	}
 }
 
-// Validate validates the configuration
+// Validate validates the configuration and reports every problem found
 func (c *Config) Validate() error {
+	var v ValidationError
+
 	if c.Telegram.BotToken == "" {
-		return fmt.Errorf("TELEGRAM_BOT_TOKEN is required")
+		v.add("TELEGRAM_BOT_TOKEN", "is required")
 	}
 
-	if c.Firebase.ProjectID == "" {
-		return fmt.Errorf("FIREBASE_PROJECT_ID is required")
+	// Telegram only opens Mini Apps served over HTTPS
+	if c.Telegram.WebAppURL != "" && !strings.HasPrefix(c.Telegram.WebAppURL, "https://") {
+		v.add("TELEGRAM_WEBAPP_URL", fmt.Sprintf("must be an https:// URL, got %q", c.Telegram.WebAppURL))
 	}
 
-	// Firebase credentials can come from either file path or JSON environment variable
-	if c.Firebase.CredentialsPath == "" && viper.GetString("GOOGLE_APPLICATION_CREDENTIALS_JSON") == "" {
-		return fmt.Errorf("FIREBASE_CREDENTIALS_PATH or GOOGLE_APPLICATION_CREDENTIALS_JSON is required")
+	// In sandbox mode fixtures replace the scraper and the LLM and
+	// the emulator or memory replaces Firestore
+	if !c.App.Sandbox {
+		c.validateServices(&v)
 	}
 
-	if c.LLM.APIKey == "" {
-		return fmt.Errorf("LLM API key is required (GEMINI_API_KEY, OPENAI_API_KEY, or ANTHROPIC_API_KEY)")
+	if c.Python.Timeout <= 0 {
+		v.add("PYTHON_SERVICE_TIMEOUT", fmt.Sprintf("must be a positive number of seconds, got %d", c.Python.Timeout))
 	}
 
-	if c.Python.URL == "" {
-		return fmt.Errorf("PYTHON_SERVICE_URL is required")
+	// A link is scraped while its update is handled, so the update needs longer
+	if c.Telegram.UpdateTimeout <= c.Python.Timeout {
+		v.add("TELEGRAM_UPDATE_TIMEOUT", fmt.Sprintf("must be longer than PYTHON_SERVICE_TIMEOUT (%ds), got %d", c.Python.Timeout, c.Telegram.UpdateTimeout))
+	}
+	if c.Telegram.UpdateWorkers <= 0 {
+		v.add("TELEGRAM_UPDATE_WORKERS", fmt.Sprintf("must be a positive number, got %d", c.Telegram.UpdateWorkers))
+	}
+	if c.Telegram.MessageWindow < 0 {
+		v.add("TELEGRAM_MESSAGE_WINDOW_MS", fmt.Sprintf("must not be negative, got %d", c.Telegram.MessageWindow))
+	}
+
+	if c.App.Port <= 0 || c.App.Port > 65535 {
+		v.add("APP_PORT", fmt.Sprintf("must be between 1 and 65535, got %d", c.App.Port))
+	}
+	if c.App.TranscriptRetentionDays < 0 {
+		v.add("TRANSCRIPT_RETENTION_DAYS", fmt.Sprintf("must not be negative, got %d", c.App.TranscriptRetentionDays))
+	}
+
+	if c.LLM.PromptCacheMinutes < 0 {
+		v.add("LLM_PROMPT_CACHE_MINUTES", fmt.Sprintf("cannot be negative, got %d", c.LLM.PromptCacheMinutes))
+	}
+	c.LLM.IntentTask.validate("INTENT", &v)
+	c.LLM.ExtractTask.validate("EXTRACT", &v)
+	c.LLM.TranslateTask.validate("TRANSLATE", &v)
+	c.LLM.ExtractionExperiment.validate("EXTRACTION_EXPERIMENT", &v)
+	c.LLM.IntentExperiment.validate("INTENT_EXPERIMENT", &v)
+
+	if c.Telemetry.Enabled {
+		if !strings.HasPrefix(c.Telemetry.Endpoint, "https://") && !strings.HasPrefix(c.Telemetry.Endpoint, "http://") {
+			v.add("TELEMETRY_ENDPOINT", fmt.Sprintf("must be an http(s):// URL when TELEMETRY_ENABLED is set, got %q", c.Telemetry.Endpoint))
+		}
+		if c.Telemetry.Interval <= 0 {
+			v.add("TELEMETRY_INTERVAL_MINUTES", fmt.Sprintf("must be a positive number of minutes, got %d", c.Telemetry.Interval))
+		}
 	}
 
-	return nil
+	if c.Payments.Enabled {
+		switch {
+		case c.Payments.Currency == "XTR" && c.Payments.ProviderToken != "":
+			v.add("PAYMENTS_PROVIDER_TOKEN", "must be empty when PAYMENTS_CURRENCY is XTR (Telegram Stars)")
+		case c.Payments.Currency != "XTR" && c.Payments.ProviderToken == "":
+			v.add("PAYMENTS_PROVIDER_TOKEN", fmt.Sprintf("is required to charge in %s; leave PAYMENTS_CURRENCY as XTR for Telegram Stars", c.Payments.Currency))
+		}
+		if c.Payments.MonthPrice < 0 || c.Payments.YearPrice < 0 {
+			v.add("PREMIUM_MONTH_PRICE", "and PREMIUM_YEAR_PRICE cannot be negative")
+		}
+		if c.Payments.MonthPrice == 0 && c.Payments.YearPrice == 0 {
+			v.add("PREMIUM_MONTH_PRICE", "or PREMIUM_YEAR_PRICE must be set when PAYMENTS_ENABLED is set")
+		}
+	}
+
+	if c.Bookmarks.Interval < 0 {
+		v.add("BOOKMARK_IMPORT_INTERVAL_SECONDS", fmt.Sprintf("cannot be negative, got %d", c.Bookmarks.Interval))
+	}
+
+	if c.Clip.Secret != "" && len(c.Clip.Secret) < minClipSecretLength {
+		v.add("CLIP_API_SECRET", fmt.Sprintf("must be at least %d characters", minClipSecretLength))
+	}
+	if c.Clip.URL != "" && !strings.HasPrefix(c.Clip.URL, "https://") {
+		v.add("CLIP_API_URL", fmt.Sprintf("must be an https:// URL, got %q", c.Clip.URL))
+	}
+
+	if c.Email.Domain != "" {
+		if len(c.Email.Secret) < minClipSecretLength {
+			v.add("INBOUND_EMAIL_SECRET", fmt.Sprintf("must be at least %d characters when INBOUND_EMAIL_DOMAIN is set", minClipSecretLength))
+		}
+		if c.Email.SigningKey == "" {
+			v.add("INBOUND_EMAIL_SIGNING_KEY", "is required when INBOUND_EMAIL_DOMAIN is set")
+		}
+	}
+
+	// Generated covers are kept in blob storage
+	if c.Images.APIKey != "" && c.Blobs.Provider == "" {
+		v.add("BLOB_STORAGE", "is required when IMAGE_GENERATION_API_KEY is set")
+	}
+
+	switch c.Blobs.Provider {
+	case "":
+	case "gcs", "s3":
+		if c.Blobs.Bucket == "" {
+			v.add("BLOB_BUCKET", "is required when BLOB_STORAGE is set")
+		}
+		if c.Blobs.Provider == "s3" {
+			if c.Blobs.Region == "" {
+				v.add("S3_REGION", "is required when BLOB_STORAGE is s3")
+			}
+			if c.Blobs.AccessKeyID == "" || c.Blobs.SecretAccessKey == "" {
+				v.add("S3_ACCESS_KEY_ID", "and S3_SECRET_ACCESS_KEY are required when BLOB_STORAGE is s3")
+			}
+		}
+	default:
+		v.add("BLOB_STORAGE", fmt.Sprintf("must be gcs or s3, got %q", c.Blobs.Provider))
+	}
+
+	if c.Matching.SynonymsFile != "" {
+		if _, err := os.Stat(c.Matching.SynonymsFile); err != nil {
+			v.add("INGREDIENT_SYNONYMS_FILE", fmt.Sprintf("cannot be read: %v", err))
+		}
+	}
+	if c.Diets.RulesFile != "" {
+		if _, err := os.Stat(c.Diets.RulesFile); err != nil {
+			v.add("DIET_RULES_FILE", fmt.Sprintf("cannot be read: %v", err))
+		}
+	}
+	if c.Matching.Specificity < 0 || c.Matching.Specificity > maxMatchSpecificity {
+		v.add("INGREDIENT_MATCH_SPECIFICITY", fmt.Sprintf("must be between 0 and %d, got %d", maxMatchSpecificity, c.Matching.Specificity))
+	}
+
+	for _, entry := range c.Features.invalid {
+		v.add("FEATURE_FLAGS", fmt.Sprintf("entry %q must be name=true or name=false", entry))
+	}
+
+	c.Runtime().validate(&v)
+
+	return v.errOrNil()
 }
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+// validConfig returns a configuration that passes validation
+func validConfig() *Config {
+	return &Config{
+		Telegram:  TelegramConfig{BotToken: "123:token", UpdateTimeout: 600, UpdateWorkers: 8},
+		Firebase:  FirebaseConfig{ProjectID: "receipt-bot", CredentialsPath: "credentials.json"},
+		LLM:       LLMConfig{Provider: "gemini", APIKey: "key"},
+		Python:    PythonServiceConfig{URL: "localhost:50051", Timeout: 300},
+		App:       AppConfig{Port: 8080, LogLevel: "info"},
+		RateLimit: RateLimitConfig{LinksPerHour: 20, PremiumLinksPerHour: 200},
+	}
+}
+
+// invalidKeys returns the keys err reports as invalid
+func invalidKeys(t *testing.T, err error) map[string]bool {
+	t.Helper()
+	keys := make(map[string]bool)
+	if err == nil {
+		return keys
+	}
+	var v *ValidationError
+	if !errors.As(err, &v) {
+		t.Fatalf("Validate() error = %v, want a *ValidationError", err)
+	}
+	for _, f := range v.Fields {
+		keys[f.Key] = true
+	}
+	return keys
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("Validate() of a valid config error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		key    string
+	}{
+		{"missing bot token", func(c *Config) { c.Telegram.BotToken = "" }, "TELEGRAM_BOT_TOKEN"},
+		{"unsupported provider", func(c *Config) { c.LLM.Provider = "anthropic" }, "LLM_PROVIDER"},
+		{"missing API key", func(c *Config) { c.LLM.APIKey = "" }, "GEMINI_API_KEY"},
+		{"missing project", func(c *Config) { c.Firebase.ProjectID = "" }, "FIREBASE_PROJECT_ID"},
+		{"unknown log level", func(c *Config) { c.App.LogLevel = "loud" }, "APP_LOG_LEVEL"},
+		{"negative link limit", func(c *Config) { c.RateLimit.LinksPerHour = -1 }, "RATE_LIMIT_LINKS_PER_HOUR"},
+		{"port out of range", func(c *Config) { c.App.Port = 70000 }, "APP_PORT"},
+		{"update timeout shorter than scraping", func(c *Config) { c.Telegram.UpdateTimeout = 60 }, "TELEGRAM_UPDATE_TIMEOUT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			if keys := invalidKeys(t, cfg.Validate()); !keys[tt.key] {
+				t.Errorf("Validate() invalid keys = %v, want %s", keys, tt.key)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_ReportsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.Telegram.BotToken = ""
+	cfg.App.Port = 0
+	cfg.App.LogLevel = "loud"
+
+	keys := invalidKeys(t, cfg.Validate())
+	for _, key := range []string{"TELEGRAM_BOT_TOKEN", "APP_PORT", "APP_LOG_LEVEL"} {
+		if !keys[key] {
+			t.Errorf("Validate() invalid keys = %v, want %s among them", keys, key)
+		}
+	}
+}
+
+func TestConfig_Validate_SandboxNeedsNoServices(t *testing.T) {
+	cfg := validConfig()
+	cfg.App.Sandbox = true
+	cfg.Firebase = FirebaseConfig{}
+	cfg.LLM = LLMConfig{}
+	cfg.Python.URL = ""
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() in sandbox mode error = %v", err)
+	}
+}
+
+func TestLoad_LeavesModelToProvider(t *testing.T) {
+	cfg, _ := loadWithFile(t, "llm_provider: ollama\n")
+	if cfg.LLM.Model != "" {
+		t.Errorf("LLM.Model = %q, want it empty so the provider picks its default", cfg.LLM.Model)
+	}
+}
+
+func TestLoad_TaskConfig(t *testing.T) {
+	cfg, _ := loadWithFile(t, "llm_model_extract: gemini-1.5-pro\nllm_temperature_intent: \"0\"\n")
+
+	if cfg.LLM.ExtractTask.Model != "gemini-1.5-pro" || cfg.LLM.ExtractTask.Temperature != nil {
+		t.Errorf("ExtractTask = %+v, want the model override only", cfg.LLM.ExtractTask)
+	}
+	if cfg.LLM.IntentTask.Model != "" {
+		t.Errorf("IntentTask.Model = %q, want it empty so the global model is used", cfg.LLM.IntentTask.Model)
+	}
+	if temperature := cfg.LLM.IntentTask.Temperature; temperature == nil || *temperature != 0 {
+		t.Errorf("IntentTask.Temperature = %v, want 0", temperature)
+	}
+	if cfg.LLM.TranslateTask.Model != "" || cfg.LLM.TranslateTask.Temperature != nil {
+		t.Errorf("TranslateTask = %+v, want no overrides", cfg.LLM.TranslateTask)
+	}
+}
+
+func TestConfig_Validate_TaskTemperature(t *testing.T) {
+	hot := float32(maxTemperature + 1)
+	cfg := validConfig()
+	cfg.LLM.ExtractTask = TaskConfig{Temperature: &hot}
+	cfg.LLM.IntentTask = TaskConfig{invalid: "warm"}
+
+	keys := invalidKeys(t, cfg.Validate())
+	for _, key := range []string{"LLM_TEMPERATURE_EXTRACT", "LLM_TEMPERATURE_INTENT"} {
+		if !keys[key] {
+			t.Errorf("Validate() did not report %s, got %v", key, keys)
+		}
+	}
+	if keys["LLM_TEMPERATURE_TRANSLATE"] {
+		t.Error("Validate() reported LLM_TEMPERATURE_TRANSLATE, which is unset")
+	}
+}
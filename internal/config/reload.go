@@ -0,0 +1,201 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// RuntimeConfig holds the non-critical settings that can be changed without a restart
+type RuntimeConfig struct {
+	LogLevel      string
+	RateLimit     RateLimitConfig
+	PromptVersion string
+}
+
+// Runtime returns the hot-reloadable part of the configuration
+func (c *Config) Runtime() RuntimeConfig {
+	return RuntimeConfig{
+		LogLevel:      c.App.LogLevel,
+		RateLimit:     c.RateLimit,
+		PromptVersion: c.LLM.PromptVersion,
+	}
+}
+
+// validate records problems with the runtime settings
+func (r RuntimeConfig) validate(v *ValidationError) {
+	if !contains(validLogLevels, r.LogLevel) {
+		v.add("APP_LOG_LEVEL", fmt.Sprintf("must be one of %s, got %q", strings.Join(validLogLevels, ", "), r.LogLevel))
+	}
+
+	if r.PromptVersion != "" && !contains(PromptVersions, r.PromptVersion) {
+		v.add("LLM_PROMPT_VERSION", fmt.Sprintf("must be one of %s, got %q", strings.Join(PromptVersions, ", "), r.PromptVersion))
+	}
+
+	if r.RateLimit.LinksPerHour < 0 {
+		v.add("RATE_LIMIT_LINKS_PER_HOUR", fmt.Sprintf("cannot be negative, got %d", r.RateLimit.LinksPerHour))
+	}
+
+	if r.RateLimit.PremiumLinksPerHour < 0 {
+		v.add("RATE_LIMIT_PREMIUM_LINKS_PER_HOUR", fmt.Sprintf("cannot be negative, got %d", r.RateLimit.PremiumLinksPerHour))
+	}
+}
+
+// Watcher keeps the runtime settings up to date.
+// Settings are reloaded on SIGHUP and whenever the YAML config file changes.
+// Critical settings (tokens, project IDs, service URLs) still require a restart.
+type Watcher struct {
+	mu        sync.RWMutex
+	current   RuntimeConfig
+	listeners []func(RuntimeConfig)
+
+	// reloading serializes reloads, as viper is not safe for concurrent use
+	reloading sync.Mutex
+}
+
+// NewWatcher creates a watcher seeded with the loaded configuration
+func NewWatcher(cfg *Config) *Watcher {
+	return &Watcher{
+		current: cfg.Runtime(),
+	}
+}
+
+// Current returns the latest runtime settings
+func (w *Watcher) Current() RuntimeConfig {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// OnChange registers a callback invoked after the runtime settings change
+func (w *Watcher) OnChange(fn func(RuntimeConfig)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.listeners = append(w.listeners, fn)
+}
+
+// Reload re-reads the config files and applies the runtime settings.
+// Invalid settings are rejected and the previous values are kept.
+func (w *Watcher) Reload() error {
+	w.reloading.Lock()
+	defer w.reloading.Unlock()
+
+	_ = viper.ReadInConfig()
+
+	configFile, err := mergeYAMLConfig()
+	if err != nil {
+		return err
+	}
+
+	next := build(configFile).Runtime()
+
+	var v ValidationError
+	next.validate(&v)
+	if err := v.errOrNil(); err != nil {
+		return fmt.Errorf("invalid runtime configuration: %w", err)
+	}
+
+	w.mu.Lock()
+	if next == w.current {
+		w.mu.Unlock()
+		return nil
+	}
+	w.current = next
+	listeners := append([]func(RuntimeConfig){}, w.listeners...)
+	w.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(next)
+	}
+
+	return nil
+}
+
+// Start reloads the runtime settings on SIGHUP and on config file changes
+// until the context is cancelled
+func (w *Watcher) Start(ctx context.Context) {
+	w.reloading.Lock()
+	configFile := viper.ConfigFileUsed()
+	w.reloading.Unlock()
+	if configFile != "" {
+		if err := w.watchFile(ctx, configFile); err != nil {
+			log.Printf("Warning: Failed to watch %s for changes: %v", configFile, err)
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				log.Println("Received SIGHUP, reloading configuration...")
+				w.reload()
+			}
+		}
+	}()
+}
+
+// watchFile reloads the runtime settings whenever the config file is written or
+// replaced. Its directory is watched, as editors often save by replacing the file.
+// Unlike viper.WatchConfig, the file is only read by Reload, under its lock.
+func (w *Watcher) watchFile(ctx context.Context, path string) error {
+	path = filepath.Clean(path)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == path && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					log.Printf("Config file changed (%s), reloading...", event.Name)
+					w.reload()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("Warning: Config file watch error: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// reload applies a reload and logs the outcome
+func (w *Watcher) reload() {
+	if err := w.Reload(); err != nil {
+		log.Printf("Warning: Failed to reload configuration: %v", err)
+		return
+	}
+
+	current := w.Current()
+	log.Printf("Runtime configuration: log level=%s, rate limits=%d links/hour (premium %d), prompt version=%s",
+		current.LogLevel, current.RateLimit.LinksPerHour, current.RateLimit.PremiumLinksPerHour, current.PromptVersion)
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// loadWithFile loads the configuration in sandbox mode with a YAML config file
+// holding yaml, returning the file's path
+func loadWithFile(t *testing.T, yaml string) (*Config, string) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, yaml)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("SANDBOX", "true")
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:token")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return cfg, path
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	cfg, path := loadWithFile(t, "app_log_level: info\nrate_limit_links_per_hour: 20\n")
+	w := NewWatcher(cfg)
+
+	var changes []RuntimeConfig
+	w.OnChange(func(r RuntimeConfig) { changes = append(changes, r) })
+
+	// A changed setting is applied and reported
+	writeFile(t, path, "app_log_level: debug\nrate_limit_links_per_hour: 5\nllm_prompt_version: v2\n")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := w.Current(); got.LogLevel != "debug" || got.RateLimit.LinksPerHour != 5 || got.PromptVersion != "v2" {
+		t.Errorf("Current() = %+v, want debug, 5 links per hour and prompt version v2", got)
+	}
+	if len(changes) != 1 || changes[0].LogLevel != "debug" {
+		t.Fatalf("listeners got %+v, want one change to debug", changes)
+	}
+
+	// Reloading unchanged settings notifies no one
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if len(changes) != 1 {
+		t.Errorf("listeners called %d times, want no call for unchanged settings", len(changes))
+	}
+}
+
+func TestWatcher_Reload_KeepsPreviousOnInvalid(t *testing.T) {
+	cfg, path := loadWithFile(t, "app_log_level: warn\n")
+	w := NewWatcher(cfg)
+
+	called := false
+	w.OnChange(func(RuntimeConfig) { called = true })
+
+	writeFile(t, path, "app_log_level: loud\nrate_limit_links_per_hour: -3\nllm_prompt_version: v9\n")
+	err := w.Reload()
+	if err == nil {
+		t.Fatal("Reload() of invalid settings returned no error")
+	}
+	keys := invalidKeys(t, err)
+	if !keys["APP_LOG_LEVEL"] || !keys["RATE_LIMIT_LINKS_PER_HOUR"] || !keys["LLM_PROMPT_VERSION"] {
+		t.Errorf("Reload() invalid keys = %v, want APP_LOG_LEVEL, RATE_LIMIT_LINKS_PER_HOUR and LLM_PROMPT_VERSION", keys)
+	}
+	if got := w.Current(); got.LogLevel != "warn" || got.RateLimit.LinksPerHour != 20 || got.PromptVersion != "v1" {
+		t.Errorf("Current() = %+v, want the previous settings kept", got)
+	}
+	if called {
+		t.Error("listeners were notified of rejected settings")
+	}
+}
+
+func TestWatcher_Reload_Concurrent(t *testing.T) {
+	cfg, path := loadWithFile(t, "app_log_level: info\n")
+	w := NewWatcher(cfg)
+
+	// Reloads from the file watcher and SIGHUP may overlap
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i == 0 {
+				writeFile(t, path, "app_log_level: error\n")
+			}
+			_ = w.Reload()
+		}(i)
+	}
+	wg.Wait()
+
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := w.Current().LogLevel; got != "error" {
+		t.Errorf("Current().LogLevel = %q, want error", got)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LLMProviders lists the supported LLM_PROVIDER values, the providers
+// llm.NewLLMAdapter builds adapters for
+var LLMProviders = []string{"gemini", "openai", "ollama"}
+
+// PromptVersions lists the supported LLM_PROMPT_VERSION values, the prompt
+// sets the llm adapters can extract with
+var PromptVersions = []string{"v1", "v2"}
+
+// validLogLevels lists the supported APP_LOG_LEVEL values
+var validLogLevels = []string{"debug", "info", "warn", "error"}
+
+// FieldError describes a single invalid configuration key
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+// ValidationError collects every problem found while validating the configuration
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = fmt.Sprintf("%s %s", f.Key, f.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// add records a problem with the given key
+func (e *ValidationError) add(key, message string) {
+	e.Fields = append(e.Fields, FieldError{Key: key, Message: message})
+}
+
+// errOrNil returns the error only if at least one problem was recorded
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// contains reports whether value is in the list
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
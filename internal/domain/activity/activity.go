@@ -0,0 +1,85 @@
+// Package activity keeps an append-only audit trail of the actions that changed
+// what a user has stored or shared.
+package activity
+
+import (
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// Retention is how long entries are kept before they are deleted
+const Retention = 90 * 24 * time.Hour
+
+// Action is a kind of user-affecting action
+type Action string
+
+const (
+	ActionRecipeSaved         Action = "recipe_saved"
+	ActionRecipeDeleted       Action = "recipe_deleted"
+	ActionRecipeExported      Action = "recipe_exported"
+	ActionServiceConnected    Action = "service_connected"
+	ActionServiceDisconnected Action = "service_disconnected"
+	ActionPremiumPurchased    Action = "premium_purchased"
+)
+
+// Label returns a short human-readable description of the action
+func (a Action) Label() string {
+	switch a {
+	case ActionRecipeSaved:
+		return "Saved"
+	case ActionRecipeDeleted:
+		return "Deleted"
+	case ActionRecipeExported:
+		return "Exported"
+	case ActionServiceConnected:
+		return "Connected"
+	case ActionServiceDisconnected:
+		return "Disconnected"
+	case ActionPremiumPurchased:
+		return "Bought premium"
+	default:
+		return string(a)
+	}
+}
+
+// Entry is one action in a user's audit trail. Entries are never changed once recorded.
+type Entry struct {
+	ID        shared.ID
+	UserID    UserID
+	Action    Action
+	Detail    string // what the action was about, e.g. a recipe title or a service name
+	CreatedAt time.Time
+}
+
+// NewEntry creates an audit entry for an action taken at now
+func NewEntry(userID UserID, action Action, detail string, now time.Time) (*Entry, error) {
+	if userID.IsEmpty() || action == "" {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &Entry{
+		ID:        shared.NewID(),
+		UserID:    userID,
+		Action:    action,
+		Detail:    detail,
+		CreatedAt: now,
+	}, nil
+}
+
+// Expired reports whether the entry is past the retention period at now
+func (e *Entry) Expired(now time.Time) bool {
+	return now.Sub(e.CreatedAt) > Retention
+}
+
+// Count returns how many times each action was taken in the entries
+func Count(entries []*Entry) map[Action]int {
+	counts := make(map[Action]int)
+	for _, e := range entries {
+		counts[e.Action]++
+	}
+	return counts
+}
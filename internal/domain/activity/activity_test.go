@@ -0,0 +1,54 @@
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestNewEntry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	entry, err := NewEntry(shared.NewID(), ActionRecipeSaved, "Carbonara", now)
+	if err != nil {
+		t.Fatalf("NewEntry() error = %v", err)
+	}
+	if entry.ID.IsEmpty() || entry.Detail != "Carbonara" || !entry.CreatedAt.Equal(now) {
+		t.Errorf("NewEntry() = %+v", entry)
+	}
+
+	if _, err := NewEntry("", ActionRecipeSaved, "", now); err == nil {
+		t.Error("NewEntry() without user error = nil, want an error")
+	}
+	if _, err := NewEntry(shared.NewID(), "", "", now); err == nil {
+		t.Error("NewEntry() without action error = nil, want an error")
+	}
+}
+
+func TestEntry_Expired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entry, _ := NewEntry(shared.NewID(), ActionRecipeExported, "obsidian", now)
+
+	if entry.Expired(now.Add(Retention)) {
+		t.Error("Expired() at the end of retention = true, want false")
+	}
+	if !entry.Expired(now.Add(Retention + time.Hour)) {
+		t.Error("Expired() after retention = false, want true")
+	}
+}
+
+func TestCount(t *testing.T) {
+	userID := shared.NewID()
+	now := time.Now()
+	var entries []*Entry
+	for _, action := range []Action{ActionRecipeSaved, ActionRecipeSaved, ActionRecipeExported} {
+		entry, _ := NewEntry(userID, action, "", now)
+		entries = append(entries, entry)
+	}
+
+	counts := Count(entries)
+	if counts[ActionRecipeSaved] != 2 || counts[ActionRecipeExported] != 1 || counts[ActionRecipeDeleted] != 0 {
+		t.Errorf("Count() = %v", counts)
+	}
+}
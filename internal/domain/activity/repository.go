@@ -0,0 +1,20 @@
+package activity
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for audit trail persistence (Port).
+// Entries can only be appended and, once past retention, deleted.
+type Repository interface {
+	// Append stores a new entry
+	Append(ctx context.Context, e *Entry) error
+
+	// FindByUser retrieves the entries of a user recorded since the given time, newest first
+	FindByUser(ctx context.Context, userID UserID, since time.Time) ([]*Entry, error)
+
+	// DeleteBefore removes the entries of a user recorded before the cutoff
+	// and returns how many were removed
+	DeleteBefore(ctx context.Context, userID UserID, cutoff time.Time) (int, error)
+}
@@ -0,0 +1,15 @@
+package analytics
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for usage count persistence (Port)
+type Repository interface {
+	// Increment counts one use of a feature in the week starting at week
+	Increment(ctx context.Context, week time.Time, feature Feature) error
+
+	// FindSince retrieves the weeks starting at or after since, oldest first
+	FindSince(ctx context.Context, since time.Time) ([]*Week, error)
+}
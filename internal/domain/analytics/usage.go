@@ -0,0 +1,97 @@
+// Package analytics counts how often each feature of the bot is used, per week,
+// so the owner can see where development time is best spent. Counts are
+// anonymous: they never record who used a feature.
+package analytics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feature names something users can do with the bot
+type Feature string
+
+const (
+	FeatureRecipeLink     Feature = "link"            // sending a recipe link to save
+	FeatureUnknownCommand Feature = "unknown_command" // a command the bot does not have
+)
+
+// CommandFeature returns the feature of a command, e.g. "/export"
+func CommandFeature(command string) Feature {
+	return Feature("/" + strings.ToLower(command))
+}
+
+// IntentFeature returns the feature of a detected intent, e.g. "intent:list_recipes"
+func IntentFeature(intent string) Feature {
+	return Feature("intent:" + strings.ToLower(intent))
+}
+
+// WeekOf returns the start of the week t is in: Monday, 00:00 UTC
+func WeekOf(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// Week counts the uses of each feature in one week
+type Week struct {
+	Start  time.Time // see WeekOf
+	Counts map[Feature]int
+}
+
+// Usage is how much a feature was used over the weeks of a summary
+type Usage struct {
+	Feature  Feature
+	ThisWeek int
+	LastWeek int
+	Total    int
+}
+
+// Trend returns how this week compares to last week: "up", "down" or "flat"
+func (u Usage) Trend() string {
+	switch {
+	case u.ThisWeek > u.LastWeek:
+		return "up"
+	case u.ThisWeek < u.LastWeek:
+		return "down"
+	default:
+		return "flat"
+	}
+}
+
+// Summarize adds up the usage of each feature over the weeks, most used first.
+// thisWeek is the start of the current week.
+func Summarize(weeks []*Week, thisWeek time.Time) []Usage {
+	lastWeek := thisWeek.AddDate(0, 0, -7)
+
+	byFeature := make(map[Feature]*Usage)
+	for _, week := range weeks {
+		for feature, count := range week.Counts {
+			u, ok := byFeature[feature]
+			if !ok {
+				u = &Usage{Feature: feature}
+				byFeature[feature] = u
+			}
+			u.Total += count
+			switch {
+			case week.Start.Equal(thisWeek):
+				u.ThisWeek += count
+			case week.Start.Equal(lastWeek):
+				u.LastWeek += count
+			}
+		}
+	}
+
+	usage := make([]Usage, 0, len(byFeature))
+	for _, u := range byFeature {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Total != usage[j].Total {
+			return usage[i].Total > usage[j].Total
+		}
+		return usage[i].Feature < usage[j].Feature
+	})
+	return usage
+}
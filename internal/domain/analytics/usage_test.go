@@ -0,0 +1,54 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekOf(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	tests := []time.Time{
+		monday,
+		time.Date(2026, 10, 16, 18, 30, 0, 0, time.UTC),
+		time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC),
+		// Monday morning in São Paulo is still Monday in UTC
+		time.Date(2026, 10, 12, 1, 0, 0, 0, time.FixedZone("BRT", -3*3600)),
+	}
+	for _, tt := range tests {
+		if got := WeekOf(tt); !got.Equal(monday) {
+			t.Errorf("WeekOf(%v) = %v, want %v", tt, got, monday)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	thisWeek := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	weeks := []*Week{
+		{Start: thisWeek.AddDate(0, 0, -14), Counts: map[Feature]int{"/plan": 9}},
+		{Start: thisWeek.AddDate(0, 0, -7), Counts: map[Feature]int{FeatureRecipeLink: 4, "/export": 1}},
+		{Start: thisWeek, Counts: map[Feature]int{FeatureRecipeLink: 6, "/export": 1, IntentFeature("LIST_RECIPES"): 2}},
+	}
+
+	usage := Summarize(weeks, thisWeek)
+	want := []Usage{
+		{Feature: FeatureRecipeLink, ThisWeek: 6, LastWeek: 4, Total: 10},
+		{Feature: "/plan", Total: 9},
+		{Feature: "/export", ThisWeek: 1, LastWeek: 1, Total: 2},
+		{Feature: "intent:list_recipes", ThisWeek: 2, Total: 2},
+	}
+	if len(usage) != len(want) {
+		t.Fatalf("Summarize() = %+v, want %+v", usage, want)
+	}
+	for i := range want {
+		if usage[i] != want[i] {
+			t.Errorf("Summarize()[%d] = %+v, want %+v", i, usage[i], want[i])
+		}
+	}
+
+	if got := usage[0].Trend(); got != "up" {
+		t.Errorf("Trend() = %q, want up", got)
+	}
+	if got := usage[1].Trend(); got != "flat" {
+		t.Errorf("Trend() = %q, want flat", got)
+	}
+}
@@ -0,0 +1,94 @@
+// Package bookmark picks the recipe links out of a user's browser bookmarks.
+package bookmark
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Bookmark is a saved link and the title the browser gave it
+type Bookmark struct {
+	URL   string
+	Title string
+}
+
+// DefaultDomains are recipe sites whose links are imported without further checks
+var DefaultDomains = []string{
+	"allrecipes.com",
+	"bbcgoodfood.com",
+	"bonappetit.com",
+	"cooking.nytimes.com",
+	"epicurious.com",
+	"food52.com",
+	"panelinha.com.br",
+	"seriouseats.com",
+	"tudogostoso.com.br",
+}
+
+// recipeWords hint that a link on any other site is a recipe
+var recipeWords = []string{
+	"recipe", "receita", "receta", "recette", "rezept", "ricetta",
+	"how to make", "como fazer", "homemade", "caseir",
+}
+
+// Selector decides which bookmarks look like recipes
+type Selector struct {
+	domains []string
+}
+
+// NewSelector creates a selector trusting the given domains and their subdomains.
+// DefaultDomains are used when none are given.
+func NewSelector(domains []string) *Selector {
+	if len(domains) == 0 {
+		domains = DefaultDomains
+	}
+
+	normalized := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "www.")
+		if d != "" {
+			normalized = append(normalized, d)
+		}
+	}
+	return &Selector{domains: normalized}
+}
+
+// LooksLikeRecipe reports whether a bookmark links to a page of an allowed recipe site,
+// or its address or title mentions a recipe. Only http and https links qualify.
+func (s *Selector) LooksLikeRecipe(b Bookmark) bool {
+	u, err := url.Parse(strings.TrimSpace(b.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	for _, d := range s.domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return u.Path != "" && u.Path != "/" // a recipe, not the site's home page
+		}
+	}
+
+	text := strings.ToLower(u.Path + " " + u.RawQuery + " " + b.Title)
+	for _, word := range recipeWords {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecipeLinks returns the links of the bookmarks that look like recipes,
+// in bookmark order and without duplicates
+func (s *Selector) RecipeLinks(bookmarks []Bookmark) []string {
+	seen := make(map[string]bool)
+	var links []string
+	for _, b := range bookmarks {
+		link := strings.TrimSpace(b.URL)
+		if seen[link] || !s.LooksLikeRecipe(b) {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+	}
+	return links
+}
@@ -0,0 +1,56 @@
+package bookmark
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelector_LooksLikeRecipe(t *testing.T) {
+	selector := NewSelector(nil)
+
+	tests := []struct {
+		bookmark Bookmark
+		want     bool
+	}{
+		{Bookmark{URL: "https://www.seriouseats.com/pasta-carbonara"}, true},
+		{Bookmark{URL: "https://cooking.nytimes.com/recipes/1234"}, true},
+		{Bookmark{URL: "https://www.seriouseats.com/"}, false},
+		{Bookmark{URL: "https://blog.example.com/2020/06/lemon-cake-recipe"}, true},
+		{Bookmark{URL: "https://www.youtube.com/watch?v=abc", Title: "Pão caseiro fácil"}, true},
+		{Bookmark{URL: "https://www.youtube.com/watch?v=xyz", Title: "Lo-fi beats to study to"}, false},
+		{Bookmark{URL: "https://news.example.com/politics"}, false},
+		{Bookmark{URL: "javascript:alert('recipe')"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := selector.LooksLikeRecipe(tt.bookmark); got != tt.want {
+			t.Errorf("LooksLikeRecipe(%+v) = %v, want %v", tt.bookmark, got, tt.want)
+		}
+	}
+}
+
+func TestSelector_CustomDomains(t *testing.T) {
+	selector := NewSelector([]string{" WWW.MyFoodBlog.net "})
+
+	if !selector.LooksLikeRecipe(Bookmark{URL: "https://myfoodblog.net/sunday-roast"}) {
+		t.Error("LooksLikeRecipe() = false for an allowed domain")
+	}
+	if selector.LooksLikeRecipe(Bookmark{URL: "https://www.seriouseats.com/pasta-carbonara"}) {
+		t.Error("LooksLikeRecipe() = true for a default domain that was replaced")
+	}
+}
+
+func TestSelector_RecipeLinks(t *testing.T) {
+	bookmarks := []Bookmark{
+		{URL: "https://www.allrecipes.com/recipe/1/"},
+		{URL: "https://mail.example.com/"},
+		{URL: "https://www.allrecipes.com/recipe/1/"},
+		{URL: "https://food52.com/recipes/2"},
+	}
+
+	got := NewSelector(nil).RecipeLinks(bookmarks)
+	want := []string{"https://www.allrecipes.com/recipe/1/", "https://food52.com/recipes/2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RecipeLinks() = %v, want %v", got, want)
+	}
+}
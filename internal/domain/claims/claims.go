@@ -0,0 +1,201 @@
+// Package claims checks the times, servings and amounts an extracted recipe states
+// for values no cook would write, so the user can confirm or fix them before saving.
+package claims
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shopping"
+)
+
+// Kind is the part of a recipe an issue is about
+type Kind string
+
+const (
+	KindTime     Kind = "time"
+	KindServings Kind = "servings"
+	KindAmount   Kind = "amount"
+)
+
+// Issue is a claim of a recipe that looks implausible
+type Issue struct {
+	Kind   Kind
+	Detail string
+}
+
+const (
+	// maxStatedTime is the longest total time a recipe can plausibly state
+	maxStatedTime = 48 * time.Hour
+	// maxServings is the largest number of servings a recipe can plausibly state
+	maxServings = 100
+	// placeholderQuantity replaces an amount that makes no sense and cannot be corrected
+	placeholderQuantity = "as needed"
+)
+
+// maxAmounts is the largest plausible amount of an ingredient per canonical unit
+// (see shopping.ParseAmount); an empty unit is a plain count
+var maxAmounts = map[string]float64{
+	"g":     25000,
+	"ml":    25000,
+	"tsp":   60,
+	"tbsp":  60,
+	"cup":   40,
+	"oz":    900,
+	"lb":    50,
+	"clove": 100,
+	"can":   50,
+	"bunch": 30,
+	"slice": 200,
+	"":      500,
+}
+
+// Check returns the implausible claims of a recipe: a stated time far from what its
+// timed steps add up to, or longer than two days, servings below one or above a
+// hundred, and ingredient amounts of zero or far more than a kitchen holds
+func Check(rec *recipe.Recipe) []Issue {
+	var issues []Issue
+	if issue, ok := checkTime(rec); ok {
+		issues = append(issues, issue)
+	}
+	if servings := rec.Servings(); servings != nil && (*servings < 1 || *servings > maxServings) {
+		issues = append(issues, Issue{Kind: KindServings, Detail: fmt.Sprintf("serves %d", *servings)})
+	}
+	for _, ing := range rec.Ingredients() {
+		if reason, ok := checkAmount(ing); ok {
+			issues = append(issues, Issue{Kind: KindAmount, Detail: ing.String() + ": " + reason})
+		}
+	}
+	return issues
+}
+
+// Fix returns a copy of the recipe with its implausible claims corrected: the time
+// is taken from the steps, or dropped when the steps have none, implausible servings
+// are dropped, and amounts are read in the smaller unit when that makes them
+// plausible (500 kg of flour becomes 500 g), or replaced with "as needed"
+func Fix(rec *recipe.Recipe) *recipe.Recipe {
+	snap := rec.Snapshot()
+
+	if _, ok := checkTime(rec); ok {
+		snap.PrepTime, snap.CookTime = nil, nil
+		if steps, _ := stepTime(rec); steps > 0 {
+			prep := rec.PrepTime()
+			if prep != nil && *prep < steps {
+				p := *prep
+				snap.PrepTime = &p
+				steps -= p
+			}
+			snap.CookTime = &steps
+		}
+	}
+
+	if servings := rec.Servings(); servings != nil && (*servings < 1 || *servings > maxServings) {
+		snap.Servings = nil
+	}
+
+	for i, ing := range snap.Ingredients {
+		if _, ok := checkAmount(ing); ok {
+			snap.Ingredients[i] = fixAmount(ing)
+		}
+	}
+
+	fixed := rec.Clone()
+	fixed.ApplySnapshot(snap)
+	return fixed
+}
+
+// checkTime compares the stated time of a recipe with what its timed steps add up to
+func checkTime(rec *recipe.Recipe) (Issue, bool) {
+	var stated time.Duration
+	for _, d := range []*time.Duration{rec.PrepTime(), rec.CookTime()} {
+		if d != nil {
+			stated += *d
+		}
+	}
+	if stated <= 0 {
+		return Issue{}, false
+	}
+
+	if stated > maxStatedTime {
+		return Issue{Kind: KindTime, Detail: "takes " + formatDuration(stated)}, true
+	}
+
+	steps, allTimed := stepTime(rec)
+	switch {
+	case steps <= 0:
+		return Issue{}, false
+	case steps > 2*stated && steps-stated > 30*time.Minute:
+		return Issue{Kind: KindTime, Detail: fmt.Sprintf("takes %s, but its steps add up to %s",
+			formatDuration(stated), formatDuration(steps))}, true
+	case allTimed && stated > 4*steps && stated-steps > 3*time.Hour:
+		return Issue{Kind: KindTime, Detail: fmt.Sprintf("takes %s, but its steps add up to only %s",
+			formatDuration(stated), formatDuration(steps))}, true
+	}
+	return Issue{}, false
+}
+
+// stepTime adds up the durations of the steps, reporting whether every step has one
+func stepTime(rec *recipe.Recipe) (time.Duration, bool) {
+	var total time.Duration
+	allTimed := true
+	for _, inst := range rec.Instructions() {
+		if d := inst.Duration(); d != nil {
+			total += *d
+		} else {
+			allTimed = false
+		}
+	}
+	return total, allTimed
+}
+
+// checkAmount reports why the amount of an ingredient makes no sense, if it does not
+func checkAmount(ing recipe.Ingredient) (string, bool) {
+	if v, err := strconv.ParseFloat(strings.ReplaceAll(ing.Quantity(), ",", "."), 64); err == nil && v <= 0 {
+		return "amount of zero", true
+	}
+
+	amount, ok := shopping.ParseAmount(ing.Quantity(), ing.Unit())
+	if !ok {
+		return "", false
+	}
+	limit, known := maxAmounts[amount.Unit]
+	if !known || amount.Value <= limit {
+		return "", false
+	}
+	return "far too much", true
+}
+
+// fixAmount reads an implausible amount in the smaller unit of the same kind,
+// or replaces it when that does not help
+func fixAmount(ing recipe.Ingredient) recipe.Ingredient {
+	if amount, ok := shopping.ParseAmount(ing.Quantity(), ing.Unit()); ok && amount.Unit != "" {
+		smaller, err := recipe.NewIngredient(ing.Name(), ing.Quantity(), amount.Unit, ing.Notes())
+		if err == nil && amount.Unit != strings.ToLower(ing.Unit()) {
+			if _, wrong := checkAmount(smaller); !wrong {
+				return smaller
+			}
+		}
+	}
+
+	fixed, err := recipe.NewIngredient(ing.Name(), placeholderQuantity, "", ing.Notes())
+	if err != nil {
+		return ing
+	}
+	return fixed
+}
+
+// formatDuration formats a duration as "45 min", "3h" or "2h 30 min"
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%d min", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dh %d min", minutes/60, minutes%60)
+	}
+}
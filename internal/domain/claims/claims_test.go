@@ -0,0 +1,150 @@
+package claims
+
+import (
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+func minutes(m int) *time.Duration {
+	d := time.Duration(m) * time.Minute
+	return &d
+}
+
+func newRecipe(t *testing.T, ingredients [][3]string, stepMinutes ...int) *recipe.Recipe {
+	t.Helper()
+	var ings []recipe.Ingredient
+	for _, i := range ingredients {
+		ing, err := recipe.NewIngredient(i[0], i[1], i[2], "")
+		if err != nil {
+			t.Fatalf("NewIngredient() error = %v", err)
+		}
+		ings = append(ings, ing)
+	}
+	var insts []recipe.Instruction
+	for n, m := range stepMinutes {
+		var d *time.Duration
+		if m > 0 {
+			d = minutes(m)
+		}
+		inst, _ := recipe.NewInstruction(n+1, "Step", d)
+		insts = append(insts, inst)
+	}
+	source, _ := recipe.NewSource("https://example.com/stew", recipe.PlatformWeb, "Chef")
+	rec, err := recipe.NewRecipe(shared.NewID(), "Stew", ings, insts, source, "", "")
+	if err != nil {
+		t.Fatalf("NewRecipe() error = %v", err)
+	}
+	return rec
+}
+
+func kinds(issues []Issue) map[Kind]int {
+	counts := make(map[Kind]int)
+	for _, issue := range issues {
+		counts[issue.Kind]++
+	}
+	return counts
+}
+
+func TestCheck_Plausible(t *testing.T) {
+	rec := newRecipe(t, [][3]string{{"beef", "1.5", "kg"}, {"salt", "1", "tsp"}, {"onions", "2", ""}}, 10, 0, 90)
+	rec.SetPrepTime(15 * time.Minute)
+	rec.SetCookTime(90 * time.Minute)
+	rec.SetServings(6)
+
+	if issues := Check(rec); len(issues) != 0 {
+		t.Errorf("Check() = %v, want no issues", issues)
+	}
+}
+
+func TestCheck_Time(t *testing.T) {
+	tests := []struct {
+		name       string
+		prep, cook int
+		steps      []int
+		want       bool
+	}{
+		{"steps far longer than stated", 5, 10, []int{10, 180}, true},
+		{"steps a bit longer than stated", 10, 20, []int{25, 20}, false},
+		{"stated far longer than fully timed steps", 0, 600, []int{10, 20}, true},
+		{"stated longer than partly timed steps", 0, 600, []int{10, 0}, false}, // an untimed step may be the long one
+		{"more than two days", 0, 3 * 24 * 60, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := tt.steps
+			if steps == nil {
+				steps = []int{0}
+			}
+			rec := newRecipe(t, [][3]string{{"beef", "1", "kg"}}, steps...)
+			if tt.prep > 0 {
+				rec.SetPrepTime(time.Duration(tt.prep) * time.Minute)
+			}
+			rec.SetCookTime(time.Duration(tt.cook) * time.Minute)
+
+			if got := kinds(Check(rec))[KindTime] > 0; got != tt.want {
+				t.Errorf("time issue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck_ServingsAndAmounts(t *testing.T) {
+	rec := newRecipe(t, [][3]string{
+		{"beef", "1200", "kg"},
+		{"flour", "300", "cups"},
+		{"sugar", "0", "g"},
+		{"salt", "a pinch", ""},
+		{"water", "2", "l"},
+	}, 0)
+	rec.SetServings(400)
+
+	got := kinds(Check(rec))
+	if got[KindServings] != 1 {
+		t.Errorf("servings issues = %d, want 1", got[KindServings])
+	}
+	if got[KindAmount] != 3 {
+		t.Errorf("amount issues = %d, want 3 (beef, flour, sugar)", got[KindAmount])
+	}
+}
+
+func TestFix(t *testing.T) {
+	rec := newRecipe(t, [][3]string{{"beef", "1200", "kg"}, {"flour", "300", "cups"}, {"water", "2", "l"}}, 10, 170)
+	rec.SetPrepTime(20 * time.Minute)
+	rec.SetCookTime(10 * time.Minute)
+	rec.SetServings(0)
+
+	fixed := Fix(rec)
+
+	if issues := Check(fixed); len(issues) != 0 {
+		t.Errorf("Check(Fix()) = %v, want no issues", issues)
+	}
+	if fixed.ID() != rec.ID() {
+		t.Error("Fix() changed the recipe ID")
+	}
+	if p, c := fixed.PrepTime(), fixed.CookTime(); p == nil || *p != 20*time.Minute || c == nil || *c != 160*time.Minute {
+		t.Errorf("times = %v + %v, want the steps' 3h split into 20m prep and 2h40m cook", p, c)
+	}
+	if fixed.Servings() != nil {
+		t.Errorf("servings = %d, want dropped", *fixed.Servings())
+	}
+
+	ings := fixed.Ingredients()
+	if ings[0].Quantity() != "1200" || ings[0].Unit() != "g" {
+		t.Errorf("beef = %s, want read as 1200 g", ings[0])
+	}
+	if ings[1].Quantity() != placeholderQuantity {
+		t.Errorf("flour = %s, want %q", ings[1], placeholderQuantity)
+	}
+	if ings[2].String() != "2 l water" {
+		t.Errorf("water = %s, want unchanged", ings[2])
+	}
+
+	// The original is left alone
+	if rec.Ingredients()[0].Unit() != "kg" || *rec.Servings() != 0 {
+		t.Error("Fix() modified the original recipe")
+	}
+}
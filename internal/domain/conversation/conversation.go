@@ -0,0 +1,58 @@
+// Package conversation keeps what users and the bot said to each other in
+// natural language, so users can download it with /transcript.
+package conversation
+
+import (
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// Retention is how long turns are kept before they are deleted
+const Retention = 30 * 24 * time.Hour
+
+// Role is who said something
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// IsValid checks if the role is valid
+func (r Role) IsValid() bool {
+	return r == RoleUser || r == RoleAssistant
+}
+
+// Turn is one message in a conversation. Turns are never changed once recorded.
+type Turn struct {
+	ID        shared.ID
+	UserID    UserID
+	Role      Role
+	Text      string
+	CreatedAt time.Time
+}
+
+// NewTurn creates a turn said at now
+func NewTurn(userID UserID, role Role, text string, now time.Time) (*Turn, error) {
+	if userID.IsEmpty() || !role.IsValid() || strings.TrimSpace(text) == "" {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &Turn{
+		ID:        shared.NewID(),
+		UserID:    userID,
+		Role:      role,
+		Text:      text,
+		CreatedAt: now,
+	}, nil
+}
+
+// Expired reports whether the turn is past the retention period at now
+func (t *Turn) Expired(now time.Time) bool {
+	return now.Sub(t.CreatedAt) > Retention
+}
@@ -0,0 +1,49 @@
+package conversation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestNewTurn(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	turn, err := NewTurn(shared.NewID(), RoleUser, "something with chicken", now)
+	if err != nil {
+		t.Fatalf("NewTurn() error = %v", err)
+	}
+	if turn.ID.IsEmpty() || turn.Text != "something with chicken" || !turn.CreatedAt.Equal(now) {
+		t.Errorf("NewTurn() = %+v", turn)
+	}
+
+	tests := []struct {
+		name   string
+		userID shared.ID
+		role   Role
+		text   string
+	}{
+		{"no user", "", RoleUser, "hi"},
+		{"unknown role", shared.NewID(), "system", "hi"},
+		{"blank text", shared.NewID(), RoleAssistant, "  "},
+	}
+	for _, tt := range tests {
+		if _, err := NewTurn(tt.userID, tt.role, tt.text, now); !errors.Is(err, shared.ErrInvalidInput) {
+			t.Errorf("NewTurn() with %s error = %v, want ErrInvalidInput", tt.name, err)
+		}
+	}
+}
+
+func TestTurn_Expired(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	turn, _ := NewTurn(shared.NewID(), RoleAssistant, "Which one?", now)
+
+	if turn.Expired(now.Add(Retention)) {
+		t.Error("Expired() at the end of retention = true, want false")
+	}
+	if !turn.Expired(now.Add(Retention + time.Hour)) {
+		t.Error("Expired() after retention = false, want true")
+	}
+}
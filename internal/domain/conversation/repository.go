@@ -0,0 +1,20 @@
+package conversation
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for conversation persistence (Port).
+// Turns can only be appended and, once past retention, deleted.
+type Repository interface {
+	// Append stores a new turn
+	Append(ctx context.Context, t *Turn) error
+
+	// FindByUser retrieves the turns of a user said since the given time, newest first
+	FindByUser(ctx context.Context, userID UserID, since time.Time) ([]*Turn, error)
+
+	// DeleteBefore removes the turns of a user said before the cutoff
+	// and returns how many were removed
+	DeleteBefore(ctx context.Context, userID UserID, cutoff time.Time) (int, error)
+}
@@ -0,0 +1,144 @@
+// Package cooking schedules the steps of several recipes cooked together.
+package cooking
+
+import (
+	"sort"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Recipes that can be cooked together in one schedule
+const (
+	MinRecipes = 2
+	MaxRecipes = 4
+)
+
+// MinStepDuration is the time given to a step whose duration can't be worked out
+const MinStepDuration = 5 * time.Minute
+
+// Task is one step of a recipe
+type Task struct {
+	Text     string
+	Duration time.Duration // 0 if the recipe doesn't say
+}
+
+// Plan is a recipe to schedule
+type Plan struct {
+	Title     string
+	Tasks     []Task
+	TotalTime time.Duration // prep and cook time of the recipe, 0 if unknown
+}
+
+// Step is a recipe step placed on the schedule
+type Step struct {
+	At       time.Time
+	Duration time.Duration
+	Recipe   string // empty for the serving step
+	Number   int    // step number within its recipe
+	Text     string
+	Serve    bool // the final step, when everything is served
+}
+
+// End returns when the step is done
+func (s Step) End() time.Time {
+	return s.At.Add(s.Duration)
+}
+
+// Schedule is the interleaved steps of several recipes, all finishing when the food is served
+type Schedule struct {
+	serveAt time.Time
+	steps   []Step
+}
+
+// NewSchedule works backward from serveAt so that every recipe finishes on time.
+// Steps without a duration share the time the recipe has left over, at least MinStepDuration each.
+func NewSchedule(plans []Plan, serveAt time.Time) (*Schedule, error) {
+	if len(plans) < MinRecipes || len(plans) > MaxRecipes {
+		return nil, shared.ErrInvalidInput
+	}
+
+	var steps []Step
+	for _, plan := range plans {
+		if len(plan.Tasks) == 0 {
+			return nil, shared.ErrInvalidInput
+		}
+
+		durations := stepDurations(plan)
+		end := serveAt
+		recipeSteps := make([]Step, len(plan.Tasks))
+		for i := len(plan.Tasks) - 1; i >= 0; i-- {
+			at := end.Add(-durations[i])
+			recipeSteps[i] = Step{At: at, Duration: durations[i], Recipe: plan.Title, Number: i + 1, Text: plan.Tasks[i].Text}
+			end = at
+		}
+		steps = append(steps, recipeSteps...)
+	}
+
+	// Interleave by start time, keeping recipe order for steps starting together
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].At.Before(steps[j].At)
+	})
+	steps = append(steps, Step{At: serveAt, Serve: true})
+
+	return &Schedule{serveAt: serveAt, steps: steps}, nil
+}
+
+// stepDurations returns how long each task takes, spreading the recipe's unaccounted
+// time over the tasks that don't say
+func stepDurations(plan Plan) []time.Duration {
+	durations := make([]time.Duration, len(plan.Tasks))
+
+	var known time.Duration
+	unknown := 0
+	for i, task := range plan.Tasks {
+		durations[i] = task.Duration
+		if task.Duration > 0 {
+			known += task.Duration
+		} else {
+			unknown++
+		}
+	}
+	if unknown == 0 {
+		return durations
+	}
+
+	share := MinStepDuration
+	if left := plan.TotalTime - known; left > 0 {
+		if each := (left / time.Duration(unknown)).Round(time.Minute); each > share {
+			share = each
+		}
+	}
+	for i := range durations {
+		if durations[i] <= 0 {
+			durations[i] = share
+		}
+	}
+	return durations
+}
+
+// ServeAt returns when the food is served
+func (s *Schedule) ServeAt() time.Time {
+	return s.serveAt
+}
+
+// Start returns when the first step begins
+func (s *Schedule) Start() time.Time {
+	return s.steps[0].At
+}
+
+// Steps returns all steps in order, ending with the serving step
+func (s *Schedule) Steps() []Step {
+	return s.steps
+}
+
+// Upcoming returns the steps that haven't started by now
+func (s *Schedule) Upcoming(now time.Time) []Step {
+	var upcoming []Step
+	for _, step := range s.steps {
+		if !step.At.Before(now) {
+			upcoming = append(upcoming, step)
+		}
+	}
+	return upcoming
+}
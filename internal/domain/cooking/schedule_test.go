@@ -0,0 +1,77 @@
+package cooking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSchedule_WorksBackwardFromServing(t *testing.T) {
+	serveAt := time.Date(2026, 10, 16, 19, 30, 0, 0, time.UTC)
+
+	pasta := Plan{
+		Title: "Pasta",
+		Tasks: []Task{
+			{Text: "Boil the pasta", Duration: 10 * time.Minute},
+			{Text: "Toss with the sauce"},
+		},
+	}
+	stew := Plan{
+		Title:     "Stew",
+		TotalTime: 90 * time.Minute,
+		Tasks: []Task{
+			{Text: "Chop the vegetables"},
+			{Text: "Brown the meat"},
+			{Text: "Simmer", Duration: time.Hour},
+		},
+	}
+
+	schedule, err := NewSchedule([]Plan{pasta, stew}, serveAt)
+	if err != nil {
+		t.Fatalf("NewSchedule() error = %v", err)
+	}
+
+	want := []struct {
+		at     string
+		recipe string
+		number int
+	}{
+		{"18:00", "Stew", 1},
+		{"18:15", "Stew", 2},
+		{"18:30", "Stew", 3},
+		{"19:15", "Pasta", 1},
+		{"19:25", "Pasta", 2},
+		{"19:30", "", 0},
+	}
+
+	steps := schedule.Steps()
+	if len(steps) != len(want) {
+		t.Fatalf("Steps() = %+v, want %d steps", steps, len(want))
+	}
+	for i, w := range want {
+		got := steps[i]
+		if got.At.Format("15:04") != w.at || got.Recipe != w.recipe || got.Number != w.number {
+			t.Errorf("step %d = %s %s #%d, want %s %s #%d", i, got.At.Format("15:04"), got.Recipe, got.Number, w.at, w.recipe, w.number)
+		}
+	}
+
+	if !steps[len(steps)-1].Serve {
+		t.Error("last step is not the serving step")
+	}
+	if got := schedule.Start().Format("15:04"); got != "18:00" {
+		t.Errorf("Start() = %s, want 18:00", got)
+	}
+	if got := len(schedule.Upcoming(serveAt.Add(-15 * time.Minute))); got != 3 {
+		t.Errorf("Upcoming(19:15) = %d steps, want 3", got)
+	}
+}
+
+func TestNewSchedule_RecipeCount(t *testing.T) {
+	plan := Plan{Title: "Toast", Tasks: []Task{{Text: "Toast the bread"}}}
+
+	if _, err := NewSchedule([]Plan{plan}, time.Now()); err == nil {
+		t.Error("NewSchedule() with one recipe succeeded, want error")
+	}
+	if _, err := NewSchedule([]Plan{plan, plan, plan, plan, plan}, time.Now()); err == nil {
+		t.Error("NewSchedule() with five recipes succeeded, want error")
+	}
+}
@@ -0,0 +1,176 @@
+package cooking
+
+import (
+	"strconv"
+	"strings"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Cook is a member of a chat cooking together
+type Cook struct {
+	ID   shared.ID
+	Name string
+}
+
+// SessionStep is a recipe step cooked together
+type SessionStep struct {
+	Number    int
+	Text      string
+	ClaimedBy *Cook // nil while nobody has taken the step
+	Done      bool
+}
+
+// Session is a recipe cooked together in a group chat: everyone sees the steps,
+// members claim the ones they do and mark them done
+type Session struct {
+	Recipe    string
+	StartedBy Cook
+	steps     []SessionStep
+}
+
+// NewSession starts cooking the steps of a recipe together
+func NewSession(recipe string, startedBy Cook, steps []string) (*Session, error) {
+	if len(steps) == 0 {
+		return nil, shared.ErrInvalidInput
+	}
+
+	s := &Session{Recipe: recipe, StartedBy: startedBy}
+	for i, text := range steps {
+		s.steps = append(s.steps, SessionStep{Number: i + 1, Text: text})
+	}
+	return s, nil
+}
+
+// Steps returns the steps in order
+func (s *Session) Steps() []SessionStep {
+	return s.steps
+}
+
+// Clone returns a copy of the session that changes to it do not affect
+func (s *Session) Clone() *Session {
+	clone := *s
+	clone.steps = append([]SessionStep(nil), s.steps...)
+	return &clone
+}
+
+// Claim gives a step to a cook. Claiming a step you already have gives it back.
+// Returns shared.ErrStepClaimed if another cook has it and shared.ErrStepDone if it is done.
+func (s *Session) Claim(number int, cook Cook) error {
+	step, err := s.step(number)
+	if err != nil {
+		return err
+	}
+	switch {
+	case step.Done:
+		return shared.ErrStepDone
+	case step.ClaimedBy == nil:
+		step.ClaimedBy = &cook
+	case step.ClaimedBy.ID == cook.ID:
+		step.ClaimedBy = nil
+	default:
+		return shared.ErrStepClaimed
+	}
+	return nil
+}
+
+// Complete marks a step done, crediting the cook who claimed it or, when nobody
+// did, the cook completing it. Returns shared.ErrStepDone if it already is.
+func (s *Session) Complete(number int, cook Cook) error {
+	step, err := s.step(number)
+	if err != nil {
+		return err
+	}
+	if step.Done {
+		return shared.ErrStepDone
+	}
+	if step.ClaimedBy == nil {
+		step.ClaimedBy = &cook
+	}
+	step.Done = true
+	return nil
+}
+
+// Progress returns how many steps are done out of all of them
+func (s *Session) Progress() (done, total int) {
+	for _, step := range s.steps {
+		if step.Done {
+			done++
+		}
+	}
+	return done, len(s.steps)
+}
+
+// Finished reports whether every step is done
+func (s *Session) Finished() bool {
+	done, total := s.Progress()
+	return done == total
+}
+
+// FindStep finds the step a member means, by its number or by words of its text:
+// "3", or "I'll chop the onions" for "Chop the onions and garlic". Only steps not
+// done yet are matched by words; the step sharing the most words wins.
+func (s *Session) FindStep(phrase string) (int, bool) {
+	phrase = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(phrase), "#"))
+	if number, err := strconv.Atoi(phrase); err == nil {
+		_, err := s.step(number)
+		return number, err == nil
+	}
+
+	words := significantWords(phrase)
+	best, bestScore := 0, 0
+	for _, step := range s.steps {
+		if step.Done {
+			continue
+		}
+		score := 0
+		stepWords := significantWords(step.Text)
+		for _, word := range words {
+			for _, stepWord := range stepWords {
+				if sameWord(word, stepWord) {
+					score++
+					break
+				}
+			}
+		}
+		if score > bestScore {
+			best, bestScore = step.Number, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// step returns the step with the given number, or shared.ErrNoSuchStep
+func (s *Session) step(number int) (*SessionStep, error) {
+	if number < 1 || number > len(s.steps) {
+		return nil, shared.ErrNoSuchStep
+	}
+	return &s.steps[number-1], nil
+}
+
+// fillerWords say who does a step rather than what it is
+var fillerWords = map[string]bool{
+	"i'll": true, "ill": true, "will": true, "can": true, "the": true, "and": true, "take": true, "do": true,
+	"eu": true, "vou": true, "faço": true, "deixa": true, "comigo": true, "que": true, "com": true,
+}
+
+// significantWords splits text into lowercase words, leaving out short and filler words
+func significantWords(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r == '\'' || r >= 'a' && r <= 'z' || r > 127)
+	}) {
+		if len([]rune(word)) >= 3 && !fillerWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// sameWord reports whether two words are the same up to a plural ending
+func sameWord(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return a == b || strings.HasPrefix(b, a) && len(b)-len(a) <= 2
+}
@@ -0,0 +1,92 @@
+package cooking
+
+import (
+	"errors"
+	"testing"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func newTestSession(t *testing.T) *Session {
+	t.Helper()
+	s, err := NewSession("Curry", Cook{ID: "ana", Name: "Ana"}, []string{
+		"Chop the onions and garlic",
+		"Fry the onions until golden",
+		"Add the chickpeas and coconut milk",
+	})
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	return s
+}
+
+func TestSession_ClaimAndComplete(t *testing.T) {
+	s := newTestSession(t)
+	ana, bruno := Cook{ID: "ana", Name: "Ana"}, Cook{ID: "bruno", Name: "Bruno"}
+
+	if err := s.Claim(1, bruno); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	if err := s.Claim(1, ana); !errors.Is(err, shared.ErrStepClaimed) {
+		t.Errorf("Claim() of a taken step error = %v, want ErrStepClaimed", err)
+	}
+
+	// Claiming again gives the step back
+	if err := s.Claim(1, bruno); err != nil || s.Steps()[0].ClaimedBy != nil {
+		t.Errorf("second Claim() = %v, claimed by %v, want the step given back", err, s.Steps()[0].ClaimedBy)
+	}
+
+	// Completing an unclaimed step credits whoever did it
+	if err := s.Complete(2, ana); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if step := s.Steps()[1]; !step.Done || step.ClaimedBy == nil || step.ClaimedBy.Name != "Ana" {
+		t.Errorf("step 2 = %+v, want done by Ana", step)
+	}
+	if err := s.Complete(2, bruno); !errors.Is(err, shared.ErrStepDone) {
+		t.Errorf("Complete() of a done step error = %v, want ErrStepDone", err)
+	}
+	if err := s.Claim(4, ana); !errors.Is(err, shared.ErrNoSuchStep) {
+		t.Errorf("Claim(4) error = %v, want ErrNoSuchStep", err)
+	}
+
+	if done, total := s.Progress(); done != 1 || total != 3 || s.Finished() {
+		t.Errorf("Progress() = %d/%d, finished %v, want 1/3", done, total, s.Finished())
+	}
+	_ = s.Complete(1, bruno)
+	_ = s.Complete(3, bruno)
+	if !s.Finished() {
+		t.Error("Finished() = false with every step done")
+	}
+}
+
+func TestSession_FindStep(t *testing.T) {
+	s := newTestSession(t)
+
+	tests := []struct {
+		phrase string
+		want   int
+		found  bool
+	}{
+		{"2", 2, true},
+		{"#3", 3, true},
+		{"7", 0, false},
+		{"I'll chop the onions", 1, true},
+		{"eu vou fritar", 0, false},
+		{"I'll do the chickpeas", 3, true},
+		{"fry onion", 2, true},
+	}
+
+	for _, tt := range tests {
+		got, found := s.FindStep(tt.phrase)
+		if found != tt.found || (found && got != tt.want) {
+			t.Errorf("FindStep(%q) = %d, %v, want %d, %v", tt.phrase, got, found, tt.want, tt.found)
+		}
+	}
+
+	// Steps already done are not matched by words
+	_ = s.Complete(1, Cook{ID: "ana"})
+	if got, _ := s.FindStep("onions"); got != 2 {
+		t.Errorf("FindStep(onions) = %d after step 1 is done, want 2", got)
+	}
+}
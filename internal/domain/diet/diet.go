@@ -0,0 +1,364 @@
+// Package diet checks a recipe's ingredients against the rules of a diet the user
+// keeps, rather than trusting the dietary tags extraction guessed for the recipe.
+// Ingredients a rule forbids are violations; ingredients that break the rule only
+// in some forms, like gelatin or wine, are uncertain and left to the user.
+package diet
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Diet is a set of rules on what a recipe may contain
+type Diet string
+
+const (
+	Vegetarian Diet = "vegetarian"
+	Vegan      Diet = "vegan"
+	Kosher     Diet = "kosher"
+	Halal      Diet = "halal"
+)
+
+// All returns the diets with built-in rules
+func All() []Diet {
+	return []Diet{Vegetarian, Vegan, Kosher, Halal}
+}
+
+// Parse parses a diet name, accepting aliases and Portuguese names
+func Parse(s string) (Diet, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "vegetarian", "veggie", "vegetariano", "vegetariana":
+		return Vegetarian, true
+	case "vegan", "plant-based", "plant based", "vegano", "vegana":
+		return Vegan, true
+	case "kosher", "kosher-style", "kosher style", "kasher":
+		return Kosher, true
+	case "halal", "halal-style", "halal style":
+		return Halal, true
+	default:
+		return "", false
+	}
+}
+
+// Rules are what a diet rules out. Terms are ingredient names or, prefixed with
+// "@", groups of them: @pork, @meat, @poultry, @fish, @shellfish, @dairy, @egg,
+// @honey, @gelatin, @alcohol, @rennet cheese and @stock.
+type Rules struct {
+	Forbidden []string    // terms the diet never allows
+	Uncertain []string    // terms the diet allows only in some forms
+	Apart     [][2]string // pairs of terms the diet does not allow in the same recipe
+}
+
+// groups are the ingredients terms like "@meat" stand for, in English and Portuguese
+var groups = map[string][]string{
+	"pork": {"pork", "bacon", "ham", "prosciutto", "pancetta", "lard", "chorizo", "salami", "pepperoni",
+		"guanciale", "porco", "presunto", "toucinho", "linguiça", "linguica", "banha"},
+	"meat": {"beef", "steak", "veal", "lamb", "mutton", "goat", "venison", "mince", "ground meat", "sausage",
+		"meatball", "oxtail", "brisket", "carne", "bife", "carne moída", "salsicha", "cordeiro", "picanha"},
+	"poultry": {"chicken", "turkey", "duck", "goose", "quail", "frango", "galinha", "peru", "pato"},
+	"fish": {"fish", "salmon", "tuna", "cod", "anchovy", "sardine", "trout", "tilapia", "mackerel",
+		"fish sauce", "peixe", "salmão", "atum", "bacalhau", "sardinha"},
+	"shellfish": {"shrimp", "prawn", "crab", "lobster", "clam", "mussel", "oyster", "scallop", "squid",
+		"octopus", "calamari", "camarão", "camarao", "caranguejo", "lagosta", "lula", "polvo", "marisco"},
+	"dairy": {"milk", "butter", "cream", "cheese", "yogurt", "yoghurt", "ghee", "whey", "parmesan",
+		"mozzarella", "ricotta", "mascarpone", "buttermilk", "leite", "manteiga", "queijo", "creme de leite",
+		"iogurte", "requeijão", "requeijao", "nata"},
+	"egg":     {"egg", "mayonnaise", "mayo", "meringue", "ovo", "maionese"},
+	"honey":   {"honey", "mel"},
+	"gelatin": {"gelatin", "gelatine", "gelatina"},
+	"alcohol": {"wine", "beer", "rum", "vodka", "whisky", "whiskey", "brandy", "cognac", "sake", "mirin",
+		"liqueur", "sherry", "vinho", "cerveja", "cachaça", "cachaca"},
+	"rennet cheese": {"parmesan", "parmigiano", "pecorino", "gruyere", "gruyère", "grana padano"},
+	"stock":         {"stock", "broth", "bouillon", "caldo"},
+}
+
+// plantBased are words that make an ingredient the plant version of an animal one,
+// like "coconut milk" or "vegan butter", or no animal product at all, like "cream
+// of tartar"
+var plantBased = []string{"vegan", "plant", "coconut", "almond", "soy", "oat", "rice", "cashew", "peanut",
+	"cocoa", "vegetable", "veggie", "mushroom", "meatless", "dairy-free", "tartar", "vegetal", "coco", "amêndoa"}
+
+// builtIn are the rules of the diets All returns. Kosher- and halal-style rules only
+// cover what the ingredients tell: how meat was slaughtered or certified is always
+// for the user to check.
+var builtIn = map[Diet]Rules{
+	Vegetarian: {
+		Forbidden: []string{"@meat", "@pork", "@poultry", "@fish", "@shellfish", "@gelatin"},
+		Uncertain: []string{"@rennet cheese", "@stock", "worcestershire"},
+	},
+	Vegan: {
+		Forbidden: []string{"@meat", "@pork", "@poultry", "@fish", "@shellfish", "@gelatin", "@dairy", "@egg", "@honey"},
+		Uncertain: []string{"@stock", "worcestershire", "@alcohol", "sugar"},
+	},
+	Kosher: {
+		Forbidden: []string{"@pork", "@shellfish"},
+		Uncertain: []string{"@gelatin", "@alcohol", "@rennet cheese"},
+		Apart:     [][2]string{{"@meat", "@dairy"}, {"@poultry", "@dairy"}},
+	},
+	Halal: {
+		Forbidden: []string{"@pork", "@alcohol"},
+		Uncertain: []string{"@meat", "@poultry", "@gelatin", "vanilla extract"},
+	},
+}
+
+// Finding is an ingredient that breaks, or may break, a diet's rules
+type Finding struct {
+	Ingredient string
+	Reason     string // e.g. "pork", or "meat with dairy"
+}
+
+// Report is what checking a recipe against a diet found
+type Report struct {
+	Diet       Diet
+	Violations []Finding
+	Uncertain  []Finding
+}
+
+// Compliant reports whether nothing in the recipe breaks the diet for sure
+func (r Report) Compliant() bool {
+	return len(r.Violations) == 0
+}
+
+// Clear reports whether nothing in the recipe breaks the diet or might
+func (r Report) Clear() bool {
+	return len(r.Violations) == 0 && len(r.Uncertain) == 0
+}
+
+// Checker checks ingredients against the rules of diets
+type Checker struct {
+	rules map[Diet]Rules
+}
+
+// NewChecker creates a checker with the built-in rules
+func NewChecker() *Checker {
+	rules := make(map[Diet]Rules, len(builtIn))
+	for d, r := range builtIn {
+		rules[d] = r
+	}
+	return &Checker{rules: rules}
+}
+
+// Configure replaces the rules of the given diets, adding diets that have no
+// built-in rules, such as rules read by ParseRules
+func (c *Checker) Configure(rules map[Diet]Rules) {
+	for d, r := range rules {
+		c.rules[d] = r
+	}
+}
+
+// Diets returns the diets the checker has rules for, sorted
+func (c *Checker) Diets() []Diet {
+	diets := make([]Diet, 0, len(c.rules))
+	for d := range c.rules {
+		diets = append(diets, d)
+	}
+	sort.Slice(diets, func(i, j int) bool { return diets[i] < diets[j] })
+	return diets
+}
+
+// Has reports whether the checker has rules for a diet
+func (c *Checker) Has(d Diet) bool {
+	_, ok := c.rules[d]
+	return ok
+}
+
+// Check checks the names of a recipe's ingredients against a diet's rules. An
+// ingredient is reported once, as a violation if any rule forbids it.
+func (c *Checker) Check(d Diet, ingredients []string) Report {
+	report := Report{Diet: d}
+	rules, ok := c.rules[d]
+	if !ok {
+		return report
+	}
+
+	reported := make(map[int]bool)
+	for i, name := range ingredients {
+		if reason, ok := matchAny(name, rules.Forbidden); ok {
+			report.Violations = append(report.Violations, Finding{Ingredient: name, Reason: reason})
+			reported[i] = true
+		}
+	}
+
+	for _, pair := range rules.Apart {
+		first, second := matching(ingredients, pair[0]), matching(ingredients, pair[1])
+		if len(first) == 0 || len(second) == 0 {
+			continue
+		}
+		reason := termName(pair[0]) + " with " + termName(pair[1])
+		for _, i := range append(first, second...) {
+			if !reported[i] {
+				report.Violations = append(report.Violations, Finding{Ingredient: ingredients[i], Reason: reason})
+				reported[i] = true
+			}
+		}
+	}
+
+	for i, name := range ingredients {
+		if reported[i] {
+			continue
+		}
+		if reason, ok := matchAny(name, rules.Uncertain); ok {
+			report.Uncertain = append(report.Uncertain, Finding{Ingredient: name, Reason: reason})
+		}
+	}
+	return report
+}
+
+// matching returns the positions of the ingredients a term matches
+func matching(ingredients []string, term string) []int {
+	var positions []int
+	for i, name := range ingredients {
+		if _, ok := matchAny(name, []string{term}); ok {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// matchAny returns the first of the terms an ingredient name matches, named for the user
+func matchAny(name string, terms []string) (string, bool) {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+	for i, word := range words {
+		words[i] = singular(word)
+	}
+
+	for _, term := range terms {
+		names := []string{term}
+		if group, ok := strings.CutPrefix(term, "@"); ok {
+			names = groups[group]
+			// The plant version of an animal ingredient is none of them
+			if isAnimal(group) && containsAny(words, plantBased) {
+				continue
+			}
+		}
+		for _, n := range names {
+			if containsPhrase(words, strings.Fields(strings.ToLower(n))) {
+				return termName(term), true
+			}
+		}
+	}
+	return "", false
+}
+
+// isAnimal reports whether a group is of animal ingredients, which have plant versions
+func isAnimal(group string) bool {
+	switch group {
+	case "alcohol", "rennet cheese":
+		return false
+	default:
+		return true
+	}
+}
+
+// termName names a term for the user: the group for "@group", the term itself otherwise
+func termName(term string) string {
+	return strings.TrimPrefix(term, "@")
+}
+
+// containsPhrase reports whether words, made singular, contain the phrase's words in a row
+func containsPhrase(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, p := range phrase {
+			if words[i+j] != singular(p) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAny reports whether words contain any of the candidates
+func containsAny(words, candidates []string) bool {
+	for _, w := range words {
+		for _, c := range candidates {
+			if w == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// singular strips the plural ending of an English word, e.g. "anchovies" or "eggs"
+func singular(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+// ParseRules reads diet rules, one rule per line: the diet, the kind of rule and
+// its terms after a colon. Kinds are forbidden, uncertain and apart, whose terms are
+// pairs joined by "+". Blank lines and lines starting with # are skipped.
+//
+//	kosher forbidden: @pork, @shellfish
+//	kosher apart: @meat + @dairy
+//	pescatarian forbidden: @meat, @pork, @poultry
+func ParseRules(r io.Reader) (map[Diet]Rules, error) {
+	parsed := make(map[Diet]Rules)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		head, body, found := strings.Cut(text, ":")
+		fields := strings.Fields(strings.ToLower(head))
+		if !found || len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want \"diet kind: terms\"", line)
+		}
+		d, kind := Diet(fields[0]), fields[1]
+		if parsedDiet, ok := Parse(fields[0]); ok {
+			d = parsedDiet
+		}
+
+		var terms []string
+		for _, term := range strings.FieldsFunc(body, func(r rune) bool { return r == ',' || r == '+' }) {
+			if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+				if group, ok := strings.CutPrefix(term, "@"); ok && groups[group] == nil {
+					return nil, fmt.Errorf("line %d: unknown group %q", line, term)
+				}
+				terms = append(terms, term)
+			}
+		}
+
+		rules := parsed[d]
+		switch kind {
+		case "forbidden":
+			rules.Forbidden = append(rules.Forbidden, terms...)
+		case "uncertain":
+			rules.Uncertain = append(rules.Uncertain, terms...)
+		case "apart":
+			for _, pair := range strings.Split(body, ",") {
+				first, second, ok := strings.Cut(strings.ToLower(pair), "+")
+				if !ok {
+					return nil, fmt.Errorf("line %d: want pairs like \"@meat + @dairy\"", line)
+				}
+				rules.Apart = append(rules.Apart, [2]string{strings.TrimSpace(first), strings.TrimSpace(second)})
+			}
+		default:
+			return nil, fmt.Errorf("line %d: unknown kind %q, want forbidden, uncertain or apart", line, kind)
+		}
+		parsed[d] = rules
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read diet rules: %w", err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,144 @@
+package diet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChecker_Check(t *testing.T) {
+	checker := NewChecker()
+
+	tests := []struct {
+		name          string
+		diet          Diet
+		ingredients   []string
+		wantViolating []string
+		wantUncertain []string
+	}{
+		{
+			name:          "vegan finds animal products",
+			diet:          Vegan,
+			ingredients:   []string{"spaghetti", "eggs", "parmesan cheese", "olive oil"},
+			wantViolating: []string{"eggs", "parmesan cheese"},
+		},
+		{
+			name:        "plant versions pass",
+			diet:        Vegan,
+			ingredients: []string{"coconut milk", "vegan butter", "eggplant", "cream of tartar", "peanut butter"},
+		},
+		{
+			name:          "vegan leaves unclear ingredients to the user",
+			diet:          Vegan,
+			ingredients:   []string{"vegetable stock", "chicken broth", "white wine"},
+			wantViolating: []string{"chicken broth"},
+			wantUncertain: []string{"white wine"},
+		},
+		{
+			name:          "vegetarian allows dairy",
+			diet:          Vegetarian,
+			ingredients:   []string{"butter", "anchovies", "worcestershire sauce"},
+			wantViolating: []string{"anchovies"},
+			wantUncertain: []string{"worcestershire sauce"},
+		},
+		{
+			name:          "kosher keeps meat and dairy apart",
+			diet:          Kosher,
+			ingredients:   []string{"ground beef", "cheddar cheese", "onion"},
+			wantViolating: []string{"ground beef", "cheddar cheese"},
+		},
+		{
+			name:        "kosher allows meat without dairy",
+			diet:        Kosher,
+			ingredients: []string{"chicken thighs", "olive oil"},
+		},
+		{
+			name:          "halal forbids pork and alcohol",
+			diet:          Halal,
+			ingredients:   []string{"bacon", "red wine", "chicken"},
+			wantViolating: []string{"bacon", "red wine"},
+			wantUncertain: []string{"chicken"},
+		},
+		{
+			name:          "portuguese names",
+			diet:          Vegan,
+			ingredients:   []string{"leite de coco", "queijo", "ovos"},
+			wantViolating: []string{"queijo", "ovos"},
+		},
+		{
+			name:        "unknown diet",
+			diet:        Diet("paleo"),
+			ingredients: []string{"sugar"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := checker.Check(tt.diet, tt.ingredients)
+			if got := ingredientsOf(report.Violations); !reflect.DeepEqual(got, tt.wantViolating) {
+				t.Errorf("violations = %v, want %v", got, tt.wantViolating)
+			}
+			if got := ingredientsOf(report.Uncertain); !reflect.DeepEqual(got, tt.wantUncertain) {
+				t.Errorf("uncertain = %v, want %v", got, tt.wantUncertain)
+			}
+			if report.Compliant() != (len(tt.wantViolating) == 0) {
+				t.Errorf("Compliant() = %v with violations %v", report.Compliant(), report.Violations)
+			}
+		})
+	}
+}
+
+func TestChecker_ApartReason(t *testing.T) {
+	report := NewChecker().Check(Kosher, []string{"chicken", "butter"})
+	for _, f := range report.Violations {
+		if f.Reason != "poultry with dairy" {
+			t.Errorf("%s reason = %q, want %q", f.Ingredient, f.Reason, "poultry with dairy")
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	input := `# house rules
+kosher-style forbidden: @pork, @shellfish, catfish
+kosher apart: @meat + @dairy, @poultry + @dairy
+
+pescatarian forbidden: @meat, @pork, @poultry
+`
+	rules, err := ParseRules(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	want := map[Diet]Rules{
+		Kosher: {
+			Forbidden: []string{"@pork", "@shellfish", "catfish"},
+			Apart:     [][2]string{{"@meat", "@dairy"}, {"@poultry", "@dairy"}},
+		},
+		"pescatarian": {Forbidden: []string{"@meat", "@pork", "@poultry"}},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("ParseRules() = %+v, want %+v", rules, want)
+	}
+
+	checker := NewChecker()
+	checker.Configure(rules)
+	if report := checker.Check("pescatarian", []string{"salmon", "beef stock"}); len(report.Violations) != 1 {
+		t.Errorf("pescatarian violations = %v, want only the beef stock", report.Violations)
+	}
+	if report := checker.Check(Kosher, []string{"catfish"}); report.Compliant() {
+		t.Error("expected configured kosher rules to forbid catfish")
+	}
+
+	for _, bad := range []string{"vegan: @meat", "vegan never: @meat", "vegan forbidden: @unicorn", "kosher apart: @meat"} {
+		if _, err := ParseRules(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseRules(%q) error = nil, want an error", bad)
+		}
+	}
+}
+
+func ingredientsOf(findings []Finding) []string {
+	var names []string
+	for _, f := range findings {
+		names = append(names, f.Ingredient)
+	}
+	return names
+}
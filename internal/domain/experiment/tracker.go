@@ -0,0 +1,127 @@
+package experiment
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+)
+
+// Control is the variant name of calls that use the built-in prompt and model
+const Control = "control"
+
+// Experiments run on LLM calls
+const (
+	Extraction = "extraction"
+	Intent     = "intent"
+)
+
+// Outcome is how an LLM call in an experiment ended
+type Outcome string
+
+const (
+	OutcomeSuccess    Outcome = "success"
+	OutcomeFailure    Outcome = "failure"     // the call failed or returned nothing usable
+	OutcomeParseError Outcome = "parse_error" // the answer was not the JSON asked for
+)
+
+// Stats counts the outcomes of one variant of an experiment
+type Stats struct {
+	Experiment  string
+	Variant     string
+	Calls       int
+	Failures    int
+	ParseErrors int
+}
+
+// FailureRate returns the share of calls that failed, parse errors excluded
+func (s Stats) FailureRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Calls)
+}
+
+// ParseErrorRate returns the share of calls whose answer could not be parsed
+func (s Stats) ParseErrorRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.ParseErrors) / float64(s.Calls)
+}
+
+// String returns a one-line summary of the stats
+func (s Stats) String() string {
+	return fmt.Sprintf("%s/%s: %d calls, %.1f%% failures, %.1f%% parse errors",
+		s.Experiment, s.Variant, s.Calls, 100*s.FailureRate(), 100*s.ParseErrorRate())
+}
+
+// Tracker assigns LLM calls to experiment variants and counts their outcomes.
+// Stats are kept in memory, so they cover the calls since the bot started.
+type Tracker struct {
+	mu    sync.Mutex
+	stats map[string]*Stats // "experiment/variant" -> stats
+	roll  func() int        // random number from 0 to 99
+}
+
+// NewTracker creates a new tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		stats: make(map[string]*Stats),
+		roll:  func() int { return rand.IntN(100) },
+	}
+}
+
+// Assign picks the variant of a call: variant for percent% of calls, Control for the rest
+func (t *Tracker) Assign(variant string, percent int) string {
+	if variant == "" || percent <= 0 {
+		return Control
+	}
+	if t.roll() < percent {
+		return variant
+	}
+	return Control
+}
+
+// Record counts the outcome of a call
+func (t *Tracker) Record(experiment, variant string, outcome Outcome) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := experiment + "/" + variant
+	stats, ok := t.stats[key]
+	if !ok {
+		stats = &Stats{Experiment: experiment, Variant: variant}
+		t.stats[key] = stats
+	}
+
+	stats.Calls++
+	switch outcome {
+	case OutcomeFailure:
+		stats.Failures++
+	case OutcomeParseError:
+		stats.ParseErrors++
+	}
+}
+
+// Report returns the stats of every variant, by experiment with the control first
+func (t *Tracker) Report() []Stats {
+	t.mu.Lock()
+	report := make([]Stats, 0, len(t.stats))
+	for _, stats := range t.stats {
+		report = append(report, *stats)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.Experiment != b.Experiment {
+			return a.Experiment < b.Experiment
+		}
+		if (a.Variant == Control) != (b.Variant == Control) {
+			return a.Variant == Control
+		}
+		return a.Variant < b.Variant
+	})
+	return report
+}
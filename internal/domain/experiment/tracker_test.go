@@ -0,0 +1,60 @@
+package experiment
+
+import "testing"
+
+func TestTracker_Assign(t *testing.T) {
+	tracker := NewTracker()
+
+	rolls := []int{0, 19, 20, 99}
+	tracker.roll = func() int {
+		roll := rolls[0]
+		rolls = rolls[1:]
+		return roll
+	}
+
+	want := []string{"strict", "strict", Control, Control}
+	for i, w := range want {
+		if got := tracker.Assign("strict", 20); got != w {
+			t.Errorf("Assign() call %d = %q, want %q", i+1, got, w)
+		}
+	}
+
+	// No variant or no share of calls always uses the control
+	if got := tracker.Assign("", 100); got != Control {
+		t.Errorf("Assign() without variant = %q, want %q", got, Control)
+	}
+	if got := tracker.Assign("strict", 0); got != Control {
+		t.Errorf("Assign() at 0%% = %q, want %q", got, Control)
+	}
+}
+
+func TestTracker_Report(t *testing.T) {
+	tracker := NewTracker()
+
+	tracker.Record(Intent, Control, OutcomeSuccess)
+	tracker.Record(Extraction, "strict", OutcomeParseError)
+	tracker.Record(Extraction, "strict", OutcomeSuccess)
+	tracker.Record(Extraction, Control, OutcomeSuccess)
+	tracker.Record(Extraction, Control, OutcomeFailure)
+	tracker.Record(Extraction, Control, OutcomeSuccess)
+	tracker.Record(Extraction, Control, OutcomeSuccess)
+
+	report := tracker.Report()
+	if len(report) != 3 {
+		t.Fatalf("Report() returned %d stats, want 3", len(report))
+	}
+
+	control, variant := report[0], report[1]
+	if control.Experiment != Extraction || control.Variant != Control {
+		t.Errorf("report[0] = %s/%s, want the extraction control", control.Experiment, control.Variant)
+	}
+	if control.Calls != 4 || control.FailureRate() != 0.25 || control.ParseErrorRate() != 0 {
+		t.Errorf("control stats = %+v", control)
+	}
+	if variant.Variant != "strict" || variant.Calls != 2 || variant.ParseErrorRate() != 0.5 {
+		t.Errorf("variant stats = %+v", variant)
+	}
+	if report[2].Experiment != Intent {
+		t.Errorf("report[2] experiment = %q, want %q", report[2].Experiment, Intent)
+	}
+}
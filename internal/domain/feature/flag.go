@@ -0,0 +1,93 @@
+package feature
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Flag identifies a capability that can be toggled without a redeploy
+type Flag string
+
+const (
+	FlagIntentDetection Flag = "intent_detection"
+	FlagTranslation     Flag = "translation"
+	FlagExport          Flag = "export"
+	FlagWebUI           Flag = "web_ui"
+	FlagCookbookExport  Flag = "cookbook_export"
+)
+
+// AllFlags returns all known flags
+func AllFlags() []Flag {
+	return []Flag{
+		FlagIntentDetection,
+		FlagTranslation,
+		FlagExport,
+		FlagWebUI,
+		FlagCookbookExport,
+	}
+}
+
+// DefaultValues returns the built-in value of each flag
+func DefaultValues() map[Flag]bool {
+	return map[Flag]bool{
+		FlagIntentDetection: true,
+		FlagTranslation:     true,
+		FlagExport:          true,
+		FlagWebUI:           false,
+		FlagCookbookExport:  true,
+	}
+}
+
+// ParseFlag converts a string to a Flag, returning false if it is unknown
+func ParseFlag(s string) (Flag, bool) {
+	normalized := Flag(strings.ToLower(strings.TrimSpace(s)))
+	for _, f := range AllFlags() {
+		if f == normalized {
+			return f, true
+		}
+	}
+	return "", false
+}
+
+// String returns the string representation of the flag
+func (f Flag) String() string {
+	return string(f)
+}
+
+// Override changes a flag's value for everyone, a share of users, or specific users.
+// Overrides are stored outside the deployment so they can change at runtime.
+type Override struct {
+	Flag           Flag
+	Enabled        bool        // Enabled for every user
+	RolloutPercent int         // Enabled for this share of users (0-100) when not Enabled
+	UserIDs        []shared.ID // Always enabled for these users
+}
+
+// EnabledFor reports whether the override enables the flag for a user
+func (o Override) EnabledFor(userID shared.ID) bool {
+	if o.Enabled {
+		return true
+	}
+
+	for _, id := range o.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+
+	if o.RolloutPercent <= 0 || userID.IsEmpty() {
+		return false
+	}
+
+	return cohort(o.Flag, userID) < o.RolloutPercent
+}
+
+// cohort places a user in a stable bucket between 0 and 99 for a flag.
+// Salting with the flag name keeps rollouts of different flags independent.
+func cohort(flag Flag, userID shared.ID) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(flag.String() + ":" + userID.String()))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,11 @@
+package feature
+
+import (
+	"context"
+)
+
+// Repository defines the interface for feature flag override persistence
+type Repository interface {
+	// FindAll retrieves all stored overrides
+	FindAll(ctx context.Context) ([]Override, error)
+}
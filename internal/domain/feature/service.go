@@ -0,0 +1,82 @@
+package feature
+
+import (
+	"context"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Service decides whether a feature is enabled for a user.
+// Stored overrides take precedence over the configured defaults.
+type Service struct {
+	defaults map[Flag]bool
+	repo     Repository
+
+	premium      map[Flag]bool
+	entitlements Entitlements
+}
+
+// Entitlements tells which users pay for the premium tier
+type Entitlements interface {
+	// IsPremium reports whether the user has the premium tier
+	IsPremium(ctx context.Context, userID shared.ID) bool
+}
+
+// NewService creates a new feature flag service.
+// repo may be nil, in which case only the defaults are used.
+func NewService(defaults map[Flag]bool, repo Repository) *Service {
+	values := DefaultValues()
+	for flag, enabled := range defaults {
+		values[flag] = enabled
+	}
+
+	return &Service{
+		defaults: values,
+		repo:     repo,
+	}
+}
+
+// RequirePremium makes flags premium features: once enabled, they are only
+// enabled for users with the premium tier. Without calling it, or with nil
+// entitlements, every user gets them.
+func (s *Service) RequirePremium(entitlements Entitlements, flags ...Flag) {
+	s.entitlements = entitlements
+	s.premium = make(map[Flag]bool, len(flags))
+	for _, flag := range flags {
+		s.premium[flag] = true
+	}
+}
+
+// IsPremium reports whether a flag is a premium feature
+func (s *Service) IsPremium(flag Flag) bool {
+	return s.entitlements != nil && s.premium[flag]
+}
+
+// IsEnabled reports whether a flag is enabled for the given user.
+// If overrides cannot be loaded, the default value is used.
+func (s *Service) IsEnabled(ctx context.Context, flag Flag, userID shared.ID) bool {
+	if !s.isEnabled(ctx, flag, userID) {
+		return false
+	}
+	return !s.IsPremium(flag) || s.entitlements.IsPremium(ctx, userID)
+}
+
+// isEnabled reports whether a flag is enabled for the given user, before entitlements
+func (s *Service) isEnabled(ctx context.Context, flag Flag, userID shared.ID) bool {
+	if s.repo != nil {
+		overrides, err := s.repo.FindAll(ctx)
+		if err == nil {
+			for _, o := range overrides {
+				if o.Flag == flag {
+					return o.EnabledFor(userID)
+				}
+			}
+		}
+	}
+
+	enabled, ok := s.defaults[flag]
+	if !ok {
+		return false
+	}
+	return enabled
+}
@@ -0,0 +1,147 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+type mockRepository struct {
+	overrides []Override
+	err       error
+}
+
+func (m *mockRepository) FindAll(ctx context.Context) ([]Override, error) {
+	return m.overrides, m.err
+}
+
+func TestService_IsEnabled(t *testing.T) {
+	ctx := context.Background()
+	userID := shared.ID("user-1")
+
+	tests := []struct {
+		name     string
+		defaults map[Flag]bool
+		repo     Repository
+		flag     Flag
+		want     bool
+	}{
+		{
+			name: "built-in default",
+			flag: FlagTranslation,
+			want: true,
+		},
+		{
+			name: "web UI disabled by default",
+			flag: FlagWebUI,
+			want: false,
+		},
+		{
+			name:     "configured default",
+			defaults: map[Flag]bool{FlagTranslation: false},
+			flag:     FlagTranslation,
+			want:     false,
+		},
+		{
+			name:     "override wins over default",
+			defaults: map[Flag]bool{FlagExport: true},
+			repo:     &mockRepository{overrides: []Override{{Flag: FlagExport, Enabled: false}}},
+			flag:     FlagExport,
+			want:     false,
+		},
+		{
+			name: "override allowlist",
+			repo: &mockRepository{overrides: []Override{{Flag: FlagWebUI, UserIDs: []shared.ID{userID}}}},
+			flag: FlagWebUI,
+			want: true,
+		},
+		{
+			name: "full rollout",
+			repo: &mockRepository{overrides: []Override{{Flag: FlagWebUI, RolloutPercent: 100}}},
+			flag: FlagWebUI,
+			want: true,
+		},
+		{
+			name:     "repository error falls back to default",
+			defaults: map[Flag]bool{FlagExport: false},
+			repo:     &mockRepository{err: errors.New("unavailable")},
+			flag:     FlagExport,
+			want:     false,
+		},
+		{
+			name: "unknown flag",
+			flag: Flag("unknown"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(tt.defaults, tt.repo)
+			if got := s.IsEnabled(ctx, tt.flag, userID); got != tt.want {
+				t.Errorf("IsEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type premiumUsers map[shared.ID]bool
+
+func (p premiumUsers) IsPremium(ctx context.Context, userID shared.ID) bool {
+	return p[userID]
+}
+
+func TestService_RequirePremium(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(map[Flag]bool{FlagWebUI: false}, nil)
+
+	// Without entitlements premium flags are everyone's
+	if !s.IsEnabled(ctx, FlagCookbookExport, "free") {
+		t.Error("IsEnabled() = false before RequirePremium, want true")
+	}
+
+	s.RequirePremium(premiumUsers{"paid": true}, FlagCookbookExport, FlagWebUI)
+	if s.IsEnabled(ctx, FlagCookbookExport, "free") {
+		t.Error("IsEnabled() = true for a free user, want false")
+	}
+	if !s.IsEnabled(ctx, FlagCookbookExport, "paid") {
+		t.Error("IsEnabled() = false for a premium user, want true")
+	}
+	if s.IsEnabled(ctx, FlagWebUI, "paid") {
+		t.Error("IsEnabled() = true for a disabled flag, want premium not to enable it")
+	}
+	if !s.IsEnabled(ctx, FlagTranslation, "free") {
+		t.Error("IsEnabled() = false for a flag that is not premium, want true")
+	}
+}
+
+func TestOverride_RolloutIsStable(t *testing.T) {
+	o := Override{Flag: FlagTranslation, RolloutPercent: 50}
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		id := shared.NewID()
+		first := o.EnabledFor(id)
+		if first != o.EnabledFor(id) {
+			t.Fatalf("EnabledFor() not stable for user %s", id)
+		}
+		if first {
+			enabled++
+		}
+	}
+
+	if enabled < 400 || enabled > 600 {
+		t.Errorf("EnabledFor() enabled %d of 1000 users, want roughly 500", enabled)
+	}
+}
+
+func TestParseFlag(t *testing.T) {
+	if f, ok := ParseFlag(" Translation "); !ok || f != FlagTranslation {
+		t.Errorf("ParseFlag() = %v, %v, want %v, true", f, ok, FlagTranslation)
+	}
+	if _, ok := ParseFlag("nope"); ok {
+		t.Error("ParseFlag() should reject unknown flags")
+	}
+}
@@ -0,0 +1,108 @@
+// Package feed lets users follow recipe blogs through their RSS or Atom feeds.
+package feed
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// MaxSubscriptions caps the feeds a user follows
+const MaxSubscriptions = 20
+
+// maxSeen caps the item IDs remembered per feed; feeds list far fewer items
+const maxSeen = 200
+
+// idBytes is the amount of randomness in a subscription ID
+const idBytes = 8
+
+// Feed is the current content of an RSS or Atom feed
+type Feed struct {
+	Title string
+	Items []Item // newest first, as feeds list them
+}
+
+// Item is a post of a feed
+type Item struct {
+	ID        string // the feed's GUID for the post, or its link
+	Title     string
+	Link      string
+	Published time.Time // zero if the feed does not say
+}
+
+// Subscription is a feed a user follows. Items already seen are remembered so
+// only posts published after subscribing are proposed.
+type Subscription struct {
+	ID        string
+	UserID    UserID
+	URL       string
+	Title     string
+	Seen      []string // IDs of the items seen, oldest first
+	CreatedAt time.Time
+}
+
+// NewSubscription creates a subscription to a feed, treating its current items as seen
+func NewSubscription(userID UserID, url string, current *Feed, now time.Time) (*Subscription, error) {
+	if userID.IsEmpty() || url == "" {
+		return nil, shared.ErrInvalidInput
+	}
+
+	b := make([]byte, idBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate subscription ID: %w", err)
+	}
+
+	s := &Subscription{
+		ID:        hex.EncodeToString(b),
+		UserID:    userID,
+		URL:       url,
+		Title:     current.Title,
+		CreatedAt: now,
+	}
+	if s.Title == "" {
+		s.Title = url
+	}
+	s.MarkSeen(current.Items)
+	return s, nil
+}
+
+// NewItems returns the items of the feed not seen yet, oldest first
+func (s *Subscription) NewItems(current *Feed) []Item {
+	seen := make(map[string]bool, len(s.Seen))
+	for _, id := range s.Seen {
+		seen[id] = true
+	}
+
+	var items []Item
+	for i := len(current.Items) - 1; i >= 0; i-- {
+		if item := current.Items[i]; !seen[item.ID] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// MarkSeen remembers items as seen, forgetting the oldest beyond maxSeen
+func (s *Subscription) MarkSeen(items []Item) {
+	seen := make(map[string]bool, len(s.Seen))
+	for _, id := range s.Seen {
+		seen[id] = true
+	}
+
+	// Items are listed newest first, so they are remembered in reverse
+	for i := len(items) - 1; i >= 0; i-- {
+		if id := items[i].ID; id != "" && !seen[id] {
+			seen[id] = true
+			s.Seen = append(s.Seen, id)
+		}
+	}
+	if len(s.Seen) > maxSeen {
+		s.Seen = append([]string(nil), s.Seen[len(s.Seen)-maxSeen:]...)
+	}
+}
@@ -0,0 +1,61 @@
+package feed
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestSubscription_NewItems(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	current := &Feed{Title: "Smitten Kitchen", Items: []Item{{ID: "b"}, {ID: "a"}}}
+
+	s, err := NewSubscription(shared.NewID(), "https://smittenkitchen.com/feed/", current, now)
+	if err != nil {
+		t.Fatalf("NewSubscription() error = %v", err)
+	}
+	if got := s.NewItems(current); len(got) != 0 {
+		t.Errorf("NewItems() = %v right after subscribing, want none", got)
+	}
+
+	// Two posts later, newest first
+	later := &Feed{Items: []Item{{ID: "d"}, {ID: "c"}, {ID: "b"}}}
+	var ids []string
+	for _, item := range s.NewItems(later) {
+		ids = append(ids, item.ID)
+	}
+	if want := []string{"c", "d"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("NewItems() = %v, want %v oldest first", ids, want)
+	}
+
+	s.MarkSeen(later.Items)
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(s.Seen, want) {
+		t.Errorf("Seen = %v, want %v", s.Seen, want)
+	}
+}
+
+func TestSubscription_MarkSeenForgetsOldest(t *testing.T) {
+	s := &Subscription{}
+	items := make([]Item, maxSeen+10)
+	for i := range items {
+		items[i] = Item{ID: string(rune('A' + i))}
+	}
+
+	s.MarkSeen(items)
+	if len(s.Seen) != maxSeen {
+		t.Fatalf("len(Seen) = %d, want %d", len(s.Seen), maxSeen)
+	}
+	// The feed lists newest first, so its first item is the newest
+	if s.Seen[len(s.Seen)-1] != items[0].ID {
+		t.Errorf("newest seen = %q, want %q", s.Seen[len(s.Seen)-1], items[0].ID)
+	}
+}
+
+func TestNewSubscription_Invalid(t *testing.T) {
+	if _, err := NewSubscription("", "https://example.com/feed", &Feed{}, time.Now()); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Errorf("NewSubscription() error = %v, want %v", err, shared.ErrInvalidInput)
+	}
+}
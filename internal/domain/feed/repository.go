@@ -0,0 +1,18 @@
+package feed
+
+import "context"
+
+// Repository defines the interface for feed subscription persistence (Port)
+type Repository interface {
+	// Save persists a subscription
+	Save(ctx context.Context, s *Subscription) error
+
+	// FindByUser retrieves the subscriptions of a user, oldest first
+	FindByUser(ctx context.Context, userID UserID) ([]*Subscription, error)
+
+	// FindAll retrieves every subscription, for polling
+	FindAll(ctx context.Context) ([]*Subscription, error)
+
+	// Delete removes a subscription
+	Delete(ctx context.Context, id string) error
+}
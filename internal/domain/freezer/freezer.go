@@ -0,0 +1,188 @@
+// Package freezer keeps track of cooked portions a user has frozen.
+package freezer
+
+import (
+	"sort"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// RecipeID represents a unique recipe identifier
+type RecipeID = shared.ID
+
+// How long frozen food keeps its quality
+const (
+	EatSoonAfter = 60 * 24 * time.Hour // two months
+	TooOldAfter  = 90 * 24 * time.Hour // three months
+)
+
+// Age describes how long a batch has been in the freezer
+type Age string
+
+const (
+	AgeFresh   Age = "fresh"
+	AgeEatSoon Age = "eat_soon"
+	AgeTooOld  Age = "too_old"
+)
+
+// Batch is a number of portions of a recipe frozen at the same time
+type Batch struct {
+	RecipeID RecipeID
+	Title    string // recipe title when it was frozen, kept if the recipe is deleted
+	Portions int
+	FrozenAt time.Time
+}
+
+// Age returns how long the batch has been frozen at now
+func (b Batch) Age(now time.Time) Age {
+	switch d := now.Sub(b.FrozenAt); {
+	case d >= TooOldAfter:
+		return AgeTooOld
+	case d >= EatSoonAfter:
+		return AgeEatSoon
+	default:
+		return AgeFresh
+	}
+}
+
+// Days returns the number of whole days the batch has been frozen at now
+func (b Batch) Days(now time.Time) int {
+	return int(now.Sub(b.FrozenAt).Hours() / 24)
+}
+
+// Freezer holds the batches a user has frozen (Aggregate Root)
+type Freezer struct {
+	userID    UserID
+	batches   []Batch // oldest first
+	updatedAt shared.Timestamp
+}
+
+// NewFreezer creates an empty freezer for a user
+func NewFreezer(userID UserID) (*Freezer, error) {
+	if userID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &Freezer{
+		userID:    userID,
+		batches:   []Batch{},
+		updatedAt: shared.NewTimestamp(),
+	}, nil
+}
+
+// FreezerData contains data for reconstructing a freezer from storage
+type FreezerData struct {
+	UserID    UserID
+	Batches   []Batch
+	UpdatedAt time.Time
+}
+
+// ReconstructFreezer reconstructs a freezer from stored data (for repository)
+func ReconstructFreezer(data FreezerData) *Freezer {
+	batches := append([]Batch{}, data.Batches...)
+	sortBatches(batches)
+	return &Freezer{
+		userID:    data.UserID,
+		batches:   batches,
+		updatedAt: shared.NewTimestampFromTime(data.UpdatedAt),
+	}
+}
+
+// UserID returns the owner of the freezer
+func (f *Freezer) UserID() UserID {
+	return f.userID
+}
+
+// Batches returns the frozen batches, oldest first
+func (f *Freezer) Batches() []Batch {
+	return f.batches
+}
+
+// UpdatedAt returns the last update timestamp
+func (f *Freezer) UpdatedAt() time.Time {
+	return f.updatedAt.Time()
+}
+
+// IsEmpty reports whether nothing is frozen
+func (f *Freezer) IsEmpty() bool {
+	return len(f.batches) == 0
+}
+
+// Portions returns the total number of frozen portions of a recipe
+func (f *Freezer) Portions(recipeID RecipeID) int {
+	total := 0
+	for _, b := range f.batches {
+		if b.RecipeID == recipeID {
+			total += b.Portions
+		}
+	}
+	return total
+}
+
+// Add records portions of a recipe frozen at frozenAt
+func (f *Freezer) Add(recipeID RecipeID, title string, portions int, frozenAt time.Time) error {
+	if recipeID.IsEmpty() || portions <= 0 {
+		return shared.ErrInvalidInput
+	}
+
+	f.batches = append(f.batches, Batch{RecipeID: recipeID, Title: title, Portions: portions, FrozenAt: frozenAt})
+	sortBatches(f.batches)
+	f.updatedAt = shared.NewTimestamp()
+	return nil
+}
+
+// Take removes portions of a recipe, oldest batches first, and returns how many were taken.
+// Taking more portions than are frozen empties the recipe's batches.
+func (f *Freezer) Take(recipeID RecipeID, portions int) (int, error) {
+	if portions <= 0 {
+		return 0, shared.ErrInvalidInput
+	}
+	if f.Portions(recipeID) == 0 {
+		return 0, shared.ErrNotInFreezer
+	}
+
+	taken := 0
+	kept := f.batches[:0]
+	for _, b := range f.batches {
+		if b.RecipeID == recipeID && taken < portions {
+			n := min(b.Portions, portions-taken)
+			taken += n
+			b.Portions -= n
+		}
+		if b.Portions > 0 {
+			kept = append(kept, b)
+		}
+	}
+	f.batches = kept
+	f.updatedAt = shared.NewTimestamp()
+	return taken, nil
+}
+
+// EatFirst returns the batches that should be eaten soon at now, oldest first
+func (f *Freezer) EatFirst(now time.Time) []Batch {
+	var batches []Batch
+	for _, b := range f.batches {
+		if b.Age(now) != AgeFresh {
+			batches = append(batches, b)
+		}
+	}
+	return batches
+}
+
+// Clone returns a copy of the freezer that shares no mutable state with the original
+func (f *Freezer) Clone() *Freezer {
+	cp := *f
+	cp.batches = append([]Batch{}, f.batches...)
+	return &cp
+}
+
+// sortBatches orders batches oldest first
+func sortBatches(batches []Batch) {
+	sort.SliceStable(batches, func(i, j int) bool {
+		return batches[i].FrozenAt.Before(batches[j].FrozenAt)
+	})
+}
@@ -0,0 +1,78 @@
+package freezer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestBatch_Age(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		frozenAt time.Time
+		want     Age
+	}{
+		{"this week", now.AddDate(0, 0, -3), AgeFresh},
+		{"two months", now.AddDate(0, 0, -60), AgeEatSoon},
+		{"three months", now.AddDate(0, 0, -90), AgeTooOld},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Batch{RecipeID: "r1", Portions: 1, FrozenAt: tt.frozenAt}
+			if got := b.Age(now); got != tt.want {
+				t.Errorf("Age() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreezer_AddAndTake(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	f, err := NewFreezer("user-1")
+	if err != nil {
+		t.Fatalf("NewFreezer() error = %v", err)
+	}
+
+	_ = f.Add("chili", "Chili", 2, now.AddDate(0, 0, -10))
+	_ = f.Add("soup", "Soup", 4, now.AddDate(0, 0, -70))
+	_ = f.Add("chili", "Chili", 3, now.AddDate(0, 0, -100))
+
+	if err := f.Add("chili", "Chili", 0, now); err == nil {
+		t.Error("Add() with no portions should fail")
+	}
+
+	batches := f.Batches()
+	if len(batches) != 3 || batches[0].Portions != 3 || batches[2].Portions != 2 {
+		t.Fatalf("Batches() = %+v, want oldest first", batches)
+	}
+	if got := f.Portions("chili"); got != 5 {
+		t.Errorf("Portions(chili) = %d, want 5", got)
+	}
+
+	eatFirst := f.EatFirst(now)
+	if len(eatFirst) != 2 || eatFirst[0].RecipeID != "chili" || eatFirst[1].RecipeID != "soup" {
+		t.Errorf("EatFirst() = %+v, want the old chili then the soup", eatFirst)
+	}
+
+	// Oldest batch is eaten first
+	taken, err := f.Take("chili", 4)
+	if err != nil || taken != 4 {
+		t.Fatalf("Take() = %d, %v; want 4, nil", taken, err)
+	}
+	if got := f.Portions("chili"); got != 1 || len(f.Batches()) != 2 {
+		t.Errorf("after Take(): %d chili portions in %d batches, want 1 in 2", got, len(f.Batches()))
+	}
+
+	taken, _ = f.Take("soup", 10)
+	if taken != 4 {
+		t.Errorf("Take() more than frozen = %d, want 4", taken)
+	}
+	if _, err := f.Take("soup", 1); !errors.Is(err, shared.ErrNotInFreezer) {
+		t.Errorf("Take() of an eaten recipe error = %v, want ErrNotInFreezer", err)
+	}
+}
@@ -0,0 +1,13 @@
+package freezer
+
+import "context"
+
+// Repository defines the interface for freezer persistence (Port)
+type Repository interface {
+	// FindByUser retrieves the freezer of a user.
+	// It returns shared.ErrFreezerNotFound if nothing was frozen yet.
+	FindByUser(ctx context.Context, userID UserID) (*Freezer, error)
+
+	// Save persists a freezer, replacing the user's previous one
+	Save(ctx context.Context, f *Freezer) error
+}
@@ -2,6 +2,7 @@ package matching
 
 import (
 	"sort"
+	"strings"
 
 	"receipt-bot/internal/domain/recipe"
 )
@@ -23,14 +24,36 @@ type MatchResult struct {
 	MatchedItems    []string
 	MissingItems    []string
 	MatchLevel      MatchLevel
+	Details         []IngredientMatch // how each recipe ingredient was counted, in recipe order
+}
+
+// MatchKind is how a recipe ingredient was counted
+type MatchKind string
+
+const (
+	MatchKindExact      MatchKind = "exact"      // the user has the same ingredient
+	MatchKindPartial    MatchKind = "partial"    // one name contains the other: "chicken" for "chicken breast"
+	MatchKindSubstitute MatchKind = "substitute" // a related ingredient stands in: "pasta" for "linguine"
+	MatchKindStaple     MatchKind = "staple"     // a pantry staple, left out of the percentage
+	MatchKindMissing    MatchKind = "missing"
+)
+
+// IngredientMatch explains how one recipe ingredient was counted
+type IngredientMatch struct {
+	Ingredient string    // as the recipe lists it
+	Kind       MatchKind
+	Via        string    // the normalized user ingredient it was matched with, if any
 }
 
 // MatchOptions configures the matching behavior
 type MatchOptions struct {
 	StrictMatch      bool             // Only return perfect matches
 	CategoryFilter   *recipe.Category // Filter by category
-	ExcludeStaples   bool             // Exclude common pantry staples from calculation
+	ExcludeStaples   bool             // Exclude pantry staples from calculation
+	Staples          []string         // Ingredients the user always has; nil means CommonPantryStaples
+	Specificity      int              // Levels up the ingredient hierarchy a user ingredient may stand in (0 = only its own kinds)
 	MinMatchLevel    MatchLevel       // Minimum match level to include
+	MaxMissing       int              // Most ingredients a recipe may lack (0 = no limit)
 	MaxResults       int              // Maximum number of results (0 = unlimited)
 }
 
@@ -40,6 +63,7 @@ func DefaultMatchOptions() MatchOptions {
 		StrictMatch:    false,
 		CategoryFilter: nil,
 		ExcludeStaples: true,
+		Specificity:    DefaultSpecificity,
 		MinMatchLevel:  MatchLevelMedium,
 		MaxResults:     20,
 	}
@@ -48,12 +72,14 @@ func DefaultMatchOptions() MatchOptions {
 // IngredientMatcher matches user ingredients against recipes
 type IngredientMatcher struct {
 	normalizer IngredientNormalizer
+	ontology   *Ontology
 }
 
 // NewIngredientMatcher creates a new matcher
 func NewIngredientMatcher(normalizer IngredientNormalizer) *IngredientMatcher {
 	return &IngredientMatcher{
 		normalizer: normalizer,
+		ontology:   DefaultOntology(),
 	}
 }
 
@@ -76,6 +102,11 @@ func (m *IngredientMatcher) Match(
 		return nil
 	}
 
+	var staples map[string]bool
+	if options.ExcludeStaples {
+		staples = m.stapleSet(options.Staples)
+	}
+
 	var results []MatchResult
 
 	for _, rec := range recipes {
@@ -84,7 +115,7 @@ func (m *IngredientMatcher) Match(
 			continue
 		}
 
-		result := m.matchRecipe(rec, normalizedUser, options.ExcludeStaples)
+		result := m.matchRecipe(rec, normalizedUser, staples, options.Specificity)
 
 		// Apply minimum match level filter
 		if result.MatchLevel > options.MinMatchLevel {
@@ -96,6 +127,10 @@ func (m *IngredientMatcher) Match(
 			continue
 		}
 
+		if options.MaxMissing > 0 && len(result.MissingItems) > options.MaxMissing {
+			continue
+		}
+
 		results = append(results, result)
 	}
 
@@ -116,7 +151,8 @@ func (m *IngredientMatcher) Match(
 func (m *IngredientMatcher) matchRecipe(
 	rec *recipe.Recipe,
 	normalizedUser map[string]bool,
-	excludeStaples bool,
+	staples map[string]bool,
+	specificity int,
 ) MatchResult {
 	result := MatchResult{
 		Recipe:       rec,
@@ -131,17 +167,20 @@ func (m *IngredientMatcher) matchRecipe(
 		normalized := m.normalizer.Normalize(ing.Name())
 
 		// Skip pantry staples if configured
-		if excludeStaples && IsPantryStaple(normalized) {
+		if staples[normalized] {
+			result.Details = append(result.Details, IngredientMatch{Ingredient: ing.Name(), Kind: MatchKindStaple})
 			continue
 		}
 
 		totalRequired++
 
-		if m.hasIngredient(normalized, normalizedUser) {
+		via, kind := m.findIngredient(normalized, normalizedUser, specificity)
+		if kind != MatchKindMissing {
 			result.MatchedItems = append(result.MatchedItems, ing.Name())
 		} else {
 			result.MissingItems = append(result.MissingItems, ing.Name())
 		}
+		result.Details = append(result.Details, IngredientMatch{Ingredient: ing.Name(), Kind: kind, Via: via})
 	}
 
 	// Calculate match percentage
@@ -167,21 +206,48 @@ func (m *IngredientMatcher) matchRecipe(
 	return result
 }
 
-// hasIngredient checks if the user has an ingredient (exact or similar)
-func (m *IngredientMatcher) hasIngredient(recipeIng string, userIngredients map[string]bool) bool {
+// stapleSet normalizes the staples to exclude, defaulting to CommonPantryStaples
+func (m *IngredientMatcher) stapleSet(staples []string) map[string]bool {
+	if staples == nil {
+		staples = DefaultStaples()
+	}
+	set := make(map[string]bool, len(staples))
+	for _, staple := range staples {
+		if normalized := m.normalizer.Normalize(staple); normalized != "" {
+			set[normalized] = true
+		}
+	}
+	return set
+}
+
+// findIngredient finds the user ingredient that covers a recipe ingredient, preferring
+// the same ingredient, then one whose name contains or is contained in it, then one
+// that can stand in for it within the given specificity
+func (m *IngredientMatcher) findIngredient(recipeIng string, userIngredients map[string]bool, specificity int) (string, MatchKind) {
 	// Direct match
 	if userIngredients[recipeIng] {
-		return true
+		return recipeIng, MatchKindExact
 	}
 
-	// Check for similar ingredients
+	// Check for compound ingredients and related kinds, in a stable order
+	users := make([]string, 0, len(userIngredients))
 	for userIng := range userIngredients {
-		if m.normalizer.AreSimilar(recipeIng, userIng) {
-			return true
+		users = append(users, userIng)
+	}
+	sort.Strings(users)
+
+	for _, userIng := range users {
+		if strings.Contains(recipeIng, userIng) || strings.Contains(userIng, recipeIng) {
+			return userIng, MatchKindPartial
+		}
+	}
+	for _, userIng := range users {
+		if m.ontology.Covers(userIng, recipeIng, specificity) {
+			return userIng, MatchKindSubstitute
 		}
 	}
 
-	return false
+	return "", MatchKindMissing
 }
 
 // GroupByMatchLevel groups results by their match level
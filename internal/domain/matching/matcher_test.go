@@ -1,6 +1,7 @@
 package matching
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -32,7 +33,7 @@ func createTestRecipe(title string, category recipe.Category, ingredientNames []
 }
 
 func TestIngredientMatcher_Match(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 	matcher := NewIngredientMatcher(normalizer)
 
 	// Create test recipes
@@ -111,7 +112,7 @@ func TestIngredientMatcher_Match(t *testing.T) {
 }
 
 func TestIngredientMatcher_MatchSorting(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 	matcher := NewIngredientMatcher(normalizer)
 
 	// Create recipes with different match levels
@@ -145,7 +146,7 @@ func TestIngredientMatcher_MatchSorting(t *testing.T) {
 }
 
 func TestIngredientMatcher_MaxResults(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 	matcher := NewIngredientMatcher(normalizer)
 
 	// Create many recipes
@@ -171,7 +172,7 @@ func TestIngredientMatcher_MaxResults(t *testing.T) {
 }
 
 func TestIngredientMatcher_StrictMode(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 	matcher := NewIngredientMatcher(normalizer)
 
 	perfectMatch := createTestRecipe("Perfect", recipe.CategoryOther,
@@ -306,7 +307,7 @@ func TestDefaultMatchOptions(t *testing.T) {
 }
 
 func TestIngredientMatcher_ExcludeStaples(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 	matcher := NewIngredientMatcher(normalizer)
 
 	// Recipe with staples and non-staples
@@ -345,8 +346,92 @@ func TestIngredientMatcher_ExcludeStaples(t *testing.T) {
 	}
 }
 
+func TestIngredientMatcher_CustomStaples(t *testing.T) {
+	matcher := NewIngredientMatcher(NewRuleBasedNormalizer(nil))
+	rec := createTestRecipe("Test Recipe", recipe.CategoryOther,
+		[]string{"chicken breast", "salt", "soy sauce", "garlic"})
+
+	options := MatchOptions{
+		ExcludeStaples: true,
+		Staples:        []string{"Soy Sauce", "salt"},
+		MinMatchLevel:  MatchLevelLow,
+	}
+	results := matcher.Match([]string{"chicken breast", "garlic"}, []*recipe.Recipe{rec}, options)
+	if len(results) != 1 || results[0].MatchLevel != MatchLevelPerfect {
+		t.Fatalf("results = %+v, want a perfect match with soy sauce and salt as staples", results)
+	}
+
+	// An empty list means the user has no staples at all
+	options.Staples = []string{}
+	results = matcher.Match([]string{"chicken breast", "garlic"}, []*recipe.Recipe{rec}, options)
+	if len(results) != 1 || results[0].MatchPercentage != 50 {
+		t.Errorf("results = %+v, want 50%% with no staples", results)
+	}
+}
+
+func TestIngredientMatcher_Specificity(t *testing.T) {
+	matcher := NewIngredientMatcher(NewRuleBasedNormalizer(nil))
+	linguine := createTestRecipe("Linguine", recipe.CategoryPasta, []string{"linguine", "clams"})
+	ramen := createTestRecipe("Ramen", recipe.CategoryPasta, []string{"ramen noodles", "clams"})
+	recipes := []*recipe.Recipe{linguine, ramen}
+
+	options := MatchOptions{MinMatchLevel: MatchLevelPerfect}
+	user := []string{"pasta", "clams"}
+
+	if results := matcher.Match(user, recipes, options); len(results) != 0 {
+		t.Errorf("specificity 0: got %d matches, want none", len(results))
+	}
+
+	options.Specificity = 1
+	if results := matcher.Match(user, recipes, options); len(results) != 1 || results[0].Recipe != linguine {
+		t.Errorf("specificity 1: got %+v, want only the linguine", results)
+	}
+
+	options.Specificity = 2
+	if results := matcher.Match(user, recipes, options); len(results) != 2 {
+		t.Errorf("specificity 2: got %d matches, want both noodle recipes", len(results))
+	}
+}
+
+func TestIngredientMatcher_Details(t *testing.T) {
+	matcher := NewIngredientMatcher(NewRuleBasedNormalizer(nil))
+	rec := createTestRecipe("Linguine", recipe.CategoryPasta,
+		[]string{"linguine", "chicken breast", "garlic", "salt", "white wine"})
+
+	results := matcher.Match([]string{"pasta", "chicken", "garlic"}, []*recipe.Recipe{rec}, DefaultMatchOptions())
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+
+	want := []IngredientMatch{
+		{Ingredient: "linguine", Kind: MatchKindSubstitute, Via: "pasta"},
+		{Ingredient: "chicken breast", Kind: MatchKindPartial, Via: "chicken"},
+		{Ingredient: "garlic", Kind: MatchKindExact, Via: "garlic"},
+		{Ingredient: "salt", Kind: MatchKindStaple},
+		{Ingredient: "white wine", Kind: MatchKindMissing},
+	}
+	if got := results[0].Details; !reflect.DeepEqual(got, want) {
+		t.Errorf("Details = %+v, want %+v", got, want)
+	}
+	if results[0].MatchPercentage != 75 {
+		t.Errorf("MatchPercentage = %.0f, want 75 with salt left out", results[0].MatchPercentage)
+	}
+}
+
+func TestIngredientMatcher_MaxMissing(t *testing.T) {
+	matcher := NewIngredientMatcher(NewRuleBasedNormalizer(nil))
+	oneMissing := createTestRecipe("Omelette", recipe.CategoryBreakfast, []string{"eggs", "butter", "chives"})
+	threeMissing := createTestRecipe("Quiche", recipe.CategoryBreakfast, []string{"eggs", "cream", "bacon", "leeks", "pastry"})
+
+	options := MatchOptions{MinMatchLevel: MatchLevelLow, MaxMissing: 2}
+	results := matcher.Match([]string{"eggs", "butter"}, []*recipe.Recipe{oneMissing, threeMissing}, options)
+	if len(results) != 1 || results[0].Recipe != oneMissing {
+		t.Errorf("results = %+v, want only the omelette", results)
+	}
+}
+
 func TestNewIngredientMatcher(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 	matcher := NewIngredientMatcher(normalizer)
 
 	if matcher == nil {
@@ -1,8 +1,13 @@
 package matching
 
 import (
+	"bufio"
+	"fmt"
+	"io"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // IngredientNormalizer normalizes ingredient names for matching
@@ -20,49 +25,29 @@ type IngredientNormalizer interface {
 
 // RuleBasedNormalizer implements IngredientNormalizer using rule-based logic
 type RuleBasedNormalizer struct {
-	substitutionIndex map[string]int // maps ingredient to group index
+	ontology *Ontology         // decides which ingredients can substitute each other
+	synonyms map[string]string // other names of an ingredient to the one used for matching
 }
 
-// NewRuleBasedNormalizer creates a new rule-based normalizer
-func NewRuleBasedNormalizer() *RuleBasedNormalizer {
+// NewRuleBasedNormalizer creates a new rule-based normalizer that resolves the
+// built-in synonyms and extra ones, such as those read by ParseSynonyms. Extra
+// names are normalized the way ingredients are; nil adds none.
+func NewRuleBasedNormalizer(extra map[string]string) *RuleBasedNormalizer {
 	n := &RuleBasedNormalizer{
-		substitutionIndex: make(map[string]int),
+		ontology: DefaultOntology(),
+		synonyms: make(map[string]string, len(builtinSynonyms)+len(extra)),
 	}
-
-	// Build substitution index from groups
-	for i, group := range substitutionGroups {
-		for _, item := range group {
-			n.substitutionIndex[item] = i
+	for alias, canonical := range builtinSynonyms {
+		n.synonyms[alias] = canonical
+	}
+	for alias, canonical := range extra {
+		if alias, canonical = n.normalizeName(alias), n.normalizeName(canonical); alias != "" && canonical != "" {
+			n.synonyms[alias] = canonical
 		}
 	}
-
 	return n
 }
 
-// substitutionGroups defines ingredients that can substitute each other
-var substitutionGroups = [][]string{
-	{"milk", "cream", "half-and-half", "heavy cream", "whole milk", "skim milk"},
-	{"parmesan", "romano", "pecorino", "grana padano", "asiago"},
-	{"butter", "margarine", "unsalted butter", "salted butter"},
-	{"chicken breast", "chicken thigh", "chicken", "chicken leg"},
-	{"sugar", "honey", "maple syrup", "agave", "brown sugar", "white sugar"},
-	{"spaghetti", "linguine", "fettuccine", "penne", "rigatoni", "pasta"},
-	{"olive oil", "vegetable oil", "canola oil", "cooking oil", "oil"},
-	{"garlic", "garlic cloves", "minced garlic", "garlic powder"},
-	{"onion", "yellow onion", "white onion", "red onion", "shallot"},
-	{"tomato", "tomatoes", "cherry tomatoes", "roma tomatoes", "diced tomatoes"},
-	{"ground beef", "ground turkey", "ground pork", "ground meat"},
-	{"cheddar", "monterey jack", "colby", "american cheese"},
-	{"mozzarella", "provolone", "fontina"},
-	{"basil", "fresh basil", "dried basil", "basil leaves"},
-	{"oregano", "dried oregano", "fresh oregano"},
-	{"thyme", "fresh thyme", "dried thyme"},
-	{"lemon juice", "lime juice", "citrus juice"},
-	{"chicken broth", "chicken stock", "vegetable broth", "vegetable stock", "broth", "stock"},
-	{"sour cream", "greek yogurt", "plain yogurt"},
-	{"bread crumbs", "breadcrumbs", "panko", "panko breadcrumbs"},
-}
-
 // quantityPattern matches common quantity patterns
 var quantityPattern = regexp.MustCompile(`^[\d\s/½⅓¼⅔¾⅛⅜⅝⅞]+\s*`)
 
@@ -75,20 +60,76 @@ var prepWordsPattern = regexp.MustCompile(`(?i)\b(fresh|freshly|chopped|minced|d
 // trailingPunctPattern removes trailing punctuation and parenthetical notes
 var trailingPunctPattern = regexp.MustCompile(`[,;:]+.*$|\s*\([^)]*\)\s*$`)
 
-// pluralSuffixes for simple depluralization
+// irregularPlurals are plurals the suffix rules get wrong, in English and Portuguese
+var irregularPlurals = map[string]string{
+	"leaves":    "leaf",
+	"loaves":    "loaf",
+	"halves":    "half",
+	"knives":    "knife",
+	"cookies":   "cookie",
+	"brownies":  "brownie",
+	"smoothies": "smoothie",
+	"pães":      "pão",
+	"vagens":    "vagem",
+	"amendoins": "amendoim",
+	"pudins":    "pudim",
+}
+
+// invariantSuffixes end words that look plural but are not:
+// asparagus, hummus, couscous, swiss, watercress
+var invariantSuffixes = []string{"us", "ss"}
+
+// pluralSuffixes for light depluralization, tried in order
 var pluralSuffixes = []struct {
-	suffix string
+	suffix  string
 	replace string
 }{
-	{"ies", "y"},      // berries -> berry
-	{"ves", "f"},      // leaves -> leaf
-	{"oes", "o"},      // tomatoes -> tomato
-	{"es", ""},        // dishes -> dish
-	{"s", ""},         // carrots -> carrot
+	{"ões", "ão"},  // limões -> limão
+	{"ães", "ão"},  // alemães -> alemão
+	{"ãos", "ão"},  // grãos -> grão
+	{"éis", "el"},  // pastéis -> pastel
+	{"óis", "ol"},  // faróis -> farol
+	{"ais", "al"},  // cereais -> cereal
+	{"ies", "y"},   // berries -> berry
+	{"oes", "o"},   // tomatoes -> tomato
+	{"ches", "ch"}, // peaches -> peach
+	{"shes", "sh"}, // radishes -> radish
+	{"sses", "ss"}, // glasses -> glass
+	{"xes", "x"},   // boxes -> box
+	{"zes", "z"},   // nozes -> noz
+	{"ares", "ar"}, // açúcares -> açúcar
+	{"eres", "er"}, // colheres -> colher
+	{"ores", "or"}, // flores -> flor
+	{"s", ""},      // carrots -> carrot, cebolas -> cebola
+}
+
+// builtinSynonyms maps other names of an ingredient, normalized, to the one used for matching
+var builtinSynonyms = map[string]string{
+	"scallion":            "green onion",
+	"spring onion":        "green onion",
+	"aubergine":           "eggplant",
+	"courgette":           "zucchini",
+	"garbanzo bean":       "chickpea",
+	"rocket":              "arugula",
+	"coriander":           "cilantro",
+	"capsicum":            "bell pepper",
+	"bicarbonate of soda": "baking soda",
+	"icing sugar":         "powdered sugar",
+	"grão-de-bico":        "grão de bico",
 }
 
 // Normalize extracts the base ingredient name
 func (n *RuleBasedNormalizer) Normalize(raw string) string {
+	result := n.normalizeName(raw)
+
+	if canonical, ok := n.synonyms[result]; ok {
+		return canonical
+	}
+	return result
+}
+
+// normalizeName extracts the base ingredient name, before synonyms are resolved
+func (n *RuleBasedNormalizer) normalizeName(raw string) string {
 	if raw == "" {
 		return ""
 	}
@@ -109,27 +150,40 @@ func (n *RuleBasedNormalizer) Normalize(raw string) string {
 	result = prepWordsPattern.ReplaceAllString(result, " ")
 
 	// Clean up extra whitespace
-	result = strings.Join(strings.Fields(result), " ")
+	words := strings.Fields(result)
+	if len(words) == 0 {
+		return ""
+	}
 
-	// Simple depluralization
-	result = n.depluralize(result)
+	// Light depluralization of the last word: "cherry tomatoes" -> "cherry tomato"
+	words[len(words)-1] = n.depluralize(words[len(words)-1])
 
-	return strings.TrimSpace(result)
+	return strings.Join(words, " ")
 }
 
-// depluralize attempts to convert plural to singular
+// depluralize attempts to convert an English or Portuguese plural to singular
 func (n *RuleBasedNormalizer) depluralize(word string) string {
+	if singular, ok := irregularPlurals[word]; ok {
+		return singular
+	}
+
 	// Don't depluralize very short words
-	if len(word) <= 3 {
+	if utf8.RuneCountInString(word) <= 3 {
 		return word
 	}
 
+	for _, suffix := range invariantSuffixes {
+		if strings.HasSuffix(word, suffix) {
+			return word
+		}
+	}
+
 	// Check each suffix pattern
 	for _, p := range pluralSuffixes {
 		if strings.HasSuffix(word, p.suffix) {
 			candidate := strings.TrimSuffix(word, p.suffix) + p.replace
 			// Don't return empty or very short results
-			if len(candidate) >= 2 {
+			if utf8.RuneCountInString(candidate) >= 2 {
 				return candidate
 			}
 		}
@@ -138,6 +192,36 @@ func (n *RuleBasedNormalizer) depluralize(word string) string {
 	return word
 }
 
+// ParseSynonyms reads ingredient synonyms, one ingredient per line with its other
+// names after a colon: "green onion: scallions, spring onions". Blank lines and
+// lines starting with # are skipped.
+func ParseSynonyms(r io.Reader) (map[string]string, error) {
+	var n RuleBasedNormalizer
+	parsed := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		canonical, aliases, found := strings.Cut(text, ":")
+		canonical = n.normalizeName(canonical)
+		if !found || canonical == "" {
+			return nil, fmt.Errorf("line %d: want \"ingredient: other names\"", line)
+		}
+		for _, alias := range strings.Split(aliases, ",") {
+			if alias = n.normalizeName(alias); alias != "" && alias != canonical {
+				parsed[alias] = canonical
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read synonyms: %w", err)
+	}
+	return parsed, nil
+}
+
 // AreSimilar checks if two normalized ingredients can substitute each other
 func (n *RuleBasedNormalizer) AreSimilar(a, b string) bool {
 	// Normalize both inputs
@@ -154,15 +238,8 @@ func (n *RuleBasedNormalizer) AreSimilar(a, b string) bool {
 		return true
 	}
 
-	// Check substitution groups
-	groupA, okA := n.substitutionIndex[normA]
-	groupB, okB := n.substitutionIndex[normB]
-
-	if okA && okB && groupA == groupB {
-		return true
-	}
-
-	return false
+	// Check the ingredient hierarchy
+	return n.ontology.Related(normA, normB, DefaultSpecificity)
 }
 
 // CommonPantryStaples are ingredients typically found in most kitchens
@@ -188,9 +265,20 @@ var CommonPantryStaples = map[string]bool{
 	"baking powder": true,
 }
 
+// DefaultStaples returns CommonPantryStaples as a sorted list, the staples of users
+// who never chose their own
+func DefaultStaples() []string {
+	staples := make([]string, 0, len(CommonPantryStaples))
+	for staple := range CommonPantryStaples {
+		staples = append(staples, staple)
+	}
+	sort.Strings(staples)
+	return staples
+}
+
 // IsPantryStaple checks if an ingredient is a common pantry staple
 func IsPantryStaple(ingredient string) bool {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 	normalized := normalizer.Normalize(ingredient)
 	return CommonPantryStaples[normalized]
 }
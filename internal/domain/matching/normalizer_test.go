@@ -1,11 +1,12 @@
 package matching
 
 import (
+	"strings"
 	"testing"
 )
 
 func TestRuleBasedNormalizer_Normalize(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 
 	tests := []struct {
 		name  string
@@ -53,6 +54,24 @@ func TestRuleBasedNormalizer_Normalize(t *testing.T) {
 		{"plural berries", "berries", "berry"},
 		{"plural leaves", "leaves", "leaf"},
 		{"plural carrots", "carrots", "carrot"},
+		{"plural peaches", "peaches", "peach"},
+		{"plural olives", "olives", "olive"},
+		{"plural cookies", "cookies", "cookie"},
+		{"plural compound", "cherry tomatoes", "cherry tomato"},
+		{"not plural asparagus", "asparagus", "asparagus"},
+		{"not plural hummus", "hummus", "hummus"},
+
+		// Portuguese plurals
+		{"plural cebolas", "cebolas", "cebola"},
+		{"plural limões", "limões", "limão"},
+		{"plural pães", "pães", "pão"},
+		{"plural nozes", "nozes", "noz"},
+		{"plural cereais", "cereais", "cereal"},
+		{"plural vagens", "vagens", "vagem"},
+
+		// Synonyms
+		{"synonym scallions", "3 scallions, sliced", "green onion"},
+		{"synonym aubergine", "aubergine", "eggplant"},
 
 		// Edge cases
 		{"empty string", "", ""},
@@ -76,7 +95,7 @@ func TestRuleBasedNormalizer_Normalize(t *testing.T) {
 }
 
 func TestRuleBasedNormalizer_AreSimilar(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 
 	tests := []struct {
 		name string
@@ -171,33 +190,28 @@ func TestIsPantryStaple(t *testing.T) {
 }
 
 func TestNewRuleBasedNormalizer(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 
 	if normalizer == nil {
-		t.Fatal("NewRuleBasedNormalizer() returned nil")
-	}
-
-	if normalizer.substitutionIndex == nil {
-		t.Error("substitutionIndex is nil")
+		t.Fatal("NewRuleBasedNormalizer(nil) returned nil")
 	}
 
-	// Check that substitution index is populated
-	if len(normalizer.substitutionIndex) == 0 {
-		t.Error("substitutionIndex is empty")
+	if normalizer.ontology == nil {
+		t.Fatal("ontology is nil")
 	}
 
 	// Verify specific entries
-	if _, ok := normalizer.substitutionIndex["milk"]; !ok {
-		t.Error("milk not found in substitution index")
+	if len(normalizer.ontology.Ancestors("milk")) == 0 {
+		t.Error("milk not found in ontology")
 	}
 
-	if _, ok := normalizer.substitutionIndex["parmesan"]; !ok {
-		t.Error("parmesan not found in substitution index")
+	if len(normalizer.ontology.Ancestors("parmesan")) == 0 {
+		t.Error("parmesan not found in ontology")
 	}
 }
 
 func TestDepluralize(t *testing.T) {
-	normalizer := NewRuleBasedNormalizer()
+	normalizer := NewRuleBasedNormalizer(nil)
 
 	tests := []struct {
 		name  string
@@ -222,3 +236,48 @@ func TestDepluralize(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSynonyms(t *testing.T) {
+	input := `# my kitchen
+green onion: scallions, cebolinha
+Pimentão: bell peppers
+
+`
+	synonyms, err := ParseSynonyms(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseSynonyms() error = %v", err)
+	}
+
+	want := map[string]string{"scallion": "green onion", "cebolinha": "green onion", "bell pepper": "pimentão"}
+	if len(synonyms) != len(want) {
+		t.Errorf("ParseSynonyms() = %v, want %v", synonyms, want)
+	}
+	for alias, canonical := range want {
+		if synonyms[alias] != canonical {
+			t.Errorf("synonyms[%q] = %q, want %q", alias, synonyms[alias], canonical)
+		}
+	}
+
+	if _, err := ParseSynonyms(strings.NewReader("just a line")); err == nil {
+		t.Error("ParseSynonyms() accepted a line without a colon")
+	}
+}
+
+func TestNewRuleBasedNormalizer_ExtraSynonyms(t *testing.T) {
+	normalizer := NewRuleBasedNormalizer(map[string]string{"Mandioquinhas": "arracacha"})
+
+	if got := normalizer.Normalize("2 mandioquinhas"); got != "arracacha" {
+		t.Errorf("Normalize() = %q, want the extra synonym", got)
+	}
+	if got := normalizer.Normalize("spring onions"); got != "green onion" {
+		t.Errorf("Normalize() = %q, want the built-in synonyms kept", got)
+	}
+	if !normalizer.AreSimilar("mandioquinha", "arracacha") {
+		t.Error("AreSimilar() = false for synonyms")
+	}
+
+	// Other normalizers only resolve the built-in synonyms
+	if got := NewRuleBasedNormalizer(nil).Normalize("mandioquinhas"); got != "mandioquinha" {
+		t.Errorf("Normalize() without extra synonyms = %q, want mandioquinha", got)
+	}
+}
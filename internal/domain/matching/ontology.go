@@ -0,0 +1,149 @@
+package matching
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultSpecificity is how many levels up the ingredient hierarchy an ingredient
+// may stand in for another by default: pasta for linguine, or spaghetti for linguine
+const DefaultSpecificity = 1
+
+// Ontology arranges ingredients from specific to general (cheddar -> cheese -> dairy,
+// linguine -> pasta) so an ingredient the user has can stand in for a related one a
+// recipe asks for. Categories such as dairy organize ingredients without making them
+// substitutes: milk and cheese are both dairy, but one does not replace the other.
+type Ontology struct {
+	parents    map[string]string // normalized ingredient -> its more general kind
+	categories map[string]bool
+}
+
+// NewOntology builds an ontology from the more specific kinds of each ingredient.
+// Names are normalized; categories are ingredients that never stand in for another.
+func NewOntology(kinds map[string][]string, categories []string) *Ontology {
+	var n RuleBasedNormalizer
+	o := &Ontology{
+		parents:    make(map[string]string),
+		categories: make(map[string]bool, len(categories)),
+	}
+	for _, category := range categories {
+		o.categories[n.Normalize(category)] = true
+	}
+
+	generals := make([]string, 0, len(kinds))
+	for general := range kinds {
+		generals = append(generals, general)
+	}
+	sort.Strings(generals)
+
+	for _, general := range generals {
+		parent := n.Normalize(general)
+		for _, kind := range kinds[general] {
+			// "whole milk" normalizes to "milk": it cannot be its own kind
+			if child := n.Normalize(kind); child != "" && child != parent {
+				o.parents[child] = parent
+			}
+		}
+	}
+	return o
+}
+
+// DefaultOntology returns the built-in ingredient hierarchy
+var DefaultOntology = sync.OnceValue(func() *Ontology {
+	return NewOntology(ingredientKinds, ingredientCategories)
+})
+
+// ingredientCategories group ingredients without making them substitutes
+var ingredientCategories = []string{"dairy", "meat", "seafood", "grain", "vegetable", "herb"}
+
+// ingredientKinds lists the more specific kinds of each ingredient
+var ingredientKinds = map[string][]string{
+	"dairy":          {"milk", "butter", "cheese", "cultured dairy"},
+	"milk":           {"whole milk", "skim milk", "half-and-half", "cream"},
+	"cream":          {"heavy cream", "whipping cream", "double cream", "single cream"},
+	"butter":         {"unsalted butter", "salted butter", "margarine", "ghee"},
+	"cheese":         {"hard cheese", "cheddar", "monterey jack", "colby", "american cheese", "gouda", "gruyere", "swiss cheese", "mozzarella", "provolone", "fontina", "feta", "ricotta", "goat cheese", "cream cheese"},
+	"hard cheese":    {"parmesan", "romano", "pecorino", "grana padano", "asiago"},
+	"cultured dairy": {"sour cream", "yogurt", "greek yogurt", "plain yogurt", "crème fraîche"},
+
+	"meat":      {"poultry", "beef", "pork", "lamb"},
+	"poultry":   {"chicken", "turkey", "duck"},
+	"chicken":   {"chicken breast", "chicken thigh", "chicken leg", "chicken wing"},
+	"beef":      {"steak", "brisket", "beef chuck"},
+	"pork":      {"bacon", "pancetta", "guanciale", "ham", "pork chop"},
+	"seafood":   {"fish", "shellfish"},
+	"fish":      {"salmon", "tuna", "cod", "tilapia"},
+	"shellfish": {"shrimp", "prawn", "crab", "lobster", "mussel", "clam"},
+
+	"grain":      {"pasta", "rice", "flour", "bread", "breadcrumb"},
+	"pasta":      {"spaghetti", "linguine", "fettuccine", "tagliatelle", "penne", "rigatoni", "macaroni", "fusilli", "lasagna", "egg noodle", "noodle"},
+	"noodle":     {"rice noodle", "ramen", "ramen noodle", "udon", "udon noodle", "soba", "soba noodle"},
+	"rice":       {"white rice", "brown rice", "basmati rice", "jasmine rice", "arborio rice"},
+	"flour":      {"all-purpose flour", "bread flour", "cake flour", "wheat flour"},
+	"bread":      {"baguette", "ciabatta", "sourdough", "sandwich bread"},
+	"breadcrumb": {"bread crumb", "panko", "panko breadcrumb"},
+
+	"sweetener": {"sugar", "honey", "maple syrup", "agave", "molasses"},
+	"sugar":     {"brown sugar", "white sugar", "powdered sugar", "caster sugar", "cane sugar"},
+	"oil":       {"olive oil", "vegetable oil", "canola oil", "sunflower oil", "coconut oil", "cooking oil"},
+	"stock":     {"broth", "chicken stock", "chicken broth", "beef stock", "beef broth", "vegetable stock", "vegetable broth", "fish stock"},
+
+	"citrus juice": {"lemon juice", "lime juice", "orange juice"},
+
+	"vegetable":    {"onion", "garlic", "tomato", "bell pepper", "chili pepper", "potato", "lettuce"},
+	"onion":        {"yellow onion", "white onion", "red onion", "shallot", "green onion"},
+	"garlic":       {"garlic clove", "garlic powder"},
+	"tomato":       {"cherry tomato", "roma tomato", "plum tomato"},
+	"bell pepper":  {"red bell pepper", "green bell pepper", "yellow bell pepper"},
+	"chili pepper": {"jalapeño", "serrano", "bird's eye chili"},
+	"potato":       {"russet potato", "yukon gold potato", "new potato"},
+	"lettuce":      {"romaine lettuce", "iceberg lettuce", "butter lettuce"},
+
+	"herb":    {"basil", "oregano", "thyme", "parsley", "cilantro", "rosemary", "dill", "mint"},
+	"basil":   {"basil leaf", "thai basil"},
+	"parsley": {"flat-leaf parsley", "curly parsley"},
+}
+
+// Ancestors returns the more general kinds of a normalized ingredient, nearest first
+func (o *Ontology) Ancestors(name string) []string {
+	var ancestors []string
+	seen := map[string]bool{name: true}
+	for parent, ok := o.parents[name]; ok && !seen[parent]; parent, ok = o.parents[parent] {
+		seen[parent] = true
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// Covers reports whether a normalized ingredient the user has can be used where a
+// recipe asks for another: the same ingredient, a more specific kind of it (cheddar
+// for cheese), or one sharing a kind at most specificity levels up from both (pasta
+// for linguine, spaghetti for linguine at 1). Categories never stand in for anything.
+func (o *Ontology) Covers(have, need string, specificity int) bool {
+	if have == need {
+		return true
+	}
+
+	levels := map[string]int{have: 0}
+	for i, ancestor := range o.Ancestors(have) {
+		if ancestor == need {
+			return true
+		}
+		levels[ancestor] = i + 1
+	}
+
+	for i, ancestor := range append([]string{need}, o.Ancestors(need)...) {
+		if i > specificity {
+			break
+		}
+		if level, ok := levels[ancestor]; ok && level <= specificity && !o.categories[ancestor] {
+			return true
+		}
+	}
+	return false
+}
+
+// Related reports whether either of two normalized ingredients covers the other
+func (o *Ontology) Related(a, b string, specificity int) bool {
+	return o.Covers(a, b, specificity) || o.Covers(b, a, specificity)
+}
@@ -0,0 +1,66 @@
+package matching
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOntology_Ancestors(t *testing.T) {
+	o := DefaultOntology()
+
+	if got, want := o.Ancestors("cheddar"), []string{"cheese", "dairy"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ancestors(cheddar) = %v, want %v", got, want)
+	}
+	if got := o.Ancestors("water"); len(got) != 0 {
+		t.Errorf("Ancestors(water) = %v, want none", got)
+	}
+}
+
+func TestOntology_Covers(t *testing.T) {
+	o := DefaultOntology()
+
+	tests := []struct {
+		have, need  string
+		specificity int
+		want        bool
+	}{
+		{"cheddar", "cheddar", 0, true},
+		{"cheddar", "cheese", 0, true},  // a more specific kind always will do
+		{"parmesan", "cheese", 0, true}, // however far down
+		{"cheese", "cheddar", 0, false}, // the general one needs a specificity
+		{"cheese", "cheddar", 1, true},
+		{"pasta", "linguine", 1, true},
+		{"pasta", "ramen", 1, false}, // ramen -> noodle -> pasta
+		{"pasta", "ramen", 2, true},
+		{"spaghetti", "linguine", 1, true}, // both pasta
+		{"spaghetti", "linguine", 0, false},
+		{"cheddar", "parmesan", 1, false}, // parmesan is a hard cheese
+		{"cheddar", "parmesan", 2, true},
+		{"milk", "cheddar", 3, false}, // dairy is only a category
+		{"dairy", "milk", 3, false},
+		{"beef", "turkey", 2, false},
+	}
+
+	for _, tt := range tests {
+		if got := o.Covers(tt.have, tt.need, tt.specificity); got != tt.want {
+			t.Errorf("Covers(%q, %q, %d) = %v, want %v", tt.have, tt.need, tt.specificity, got, tt.want)
+		}
+	}
+}
+
+func TestNewOntology(t *testing.T) {
+	o := NewOntology(map[string][]string{
+		"Noodles": {"Udon", "soba noodles"},
+		"milk":    {"whole milk"}, // normalizes to milk itself
+	}, []string{"noodles"})
+
+	if got := o.Ancestors("soba noodle"); !reflect.DeepEqual(got, []string{"noodle"}) {
+		t.Errorf("Ancestors(soba noodle) = %v, want [noodle]", got)
+	}
+	if len(o.Ancestors("milk")) != 0 {
+		t.Error("milk became its own kind")
+	}
+	if o.Covers("udon", "soba noodle", 1) {
+		t.Error("udon covers soba through a category")
+	}
+}
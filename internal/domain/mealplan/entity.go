@@ -0,0 +1,178 @@
+package mealplan
+
+import (
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// RecipeID represents a unique recipe identifier
+type RecipeID = shared.ID
+
+// Entry is a recipe planned for one day of the week
+type Entry struct {
+	Day      time.Weekday
+	RecipeID RecipeID
+	Servings int // 0 means the recipe's own servings
+}
+
+// MealPlan holds the recipes a user plans to cook in one week (Aggregate Root).
+// Weeks start on Monday.
+type MealPlan struct {
+	userID    UserID
+	weekStart time.Time
+	entries   []Entry
+	updatedAt shared.Timestamp
+}
+
+// NewMealPlan creates an empty meal plan for the week containing day
+func NewMealPlan(userID UserID, day time.Time) (*MealPlan, error) {
+	if userID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &MealPlan{
+		userID:    userID,
+		weekStart: WeekStart(day),
+		entries:   []Entry{},
+		updatedAt: shared.NewTimestamp(),
+	}, nil
+}
+
+// PlanData contains data for reconstructing a meal plan from storage
+type PlanData struct {
+	UserID    UserID
+	WeekStart time.Time
+	Entries   []Entry
+	UpdatedAt time.Time
+}
+
+// ReconstructMealPlan reconstructs a meal plan from stored data (for repository)
+func ReconstructMealPlan(data PlanData) *MealPlan {
+	entries := data.Entries
+	if entries == nil {
+		entries = []Entry{}
+	}
+	return &MealPlan{
+		userID:    data.UserID,
+		weekStart: WeekStart(data.WeekStart),
+		entries:   entries,
+		updatedAt: shared.NewTimestampFromTime(data.UpdatedAt),
+	}
+}
+
+// WeekStart returns midnight of the Monday starting the week that contains t
+func WeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Monday = 0
+	y, m, d := t.AddDate(0, 0, -offset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// UserID returns the owner of the plan
+func (p *MealPlan) UserID() UserID {
+	return p.userID
+}
+
+// WeekStart returns the Monday the plan starts on
+func (p *MealPlan) WeekStart() time.Time {
+	return p.weekStart
+}
+
+// Entries returns the planned recipes, ordered Monday to Sunday
+func (p *MealPlan) Entries() []Entry {
+	return p.entries
+}
+
+// UpdatedAt returns the last update timestamp
+func (p *MealPlan) UpdatedAt() time.Time {
+	return p.updatedAt.Time()
+}
+
+// IsEmpty reports whether nothing is planned
+func (p *MealPlan) IsEmpty() bool {
+	return len(p.entries) == 0
+}
+
+// Add plans a recipe for a day. A day can hold several recipes.
+func (p *MealPlan) Add(day time.Weekday, recipeID RecipeID, servings int) error {
+	if recipeID.IsEmpty() || servings < 0 {
+		return shared.ErrInvalidInput
+	}
+
+	entry := Entry{Day: day, RecipeID: recipeID, Servings: servings}
+
+	// Keep entries ordered Monday to Sunday, in insertion order within a day
+	pos := len(p.entries)
+	for i, e := range p.entries {
+		if dayIndex(e.Day) > dayIndex(day) {
+			pos = i
+			break
+		}
+	}
+	p.entries = append(p.entries, Entry{})
+	copy(p.entries[pos+1:], p.entries[pos:])
+	p.entries[pos] = entry
+
+	p.updatedAt = shared.NewTimestamp()
+	return nil
+}
+
+// RemoveDay removes everything planned for a day and returns how many entries were removed
+func (p *MealPlan) RemoveDay(day time.Weekday) int {
+	kept := p.entries[:0]
+	for _, e := range p.entries {
+		if e.Day != day {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(p.entries) - len(kept)
+	p.entries = kept
+	if removed > 0 {
+		p.updatedAt = shared.NewTimestamp()
+	}
+	return removed
+}
+
+// Clear removes all planned recipes
+func (p *MealPlan) Clear() {
+	p.entries = []Entry{}
+	p.updatedAt = shared.NewTimestamp()
+}
+
+// Clone returns a copy of the plan that shares no mutable state with the original
+func (p *MealPlan) Clone() *MealPlan {
+	cp := *p
+	cp.entries = append([]Entry{}, p.entries...)
+	return &cp
+}
+
+// ParseDay parses an English or Portuguese weekday name or abbreviation
+func ParseDay(s string) (time.Weekday, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "monday", "mon", "segunda", "seg":
+		return time.Monday, true
+	case "tuesday", "tue", "tues", "terça", "terca", "ter":
+		return time.Tuesday, true
+	case "wednesday", "wed", "quarta", "qua":
+		return time.Wednesday, true
+	case "thursday", "thu", "thurs", "quinta", "qui":
+		return time.Thursday, true
+	case "friday", "fri", "sexta", "sex":
+		return time.Friday, true
+	case "saturday", "sat", "sábado", "sabado", "sab":
+		return time.Saturday, true
+	case "sunday", "sun", "domingo", "dom":
+		return time.Sunday, true
+	default:
+		return time.Sunday, false
+	}
+}
+
+// dayIndex orders weekdays starting on Monday
+func dayIndex(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
@@ -0,0 +1,76 @@
+package mealplan
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		day  time.Time
+	}{
+		{"monday", monday},
+		{"wednesday afternoon", time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC)},
+		{"sunday night", time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WeekStart(tt.day); !got.Equal(monday) {
+				t.Errorf("WeekStart(%v) = %v, want %v", tt.day, got, monday)
+			}
+		})
+	}
+}
+
+func TestMealPlan_AddKeepsWeekOrder(t *testing.T) {
+	plan, err := NewMealPlan("user-1", time.Now())
+	if err != nil {
+		t.Fatalf("NewMealPlan() error = %v", err)
+	}
+
+	_ = plan.Add(time.Sunday, "r1", 0)
+	_ = plan.Add(time.Monday, "r2", 0)
+	_ = plan.Add(time.Wednesday, "r3", 4)
+	_ = plan.Add(time.Monday, "r4", 0)
+
+	want := []RecipeID{"r2", "r4", "r3", "r1"}
+	for i, e := range plan.Entries() {
+		if e.RecipeID != want[i] {
+			t.Fatalf("entry %d = %s, want %s (entries %+v)", i, e.RecipeID, want[i], plan.Entries())
+		}
+	}
+
+	if removed := plan.RemoveDay(time.Monday); removed != 2 {
+		t.Errorf("RemoveDay(Monday) = %d, want 2", removed)
+	}
+	if len(plan.Entries()) != 2 {
+		t.Errorf("Entries() after RemoveDay = %d, want 2", len(plan.Entries()))
+	}
+
+	if err := plan.Add(time.Friday, "", 0); err == nil {
+		t.Error("Add() with empty recipe ID should fail")
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	tests := map[string]time.Weekday{
+		"Monday": time.Monday,
+		"tue":    time.Tuesday,
+		"quarta": time.Wednesday,
+		"sábado": time.Saturday,
+		"SUN":    time.Sunday,
+	}
+	for input, want := range tests {
+		if got, ok := ParseDay(input); !ok || got != want {
+			t.Errorf("ParseDay(%q) = %v, %v; want %v", input, got, ok, want)
+		}
+	}
+
+	if _, ok := ParseDay("someday"); ok {
+		t.Error("ParseDay(someday) should fail")
+	}
+}
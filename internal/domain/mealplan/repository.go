@@ -0,0 +1,16 @@
+package mealplan
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for meal plan persistence (Port)
+type Repository interface {
+	// FindByWeek retrieves the plan of a user for the week starting on weekStart.
+	// It returns shared.ErrMealPlanNotFound if nothing was planned yet.
+	FindByWeek(ctx context.Context, userID UserID, weekStart time.Time) (*MealPlan, error)
+
+	// Save persists a meal plan, replacing any plan for the same user and week
+	Save(ctx context.Context, plan *MealPlan) error
+}
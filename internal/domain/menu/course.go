@@ -0,0 +1,87 @@
+package menu
+
+import (
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+)
+
+// Course is one part of a multi-course menu
+type Course string
+
+const (
+	CourseAppetizer Course = "appetizer"
+	CourseMain      Course = "main"
+	CourseSide      Course = "side"
+	CourseDessert   Course = "dessert"
+)
+
+// AllCourses returns all courses in serving order
+func AllCourses() []Course {
+	return []Course{CourseAppetizer, CourseMain, CourseSide, CourseDessert}
+}
+
+// String returns the string representation of the course
+func (c Course) String() string {
+	return string(c)
+}
+
+// IsValid checks if the course is valid
+func (c Course) IsValid() bool {
+	switch c {
+	case CourseAppetizer, CourseMain, CourseSide, CourseDessert:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseCourse parses a string into a Course.
+// Returns the course and a boolean indicating if it's valid
+func ParseCourse(s string) (Course, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case "appetizer", "appetizers", "starter", "starters", "entrada", "entradas", "aperitivo", "aperitivos":
+		return CourseAppetizer, true
+	case "main", "mains", "main course", "prato principal", "principal":
+		return CourseMain, true
+	case "side", "sides", "side dish", "acompanhamento", "acompanhamentos":
+		return CourseSide, true
+	case "dessert", "desserts", "sobremesa", "sobremesas":
+		return CourseDessert, true
+	default:
+		return "", false
+	}
+}
+
+// CourseForCategory returns the course a recipe category is usually served as.
+// Returns false for categories that don't belong on a dinner menu (drinks, breakfast, sauces).
+func CourseForCategory(category recipe.Category) (Course, bool) {
+	switch category {
+	case recipe.CategoryAppetizers:
+		return CourseAppetizer, true
+	case recipe.CategoryMeat, recipe.CategorySeafood, recipe.CategoryPasta, recipe.CategoryVegetarian:
+		return CourseMain, true
+	case recipe.CategorySalads, recipe.CategorySoups, recipe.CategoryRice, recipe.CategoryBread:
+		return CourseSide, true
+	case recipe.CategoryDesserts:
+		return CourseDessert, true
+	default:
+		return "", false
+	}
+}
+
+// ServeOffset returns when a course is served, relative to the start of the meal.
+// Mains and sides come out together after the appetizer; dessert follows the main.
+func ServeOffset(course Course) time.Duration {
+	switch course {
+	case CourseMain, CourseSide:
+		return 30 * time.Minute
+	case CourseDessert:
+		return 75 * time.Minute
+	default:
+		return 0
+	}
+}
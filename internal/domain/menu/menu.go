@@ -0,0 +1,164 @@
+// Package menu plans multi-course menus for an occasion and schedules their cooking.
+package menu
+
+import (
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// RecipeID represents a unique recipe identifier
+type RecipeID = shared.ID
+
+// Dish is a recipe from the user's collection, or a suggested dish, offered for a course
+type Dish struct {
+	Course     Course
+	RecipeID   RecipeID // empty for suggested dishes
+	Title      string
+	Servings   int // servings the recipe makes, 0 if unknown
+	PrepTime   time.Duration
+	CookTime   time.Duration
+	Appliances []Appliance
+	Suggested  bool // generated suggestion, not a saved recipe
+}
+
+// Uses reports whether the dish needs an appliance
+func (d Dish) Uses(appliance Appliance) bool {
+	for _, a := range d.Appliances {
+		if a == appliance {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryAppliance returns the appliance the dish is cooked in, preferring the oven
+func (d Dish) primaryAppliance() Appliance {
+	if d.Uses(ApplianceOven) {
+		return ApplianceOven
+	}
+	if d.Uses(ApplianceStove) {
+		return ApplianceStove
+	}
+	return ""
+}
+
+// Menu is a multi-course menu being planned for an occasion.
+// Each course has a list of options, one of which is selected.
+type Menu struct {
+	occasion string
+	guests   int
+	serveAt  time.Time
+	options  map[Course][]Dish
+	selected map[Course]int
+}
+
+// NewMenu creates an empty menu for an occasion, with the meal starting at serveAt
+func NewMenu(occasion string, guests int, serveAt time.Time) (*Menu, error) {
+	occasion = strings.TrimSpace(occasion)
+	if occasion == "" || guests <= 0 {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &Menu{
+		occasion: occasion,
+		guests:   guests,
+		serveAt:  serveAt,
+		options:  make(map[Course][]Dish),
+		selected: make(map[Course]int),
+	}, nil
+}
+
+// Occasion returns what the menu is for
+func (m *Menu) Occasion() string {
+	return m.occasion
+}
+
+// Guests returns the number of people the menu serves
+func (m *Menu) Guests() int {
+	return m.guests
+}
+
+// ServeAt returns when the meal starts
+func (m *Menu) ServeAt() time.Time {
+	return m.serveAt
+}
+
+// AddOption offers a dish for its course. The first option of a course is selected.
+func (m *Menu) AddOption(dish Dish) error {
+	if !dish.Course.IsValid() || strings.TrimSpace(dish.Title) == "" {
+		return shared.ErrInvalidInput
+	}
+	m.options[dish.Course] = append(m.options[dish.Course], dish)
+	return nil
+}
+
+// Options returns the dishes offered for a course
+func (m *Menu) Options(course Course) []Dish {
+	return m.options[course]
+}
+
+// Selected returns the selected dish of every course that has options, in serving order
+func (m *Menu) Selected() []Dish {
+	var dishes []Dish
+	for _, course := range AllCourses() {
+		if options := m.options[course]; len(options) > 0 {
+			dishes = append(dishes, options[m.selected[course]])
+		}
+	}
+	return dishes
+}
+
+// MissingCourses returns the courses nothing is offered for
+func (m *Menu) MissingCourses() []Course {
+	var missing []Course
+	for _, course := range AllCourses() {
+		if len(m.options[course]) == 0 {
+			missing = append(missing, course)
+		}
+	}
+	return missing
+}
+
+// Swap selects the next option of a course, wrapping around.
+// Returns false if the course has fewer than two options.
+func (m *Menu) Swap(course Course) bool {
+	options := m.options[course]
+	if len(options) < 2 {
+		return false
+	}
+	m.selected[course] = (m.selected[course] + 1) % len(options)
+	return true
+}
+
+// Scale returns how much a dish must be scaled to feed the guests, 1 if its servings are unknown
+func (m *Menu) Scale(dish Dish) float64 {
+	if dish.Servings <= 0 {
+		return 1
+	}
+	return float64(m.guests) / float64(dish.Servings)
+}
+
+// Timeline schedules the selected dishes
+func (m *Menu) Timeline(burners int) *Timeline {
+	return PlanTimeline(m.Selected(), m.serveAt, burners)
+}
+
+// Clone returns a copy of the menu that can be changed independently
+func (m *Menu) Clone() *Menu {
+	clone := &Menu{
+		occasion: m.occasion,
+		guests:   m.guests,
+		serveAt:  m.serveAt,
+		options:  make(map[Course][]Dish, len(m.options)),
+		selected: make(map[Course]int, len(m.selected)),
+	}
+	for course, options := range m.options {
+		clone.options[course] = append([]Dish(nil), options...)
+	}
+	for course, index := range m.selected {
+		clone.selected[course] = index
+	}
+	return clone
+}
@@ -0,0 +1,81 @@
+package menu
+
+import (
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+)
+
+func TestCourseForCategory(t *testing.T) {
+	tests := []struct {
+		category recipe.Category
+		want     Course
+		ok       bool
+	}{
+		{recipe.CategoryAppetizers, CourseAppetizer, true},
+		{recipe.CategoryMeat, CourseMain, true},
+		{recipe.CategoryPasta, CourseMain, true},
+		{recipe.CategorySalads, CourseSide, true},
+		{recipe.CategoryDesserts, CourseDessert, true},
+		{recipe.CategoryBeverages, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.category), func(t *testing.T) {
+			got, ok := CourseForCategory(tt.category)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("CourseForCategory(%q) = %q, %v, want %q, %v", tt.category, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestMenu_SelectAndSwap(t *testing.T) {
+	m, err := NewMenu("Christmas dinner", 8, time.Now())
+	if err != nil {
+		t.Fatalf("NewMenu() error = %v", err)
+	}
+
+	_ = m.AddOption(Dish{Course: CourseDessert, Title: "Pudding"})
+	_ = m.AddOption(Dish{Course: CourseMain, Title: "Turkey", Servings: 4})
+	_ = m.AddOption(Dish{Course: CourseMain, Title: "Ham"})
+
+	selected := m.Selected()
+	if len(selected) != 2 || selected[0].Title != "Turkey" || selected[1].Title != "Pudding" {
+		t.Fatalf("Selected() = %+v, want Turkey then Pudding", selected)
+	}
+
+	if got := m.Scale(selected[0]); got != 2 {
+		t.Errorf("Scale(Turkey) = %v, want 2", got)
+	}
+
+	clone := m.Clone()
+	if !m.Swap(CourseMain) {
+		t.Fatal("Swap(main) = false, want true")
+	}
+	if got := m.Selected()[0].Title; got != "Ham" {
+		t.Errorf("after Swap(main) selected %q, want Ham", got)
+	}
+	if got := clone.Selected()[0].Title; got != "Turkey" {
+		t.Errorf("clone selected %q after swapping the original, want Turkey", got)
+	}
+
+	if m.Swap(CourseDessert) {
+		t.Error("Swap(dessert) with one option = true, want false")
+	}
+
+	missing := m.MissingCourses()
+	if len(missing) != 2 || missing[0] != CourseAppetizer || missing[1] != CourseSide {
+		t.Errorf("MissingCourses() = %v, want [appetizer side]", missing)
+	}
+}
+
+func TestNewMenu_Invalid(t *testing.T) {
+	if _, err := NewMenu("", 4, time.Now()); err == nil {
+		t.Error("NewMenu() with empty occasion succeeded, want error")
+	}
+	if _, err := NewMenu("Dinner", 0, time.Now()); err == nil {
+		t.Error("NewMenu() with no guests succeeded, want error")
+	}
+}
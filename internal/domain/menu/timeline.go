@@ -0,0 +1,178 @@
+package menu
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Appliance is a piece of kitchen equipment dishes compete for
+type Appliance string
+
+const (
+	ApplianceOven  Appliance = "oven"
+	ApplianceStove Appliance = "stove"
+)
+
+// DefaultBurners is the number of dishes a home stove can cook at once
+const DefaultBurners = 4
+
+// applianceKeywords match instruction words (English and Portuguese) that show which appliance a dish needs
+var applianceKeywords = map[Appliance]*regexp.Regexp{
+	ApplianceOven:  regexp.MustCompile(`\b(oven|bak|roast|broil|grill|gratin|forno|assa|asse)`),
+	ApplianceStove: regexp.MustCompile(`\b(boil|simmer|fry|fried|saut|sear|skillet|saucepan|pots?\b|pans?\b|wok|stovetop|ferv|refog|frit|panela|frigideira)`),
+}
+
+// DetectAppliances returns the appliances the instructions mention, oven first
+func DetectAppliances(instructions []string) []Appliance {
+	text := strings.ToLower(strings.Join(instructions, "\n"))
+
+	var appliances []Appliance
+	for _, appliance := range []Appliance{ApplianceOven, ApplianceStove} {
+		if applianceKeywords[appliance].MatchString(text) {
+			appliances = append(appliances, appliance)
+		}
+	}
+	return appliances
+}
+
+// StepKind is what happens at a step of the timeline
+type StepKind string
+
+const (
+	StepPrep  StepKind = "prep"  // start preparing a dish
+	StepCook  StepKind = "cook"  // start cooking a dish
+	StepServe StepKind = "serve" // serve a course
+)
+
+// Step is one entry of a cooking timeline
+type Step struct {
+	At        time.Time
+	Kind      StepKind
+	Course    Course
+	Dish      string    // empty for StepServe
+	Appliance Appliance // appliance used by a StepCook, empty if none
+}
+
+// Conflict is a time when more dishes need an appliance than it can hold
+type Conflict struct {
+	Appliance Appliance
+	At        time.Time
+	Dishes    []string
+}
+
+// Timeline is the combined cooking schedule of a menu
+type Timeline struct {
+	Start     time.Time
+	ServeAt   time.Time
+	Steps     []Step
+	CookAhead []string   // dishes cooked early to free the oven, to be kept warm
+	Conflicts []Conflict // stove overloads that could not be scheduled away
+}
+
+// window is the time a dish spends cooking
+type window struct {
+	dish       Dish
+	start, end time.Time
+}
+
+func (w window) overlaps(other window) bool {
+	return w.start.Before(other.end) && other.start.Before(w.end)
+}
+
+// ovenPriority is the order oven dishes get their preferred slot in
+var ovenPriority = map[Course]int{CourseMain: 0, CourseSide: 1, CourseDessert: 2, CourseAppetizer: 3}
+
+// PlanTimeline schedules dishes so every course is ready when it is served, starting
+// the meal at serveAt. Each dish is cooked as late as possible. There is a single oven:
+// dishes that would share it are moved earlier, the main course keeping its slot.
+// Stove dishes beyond the number of burners are reported as conflicts.
+func PlanTimeline(dishes []Dish, serveAt time.Time, burners int) *Timeline {
+	timeline := &Timeline{ServeAt: serveAt}
+
+	ordered := append([]Dish(nil), dishes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ovenPriority[ordered[i].Course] < ovenPriority[ordered[j].Course]
+	})
+
+	var ovenWindows, stoveWindows []window
+	courses := make(map[Course]bool)
+	for _, dish := range ordered {
+		courses[dish.Course] = true
+		readyBy := serveAt.Add(ServeOffset(dish.Course))
+		w := window{dish: dish, start: readyBy.Add(-dish.CookTime), end: readyBy}
+
+		if dish.CookTime > 0 && dish.Uses(ApplianceOven) {
+			w = fitBefore(w, ovenWindows)
+			if w.end.Before(readyBy) {
+				timeline.CookAhead = append(timeline.CookAhead, dish.Title)
+			}
+			ovenWindows = append(ovenWindows, w)
+		} else if dish.CookTime > 0 && dish.Uses(ApplianceStove) {
+			stoveWindows = append(stoveWindows, w)
+		}
+
+		if dish.PrepTime > 0 {
+			timeline.Steps = append(timeline.Steps, Step{At: w.start.Add(-dish.PrepTime), Kind: StepPrep, Course: dish.Course, Dish: dish.Title})
+		}
+		if dish.CookTime > 0 {
+			timeline.Steps = append(timeline.Steps, Step{At: w.start, Kind: StepCook, Course: dish.Course, Dish: dish.Title, Appliance: dish.primaryAppliance()})
+		}
+	}
+
+	for _, course := range AllCourses() {
+		if courses[course] {
+			timeline.Steps = append(timeline.Steps, Step{At: serveAt.Add(ServeOffset(course)), Kind: StepServe, Course: course})
+		}
+	}
+
+	sort.SliceStable(timeline.Steps, func(i, j int) bool {
+		return timeline.Steps[i].At.Before(timeline.Steps[j].At)
+	})
+
+	timeline.Start = serveAt
+	if len(timeline.Steps) > 0 && timeline.Steps[0].At.Before(serveAt) {
+		timeline.Start = timeline.Steps[0].At
+	}
+
+	if conflict, ok := busiest(stoveWindows, burners); ok {
+		conflict.Appliance = ApplianceStove
+		timeline.Conflicts = append(timeline.Conflicts, conflict)
+	}
+
+	return timeline
+}
+
+// fitBefore moves a window earlier until it overlaps none of the taken windows
+func fitBefore(w window, taken []window) window {
+	for moved := true; moved; {
+		moved = false
+		for _, other := range taken {
+			if w.overlaps(other) {
+				length := w.end.Sub(w.start)
+				w.end = other.start
+				w.start = w.end.Add(-length)
+				moved = true
+			}
+		}
+	}
+	return w
+}
+
+// busiest returns the moment most windows overlap, if that is more than capacity
+func busiest(windows []window, capacity int) (Conflict, bool) {
+	var worst Conflict
+	for _, w := range windows {
+		var dishes []string
+		for _, other := range windows {
+			if !w.start.Before(other.start) && w.start.Before(other.end) {
+				dishes = append(dishes, other.dish.Title)
+			}
+		}
+		if len(dishes) > capacity && len(dishes) > len(worst.Dishes) {
+			worst = Conflict{At: w.start, Dishes: dishes}
+		}
+	}
+	return worst, len(worst.Dishes) > 0
+}
@@ -0,0 +1,98 @@
+package menu
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDetectAppliances(t *testing.T) {
+	tests := []struct {
+		name         string
+		instructions []string
+		want         []Appliance
+	}{
+		{"oven", []string{"Roast the turkey for 3 hours."}, []Appliance{ApplianceOven}},
+		{"stove", []string{"Boil the potatoes.", "Mash with butter."}, []Appliance{ApplianceStove}},
+		{"both", []string{"Sear the beef in a pan.", "Finish in the oven."}, []Appliance{ApplianceOven, ApplianceStove}},
+		{"portuguese", []string{"Leve ao forno por 40 minutos."}, []Appliance{ApplianceOven}},
+		{"none", []string{"Mash the potatoes.", "Toss the salad with the dressing."}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectAppliances(tt.instructions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectAppliances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanTimeline_OvenContention(t *testing.T) {
+	serveAt := time.Date(2026, 12, 24, 19, 0, 0, 0, time.UTC)
+	oven := []Appliance{ApplianceOven}
+
+	dishes := []Dish{
+		{Course: CourseSide, Title: "Roast Potatoes", CookTime: time.Hour, Appliances: oven},
+		{Course: CourseMain, Title: "Turkey", PrepTime: 30 * time.Minute, CookTime: 3 * time.Hour, Appliances: oven},
+		{Course: CourseDessert, Title: "Pudding", CookTime: 45 * time.Minute, Appliances: oven},
+	}
+
+	timeline := PlanTimeline(dishes, serveAt, DefaultBurners)
+
+	// Mains and sides are served at 19:30, so the turkey keeps the oven until then
+	// and the potatoes go in first. The pudding bakes during the main course.
+	cookAt := make(map[string]time.Time)
+	for _, step := range timeline.Steps {
+		if step.Kind == StepCook {
+			cookAt[step.Dish] = step.At
+		}
+	}
+
+	want := map[string]time.Time{
+		"Turkey":         serveAt.Add(-150 * time.Minute),
+		"Roast Potatoes": serveAt.Add(-210 * time.Minute),
+		"Pudding":        serveAt.Add(30 * time.Minute),
+	}
+	if !reflect.DeepEqual(cookAt, want) {
+		t.Errorf("cook times = %v, want %v", cookAt, want)
+	}
+
+	if !reflect.DeepEqual(timeline.CookAhead, []string{"Roast Potatoes"}) {
+		t.Errorf("CookAhead = %v, want [Roast Potatoes]", timeline.CookAhead)
+	}
+
+	if !timeline.Start.Equal(serveAt.Add(-210 * time.Minute)) {
+		t.Errorf("Start = %v, want 15:30", timeline.Start)
+	}
+
+	for i := 1; i < len(timeline.Steps); i++ {
+		if timeline.Steps[i].At.Before(timeline.Steps[i-1].At) {
+			t.Fatalf("steps out of order: %+v", timeline.Steps)
+		}
+	}
+}
+
+func TestPlanTimeline_StoveConflict(t *testing.T) {
+	serveAt := time.Date(2026, 12, 24, 19, 0, 0, 0, time.UTC)
+	stove := []Appliance{ApplianceStove}
+
+	dishes := []Dish{
+		{Course: CourseMain, Title: "Risotto", CookTime: 30 * time.Minute, Appliances: stove},
+		{Course: CourseSide, Title: "Green Beans", CookTime: 10 * time.Minute, Appliances: stove},
+		{Course: CourseSide, Title: "Gravy", CookTime: 15 * time.Minute, Appliances: stove},
+	}
+
+	if timeline := PlanTimeline(dishes, serveAt, 3); len(timeline.Conflicts) != 0 {
+		t.Errorf("Conflicts with 3 burners = %+v, want none", timeline.Conflicts)
+	}
+
+	timeline := PlanTimeline(dishes, serveAt, 2)
+	if len(timeline.Conflicts) != 1 {
+		t.Fatalf("Conflicts with 2 burners = %+v, want one", timeline.Conflicts)
+	}
+	conflict := timeline.Conflicts[0]
+	if conflict.Appliance != ApplianceStove || len(conflict.Dishes) != 3 {
+		t.Errorf("conflict = %+v, want all three dishes on the stove", conflict)
+	}
+}
@@ -0,0 +1,162 @@
+// Package moderation decides which recipes may be shown to people other than their owner,
+// such as the guests of a shared collection.
+package moderation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// Verdict is the outcome of a moderation check
+type Verdict string
+
+const (
+	VerdictAllowed Verdict = "allowed" // may be shared
+	VerdictReview  Verdict = "review"  // held back until an admin reviews it
+	VerdictBlocked Verdict = "blocked" // never shared
+)
+
+// severity orders verdicts from the least to the most restrictive
+var severity = map[Verdict]int{
+	VerdictAllowed: 0,
+	VerdictReview:  1,
+	VerdictBlocked: 2,
+}
+
+// ParseVerdict parses a verdict, treating anything unknown as VerdictReview
+func ParseVerdict(s string) Verdict {
+	v := Verdict(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := severity[v]; !ok {
+		return VerdictReview
+	}
+	return v
+}
+
+// Stricter returns the more restrictive of two verdicts
+func Stricter(a, b Verdict) Verdict {
+	if severity[b] > severity[a] {
+		return b
+	}
+	return a
+}
+
+// Decision is the moderation outcome of a recipe, as it was when checked
+type Decision struct {
+	RecipeID    recipe.RecipeID
+	OwnerID     UserID
+	Title       string
+	SourceURL   string
+	Fingerprint string // the recipe content checked, so edited recipes are checked again
+	Verdict     Verdict
+	Reason      string
+	CheckedAt   time.Time
+	Reviewed    bool // decided by an admin rather than the automatic checks
+}
+
+// Covers reports whether the decision was made on the current content of a recipe
+func (d *Decision) Covers(rec *recipe.Recipe) bool {
+	return d.RecipeID == rec.ID() && d.Fingerprint == Fingerprint(rec)
+}
+
+// Fingerprint hashes the content of a recipe shown to others
+func Fingerprint(rec *recipe.Recipe) string {
+	h := sha256.New()
+	h.Write([]byte(rec.Title() + "\n" + rec.Source().URL() + "\n"))
+	for _, ing := range rec.Ingredients() {
+		h.Write([]byte(ing.String() + "\n"))
+	}
+	for _, inst := range rec.Instructions() {
+		h.Write([]byte(inst.Text() + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DefaultShorteners are link shorteners, which hide where a link goes
+var DefaultShorteners = []string{
+	"bit.ly",
+	"cutt.ly",
+	"goo.gl",
+	"is.gd",
+	"ow.ly",
+	"rebrand.ly",
+	"shorturl.at",
+	"t.co",
+	"tinyurl.com",
+}
+
+// LinkPolicy judges the reputation of the links recipes are saved from
+type LinkPolicy struct {
+	blocked    []string
+	shorteners []string
+}
+
+// NewLinkPolicy creates a policy blocking the given domains and their subdomains
+func NewLinkPolicy(blocked []string) *LinkPolicy {
+	return &LinkPolicy{
+		blocked:    normalizeDomains(blocked),
+		shorteners: normalizeDomains(DefaultShorteners),
+	}
+}
+
+// Check judges a link. Links of blocked sites are blocked; links that hide where
+// they go, such as shortened links and bare IP addresses, are held for review.
+// Recipes without a link, like those proposed from a photo, pass.
+func (p *LinkPolicy) Check(rawURL string) (Verdict, string) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return VerdictAllowed, ""
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return VerdictReview, "unusual link"
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if matchesDomain(host, p.blocked) {
+		return VerdictBlocked, "blocked site " + host
+	}
+	switch {
+	case u.User != nil:
+		return VerdictReview, "link with credentials"
+	case net.ParseIP(host) != nil:
+		return VerdictReview, "link to a bare IP address"
+	case strings.HasPrefix(host, "xn--") || strings.Contains(host, ".xn--"):
+		return VerdictReview, "internationalized domain " + host
+	case matchesDomain(host, p.shorteners):
+		return VerdictReview, "shortened link"
+	}
+	return VerdictAllowed, ""
+}
+
+// normalizeDomains lowercases domains and drops "www." and empty entries
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "www.")
+		if d != "" {
+			normalized = append(normalized, d)
+		}
+	}
+	return normalized
+}
+
+// matchesDomain checks if host is one of the domains or a subdomain of one
+func matchesDomain(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package moderation
+
+import (
+	"context"
+
+	"receipt-bot/internal/domain/recipe"
+)
+
+// Repository defines the interface for moderation decision persistence (Port)
+type Repository interface {
+	// Save persists a decision, replacing the previous one of the recipe
+	Save(ctx context.Context, d *Decision) error
+
+	// FindByRecipe retrieves the decision of a recipe.
+	// Returns shared.ErrModerationNotFound if the recipe was never checked.
+	FindByRecipe(ctx context.Context, recipeID recipe.RecipeID) (*Decision, error)
+
+	// FindPending retrieves the decisions waiting for an admin review, oldest first
+	FindPending(ctx context.Context) ([]*Decision, error)
+}
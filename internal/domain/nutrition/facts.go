@@ -0,0 +1,72 @@
+package nutrition
+
+import "math"
+
+// Facts holds nutrition values per 100 g (or 100 ml) of a product, or for a given amount once scaled
+type Facts struct {
+	EnergyKcal    float64
+	Protein       float64 // g
+	Carbohydrates float64 // g
+	Sugars        float64 // g
+	Fat           float64 // g
+	SaturatedFat  float64 // g
+	Fiber         float64 // g
+	Salt          float64 // g
+}
+
+// IsEmpty returns true if no value is known
+func (f Facts) IsEmpty() bool {
+	return f == Facts{}
+}
+
+// ForAmount scales per-100 g facts to the given amount in grams (or millilitres)
+func (f Facts) ForAmount(grams float64) Facts {
+	k := grams / 100
+	return Facts{
+		EnergyKcal:    f.EnergyKcal * k,
+		Protein:       f.Protein * k,
+		Carbohydrates: f.Carbohydrates * k,
+		Sugars:        f.Sugars * k,
+		Fat:           f.Fat * k,
+		SaturatedFat:  f.SaturatedFat * k,
+		Fiber:         f.Fiber * k,
+		Salt:          f.Salt * k,
+	}
+}
+
+// Add returns the sum of both facts
+func (f Facts) Add(other Facts) Facts {
+	return Facts{
+		EnergyKcal:    f.EnergyKcal + other.EnergyKcal,
+		Protein:       f.Protein + other.Protein,
+		Carbohydrates: f.Carbohydrates + other.Carbohydrates,
+		Sugars:        f.Sugars + other.Sugars,
+		Fat:           f.Fat + other.Fat,
+		SaturatedFat:  f.SaturatedFat + other.SaturatedFat,
+		Fiber:         f.Fiber + other.Fiber,
+		Salt:          f.Salt + other.Salt,
+	}
+}
+
+// Divide returns the facts split into n equal portions
+func (f Facts) Divide(n int) Facts {
+	if n <= 1 {
+		return f
+	}
+	return f.ForAmount(100 / float64(n)) // i.e. scale by 1/n
+}
+
+// Round returns the facts rounded to one decimal, whole kilocalories
+func (f Facts) Round() Facts {
+	round := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return Facts{
+		EnergyKcal:    math.Round(f.EnergyKcal),
+		Protein:       round(f.Protein),
+		Carbohydrates: round(f.Carbohydrates),
+		Sugars:        round(f.Sugars),
+		Fat:           round(f.Fat),
+		SaturatedFat:  round(f.SaturatedFat),
+		Fiber:         round(f.Fiber),
+		Salt:          round(f.Salt),
+	}
+}
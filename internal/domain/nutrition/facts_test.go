@@ -0,0 +1,47 @@
+package nutrition
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFacts_ForAmountAndDivide(t *testing.T) {
+	per100g := Facts{EnergyKcal: 359, Protein: 13, Carbohydrates: 70.2, Fat: 2}
+
+	total := per100g.ForAmount(200).Add(Facts{EnergyKcal: 10, Salt: 0.5})
+	if total.EnergyKcal != 728 || total.Protein != 26 || total.Salt != 0.5 {
+		t.Errorf("ForAmount().Add() = %+v", total)
+	}
+
+	perServing := total.Divide(4).Round()
+	want := Facts{EnergyKcal: 182, Protein: 6.5, Carbohydrates: 35.1, Fat: 1, Salt: 0.1}
+	if perServing != want {
+		t.Errorf("Divide(4).Round() = %+v, want %+v", perServing, want)
+	}
+
+	if !(Facts{}).IsEmpty() || per100g.IsEmpty() {
+		t.Error("IsEmpty() is wrong")
+	}
+}
+
+func TestProduct_IsStale(t *testing.T) {
+	now := time.Now()
+	if (&Product{FetchedAt: now.Add(-time.Hour)}).IsStale(now) {
+		t.Error("product fetched an hour ago should be fresh")
+	}
+	if !(&Product{FetchedAt: now.Add(-ProductTTL - time.Hour)}).IsStale(now) {
+		t.Error("product older than the TTL should be stale")
+	}
+}
+
+func TestNewPantryProduct(t *testing.T) {
+	if _, err := NewPantryProduct("user-1", "spaghetti (500 g)", "8076800195057"); err != nil {
+		t.Errorf("NewPantryProduct() error = %v", err)
+	}
+	if _, err := NewPantryProduct("user-1", " ", "8076800195057"); err == nil {
+		t.Error("NewPantryProduct() with empty item should fail")
+	}
+	if _, err := NewPantryProduct("", "spaghetti", "8076800195057"); err == nil {
+		t.Error("NewPantryProduct() without user should fail")
+	}
+}
@@ -0,0 +1,114 @@
+package nutrition
+
+import (
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// MaxDailyKcal is the highest daily energy goal accepted, to catch typos like 20000
+const MaxDailyKcal = 10000
+
+// Goals are a user's daily macro targets. Energy, protein, carbohydrates and fat
+// can be tracked; a macro with a target of 0 is not.
+type Goals struct {
+	UserID    UserID
+	Daily     Facts // only EnergyKcal, Protein, Carbohydrates and Fat are used
+	UpdatedAt time.Time
+}
+
+// NewGoals creates a user's daily macro targets, dropping the facts that aren't tracked.
+// Returns shared.ErrInvalidMacroGoal if no target is set or one is out of range.
+func NewGoals(userID UserID, daily Facts, now time.Time) (*Goals, error) {
+	if userID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	daily = Facts{EnergyKcal: daily.EnergyKcal, Protein: daily.Protein, Carbohydrates: daily.Carbohydrates, Fat: daily.Fat}
+	if daily.IsEmpty() || daily.EnergyKcal < 0 || daily.Protein < 0 || daily.Carbohydrates < 0 || daily.Fat < 0 ||
+		daily.EnergyKcal > MaxDailyKcal {
+		return nil, shared.ErrInvalidMacroGoal
+	}
+
+	return &Goals{UserID: userID, Daily: daily, UpdatedAt: now}, nil
+}
+
+// Remaining returns what is left of the targets after the meals, negative for a
+// macro gone over. Macros without a target are left at 0.
+func (g *Goals) Remaining(meals []*Meal) Facts {
+	var eaten Facts
+	for _, m := range meals {
+		eaten = eaten.Add(m.Facts)
+	}
+
+	goal, ate := macros(g.Daily), macros(eaten)
+	var left [4]float64
+	for i := range goal {
+		if goal[i] > 0 {
+			left[i] = goal[i] - ate[i]
+		}
+	}
+	return Facts{EnergyKcal: left[0], Protein: left[1], Carbohydrates: left[2], Fat: left[3]}
+}
+
+// Fits reports whether a serving stays within what is left of every tracked macro
+func (g *Goals) Fits(remaining, serving Facts) bool {
+	goal, left, s := macros(g.Daily), macros(remaining), macros(serving)
+	for i := range goal {
+		if goal[i] > 0 && s[i] > left[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Misfit scores how far a serving is from what is left of the targets: 0 when it
+// takes exactly what is left of every tracked macro, growing with the room it
+// leaves unused and twice as fast with what it goes over, relative to each target
+func (g *Goals) Misfit(remaining, serving Facts) float64 {
+	goal, left, s := macros(g.Daily), macros(remaining), macros(serving)
+	var score float64
+	for i := range goal {
+		if goal[i] <= 0 {
+			continue
+		}
+		diff := (s[i] - left[i]) / goal[i]
+		if diff > 0 {
+			score += 2 * diff
+		} else {
+			score -= diff
+		}
+	}
+	return score
+}
+
+// macros returns the macros goals can track: energy, protein, carbohydrates and fat
+func macros(f Facts) [4]float64 {
+	return [4]float64{f.EnergyKcal, f.Protein, f.Carbohydrates, f.Fat}
+}
+
+// Meal is a serving of a recipe a user ate, logged with its estimated nutrition
+type Meal struct {
+	ID       shared.ID
+	UserID   UserID
+	RecipeID shared.ID
+	Title    string // recipe title when eaten, kept if the recipe is deleted
+	Facts    Facts  // estimated nutrition of what was eaten, empty when unknown
+	EatenAt  time.Time
+}
+
+// NewMeal logs a serving of a recipe eaten at eatenAt
+func NewMeal(userID UserID, recipeID shared.ID, title string, facts Facts, eatenAt time.Time) (*Meal, error) {
+	if userID.IsEmpty() || recipeID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &Meal{
+		ID:       shared.NewID(),
+		UserID:   userID,
+		RecipeID: recipeID,
+		Title:    title,
+		Facts:    facts,
+		EatenAt:  eatenAt,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package nutrition
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestNewGoals(t *testing.T) {
+	now := time.Now()
+
+	goals, err := NewGoals("user-1", Facts{EnergyKcal: 2000, Protein: 150, Salt: 6}, now)
+	if err != nil {
+		t.Fatalf("NewGoals() error = %v", err)
+	}
+	if goals.Daily != (Facts{EnergyKcal: 2000, Protein: 150}) {
+		t.Errorf("Daily = %+v, want only the tracked macros", goals.Daily)
+	}
+
+	for _, daily := range []Facts{{}, {Salt: 6}, {EnergyKcal: -1}, {EnergyKcal: MaxDailyKcal + 1}} {
+		if _, err := NewGoals("user-1", daily, now); !errors.Is(err, shared.ErrInvalidMacroGoal) {
+			t.Errorf("NewGoals(%+v) error = %v, want ErrInvalidMacroGoal", daily, err)
+		}
+	}
+}
+
+func TestGoals_RemainingAndFit(t *testing.T) {
+	goals, _ := NewGoals("user-1", Facts{EnergyKcal: 2000, Protein: 100}, time.Now())
+	lunch := &Meal{Facts: Facts{EnergyKcal: 1500, Protein: 40, Fat: 50}}
+
+	remaining := goals.Remaining([]*Meal{lunch})
+	if remaining != (Facts{EnergyKcal: 500, Protein: 60}) {
+		t.Fatalf("Remaining() = %+v", remaining)
+	}
+
+	light := Facts{EnergyKcal: 450, Protein: 50, Fat: 90}
+	heavy := Facts{EnergyKcal: 900, Protein: 60}
+	if !goals.Fits(remaining, light) {
+		t.Error("Fits() = false for a serving within every tracked macro, want true")
+	}
+	if goals.Fits(remaining, heavy) {
+		t.Error("Fits() = true for a serving over the energy left, want false")
+	}
+	if goals.Misfit(remaining, light) >= goals.Misfit(remaining, heavy) {
+		t.Errorf("Misfit() of a serving that fits = %g, want less than %g", goals.Misfit(remaining, light), goals.Misfit(remaining, heavy))
+	}
+}
@@ -0,0 +1,53 @@
+package nutrition
+
+import (
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// ProductTTL is how long cached product data is trusted before it is fetched again
+const ProductTTL = 30 * 24 * time.Hour
+
+// UserID is the ID of the user owning a pantry product
+type UserID = shared.ID
+
+// Product is the cached product data behind a barcode
+type Product struct {
+	Barcode   string
+	Name      string
+	Brand     string
+	Quantity  string // package size as printed, e.g. "500 g"
+	Facts     *Facts // per 100 g, nil if the product has no nutrition data
+	FetchedAt time.Time
+}
+
+// IsStale reports whether the cached data should be fetched again
+func (p *Product) IsStale(now time.Time) bool {
+	return now.Sub(p.FetchedAt) > ProductTTL
+}
+
+// PantryProduct links an item of a user's pantry to the product it was scanned from
+type PantryProduct struct {
+	UserID   UserID
+	Item     string // pantry item, e.g. "spaghetti (500 g)"
+	Barcode  string
+	LinkedAt time.Time
+}
+
+// NewPantryProduct creates a new link between a pantry item and a product
+func NewPantryProduct(userID UserID, item, barcode string) (*PantryProduct, error) {
+	item = strings.TrimSpace(item)
+	barcode = strings.TrimSpace(barcode)
+	if userID.IsEmpty() || item == "" || barcode == "" {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &PantryProduct{
+		UserID:   userID,
+		Item:     item,
+		Barcode:  barcode,
+		LinkedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,37 @@
+package nutrition
+
+import (
+	"context"
+	"time"
+)
+
+// Repository stores product data, the pantry items identified as products, and
+// users' macro goals and the meals they log (Port)
+type Repository interface {
+	// SaveProduct caches product data by barcode
+	SaveProduct(ctx context.Context, product *Product) error
+
+	// FindProduct returns cached product data, or shared.ErrProductNotFound
+	FindProduct(ctx context.Context, barcode string) (*Product, error)
+
+	// SavePantryProduct links a pantry item to a product, replacing any link for the same barcode
+	SavePantryProduct(ctx context.Context, link *PantryProduct) error
+
+	// FindPantryProducts returns all pantry product links of a user
+	FindPantryProducts(ctx context.Context, userID UserID) ([]*PantryProduct, error)
+
+	// SaveGoals stores a user's daily macro goals, replacing any previous ones
+	SaveGoals(ctx context.Context, goals *Goals) error
+
+	// FindGoals returns a user's daily macro goals, or shared.ErrNoMacroGoals
+	FindGoals(ctx context.Context, userID UserID) (*Goals, error)
+
+	// DeleteGoals removes a user's daily macro goals
+	DeleteGoals(ctx context.Context, userID UserID) error
+
+	// SaveMeal logs a meal
+	SaveMeal(ctx context.Context, meal *Meal) error
+
+	// FindMeals returns the meals a user logged since the given time, oldest first
+	FindMeals(ctx context.Context, userID UserID, since time.Time) ([]*Meal, error)
+}
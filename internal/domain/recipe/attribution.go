@@ -0,0 +1,100 @@
+package recipe
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UnknownAuthor is the author recorded when the scraper could not tell who published
+// the content
+const UnknownAuthor = "Unknown"
+
+// Attribution records who published a recipe's content and where, so exports and
+// shares can credit its creator (Value Object)
+type Attribution struct {
+	Handle       string    // the creator's account on the platform, like "@chefana"
+	CanonicalURL string    // the content's own link, which short or tracking links resolve to
+	PublishedAt  time.Time // zero when the publish date is unknown
+}
+
+// NewAttribution creates an Attribution, adding the "@" handles are written with
+// and dropping a canonical URL that is not an absolute link
+func NewAttribution(handle, canonicalURL string, publishedAt time.Time) Attribution {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	if handle != "" {
+		handle = "@" + handle
+	}
+
+	canonicalURL = strings.TrimSpace(canonicalURL)
+	if parsed, err := url.Parse(canonicalURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		canonicalURL = ""
+	}
+
+	return Attribution{Handle: handle, CanonicalURL: canonicalURL, PublishedAt: publishedAt}
+}
+
+// IsZero reports whether nothing is known about who published the content
+func (a Attribution) IsZero() bool {
+	return a == Attribution{}
+}
+
+// Attribution returns who published the content and where
+func (s Source) Attribution() Attribution {
+	return s.attribution
+}
+
+// WithAttribution returns a copy of the source crediting the content to a
+func (s Source) WithAttribution(a Attribution) Source {
+	s.attribution = a
+	return s
+}
+
+// OriginalURL returns the content's canonical link, or the link it was shared with
+// when the canonical one is unknown
+func (s Source) OriginalURL() string {
+	if s.attribution.CanonicalURL != "" {
+		return s.attribution.CanonicalURL
+	}
+	return s.url
+}
+
+// Creator returns how the content's creator is credited: their name with their
+// handle, like "Ana Silva (@chefana)", either one alone, or "" when neither is known
+func (s Source) Creator() string {
+	author := s.author
+	if author == UnknownAuthor {
+		author = ""
+	}
+	handle := s.attribution.Handle
+
+	switch {
+	case author == "":
+		return handle
+	case handle == "" || NormalizeAuthor(author) == NormalizeAuthor(handle):
+		return author
+	default:
+		return author + " (" + handle + ")"
+	}
+}
+
+// Credit returns the attribution footer of exports and shares, like "Recipe by
+// Ana Silva (@chefana), published 2 March 2024. Original: https://...". Recipes
+// the LLM generated credit no one and return "".
+func (s Source) Credit() string {
+	if s.IsGenerated() || s.url == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	if creator := s.Creator(); creator != "" {
+		sb.WriteString("Recipe by " + creator)
+	} else {
+		sb.WriteString("Recipe")
+	}
+	if !s.attribution.PublishedAt.IsZero() {
+		sb.WriteString(", published " + s.attribution.PublishedAt.Format("2 January 2006"))
+	}
+	sb.WriteString(". Original: " + s.OriginalURL())
+	return sb.String()
+}
@@ -0,0 +1,66 @@
+package recipe
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Cover is an image of the finished dish shown with a recipe whose source has
+// none, stored in blob storage
+type Cover struct {
+	Key         string // blob storage key, see CoverKey
+	AIGenerated bool   // drawn by an image generator rather than photographed
+	CreatedAt   time.Time
+}
+
+// CoverKey returns the blob storage key of a recipe's cover
+func CoverKey(id RecipeID) string {
+	return fmt.Sprintf("covers/%s.png", id)
+}
+
+// Cover returns the recipe's cover image, nil if it has none
+func (r *Recipe) Cover() *Cover {
+	return r.cover
+}
+
+// SetCover sets the recipe's cover image; nil removes it
+func (r *Recipe) SetCover(cover *Cover) {
+	r.cover = cover
+	r.updatedAt = shared.NewTimestamp()
+}
+
+// youTubeID matches the video ID in youtube.com/watch, youtu.be and /shorts/ links
+var youTubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{6,}$`)
+
+// SourceImageURL returns an image of a recipe derived from its source link,
+// or "" when the source has no predictable thumbnail
+func SourceImageURL(sourceURL string) string {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	host = strings.TrimPrefix(host, "m.")
+
+	var id string
+	switch host {
+	case "youtube.com":
+		id = u.Query().Get("v")
+		if after, ok := strings.CutPrefix(u.Path, "/shorts/"); ok {
+			id = after
+		}
+	case "youtu.be":
+		id = strings.TrimPrefix(u.Path, "/")
+	}
+
+	if !youTubeID.MatchString(id) {
+		return ""
+	}
+	return "https://img.youtube.com/vi/" + id + "/hqdefault.jpg"
+}
@@ -0,0 +1,22 @@
+package recipe
+
+import "testing"
+
+func TestSourceImageURL(t *testing.T) {
+	tests := []struct {
+		sourceURL string
+		want      string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "https://img.youtube.com/vi/dQw4w9WgXcQ/hqdefault.jpg"},
+		{"https://youtu.be/dQw4w9WgXcQ", "https://img.youtube.com/vi/dQw4w9WgXcQ/hqdefault.jpg"},
+		{"https://m.youtube.com/shorts/abc123XYZ", "https://img.youtube.com/vi/abc123XYZ/hqdefault.jpg"},
+		{"https://www.instagram.com/reel/abc", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := SourceImageURL(tt.sourceURL); got != tt.want {
+			t.Errorf("SourceImageURL(%q) = %q, want %q", tt.sourceURL, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package recipe
+
+import (
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Difficulty represents how hard a recipe is to make
+type Difficulty string
+
+const (
+	DifficultyEasy   Difficulty = "easy"
+	DifficultyMedium Difficulty = "medium"
+	DifficultyHard   Difficulty = "hard"
+)
+
+// Difficulty scores range from MinDifficultyScore (trivial) to MaxDifficultyScore (demanding)
+const (
+	MinDifficultyScore = 1
+	MaxDifficultyScore = 10
+)
+
+// techniqueKeywords are instruction words (English and Portuguese) that signal a demanding technique
+var techniqueKeywords = []string{
+	"tempering", "emulsif", "flambé", "flambe", "sous vide", "caramelize", "caramelise",
+	"laminat", "proof", "knead", "deep fry", "deep-fry", "julienne", "brunoise", "deglaze",
+	"braise", "confit", "soufflé", "souffle", "meringue", "bain-marie", "water bath", "piping",
+	"fillet the", "debone", "blind bake", "roux", "clarif", "ferment",
+	"emulsion", "flambar", "caramelizar", "sovar", "deglaçar", "merengue", "banho-maria",
+	"desossar", "filetar", "fritura por imersão",
+}
+
+// String returns the string representation of the difficulty
+func (d Difficulty) String() string {
+	return string(d)
+}
+
+// IsValid checks if the difficulty is valid
+func (d Difficulty) IsValid() bool {
+	switch d {
+	case DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return true
+	default:
+		return false
+	}
+}
+
+// AllDifficulties returns all difficulty levels from easiest to hardest
+func AllDifficulties() []Difficulty {
+	return []Difficulty{DifficultyEasy, DifficultyMedium, DifficultyHard}
+}
+
+// ParseDifficulty parses a string into a Difficulty.
+// Returns the difficulty and a boolean indicating if it's valid
+func ParseDifficulty(s string) (Difficulty, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case "easy", "simple", "beginner", "basic", "fácil", "facil", "simples", "iniciante":
+		return DifficultyEasy, true
+	case "medium", "moderate", "intermediate", "médio", "medio", "média", "media", "intermediário", "intermediario":
+		return DifficultyMedium, true
+	case "hard", "difficult", "advanced", "challenging", "difícil", "dificil", "avançado", "avancado":
+		return DifficultyHard, true
+	default:
+		return "", false
+	}
+}
+
+// DifficultyFromScore returns the difficulty level of a score
+func DifficultyFromScore(score int) Difficulty {
+	switch {
+	case score <= 3:
+		return DifficultyEasy
+	case score <= 6:
+		return DifficultyMedium
+	default:
+		return DifficultyHard
+	}
+}
+
+// ScoreDifficulty scores how hard a recipe is from its ingredient count, number of steps,
+// total time and the techniques its instructions mention
+func ScoreDifficulty(ingredients []Ingredient, instructions []Instruction, prepTime, cookTime *time.Duration) int {
+	score := MinDifficultyScore
+
+	switch n := len(ingredients); {
+	case n > 14:
+		score += 3
+	case n > 9:
+		score += 2
+	case n > 5:
+		score += 1
+	}
+
+	switch n := len(instructions); {
+	case n > 8:
+		score += 2
+	case n > 4:
+		score += 1
+	}
+
+	var total time.Duration
+	if prepTime != nil {
+		total += *prepTime
+	}
+	if cookTime != nil {
+		total += *cookTime
+	}
+	switch {
+	case total > 2*time.Hour:
+		score += 3
+	case total > time.Hour:
+		score += 2
+	case total > 30*time.Minute:
+		score += 1
+	}
+
+	techniques := countTechniques(instructions)
+	if techniques > 3 {
+		techniques = 3
+	}
+	score += techniques
+
+	if score > MaxDifficultyScore {
+		score = MaxDifficultyScore
+	}
+	return score
+}
+
+// countTechniques counts the distinct demanding techniques mentioned in the instructions
+func countTechniques(instructions []Instruction) int {
+	var sb strings.Builder
+	for _, inst := range instructions {
+		sb.WriteString(strings.ToLower(inst.Text()))
+		sb.WriteString("\n")
+	}
+	text := sb.String()
+
+	count := 0
+	for _, keyword := range techniqueKeywords {
+		if strings.Contains(text, keyword) {
+			count++
+		}
+	}
+	return count
+}
+
+// DifficultyScore returns the stored difficulty score, computing it from the
+// recipe content for recipes saved before difficulty was scored
+func (r *Recipe) DifficultyScore() int {
+	if r.difficultyScore > 0 {
+		return r.difficultyScore
+	}
+	return ScoreDifficulty(r.ingredients, r.instructions, r.prepTime, r.cookTime)
+}
+
+// Difficulty returns the difficulty level of the recipe
+func (r *Recipe) Difficulty() Difficulty {
+	return DifficultyFromScore(r.DifficultyScore())
+}
+
+// UpdateDifficulty scores the recipe content and stores the result
+func (r *Recipe) UpdateDifficulty() {
+	r.difficultyScore = ScoreDifficulty(r.ingredients, r.instructions, r.prepTime, r.cookTime)
+	r.updatedAt = shared.NewTimestamp()
+}
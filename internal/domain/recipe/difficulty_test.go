@@ -0,0 +1,134 @@
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestParseDifficulty(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      Difficulty
+		wantValid bool
+	}{
+		{"easy", DifficultyEasy, true},
+		{"Beginner", DifficultyEasy, true},
+		{"fácil", DifficultyEasy, true},
+		{"intermediate", DifficultyMedium, true},
+		{"médio", DifficultyMedium, true},
+		{" hard ", DifficultyHard, true},
+		{"difícil", DifficultyHard, true},
+		{"quick", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, valid := ParseDifficulty(tt.input)
+		if got != tt.want || valid != tt.wantValid {
+			t.Errorf("ParseDifficulty(%q) = %q, %v; want %q, %v", tt.input, got, valid, tt.want, tt.wantValid)
+		}
+	}
+}
+
+func TestScoreDifficulty(t *testing.T) {
+	ingredients := func(n int) []Ingredient {
+		list := make([]Ingredient, n)
+		for i := range list {
+			list[i], _ = NewIngredient("ingredient", "1", "", "")
+		}
+		return list
+	}
+	steps := func(texts ...string) []Instruction {
+		list := make([]Instruction, len(texts))
+		for i, text := range texts {
+			list[i], _ = NewInstruction(i+1, text, nil)
+		}
+		return list
+	}
+	minutes := func(m int) *time.Duration {
+		d := time.Duration(m) * time.Minute
+		return &d
+	}
+
+	tests := []struct {
+		name         string
+		ingredients  []Ingredient
+		instructions []Instruction
+		prep, cook   *time.Duration
+		wantScore    int
+		wantLevel    Difficulty
+	}{
+		{
+			name:         "toast",
+			ingredients:  ingredients(2),
+			instructions: steps("Toast the bread", "Spread the butter"),
+			prep:         minutes(2),
+			cook:         minutes(3),
+			wantScore:    1,
+			wantLevel:    DifficultyEasy,
+		},
+		{
+			name:         "weeknight curry",
+			ingredients:  ingredients(8),
+			instructions: steps("Fry the onion", "Add the spices", "Add the chickpeas", "Pour in the coconut milk", "Simmer"),
+			prep:         minutes(10),
+			cook:         minutes(25),
+			wantScore:    4,
+			wantLevel:    DifficultyMedium,
+		},
+		{
+			name:        "croissants",
+			ingredients: ingredients(10),
+			instructions: steps(
+				"Knead the dough", "Let it proof overnight", "Laminate the butter into the dough",
+				"Fold and chill", "Fold and chill again", "Roll out", "Cut triangles", "Shape", "Bake",
+			),
+			prep:      minutes(180),
+			cook:      minutes(20),
+			wantScore: 10,
+			wantLevel: DifficultyHard,
+		},
+		{
+			name:         "unknown times",
+			ingredients:  ingredients(3),
+			instructions: steps("Whisk the egg yolks and oil into an emulsion"),
+			wantScore:    2,
+			wantLevel:    DifficultyEasy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := ScoreDifficulty(tt.ingredients, tt.instructions, tt.prep, tt.cook)
+			if score != tt.wantScore {
+				t.Errorf("ScoreDifficulty() = %d, want %d", score, tt.wantScore)
+			}
+			if level := DifficultyFromScore(score); level != tt.wantLevel {
+				t.Errorf("DifficultyFromScore(%d) = %q, want %q", score, level, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestRecipe_Difficulty(t *testing.T) {
+	ing, _ := NewIngredient("flour", "500", "g", "")
+	inst, _ := NewInstruction(1, "Knead the dough for ten minutes", nil)
+	source, _ := NewSource("https://example.com/bread", PlatformWeb, "")
+	rec, err := NewRecipe(shared.NewID(), "Bread", []Ingredient{ing}, []Instruction{inst}, source, "", "")
+	if err != nil {
+		t.Fatalf("NewRecipe() error = %v", err)
+	}
+
+	// Unscored recipes are scored from their content
+	if rec.DifficultyScore() != 2 || rec.Difficulty() != DifficultyEasy {
+		t.Errorf("unscored recipe difficulty = %d (%s), want 2 (easy)", rec.DifficultyScore(), rec.Difficulty())
+	}
+
+	rec.SetPrepTime(3 * time.Hour)
+	rec.UpdateDifficulty()
+	if rec.DifficultyScore() != 5 || rec.Difficulty() != DifficultyMedium {
+		t.Errorf("scored recipe difficulty = %d (%s), want 5 (medium)", rec.DifficultyScore(), rec.Difficulty())
+	}
+}
@@ -40,6 +40,24 @@ type Recipe struct {
 
 	// Cached normalized ingredients for faster matching
 	normalizedIngredients []string
+
+	// Difficulty score computed at extraction (0 if not scored yet)
+	difficultyScore int
+
+	// Where the extracted fields came from and how confident the extraction is
+	provenance []FieldProvenance
+
+	// The recipe this one was remixed from, nil if it isn't a remix
+	remix *Remix
+
+	// Dominant tastes classified at extraction
+	flavors []Flavor
+
+	// Image shown with the recipe, nil if it has none of its own
+	cover *Cover
+
+	// Only the fields shown in lists are loaded, see Summary
+	summary bool
 }
 
 // NewRecipe creates a new Recipe
@@ -89,6 +107,7 @@ func NewRecipe(
 		cuisine:        "",
 		dietaryTags:    []DietaryTag{},
 		tags:           []string{},
+		flavors:        []Flavor{},
 		sourceLanguage: "en", // Default to English
 		createdAt:      now,
 		updatedAt:      now,
@@ -156,7 +175,7 @@ func ReconstructRecipeWithTranslations(
 	)
 }
 
-// ReconstructRecipeWithNormalizedIngredients reconstructs a recipe with all fields including normalized ingredients
+// ReconstructRecipeWithNormalizedIngredients reconstructs a recipe with normalized ingredients
 func ReconstructRecipeWithNormalizedIngredients(
 	id RecipeID,
 	userID UserID,
@@ -180,6 +199,191 @@ func ReconstructRecipeWithNormalizedIngredients(
 	translatedIngredients []Ingredient,
 	translatedInstructions []Instruction,
 	normalizedIngredients []string,
+) *Recipe {
+	return ReconstructRecipeWithDifficulty(
+		id, userID, title, ingredients, instructions, source,
+		transcript, captions, prepTime, cookTime, servings,
+		category, cuisine, dietaryTags, tags, createdAt, updatedAt,
+		sourceLanguage, translatedTitle, translatedIngredients, translatedInstructions,
+		normalizedIngredients, 0,
+	)
+}
+
+// ReconstructRecipeWithDifficulty reconstructs a recipe with all fields including the difficulty score
+func ReconstructRecipeWithDifficulty(
+	id RecipeID,
+	userID UserID,
+	title string,
+	ingredients []Ingredient,
+	instructions []Instruction,
+	source Source,
+	transcript string,
+	captions string,
+	prepTime *time.Duration,
+	cookTime *time.Duration,
+	servings *int,
+	category Category,
+	cuisine string,
+	dietaryTags []DietaryTag,
+	tags []string,
+	createdAt time.Time,
+	updatedAt time.Time,
+	sourceLanguage string,
+	translatedTitle *string,
+	translatedIngredients []Ingredient,
+	translatedInstructions []Instruction,
+	normalizedIngredients []string,
+	difficultyScore int,
+) *Recipe {
+	return ReconstructRecipeWithProvenance(
+		id, userID, title, ingredients, instructions, source,
+		transcript, captions, prepTime, cookTime, servings,
+		category, cuisine, dietaryTags, tags, createdAt, updatedAt,
+		sourceLanguage, translatedTitle, translatedIngredients, translatedInstructions,
+		normalizedIngredients, difficultyScore, nil,
+	)
+}
+
+// ReconstructRecipeWithProvenance reconstructs a recipe with all fields including the extraction provenance
+func ReconstructRecipeWithProvenance(
+	id RecipeID,
+	userID UserID,
+	title string,
+	ingredients []Ingredient,
+	instructions []Instruction,
+	source Source,
+	transcript string,
+	captions string,
+	prepTime *time.Duration,
+	cookTime *time.Duration,
+	servings *int,
+	category Category,
+	cuisine string,
+	dietaryTags []DietaryTag,
+	tags []string,
+	createdAt time.Time,
+	updatedAt time.Time,
+	sourceLanguage string,
+	translatedTitle *string,
+	translatedIngredients []Ingredient,
+	translatedInstructions []Instruction,
+	normalizedIngredients []string,
+	difficultyScore int,
+	provenance []FieldProvenance,
+) *Recipe {
+	return ReconstructRecipeWithRemix(
+		id, userID, title, ingredients, instructions, source,
+		transcript, captions, prepTime, cookTime, servings,
+		category, cuisine, dietaryTags, tags, createdAt, updatedAt,
+		sourceLanguage, translatedTitle, translatedIngredients, translatedInstructions,
+		normalizedIngredients, difficultyScore, provenance, nil,
+	)
+}
+
+// ReconstructRecipeWithRemix reconstructs a recipe with all fields including the recipe it was remixed from
+func ReconstructRecipeWithRemix(
+	id RecipeID,
+	userID UserID,
+	title string,
+	ingredients []Ingredient,
+	instructions []Instruction,
+	source Source,
+	transcript string,
+	captions string,
+	prepTime *time.Duration,
+	cookTime *time.Duration,
+	servings *int,
+	category Category,
+	cuisine string,
+	dietaryTags []DietaryTag,
+	tags []string,
+	createdAt time.Time,
+	updatedAt time.Time,
+	sourceLanguage string,
+	translatedTitle *string,
+	translatedIngredients []Ingredient,
+	translatedInstructions []Instruction,
+	normalizedIngredients []string,
+	difficultyScore int,
+	provenance []FieldProvenance,
+	remix *Remix,
+) *Recipe {
+	return ReconstructRecipeWithFlavors(
+		id, userID, title, ingredients, instructions, source,
+		transcript, captions, prepTime, cookTime, servings,
+		category, cuisine, dietaryTags, tags, createdAt, updatedAt,
+		sourceLanguage, translatedTitle, translatedIngredients, translatedInstructions,
+		normalizedIngredients, difficultyScore, provenance, remix, nil,
+	)
+}
+
+// ReconstructRecipeWithFlavors reconstructs a recipe with all fields including its flavor profiles
+func ReconstructRecipeWithFlavors(
+	id RecipeID,
+	userID UserID,
+	title string,
+	ingredients []Ingredient,
+	instructions []Instruction,
+	source Source,
+	transcript string,
+	captions string,
+	prepTime *time.Duration,
+	cookTime *time.Duration,
+	servings *int,
+	category Category,
+	cuisine string,
+	dietaryTags []DietaryTag,
+	tags []string,
+	createdAt time.Time,
+	updatedAt time.Time,
+	sourceLanguage string,
+	translatedTitle *string,
+	translatedIngredients []Ingredient,
+	translatedInstructions []Instruction,
+	normalizedIngredients []string,
+	difficultyScore int,
+	provenance []FieldProvenance,
+	remix *Remix,
+	flavors []Flavor,
+) *Recipe {
+	return ReconstructRecipeWithCover(
+		id, userID, title, ingredients, instructions, source,
+		transcript, captions, prepTime, cookTime, servings,
+		category, cuisine, dietaryTags, tags, createdAt, updatedAt,
+		sourceLanguage, translatedTitle, translatedIngredients, translatedInstructions,
+		normalizedIngredients, difficultyScore, provenance, remix, flavors, nil,
+	)
+}
+
+// ReconstructRecipeWithCover reconstructs a recipe with all fields including its cover image
+func ReconstructRecipeWithCover(
+	id RecipeID,
+	userID UserID,
+	title string,
+	ingredients []Ingredient,
+	instructions []Instruction,
+	source Source,
+	transcript string,
+	captions string,
+	prepTime *time.Duration,
+	cookTime *time.Duration,
+	servings *int,
+	category Category,
+	cuisine string,
+	dietaryTags []DietaryTag,
+	tags []string,
+	createdAt time.Time,
+	updatedAt time.Time,
+	sourceLanguage string,
+	translatedTitle *string,
+	translatedIngredients []Ingredient,
+	translatedInstructions []Instruction,
+	normalizedIngredients []string,
+	difficultyScore int,
+	provenance []FieldProvenance,
+	remix *Remix,
+	flavors []Flavor,
+	cover *Cover,
 ) *Recipe {
 	// Default category to Other if empty
 	if category == "" {
@@ -199,6 +403,9 @@ func ReconstructRecipeWithNormalizedIngredients(
 	if normalizedIngredients == nil {
 		normalizedIngredients = []string{}
 	}
+	if flavors == nil {
+		flavors = []Flavor{}
+	}
 
 	return &Recipe{
 		id:                     id,
@@ -223,6 +430,11 @@ func ReconstructRecipeWithNormalizedIngredients(
 		translatedIngredients:  translatedIngredients,
 		translatedInstructions: translatedInstructions,
 		normalizedIngredients:  normalizedIngredients,
+		difficultyScore:        difficultyScore,
+		provenance:             provenance,
+		remix:                  remix,
+		flavors:                flavors,
+		cover:                  cover,
 	}
 }
 
@@ -348,6 +560,57 @@ func (r *Recipe) HasNormalizedIngredients() bool {
 	return len(r.normalizedIngredients) > 0
 }
 
+// Provenance returns where the extracted fields came from, empty for recipes
+// extracted before it was recorded
+func (r *Recipe) Provenance() []FieldProvenance {
+	return r.provenance
+}
+
+// ProvenanceOf returns the provenance recorded for an extracted field
+func (r *Recipe) ProvenanceOf(field string) (FieldProvenance, bool) {
+	for _, p := range r.provenance {
+		if p.Field() == field {
+			return p, true
+		}
+	}
+	return FieldProvenance{}, false
+}
+
+// SetProvenance sets where the extracted fields came from
+func (r *Recipe) SetProvenance(provenance []FieldProvenance) {
+	r.provenance = provenance
+	r.updatedAt = shared.NewTimestamp()
+}
+
+// Remix returns the recipe this one was remixed from and its substitutions, nil if it isn't a remix
+func (r *Recipe) Remix() *Remix {
+	return r.remix
+}
+
+// ParentRecipeID returns the ID of the recipe this one was remixed from, empty if it isn't a remix
+func (r *Recipe) ParentRecipeID() RecipeID {
+	if r.remix == nil {
+		return ""
+	}
+	return r.remix.ParentID
+}
+
+// SetRemix links the recipe to the recipe it was remixed from
+func (r *Recipe) SetRemix(remix *Remix) {
+	r.remix = remix
+	r.updatedAt = shared.NewTimestamp()
+}
+
+// NeedsQuantityCheck reports whether the extraction is unsure of the ingredients or their quantities
+func (r *Recipe) NeedsQuantityCheck() bool {
+	for _, field := range []string{FieldIngredients, FieldQuantities} {
+		if p, ok := r.ProvenanceOf(field); ok && p.IsLowConfidence() {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEnglish returns true if the source language is English
 func (r *Recipe) IsEnglish() bool {
 	return r.sourceLanguage == "" || r.sourceLanguage == "en"
@@ -402,6 +665,30 @@ func (r *Recipe) SetDietaryTags(tags []DietaryTag) {
 	r.updatedAt = shared.NewTimestamp()
 }
 
+// Flavors returns the dominant tastes of the recipe, e.g. spicy or tangy
+func (r *Recipe) Flavors() []Flavor {
+	return r.flavors
+}
+
+// HasFlavor reports whether the recipe has a flavor profile
+func (r *Recipe) HasFlavor(flavor Flavor) bool {
+	for _, f := range r.flavors {
+		if f == flavor {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFlavors sets the flavor profiles
+func (r *Recipe) SetFlavors(flavors []Flavor) {
+	if flavors == nil {
+		flavors = []Flavor{}
+	}
+	r.flavors = flavors
+	r.updatedAt = shared.NewTimestamp()
+}
+
 // SetTags sets the free-form tags
 func (r *Recipe) SetTags(tags []string) {
 	if tags == nil {
@@ -439,6 +726,34 @@ func (r *Recipe) AddInstruction(instruction Instruction) error {
 	return nil
 }
 
+// Clone returns a copy of the recipe that shares no mutable state with the original
+func (r *Recipe) Clone() *Recipe {
+	cp := *r
+	cp.ingredients = append([]Ingredient(nil), r.ingredients...)
+	cp.instructions = append([]Instruction(nil), r.instructions...)
+	cp.dietaryTags = append([]DietaryTag{}, r.dietaryTags...)
+	cp.tags = append([]string{}, r.tags...)
+	cp.flavors = append([]Flavor{}, r.flavors...)
+	cp.normalizedIngredients = append([]string{}, r.normalizedIngredients...)
+	if r.translatedIngredients != nil {
+		cp.translatedIngredients = append([]Ingredient(nil), r.translatedIngredients...)
+	}
+	if r.translatedInstructions != nil {
+		cp.translatedInstructions = append([]Instruction(nil), r.translatedInstructions...)
+	}
+	if r.provenance != nil {
+		cp.provenance = append([]FieldProvenance(nil), r.provenance...)
+	}
+	if r.remix != nil {
+		cp.remix = r.remix.Clone()
+	}
+	if r.cover != nil {
+		cover := *r.cover
+		cp.cover = &cover
+	}
+	return &cp
+}
+
 // Validate validates the recipe according to domain rules
 func (r *Recipe) Validate() error {
 	if r.title == "" {
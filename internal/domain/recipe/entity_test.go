@@ -1,6 +1,7 @@
 package recipe
 
 import (
+	"strings"
 	"testing"
 
 	"receipt-bot/internal/domain/shared"
@@ -102,7 +103,7 @@ func TestNewRecipe(t *testing.T) {
 					t.Errorf("NewRecipe() expected error but got nil")
 					return
 				}
-				if tt.errContains != "" && err.Error() != tt.errContains {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
 					t.Errorf("NewRecipe() error = %v, want error containing %v", err, tt.errContains)
 				}
 				return
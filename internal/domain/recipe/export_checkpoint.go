@@ -0,0 +1,15 @@
+package recipe
+
+import "context"
+
+// ExportCheckpointRepository stores how far an unfinished bulk export got, the
+// recipes it already sent, so the next run resumes it (Port)
+type ExportCheckpointRepository interface {
+	// FindExportCheckpoint returns the recipes the user's unfinished bulk export to
+	// target already sent, none when no export is unfinished
+	FindExportCheckpoint(ctx context.Context, userID UserID, target string) ([]RecipeID, error)
+
+	// SaveExportCheckpoint replaces the recipes the user's bulk export to target already
+	// sent. No recipes remove the checkpoint.
+	SaveExportCheckpoint(ctx context.Context, userID UserID, target string, sent []RecipeID) error
+}
@@ -0,0 +1,169 @@
+package recipe
+
+import (
+	"sort"
+	"strings"
+)
+
+// ExportField is a part of a recipe an export can include
+type ExportField string
+
+const (
+	ExportFieldMetadata    ExportField = "metadata" // category, cuisine, tags, times and servings
+	ExportFieldIngredients ExportField = "ingredients"
+	ExportFieldSteps       ExportField = "steps"
+	ExportFieldNotes       ExportField = "notes" // ingredient notes, like "cut into cubes"
+	ExportFieldSource      ExportField = "source"
+	ExportFieldTranscript  ExportField = "transcript"
+)
+
+// AllExportFields returns every field an export can include, in the order they are shown
+func AllExportFields() []ExportField {
+	return []ExportField{
+		ExportFieldMetadata, ExportFieldIngredients, ExportFieldSteps,
+		ExportFieldNotes, ExportFieldSource, ExportFieldTranscript,
+	}
+}
+
+// ExportFields is the set of fields an export includes. The title is always included.
+type ExportFields []ExportField
+
+// DefaultExportFields returns the fields exported unless the user chooses others:
+// everything but the transcript
+func DefaultExportFields() ExportFields {
+	return ExportFields{ExportFieldMetadata, ExportFieldIngredients, ExportFieldSteps, ExportFieldNotes, ExportFieldSource}
+}
+
+// ExportProfiles are named sets of fields, e.g. /export obsidian 3 --fields=shopping
+var ExportProfiles = map[string]ExportFields{
+	"full":          ExportFields(AllExportFields()),
+	"shopping":      {ExportFieldIngredients},
+	"no-transcript": DefaultExportFields(),
+	"with-notes":    {ExportFieldIngredients, ExportFieldSteps, ExportFieldNotes},
+}
+
+// exportFieldAliases maps other words for a field to it
+var exportFieldAliases = map[string]ExportField{
+	"meta":         ExportFieldMetadata,
+	"instructions": ExportFieldSteps,
+	"method":       ExportFieldSteps,
+	"note":         ExportFieldNotes,
+	"url":          ExportFieldSource,
+}
+
+// ParseExportFields parses a profile name, or a comma separated list of fields
+// like "ingredients,steps". Returns false if a field is unknown or none is given.
+func ParseExportFields(s string) (ExportFields, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if profile, ok := ExportProfiles[s]; ok {
+		return append(ExportFields{}, profile...), true
+	}
+
+	seen := make(map[ExportField]bool)
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		field := ExportField(name)
+		if alias, ok := exportFieldAliases[name]; ok {
+			field = alias
+		}
+		if !field.IsValid() {
+			return nil, false
+		}
+		seen[field] = true
+	}
+	if len(seen) == 0 {
+		return nil, false
+	}
+
+	var fields ExportFields
+	for _, field := range AllExportFields() {
+		if seen[field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields, true
+}
+
+// ExportProfileNames returns the names of the export profiles, sorted
+func ExportProfileNames() []string {
+	names := make([]string, 0, len(ExportProfiles))
+	for name := range ExportProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsValid checks if the field is valid
+func (f ExportField) IsValid() bool {
+	switch f {
+	case ExportFieldMetadata, ExportFieldIngredients, ExportFieldSteps,
+		ExportFieldNotes, ExportFieldSource, ExportFieldTranscript:
+		return true
+	default:
+		return false
+	}
+}
+
+// Has reports whether the set includes a field
+func (fs ExportFields) Has(field ExportField) bool {
+	for _, f := range fs {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the fields as strings, for storage
+func (fs ExportFields) Names() []string {
+	names := make([]string, len(fs))
+	for i, f := range fs {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// String returns the fields as a comma separated list
+func (fs ExportFields) String() string {
+	return strings.Join(fs.Names(), ",")
+}
+
+// WithFields returns a copy of the recipe with only the fields to export; the
+// others are left empty, so exporters skip them. Nil fields keep the defaults.
+func (r *Recipe) WithFields(fields ExportFields) *Recipe {
+	if fields == nil {
+		fields = DefaultExportFields()
+	}
+
+	cp := r.Clone()
+	if !fields.Has(ExportFieldMetadata) {
+		cp.category = ""
+		cp.cuisine = ""
+		cp.dietaryTags = nil
+		cp.tags = nil
+		cp.prepTime = nil
+		cp.cookTime = nil
+		cp.servings = nil
+	}
+	if !fields.Has(ExportFieldIngredients) {
+		cp.ingredients = nil
+	} else if !fields.Has(ExportFieldNotes) {
+		for i := range cp.ingredients {
+			cp.ingredients[i].notes = ""
+		}
+	}
+	if !fields.Has(ExportFieldSteps) {
+		cp.instructions = nil
+	}
+	if !fields.Has(ExportFieldSource) {
+		cp.source = Source{}
+	}
+	if !fields.Has(ExportFieldTranscript) {
+		cp.transcript = ""
+	}
+	return cp
+}
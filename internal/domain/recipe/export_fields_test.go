@@ -0,0 +1,55 @@
+package recipe
+
+import "testing"
+
+func TestParseExportFields(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"shopping", "ingredients", true},
+		{"Full", "metadata,ingredients,steps,notes,source,transcript", true},
+		{"steps, ingredients,steps", "ingredients,steps", true},
+		{"instructions,url", "steps,source", true},
+		{"ingredients,pictures", "", false},
+		{" , ", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseExportFields(tt.in)
+		if ok != tt.wantOK || got.String() != tt.want {
+			t.Errorf("ParseExportFields(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRecipe_WithFields(t *testing.T) {
+	ing, _ := NewIngredient("chicken", "500", "g", "cut into cubes")
+	inst, _ := NewInstruction(1, "Brown the chicken", nil)
+	source, _ := NewSource("https://example.com/curry", PlatformWeb, "Cook")
+	rec, err := NewRecipe(UserID("user-1"), "Curry", []Ingredient{ing}, []Instruction{inst}, source, "brown it well", "")
+	if err != nil {
+		t.Fatalf("NewRecipe() error = %v", err)
+	}
+	rec.SetCuisine("Indian")
+
+	defaults := rec.WithFields(nil)
+	if defaults.Transcript() != "" || defaults.Ingredients()[0].Notes() != "cut into cubes" || defaults.Cuisine() != "Indian" {
+		t.Errorf("default fields should drop only the transcript, got transcript %q, notes %q, cuisine %q",
+			defaults.Transcript(), defaults.Ingredients()[0].Notes(), defaults.Cuisine())
+	}
+
+	shopping := rec.WithFields(ExportFields{ExportFieldIngredients})
+	if len(shopping.Instructions()) != 0 || shopping.Source().URL() != "" || shopping.Cuisine() != "" {
+		t.Errorf("ingredients only kept %d instructions, source %q, cuisine %q",
+			len(shopping.Instructions()), shopping.Source().URL(), shopping.Cuisine())
+	}
+	if notes := shopping.Ingredients()[0].Notes(); notes != "" {
+		t.Errorf("ingredients without notes kept %q", notes)
+	}
+
+	if rec.Ingredients()[0].Notes() == "" || len(rec.Instructions()) == 0 || rec.Transcript() == "" {
+		t.Error("WithFields() changed the recipe")
+	}
+}
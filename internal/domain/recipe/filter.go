@@ -0,0 +1,104 @@
+package recipe
+
+import "strings"
+
+// Matches reports whether a recipe satisfies the filter.
+// Ingredients are matched by substring against the title, ingredient names
+// and normalized ingredients.
+func (f IngredientFilter) Matches(rec *Recipe) bool {
+	searchable := rec.searchableText()
+
+	// All INCLUDE ingredients must be present (AND logic)
+	for _, required := range f.Include {
+		if !containsIngredient(searchable, required) {
+			return false
+		}
+	}
+
+	// No EXCLUDE ingredients should be present (NOT logic)
+	for _, excluded := range f.Exclude {
+		if containsIngredient(searchable, excluded) {
+			return false
+		}
+	}
+
+	// Optional: at least one of these should be present (OR logic)
+	// Only apply if there are optional ingredients specified
+	if len(f.Optional) > 0 {
+		for _, optional := range f.Optional {
+			if containsIngredient(searchable, optional) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// MentionsIngredient reports whether the title or any ingredient name contains the term
+func (r *Recipe) MentionsIngredient(term string) bool {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return true
+	}
+
+	if strings.Contains(strings.ToLower(r.title), term) {
+		return true
+	}
+
+	for _, ing := range r.ingredients {
+		if strings.Contains(strings.ToLower(ing.Name()), term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasAllDietaryTags reports whether the recipe has every one of the given tags
+func (r *Recipe) HasAllDietaryTags(required []DietaryTag) bool {
+	tags := make(map[DietaryTag]bool, len(r.dietaryTags))
+	for _, tag := range r.dietaryTags {
+		tags[tag] = true
+	}
+
+	for _, tag := range required {
+		if !tags[tag] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// searchableText returns the lowercased title, ingredient names and normalized ingredients
+func (r *Recipe) searchableText() []string {
+	texts := make([]string, 0, 1+len(r.ingredients)+len(r.normalizedIngredients))
+	texts = append(texts, strings.ToLower(r.title))
+
+	for _, ing := range r.ingredients {
+		texts = append(texts, strings.ToLower(ing.Name()))
+	}
+
+	for _, normalized := range r.normalizedIngredients {
+		texts = append(texts, strings.ToLower(normalized))
+	}
+
+	return texts
+}
+
+// containsIngredient checks if any of the searchable texts contain the ingredient
+func containsIngredient(searchable []string, ingredient string) bool {
+	ingredient = strings.ToLower(strings.TrimSpace(ingredient))
+	if ingredient == "" {
+		return true // Empty ingredient matches everything
+	}
+
+	for _, text := range searchable {
+		if strings.Contains(text, ingredient) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package recipe
+
+import (
+	"testing"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func newFilterTestRecipe(t *testing.T, title string, ingredientNames ...string) *Recipe {
+	t.Helper()
+
+	ingredients := make([]Ingredient, len(ingredientNames))
+	for i, name := range ingredientNames {
+		ing, err := NewIngredient(name, "1", "", "")
+		if err != nil {
+			t.Fatalf("NewIngredient() error = %v", err)
+		}
+		ingredients[i] = ing
+	}
+	instruction, _ := NewInstruction(1, "Cook", nil)
+	source, _ := NewSource("https://example.com", PlatformWeb, "")
+
+	rec, err := NewRecipe(shared.NewID(), title, ingredients, []Instruction{instruction}, source, "", "")
+	if err != nil {
+		t.Fatalf("NewRecipe() error = %v", err)
+	}
+	return rec
+}
+
+func TestIngredientFilter_Matches(t *testing.T) {
+	rec := newFilterTestRecipe(t, "Chicken Curry", "chicken thigh", "coconut milk", "onion")
+
+	tests := []struct {
+		name   string
+		filter IngredientFilter
+		want   bool
+	}{
+		{"empty filter", IngredientFilter{}, true},
+		{"include present", IngredientFilter{Include: []string{"chicken", "Coconut"}}, true},
+		{"include missing", IngredientFilter{Include: []string{"chicken", "rice"}}, false},
+		{"exclude present", IngredientFilter{Exclude: []string{"onion"}}, false},
+		{"exclude missing", IngredientFilter{Exclude: []string{"garlic"}}, true},
+		{"optional any", IngredientFilter{Optional: []string{"rice", "onion"}}, true},
+		{"optional none", IngredientFilter{Optional: []string{"rice", "beef"}}, false},
+		{"title counts", IngredientFilter{Include: []string{"curry"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(rec); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecipe_HasAllDietaryTags(t *testing.T) {
+	rec := newFilterTestRecipe(t, "Salad", "lettuce")
+	rec.SetDietaryTags([]DietaryTag{TagVegan, TagGlutenFree})
+
+	if !rec.HasAllDietaryTags(nil) {
+		t.Error("HasAllDietaryTags(nil) = false, want true")
+	}
+	if !rec.HasAllDietaryTags([]DietaryTag{TagVegan}) {
+		t.Error("HasAllDietaryTags(vegan) = false, want true")
+	}
+	if rec.HasAllDietaryTags([]DietaryTag{TagVegan, TagLowCarb}) {
+		t.Error("HasAllDietaryTags(vegan, keto) = true, want false")
+	}
+}
+
+func TestRecipe_Clone(t *testing.T) {
+	rec := newFilterTestRecipe(t, "Soup", "carrot")
+	clone := rec.Clone()
+
+	extra, _ := NewIngredient("celery", "1", "", "")
+	_ = clone.AddIngredient(extra)
+	clone.SetCategory(CategorySoups)
+
+	if len(rec.Ingredients()) != 1 {
+		t.Errorf("original ingredients = %d, want 1", len(rec.Ingredients()))
+	}
+	if rec.Category() == CategorySoups {
+		t.Error("original category changed through clone")
+	}
+	if clone.ID() != rec.ID() {
+		t.Error("clone should keep the same ID")
+	}
+}
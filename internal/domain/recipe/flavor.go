@@ -0,0 +1,100 @@
+package recipe
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Flavor is a dominant taste of a recipe, classified at extraction
+type Flavor string
+
+const (
+	FlavorSpicy Flavor = "spicy"
+	FlavorSweet Flavor = "sweet"
+	FlavorUmami Flavor = "umami-rich"
+	FlavorTangy Flavor = "tangy"
+)
+
+// AllFlavors returns all flavor profiles, in the order they are shown
+func AllFlavors() []Flavor {
+	return []Flavor{FlavorSpicy, FlavorSweet, FlavorUmami, FlavorTangy}
+}
+
+// IsValid checks if the flavor is valid
+func (f Flavor) IsValid() bool {
+	switch f {
+	case FlavorSpicy, FlavorSweet, FlavorUmami, FlavorTangy:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the flavor
+func (f Flavor) String() string {
+	return string(f)
+}
+
+// flavorAliases maps the words people use for a flavor, in English and Portuguese
+var flavorAliases = map[string]Flavor{
+	"spicy":      FlavorSpicy,
+	"hot":        FlavorSpicy,
+	"fiery":      FlavorSpicy,
+	"picante":    FlavorSpicy,
+	"apimentado": FlavorSpicy,
+	"apimentada": FlavorSpicy,
+	"ardido":     FlavorSpicy,
+	"sweet":      FlavorSweet,
+	"doce":       FlavorSweet,
+	"umami-rich": FlavorUmami,
+	"umami rich": FlavorUmami,
+	"umami":      FlavorUmami,
+	"savory":     FlavorUmami,
+	"savoury":    FlavorUmami,
+	"tangy":      FlavorTangy,
+	"sour":       FlavorTangy,
+	"zesty":      FlavorTangy,
+	"acidic":     FlavorTangy,
+	"azedo":      FlavorTangy,
+	"azedinho":   FlavorTangy,
+	"ácido":      FlavorTangy,
+	"cítrico":    FlavorTangy,
+}
+
+// ParseFlavor parses a string into a Flavor, accepting aliases like "hot" or "picante".
+// Returns the flavor and a boolean indicating if it's valid
+func ParseFlavor(s string) (Flavor, bool) {
+	f, ok := flavorAliases[strings.Join(strings.Fields(strings.ToLower(s)), " ")]
+	return f, ok
+}
+
+// ParseFlavors parses a slice of strings into valid Flavors.
+// Invalid and repeated flavors are filtered out
+func ParseFlavors(flavors []string) []Flavor {
+	result := make([]Flavor, 0, len(flavors))
+	seen := make(map[Flavor]bool)
+
+	for _, s := range flavors {
+		f, valid := ParseFlavor(s)
+		if valid && !seen[f] {
+			result = append(result, f)
+			seen[f] = true
+		}
+	}
+
+	return result
+}
+
+// FlavorInText returns the first flavor a message names, like "spicy" in
+// "I want something spicy", or false if it names none
+func FlavorInText(text string) (Flavor, bool) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return r != '-' && !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		if f, ok := flavorAliases[word]; ok {
+			return f, true
+		}
+	}
+	return "", false
+}
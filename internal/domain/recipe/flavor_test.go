@@ -0,0 +1,57 @@
+package recipe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFlavor(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Flavor
+		ok    bool
+	}{
+		{"spicy", FlavorSpicy, true},
+		{"Picante", FlavorSpicy, true},
+		{"umami rich", FlavorUmami, true},
+		{"savory", FlavorUmami, true},
+		{"sour", FlavorTangy, true},
+		{"doce", FlavorSweet, true},
+		{"bitter", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseFlavor(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseFlavor(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseFlavors(t *testing.T) {
+	got := ParseFlavors([]string{"spicy", "hot", "tangy", "crunchy"})
+	if want := []Flavor{FlavorSpicy, FlavorTangy}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFlavors() = %v, want %v", got, want)
+	}
+}
+
+func TestFlavorInText(t *testing.T) {
+	tests := []struct {
+		text string
+		want Flavor
+		ok   bool
+	}{
+		{"I want something spicy", FlavorSpicy, true},
+		{"quero algo azedinho!", FlavorTangy, true},
+		{"something umami-rich for dinner", FlavorUmami, true},
+		{"something quick", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := FlavorInText(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("FlavorInText(%q) = %q, %v; want %q, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package recipe
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -67,7 +68,7 @@ func TestNewIngredient(t *testing.T) {
 					t.Errorf("NewIngredient() expected error but got nil")
 					return
 				}
-				if tt.errContains != "" && err.Error() != tt.errContains {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
 					t.Errorf("NewIngredient() error = %v, want error containing %v", err, tt.errContains)
 				}
 				return
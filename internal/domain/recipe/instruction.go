@@ -12,6 +12,7 @@ type Instruction struct {
 	stepNumber int
 	text       string
 	duration   *time.Duration
+	timestamp  *time.Duration // where the step starts in the source video
 }
 
 // NewInstruction creates a new Instruction
@@ -48,6 +49,20 @@ func (i Instruction) Duration() *time.Duration {
 	return i.duration
 }
 
+// Timestamp returns where the step starts in the source video, or nil if unknown
+func (i Instruction) Timestamp() *time.Duration {
+	return i.timestamp
+}
+
+// WithTimestamp returns a copy of the instruction starting at the given point of the source video
+func (i Instruction) WithTimestamp(at time.Duration) Instruction {
+	if at < 0 {
+		at = 0
+	}
+	i.timestamp = &at
+	return i
+}
+
 // String returns a formatted string representation
 func (i Instruction) String() string {
 	result := fmt.Sprintf("%d. %s", i.stepNumber, i.text)
@@ -1,6 +1,7 @@
 package recipe
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -72,7 +73,7 @@ func TestNewInstruction(t *testing.T) {
 					t.Errorf("NewInstruction() expected error but got nil")
 					return
 				}
-				if tt.errContains != "" && err.Error() != tt.errContains {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
 					t.Errorf("NewInstruction() error = %v, want error containing %v", err, tt.errContains)
 				}
 				return
@@ -131,3 +132,18 @@ func TestInstruction_String(t *testing.T) {
 		})
 	}
 }
+
+func TestInstruction_WithTimestamp(t *testing.T) {
+	inst, _ := NewInstruction(1, "Make the sauce", nil)
+	if inst.Timestamp() != nil {
+		t.Fatalf("Timestamp() = %v, want nil for a new instruction", *inst.Timestamp())
+	}
+
+	timed := inst.WithTimestamp(95 * time.Second)
+	if timed.Timestamp() == nil || *timed.Timestamp() != 95*time.Second {
+		t.Errorf("Timestamp() = %v, want 1m35s", timed.Timestamp())
+	}
+	if inst.Timestamp() != nil {
+		t.Error("WithTimestamp() changed the original instruction")
+	}
+}
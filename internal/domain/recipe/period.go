@@ -0,0 +1,109 @@
+package recipe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateRange is a span of time recipes were saved in, from From up to but not including To
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Contains reports whether t falls in the range
+func (r DateRange) Contains(t time.Time) bool {
+	return !t.Before(r.From) && t.Before(r.To)
+}
+
+// ParsePeriod returns the range of a period, as the intent detector names it, in
+// the time zone of now. It understands "today", "yesterday", "this week" or "last
+// week" (weeks start on Monday), the same for months and years, "last N days",
+// "last N weeks" and "last N months" counting today, a month name such as
+// "december" (the latest one that has begun) or "december 2024", and "2024-12-24",
+// "2024-12" or "2024". Returns false for anything else.
+func ParsePeriod(period string, now time.Time) (DateRange, bool) {
+	p := strings.Join(strings.Fields(strings.ToLower(period)), " ")
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	week := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	year := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, loc)
+
+	switch p {
+	case "today":
+		return DateRange{today, today.AddDate(0, 0, 1)}, true
+	case "yesterday":
+		return DateRange{today.AddDate(0, 0, -1), today}, true
+	case "this week":
+		return DateRange{week, week.AddDate(0, 0, 7)}, true
+	case "last week":
+		return DateRange{week.AddDate(0, 0, -7), week}, true
+	case "this month":
+		return DateRange{month, month.AddDate(0, 1, 0)}, true
+	case "last month":
+		return DateRange{month.AddDate(0, -1, 0), month}, true
+	case "this year":
+		return DateRange{year, year.AddDate(1, 0, 0)}, true
+	case "last year":
+		return DateRange{year.AddDate(-1, 0, 0), year}, true
+	}
+
+	// "last 30 days", "last 2 weeks", "last 3 months"
+	var n int
+	var unit string
+	if _, err := fmt.Sscanf(p, "last %d %s", &n, &unit); err == nil {
+		end := today.AddDate(0, 0, 1)
+		if n < 1 {
+			return DateRange{}, false
+		}
+		switch strings.TrimSuffix(unit, "s") {
+		case "day":
+			return DateRange{end.AddDate(0, 0, -n), end}, true
+		case "week":
+			return DateRange{end.AddDate(0, 0, -7*n), end}, true
+		case "month":
+			return DateRange{end.AddDate(0, -n, 0), end}, true
+		}
+		return DateRange{}, false
+	}
+
+	// A day, month or year
+	for _, f := range []struct {
+		layout              string
+		years, months, days int
+	}{
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	} {
+		if t, err := time.ParseInLocation(f.layout, p, loc); err == nil {
+			return DateRange{t, t.AddDate(f.years, f.months, f.days)}, true
+		}
+	}
+
+	// A month by name, with or without its year
+	name, yearText, _ := strings.Cut(p, " ")
+	for m := time.January; m <= time.December; m++ {
+		full := strings.ToLower(m.String())
+		if name != full && name != full[:3] {
+			continue
+		}
+		y := now.Year()
+		if yearText != "" {
+			parsed, err := strconv.Atoi(yearText)
+			if err != nil {
+				return DateRange{}, false
+			}
+			y = parsed
+		} else if m > now.Month() {
+			y--
+		}
+		start := time.Date(y, m, 1, 0, 0, 0, 0, loc)
+		return DateRange{start, start.AddDate(0, 1, 0)}, true
+	}
+
+	return DateRange{}, false
+}
@@ -0,0 +1,59 @@
+package recipe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	// A Friday afternoon
+	now := time.Date(2026, time.October, 16, 15, 0, 0, 0, time.UTC)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		period   string
+		from, to time.Time
+	}{
+		{"today", day(2026, time.October, 16), day(2026, time.October, 17)},
+		{"Yesterday", day(2026, time.October, 15), day(2026, time.October, 16)},
+		{"this week", day(2026, time.October, 12), day(2026, time.October, 19)},
+		{"last  week", day(2026, time.October, 5), day(2026, time.October, 12)},
+		{"last month", day(2026, time.September, 1), day(2026, time.October, 1)},
+		{"this year", day(2026, time.January, 1), day(2027, time.January, 1)},
+		{"last 30 days", day(2026, time.September, 17), day(2026, time.October, 17)},
+		{"last 2 weeks", day(2026, time.October, 3), day(2026, time.October, 17)},
+		{"last 1 month", day(2026, time.September, 17), day(2026, time.October, 17)},
+		{"december", day(2025, time.December, 1), day(2026, time.January, 1)},
+		{"Oct", day(2026, time.October, 1), day(2026, time.November, 1)},
+		{"march 2024", day(2024, time.March, 1), day(2024, time.April, 1)},
+		{"2025-12-24", day(2025, time.December, 24), day(2025, time.December, 25)},
+		{"2025-12", day(2025, time.December, 1), day(2026, time.January, 1)},
+		{"2024", day(2024, time.January, 1), day(2025, time.January, 1)},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParsePeriod(tt.period, now)
+		if !ok || !got.From.Equal(tt.from) || !got.To.Equal(tt.to) {
+			t.Errorf("ParsePeriod(%q) = %v - %v, %v, want %v - %v", tt.period, got.From, got.To, ok, tt.from, tt.to)
+		}
+	}
+
+	for _, period := range []string{"", "someday", "last 0 days", "last 3 fortnights", "december of 2024"} {
+		if _, ok := ParsePeriod(period, now); ok {
+			t.Errorf("ParsePeriod(%q) = true, want false", period)
+		}
+	}
+}
+
+func TestDateRange_Contains(t *testing.T) {
+	r, _ := ParsePeriod("last month", time.Date(2026, time.October, 16, 15, 0, 0, 0, time.UTC))
+
+	if !r.Contains(time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Contains() = false for the start of the range, want true")
+	}
+	if r.Contains(time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Contains() = true for the end of the range, want false")
+	}
+}
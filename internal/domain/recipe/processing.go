@@ -0,0 +1,37 @@
+package recipe
+
+import (
+	"context"
+	"time"
+)
+
+// ProcessingReport records how the latest extraction of a recipe went: how long each
+// stage took and what the LLM calls cost, to find which platforms and models are slow
+// or expensive
+type ProcessingReport struct {
+	RecipeID      RecipeID
+	Platform      Platform
+	Scrape        time.Duration // downloading the content, transcription included
+	Transcription time.Duration // zero when there was no audio to transcribe
+	OnScreenText  time.Duration // reading the text shown in key frames
+	LLM           time.Duration // the recipe extraction
+	Model         string
+	LLMCalls      int // on-screen text, extraction and consolidation calls together
+	InputTokens   int
+	OutputTokens  int
+	ProcessedAt   time.Time
+}
+
+// Total returns the time spent on all the stages
+func (r *ProcessingReport) Total() time.Duration {
+	return r.Scrape + r.OnScreenText + r.LLM
+}
+
+// ProcessingRepository persists the processing report of each recipe (Port)
+type ProcessingRepository interface {
+	// SaveProcessingReport stores a report, replacing the recipe's previous one
+	SaveProcessingReport(ctx context.Context, report *ProcessingReport) error
+
+	// FindProcessingReport returns the report of a recipe, or shared.ErrNoProcessingReport
+	FindProcessingReport(ctx context.Context, recipeID RecipeID) (*ProcessingReport, error)
+}
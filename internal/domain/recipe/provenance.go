@@ -0,0 +1,100 @@
+package recipe
+
+import (
+	"strings"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// TextSource is the part of the scraped content an extracted field was found in
+type TextSource string
+
+const (
+	TextSourceCaptions   TextSource = "captions"
+	TextSourceTranscript TextSource = "transcript"
+	TextSourceOnScreen   TextSource = "on_screen"
+	TextSourceWebpage    TextSource = "webpage"
+	TextSourceInferred   TextSource = "inferred" // not stated in the content, filled in by the LLM
+)
+
+// Extracted fields that provenance is recorded for
+const (
+	FieldTitle        = "title"
+	FieldIngredients  = "ingredients"
+	FieldQuantities   = "quantities"
+	FieldInstructions = "instructions"
+	FieldTimes        = "times"
+	FieldServings     = "servings"
+)
+
+// LowConfidence is the confidence below which an extracted field should be double-checked
+const LowConfidence = 0.6
+
+// ParseTextSource parses a string into a TextSource.
+// Returns the source and a boolean indicating if it's valid
+func ParseTextSource(s string) (TextSource, bool) {
+	source := TextSource(strings.ToLower(strings.TrimSpace(s)))
+	switch source {
+	case TextSourceCaptions, TextSourceTranscript, TextSourceOnScreen, TextSourceWebpage, TextSourceInferred:
+		return source, true
+	default:
+		return "", false
+	}
+}
+
+// FieldProvenance records where an extracted field came from and how confident
+// the extraction is about it (Value Object)
+type FieldProvenance struct {
+	field      string
+	source     TextSource
+	confidence float64 // 0 (a guess) to 1 (stated exactly)
+}
+
+// NewFieldProvenance creates a new FieldProvenance. Unknown sources are recorded as
+// inferred and the confidence is clamped to 0-1.
+func NewFieldProvenance(field string, source TextSource, confidence float64) (FieldProvenance, error) {
+	field = strings.ToLower(strings.TrimSpace(field))
+	if field == "" {
+		return FieldProvenance{}, shared.ErrInvalidInput
+	}
+
+	parsed, ok := ParseTextSource(string(source))
+	if !ok {
+		parsed = TextSourceInferred
+	}
+
+	if confidence < 0 {
+		confidence = 0
+	}
+	if confidence > 1 {
+		confidence = 1
+	}
+
+	return FieldProvenance{field: field, source: parsed, confidence: confidence}, nil
+}
+
+// Field returns the extracted field
+func (p FieldProvenance) Field() string {
+	return p.field
+}
+
+// Source returns where the field was found
+func (p FieldProvenance) Source() TextSource {
+	return p.source
+}
+
+// Confidence returns how confident the extraction is, from 0 to 1
+func (p FieldProvenance) Confidence() float64 {
+	return p.confidence
+}
+
+// IsLowConfidence reports whether the field should be double-checked
+func (p FieldProvenance) IsLowConfidence() bool {
+	return p.confidence < LowConfidence
+}
+
+// WithSource returns a copy of the provenance found in another source
+func (p FieldProvenance) WithSource(source TextSource) FieldProvenance {
+	p.source = source
+	return p
+}
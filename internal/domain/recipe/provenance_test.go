@@ -0,0 +1,92 @@
+package recipe
+
+import (
+	"testing"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestNewFieldProvenance(t *testing.T) {
+	tests := []struct {
+		name           string
+		field          string
+		source         TextSource
+		confidence     float64
+		wantErr        bool
+		wantSource     TextSource
+		wantConfidence float64
+	}{
+		{
+			name:           "valid provenance",
+			field:          FieldQuantities,
+			source:         TextSourceTranscript,
+			confidence:     0.8,
+			wantSource:     TextSourceTranscript,
+			wantConfidence: 0.8,
+		},
+		{
+			name:           "unknown source is inferred",
+			field:          FieldServings,
+			source:         "guess",
+			confidence:     0.3,
+			wantSource:     TextSourceInferred,
+			wantConfidence: 0.3,
+		},
+		{
+			name:           "confidence is clamped",
+			field:          FieldTitle,
+			source:         TextSourceCaptions,
+			confidence:     1.7,
+			wantSource:     TextSourceCaptions,
+			wantConfidence: 1,
+		},
+		{
+			name:    "empty field",
+			field:   "  ",
+			source:  TextSourceCaptions,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFieldProvenance(tt.field, tt.source, tt.confidence)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewFieldProvenance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Source() != tt.wantSource {
+				t.Errorf("Source() = %v, want %v", got.Source(), tt.wantSource)
+			}
+			if got.Confidence() != tt.wantConfidence {
+				t.Errorf("Confidence() = %v, want %v", got.Confidence(), tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestRecipe_NeedsQuantityCheck(t *testing.T) {
+	ingredient, _ := NewIngredient("flour", "2", "cups", "")
+	instruction, _ := NewInstruction(1, "Mix", nil)
+	source, _ := NewSource("https://example.com", PlatformWeb, "Chef")
+	rec, _ := NewRecipe(shared.NewID(), "Cake", []Ingredient{ingredient}, []Instruction{instruction}, source, "", "")
+
+	if rec.NeedsQuantityCheck() {
+		t.Error("NeedsQuantityCheck() = true without provenance, want false")
+	}
+
+	title, _ := NewFieldProvenance(FieldTitle, TextSourceInferred, 0.2)
+	quantities, _ := NewFieldProvenance(FieldQuantities, TextSourceTranscript, 0.9)
+	rec.SetProvenance([]FieldProvenance{title, quantities})
+	if rec.NeedsQuantityCheck() {
+		t.Error("NeedsQuantityCheck() = true with confident quantities, want false")
+	}
+
+	quantities, _ = NewFieldProvenance(FieldQuantities, TextSourceTranscript, 0.4)
+	rec.SetProvenance([]FieldProvenance{title, quantities})
+	if !rec.NeedsQuantityCheck() {
+		t.Error("NeedsQuantityCheck() = false with unsure quantities, want true")
+	}
+}
@@ -0,0 +1,85 @@
+package recipe
+
+import (
+	"strings"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Substitution is an ingredient swapped for another in a remix
+type Substitution struct {
+	Original    string
+	Replacement string
+	Reason      string // why it works, e.g. "smoky and firm like the pork"
+}
+
+// Remix links a recipe to the recipe it was remixed from and records the
+// substitutions that adapted it to a goal, e.g. "vegan" or "gluten-free".
+// Unlike a Variant, a remix is saved as a recipe of its own.
+type Remix struct {
+	ParentID      RecipeID
+	ParentTitle   string
+	Goal          string
+	Substitutions []Substitution
+}
+
+// NewRemix creates the link of a remix to its parent recipe
+func NewRemix(parentID RecipeID, parentTitle, goal string, substitutions []Substitution) (*Remix, error) {
+	goal = NormalizeRemixGoal(goal)
+	if parentID.IsEmpty() || goal == "" {
+		return nil, shared.ErrInvalidInput
+	}
+
+	var subs []Substitution
+	for _, s := range substitutions {
+		s.Original = strings.TrimSpace(s.Original)
+		s.Replacement = strings.TrimSpace(s.Replacement)
+		if s.Original == "" || s.Replacement == "" {
+			continue
+		}
+		s.Reason = strings.TrimSpace(s.Reason)
+		subs = append(subs, s)
+	}
+
+	return &Remix{
+		ParentID:      parentID,
+		ParentTitle:   strings.TrimSpace(parentTitle),
+		Goal:          goal,
+		Substitutions: subs,
+	}, nil
+}
+
+// NormalizeRemixGoal turns what the user asked for into a short goal, e.g.
+// "to be Vegan" into "vegan" and "make it gluten free" into "gluten free"
+func NormalizeRemixGoal(goal string) string {
+	goal = strings.Join(strings.Fields(strings.ToLower(goal)), " ")
+	for _, prefix := range []string{"make it", "make this", "to be", "into", "to", "para ser", "para ficar", "para"} {
+		if goal == prefix {
+			return ""
+		}
+		goal = strings.TrimPrefix(goal, prefix+" ")
+	}
+	return strings.Trim(goal, " .!?")
+}
+
+// SubstitutionFor returns the substitution whose replacement an ingredient is, matched by name
+func (m *Remix) SubstitutionFor(ingredient string) (Substitution, bool) {
+	name := strings.ToLower(strings.TrimSpace(ingredient))
+	if name == "" {
+		return Substitution{}, false
+	}
+	for _, s := range m.Substitutions {
+		replacement := strings.ToLower(s.Replacement)
+		if replacement == name || strings.Contains(name, replacement) {
+			return s, true
+		}
+	}
+	return Substitution{}, false
+}
+
+// Clone returns a copy of the remix that shares no mutable state with the original
+func (m *Remix) Clone() *Remix {
+	cp := *m
+	cp.Substitutions = append([]Substitution(nil), m.Substitutions...)
+	return &cp
+}
@@ -0,0 +1,56 @@
+package recipe
+
+import "testing"
+
+func TestNormalizeRemixGoal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"vegan", "vegan"},
+		{"to be Vegan", "vegan"},
+		{"make it  gluten-free!", "gluten-free"},
+		{"into a low carb dish", "a low carb dish"},
+		{"para ser vegana", "vegana"},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeRemixGoal(tt.input); got != tt.want {
+			t.Errorf("NormalizeRemixGoal(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNewRemix(t *testing.T) {
+	rec := newVersionTestRecipe(t)
+
+	if _, err := NewRemix(rec.ID(), rec.Title(), "make it", nil); err == nil {
+		t.Error("NewRemix() without a goal should fail")
+	}
+
+	remix, err := NewRemix(rec.ID(), rec.Title(), "to be vegan", []Substitution{
+		{Original: "guanciale", Replacement: " smoked tofu ", Reason: "smoky and firm"},
+		{Original: "eggs", Replacement: ""},
+	})
+	if err != nil {
+		t.Fatalf("NewRemix() error = %v", err)
+	}
+	if remix.Goal != "vegan" || len(remix.Substitutions) != 1 {
+		t.Errorf("NewRemix() = %+v, want the vegan goal and the complete substitution only", remix)
+	}
+
+	if s, ok := remix.SubstitutionFor("Smoked tofu, diced"); !ok || s.Original != "guanciale" {
+		t.Errorf("SubstitutionFor() = %+v, %v; want the guanciale substitution", s, ok)
+	}
+	if _, ok := remix.SubstitutionFor("spaghetti"); ok {
+		t.Error("SubstitutionFor() matched an ingredient that wasn't substituted")
+	}
+
+	rec.SetRemix(remix)
+	cp := rec.Clone()
+	cp.Remix().Substitutions[0].Replacement = "tempeh"
+	if rec.Remix().Substitutions[0].Replacement != "smoked tofu" || rec.ParentRecipeID() != rec.ID() {
+		t.Error("Clone() shares the remix with the original")
+	}
+}
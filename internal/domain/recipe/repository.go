@@ -1,6 +1,9 @@
 package recipe
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // IngredientFilter represents complex ingredient filtering with AND/OR/NOT logic
 type IngredientFilter struct {
@@ -14,17 +17,26 @@ type Repository interface {
 	// Save persists a recipe
 	Save(ctx context.Context, recipe *Recipe) error
 
+	// SaveAll persists several recipes in as few writes as the store allows and
+	// returns how many were saved, in order, before an error
+	SaveAll(ctx context.Context, recipes []*Recipe) (int, error)
+
 	// FindByID retrieves a recipe by its ID
 	FindByID(ctx context.Context, id RecipeID) (*Recipe, error)
 
 	// FindByUserID retrieves all recipes for a user
 	FindByUserID(ctx context.Context, userID UserID) ([]*Recipe, error)
 
+	// FindSummariesByUserID retrieves summaries of all recipes for a user, newest first,
+	// loading only the fields kept by Recipe.Summary
+	FindSummariesByUserID(ctx context.Context, userID UserID) ([]*Recipe, error)
+
 	// FindByUserIDAndCategory retrieves recipes for a user filtered by category
 	FindByUserIDAndCategory(ctx context.Context, userID UserID, category Category) ([]*Recipe, error)
 
-	// FindByUserIDAndFilters retrieves recipes for a user with optional category and dietary tag filters
-	FindByUserIDAndFilters(ctx context.Context, userID UserID, category *Category, dietaryTags []DietaryTag) ([]*Recipe, error)
+	// FindByUserIDAndFilters retrieves recipes for a user with optional category, dietary tag
+	// and source language filters; an empty language matches any
+	FindByUserIDAndFilters(ctx context.Context, userID UserID, category *Category, dietaryTags []DietaryTag, language string) ([]*Recipe, error)
 
 	// SearchByIngredient searches recipes containing a specific ingredient in title or ingredients
 	SearchByIngredient(ctx context.Context, userID UserID, ingredient string) ([]*Recipe, error)
@@ -38,9 +50,31 @@ type Repository interface {
 	// GetCategoryCounts returns the count of recipes per category for a user
 	GetCategoryCounts(ctx context.Context, userID UserID) (map[Category]int, error)
 
+	// FindByUserIDAndAuthor retrieves recipes for a user saved from a source author
+	FindByUserIDAndAuthor(ctx context.Context, userID UserID, author string) ([]*Recipe, error)
+
+	// GetAuthorCounts returns the count of recipes per source author for a user
+	GetAuthorCounts(ctx context.Context, userID UserID) (map[string]int, error)
+
+	// FindByUserIDAndPlatform retrieves recipes for a user saved from a source platform
+	FindByUserIDAndPlatform(ctx context.Context, userID UserID, platform Platform) ([]*Recipe, error)
+
+	// GetPlatformCounts returns the count of recipes per source platform for a user
+	GetPlatformCounts(ctx context.Context, userID UserID) (map[Platform]int, error)
+
+	// FindByUserIDAndFlavor retrieves recipes for a user classified with a flavor profile
+	FindByUserIDAndFlavor(ctx context.Context, userID UserID, flavor Flavor) ([]*Recipe, error)
+
+	// FindByUserIDAndDateRange retrieves recipes for a user saved within a date range, newest first
+	FindByUserIDAndDateRange(ctx context.Context, userID UserID, saved DateRange) ([]*Recipe, error)
+
 	// Update updates an existing recipe
 	Update(ctx context.Context, recipe *Recipe) error
 
+	// StripTranscripts removes the transcripts and captions of every user's recipes
+	// saved before the cutoff and returns how many recipes had either
+	StripTranscripts(ctx context.Context, savedBefore time.Time) (int, error)
+
 	// Delete removes a recipe
 	Delete(ctx context.Context, id RecipeID) error
 }
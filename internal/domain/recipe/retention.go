@@ -0,0 +1,12 @@
+package recipe
+
+// StripTranscript removes the transcript and captions kept with the recipe, which
+// are only needed to extract it. The recipe keeps its updated date, as nothing the
+// user reads changes. Returns false when it had neither.
+func (r *Recipe) StripTranscript() bool {
+	if r.transcript == "" && r.captions == "" {
+		return false
+	}
+	r.transcript, r.captions = "", ""
+	return true
+}
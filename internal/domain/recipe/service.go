@@ -12,14 +12,23 @@ func NewService() *Service {
 	return &Service{}
 }
 
-// MergeTextSources combines captions and transcript into a single text for LLM processing
-func (s *Service) MergeTextSources(captions, transcript string) string {
+// MergeTextSources combines captions, text shown on screen, the video timeline and transcript
+// into a single text for LLM processing
+func (s *Service) MergeTextSources(captions, onScreenText, timeline, transcript string) string {
 	var parts []string
 
 	if captions = strings.TrimSpace(captions); captions != "" {
 		parts = append(parts, "CAPTIONS/DESCRIPTION:", captions, "")
 	}
 
+	if onScreenText = strings.TrimSpace(onScreenText); onScreenText != "" {
+		parts = append(parts, "ON-SCREEN TEXT:", onScreenText, "")
+	}
+
+	if timeline = strings.TrimSpace(timeline); timeline != "" {
+		parts = append(parts, "VIDEO TIMELINE:", timeline, "")
+	}
+
 	if transcript = strings.TrimSpace(transcript); transcript != "" {
 		parts = append(parts, "VIDEO TRANSCRIPT:", transcript)
 	}
@@ -27,6 +36,42 @@ func (s *Service) MergeTextSources(captions, transcript string) string {
 	return strings.Join(parts, "\n")
 }
 
+// SplitTranscript splits a long transcript into chunks of at most maxChars characters,
+// breaking after sentences where possible so no ingredient or step is cut in half.
+// Transcripts that fit in one chunk are returned as is.
+func (s *Service) SplitTranscript(transcript string, maxChars int) []string {
+	transcript = strings.TrimSpace(transcript)
+	if transcript == "" {
+		return nil
+	}
+	if maxChars <= 0 || len(transcript) <= maxChars {
+		return []string{transcript}
+	}
+
+	var chunks []string
+	for len(transcript) > maxChars {
+		cut := sentenceBreak(transcript[:maxChars])
+		chunks = append(chunks, strings.TrimSpace(transcript[:cut]))
+		transcript = strings.TrimSpace(transcript[cut:])
+	}
+	if transcript != "" {
+		chunks = append(chunks, transcript)
+	}
+	return chunks
+}
+
+// sentenceBreak returns where to cut the text: after its last sentence or line,
+// else after its last word, else at its end
+func sentenceBreak(text string) int {
+	if i := strings.LastIndexAny(text, ".!?\n"); i > len(text)/2 {
+		return i + 1
+	}
+	if i := strings.LastIndex(text, " "); i > 0 {
+		return i + 1
+	}
+	return len(text)
+}
+
 // ValidateRecipe validates a recipe according to domain rules
 func (s *Service) ValidateRecipe(recipe *Recipe) error {
 	return recipe.Validate()
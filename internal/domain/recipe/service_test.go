@@ -0,0 +1,47 @@
+package recipe
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestService_SplitTranscript(t *testing.T) {
+	s := NewService()
+
+	if got := s.SplitTranscript("  ", 100); got != nil {
+		t.Errorf("SplitTranscript(blank) = %v, want nil", got)
+	}
+
+	if got := s.SplitTranscript("Boil the pasta.", 100); len(got) != 1 || got[0] != "Boil the pasta." {
+		t.Errorf("SplitTranscript(short) = %v, want the transcript as is", got)
+	}
+
+	transcript := "Boil the pasta for ten minutes. Fry the garlic in olive oil. Add the tomatoes and simmer. Toss everything together."
+	chunks := s.SplitTranscript(transcript, 45)
+	if len(chunks) < 3 {
+		t.Fatalf("SplitTranscript() = %d chunks, want at least 3: %q", len(chunks), chunks)
+	}
+	for _, chunk := range chunks {
+		if len(chunk) > 45 {
+			t.Errorf("chunk %q is longer than 45 characters", chunk)
+		}
+		if !strings.HasSuffix(chunk, ".") {
+			t.Errorf("chunk %q does not end at a sentence", chunk)
+		}
+	}
+	if joined := strings.Join(chunks, " "); joined != transcript {
+		t.Errorf("chunks joined = %q, want %q", joined, transcript)
+	}
+}
+
+func TestService_SplitTranscript_NoSentences(t *testing.T) {
+	chunks := NewService().SplitTranscript("then you add the onion and then the garlic and then the wine", 20)
+	for _, chunk := range chunks {
+		if len(chunk) > 20 {
+			t.Errorf("chunk %q is longer than 20 characters", chunk)
+		}
+		if strings.HasPrefix(chunk, " ") || strings.HasSuffix(chunk, " ") {
+			t.Errorf("chunk %q is not cut between words", chunk)
+		}
+	}
+}
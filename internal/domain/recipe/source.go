@@ -1,9 +1,11 @@
 package recipe
 
 import (
+	"fmt"
 	"net/url"
 	"receipt-bot/internal/domain/shared"
 	"strings"
+	"time"
 )
 
 // Platform represents the source platform of a recipe
@@ -15,6 +17,9 @@ const (
 	PlatformInstagram Platform = "instagram"
 	PlatformWeb       Platform = "web"
 	PlatformUnknown   Platform = "unknown"
+
+	// PlatformAIGenerated marks recipes the LLM proposed from a photo of a dish or remixed from another recipe
+	PlatformAIGenerated Platform = "ai-generated"
 )
 
 // Source represents the origin of a recipe (Value Object)
@@ -22,6 +27,9 @@ type Source struct {
 	url      string
 	platform Platform
 	author   string
+	segment  int // position of the recipe in content holding several, 0 when it holds one
+
+	attribution Attribution // who published the content and where, as far as the scraper could tell
 }
 
 // NewSource creates a new Source
@@ -51,6 +59,13 @@ func NewSource(rawURL string, platform Platform, author string) (Source, error)
 	}, nil
 }
 
+// NewGeneratedSource creates the Source of a recipe the LLM proposed from a
+// photo of a dish or remixed from another recipe. Generated recipes have no URL
+// and no author.
+func NewGeneratedSource() Source {
+	return Source{platform: PlatformAIGenerated}
+}
+
 // URL returns the source URL
 func (s Source) URL() string {
 	return s.url
@@ -66,9 +81,52 @@ func (s Source) Author() string {
 	return s.author
 }
 
+// Segment returns the position, from 1, of the recipe in content that holds several
+// recipes, such as a compilation video. It is 0 when the content holds a single recipe.
+func (s Source) Segment() int {
+	return s.segment
+}
+
+// WithSegment returns a copy of the source marking the recipe as the nth of its content
+func (s Source) WithSegment(n int) Source {
+	if n < 0 {
+		n = 0
+	}
+	s.segment = n
+	return s
+}
+
+// TimestampURL returns a link to the given point of the source video, or "" when
+// the platform has no such links
+func (s Source) TimestampURL(at time.Duration) string {
+	return VideoTimestampURL(s.url, s.platform, at)
+}
+
+// VideoTimestampURL returns a link that starts the video at the given point.
+// Only YouTube links support it; "" is returned for other platforms.
+func VideoTimestampURL(rawURL string, platform Platform, at time.Duration) string {
+	if platform != PlatformYouTube {
+		return ""
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+
+	query := parsed.Query()
+	query.Set("t", fmt.Sprintf("%ds", int(at.Seconds())))
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// IsGenerated reports whether the recipe was proposed by the LLM rather than published by someone
+func (s Source) IsGenerated() bool {
+	return s.platform == PlatformAIGenerated
+}
+
 // IsValid checks if the source is valid
 func (s Source) IsValid() bool {
-	return s.url != "" && isValidPlatform(s.platform)
+	return s.IsGenerated() || (s.url != "" && isValidPlatform(s.platform))
 }
 
 // isValidPlatform checks if a platform is valid
@@ -97,3 +155,76 @@ func DetectPlatform(rawURL string) Platform {
 
 	return PlatformWeb
 }
+
+// platformAliases maps the ways users name a platform to the platform
+var platformAliases = map[string]Platform{
+	"tiktok":       PlatformTikTok,
+	"tik tok":      PlatformTikTok,
+	"youtube":      PlatformYouTube,
+	"yt":           PlatformYouTube,
+	"shorts":       PlatformYouTube,
+	"instagram":    PlatformInstagram,
+	"insta":        PlatformInstagram,
+	"ig":           PlatformInstagram,
+	"reels":        PlatformInstagram,
+	"web":          PlatformWeb,
+	"website":      PlatformWeb,
+	"websites":     PlatformWeb,
+	"site":         PlatformWeb,
+	"sites":        PlatformWeb,
+	"blog":         PlatformWeb,
+	"blogs":        PlatformWeb,
+	"ai":           PlatformAIGenerated,
+	"ai-generated": PlatformAIGenerated,
+	"generated":    PlatformAIGenerated,
+}
+
+// ParsePlatform returns the platform a user names, like "TikTok", "insta" or
+// "websites". Returns false if the name is not a known platform.
+func ParsePlatform(name string) (Platform, bool) {
+	key := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	p, ok := platformAliases[key]
+	return p, ok
+}
+
+// CountPlatforms counts recipes per source platform
+func CountPlatforms(recipes []*Recipe) map[Platform]int {
+	counts := make(map[Platform]int)
+	for _, rec := range recipes {
+		counts[rec.Source().Platform()]++
+	}
+	return counts
+}
+
+// NormalizeAuthor returns an author in the form used for matching:
+// lowercase, trimmed and without a leading @
+func NormalizeAuthor(author string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(author), "@"))
+}
+
+// IsFrom reports whether the content was published by the given author,
+// ignoring case and a leading @
+func (s Source) IsFrom(author string) bool {
+	key := NormalizeAuthor(author)
+	return key != "" && NormalizeAuthor(s.author) == key
+}
+
+// CountAuthors counts recipes per source author. Spellings of the same author
+// are counted together under the first one seen; recipes without an author are skipped
+func CountAuthors(recipes []*Recipe) map[string]int {
+	names := make(map[string]string)
+	counts := make(map[string]int)
+	for _, rec := range recipes {
+		key := NormalizeAuthor(rec.Source().Author())
+		if key == "" {
+			continue
+		}
+		name, ok := names[key]
+		if !ok {
+			name = rec.Source().Author()
+			names[key] = name
+		}
+		counts[name]++
+	}
+	return counts
+}
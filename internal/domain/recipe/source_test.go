@@ -1,7 +1,9 @@
 package recipe
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDetectPlatform(t *testing.T) {
@@ -105,7 +107,7 @@ func TestNewSource(t *testing.T) {
 					t.Errorf("NewSource() expected error but got nil")
 					return
 				}
-				if tt.errContains != "" && err.Error() != tt.errContains {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
 					t.Errorf("NewSource() error = %v, want error containing %v", err, tt.errContains)
 				}
 				return
@@ -130,3 +132,139 @@ func TestNewSource(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		name string
+		want Platform
+		ok   bool
+	}{
+		{"TikTok", PlatformTikTok, true},
+		{" tik  tok ", PlatformTikTok, true},
+		{"YT", PlatformYouTube, true},
+		{"insta", PlatformInstagram, true},
+		{"websites", PlatformWeb, true},
+		{"AI", PlatformAIGenerated, true},
+		{"myspace", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParsePlatform(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParsePlatform(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSource_IsFrom(t *testing.T) {
+	source, _ := NewSource("https://www.tiktok.com/@thatpastaguy/video/1", PlatformTikTok, "@ThatPastaGuy")
+
+	for _, author := range []string{"@thatpastaguy", "thatpastaguy", " ThatPastaGuy "} {
+		if !source.IsFrom(author) {
+			t.Errorf("IsFrom(%q) = false, want true", author)
+		}
+	}
+	for _, author := range []string{"", "@", "pastaguy"} {
+		if source.IsFrom(author) {
+			t.Errorf("IsFrom(%q) = true, want false", author)
+		}
+	}
+}
+
+func TestNewGeneratedSource(t *testing.T) {
+	source := NewGeneratedSource()
+
+	if !source.IsGenerated() || !source.IsValid() {
+		t.Errorf("IsGenerated() = %v, IsValid() = %v, want both true", source.IsGenerated(), source.IsValid())
+	}
+	if source.URL() != "" || source.Author() != "" {
+		t.Errorf("generated source has URL %q and author %q, want neither", source.URL(), source.Author())
+	}
+
+	published, _ := NewSource("https://example.com/recipe", PlatformWeb, "")
+	if published.IsGenerated() {
+		t.Error("IsGenerated() = true for a published recipe")
+	}
+}
+
+func TestSource_WithSegment(t *testing.T) {
+	source, _ := NewSource("https://www.youtube.com/watch?v=abc", PlatformYouTube, "Chef")
+
+	if source.Segment() != 0 {
+		t.Errorf("Segment() = %d, want 0 for a new source", source.Segment())
+	}
+
+	second := source.WithSegment(2)
+	if second.Segment() != 2 || second.URL() != source.URL() || second.Author() != source.Author() {
+		t.Errorf("WithSegment(2) = %+v, want the same source as the 2nd recipe", second)
+	}
+	if source.Segment() != 0 {
+		t.Error("WithSegment() changed the original source")
+	}
+}
+
+func TestSource_TimestampURL(t *testing.T) {
+	tests := []struct {
+		rawURL   string
+		platform Platform
+		want     string
+	}{
+		{"https://www.youtube.com/watch?v=abc", PlatformYouTube, "https://www.youtube.com/watch?t=95s&v=abc"},
+		{"https://www.youtube.com/watch?v=abc&t=10s", PlatformYouTube, "https://www.youtube.com/watch?t=95s&v=abc"},
+		{"https://youtu.be/abc", PlatformYouTube, "https://youtu.be/abc?t=95s"},
+		{"https://www.tiktok.com/@chef/video/1", PlatformTikTok, ""},
+	}
+
+	for _, tt := range tests {
+		source, _ := NewSource(tt.rawURL, tt.platform, "")
+		if got := source.TimestampURL(95 * time.Second); got != tt.want {
+			t.Errorf("TimestampURL(%q) = %q, want %q", tt.rawURL, got, tt.want)
+		}
+	}
+}
+
+func TestSource_Credit(t *testing.T) {
+	published := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		author      string
+		attribution Attribution
+		want        string
+	}{
+		{
+			name:        "author, handle and date",
+			author:      "Ana Silva",
+			attribution: NewAttribution("chefana", "https://www.tiktok.com/@chefana/video/1", published),
+			want:        "Recipe by Ana Silva (@chefana), published 2 March 2024. Original: https://www.tiktok.com/@chefana/video/1",
+		},
+		{
+			name:        "handle naming the author",
+			author:      "chefana",
+			attribution: NewAttribution("@chefana", "", time.Time{}),
+			want:        "Recipe by chefana. Original: https://vm.tiktok.com/abc",
+		},
+		{
+			name:        "unknown author",
+			author:      UnknownAuthor,
+			attribution: NewAttribution("", "not a link", time.Time{}),
+			want:        "Recipe. Original: https://vm.tiktok.com/abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewSource("https://vm.tiktok.com/abc", PlatformTikTok, tt.author)
+			if err != nil {
+				t.Fatalf("NewSource() unexpected error = %v", err)
+			}
+			if got := source.WithAttribution(tt.attribution).Credit(); got != tt.want {
+				t.Errorf("Credit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := NewGeneratedSource().Credit(); got != "" {
+		t.Errorf("Credit() of a generated recipe = %q, want none", got)
+	}
+}
@@ -0,0 +1,24 @@
+package recipe
+
+// Summary returns a copy of the recipe with only the fields shown in lists and
+// used for matching ingredients: no instructions, transcript, captions,
+// translations or provenance. The difficulty is kept as scored from the full
+// recipe. Summaries cannot be saved.
+func (r *Recipe) Summary() *Recipe {
+	cp := r.Clone()
+	cp.difficultyScore = r.DifficultyScore()
+	cp.instructions = nil
+	cp.transcript = ""
+	cp.captions = ""
+	cp.translatedTitle = nil
+	cp.translatedIngredients = nil
+	cp.translatedInstructions = nil
+	cp.provenance = nil
+	cp.summary = true
+	return cp
+}
+
+// IsSummary reports whether the recipe is a summary, missing the fields Summary drops
+func (r *Recipe) IsSummary() bool {
+	return r.summary
+}
@@ -0,0 +1,31 @@
+package recipe
+
+import "testing"
+
+func TestRecipe_Summary(t *testing.T) {
+	ing, _ := NewIngredient("flour", "200", "g", "")
+	inst, _ := NewInstruction(1, "Knead for ten minutes", nil)
+	source, _ := NewSource("https://example.com/bread", PlatformWeb, "Baker")
+	rec, err := NewRecipe(UserID("user-1"), "Bread", []Ingredient{ing}, []Instruction{inst}, source, "knead it well", "")
+	if err != nil {
+		t.Fatalf("NewRecipe() error = %v", err)
+	}
+	score := rec.DifficultyScore()
+
+	summary := rec.Summary()
+	if !summary.IsSummary() || rec.IsSummary() {
+		t.Fatalf("IsSummary() = %v for the summary, %v for the recipe", summary.IsSummary(), rec.IsSummary())
+	}
+	if summary.Title() != rec.Title() || len(summary.Ingredients()) != len(rec.Ingredients()) {
+		t.Errorf("summary lost list fields: %q with %d ingredients", summary.Title(), len(summary.Ingredients()))
+	}
+	if len(summary.Instructions()) != 0 || summary.Transcript() != "" {
+		t.Errorf("summary kept %d instructions and transcript %q", len(summary.Instructions()), summary.Transcript())
+	}
+	if summary.DifficultyScore() != score {
+		t.Errorf("summary DifficultyScore() = %d, want %d as scored with the instructions", summary.DifficultyScore(), score)
+	}
+	if len(rec.Instructions()) == 0 {
+		t.Error("Summary() changed the recipe")
+	}
+}
@@ -0,0 +1,109 @@
+package recipe
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Appliance is a cooking appliance a recipe can be converted to
+type Appliance string
+
+const (
+	ApplianceSlowCooker Appliance = "slow_cooker"
+	ApplianceInstantPot Appliance = "instant_pot"
+)
+
+// String returns the display name of the appliance
+func (a Appliance) String() string {
+	switch a {
+	case ApplianceSlowCooker:
+		return "Slow cooker"
+	case ApplianceInstantPot:
+		return "Instant Pot"
+	default:
+		return string(a)
+	}
+}
+
+// IsValid checks if the appliance is valid
+func (a Appliance) IsValid() bool {
+	switch a {
+	case ApplianceSlowCooker, ApplianceInstantPot:
+		return true
+	default:
+		return false
+	}
+}
+
+// AllAppliances returns all appliances recipes can be converted to
+func AllAppliances() []Appliance {
+	return []Appliance{ApplianceSlowCooker, ApplianceInstantPot}
+}
+
+// ParseAppliance parses a string into an Appliance.
+// Returns the appliance and a boolean indicating if it's valid
+func ParseAppliance(s string) (Appliance, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer("-", " ", "_", " ").Replace(s)
+
+	switch s {
+	case "slow cooker", "slowcooker", "crockpot", "crock pot", "panela elétrica", "panela eletrica", "panela de cozimento lento":
+		return ApplianceSlowCooker, true
+	case "instant pot", "instantpot", "pressure cooker", "multicooker", "panela de pressão", "panela de pressao", "panela de pressão elétrica", "panela de pressao eletrica":
+		return ApplianceInstantPot, true
+	default:
+		return "", false
+	}
+}
+
+// Variant is an alternate version of a recipe adapted to cook in another appliance.
+// It is attached to the recipe it was made from instead of being saved as a new recipe.
+type Variant struct {
+	RecipeID     RecipeID
+	Appliance    Appliance
+	Instructions []Instruction
+	PrepTime     *time.Duration
+	CookTime     *time.Duration
+	Notes        string // advice that doesn't fit a step, e.g. liquid adjustments
+	CreatedAt    time.Time
+}
+
+// NewVariant creates a variant of a recipe for an appliance
+func NewVariant(recipeID RecipeID, appliance Appliance, instructions []Instruction, prepTime, cookTime *time.Duration, notes string) (*Variant, error) {
+	if recipeID.IsEmpty() || !appliance.IsValid() {
+		return nil, shared.ErrInvalidInput
+	}
+	if len(instructions) == 0 {
+		return nil, shared.ErrNoInstructions
+	}
+
+	return &Variant{
+		RecipeID:     recipeID,
+		Appliance:    appliance,
+		Instructions: instructions,
+		PrepTime:     prepTime,
+		CookTime:     cookTime,
+		Notes:        strings.TrimSpace(notes),
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// IsStale reports whether the recipe changed after the variant was made from it
+func (v *Variant) IsStale(rec *Recipe) bool {
+	return rec.UpdatedAt().After(v.CreatedAt)
+}
+
+// VariantRepository persists the appliance variants of recipes (Port)
+type VariantRepository interface {
+	// SaveVariant stores a variant, replacing the recipe's previous variant for the same appliance
+	SaveVariant(ctx context.Context, variant *Variant) error
+
+	// FindVariants returns all variants of a recipe
+	FindVariants(ctx context.Context, recipeID RecipeID) ([]*Variant, error)
+
+	// FindVariant returns the variant of a recipe for an appliance
+	FindVariant(ctx context.Context, recipeID RecipeID, appliance Appliance) (*Variant, error)
+}
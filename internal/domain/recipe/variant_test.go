@@ -0,0 +1,59 @@
+package recipe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAppliance(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Appliance
+		ok    bool
+	}{
+		{"Instant Pot", ApplianceInstantPot, true},
+		{"instant-pot", ApplianceInstantPot, true},
+		{"pressure cooker", ApplianceInstantPot, true},
+		{"panela de pressão", ApplianceInstantPot, true},
+		{"slow cooker", ApplianceSlowCooker, true},
+		{"Crockpot", ApplianceSlowCooker, true},
+		{"slow_cooker", ApplianceSlowCooker, true},
+		{"air fryer", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseAppliance(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseAppliance(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNewVariant(t *testing.T) {
+	rec := newVersionTestRecipe(t)
+	inst, _ := NewInstruction(1, "Pressure cook on high for 10 minutes", nil)
+
+	if _, err := NewVariant(rec.ID(), "air_fryer", []Instruction{inst}, nil, nil, ""); err == nil {
+		t.Error("NewVariant() with an unknown appliance should fail")
+	}
+	if _, err := NewVariant(rec.ID(), ApplianceInstantPot, nil, nil, nil, ""); err == nil {
+		t.Error("NewVariant() without instructions should fail")
+	}
+
+	variant, err := NewVariant(rec.ID(), ApplianceInstantPot, []Instruction{inst}, nil, nil, "  Use 1 cup of water  ")
+	if err != nil {
+		t.Fatalf("NewVariant() error = %v", err)
+	}
+	if variant.Notes != "Use 1 cup of water" {
+		t.Errorf("Notes = %q, want trimmed notes", variant.Notes)
+	}
+	if variant.IsStale(rec) {
+		t.Error("IsStale() = true for a variant made after the recipe changed")
+	}
+
+	variant.CreatedAt = rec.UpdatedAt().Add(-time.Minute)
+	if !variant.IsStale(rec) {
+		t.Error("IsStale() = false for a variant made before the recipe changed")
+	}
+}
@@ -0,0 +1,246 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Reasons recorded alongside a recipe version
+const (
+	VersionReasonEdit      = "edited"
+	VersionReasonReextract = "re-extracted"
+	VersionReasonRevert    = "reverted"
+)
+
+// Snapshot captures the editable content of a recipe at a point in time.
+// Identity, ownership and source are never versioned.
+type Snapshot struct {
+	Title                  string
+	Ingredients            []Ingredient
+	Instructions           []Instruction
+	PrepTime               *time.Duration
+	CookTime               *time.Duration
+	Servings               *int
+	Category               Category
+	Cuisine                string
+	DietaryTags            []DietaryTag
+	Tags                   []string
+	SourceLanguage         string
+	TranslatedTitle        *string
+	TranslatedIngredients  []Ingredient
+	TranslatedInstructions []Instruction
+	NormalizedIngredients  []string
+	Flavors                []Flavor // nil for versions stored before flavors were kept
+}
+
+// Snapshot returns the current content of the recipe
+func (r *Recipe) Snapshot() Snapshot {
+	cp := r.Clone()
+	return Snapshot{
+		Title:                  cp.title,
+		Ingredients:            cp.ingredients,
+		Instructions:           cp.instructions,
+		PrepTime:               cp.prepTime,
+		CookTime:               cp.cookTime,
+		Servings:               cp.servings,
+		Category:               cp.category,
+		Cuisine:                cp.cuisine,
+		DietaryTags:            cp.dietaryTags,
+		Tags:                   cp.tags,
+		SourceLanguage:         cp.sourceLanguage,
+		TranslatedTitle:        cp.translatedTitle,
+		TranslatedIngredients:  cp.translatedIngredients,
+		TranslatedInstructions: cp.translatedInstructions,
+		NormalizedIngredients:  cp.normalizedIngredients,
+		Flavors:                cp.flavors,
+	}
+}
+
+// ApplySnapshot replaces the content of the recipe with the snapshot. The recipe
+// keeps its flavors when the snapshot has none recorded, and always keeps the
+// recipe it was remixed from.
+func (r *Recipe) ApplySnapshot(s Snapshot) {
+	restored := ReconstructRecipeWithNormalizedIngredients(
+		r.id, r.userID, s.Title, s.Ingredients, s.Instructions, r.source,
+		r.transcript, r.captions, s.PrepTime, s.CookTime, s.Servings,
+		s.Category, s.Cuisine, s.DietaryTags, s.Tags, r.CreatedAt(), time.Now(),
+		s.SourceLanguage, s.TranslatedTitle, s.TranslatedIngredients, s.TranslatedInstructions,
+		s.NormalizedIngredients,
+	).Clone()
+	restored.provenance = r.provenance
+	restored.cover = r.cover
+	restored.remix = r.remix
+	if s.Flavors != nil {
+		restored.flavors = append([]Flavor{}, s.Flavors...)
+	} else {
+		restored.flavors = r.flavors
+	}
+	*r = *restored
+	r.difficultyScore = ScoreDifficulty(r.ingredients, r.instructions, r.prepTime, r.cookTime)
+}
+
+// FieldChange describes how a single recipe field differs between two snapshots
+type FieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// String returns a short human-readable description of the change
+func (c FieldChange) String() string {
+	switch {
+	case c.Before == "":
+		return fmt.Sprintf("%s: + %s", c.Field, c.After)
+	case c.After == "":
+		return fmt.Sprintf("%s: − %s", c.Field, c.Before)
+	default:
+		return fmt.Sprintf("%s: %s → %s", c.Field, c.Before, c.After)
+	}
+}
+
+// Diff returns the field-level changes needed to go from before to after.
+// List fields report only the removed (Before) and added (After) items.
+func Diff(before, after Snapshot) []FieldChange {
+	var changes []FieldChange
+
+	add := func(field, b, a string) {
+		if b != a {
+			changes = append(changes, FieldChange{Field: field, Before: b, After: a})
+		}
+	}
+	addList := func(field string, b, a []string) {
+		removed, added := listDiff(b, a)
+		if len(removed) > 0 || len(added) > 0 {
+			changes = append(changes, FieldChange{
+				Field:  field,
+				Before: strings.Join(removed, "; "),
+				After:  strings.Join(added, "; "),
+			})
+		}
+	}
+
+	add("title", before.Title, after.Title)
+	addList("ingredients", ingredientStrings(before.Ingredients), ingredientStrings(after.Ingredients))
+	addList("instructions", instructionStrings(before.Instructions), instructionStrings(after.Instructions))
+	add("prep time", formatDuration(before.PrepTime), formatDuration(after.PrepTime))
+	add("cook time", formatDuration(before.CookTime), formatDuration(after.CookTime))
+	add("servings", formatInt(before.Servings), formatInt(after.Servings))
+	add("category", before.Category.String(), after.Category.String())
+	add("cuisine", before.Cuisine, after.Cuisine)
+	addList("dietary tags", dietaryTagStrings(before.DietaryTags), dietaryTagStrings(after.DietaryTags))
+	addList("tags", before.Tags, after.Tags)
+	if before.Flavors != nil && after.Flavors != nil {
+		addList("flavors", flavorStrings(before.Flavors), flavorStrings(after.Flavors))
+	}
+
+	return changes
+}
+
+// Version is a stored snapshot of a recipe taken just before it changed
+type Version struct {
+	Number    int
+	RecipeID  RecipeID
+	Snapshot  Snapshot
+	Changes   []FieldChange // what changed when this version was replaced
+	Reason    string
+	CreatedAt time.Time
+}
+
+// NewVersion records the state of a recipe before it is replaced by next
+func NewVersion(number int, recipeID RecipeID, previous, next Snapshot, reason string) (*Version, error) {
+	if number < 1 || recipeID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &Version{
+		Number:    number,
+		RecipeID:  recipeID,
+		Snapshot:  previous,
+		Changes:   Diff(previous, next),
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// VersionRepository persists the history of recipe versions (Port)
+type VersionRepository interface {
+	// SaveVersion stores a version of a recipe
+	SaveVersion(ctx context.Context, version *Version) error
+
+	// FindVersions returns all versions of a recipe, oldest first
+	FindVersions(ctx context.Context, recipeID RecipeID) ([]*Version, error)
+
+	// FindVersion returns a single version of a recipe
+	FindVersion(ctx context.Context, recipeID RecipeID, number int) (*Version, error)
+}
+
+func listDiff(before, after []string) (removed, added []string) {
+	counts := make(map[string]int, len(before))
+	for _, item := range before {
+		counts[item]++
+	}
+	for _, item := range after {
+		if counts[item] > 0 {
+			counts[item]--
+			continue
+		}
+		added = append(added, item)
+	}
+	for _, item := range before {
+		if counts[item] > 0 {
+			counts[item]--
+			removed = append(removed, item)
+		}
+	}
+	return removed, added
+}
+
+func ingredientStrings(ingredients []Ingredient) []string {
+	out := make([]string, len(ingredients))
+	for i, ing := range ingredients {
+		out[i] = ing.String()
+	}
+	return out
+}
+
+func instructionStrings(instructions []Instruction) []string {
+	out := make([]string, len(instructions))
+	for i, inst := range instructions {
+		out[i] = inst.Text()
+	}
+	return out
+}
+
+func dietaryTagStrings(tags []DietaryTag) []string {
+	out := make([]string, len(tags))
+	for i, tag := range tags {
+		out[i] = string(tag)
+	}
+	return out
+}
+
+func flavorStrings(flavors []Flavor) []string {
+	out := make([]string, len(flavors))
+	for i, flavor := range flavors {
+		out[i] = string(flavor)
+	}
+	return out
+}
+
+func formatDuration(d *time.Duration) string {
+	if d == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d min", int(d.Minutes()))
+}
+
+func formatInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", *n)
+}
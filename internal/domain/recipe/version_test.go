@@ -0,0 +1,145 @@
+package recipe
+
+import (
+	"testing"
+	"time"
+)
+
+func newVersionTestRecipe(t *testing.T) *Recipe {
+	t.Helper()
+
+	ing1, _ := NewIngredient("flour", "200", "g", "")
+	ing2, _ := NewIngredient("sugar", "100", "g", "")
+	inst, _ := NewInstruction(1, "Mix everything", nil)
+	source, _ := NewSource("https://example.com/cake", PlatformWeb, "Chef")
+
+	rec, err := NewRecipe(UserID("user-1"), "Cake", []Ingredient{ing1, ing2}, []Instruction{inst}, source, "", "")
+	if err != nil {
+		t.Fatalf("NewRecipe() error = %v", err)
+	}
+	rec.SetServings(4)
+	return rec
+}
+
+func TestDiff(t *testing.T) {
+	rec := newVersionTestRecipe(t)
+	before := rec.Snapshot()
+
+	if changes := Diff(before, rec.Snapshot()); len(changes) != 0 {
+		t.Fatalf("Diff() of identical snapshots = %v, want none", changes)
+	}
+
+	after := rec.Snapshot()
+	after.Title = "Chocolate Cake"
+	butter, _ := NewIngredient("butter", "50", "g", "")
+	after.Ingredients = []Ingredient{after.Ingredients[0], butter}
+	after.Servings = nil
+
+	changes := Diff(before, after)
+	got := make(map[string]FieldChange)
+	for _, c := range changes {
+		got[c.Field] = c
+	}
+
+	if len(changes) != 3 {
+		t.Errorf("Diff() returned %d changes, want 3: %v", len(changes), changes)
+	}
+	if c := got["title"]; c.Before != "Cake" || c.After != "Chocolate Cake" {
+		t.Errorf("title change = %+v", c)
+	}
+	if c := got["ingredients"]; c.Before != "100 g sugar" || c.After != "50 g butter" {
+		t.Errorf("ingredients change = %+v", c)
+	}
+	if c := got["servings"]; c.Before != "4" || c.After != "" {
+		t.Errorf("servings change = %+v", c)
+	}
+}
+
+func TestRecipe_ApplySnapshot(t *testing.T) {
+	rec := newVersionTestRecipe(t)
+	original := rec.Snapshot()
+
+	edited := rec.Snapshot()
+	edited.Title = "Sponge Cake"
+	edited.Tags = []string{"baking"}
+	rec.ApplySnapshot(edited)
+
+	if rec.Title() != "Sponge Cake" || len(rec.Tags()) != 1 {
+		t.Fatalf("ApplySnapshot() did not apply edits: title=%q tags=%v", rec.Title(), rec.Tags())
+	}
+	if rec.Source().URL() != "https://example.com/cake" || rec.UserID() != "user-1" {
+		t.Error("ApplySnapshot() must not change identity or source")
+	}
+
+	// Mutating the snapshot afterwards must not leak into the recipe
+	edited.Tags[0] = "changed"
+	if rec.Tags()[0] != "baking" {
+		t.Error("ApplySnapshot() shares state with the snapshot")
+	}
+
+	rec.ApplySnapshot(original)
+	if len(Diff(original, rec.Snapshot())) != 0 {
+		t.Error("restoring the original snapshot did not round-trip")
+	}
+}
+
+func TestRecipe_ApplySnapshot_KeepsFlavorsAndRemix(t *testing.T) {
+	rec := newVersionTestRecipe(t)
+	rec.SetFlavors([]Flavor{FlavorSweet})
+	remix, err := NewRemix(RecipeID("parent-1"), "Butter Cake", "vegan", []Substitution{{Original: "butter", Replacement: "margarine"}})
+	if err != nil {
+		t.Fatalf("NewRemix() error = %v", err)
+	}
+	rec.SetRemix(remix)
+	original := rec.Snapshot()
+
+	// A snapshot with other flavors replaces them, as re-extracting does
+	edited := rec.Snapshot()
+	edited.Title = "Spiced Cake"
+	edited.Flavors = []Flavor{FlavorSpicy, FlavorSweet}
+	rec.ApplySnapshot(edited)
+	if got := rec.Flavors(); len(got) != 2 || got[0] != FlavorSpicy {
+		t.Errorf("ApplySnapshot() flavors = %v, want the snapshot's", got)
+	}
+	if rec.Remix() == nil || rec.ParentRecipeID() != "parent-1" || len(rec.Remix().Substitutions) != 1 {
+		t.Errorf("ApplySnapshot() remix = %+v, want it kept", rec.Remix())
+	}
+
+	// Reverting restores the original flavors
+	rec.ApplySnapshot(original)
+	if got := rec.Flavors(); len(got) != 1 || got[0] != FlavorSweet {
+		t.Errorf("reverted flavors = %v, want [sweet]", got)
+	}
+	if len(Diff(original, rec.Snapshot())) != 0 || rec.Remix() == nil {
+		t.Error("restoring the original snapshot did not round-trip")
+	}
+
+	// A version stored before flavors were kept leaves them alone
+	legacy := rec.Snapshot()
+	legacy.Flavors = nil
+	rec.ApplySnapshot(legacy)
+	if got := rec.Flavors(); len(got) != 1 || got[0] != FlavorSweet {
+		t.Errorf("flavors after a snapshot without any = %v, want [sweet] kept", got)
+	}
+}
+
+func TestNewVersion(t *testing.T) {
+	rec := newVersionTestRecipe(t)
+	next := rec.Snapshot()
+	next.Cuisine = "French"
+
+	v, err := NewVersion(1, rec.ID(), rec.Snapshot(), next, VersionReasonEdit)
+	if err != nil {
+		t.Fatalf("NewVersion() error = %v", err)
+	}
+	if v.Snapshot.Cuisine != "" || len(v.Changes) != 1 || v.Changes[0].Field != "cuisine" {
+		t.Errorf("NewVersion() = %+v", v)
+	}
+	if time.Since(v.CreatedAt) > time.Minute {
+		t.Errorf("CreatedAt = %v, want now", v.CreatedAt)
+	}
+
+	if _, err := NewVersion(0, rec.ID(), next, next, VersionReasonEdit); err == nil {
+		t.Error("NewVersion() with number 0 should fail")
+	}
+}
@@ -0,0 +1,167 @@
+// Package report turns the context of a user's failed operation into a bug report
+// the maintainer can act on.
+package report
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// Stage is the step of recipe processing that failed
+type Stage string
+
+const (
+	StageScrape   Stage = "scrape"   // downloading the content
+	StageExtract  Stage = "extract"  // the LLM extraction
+	StageValidate Stage = "validate" // checking the extracted recipe
+	StageSave     Stage = "save"     // storing the recipe
+	StageUnknown  Stage = "unknown"
+)
+
+// Limits of the text kept in a report
+const (
+	maxErrorChars       = 500
+	maxLLMResponseChars = 1000
+	maxNoteChars        = 500
+)
+
+// idBytes is the amount of randomness in a report ID
+const idBytes = 4
+
+// StageError is an error tagged with the stage that failed
+type StageError struct {
+	Stage Stage
+	Err   error
+}
+
+// Error implements the error interface
+func (e *StageError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// StageOf returns the stage an error was tagged with, or StageUnknown
+func StageOf(err error) Stage {
+	var stageErr *StageError
+	if errors.As(err, &stageErr) {
+		return stageErr.Stage
+	}
+	return StageUnknown
+}
+
+// Failure is the context of a failed operation, kept until the user reports it
+type Failure struct {
+	URL         string
+	Platform    string
+	Stage       Stage
+	Error       string
+	LLMResponse string // raw LLM answer, if the failure came from parsing it
+	OccurredAt  time.Time
+}
+
+// ErrorReport is a user's report of a failed operation, with its sanitized context
+type ErrorReport struct {
+	ID        string
+	UserID    UserID
+	Failure   Failure
+	Note      string // what the user said about the problem, optional
+	CreatedAt time.Time
+}
+
+// NewErrorReport creates a report of a failure. Links lose their credentials and
+// tracking parameters, secrets are redacted and long texts are truncated.
+func NewErrorReport(userID UserID, failure Failure, note string, now time.Time) (*ErrorReport, error) {
+	if userID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	b := make([]byte, idBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate report ID: %w", err)
+	}
+
+	failure.URL = SanitizeURL(failure.URL)
+	failure.Error = truncate(Redact(failure.Error), maxErrorChars)
+	failure.LLMResponse = truncate(Redact(failure.LLMResponse), maxLLMResponseChars)
+	if failure.Stage == "" {
+		failure.Stage = StageUnknown
+	}
+
+	return &ErrorReport{
+		ID:        hex.EncodeToString(b),
+		UserID:    userID,
+		Failure:   failure,
+		Note:      truncate(Redact(strings.TrimSpace(note)), maxNoteChars),
+		CreatedAt: now,
+	}, nil
+}
+
+// keptQueryParams are the query parameters that identify content rather than the visitor
+var keptQueryParams = map[string]bool{"v": true, "list": true, "t": true}
+
+// SanitizeURL drops credentials, the fragment and every query parameter that does
+// not identify the content. Unparseable links are dropped entirely.
+func SanitizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	u.User = nil
+	u.Fragment = ""
+
+	query := u.Query()
+	for key := range query {
+		if !keptQueryParams[key] {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// secretPatterns match credentials that could appear in errors and LLM answers
+var secretPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)\b(key|token|secret|password|api_key|access_token)=[^&\s"]+`), "$1=[redacted]"},
+	{regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`), "Bearer [redacted]"},
+	{regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), "[redacted]"},                   // Google API keys
+	{regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}\b`), "[redacted]"},                   // OpenAI API keys
+	{regexp.MustCompile(`\b\d{6,}:[A-Za-z0-9_-]{30,}\b`), "[redacted]"},               // Telegram bot tokens
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[email]"}, // email addresses
+}
+
+// Redact replaces credentials and email addresses in text
+func Redact(text string) string {
+	for _, secret := range secretPatterns {
+		text = secret.pattern.ReplaceAllString(text, secret.replacement)
+	}
+	return text
+}
+
+// truncate shortens text to at most max runes, marking the cut
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "…"
+}
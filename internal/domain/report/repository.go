@@ -0,0 +1,9 @@
+package report
+
+import "context"
+
+// Repository defines the interface for error report persistence (Port)
+type Repository interface {
+	// Save persists an error report
+	Save(ctx context.Context, r *ErrorReport) error
+}
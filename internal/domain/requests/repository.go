@@ -0,0 +1,13 @@
+package requests
+
+import "context"
+
+// Repository defines the interface for request list persistence (Port)
+type Repository interface {
+	// FindByOwner retrieves the request list of a household.
+	// It returns shared.ErrRequestsNotFound if nothing was requested yet.
+	FindByOwner(ctx context.Context, ownerID OwnerID) (*List, error)
+
+	// Save persists a request list, replacing the owner's previous one
+	Save(ctx context.Context, l *List) error
+}
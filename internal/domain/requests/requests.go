@@ -0,0 +1,185 @@
+// Package requests keeps the dishes members of a household ask each other to
+// cook, e.g. "someone please make lasagna this week".
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// OwnerID identifies who shares a request list: the household of a group chat,
+// or a user and the accounts linked to them
+type OwnerID = shared.ID
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// RecipeID represents a unique recipe identifier
+type RecipeID = shared.ID
+
+// MaxOpen is how many requests a list holds at once
+const MaxOpen = 30
+
+// Member is a member of the household, who asks for dishes or cooks them
+type Member struct {
+	ID         UserID
+	TelegramID int64 // the member's private chat with the bot
+	Name       string
+}
+
+// Plan is the recipe a member attached to their meal plan to cook a request
+type Plan struct {
+	Cook     Member
+	RecipeID RecipeID
+	Title    string
+	Day      time.Weekday
+}
+
+// Request is a dish a member asked someone to cook
+type Request struct {
+	Dish        string
+	Requester   Member
+	RequestedAt time.Time
+	Plan        *Plan // nil until someone plans to cook it
+}
+
+// List holds the open requests of a household (Aggregate Root)
+type List struct {
+	ownerID   OwnerID
+	requests  []Request // oldest first
+	updatedAt shared.Timestamp
+}
+
+// NewList creates an empty request list
+func NewList(ownerID OwnerID) (*List, error) {
+	if ownerID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &List{
+		ownerID:   ownerID,
+		requests:  []Request{},
+		updatedAt: shared.NewTimestamp(),
+	}, nil
+}
+
+// ListData contains data for reconstructing a request list from storage
+type ListData struct {
+	OwnerID   OwnerID
+	Requests  []Request
+	UpdatedAt time.Time
+}
+
+// ReconstructList reconstructs a request list from stored data (for repository)
+func ReconstructList(data ListData) *List {
+	return &List{
+		ownerID:   data.OwnerID,
+		requests:  append([]Request{}, data.Requests...),
+		updatedAt: shared.NewTimestampFromTime(data.UpdatedAt),
+	}
+}
+
+// OwnerID returns who shares the list
+func (l *List) OwnerID() OwnerID {
+	return l.ownerID
+}
+
+// Requests returns the open requests, oldest first
+func (l *List) Requests() []Request {
+	return l.requests
+}
+
+// UpdatedAt returns the last update timestamp
+func (l *List) UpdatedAt() time.Time {
+	return l.updatedAt.Time()
+}
+
+// IsEmpty reports whether nothing is requested
+func (l *List) IsEmpty() bool {
+	return len(l.requests) == 0
+}
+
+// NormalizeDish turns what a member asked for into the dish, e.g.
+// "someone please make lasagna this week" into "lasagna"
+func NormalizeDish(dish string) string {
+	dish = strings.Join(strings.Fields(strings.TrimRight(dish, " .!?")), " ")
+	lower := strings.ToLower(dish) + " "
+	for _, prefix := range []string{"someone please make ", "someone please cook ", "can someone make ", "can someone cook ",
+		"please make ", "please cook ", "make ", "cook ", "alguém faz ", "alguém pode fazer ", "por favor faz ", "faz "} {
+		if strings.HasPrefix(lower, prefix) {
+			dish = dish[min(len(prefix), len(dish)):]
+			break
+		}
+	}
+	lower = strings.ToLower(dish)
+	for _, suffix := range []string{" this week", " tonight", " soon", " esta semana", " essa semana", " hoje"} {
+		if strings.HasSuffix(lower, suffix) {
+			dish = dish[:len(dish)-len(suffix)]
+			break
+		}
+	}
+	return strings.TrimSpace(dish)
+}
+
+// Add records a dish a member asked for at requestedAt
+func (l *List) Add(dish string, requester Member, requestedAt time.Time) (Request, error) {
+	dish = NormalizeDish(dish)
+	if dish == "" || requester.ID.IsEmpty() || len(l.requests) >= MaxOpen {
+		return Request{}, shared.ErrInvalidInput
+	}
+
+	req := Request{Dish: dish, Requester: requester, RequestedAt: requestedAt}
+	l.requests = append(l.requests, req)
+	l.updatedAt = shared.NewTimestamp()
+	return req, nil
+}
+
+// Get returns the request with a 1-based number, as listed
+func (l *List) Get(number int) (Request, error) {
+	if number < 1 || number > len(l.requests) {
+		return Request{}, shared.ErrNoSuchRequest
+	}
+	return l.requests[number-1], nil
+}
+
+// Attach records that a member planned a recipe to cook the request with a 1-based number
+func (l *List) Attach(number int, plan Plan) (Request, error) {
+	if number < 1 || number > len(l.requests) {
+		return Request{}, shared.ErrNoSuchRequest
+	}
+	if plan.RecipeID.IsEmpty() || plan.Cook.ID.IsEmpty() {
+		return Request{}, shared.ErrInvalidInput
+	}
+
+	l.requests[number-1].Plan = &plan
+	l.updatedAt = shared.NewTimestamp()
+	return l.requests[number-1], nil
+}
+
+// Remove removes the request with a 1-based number, once cooked or called off, and returns it
+func (l *List) Remove(number int) (Request, error) {
+	if number < 1 || number > len(l.requests) {
+		return Request{}, shared.ErrNoSuchRequest
+	}
+
+	req := l.requests[number-1]
+	l.requests = append(l.requests[:number-1:number-1], l.requests[number:]...)
+	l.updatedAt = shared.NewTimestamp()
+	return req, nil
+}
+
+// Clone returns a copy of the list that shares no mutable state with the original
+func (l *List) Clone() *List {
+	cp := *l
+	cp.requests = make([]Request, len(l.requests))
+	for i, req := range l.requests {
+		if req.Plan != nil {
+			plan := *req.Plan
+			req.Plan = &plan
+		}
+		cp.requests[i] = req
+	}
+	return &cp
+}
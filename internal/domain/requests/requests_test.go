@@ -0,0 +1,95 @@
+package requests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestNormalizeDish(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"someone please make lasagna this week", "lasagna"},
+		{"Can someone cook  Thai Curry tonight?", "Thai Curry"},
+		{"alguém faz lasanha esta semana", "lasanha"},
+		{"lasagna", "lasagna"},
+		{"please make ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeDish(tt.in); got != tt.want {
+			t.Errorf("NormalizeDish(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestList_AddAttachRemove(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	l, err := NewList("household-1")
+	if err != nil {
+		t.Fatalf("NewList() error = %v", err)
+	}
+	bob := Member{ID: "bob", TelegramID: 3003, Name: "Bob"}
+	alice := Member{ID: "alice", TelegramID: 1001, Name: "Alice"}
+
+	if _, err := l.Add("someone please make lasagna this week", bob, now); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := l.Add("curry", alice, now); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := l.Add("  ", bob, now); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Errorf("Add() of nothing error = %v, want ErrInvalidInput", err)
+	}
+
+	req, err := l.Attach(1, Plan{Cook: alice, RecipeID: "r1", Title: "Lasagna", Day: time.Wednesday})
+	if err != nil {
+		t.Fatalf("Attach() error = %v", err)
+	}
+	if req.Dish != "lasagna" || req.Plan == nil || req.Plan.Cook.Name != "Alice" {
+		t.Errorf("Attach() = %+v, want Alice's plan for the lasagna", req)
+	}
+	if _, err := l.Attach(3, Plan{Cook: alice, RecipeID: "r1"}); !errors.Is(err, shared.ErrNoSuchRequest) {
+		t.Errorf("Attach(3) error = %v, want ErrNoSuchRequest", err)
+	}
+
+	// A clone keeps its plans when the original changes
+	cp := l.Clone()
+	if _, err := l.Attach(1, Plan{Cook: bob, RecipeID: "r2", Title: "Quick Lasagna", Day: time.Friday}); err != nil {
+		t.Fatalf("Attach() error = %v", err)
+	}
+	if got := cp.Requests()[0].Plan.Title; got != "Lasagna" {
+		t.Errorf("clone plan = %q, want it unchanged", got)
+	}
+
+	removed, err := l.Remove(1)
+	if err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if removed.Requester.TelegramID != 3003 {
+		t.Errorf("Remove() = %+v, want Bob's request", removed)
+	}
+	if reqs := l.Requests(); len(reqs) != 1 || reqs[0].Dish != "curry" {
+		t.Errorf("Requests() after Remove() = %+v, want only the curry", reqs)
+	}
+	if len(cp.Requests()) != 2 {
+		t.Errorf("clone has %d requests after Remove(), want 2", len(cp.Requests()))
+	}
+}
+
+func TestList_Add_Full(t *testing.T) {
+	l, _ := NewList("household-1")
+	bob := Member{ID: "bob", Name: "Bob"}
+	for i := 0; i < MaxOpen; i++ {
+		if _, err := l.Add("soup", bob, time.Now()); err != nil {
+			t.Fatalf("Add() #%d error = %v", i+1, err)
+		}
+	}
+	if _, err := l.Add("soup", bob, time.Now()); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Errorf("Add() past MaxOpen error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,16 @@
+package share
+
+import "context"
+
+// Repository defines the interface for guest share persistence (Port)
+type Repository interface {
+	// Save persists a guest share
+	Save(ctx context.Context, s *GuestShare) error
+
+	// FindByToken retrieves a guest share by its token.
+	// It returns shared.ErrShareNotFound if there is none.
+	FindByToken(ctx context.Context, token string) (*GuestShare, error)
+
+	// DeleteByOwner removes all guest shares of a user
+	DeleteByOwner(ctx context.Context, ownerID UserID) error
+}
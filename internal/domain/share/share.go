@@ -0,0 +1,68 @@
+// Package share lets a user hand out time-limited, read-only access to their recipe collection.
+package share
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// How long a guest share stays open
+const (
+	DefaultTTL = 7 * 24 * time.Hour
+	MaxTTL     = 30 * 24 * time.Hour
+)
+
+// tokenBytes is the amount of randomness in a share token
+const tokenBytes = 8
+
+// GuestShare gives anyone holding its token read-only access to a user's
+// recipes until it expires
+type GuestShare struct {
+	Token     string
+	OwnerID   UserID
+	Category  *recipe.Category // nil shares the whole collection
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// NewGuestShare creates a share of a user's collection, or of one category of it,
+// open for ttl. A ttl outside (0, MaxTTL] is rejected.
+func NewGuestShare(ownerID UserID, category *recipe.Category, ttl time.Duration, now time.Time) (*GuestShare, error) {
+	if ownerID.IsEmpty() || ttl <= 0 || ttl > MaxTTL {
+		return nil, shared.ErrInvalidInput
+	}
+
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	return &GuestShare{
+		Token:     hex.EncodeToString(b),
+		OwnerID:   ownerID,
+		Category:  category,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// IsExpired checks if the share can no longer be opened
+func (s *GuestShare) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// Includes checks if a recipe is visible through the share
+func (s *GuestShare) Includes(rec *recipe.Recipe) bool {
+	if rec.UserID() != s.OwnerID {
+		return false
+	}
+	return s.Category == nil || rec.Category() == *s.Category
+}
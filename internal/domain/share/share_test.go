@@ -0,0 +1,73 @@
+package share
+
+import (
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shared"
+)
+
+func newShareTestRecipe(t *testing.T, owner UserID, category recipe.Category) *recipe.Recipe {
+	t.Helper()
+
+	ing, _ := recipe.NewIngredient("pasta", "200", "g", "")
+	inst, _ := recipe.NewInstruction(1, "Cook", nil)
+	source, _ := recipe.NewSource("https://example.com/pasta", recipe.PlatformWeb, "")
+	rec, err := recipe.NewRecipe(owner, "Pasta", []recipe.Ingredient{ing}, []recipe.Instruction{inst}, source, "", "")
+	if err != nil {
+		t.Fatalf("NewRecipe() error = %v", err)
+	}
+	rec.SetCategory(category)
+	return rec
+}
+
+func TestNewGuestShare(t *testing.T) {
+	owner := shared.NewID()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, err := NewGuestShare(owner, nil, 0, now); err == nil {
+		t.Error("NewGuestShare() with no ttl should fail")
+	}
+	if _, err := NewGuestShare(owner, nil, MaxTTL+time.Hour, now); err == nil {
+		t.Error("NewGuestShare() longer than MaxTTL should fail")
+	}
+
+	s, err := NewGuestShare(owner, nil, DefaultTTL, now)
+	if err != nil {
+		t.Fatalf("NewGuestShare() error = %v", err)
+	}
+	other, _ := NewGuestShare(owner, nil, DefaultTTL, now)
+	if len(s.Token) != 2*tokenBytes || s.Token == other.Token {
+		t.Errorf("tokens %q and %q should be distinct hex strings", s.Token, other.Token)
+	}
+	if s.IsExpired(now.Add(DefaultTTL - time.Second)) {
+		t.Error("IsExpired() = true before the share expires")
+	}
+	if !s.IsExpired(now.Add(DefaultTTL)) {
+		t.Error("IsExpired() = false once the share expired")
+	}
+}
+
+func TestGuestShare_Includes(t *testing.T) {
+	owner := shared.NewID()
+	now := time.Now()
+	pasta := recipe.CategoryPasta
+
+	whole, _ := NewGuestShare(owner, nil, DefaultTTL, now)
+	subset, _ := NewGuestShare(owner, &pasta, DefaultTTL, now)
+
+	pastaRecipe := newShareTestRecipe(t, owner, recipe.CategoryPasta)
+	saladRecipe := newShareTestRecipe(t, owner, recipe.CategorySalads)
+	strangerRecipe := newShareTestRecipe(t, shared.NewID(), recipe.CategoryPasta)
+
+	if !whole.Includes(pastaRecipe) || !whole.Includes(saladRecipe) {
+		t.Error("a whole-collection share should include all of the owner's recipes")
+	}
+	if !subset.Includes(pastaRecipe) || subset.Includes(saladRecipe) {
+		t.Error("a category share should only include recipes of that category")
+	}
+	if whole.Includes(strangerRecipe) {
+		t.Error("a share should never include another user's recipes")
+	}
+}
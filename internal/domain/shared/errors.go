@@ -10,6 +10,16 @@ var (
 	ErrNoIngredients        = errors.New("recipe must have at least one ingredient")
 	ErrNoInstructions       = errors.New("recipe must have at least one instruction")
 	ErrInvalidSource        = errors.New("invalid recipe source")
+	ErrVersionNotFound      = errors.New("recipe version not found")
+	ErrVariantNotFound      = errors.New("recipe variant not found")
+	ErrNoDishProposal       = errors.New("no recipe proposed from a photo")
+	ErrMultipleRecipes      = errors.New("content contains several recipes")
+	ErrNoPendingRecipes     = errors.New("no recipes waiting to be saved")
+	ErrImplausibleClaims    = errors.New("recipe states implausible times, servings or amounts")
+	ErrNoHeldRecipe         = errors.New("no recipe waiting for a check")
+	ErrRecipeSummary        = errors.New("recipe summaries cannot be saved")
+	ErrNoProcessingReport   = errors.New("no processing report for the recipe")
+	ErrNothingToRemix       = errors.New("recipe already fits the remix goal")
 
 	// Ingredient errors
 	ErrInvalidIngredientName = errors.New("ingredient name cannot be empty")
@@ -27,6 +37,107 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidTelegramID  = errors.New("invalid telegram ID")
 	ErrInvalidUsername    = errors.New("invalid username")
+	ErrInvalidLinkCode    = errors.New("link code is invalid or expired")
+	ErrAlreadyLinked      = errors.New("account is already linked")
+	ErrNotLinked          = errors.New("account is not linked")
+	ErrInvalidAPIToken    = errors.New("API token is invalid")
+
+	// Saved filter errors
+	ErrFilterNotFound    = errors.New("saved filter not found")
+	ErrInvalidFilterName = errors.New("saved filter name cannot be empty")
+	ErrEmptyFilter       = errors.New("saved filter has no criteria")
+	ErrTooManyFilters    = errors.New("too many saved filters")
+
+	// Shortcut errors
+	ErrShortcutNotFound = errors.New("shortcut not found")
+	ErrInvalidShortcut  = errors.New("shortcut needs a trigger and a different expansion")
+	ErrTooManyShortcuts = errors.New("too many shortcuts")
+
+	// Notification errors
+	ErrInvalidNotification = errors.New("unknown notification")
+	ErrInvalidQuietHours   = errors.New("quiet hours must be a span like 22:00-07:00")
+	ErrInvalidTimezone     = errors.New("unknown time zone")
+	ErrInvalidLocale       = errors.New("unknown locale")
+
+	// Export errors
+	ErrInvalidAutoExport        = errors.New("auto-export needs a target of notion or obsidian")
+	ErrNotionNotConnected       = errors.New("notion is not connected")
+	ErrNotionRateLimited        = errors.New("notion rate limit exceeded")
+	ErrInvalidCloudProvider     = errors.New("cloud storage must be dropbox or drive")
+	ErrCloudStorageNotConnected = errors.New("cloud storage is not connected")
+	ErrConnectLinkExpired       = errors.New("connect link expired")
+
+	// Meal plan and shopping list errors
+	ErrMealPlanNotFound     = errors.New("meal plan not found")
+	ErrShoppingListNotFound = errors.New("shopping list not found")
+	ErrMenuNotFound         = errors.New("menu not found")
+	ErrScheduleNotFound     = errors.New("cooking schedule not found")
+
+	// Cook-along errors
+	ErrCookSessionNotFound = errors.New("no cook-along in this chat")
+	ErrNoSuchStep          = errors.New("no such step")
+	ErrStepClaimed         = errors.New("step already claimed by someone else")
+	ErrStepDone            = errors.New("step already done")
+
+	// Freezer errors
+	ErrFreezerNotFound = errors.New("freezer not found")
+	ErrNotInFreezer    = errors.New("recipe is not in the freezer")
+
+	// Dish request errors
+	ErrRequestsNotFound = errors.New("dish requests not found")
+	ErrNoSuchRequest    = errors.New("no such dish request")
+
+	// Cooking stats errors
+	ErrStatsNotFound = errors.New("cooking stats not found")
+
+	// Sharing errors
+	ErrShareNotFound = errors.New("share not found")
+	ErrShareExpired  = errors.New("share has expired")
+
+	// Barcode and pantry photo errors
+	ErrNoBarcode       = errors.New("no barcode found in image")
+	ErrProductNotFound = errors.New("product not found")
+	ErrNoItemsFound    = errors.New("no pantry items found in image")
+	ErrNoPantryPhoto   = errors.New("no pantry photo to confirm")
+
+	// Macro goal errors
+	ErrNoMacroGoals     = errors.New("no macro goals set")
+	ErrInvalidMacroGoal = errors.New("invalid macro goal")
+
+	// Recipe processing errors
+	ErrScrapeFailed     = errors.New("scraping failed")
+	ErrNoContent        = errors.New("no content extracted from URL")
+	ErrExtractionFailed = errors.New("recipe extraction failed")
+	ErrLLMTimeout       = errors.New("LLM call timed out")
+	ErrQuotaExceeded    = errors.New("LLM quota exceeded")
+
+	// Feed errors
+	ErrSubscriptionNotFound = errors.New("feed subscription not found")
+	ErrAlreadySubscribed    = errors.New("already subscribed to this feed")
+	ErrTooManySubscriptions = errors.New("too many feed subscriptions")
+	ErrFeedUnreadable       = errors.New("not a readable RSS or Atom feed")
+
+	// Moderation errors
+	ErrModerationNotFound = errors.New("moderation decision not found")
+
+	// Bookmark import errors
+	ErrNoRecipeLinks   = errors.New("no recipe links found in bookmarks")
+	ErrNoPendingImport = errors.New("no bookmark import to start")
+	ErrImportRunning   = errors.New("a bookmark import is already running")
+
+	// Error report errors
+	ErrNothingToReport = errors.New("no failed operation to report")
+
+	// Premium errors
+	ErrUnknownPlan     = errors.New("unknown premium plan")
+	ErrPaymentMismatch = errors.New("payment does not match the invoice")
+	ErrPaymentRecorded = errors.New("payment already recorded")
+
+	// Blob storage errors
+	ErrBlobNotFound = errors.New("blob not found")
+
+	// Cover image errors
+	ErrHasSourceImage = errors.New("recipe has an image from its source")
 
 	// General errors
 	ErrInvalidInput = errors.New("invalid input")
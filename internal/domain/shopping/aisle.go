@@ -0,0 +1,61 @@
+package shopping
+
+import "strings"
+
+// Aisle is the grocery store department an item is found in
+type Aisle string
+
+const (
+	AisleProduce   Aisle = "produce"
+	AisleMeat      Aisle = "meat"
+	AisleSeafood   Aisle = "seafood"
+	AisleDairy     Aisle = "dairy"
+	AisleBakery    Aisle = "bakery"
+	AislePantry    Aisle = "pantry"
+	AisleSpices    Aisle = "spices"
+	AisleFrozen    Aisle = "frozen"
+	AisleBeverages Aisle = "beverages"
+	AisleOther     Aisle = "other"
+)
+
+// AllAisles returns the aisles in the order a shopping list is walked
+func AllAisles() []Aisle {
+	return []Aisle{
+		AisleProduce,
+		AisleBakery,
+		AisleMeat,
+		AisleSeafood,
+		AisleDairy,
+		AislePantry,
+		AisleSpices,
+		AisleFrozen,
+		AisleBeverages,
+		AisleOther,
+	}
+}
+
+// ParseAisle converts a string to an Aisle, defaulting to AisleOther
+func ParseAisle(s string) Aisle {
+	a := Aisle(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range AllAisles() {
+		if a == known {
+			return a
+		}
+	}
+	return AisleOther
+}
+
+// String returns the string representation of the aisle
+func (a Aisle) String() string {
+	return string(a)
+}
+
+// order returns the position of the aisle in AllAisles
+func (a Aisle) order() int {
+	for i, known := range AllAisles() {
+		if a == known {
+			return i
+		}
+	}
+	return len(AllAisles())
+}
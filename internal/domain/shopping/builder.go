@@ -0,0 +1,97 @@
+package shopping
+
+import (
+	"strings"
+
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/recipe"
+)
+
+// Builder aggregates ingredients from several recipes into shopping list items.
+// Ingredients are merged by normalized name, and quantities with the same
+// canonical unit are summed.
+type Builder struct {
+	normalizer matching.IngredientNormalizer
+	items      map[string]*Item
+	order      []string
+}
+
+// NewBuilder creates a new shopping list builder
+func NewBuilder(normalizer matching.IngredientNormalizer) *Builder {
+	return &Builder{
+		normalizer: normalizer,
+		items:      make(map[string]*Item),
+	}
+}
+
+// Add adds an ingredient, multiplying its quantity by scale (1 keeps it as written)
+func (b *Builder) Add(ing recipe.Ingredient, scale float64) {
+	name := b.normalizer.Normalize(ing.Name())
+	if name == "" {
+		return
+	}
+
+	item, ok := b.items[name]
+	if !ok {
+		item = &Item{Name: name, Aisle: AisleOther}
+		b.items[name] = item
+		b.order = append(b.order, name)
+	}
+
+	amount, ok := ParseAmount(ing.Quantity(), ing.Unit())
+	if !ok {
+		extra := strings.TrimSpace(ing.Quantity() + " " + ing.Unit())
+		if extra != "" && !containsString(item.Extras, extra) {
+			item.Extras = append(item.Extras, extra)
+		}
+		return
+	}
+
+	amount.Value *= scale
+	item.Amounts = addAmount(item.Amounts, amount)
+}
+
+// addAmount sums an amount into the one with the same unit, or appends it
+func addAmount(amounts []Amount, amount Amount) []Amount {
+	for i := range amounts {
+		if amounts[i].Unit == amount.Unit {
+			amounts[i].Value += amount.Value
+			return amounts
+		}
+	}
+	return append(amounts, amount)
+}
+
+// RemovePantry drops items the user already has and returns their names
+func (b *Builder) RemovePantry(pantry []string) []string {
+	var removed []string
+	for _, p := range pantry {
+		name := b.normalizer.Normalize(p)
+		if _, ok := b.items[name]; !ok {
+			continue
+		}
+		delete(b.items, name)
+		removed = append(removed, name)
+	}
+	return removed
+}
+
+// Items returns the aggregated items in the order they were first added
+func (b *Builder) Items() []Item {
+	items := make([]Item, 0, len(b.items))
+	for _, name := range b.order {
+		if item, ok := b.items[name]; ok {
+			items = append(items, *item)
+		}
+	}
+	return items
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package shopping
+
+import (
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/matching"
+	"receipt-bot/internal/domain/recipe"
+)
+
+func mustIngredient(t *testing.T, name, quantity, unit string) recipe.Ingredient {
+	t.Helper()
+	ing, err := recipe.NewIngredient(name, quantity, unit, "")
+	if err != nil {
+		t.Fatalf("NewIngredient() error = %v", err)
+	}
+	return ing
+}
+
+func TestBuilder_MergesAndScales(t *testing.T) {
+	b := NewBuilder(matching.NewRuleBasedNormalizer(nil))
+
+	b.Add(mustIngredient(t, "Tomatoes", "200", "g"), 1)
+	b.Add(mustIngredient(t, "diced tomatoes", "0.4", "kg"), 1)
+	b.Add(mustIngredient(t, "tomato", "1", "can"), 2)
+	b.Add(mustIngredient(t, "salt", "a pinch", ""), 1)
+	b.Add(mustIngredient(t, "salt", "a pinch", ""), 1)
+	b.Add(mustIngredient(t, "eggs", "2", ""), 1.5)
+
+	items := b.Items()
+	if len(items) != 3 {
+		t.Fatalf("Items() = %d items, want 3: %+v", len(items), items)
+	}
+
+	if got := items[0].String(); got != "600 g + 2 can tomato" {
+		t.Errorf("tomato = %q", got)
+	}
+	if got := items[1].String(); got != "a pinch salt" {
+		t.Errorf("salt = %q", got)
+	}
+	if got := items[2].String(); got != "3 egg" {
+		t.Errorf("egg = %q", got)
+	}
+}
+
+func TestBuilder_RemovePantry(t *testing.T) {
+	b := NewBuilder(matching.NewRuleBasedNormalizer(nil))
+	b.Add(mustIngredient(t, "eggs", "2", ""), 1)
+	b.Add(mustIngredient(t, "flour", "200", "g"), 1)
+
+	removed := b.RemovePantry([]string{"egg", "milk"})
+	if len(removed) != 1 || removed[0] != "egg" {
+		t.Errorf("RemovePantry() = %v, want [egg]", removed)
+	}
+	if items := b.Items(); len(items) != 1 || items[0].Name != "flour" {
+		t.Errorf("Items() after RemovePantry = %+v", items)
+	}
+}
+
+func TestNewList_SortsByAisleAndToggles(t *testing.T) {
+	items := []Item{
+		{Name: "salt", Aisle: AisleSpices},
+		{Name: "onion", Aisle: AisleProduce},
+		{Name: "butter", Aisle: AisleDairy},
+		{Name: "garlic", Aisle: AisleProduce},
+	}
+
+	list, err := NewList("user-1", time.Now(), items)
+	if err != nil {
+		t.Fatalf("NewList() error = %v", err)
+	}
+
+	var names []string
+	for _, item := range list.Items() {
+		names = append(names, item.Name)
+	}
+	want := []string{"garlic", "onion", "butter", "salt"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("Items() order = %v, want %v", names, want)
+		}
+	}
+
+	if err := list.Toggle(1); err != nil {
+		t.Fatalf("Toggle() error = %v", err)
+	}
+	if !list.Items()[1].Checked || list.Remaining() != 3 {
+		t.Errorf("Toggle() did not check the item")
+	}
+	if err := list.Toggle(4); err == nil {
+		t.Error("Toggle() out of range should fail")
+	}
+}
+
+func TestList_Add(t *testing.T) {
+	list, err := NewList("user-1", time.Now(), []Item{
+		{Name: "spaghetti", Amounts: []Amount{{Value: 200, Unit: "g"}}, Aisle: AislePantry},
+		{Name: "onion", Amounts: []Amount{{Value: 1}}, Aisle: AisleProduce},
+	})
+	if err != nil {
+		t.Fatalf("NewList() error = %v", err)
+	}
+	if err := list.Toggle(1); err != nil {
+		t.Fatalf("Toggle() error = %v", err)
+	}
+
+	list.Add([]Item{
+		{Name: "spaghetti", Amounts: []Amount{{Value: 300, Unit: "g"}}, Extras: []string{"a handful"}, Aisle: AislePantry},
+		{Name: "garlic", Amounts: []Amount{{Value: 2, Unit: "clove"}}, Aisle: AisleProduce},
+	})
+
+	items := list.Items()
+	if len(items) != 3 || items[0].Name != "garlic" || items[1].Name != "onion" || items[2].Name != "spaghetti" {
+		t.Fatalf("Items() after Add = %+v", items)
+	}
+	if got := items[2].Quantity(); got != "500 g + a handful" {
+		t.Errorf("spaghetti quantity = %q, want %q", got, "500 g + a handful")
+	}
+	if items[2].Checked {
+		t.Error("Add() should uncheck an item that needs more")
+	}
+}
@@ -0,0 +1,182 @@
+package shopping
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// Item is one line of a shopping list
+type Item struct {
+	Name    string   // normalized ingredient name
+	Amounts []Amount // summed quantities, one per unit
+	Extras  []string // quantities that could not be summed, e.g. "a pinch"
+	Aisle   Aisle
+	Checked bool
+}
+
+// Quantity returns the combined quantity of the item, e.g. "400 g + 2 tbsp"
+func (i Item) Quantity() string {
+	parts := make([]string, 0, len(i.Amounts)+len(i.Extras))
+	for _, a := range i.Amounts {
+		parts = append(parts, a.String())
+	}
+	parts = append(parts, i.Extras...)
+	return strings.Join(parts, " + ")
+}
+
+// String returns the item with its quantity, e.g. "400 g spaghetti"
+func (i Item) String() string {
+	if q := i.Quantity(); q != "" {
+		return q + " " + i.Name
+	}
+	return i.Name
+}
+
+// List is a checkable shopping list generated from a week's meal plan (Aggregate Root)
+type List struct {
+	userID    UserID
+	weekStart time.Time
+	items     []Item
+	createdAt shared.Timestamp
+}
+
+// NewList creates a shopping list, ordering items by aisle and then by name
+func NewList(userID UserID, weekStart time.Time, items []Item) (*List, error) {
+	if userID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	sorted := append([]Item{}, items...)
+	sortItems(sorted)
+
+	return &List{
+		userID:    userID,
+		weekStart: weekStart,
+		items:     sorted,
+		createdAt: shared.NewTimestamp(),
+	}, nil
+}
+
+// sortItems orders items by aisle and then by name
+func sortItems(items []Item) {
+	sort.SliceStable(items, func(a, b int) bool {
+		if items[a].Aisle.order() != items[b].Aisle.order() {
+			return items[a].Aisle.order() < items[b].Aisle.order()
+		}
+		return items[a].Name < items[b].Name
+	})
+}
+
+// ListData contains data for reconstructing a shopping list from storage
+type ListData struct {
+	UserID    UserID
+	WeekStart time.Time
+	Items     []Item
+	CreatedAt time.Time
+}
+
+// ReconstructList reconstructs a shopping list from stored data (for repository)
+func ReconstructList(data ListData) *List {
+	items := data.Items
+	if items == nil {
+		items = []Item{}
+	}
+	return &List{
+		userID:    data.UserID,
+		weekStart: data.WeekStart,
+		items:     items,
+		createdAt: shared.NewTimestampFromTime(data.CreatedAt),
+	}
+}
+
+// UserID returns the owner of the list
+func (l *List) UserID() UserID {
+	return l.userID
+}
+
+// WeekStart returns the Monday of the planned week the list was built for
+func (l *List) WeekStart() time.Time {
+	return l.weekStart
+}
+
+// Items returns the items grouped by aisle
+func (l *List) Items() []Item {
+	return l.items
+}
+
+// CreatedAt returns when the list was generated
+func (l *List) CreatedAt() time.Time {
+	return l.createdAt.Time()
+}
+
+// Toggle checks or unchecks the item at index
+func (l *List) Toggle(index int) error {
+	if index < 0 || index >= len(l.items) {
+		return shared.ErrInvalidInput
+	}
+	l.items[index].Checked = !l.items[index].Checked
+	return nil
+}
+
+// Add puts items on the list, adding their quantities to the items already on it.
+// An item that was checked off is unchecked again, since more of it is needed now.
+func (l *List) Add(items []Item) {
+	for _, item := range items {
+		i := l.indexOf(item.Name)
+		if i < 0 {
+			l.items = append(l.items, item)
+			continue
+		}
+
+		existing := &l.items[i]
+		for _, amount := range item.Amounts {
+			existing.Amounts = addAmount(existing.Amounts, amount)
+		}
+		for _, extra := range item.Extras {
+			if !containsString(existing.Extras, extra) {
+				existing.Extras = append(existing.Extras, extra)
+			}
+		}
+		existing.Checked = false
+	}
+	sortItems(l.items)
+}
+
+// indexOf returns the index of the item with the name, or -1
+func (l *List) indexOf(name string) int {
+	for i, item := range l.items {
+		if item.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Remaining returns how many items are not checked yet
+func (l *List) Remaining() int {
+	n := 0
+	for _, item := range l.items {
+		if !item.Checked {
+			n++
+		}
+	}
+	return n
+}
+
+// Clone returns a copy of the list that shares no mutable state with the original
+func (l *List) Clone() *List {
+	cp := *l
+	cp.items = make([]Item, len(l.items))
+	for i, item := range l.items {
+		item.Amounts = append([]Amount(nil), item.Amounts...)
+		item.Extras = append([]string(nil), item.Extras...)
+		cp.items[i] = item
+	}
+	return &cp
+}
@@ -0,0 +1,137 @@
+package shopping
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amount is a numeric quantity in a canonical unit.
+// An empty unit means a plain count ("2 eggs").
+type Amount struct {
+	Value float64
+	Unit  string
+}
+
+// unitAliases maps spellings found in recipes to a canonical unit and a factor
+// converting into it. Metric units are folded into grams and millilitres so
+// quantities from different recipes can be summed.
+var unitAliases = map[string]struct {
+	unit   string
+	factor float64
+}{
+	"g": {"g", 1}, "gr": {"g", 1}, "gram": {"g", 1}, "grams": {"g", 1}, "grama": {"g", 1}, "gramas": {"g", 1},
+	"kg": {"g", 1000}, "kilo": {"g", 1000}, "kilos": {"g", 1000}, "kilogram": {"g", 1000}, "kilograms": {"g", 1000},
+	"mg": {"g", 0.001},
+	"ml": {"ml", 1}, "millilitre": {"ml", 1}, "millilitres": {"ml", 1}, "milliliter": {"ml", 1}, "milliliters": {"ml", 1},
+	"cl": {"ml", 10}, "dl": {"ml", 100},
+	"l": {"ml", 1000}, "litre": {"ml", 1000}, "litres": {"ml", 1000}, "liter": {"ml", 1000}, "liters": {"ml", 1000}, "litro": {"ml", 1000}, "litros": {"ml", 1000},
+	"tsp": {"tsp", 1}, "teaspoon": {"tsp", 1}, "teaspoons": {"tsp", 1}, "colher de chá": {"tsp", 1}, "colheres de chá": {"tsp", 1},
+	"tbsp": {"tbsp", 1}, "tbs": {"tbsp", 1}, "tablespoon": {"tbsp", 1}, "tablespoons": {"tbsp", 1}, "colher de sopa": {"tbsp", 1}, "colheres de sopa": {"tbsp", 1},
+	"cup": {"cup", 1}, "cups": {"cup", 1}, "xícara": {"cup", 1}, "xícaras": {"cup", 1}, "xicara": {"cup", 1}, "xicaras": {"cup", 1},
+	"oz": {"oz", 1}, "ounce": {"oz", 1}, "ounces": {"oz", 1},
+	"lb": {"lb", 1}, "lbs": {"lb", 1}, "pound": {"lb", 1}, "pounds": {"lb", 1},
+	"clove": {"clove", 1}, "cloves": {"clove", 1}, "dente": {"clove", 1}, "dentes": {"clove", 1},
+	"can": {"can", 1}, "cans": {"can", 1}, "lata": {"can", 1}, "latas": {"can", 1},
+	"bunch": {"bunch", 1}, "bunches": {"bunch", 1}, "maço": {"bunch", 1}, "maços": {"bunch", 1},
+	"slice": {"slice", 1}, "slices": {"slice", 1}, "fatia": {"slice", 1}, "fatias": {"slice", 1},
+	"piece": {"", 1}, "pieces": {"", 1}, "pc": {"", 1}, "pcs": {"", 1}, "unit": {"", 1}, "units": {"", 1}, "unidade": {"", 1}, "unidades": {"", 1},
+}
+
+// vulgarFractions maps unicode fraction characters to their value
+var vulgarFractions = map[rune]float64{
+	'½': 0.5, '⅓': 1.0 / 3, '⅔': 2.0 / 3, '¼': 0.25, '¾': 0.75,
+	'⅛': 0.125, '⅜': 0.375, '⅝': 0.625, '⅞': 0.875,
+}
+
+// ParseAmount parses a recipe quantity and unit into a canonical amount.
+// It returns false for quantities that are not numeric, like "a pinch" or "to taste".
+func ParseAmount(quantity, unit string) (Amount, bool) {
+	value, ok := parseNumber(quantity)
+	if !ok {
+		return Amount{}, false
+	}
+
+	unit = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(unit), ".")))
+	if alias, found := unitAliases[unit]; found {
+		return Amount{Value: value * alias.factor, Unit: alias.unit}, true
+	}
+	return Amount{Value: value, Unit: unit}, true
+}
+
+// parseNumber parses "2", "1.5", "1,5", "1/2", "1 1/2", "1½" and ranges like "2-3".
+// Ranges resolve to their upper bound, so the list never comes up short.
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+
+	for _, sep := range []string{"-", "–", " to ", " a "} {
+		if i := strings.LastIndex(s, sep); i > 0 {
+			return parseNumber(s[i+len(sep):])
+		}
+	}
+
+	total := 0.0
+	parsed := false
+	for _, field := range strings.Fields(s) {
+		v, ok := parseSimpleNumber(field)
+		if !ok {
+			return 0, false
+		}
+		total += v
+		parsed = true
+	}
+	return total, parsed && total > 0
+}
+
+func parseSimpleNumber(s string) (float64, bool) {
+	total := 0.0
+	// Trailing unicode fraction, e.g. "1½"
+	runes := []rune(s)
+	if f, ok := vulgarFractions[runes[len(runes)-1]]; ok {
+		total += f
+		s = string(runes[:len(runes)-1])
+		if s == "" {
+			return total, true
+		}
+	}
+
+	if num, den, found := strings.Cut(s, "/"); found {
+		n, err1 := strconv.ParseFloat(num, 64)
+		d, err2 := strconv.ParseFloat(den, 64)
+		if err1 != nil || err2 != nil || d == 0 {
+			return 0, false
+		}
+		return total + n/d, true
+	}
+
+	v, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+	return total + v, true
+}
+
+// String formats the amount for display, e.g. "1.5 kg", "2 tbsp" or "3"
+func (a Amount) String() string {
+	value, unit := a.Value, a.Unit
+	switch {
+	case unit == "g" && value >= 1000:
+		value, unit = value/1000, "kg"
+	case unit == "ml" && value >= 1000:
+		value, unit = value/1000, "l"
+	}
+
+	text := formatNumber(value)
+	if unit == "" {
+		return text
+	}
+	return text + " " + unit
+}
+
+func formatNumber(v float64) string {
+	v = math.Round(v*100) / 100
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
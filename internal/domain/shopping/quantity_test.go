@@ -0,0 +1,57 @@
+package shopping
+
+import "testing"
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity string
+		unit     string
+		want     Amount
+		wantOK   bool
+	}{
+		{"grams", "200", "g", Amount{200, "g"}, true},
+		{"kilograms fold into grams", "1.5", "kg", Amount{1500, "g"}, true},
+		{"litres fold into millilitres", "1", "L", Amount{1000, "ml"}, true},
+		{"decimal comma", "0,5", "l", Amount{500, "ml"}, true},
+		{"fraction", "1/2", "cup", Amount{0.5, "cup"}, true},
+		{"mixed number", "1 1/2", "tablespoons", Amount{1.5, "tbsp"}, true},
+		{"unicode fraction", "1½", "tsp", Amount{1.5, "tsp"}, true},
+		{"range uses upper bound", "2-3", "cloves", Amount{3, "clove"}, true},
+		{"plain count", "2", "", Amount{2, ""}, true},
+		{"unknown unit kept", "1", "sachet", Amount{1, "sachet"}, true},
+		{"not numeric", "a pinch", "", Amount{}, false},
+		{"empty", "", "g", Amount{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseAmount(tt.quantity, tt.unit)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseAmount(%q, %q) ok = %v, want %v", tt.quantity, tt.unit, ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("ParseAmount(%q, %q) = %+v, want %+v", tt.quantity, tt.unit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{Amount{600, "g"}, "600 g"},
+		{Amount{1500, "g"}, "1.5 kg"},
+		{Amount{1000, "ml"}, "1 l"},
+		{Amount{1.0 / 3, "cup"}, "0.33 cup"},
+		{Amount{3, ""}, "3"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
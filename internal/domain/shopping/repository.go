@@ -0,0 +1,20 @@
+package shopping
+
+import "context"
+
+// Repository defines the interface for shopping list persistence (Port).
+// Each user has a single current list that is replaced when a new one is generated.
+type Repository interface {
+	// Save persists the current shopping list of a user
+	Save(ctx context.Context, list *List) error
+
+	// FindByUserID retrieves the current shopping list of a user.
+	// It returns shared.ErrShoppingListNotFound if none was generated yet.
+	FindByUserID(ctx context.Context, userID UserID) (*List, error)
+
+	// Modify changes the current shopping list of a user in one transaction, so
+	// changes made at the same time are not lost. change may run more than once.
+	// It returns shared.ErrShoppingListNotFound if none was generated yet,
+	// and the error of change without saving.
+	Modify(ctx context.Context, userID UserID, change func(list *List) error) (*List, error)
+}
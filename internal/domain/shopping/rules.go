@@ -0,0 +1,119 @@
+package shopping
+
+import "strings"
+
+// aisleKeywords maps ingredient names and head words to the aisle they are found in.
+// Whole names are checked first so that "peanut butter" does not land in dairy.
+var aisleKeywords = map[string]Aisle{
+	// Produce
+	"apple": AisleProduce, "avocado": AisleProduce, "banana": AisleProduce, "basil": AisleProduce,
+	"bean sprout": AisleProduce, "bell pepper": AisleProduce, "berry": AisleProduce, "broccoli": AisleProduce,
+	"green pepper": AisleProduce, "red pepper": AisleProduce,
+	"cabbage": AisleProduce, "carrot": AisleProduce, "cauliflower": AisleProduce, "celery": AisleProduce,
+	"chili": AisleProduce, "chive": AisleProduce, "cilantro": AisleProduce, "coriander": AisleProduce,
+	"cucumber": AisleProduce, "eggplant": AisleProduce, "garlic": AisleProduce, "ginger": AisleProduce,
+	"herb": AisleProduce, "kale": AisleProduce, "leek": AisleProduce, "lemon": AisleProduce,
+	"lettuce": AisleProduce, "lime": AisleProduce, "mango": AisleProduce, "mint": AisleProduce,
+	"mushroom": AisleProduce, "onion": AisleProduce, "orange": AisleProduce, "parsley": AisleProduce,
+	"potato": AisleProduce, "pumpkin": AisleProduce, "rosemary": AisleProduce,
+	"scallion": AisleProduce, "shallot": AisleProduce, "spinach": AisleProduce, "squash": AisleProduce,
+	"thyme": AisleProduce, "tomato": AisleProduce, "zucchini": AisleProduce,
+	"alho": AisleProduce, "batata": AisleProduce, "cebola": AisleProduce, "cenoura": AisleProduce,
+	"limão": AisleProduce, "tomate": AisleProduce,
+
+	// Bakery
+	"bagel": AisleBakery, "baguette": AisleBakery, "bread": AisleBakery, "bun": AisleBakery,
+	"croissant": AisleBakery, "pita": AisleBakery, "roll": AisleBakery, "tortilla": AisleBakery,
+	"pão": AisleBakery,
+
+	// Meat
+	"bacon": AisleMeat, "beef": AisleMeat, "chicken": AisleMeat, "chorizo": AisleMeat,
+	"ground meat": AisleMeat, "ham": AisleMeat, "lamb": AisleMeat, "pancetta": AisleMeat,
+	"pork": AisleMeat, "prosciutto": AisleMeat, "sausage": AisleMeat, "steak": AisleMeat,
+	"turkey": AisleMeat, "carne": AisleMeat, "frango": AisleMeat, "linguiça": AisleMeat,
+
+	// Seafood
+	"anchovy": AisleSeafood, "clam": AisleSeafood, "cod": AisleSeafood, "crab": AisleSeafood,
+	"fish": AisleSeafood, "mussel": AisleSeafood, "prawn": AisleSeafood, "salmon": AisleSeafood,
+	"shrimp": AisleSeafood, "squid": AisleSeafood, "tuna": AisleSeafood,
+	"bacalhau": AisleSeafood, "camarão": AisleSeafood, "peixe": AisleSeafood,
+
+	// Dairy & eggs
+	"butter": AisleDairy, "cheddar": AisleDairy, "cheese": AisleDairy, "cream": AisleDairy,
+	"egg": AisleDairy, "feta": AisleDairy, "milk": AisleDairy, "mozzarella": AisleDairy,
+	"parmesan": AisleDairy, "pecorino": AisleDairy, "ricotta": AisleDairy, "yogurt": AisleDairy,
+	"leite": AisleDairy, "manteiga": AisleDairy, "ovo": AisleDairy, "queijo": AisleDairy,
+
+	// Pantry
+	"bean": AislePantry, "breadcrumb": AislePantry, "broth": AislePantry, "chickpea": AislePantry,
+	"coconut milk": AislePantry, "flour": AislePantry, "honey": AislePantry, "ketchup": AislePantry,
+	"lentil": AislePantry, "mayonnaise": AislePantry, "mustard": AislePantry, "noodle": AislePantry,
+	"oat": AislePantry, "oil": AislePantry, "pasta": AislePantry, "peanut butter": AislePantry,
+	"penne": AislePantry, "quinoa": AislePantry, "rice": AislePantry, "sauce": AislePantry,
+	"spaghetti": AislePantry, "stock": AislePantry, "sugar": AislePantry, "vinegar": AislePantry,
+	"yeast": AislePantry, "baking powder": AislePantry, "baking soda": AislePantry,
+	"arroz": AislePantry, "azeite": AislePantry, "açúcar": AislePantry, "farinha": AislePantry,
+	"feijão": AislePantry, "macarrão": AislePantry,
+
+	// Spices
+	"black pepper": AisleSpices, "cinnamon": AisleSpices, "cumin": AisleSpices, "curry": AisleSpices,
+	"nutmeg": AisleSpices, "oregano": AisleSpices, "paprika": AisleSpices, "pepper": AisleSpices,
+	"powder": AisleSpices, "salt": AisleSpices, "turmeric": AisleSpices, "vanilla": AisleSpices, "sal": AisleSpices,
+
+	// Frozen
+	"ice cream": AisleFrozen, "frozen pea": AisleFrozen,
+
+	// Beverages
+	"beer": AisleBeverages, "coffee": AisleBeverages, "juice": AisleBeverages, "tea": AisleBeverages,
+	"water": AisleBeverages, "wine": AisleBeverages, "vinho": AisleBeverages,
+}
+
+// ClassifyByRules returns the aisle of a normalized ingredient name using
+// keyword rules. It reports false when no rule applies.
+//
+// The whole name is tried first, then trailing phrases and single words from
+// the end, since the last word is usually the head noun ("chicken broth" is broth).
+func ClassifyByRules(name string) (Aisle, bool) {
+	words := strings.Fields(strings.ToLower(name))
+	if len(words) == 0 {
+		return "", false
+	}
+
+	// Longest trailing phrase first: "black pepper" before "pepper"
+	for start := 0; start < len(words); start++ {
+		if aisle, ok := aisleKeywords[strings.Join(words[start:], " ")]; ok {
+			return aisle, true
+		}
+	}
+
+	// Fall back to any known word, last word first
+	for i := len(words) - 1; i >= 0; i-- {
+		if aisle, ok := aisleKeywords[singular(words[i])]; ok {
+			return aisle, true
+		}
+	}
+
+	return "", false
+}
+
+// singular strips a simple English plural so "tomatoes" matches "tomato"
+func singular(word string) string {
+	if _, ok := aisleKeywords[word]; ok {
+		return word
+	}
+	for _, suffix := range []string{"oes", "ies", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word) > len(suffix)+2 {
+			base := strings.TrimSuffix(word, suffix)
+			switch suffix {
+			case "oes":
+				base += "o"
+			case "ies":
+				base += "y"
+			}
+			if _, ok := aisleKeywords[base]; ok {
+				return base
+			}
+		}
+	}
+	return word
+}
@@ -0,0 +1,34 @@
+package shopping
+
+import "testing"
+
+func TestClassifyByRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   Aisle
+		wantOK bool
+	}{
+		{"garlic", AisleProduce, true},
+		{"tomatoes", AisleProduce, true},
+		{"chicken breast", AisleMeat, true},
+		{"chicken broth", AislePantry, true},
+		{"peanut butter", AislePantry, true},
+		{"butter", AisleDairy, true},
+		{"black pepper", AisleSpices, true},
+		{"red pepper", AisleProduce, true},
+		{"garlic powder", AisleSpices, true},
+		{"salmon fillets", AisleSeafood, true},
+		{"queijo", AisleDairy, true},
+		{"guanciale", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ClassifyByRules(tt.name)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ClassifyByRules(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package stats
+
+import "context"
+
+// Repository defines the interface for cooking stats persistence (Port)
+type Repository interface {
+	// FindByUser retrieves the stats of a user.
+	// It returns shared.ErrStatsNotFound if nothing was cooked yet.
+	FindByUser(ctx context.Context, userID UserID) (*Stats, error)
+
+	// Save persists stats, replacing the user's previous ones
+	Save(ctx context.Context, s *Stats) error
+
+	// Modify reads the stats of a user, new ones if nothing was cooked yet, applies
+	// change and saves them in one transaction, so cooking recorded at the same time
+	// is not lost. change may run more than once; when it fails nothing is saved.
+	// Returns the stats as saved.
+	Modify(ctx context.Context, userID UserID, change func(s *Stats) error) (*Stats, error)
+}
@@ -0,0 +1,349 @@
+// Package stats keeps a user's record of cooking at home, from which streaks,
+// badges and monthly recaps are drawn.
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// UserID represents a unique user identifier
+type UserID = shared.ID
+
+// RecipeID represents a unique recipe identifier
+type RecipeID = shared.ID
+
+// MonthsKept is how many months of tallies are kept for recaps
+const MonthsKept = 12
+
+// Layouts of the days and months stats are kept by, in the user's time zone
+const (
+	dayLayout   = "2006-01-02"
+	monthLayout = "2006-01"
+)
+
+// Metric is what a badge counts
+type Metric string
+
+const (
+	MetricStreak   Metric = "streak"   // days in a row cooked at home
+	MetricRecipes  Metric = "recipes"  // different recipes cooked
+	MetricCuisines Metric = "cuisines" // different cuisines cooked
+)
+
+// Badge is an achievement earned by reaching a goal in a metric
+type Badge struct {
+	ID     string
+	Emoji  string
+	Name   string
+	Metric Metric
+	Goal   int
+}
+
+// AllBadges returns every badge, in the order they are shown
+func AllBadges() []Badge {
+	return []Badge{
+		{ID: "first_recipe", Emoji: "🍳", Name: "First dish", Metric: MetricRecipes, Goal: 1},
+		{ID: "streak_3", Emoji: "🔥", Name: "On a roll", Metric: MetricStreak, Goal: 3},
+		{ID: "streak_7", Emoji: "📅", Name: "Week of home cooking", Metric: MetricStreak, Goal: 7},
+		{ID: "streak_30", Emoji: "🏆", Name: "Month of home cooking", Metric: MetricStreak, Goal: 30},
+		{ID: "recipes_10", Emoji: "🧭", Name: "Explorer", Metric: MetricRecipes, Goal: 10},
+		{ID: "recipes_50", Emoji: "📚", Name: "Repertoire", Metric: MetricRecipes, Goal: 50},
+		{ID: "cuisines_3", Emoji: "🌍", Name: "Globetrotter", Metric: MetricCuisines, Goal: 3},
+		{ID: "cuisines_10", Emoji: "✈️", Name: "World tour", Metric: MetricCuisines, Goal: 10},
+	}
+}
+
+// BadgeByID returns the badge with an ID, or false if there is none
+func BadgeByID(id string) (Badge, bool) {
+	for _, b := range AllBadges() {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return Badge{}, false
+}
+
+// Description returns what it takes to earn the badge
+func (b Badge) Description() string {
+	switch b.Metric {
+	case MetricStreak:
+		return fmt.Sprintf("Cook at home %d days in a row", b.Goal)
+	case MetricRecipes:
+		if b.Goal == 1 {
+			return "Cook your first recipe"
+		}
+		return fmt.Sprintf("Cook %d different recipes", b.Goal)
+	case MetricCuisines:
+		return fmt.Sprintf("Cook %d different cuisines", b.Goal)
+	default:
+		return b.Name
+	}
+}
+
+// Earned is a badge and when it was earned
+type Earned struct {
+	Badge    Badge
+	EarnedAt time.Time
+}
+
+// Month is the tally of a month of cooking at home
+type Month struct {
+	Cooked      int      // meals cooked
+	Days        int      // days cooked on
+	NewRecipes  int      // recipes cooked for the first time
+	NewCuisines []string // cuisines cooked for the first time
+	Badges      []string // IDs of the badges earned
+}
+
+// Stats is a user's record of cooking at home (Aggregate Root)
+type Stats struct {
+	userID        UserID
+	currentStreak int
+	longestStreak int
+	lastCooked    string               // day of the last meal cooked
+	recipes       map[RecipeID]bool    // recipes cooked at least once
+	cuisines      map[string]bool      // lowercase cuisines cooked at least once
+	earned        map[string]time.Time // badge ID -> when it was earned
+	months        map[string]Month     // keyed by month, e.g. "2026-10"
+	updatedAt     shared.Timestamp
+}
+
+// NewStats creates an empty record for a user
+func NewStats(userID UserID) (*Stats, error) {
+	if userID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	return &Stats{
+		userID:    userID,
+		recipes:   make(map[RecipeID]bool),
+		cuisines:  make(map[string]bool),
+		earned:    make(map[string]time.Time),
+		months:    make(map[string]Month),
+		updatedAt: shared.NewTimestamp(),
+	}, nil
+}
+
+// StatsData contains data for reconstructing stats from storage
+type StatsData struct {
+	UserID        UserID
+	CurrentStreak int
+	LongestStreak int
+	LastCooked    string
+	Recipes       []RecipeID
+	Cuisines      []string
+	Earned        map[string]time.Time
+	Months        map[string]Month
+	UpdatedAt     time.Time
+}
+
+// ReconstructStats reconstructs stats from stored data (for repository)
+func ReconstructStats(data StatsData) *Stats {
+	s := &Stats{
+		userID:        data.UserID,
+		currentStreak: data.CurrentStreak,
+		longestStreak: data.LongestStreak,
+		lastCooked:    data.LastCooked,
+		recipes:       make(map[RecipeID]bool, len(data.Recipes)),
+		cuisines:      make(map[string]bool, len(data.Cuisines)),
+		earned:        make(map[string]time.Time, len(data.Earned)),
+		months:        make(map[string]Month, len(data.Months)),
+		updatedAt:     shared.NewTimestampFromTime(data.UpdatedAt),
+	}
+	for _, id := range data.Recipes {
+		s.recipes[id] = true
+	}
+	for _, c := range data.Cuisines {
+		s.cuisines[c] = true
+	}
+	for id, at := range data.Earned {
+		s.earned[id] = at
+	}
+	for key, m := range data.Months {
+		s.months[key] = m
+	}
+	return s
+}
+
+// UserID returns the owner of the stats
+func (s *Stats) UserID() UserID {
+	return s.userID
+}
+
+// CurrentStreak returns the days in a row cooked up to the last day cooked.
+// Use Streak for the streak still running on a given day.
+func (s *Stats) CurrentStreak() int {
+	return s.currentStreak
+}
+
+// LongestStreak returns the most days in a row ever cooked
+func (s *Stats) LongestStreak() int {
+	return s.longestStreak
+}
+
+// LastCooked returns the day of the last meal cooked, e.g. "2026-10-16", empty if none
+func (s *Stats) LastCooked() string {
+	return s.lastCooked
+}
+
+// Recipes returns the recipes cooked at least once, sorted
+func (s *Stats) Recipes() []RecipeID {
+	ids := make([]RecipeID, 0, len(s.recipes))
+	for id := range s.recipes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Cuisines returns the lowercase cuisines cooked at least once, sorted
+func (s *Stats) Cuisines() []string {
+	cuisines := make([]string, 0, len(s.cuisines))
+	for c := range s.cuisines {
+		cuisines = append(cuisines, c)
+	}
+	sort.Strings(cuisines)
+	return cuisines
+}
+
+// Earned returns the badges earned, in the order of AllBadges
+func (s *Stats) Earned() []Earned {
+	var earned []Earned
+	for _, b := range AllBadges() {
+		if at, ok := s.earned[b.ID]; ok {
+			earned = append(earned, Earned{Badge: b, EarnedAt: at})
+		}
+	}
+	return earned
+}
+
+// Months returns the tallies of the months kept, keyed by month, e.g. "2026-10"
+func (s *Stats) Months() map[string]Month {
+	return s.months
+}
+
+// UpdatedAt returns the last update timestamp
+func (s *Stats) UpdatedAt() time.Time {
+	return s.updatedAt.Time()
+}
+
+// Streak returns the days in a row cooked that are still running on the day of
+// now: a streak lasts until a whole day passes without cooking
+func (s *Stats) Streak(now time.Time) int {
+	if s.lastCooked == now.Format(dayLayout) || s.lastCooked == now.AddDate(0, 0, -1).Format(dayLayout) {
+		return s.currentStreak
+	}
+	return 0
+}
+
+// Progress returns how far the user is towards a badge on the day of now
+func (s *Stats) Progress(b Badge, now time.Time) int {
+	switch b.Metric {
+	case MetricStreak:
+		return s.Streak(now)
+	case MetricRecipes:
+		return len(s.recipes)
+	case MetricCuisines:
+		return len(s.cuisines)
+	default:
+		return 0
+	}
+}
+
+// RecordCooking records a meal of a recipe cooked at home at cookedAt, in the
+// user's time zone, and returns the badges it earned
+func (s *Stats) RecordCooking(recipeID RecipeID, cuisine string, cookedAt time.Time) ([]Badge, error) {
+	if recipeID.IsEmpty() {
+		return nil, shared.ErrInvalidInput
+	}
+
+	key := cookedAt.Format(monthLayout)
+	month := s.months[key]
+	month.Cooked++
+
+	// Cooking again on the same day keeps the streak as it is
+	if day := cookedAt.Format(dayLayout); day > s.lastCooked {
+		if s.lastCooked == cookedAt.AddDate(0, 0, -1).Format(dayLayout) {
+			s.currentStreak++
+		} else {
+			s.currentStreak = 1
+		}
+		s.longestStreak = max(s.longestStreak, s.currentStreak)
+		s.lastCooked = day
+		month.Days++
+	}
+
+	if !s.recipes[recipeID] {
+		s.recipes[recipeID] = true
+		month.NewRecipes++
+	}
+	if name := strings.TrimSpace(cuisine); name != "" && !s.cuisines[strings.ToLower(name)] {
+		s.cuisines[strings.ToLower(name)] = true
+		month.NewCuisines = append(month.NewCuisines, name)
+	}
+
+	var earned []Badge
+	for _, b := range AllBadges() {
+		if _, ok := s.earned[b.ID]; ok || s.Progress(b, cookedAt) < b.Goal {
+			continue
+		}
+		s.earned[b.ID] = cookedAt
+		month.Badges = append(month.Badges, b.ID)
+		earned = append(earned, b)
+	}
+
+	s.months[key] = month
+	s.trimMonths()
+	s.updatedAt = shared.NewTimestamp()
+	return earned, nil
+}
+
+// Recap returns the tally of the month of t, or false if nothing was cooked in it
+func (s *Stats) Recap(t time.Time) (Month, bool) {
+	month, ok := s.months[t.Format(monthLayout)]
+	return month, ok && month.Cooked > 0
+}
+
+// Clone returns a copy of the stats that shares no mutable state with the original
+func (s *Stats) Clone() *Stats {
+	cp := *s
+	cp.recipes = make(map[RecipeID]bool, len(s.recipes))
+	for id := range s.recipes {
+		cp.recipes[id] = true
+	}
+	cp.cuisines = make(map[string]bool, len(s.cuisines))
+	for c := range s.cuisines {
+		cp.cuisines[c] = true
+	}
+	cp.earned = make(map[string]time.Time, len(s.earned))
+	for id, at := range s.earned {
+		cp.earned[id] = at
+	}
+	cp.months = make(map[string]Month, len(s.months))
+	for key, m := range s.months {
+		m.NewCuisines = append([]string(nil), m.NewCuisines...)
+		m.Badges = append([]string(nil), m.Badges...)
+		cp.months[key] = m
+	}
+	return &cp
+}
+
+// trimMonths drops the oldest tallies beyond MonthsKept
+func (s *Stats) trimMonths() {
+	if len(s.months) <= MonthsKept {
+		return
+	}
+	keys := make([]string, 0, len(s.months))
+	for key := range s.months {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys[:len(keys)-MonthsKept] {
+		delete(s.months, key)
+	}
+}
@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestStats_RecordCooking(t *testing.T) {
+	s, _ := NewStats(shared.NewID())
+	day := func(d, hour int) time.Time {
+		return time.Date(2026, time.October, d, hour, 0, 0, 0, time.UTC)
+	}
+
+	carbonara, curry, ramen := shared.NewID(), shared.NewID(), shared.NewID()
+
+	earned, err := s.RecordCooking(carbonara, "Italian", day(1, 19))
+	if err != nil {
+		t.Fatalf("RecordCooking() error = %v", err)
+	}
+	if len(earned) != 1 || earned[0].ID != "first_recipe" {
+		t.Errorf("first meal earned %+v, want the first dish badge", earned)
+	}
+
+	// Twice on one day counts once for the streak
+	_, _ = s.RecordCooking(carbonara, "italian", day(1, 21))
+	_, _ = s.RecordCooking(curry, "Indian", day(2, 19))
+	earned, _ = s.RecordCooking(ramen, "Japanese", day(3, 19))
+	if s.Streak(day(3, 22)) != 3 || len(earned) != 2 || earned[0].ID != "streak_3" || earned[1].ID != "cuisines_3" {
+		t.Errorf("streak = %d, earned %+v, want 3 and the streak and cuisine badges", s.Streak(day(3, 22)), earned)
+	}
+
+	// The streak survives the next day and ends after a day without cooking
+	if s.Streak(day(4, 12)) != 3 || s.Streak(day(5, 12)) != 0 {
+		t.Errorf("Streak() = %d the next day and %d after a day off, want 3 and 0", s.Streak(day(4, 12)), s.Streak(day(5, 12)))
+	}
+	_, _ = s.RecordCooking(curry, "Indian", day(6, 19))
+	if s.CurrentStreak() != 1 || s.LongestStreak() != 3 {
+		t.Errorf("streak after a break = %d, longest %d, want 1 and 3", s.CurrentStreak(), s.LongestStreak())
+	}
+
+	month, ok := s.Recap(day(15, 0))
+	if !ok || month.Cooked != 5 || month.Days != 4 || month.NewRecipes != 3 || len(month.NewCuisines) != 3 || len(month.Badges) != 3 {
+		t.Errorf("Recap() = %+v, %v", month, ok)
+	}
+	if _, ok := s.Recap(day(1, 0).AddDate(0, 1, 0)); ok {
+		t.Error("Recap() of a month without cooking = true, want false")
+	}
+}
+
+func TestStats_KeepsRecentMonths(t *testing.T) {
+	s, _ := NewStats(shared.NewID())
+	start := time.Date(2025, time.January, 10, 19, 0, 0, 0, time.UTC)
+	for i := 0; i < MonthsKept+3; i++ {
+		_, _ = s.RecordCooking(shared.NewID(), "", start.AddDate(0, i, 0))
+	}
+
+	if len(s.Months()) != MonthsKept {
+		t.Errorf("kept %d months, want %d", len(s.Months()), MonthsKept)
+	}
+	if _, ok := s.Recap(start); ok {
+		t.Error("Recap() of the oldest month = true, want it dropped")
+	}
+}
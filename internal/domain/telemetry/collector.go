@@ -0,0 +1,137 @@
+package telemetry
+
+import (
+	"sync"
+	"time"
+)
+
+// ExtractionStats counts recipe extractions from one platform
+type ExtractionStats struct {
+	Attempts  int
+	Successes int
+}
+
+// SuccessRate returns the share of extractions that succeeded
+func (s ExtractionStats) SuccessRate() float64 {
+	if s.Attempts == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(s.Attempts)
+}
+
+// FeedbackStats counts the thumbs up and down users gave for one intent type
+type FeedbackStats struct {
+	Helpful    int
+	NotHelpful int
+}
+
+// Accuracy returns the share of feedback saying the intent was understood
+func (s FeedbackStats) Accuracy() float64 {
+	total := s.Helpful + s.NotHelpful
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Helpful) / float64(total)
+}
+
+// Report is a batch of anonymous quality metrics. It only holds aggregate counts,
+// never user IDs, messages or links.
+type Report struct {
+	From        time.Time
+	To          time.Time
+	Extractions map[string]ExtractionStats // by platform
+	Intents     map[string]FeedbackStats   // by intent type
+}
+
+// IsEmpty reports whether nothing was recorded in the report
+func (r Report) IsEmpty() bool {
+	return len(r.Extractions) == 0 && len(r.Intents) == 0
+}
+
+// Collector aggregates quality metrics in memory until they are flushed
+type Collector struct {
+	mu          sync.Mutex
+	from        time.Time
+	extractions map[string]ExtractionStats
+	intents     map[string]FeedbackStats
+	now         func() time.Time
+}
+
+// NewCollector creates a new collector
+func NewCollector() *Collector {
+	c := &Collector{now: time.Now}
+	c.reset()
+	return c
+}
+
+// reset starts a new batch
+func (c *Collector) reset() {
+	c.from = c.now()
+	c.extractions = make(map[string]ExtractionStats)
+	c.intents = make(map[string]FeedbackStats)
+}
+
+// RecordExtraction counts an extraction from the platform
+func (c *Collector) RecordExtraction(platform string, success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.extractions[platform]
+	stats.Attempts++
+	if success {
+		stats.Successes++
+	}
+	c.extractions[platform] = stats
+}
+
+// RecordIntentFeedback counts a thumbs up (helpful) or down for a detected intent type
+func (c *Collector) RecordIntentFeedback(intent string, helpful bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.intents[intent]
+	if helpful {
+		stats.Helpful++
+	} else {
+		stats.NotHelpful++
+	}
+	c.intents[intent] = stats
+}
+
+// Flush returns the metrics recorded since the last flush and starts a new batch
+func (c *Collector) Flush() Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := Report{
+		From:        c.from,
+		To:          c.now(),
+		Extractions: c.extractions,
+		Intents:     c.intents,
+	}
+	c.reset()
+	return report
+}
+
+// Restore adds back the metrics of a report that could not be sent,
+// so they go out with the next batch
+func (c *Collector) Restore(report Report) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if report.From.Before(c.from) {
+		c.from = report.From
+	}
+	for platform, stats := range report.Extractions {
+		current := c.extractions[platform]
+		current.Attempts += stats.Attempts
+		current.Successes += stats.Successes
+		c.extractions[platform] = current
+	}
+	for intent, stats := range report.Intents {
+		current := c.intents[intent]
+		current.Helpful += stats.Helpful
+		current.NotHelpful += stats.NotHelpful
+		c.intents[intent] = current
+	}
+}
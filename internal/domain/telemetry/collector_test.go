@@ -0,0 +1,63 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCollector_Flush(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	collector := NewCollector()
+	collector.now = func() time.Time { return now }
+	collector.reset()
+
+	collector.RecordExtraction("tiktok", true)
+	collector.RecordExtraction("tiktok", false)
+	collector.RecordExtraction("youtube", true)
+	collector.RecordIntentFeedback("LIST_RECIPES", true)
+	collector.RecordIntentFeedback("LIST_RECIPES", true)
+	collector.RecordIntentFeedback("LIST_RECIPES", false)
+
+	now = now.Add(time.Hour)
+	report := collector.Flush()
+
+	if !report.From.Equal(now.Add(-time.Hour)) || !report.To.Equal(now) {
+		t.Errorf("Flush() window = %v - %v", report.From, report.To)
+	}
+	if tiktok := report.Extractions["tiktok"]; tiktok.Attempts != 2 || tiktok.SuccessRate() != 0.5 {
+		t.Errorf("tiktok stats = %+v", tiktok)
+	}
+	if youtube := report.Extractions["youtube"]; youtube.SuccessRate() != 1 {
+		t.Errorf("youtube stats = %+v", youtube)
+	}
+	if list := report.Intents["LIST_RECIPES"]; list.Helpful != 2 || list.NotHelpful != 1 {
+		t.Errorf("LIST_RECIPES feedback = %+v", list)
+	}
+
+	// The next batch starts empty
+	if next := collector.Flush(); !next.IsEmpty() {
+		t.Errorf("second Flush() = %+v, want an empty report", next)
+	}
+}
+
+func TestCollector_Restore(t *testing.T) {
+	collector := NewCollector()
+
+	collector.RecordExtraction("web", false)
+	failed := collector.Flush()
+
+	collector.RecordExtraction("web", true)
+	collector.RecordIntentFeedback("SHOW_DETAILS", false)
+	collector.Restore(failed)
+
+	report := collector.Flush()
+	if web := report.Extractions["web"]; web.Attempts != 2 || web.Successes != 1 {
+		t.Errorf("web stats = %+v, want the restored attempt counted", web)
+	}
+	if details := report.Intents["SHOW_DETAILS"]; details.Accuracy() != 0 || details.NotHelpful != 1 {
+		t.Errorf("SHOW_DETAILS feedback = %+v", details)
+	}
+	if !report.From.Equal(failed.From) {
+		t.Errorf("report starts at %v, want the restored batch start %v", report.From, failed.From)
+	}
+}
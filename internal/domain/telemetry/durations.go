@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// durationWindow is how many of the latest durations the average covers
+	durationWindow = 20
+
+	// minDurationSamples is how many durations are needed before the average is trusted
+	minDurationSamples = 3
+)
+
+// Durations keeps a rolling average of how long an operation takes, by key, such as
+// recipe extraction by platform. Unlike the Collector, it is never flushed.
+type Durations struct {
+	mu      sync.Mutex
+	samples map[string][]time.Duration // oldest first, at most durationWindow per key
+}
+
+// NewDurations creates an empty store
+func NewDurations() *Durations {
+	return &Durations{samples: make(map[string][]time.Duration)}
+}
+
+// Record adds how long one operation took, dropping the oldest duration past the window
+func (d *Durations) Record(key string, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	samples := append(d.samples[key], duration)
+	if len(samples) > durationWindow {
+		samples = samples[len(samples)-durationWindow:]
+	}
+	d.samples[key] = samples
+}
+
+// Average returns the average of the latest durations of the key, and false until
+// enough were recorded to go by
+func (d *Durations) Average(key string) (time.Duration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	samples := d.samples[key]
+	if len(samples) < minDurationSamples {
+		return 0, false
+	}
+
+	var total time.Duration
+	for _, s := range samples {
+		total += s
+	}
+	return total / time.Duration(len(samples)), true
+}
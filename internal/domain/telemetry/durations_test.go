@@ -0,0 +1,33 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurations_Average(t *testing.T) {
+	durations := NewDurations()
+
+	durations.Record("tiktok", 30*time.Second)
+	durations.Record("tiktok", 50*time.Second)
+	if _, ok := durations.Average("tiktok"); ok {
+		t.Error("Average() with 2 durations should not be trusted yet")
+	}
+
+	durations.Record("tiktok", 40*time.Second)
+	if avg, ok := durations.Average("tiktok"); !ok || avg != 40*time.Second {
+		t.Errorf("Average() = %v, %v, want 40s", avg, ok)
+	}
+
+	// Only the latest durations count
+	for i := 0; i < durationWindow; i++ {
+		durations.Record("tiktok", 10*time.Second)
+	}
+	if avg, _ := durations.Average("tiktok"); avg != 10*time.Second {
+		t.Errorf("Average() after the window rolled = %v, want 10s", avg)
+	}
+
+	if _, ok := durations.Average("youtube"); ok {
+		t.Error("Average() of an unknown key should not be trusted")
+	}
+}
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// AutoExportTarget is where saved recipes are exported to without /export
+type AutoExportTarget string
+
+const (
+	AutoExportNotion   AutoExportTarget = "notion"
+	AutoExportObsidian AutoExportTarget = "obsidian"
+)
+
+// AutoExportSchedule is when saved recipes are exported
+type AutoExportSchedule string
+
+const (
+	AutoExportOnSave  AutoExportSchedule = "on_save" // shortly after each recipe is saved
+	AutoExportNightly AutoExportSchedule = "nightly" // once a night, every recipe saved that day
+)
+
+// AutoExport keeps a user's recipes exported as they save them. Since works as a
+// cursor: recipes saved after it are waiting for the next export.
+type AutoExport struct {
+	Target   AutoExportTarget
+	Schedule AutoExportSchedule
+	Since    time.Time
+}
+
+// NewAutoExport turns on auto-export from now on; recipes saved before are left to /export
+func NewAutoExport(target AutoExportTarget, schedule AutoExportSchedule, now time.Time) (*AutoExport, error) {
+	if target != AutoExportNotion && target != AutoExportObsidian {
+		return nil, shared.ErrInvalidAutoExport
+	}
+	if schedule != AutoExportOnSave && schedule != AutoExportNightly {
+		return nil, shared.ErrInvalidAutoExport
+	}
+	return &AutoExport{Target: target, Schedule: schedule, Since: now}, nil
+}
+
+// AutoExport returns the user's auto-export, nil when it is off
+func (u *User) AutoExport() *AutoExport {
+	return u.autoExport
+}
+
+// SetAutoExport replaces the user's auto-export; nil turns it off
+func (u *User) SetAutoExport(autoExport *AutoExport) {
+	u.autoExport = autoExport
+}
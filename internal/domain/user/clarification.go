@@ -0,0 +1,90 @@
+package user
+
+import "strings"
+
+// UsualChoiceAfter is how many times in a row a user must answer a clarifying
+// question the same way before the answer is taken as their usual choice
+const UsualChoiceAfter = 3
+
+// MaxClarificationChoices is how many answered questions are remembered per user
+const MaxClarificationChoices = 50
+
+// ClarificationChoice is how a user answered the clarifying question asked about
+// a message, e.g. "something spicy" = "Any recipe with hot peppers", and how many
+// times in a row they answered it that way
+type ClarificationChoice struct {
+	Message string
+	Choice  string
+	Count   int
+}
+
+// normalizeClarificationText lowercases a message or answer, collapses its
+// whitespace and drops trailing punctuation, so "Something spicy!" matches "something spicy"
+func normalizeClarificationText(text string) string {
+	return strings.TrimRight(strings.Join(strings.Fields(strings.ToLower(text)), " "), " .!?")
+}
+
+// ClarificationChoices returns how the user answered clarifying questions
+func (u *User) ClarificationChoices() []ClarificationChoice {
+	return u.clarificationChoices
+}
+
+// SetClarificationChoices replaces how the user answered clarifying questions
+func (u *User) SetClarificationChoices(choices []ClarificationChoice) {
+	u.clarificationChoices = choices
+}
+
+// RecordClarificationChoice records the answer to the question asked about a message.
+// The same answer again counts towards the usual choice; a different one starts over.
+// The least recently answered question is forgotten beyond MaxClarificationChoices.
+// Returns false if the message or the answer is empty.
+func (u *User) RecordClarificationChoice(message, choice string) bool {
+	message = normalizeClarificationText(message)
+	choice = strings.TrimSpace(choice)
+	if message == "" || choice == "" {
+		return false
+	}
+
+	record := ClarificationChoice{Message: message, Choice: choice, Count: 1}
+	for i, c := range u.clarificationChoices {
+		if c.Message != message {
+			continue
+		}
+		if normalizeClarificationText(c.Choice) == normalizeClarificationText(choice) {
+			record.Count = c.Count + 1
+		}
+		u.clarificationChoices = append(u.clarificationChoices[:i:i], u.clarificationChoices[i+1:]...)
+		break
+	}
+
+	if len(u.clarificationChoices) >= MaxClarificationChoices {
+		u.clarificationChoices = u.clarificationChoices[len(u.clarificationChoices)-MaxClarificationChoices+1:]
+	}
+	u.clarificationChoices = append(u.clarificationChoices, record)
+	return true
+}
+
+// UsualClarificationChoice returns the answer the user gave at least UsualChoiceAfter
+// times in a row to the question asked about a message, or false if there is none
+func (u *User) UsualClarificationChoice(message string) (string, bool) {
+	message = normalizeClarificationText(message)
+	for _, c := range u.clarificationChoices {
+		if c.Message == message && c.Count >= UsualChoiceAfter {
+			return c.Choice, true
+		}
+	}
+	return "", false
+}
+
+// ForgetClarificationChoice forgets how the user answered the question asked about
+// a message, so it is asked again. Returns false if nothing was remembered.
+func (u *User) ForgetClarificationChoice(message string) bool {
+	message = normalizeClarificationText(message)
+	for i, c := range u.clarificationChoices {
+		if c.Message == message {
+			u.clarificationChoices = append(u.clarificationChoices[:i:i], u.clarificationChoices[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package user
+
+import "testing"
+
+func TestUser_UsualClarificationChoice(t *testing.T) {
+	u := &User{}
+
+	for i := 0; i < UsualChoiceAfter-1; i++ {
+		u.RecordClarificationChoice("Something spicy", "Any recipe with hot peppers")
+	}
+	if _, ok := u.UsualClarificationChoice("something spicy"); ok {
+		t.Fatalf("UsualClarificationChoice() found a choice after %d answers", UsualChoiceAfter-1)
+	}
+
+	u.RecordClarificationChoice("  something SPICY! ", "any recipe with hot peppers")
+	if got, ok := u.UsualClarificationChoice("something spicy?"); !ok || got != "any recipe with hot peppers" {
+		t.Errorf("UsualClarificationChoice() = %q, %v; want the hot peppers choice", got, ok)
+	}
+
+	// A different answer starts over
+	u.RecordClarificationChoice("something spicy", "Spicy seafood")
+	if _, ok := u.UsualClarificationChoice("something spicy"); ok {
+		t.Error("UsualClarificationChoice() kept the choice after a different answer")
+	}
+
+	if !u.ForgetClarificationChoice("something spicy") || len(u.ClarificationChoices()) != 0 {
+		t.Errorf("ForgetClarificationChoice() left %+v", u.ClarificationChoices())
+	}
+	if u.RecordClarificationChoice("something spicy", " ") {
+		t.Error("RecordClarificationChoice() recorded an empty answer")
+	}
+}
+
+func TestUser_RecordClarificationChoice_ForgetsOldest(t *testing.T) {
+	u := &User{}
+	for i := 0; i <= MaxClarificationChoices; i++ {
+		u.RecordClarificationChoice(string(rune('a'+i%26))+string(rune('a'+i/26)), "choice")
+	}
+
+	choices := u.ClarificationChoices()
+	if len(choices) != MaxClarificationChoices {
+		t.Fatalf("ClarificationChoices() has %d answers, want %d", len(choices), MaxClarificationChoices)
+	}
+	if choices[0].Message != "ba" {
+		t.Errorf("oldest answer kept = %q, want the first one forgotten", choices[0].Message)
+	}
+}
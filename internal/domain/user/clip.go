@@ -0,0 +1,12 @@
+package user
+
+// ClipTokenVersion returns the version of the user's browser extension token.
+// Raising it invalidates the tokens given out before.
+func (u *User) ClipTokenVersion() int {
+	return u.clipTokenVersion
+}
+
+// SetClipTokenVersion sets the version of the user's browser extension token
+func (u *User) SetClipTokenVersion(version int) {
+	u.clipTokenVersion = version
+}
@@ -0,0 +1,90 @@
+package user
+
+import (
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// CloudProvider is a cloud storage service /export obsidian can write into
+type CloudProvider string
+
+const (
+	CloudDropbox CloudProvider = "dropbox"
+	CloudDrive   CloudProvider = "drive"
+)
+
+// DefaultCloudFolder is the folder exports are written to until the user picks another
+const DefaultCloudFolder = "Receipt Bot"
+
+// ParseCloudProvider parses a provider as users type it: "dropbox", "drive" or "gdrive"
+func ParseCloudProvider(s string) (CloudProvider, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "dropbox":
+		return CloudDropbox, nil
+	case "drive", "gdrive", "googledrive", "google":
+		return CloudDrive, nil
+	}
+	return "", shared.ErrInvalidCloudProvider
+}
+
+// Name returns the provider's name as shown to users
+func (p CloudProvider) Name() string {
+	switch p {
+	case CloudDropbox:
+		return "Dropbox"
+	case CloudDrive:
+		return "Google Drive"
+	}
+	return string(p)
+}
+
+// CloudStorage is the cloud storage folder a user connected for their Markdown exports
+type CloudStorage struct {
+	Provider     CloudProvider
+	RefreshToken string // exchanged for a short-lived access token on each export
+	Folder       string // path under the root of the storage, without leading slash
+	ConnectedAt  time.Time
+}
+
+// NewCloudStorage connects a provider with the token its OAuth flow granted,
+// exporting into DefaultCloudFolder
+func NewCloudStorage(provider CloudProvider, refreshToken string, now time.Time) (*CloudStorage, error) {
+	if provider != CloudDropbox && provider != CloudDrive {
+		return nil, shared.ErrInvalidCloudProvider
+	}
+	if refreshToken == "" {
+		return nil, shared.ErrInvalidInput
+	}
+	return &CloudStorage{Provider: provider, RefreshToken: refreshToken, Folder: DefaultCloudFolder, ConnectedAt: now}, nil
+}
+
+// SetFolder changes the folder exports are written to, "Recipes/Mains" or "/Recipes".
+// An empty path goes back to DefaultCloudFolder.
+func (c *CloudStorage) SetFolder(path string) error {
+	parts := strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' })
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "." || part == ".." {
+			return shared.ErrInvalidInput
+		}
+		parts[i] = part
+	}
+	if len(parts) == 0 {
+		c.Folder = DefaultCloudFolder
+		return nil
+	}
+	c.Folder = strings.Join(parts, "/")
+	return nil
+}
+
+// CloudStorage returns the user's connected cloud storage, nil when none is
+func (u *User) CloudStorage() *CloudStorage {
+	return u.cloudStorage
+}
+
+// SetCloudStorage replaces the user's cloud storage; nil disconnects it
+func (u *User) SetCloudStorage(storage *CloudStorage) {
+	u.cloudStorage = storage
+}
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestCloudStorage_SetFolder(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{"Recipes", "Recipes", nil},
+		{"/Recipes/Mains/", "Recipes/Mains", nil},
+		{`Notes\Recipes`, "Notes/Recipes", nil},
+		{" Obsidian / Recipes ", "Obsidian/Recipes", nil},
+		{"", DefaultCloudFolder, nil},
+		{"Recipes/../Secrets", "", shared.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		storage, err := NewCloudStorage(CloudDropbox, "token", time.Now())
+		if err != nil {
+			t.Fatalf("NewCloudStorage() error = %v", err)
+		}
+		err = storage.SetFolder(tt.input)
+		if !errors.Is(err, tt.err) || (err == nil && storage.Folder != tt.want) {
+			t.Errorf("SetFolder(%q) = %q, %v, want %q, %v", tt.input, storage.Folder, err, tt.want, tt.err)
+		}
+	}
+
+	if _, err := NewCloudStorage("box", "token", time.Now()); !errors.Is(err, shared.ErrInvalidCloudProvider) {
+		t.Errorf("NewCloudStorage(box) error = %v, want ErrInvalidCloudProvider", err)
+	}
+}
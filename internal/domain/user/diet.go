@@ -0,0 +1,11 @@
+package user
+
+// Diet returns the diet the user's recipes are checked against, empty when none
+func (u *User) Diet() string {
+	return u.diet
+}
+
+// SetDiet sets the diet the user's recipes are checked against; empty clears it
+func (u *User) SetDiet(diet string) {
+	u.diet = diet
+}
@@ -52,11 +52,65 @@ type User struct {
 	pantryItems     []string
 	pantryUpdatedAt *time.Time
 
+	// linkedTelegramIDs are other Telegram accounts that share this user's collection
+	linkedTelegramIDs []int64
+
+	// savedFilters are named searches the user can re-run
+	savedFilters []SavedFilter
+
+	// shortcuts expand short texts the user types into longer messages
+	shortcuts []Shortcut
+
+	// clarificationChoices are how the user answered clarifying questions, oldest first
+	clarificationChoices []ClarificationChoice
+
+	// notifications are the notification kinds the user turned on or off
+	notifications map[Notification]bool
+
+	// staples are the ingredients the user always has, nil until they choose their own
+	staples []string
+
+	// exportFields are the recipe fields exported by default, nil for the default fields
+	exportFields []string
+
+	// timezone is the IANA name of the user's timezone, empty for the server's
+	timezone string
+	// location is the timezone resolved once, nil for the server's
+	location *time.Location
+
+	// locale is the regional style of dates, empty for the default of the language
+	locale Locale
+
+	// plainMode writes messages without emojis or formatting, for screen readers
+	plainMode bool
+
+	// diet is the diet recipes are checked against, empty when none
+	diet string
+
+	// clipTokenVersion is signed into the browser extension token, raised to revoke it
+	clipTokenVersion int
+
+	// skipTranscripts leaves transcripts and captions out of the recipes the user saves
+	skipTranscripts bool
+
+	// quietHours are when scheduled messages wait until morning, nil when off
+	quietHours *QuietHours
+
+	// autoExport exports recipes as they are saved, nil when off
+	autoExport *AutoExport
+
+	// cloudStorage is where /export obsidian writes Markdown files, nil to send them in chat
+	cloudStorage *CloudStorage
+
+	// premiumUntil is when the premium tier the user paid for ends, nil if they never had it
+	premiumUntil *time.Time
+
 	// Notion integration
 	notionAccessToken  string
 	notionWorkspaceID  string
 	notionDatabaseID   string
 	notionConnectedAt  *time.Time
+	notionProperties   NotionProperties // recipe fields to the database's properties, nil until detected
 }
 
 // NewUser creates a new User
@@ -74,6 +128,22 @@ func NewUser(telegramID int64, username string) (*User, error) {
 	}, nil
 }
 
+// NewHousehold creates the account a group chat shares, holding the group's pantry
+// and shopping list. Group chat IDs are negative, unlike user IDs.
+func NewHousehold(chatID int64, title string) (*User, error) {
+	if chatID >= 0 {
+		return nil, shared.ErrInvalidTelegramID
+	}
+
+	return &User{
+		id:         shared.NewID(),
+		telegramID: chatID,
+		username:   title,
+		language:   DefaultLanguage(),
+		createdAt:  shared.NewTimestamp(),
+	}, nil
+}
+
 // UserData contains data for reconstructing a user from storage
 type UserData struct {
 	ID              UserID
@@ -84,11 +154,59 @@ type UserData struct {
 	PantryItems     []string
 	PantryUpdatedAt *time.Time
 
+	// Linked Telegram accounts (optional)
+	LinkedTelegramIDs []int64
+
+	// Saved filters (optional)
+	SavedFilters []SavedFilter
+
+	// Text shortcuts (optional)
+	Shortcuts []Shortcut
+
+	// Answers to clarifying questions (optional)
+	ClarificationChoices []ClarificationChoice
+
+	// Notification choices (optional)
+	Notifications map[Notification]bool
+
+	// Pantry staples, nil when the user never chose their own (optional)
+	Staples []string
+
+	// Default export fields, nil for the default fields (optional)
+	ExportFields []string
+
+	// Timezone, locale and quiet hours (optional)
+	Timezone   string
+	Locale     Locale
+	QuietHours *QuietHours
+
+	// Plain mode (optional)
+	PlainMode bool
+
+	// Diet (optional)
+	Diet string
+
+	// Browser extension token version (optional)
+	ClipTokenVersion int
+
+	// Transcript opt-out (optional)
+	SkipTranscripts bool
+
+	// Auto-export (optional)
+	AutoExport *AutoExport
+
+	// Cloud storage for exports (optional)
+	CloudStorage *CloudStorage
+
+	// End of the paid premium tier (optional)
+	PremiumUntil *time.Time
+
 	// Notion integration (optional)
 	NotionAccessToken string
 	NotionWorkspaceID string
 	NotionDatabaseID  string
 	NotionConnectedAt *time.Time
+	NotionProperties  NotionProperties
 }
 
 // ReconstructUser reconstructs a user from stored data (for repository)
@@ -110,6 +228,10 @@ func ReconstructUserFromData(data UserData) *User {
 	if !lang.IsValid() {
 		lang = DefaultLanguage()
 	}
+	locale := data.Locale
+	if !locale.IsValid() {
+		locale = ""
+	}
 	return &User{
 		id:                 data.ID,
 		telegramID:         data.TelegramID,
@@ -118,10 +240,29 @@ func ReconstructUserFromData(data UserData) *User {
 		createdAt:          data.CreatedAt,
 		pantryItems:        data.PantryItems,
 		pantryUpdatedAt:    data.PantryUpdatedAt,
+		linkedTelegramIDs:  data.LinkedTelegramIDs,
+		savedFilters:       data.SavedFilters,
+		shortcuts:          data.Shortcuts,
+		clarificationChoices: data.ClarificationChoices,
+		notifications:      data.Notifications,
+		staples:            data.Staples,
+		exportFields:       data.ExportFields,
+		timezone:           data.Timezone,
+		location:           loadLocation(data.Timezone),
+		locale:             locale,
+		plainMode:          data.PlainMode,
+		diet:               data.Diet,
+		clipTokenVersion:   data.ClipTokenVersion,
+		skipTranscripts:    data.SkipTranscripts,
+		quietHours:         data.QuietHours,
+		autoExport:         data.AutoExport,
+		cloudStorage:       data.CloudStorage,
+		premiumUntil:       data.PremiumUntil,
 		notionAccessToken:  data.NotionAccessToken,
 		notionWorkspaceID:  data.NotionWorkspaceID,
 		notionDatabaseID:   data.NotionDatabaseID,
 		notionConnectedAt:  data.NotionConnectedAt,
+		notionProperties:   data.NotionProperties,
 	}
 }
 
@@ -135,6 +276,11 @@ func (u *User) TelegramID() int64 {
 	return u.telegramID
 }
 
+// IsHousehold reports whether the account is shared by a group chat
+func (u *User) IsHousehold() bool {
+	return u.telegramID < 0
+}
+
 // Username returns the username
 func (u *User) Username() string {
 	return u.username
@@ -228,6 +374,97 @@ func (u *User) ClearPantry() {
 	u.pantryUpdatedAt = &now
 }
 
+// LinkedTelegramIDs returns the other Telegram accounts linked to this user
+func (u *User) LinkedTelegramIDs() []int64 {
+	return u.linkedTelegramIDs
+}
+
+// HasTelegramID checks if a Telegram account is the user's own or linked to it
+func (u *User) HasTelegramID(telegramID int64) bool {
+	if u.telegramID == telegramID {
+		return true
+	}
+	for _, id := range u.linkedTelegramIDs {
+		if id == telegramID {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkTelegramID links another Telegram account to this user's collection
+func (u *User) LinkTelegramID(telegramID int64) error {
+	if telegramID <= 0 {
+		return shared.ErrInvalidTelegramID
+	}
+	if u.HasTelegramID(telegramID) {
+		return shared.ErrAlreadyLinked
+	}
+	u.linkedTelegramIDs = append(u.linkedTelegramIDs, telegramID)
+	return nil
+}
+
+// UnlinkTelegramID removes a linked Telegram account.
+// The user's own account cannot be unlinked
+func (u *User) UnlinkTelegramID(telegramID int64) error {
+	for i, id := range u.linkedTelegramIDs {
+		if id == telegramID {
+			u.linkedTelegramIDs = append(u.linkedTelegramIDs[:i:i], u.linkedTelegramIDs[i+1:]...)
+			return nil
+		}
+	}
+	return shared.ErrNotLinked
+}
+
+// Clone returns a copy of the user that shares no mutable state with the original
+func (u *User) Clone() *User {
+	cp := *u
+	if u.pantryItems != nil {
+		cp.pantryItems = append([]string(nil), u.pantryItems...)
+	}
+	if u.linkedTelegramIDs != nil {
+		cp.linkedTelegramIDs = append([]int64(nil), u.linkedTelegramIDs...)
+	}
+	if u.savedFilters != nil {
+		cp.savedFilters = append([]SavedFilter(nil), u.savedFilters...)
+	}
+	if u.shortcuts != nil {
+		cp.shortcuts = append([]Shortcut(nil), u.shortcuts...)
+	}
+	if u.clarificationChoices != nil {
+		cp.clarificationChoices = append([]ClarificationChoice(nil), u.clarificationChoices...)
+	}
+	if u.notifications != nil {
+		cp.notifications = make(map[Notification]bool, len(u.notifications))
+		for n, enabled := range u.notifications {
+			cp.notifications[n] = enabled
+		}
+	}
+	if u.quietHours != nil {
+		quiet := *u.quietHours
+		cp.quietHours = &quiet
+	}
+	if u.autoExport != nil {
+		autoExport := *u.autoExport
+		cp.autoExport = &autoExport
+	}
+	if u.notionProperties != nil {
+		cp.notionProperties = make(NotionProperties, len(u.notionProperties))
+		for field, property := range u.notionProperties {
+			cp.notionProperties[field] = property
+		}
+	}
+	if u.cloudStorage != nil {
+		storage := *u.cloudStorage
+		cp.cloudStorage = &storage
+	}
+	if u.premiumUntil != nil {
+		until := *u.premiumUntil
+		cp.premiumUntil = &until
+	}
+	return &cp
+}
+
 // NotionAccessToken returns the Notion access token
 func (u *User) NotionAccessToken() string {
 	return u.notionAccessToken
@@ -268,4 +505,5 @@ func (u *User) ClearNotionConnection() {
 	u.notionWorkspaceID = ""
 	u.notionDatabaseID = ""
 	u.notionConnectedAt = nil
+	u.notionProperties = nil
 }
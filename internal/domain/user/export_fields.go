@@ -0,0 +1,12 @@
+package user
+
+// ExportFields returns the names of the recipe fields the user exports by default,
+// like "ingredients" and "steps". Nil means the default fields.
+func (u *User) ExportFields() []string {
+	return u.exportFields
+}
+
+// SetExportFields replaces the user's default export fields; nil goes back to the defaults
+func (u *User) SetExportFields(fields []string) {
+	u.exportFields = fields
+}
@@ -0,0 +1,74 @@
+package user
+
+import (
+	"strings"
+	"unicode"
+)
+
+// languageWords are common words that tell English and Portuguese apart. Words
+// both languages use, like "a", "as" or "me", are left out.
+var languageWords = map[Language]map[string]bool{
+	LanguageEnglish: wordSet(
+		"the", "and", "of", "to", "is", "are", "with", "without", "for", "my", "i",
+		"you", "what", "how", "show", "find", "give", "want", "can", "please", "recipe",
+		"recipes", "some", "any", "something", "this", "that", "it", "have", "which",
+		"hi", "hello", "thanks", "thank", "dinner", "lunch", "breakfast", "chicken", "cake",
+	),
+	LanguagePortuguese: wordSet(
+		"o", "os", "de", "do", "da", "dos", "das", "e", "com", "sem", "para", "pra",
+		"um", "uma", "meu", "minha", "minhas", "meus", "eu", "você", "voce", "que", "como",
+		"mostra", "mostre", "quero", "pode", "por", "favor", "receita", "receitas",
+		"algo", "alguma", "isso", "essa", "esse", "tem", "qual", "oi", "olá", "ola",
+		"obrigado", "obrigada", "jantar", "almoço", "almoco", "frango", "bolo", "não", "nao",
+	),
+}
+
+// portugueseLetters only appear in Portuguese words
+const portugueseLetters = "ãõçâêôáéíóú"
+
+// DetectLanguage guesses whether a message is in English or Portuguese from the
+// words it uses. Returns false when it cannot tell, e.g. for a link, a single
+// ingredient or a message mixing both languages evenly.
+func DetectLanguage(text string) (Language, bool) {
+	var words []string
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		// Links say nothing about the language they are shared in
+		if strings.Contains(field, "://") || strings.HasPrefix(field, "www.") {
+			continue
+		}
+		words = append(words, strings.FieldsFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && r != '\''
+		})...)
+	}
+
+	scores := make(map[Language]int)
+	for _, word := range words {
+		for lang, set := range languageWords {
+			if set[word] {
+				scores[lang]++
+			}
+		}
+		if strings.ContainsAny(word, portugueseLetters) && !languageWords[LanguagePortuguese][word] {
+			scores[LanguagePortuguese]++
+		}
+	}
+
+	en, pt := scores[LanguageEnglish], scores[LanguagePortuguese]
+	switch {
+	case en > pt:
+		return LanguageEnglish, true
+	case pt > en:
+		return LanguagePortuguese, true
+	default:
+		return "", false
+	}
+}
+
+// wordSet builds a set of words
+func wordSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,26 @@
+package user
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want Language
+		ok   bool
+	}{
+		{"show me my chicken recipes", LanguageEnglish, true},
+		{"me mostra as receitas de frango", LanguagePortuguese, true},
+		{"algo rápido pro jantar", LanguagePortuguese, true},
+		{"What can I cook with eggs?", LanguageEnglish, true},
+		{"lasagna", "", false},
+		{"https://instagram.com/reel/abc", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := DetectLanguage(tt.text)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("DetectLanguage(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
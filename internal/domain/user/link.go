@@ -0,0 +1,50 @@
+package user
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// LinkCodeTTL is how long a link code can be redeemed after it is created
+const LinkCodeTTL = 15 * time.Minute
+
+// linkCodeDigits is the length of a link code
+const linkCodeDigits = 6
+
+// LinkCode is a one-time code that links another Telegram account to a user's collection
+type LinkCode struct {
+	Code      string
+	UserID    UserID
+	ExpiresAt time.Time
+}
+
+// NewLinkCode creates a random link code for a user, valid for LinkCodeTTL
+func NewLinkCode(userID UserID, now time.Time) (*LinkCode, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < linkCodeDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate link code: %w", err)
+	}
+
+	return &LinkCode{
+		Code:      fmt.Sprintf("%0*d", linkCodeDigits, n),
+		UserID:    userID,
+		ExpiresAt: now.Add(LinkCodeTTL),
+	}, nil
+}
+
+// IsExpired checks if the code can no longer be redeemed
+func (c *LinkCode) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// NormalizeLinkCode strips the spaces and dashes users may type inside a code
+func NormalizeLinkCode(code string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code))
+}
@@ -0,0 +1,110 @@
+package user
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// Locale is the regional style a user reads dates in, as an IETF tag such as "en-US"
+type Locale string
+
+const (
+	LocaleEnglishUS    Locale = "en-US"
+	LocaleEnglishUK    Locale = "en-GB"
+	LocalePortugueseBR Locale = "pt-BR"
+	LocalePortuguesePT Locale = "pt-PT"
+)
+
+// AllLocales returns the supported locales in display order
+func AllLocales() []Locale {
+	return []Locale{LocaleEnglishUS, LocaleEnglishUK, LocalePortugueseBR, LocalePortuguesePT}
+}
+
+// IsValid checks if the locale is supported
+func (l Locale) IsValid() bool {
+	for _, locale := range AllLocales() {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Language returns the language the locale is written in
+func (l Locale) Language() Language {
+	if strings.HasPrefix(string(l), "pt") {
+		return LanguagePortuguese
+	}
+	return LanguageEnglish
+}
+
+// DefaultLocale returns the locale of users who did not choose one
+func DefaultLocale(lang Language) Locale {
+	if lang == LanguagePortuguese {
+		return LocalePortugueseBR
+	}
+	return LocaleEnglishUK
+}
+
+// ParseLocale parses a locale tag like "en-US", "pt_br" or "EN-gb".
+// Bare languages such as "en" say nothing about the region and are not locales.
+func ParseLocale(code string) (Locale, bool) {
+	lang, region, ok := strings.Cut(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"), "-")
+	if !ok {
+		return "", false
+	}
+	locale := Locale(strings.ToLower(lang) + "-" + strings.ToUpper(region))
+	return locale, locale.IsValid()
+}
+
+// Locale returns the user's locale, the default of their language when they did not choose one
+func (u *User) Locale() Locale {
+	if u.locale == "" {
+		return DefaultLocale(u.language)
+	}
+	return u.locale
+}
+
+// HasLocale reports whether the user chose a locale
+func (u *User) HasLocale() bool {
+	return u.locale != ""
+}
+
+// SetLocale sets the user's locale; empty goes back to the default of their language
+func (u *User) SetLocale(locale Locale) error {
+	if locale != "" && !locale.IsValid() {
+		return shared.ErrInvalidLocale
+	}
+	u.locale = locale
+	return nil
+}
+
+// TimezoneAt returns a time zone for a shared location, from its longitude.
+// It follows the sun rather than borders and has no daylight saving time,
+// so /timezone is the way to set an exact one.
+func TimezoneAt(longitude float64) string {
+	offset := int(math.Round(longitude / 15))
+	switch {
+	case offset == 0:
+		return "Etc/GMT"
+	case offset > 0:
+		// The Etc zones count hours west of Greenwich, so their signs are inverted
+		return "Etc/GMT-" + strconv.Itoa(offset)
+	default:
+		return "Etc/GMT+" + strconv.Itoa(-offset)
+	}
+}
+
+// PlainMode reports whether the user reads messages without emojis or formatting,
+// as screen readers read them best
+func (u *User) PlainMode() bool {
+	return u.plainMode
+}
+
+// SetPlainMode turns plain mode on or off
+func (u *User) SetPlainMode(on bool) {
+	u.plainMode = on
+}
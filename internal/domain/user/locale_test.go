@@ -0,0 +1,41 @@
+package user
+
+import "testing"
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		code  string
+		want  Locale
+		valid bool
+	}{
+		{"en-US", LocaleEnglishUS, true},
+		{"pt_br", LocalePortugueseBR, true},
+		{" EN-gb ", LocaleEnglishUK, true},
+		{"en", "", false},
+		{"fr-FR", "", false},
+	}
+
+	for _, tt := range tests {
+		got, valid := ParseLocale(tt.code)
+		if valid != tt.valid || (valid && got != tt.want) {
+			t.Errorf("ParseLocale(%q) = %q, %v, want %q, %v", tt.code, got, valid, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestTimezoneAt(t *testing.T) {
+	tests := []struct {
+		longitude float64
+		want      string
+	}{
+		{-46.63, "Etc/GMT+3"}, // São Paulo
+		{-0.13, "Etc/GMT"},    // London
+		{139.69, "Etc/GMT-9"}, // Tokyo
+	}
+
+	for _, tt := range tests {
+		if got := TimezoneAt(tt.longitude); got != tt.want {
+			t.Errorf("TimezoneAt(%v) = %q, want %q", tt.longitude, got, tt.want)
+		}
+	}
+}
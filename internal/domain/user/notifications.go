@@ -0,0 +1,77 @@
+package user
+
+import "receipt-bot/internal/domain/shared"
+
+// Notification is a kind of message the bot sends without being asked
+type Notification string
+
+const (
+	NotificationDailySuggestion Notification = "daily_suggestion" // a recipe idea for dinner
+	NotificationExpiryWarning   Notification = "expiry_warning"   // frozen portions to eat soon
+	NotificationTimerPing       Notification = "timer_ping"       // cooking step reminders
+	NotificationWeeklyDigest    Notification = "weekly_digest"    // the recipes saved this week
+	NotificationAchievements    Notification = "achievements"     // badges as they are earned and a monthly recap
+)
+
+// AllNotifications returns every notification kind, in the order they are shown
+func AllNotifications() []Notification {
+	return []Notification{
+		NotificationDailySuggestion,
+		NotificationExpiryWarning,
+		NotificationTimerPing,
+		NotificationWeeklyDigest,
+		NotificationAchievements,
+	}
+}
+
+// IsValid checks if the notification kind is known
+func (n Notification) IsValid() bool {
+	for _, known := range AllNotifications() {
+		if n == known {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultEnabled reports whether a notification is on for users who never changed it.
+// Messages the user did not ask for start off; warnings and reminders start on.
+func (n Notification) DefaultEnabled() bool {
+	switch n {
+	case NotificationExpiryWarning, NotificationTimerPing:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationSettings returns the notification choices the user made.
+// Kinds missing from the map use their default.
+func (u *User) NotificationSettings() map[Notification]bool {
+	return u.notifications
+}
+
+// SetNotificationSettings replaces the user's notification choices
+func (u *User) SetNotificationSettings(settings map[Notification]bool) {
+	u.notifications = settings
+}
+
+// WantsNotification reports whether the user receives a kind of notification
+func (u *User) WantsNotification(n Notification) bool {
+	if enabled, ok := u.notifications[n]; ok {
+		return enabled
+	}
+	return n.DefaultEnabled()
+}
+
+// SetNotification turns a kind of notification on or off
+func (u *User) SetNotification(n Notification, enabled bool) error {
+	if !n.IsValid() {
+		return shared.ErrInvalidNotification
+	}
+	if u.notifications == nil {
+		u.notifications = make(map[Notification]bool)
+	}
+	u.notifications[n] = enabled
+	return nil
+}
@@ -0,0 +1,44 @@
+package user
+
+// NotionField is a recipe field exported as a property of the user's Notion database
+type NotionField string
+
+const (
+	NotionFieldTitle     NotionField = "title"
+	NotionFieldCategory  NotionField = "category"
+	NotionFieldCuisine   NotionField = "cuisine"
+	NotionFieldPrepTime  NotionField = "prep_time"
+	NotionFieldCookTime  NotionField = "cook_time"
+	NotionFieldServings  NotionField = "servings"
+	NotionFieldSourceURL NotionField = "source_url"
+	NotionFieldTags      NotionField = "tags"
+)
+
+// AllNotionFields returns the recipe fields exported as Notion properties, in the order they are shown
+func AllNotionFields() []NotionField {
+	return []NotionField{
+		NotionFieldTitle, NotionFieldCategory, NotionFieldCuisine, NotionFieldPrepTime,
+		NotionFieldCookTime, NotionFieldServings, NotionFieldSourceURL, NotionFieldTags,
+	}
+}
+
+// NotionProperty is a property of the user's Notion database, by name and type (e.g. "select")
+type NotionProperty struct {
+	Name string
+	Type string
+}
+
+// NotionProperties maps recipe fields to the properties of the user's Notion
+// database they are exported to. Fields without a property are not exported.
+type NotionProperties map[NotionField]NotionProperty
+
+// NotionProperties returns how recipe fields map to the properties of the user's
+// Notion database, nil until they were detected
+func (u *User) NotionProperties() NotionProperties {
+	return u.notionProperties
+}
+
+// SetNotionProperties replaces how recipe fields map to the user's Notion database properties
+func (u *User) SetNotionProperties(properties NotionProperties) {
+	u.notionProperties = properties
+}
@@ -0,0 +1,42 @@
+package user
+
+import "time"
+
+// PremiumUntil returns when the user's premium tier ends, nil if they never had it
+func (u *User) PremiumUntil() *time.Time {
+	return u.premiumUntil
+}
+
+// IsPremium reports whether the user has the premium tier at a time
+func (u *User) IsPremium(now time.Time) bool {
+	return u.premiumUntil != nil && now.Before(*u.premiumUntil)
+}
+
+// ExtendPremium adds days of premium, counted from when the current tier ends if
+// it is still running, from now otherwise. Returns when the tier now ends.
+func (u *User) ExtendPremium(days int, now time.Time) time.Time {
+	start := now
+	if u.IsPremium(now) {
+		start = *u.premiumUntil
+	}
+	until := start.AddDate(0, 0, days)
+	u.premiumUntil = &until
+	return until
+}
+
+// SetPremiumUntil replaces when the user's premium tier ends; nil removes it
+func (u *User) SetPremiumUntil(until *time.Time) {
+	u.premiumUntil = until
+}
+
+// Payment is a completed purchase of the premium tier, kept for refunds and audits
+type Payment struct {
+	ChargeID     string // Telegram's charge ID, unique per payment
+	UserID       UserID
+	PlanID       string
+	Days         int
+	Amount       int // in the currency's smallest unit, Stars for XTR
+	Currency     string
+	PaidAt       time.Time
+	PremiumUntil time.Time // when the tier ended after the payment was applied
+}
@@ -0,0 +1,127 @@
+package user
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// minutesPerDay bounds the minutes after midnight of quiet hours
+const minutesPerDay = 24 * 60
+
+// QuietHours is a daily span of the user's local time, such as 22:00 to 07:00,
+// during which scheduled notifications are held back until it ends
+type QuietHours struct {
+	Start int // minutes after midnight
+	End   int // minutes after midnight, before Start when the span crosses midnight
+}
+
+// ParseQuietHours parses a span of local time like "22:00-07:00" or "22-7"
+func ParseQuietHours(s string) (QuietHours, error) {
+	from, to, ok := strings.Cut(strings.ReplaceAll(s, "–", "-"), "-")
+	if !ok {
+		return QuietHours{}, shared.ErrInvalidQuietHours
+	}
+
+	start, err := parseClock(from)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	if start == end {
+		return QuietHours{}, shared.ErrInvalidQuietHours
+	}
+	return QuietHours{Start: start, End: end}, nil
+}
+
+// parseClock parses a time of day like "22:00", "7" or "7h30" into minutes after midnight
+func parseClock(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	hour, minute, _ := strings.Cut(strings.NewReplacer("h", ":", ".", ":").Replace(s), ":")
+
+	h, err := strconv.Atoi(hour)
+	if err != nil || h < 0 || h > 24 {
+		return 0, shared.ErrInvalidQuietHours
+	}
+	m := 0
+	if minute != "" {
+		if m, err = strconv.Atoi(minute); err != nil || m < 0 || m > 59 {
+			return 0, shared.ErrInvalidQuietHours
+		}
+	}
+	return (h*60 + m) % minutesPerDay, nil
+}
+
+// Contains reports whether a time of day falls in the quiet hours
+func (q QuietHours) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if q.Start < q.End {
+		return minute >= q.Start && minute < q.End
+	}
+	return minute >= q.Start || minute < q.End
+}
+
+// String formats the quiet hours like "22:00–07:00"
+func (q QuietHours) String() string {
+	return fmt.Sprintf("%02d:%02d–%02d:%02d", q.Start/60, q.Start%60, q.End/60, q.End%60)
+}
+
+// QuietHours returns the user's quiet hours, nil when they have none
+func (u *User) QuietHours() *QuietHours {
+	return u.quietHours
+}
+
+// SetQuietHours replaces the user's quiet hours; nil turns them off
+func (u *User) SetQuietHours(quiet *QuietHours) {
+	u.quietHours = quiet
+}
+
+// Timezone returns the IANA name of the user's time zone, empty for the server's
+func (u *User) Timezone() string {
+	return u.timezone
+}
+
+// SetTimezone sets the user's time zone from its IANA name, such as "Europe/Lisbon".
+// Empty goes back to the server's time zone.
+func (u *User) SetTimezone(name string) error {
+	var loc *time.Location
+	if name != "" {
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return shared.ErrInvalidTimezone
+		}
+	}
+	u.timezone, u.location = name, loc
+	return nil
+}
+
+// Location returns the user's time zone, the server's when they did not choose one
+func (u *User) Location() *time.Location {
+	if u.location == nil {
+		return time.Local
+	}
+	return u.location
+}
+
+// loadLocation resolves the IANA name of a time zone, nil when empty or unknown
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+// InQuietHours reports whether t falls in the user's quiet hours, in their time zone
+func (u *User) InQuietHours(t time.Time) bool {
+	return u.quietHours != nil && u.quietHours.Contains(t.In(u.Location()))
+}
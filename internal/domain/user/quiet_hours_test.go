@@ -0,0 +1,96 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		input string
+		want  QuietHours
+		err   error
+	}{
+		{"22:00-07:00", QuietHours{Start: 22 * 60, End: 7 * 60}, nil},
+		{"22-7", QuietHours{Start: 22 * 60, End: 7 * 60}, nil},
+		{"13h30 – 15h", QuietHours{Start: 13*60 + 30, End: 15 * 60}, nil},
+		{"23:00-24:00", QuietHours{Start: 23 * 60, End: 0}, nil},
+		{"22:00", QuietHours{}, shared.ErrInvalidQuietHours},
+		{"7-7", QuietHours{}, shared.ErrInvalidQuietHours},
+		{"25:00-07:00", QuietHours{}, shared.ErrInvalidQuietHours},
+		{"night-morning", QuietHours{}, shared.ErrInvalidQuietHours},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseQuietHours(tt.input)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("ParseQuietHours(%q) = %+v, %v, want %+v, %v", tt.input, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestQuietHours_Contains(t *testing.T) {
+	night := QuietHours{Start: 22 * 60, End: 7 * 60}
+	afternoon := QuietHours{Start: 13 * 60, End: 15 * 60}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.March, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		quiet QuietHours
+		t     time.Time
+		want  bool
+	}{
+		{night, at(23, 0), true},
+		{night, at(3, 0), true},
+		{night, at(7, 0), false},
+		{night, at(21, 59), false},
+		{afternoon, at(14, 0), true},
+		{afternoon, at(15, 0), false},
+		{afternoon, at(3, 0), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.quiet.Contains(tt.t); got != tt.want {
+			t.Errorf("%s.Contains(%s) = %v, want %v", tt.quiet, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestUser_Location(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	loaded := ReconstructUserFromData(UserData{ID: UserID(shared.NewID()), Timezone: "Europe/Lisbon"})
+	if got := loaded.Location(); got.String() != lisbon.String() {
+		t.Errorf("Location() of a loaded user = %s, want %s", got, lisbon)
+	}
+	if loaded.Location() != loaded.Location() {
+		t.Error("Location() resolved the time zone again, want it kept")
+	}
+
+	usr := ReconstructUserFromData(UserData{ID: UserID(shared.NewID())})
+	if got := usr.Location(); got != time.Local {
+		t.Errorf("Location() without a time zone = %s, want the server's", got)
+	}
+	if err := usr.SetTimezone("Nowhere/Else"); !errors.Is(err, shared.ErrInvalidTimezone) {
+		t.Fatalf("SetTimezone(unknown) error = %v, want %v", err, shared.ErrInvalidTimezone)
+	}
+	if err := usr.SetTimezone("Europe/Lisbon"); err != nil {
+		t.Fatalf("SetTimezone() unexpected error = %v", err)
+	}
+	if got := usr.Location(); got.String() != lisbon.String() {
+		t.Errorf("Location() after SetTimezone = %s, want %s", got, lisbon)
+	}
+	if err := usr.SetTimezone(""); err != nil {
+		t.Fatalf("SetTimezone(\"\") unexpected error = %v", err)
+	}
+	if got := usr.Location(); got != time.Local {
+		t.Errorf("Location() after clearing the time zone = %s, want the server's", got)
+	}
+}
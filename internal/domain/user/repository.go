@@ -1,6 +1,9 @@
 package user
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repository defines the interface for user persistence (Port)
 type Repository interface {
@@ -10,7 +13,8 @@ type Repository interface {
 	// FindByID retrieves a user by their ID
 	FindByID(ctx context.Context, id UserID) (*User, error)
 
-	// FindByTelegramID retrieves a user by their Telegram ID
+	// FindByTelegramID retrieves a user by their Telegram ID.
+	// An account linked to another user resolves to that user
 	FindByTelegramID(ctx context.Context, telegramID int64) (*User, error)
 
 	// Update updates an existing user
@@ -22,6 +26,91 @@ type Repository interface {
 	// GetPantry retrieves the pantry items for a user
 	GetPantry(ctx context.Context, userID UserID) ([]string, error)
 
+	// ModifyPantry reads and replaces the pantry items for a user in one transaction,
+	// so changes made at the same time are not lost. change may run more than once
+	// and must only depend on the items it is given. Returns the saved items.
+	ModifyPantry(ctx context.Context, userID UserID, change func(items []string) []string) ([]string, error)
+
+	// ModifyLinkedTelegramIDs applies change to the user and saves only the Telegram
+	// accounts linked to them, in one transaction, so other changes made at the same
+	// time are not lost. change may run more than once; when it fails nothing is saved.
+	// Returns the user as saved.
+	ModifyLinkedTelegramIDs(ctx context.Context, userID UserID, change func(u *User) error) (*User, error)
+
 	// UpdateLanguage updates the user's language preference
 	UpdateLanguage(ctx context.Context, userID UserID, language Language) error
+
+	// UpdateSavedFilters replaces the user's saved filters
+	UpdateSavedFilters(ctx context.Context, userID UserID, filters []SavedFilter) error
+
+	// ModifyShortcuts applies change to the user and saves only their text shortcuts,
+	// in one transaction, so changes made at the same time are not lost. change may
+	// run more than once; when it fails nothing is saved. Returns the user as saved.
+	ModifyShortcuts(ctx context.Context, userID UserID, change func(u *User) error) (*User, error)
+
+	// ModifyClarificationChoices applies change to the user and saves only how they
+	// answered clarifying questions, in one transaction, so answers given at the same
+	// time are not lost. change may run more than once; when it fails nothing is saved.
+	// Returns the user as saved.
+	ModifyClarificationChoices(ctx context.Context, userID UserID, change func(u *User) error) (*User, error)
+
+	// UpdateNotificationSettings replaces the user's notification choices
+	UpdateNotificationSettings(ctx context.Context, userID UserID, settings map[Notification]bool) error
+
+	// ModifyStaples reads and replaces the user's pantry staples in one transaction,
+	// so changes made at the same time are not lost. nil staples are the defaults,
+	// both given to change and returned by it. change may run more than once and must
+	// only depend on the staples it is given. Returns the saved staples.
+	ModifyStaples(ctx context.Context, userID UserID, change func(staples []string) []string) ([]string, error)
+
+	// UpdateExportFields replaces the recipe fields the user exports by default; nil goes back to the defaults
+	UpdateExportFields(ctx context.Context, userID UserID, fields []string) error
+
+	// UpdateQuietHours replaces the user's quiet hours; nil turns them off
+	UpdateQuietHours(ctx context.Context, userID UserID, quiet *QuietHours) error
+
+	// UpdateTimezone sets the IANA name of the user's time zone
+	UpdateTimezone(ctx context.Context, userID UserID, timezone string) error
+
+	// UpdateLocale sets the user's locale; empty goes back to the default of their language
+	UpdateLocale(ctx context.Context, userID UserID, locale Locale) error
+
+	// UpdatePlainMode turns the user's plain mode on or off
+	UpdatePlainMode(ctx context.Context, userID UserID, on bool) error
+
+	// UpdateKeepTranscripts sets whether the user's recipes keep their transcripts and captions
+	UpdateKeepTranscripts(ctx context.Context, userID UserID, keep bool) error
+
+	// UpdateClipTokenVersion sets the version of the user's browser extension token
+	UpdateClipTokenVersion(ctx context.Context, userID UserID, version int) error
+
+	// UpdateDiet sets the diet the user's recipes are checked against; empty clears it
+	UpdateDiet(ctx context.Context, userID UserID, diet string) error
+
+	// UpdateAutoExport replaces the user's auto-export; nil turns it off
+	UpdateAutoExport(ctx context.Context, userID UserID, autoExport *AutoExport) error
+
+	// UpdateCloudStorage replaces the user's connected cloud storage; nil disconnects it
+	UpdateCloudStorage(ctx context.Context, userID UserID, storage *CloudStorage) error
+
+	// RecordPayment extends the user's premium tier by the payment's days and saves
+	// the payment, in one transaction. A payment whose charge ID is already recorded
+	// changes nothing: the recorded payment is returned with shared.ErrPaymentRecorded.
+	// Returns the payment as saved, with when the tier now ends.
+	RecordPayment(ctx context.Context, payment Payment) (*Payment, error)
+
+	// UpdatePremium sets when the user's premium tier ends; nil removes it
+	UpdatePremium(ctx context.Context, userID UserID, until *time.Time) error
+
+	// FindAll retrieves every user, for scheduled notifications
+	FindAll(ctx context.Context) ([]*User, error)
+}
+
+// LinkCodeRepository stores the one-time codes used to link accounts (Port)
+type LinkCodeRepository interface {
+	// SaveLinkCode persists a link code
+	SaveLinkCode(ctx context.Context, code *LinkCode) error
+
+	// TakeLinkCode retrieves a link code and deletes it so it can only be used once
+	TakeLinkCode(ctx context.Context, code string) (*LinkCode, error)
 }
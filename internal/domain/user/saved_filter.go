@@ -0,0 +1,95 @@
+package user
+
+import (
+	"strings"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// MaxSavedFilters is how many saved filters a user can keep
+const MaxSavedFilters = 20
+
+// SavedFilter is a named search the user can re-run, e.g.
+// "weeknight" = quick + vegetarian + under 30 min.
+// Values are stored as plain strings so the user domain does not depend on recipes.
+type SavedFilter struct {
+	Name        string
+	Category    string
+	DietaryTags []string
+	Difficulty  string
+	SearchTerm  string
+	MaxMinutes  int // 0 means no time limit
+
+	// Ingredient filter: all of Include, none of Exclude, any of Optional
+	Include  []string
+	Exclude  []string
+	Optional []string
+}
+
+// NormalizeFilterName lowercases a filter name and collapses its whitespace
+func NormalizeFilterName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// IsEmpty checks if the filter has no criteria
+func (f SavedFilter) IsEmpty() bool {
+	return f.Category == "" && len(f.DietaryTags) == 0 && f.Difficulty == "" && f.SearchTerm == "" &&
+		f.MaxMinutes <= 0 && len(f.Include) == 0 && len(f.Exclude) == 0 && len(f.Optional) == 0
+}
+
+// SavedFilters returns the user's saved filters
+func (u *User) SavedFilters() []SavedFilter {
+	return u.savedFilters
+}
+
+// SetSavedFilters replaces the user's saved filters
+func (u *User) SetSavedFilters(filters []SavedFilter) {
+	u.savedFilters = filters
+}
+
+// SavedFilter finds a saved filter by name
+func (u *User) SavedFilter(name string) (SavedFilter, bool) {
+	name = NormalizeFilterName(name)
+	for _, f := range u.savedFilters {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return SavedFilter{}, false
+}
+
+// SaveFilter saves a filter under its name, replacing a filter with the same name
+func (u *User) SaveFilter(filter SavedFilter) error {
+	filter.Name = NormalizeFilterName(filter.Name)
+	if filter.Name == "" {
+		return shared.ErrInvalidFilterName
+	}
+	if filter.IsEmpty() {
+		return shared.ErrEmptyFilter
+	}
+
+	for i, f := range u.savedFilters {
+		if f.Name == filter.Name {
+			u.savedFilters[i] = filter
+			return nil
+		}
+	}
+
+	if len(u.savedFilters) >= MaxSavedFilters {
+		return shared.ErrTooManyFilters
+	}
+	u.savedFilters = append(u.savedFilters, filter)
+	return nil
+}
+
+// DeleteFilter removes a saved filter by name
+func (u *User) DeleteFilter(name string) error {
+	name = NormalizeFilterName(name)
+	for i, f := range u.savedFilters {
+		if f.Name == name {
+			u.savedFilters = append(u.savedFilters[:i:i], u.savedFilters[i+1:]...)
+			return nil
+		}
+	}
+	return shared.ErrFilterNotFound
+}
@@ -0,0 +1,100 @@
+package user
+
+import (
+	"strings"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+// MaxShortcuts is how many text shortcuts a user can keep
+const MaxShortcuts = 30
+
+// Shortcut expands a short text the user types into a longer message before its
+// intent is detected, e.g. "wp" = "what can I make with my pantry". An expansion
+// starting with "/" runs that command instead.
+type Shortcut struct {
+	Trigger   string
+	Expansion string
+}
+
+// NormalizeShortcutTrigger lowercases a trigger and collapses its whitespace
+func NormalizeShortcutTrigger(trigger string) string {
+	return strings.Join(strings.Fields(strings.ToLower(trigger)), " ")
+}
+
+// Shortcuts returns the user's text shortcuts
+func (u *User) Shortcuts() []Shortcut {
+	return u.shortcuts
+}
+
+// SetShortcuts replaces the user's text shortcuts
+func (u *User) SetShortcuts(shortcuts []Shortcut) {
+	u.shortcuts = shortcuts
+}
+
+// SaveShortcut saves a shortcut under its trigger, replacing one with the same trigger
+func (u *User) SaveShortcut(shortcut Shortcut) error {
+	shortcut.Trigger = NormalizeShortcutTrigger(shortcut.Trigger)
+	shortcut.Expansion = strings.TrimSpace(shortcut.Expansion)
+	if shortcut.Trigger == "" || shortcut.Expansion == "" || strings.HasPrefix(shortcut.Trigger, "/") ||
+		NormalizeShortcutTrigger(shortcut.Expansion) == shortcut.Trigger {
+		return shared.ErrInvalidShortcut
+	}
+
+	for i, s := range u.shortcuts {
+		if s.Trigger == shortcut.Trigger {
+			u.shortcuts[i] = shortcut
+			return nil
+		}
+	}
+
+	if len(u.shortcuts) >= MaxShortcuts {
+		return shared.ErrTooManyShortcuts
+	}
+	u.shortcuts = append(u.shortcuts, shortcut)
+	return nil
+}
+
+// DeleteShortcut removes a shortcut by its trigger
+func (u *User) DeleteShortcut(trigger string) error {
+	trigger = NormalizeShortcutTrigger(trigger)
+	for i, s := range u.shortcuts {
+		if s.Trigger == trigger {
+			u.shortcuts = append(u.shortcuts[:i:i], u.shortcuts[i+1:]...)
+			return nil
+		}
+	}
+	return shared.ErrShortcutNotFound
+}
+
+// ExpandShortcut expands a message that is a trigger, or starts with one followed
+// by more words, which are kept after the expansion: with "r" = "recipes with",
+// "r chicken" becomes "recipes with chicken". The longest matching trigger wins.
+// Returns false if no shortcut matches.
+func (u *User) ExpandShortcut(text string) (string, bool) {
+	normalized := NormalizeShortcutTrigger(text)
+
+	var match Shortcut
+	var rest string
+	for _, s := range u.shortcuts {
+		if len(s.Trigger) <= len(match.Trigger) {
+			continue
+		}
+		if normalized == s.Trigger {
+			match, rest = s, ""
+		} else if after, ok := strings.CutPrefix(normalized, s.Trigger+" "); ok {
+			match, rest = s, after
+		}
+	}
+	if match.Trigger == "" {
+		return "", false
+	}
+
+	// Keep the words after the trigger as the user typed them
+	if rest != "" {
+		words := strings.Fields(text)
+		rest = strings.Join(words[len(strings.Fields(match.Trigger)):], " ")
+		return match.Expansion + " " + rest, true
+	}
+	return match.Expansion, true
+}
@@ -0,0 +1,71 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"receipt-bot/internal/domain/shared"
+)
+
+func TestUser_ExpandShortcut(t *testing.T) {
+	u := &User{}
+	for _, s := range []Shortcut{
+		{Trigger: "wp", Expansion: "what can I make with my pantry"},
+		{Trigger: "r", Expansion: "recipes with"},
+		{Trigger: "r q", Expansion: "quick recipes with"},
+	} {
+		if err := u.SaveShortcut(s); err != nil {
+			t.Fatalf("SaveShortcut(%+v) error = %v", s, err)
+		}
+	}
+
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{"wp", "what can I make with my pantry", true},
+		{"  WP ", "what can I make with my pantry", true},
+		{"r Chicken Thighs", "recipes with Chicken Thighs", true},
+		{"r q tofu", "quick recipes with tofu", true},
+		{"wps", "", false},
+		{"show me wp", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := u.ExpandShortcut(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ExpandShortcut(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestUser_SaveShortcut(t *testing.T) {
+	u := &User{}
+
+	invalid := []Shortcut{
+		{Trigger: " ", Expansion: "recipes"},
+		{Trigger: "wp", Expansion: ""},
+		{Trigger: "/wp", Expansion: "recipes"},
+		{Trigger: "Recipes", Expansion: "recipes"},
+	}
+	for _, s := range invalid {
+		if err := u.SaveShortcut(s); !errors.Is(err, shared.ErrInvalidShortcut) {
+			t.Errorf("SaveShortcut(%+v) error = %v, want %v", s, err, shared.ErrInvalidShortcut)
+		}
+	}
+
+	// Saving a trigger again replaces its expansion
+	_ = u.SaveShortcut(Shortcut{Trigger: "wp", Expansion: "what can I make"})
+	_ = u.SaveShortcut(Shortcut{Trigger: "WP", Expansion: "what can I make with my pantry"})
+	if got := u.Shortcuts(); len(got) != 1 || got[0].Expansion != "what can I make with my pantry" {
+		t.Errorf("Shortcuts() = %+v, want the replaced shortcut only", got)
+	}
+
+	if err := u.DeleteShortcut("wp"); err != nil {
+		t.Errorf("DeleteShortcut() error = %v", err)
+	}
+	if err := u.DeleteShortcut("wp"); !errors.Is(err, shared.ErrShortcutNotFound) {
+		t.Errorf("DeleteShortcut() error = %v, want %v", err, shared.ErrShortcutNotFound)
+	}
+}
@@ -0,0 +1,12 @@
+package user
+
+// Staples returns the ingredients the user always has, which recipe matching
+// leaves out. Nil means the user never chose their own and gets the defaults.
+func (u *User) Staples() []string {
+	return u.staples
+}
+
+// SetStaples replaces the user's staples; nil goes back to the defaults
+func (u *User) SetStaples(staples []string) {
+	u.staples = staples
+}
@@ -0,0 +1,13 @@
+package user
+
+// KeepsTranscripts reports whether the recipes the user saves keep the transcript
+// and captions they were extracted from, as they do unless the user opts out
+func (u *User) KeepsTranscripts() bool {
+	return !u.skipTranscripts
+}
+
+// SetKeepTranscripts sets whether the recipes the user saves keep their transcript
+// and captions
+func (u *User) SetKeepTranscripts(keep bool) {
+	u.skipTranscripts = !keep
+}
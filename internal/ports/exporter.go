@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"receipt-bot/internal/domain/recipe"
 )
@@ -18,11 +19,12 @@ type ExportResult struct {
 
 // ObsidianExporter defines the interface for exporting recipes to Obsidian format
 type ObsidianExporter interface {
-	// ExportRecipe exports a single recipe as Obsidian-compatible markdown
-	ExportRecipe(recipe *recipe.Recipe) (*ExportResult, error)
+	// ExportRecipe exports a single recipe as Obsidian-compatible markdown,
+	// with its dates in the user's time zone
+	ExportRecipe(recipe *recipe.Recipe, loc *time.Location) (*ExportResult, error)
 
 	// ExportRecipes exports multiple recipes as a ZIP file
-	ExportRecipes(recipes []*recipe.Recipe) (*ExportResult, error)
+	ExportRecipes(recipes []*recipe.Recipe, loc *time.Location) (*ExportResult, error)
 }
 
 // NotionExporter defines the interface for exporting recipes to Notion
@@ -45,3 +47,27 @@ type NotionExporter interface {
 	// Disconnect removes the Notion connection for a user
 	Disconnect(ctx context.Context, userID string) error
 }
+
+// AppExporter defines the interface for exporting recipes as files another
+// recipe app can import (Crouton, AnyList, Whisk / Samsung Food)
+type AppExporter interface {
+	// ExportRecipe exports a single recipe as an importable file
+	ExportRecipe(recipe *recipe.Recipe) (*ExportResult, error)
+
+	// ExportRecipes exports multiple recipes as a single importable file or ZIP,
+	// named with the date in the user's time zone
+	ExportRecipes(recipes []*recipe.Recipe, loc *time.Location) (*ExportResult, error)
+}
+
+// CloudStorage writes export files into a folder of a user's Dropbox or Google Drive,
+// connected through the provider's OAuth flow
+type CloudStorage interface {
+	// AuthURL returns the OAuth authorization URL, which hands state back to the callback
+	AuthURL(state string) string
+
+	// ExchangeCode exchanges the code the OAuth callback received for a refresh token
+	ExchangeCode(ctx context.Context, code string) (refreshToken string, err error)
+
+	// Upload writes a file into a folder, creating the folder and replacing a file of the same name
+	Upload(ctx context.Context, refreshToken, folder, filename string, data []byte) error
+}
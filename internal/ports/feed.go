@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"receipt-bot/internal/domain/feed"
+)
+
+// FeedReader reads RSS and Atom feeds
+type FeedReader interface {
+	// Read fetches and parses the feed at url
+	Read(ctx context.Context, url string) (*feed.Feed, error)
+}
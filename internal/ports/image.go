@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// ImageGenerator draws images from a description, e.g. covers for recipes saved
+// from sources without a picture of the dish
+type ImageGenerator interface {
+	// GenerateImage draws the description and returns the image as PNG
+	GenerateImage(ctx context.Context, description string) ([]byte, error)
+}
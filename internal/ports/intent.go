@@ -22,6 +22,18 @@ type ConversationTurn struct {
 	Timestamp time.Time
 }
 
+// RoleSummary is the role of a turn summarizing the conversation before the
+// recent turns, which comes first in a history
+const RoleSummary = "summary"
+
+// ConversationSummarizer condenses the turns that no longer fit the recent history,
+// so long refinement sessions keep their context
+type ConversationSummarizer interface {
+	// SummarizeConversation folds the turns into the summary of the conversation so
+	// far, empty at first, and returns the new summary
+	SummarizeConversation(ctx context.Context, summary string, turns []ConversationTurn) (string, error)
+}
+
 // ConversationAction tells the handler what to do next
 type ConversationAction string
 
@@ -40,6 +52,11 @@ const (
 	IntentFilterIngredient IntentType = "FILTER_INGREDIENT"
 	IntentMatchIngredients IntentType = "MATCH_INGREDIENTS"
 	IntentShowCategories   IntentType = "SHOW_CATEGORIES"
+	IntentFilterAuthor     IntentType = "FILTER_AUTHOR"   // "show me everything from @thatpastaguy"
+	IntentShowAuthors      IntentType = "SHOW_AUTHORS"    // "who do I save the most"
+	IntentFilterPlatform   IntentType = "FILTER_PLATFORM" // "show my TikTok recipes"
+	IntentShowPlatforms    IntentType = "SHOW_PLATFORMS"  // "where do I save recipes from"
+	IntentFilterFlavor     IntentType = "FILTER_FLAVOR"   // "I want something spicy"
 	IntentManagePantry     IntentType = "MANAGE_PANTRY"
 	IntentHelp             IntentType = "HELP"
 	IntentGreeting         IntentType = "GREETING"
@@ -53,6 +70,32 @@ const (
 
 	// Complex search with multiple ingredients
 	IntentComplexSearch IntentType = "COMPLEX_SEARCH" // "salmon and sriracha", "pasta without dairy"
+
+	// Meal planning
+	IntentShoppingList IntentType = "SHOPPING_LIST" // "generate shopping list for this week"
+	IntentPlanMenu     IntentType = "PLAN_MENU"     // "plan Christmas dinner for 8"
+
+	// Recipe adaptation
+	IntentConvertRecipe IntentType = "CONVERT_RECIPE" // "convert #4 to Instant Pot"
+	IntentRemixRecipe   IntentType = "REMIX_RECIPE"   // "remix recipe #3 to be vegan", "make it gluten-free"
+
+	// Freezer inventory
+	IntentFreezer IntentType = "FREEZER" // "I froze 3 portions of recipe #7", "what's in my freezer"
+
+	// Household dish requests
+	IntentRequestDish IntentType = "REQUEST_DISH" // "someone please make lasagna this week"
+
+	// Saved searches
+	IntentSaveFilter IntentType = "SAVE_FILTER" // "save this search as weeknight"
+	IntentRunFilter  IntentType = "RUN_FILTER"  // "show my weeknight recipes"
+
+	// Conversation
+	IntentStartOver IntentType = "START_OVER" // "start over", "forget that", "never mind"
+
+	// Follow-ups on the last viewed recipe ("it", "that", "essa")
+	IntentExportRecipe      IntentType = "EXPORT_RECIPE"        // "export it to Notion"
+	IntentAddToShoppingList IntentType = "ADD_TO_SHOPPING_LIST" // "add its ingredients to my shopping list"
+	IntentTranslateRecipe   IntentType = "TRANSLATE_RECIPE"     // "translate it", "traduz essa"
 )
 
 // PantryAction represents the type of pantry management action
@@ -65,6 +108,16 @@ const (
 	PantryActionClear  PantryAction = "CLEAR"
 )
 
+// FreezerAction represents the type of freezer inventory action
+type FreezerAction string
+
+const (
+	FreezerActionShow     FreezerAction = "SHOW"
+	FreezerActionAdd      FreezerAction = "ADD"
+	FreezerActionRemove   FreezerAction = "REMOVE"
+	FreezerActionEatFirst FreezerAction = "EAT_FIRST"
+)
+
 // Intent represents the detected intent from user input
 type Intent struct {
 	// Type is the detected intent type
@@ -76,12 +129,37 @@ type Intent struct {
 	// DietaryTags is set for COMPOUND_QUERY intent (e.g., "quick", "vegan")
 	DietaryTags []recipe.DietaryTag
 
+	// Difficulty is set for COMPOUND_QUERY and COMPLEX_SEARCH intents (e.g., "easy recipes")
+	Difficulty *recipe.Difficulty
+
+	// MaxMinutes is set for COMPOUND_QUERY and COMPLEX_SEARCH intents with a time limit (e.g., "under 30 min")
+	MaxMinutes int
+
+	// SavedPeriod is set for LIST_RECIPES intent when the user asks for recipes saved in a
+	// period (e.g., "last month", "december"; see recipe.ParsePeriod)
+	SavedPeriod string
+
 	// Ingredients is set for MATCH_INGREDIENTS intent (ingredients user has)
 	Ingredients []string
 
+	// PerfectOnly is set when refining ingredient matches to recipes with nothing missing
+	PerfectOnly bool
+
+	// MaxMissing is set when refining ingredient matches to allow a few missing items (e.g., "allow 2 missing")
+	MaxMissing int
+
 	// SearchTerm is set for FILTER_INGREDIENT intent (specific ingredient to search for)
 	SearchTerm string
 
+	// Author is set for FILTER_AUTHOR intent (creator name or handle, e.g. "@thatpastaguy")
+	Author string
+
+	// Platform is set for FILTER_PLATFORM intent (tiktok, youtube, instagram, web or ai-generated)
+	Platform string
+
+	// Flavor is set for FILTER_FLAVOR intent (spicy, sweet, umami-rich or tangy)
+	Flavor string
+
 	// IngredientFilter is set for COMPLEX_SEARCH intent (multiple ingredients with AND/OR/NOT)
 	IngredientFilter *recipe.IngredientFilter
 
@@ -91,15 +169,53 @@ type Intent struct {
 	// PantryItems are items to add/remove for MANAGE_PANTRY intent
 	PantryItems []string
 
-	// RecipeNumber is set for SHOW_DETAILS intent (1-based index)
+	// Occasion is set for PLAN_MENU intent (e.g., "Christmas dinner")
+	Occasion string
+
+	// Guests is set for PLAN_MENU intent when the user says how many people to serve
+	Guests int
+
+	// FreezerAction is set for FREEZER intent
+	FreezerAction FreezerAction
+
+	// Portions is set for FREEZER intent when portions are frozen or eaten
+	Portions int
+
+	// Dish is set for REQUEST_DISH intent (the dish asked for, e.g. "lasagna")
+	Dish string
+
+	// Appliance is set for CONVERT_RECIPE intent (e.g., "instant pot")
+	Appliance string
+
+	// RemixGoal is set for REMIX_RECIPE intent, what to adapt the recipe to (e.g., "vegan")
+	RemixGoal string
+
+	// FilterName is set for SAVE_FILTER and RUN_FILTER intents (e.g., "weeknight")
+	FilterName string
+
+	// ExportFormat is set for EXPORT_RECIPE intent (e.g., "notion", "obsidian")
+	ExportFormat string
+
+	// Language is set for TRANSLATE_RECIPE intent, the language to translate into (e.g., "Portuguese")
+	Language string
+
+	// RecipeNumber is set for SHOW_DETAILS, CONVERT_RECIPE, REMIX_RECIPE, FREEZER and the follow-ups on a recipe
+	// (1-based index). Without it, CONVERT_RECIPE, REMIX_RECIPE, FREEZER and the follow-ups mean the last viewed recipe.
 	RecipeNumber int
 
+	// RecipeName is set for SHOW_DETAILS when the user names the recipe (e.g., "carbonara").
+	// RecipeNumber then counts among the recipes with that name ("the second salmon one").
+	RecipeName string
+
 	// Confidence is the confidence score (0.0 to 1.0)
 	Confidence float64
 
 	// RawResponse is the original text for debugging
 	RawResponse string
 
+	// Variant is the prompt experiment variant that detected the intent ("control" for the built-in prompt)
+	Variant string
+
 	// === Conversation control fields ===
 
 	// NextAction tells the handler what to do (EXECUTE, CLARIFY, or REFINE)
@@ -3,6 +3,11 @@ package ports
 import (
 	"context"
 	"time"
+
+	"receipt-bot/internal/domain/menu"
+	"receipt-bot/internal/domain/moderation"
+	"receipt-bot/internal/domain/recipe"
+	"receipt-bot/internal/domain/shopping"
 )
 
 // LLMPort defines the interface for LLM-based recipe extraction
@@ -39,6 +44,7 @@ type RecipeExtraction struct {
 	Category     string
 	Cuisine      string
 	DietaryTags  []string
+	Flavors      []string // dominant tastes, e.g. "spicy" or "tangy"
 	Tags         []string
 
 	// Multilingual support
@@ -46,6 +52,36 @@ type RecipeExtraction struct {
 	TranslatedTitle        *string           // English translation (nil if source is English)
 	TranslatedIngredients  []IngredientData  // English translations (nil if source is English)
 	TranslatedInstructions []InstructionData // English translations (nil if source is English)
+
+	// Where the fields were found and how confident the LLM is about them
+	Provenance []ProvenanceData
+
+	// Variant is the prompt experiment variant that extracted the recipe ("control" for the built-in prompt)
+	Variant string
+}
+
+// LLMResponseError is returned when the LLM answer cannot be used, keeping the
+// raw answer for error reports
+type LLMResponseError struct {
+	Response string
+	Err      error
+}
+
+// Error implements the error interface
+func (e *LLMResponseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *LLMResponseError) Unwrap() error {
+	return e.Err
+}
+
+// ProvenanceData represents where the LLM found an extracted field
+type ProvenanceData struct {
+	Field      string  // title, ingredients, quantities, instructions, times or servings
+	Source     string  // captions, transcript, on_screen, webpage or inferred
+	Confidence float64 // 0 (a guess) to 1 (stated exactly)
 }
 
 // IngredientData represents ingredient information from LLM
@@ -61,4 +97,126 @@ type InstructionData struct {
 	StepNumber int
 	Text       string
 	Duration   *time.Duration
+	Timestamp  *time.Duration // where the step starts in the source video, nil if unknown
+}
+
+// AisleClassifier classifies ingredients into grocery store aisles
+type AisleClassifier interface {
+	// ClassifyAisles returns the aisle of each ingredient name.
+	// Names missing from the result are treated as shopping.AisleOther.
+	ClassifyAisles(ctx context.Context, ingredients []string) (map[string]shopping.Aisle, error)
+}
+
+// InstructionSimplifier rewrites recipe instructions for kids and beginner cooks
+type InstructionSimplifier interface {
+	// SimplifyInstructions rewrites the steps into shorter, simpler ones in the target language
+	SimplifyInstructions(ctx context.Context, instructions []InstructionData, targetLang string) ([]InstructionData, error)
+}
+
+// RecipeConverter adapts recipes to cook in another appliance
+type RecipeConverter interface {
+	// ConvertRecipe rewrites the instructions and times of a recipe for the appliance, in the target language
+	ConvertRecipe(ctx context.Context, input *RecipeConversionInput, appliance recipe.Appliance, targetLang string) (*RecipeConversionOutput, error)
+}
+
+// RecipeConversionInput contains the recipe data to convert
+type RecipeConversionInput struct {
+	Title        string
+	Ingredients  []IngredientData
+	Instructions []InstructionData
+	PrepTime     *time.Duration
+	CookTime     *time.Duration
+	Servings     *int
+}
+
+// RecipeConversionOutput contains the instructions and times adapted to the appliance
+type RecipeConversionOutput struct {
+	Instructions []InstructionData
+	PrepTime     *time.Duration
+	CookTime     *time.Duration
+	Notes        string
+}
+
+// RecipeRemixer adapts recipes to a goal such as a diet, e.g. "vegan" or "gluten-free"
+type RecipeRemixer interface {
+	// RemixRecipe rewrites the recipe for the goal, written in the target language,
+	// and lists the ingredients it substituted
+	RemixRecipe(ctx context.Context, original *RecipeExtraction, goal string, targetLang string) (*RecipeRemix, error)
+}
+
+// RecipeRemix is a recipe adapted to a goal and the substitutions that adapted it
+type RecipeRemix struct {
+	Recipe        *RecipeExtraction
+	Substitutions []SubstitutionData
+}
+
+// SubstitutionData represents an ingredient swapped for another in a remix
+type SubstitutionData struct {
+	Original    string
+	Replacement string
+	Reason      string
+}
+
+// DishRecreator proposes recipes for dishes seen in photos, such as a plate at a restaurant.
+// Unlike recipe extraction, the recipe is invented by the LLM and not read from the image.
+type DishRecreator interface {
+	// RecreateDish proposes a recipe for the dish in the photo, written in the target language.
+	// The hint is what the user wrote with the photo and may be empty.
+	RecreateDish(ctx context.Context, image []byte, hint string, targetLang string) (*RecipeExtraction, error)
+}
+
+// PantryRecognizer lists the food items visible in photos of a fridge or pantry shelf
+type PantryRecognizer interface {
+	// RecognizeItems returns the names of the recognizable food items, written in the target language
+	RecognizeItems(ctx context.Context, image []byte, targetLang string) ([]string, error)
+}
+
+// FrameReader reads the text shown on screen in video frames, such as
+// ingredient quantities that are shown but never said out loud
+type FrameReader interface {
+	// ReadOnScreenText returns the recipe-related text visible in the frames, in video order
+	ReadOnScreenText(ctx context.Context, frames [][]byte) (string, error)
+}
+
+// MultiRecipeExtractor extracts every recipe from content that may hold several,
+// such as a compilation video
+type MultiRecipeExtractor interface {
+	// ExtractRecipes parses text into one structured recipe per dish it describes, in order
+	ExtractRecipes(ctx context.Context, text string) ([]*RecipeExtraction, error)
+}
+
+// RecipeConsolidator merges the partial recipes extracted from the parts of a long
+// transcript, for videos too long to extract from in a single prompt
+type RecipeConsolidator interface {
+	// ConsolidateRecipe combines the candidates, in transcript order, into one recipe
+	// without repeated ingredients or steps
+	ConsolidateRecipe(ctx context.Context, candidates []*RecipeExtraction) (*RecipeExtraction, error)
+}
+
+// MenuSuggester suggests dishes for the courses of a menu the user's collection can't fill
+type MenuSuggester interface {
+	// SuggestDishes suggests dishes for each course, written in the target language
+	SuggestDishes(ctx context.Context, occasion string, courses []menu.Course, guests int, targetLang string) ([]DishSuggestion, error)
+}
+
+// DishSuggestion is a dish suggested by the LLM for a course of a menu
+type DishSuggestion struct {
+	Course     menu.Course
+	Title      string
+	PrepTime   *time.Duration
+	CookTime   *time.Duration
+	Servings   *int
+	Appliances []menu.Appliance
+}
+
+// ContentModerator checks that recipes are safe to show to people other than their owner
+type ContentModerator interface {
+	// ModerateRecipe judges the text of a recipe: allowed, held for an admin review, or blocked
+	ModerateRecipe(ctx context.Context, text string) (*ModerationResult, error)
+}
+
+// ModerationResult is the LLM's judgement of a recipe
+type ModerationResult struct {
+	Verdict moderation.Verdict
+	Reason  string
 }
@@ -0,0 +1,49 @@
+package ports
+
+import (
+	"context"
+	"sync"
+)
+
+// LLMUsage is what the LLM calls of an operation used
+type LLMUsage struct {
+	Model        string // model of the latest call
+	Calls        int
+	InputTokens  int
+	OutputTokens int
+}
+
+// usageMeterContextKey carries the meter of an operation in its context
+type usageMeterContextKey struct{}
+
+// usageMeter adds up the usage of calls that may run concurrently
+type usageMeter struct {
+	mu    sync.Mutex
+	usage LLMUsage
+}
+
+// MeterLLMUsage starts adding up the usage LLM adapters record with ctx, returning
+// the context to pass them and a function reading the total so far
+func MeterLLMUsage(ctx context.Context) (context.Context, func() LLMUsage) {
+	meter := &usageMeter{}
+	read := func() LLMUsage {
+		meter.mu.Lock()
+		defer meter.mu.Unlock()
+		return meter.usage
+	}
+	return context.WithValue(ctx, usageMeterContextKey{}, meter), read
+}
+
+// RecordLLMUsage adds the usage of one LLM call to the meter of ctx, if it has one
+func RecordLLMUsage(ctx context.Context, model string, inputTokens, outputTokens int) {
+	meter, ok := ctx.Value(usageMeterContextKey{}).(*usageMeter)
+	if !ok {
+		return
+	}
+	meter.mu.Lock()
+	defer meter.mu.Unlock()
+	meter.usage.Model = model
+	meter.usage.Calls++
+	meter.usage.InputTokens += inputTokens
+	meter.usage.OutputTokens += outputTokens
+}
@@ -18,4 +18,17 @@ type MessengerPort interface {
 
 	// SendError sends an error message to a chat
 	SendError(ctx context.Context, chatID int64, errorMsg string) error
+
+	// KeepChatAction shows a chat action, such as "typing…", until stop is called
+	KeepChatAction(ctx context.Context, chatID int64, action ChatAction) (stop func())
 }
+
+// ChatAction is what the chat shows the bot doing while it works on a reply
+type ChatAction string
+
+const (
+	ChatActionTyping         ChatAction = "typing"
+	ChatActionUploadDocument ChatAction = "upload_document"
+	ChatActionRecordVoice    ChatAction = "record_voice"
+	ChatActionUploadPhoto    ChatAction = "upload_photo"
+)
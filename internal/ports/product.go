@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+
+	"receipt-bot/internal/domain/nutrition"
+)
+
+// Product is a packaged grocery product identified by its barcode
+type Product struct {
+	Barcode   string
+	Name      string
+	Brand     string
+	Quantity  string           // package size as printed, e.g. "500 g"
+	Nutrition *nutrition.Facts // per 100 g, nil if unknown
+}
+
+// BarcodeDecoder reads product barcodes from photos
+type BarcodeDecoder interface {
+	// DecodeBarcode returns the digits of the first barcode found in the image,
+	// or shared.ErrNoBarcode if there is none
+	DecodeBarcode(image []byte) (string, error)
+}
+
+// ProductLookup finds packaged products by barcode
+type ProductLookup interface {
+	// LookupBarcode returns the product with the barcode, or shared.ErrProductNotFound
+	LookupBarcode(ctx context.Context, barcode string) (*Product, error)
+}
@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"receipt-bot/internal/domain/recipe"
+	"time"
 )
 
 // ScraperPort defines the interface for content scraping
@@ -23,4 +24,12 @@ type ScrapeResult struct {
 	Transcript  string
 	OriginalURL string
 	Metadata    map[string]string
+	KeyFrames   [][]byte        // JPEG frames of the video, for text shown on screen
+	Timeline    []TimelineEntry // chapters, or timed transcript lines, for linking steps to the video
+}
+
+// TimelineEntry marks where something starts in the video
+type TimelineEntry struct {
+	Start time.Duration
+	Text  string // chapter title or transcript line
 }
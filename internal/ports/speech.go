@@ -0,0 +1,14 @@
+package ports
+
+import "context"
+
+// SpeechSynthesizer turns text into spoken audio
+type SpeechSynthesizer interface {
+	// Synthesize speaks the text in a language like "en" or "pt-BR" and returns
+	// OGG/Opus audio, the format of Telegram voice messages. Texts longer than
+	// MaxSpeechText bytes are refused.
+	Synthesize(ctx context.Context, text string, language string) ([]byte, error)
+}
+
+// MaxSpeechText is the longest text, in bytes, a SpeechSynthesizer speaks in one call
+const MaxSpeechText = 4500
@@ -1,19 +1,23 @@
 package ports
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
-// StoragePort defines the interface for file storage operations
-// Note: Currently not used for video storage, but kept for future extensibility
-type StoragePort interface {
-	// Upload stores a file and returns its URL
-	Upload(ctx context.Context, filePath string, destination string) (string, error)
+// BlobStorage stores large content outside Firestore, in a GCS or S3 bucket,
+// under slash-separated keys such as "sources/<recipe id>/transcript.txt"
+type BlobStorage interface {
+	// Put stores data under a key, replacing what was there
+	Put(ctx context.Context, key, contentType string, data []byte) error
 
-	// Download retrieves a file
-	Download(ctx context.Context, url string, destination string) error
+	// Get returns the data stored under a key, or shared.ErrBlobNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
 
-	// Delete removes a file
-	Delete(ctx context.Context, url string) error
+	// Delete removes the data stored under a key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
 
-	// GetURL returns a public URL for a stored file
-	GetURL(ctx context.Context, path string) (string, error)
+	// SignedURL returns a URL the web UI can fetch a key from without credentials,
+	// valid for the given time
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
 }
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"receipt-bot/internal/domain/telemetry"
+)
+
+// TelemetrySender delivers batches of anonymous quality metrics
+type TelemetrySender interface {
+	// Send delivers one batch of metrics
+	Send(ctx context.Context, report telemetry.Report) error
+}
@@ -58,6 +58,11 @@ class ScraperServicer:
                 transcript=result.transcript or "",
                 original_url=result.original_url,
                 metadata=result.metadata,
+                key_frames=result.key_frames,
+                timeline=[
+                    scraper_pb2.TimelineEntry(start_seconds=entry.start_seconds, text=entry.text)
+                    for entry in result.timeline
+                ],
             )
 
             if result.error:
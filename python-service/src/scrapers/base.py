@@ -1,8 +1,15 @@
 """Base scraper interface and data models."""
 
 from abc import ABC, abstractmethod
-from dataclasses import dataclass
-from typing import Optional, Dict
+from dataclasses import dataclass, field
+from typing import Optional, Dict, List
+
+
+@dataclass
+class TimelineEntry:
+    """Something that starts at a point of the video: a chapter or a transcript line."""
+    start_seconds: int
+    text: str
 
 
 @dataclass
@@ -14,6 +21,8 @@ class ScrapeResult:
     original_url: str
     metadata: Dict[str, str]
     error: Optional[str] = None
+    key_frames: List[bytes] = field(default_factory=list)  # JPEG frames for on-screen text
+    timeline: List[TimelineEntry] = field(default_factory=list)  # Chapters, or timed transcript lines
 
 
 class BaseScraper(ABC):
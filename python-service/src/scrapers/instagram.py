@@ -1,10 +1,12 @@
 """Instagram scraper implementation."""
 
 import logging
+import time
 import instaloader
 from pathlib import Path
 from .base import BaseScraper, ScrapeResult
 from ..video.audio_extractor import AudioExtractor
+from ..video.frame_extractor import FrameExtractor
 from ..video.transcriber import create_transcriber
 from ..utils.cleanup import cleanup_files
 
@@ -34,6 +36,7 @@ class InstagramScraper(BaseScraper):
             compress_json=False,
         )
         self.audio_extractor = AudioExtractor()
+        self.frame_extractor = FrameExtractor()
         self.transcriber = create_transcriber()
 
     def can_handle(self, url: str) -> bool:
@@ -73,9 +76,13 @@ class InstagramScraper(BaseScraper):
                 'title': captions[:100] if captions else "Instagram Post",
                 'author': post.owner_username,
                 'likes': str(post.likes),
+                'handle': post.owner_username,
+                'canonical_url': f"https://www.instagram.com/p/{post.shortcode}/",
+                'published': post.date_utc.strftime('%Y-%m-%d') if post.date_utc else '',
             }
 
             transcript = ""
+            key_frames = []
 
             # Only process if it's a video
             if post.is_video:
@@ -98,7 +105,13 @@ class InstagramScraper(BaseScraper):
 
                             # Transcribe audio
                             logger.info("Transcribing Instagram audio")
+                            started = time.monotonic()
                             transcript = self.transcriber.transcribe(audio_path)
+                            metadata['transcription_ms'] = str(int((time.monotonic() - started) * 1000))
+
+                        # Quantities are often shown on screen but never said out loud
+                        logger.info("Extracting key frames from Instagram video")
+                        key_frames = self.frame_extractor.extract_key_frames(video_path)
 
                 except Exception as e:
                     logger.error(f"Failed to process Instagram video: {e}")
@@ -110,6 +123,7 @@ class InstagramScraper(BaseScraper):
                 transcript=transcript,
                 original_url=url,
                 metadata=metadata,
+                key_frames=key_frames,
             )
 
             logger.info(f"Successfully scraped Instagram post by {metadata.get('author')}")
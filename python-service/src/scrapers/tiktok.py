@@ -1,9 +1,11 @@
 """TikTok scraper implementation."""
 
 import logging
+import time
 from .base import BaseScraper, ScrapeResult
 from ..video.downloader import VideoDownloader
 from ..video.audio_extractor import AudioExtractor
+from ..video.frame_extractor import FrameExtractor
 from ..video.transcriber import create_transcriber
 from ..utils.cleanup import cleanup_files
 
@@ -22,6 +24,7 @@ class TikTokScraper(BaseScraper):
         """
         self.downloader = VideoDownloader(output_dir)
         self.audio_extractor = AudioExtractor()
+        self.frame_extractor = FrameExtractor()
         self.transcriber = create_transcriber()
 
     def can_handle(self, url: str) -> bool:
@@ -55,6 +58,9 @@ class TikTokScraper(BaseScraper):
                 'title': download_result.get('title', ''),
                 'author': download_result.get('author', ''),
                 'duration': str(download_result.get('duration', 0)),
+                'handle': download_result.get('handle', ''),
+                'canonical_url': download_result.get('canonical_url', ''),
+                'published': download_result.get('published', ''),
             }
 
             transcript = ""
@@ -66,18 +72,30 @@ class TikTokScraper(BaseScraper):
 
                     # Transcribe audio
                     logger.info("Transcribing TikTok audio")
+                    started = time.monotonic()
                     transcript = self.transcriber.transcribe(audio_path)
+                    metadata['transcription_ms'] = str(int((time.monotonic() - started) * 1000))
 
                 except Exception as e:
                     logger.error(f"Transcription failed: {e}")
                     # Continue without transcript
 
+            # Quantities are often shown on screen but never said out loud
+            key_frames = []
+            try:
+                logger.info("Extracting key frames from TikTok video")
+                key_frames = self.frame_extractor.extract_key_frames(video_path)
+            except Exception as e:
+                logger.error(f"Key frame extraction failed: {e}")
+                # Continue without key frames
+
             result = ScrapeResult(
                 captions=captions,
                 description=captions,
                 transcript=transcript,
                 original_url=url,
                 metadata=metadata,
+                key_frames=key_frames,
             )
 
             logger.info(f"Successfully scraped TikTok video: {metadata.get('title')}")
@@ -46,7 +46,10 @@ class WebScraper(BaseScraper):
 
             # Try to extract structured recipe data (schema.org)
             recipe_data = self._extract_recipe_schema(soup)
+            canonical_url = self._extract_canonical_url(soup)
             if recipe_data:
+                if canonical_url:
+                    recipe_data['canonical_url'] = canonical_url
                 logger.info("Found structured recipe data")
                 return ScrapeResult(
                     captions=recipe_data.get('description', ''),
@@ -63,6 +66,8 @@ class WebScraper(BaseScraper):
             metadata = {
                 'title': soup.title.string if soup.title else '',
             }
+            if canonical_url:
+                metadata['canonical_url'] = canonical_url
 
             result = ScrapeResult(
                 captions=text_content,
@@ -141,6 +146,7 @@ class WebScraper(BaseScraper):
                         'prep_time': data.get('prepTime', ''),
                         'cook_time': data.get('cookTime', ''),
                         'servings': str(data.get('recipeYield', '')),
+                        'published': str(data.get('datePublished', ''))[:10],
                     }
 
             except (json.JSONDecodeError, AttributeError, KeyError) as e:
@@ -149,6 +155,26 @@ class WebScraper(BaseScraper):
 
         return None
 
+    def _extract_canonical_url(self, soup: BeautifulSoup) -> str:
+        """
+        Extract the page's canonical link, which tracking and short links resolve to.
+
+        Args:
+            soup: BeautifulSoup object
+
+        Returns:
+            The canonical URL, or an empty string if the page declares none
+        """
+        link = soup.find('link', rel='canonical')
+        if link and link.get('href'):
+            return link['href'].strip()
+
+        og_url = soup.find('meta', property='og:url')
+        if og_url and og_url.get('content'):
+            return og_url['content'].strip()
+
+        return ''
+
     def _extract_text_content(self, soup: BeautifulSoup) -> str:
         """
         Extract readable text content from the page.
@@ -1,8 +1,9 @@
 """YouTube scraper implementation."""
 
 import logging
+import time
 from typing import Optional
-from .base import BaseScraper, ScrapeResult
+from .base import BaseScraper, ScrapeResult, TimelineEntry
 from ..video.downloader import VideoDownloader
 from ..video.audio_extractor import AudioExtractor
 from ..video.transcriber import create_transcriber
@@ -56,8 +57,19 @@ class YouTubeScraper(BaseScraper):
                 'title': download_result.get('title', ''),
                 'author': download_result.get('author', ''),
                 'duration': str(download_result.get('duration', 0)),
+                'handle': download_result.get('handle', ''),
+                'canonical_url': download_result.get('canonical_url', ''),
+                'published': download_result.get('published', ''),
             }
 
+            # Chapters (from the video or timestamps in its description) let
+            # recipe steps link to where they start in the video
+            timeline = [
+                TimelineEntry(start_seconds=int(chapter.get('start_time') or 0), text=chapter['title'])
+                for chapter in download_result.get('chapters', [])
+                if chapter.get('title')
+            ]
+
             transcript = ""
             if transcribe:
                 try:
@@ -65,9 +77,15 @@ class YouTubeScraper(BaseScraper):
                     logger.info("Extracting audio from video")
                     audio_path = self.audio_extractor.extract_audio(video_path)
 
-                    # Transcribe audio
+                    # Transcribe audio, timing its lines when there are no chapters
                     logger.info("Transcribing audio")
-                    transcript = self.transcriber.transcribe(audio_path)
+                    started = time.monotonic()
+                    if timeline:
+                        transcript = self.transcriber.transcribe(audio_path)
+                    else:
+                        transcript, lines = self.transcriber.transcribe_timed(audio_path)
+                        timeline = [TimelineEntry(start_seconds=int(start), text=text) for start, text in lines]
+                    metadata['transcription_ms'] = str(int((time.monotonic() - started) * 1000))
 
                 except Exception as e:
                     logger.error(f"Transcription failed: {e}")
@@ -79,6 +97,7 @@ class YouTubeScraper(BaseScraper):
                 transcript=transcript,
                 original_url=url,
                 metadata=metadata,
+                timeline=timeline,
             )
 
             logger.info(f"Successfully scraped YouTube video: {metadata.get('title')}")
@@ -30,7 +30,10 @@ class VideoDownloader:
             platform: Platform name (for optimization)
 
         Returns:
-            Dictionary with 'video_path', 'title', 'description', 'author'
+            Dictionary with 'video_path', 'title', 'description', 'author', 'duration',
+            'chapters' (list of dicts with 'start_time' and 'title', empty if none),
+            'handle' (the uploader's account), 'canonical_url' and 'published'
+            (YYYY-MM-DD, empty if unknown)
 
         Raises:
             Exception: If download fails
@@ -59,6 +62,10 @@ class VideoDownloader:
                     'description': info.get('description', ''),
                     'author': info.get('uploader', '') or info.get('channel', ''),
                     'duration': info.get('duration', 0),
+                    'chapters': info.get('chapters') or [],
+                    'handle': info.get('uploader_id', '') or '',
+                    'canonical_url': info.get('webpage_url', '') or '',
+                    'published': self._published_date(info.get('upload_date')),
                 }
 
                 logger.info(f"Downloaded video: {result['title']}")
@@ -68,6 +75,21 @@ class VideoDownloader:
             logger.error(f"Failed to download video from {url}: {e}")
             raise
 
+    @staticmethod
+    def _published_date(upload_date: str) -> str:
+        """
+        Convert yt-dlp's YYYYMMDD upload date to YYYY-MM-DD.
+
+        Args:
+            upload_date: The upload date yt-dlp reports, possibly None
+
+        Returns:
+            The date as YYYY-MM-DD, or an empty string if unknown
+        """
+        if not upload_date or len(upload_date) != 8 or not upload_date.isdigit():
+            return ''
+        return f"{upload_date[:4]}-{upload_date[4:6]}-{upload_date[6:]}"
+
     def extract_metadata(self, url: str) -> Dict[str, str]:
         """
         Extract video metadata without downloading.
@@ -2,7 +2,7 @@
 
 import logging
 import os
-from typing import Optional
+from typing import List, Optional, Tuple
 
 logger = logging.getLogger(__name__)
 
@@ -79,6 +79,34 @@ class Transcriber:
             logger.error(f"Transcription failed: {e}")
             raise
 
+    def transcribe_timed(self, audio_path: str, language: str = None) -> Tuple[str, List[Tuple[float, str]]]:
+        """
+        Transcribe an audio file, with the time each line of the transcript starts at.
+
+        Providers without timestamps return the transcript with no lines.
+
+        Args:
+            audio_path: Path to the audio file
+            language: Language code (optional)
+
+        Returns:
+            The transcribed text and its lines as (start in seconds, text) pairs
+
+        Raises:
+            Exception: If transcription fails
+        """
+        if self.provider == "elevenlabs":
+            language_code = self._convert_to_elevenlabs_language(language)
+            result = self.transcriber.transcribe_with_timestamps(audio_path, language_code=language_code)
+            return result['text'], group_words(result['words'])
+
+        if self.provider == "whisper":
+            result = self.transcriber.transcribe_with_timestamps(audio_path, language)
+            lines = [(s['start'], s['text'].strip()) for s in result['segments'] if s['text'].strip()]
+            return result['text'], lines
+
+        return self.transcribe(audio_path, language), []
+
     def _convert_to_elevenlabs_language(self, language: str) -> Optional[str]:
         """Convert language codes to ElevenLabs format."""
         if not language or language.strip() == "":
@@ -99,6 +127,37 @@ class Transcriber:
         return language_map.get(language, language)
 
 
+def group_words(words: List[dict], max_seconds: float = 20.0) -> List[Tuple[float, str]]:
+    """
+    Group timed words into transcript lines that end with a sentence or after max_seconds.
+
+    Args:
+        words: Words with 'text' and 'start' (seconds, may be None)
+        max_seconds: Longest time a line may span
+
+    Returns:
+        Lines as (start in seconds, text) pairs
+    """
+    lines = []
+    start, parts = None, []
+    for word in words:
+        text = (word.get('text') or '').strip()
+        if not text:
+            continue
+        if start is None:
+            start = word.get('start') or 0.0
+        parts.append(text)
+
+        at = word.get('start') or start
+        if text[-1] in '.!?' or at - start >= max_seconds:
+            lines.append((start, ' '.join(parts)))
+            start, parts = None, []
+
+    if parts:
+        lines.append((start, ' '.join(parts)))
+    return lines
+
+
 def create_transcriber(provider: str = None) -> Transcriber:
     """
     Factory function to create a transcriber.
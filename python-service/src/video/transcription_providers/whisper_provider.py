@@ -79,3 +79,32 @@ class WhisperTranscriber:
 
         result = self.model.transcribe(audio_path, **options)
         return result['text']
+
+    def transcribe_with_timestamps(self, audio_path: str, language: str = None) -> dict:
+        """
+        Transcribe an audio file with segment-level timestamps.
+
+        Args:
+            audio_path: Path to the audio file
+            language: Language code (optional, e.g., 'en')
+
+        Returns:
+            Dictionary with 'text' and 'segments' (each with 'start' in seconds and 'text')
+        """
+        if self.use_api:
+            with open(audio_path, 'rb') as audio_file:
+                result = self.client.audio.transcriptions.create(
+                    model="whisper-1",
+                    file=audio_file,
+                    response_format="verbose_json"
+                )
+            segments = [{'start': s.start, 'text': s.text} for s in (result.segments or [])]
+            return {'text': result.text, 'segments': segments}
+
+        options = {}
+        if language:
+            options['language'] = language
+
+        result = self.model.transcribe(audio_path, **options)
+        segments = [{'start': s['start'], 'text': s['text']} for s in result.get('segments', [])]
+        return {'text': result['text'], 'segments': segments}